@@ -0,0 +1,255 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// contractABIsJSON registers the methods the explorer decodes, keyed by
+// contract address. Adding a method only needs a new entry here.
+//
+//go:embed contract_abis.json
+var contractABIsJSON []byte
+
+// contractABIs is the parsed registry, built once at startup
+var contractABIs = mustParseContractABIs(contractABIsJSON)
+
+// abiWordSize is the width of one ABI-encoded word
+const abiWordSize = 32
+
+// abiMethod is one registered contract method
+type abiMethod struct {
+	Name   string
+	Inputs []abiInput
+}
+
+// abiInput is one named method parameter
+type abiInput struct {
+	Name string
+	Type string
+}
+
+// contractABI is the registered methods of one contract, keyed by 4-byte selector ("0x" + 8 hex)
+type contractABI struct {
+	Name    string
+	Methods map[string]abiMethod
+}
+
+// contractABIFile is the on-disk shape of contract_abis.json
+type contractABIFile map[string]struct {
+	Name    string `json:"name"`
+	Methods []struct {
+		Signature string   `json:"signature"` // e.g. "transfer(address,uint256)"
+		Params    []string `json:"params"`    // Names for the signature's parameters, in order
+	} `json:"methods"`
+}
+
+// parseContractABIs builds the registry from contract_abis.json, rejecting
+// signatures the decoder cannot handle so a bad entry fails at startup
+func parseContractABIs(data []byte) (map[string]contractABI, error) {
+	var file contractABIFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	registry := make(map[string]contractABI, len(file))
+	for address, entry := range file {
+		contract := contractABI{Name: entry.Name, Methods: make(map[string]abiMethod, len(entry.Methods))}
+		for _, m := range entry.Methods {
+			method, err := parseABISignature(m.Signature, m.Params)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", address, err)
+			}
+			selector := abiSelector(m.Signature)
+			if _, dup := contract.Methods[selector]; dup {
+				return nil, fmt.Errorf("%s: duplicate selector %s for %s", address, selector, m.Signature)
+			}
+			contract.Methods[selector] = method
+		}
+		registry[strings.ToLower(address)] = contract
+	}
+	return registry, nil
+}
+
+func mustParseContractABIs(data []byte) map[string]contractABI {
+	registry, err := parseContractABIs(data)
+	if err != nil {
+		panic("contract_abis.json: " + err.Error())
+	}
+	return registry
+}
+
+// abiSelector returns the 4-byte selector of a canonical method signature
+func abiSelector(signature string) string {
+	return "0x" + hex.EncodeToString(crypto.Keccak256([]byte(signature))[:4])
+}
+
+// parseABISignature splits "name(type,...)" into a method with the given parameter names
+func parseABISignature(signature string, names []string) (abiMethod, error) {
+	open := strings.IndexByte(signature, '(')
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return abiMethod{}, fmt.Errorf("malformed signature %q", signature)
+	}
+	method := abiMethod{Name: signature[:open]}
+
+	var types []string
+	if args := signature[open+1 : len(signature)-1]; args != "" {
+		types = strings.Split(args, ",")
+	}
+	if len(types) != len(names) {
+		return abiMethod{}, fmt.Errorf("%s: %d parameter names for %d parameters", signature, len(names), len(types))
+	}
+	for i, typ := range types {
+		if !isSupportedABIType(typ) {
+			return abiMethod{}, fmt.Errorf("%s: unsupported parameter type %q", signature, typ)
+		}
+		method.Inputs = append(method.Inputs, abiInput{Name: names[i], Type: typ})
+	}
+	return method, nil
+}
+
+// isSupportedABIType reports whether the decoder handles typ: address, bool,
+// string, bytes, bytes1..32 and (u)int8..256. Arrays and tuples are not supported.
+func isSupportedABIType(typ string) bool {
+	switch typ {
+	case "address", "bool", "string", "bytes":
+		return true
+	}
+	for prefix, limit := range map[string]int{"bytes": 32, "uint": 256, "int": 256} {
+		bits, ok := strings.CutPrefix(typ, prefix)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(bits)
+		if err != nil || n < 1 || n > limit || (prefix != "bytes" && n%8 != 0) {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// decodeContractCall decodes calldata sent to contract against the ABI
+// registry. The result always names the method ("unknown" for unregistered
+// selectors); parameters follow when the calldata decodes cleanly. Calldata
+// without a selector decodes to an empty map.
+func decodeContractCall(contract, inputData string) map[string]interface{} {
+	params := make(map[string]interface{})
+	data, err := hex.DecodeString(strings.TrimPrefix(inputData, "0x"))
+	if err != nil || len(data) < 4 {
+		return params
+	}
+
+	method, ok := contractABIs[strings.ToLower(contract)].Methods["0x"+hex.EncodeToString(data[:4])]
+	if !ok {
+		params["method"] = "unknown"
+		return params
+	}
+	params["method"] = method.Name
+
+	args, err := decodeABIArgs(method.Inputs, data[4:])
+	if err != nil {
+		params["decode_error"] = err.Error()
+		return params
+	}
+	for name, value := range args {
+		params[name] = value
+	}
+	return params
+}
+
+// decodeABIArgs decodes ABI-encoded arguments: addresses as 0x-prefixed hex,
+// integers as decimal strings, bytes as 0x-prefixed hex and strings as UTF-8
+func decodeABIArgs(inputs []abiInput, data []byte) (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(inputs))
+	for i, in := range inputs {
+		word, err := abiWord(data, i*abiWordSize)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", in.Name, err)
+		}
+
+		var value interface{}
+		switch {
+		case in.Type == "address":
+			value = "0x" + hex.EncodeToString(word[12:])
+		case in.Type == "bool":
+			value = word[abiWordSize-1] != 0
+		case in.Type == "string" || in.Type == "bytes":
+			raw, err := abiDynamicBytes(data, word)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", in.Name, err)
+			}
+			if in.Type == "bytes" {
+				value = "0x" + hex.EncodeToString(raw)
+				break
+			}
+			if !utf8.Valid(raw) {
+				return nil, fmt.Errorf("%s: invalid UTF-8", in.Name)
+			}
+			value = string(raw)
+		case strings.HasPrefix(in.Type, "bytes"):
+			n, _ := strconv.Atoi(strings.TrimPrefix(in.Type, "bytes"))
+			value = "0x" + hex.EncodeToString(word[:n])
+		case strings.HasPrefix(in.Type, "uint"):
+			value = new(big.Int).SetBytes(word).String()
+		case strings.HasPrefix(in.Type, "int"):
+			n := new(big.Int).SetBytes(word)
+			if word[0]&0x80 != 0 {
+				n.Sub(n, new(big.Int).Lsh(big.NewInt(1), abiWordSize*8))
+			}
+			value = n.String()
+		}
+		args[in.Name] = value
+	}
+	return args, nil
+}
+
+// abiWord returns the 32-byte word at offset
+func abiWord(data []byte, offset int) ([]byte, error) {
+	if offset < 0 || offset+abiWordSize > len(data) {
+		return nil, errors.New("calldata too short")
+	}
+	return data[offset : offset+abiWordSize], nil
+}
+
+// abiDynamicBytes follows a head word's offset to a length-prefixed tail
+func abiDynamicBytes(data, head []byte) ([]byte, error) {
+	offset, ok := abiWordInt(head, len(data))
+	if !ok {
+		return nil, errors.New("offset out of range")
+	}
+	lengthWord, err := abiWord(data, offset)
+	if err != nil {
+		return nil, err
+	}
+	start := offset + abiWordSize
+	length, ok := abiWordInt(lengthWord, len(data)-start)
+	if !ok {
+		return nil, errors.New("length out of range")
+	}
+	return data[start : start+length], nil
+}
+
+// abiWordInt reads a word as a non-negative integer no greater than limit
+func abiWordInt(word []byte, limit int) (int, bool) {
+	for _, b := range word[:abiWordSize-8] {
+		if b != 0 {
+			return 0, false
+		}
+	}
+	n := binary.BigEndian.Uint64(word[abiWordSize-8:])
+	if limit < 0 || n > uint64(limit) {
+		return 0, false
+	}
+	return int(n), true
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// abiTestWord left-pads hex to one 32-byte word
+func abiTestWord(h string) string {
+	return strings.Repeat("0", 64-len(h)) + h
+}
+
+// abiTestString encodes s as a dynamic ABI tail: length word, then padded bytes
+func abiTestString(s string) string {
+	data := hex.EncodeToString([]byte(s))
+	if pad := len(data) % 64; pad != 0 {
+		data += strings.Repeat("0", 64-pad)
+	}
+	return abiTestWord(fmt.Sprintf("%x", len(s))) + data
+}
+
+func TestDecodeContractCallTransfer(t *testing.T) {
+	// transfer(0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed, 1.5 CERT)
+	const calldata = "0xa9059cbb" +
+		"0000000000000000000000005aaeb6053f3e94c9b9a09f33669435e7ef1beaed" +
+		"00000000000000000000000000000000000000000000000014d1120d7b160000"
+
+	if got := abiSelector("transfer(address,uint256)"); got != "0xa9059cbb" {
+		t.Fatalf("transfer selector = %s", got)
+	}
+	params := decodeContractCall(CertTokenContract, calldata)
+	if params["method"] != "transfer" {
+		t.Fatalf("params = %v", params)
+	}
+	if params["to"] != "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed" {
+		t.Errorf("to = %v", params["to"])
+	}
+	if params["amount"] != "1500000000000000000" {
+		t.Errorf("amount = %v", params["amount"])
+	}
+
+	t.Run("truncated calldata keeps the method", func(t *testing.T) {
+		params := decodeContractCall(CertTokenContract, calldata[:74])
+		if params["method"] != "transfer" || params["decode_error"] == nil || params["amount"] != nil {
+			t.Errorf("params = %v", params)
+		}
+	})
+
+	t.Run("unregistered selector", func(t *testing.T) {
+		params := decodeContractCall(CertTokenContract, "0xdeadbeef"+calldata[10:])
+		if params["method"] != "unknown" || len(params) != 1 {
+			t.Errorf("params = %v", params)
+		}
+	})
+
+	t.Run("other contracts do not share the token ABI", func(t *testing.T) {
+		if params := decodeContractCall(CertIDContract, calldata); params["method"] != "unknown" {
+			t.Errorf("params = %v", params)
+		}
+	})
+}
+
+func TestDecodeContractCallDynamicParams(t *testing.T) {
+	calldata := abiSelector("issueCertificate(address,string,string)") +
+		abiTestWord("88e6a0c2ddd26feeb64f039a2c41296fcb3f5641") +
+		abiTestWord("60") +
+		abiTestWord("e0") +
+		abiTestString("sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08") +
+		abiTestString(`{"course":"Café Solidity"}`)
+
+	tx := &TransactionResponse{To: ChainCertifyContract, InputData: calldata}
+	(&Server{}).enrichTransactionData(tx)
+
+	if tx.EcosystemType != "ChainCertify" || tx.DecodedParams["method"] != "issueCertificate" {
+		t.Fatalf("tx = %+v", tx)
+	}
+	if tx.DecodedParams["recipient"] != "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5641" {
+		t.Errorf("recipient = %v", tx.DecodedParams["recipient"])
+	}
+	if tx.CertHash != "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08" {
+		t.Errorf("cert hash = %q", tx.CertHash)
+	}
+	if tx.Metadata != `{"course":"Café Solidity"}` {
+		t.Errorf("metadata = %q", tx.Metadata)
+	}
+
+	t.Run("offset past the calldata", func(t *testing.T) {
+		bad := strings.Replace(calldata, abiTestWord("e0"), abiTestWord("ffff"), 1)
+		params := decodeContractCall(ChainCertifyContract, bad)
+		if params["decode_error"] == nil || params["docHash"] != nil {
+			t.Errorf("params = %v", params)
+		}
+	})
+}
+
+func TestParseContractABIs(t *testing.T) {
+	registry, err := parseContractABIs([]byte(`{"0xABC": {"name": "Demo", "methods": [{"signature": "set(int128,bytes32,bool)", "params": ["delta", "key", "enabled"]}]}}`))
+	if err != nil {
+		t.Fatalf("parseContractABIs: %v", err)
+	}
+	if _, ok := registry["0xabc"].Methods[abiSelector("set(int128,bytes32,bool)")]; !ok {
+		t.Fatalf("registry = %+v, want set registered under the lowercased address", registry)
+	}
+
+	invalid := map[string]string{
+		"unsupported type": `{"0x1": {"methods": [{"signature": "batch(address[])", "params": ["to"]}]}}`,
+		"missing names":    `{"0x1": {"methods": [{"signature": "transfer(address,uint256)", "params": ["to"]}]}}`,
+		"odd int width":    `{"0x1": {"methods": [{"signature": "set(uint7)", "params": ["n"]}]}}`,
+		"duplicate":        `{"0x1": {"methods": [{"signature": "burn(uint256)", "params": ["a"]}, {"signature": "burn(uint256)", "params": ["b"]}]}}`,
+	}
+	for name, data := range invalid {
+		if _, err := parseContractABIs([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDecodeABIArgsSignedAndFixed(t *testing.T) {
+	inputs := []abiInput{{"delta", "int128"}, {"key", "bytes4"}, {"enabled", "bool"}}
+	data, _ := hex.DecodeString(strings.Repeat("f", 62) + "fe" + "c0ffee00" + strings.Repeat("0", 56) + abiTestWord("1"))
+	args, err := decodeABIArgs(inputs, data)
+	if err != nil {
+		t.Fatalf("decodeABIArgs: %v", err)
+	}
+	if args["delta"] != "-2" || args["key"] != "0xc0ffee00" || args["enabled"] != true {
+		t.Errorf("args = %v", args)
+	}
+}
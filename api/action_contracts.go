@@ -0,0 +1,333 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Action verification contracts
+// Layer3 actions are verified from event logs: a swap is a Swap event routed
+// through a known DEX router, a bridge a deposit into a known bridge, and so on.
+// Without a tx hash the address's recent logs are scanned with eth_getLogs;
+// with one, the receipt's logs are matched instead.
+
+// actionScanBlocks is how far back eth_getLogs looks for an action; public RPCs
+// commonly reject wider ranges
+const actionScanBlocks = 10_000
+
+// ActionContract is a router, bridge or staking contract whose events verify an action
+type ActionContract struct {
+	Address  string // 0x address, any case
+	Protocol string // e.g. "Uniswap V3 SwapRouter"
+	Action   string // swap, bridge or stake
+}
+
+// Well-known contracts deployed at the same address on several chains
+const (
+	uniswapV3SwapRouter   = "0xE592427A0AEce92De3Edee1F18E0157C05861564"
+	uniswapSwapRouter02   = "0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"
+	uniswapUniversalRoute = "0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD"
+	oneInchRouterV4       = "0x1111111254fb6c44bAC0beD2854e76F90643097d"
+	oneInchRouterV5       = "0x1111111254EEB25477B68fb85Ed929f73A960582"
+)
+
+// ActionContracts lists the known contracts per chain, keyed like SupportedChains
+var ActionContracts = map[string][]ActionContract{
+	"ethereum": {
+		{Address: "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D", Protocol: "Uniswap V2 Router", Action: "swap"},
+		{Address: uniswapV3SwapRouter, Protocol: "Uniswap V3 SwapRouter", Action: "swap"},
+		{Address: uniswapSwapRouter02, Protocol: "Uniswap SwapRouter02", Action: "swap"},
+		{Address: uniswapUniversalRoute, Protocol: "Uniswap Universal Router", Action: "swap"},
+		{Address: oneInchRouterV4, Protocol: "1inch Aggregation Router V4", Action: "swap"},
+		{Address: oneInchRouterV5, Protocol: "1inch Aggregation Router V5", Action: "swap"},
+		{Address: "0x5c7BCd6E7De5423a257D81B442095A1a6ced35C5", Protocol: "Across SpokePool", Action: "bridge"},
+		{Address: "0xae7ab96520DE3A18E5e111B5EaAb095312D7fE84", Protocol: "Lido stETH", Action: "stake"},
+	},
+	"arbitrum": {
+		{Address: uniswapV3SwapRouter, Protocol: "Uniswap V3 SwapRouter", Action: "swap"},
+		{Address: uniswapSwapRouter02, Protocol: "Uniswap SwapRouter02", Action: "swap"},
+		{Address: oneInchRouterV5, Protocol: "1inch Aggregation Router V5", Action: "swap"},
+		{Address: "0xe35e9842fceaCA96570B734083f4a58e8F7C5f2A", Protocol: "Across SpokePool", Action: "bridge"},
+	},
+	"optimism": {
+		{Address: uniswapV3SwapRouter, Protocol: "Uniswap V3 SwapRouter", Action: "swap"},
+		{Address: uniswapSwapRouter02, Protocol: "Uniswap SwapRouter02", Action: "swap"},
+		{Address: oneInchRouterV5, Protocol: "1inch Aggregation Router V5", Action: "swap"},
+		{Address: "0x6f26Bf09B1C792e3228e5467807a900A503c0281", Protocol: "Across SpokePool", Action: "bridge"},
+	},
+	"base": {
+		{Address: "0x2626664c2603336E57B271c5C0b26F421741e481", Protocol: "Uniswap SwapRouter02", Action: "swap"},
+		{Address: oneInchRouterV5, Protocol: "1inch Aggregation Router V5", Action: "swap"},
+		{Address: "0x09aea4b2242abC8bb4BB78D537A67a245A7bEC64", Protocol: "Across SpokePool", Action: "bridge"},
+	},
+	"polygon": {
+		{Address: uniswapV3SwapRouter, Protocol: "Uniswap V3 SwapRouter", Action: "swap"},
+		{Address: uniswapSwapRouter02, Protocol: "Uniswap SwapRouter02", Action: "swap"},
+		{Address: oneInchRouterV5, Protocol: "1inch Aggregation Router V5", Action: "swap"},
+		{Address: "0x9295ee1d8C5b022Be115A2AD3c30C72E34e7F096", Protocol: "Across SpokePool", Action: "bridge"},
+	},
+}
+
+// actionEvent is an event signature that verifies an action. Topic positions
+// count the indexed arguments from 1; topic 0 is the signature hash.
+type actionEvent struct {
+	Action      string
+	Name        string
+	Signature   string
+	IndexedArgs int // Logs must carry exactly 1+IndexedArgs topics
+
+	// AccountTopic holds the acting address; 0 means the event can only be
+	// matched through a receipt, whose sender is the account
+	AccountTopic int
+	// RouterTopic holds the known contract that called the emitter (a pool's
+	// Swap names its router); 0 means the emitter itself must be known
+	RouterTopic int
+	// AnyEmitter accepts the event from any contract; ZeroTopic, if set, must be the zero address
+	AnyEmitter bool
+	ZeroTopic  int
+}
+
+// actionEvents are the events checked for each action
+var actionEvents = []actionEvent{
+	{Action: "swap", Name: "Uniswap V2 Swap", Signature: "Swap(address,uint256,uint256,uint256,uint256,address)", IndexedArgs: 2, AccountTopic: 2, RouterTopic: 1},
+	{Action: "swap", Name: "Uniswap V3 Swap", Signature: "Swap(address,address,int256,int256,uint160,uint128,int24)", IndexedArgs: 2, AccountTopic: 2, RouterTopic: 1},
+	{Action: "swap", Name: "1inch Swapped", Signature: "Swapped(address,address,address,address,uint256,uint256)"},
+	{Action: "bridge", Name: "Across V3FundsDeposited", Signature: "V3FundsDeposited(address,address,uint256,uint256,uint256,uint32,uint32,uint32,uint32,address,address,address,bytes)", IndexedArgs: 3, AccountTopic: 3},
+	{Action: "mint", Name: "ERC-721 mint", Signature: "Transfer(address,address,uint256)", IndexedArgs: 3, AccountTopic: 2, AnyEmitter: true, ZeroTopic: 1},
+	{Action: "stake", Name: "Lido Submitted", Signature: "Submitted(address,uint256,address)", IndexedArgs: 1, AccountTopic: 1},
+}
+
+// topic0 is the event's signature hash
+func (e actionEvent) topic0() string {
+	return crypto.Keccak256Hash([]byte(e.Signature)).Hex()
+}
+
+// canonicalAction maps request action aliases onto the event tables
+func canonicalAction(action string) string {
+	switch action = strings.ToLower(action); action {
+	case "nft_mint":
+		return "mint"
+	case "staking":
+		return "stake"
+	}
+	return action
+}
+
+// actionHasEvents reports whether action is verified from event logs
+func actionHasEvents(action string) bool {
+	for _, ev := range actionEvents {
+		if ev.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+// knownActionContracts indexes the chain's contracts for action by lowercase address
+func knownActionContracts(chainKey, action string) map[string]ActionContract {
+	known := make(map[string]ActionContract)
+	for _, c := range ActionContracts[chainKey] {
+		if c.Action == action {
+			known[strings.ToLower(c.Address)] = c
+		}
+	}
+	return known
+}
+
+// ethLog is the subset of a JSON-RPC log used for matching
+type ethLog struct {
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	TransactionHash string   `json:"transactionHash"`
+}
+
+// topicAddress is the address held in a 32-byte topic
+func topicAddress(topic string) string {
+	topic = strings.ToLower(strings.TrimPrefix(topic, "0x"))
+	if len(topic) != 64 {
+		return ""
+	}
+	return "0x" + topic[24:]
+}
+
+// zeroAddress is the mint sender of token Transfer events
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// addressTopic left-pads an address to a topic
+func addressTopic(address string) string {
+	return "0x" + strings.Repeat("0", 24) + strings.ToLower(strings.TrimPrefix(address, "0x"))
+}
+
+// matchActionLog checks log against ev, returning the known contract it went
+// through. An empty account skips the account check, as a receipt's sender
+// already identifies the account.
+func matchActionLog(ev actionEvent, log ethLog, known map[string]ActionContract, account string) (ActionContract, bool) {
+	if len(log.Topics) != 1+ev.IndexedArgs || !strings.EqualFold(log.Topics[0], ev.topic0()) {
+		return ActionContract{}, false
+	}
+	if account != "" && ev.AccountTopic > 0 && topicAddress(log.Topics[ev.AccountTopic]) != strings.ToLower(account) {
+		return ActionContract{}, false
+	}
+	if ev.ZeroTopic > 0 && topicAddress(log.Topics[ev.ZeroTopic]) != zeroAddress {
+		return ActionContract{}, false
+	}
+	if ev.AnyEmitter {
+		return ActionContract{Address: log.Address, Protocol: ev.Name, Action: ev.Action}, true
+	}
+	via := strings.ToLower(log.Address)
+	if ev.RouterTopic > 0 {
+		via = topicAddress(log.Topics[ev.RouterTopic])
+	}
+	c, ok := known[via]
+	return c, ok
+}
+
+// rpcResult decodes a JSON-RPC response's result into out
+func rpcResult(response map[string]interface{}, out interface{}) error {
+	if rpcErr, ok := response["error"].(map[string]interface{}); ok {
+		return fmt.Errorf("rpc error: %v", rpcErr["message"])
+	}
+	bz, err := json.Marshal(response["result"])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bz, out)
+}
+
+// ethBlockNumber calls eth_blockNumber
+func (s *Server) ethBlockNumber(ctx context.Context, rpcURL string) (int64, error) {
+	result, err := s.makeRPCCall(ctx, rpcURL, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "eth_blockNumber",
+		"params":  []interface{}{},
+		"id":      1,
+	})
+	if err != nil {
+		return 0, err
+	}
+	var hexHeight string
+	if err := rpcResult(result, &hexHeight); err != nil {
+		return 0, fmt.Errorf("invalid block number response: %w", err)
+	}
+	height, ok := new(big.Int).SetString(strings.TrimPrefix(hexHeight, "0x"), 16)
+	if !ok {
+		return 0, fmt.Errorf("invalid block number %q", hexHeight)
+	}
+	return height.Int64(), nil
+}
+
+// ethGetLogs calls eth_getLogs with filter
+func (s *Server) ethGetLogs(ctx context.Context, rpcURL string, filter map[string]interface{}) ([]ethLog, error) {
+	result, err := s.makeRPCCall(ctx, rpcURL, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "eth_getLogs",
+		"params":  []interface{}{filter},
+		"id":      1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var logs []ethLog
+	if err := rpcResult(result, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// ethGetReceipt calls eth_getTransactionReceipt; a nil receipt means the tx is unknown or pending
+func (s *Server) ethGetReceipt(ctx context.Context, rpcURL, txHash string) (*ethReceipt, error) {
+	result, err := s.makeRPCCall(ctx, rpcURL, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "eth_getTransactionReceipt",
+		"params":  []interface{}{txHash},
+		"id":      1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var receipt *ethReceipt
+	if err := rpcResult(result, &receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// ethReceipt is the subset of a transaction receipt used for matching
+type ethReceipt struct {
+	From   string   `json:"from"`
+	Status string   `json:"status"`
+	Logs   []ethLog `json:"logs"`
+}
+
+// findActionLog scans the account's logs over the last actionScanBlocks blocks
+// for one that verifies action
+func (s *Server) findActionLog(ctx context.Context, rpcURL, chainKey, action, account string) (ethLog, ActionContract, bool, error) {
+	latest, err := s.ethBlockNumber(ctx, rpcURL)
+	if err != nil {
+		return ethLog{}, ActionContract{}, false, err
+	}
+	from := latest - actionScanBlocks
+	if from < 0 {
+		from = 0
+	}
+
+	known := knownActionContracts(chainKey, action)
+	for _, ev := range actionEvents {
+		if ev.Action != action || ev.AccountTopic == 0 {
+			continue
+		}
+		if !ev.AnyEmitter && len(known) == 0 {
+			continue
+		}
+		topics := make([]interface{}, 1+ev.IndexedArgs)
+		topics[0] = ev.topic0()
+		topics[ev.AccountTopic] = addressTopic(account)
+		filter := map[string]interface{}{
+			"fromBlock": fmt.Sprintf("0x%x", from),
+			"toBlock":   "latest",
+			"topics":    topics,
+		}
+		if ev.RouterTopic == 0 && !ev.AnyEmitter {
+			addresses := make([]string, 0, len(known))
+			for addr := range known {
+				addresses = append(addresses, addr)
+			}
+			filter["address"] = addresses
+		}
+
+		logs, err := s.ethGetLogs(ctx, rpcURL, filter)
+		if err != nil {
+			return ethLog{}, ActionContract{}, false, err
+		}
+		for _, log := range logs {
+			if c, ok := matchActionLog(ev, log, known, account); ok {
+				return log, c, true, nil
+			}
+		}
+	}
+	return ethLog{}, ActionContract{}, false, nil
+}
+
+// matchActionReceipt finds a log in a successful receipt sent by account that verifies action
+func matchActionReceipt(receipt *ethReceipt, chainKey, action, account string) (actionEvent, ActionContract, bool) {
+	if receipt == nil || receipt.Status != "0x1" || !strings.EqualFold(receipt.From, account) {
+		return actionEvent{}, ActionContract{}, false
+	}
+	known := knownActionContracts(chainKey, action)
+	for _, log := range receipt.Logs {
+		for _, ev := range actionEvents {
+			if ev.Action != action {
+				continue
+			}
+			if c, ok := matchActionLog(ev, log, known, ""); ok {
+				return ev, c, true
+			}
+		}
+	}
+	return actionEvent{}, ActionContract{}, false
+}
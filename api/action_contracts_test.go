@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+const (
+	swapperAddr   = "0x00000000000000000000000000000000000a11ce"
+	uniswapV3Pool = "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+	swapTxHash    = "0x5a0b54d5dc17e0aadc383d2db43b0a0d3e029c4c"
+)
+
+// evmLogsStub serves eth_blockNumber, eth_getLogs and eth_getTransactionReceipt.
+// eth_getLogs returns the logs whose topic0 matches the filter's.
+type evmLogsStub struct {
+	logs    []ethLog
+	receipt *ethReceipt
+
+	mu      sync.Mutex
+	filters []map[string]interface{}
+}
+
+func (s *evmLogsStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var result interface{}
+	switch req.Method {
+	case "eth_blockNumber":
+		result = "0x1312d00" // 20,000,000
+	case "eth_getLogs":
+		var filter map[string]interface{}
+		json.Unmarshal(req.Params[0], &filter) //nolint:errcheck
+		s.mu.Lock()
+		s.filters = append(s.filters, filter)
+		s.mu.Unlock()
+		topics, _ := filter["topics"].([]interface{})
+		matched := []ethLog{}
+		for _, log := range s.logs {
+			if len(topics) > 0 && strings.EqualFold(log.Topics[0], topics[0].(string)) {
+				matched = append(matched, log)
+			}
+		}
+		result = matched
+	case "eth_getTransactionReceipt":
+		result = s.receipt
+	default:
+		http.Error(w, "unexpected method "+req.Method, http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result}) //nolint:errcheck
+}
+
+// uniswapV3SwapLog is a pool's Swap event with router as sender and recipient as recipient
+func uniswapV3SwapLog(router, recipient string) ethLog {
+	return ethLog{
+		Address:         uniswapV3Pool,
+		Topics:          []string{actionEvents[1].topic0(), addressTopic(router), addressTopic(recipient)},
+		TransactionHash: swapTxHash,
+	}
+}
+
+func verifyTestAction(t *testing.T, stub *evmLogsStub, req VerifyActionRequest) (bool, string, string) {
+	t.Helper()
+	rpc := httptest.NewServer(stub)
+	defer rpc.Close()
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	verified, txHash, _, details := s.verifyAction(context.Background(), req, ChainConfig{Name: "Ethereum", ChainID: 1, RPCURL: rpc.URL})
+	return verified, txHash, details
+}
+
+func TestVerifyActionSwapFromKnownRouter(t *testing.T) {
+	stub := &evmLogsStub{logs: []ethLog{uniswapV3SwapLog(uniswapSwapRouter02, swapperAddr)}}
+	verified, txHash, details := verifyTestAction(t, stub, VerifyActionRequest{Address: swapperAddr, Chain: "Ethereum", Action: "swap"})
+	if !verified || txHash != swapTxHash {
+		t.Fatalf("verified = %v, tx = %s (%s); want the swap tx", verified, txHash, details)
+	}
+	if !strings.Contains(details, "Uniswap SwapRouter02") {
+		t.Errorf("details = %q, want the router named", details)
+	}
+
+	// The scan looks back actionScanBlocks for logs to the account
+	if len(stub.filters) == 0 {
+		t.Fatal("eth_getLogs was not called")
+	}
+	filter := stub.filters[0]
+	if filter["fromBlock"] != "0x13105f0" || filter["toBlock"] != "latest" {
+		t.Errorf("block range = %v..%v", filter["fromBlock"], filter["toBlock"])
+	}
+	if topics := filter["topics"].([]interface{}); topics[2] != addressTopic(swapperAddr) {
+		t.Errorf("topics = %v, want the account as recipient", topics)
+	}
+}
+
+func TestVerifyActionSwapRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		logs []ethLog
+		req  VerifyActionRequest
+	}{
+		{
+			name: "no swaps",
+			req:  VerifyActionRequest{Address: swapperAddr, Chain: "ethereum", Action: "swap"},
+		},
+		{
+			name: "unknown router",
+			logs: []ethLog{uniswapV3SwapLog("0x000000000000000000000000000000000000dead", swapperAddr)},
+			req:  VerifyActionRequest{Address: swapperAddr, Chain: "ethereum", Action: "swap"},
+		},
+		{
+			// Known addresses are per chain; the mainnet router02 address is not Base's
+			name: "router on another chain",
+			logs: []ethLog{uniswapV3SwapLog(uniswapSwapRouter02, swapperAddr)},
+			req:  VerifyActionRequest{Address: swapperAddr, Chain: "base", Action: "swap"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			verified, txHash, details := verifyTestAction(t, &evmLogsStub{logs: tc.logs}, tc.req)
+			if verified || txHash != "" {
+				t.Errorf("verified = %v, tx = %s (%s); want unverified", verified, txHash, details)
+			}
+		})
+	}
+}
+
+func TestVerifyActionSwapReceipt(t *testing.T) {
+	receipt := &ethReceipt{From: swapperAddr, Status: "0x1", Logs: []ethLog{
+		// An ERC-20 Transfer precedes the pool's Swap
+		{Address: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Topics: []string{actionEvents[4].topic0(), addressTopic(uniswapV3Pool), addressTopic(swapperAddr)}},
+		uniswapV3SwapLog(uniswapV3SwapRouter, swapperAddr),
+	}}
+	req := VerifyActionRequest{Address: swapperAddr, Chain: "ethereum", Action: "swap", TxHash: swapTxHash}
+
+	if verified, _, details := verifyTestAction(t, &evmLogsStub{receipt: receipt}, req); !verified {
+		t.Errorf("swap receipt not verified: %s", details)
+	}
+
+	// The same receipt proves no bridge
+	bridge := req
+	bridge.Action = "bridge"
+	if verified, _, _ := verifyTestAction(t, &evmLogsStub{receipt: receipt}, bridge); verified {
+		t.Error("swap receipt verified as a bridge")
+	}
+
+	failed := *receipt
+	failed.Status = "0x0"
+	if verified, _, details := verifyTestAction(t, &evmLogsStub{receipt: &failed}, req); verified || details != "Transaction failed" {
+		t.Errorf("failed tx: verified = %v (%s)", verified, details)
+	}
+
+	other := req
+	other.Address = "0x000000000000000000000000000000000000b0b0"
+	if verified, _, details := verifyTestAction(t, &evmLogsStub{receipt: receipt}, other); verified || details != "Transaction sender does not match" {
+		t.Errorf("other sender: verified = %v (%s)", verified, details)
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// API key scopes
+// Each key carries the scopes it was created with, and endpoints wrapped in
+// requireScope reject key requests without the scope they need. Requests
+// without an API key are not affected; JWT auth still applies where required.
+
+const (
+	scopeReadAttestations  = "read:attestations"
+	scopeWriteAttestations = "write:attestations"
+	scopeReadSchemas       = "read:schemas"
+	scopeWriteSchemas      = "write:schemas"
+	scopeReadIdentity      = "read:identity"
+)
+
+// apiKeyScopes are the scopes a key can be granted
+var apiKeyScopes = map[string]bool{
+	scopeReadAttestations:  true,
+	scopeWriteAttestations: true,
+	scopeReadSchemas:       true,
+	scopeWriteSchemas:      true,
+	scopeReadIdentity:      true,
+}
+
+// defaultAPIKeyScopes returns the scopes tier allows, which are also the
+// scopes of a key created without any: free keys are read-only, paid keys get
+// every scope
+func defaultAPIKeyScopes(tier string) []string {
+	scopes := []string{scopeReadAttestations, scopeReadIdentity, scopeReadSchemas}
+	if tier != "free" {
+		scopes = append(scopes, scopeWriteAttestations, scopeWriteSchemas)
+		sort.Strings(scopes)
+	}
+	return scopes
+}
+
+// resolveAPIKeyScopes validates the scopes requested for a new key of tier,
+// returning them sorted and deduplicated. Scopes the tier does not allow are
+// rejected rather than dropped so the caller sees what the key can do.
+func resolveAPIKeyScopes(tier string, requested []string) ([]string, error) {
+	allowed := defaultAPIKeyScopes(tier)
+	if len(requested) == 0 {
+		return allowed, nil
+	}
+	seen := make(map[string]bool, len(requested))
+	scopes := make([]string, 0, len(requested))
+	for _, scope := range requested {
+		if !apiKeyScopes[scope] {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+		if !hasScope(allowed, scope) {
+			return nil, fmt.Errorf("scope %q is not available on the %s tier", scope, tier)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes, nil
+}
+
+// effectiveAPIKeyScopes returns the stored scopes of a key that its current
+// tier still allows, so a key downgraded by billing loses its write scopes
+func effectiveAPIKeyScopes(tier string, scopes []string) []string {
+	allowed := defaultAPIKeyScopes(tier)
+	effective := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if hasScope(allowed, scope) {
+			effective = append(effective, scope)
+		}
+	}
+	return effective
+}
+
+// hasScope reports whether scopes contains scope
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// requireScope wraps a handler so that API key requests need scope
+func (s *Server) requireScope(scope string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if scopes, ok := r.Context().Value(APIKeyScopesKey).([]string); ok && !hasScope(scopes, scope) {
+			s.respondError(w, http.StatusForbidden, "API key lacks scope "+scope)
+			return
+		}
+		handler(w, r)
+	}
+}
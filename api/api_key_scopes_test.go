@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// serveWithKeyScopes routes a request that apiKeyMiddleware has already
+// resolved to a key with scopes
+func serveWithKeyScopes(s *Server, method, path, body string, scopes []string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), APIKeyScopesKey, scopes))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRequireScope(t *testing.T) {
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	readOnly := defaultAPIKeyScopes("free")
+
+	for _, tc := range []struct{ method, path, body string }{
+		{"POST", "/api/v1/schemas", `{"schema":"string name"}`},
+		{"POST", "/api/v1/attestations", `{"schema_uid":"0x01"}`},
+		{"POST", "/api/v1/attestations/batch-revoke", `{"uids":["0x01"]}`},
+	} {
+		if rec := serveWithKeyScopes(s, tc.method, tc.path, tc.body, readOnly); rec.Code != http.StatusForbidden {
+			t.Errorf("read-only key: %s %s = %d, want 403", tc.method, tc.path, rec.Code)
+		}
+	}
+
+	// Read endpoints get past the scope check to the handler
+	for _, path := range []string{"/api/v1/schemas/0x01", "/api/v1/attestations/0x01", "/api/v1/identity/" + oauthTestAddress + "/badges"} {
+		if rec := serveWithKeyScopes(s, "GET", path, "", readOnly); rec.Code == http.StatusForbidden {
+			t.Errorf("read-only key: GET %s = 403: %s", path, rec.Body.String())
+		}
+	}
+
+	// A key with write:schemas may create schemas, and requests without a key are unaffected
+	if rec := serveWithKeyScopes(s, "POST", "/api/v1/schemas", `{}`, []string{scopeWriteSchemas}); rec.Code == http.StatusForbidden {
+		t.Errorf("write:schemas key: POST /schemas = 403")
+	}
+	req := httptest.NewRequest("POST", "/api/v1/schemas", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	if rec.Code == http.StatusForbidden {
+		t.Errorf("no key: POST /schemas = 403")
+	}
+}
+
+func TestResolveAPIKeyScopes(t *testing.T) {
+	for _, tc := range []struct {
+		tier      string
+		requested []string
+		want      []string
+	}{
+		{"free", nil, []string{"read:attestations", "read:identity", "read:schemas"}},
+		{"developer", nil, []string{"read:attestations", "read:identity", "read:schemas", "write:attestations", "write:schemas"}},
+		{"free", []string{"read:schemas", "read:identity", "read:schemas"}, []string{"read:identity", "read:schemas"}},
+		{"developer", []string{"write:schemas", "read:schemas", "write:schemas"}, []string{"read:schemas", "write:schemas"}},
+	} {
+		got, err := resolveAPIKeyScopes(tc.tier, tc.requested)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("resolveAPIKeyScopes(%s, %v) = %v, %v; want %v", tc.tier, tc.requested, got, err, tc.want)
+		}
+	}
+	if _, err := resolveAPIKeyScopes("free", []string{"admin:everything"}); err == nil {
+		t.Error("unknown scope accepted")
+	}
+	// Free keys are read-only, so asking for a write scope fails instead of
+	// being silently granted or dropped
+	for _, scope := range []string{scopeWriteAttestations, scopeWriteSchemas} {
+		if got, err := resolveAPIKeyScopes("free", []string{scopeReadSchemas, scope}); err == nil {
+			t.Errorf("free key granted %s: %v", scope, got)
+		}
+	}
+}
+
+func TestEffectiveAPIKeyScopes(t *testing.T) {
+	stored := []string{"read:attestations", "read:schemas", "write:attestations", "write:schemas"}
+	if got := effectiveAPIKeyScopes("developer", stored); !reflect.DeepEqual(got, stored) {
+		t.Errorf("developer key scopes = %v, want %v", got, stored)
+	}
+	// A key downgraded to free keeps only its read scopes
+	if got, want := effectiveAPIKeyScopes("free", stored), []string{"read:attestations", "read:schemas"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("downgraded key scopes = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// attestationBatchWorkers bounds the chain queries a batch lookup runs at once
+const attestationBatchWorkers = 8
+
+// handleBatchGetAttestations handles POST /api/v1/attestations/batch. It looks
+// up each of {"uids": [...]} as GET /attestations/{uid} would and returns a map
+// of UID to attestation, with null for UIDs that do not exist on chain.
+func (s *Server) handleBatchGetAttestations(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UIDs []string `json:"uids"`
+	}
+	if err := decodeJSONBodyStrict(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
+		return
+	}
+
+	// Duplicates count against the limit, so the request is bounded before any work
+	if !s.enforceBatchSize(w, len(req.UIDs), s.config.BatchLimits.LookupUIDs) {
+		return
+	}
+
+	uids := make([]string, 0, len(req.UIDs))
+	seen := make(map[string]bool, len(req.UIDs))
+	for _, uid := range req.UIDs {
+		uid = strings.TrimSpace(uid)
+		if uid == "" {
+			s.respondError(w, http.StatusBadRequest, "uids cannot contain empty values")
+			return
+		}
+		if !seen[uid] {
+			seen[uid] = true
+			uids = append(uids, uid)
+		}
+	}
+	if len(uids) == 0 {
+		s.respondError(w, http.StatusBadRequest, "uids is required")
+		return
+	}
+
+	var (
+		mu      sync.Mutex
+		results = make(map[string]any, len(uids))
+		failed  error
+		wg      sync.WaitGroup
+	)
+	jobs := make(chan string)
+	for i := 0; i < attestationBatchWorkers && i < len(uids); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for uid := range jobs {
+				var raw map[string]any
+				err := s.chain.Query(r.Context(), &raw, 0, "attestation", "attestation", uid)
+				var attestation map[string]any
+				if err == nil {
+					attestation = s.attestationResponse(r, uid, raw)
+				} else if errors.Is(err, ErrChainNotFound) {
+					err = nil
+				}
+
+				mu.Lock()
+				if err == nil {
+					results[uid] = attestation
+				} else if failed == nil {
+					failed = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, uid := range uids {
+		jobs <- uid
+	}
+	close(jobs)
+	wg.Wait()
+
+	// A failed lookup cannot be told apart from a missing attestation
+	if failed != nil {
+		s.logger.Warn("failed to query attestation batch", zap.Int("uids", len(uids)), zap.Error(failed))
+		s.respondError(w, http.StatusBadGateway, "Failed to query attestations")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, results)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+func postAttestationBatch(s *Server, uids []string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]any{"uids": uids})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/attestations/batch", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBatchGetAttestations(t *testing.T) {
+	chain := &mockChainClient{query: func(out any, _ int64, args ...string) error {
+		uid := args[len(args)-1]
+		if !strings.HasPrefix(uid, "0xexists") {
+			return fmt.Errorf("%w: rpc error: %s", ErrChainNotFound, attestationtypes.ErrAttestationNotFound.Error())
+		}
+		return json.Unmarshal([]byte(`{"attestation":{"uid":"`+uid+`","schema_uid":"0xschema","attester":"`+validateAttester+`","attestation_type":"public"}}`), out)
+	}}
+	s := newMockChainServer(chain)
+
+	rec := postAttestationBatch(s, []string{"0xexists1", "0xmissing", "0xexists2", "0xexists1"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var out map[string]map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 3 {
+		t.Errorf("got %d entries, want 3: %v", len(out), out)
+	}
+	for _, uid := range []string{"0xexists1", "0xexists2"} {
+		// Normalized as GET /attestations/{uid} does
+		if a := out[uid]; a["uid"] != uid || a["issuer"] != validateAttester || a["schema"] != "0xschema" || a["encrypted"] != false {
+			t.Errorf("%s = %v", uid, a)
+		}
+	}
+	if a, ok := out["0xmissing"]; !ok || a != nil {
+		t.Errorf("0xmissing = %v (present %v), want null", a, ok)
+	}
+}
+
+func TestBatchGetAttestationsLimits(t *testing.T) {
+	s := newMockChainServer(&mockChainClient{query: func(out any, _ int64, args ...string) error {
+		return json.Unmarshal([]byte(`{"attestation":{}}`), out)
+	}})
+	uids := func(n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = fmt.Sprintf("0x%03d", i)
+		}
+		return out
+	}
+
+	if rec := postAttestationBatch(s, uids(100)); rec.Code != http.StatusOK {
+		t.Errorf("100 uids: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	rec := postAttestationBatch(s, uids(101))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "batch size 101 exceeds limit 100") {
+		t.Errorf("101 uids: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	// Duplicates count against the limit
+	rec = postAttestationBatch(s, append(uids(100), "0x000"))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "batch size 101 exceeds limit 100") {
+		t.Errorf("100 uids and a duplicate: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if rec := postAttestationBatch(s, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("no uids: status = %d", rec.Code)
+	}
+	if rec := postAttestationBatch(s, []string{"0x1", " "}); rec.Code != http.StatusBadRequest {
+		t.Errorf("empty uid: status = %d", rec.Code)
+	}
+}
+
+func TestBatchGetAttestationsChainFailure(t *testing.T) {
+	s := newMockChainServer(&mockChainClient{query: func(any, int64, ...string) error {
+		return errors.New("connection refused")
+	}})
+	if rec := postAttestationBatch(s, []string{"0x1", "0x2"}); rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", rec.Code)
+	}
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"go.uber.org/zap"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+// Gas model for AttestationEstimate. KV costs come from the store's default
+// gas config; the rest approximates the ante handler and message overhead.
+const (
+	// estimateBaseTxGas covers signature verification and fee deduction
+	estimateBaseTxGas = 60_000
+
+	// estimateTxSizeCostPerByte matches the auth module default
+	estimateTxSizeCostPerByte = 10
+
+	// estimateMsgOverheadBytes approximates the signed tx envelope around the data
+	estimateMsgOverheadBytes = 400
+
+	// estimateReadsPerAttestation counts the schema, params and nonce lookups
+	estimateReadsPerAttestation = 3
+)
+
+// AttestationEstimateRequest describes an attestation an issuer intends to submit
+// POST /api/v1/attestations/estimate
+type AttestationEstimateRequest struct {
+	SchemaUID      string `json:"schema_uid"`
+	Data           string `json:"data"`
+	RecipientCount int    `json:"recipient_count"`
+}
+
+// AttestationEstimate is the projected state and fee footprint of a submission.
+// One attestation is written per recipient; a count of zero means one
+// attestation without a recipient.
+type AttestationEstimate struct {
+	SchemaUID      string `json:"schema_uid"`
+	RecipientCount int    `json:"recipient_count"`
+	Attestations   int    `json:"attestations"`
+
+	// DataSize is the raw data size the size limit applies to
+	DataSize       int  `json:"data_size"`
+	StoredDataSize int  `json:"stored_data_size"`
+	Compressed     bool `json:"compressed"`
+
+	// StoredSizePerAttestation includes the record and its index entries
+	StoredSizePerAttestation int `json:"stored_size_per_attestation"`
+	TotalStoredSize          int `json:"total_stored_size"`
+
+	Fee         sdk.Coins `json:"fee"`
+	FeeDisplay  string    `json:"fee_display,omitempty"`
+	GasEstimate uint64    `json:"gas_estimate"`
+
+	Limits AttestationEstimateLimits `json:"limits"`
+}
+
+// AttestationEstimateLimits reports the limits the estimate was checked against
+type AttestationEstimateLimits struct {
+	MaxDataSize   uint64 `json:"max_data_size"`
+	MaxRecipients int    `json:"max_recipients"`
+}
+
+// protoUint decodes an unsigned integer that proto JSON may render as a string
+type protoUint uint64
+
+func (u *protoUint) UnmarshalJSON(b []byte) error {
+	n, err := strconv.ParseUint(strings.Trim(string(b), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+	*u = protoUint(n)
+	return nil
+}
+
+// attestationParamsResult is the `certd query attestation params` output
+type attestationParamsResult struct {
+	Params struct {
+		MaxRecipientsPerAttestation protoUint `json:"max_recipients_per_attestation"`
+		MaxAttestationDataSize      protoUint `json:"max_attestation_data_size"`
+		AttestationFee              sdk.Coins `json:"attestation_fee"`
+		RestrictSchemas             bool      `json:"restrict_schemas"`
+		AllowedSchemaCreators       []string  `json:"allowed_schema_creators"`
+	} `json:"params"`
+}
+
+// queryAttestationParams fetches the attestation module params, applying the
+// module's zero-means-default rule
+func (s *Server) queryAttestationParams() (attestationtypes.Params, error) {
+	var res attestationParamsResult
+	if err := s.execCertdQueryJSON(&res, "attestation", "params"); err != nil {
+		return attestationtypes.Params{}, err
+	}
+
+	params := attestationtypes.Params{
+		MaxRecipientsPerAttestation: uint32(res.Params.MaxRecipientsPerAttestation),
+		MaxAttestationDataSize:      uint64(res.Params.MaxAttestationDataSize),
+		AttestationFee:              res.Params.AttestationFee,
+		RestrictSchemas:             res.Params.RestrictSchemas,
+		AllowedSchemaCreators:       res.Params.AllowedSchemaCreators,
+	}
+	if params.MaxRecipientsPerAttestation == 0 {
+		params.MaxRecipientsPerAttestation = attestationtypes.DefaultParams().MaxRecipientsPerAttestation
+	}
+	if params.MaxAttestationDataSize == 0 {
+		params.MaxAttestationDataSize = attestationtypes.DefaultMaxAttestationDataSize
+	}
+	return params, nil
+}
+
+// estimateAttestation computes the footprint of count attestations carrying data
+func estimateAttestation(schemaUID string, data []byte, recipientCount int, params attestationtypes.Params) AttestationEstimate {
+	attestations := recipientCount
+	if attestations < 1 {
+		attestations = 1
+	}
+
+	stored := attestationtypes.CompressAttestationData(data)
+
+	// A representative record: real UIDs are 0x-prefixed 32-byte hashes and
+	// module addresses are 20 bytes
+	placeholder := sdk.AccAddress(make([]byte, 20))
+	uid := "0x" + strings.Repeat("0", 64)
+	record := attestationtypes.Attestation{
+		UID:             uid,
+		SchemaUID:       schemaUID,
+		Attester:        placeholder,
+		Time:            time.Unix(0, 0).UTC(),
+		Revocable:       true,
+		Data:            stored,
+		AttestationType: attestationtypes.AttestationTypePublic,
+	}
+	if recipientCount > 0 {
+		record.Recipient = placeholder
+	}
+	bz, _ := json.Marshal(record)
+
+	writes := [][2]int{
+		{len(attestationtypes.GetAttestationKey(uid)), len(bz)},
+		{len(attestationtypes.GetAttestationByAttesterKey(placeholder, uid)), 1},
+		{len(attestationtypes.GetAttestationBySchemaKey(schemaUID, uid)), 1},
+	}
+	if recipientCount > 0 {
+		writes = append(writes, [2]int{len(attestationtypes.GetAttestationByRecipientKey(placeholder, uid)), 1})
+	}
+
+	gasConfig := storetypes.KVGasConfig()
+	perAttestationSize := 0
+	perAttestationGas := estimateReadsPerAttestation * gasConfig.ReadCostFlat
+	for _, kv := range writes {
+		size := kv[0] + kv[1]
+		perAttestationSize += size
+		perAttestationGas += gasConfig.WriteCostFlat + gasConfig.WriteCostPerByte*uint64(size)
+	}
+
+	// Data travels hex-encoded in each message
+	txBytes := uint64(attestations) * uint64(estimateMsgOverheadBytes+2*len(data))
+	gas := uint64(estimateBaseTxGas) + estimateTxSizeCostPerByte*txBytes + uint64(attestations)*perAttestationGas
+
+	fee := sdk.NewCoins()
+	for _, c := range params.AttestationFee {
+		fee = fee.Add(sdk.NewCoin(c.Denom, c.Amount.MulRaw(int64(attestations))))
+	}
+
+	return AttestationEstimate{
+		SchemaUID:                schemaUID,
+		RecipientCount:           recipientCount,
+		Attestations:             attestations,
+		DataSize:                 len(data),
+		StoredDataSize:           len(stored),
+		Compressed:               attestationtypes.IsCompressedAttestationData(stored),
+		StoredSizePerAttestation: perAttestationSize,
+		TotalStoredSize:          perAttestationSize * attestations,
+		Fee:                      fee,
+		GasEstimate:              gas,
+		Limits: AttestationEstimateLimits{
+			MaxDataSize:   params.MaxAttestationDataSize,
+			MaxRecipients: int(params.MaxRecipientsPerAttestation),
+		},
+	}
+}
+
+// handleEstimateAttestation projects the stored size, fee and gas of an
+// attestation before it is submitted
+// POST /api/v1/attestations/estimate
+func (s *Server) handleEstimateAttestation(w http.ResponseWriter, r *http.Request) {
+	var req AttestationEstimateRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
+		return
+	}
+
+	req.SchemaUID = strings.TrimSpace(req.SchemaUID)
+	if req.SchemaUID == "" {
+		s.respondError(w, http.StatusBadRequest, "schema_uid is required")
+		return
+	}
+	if req.RecipientCount < 0 {
+		s.respondError(w, http.StatusBadRequest, "recipient_count must not be negative")
+		return
+	}
+	data, err := decodeFlexibleBytes(req.Data)
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid data: %v", err))
+		return
+	}
+
+	params, err := s.queryAttestationParams()
+	if err != nil {
+		s.logger.Warn("failed to query attestation params", zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query attestation params")
+		return
+	}
+
+	if uint64(len(data)) > params.MaxAttestationDataSize {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("data size %d exceeds limit %d", len(data), params.MaxAttestationDataSize))
+		return
+	}
+	if !s.enforceBatchSize(w, req.RecipientCount, int(params.MaxRecipientsPerAttestation)) {
+		return
+	}
+	if !s.enforceBatchSize(w, req.RecipientCount, s.config.BatchLimits.Recipients) {
+		return
+	}
+
+	var schema struct {
+		Schema struct {
+			Schema string `json:"schema"`
+		} `json:"schema"`
+	}
+	if err := s.execCertdQueryJSON(&schema, "attestation", "schema", req.SchemaUID); err != nil {
+		if strings.Contains(err.Error(), attestationtypes.ErrSchemaNotFound.Error()) {
+			s.respondError(w, http.StatusNotFound, "schema not found")
+			return
+		}
+		s.logger.Warn("failed to query schema", zap.String("uid", req.SchemaUID), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query schema")
+		return
+	}
+	if err := attestationtypes.ValidateSchemaData(schema.Schema.Schema, data); err != nil {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("data does not match schema: %v", err))
+		return
+	}
+
+	estimate := estimateAttestation(req.SchemaUID, data, req.RecipientCount, params)
+	if amount := estimate.Fee.AmountOf(s.denom.Base); amount.IsInt64() && !amount.IsZero() {
+		estimate.FeeDisplay = s.denom.FormatDisplay(amount.Int64()) + " " + s.denom.Symbol
+	}
+	s.respondJSON(w, http.StatusOK, estimate)
+}
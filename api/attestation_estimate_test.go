@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func stubEstimateQueries(t *testing.T, params string) {
+	t.Helper()
+	orig := runCertdCLI
+	t.Cleanup(func() { runCertdCLI = orig })
+	runCertdCLI = func(args ...string) ([]byte, error) {
+		switch {
+		case len(args) > 2 && args[2] == "params":
+			return []byte(params), nil
+		case len(args) > 3 && args[2] == "schema" && args[3] == "0xtranscript":
+			return []byte(`{"schema":{"uid":"0xtranscript","schema":"string transcript","revocable":true}}`), nil
+		default:
+			return []byte("Error: schema not found"), errors.New("exit status 1")
+		}
+	}
+}
+
+func postEstimate(t *testing.T, s *Server, body map[string]any) (int, map[string]any) {
+	t.Helper()
+	b, _ := json.Marshal(body)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/v1/attestations/estimate", strings.NewReader(string(b)))
+	req.Header.Set("Content-Type", "application/json")
+	s.router.ServeHTTP(rec, req)
+	var out map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &out)
+	return rec.Code, out
+}
+
+// abiString ABI-encodes a single string argument as hex
+func abiString(s string) string {
+	word := func(n int) string {
+		b := make([]byte, 32)
+		for i := 31; n > 0; i-- {
+			b[i] = byte(n)
+			n >>= 8
+		}
+		return string(b)
+	}
+	padded := s + strings.Repeat("\x00", (32-len(s)%32)%32)
+	return "0x" + hex.EncodeToString([]byte(word(32)+word(len(s))+padded))
+}
+
+func TestHandleEstimateAttestation(t *testing.T) {
+	stubEstimateQueries(t, `{"params":{"max_recipients_per_attestation":10,"max_attestation_data_size":"4096","attestation_fee":[{"denom":"ucert","amount":"250000"}]}}`)
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	transcript := abiString(strings.Repeat("Distributed Systems: A; ", 40))
+
+	t.Run("within limits", func(t *testing.T) {
+		code, out := postEstimate(t, s, map[string]any{"schema_uid": "0xtranscript", "data": transcript, "recipient_count": 3})
+		if code != http.StatusOK {
+			t.Fatalf("status = %d, body = %v", code, out)
+		}
+		if out["attestations"].(float64) != 3 || out["compressed"] != true {
+			t.Errorf("estimate = %v", out)
+		}
+		if out["stored_data_size"].(float64) >= out["data_size"].(float64) {
+			t.Errorf("stored %v >= raw %v for compressible data", out["stored_data_size"], out["data_size"])
+		}
+		if out["total_stored_size"].(float64) != 3*out["stored_size_per_attestation"].(float64) {
+			t.Errorf("total stored size = %v", out["total_stored_size"])
+		}
+		fee := out["fee"].([]any)[0].(map[string]any)
+		if fee["denom"] != "ucert" || fee["amount"] != "750000" || out["fee_display"] != "0.750000 CERT" {
+			t.Errorf("fee = %v (%v)", fee, out["fee_display"])
+		}
+		if out["gas_estimate"].(float64) <= estimateBaseTxGas {
+			t.Errorf("gas estimate = %v", out["gas_estimate"])
+		}
+		limits := out["limits"].(map[string]any)
+		if limits["max_data_size"].(float64) != 4096 || limits["max_recipients"].(float64) != 10 {
+			t.Errorf("limits = %v", limits)
+		}
+	})
+
+	t.Run("without recipients", func(t *testing.T) {
+		code, out := postEstimate(t, s, map[string]any{"schema_uid": "0xtranscript", "data": transcript})
+		if code != http.StatusOK || out["attestations"].(float64) != 1 {
+			t.Fatalf("status = %d, body = %v", code, out)
+		}
+	})
+
+	t.Run("exceeding limits", func(t *testing.T) {
+		tests := []struct {
+			name string
+			body map[string]any
+			code int
+		}{
+			{"data too large", map[string]any{"schema_uid": "0xtranscript", "data": abiString(strings.Repeat("x", 5000))}, http.StatusBadRequest},
+			{"too many recipients", map[string]any{"schema_uid": "0xtranscript", "data": transcript, "recipient_count": 11}, http.StatusBadRequest},
+			{"data does not match schema", map[string]any{"schema_uid": "0xtranscript", "data": "0x01"}, http.StatusBadRequest},
+			{"unknown schema", map[string]any{"schema_uid": "0xmissing", "data": transcript}, http.StatusNotFound},
+			{"missing schema", map[string]any{"data": transcript}, http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			if code, out := postEstimate(t, s, tt.body); code != tt.code {
+				t.Errorf("%s: status = %d, want %d (%v)", tt.name, code, tt.code, out)
+			}
+		}
+	})
+}
+
+func TestHandleEstimateAttestationDefaultsZeroParams(t *testing.T) {
+	stubEstimateQueries(t, `{"params":{"max_recipients_per_attestation":50,"attestation_fee":[]}}`)
+	s := NewServer(DefaultConfig(), zap.NewNop())
+
+	code, out := postEstimate(t, s, map[string]any{"schema_uid": "0xtranscript", "data": abiString("short")})
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, body = %v", code, out)
+	}
+	if out["compressed"] != false || out["stored_data_size"] != out["data_size"] {
+		t.Errorf("small data should be stored verbatim: %v", out)
+	}
+	if limits := out["limits"].(map[string]any); limits["max_data_size"].(float64) != 64*1024 {
+		t.Errorf("max data size = %v, want the module default", limits["max_data_size"])
+	}
+	if _, ok := out["fee_display"]; ok {
+		t.Errorf("fee_display set for a free attestation: %v", out["fee_display"])
+	}
+}
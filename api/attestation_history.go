@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+// isPrunedHeightMessage reports whether an upstream message says the state or
+// block at a height has been pruned
+func isPrunedHeightMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "pruned") || strings.Contains(msg, "lowest height is")
+}
+
+// handleGetAttestationAtHeight answers GET /api/v1/attestations/{uid}?height=
+// with the attestation as it was committed at that height, so revocations and
+// expirations after it do not show. Heights pruned from the node answer 410.
+func (s *Server) handleGetAttestationAtHeight(w http.ResponseWriter, r *http.Request, uid string) {
+	height, err := strconv.ParseInt(r.URL.Query().Get("height"), 10, 64)
+	if err != nil || height <= 0 {
+		s.respondError(w, http.StatusBadRequest, "height must be a positive block height")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	latest, err := s.chain.LatestHeight(ctx)
+	if err != nil {
+		s.logger.Warn("failed to fetch latest height", zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query chain height")
+		return
+	}
+	if height > latest {
+		s.respondError(w, http.StatusBadRequest, "height "+strconv.FormatInt(height, 10)+" is above the latest block "+strconv.FormatInt(latest, 10))
+		return
+	}
+
+	var raw map[string]any
+	if err := s.chain.Query(ctx, &raw, height, "attestation", "attestation", uid); err != nil {
+		switch {
+		case strings.Contains(err.Error(), attestationtypes.ErrAttestationNotFound.Error()):
+			s.respondError(w, http.StatusNotFound, "Attestation did not exist at height "+strconv.FormatInt(height, 10))
+		case errors.Is(err, ErrStateVersionUnavailable) && height >= latest-stateVersionHeightLag:
+			// The tip itself can briefly report a missing version; see state_version.go
+			s.respondStateUnavailable(w, "Chain state at this height is not available yet")
+		case errors.Is(err, ErrStateVersionUnavailable) || isPrunedHeightMessage(err.Error()):
+			s.respondError(w, http.StatusGone, "State at height "+strconv.FormatInt(height, 10)+" has been pruned from the node")
+		default:
+			s.logger.Warn("failed to query attestation at height", zap.String("uid", uid), zap.Int64("height", height), zap.Error(err))
+			s.respondError(w, http.StatusBadGateway, "Failed to query attestation")
+		}
+		return
+	}
+
+	out := s.attestationResponse(r, uid, raw)
+	out["height"] = height
+	a, _ := raw["attestation"].(map[string]any)
+	revocation, _ := a["revocation_time"].(string)
+	out["revoked"] = isRevokedTimestamp(revocation)
+
+	// Validity depends on the block time, which needs the block to still be stored
+	block, err := s.chain.Block(ctx, strconv.FormatInt(height, 10))
+	if err != nil {
+		s.logger.Warn("failed to fetch block for attestation height", zap.Int64("height", height), zap.Error(err))
+		s.respondJSON(w, http.StatusOK, out)
+		return
+	}
+	blockTime, _ := parseTimestamp(block.Time)
+	expiration, _ := a["expiration_time"].(string)
+	expiresAt, _ := parseTimestamp(expiration)
+	expired := !expiresAt.IsZero() && blockTime.After(expiresAt)
+	out["block_time"] = NewTimestamp(blockTime)
+	out["expired"] = expired
+	out["valid"] = !isRevokedTimestamp(revocation) && !expired
+
+	s.respondJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestGetAttestationAtHeight(t *testing.T) {
+	const (
+		prunedBelow     = 50
+		createdAt       = 100
+		revokedAt       = 150
+		latest          = 200
+		attestationJSON = `{"attestation":{"uid":"0xdiploma","schema_uid":"0xschema","attester":"cert1issuer","time":"2026-01-01T00:00:00Z","expiration_time":"2026-06-01T00:00:00Z","revocation_time":%q,"attestation_type":"public"}}`
+	)
+	chain := &mockChainClient{
+		height: latest,
+		blocks: map[string]ChainBlock{
+			"120": {Height: 120, Time: "2026-01-10T08:00:00.5Z"},
+			"160": {Height: 160, Time: "2026-01-20T08:00:00Z"},
+		},
+		query: func(out any, height int64, args ...string) error {
+			if len(args) < 3 || args[1] != "attestation" || args[2] != "0xdiploma" {
+				return errors.New("unexpected query")
+			}
+			switch {
+			case height < prunedBelow:
+				return checkStateVersion(versionDoesNotExist, height)
+			case height < createdAt:
+				return errors.New("certd query failed: exit status 1: rpc error: code = NotFound desc = 0xdiploma: attestation not found")
+			}
+			revocation := "0001-01-01T00:00:00Z"
+			if height >= revokedAt {
+				revocation = "2026-01-15T00:00:00Z"
+			}
+			return json.Unmarshal([]byte(fmt.Sprintf(attestationJSON, revocation)), out)
+		},
+	}
+	config := DefaultConfig()
+	config.Chain = chain
+	s := NewServer(config, zap.NewNop())
+
+	get := func(height string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/attestations/0xdiploma?height="+height, nil))
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+
+	t.Run("before revocation", func(t *testing.T) {
+		code, out := get("120")
+		if code != http.StatusOK {
+			t.Fatalf("status = %d, body = %v", code, out)
+		}
+		if out["height"] != float64(120) || out["revoked"] != false || out["valid"] != true || out["expired"] != false {
+			t.Errorf("response = %v", out)
+		}
+		if out["block_time"] != "2026-01-10T08:00:00Z" {
+			t.Errorf("block_time = %v", out["block_time"])
+		}
+	})
+
+	t.Run("after revocation", func(t *testing.T) {
+		code, out := get("160")
+		if code != http.StatusOK || out["revoked"] != true || out["valid"] != false {
+			t.Errorf("status = %d, response = %v", code, out)
+		}
+	})
+
+	t.Run("without the block it omits validity", func(t *testing.T) {
+		code, out := get(strconv.Itoa(revokedAt))
+		if code != http.StatusOK || out["revoked"] != true {
+			t.Fatalf("status = %d, response = %v", code, out)
+		}
+		if _, ok := out["valid"]; ok {
+			t.Errorf("valid reported without a block time: %v", out)
+		}
+	})
+
+	errorCases := []struct {
+		name   string
+		height string
+		want   int
+	}{
+		{"before creation", "80", http.StatusNotFound},
+		{"pruned", "10", http.StatusGone},
+		{"above the tip", "201", http.StatusBadRequest},
+		{"not a height", "yesterday", http.StatusBadRequest},
+		{"zero", "0", http.StatusBadRequest},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, out := get(tt.height); code != tt.want {
+				t.Errorf("status = %d, want %d (%v)", code, tt.want, out)
+			}
+		})
+	}
+}
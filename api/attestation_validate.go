@@ -0,0 +1,223 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"go.uber.org/zap"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+// Outcomes of an AttestationCheck
+const (
+	checkPass = "pass"
+	checkFail = "fail"
+	checkSkip = "skip" // A check it depends on failed
+)
+
+// AttestationValidateRequest is an attestation an issuer intends to submit.
+// One attestation is written per recipient; no recipients means one
+// attestation without a recipient.
+// POST /api/v1/attestations/validate
+type AttestationValidateRequest struct {
+	Attester   string   `json:"attester"`
+	SchemaUID  string   `json:"schema_uid"`
+	Recipients []string `json:"recipients,omitempty"`
+	Data       string   `json:"data"`
+	Revocable  bool     `json:"revocable"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// AttestationCheck is the result of one validation step
+type AttestationCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// AttestationValidation reports whether a submission would pass MsgAttest
+// validation and the keeper checks against current state
+type AttestationValidation struct {
+	Valid        bool               `json:"valid"`
+	Attestations int                `json:"attestations"`
+	Checks       []AttestationCheck `json:"checks"`
+}
+
+// add records a check; a non-pass status marks the validation invalid
+func (v *AttestationValidation) add(name, status, message string) bool {
+	v.Checks = append(v.Checks, AttestationCheck{Name: name, Status: status, Message: message})
+	if status != checkPass {
+		v.Valid = false
+	}
+	return status == checkPass
+}
+
+// check records a pass for a nil err and a fail carrying err otherwise
+func (v *AttestationValidation) check(name string, err error) bool {
+	if err != nil {
+		return v.add(name, checkFail, err.Error())
+	}
+	return v.add(name, checkPass, "")
+}
+
+// schemaQueryResult is the `certd query attestation schema` output
+type schemaQueryResult struct {
+	Schema struct {
+		UID       string    `json:"uid"`
+		Schema    string    `json:"schema"`
+		Revocable bool      `json:"revocable"`
+		Creator   string    `json:"creator"`
+		Fee       sdk.Coins `json:"fee"`
+	} `json:"schema"`
+}
+
+// certAccAddress normalizes addr to bech32 and checks it is a 20-byte cert account
+func certAccAddress(addr string) (string, error) {
+	bech, err := toBech32Address(strings.TrimSpace(addr))
+	if err != nil {
+		return "", err
+	}
+	hrp, bz, err := bech32.DecodeAndConvert(bech)
+	if err != nil {
+		return "", fmt.Errorf("invalid bech32 address: %w", err)
+	}
+	if hrp != "cert" || len(bz) != 20 {
+		return "", fmt.Errorf("%s is not a cert account address", bech)
+	}
+	return bech, nil
+}
+
+// handleValidateAttestation runs the checks an attestation submission would
+// face, MsgAttest.ValidateBasic and then the keeper's checks against current
+// state, and reports each result. Nothing is signed or written.
+// POST /api/v1/attestations/validate
+func (s *Server) handleValidateAttestation(w http.ResponseWriter, r *http.Request) {
+	var req AttestationValidateRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
+		return
+	}
+
+	params, err := s.queryAttestationParams()
+	if err != nil {
+		s.logger.Warn("failed to query attestation params", zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query attestation params")
+		return
+	}
+
+	result := AttestationValidation{Valid: true, Attestations: len(req.Recipients)}
+	if result.Attestations < 1 {
+		result.Attestations = 1
+	}
+
+	// Stateless checks, as in MsgAttest.ValidateBasic
+	attester, err := certAccAddress(req.Attester)
+	attesterOK := result.check("attester", err)
+
+	req.SchemaUID = strings.TrimSpace(req.SchemaUID)
+	schemaUIDOK := req.SchemaUID != ""
+	if schemaUIDOK {
+		result.add("schema_uid", checkPass, "")
+	} else {
+		result.add("schema_uid", checkFail, "schema_uid is required")
+	}
+
+	_, err = attestationtypes.NormalizeTags(req.Tags)
+	result.check("tags", err)
+
+	err = validateBatchSize(len(req.Recipients), int(params.MaxRecipientsPerAttestation))
+	if err == nil {
+		err = validateBatchSize(len(req.Recipients), s.config.BatchLimits.Recipients)
+	}
+	for _, recipient := range req.Recipients {
+		if err != nil {
+			break
+		}
+		if _, addrErr := certAccAddress(recipient); addrErr != nil {
+			err = fmt.Errorf("invalid recipient %q: %v", recipient, addrErr)
+		}
+	}
+	result.check("recipients", err)
+
+	data, err := decodeFlexibleBytes(req.Data)
+	if err == nil && uint64(len(data)) > params.MaxAttestationDataSize {
+		err = fmt.Errorf("data size %d exceeds limit %d", len(data), params.MaxAttestationDataSize)
+	}
+	dataOK := result.check("data", err)
+
+	// Keeper checks against the schema and current state
+	var schema schemaQueryResult
+	schemaOK := false
+	if !schemaUIDOK {
+		result.add("schema", checkSkip, "schema_uid is required")
+	} else if err := s.execCertdQueryJSON(&schema, "attestation", "schema", req.SchemaUID); err != nil {
+		if !strings.Contains(err.Error(), attestationtypes.ErrSchemaNotFound.Error()) {
+			s.logger.Warn("failed to query schema", zap.String("uid", req.SchemaUID), zap.Error(err))
+			s.respondError(w, http.StatusBadGateway, "Failed to query schema")
+			return
+		}
+		result.add("schema", checkFail, "schema not found")
+	} else {
+		schemaOK = result.add("schema", checkPass, "")
+	}
+
+	if !schemaOK {
+		for _, name := range []string{"schema_allowed", "revocable", "schema_data", "fee"} {
+			result.add(name, checkSkip, "schema unavailable")
+		}
+		s.respondJSON(w, http.StatusOK, result)
+		return
+	}
+
+	// Params.IsSchemaCreatorAllowed formats with the process bech32 prefix,
+	// which the API does not set, so compare the chain's strings directly
+	if !params.RestrictSchemas || slices.Contains(params.AllowedSchemaCreators, schema.Schema.Creator) {
+		result.add("schema_allowed", checkPass, "")
+	} else {
+		result.add("schema_allowed", checkFail, fmt.Sprintf("schema creator %s is not allowlisted", schema.Schema.Creator))
+	}
+
+	if req.Revocable && !schema.Schema.Revocable {
+		result.add("revocable", checkFail, "schema does not allow revocable attestations")
+	} else {
+		result.add("revocable", checkPass, "")
+	}
+
+	if dataOK {
+		result.check("schema_data", attestationtypes.ValidateSchemaData(schema.Schema.Schema, data))
+	} else {
+		result.add("schema_data", checkSkip, "data is invalid")
+	}
+
+	fee := sdk.NewCoins()
+	for _, c := range schema.Schema.Fee {
+		fee = fee.Add(sdk.NewCoin(c.Denom, c.Amount.MulRaw(int64(result.Attestations))))
+	}
+	switch {
+	case fee.IsZero():
+		result.add("fee", checkPass, "")
+	case !attesterOK:
+		result.add("fee", checkSkip, "attester is invalid")
+	default:
+		var balances struct {
+			Balances sdk.Coins `json:"balances"`
+		}
+		if err := s.execCertdQueryJSON(&balances, "bank", "balances", attester); err != nil {
+			s.logger.Warn("failed to query attester balance", zap.String("attester", attester), zap.Error(err))
+			s.respondError(w, http.StatusBadGateway, "Failed to query attester balance")
+			return
+		}
+		if balances.Balances.IsAllGTE(fee) {
+			result.add("fee", checkPass, "")
+		} else {
+			result.add("fee", checkFail, fmt.Sprintf("balance %s does not cover schema fees %s", balances.Balances, fee))
+		}
+	}
+
+	s.respondJSON(w, http.StatusOK, result)
+}
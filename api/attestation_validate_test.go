@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+const (
+	validateCreator  = "cert1wd3ksetdv90kxun9v96x7ujlta047h6lrfq304"
+	validateAttester = "cert1v968getnw3jhyh6lta047h6lta047h6l0ytjja"
+)
+
+func stubValidateQueries(t *testing.T, params, balance string) {
+	t.Helper()
+	orig := runCertdCLI
+	t.Cleanup(func() { runCertdCLI = orig })
+	runCertdCLI = func(args ...string) ([]byte, error) {
+		switch {
+		case len(args) > 2 && args[1] == "attestation" && args[2] == "params":
+			return []byte(params), nil
+		case len(args) > 3 && args[2] == "schema" && args[3] == "0xtranscript":
+			return []byte(`{"schema":{"uid":"0xtranscript","schema":"string transcript","revocable":true,"creator":"` + validateCreator + `","fee":[{"denom":"ucert","amount":"1000"}]}}`), nil
+		case len(args) > 3 && args[2] == "schema" && args[3] == "0xpermanent":
+			return []byte(`{"schema":{"uid":"0xpermanent","schema":"string transcript","revocable":false,"creator":"` + validateCreator + `"}}`), nil
+		case len(args) > 3 && args[1] == "bank" && args[3] == validateAttester:
+			return []byte(balance), nil
+		default:
+			return []byte("Error: schema not found"), errors.New("exit status 1")
+		}
+	}
+}
+
+func postValidate(t *testing.T, s *Server, body map[string]any) (int, AttestationValidation) {
+	t.Helper()
+	b, _ := json.Marshal(body)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, newJSONRequest("POST", "/api/v1/attestations/validate", strings.NewReader(string(b))))
+	var out AttestationValidation
+	_ = json.Unmarshal(rec.Body.Bytes(), &out)
+	return rec.Code, out
+}
+
+func TestHandleValidateAttestation(t *testing.T) {
+	params := `{"params":{"max_recipients_per_attestation":2,"max_attestation_data_size":"4096"}}`
+	stubValidateQueries(t, params, `{"balances":[{"denom":"ucert","amount":"5000"}]}`)
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	transcript := abiString("Distributed Systems: A")
+
+	valid := func() map[string]any {
+		return map[string]any{
+			"attester":   validateAttester,
+			"schema_uid": "0xtranscript",
+			"recipients": []string{validateCreator, "0x0000000000000000000000000000000000000001"},
+			"data":       transcript,
+			"revocable":  true,
+			"tags":       []string{"degree"},
+		}
+	}
+
+	t.Run("fully valid", func(t *testing.T) {
+		code, out := postValidate(t, s, valid())
+		if code != http.StatusOK || !out.Valid || out.Attestations != 2 {
+			t.Fatalf("status = %d, result = %+v", code, out)
+		}
+		for _, c := range out.Checks {
+			if c.Status != checkPass {
+				t.Errorf("check %s = %s (%s)", c.Name, c.Status, c.Message)
+			}
+		}
+		if len(out.Checks) != 10 {
+			t.Errorf("ran %d checks, want 10", len(out.Checks))
+		}
+	})
+
+	tests := []struct {
+		name   string
+		modify func(body map[string]any)
+		failed string
+	}{
+		{"invalid attester", func(b map[string]any) { b["attester"] = "cert1notanaddress" }, "attester"},
+		{"missing schema uid", func(b map[string]any) { delete(b, "schema_uid") }, "schema_uid"},
+		{"invalid tag", func(b map[string]any) { b["tags"] = []string{"no spaces"} }, "tags"},
+		{"too many recipients", func(b map[string]any) { b["recipients"] = []string{validateCreator, validateCreator, validateCreator} }, "recipients"},
+		{"invalid recipient", func(b map[string]any) { b["recipients"] = []string{"cosmos1wfjkx6tsd9jkuazlta047h6lta047h6l0n7r6e"} }, "recipients"},
+		{"undecodable data", func(b map[string]any) { b["data"] = "0xzz" }, "data"},
+		{"data too large", func(b map[string]any) { b["data"] = abiString(strings.Repeat("x", 5000)) }, "data"},
+		{"unknown schema", func(b map[string]any) { b["schema_uid"] = "0xmissing" }, "schema"},
+		{"revocable on permanent schema", func(b map[string]any) { b["schema_uid"] = "0xpermanent" }, "revocable"},
+		{"data does not match schema", func(b map[string]any) { b["data"] = "0x01" }, "schema_data"},
+		{"balance does not cover fees", func(b map[string]any) {
+			b["recipients"] = []string{validateCreator, validateCreator}
+			b["data"] = transcript
+			stubValidateQueries(t, params, `{"balances":[{"denom":"ucert","amount":"1999"}]}`)
+		}, "fee"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := valid()
+			tt.modify(body)
+			code, out := postValidate(t, s, body)
+			if code != http.StatusOK || out.Valid {
+				t.Fatalf("status = %d, result = %+v", code, out)
+			}
+			for _, c := range out.Checks {
+				switch {
+				case c.Name == tt.failed && c.Status != checkFail:
+					t.Errorf("check %s = %s, want fail", c.Name, c.Status)
+				case c.Name != tt.failed && c.Status == checkFail:
+					t.Errorf("unexpected failure in %s: %s", c.Name, c.Message)
+				}
+			}
+		})
+	}
+
+	t.Run("schema creator not allowlisted", func(t *testing.T) {
+		stubValidateQueries(t, `{"params":{"max_recipients_per_attestation":2,"restrict_schemas":true,"allowed_schema_creators":["`+validateAttester+`"]}}`, `{"balances":[{"denom":"ucert","amount":"5000"}]}`)
+		code, out := postValidate(t, s, valid())
+		if code != http.StatusOK || out.Valid {
+			t.Fatalf("status = %d, result = %+v", code, out)
+		}
+		for _, c := range out.Checks {
+			if (c.Name == "schema_allowed") != (c.Status == checkFail) {
+				t.Errorf("check %s = %s (%s)", c.Name, c.Status, c.Message)
+			}
+		}
+	})
+}
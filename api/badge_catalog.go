@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// BadgeMapping awards BadgeID for verified credentials of CredentialTypes.
+// A mapping with an Issuer only applies to credentials from that issuer and
+// takes precedence over global mappings of the same credential type.
+type BadgeMapping struct {
+	CredentialTypes []string `json:"credential_types"`
+	BadgeID         string   `json:"badge"`
+	Issuer          string   `json:"issuer,omitempty"`
+}
+
+// BadgeCatalog is the set of badge definitions and the credential types that award them
+type BadgeCatalog struct {
+	Badges   []Badge        `json:"badges"`
+	Mappings []BadgeMapping `json:"mappings"`
+}
+
+// DefaultBadgeCatalog returns the standard badges and credential type mappings
+func DefaultBadgeCatalog() BadgeCatalog {
+	return BadgeCatalog{
+		Badges: []Badge{
+			{ID: "KYC_L1", Name: "KYC Level 1", Icon: "kyc-l1", Emoji: "\U0001FAAA", Description: "Basic identity verification"},
+			{ID: "KYC_L2", Name: "KYC Level 2", Icon: "kyc-l2", Emoji: "\U0001F6E1\uFE0F", Description: "Advanced identity verification"},
+			{ID: "ACADEMIC_ISSUER", Name: "Academic Issuer", Icon: "academic", Emoji: "\U0001F393", Description: "Verified educational institution", Institutional: true},
+			{ID: "VERIFIED_CREATOR", Name: "Verified Creator", Icon: "creator", Emoji: "\u2728", Description: "Verified content creator"},
+			{ID: "GOV_AGENCY", Name: "Government Agency", Icon: "government", Emoji: "\U0001F3DB\uFE0F", Description: "Verified government entity", Institutional: true},
+			{ID: "LEGAL_ENTITY", Name: "Legal Entity", Icon: "legal", Emoji: "\u2696\uFE0F", Description: "Verified legal organization", Institutional: true},
+			{ID: "ISO_9001_CERTIFIED", Name: "ISO 9001", Icon: "iso-9001", Emoji: "\U0001F4CB", Description: "ISO 9001 quality certified"},
+		},
+		Mappings: []BadgeMapping{
+			{CredentialTypes: []string{"KYC", "KYC_L1", "IDENTITY"}, BadgeID: "KYC_L1"},
+			{CredentialTypes: []string{"KYC_L2", "IDENTITY_ADVANCED"}, BadgeID: "KYC_L2"},
+			{CredentialTypes: []string{"EDUCATION", "ACADEMIC", "UNIVERSITY"}, BadgeID: "ACADEMIC_ISSUER"},
+			{CredentialTypes: []string{"CREATOR", "ARTIST", "DEVELOPER"}, BadgeID: "VERIFIED_CREATOR"},
+			{CredentialTypes: []string{"GOVERNMENT", "GOV"}, BadgeID: "GOV_AGENCY"},
+			{CredentialTypes: []string{"LEGAL", "BUSINESS", "COMPANY"}, BadgeID: "LEGAL_ENTITY"},
+			{CredentialTypes: []string{"ISO", "ISO_9001", "QUALITY"}, BadgeID: "ISO_9001_CERTIFIED"},
+		},
+	}
+}
+
+// LoadBadgeCatalog reads a JSON BadgeCatalog from path and layers it over the
+// standard catalog: badges replace standard badges with the same ID and
+// mappings override standard mappings of the same credential type
+func LoadBadgeCatalog(path string) (BadgeCatalog, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return BadgeCatalog{}, err
+	}
+	var custom BadgeCatalog
+	if err := json.Unmarshal(bz, &custom); err != nil {
+		return BadgeCatalog{}, fmt.Errorf("failed to parse badge catalog: %w", err)
+	}
+	catalog := DefaultBadgeCatalog().Merge(custom)
+	if err := catalog.Validate(); err != nil {
+		return BadgeCatalog{}, err
+	}
+	return catalog, nil
+}
+
+// Merge returns c extended with other's badges and mappings; other wins on conflicts
+func (c BadgeCatalog) Merge(other BadgeCatalog) BadgeCatalog {
+	merged := BadgeCatalog{
+		Badges:   append([]Badge(nil), c.Badges...),
+		Mappings: append(append([]BadgeMapping(nil), c.Mappings...), other.Mappings...),
+	}
+	for _, b := range other.Badges {
+		replaced := false
+		for i := range merged.Badges {
+			if merged.Badges[i].ID == b.ID {
+				merged.Badges[i] = b
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged.Badges = append(merged.Badges, b)
+		}
+	}
+	return merged
+}
+
+// Validate checks that badges are unique and named and that every mapping awards a defined badge
+func (c BadgeCatalog) Validate() error {
+	defined := make(map[string]bool, len(c.Badges))
+	for _, b := range c.Badges {
+		if b.ID == "" || b.Name == "" {
+			return fmt.Errorf("badge %q needs an id and a name", b.ID)
+		}
+		if defined[b.ID] {
+			return fmt.Errorf("duplicate badge id %q", b.ID)
+		}
+		defined[b.ID] = true
+	}
+	for _, m := range c.Mappings {
+		if !defined[m.BadgeID] {
+			return fmt.Errorf("mapping awards undefined badge %q", m.BadgeID)
+		}
+		if len(m.CredentialTypes) == 0 {
+			return fmt.Errorf("mapping for badge %q has no credential types", m.BadgeID)
+		}
+	}
+	return nil
+}
+
+// badgeIndex is a compiled BadgeCatalog; it is immutable once built
+type badgeIndex struct {
+	badges   map[string]Badge
+	global   map[string]string            // credential type -> badge ID
+	byIssuer map[string]map[string]string // issuer -> credential type -> badge ID
+}
+
+// newBadgeIndex compiles catalog; later mappings override earlier ones
+func newBadgeIndex(catalog BadgeCatalog) *badgeIndex {
+	idx := &badgeIndex{
+		badges:   make(map[string]Badge, len(catalog.Badges)),
+		global:   make(map[string]string),
+		byIssuer: make(map[string]map[string]string),
+	}
+	for _, b := range catalog.Badges {
+		idx.badges[b.ID] = normalizeBadgeIcon(b)
+	}
+	for _, m := range catalog.Mappings {
+		target := idx.global
+		if issuer := normalizeBadgeIssuer(m.Issuer); issuer != "" {
+			if idx.byIssuer[issuer] == nil {
+				idx.byIssuer[issuer] = make(map[string]string)
+			}
+			target = idx.byIssuer[issuer]
+		}
+		for _, credType := range m.CredentialTypes {
+			target[strings.ToUpper(strings.TrimSpace(credType))] = m.BadgeID
+		}
+	}
+	return idx
+}
+
+// resolve returns the badge a verified credential of credType from issuer awards
+func (idx *badgeIndex) resolve(credType, issuer string) (Badge, bool) {
+	credType = strings.ToUpper(strings.TrimSpace(credType))
+	id, ok := idx.byIssuer[normalizeBadgeIssuer(issuer)][credType]
+	if !ok {
+		id, ok = idx.global[credType]
+	}
+	if !ok {
+		return Badge{}, false
+	}
+	badge, ok := idx.badges[id]
+	return badge, ok
+}
+
+func normalizeBadgeIssuer(issuer string) string {
+	return strings.ToLower(strings.TrimSpace(issuer))
+}
+
+// badgeCatalogState holds the active badge index, swapped atomically on reload
+type badgeCatalogState struct {
+	index atomic.Pointer[badgeIndex]
+}
+
+func (b *badgeCatalogState) load() *badgeIndex {
+	return b.index.Load()
+}
+
+// mapCredentialToBadge returns the badge a verified credential awards, if any
+func (s *Server) mapCredentialToBadge(credType, issuer string) (Badge, bool) {
+	return s.badges.load().resolve(credType, issuer)
+}
+
+// ReloadBadgeCatalog swaps in a new badge catalog, layering the custom badges
+// stored in the database over it. An invalid catalog leaves the current one in place.
+func (s *Server) ReloadBadgeCatalog(catalog BadgeCatalog) error {
+	if s.db != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		custom, err := s.loadDBBadgeCatalog(ctx)
+		if err != nil {
+			s.logger.Warn("failed to load custom badges from database", zap.Error(err))
+		} else {
+			catalog = catalog.Merge(custom)
+		}
+	}
+	if err := catalog.Validate(); err != nil {
+		return err
+	}
+	s.badges.index.Store(newBadgeIndex(catalog))
+	return nil
+}
+
+// loadDBBadgeCatalog reads the custom badge definitions and mappings stored in the database
+func (s *Server) loadDBBadgeCatalog(ctx context.Context) (BadgeCatalog, error) {
+	defs, err := s.db.ListBadgeDefinitions(ctx)
+	if err != nil {
+		return BadgeCatalog{}, err
+	}
+	mappings, err := s.db.ListBadgeMappings(ctx)
+	if err != nil {
+		return BadgeCatalog{}, err
+	}
+
+	var catalog BadgeCatalog
+	for _, d := range defs {
+		catalog.Badges = append(catalog.Badges, Badge{
+			ID:            d.ID,
+			Name:          d.Name,
+			Description:   d.Description,
+			Icon:          d.Icon,
+			Institutional: d.Institutional,
+		})
+	}
+	for _, m := range mappings {
+		catalog.Mappings = append(catalog.Mappings, BadgeMapping{
+			CredentialTypes: []string{m.CredentialType},
+			BadgeID:         m.BadgeID,
+			Issuer:          m.IssuerAddress,
+		})
+	}
+	return catalog, nil
+}
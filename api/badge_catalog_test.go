@@ -0,0 +1,72 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestBadgeCatalogResolution(t *testing.T) {
+	const university = "cert1university_____________"
+	path := filepath.Join(t.TempDir(), "badges.json")
+	catalog := `{
+		"badges": [
+			{"id": "ACME_ENGINEER", "name": "ACME Certified Engineer", "icon": "🔧"},
+			{"id": "DEANS_LIST", "name": "Dean's List", "icon": "🏅"},
+			{"id": "KYC_L1", "name": "Verified Human", "icon": "🪪"}
+		],
+		"mappings": [
+			{"credential_types": ["acme_engineer"], "badge": "ACME_ENGINEER"},
+			{"credential_types": ["ACADEMIC"], "badge": "DEANS_LIST", "issuer": "CERT1UNIVERSITY_____________"}
+		]
+	}`
+	if err := os.WriteFile(path, []byte(catalog), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBadgeCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadBadgeCatalog: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.Badges = loaded
+	s := NewServer(config, zap.NewNop())
+
+	tests := []struct {
+		name     string
+		credType string
+		issuer   string
+		want     string // badge ID, empty for no badge
+	}{
+		{"standard badge", "university", "", "ACADEMIC_ISSUER"},
+		{"standard badge overridden by the catalog", "kyc", "", "KYC_L1"},
+		{"custom badge", "Acme_Engineer", "cert1anyone", "ACME_ENGINEER"},
+		{"issuer-specific badge", "academic", university, "DEANS_LIST"},
+		{"issuer-specific mapping does not leak to other issuers", "academic", "cert1other", "ACADEMIC_ISSUER"},
+		{"unmapped credential type", "PILOT_LICENSE", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			badge, ok := s.mapCredentialToBadge(tt.credType, tt.issuer)
+			if ok != (tt.want != "") || badge.ID != tt.want {
+				t.Errorf("mapCredentialToBadge(%q, %q) = %q, %v; want %q", tt.credType, tt.issuer, badge.ID, ok, tt.want)
+			}
+		})
+	}
+	if badge, _ := s.mapCredentialToBadge("kyc", ""); badge.Name != "Verified Human" {
+		t.Errorf("overridden KYC_L1 name = %q", badge.Name)
+	}
+	if !s.isInstitutionalCredential("government", "") || s.isInstitutionalCredential("acme_engineer", "") {
+		t.Error("institutional flag not taken from the badge definition")
+	}
+
+	// A catalog that awards an undefined badge is rejected and the current one kept
+	if err := s.ReloadBadgeCatalog(BadgeCatalog{Mappings: []BadgeMapping{{CredentialTypes: []string{"X"}, BadgeID: "MISSING"}}}); err == nil {
+		t.Error("ReloadBadgeCatalog accepted a mapping to an undefined badge")
+	}
+	if _, ok := s.mapCredentialToBadge("acme_engineer", ""); !ok {
+		t.Error("failed reload replaced the catalog")
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+)
+
+// badgeIconFS is the icon registry: one SVG per icon identifier
+//
+//go:embed badge_icons/*.svg
+var badgeIconFS embed.FS
+
+const (
+	// badgeIconRoute is where registry icons are served
+	badgeIconRoute = "/api/v1/badges/icons/"
+
+	// defaultBadgeIcon is shown for badges whose icon is not in the registry
+	defaultBadgeIcon = "badge"
+)
+
+// badgeIconSVG returns the registry icon for id
+func badgeIconSVG(id string) ([]byte, bool) {
+	if !isBadgeIconID(id) {
+		return nil, false
+	}
+	svg, err := fs.ReadFile(badgeIconFS, "badge_icons/"+id+".svg")
+	return svg, err == nil
+}
+
+// isBadgeIconID reports whether id is shaped like an icon identifier (e.g. "kyc-l1")
+func isBadgeIconID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmoji reports whether s is a short run of non-ASCII symbols, as legacy badge icons were
+func isEmoji(s string) bool {
+	if s == "" || utf8.RuneCountInString(s) > 8 {
+		return false
+	}
+	for _, c := range s {
+		if c < utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeBadgeIcon resolves b's icon to a stable reference. Registry
+// identifiers and absolute http(s) URLs are kept; an emoji (as in catalogs
+// written before the registry) moves to the Emoji fallback; anything else
+// falls back to the default icon.
+func normalizeBadgeIcon(b Badge) Badge {
+	icon := strings.TrimSpace(b.Icon)
+	if strings.HasPrefix(icon, "https://") || strings.HasPrefix(icon, "http://") {
+		b.Icon = icon
+		b.IconURL = icon
+		return b
+	}
+	if _, ok := badgeIconSVG(icon); !ok {
+		if b.Emoji == "" && isEmoji(icon) {
+			b.Emoji = icon
+		}
+		icon = defaultBadgeIcon
+	}
+	b.Icon = icon
+	b.IconURL = badgeIconRoute + icon + ".svg"
+	return b
+}
+
+// handleGetBadgeIcon serves a registry icon
+// GET /api/v1/badges/icons/{name}.svg
+func (s *Server) handleGetBadgeIcon(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(mux.Vars(r)["name"], ".svg")
+	svg, found := badgeIconSVG(name)
+	if !ok || !found {
+		s.respondError(w, http.StatusNotFound, "Badge icon not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	// SVG can carry script; never let it run if opened directly
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(svg)
+}
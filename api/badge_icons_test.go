@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestStandardBadgeIconsResolve(t *testing.T) {
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	idx := s.badges.load()
+
+	for _, def := range DefaultBadgeCatalog().Badges {
+		badge := idx.badges[def.ID]
+		if badge.Icon == defaultBadgeIcon {
+			t.Errorf("%s fell back to the default icon", def.ID)
+		}
+		if badge.Emoji == "" {
+			t.Errorf("%s has no emoji fallback", def.ID)
+		}
+		if badge.IconURL != badgeIconRoute+badge.Icon+".svg" {
+			t.Errorf("%s icon_url = %q", def.ID, badge.IconURL)
+		}
+
+		rec := serve(s, "GET", badge.IconURL)
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s = %d", badge.IconURL, rec.Code)
+			continue
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "image/svg+xml" {
+			t.Errorf("%s Content-Type = %q", badge.IconURL, ct)
+		}
+		if !strings.HasPrefix(rec.Body.String(), "<svg") {
+			t.Errorf("%s is not an SVG", badge.IconURL)
+		}
+	}
+}
+
+func TestNormalizeBadgeIcon(t *testing.T) {
+	tests := []struct {
+		name      string
+		badge     Badge
+		wantIcon  string
+		wantURL   string
+		wantEmoji string
+	}{
+		{"registry icon", Badge{ID: "A", Icon: "academic"}, "academic", badgeIconRoute + "academic.svg", ""},
+		{"hosted icon", Badge{ID: "B", Icon: "https://cdn.example.com/acme.png"}, "https://cdn.example.com/acme.png", "https://cdn.example.com/acme.png", ""},
+		{"legacy emoji icon", Badge{ID: "C", Icon: "\U0001F527"}, defaultBadgeIcon, badgeIconRoute + defaultBadgeIcon + ".svg", "\U0001F527"},
+		{"unknown icon identifier", Badge{ID: "D", Icon: "acme-wrench", Emoji: "\U0001F527"}, defaultBadgeIcon, badgeIconRoute + defaultBadgeIcon + ".svg", "\U0001F527"},
+		{"no icon", Badge{ID: "E"}, defaultBadgeIcon, badgeIconRoute + defaultBadgeIcon + ".svg", ""},
+		{"path traversal", Badge{ID: "F", Icon: "../server.go"}, defaultBadgeIcon, badgeIconRoute + defaultBadgeIcon + ".svg", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeBadgeIcon(tt.badge)
+			if got.Icon != tt.wantIcon || got.IconURL != tt.wantURL || got.Emoji != tt.wantEmoji {
+				t.Errorf("normalizeBadgeIcon = {icon %q, url %q, emoji %q}; want {%q, %q, %q}",
+					got.Icon, got.IconURL, got.Emoji, tt.wantIcon, tt.wantURL, tt.wantEmoji)
+			}
+		})
+	}
+}
+
+func TestGetBadgeIconNotFound(t *testing.T) {
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	for _, path := range []string{
+		badgeIconRoute + "no-such-icon.svg",
+		badgeIconRoute + "academic.png",
+		badgeIconRoute + "academic",
+	} {
+		if rec := serve(s, "GET", path); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", path, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"go.uber.org/zap"
+)
+
+// Balance source names, reported to clients as the balance "source"
+const (
+	BalanceSourceGRPC        = "grpc"
+	BalanceSourceREST        = "rest"
+	BalanceSourceDBEstimate  = "db_estimate"
+	BalanceSourceUnavailable = "unavailable"
+)
+
+// BalanceSourcesConfig controls which sources answer balance queries and in what order
+type BalanceSourcesConfig struct {
+	// Order lists enabled sources, most preferred first; unknown names are ignored
+	Order []string
+
+	// RESTURL is the Cosmos REST (LCD) base URL used by the "rest" source
+	RESTURL string
+
+	// Timeout bounds each individual source attempt
+	Timeout time.Duration
+
+	// FailureThreshold consecutive failures open a source's circuit breaker
+	FailureThreshold int
+
+	// Cooldown is how long an open breaker skips its source before a trial call
+	Cooldown time.Duration
+}
+
+// DefaultBalanceSourcesConfig returns the default balance source ordering
+func DefaultBalanceSourcesConfig() BalanceSourcesConfig {
+	return BalanceSourcesConfig{
+		Order:            []string{BalanceSourceGRPC, BalanceSourceREST, BalanceSourceDBEstimate},
+		RESTURL:          "http://localhost:1317",
+		Timeout:          3 * time.Second,
+		FailureThreshold: 3,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+// balanceSource is one way of looking up an address's ucert balance
+type balanceSource struct {
+	name    string
+	fetch   func(ctx context.Context, bech32Addr string) (int64, error)
+	breaker *circuitBreaker
+}
+
+// AddressBalance is a CERT balance tagged with the source that produced it
+type AddressBalance struct {
+	Balance string `json:"balance"`
+	Source  string `json:"source"`
+}
+
+// newBalanceSources builds the enabled balance sources in configured order
+func (s *Server) newBalanceSources(cfg BalanceSourcesConfig) []*balanceSource {
+	var sources []*balanceSource
+	seen := make(map[string]bool)
+	for _, name := range cfg.Order {
+		name = strings.TrimSpace(name)
+		if seen[name] {
+			continue
+		}
+
+		var fetch func(ctx context.Context, bech32Addr string) (int64, error)
+		switch name {
+		case BalanceSourceGRPC:
+			fetch = s.fetchBalanceGRPC
+		case BalanceSourceREST:
+			fetch = s.fetchBalanceREST
+		case BalanceSourceDBEstimate:
+			fetch = s.fetchBalanceDBEstimate
+		default:
+			s.logger.Warn("ignoring unknown balance source", zap.String("source", name))
+			continue
+		}
+		seen[name] = true
+		sources = append(sources, &balanceSource{
+			name:    name,
+			fetch:   fetch,
+			breaker: newCircuitBreaker(cfg.FailureThreshold, cfg.Cooldown),
+		})
+	}
+	return sources
+}
+
+// queryAddressBalance queries for an address's CERT balance.
+// Sources are tried in configured order; sources whose circuit breaker is open are
+// skipped so a persistently broken backend does not add latency to every lookup.
+// The gRPC source queries the bank module through the node's query router, which
+// avoids the REST gateway's "version does not exist" errors (a known Cosmos SDK
+// v0.50.x state versioning bug) and retries the latest height on them. Its
+// failures are logged at warn, since they mean the answer comes from a less
+// reliable source such as the faucet-tracked database estimate.
+func (s *Server) queryAddressBalance(bech32Addr string) (*AddressBalance, error) {
+	timeout := s.config.BalanceSources.Timeout
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+
+	var lastErr error
+	for _, src := range s.balanceSources {
+		if !src.breaker.Allow() {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		ucert, err := src.fetch(ctx, bech32Addr)
+		cancel()
+		if err != nil {
+			src.breaker.RecordFailure()
+			logFailure := s.logger.Debug
+			if src.name == BalanceSourceGRPC {
+				logFailure = s.logger.Warn
+			}
+			logFailure("balance source failed",
+				zap.String("source", src.name),
+				zap.String("address", bech32Addr),
+				zap.Bool("circuit_open", src.breaker.Open()),
+				zap.Error(err))
+			lastErr = err
+			continue
+		}
+
+		src.breaker.RecordSuccess()
+		return &AddressBalance{Balance: s.denom.FormatDisplay(ucert), Source: src.name}, nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no balance source available")
+	}
+	return nil, lastErr
+}
+
+// fetchBalanceGRPC runs the bank Balance gRPC query through the node's ABCI query router
+func (s *Server) fetchBalanceGRPC(ctx context.Context, bech32Addr string) (int64, error) {
+	var amount int64
+	err := queryAtLatestHeight(ctx, s.config.ChainRPCURL, func(ctx context.Context, height int64) error {
+		var err error
+		amount, err = s.fetchBalanceGRPCAtHeight(ctx, bech32Addr, height)
+		return err
+	})
+	return amount, err
+}
+
+// fetchBalanceGRPCAtHeight runs the bank Balance query pinned to height (0 for latest)
+func (s *Server) fetchBalanceGRPCAtHeight(ctx context.Context, bech32Addr string, height int64) (int64, error) {
+	reqBz, err := (&banktypes.QueryBalanceRequest{Address: bech32Addr, Denom: s.denom.Base}).Marshal()
+	if err != nil {
+		return 0, err
+	}
+
+	params := url.Values{}
+	params.Set("path", strconv.Quote("/cosmos.bank.v1beta1.Query/Balance"))
+	params.Set("data", "0x"+hex.EncodeToString(reqBz))
+	if height > 0 {
+		params.Set("height", strconv.FormatInt(height, 10))
+	}
+	rpcURL := fmt.Sprintf("%s/abci_query?%s", s.config.ChainRPCURL, params.Encode())
+
+	body, err := getBody(ctx, rpcURL)
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		Result struct {
+			Response struct {
+				Code  uint32 `json:"code"`
+				Log   string `json:"log"`
+				Value string `json:"value"`
+			} `json:"response"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, fmt.Errorf("failed to parse abci_query response: %w", err)
+	}
+	if result.Result.Response.Code != 0 {
+		if err := checkStateVersion(result.Result.Response.Log, height); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("bank balance query failed (code %d): %s", result.Result.Response.Code, result.Result.Response.Log)
+	}
+
+	valueBz, err := base64.StdEncoding.DecodeString(result.Result.Response.Value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode bank balance response: %w", err)
+	}
+	var resp banktypes.QueryBalanceResponse
+	if err := resp.Unmarshal(valueBz); err != nil {
+		return 0, fmt.Errorf("failed to decode bank balance response: %w", err)
+	}
+	if resp.Balance == nil {
+		return 0, nil
+	}
+	if !resp.Balance.Amount.IsInt64() {
+		return 0, fmt.Errorf("balance %s overflows int64", resp.Balance.Amount)
+	}
+	return resp.Balance.Amount.Int64(), nil
+}
+
+// fetchBalanceREST queries the Cosmos REST gateway, retrying at the latest
+// committed height on state-versioning failures
+func (s *Server) fetchBalanceREST(ctx context.Context, bech32Addr string) (int64, error) {
+	restURL := strings.TrimRight(s.config.BalanceSources.RESTURL, "/")
+	var body []byte
+	err := queryAtLatestHeight(ctx, s.config.ChainRPCURL, func(ctx context.Context, height int64) error {
+		var err error
+		body, err = getRESTBodyAtHeight(ctx, fmt.Sprintf("%s/cosmos/bank/v1beta1/balances/%s", restURL, bech32Addr), height)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		Balances []struct {
+			Denom  string `json:"denom"`
+			Amount string `json:"amount"`
+		} `json:"balances"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, fmt.Errorf("failed to parse balances response: %w", err)
+	}
+	for _, bal := range result.Balances {
+		if bal.Denom == s.denom.Base {
+			return strconv.ParseInt(bal.Amount, 10, 64)
+		}
+	}
+	return 0, nil
+}
+
+// fetchBalanceDBEstimate estimates a balance from faucet transactions tracked in the database
+func (s *Server) fetchBalanceDBEstimate(ctx context.Context, bech32Addr string) (int64, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("database not configured")
+	}
+	return s.db.GetFaucetBalance(ctx, bech32Addr)
+}
+
+// getBody performs a GET and returns the body of a 200 response
+func getBody(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return body, nil
+}
@@ -0,0 +1,306 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const testBalanceAddr = "cert1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+
+// mockABCIBalance serves abci_query bank balance responses
+func mockABCIBalance(t *testing.T, ucert int64, calls *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if r.URL.Path != "/abci_query" {
+			http.NotFound(w, r)
+			return
+		}
+		coin := sdk.NewCoin("ucert", sdkmath.NewInt(ucert))
+		bz, err := (&banktypes.QueryBalanceResponse{Balance: &coin}).Marshal()
+		if err != nil {
+			t.Fatalf("marshal balance response: %v", err)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"result":{"response":{"code":0,"value":%q}}}`, base64.StdEncoding.EncodeToString(bz))
+	}))
+}
+
+// mockRESTBalance serves Cosmos REST bank balance responses
+func mockRESTBalance(t *testing.T, ucert int64, calls *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"balances": []map[string]string{{"denom": "ucert", "amount": fmt.Sprint(ucert)}},
+		})
+	}))
+}
+
+func failingBackend(calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		http.Error(w, "version does not exist", http.StatusInternalServerError)
+	}))
+}
+
+func newBalanceTestServer(rpcURL, restURL string, order ...string) *Server {
+	config := DefaultConfig()
+	config.ChainRPCURL = rpcURL
+	config.BalanceSources.RESTURL = restURL
+	config.BalanceSources.Order = order
+	config.BalanceSources.Timeout = time.Second
+	return NewServer(config, zap.NewNop())
+}
+
+func stubDBEstimate(s *Server, ucert int64, err error) {
+	for _, src := range s.balanceSources {
+		if src.name == BalanceSourceDBEstimate {
+			src.fetch = func(context.Context, string) (int64, error) { return ucert, err }
+		}
+	}
+}
+
+// TestQueryAddressBalanceGRPCSource tests that gRPC answers first when healthy
+func TestQueryAddressBalanceGRPCSource(t *testing.T) {
+	var grpcCalls, restCalls int32
+	rpc := mockABCIBalance(t, 2_500_000, &grpcCalls)
+	defer rpc.Close()
+	rest := mockRESTBalance(t, 1, &restCalls)
+	defer rest.Close()
+
+	s := newBalanceTestServer(rpc.URL, rest.URL, "grpc", "rest", "db_estimate")
+	bal, err := s.queryAddressBalance(testBalanceAddr)
+	if err != nil {
+		t.Fatalf("queryAddressBalance: %v", err)
+	}
+	if bal.Source != BalanceSourceGRPC || bal.Balance != "2.500000" {
+		t.Errorf("balance = %+v, want 2.500000 from grpc", bal)
+	}
+	if restCalls != 0 {
+		t.Errorf("REST called %d times, want 0", restCalls)
+	}
+}
+
+// TestQueryAddressBalanceGRPCQuery tests the bank Balance query sent through the
+// query router and the ucert to CERT conversion of its answer
+func TestQueryAddressBalanceGRPCQuery(t *testing.T) {
+	var req banktypes.QueryBalanceRequest
+	var path string
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Query().Get("path")
+		data, _ := hex.DecodeString(strings.TrimPrefix(r.URL.Query().Get("data"), "0x"))
+		if err := req.Unmarshal(data); err != nil {
+			t.Errorf("decode balance request: %v", err)
+		}
+		coin := sdk.NewCoin("ucert", sdkmath.NewInt(1_234_567))
+		bz, _ := (&banktypes.QueryBalanceResponse{Balance: &coin}).Marshal()
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"result":{"response":{"code":0,"value":%q}}}`, base64.StdEncoding.EncodeToString(bz))
+	}))
+	defer rpc.Close()
+
+	s := newBalanceTestServer(rpc.URL, "", "grpc")
+	bal, err := s.queryAddressBalance(testBalanceAddr)
+	if err != nil {
+		t.Fatalf("queryAddressBalance: %v", err)
+	}
+	if path != `"/cosmos.bank.v1beta1.Query/Balance"` || req.Address != testBalanceAddr || req.Denom != "ucert" {
+		t.Errorf("query path = %s, request = %+v", path, req)
+	}
+	if bal.Source != BalanceSourceGRPC || bal.Balance != "1.234567" {
+		t.Errorf("balance = %+v, want 1.234567 from grpc", bal)
+	}
+}
+
+// TestQueryAddressBalanceWarnsOnGRPCFailure tests that only a failing gRPC
+// source is logged at warn
+func TestQueryAddressBalanceWarnsOnGRPCFailure(t *testing.T) {
+	var grpcCalls, restCalls int32
+	rpc := failingBackend(&grpcCalls)
+	defer rpc.Close()
+	rest := failingBackend(&restCalls)
+	defer rest.Close()
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	config := DefaultConfig()
+	config.ChainRPCURL = rpc.URL
+	config.BalanceSources.RESTURL = rest.URL
+	config.BalanceSources.Timeout = time.Second
+	s := NewServer(config, zap.New(core))
+	stubDBEstimate(s, 3_000_000, nil)
+
+	bal, err := s.queryAddressBalance(testBalanceAddr)
+	if err != nil || bal.Source != BalanceSourceDBEstimate {
+		t.Fatalf("balance = %+v, err = %v; want the db_estimate fallback", bal, err)
+	}
+	warnings := logs.FilterMessage("balance source failed").All()
+	if len(warnings) != 1 || warnings[0].ContextMap()["source"] != BalanceSourceGRPC {
+		t.Errorf("warnings = %v, want one for the grpc source", warnings)
+	}
+
+	// A healthy gRPC source logs nothing
+	healthy := mockABCIBalance(t, 1, &grpcCalls)
+	defer healthy.Close()
+	config.ChainRPCURL = healthy.URL
+	s = NewServer(config, zap.New(core))
+	if _, err := s.queryAddressBalance(testBalanceAddr); err != nil {
+		t.Fatalf("queryAddressBalance: %v", err)
+	}
+	if n := logs.FilterMessage("balance source failed").Len(); n != 1 {
+		t.Errorf("warnings after a healthy query = %d, want 1", n)
+	}
+}
+
+// TestQueryAddressBalanceRESTSource tests falling through to REST when gRPC fails
+func TestQueryAddressBalanceRESTSource(t *testing.T) {
+	var grpcCalls, restCalls int32
+	rpc := failingBackend(&grpcCalls)
+	defer rpc.Close()
+	rest := mockRESTBalance(t, 7_000_001, &restCalls)
+	defer rest.Close()
+
+	s := newBalanceTestServer(rpc.URL, rest.URL, "grpc", "rest", "db_estimate")
+	bal, err := s.queryAddressBalance(testBalanceAddr)
+	if err != nil {
+		t.Fatalf("queryAddressBalance: %v", err)
+	}
+	if bal.Source != BalanceSourceREST || bal.Balance != "7.000001" {
+		t.Errorf("balance = %+v, want 7.000001 from rest", bal)
+	}
+}
+
+// TestQueryAddressBalanceDBEstimateSource tests the database estimate when the chain is unreachable
+func TestQueryAddressBalanceDBEstimateSource(t *testing.T) {
+	var grpcCalls, restCalls int32
+	rpc := failingBackend(&grpcCalls)
+	defer rpc.Close()
+	rest := failingBackend(&restCalls)
+	defer rest.Close()
+
+	s := newBalanceTestServer(rpc.URL, rest.URL, "grpc", "rest", "db_estimate")
+	stubDBEstimate(s, 100_000_000, nil)
+
+	bal, err := s.queryAddressBalance(testBalanceAddr)
+	if err != nil {
+		t.Fatalf("queryAddressBalance: %v", err)
+	}
+	if bal.Source != BalanceSourceDBEstimate || bal.Balance != "100.000000" {
+		t.Errorf("balance = %+v, want 100.000000 from db_estimate", bal)
+	}
+}
+
+// TestQueryAddressBalanceConfiguredOrder tests that source order and enablement follow config
+func TestQueryAddressBalanceConfiguredOrder(t *testing.T) {
+	var grpcCalls, restCalls int32
+	rpc := mockABCIBalance(t, 1_000_000, &grpcCalls)
+	defer rpc.Close()
+	rest := mockRESTBalance(t, 3_000_000, &restCalls)
+	defer rest.Close()
+
+	s := newBalanceTestServer(rpc.URL, rest.URL, "rest", "grpc")
+	bal, err := s.queryAddressBalance(testBalanceAddr)
+	if err != nil {
+		t.Fatalf("queryAddressBalance: %v", err)
+	}
+	if bal.Source != BalanceSourceREST {
+		t.Errorf("source = %s, want rest", bal.Source)
+	}
+	if grpcCalls != 0 {
+		t.Errorf("gRPC called %d times, want 0", grpcCalls)
+	}
+
+	// Disabled sources are never consulted
+	s = newBalanceTestServer(rpc.URL, rest.URL, "db_estimate")
+	stubDBEstimate(s, 0, errors.New("db down"))
+	if _, err := s.queryAddressBalance(testBalanceAddr); err == nil {
+		t.Error("expected error when only source fails")
+	}
+	if restCalls != 1 || grpcCalls != 0 {
+		t.Errorf("disabled sources called: rest=%d grpc=%d", restCalls, grpcCalls)
+	}
+}
+
+// TestQueryAddressBalanceSkipsOpenCircuit tests that a persistently failing source is skipped
+func TestQueryAddressBalanceSkipsOpenCircuit(t *testing.T) {
+	var grpcCalls, restCalls int32
+	rpc := failingBackend(&grpcCalls)
+	defer rpc.Close()
+	rest := mockRESTBalance(t, 5_000_000, &restCalls)
+	defer rest.Close()
+
+	s := newBalanceTestServer(rpc.URL, rest.URL, "grpc", "rest")
+	threshold := s.config.BalanceSources.FailureThreshold
+	for i := 0; i < threshold+5; i++ {
+		bal, err := s.queryAddressBalance(testBalanceAddr)
+		if err != nil || bal.Source != BalanceSourceREST {
+			t.Fatalf("query %d: balance=%+v err=%v", i, bal, err)
+		}
+	}
+	if int(grpcCalls) != threshold {
+		t.Errorf("gRPC called %d times, want %d before the circuit opened", grpcCalls, threshold)
+	}
+}
+
+// TestCircuitBreakerCooldown tests that the breaker allows a trial call after cooldown
+func TestCircuitBreakerCooldown(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	b := newCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.RecordFailure()
+	if !b.Allow() {
+		t.Fatal("breaker should stay closed below threshold")
+	}
+	b.RecordFailure()
+	if b.Allow() {
+		t.Fatal("breaker should be open at threshold")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.Allow() {
+		t.Fatal("breaker should allow a trial after cooldown")
+	}
+	if b.Allow() {
+		t.Fatal("only one trial call should be allowed")
+	}
+	b.RecordSuccess()
+	if !b.Allow() || b.Open() {
+		t.Fatal("breaker should close after a successful trial")
+	}
+}
+
+// TestGetAddressTagsBalanceSource tests the source tag on the address endpoint
+func TestGetAddressTagsBalanceSource(t *testing.T) {
+	var restCalls int32
+	rest := mockRESTBalance(t, 42_000_000, &restCalls)
+	defer rest.Close()
+
+	s := newBalanceTestServer("http://127.0.0.1:0", rest.URL, "rest")
+	req := httptest.NewRequest("GET", "/api/v1/explorer/address/"+testBalanceAddr, nil)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+
+	var resp AddressResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v (body %s)", err, rec.Body.String())
+	}
+	if resp.BalanceSource != BalanceSourceREST || resp.Balance != "42.000000" {
+		t.Errorf("response = %+v, want 42.000000 tagged rest", resp)
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// BatchLimits caps the number of items accepted by batch endpoints. Balance
+// lookups take one address per request, so there is no balance limit.
+type BatchLimits struct {
+	VerifyActions  int // POST /sybil/verify-actions
+	SybilAddresses int // POST /sybil/batch
+	Recipients     int // POST /encrypted-attestations (Per Whitepaper Section 12)
+	RevokeUIDs     int // POST /attestations/batch-revoke
+	LookupUIDs     int // POST /attestations/batch
+}
+
+// DefaultBatchLimits returns the default batch size limits
+func DefaultBatchLimits() BatchLimits {
+	return BatchLimits{
+		VerifyActions:  20,
+		SybilAddresses: 100,
+		Recipients:     50,
+		RevokeUIDs:     100, // MsgBatchRevoke accepts at most 100 UIDs
+		LookupUIDs:     100,
+	}
+}
+
+// BatchSizeError is returned when a batch exceeds its configured limit
+type BatchSizeError struct {
+	Size  int
+	Limit int
+}
+
+func (e *BatchSizeError) Error() string {
+	return fmt.Sprintf("batch size %d exceeds limit %d", e.Size, e.Limit)
+}
+
+// validateBatchSize checks a batch against its limit. A limit <= 0 is a
+// misconfiguration and admits no items rather than any number of them.
+func validateBatchSize(size, limit int) error {
+	if size > max(limit, 0) {
+		return &BatchSizeError{Size: size, Limit: max(limit, 0)}
+	}
+	return nil
+}
+
+// enforceBatchSize writes a 400 response and returns false if the batch is too large
+func (s *Server) enforceBatchSize(w http.ResponseWriter, size, limit int) bool {
+	if err := validateBatchSize(size, limit); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
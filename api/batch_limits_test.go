@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// TestValidateBatchSize tests the shared batch size check
+func TestValidateBatchSize(t *testing.T) {
+	if err := validateBatchSize(5, 5); err != nil {
+		t.Errorf("batch at limit rejected: %v", err)
+	}
+	// A missing or negative limit fails closed instead of disabling the check
+	for _, limit := range []int{0, -1} {
+		if err := validateBatchSize(1, limit); err == nil || err.Error() != "batch size 1 exceeds limit 0" {
+			t.Errorf("limit %d: err = %v, want the batch rejected", limit, err)
+		}
+	}
+	if err := validateBatchSize(0, 0); err != nil {
+		t.Errorf("empty batch rejected: %v", err)
+	}
+	err := validateBatchSize(6, 5)
+	if err == nil {
+		t.Fatal("expected error for oversized batch")
+	}
+	if err.Error() != "batch size 6 exceeds limit 5" {
+		t.Errorf("error = %q", err.Error())
+	}
+}
+
+func testAuthToken(t *testing.T, secret []byte, address string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"address": address,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+// TestBatchEndpointsEnforceConfiguredLimits tests each batch endpoint against its configured limit
+func TestBatchEndpointsEnforceConfiguredLimits(t *testing.T) {
+	config := DefaultConfig()
+	config.BatchLimits.VerifyActions = 2
+	config.BatchLimits.SybilAddresses = 3
+	config.BatchLimits.Recipients = 4
+	server := NewServer(config, zap.NewNop())
+	token := testAuthToken(t, config.JWTSecret, "cert1batchtester")
+
+	items := func(n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = fmt.Sprintf("cert1addr%d", i)
+		}
+		return out
+	}
+
+	actions := make([]VerifyActionRequest, 3)
+	for i := range actions {
+		actions[i] = VerifyActionRequest{Address: "0xabc", Chain: "unsupported", Action: "swap"}
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		body  interface{}
+		auth  bool
+		size  int
+		limit int
+	}{
+		{
+			name:  "verify actions",
+			path:  "/api/v1/sybil/verify-actions",
+			body:  actions,
+			size:  3,
+			limit: 2,
+		},
+		{
+			name:  "sybil batch",
+			path:  "/api/v1/sybil/batch",
+			body:  BatchCheckRequest{Addresses: items(4)},
+			size:  4,
+			limit: 3,
+		},
+		{
+			name: "encrypted attestation recipients",
+			path: "/api/v1/encrypted-attestations",
+			body: CreateEncryptedAttestationRequest{
+				SchemaUID:  "schema-1",
+				IPFSCID:    "bafy-test",
+				Recipients: items(5),
+			},
+			auth:  true,
+			size:  5,
+			limit: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest("POST", tt.path, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.auth {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusBadRequest, rec.Body.String())
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			want := fmt.Sprintf("batch size %d exceeds limit %d", tt.size, tt.limit)
+			if resp.Message != want {
+				t.Errorf("message = %q, want %q", resp.Message, want)
+			}
+		})
+	}
+}
+
+// TestBatchEndpointsAllowBatchAtLimit tests that a batch exactly at the limit passes the size check
+func TestBatchEndpointsAllowBatchAtLimit(t *testing.T) {
+	config := DefaultConfig()
+	config.BatchLimits.VerifyActions = 1
+	server := NewServer(config, zap.NewNop())
+
+	body, _ := json.Marshal([]VerifyActionRequest{{Address: "0xabc", Chain: "unsupported", Action: "swap"}})
+	req := newJSONRequest("POST", "/api/v1/sybil/verify-actions", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
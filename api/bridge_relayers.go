@@ -0,0 +1,115 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Bridge relayer confirmations
+//
+// POST /bridge/transfer/{transfer_id}/confirm must carry relayer_signature,
+// an EIP-191 personal_sign signature over
+// "<transfer_id>|<tx_hash>|<status>|<confirmations>" by one of the relayer
+// keys in Config.BridgeRelayers. With no relayers configured every
+// confirmation is rejected.
+
+// bridgeStatusRank orders transfer statuses; a confirmation may not lower it.
+// completed and failed are terminal.
+var bridgeStatusRank = map[string]int{
+	"pending":   0,
+	"confirmed": 1,
+	"completed": 2,
+	"failed":    2,
+}
+
+// ParseRelayerKeys parses a comma-separated list of hex secp256k1 public keys, compressed or uncompressed
+func ParseRelayerKeys(s string) ([]*ecdsa.PublicKey, error) {
+	var keys []*ecdsa.PublicKey
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bz, err := hex.DecodeString(strings.TrimPrefix(part, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("relayer key %q: invalid hex", part)
+		}
+		var key *ecdsa.PublicKey
+		if len(bz) == 33 {
+			key, err = crypto.DecompressPubkey(bz)
+		} else {
+			key, err = crypto.UnmarshalPubkey(bz)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("relayer key %q: %w", part, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// bridgeConfirmationMessage is the text a relayer signs to confirm a transfer
+func bridgeConfirmationMessage(transferID, txHash, status string, confirmations int) string {
+	return fmt.Sprintf("%s|%s|%s|%d", transferID, txHash, status, confirmations)
+}
+
+// SignBridgeConfirmation signs a confirmation as a relayer, returning the hex signature
+func SignBridgeConfirmation(key *ecdsa.PrivateKey, transferID, txHash, status string, confirmations int) (string, error) {
+	msg := bridgeConfirmationMessage(transferID, txHash, status, confirmations)
+	sig, err := crypto.Sign(accounts.TextHash([]byte(msg)), key)
+	if err != nil {
+		return "", err
+	}
+	sig[64] += 27
+	return "0x" + hex.EncodeToString(sig), nil
+}
+
+// verifyRelayerSignature returns the address of the configured relayer that signed message
+func (s *Server) verifyRelayerSignature(message, signature string) (common.Address, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != 65 {
+		return common.Address{}, errors.New("relayer_signature must be a 65-byte hex signature")
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return common.Address{}, errors.New("invalid relayer signature")
+	}
+
+	signer := crypto.PubkeyToAddress(*pub)
+	for _, key := range s.config.BridgeRelayers {
+		if crypto.PubkeyToAddress(*key) == signer {
+			return signer, nil
+		}
+	}
+	return common.Address{}, errors.New("signature is not from an allowed relayer")
+}
+
+// checkBridgeStatusTransition rejects confirmations that would move a transfer backward
+func checkBridgeStatusTransition(current, next string, currentConfirmations, nextConfirmations int) error {
+	if next != "" && next != current {
+		nextRank, ok := bridgeStatusRank[next]
+		if !ok {
+			return fmt.Errorf("unknown status %q", next)
+		}
+		if current == "completed" || current == "failed" {
+			return fmt.Errorf("transfer is already %s", current)
+		}
+		if nextRank < bridgeStatusRank[current] {
+			return fmt.Errorf("cannot move transfer from %s back to %s", current, next)
+		}
+	}
+	if nextConfirmations > 0 && nextConfirmations < currentConfirmations {
+		return fmt.Errorf("confirmations cannot decrease from %d to %d", currentConfirmations, nextConfirmations)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// testRelayerKey is the bridge relayer test servers allow
+var testRelayerKey, _ = crypto.GenerateKey()
+
+// testConfirmBody is a confirm request body signed by key
+func testConfirmBody(key *ecdsa.PrivateKey, transferID, txHash, status string, confirmations int) string {
+	sig, _ := SignBridgeConfirmation(key, transferID, txHash, status, confirmations)
+	body, _ := json.Marshal(map[string]any{"tx_hash": txHash, "status": status, "confirmations": confirmations, "relayer_signature": sig})
+	return string(body)
+}
+
+func TestParseRelayerKeys(t *testing.T) {
+	compressed := "0x" + hex.EncodeToString(crypto.CompressPubkey(&testRelayerKey.PublicKey))
+	uncompressed := hex.EncodeToString(crypto.FromECDSAPub(&testRelayerKey.PublicKey))
+	keys, err := ParseRelayerKeys(compressed + ", " + uncompressed + ",")
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("keys = %v, err = %v", keys, err)
+	}
+	if crypto.PubkeyToAddress(*keys[0]) != crypto.PubkeyToAddress(testRelayerKey.PublicKey) || !keys[0].Equal(keys[1]) {
+		t.Error("parsed keys do not match the relayer key")
+	}
+	if _, err := ParseRelayerKeys("0x02deadbeef"); err == nil {
+		t.Error("expected error for a malformed key")
+	}
+}
+
+func TestConfirmTransferRelayerSignature(t *testing.T) {
+	s := newClockTestServer(newFakeClock(time.Unix(1_750_000_000, 0)))
+	ctx := context.Background()
+	_ = s.bridgeTransfers.CreateBridgeTransfer(ctx, &database.BridgeTransfer{TransferID: "0xrelay", Status: "pending"})
+
+	confirm := func(body string) *httptest.ResponseRecorder {
+		req := newJSONRequest("POST", "/api/v1/bridge/transfer/0xrelay/confirm", strings.NewReader(body))
+		SignServiceRequest(req, "bridge-validator", []byte("validator-secret"), s.clock.Now())
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec
+	}
+	status := func() string {
+		got, _ := s.bridgeTransfers.GetBridgeTransfer(ctx, "0xrelay")
+		return got.Status
+	}
+
+	t.Run("valid relayer signature", func(t *testing.T) {
+		if rec := confirm(testConfirmBody(testRelayerKey, "0xrelay", "0xlock", "confirmed", 6)); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+		}
+		if got := status(); got != "confirmed" {
+			t.Errorf("transfer status = %q, want confirmed", got)
+		}
+	})
+
+	t.Run("invalid relayer signature", func(t *testing.T) {
+		stranger, _ := crypto.GenerateKey()
+		tampered := strings.Replace(testConfirmBody(testRelayerKey, "0xrelay", "0xlock", "confirmed", 12), `"status":"confirmed"`, `"status":"completed"`, 1)
+		for name, body := range map[string]string{
+			"unknown relayer": testConfirmBody(stranger, "0xrelay", "0xlock", "completed", 12),
+			"tampered fields": tampered,
+			"other transfer":  testConfirmBody(testRelayerKey, "0xother", "0xlock", "completed", 12),
+			"missing":         `{"tx_hash":"0xlock","status":"completed","confirmations":12}`,
+			"malformed hex":   `{"status":"completed","relayer_signature":"0xnothex"}`,
+		} {
+			if rec := confirm(body); rec.Code != http.StatusUnauthorized {
+				t.Errorf("%s: status = %d, want 401 (%s)", name, rec.Code, rec.Body.String())
+			}
+		}
+		if got := status(); got != "confirmed" {
+			t.Errorf("transfer status = %q after rejected confirmations", got)
+		}
+	})
+
+	t.Run("backward moves are rejected", func(t *testing.T) {
+		if rec := confirm(testConfirmBody(testRelayerKey, "0xrelay", "", "confirmed", 3)); rec.Code != http.StatusConflict {
+			t.Errorf("fewer confirmations: status = %d, want 409", rec.Code)
+		}
+		if rec := confirm(testConfirmBody(testRelayerKey, "0xrelay", "0xlock", "completed", 12)); rec.Code != http.StatusOK {
+			t.Fatalf("complete: status = %d: %s", rec.Code, rec.Body.String())
+		}
+		// Replaying the earlier confirmation must not reopen the transfer
+		for _, next := range []string{"confirmed", "pending", "failed"} {
+			if rec := confirm(testConfirmBody(testRelayerKey, "0xrelay", "", next, 0)); rec.Code != http.StatusConflict {
+				t.Errorf("completed -> %s: status = %d, want 409", next, rec.Code)
+			}
+		}
+		if got := status(); got != "completed" {
+			t.Errorf("transfer status = %q, want completed", got)
+		}
+	})
+
+	t.Run("no relayers configured", func(t *testing.T) {
+		config := DefaultConfig()
+		config.ServiceSecrets = map[string][]byte{"bridge-validator": []byte("validator-secret")}
+		open := NewServer(config, zap.NewNop())
+		_ = open.bridgeTransfers.CreateBridgeTransfer(ctx, &database.BridgeTransfer{TransferID: "0xrelay", Status: "pending"})
+
+		req := newJSONRequest("POST", "/api/v1/bridge/transfer/0xrelay/confirm", strings.NewReader(testConfirmBody(testRelayerKey, "0xrelay", "", "confirmed", 1)))
+		SignServiceRequest(req, "bridge-validator", []byte("validator-secret"), time.Now())
+		rec := httptest.NewRecorder()
+		open.router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want 401", rec.Code)
+		}
+	})
+}
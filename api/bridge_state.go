@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// DefaultSupportedChains returns the built-in bridge chain configuration
+func DefaultSupportedChains() []SupportedChain {
+	return []SupportedChain{
+		{ChainID: 951753, Name: "CERT Chain (EVM)", Symbol: "CERT", BridgeAddr: "0x...", IsActive: true, MinAmount: "1", MaxAmount: "1000000", Fee: "0.1"},
+		{ChainID: 1, Name: "Ethereum", Symbol: "ETH", BridgeAddr: "0x...", IsActive: true, MinAmount: "1", MaxAmount: "1000000", Fee: "0.1"},
+		{ChainID: 42161, Name: "Arbitrum One", Symbol: "ARB", BridgeAddr: "0x...", IsActive: true, MinAmount: "1", MaxAmount: "1000000", Fee: "0.05"},
+		{ChainID: 137, Name: "Polygon", Symbol: "MATIC", BridgeAddr: "0x...", IsActive: false, MinAmount: "1", MaxAmount: "1000000", Fee: "0.05"},
+	}
+}
+
+// LoadSupportedChains reads a JSON array of SupportedChain from path
+func LoadSupportedChains(path string) ([]SupportedChain, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var chains []SupportedChain
+	if err := json.Unmarshal(bz, &chains); err != nil {
+		return nil, fmt.Errorf("failed to parse bridge chains: %w", err)
+	}
+	if err := validateSupportedChains(chains); err != nil {
+		return nil, err
+	}
+	return chains, nil
+}
+
+func validateSupportedChains(chains []SupportedChain) error {
+	seen := make(map[uint64]bool, len(chains))
+	for _, c := range chains {
+		if c.ChainID == 0 {
+			return fmt.Errorf("bridge chain %q has no chain_id", c.Name)
+		}
+		if seen[c.ChainID] {
+			return fmt.Errorf("duplicate bridge chain_id %d", c.ChainID)
+		}
+		seen[c.ChainID] = true
+	}
+	return nil
+}
+
+// bridgeState holds one server's bridge chain configuration. The list is
+// swapped atomically on reload so readers never lock.
+type bridgeState struct {
+	chains atomic.Pointer[[]SupportedChain]
+}
+
+func newBridgeState(chains []SupportedChain) *bridgeState {
+	b := &bridgeState{}
+	b.setChains(chains)
+	return b
+}
+
+// supportedChains returns the current chain list; callers must not modify it
+func (b *bridgeState) supportedChains() []SupportedChain {
+	return *b.chains.Load()
+}
+
+// setChains replaces the chain list with a copy of chains
+func (b *bridgeState) setChains(chains []SupportedChain) {
+	snapshot := append([]SupportedChain(nil), chains...)
+	b.chains.Store(&snapshot)
+}
+
+// bridgeTransferStore persists bridge transfers; *database.DB implements it
+type bridgeTransferStore interface {
+	CreateBridgeTransfer(ctx context.Context, t *database.BridgeTransfer) error
+	GetBridgeTransfer(ctx context.Context, transferID string) (*database.BridgeTransfer, error)
+	ListBridgeTransfersByAddress(ctx context.Context, address string, limit, offset int) ([]database.BridgeTransfer, int, error)
+	UpdateBridgeTransferStatus(ctx context.Context, transferID string, update database.BridgeTransferUpdate) (*database.BridgeTransfer, error)
+	GetBridgeTransferStats(ctx context.Context) (database.BridgeTransferStats, error)
+}
+
+// memoryBridgeTransferStore keeps transfers in memory (used when no database
+// is configured). Only copies leave the map.
+type memoryBridgeTransferStore struct {
+	mu        sync.RWMutex
+	transfers map[string]*database.BridgeTransfer
+}
+
+func newMemoryBridgeTransferStore() *memoryBridgeTransferStore {
+	return &memoryBridgeTransferStore{transfers: make(map[string]*database.BridgeTransfer)}
+}
+
+func (m *memoryBridgeTransferStore) CreateBridgeTransfer(_ context.Context, t *database.BridgeTransfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, dup := m.transfers[t.TransferID]; dup {
+		return fmt.Errorf("bridge transfer %s already exists", t.TransferID)
+	}
+	stored := *t
+	m.transfers[t.TransferID] = &stored
+	return nil
+}
+
+func (m *memoryBridgeTransferStore) GetBridgeTransfer(_ context.Context, transferID string) (*database.BridgeTransfer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.transfers[transferID]
+	if !ok {
+		return nil, nil
+	}
+	out := *t
+	return &out, nil
+}
+
+func (m *memoryBridgeTransferStore) ListBridgeTransfersByAddress(_ context.Context, address string, limit, offset int) ([]database.BridgeTransfer, int, error) {
+	m.mu.RLock()
+	var matched []database.BridgeTransfer
+	for _, t := range m.transfers {
+		if t.Sender == address || t.Recipient == address {
+			matched = append(matched, *t)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].CreatedAt.After(matched[j].CreatedAt)
+		}
+		return matched[i].TransferID < matched[j].TransferID
+	})
+	if offset >= len(matched) {
+		return nil, len(matched), nil
+	}
+	return matched[offset:min(offset+limit, len(matched))], len(matched), nil
+}
+
+func (m *memoryBridgeTransferStore) UpdateBridgeTransferStatus(_ context.Context, transferID string, update database.BridgeTransferUpdate) (*database.BridgeTransfer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.transfers[transferID]
+	if !ok {
+		return nil, nil
+	}
+	if update.TxHash != "" {
+		t.TxHash = update.TxHash
+	}
+	if update.Status != "" {
+		t.Status = update.Status
+	}
+	if update.Confirmations > 0 {
+		t.Confirmations = update.Confirmations
+	}
+	if update.CompletedAt != nil {
+		completedAt := *update.CompletedAt
+		t.CompletedAt = &completedAt
+	}
+	out := *t
+	return &out, nil
+}
+
+func (m *memoryBridgeTransferStore) GetBridgeTransferStats(_ context.Context) (database.BridgeTransferStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	st := database.BridgeTransferStats{Total: len(m.transfers)}
+	volume := big.NewInt(0)
+	for _, t := range m.transfers {
+		if t.Status == "pending" || t.Status == "confirmed" {
+			st.Pending++
+		} else if t.Status == "completed" {
+			st.Completed++
+		}
+		if amt, ok := new(big.Int).SetString(t.Amount, 10); ok {
+			volume.Add(volume, amt)
+		}
+	}
+	st.Volume = volume.String()
+	return st, nil
+}
+
+// ReloadBridgeChains swaps in a new bridge chain configuration without
+// blocking in-flight requests
+func (s *Server) ReloadBridgeChains(chains []SupportedChain) error {
+	if err := validateSupportedChains(chains); err != nil {
+		return err
+	}
+	s.bridge.setChains(chains)
+	return nil
+}
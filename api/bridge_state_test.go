@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// TestBridgeChainsReloadConcurrentReads exercises reads and writes during reloads; run with -race
+func TestBridgeChainsReloadConcurrentReads(t *testing.T) {
+	config := DefaultConfig()
+	s := NewServer(config, zap.NewNop())
+	alt := []SupportedChain{{ChainID: 10, Name: "Optimism", IsActive: true}}
+
+	stop := make(chan struct{})
+	reloaded := make(chan struct{})
+	go func() {
+		defer close(reloaded)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			chains := config.BridgeChains
+			if i%2 == 0 {
+				chains = alt
+			}
+			if err := s.ReloadBridgeChains(chains); err != nil {
+				t.Errorf("reload: %v", err)
+				return
+			}
+		}
+	}()
+
+	var readers sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		readers.Add(1)
+		go func(r int) {
+			defer readers.Done()
+			sender := fmt.Sprintf("cert1reader%d", r)
+			for i := 0; i < 150; i++ {
+				if i%3 == 2 {
+					body := fmt.Sprintf(`{"sender":%q,"amount":"5","target_chain_id":1}`, sender)
+					rec := httptest.NewRecorder()
+					s.router.ServeHTTP(rec, newJSONRequest("POST", "/api/v1/bridge/lock", strings.NewReader(body)))
+					if rec.Code != http.StatusOK {
+						t.Errorf("lock = %d: %s", rec.Code, rec.Body.String())
+						return
+					}
+				}
+				for _, path := range []string{"/api/v1/bridge/chains", "/api/v1/bridge/stats", "/api/v1/bridge/history/" + sender} {
+					rec := httptest.NewRecorder()
+					s.router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+					if rec.Code != http.StatusOK {
+						t.Errorf("GET %s = %d", path, rec.Code)
+						return
+					}
+				}
+			}
+		}(r)
+	}
+	readers.Wait()
+	close(stop)
+	<-reloaded
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/bridge/stats", nil))
+	var stats map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode stats: %v", err)
+	}
+	if stats["total_transfers"] != float64(4*50) {
+		t.Errorf("total_transfers = %v, want %d", stats["total_transfers"], 4*50)
+	}
+}
+
+// TestBridgeStateIsPerServer tests that transfers on one server are invisible to another
+func TestBridgeStateIsPerServer(t *testing.T) {
+	ctx := context.Background()
+	a := NewServer(DefaultConfig(), zap.NewNop())
+	b := NewServer(DefaultConfig(), zap.NewNop())
+	_ = a.bridgeTransfers.CreateBridgeTransfer(ctx, &database.BridgeTransfer{TransferID: "only-a", Status: "pending"})
+
+	if got, _ := b.bridgeTransfers.GetBridgeTransfer(ctx, "only-a"); got != nil {
+		t.Fatal("transfer leaked across servers")
+	}
+
+	// Returned transfers are copies; mutating one does not touch stored state
+	got, _ := a.bridgeTransfers.GetBridgeTransfer(ctx, "only-a")
+	got.Status = "completed"
+	if stored, _ := a.bridgeTransfers.GetBridgeTransfer(ctx, "only-a"); stored.Status != "pending" {
+		t.Errorf("stored status = %q, want pending", stored.Status)
+	}
+}
+
+// TestBridgeTransferLifecycle tests lock, validator confirmation and paged history through the handlers
+func TestBridgeTransferLifecycle(t *testing.T) {
+	clock := newFakeClock(time.Unix(1_750_000_000, 0))
+	s := newClockTestServer(clock)
+
+	lock := func(sender string) string {
+		rec := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"sender":%q,"recipient":"0xdest","amount":"250","source_chain_id":951753,"target_chain_id":1}`, sender)
+		s.router.ServeHTTP(rec, newJSONRequest("POST", "/api/v1/bridge/lock", strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("lock = %d: %s", rec.Code, rec.Body.String())
+		}
+		var out struct {
+			TransferID string `json:"transfer_id"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		clock.Advance(time.Second)
+		return out.TransferID
+	}
+	ids := []string{lock("cert1alice"), lock("cert1alice"), lock("cert1bob")}
+
+	req := newJSONRequest("POST", "/api/v1/bridge/transfer/"+ids[0]+"/confirm", strings.NewReader(testConfirmBody(testRelayerKey, ids[0], "0xlock", "completed", 12)))
+	SignServiceRequest(req, "bridge-validator", []byte("validator-secret"), clock.Now())
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	var confirmed BridgeTransfer
+	if err := json.Unmarshal(rec.Body.Bytes(), &confirmed); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("confirm = %d: %s", rec.Code, rec.Body.String())
+	}
+	if confirmed.Status != "completed" || confirmed.TxHash != "0xlock" || confirmed.Confirmations != 12 ||
+		confirmed.CompletedAt == nil || confirmed.CompletedAt.Unix() != clock.Now().Unix() {
+		t.Errorf("confirmed = %+v", confirmed)
+	}
+
+	get := func(path string, out any) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", path, rec.Code, rec.Body.String())
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), out)
+	}
+
+	var history struct {
+		Transfers []BridgeTransfer `json:"transfers"`
+		Total     int              `json:"total"`
+	}
+	get("/api/v1/bridge/history/cert1alice?limit=1", &history)
+	if history.Total != 2 || len(history.Transfers) != 1 || history.Transfers[0].TransferID != ids[1] {
+		t.Errorf("page 1 = %+v", history)
+	}
+	get("/api/v1/bridge/history/cert1alice?limit=1&page=2", &history)
+	if len(history.Transfers) != 1 || history.Transfers[0].TransferID != ids[0] || history.Transfers[0].Status != "completed" {
+		t.Errorf("page 2 = %+v", history)
+	}
+	get("/api/v1/bridge/history/0xdest", &history)
+	if history.Total != 3 {
+		t.Errorf("recipient history total = %d, want 3", history.Total)
+	}
+
+	var stats map[string]any
+	get("/api/v1/bridge/stats", &stats)
+	if stats["total_transfers"] != float64(3) || stats["pending_transfers"] != float64(2) ||
+		stats["completed_transfers"] != float64(1) || stats["total_volume"] != "750" {
+		t.Errorf("stats = %v", stats)
+	}
+}
+
+func TestLoadSupportedChains(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	chains, err := LoadSupportedChains(write("ok.json", `[{"chain_id":10,"name":"Optimism","is_active":true}]`))
+	if err != nil || len(chains) != 1 || chains[0].ChainID != 10 {
+		t.Fatalf("chains = %+v, err = %v", chains, err)
+	}
+	if _, err := LoadSupportedChains(write("dup.json", `[{"chain_id":1},{"chain_id":1}]`)); err == nil {
+		t.Error("expected error for duplicate chain_id")
+	}
+	if _, err := LoadSupportedChains(write("zero.json", `[{"name":"x"}]`)); err == nil {
+		t.Error("expected error for missing chain_id")
+	}
+
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	if err := s.ReloadBridgeChains([]SupportedChain{{ChainID: 1}, {ChainID: 1}}); err == nil {
+		t.Error("invalid reload accepted")
+	}
+	if n := len(s.bridge.supportedChains()); n != len(DefaultSupportedChains()) {
+		t.Errorf("chains after rejected reload = %d, want defaults", n)
+	}
+}
@@ -0,0 +1,651 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"go.uber.org/zap"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+// Bulk CSV issuance
+//
+// POST /api/v1/attestations/bulk-upload lets an institution issue one
+// attestation per CSV row instead of one request each. The multipart form
+// carries schema_uid (and optionally revocable and tags) followed by the CSV
+// as the file part; the fields must come first so the CSV can be validated as
+// it streams in. The header row names the columns: recipient, every schema
+// field, and optionally expiration_time.
+//
+// Every row is validated before anything is submitted. If any row is invalid
+// nothing is issued and the report lists each row's error. Otherwise rows are
+// issued as MsgBatchAttest transactions of up to MaxBatchAttestEntries rows;
+// each transaction is all-or-nothing, and a failed transaction stops the
+// upload, leaving the rows after it unsubmitted.
+
+// Status of a BulkUploadRow
+const (
+	bulkRowCreated      = "created"
+	bulkRowInvalid      = "invalid"
+	bulkRowFailed       = "failed"        // Its transaction was rejected
+	bulkRowNotSubmitted = "not_submitted" // Another row was invalid or an earlier transaction failed
+)
+
+// Reserved CSV columns; every other column must name a schema field
+const (
+	bulkColumnRecipient  = "recipient"
+	bulkColumnExpiration = "expiration_time"
+)
+
+// bulkUploadFormOverhead is the multipart framing and form fields allowed on top of the CSV
+const bulkUploadFormOverhead = 64 << 10
+
+// BulkUploadConfig configures POST /attestations/bulk-upload
+type BulkUploadConfig struct {
+	MaxFileBytes int64 // Largest CSV accepted
+	MaxRows      int   // Most data rows in one CSV
+}
+
+// DefaultBulkUploadConfig accepts CSVs of up to 10 MiB and 10,000 rows
+func DefaultBulkUploadConfig() BulkUploadConfig {
+	return BulkUploadConfig{MaxFileBytes: 10 << 20, MaxRows: 10000}
+}
+
+// BulkUploadRow is the outcome of one CSV data row (row 1 is the first row after the header)
+type BulkUploadRow struct {
+	Row       int    `json:"row"`
+	Recipient string `json:"recipient,omitempty"`
+	Status    string `json:"status"`
+	UID       string `json:"uid,omitempty"`
+	TxHash    string `json:"tx_hash,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkUploadReport is the per-row result of a bulk upload
+type BulkUploadReport struct {
+	SchemaUID    string          `json:"schema_uid"`
+	TotalRows    int             `json:"total_rows"`
+	InvalidRows  int             `json:"invalid_rows"`
+	Created      int             `json:"created"`
+	Transactions []string        `json:"transactions"`
+	Rows         []BulkUploadRow `json:"rows"`
+}
+
+// bulkUploadColumns maps CSV columns to the recipient, expiration and schema fields
+type bulkUploadColumns struct {
+	recipient  int
+	expiration int   // -1 when absent
+	fields     []int // Column of each schema field, in schema order
+	count      int
+}
+
+// parseBulkUploadHeader matches a CSV header against the schema's fields
+func parseBulkUploadHeader(header []string, fields []attestationtypes.SchemaField) (bulkUploadColumns, error) {
+	cols := bulkUploadColumns{recipient: -1, expiration: -1, fields: make([]int, len(fields)), count: len(header)}
+	byName := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // Spreadsheet exports often start with a BOM
+		}
+		if _, dup := byName[name]; dup {
+			return cols, fmt.Errorf("duplicate column %q", name)
+		}
+		byName[name] = i
+	}
+
+	var ok bool
+	if cols.recipient, ok = byName[bulkColumnRecipient]; !ok {
+		return cols, fmt.Errorf("missing %q column", bulkColumnRecipient)
+	}
+	delete(byName, bulkColumnRecipient)
+	if i, ok := byName[bulkColumnExpiration]; ok {
+		cols.expiration = i
+		delete(byName, bulkColumnExpiration)
+	}
+	for i, f := range fields {
+		if !isSupportedABIType(f.Type) {
+			return cols, fmt.Errorf("schema field %s has type %s, which CSV upload does not support", f.Name, f.Type)
+		}
+		if cols.fields[i], ok = byName[f.Name]; !ok {
+			return cols, fmt.Errorf("missing column for schema field %q", f.Name)
+		}
+		delete(byName, f.Name)
+	}
+	for name := range byName {
+		return cols, fmt.Errorf("column %q is not a schema field", name)
+	}
+	return cols, nil
+}
+
+// parseBulkUploadRow validates one CSV record and builds its attestation entry
+func parseBulkUploadRow(record []string, cols bulkUploadColumns, schema string, fields []attestationtypes.SchemaField) (attestationtypes.BatchAttestEntry, error) {
+	var entry attestationtypes.BatchAttestEntry
+	if strings.TrimSpace(record[cols.recipient]) == "" {
+		return entry, errors.New("recipient is required")
+	}
+	recipient, err := certAccAddress(record[cols.recipient])
+	if err != nil {
+		return entry, fmt.Errorf("invalid recipient: %v", err)
+	}
+	entry.Recipient = recipient
+
+	if cols.expiration >= 0 {
+		if v := strings.TrimSpace(record[cols.expiration]); v != "" {
+			entry.ExpirationTime, err = parseBulkExpiration(v)
+			if err != nil {
+				return entry, err
+			}
+		}
+	}
+
+	values := make([]string, len(fields))
+	for i, col := range cols.fields {
+		values[i] = record[col]
+	}
+	entry.Data, err = encodeSchemaValues(fields, values)
+	if err != nil {
+		return entry, err
+	}
+	// The keeper's own check, so a row that passes here is not rejected on chain
+	if err := attestationtypes.ValidateSchemaData(schema, entry.Data); err != nil {
+		return entry, err
+	}
+	return entry, nil
+}
+
+// parseBulkExpiration accepts a unix timestamp or an RFC 3339 time
+func parseBulkExpiration(v string) (int64, error) {
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+		return n, nil
+	}
+	if t, err := parseTimestamp(v); err == nil && !t.IsZero() {
+		return t.Unix(), nil
+	}
+	return 0, fmt.Errorf("invalid expiration_time %q: expected a unix timestamp or RFC 3339 time", v)
+}
+
+// encodeSchemaValues ABI-encodes CSV values for the schema fields, in order.
+// Addresses take 0x hex or bech32, integers decimal or 0x hex, bytes hex and
+// booleans true/false; strings are taken verbatim.
+func encodeSchemaValues(fields []attestationtypes.SchemaField, values []string) ([]byte, error) {
+	headSize := len(fields) * abiWordSize
+	head := make([]byte, 0, headSize)
+	var tail []byte
+	for i, f := range fields {
+		word, dynamic, err := encodeABIValue(f.Type, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if dynamic == nil {
+			head = append(head, word...)
+			continue
+		}
+		head = append(head, abiUintWord(new(big.Int).SetInt64(int64(headSize+len(tail))))...)
+		tail = append(tail, dynamic...)
+	}
+	return append(head, tail...), nil
+}
+
+// encodeABIValue encodes raw as typ: a head word for static types, or the
+// length-prefixed tail for string and bytes
+func encodeABIValue(typ, raw string) (word, dynamic []byte, err error) {
+	if typ != "string" {
+		raw = strings.TrimSpace(raw)
+	}
+	switch {
+	case typ == "string":
+		if !utf8.ValidString(raw) {
+			return nil, nil, errors.New("invalid UTF-8")
+		}
+		return nil, abiDynamicTail([]byte(raw)), nil
+	case typ == "bytes":
+		b, err := decodeHexValue(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, abiDynamicTail(b), nil
+	case typ == "address":
+		addr, err := parseABIAddress(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		word = make([]byte, abiWordSize)
+		copy(word[abiWordSize-len(addr):], addr)
+		return word, nil, nil
+	case typ == "bool":
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid bool %q", raw)
+		}
+		word = make([]byte, abiWordSize)
+		if v {
+			word[abiWordSize-1] = 1
+		}
+		return word, nil, nil
+	case strings.HasPrefix(typ, "bytes"):
+		n, _ := strconv.Atoi(strings.TrimPrefix(typ, "bytes"))
+		b, err := decodeHexValue(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(b) != n {
+			return nil, nil, fmt.Errorf("expected %d bytes, got %d", n, len(b))
+		}
+		word = make([]byte, abiWordSize)
+		copy(word, b)
+		return word, nil, nil
+	}
+
+	// uintN and intN
+	signed := strings.HasPrefix(typ, "int")
+	bits, _ := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(typ, "u"), "int"))
+	n, ok := new(big.Int).SetString(raw, 0)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid integer %q", raw)
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	if signed {
+		limit.Rsh(limit, 1)
+		if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return nil, nil, fmt.Errorf("%s out of range for %s", raw, typ)
+		}
+		if n.Sign() < 0 {
+			n.Add(n, new(big.Int).Lsh(big.NewInt(1), abiWordSize*8))
+		}
+	} else if n.Sign() < 0 || n.Cmp(limit) >= 0 {
+		return nil, nil, fmt.Errorf("%s out of range for %s", raw, typ)
+	}
+	return abiUintWord(n), nil, nil
+}
+
+// abiUintWord encodes a non-negative integer below 2^256 as one word
+func abiUintWord(n *big.Int) []byte {
+	return n.FillBytes(make([]byte, abiWordSize))
+}
+
+// abiDynamicTail is the length word followed by b zero-padded to a word boundary
+func abiDynamicTail(b []byte) []byte {
+	padded := (len(b) + abiWordSize - 1) / abiWordSize * abiWordSize
+	tail := make([]byte, abiWordSize+padded)
+	copy(tail, abiUintWord(big.NewInt(int64(len(b)))))
+	copy(tail[abiWordSize:], b)
+	return tail
+}
+
+// decodeHexValue decodes hex with or without a 0x prefix
+func decodeHexValue(raw string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(raw, "0x"), "0X"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex %q", raw)
+	}
+	return b, nil
+}
+
+// parseABIAddress decodes a 0x-prefixed or bech32 account address to its 20 bytes
+func parseABIAddress(raw string) ([]byte, error) {
+	if strings.HasPrefix(raw, "0x") || strings.HasPrefix(raw, "0X") {
+		b, err := decodeHexValue(raw)
+		if err != nil || len(b) != 20 {
+			return nil, fmt.Errorf("invalid address %q", raw)
+		}
+		return b, nil
+	}
+	_, b, err := bech32.DecodeAndConvert(raw)
+	if err != nil || len(b) != 20 {
+		return nil, fmt.Errorf("invalid address %q", raw)
+	}
+	return b, nil
+}
+
+// isInstitution reports whether address holds a verified credential that maps to an institutional badge
+func (s *Server) isInstitution(ctx context.Context, address string) (bool, error) {
+	creds, err := s.kyc.GetCredentialsByUser(ctx, strings.ToLower(address))
+	if err != nil {
+		return false, err
+	}
+	for _, c := range creds {
+		if c.Verified && s.isInstitutionalCredential(c.CredentialType, c.Issuer) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// bulkUploadForm is the form fields that precede the CSV part
+type bulkUploadForm struct {
+	schemaUID string
+	revocable bool
+	tags      []string
+}
+
+// readBulkUploadField applies one multipart form field to form
+func readBulkUploadField(form *bulkUploadForm, name, value string) error {
+	value = strings.TrimSpace(value)
+	switch name {
+	case "schema_uid":
+		form.schemaUID = value
+	case "revocable":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("revocable must be a boolean")
+		}
+		form.revocable = b
+	case "tags":
+		var tags []string
+		if value != "" {
+			tags = strings.Split(value, ",")
+		}
+		normalized, err := attestationtypes.NormalizeTags(tags)
+		if err != nil {
+			return err
+		}
+		form.tags = normalized
+	}
+	return nil
+}
+
+// handleBulkUploadAttestations issues one attestation per row of an uploaded CSV
+// POST /api/v1/attestations/bulk-upload[?format=csv]
+func (s *Server) handleBulkUploadAttestations(w http.ResponseWriter, r *http.Request) {
+	s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+		if s.kyc == nil {
+			s.respondError(w, http.StatusServiceUnavailable, "Database not available")
+			return
+		}
+		institution, err := s.isInstitution(r.Context(), getAuthenticatedAddress(r))
+		if err != nil {
+			s.logger.Error("failed to load credentials", zap.String("address", getAuthenticatedAddress(r)), zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to load credentials")
+			return
+		}
+		if !institution {
+			s.respondError(w, http.StatusForbidden, "Bulk upload requires a verified institutional credential")
+			return
+		}
+
+		limit := s.config.BulkUploads.MaxFileBytes
+		r.Body = http.MaxBytesReader(w, r.Body, limit+bulkUploadFormOverhead)
+		mr, err := r.MultipartReader()
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, "expected a multipart/form-data body")
+			return
+		}
+
+		form := bulkUploadForm{revocable: true}
+		var report *BulkUploadReport
+		var entries []attestationtypes.BatchAttestEntry
+		for report == nil {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				s.respondError(w, http.StatusBadRequest, "file is required")
+				return
+			}
+			if err != nil {
+				s.respondBulkUploadReadError(w, err, limit)
+				return
+			}
+			if part.FileName() == "" {
+				value, err := io.ReadAll(io.LimitReader(part, bulkUploadFormOverhead))
+				if err != nil {
+					s.respondBulkUploadReadError(w, err, limit)
+					return
+				}
+				if err := readBulkUploadField(&form, part.FormName(), string(value)); err != nil {
+					s.respondError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+				continue
+			}
+
+			if form.schemaUID == "" {
+				s.respondError(w, http.StatusBadRequest, "schema_uid is required and must precede the file")
+				return
+			}
+			var schema schemaQueryResult
+			if err := s.execCertdQueryJSON(&schema, "attestation", "schema", form.schemaUID); err != nil {
+				if strings.Contains(err.Error(), attestationtypes.ErrSchemaNotFound.Error()) {
+					s.respondError(w, http.StatusNotFound, "schema not found")
+					return
+				}
+				s.logger.Warn("failed to query schema", zap.String("uid", form.schemaUID), zap.Error(err))
+				s.respondError(w, http.StatusBadGateway, "Failed to query schema")
+				return
+			}
+			if form.revocable && !schema.Schema.Revocable {
+				s.respondError(w, http.StatusBadRequest, "schema does not allow revocable attestations")
+				return
+			}
+			fields, err := attestationtypes.ParseSchema(schema.Schema.Schema)
+			if err != nil {
+				s.respondError(w, http.StatusBadRequest, fmt.Sprintf("schema cannot be used for CSV upload: %v", err))
+				return
+			}
+
+			report = &BulkUploadReport{SchemaUID: form.schemaUID, Transactions: []string{}, Rows: []BulkUploadRow{}}
+			entries, err = s.readBulkUploadCSV(part, schema.Schema.Schema, fields, report)
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				switch {
+				case errors.As(err, &tooLarge):
+					s.respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("upload exceeds %d bytes", limit))
+				case errors.Is(err, errBulkUploadTooManyRows):
+					s.respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("CSV exceeds %d rows", s.config.BulkUploads.MaxRows))
+				default:
+					s.respondError(w, http.StatusBadRequest, err.Error())
+				}
+				return
+			}
+		}
+
+		status := http.StatusCreated
+		switch {
+		case report.TotalRows == 0:
+			s.respondError(w, http.StatusBadRequest, "CSV has no data rows")
+			return
+		case report.InvalidRows > 0:
+			status = http.StatusUnprocessableEntity
+			for i := range report.Rows {
+				if report.Rows[i].Status != bulkRowInvalid {
+					report.Rows[i].Status = bulkRowNotSubmitted
+				}
+			}
+		default:
+			if !s.submitBulkUpload(r.Context(), form, entries, report) {
+				status = http.StatusBadGateway
+			}
+		}
+
+		s.logger.Info("bulk upload processed",
+			zap.String("issuer", getAuthenticatedAddress(r)),
+			zap.String("schema_uid", form.schemaUID),
+			zap.Int("rows", report.TotalRows),
+			zap.Int("invalid", report.InvalidRows),
+			zap.Int("created", report.Created),
+		)
+		s.respondBulkUploadReport(w, r, status, report)
+	})(w, r)
+}
+
+// errBulkUploadTooManyRows stops reading a CSV with more than MaxRows data rows
+var errBulkUploadTooManyRows = errors.New("too many rows")
+
+// readBulkUploadCSV streams the CSV, recording a report row per data row and
+// returning the entries of the valid ones. Errors that make the whole file
+// unusable (header, size, read failures) are returned.
+func (s *Server) readBulkUploadCSV(file io.Reader, schema string, fields []attestationtypes.SchemaField, report *BulkUploadReport) ([]attestationtypes.BatchAttestEntry, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV is empty")
+	}
+	if err != nil {
+		return nil, bulkUploadCSVError(err)
+	}
+	cols, err := parseBulkUploadHeader(header, fields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %v", err)
+	}
+
+	var entries []attestationtypes.BatchAttestEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return nil, err
+		}
+		if report.TotalRows++; report.TotalRows > s.config.BulkUploads.MaxRows {
+			return nil, errBulkUploadTooManyRows
+		}
+
+		row := BulkUploadRow{Row: report.TotalRows}
+		var entry attestationtypes.BatchAttestEntry
+		switch {
+		case err != nil:
+			err = parseErr.Err
+		case len(record) != cols.count:
+			err = fmt.Errorf("expected %d columns, got %d", cols.count, len(record))
+		default:
+			entry, err = parseBulkUploadRow(record, cols, schema, fields)
+		}
+		row.Recipient = entry.Recipient
+		if err != nil {
+			row.Status, row.Error = bulkRowInvalid, err.Error()
+			report.InvalidRows++
+		} else {
+			entries = append(entries, entry)
+		}
+		report.Rows = append(report.Rows, row)
+	}
+}
+
+// bulkUploadCSVError unwraps read failures from a csv.ParseError so size limits are recognized
+func bulkUploadCSVError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("invalid CSV: %v", parseErr.Err)
+	}
+	return err
+}
+
+// submitBulkUpload issues the entries in MsgBatchAttest chunks, filling in
+// each row's outcome. It stops at the first failed transaction and reports
+// whether every chunk was issued.
+func (s *Server) submitBulkUpload(ctx context.Context, form bulkUploadForm, entries []attestationtypes.BatchAttestEntry, report *BulkUploadReport) bool {
+	for start := 0; start < len(entries); start += attestationtypes.MaxBatchAttestEntries {
+		chunk := entries[start:min(start+attestationtypes.MaxBatchAttestEntries, len(entries))]
+		rows := report.Rows[start : start+len(chunk)]
+
+		txHash, uids, err := s.submitBatchAttest(ctx, form, chunk)
+		if err != nil {
+			s.logger.Error("bulk upload tx failed", zap.String("schema_uid", form.schemaUID), zap.Int("first_row", rows[0].Row), zap.Error(err))
+			for i := range rows {
+				rows[i].Status, rows[i].Error, rows[i].TxHash = bulkRowFailed, err.Error(), txHash
+			}
+			for i := range report.Rows[start+len(chunk):] {
+				report.Rows[start+len(chunk)+i].Status = bulkRowNotSubmitted
+			}
+			return false
+		}
+
+		report.Transactions = append(report.Transactions, txHash)
+		for i := range rows {
+			rows[i].Status, rows[i].TxHash = bulkRowCreated, txHash
+			if i < len(uids) {
+				rows[i].UID = uids[i]
+			}
+		}
+		report.Created += len(chunk)
+	}
+	return true
+}
+
+// submitBatchAttest broadcasts one MsgBatchAttest and returns its tx hash and UIDs in entry order
+func (s *Server) submitBatchAttest(ctx context.Context, form bulkUploadForm, entries []attestationtypes.BatchAttestEntry) (string, []string, error) {
+	// Usage: certd tx attestation batch-attest [schema-uid] [recipient:data-hex[:expiration]]... [flags]
+	args := []string{"attestation", "batch-attest", form.schemaUID}
+	for _, e := range entries {
+		arg := e.Recipient + ":" + hex.EncodeToString(e.Data)
+		if e.ExpirationTime > 0 {
+			arg += ":" + strconv.FormatInt(e.ExpirationTime, 10)
+		}
+		args = append(args, arg)
+	}
+	args = append(args, "--revocable="+strconv.FormatBool(form.revocable))
+	if len(form.tags) > 0 {
+		args = append(args, "--tags", strings.Join(form.tags, ","))
+	}
+
+	txCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	var txRes certdTxResponse
+	if _, err := s.execCertdTxJSON(txCtx, &txRes, args...); err != nil {
+		var txErr *certdTxExecError
+		if errors.As(err, &txErr) && txErr.Tx.Code != 0 {
+			return txErr.Tx.TxHash, nil, fmt.Errorf("tx rejected: %s", txErr.Tx.RawLog)
+		}
+		return "", nil, errors.New("failed to submit tx")
+	}
+	if txRes.Code != 0 {
+		return txRes.TxHash, nil, fmt.Errorf("tx rejected: %s", txRes.RawLog)
+	}
+
+	var uids []string
+	if joined, ok := findTxEventAttribute(txRes, "attestation_uids"); ok && joined != "" {
+		uids = strings.Split(joined, ",")
+	}
+	if len(uids) != len(entries) {
+		s.logger.Warn("batch attest tx succeeded but its UIDs were not found in events", zap.String("txhash", txRes.TxHash))
+	}
+	return txRes.TxHash, uids, nil
+}
+
+// respondBulkUploadReadError answers a failure reading the multipart body
+func (s *Server) respondBulkUploadReadError(w http.ResponseWriter, err error, limit int64) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		s.respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("upload exceeds %d bytes", limit))
+		return
+	}
+	s.respondError(w, http.StatusBadRequest, "invalid multipart body")
+}
+
+// respondBulkUploadReport writes the report as JSON, or as a downloadable CSV for ?format=csv
+func (s *Server) respondBulkUploadReport(w http.ResponseWriter, r *http.Request, status int, report *BulkUploadReport) {
+	if r.URL.Query().Get("format") != "csv" {
+		s.respondJSON(w, status, report)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=bulk-upload-report.csv")
+	w.WriteHeader(status)
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"row", "recipient", "status", "uid", "tx_hash", "error"})
+	for _, row := range report.Rows {
+		_ = cw.Write([]string{strconv.Itoa(row.Row), row.Recipient, row.Status, row.UID, row.TxHash, row.Error})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		s.logger.Error("Failed to write bulk upload report", zap.Error(err))
+	}
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+const (
+	bulkUniversity = "cert1v968getnw3jhyh6lta047h6lta047h6l0ytjja"
+	bulkSchema     = "bytes32 diplomaHash,string degree,uint16 year"
+)
+
+// newBulkUploadServer returns a server whose caller bulkUniversity holds a
+// verified university credential, and a pointer to each batch-attest call's args
+func newBulkUploadServer(t *testing.T) (*Server, *[][]string) {
+	t.Helper()
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	s.kyc = &memoryKYCStore{credentials: []database.Credential{
+		{UserAddress: bulkUniversity, CredentialType: "UNIVERSITY", Issuer: "registrar", Verified: true},
+	}}
+
+	origCLI := runCertdCLI
+	t.Cleanup(func() { runCertdCLI = origCLI })
+	runCertdCLI = func(args ...string) ([]byte, error) {
+		if len(args) > 3 && args[2] == "schema" && args[3] == "0xdiploma" {
+			return []byte(`{"schema":{"uid":"0xdiploma","schema":"` + bulkSchema + `","revocable":true}}`), nil
+		}
+		return []byte("Error: schema not found"), errors.New("exit status 1")
+	}
+
+	var calls [][]string
+	origTx := runCertdTx
+	t.Cleanup(func() { runCertdTx = origTx })
+	runCertdTx = func(_ context.Context, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		var uids []string
+		for i := range args {
+			if strings.Contains(args[i], ":") && strings.HasPrefix(args[i], "cert1") {
+				uids = append(uids, fmt.Sprintf("0xuid%d", len(uids)+1))
+			}
+		}
+		return []byte(fmt.Sprintf(`{"txhash":"TX%d","code":0,"logs":[{"events":[{"type":"attestations_batch_created",
+			"attributes":[{"key":"attestation_uids","value":%q}]}]}]}`, len(calls), strings.Join(uids, ","))), nil
+	}
+	return s, &calls
+}
+
+// postBulkUpload sends schema_uid and the CSV as a multipart form
+func postBulkUpload(t *testing.T, s *Server, caller, query string, csvBody []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("schema_uid", "0xdiploma")
+	_ = mw.WriteField("tags", "Class-of-2026")
+	fw, _ := mw.CreateFormFile("file", "graduates.csv")
+	_, _ = fw.Write(csvBody)
+	_ = mw.Close()
+
+	req := httptest.NewRequest("POST", "/api/v1/attestations/bulk-upload"+query, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+testAuthToken(t, s.config.JWTSecret, caller))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func diplomaHash(b byte) string {
+	return "0x" + strings.Repeat("00", 31) + hex.EncodeToString([]byte{b})
+}
+
+func TestBulkUpload_ValidCSV(t *testing.T) {
+	s, calls := newBulkUploadServer(t)
+	csvBody := "\ufeffrecipient,diplomaHash,degree,year,expiration_time\n" +
+		validateCreator + "," + diplomaHash(1) + ",\"BSc, Computer Science\",2026,\n" +
+		validateAttester + "," + diplomaHash(2) + ",MSc Physics,2026,2030-06-30T00:00:00Z\n"
+
+	rec := postBulkUpload(t, s, bulkUniversity, "", []byte(csvBody))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var report BulkUploadReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.TotalRows != 2 || report.Created != 2 || report.InvalidRows != 0 || len(report.Transactions) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	for i, row := range report.Rows {
+		if row.Status != bulkRowCreated || row.UID != fmt.Sprintf("0xuid%d", i+1) || row.TxHash != "TX1" {
+			t.Errorf("row %d = %+v", i+1, row)
+		}
+	}
+
+	if len(*calls) != 1 {
+		t.Fatalf("tx calls = %d", len(*calls))
+	}
+	args := strings.Join((*calls)[0], " ")
+	if !strings.Contains(args, "attestation batch-attest 0xdiploma "+validateCreator+":") ||
+		!strings.Contains(args, ":1909008000 --revocable=true --tags class-of-2026") {
+		t.Errorf("tx args = %s", args)
+	}
+
+	// The data handed to the chain passes the keeper's schema check
+	entry := strings.Split((*calls)[0][4], ":")
+	data, _ := hex.DecodeString(entry[1])
+	if err := attestationtypes.ValidateSchemaData(bulkSchema, data); err != nil {
+		t.Errorf("encoded data rejected: %v", err)
+	}
+	if !bytes.Contains(data, []byte("BSc, Computer Science")) {
+		t.Errorf("encoded data = %x", data)
+	}
+}
+
+func TestBulkUpload_InvalidRowsSubmitNothing(t *testing.T) {
+	s, calls := newBulkUploadServer(t)
+	csvBody := "recipient,diplomaHash,degree,year\n" +
+		validateCreator + "," + diplomaHash(1) + ",BSc,2026\n" +
+		"cert1notanaddress," + diplomaHash(2) + ",BSc,2026\n" +
+		validateAttester + ",0x1234,BSc,2026\n" +
+		validateAttester + "," + diplomaHash(4) + ",BSc,70000\n" +
+		validateAttester + "," + diplomaHash(5) + ",BSc\n"
+
+	rec := postBulkUpload(t, s, bulkUniversity, "?format=csv", []byte(csvBody))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if len(*calls) != 0 {
+		t.Fatalf("tx submitted despite invalid rows: %v", *calls)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment") {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil || len(records) != 6 {
+		t.Fatalf("report = %v (%v)", records, err)
+	}
+	want := []struct{ status, errContains string }{
+		{bulkRowNotSubmitted, ""},
+		{bulkRowInvalid, "invalid recipient"},
+		{bulkRowInvalid, "expected 32 bytes"},
+		{bulkRowInvalid, "out of range for uint16"},
+		{bulkRowInvalid, "expected 4 columns"},
+	}
+	for i, w := range want {
+		row := records[i+1]
+		if row[0] != fmt.Sprint(i+1) || row[2] != w.status || !strings.Contains(row[5], w.errContains) || (w.errContains == "") != (row[5] == "") {
+			t.Errorf("row %d = %v, want %s %q", i+1, row, w.status, w.errContains)
+		}
+	}
+}
+
+func TestBulkUpload_Limits(t *testing.T) {
+	row := func(n int) string {
+		var b strings.Builder
+		b.WriteString("recipient,diplomaHash,degree,year\n")
+		for i := 0; i < n; i++ {
+			b.WriteString(validateCreator + "," + diplomaHash(byte(i)) + ",BSc,2026\n")
+		}
+		return b.String()
+	}
+
+	t.Run("oversize file", func(t *testing.T) {
+		s, calls := newBulkUploadServer(t)
+		s.config.BulkUploads.MaxFileBytes = 1024
+
+		rec := postBulkUpload(t, s, bulkUniversity, "", []byte(row(1000)))
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+		}
+		if len(*calls) != 0 {
+			t.Errorf("tx submitted for an oversize file")
+		}
+	})
+
+	t.Run("too many rows", func(t *testing.T) {
+		s, _ := newBulkUploadServer(t)
+		s.config.BulkUploads.MaxRows = 3
+
+		if rec := postBulkUpload(t, s, bulkUniversity, "", []byte(row(4))); rec.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("rows are chunked into batches", func(t *testing.T) {
+		s, calls := newBulkUploadServer(t)
+		n := attestationtypes.MaxBatchAttestEntries + 5
+
+		rec := postBulkUpload(t, s, bulkUniversity, "", []byte(row(n)))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+		}
+		var report BulkUploadReport
+		_ = json.Unmarshal(rec.Body.Bytes(), &report)
+		if len(*calls) != 2 || report.Created != n || report.Rows[n-1].TxHash != "TX2" || report.Rows[n-1].UID != "0xuid5" {
+			t.Errorf("calls = %d, report = %+v", len(*calls), report.Rows[n-1])
+		}
+	})
+}
+
+func TestBulkUpload_RequiresInstitution(t *testing.T) {
+	s, calls := newBulkUploadServer(t)
+	csvBody := "recipient,diplomaHash,degree,year\n" + validateCreator + "," + diplomaHash(1) + ",BSc,2026\n"
+
+	if rec := postBulkUpload(t, s, validateCreator, "", []byte(csvBody)); rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if len(*calls) != 0 {
+		t.Errorf("tx submitted for a non-institution caller")
+	}
+}
+
+func TestEncodeSchemaValues(t *testing.T) {
+	fields, err := attestationtypes.ParseSchema("address holder,int8 delta,bool active,bytes proof,uint256 score")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := encodeSchemaValues(fields, []string{validateCreator, "-2", "true", "0xc0ffee", "0x10"})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if err := attestationtypes.ValidateSchemaData("address holder,int8 delta,bool active,bytes proof,uint256 score", data); err != nil {
+		t.Fatalf("encoded data rejected: %v", err)
+	}
+	args, err := decodeABIArgs([]abiInput{{"holder", "address"}, {"delta", "int8"}, {"active", "bool"}, {"proof", "bytes"}, {"score", "uint256"}}, data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if args["delta"] != "-2" || args["active"] != true || args["proof"] != "0xc0ffee" || args["score"] != "16" {
+		t.Errorf("args = %v", args)
+	}
+
+	if _, err := encodeSchemaValues(fields, []string{validateCreator, "128", "true", "", "0"}); err == nil || !strings.Contains(err.Error(), "delta") {
+		t.Errorf("int8 overflow: err = %v", err)
+	}
+}
@@ -46,6 +46,11 @@ func (e *certdTxExecError) Error() string {
 
 func (e *certdTxExecError) Unwrap() error { return e.Err }
 
+// runCertdTx runs a certd tx command inside the node container; tests replace it
+var runCertdTx = func(ctx context.Context, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, "docker", append([]string{"exec", "certd", "certd"}, args...)...).CombinedOutput()
+}
+
 func (s *Server) execCertdTxJSON(ctx context.Context, out any, txArgs ...string) ([]byte, error) {
 	// Run inside docker: `certd tx ... --node tcp://localhost:26657 --chain-id ... --from ... --output json`
 	args := []string{"tx"}
@@ -70,8 +75,7 @@ func (s *Server) execCertdTxJSON(ctx context.Context, out any, txArgs ...string)
 		args = append(args, "--gas-prices", strings.TrimSpace(s.config.TxGasPrices))
 	}
 
-	cmd := exec.CommandContext(ctx, "docker", append([]string{"exec", "certd", "certd"}, args...)...)
-	buf, err := cmd.CombinedOutput()
+	buf, err := runCertdTx(ctx, args...)
 	if err != nil {
 		// Best-effort: still try to decode JSON from output so callers can surface raw_log.
 		var raw json.RawMessage
@@ -104,19 +108,13 @@ func (s *Server) execCertdTxJSON(ctx context.Context, out any, txArgs ...string)
 	return buf, nil
 }
 
-func certdTxTimestampUnix(ts string) int64 {
-	ts = strings.TrimSpace(ts)
-	if ts == "" {
-		return time.Now().Unix()
-	}
+// certdTxTimestamp is the block time of a tx result, or now when it has none
+func certdTxTimestamp(ts string) Timestamp {
 	// Cosmos SDK uses RFC3339 or RFC3339Nano depending on build.
-	if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
-		return t.Unix()
-	}
-	if t, err := time.Parse(time.RFC3339, ts); err == nil {
-		return t.Unix()
+	if t, err := parseTimestamp(ts); err == nil && !t.IsZero() {
+		return NewTimestamp(t)
 	}
-	return time.Now().Unix()
+	return NewTimestamp(time.Now())
 }
 
 func findTxEventAttribute(tx certdTxResponse, wantKey string) (string, bool) {
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Cross-chain activity fetching
+// A sybil check reads balance and nonce from every requested chain's public
+// RPC. Chains are fetched in parallel, each endpoint sees at most
+// PerChainConcurrency of our calls at once across all checks, and results are
+// cached briefly per address and chain.
+
+// CrossChainConfig tunes the per-chain RPC reads behind cross-chain sybil checks
+type CrossChainConfig struct {
+	// PerChainConcurrency caps in-flight fetches against one chain's RPC, to stay
+	// under public endpoints' rate limits (0 = unlimited)
+	PerChainConcurrency int
+	// Timeout bounds a whole multi-chain fetch; chains that have not answered are left out
+	Timeout time.Duration
+	// CacheTTL is how long a chain's activity for an address is reused (0 disables caching)
+	CacheTTL time.Duration
+}
+
+// DefaultCrossChainConfig returns the default cross-chain fetch settings
+func DefaultCrossChainConfig() CrossChainConfig {
+	return CrossChainConfig{
+		PerChainConcurrency: 4,
+		Timeout:             8 * time.Second,
+		CacheTTL:            time.Minute,
+	}
+}
+
+// chainActivityMaxEntries bounds the cached address|chain results
+const chainActivityMaxEntries = 10_000
+
+// chainActivityEntry is a cached fetchChainActivity result
+type chainActivityEntry struct {
+	data    ChainActivityData
+	expires time.Time
+}
+
+// chainActivityFetcher caches per-chain activity and holds the per-chain RPC slots
+type chainActivityFetcher struct {
+	config CrossChainConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]chainActivityEntry // Keyed by address|chain
+	slots   map[string]chan struct{}      // Keyed by chain
+}
+
+func newChainActivityFetcher(config CrossChainConfig, now func() time.Time) *chainActivityFetcher {
+	return &chainActivityFetcher{
+		config:  config,
+		now:     now,
+		entries: make(map[string]chainActivityEntry),
+		slots:   make(map[string]chan struct{}),
+	}
+}
+
+func chainActivityKey(address, chain string) string {
+	return address + "|" + chain
+}
+
+// get returns a cached, unexpired result
+func (f *chainActivityFetcher) get(address, chain string) (ChainActivityData, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := chainActivityKey(address, chain)
+	e, ok := f.entries[key]
+	if !ok {
+		return ChainActivityData{}, false
+	}
+	if !f.now().Before(e.expires) {
+		delete(f.entries, key)
+		return ChainActivityData{}, false
+	}
+	return e.data, true
+}
+
+func (f *chainActivityFetcher) put(address, chain string, data ChainActivityData) {
+	if f.config.CacheTTL <= 0 {
+		return
+	}
+	now := f.now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// Drop expired entries once the cache fills; if all are live, skip caching
+	if len(f.entries) >= chainActivityMaxEntries {
+		for key, e := range f.entries {
+			if !now.Before(e.expires) {
+				delete(f.entries, key)
+			}
+		}
+		if len(f.entries) >= chainActivityMaxEntries {
+			return
+		}
+	}
+	f.entries[chainActivityKey(address, chain)] = chainActivityEntry{data: data, expires: now.Add(f.config.CacheTTL)}
+}
+
+// acquire takes one of chain's RPC slots, returning its release, or fails when ctx ends first
+func (f *chainActivityFetcher) acquire(ctx context.Context, chain string) (func(), error) {
+	if f.config.PerChainConcurrency <= 0 {
+		return func() {}, nil
+	}
+	f.mu.Lock()
+	slots, ok := f.slots[chain]
+	if !ok {
+		slots = make(chan struct{}, f.config.PerChainConcurrency)
+		f.slots[chain] = slots
+	}
+	f.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// slowEVMStub answers eth_getBalance and eth_getTransactionCount after delay,
+// counting calls per URL path
+type slowEVMStub struct {
+	delay time.Duration
+
+	mu       sync.Mutex
+	calls    map[string]int
+	inFlight int
+	maxIn    int
+}
+
+func (s *slowEVMStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.calls[r.URL.Path]++
+	s.inFlight++
+	if s.inFlight > s.maxIn {
+		s.maxIn = s.inFlight
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	var req struct {
+		Method string `json:"method"`
+	}
+	json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+	time.Sleep(s.delay)
+	result := "0x0"
+	switch req.Method {
+	case "eth_getBalance":
+		result = "0xde0b6b3a7640000"
+	case "eth_getTransactionCount":
+		result = "0xc"
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result}) //nolint:errcheck
+}
+
+func (s *slowEVMStub) total() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, c := range s.calls {
+		n += c
+	}
+	return n
+}
+
+// withStubChains points every supported chain at rpc, on a path named after the chain
+func withStubChains(t *testing.T, rpcURL string) []string {
+	t.Helper()
+	saved := SupportedChains
+	stubbed := make(map[string]ChainConfig, len(saved))
+	var keys []string
+	for key, chain := range saved {
+		chain.RPCURL = rpcURL + "/" + key
+		stubbed[key] = chain
+		keys = append(keys, key)
+	}
+	SupportedChains = stubbed
+	t.Cleanup(func() { SupportedChains = saved })
+	return keys
+}
+
+func TestMultiChainActivityFetchesConcurrently(t *testing.T) {
+	const delay = 150 * time.Millisecond
+	stub := &slowEVMStub{delay: delay, calls: map[string]int{}}
+	rpc := httptest.NewServer(stub)
+	defer rpc.Close()
+	chains := withStubChains(t, rpc.URL)
+	if len(chains) != 6 {
+		t.Fatalf("supported chains = %v, want 6", chains)
+	}
+
+	clock := newFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	config := DefaultConfig()
+	config.Clock = clock
+	s := NewServer(config, zap.NewNop())
+
+	start := time.Now()
+	data := s.getMultiChainActivity(context.Background(), swapperAddr, chains)
+	elapsed := time.Since(start)
+	if len(data) != 6 {
+		t.Fatalf("activity = %v, want all 6 chains", data)
+	}
+	for _, chain := range chains {
+		if d := data[chain]; d.TxCount != 12 || !d.DeFiInteraction {
+			t.Errorf("%s activity = %+v", chain, d)
+		}
+	}
+	// Serially this is 12 round trips; in parallel, each chain's two calls
+	if elapsed >= 6*delay {
+		t.Errorf("fetch took %v, want chains queried concurrently", elapsed)
+	}
+	if stub.maxIn < 2 {
+		t.Errorf("max in-flight RPC calls = %d, want concurrent calls", stub.maxIn)
+	}
+	if n := stub.total(); n != 12 {
+		t.Fatalf("RPC calls = %d, want 12", n)
+	}
+
+	// Repeat checks are served from cache until the TTL passes
+	if again := s.getMultiChainActivity(context.Background(), swapperAddr, chains); len(again) != 6 {
+		t.Errorf("cached activity = %v", again)
+	}
+	if n := stub.total(); n != 12 {
+		t.Errorf("RPC calls after cached check = %d, want 12", n)
+	}
+	s.getMultiChainActivity(context.Background(), swapperAddr, []string{"base"})
+	if n := stub.total(); n != 12 {
+		t.Errorf("RPC calls after cached single-chain check = %d, want 12", n)
+	}
+
+	clock.Advance(DefaultCrossChainConfig().CacheTTL)
+	s.getMultiChainActivity(context.Background(), swapperAddr, []string{"base"})
+	if n := stub.total(); n != 14 {
+		t.Errorf("RPC calls after TTL = %d, want 14", n)
+	}
+}
+
+func TestMultiChainActivityPerChainConcurrency(t *testing.T) {
+	stub := &slowEVMStub{delay: 50 * time.Millisecond, calls: map[string]int{}}
+	rpc := httptest.NewServer(stub)
+	defer rpc.Close()
+	withStubChains(t, rpc.URL)
+
+	config := DefaultConfig()
+	config.CrossChain.PerChainConcurrency = 1
+	config.CrossChain.CacheTTL = 0
+	s := NewServer(config, zap.NewNop())
+
+	// Concurrent checks for different addresses share one chain's single slot
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			addr := "0x" + strings.Repeat("0", 39) + string(rune('1'+i))
+			s.getMultiChainActivity(context.Background(), addr, []string{"ethereum"})
+		}(i)
+	}
+	wg.Wait()
+	if stub.maxIn != 1 {
+		t.Errorf("max in-flight calls to one chain = %d, want 1", stub.maxIn)
+	}
+	if n := stub.total(); n != 8 {
+		t.Errorf("RPC calls = %d, want 8 with caching disabled", n)
+	}
+}
+
+func TestMultiChainActivityTimeout(t *testing.T) {
+	stub := &slowEVMStub{delay: time.Second, calls: map[string]int{}}
+	rpc := httptest.NewServer(stub)
+	defer rpc.Close()
+	withStubChains(t, rpc.URL)
+
+	config := DefaultConfig()
+	config.CrossChain.Timeout = 100 * time.Millisecond
+	s := NewServer(config, zap.NewNop())
+
+	start := time.Now()
+	data := s.getMultiChainActivity(context.Background(), swapperAddr, []string{"ethereum", "base"})
+	if elapsed := time.Since(start); elapsed > 700*time.Millisecond {
+		t.Errorf("fetch took %v past its 100ms timeout", elapsed)
+	}
+	if len(data) != 0 {
+		t.Errorf("activity = %v, want timed-out chains left out", data)
+	}
+	// Nothing was cached from the timed-out fetch
+	if _, ok := s.chainActivity.get(swapperAddr, "ethereum"); ok {
+		t.Error("timed-out fetch was cached")
+	}
+}
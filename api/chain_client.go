@@ -0,0 +1,549 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+// Chain access
+// Handlers talk to the node only through a ChainClient: the node's gRPC
+// endpoint for balances, delegations, attestation lists, revocations and stats,
+// Cosmos REST for accounts and the remaining staking and gov state, the CometBFT RPC for
+// blocks, txs and status, and the certd CLI for our other module queries.
+// Tests inject a stub through Config.Chain so handlers run without a node.
+
+// ErrChainNotFound is returned when the node reports a missing account, validator, proposal or tx
+var ErrChainNotFound = errors.New("not found on chain")
+
+// ChainClient is the node API used by handlers. Methods taking a height query
+// state pinned to it; 0 means the latest height.
+type ChainClient interface {
+	// Bank and staking
+	Balances(ctx context.Context, address string, height int64) (BankBalancesResponse, error)
+	Delegations(ctx context.Context, delegator string, height int64) (DelegationsResponse, error)
+	DelegatorRewards(ctx context.Context, delegator string) (json.RawMessage, error)
+	Validators(ctx context.Context, status string) (ValidatorsResponse, error)
+	Validator(ctx context.Context, operator string) (ValidatorInfo, error)
+	StakingParams(ctx context.Context) (json.RawMessage, error)
+
+	// AccountSequence is the number of txs the account has signed; an account
+	// the chain has never seen is ErrChainNotFound
+	AccountSequence(ctx context.Context, address string, height int64) (uint64, error)
+
+	// Governance
+	Proposals(ctx context.Context) (ProposalsResponse, error)
+	Proposal(ctx context.Context, id string) (ProposalInfo, error)
+	ProposalTally(ctx context.Context, id string) (json.RawMessage, error)
+	ProposalVotes(ctx context.Context, id string) (json.RawMessage, error)
+	GovParams(ctx context.Context, paramsType string) (json.RawMessage, error)
+
+	// Attestations
+	AttestationsByAttester(ctx context.Context, attester string, height int64) ([]attestationtypes.Attestation, error)
+	AttestationsByRecipient(ctx context.Context, recipient string, height int64) ([]attestationtypes.Attestation, error)
+	AttestationStats(ctx context.Context, height int64) (*attestationtypes.QueryStatsResponse, error)
+	AttestationRevocations(ctx context.Context, attester string, height int64) ([]attestationtypes.Revocation, error)
+
+	// Consensus
+	LatestHeight(ctx context.Context) (int64, error)
+	ConsensusValidators(ctx context.Context) ([]ConsensusValidator, error)
+	Tx(ctx context.Context, hash string) (ChainTx, error)
+	Block(ctx context.Context, heightOrHash string) (ChainBlock, error)
+
+	// Query runs a certd module query (`certd query <args...>`) and decodes its
+	// JSON into out; a record the module reports missing is ErrChainNotFound
+	Query(ctx context.Context, out any, height int64, args ...string) error
+}
+
+// BankBalancesResponse is the bank module's balances for an address
+type BankBalancesResponse struct {
+	Balances []Coin `json:"balances"`
+}
+
+// DelegationsResponse is the staking module's delegations for a delegator
+type DelegationsResponse struct {
+	DelegationResponses []DelegationResponse `json:"delegation_responses"`
+}
+
+// DelegationResponse is one delegation and its balance
+type DelegationResponse struct {
+	Delegation struct {
+		DelegatorAddress string `json:"delegator_address"`
+		ValidatorAddress string `json:"validator_address"`
+	} `json:"delegation"`
+	Balance Coin `json:"balance"`
+}
+
+// ConsensusValidator is a validator in the CometBFT validator set
+type ConsensusValidator struct {
+	Address     string
+	PubKey      string
+	VotingPower string
+}
+
+// ChainTx is a transaction result from the CometBFT RPC
+type ChainTx struct {
+	Hash      string
+	Height    int64
+	Code      int
+	Log       string
+	GasWanted int64
+	GasUsed   int64
+	Tx        string // Encoded tx bytes as returned by the RPC
+}
+
+// ChainBlock is a block from the CometBFT RPC
+type ChainBlock struct {
+	Hash     string
+	Height   int64
+	Time     string
+	Proposer string
+	Txs      []string // Base64 tx bytes
+}
+
+// restClient is a shared HTTP client for REST/LCD queries
+var restClient = &http.Client{
+	Timeout: 10 * time.Second,
+}
+
+// getRESTBaseURL returns the base URL for Cosmos REST/LCD API
+// Uses COSMOS_REST_URL env var if set, otherwise defaults to localhost
+func getRESTBaseURL() string {
+	if url := os.Getenv("COSMOS_REST_URL"); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	return "http://localhost:1317"
+}
+
+// getRPCBaseURL returns the base URL for CometBFT RPC API
+// Uses COSMOS_RPC_URL env var if set, otherwise defaults to localhost
+func getRPCBaseURL() string {
+	if url := os.Getenv("COSMOS_RPC_URL"); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	return "http://localhost:26657"
+}
+
+// runCertdCLI runs the certd binary inside the node container; tests replace it
+var runCertdCLI = func(args ...string) ([]byte, error) {
+	return exec.Command("docker", append([]string{"exec", "certd", "certd"}, args...)...).CombinedOutput()
+}
+
+// nodeChainClient is the ChainClient backed by a running node
+type nodeChainClient struct {
+	restURL  string // Cosmos REST/LCD
+	rpcURL   string // CometBFT RPC
+	grpcAddr string // Node gRPC, host:port
+	http     *http.Client
+
+	grpcOnce sync.Once
+	grpc     *chainGRPC
+	grpcErr  error
+}
+
+// newNodeChainClient resolves node endpoints: COSMOS_REST_URL and
+// COSMOS_RPC_URL override the configured REST and RPC addresses.
+func newNodeChainClient(config *Config) *nodeChainClient {
+	rpcURL := strings.TrimSuffix(config.ChainRPCURL, "/")
+	if env := os.Getenv("COSMOS_RPC_URL"); env != "" || rpcURL == "" {
+		rpcURL = getRPCBaseURL()
+	}
+	grpcAddr := config.ChainGRPCAddr
+	if grpcAddr == "" {
+		grpcAddr = defaultChainGRPCAddr
+	}
+	return &nodeChainClient{
+		restURL:  getRESTBaseURL(),
+		rpcURL:   rpcURL,
+		grpcAddr: grpcAddr,
+		http:     restClient,
+	}
+}
+
+// grpcClient returns the node gRPC client, creating it on first use
+func (c *nodeChainClient) grpcClient() (*chainGRPC, error) {
+	c.grpcOnce.Do(func() {
+		c.grpc, c.grpcErr = newChainGRPC(c.grpcAddr)
+	})
+	return c.grpc, c.grpcErr
+}
+
+// Close releases the gRPC connection, if one was opened
+func (c *nodeChainClient) Close() error {
+	if c.grpc == nil {
+		return nil
+	}
+	return c.grpc.Close()
+}
+
+// get performs a GET and decodes a 200 JSON response into out. A 404 is ErrChainNotFound.
+func (c *nodeChainClient) get(ctx context.Context, rawURL string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrChainNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// getAtHeight performs a REST GET pinned to height, surfacing state-versioning failures
+func (c *nodeChainClient) getAtHeight(ctx context.Context, rawURL string, height int64, out any) error {
+	body, err := getRESTBodyAtHeight(ctx, rawURL, height)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+func (c *nodeChainClient) Balances(ctx context.Context, address string, height int64) (BankBalancesResponse, error) {
+	g, err := c.grpcClient()
+	if err != nil {
+		return BankBalancesResponse{}, err
+	}
+	return g.Balances(ctx, address, height)
+}
+
+func (c *nodeChainClient) Delegations(ctx context.Context, delegator string, height int64) (DelegationsResponse, error) {
+	g, err := c.grpcClient()
+	if err != nil {
+		return DelegationsResponse{}, err
+	}
+	return g.Delegations(ctx, delegator, height)
+}
+
+func (c *nodeChainClient) AttestationsByAttester(ctx context.Context, attester string, height int64) ([]attestationtypes.Attestation, error) {
+	g, err := c.grpcClient()
+	if err != nil {
+		return nil, err
+	}
+	return g.AttestationsByAttester(ctx, attester, height)
+}
+
+func (c *nodeChainClient) AttestationsByRecipient(ctx context.Context, recipient string, height int64) ([]attestationtypes.Attestation, error) {
+	g, err := c.grpcClient()
+	if err != nil {
+		return nil, err
+	}
+	return g.AttestationsByRecipient(ctx, recipient, height)
+}
+
+func (c *nodeChainClient) AttestationStats(ctx context.Context, height int64) (*attestationtypes.QueryStatsResponse, error) {
+	g, err := c.grpcClient()
+	if err != nil {
+		return nil, err
+	}
+	return g.AttestationStats(ctx, height)
+}
+
+func (c *nodeChainClient) AttestationRevocations(ctx context.Context, attester string, height int64) ([]attestationtypes.Revocation, error) {
+	g, err := c.grpcClient()
+	if err != nil {
+		return nil, err
+	}
+	return g.AttestationRevocations(ctx, attester, height)
+}
+
+func (c *nodeChainClient) AccountSequence(ctx context.Context, address string, height int64) (uint64, error) {
+	var res struct {
+		Account accountSequence `json:"account"`
+	}
+	if err := c.getAtHeight(ctx, fmt.Sprintf("%s/cosmos/auth/v1beta1/accounts/%s", c.restURL, url.PathEscape(address)), height, &res); err != nil {
+		return 0, err
+	}
+	return res.Account.sequence()
+}
+
+// accountSequence holds the sequence of an auth account as served over REST:
+// on a base account itself, or on the base account nested in an EVM or
+// vesting account
+type accountSequence struct {
+	Sequence    string `json:"sequence"`
+	BaseAccount *struct {
+		Sequence string `json:"sequence"`
+	} `json:"base_account"`
+	BaseVestingAccount *struct {
+		BaseAccount struct {
+			Sequence string `json:"sequence"`
+		} `json:"base_account"`
+	} `json:"base_vesting_account"`
+}
+
+func (a accountSequence) sequence() (uint64, error) {
+	seq := a.Sequence
+	switch {
+	case a.BaseAccount != nil:
+		seq = a.BaseAccount.Sequence
+	case a.BaseVestingAccount != nil:
+		seq = a.BaseVestingAccount.BaseAccount.Sequence
+	}
+	if seq == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid account sequence %q: %w", seq, err)
+	}
+	return n, nil
+}
+
+func (c *nodeChainClient) DelegatorRewards(ctx context.Context, delegator string) (json.RawMessage, error) {
+	var res json.RawMessage
+	err := c.get(ctx, fmt.Sprintf("%s/cosmos/distribution/v1beta1/delegators/%s/rewards", c.restURL, delegator), &res)
+	return res, err
+}
+
+func (c *nodeChainClient) Validators(ctx context.Context, status string) (ValidatorsResponse, error) {
+	var res ValidatorsResponse
+	err := c.get(ctx, fmt.Sprintf("%s/cosmos/staking/v1beta1/validators?status=%s", c.restURL, url.QueryEscape(status)), &res)
+	return res, err
+}
+
+func (c *nodeChainClient) Validator(ctx context.Context, operator string) (ValidatorInfo, error) {
+	var res struct {
+		Validator ValidatorInfo `json:"validator"`
+	}
+	err := c.get(ctx, fmt.Sprintf("%s/cosmos/staking/v1beta1/validators/%s", c.restURL, url.PathEscape(operator)), &res)
+	return res.Validator, err
+}
+
+func (c *nodeChainClient) StakingParams(ctx context.Context) (json.RawMessage, error) {
+	var res json.RawMessage
+	err := c.get(ctx, c.restURL+"/cosmos/staking/v1beta1/params", &res)
+	return res, err
+}
+
+func (c *nodeChainClient) Proposals(ctx context.Context) (ProposalsResponse, error) {
+	var res ProposalsResponse
+	err := c.get(ctx, c.restURL+"/cosmos/gov/v1/proposals", &res)
+	return res, err
+}
+
+func (c *nodeChainClient) Proposal(ctx context.Context, id string) (ProposalInfo, error) {
+	var res struct {
+		Proposal ProposalInfo `json:"proposal"`
+	}
+	err := c.get(ctx, fmt.Sprintf("%s/cosmos/gov/v1/proposals/%s", c.restURL, url.PathEscape(id)), &res)
+	return res.Proposal, err
+}
+
+func (c *nodeChainClient) ProposalTally(ctx context.Context, id string) (json.RawMessage, error) {
+	var res json.RawMessage
+	err := c.get(ctx, fmt.Sprintf("%s/cosmos/gov/v1/proposals/%s/tally", c.restURL, url.PathEscape(id)), &res)
+	return res, err
+}
+
+func (c *nodeChainClient) ProposalVotes(ctx context.Context, id string) (json.RawMessage, error) {
+	var res json.RawMessage
+	err := c.get(ctx, fmt.Sprintf("%s/cosmos/gov/v1/proposals/%s/votes", c.restURL, url.PathEscape(id)), &res)
+	return res, err
+}
+
+func (c *nodeChainClient) GovParams(ctx context.Context, paramsType string) (json.RawMessage, error) {
+	var res json.RawMessage
+	err := c.get(ctx, fmt.Sprintf("%s/cosmos/gov/v1/params/%s", c.restURL, url.PathEscape(paramsType)), &res)
+	return res, err
+}
+
+func (c *nodeChainClient) LatestHeight(ctx context.Context) (int64, error) {
+	return fetchLatestHeight(ctx, c.rpcURL)
+}
+
+func (c *nodeChainClient) ConsensusValidators(ctx context.Context) ([]ConsensusValidator, error) {
+	var res struct {
+		Result struct {
+			Validators []struct {
+				Address string `json:"address"`
+				PubKey  struct {
+					Value string `json:"value"`
+				} `json:"pub_key"`
+				VotingPower string `json:"voting_power"`
+			} `json:"validators"`
+		} `json:"result"`
+	}
+	if err := c.get(ctx, c.rpcURL+"/validators", &res); err != nil {
+		return nil, err
+	}
+	out := make([]ConsensusValidator, 0, len(res.Result.Validators))
+	for _, v := range res.Result.Validators {
+		out = append(out, ConsensusValidator{Address: v.Address, PubKey: v.PubKey.Value, VotingPower: v.VotingPower})
+	}
+	return out, nil
+}
+
+func (c *nodeChainClient) Tx(ctx context.Context, hash string) (ChainTx, error) {
+	var res struct {
+		Result struct {
+			Hash     string `json:"hash"`
+			Height   string `json:"height"`
+			TxResult struct {
+				Code      int    `json:"code"`
+				Log       string `json:"log"`
+				GasWanted string `json:"gas_wanted"`
+				GasUsed   string `json:"gas_used"`
+			} `json:"tx_result"`
+			Tx string `json:"tx"`
+		} `json:"result"`
+		Error *struct {
+			Data string `json:"data"`
+		} `json:"error"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("%s/tx?hash=%s", c.rpcURL, url.QueryEscape(hash)), &res); err != nil {
+		return ChainTx{}, err
+	}
+	if res.Error != nil || res.Result.Hash == "" {
+		return ChainTx{}, ErrChainNotFound
+	}
+
+	height, _ := strconv.ParseInt(res.Result.Height, 10, 64)
+	gasWanted, _ := strconv.ParseInt(res.Result.TxResult.GasWanted, 10, 64)
+	gasUsed, _ := strconv.ParseInt(res.Result.TxResult.GasUsed, 10, 64)
+	return ChainTx{
+		Hash:      res.Result.Hash,
+		Height:    height,
+		Code:      res.Result.TxResult.Code,
+		Log:       res.Result.TxResult.Log,
+		GasWanted: gasWanted,
+		GasUsed:   gasUsed,
+		Tx:        res.Result.Tx,
+	}, nil
+}
+
+func (c *nodeChainClient) Block(ctx context.Context, heightOrHash string) (ChainBlock, error) {
+	rpcURL := fmt.Sprintf("%s/block?height=%s", c.rpcURL, url.QueryEscape(heightOrHash))
+	if strings.HasPrefix(heightOrHash, "0x") || len(heightOrHash) == 64 {
+		rpcURL = fmt.Sprintf("%s/block_by_hash?hash=%s", c.rpcURL, url.QueryEscape(heightOrHash))
+	}
+
+	var res struct {
+		Result struct {
+			BlockID struct {
+				Hash string `json:"hash"`
+			} `json:"block_id"`
+			Block struct {
+				Header struct {
+					Height          string `json:"height"`
+					Time            string `json:"time"`
+					ProposerAddress string `json:"proposer_address"`
+				} `json:"header"`
+				Data struct {
+					Txs []string `json:"txs"`
+				} `json:"data"`
+			} `json:"block"`
+		} `json:"result"`
+	}
+	if err := c.get(ctx, rpcURL, &res); err != nil {
+		return ChainBlock{}, err
+	}
+
+	height, _ := strconv.ParseInt(res.Result.Block.Header.Height, 10, 64)
+	return ChainBlock{
+		Hash:     res.Result.BlockID.Hash,
+		Height:   height,
+		Time:     res.Result.Block.Header.Time,
+		Proposer: res.Result.Block.Header.ProposerAddress,
+		Txs:      res.Result.Block.Data.Txs,
+	}, nil
+}
+
+// cliStatusCode is the gRPC status code in certd CLI error output
+// ("rpc error: code = NotFound desc = ..."), or codes.Unknown if it has none
+func cliStatusCode(output string) codes.Code {
+	_, rest, ok := strings.Cut(output, "rpc error: code = ")
+	if !ok {
+		return codes.Unknown
+	}
+	name, _, _ := strings.Cut(rest, " ")
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if code.String() == name {
+			return code
+		}
+	}
+	return codes.Unknown
+}
+
+// Query runs `certd query <args...>` inside the node container. State-versioning
+// failures are returned as a StateVersionError, and a gRPC NotFound status as
+// ErrChainNotFound.
+func (c *nodeChainClient) Query(_ context.Context, out any, height int64, queryArgs ...string) error {
+	// Command structure: certd query <queryArgs...> <flags>
+	// Flags must come AFTER subcommands for Cosmos SDK CLI.
+	args := []string{"query"}
+	args = append(args, queryArgs...)
+
+	// NOTE: certd module queries (notably our attestation and hardware modules) must use gRPC.
+	// The legacy `--node` path can fail with "unknown query path" once modules
+	// are served exclusively via gRPC Query services.
+	useGRPC := len(queryArgs) > 0 && (queryArgs[0] == "attestation" || queryArgs[0] == "hardware")
+	if useGRPC {
+		args = append(args, "--grpc-addr", c.grpcAddr, "--grpc-insecure")
+	} else {
+		args = append(args, "--node", "tcp://localhost:26657")
+	}
+
+	if height > 0 {
+		args = append(args, "--height", strconv.FormatInt(height, 10))
+	}
+
+	// Always request JSON output.
+	args = append(args, "-o", "json")
+
+	buf, err := runCertdCLI(args...)
+	if err != nil {
+		if svErr := checkStateVersion(string(buf), height); svErr != nil {
+			return svErr
+		}
+		if cliStatusCode(string(buf)) == codes.NotFound {
+			return fmt.Errorf("%w: %s", ErrChainNotFound, strings.TrimSpace(string(buf)))
+		}
+		return fmt.Errorf("certd query failed: %w: %s", err, string(buf))
+	}
+
+	// certd can print extra lines; extract first JSON object.
+	var raw json.RawMessage
+	if v, ok := extractFirstJSONObject[json.RawMessage](string(buf)); ok {
+		raw = v
+	} else {
+		raw = json.RawMessage(strings.TrimSpace(string(buf)))
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to decode certd json: %w", err)
+	}
+
+	return nil
+}
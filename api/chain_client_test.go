@@ -0,0 +1,406 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+var errMockChain = errors.New("mock chain: not stubbed")
+
+// mockChainClient is a ChainClient serving canned data; unset fields answer errMockChain
+type mockChainClient struct {
+	balances    map[string]BankBalancesResponse
+	delegations map[string]DelegationsResponse
+	issued      map[string][]attestationtypes.Attestation
+	received    map[string][]attestationtypes.Attestation
+	stats       *attestationtypes.QueryStatsResponse
+	revoked     map[string][]attestationtypes.Revocation
+	sequences   map[string]uint64
+	validators  ValidatorsResponse
+	consensus   []ConsensusValidator
+	proposals   map[string]ProposalInfo
+	tallies     map[string]json.RawMessage
+	height      int64
+	txs         map[string]ChainTx
+	blocks      map[string]ChainBlock
+	query       func(out any, height int64, args ...string) error
+
+	// latestVersionMissing makes unpinned state queries fail with a StateVersionError
+	latestVersionMissing bool
+	heights              []int64
+}
+
+func (m *mockChainClient) pinned(height int64) error {
+	m.heights = append(m.heights, height)
+	if m.latestVersionMissing && height == 0 {
+		return checkStateVersion(versionDoesNotExist, height)
+	}
+	return nil
+}
+
+func (m *mockChainClient) Balances(_ context.Context, address string, height int64) (BankBalancesResponse, error) {
+	if err := m.pinned(height); err != nil {
+		return BankBalancesResponse{}, err
+	}
+	res, ok := m.balances[address]
+	if !ok {
+		return BankBalancesResponse{}, errMockChain
+	}
+	return res, nil
+}
+
+func (m *mockChainClient) Delegations(_ context.Context, delegator string, height int64) (DelegationsResponse, error) {
+	if err := m.pinned(height); err != nil {
+		return DelegationsResponse{}, err
+	}
+	res, ok := m.delegations[delegator]
+	if !ok {
+		return DelegationsResponse{}, errMockChain
+	}
+	return res, nil
+}
+
+func (m *mockChainClient) AttestationsByAttester(_ context.Context, attester string, height int64) ([]attestationtypes.Attestation, error) {
+	if err := m.pinned(height); err != nil {
+		return nil, err
+	}
+	res, ok := m.issued[attester]
+	if !ok {
+		return nil, errMockChain
+	}
+	return res, nil
+}
+
+func (m *mockChainClient) AttestationsByRecipient(_ context.Context, recipient string, height int64) ([]attestationtypes.Attestation, error) {
+	if err := m.pinned(height); err != nil {
+		return nil, err
+	}
+	res, ok := m.received[recipient]
+	if !ok {
+		return nil, errMockChain
+	}
+	return res, nil
+}
+
+func (m *mockChainClient) AttestationStats(_ context.Context, height int64) (*attestationtypes.QueryStatsResponse, error) {
+	if err := m.pinned(height); err != nil {
+		return nil, err
+	}
+	if m.stats == nil {
+		return nil, errMockChain
+	}
+	return m.stats, nil
+}
+
+func (m *mockChainClient) AttestationRevocations(_ context.Context, attester string, height int64) ([]attestationtypes.Revocation, error) {
+	if err := m.pinned(height); err != nil {
+		return nil, err
+	}
+	if m.revoked == nil {
+		return nil, errMockChain
+	}
+	return m.revoked[attester], nil
+}
+
+func (m *mockChainClient) AccountSequence(_ context.Context, address string, height int64) (uint64, error) {
+	if err := m.pinned(height); err != nil {
+		return 0, err
+	}
+	seq, ok := m.sequences[address]
+	if !ok {
+		return 0, ErrChainNotFound
+	}
+	return seq, nil
+}
+
+func (m *mockChainClient) DelegatorRewards(context.Context, string) (json.RawMessage, error) {
+	return nil, errMockChain
+}
+
+func (m *mockChainClient) Validators(context.Context, string) (ValidatorsResponse, error) {
+	return m.validators, nil
+}
+
+func (m *mockChainClient) Validator(_ context.Context, operator string) (ValidatorInfo, error) {
+	for _, v := range m.validators.Validators {
+		if v.OperatorAddress == operator {
+			return v, nil
+		}
+	}
+	return ValidatorInfo{}, ErrChainNotFound
+}
+
+func (m *mockChainClient) StakingParams(context.Context) (json.RawMessage, error) {
+	return nil, errMockChain
+}
+
+func (m *mockChainClient) Proposals(context.Context) (ProposalsResponse, error) {
+	var res ProposalsResponse
+	for _, p := range m.proposals {
+		res.Proposals = append(res.Proposals, p)
+	}
+	return res, nil
+}
+
+func (m *mockChainClient) Proposal(_ context.Context, id string) (ProposalInfo, error) {
+	p, ok := m.proposals[id]
+	if !ok {
+		return ProposalInfo{}, ErrChainNotFound
+	}
+	return p, nil
+}
+
+func (m *mockChainClient) ProposalTally(_ context.Context, id string) (json.RawMessage, error) {
+	t, ok := m.tallies[id]
+	if !ok {
+		return nil, errMockChain
+	}
+	return t, nil
+}
+
+func (m *mockChainClient) ProposalVotes(context.Context, string) (json.RawMessage, error) {
+	return nil, errMockChain
+}
+
+func (m *mockChainClient) GovParams(context.Context, string) (json.RawMessage, error) {
+	return nil, errMockChain
+}
+
+func (m *mockChainClient) LatestHeight(context.Context) (int64, error) {
+	if m.height == 0 {
+		return 0, errMockChain
+	}
+	return m.height, nil
+}
+
+func (m *mockChainClient) ConsensusValidators(context.Context) ([]ConsensusValidator, error) {
+	if m.consensus == nil {
+		return nil, errMockChain
+	}
+	return m.consensus, nil
+}
+
+func (m *mockChainClient) Tx(_ context.Context, hash string) (ChainTx, error) {
+	tx, ok := m.txs[hash]
+	if !ok {
+		return ChainTx{}, ErrChainNotFound
+	}
+	return tx, nil
+}
+
+func (m *mockChainClient) Block(_ context.Context, heightOrHash string) (ChainBlock, error) {
+	b, ok := m.blocks[heightOrHash]
+	if !ok {
+		return ChainBlock{}, ErrChainNotFound
+	}
+	return b, nil
+}
+
+func (m *mockChainClient) Query(_ context.Context, out any, height int64, args ...string) error {
+	if m.query == nil {
+		return errMockChain
+	}
+	return m.query(out, height, args...)
+}
+
+func newMockChainServer(chain *mockChainClient) *Server {
+	config := DefaultConfig()
+	config.Chain = chain
+	return NewServer(config, zap.NewNop())
+}
+
+func serve(s *Server, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestChainClient_Validators(t *testing.T) {
+	chain := &mockChainClient{
+		consensus: []ConsensusValidator{{Address: "ABCD", PubKey: "pk", VotingPower: "10"}},
+	}
+	s := newMockChainServer(chain)
+
+	// No bonded validators over REST falls back to the consensus set
+	out := getJSON(t, s, "/api/v1/staking/validators")
+	validators := out["validators"].([]any)
+	if len(validators) != 1 {
+		t.Fatalf("validators = %v", validators)
+	}
+	if v := validators[0].(map[string]any); v["operator_address"] != "ABCD" || v["tokens"] != "10000000" {
+		t.Errorf("fallback validator = %v", v)
+	}
+
+	chain.validators = ValidatorsResponse{Validators: []ValidatorInfo{{OperatorAddress: "certvaloper1xyz", Status: "BOND_STATUS_BONDED"}}}
+	out = getJSON(t, s, "/api/v1/staking/validators/certvaloper1xyz")
+	if v := out["validator"].(map[string]any); v["operator_address"] != "certvaloper1xyz" {
+		t.Errorf("validator = %v", v)
+	}
+	if rec := serve(s, "GET", "/api/v1/staking/validators/certvaloper1missing"); rec.Code != http.StatusNotFound {
+		t.Errorf("missing validator status = %d, want 404", rec.Code)
+	}
+}
+
+func TestChainClient_Governance(t *testing.T) {
+	chain := &mockChainClient{
+		proposals: map[string]ProposalInfo{"7": {ID: "7", Title: "Raise max validators", Status: "PROPOSAL_STATUS_VOTING_PERIOD"}},
+		tallies:   map[string]json.RawMessage{"7": json.RawMessage(`{"tally":{"yes_count":"42"}}`)},
+	}
+	s := newMockChainServer(chain)
+
+	out := getJSON(t, s, "/api/v1/governance/proposals/7")
+	if p := out["proposal"].(map[string]any); p["title"] != "Raise max validators" {
+		t.Errorf("proposal = %v", p)
+	}
+	if rec := serve(s, "GET", "/api/v1/governance/proposals/8"); rec.Code != http.StatusNotFound {
+		t.Errorf("missing proposal status = %d, want 404", rec.Code)
+	}
+
+	out = getJSON(t, s, "/api/v1/governance/proposals/7/tally")
+	if tally := out["tally"].(map[string]any); tally["yes_count"] != "42" {
+		t.Errorf("tally = %v", tally)
+	}
+	if rec := serve(s, "GET", "/api/v1/governance/proposals/8/tally"); rec.Code != http.StatusBadGateway {
+		t.Errorf("tally failure status = %d, want 502", rec.Code)
+	}
+
+	// Params fall back to defaults when the node is unavailable
+	out = getJSON(t, s, "/api/v1/governance/params")
+	if _, ok := out["voting_params"]; !ok {
+		t.Errorf("default gov params = %v", out)
+	}
+}
+
+func TestChainClient_WalletBalanceRetriesAtLaggedHeight(t *testing.T) {
+	chain := &mockChainClient{
+		balances:             map[string]BankBalancesResponse{testBalanceAddr: {Balances: []Coin{{Denom: "ucert", Amount: "2500000"}}}},
+		height:               100,
+		latestVersionMissing: true,
+	}
+	s := newMockChainServer(chain)
+
+	out := getJSON(t, s, "/api/v1/wallet/"+testBalanceAddr+"/balance")
+	if out["balance_ucert"] != "2500000" || out["balance_source"] != BalanceSourceGRPC {
+		t.Errorf("balance = %v", out)
+	}
+	if len(chain.heights) != 2 || chain.heights[0] != 0 || chain.heights[1] != 100-stateVersionHeightLag {
+		t.Errorf("queried heights = %v", chain.heights)
+	}
+}
+
+func TestChainClient_ExplorerBlockAndStats(t *testing.T) {
+	// The block's only tx is base64("tx"); its hash is sha256("tx")
+	const txHash = "0x1B5B9CCB3E8D006A5230DE9BDA23FF91EDC794D4F56410560830B418528E446C"
+	chain := &mockChainClient{
+		height: 120,
+		blocks: map[string]ChainBlock{"100": {Hash: "BLOCKHASH", Height: 100, Time: "2026-01-01T00:00:00Z", Proposer: "VAL", Txs: []string{"dHg="}}},
+		txs:    map[string]ChainTx{txHash: {Hash: txHash[2:], Height: 100, Code: 0, Log: "transfer"}},
+	}
+	s := newMockChainServer(chain)
+
+	out := getJSON(t, s, "/api/v1/explorer/block/100")
+	if out["hash"] != "BLOCKHASH" || out["tx_count"].(float64) != 1 {
+		t.Errorf("block = %v", out)
+	}
+	txs := out["transactions"].([]any)
+	if tx := txs[0].(map[string]any); tx["type"] != "Transfer" || tx["success"] != true {
+		t.Errorf("block tx = %v", tx)
+	}
+
+	out = getJSON(t, s, "/api/v1/explorer/stats")
+	if out["totalBlocks"].(float64) != 120 {
+		t.Errorf("stats = %v", out)
+	}
+
+	out = getJSON(t, s, "/api/v1/explorer/tx/"+txHash)
+	if out["status"] != "success" || out["confirmations"].(float64) != 20 {
+		t.Errorf("tx = %v", out)
+	}
+	if rec := serve(s, "GET", "/api/v1/explorer/tx/0xdeadbeef"); rec.Code != http.StatusNotFound {
+		t.Errorf("missing tx status = %d, want 404", rec.Code)
+	}
+}
+
+func TestChainClient_AttestationQuery(t *testing.T) {
+	var gotArgs []string
+	chain := &mockChainClient{query: func(out any, _ int64, args ...string) error {
+		gotArgs = args
+		return json.Unmarshal([]byte(`{"attestation":{"uid":"0xabc","schema_uid":"0xdef","attester":"cert1a","recipient":"cert1b"}}`), out)
+	}}
+	s := newMockChainServer(chain)
+
+	out := getJSON(t, s, "/api/v1/attestations/0xabc")
+	if !containsArgs(gotArgs, "attestation", "attestation", "0xabc") {
+		t.Errorf("query args = %v", gotArgs)
+	}
+	if out["uid"] != "0xabc" {
+		t.Errorf("attestation = %v", out)
+	}
+}
+
+func TestNodeChainClient_AccountSequence(t *testing.T) {
+	accounts := map[string]string{
+		"cert1base":    `{"account":{"@type":"/cosmos.auth.v1beta1.BaseAccount","address":"cert1base","sequence":"7"}}`,
+		"cert1eth":     `{"account":{"@type":"/ethermint.types.v1.EthAccount","base_account":{"address":"cert1eth","sequence":"12"},"code_hash":"0x"}}`,
+		"cert1vesting": `{"account":{"@type":"/cosmos.vesting.v1beta1.DelayedVestingAccount","base_vesting_account":{"base_account":{"sequence":"3"}}}}`,
+	}
+	var gotHeight string
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeight = r.Header.Get(blockHeightHeader)
+		body, ok := accounts[strings.TrimPrefix(r.URL.Path, "/cosmos/auth/v1beta1/accounts/")]
+		if !ok {
+			http.Error(w, `{"code":5,"message":"account not found"}`, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer node.Close()
+	c := &nodeChainClient{restURL: node.URL, http: node.Client()}
+
+	for addr, want := range map[string]uint64{"cert1base": 7, "cert1eth": 12, "cert1vesting": 3} {
+		if seq, err := c.AccountSequence(context.Background(), addr, 0); err != nil || seq != want {
+			t.Errorf("AccountSequence(%s) = %d, %v; want %d", addr, seq, err, want)
+		}
+	}
+	if _, err := c.AccountSequence(context.Background(), "cert1base", 90); err != nil || gotHeight != "90" {
+		t.Errorf("pinned query sent height %q: %v", gotHeight, err)
+	}
+	if _, err := c.AccountSequence(context.Background(), "cert1missing", 0); !errors.Is(err, ErrChainNotFound) {
+		t.Errorf("missing account err = %v, want ErrChainNotFound", err)
+	}
+}
+
+func TestNodeChainClient_QueryNotFound(t *testing.T) {
+	orig := runCertdCLI
+	t.Cleanup(func() { runCertdCLI = orig })
+	output := ""
+	runCertdCLI = func(...string) ([]byte, error) {
+		return []byte(output), errors.New("exit status 1")
+	}
+	c := &nodeChainClient{}
+
+	var out map[string]any
+	output = "Error: rpc error: code = NotFound desc = 0xmissing: attestation not found"
+	if err := c.Query(context.Background(), &out, 0, "attestation", "attestation", "0xmissing"); !errors.Is(err, ErrChainNotFound) {
+		t.Errorf("NotFound status err = %v, want ErrChainNotFound", err)
+	}
+
+	// Other failures, including a not-found message without the status, are not ErrChainNotFound
+	for _, output = range []string{
+		"Error: rpc error: code = Unavailable desc = connection refused",
+		"Error: rpc error: code = Unknown desc = device not found",
+	} {
+		if err := c.Query(context.Background(), &out, 0, "attestation", "attestation", "0x1"); err == nil || errors.Is(err, ErrChainNotFound) {
+			t.Errorf("%q: err = %v", output, err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+// defaultChainGRPCAddr is the node's default gRPC listen address
+const defaultChainGRPCAddr = "localhost:9090"
+
+// chainGRPC queries bank, staking and attestation state on the node's gRPC
+// endpoint. Messages are encoded with the SDK proto codec, as the node's own
+// gRPC server does, so the attestation module's hand-written types round-trip.
+type chainGRPC struct {
+	conn        *grpc.ClientConn
+	bank        banktypes.QueryClient
+	staking     stakingtypes.QueryClient
+	attestation attestationtypes.QueryClient
+}
+
+// newChainGRPC creates a client for addr (host:port). The connection is
+// established on first use, so an unreachable node fails queries, not startup.
+func newChainGRPC(addr string) (*chainGRPC, error) {
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(cdc.GRPCCodec())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client for %s: %w", addr, err)
+	}
+	return &chainGRPC{
+		conn:        conn,
+		bank:        banktypes.NewQueryClient(conn),
+		staking:     stakingtypes.NewQueryClient(conn),
+		attestation: attestationtypes.NewQueryClient(client.Context{}.WithGRPCClient(conn)),
+	}, nil
+}
+
+// atHeight pins the queries made with ctx to height; 0 leaves them at the latest height
+func atHeight(ctx context.Context, height int64) context.Context {
+	if height <= 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
+}
+
+// grpcQueryError surfaces state-versioning failures as a StateVersionError
+func grpcQueryError(err error, height int64) error {
+	if svErr := checkStateVersion(status.Convert(err).Message(), height); svErr != nil {
+		return svErr
+	}
+	return err
+}
+
+func (c *chainGRPC) Balances(ctx context.Context, address string, height int64) (BankBalancesResponse, error) {
+	res, err := c.bank.AllBalances(atHeight(ctx, height), &banktypes.QueryAllBalancesRequest{Address: address})
+	if err != nil {
+		return BankBalancesResponse{}, grpcQueryError(err, height)
+	}
+	out := BankBalancesResponse{Balances: make([]Coin, 0, len(res.Balances))}
+	for _, coin := range res.Balances {
+		out.Balances = append(out.Balances, Coin{Denom: coin.Denom, Amount: coin.Amount.String()})
+	}
+	return out, nil
+}
+
+func (c *chainGRPC) Delegations(ctx context.Context, delegator string, height int64) (DelegationsResponse, error) {
+	res, err := c.staking.DelegatorDelegations(atHeight(ctx, height), &stakingtypes.QueryDelegatorDelegationsRequest{DelegatorAddr: delegator})
+	if err != nil {
+		return DelegationsResponse{}, grpcQueryError(err, height)
+	}
+	out := DelegationsResponse{DelegationResponses: make([]DelegationResponse, 0, len(res.DelegationResponses))}
+	for _, dr := range res.DelegationResponses {
+		var entry DelegationResponse
+		entry.Delegation.DelegatorAddress = dr.Delegation.DelegatorAddress
+		entry.Delegation.ValidatorAddress = dr.Delegation.ValidatorAddress
+		entry.Balance = Coin{Denom: dr.Balance.Denom, Amount: dr.Balance.Amount.String()}
+		out.DelegationResponses = append(out.DelegationResponses, entry)
+	}
+	return out, nil
+}
+
+func (c *chainGRPC) AttestationsByAttester(ctx context.Context, attester string, height int64) ([]attestationtypes.Attestation, error) {
+	res, err := c.attestation.AttestationsByAttester(atHeight(ctx, height), &attestationtypes.QueryAttestationsByAttesterRequest{Attester: attester})
+	if err != nil {
+		return nil, grpcQueryError(err, height)
+	}
+	return res.Attestations, nil
+}
+
+func (c *chainGRPC) AttestationsByRecipient(ctx context.Context, recipient string, height int64) ([]attestationtypes.Attestation, error) {
+	res, err := c.attestation.AttestationsByRecipient(atHeight(ctx, height), &attestationtypes.QueryAttestationsByRecipientRequest{Recipient: recipient})
+	if err != nil {
+		return nil, grpcQueryError(err, height)
+	}
+	return res.Attestations, nil
+}
+
+func (c *chainGRPC) AttestationStats(ctx context.Context, height int64) (*attestationtypes.QueryStatsResponse, error) {
+	res, err := c.attestation.Stats(atHeight(ctx, height), &attestationtypes.QueryStatsRequest{})
+	if err != nil {
+		return nil, grpcQueryError(err, height)
+	}
+	return res, nil
+}
+
+func (c *chainGRPC) AttestationRevocations(ctx context.Context, attester string, height int64) ([]attestationtypes.Revocation, error) {
+	res, err := c.attestation.Revocations(atHeight(ctx, height), &attestationtypes.QueryRevocationsRequest{Attester: attester})
+	if err != nil {
+		return nil, grpcQueryError(err, height)
+	}
+	return res.Revocations, nil
+}
+
+func (c *chainGRPC) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,318 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+// chainGRPCStub serves canned bank, staking and attestation state over gRPC.
+// With okHeight set, queries at any other height fail with the state-versioning error.
+type chainGRPCStub struct {
+	balances    map[string]sdk.Coins
+	delegations map[string]stakingtypes.DelegationResponses
+	issued      map[string][]attestationtypes.Attestation
+	received    map[string][]attestationtypes.Attestation
+	stats       attestationtypes.QueryStatsResponse
+	revoked     map[string][]attestationtypes.Revocation
+	okHeight    string
+	heights     heightRecorder
+}
+
+// atHeight records the height a query was pinned to and fails it unless it is okHeight
+func (s *chainGRPCStub) atHeight(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var height string
+	if v := md.Get(grpctypes.GRPCBlockHeightHeader); len(v) > 0 {
+		height = v[0]
+	}
+	s.heights.add(height)
+	if s.okHeight != "" && height != s.okHeight {
+		return status.Error(codes.Unknown, "version does not exist")
+	}
+	return nil
+}
+
+type bankStub struct {
+	banktypes.UnimplementedQueryServer
+	*chainGRPCStub
+}
+
+func (s bankStub) AllBalances(ctx context.Context, req *banktypes.QueryAllBalancesRequest) (*banktypes.QueryAllBalancesResponse, error) {
+	if err := s.atHeight(ctx); err != nil {
+		return nil, err
+	}
+	return &banktypes.QueryAllBalancesResponse{Balances: s.balances[req.Address]}, nil
+}
+
+type stakingStub struct {
+	stakingtypes.UnimplementedQueryServer
+	*chainGRPCStub
+}
+
+func (s stakingStub) DelegatorDelegations(ctx context.Context, req *stakingtypes.QueryDelegatorDelegationsRequest) (*stakingtypes.QueryDelegatorDelegationsResponse, error) {
+	if err := s.atHeight(ctx); err != nil {
+		return nil, err
+	}
+	return &stakingtypes.QueryDelegatorDelegationsResponse{DelegationResponses: s.delegations[req.DelegatorAddr]}, nil
+}
+
+// attestationStub implements only the list, revocation and stats queries; the embedded nil interface
+// panics if anything else is called
+type attestationStub struct {
+	attestationtypes.QueryServer
+	*chainGRPCStub
+}
+
+func (s attestationStub) AttestationsByAttester(ctx context.Context, req *attestationtypes.QueryAttestationsByAttesterRequest) (*attestationtypes.QueryAttestationsByAttesterResponse, error) {
+	if err := s.atHeight(ctx); err != nil {
+		return nil, err
+	}
+	return &attestationtypes.QueryAttestationsByAttesterResponse{Attestations: s.issued[req.Attester]}, nil
+}
+
+func (s attestationStub) AttestationsByRecipient(ctx context.Context, req *attestationtypes.QueryAttestationsByRecipientRequest) (*attestationtypes.QueryAttestationsByRecipientResponse, error) {
+	if err := s.atHeight(ctx); err != nil {
+		return nil, err
+	}
+	return &attestationtypes.QueryAttestationsByRecipientResponse{Attestations: s.received[req.Recipient]}, nil
+}
+
+func (s attestationStub) Revocations(ctx context.Context, req *attestationtypes.QueryRevocationsRequest) (*attestationtypes.QueryRevocationsResponse, error) {
+	if err := s.atHeight(ctx); err != nil {
+		return nil, err
+	}
+	return &attestationtypes.QueryRevocationsResponse{Revocations: s.revoked[req.Attester]}, nil
+}
+
+func (s attestationStub) Stats(ctx context.Context, _ *attestationtypes.QueryStatsRequest) (*attestationtypes.QueryStatsResponse, error) {
+	if err := s.atHeight(ctx); err != nil {
+		return nil, err
+	}
+	stats := s.stats
+	return &stats, nil
+}
+
+// serveChainGRPC starts stub on a local port with the node's codec and returns its address
+func serveChainGRPC(t *testing.T, stub *chainGRPCStub) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := grpc.NewServer(grpc.ForceServerCodec(codec.NewProtoCodec(codectypes.NewInterfaceRegistry()).GRPCCodec()))
+	banktypes.RegisterQueryServer(srv, &bankStub{chainGRPCStub: stub})
+	stakingtypes.RegisterQueryServer(srv, &stakingStub{chainGRPCStub: stub})
+	attestationtypes.RegisterQueryServer(srv, &attestationStub{chainGRPCStub: stub})
+	go srv.Serve(lis) //nolint:errcheck
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+// newChainGRPCTestServer returns a server querying stub through the node chain
+// client; configure, if set, adjusts the rest of its config
+func newChainGRPCTestServer(t *testing.T, stub *chainGRPCStub, configure func(*Config)) *Server {
+	t.Helper()
+	config := DefaultConfig()
+	config.ChainGRPCAddr = serveChainGRPC(t, stub)
+	if configure != nil {
+		configure(config)
+	}
+	s := NewServer(config, zap.NewNop())
+	t.Cleanup(func() { s.chain.(*nodeChainClient).Close() })
+	return s
+}
+
+// mustAccAddress decodes a cert1... address to its bytes
+func mustAccAddress(t *testing.T, addr string) sdk.AccAddress {
+	t.Helper()
+	_, bz, err := bech32.DecodeAndConvert(addr)
+	if err != nil {
+		t.Fatalf("decode %s: %v", addr, err)
+	}
+	return bz
+}
+
+func TestChainGRPCWalletBalance(t *testing.T) {
+	stub := &chainGRPCStub{balances: map[string]sdk.Coins{
+		testBalanceAddr: sdk.NewCoins(sdk.NewCoin("acert", sdkmath.NewInt(7)), sdk.NewCoin("ucert", sdkmath.NewInt(1_234_567))),
+	}}
+	s := newChainGRPCTestServer(t, stub, nil)
+
+	ucert, source, err := s.walletBalanceWithSource(context.Background(), testBalanceAddr)
+	if err != nil {
+		t.Fatalf("walletBalanceWithSource: %v", err)
+	}
+	if ucert != "1234567" || source != BalanceSourceGRPC {
+		t.Errorf("balance = %s from %s, want 1234567 from grpc", ucert, source)
+	}
+	if got := stub.heights.get(); len(got) != 1 || got[0] != "" {
+		t.Errorf("queried heights = %q, want one unpinned query", got)
+	}
+
+	// An address without a ucert coin has a zero balance
+	if ucert, err := s.queryWalletBalanceUcert(validateAttester); err != nil || ucert != "0" {
+		t.Errorf("empty balance = %s, %v; want 0", ucert, err)
+	}
+}
+
+func TestChainGRPCStakingDelegations(t *testing.T) {
+	const validator = "certvaloper1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5x9atha"
+	delegation := func(amount int64) stakingtypes.DelegationResponse {
+		return stakingtypes.DelegationResponse{
+			Delegation: stakingtypes.Delegation{DelegatorAddress: testBalanceAddr, ValidatorAddress: validator, Shares: sdkmath.LegacyNewDec(amount)},
+			Balance:    sdk.NewCoin("ucert", sdkmath.NewInt(amount)),
+		}
+	}
+	stub := &chainGRPCStub{delegations: map[string]stakingtypes.DelegationResponses{
+		testBalanceAddr: {delegation(300), delegation(200)},
+	}}
+	s := newChainGRPCTestServer(t, stub, nil)
+
+	res, err := s.queryStakingDelegations(testBalanceAddr)
+	if err != nil {
+		t.Fatalf("queryStakingDelegations: %v", err)
+	}
+	if len(res.DelegationResponses) != 2 || res.DelegationResponses[0].Delegation.ValidatorAddress != validator {
+		t.Errorf("delegations = %+v", res.DelegationResponses)
+	}
+	total, err := s.queryTotalStakedUcert(testBalanceAddr)
+	if err != nil || total != "500" {
+		t.Errorf("total staked = %s, %v; want 500", total, err)
+	}
+}
+
+func TestChainGRPCAttestations(t *testing.T) {
+	issuedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	attestation := attestationtypes.Attestation{
+		UID:             "0xabc",
+		SchemaUID:       "0xschema",
+		Attester:        mustAccAddress(t, validateAttester),
+		Recipient:       mustAccAddress(t, validateCreator),
+		Time:            issuedAt,
+		AttestationType: "public",
+		Tags:            []string{"degree"},
+	}
+	revoked := attestation
+	revoked.UID = "0xdef"
+	revoked.Recipient = nil
+	revoked.RevocationTime = issuedAt.Add(time.Hour)
+	stub := &chainGRPCStub{
+		issued:   map[string][]attestationtypes.Attestation{validateAttester: {attestation, revoked}},
+		received: map[string][]attestationtypes.Attestation{validateCreator: {attestation}},
+	}
+	s := newChainGRPCTestServer(t, stub, nil)
+
+	t.Run("by attester", func(t *testing.T) {
+		got, err := s.queryAttestationsByAttester(validateAttester)
+		if err != nil {
+			t.Fatalf("queryAttestationsByAttester: %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("attestations = %v, want 2", got)
+		}
+		// Address bytes come back as cert1... bech32
+		first := got[0]
+		if first["uid"] != "0xabc" || first["issuer"] != validateAttester || first["recipient"] != validateCreator {
+			t.Errorf("first = %v", first)
+		}
+		if first["time"] != formatTimestamp(issuedAt) || first["revoked"] != false || first["schema"] != "0xschema" {
+			t.Errorf("first = %v", first)
+		}
+		if second := got[1]; second["recipient"] != "" || second["revoked"] != true {
+			t.Errorf("second = %v", second)
+		}
+	})
+
+	t.Run("by recipient", func(t *testing.T) {
+		got, err := s.queryAttestationsByRecipient(validateCreator)
+		if err != nil {
+			t.Fatalf("queryAttestationsByRecipient: %v", err)
+		}
+		if len(got) != 1 || got[0]["uid"] != "0xabc" || got[0]["issuer"] != validateAttester {
+			t.Errorf("attestations = %v", got)
+		}
+	})
+}
+
+func TestChainGRPCAttestationStats(t *testing.T) {
+	stub := &chainGRPCStub{stats: attestationtypes.QueryStatsResponse{
+		TotalAttestations:          12,
+		TotalEncryptedAttestations: 3,
+		TotalSchemas:               4,
+		TotalRevocations:           2,
+	}}
+	s := newChainGRPCTestServer(t, stub, nil)
+
+	out := getJSON(t, s, "/api/v1/stats")
+	want := map[string]any{
+		"total_attestations":           float64(12),
+		"total_encrypted_attestations": float64(3),
+		"total_schemas":                float64(4),
+		"total_revocations":            float64(2),
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("stats = %v, want %v", out, want)
+	}
+
+	// Without a reachable chain the stats are an upstream failure, not zeros
+	down := newMockChainServer(&mockChainClient{})
+	if rec := serve(down, "GET", "/api/v1/stats"); rec.Code != http.StatusBadGateway {
+		t.Errorf("stats without chain = %d, want 502", rec.Code)
+	}
+}
+
+func TestChainGRPCAttestationRevocations(t *testing.T) {
+	revokedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	stub := &chainGRPCStub{revoked: map[string][]attestationtypes.Revocation{
+		validateAttester: {{UID: "0xabc", Attester: mustAccAddress(t, validateAttester), RevocationTime: revokedAt}},
+	}}
+	s := newChainGRPCTestServer(t, stub, nil)
+
+	rec := serve(s, "GET", "/api/v1/attestations/revoked/"+validateAttester)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("revoked = %d: %s", rec.Code, rec.Body.String())
+	}
+	var got []revocationListItem
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := []revocationListItem{{UID: "0xabc", Attester: validateAttester, RevocationTime: "2025-03-01T12:00:00Z"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("revoked = %+v, want %+v", got, want)
+	}
+
+	// An attester with no revocations gets an empty list, not null
+	if rec := serve(s, "GET", "/api/v1/attestations/revoked/"+validateCreator); strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("revoked without revocations = %q, want []", rec.Body.String())
+	}
+	if rec := serve(s, "GET", "/api/v1/attestations/revoked/not-an-address"); rec.Code != http.StatusBadRequest {
+		t.Errorf("revoked for invalid address = %d, want 400", rec.Code)
+	}
+
+	down := newMockChainServer(&mockChainClient{})
+	if rec := serve(down, "GET", "/api/v1/attestations/revoked/"+validateAttester); rec.Code != http.StatusBadGateway {
+		t.Errorf("revoked without chain = %d, want 502", rec.Code)
+	}
+}
@@ -0,0 +1,15 @@
+package api
+
+import "time"
+
+// Clock is the server's source of wall-clock time. Handlers read time through
+// s.clock so expiry windows and cooldowns can be driven deterministically in
+// tests. Chain state never depends on it; keepers use ctx.BlockTime().
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the real wall clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// fakeClock is a Clock that only moves when told to
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newClockTestServer(clock Clock) *Server {
+	config := DefaultConfig()
+	config.Clock = clock
+	config.ServiceSecrets = map[string][]byte{"bridge-validator": []byte("validator-secret")}
+	config.BridgeRelayers = []*ecdsa.PublicKey{&testRelayerKey.PublicKey}
+	return NewServer(config, zap.NewNop())
+}
+
+func TestNewServerDefaultsToSystemClock(t *testing.T) {
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	if _, ok := s.clock.(systemClock); !ok {
+		t.Fatalf("clock = %T, want systemClock", s.clock)
+	}
+}
+
+// TestAuthChallengeExpiryFollowsClock tests the five-minute challenge window
+func TestAuthChallengeExpiryFollowsClock(t *testing.T) {
+	clock := newFakeClock(time.Unix(1_700_000_000, 0))
+	s := newClockTestServer(clock)
+	const address = "0x1111111111111111111111111111111111111111"
+
+	challenge := func() authChallengeResponse {
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/auth/challenge?address="+address, nil))
+		var resp authChallengeResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode challenge: %v", err)
+		}
+		return resp
+	}
+	verify := func(nonce string) authVerifyResponse {
+		body := `{"address":"` + address + `","nonce":"` + nonce + `","signature":"0x00"}`
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, newJSONRequest("POST", "/api/v1/auth/verify", strings.NewReader(body)))
+		var resp authVerifyResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode verify: %v", err)
+		}
+		return resp
+	}
+
+	fresh := challenge()
+	if want := clock.Now().Add(5 * time.Minute).Unix(); fresh.ExpiresAtUnix != want || fresh.ExpiresAt.Unix() != want {
+		t.Errorf("expires_at = %s (%d), want %d", fresh.ExpiresAt, fresh.ExpiresAtUnix, want)
+	}
+	clock.Advance(4 * time.Minute)
+	if resp := verify(fresh.Nonce); resp.Error == "nonce expired" {
+		t.Error("challenge expired inside its window")
+	}
+
+	stale := challenge()
+	clock.Advance(5*time.Minute + time.Second)
+	if resp := verify(stale.Nonce); resp.Error != "nonce expired" {
+		t.Errorf("error = %q, want nonce expired", resp.Error)
+	}
+}
+
+// TestServiceTokenSkewFollowsClock tests that token freshness is judged by the server clock
+func TestServiceTokenSkewFollowsClock(t *testing.T) {
+	signedAt := time.Unix(1_700_000_000, 0)
+	clock := newFakeClock(signedAt)
+	s := newClockTestServer(clock)
+	_ = s.bridgeTransfers.CreateBridgeTransfer(context.Background(), &database.BridgeTransfer{TransferID: "clock-transfer", Status: "pending"})
+
+	confirm := func() int {
+		req := newJSONRequest("POST", "/api/v1/bridge/transfer/clock-transfer/confirm", strings.NewReader(testConfirmBody(testRelayerKey, "clock-transfer", "", "confirmed", 0)))
+		SignServiceRequest(req, "bridge-validator", []byte("validator-secret"), signedAt)
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	clock.Advance(serviceTokenMaxSkew - time.Second)
+	if code := confirm(); code != http.StatusOK {
+		t.Fatalf("status inside skew = %d, want 200", code)
+	}
+	clock.Advance(2 * time.Second)
+	if code := confirm(); code != http.StatusUnauthorized {
+		t.Fatalf("status past skew = %d, want 401", code)
+	}
+}
+
+// TestIssuancePeriodRollsOverWithClock tests that the monthly quota window resets at the month boundary
+func TestIssuancePeriodRollsOverWithClock(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, time.January, 31, 23, 59, 0, 0, time.UTC))
+	s := newClockTestServer(clock)
+	s.config.IssuanceQuotas = map[string]int{"free": 1}
+
+	period := issuancePeriodStart(clock.Now())
+	if _, ok, _ := s.issuance.Reserve(context.Background(), "key", period, 1); !ok {
+		t.Fatal("setup reservation failed")
+	}
+	usage, err := s.issuanceUsage(context.Background(), "key", "free")
+	if err != nil || usage.Remaining != 0 {
+		t.Fatalf("usage = %+v, err = %v; want quota used up", usage, err)
+	}
+
+	clock.Advance(2 * time.Minute)
+	usage, err = s.issuanceUsage(context.Background(), "key", "free")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Issued != 0 || usage.Remaining != 1 || usage.PeriodStart.Month() != time.February {
+		t.Errorf("usage after rollover = %+v, want fresh February period", usage)
+	}
+}
+
+func TestCalculateTrustScoreAgeWindows(t *testing.T) {
+	created := time.Unix(1_700_000_000, 0)
+	day := 24 * time.Hour
+	tests := []struct {
+		age  time.Duration
+		want int
+	}{
+		{age: 7 * day, want: 0},
+		{age: 8 * day, want: 4},
+		{age: 31 * day, want: 8},
+		{age: 181 * day, want: 12},
+		{age: 366 * day, want: 16},
+	}
+	base := calculateTrustScore(DefaultTrustScoreConfig().Identity, created, created, 0, false, 0)
+	for _, tt := range tests {
+		if got := calculateTrustScore(DefaultTrustScoreConfig().Identity, created.Add(tt.age), created, 0, false, 0) - base; got != tt.want {
+			t.Errorf("age bonus at %s = %d, want %d", tt.age, got, tt.want)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // APIKeyNew represents an API key for accessing the CertID API (extended version)
@@ -18,6 +20,7 @@ type APIKeyNew struct {
 	Tier                 string     `json:"tier"`
 	RateLimitPerDay      int        `json:"rate_limit_per_day"`
 	RateLimitPerMinute   int        `json:"rate_limit_per_minute"`
+	Scopes               []string   `json:"scopes"`
 	Active               bool       `json:"active"`
 	StripeSubscriptionID *string    `json:"stripe_subscription_id,omitempty"`
 	StripeCustomerID     *string    `json:"stripe_customer_id,omitempty"`
@@ -57,14 +60,14 @@ func (db *DB) CreateAPIKeyNew(ctx context.Context, key *APIKeyNew) error {
 	query := `
 		INSERT INTO api_keys (
 			owner_address, key_hash, key_prefix, name, description,
-			tier, rate_limit_per_day, rate_limit_per_minute, active,
+			tier, rate_limit_per_day, rate_limit_per_minute, scopes, active,
 			stripe_subscription_id, stripe_customer_id, billing_email, expires_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, created_at`
 
 	return db.conn.QueryRowContext(ctx, query,
 		key.OwnerAddress, key.KeyHash, key.KeyPrefix, key.Name, key.Description,
-		key.Tier, key.RateLimitPerDay, key.RateLimitPerMinute, key.Active,
+		key.Tier, key.RateLimitPerDay, key.RateLimitPerMinute, pq.Array(key.Scopes), key.Active,
 		key.StripeSubscriptionID, key.StripeCustomerID, key.BillingEmail, key.ExpiresAt,
 	).Scan(&key.ID, &key.CreatedAt)
 }
@@ -73,7 +76,7 @@ func (db *DB) CreateAPIKeyNew(ctx context.Context, key *APIKeyNew) error {
 func (db *DB) GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKeyNew, error) {
 	query := `
 		SELECT id, owner_address, key_hash, key_prefix, name, COALESCE(description, ''),
-			tier, rate_limit_per_day, rate_limit_per_minute, active,
+			tier, rate_limit_per_day, rate_limit_per_minute, scopes, active,
 			stripe_subscription_id, stripe_customer_id, billing_email,
 			created_at, last_used_at, expires_at
 		FROM api_keys
@@ -82,7 +85,7 @@ func (db *DB) GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKeyNew,
 	key := &APIKeyNew{}
 	err := db.conn.QueryRowContext(ctx, query, keyHash).Scan(
 		&key.ID, &key.OwnerAddress, &key.KeyHash, &key.KeyPrefix, &key.Name, &key.Description,
-		&key.Tier, &key.RateLimitPerDay, &key.RateLimitPerMinute, &key.Active,
+		&key.Tier, &key.RateLimitPerDay, &key.RateLimitPerMinute, pq.Array(&key.Scopes), &key.Active,
 		&key.StripeSubscriptionID, &key.StripeCustomerID, &key.BillingEmail,
 		&key.CreatedAt, &key.LastUsedAt, &key.ExpiresAt,
 	)
@@ -96,7 +99,7 @@ func (db *DB) GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKeyNew,
 func (db *DB) ListAPIKeysByOwner(ctx context.Context, ownerAddress string) ([]*APIKeyNew, error) {
 	query := `
 		SELECT id, owner_address, key_prefix, name, COALESCE(description, ''),
-			tier, rate_limit_per_day, rate_limit_per_minute, active,
+			tier, rate_limit_per_day, rate_limit_per_minute, scopes, active,
 			created_at, last_used_at, expires_at
 		FROM api_keys
 		WHERE owner_address = $1
@@ -113,7 +116,7 @@ func (db *DB) ListAPIKeysByOwner(ctx context.Context, ownerAddress string) ([]*A
 		key := &APIKeyNew{}
 		err := rows.Scan(
 			&key.ID, &key.OwnerAddress, &key.KeyPrefix, &key.Name, &key.Description,
-			&key.Tier, &key.RateLimitPerDay, &key.RateLimitPerMinute, &key.Active,
+			&key.Tier, &key.RateLimitPerDay, &key.RateLimitPerMinute, pq.Array(&key.Scopes), &key.Active,
 			&key.CreatedAt, &key.LastUsedAt, &key.ExpiresAt,
 		)
 		if err != nil {
@@ -138,12 +141,17 @@ func (db *DB) RevokeAPIKey(ctx context.Context, keyID, ownerAddress string) erro
 	return err
 }
 
-// CheckRateLimit checks if an API key has exceeded its rate limits
-func (db *DB) CheckRateLimit(ctx context.Context, keyID string, dailyLimit, minuteLimit int) (bool, error) {
+// ConsumeAPIRateLimit atomically charges cost against a key's daily budget and
+// one request against its minute cap, only if both allow it. It returns whether
+// the request was allowed and the budget used in the day window afterwards.
+// A limit <= 0 means unlimited.
+func (db *DB) ConsumeAPIRateLimit(ctx context.Context, keyID string, dayStart, minuteStart time.Time, cost, dailyLimit, minuteLimit int) (bool, int, error) {
+	query := `SELECT allowed, day_used FROM consume_api_rate_limit($1, $2, $3, $4, $5, $6)`
+
 	var allowed bool
-	query := `SELECT check_rate_limit($1, $2, $3)`
-	err := db.conn.QueryRowContext(ctx, query, keyID, dailyLimit, minuteLimit).Scan(&allowed)
-	return allowed, err
+	var used int
+	err := db.conn.QueryRowContext(ctx, query, keyID, dayStart, minuteStart, cost, dailyLimit, minuteLimit).Scan(&allowed, &used)
+	return allowed, used, err
 }
 
 // IncrementAPIUsage increments the usage counters for an API key
@@ -223,3 +231,93 @@ func (db *DB) GetUsageSummary(ctx context.Context, keyID string, periodType stri
 	}
 	return summaries, nil
 }
+
+// ReserveAttestationIssuance atomically counts one attestation against a key's
+// quota for the period. It returns the new count and false (without counting)
+// when the quota is already used up. A quota <= 0 means unlimited.
+func (db *DB) ReserveAttestationIssuance(ctx context.Context, keyID string, periodStart time.Time, quota int) (int, bool, error) {
+	query := `
+		INSERT INTO api_key_attestation_issuance (api_key_id, period_start, issued)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (api_key_id, period_start) DO UPDATE
+			SET issued = api_key_attestation_issuance.issued + 1, updated_at = CURRENT_TIMESTAMP
+			WHERE $3 <= 0 OR api_key_attestation_issuance.issued < $3
+		RETURNING issued`
+
+	var issued int
+	err := db.conn.QueryRowContext(ctx, query, keyID, periodStart, quota).Scan(&issued)
+	if err == sql.ErrNoRows {
+		count, err := db.GetAttestationIssuanceCount(ctx, keyID, periodStart)
+		return count, false, err
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return issued, true, nil
+}
+
+// ReleaseAttestationIssuance returns a reserved issuance (e.g. when the tx fails)
+func (db *DB) ReleaseAttestationIssuance(ctx context.Context, keyID string, periodStart time.Time) error {
+	query := `
+		UPDATE api_key_attestation_issuance
+		SET issued = issued - 1, updated_at = CURRENT_TIMESTAMP
+		WHERE api_key_id = $1 AND period_start = $2 AND issued > 0`
+	_, err := db.conn.ExecContext(ctx, query, keyID, periodStart)
+	return err
+}
+
+// GetAttestationIssuanceCount returns attestations issued by a key in a period
+func (db *DB) GetAttestationIssuanceCount(ctx context.Context, keyID string, periodStart time.Time) (int, error) {
+	query := `SELECT issued FROM api_key_attestation_issuance WHERE api_key_id = $1 AND period_start = $2`
+
+	var issued int
+	err := db.conn.QueryRowContext(ctx, query, keyID, periodStart).Scan(&issued)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return issued, err
+}
+
+// APIKeyBilling is the tier and Stripe billing state applied to an API key.
+// Nil Stripe IDs clear the stored IDs; a nil BillingEmail or Scopes keeps the
+// stored value.
+type APIKeyBilling struct {
+	Tier                 string
+	RateLimitPerDay      int
+	RateLimitPerMinute   int
+	StripeSubscriptionID *string
+	StripeCustomerID     *string
+	BillingEmail         *string
+	Scopes               []string
+}
+
+// SetAPIKeyBilling applies billing state to the key with the given ID,
+// reporting whether there was such a key
+func (db *DB) SetAPIKeyBilling(ctx context.Context, keyID string, b APIKeyBilling) (bool, error) {
+	return db.setAPIKeyBilling(ctx, "id = $1", keyID, b)
+}
+
+// SetAPIKeyBillingBySubscription applies billing state to the key paid for by
+// a Stripe subscription, reporting whether there was such a key
+func (db *DB) SetAPIKeyBillingBySubscription(ctx context.Context, subscriptionID string, b APIKeyBilling) (bool, error) {
+	return db.setAPIKeyBilling(ctx, "stripe_subscription_id = $1", subscriptionID, b)
+}
+
+func (db *DB) setAPIKeyBilling(ctx context.Context, where, arg string, b APIKeyBilling) (bool, error) {
+	query := `
+		UPDATE api_keys SET
+			tier = $2, rate_limit_per_day = $3, rate_limit_per_minute = $4,
+			stripe_subscription_id = $5, stripe_customer_id = $6,
+			billing_email = COALESCE($7, billing_email),
+			scopes = COALESCE($8, scopes)
+		WHERE ` + where
+	res, err := db.conn.ExecContext(ctx, query, arg,
+		b.Tier, b.RateLimitPerDay, b.RateLimitPerMinute,
+		b.StripeSubscriptionID, b.StripeCustomerID, b.BillingEmail, pq.Array(b.Scopes),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConsumeAPIRateLimit(t *testing.T) {
+	db := newTestDB(t, "016_api_key_rate_limits.sql")
+	ctx := context.Background()
+
+	keyID := fmt.Sprintf("test-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_, _ = db.conn.Exec(`DELETE FROM api_key_rate_limits WHERE api_key_id = $1`, keyID)
+	})
+
+	day := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	minute := day.Add(12 * time.Hour)
+
+	// limit+5 simultaneous requests against a minute cap of limit
+	const limit = 10
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < limit+5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			ok, _, err := db.ConsumeAPIRateLimit(ctx, keyID, day, minute, 3, 1000, limit)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if ok {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	if allowed != limit {
+		t.Fatalf("allowed %d of %d concurrent requests, want %d", allowed, limit+5, limit)
+	}
+
+	// The next minute resets the cap but not the day's budget; rejected requests are free
+	next := minute.Add(time.Minute)
+	if ok, used, err := db.ConsumeAPIRateLimit(ctx, keyID, day, next, 3, 1000, limit); err != nil || !ok || used != 3*(limit+1) {
+		t.Errorf("next minute: allowed %v, used %d, %v; want allowed with %d used", ok, used, err, 3*(limit+1))
+	}
+	if ok, used, err := db.ConsumeAPIRateLimit(ctx, keyID, day, next, 1000, 1000, limit); err != nil || ok || used != 3*(limit+1) {
+		t.Errorf("over budget: allowed %v, used %d, %v; want rejected with %d used", ok, used, err, 3*(limit+1))
+	}
+
+	// A new day starts from an empty budget
+	if ok, used, err := db.ConsumeAPIRateLimit(ctx, keyID, day.Add(24*time.Hour), next.Add(24*time.Hour), 5, 1000, limit); err != nil || !ok || used != 5 {
+		t.Errorf("next day: allowed %v, used %d, %v; want allowed with 5 used", ok, used, err)
+	}
+}
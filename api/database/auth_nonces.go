@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AuthNonce is a one-time sign-in challenge issued to an address
+type AuthNonce struct {
+	Nonce     string     `json:"nonce"`
+	Address   string     `json:"address"`
+	Message   string     `json:"message"`
+	IssuedAt  time.Time  `json:"issued_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
+
+// CreateAuthNonce stores a new challenge, pruning challenges that expired
+// before it was issued
+func (db *DB) CreateAuthNonce(ctx context.Context, n *AuthNonce) error {
+	if _, err := db.conn.ExecContext(ctx, `DELETE FROM auth_nonces WHERE expires_at < $1`, n.IssuedAt); err != nil {
+		return fmt.Errorf("failed to prune auth nonces: %w", err)
+	}
+	query := `
+		INSERT INTO auth_nonces (nonce, address, message, issued_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)`
+	if _, err := db.conn.ExecContext(ctx, query, n.Nonce, n.Address, n.Message, n.IssuedAt, n.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to create auth nonce: %w", err)
+	}
+	return nil
+}
+
+// GetAuthNonce retrieves a challenge, returning nil if it does not exist
+func (db *DB) GetAuthNonce(ctx context.Context, nonce string) (*AuthNonce, error) {
+	query := `
+		SELECT nonce, address, message, issued_at, expires_at, used_at
+		FROM auth_nonces
+		WHERE nonce = $1`
+	var n AuthNonce
+	var usedAt sql.NullTime
+	err := db.conn.QueryRowContext(ctx, query, nonce).Scan(&n.Nonce, &n.Address, &n.Message, &n.IssuedAt, &n.ExpiresAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth nonce: %w", err)
+	}
+	if usedAt.Valid {
+		n.UsedAt = &usedAt.Time
+	}
+	return &n, nil
+}
+
+// ConsumeAuthNonce marks an unused, unexpired challenge used at the given
+// time, reporting whether it did. Of two concurrent verifications only one
+// consumes the nonce.
+func (db *DB) ConsumeAuthNonce(ctx context.Context, nonce string, at time.Time) (bool, error) {
+	res, err := db.conn.ExecContext(ctx, `
+		UPDATE auth_nonces SET used_at = $2
+		WHERE nonce = $1 AND used_at IS NULL AND expires_at > $2`,
+		nonce, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume auth nonce: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
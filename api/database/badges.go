@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// BadgeDefinition is a custom badge added on top of the standard catalog
+type BadgeDefinition struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Icon          string `json:"icon"`
+	Institutional bool   `json:"institutional"`
+}
+
+// BadgeMapping awards a badge for a credential type, optionally only from one issuer
+type BadgeMapping struct {
+	CredentialType string `json:"credential_type"`
+	BadgeID        string `json:"badge_id"`
+	IssuerAddress  string `json:"issuer_address,omitempty"` // Empty applies to every issuer
+}
+
+// ListBadgeDefinitions returns every custom badge definition
+func (db *DB) ListBadgeDefinitions(ctx context.Context) ([]BadgeDefinition, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT id, name, description, icon, institutional
+		FROM badge_definitions
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query badge definitions: %w", err)
+	}
+	defer rows.Close()
+
+	var defs []BadgeDefinition
+	for rows.Next() {
+		var d BadgeDefinition
+		if err := rows.Scan(&d.ID, &d.Name, &d.Description, &d.Icon, &d.Institutional); err != nil {
+			return nil, fmt.Errorf("failed to scan badge definition: %w", err)
+		}
+		defs = append(defs, d)
+	}
+	return defs, rows.Err()
+}
+
+// ListBadgeMappings returns every credential type to badge mapping
+func (db *DB) ListBadgeMappings(ctx context.Context) ([]BadgeMapping, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT credential_type, badge_id, issuer_address
+		FROM badge_mappings
+		ORDER BY issuer_address, credential_type`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query badge mappings: %w", err)
+	}
+	defer rows.Close()
+
+	var mappings []BadgeMapping
+	for rows.Next() {
+		var m BadgeMapping
+		if err := rows.Scan(&m.CredentialType, &m.BadgeID, &m.IssuerAddress); err != nil {
+			return nil, fmt.Errorf("failed to scan badge mapping: %w", err)
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, rows.Err()
+}
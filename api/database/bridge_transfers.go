@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// BridgeTransfer is a cross-chain transfer tracked from lock request to completion
+type BridgeTransfer struct {
+	TransferID      string     `json:"transfer_id"`
+	Sender          string     `json:"sender"`
+	Recipient       string     `json:"recipient"`
+	Amount          string     `json:"amount"` // Base units, decimal
+	SourceChainID   uint64     `json:"source_chain_id"`
+	TargetChainID   uint64     `json:"target_chain_id"`
+	Status          string     `json:"status"`
+	TxHash          string     `json:"tx_hash"`
+	TargetTxHash    string     `json:"target_tx_hash"`
+	Confirmations   int        `json:"confirmations"`
+	RequiredConfirm int        `json:"required_confirmations"`
+	CreatedAt       time.Time  `json:"created_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+// BridgeTransferUpdate is a status report from the bridge validator service.
+// Empty strings and zero confirmations leave the stored value unchanged.
+type BridgeTransferUpdate struct {
+	TxHash        string
+	Status        string
+	Confirmations int
+	CompletedAt   *time.Time // Set when the transfer completes
+}
+
+// BridgeTransferStats aggregates every stored transfer
+type BridgeTransferStats struct {
+	Total     int
+	Pending   int // pending or confirmed
+	Completed int
+	Volume    string // Sum of amounts in base units, decimal
+}
+
+const bridgeTransferColumns = `
+	transfer_id, sender, recipient, amount::TEXT, source_chain_id, target_chain_id, status,
+	tx_hash, target_tx_hash, confirmations, required_confirmations, created_at, completed_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBridgeTransfer(row rowScanner) (*BridgeTransfer, error) {
+	var t BridgeTransfer
+	var completedAt sql.NullTime
+	if err := row.Scan(
+		&t.TransferID, &t.Sender, &t.Recipient, &t.Amount, &t.SourceChainID, &t.TargetChainID, &t.Status,
+		&t.TxHash, &t.TargetTxHash, &t.Confirmations, &t.RequiredConfirm, &t.CreatedAt, &completedAt,
+	); err != nil {
+		return nil, err
+	}
+	if completedAt.Valid {
+		t.CompletedAt = &completedAt.Time
+	}
+	return &t, nil
+}
+
+// CreateBridgeTransfer stores a new transfer
+func (db *DB) CreateBridgeTransfer(ctx context.Context, t *BridgeTransfer) error {
+	query := `
+		INSERT INTO bridge_transfers (transfer_id, sender, recipient, amount, source_chain_id, target_chain_id,
+			status, tx_hash, target_tx_hash, confirmations, required_confirmations, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	_, err := db.conn.ExecContext(ctx, query,
+		t.TransferID, t.Sender, t.Recipient, t.Amount, int64(t.SourceChainID), int64(t.TargetChainID),
+		t.Status, t.TxHash, t.TargetTxHash, t.Confirmations, t.RequiredConfirm, t.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create bridge transfer: %w", err)
+	}
+	return nil
+}
+
+// GetBridgeTransfer retrieves a transfer by ID, returning nil if it does not exist
+func (db *DB) GetBridgeTransfer(ctx context.Context, transferID string) (*BridgeTransfer, error) {
+	row := db.conn.QueryRowContext(ctx, `SELECT`+bridgeTransferColumns+` FROM bridge_transfers WHERE transfer_id = $1`, transferID)
+	t, err := scanBridgeTransfer(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bridge transfer: %w", err)
+	}
+	return t, nil
+}
+
+// ListBridgeTransfersByAddress returns one page of the transfers sent or
+// received by address, newest first, and the number of matching transfers
+func (db *DB) ListBridgeTransfersByAddress(ctx context.Context, address string, limit, offset int) ([]BridgeTransfer, int, error) {
+	var total int
+	if err := db.conn.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM bridge_transfers
+		WHERE sender = $1 OR recipient = $1`, address).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count bridge transfers: %w", err)
+	}
+
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT`+bridgeTransferColumns+`
+		FROM bridge_transfers
+		WHERE sender = $1 OR recipient = $1
+		ORDER BY created_at DESC, transfer_id
+		LIMIT $2 OFFSET $3`, address, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list bridge transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []BridgeTransfer
+	for rows.Next() {
+		t, err := scanBridgeTransfer(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan bridge transfer: %w", err)
+		}
+		transfers = append(transfers, *t)
+	}
+	return transfers, total, rows.Err()
+}
+
+// UpdateBridgeTransferStatus applies a validator status report and returns
+// the updated transfer, or nil if it does not exist
+func (db *DB) UpdateBridgeTransferStatus(ctx context.Context, transferID string, update BridgeTransferUpdate) (*BridgeTransfer, error) {
+	row := db.conn.QueryRowContext(ctx, `
+		UPDATE bridge_transfers SET
+			tx_hash = COALESCE(NULLIF($2, ''), tx_hash),
+			status = COALESCE(NULLIF($3, ''), status),
+			confirmations = CASE WHEN $4 > 0 THEN $4 ELSE confirmations END,
+			completed_at = COALESCE($5, completed_at)
+		WHERE transfer_id = $1
+		RETURNING`+bridgeTransferColumns,
+		transferID, update.TxHash, update.Status, update.Confirmations, update.CompletedAt,
+	)
+	t, err := scanBridgeTransfer(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update bridge transfer: %w", err)
+	}
+	return t, nil
+}
+
+// GetBridgeTransferStats counts transfers by status and sums their volume
+func (db *DB) GetBridgeTransferStats(ctx context.Context) (BridgeTransferStats, error) {
+	var st BridgeTransferStats
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status IN ('pending', 'confirmed')),
+			COUNT(*) FILTER (WHERE status = 'completed'),
+			COALESCE(SUM(amount), 0)::TEXT
+		FROM bridge_transfers`).Scan(&st.Total, &st.Pending, &st.Completed, &st.Volume)
+	if err != nil {
+		return BridgeTransferStats{}, fmt.Errorf("failed to aggregate bridge transfers: %w", err)
+	}
+	return st, nil
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// newTestDB connects to the database named by CERT_TEST_DATABASE_URL and
+// applies migrations, skipping the test when no test database is configured
+func newTestDB(t *testing.T, migrations ...string) *DB {
+	t.Helper()
+	url := os.Getenv("CERT_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("CERT_TEST_DATABASE_URL not set")
+	}
+	db, err := NewFromURL(url, zap.NewNop())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	for _, migration := range migrations {
+		schema, err := os.ReadFile("migrations/" + migration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.conn.Exec(string(schema)); err != nil {
+			t.Fatalf("apply %s: %v", migration, err)
+		}
+	}
+	return db
+}
+
+func TestBridgeTransfers(t *testing.T) {
+	db := newTestDB(t, "007_bridge_transfers.sql")
+	ctx := context.Background()
+
+	// Unique per run so the test can share a database
+	prefix := fmt.Sprintf("0xtest%d", time.Now().UnixNano())
+	sender, other := prefix+"-sender", prefix+"-other"
+	t.Cleanup(func() {
+		_, _ = db.conn.Exec(`DELETE FROM bridge_transfers WHERE transfer_id LIKE $1`, prefix+"%")
+	})
+
+	before, err := db.GetBridgeTransferStats(ctx)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+
+	created := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	for i, recipient := range []string{sender, other, sender} {
+		err := db.CreateBridgeTransfer(ctx, &BridgeTransfer{
+			TransferID:      fmt.Sprintf("%s-%d", prefix, i),
+			Sender:          sender,
+			Recipient:       recipient,
+			Amount:          "1000000000000000000000000", // Past int64
+			SourceChainID:   951753,
+			TargetChainID:   1,
+			Status:          "pending",
+			RequiredConfirm: 12,
+			CreatedAt:       created.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("create %d: %v", i, err)
+		}
+	}
+	if err := db.CreateBridgeTransfer(ctx, &BridgeTransfer{TransferID: prefix + "-0", Sender: sender, Recipient: sender, Amount: "1", RequiredConfirm: 12, Status: "pending"}); err == nil {
+		t.Error("duplicate transfer_id accepted")
+	}
+
+	t.Run("get", func(t *testing.T) {
+		got, err := db.GetBridgeTransfer(ctx, prefix+"-1")
+		if err != nil || got == nil {
+			t.Fatalf("get = %v, %v", got, err)
+		}
+		if got.Recipient != other || got.Amount != "1000000000000000000000000" || got.SourceChainID != 951753 ||
+			!got.CreatedAt.Equal(created.Add(time.Minute)) || got.CompletedAt != nil {
+			t.Errorf("transfer = %+v", got)
+		}
+		if missing, err := db.GetBridgeTransfer(ctx, prefix+"-missing"); missing != nil || err != nil {
+			t.Errorf("missing = %v, %v", missing, err)
+		}
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		completedAt := created.Add(time.Hour)
+		got, err := db.UpdateBridgeTransferStatus(ctx, prefix+"-0", BridgeTransferUpdate{TxHash: "0xlock", Status: "completed", Confirmations: 12, CompletedAt: &completedAt})
+		if err != nil || got == nil {
+			t.Fatalf("update = %v, %v", got, err)
+		}
+		if got.Status != "completed" || got.TxHash != "0xlock" || got.Confirmations != 12 || got.CompletedAt == nil || !got.CompletedAt.Equal(completedAt) {
+			t.Errorf("transfer = %+v", got)
+		}
+
+		// Empty fields leave stored values alone
+		got, err = db.UpdateBridgeTransferStatus(ctx, prefix+"-0", BridgeTransferUpdate{})
+		if err != nil || got.Status != "completed" || got.TxHash != "0xlock" || got.Confirmations != 12 {
+			t.Errorf("after empty update = %+v, %v", got, err)
+		}
+		if missing, err := db.UpdateBridgeTransferStatus(ctx, prefix+"-missing", BridgeTransferUpdate{Status: "failed"}); missing != nil || err != nil {
+			t.Errorf("missing = %v, %v", missing, err)
+		}
+	})
+
+	t.Run("history by address", func(t *testing.T) {
+		page, total, err := db.ListBridgeTransfersByAddress(ctx, sender, 2, 0)
+		if err != nil || total != 3 || len(page) != 2 || page[0].TransferID != prefix+"-2" || page[1].TransferID != prefix+"-1" {
+			t.Fatalf("page 1 = %+v, total %d, %v", page, total, err)
+		}
+		page, _, err = db.ListBridgeTransfersByAddress(ctx, sender, 2, 2)
+		if err != nil || len(page) != 1 || page[0].TransferID != prefix+"-0" {
+			t.Errorf("page 2 = %+v, %v", page, err)
+		}
+		page, total, err = db.ListBridgeTransfersByAddress(ctx, other, 10, 0)
+		if err != nil || total != 1 || len(page) != 1 || page[0].TransferID != prefix+"-1" {
+			t.Errorf("recipient history = %+v, total %d, %v", page, total, err)
+		}
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		after, err := db.GetBridgeTransferStats(ctx)
+		if err != nil {
+			t.Fatalf("stats: %v", err)
+		}
+		if after.Total-before.Total != 3 || after.Pending-before.Pending != 2 || after.Completed-before.Completed != 1 {
+			t.Errorf("stats before %+v, after %+v", before, after)
+		}
+		if before.Volume == "0" && after.Volume != "3000000000000000000000000" {
+			t.Errorf("volume = %s", after.Volume)
+		}
+	})
+}
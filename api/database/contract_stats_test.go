@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetContractCallStats(t *testing.T) {
+	db := newTestDB(t, "002_explorer_transactions.sql")
+	ctx := context.Background()
+
+	// Unique per run so the test can share a database
+	contract := fmt.Sprintf("0xtest%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_, _ = db.conn.Exec(`DELETE FROM transactions WHERE to_address = $1`, contract)
+	})
+
+	since := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	calls := []struct {
+		from, method, value, status string
+		at                          time.Time
+	}{
+		{"cert1alice", "transfer", "100", "success", since},
+		{"cert1bob", "transfer", "250", "success", since.Add(time.Hour)},
+		{"cert1ALICE", "transfer", "50", "failed", since.Add(2 * time.Hour)},
+		{"cert1minter", "mint", "1000000000000000000000000", "success", since.Add(3 * time.Hour)}, // Past int64
+		{"cert1minter", "mint", "1000", "success", since.Add(4 * time.Hour)},
+		{"cert1bob", "burn", "10", "success", since.Add(5 * time.Hour)},
+		{"cert1carol", "", "0", "success", since.Add(6 * time.Hour)},
+		{"cert1dave", "transfer", "999", "success", since.Add(48 * time.Hour)}, // Outside the window
+	}
+	for i, c := range calls {
+		tx := &Transaction{
+			Hash:          fmt.Sprintf("%s-%d", contract, i),
+			Status:        c.status,
+			Timestamp:     c.at,
+			FromAddress:   c.from,
+			ToAddress:     contract,
+			ValueCert:     c.value,
+			EcosystemType: "CertToken",
+		}
+		if c.method != "" {
+			tx.DecodedParams = map[string]interface{}{"method": c.method}
+		}
+		if err := db.SaveTransaction(ctx, tx); err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+	}
+
+	total, methods, err := db.GetContractCallStats(ctx, contract, since, since.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+
+	wantTotal := ContractCallStats{Calls: 7, FailedCalls: 1, UniqueCallers: 5, VolumeCert: "1000000000000000000001410"}
+	if total != wantTotal {
+		t.Errorf("total = %+v, want %+v", total, wantTotal)
+	}
+	want := []ContractCallStats{
+		{Method: "transfer", Calls: 3, FailedCalls: 1, UniqueCallers: 2, VolumeCert: "400"},
+		{Method: "mint", Calls: 2, UniqueCallers: 1, VolumeCert: "1000000000000000000001000"},
+		{Method: "burn", Calls: 1, UniqueCallers: 1, VolumeCert: "10"},
+		{Method: "unknown", Calls: 1, UniqueCallers: 1, VolumeCert: "0"},
+	}
+	if !reflect.DeepEqual(methods, want) {
+		t.Errorf("methods = %+v, want %+v", methods, want)
+	}
+
+	// An empty window still reports zero totals
+	total, methods, err = db.GetContractCallStats(ctx, contract, since.Add(-time.Hour), since)
+	if err != nil || total.Calls != 0 || total.VolumeCert != "0" || len(methods) != 0 {
+		t.Errorf("empty window = %+v, %+v, %v", total, methods, err)
+	}
+}
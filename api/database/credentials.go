@@ -49,24 +49,34 @@ func (db *DB) GetCredentialsByUser(ctx context.Context, address string) ([]Crede
 	return credentials, rows.Err()
 }
 
-// AddCredential adds a new credential to a user profile
+// AddCredential adds a new credential to a user profile. Adding a credential
+// the user already holds returns the stored one.
 func (db *DB) AddCredential(ctx context.Context, credential *Credential) error {
+	_, err := db.AddCredentialIfAbsent(ctx, credential)
+	return err
+}
+
+// AddCredentialIfAbsent adds a credential unless the user already holds one of
+// the same type for the same attestation, reporting whether it was added.
+// Either way credential.ID and CreatedAt identify the stored row.
+func (db *DB) AddCredentialIfAbsent(ctx context.Context, credential *Credential) (bool, error) {
 	// Verify max credentials per profile (50 per types_test.go)
 	count, err := db.CountCredentials(ctx, credential.UserAddress)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if count >= 50 {
-		return fmt.Errorf("maximum credentials (50) reached for user")
+		return false, fmt.Errorf("maximum credentials (50) reached for user")
 	}
 
 	query := `
 		INSERT INTO credentials (user_address, credential_type, attestation_uid, issuer, verified, issued_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_address, credential_type, attestation_uid) DO NOTHING
 		RETURNING id, created_at
 	`
 
-	return db.conn.QueryRowContext(ctx, query,
+	err = db.conn.QueryRowContext(ctx, query,
 		credential.UserAddress,
 		credential.CredentialType,
 		credential.AttestationUID,
@@ -74,6 +84,23 @@ func (db *DB) AddCredential(ctx context.Context, credential *Credential) error {
 		credential.Verified,
 		credential.IssuedAt,
 	).Scan(&credential.ID, &credential.CreatedAt)
+	if err == nil {
+		return true, nil
+	}
+	if err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to add credential: %w", err)
+	}
+
+	// Already held, e.g. a concurrent or repeated add
+	err = db.conn.QueryRowContext(ctx, `
+		SELECT id, created_at FROM credentials
+		WHERE user_address = $1 AND credential_type = $2 AND attestation_uid = $3`,
+		credential.UserAddress, credential.CredentialType, credential.AttestationUID,
+	).Scan(&credential.ID, &credential.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to get existing credential: %w", err)
+	}
+	return false, nil
 }
 
 // RemoveCredential removes a credential from a user profile
@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"time"
 
-	_ "github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -296,6 +295,104 @@ func (db *DB) SaveTransaction(ctx context.Context, tx *Transaction) error {
 	return err
 }
 
+// ContractCallStats summarizes indexed calls to a contract, either for one
+// decoded method or, with an empty Method, for all of them
+type ContractCallStats struct {
+	Method        string
+	Calls         int
+	FailedCalls   int
+	UniqueCallers int
+	VolumeCert    string
+}
+
+// GetContractCallStats aggregates indexed calls to a contract within
+// [since, until) per decoded method, most-called first, along with the totals
+// across all methods. Calls without a decoded method count as "unknown".
+func (db *DB) GetContractCallStats(ctx context.Context, contract string, since, until time.Time) (ContractCallStats, []ContractCallStats, error) {
+	query := `
+		WITH calls AS (
+			SELECT COALESCE(NULLIF(decoded_params->>'method', ''), 'unknown') AS method,
+				   status, LOWER(from_address) AS caller,
+				   CASE WHEN value_cert ~ '^[0-9]+$' THEN value_cert::NUMERIC ELSE 0 END AS value
+			FROM transactions
+			WHERE LOWER(to_address) = LOWER($1) AND timestamp >= $2 AND timestamp < $3
+		)
+		SELECT GROUPING(method) = 1, COALESCE(method, ''),
+			   COUNT(*), COUNT(*) FILTER (WHERE status = 'failed'),
+			   COUNT(DISTINCT caller), COALESCE(SUM(value), 0)::TEXT
+		FROM calls
+		GROUP BY GROUPING SETS ((method), ())
+		ORDER BY GROUPING(method) DESC, COUNT(*) DESC, method
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query, contract, since, until)
+	if err != nil {
+		return ContractCallStats{}, nil, fmt.Errorf("failed to aggregate contract calls: %w", err)
+	}
+	defer rows.Close()
+
+	var total ContractCallStats
+	var methods []ContractCallStats
+	for rows.Next() {
+		var isTotal bool
+		var stats ContractCallStats
+		if err := rows.Scan(&isTotal, &stats.Method, &stats.Calls, &stats.FailedCalls, &stats.UniqueCallers, &stats.VolumeCert); err != nil {
+			return ContractCallStats{}, nil, fmt.Errorf("failed to scan contract call stats: %w", err)
+		}
+		if isTotal {
+			total = stats
+		} else {
+			methods = append(methods, stats)
+		}
+	}
+
+	return total, methods, rows.Err()
+}
+
+// ListRecentTransactions returns the limit most recently indexed transactions, highest block first
+func (db *DB) ListRecentTransactions(ctx context.Context, limit int) ([]Transaction, error) {
+	query := `
+		SELECT hash, status, block_number, timestamp, gas_used
+		FROM transactions
+		ORDER BY block_number DESC, hash
+		LIMIT $1
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var txs []Transaction
+	for rows.Next() {
+		var tx Transaction
+		if err := rows.Scan(&tx.Hash, &tx.Status, &tx.BlockNumber, &tx.Timestamp, &tx.GasUsed); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		txs = append(txs, tx)
+	}
+
+	return txs, rows.Err()
+}
+
+// UpdateTransactionChainState overwrites the chain-derived fields of an indexed transaction
+func (db *DB) UpdateTransactionChainState(ctx context.Context, hash, status string, blockNumber, gasUsed int64) error {
+	query := `UPDATE transactions SET status = $2, block_number = $3, gas_used = $4 WHERE hash = $1`
+	if _, err := db.conn.ExecContext(ctx, query, hash, status, blockNumber, gasUsed); err != nil {
+		return fmt.Errorf("failed to update transaction: %w", err)
+	}
+	return nil
+}
+
+// DeleteTransaction removes a transaction from the index
+func (db *DB) DeleteTransaction(ctx context.Context, hash string) error {
+	if _, err := db.conn.ExecContext(ctx, `DELETE FROM transactions WHERE hash = $1`, hash); err != nil {
+		return fmt.Errorf("failed to delete transaction: %w", err)
+	}
+	return nil
+}
+
 // GetAddressLabel retrieves a label for an address
 func (db *DB) GetAddressLabel(ctx context.Context, address string) (string, error) {
 	query := `SELECT label FROM address_labels WHERE address = $1`
@@ -313,7 +410,9 @@ type APIKey struct {
 	OwnerAddress string     `json:"owner_address"`
 	KeyPrefix    string     `json:"key_prefix"`
 	Name         string     `json:"name"`
+	Tier         string     `json:"tier"`
 	RateLimit    int        `json:"rate_limit"`
+	Scopes       []string   `json:"scopes"`
 	Active       bool       `json:"active"`
 	TotalReqs    int64      `json:"total_requests"`
 	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
@@ -381,28 +480,6 @@ func (db *DB) DeleteAPIKey(ctx context.Context, keyID, ownerAddress string) erro
 	return nil
 }
 
-// ValidateAPIKey validates an API key hash and returns the key info if valid
-func (db *DB) ValidateAPIKey(ctx context.Context, keyHash string) (*APIKey, error) {
-	query := `
-		SELECT id, owner_address, key_prefix, name, rate_limit, active,
-		       total_requests, last_used_at, created_at, expires_at
-		FROM api_keys
-		WHERE key_hash = $1 AND active = true
-		  AND (expires_at IS NULL OR expires_at > NOW())`
-
-	var k APIKey
-	err := db.conn.QueryRowContext(ctx, query, keyHash).Scan(
-		&k.ID, &k.OwnerAddress, &k.KeyPrefix, &k.Name, &k.RateLimit,
-		&k.Active, &k.TotalReqs, &k.LastUsedAt, &k.CreatedAt, &k.ExpiresAt)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-	return &k, nil
-}
-
 // IncrementAPIKeyUsage increments the request count for an API key
 func (db *DB) IncrementAPIKeyUsage(ctx context.Context, keyID string) error {
 	query := `UPDATE api_keys SET total_requests = total_requests + 1, last_used_at = NOW() WHERE id = $1`
@@ -416,6 +493,8 @@ type APIUsageStats struct {
 	RequestsToday   int64 `json:"requests_today"`
 	RequestsThisWeek int64 `json:"requests_this_week"`
 	AvgResponseMs   int   `json:"avg_response_ms"`
+
+	AttestationsThisMonth int64 `json:"attestations_this_month"`
 }
 
 // GetAPIUsage retrieves usage statistics for an owner
@@ -426,6 +505,16 @@ func (db *DB) GetAPIUsage(ctx context.Context, ownerAddress string) (*APIUsageSt
 	query := `SELECT COALESCE(SUM(total_requests), 0) FROM api_keys WHERE owner_address = $1`
 	db.conn.QueryRowContext(ctx, query, ownerAddress).Scan(&stats.TotalRequests)
 
+	// Get attestations issued through the owner's keys this billing month
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	query = `
+		SELECT COALESCE(SUM(i.issued), 0)
+		FROM api_key_attestation_issuance i
+		JOIN api_keys k ON k.id::text = i.api_key_id
+		WHERE k.owner_address = $1 AND i.period_start = $2`
+	db.conn.QueryRowContext(ctx, query, ownerAddress, monthStart).Scan(&stats.AttestationsThisMonth)
+
 	return stats, nil
 }
 
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ClaimExpiryReminder records that the milestone reminder for an attestation
+// is being sent, reporting false if it was already claimed
+func (db *DB) ClaimExpiryReminder(ctx context.Context, attestationUID string, milestone time.Duration) (bool, error) {
+	res, err := db.conn.ExecContext(ctx, `
+		INSERT INTO attestation_expiry_reminders (attestation_uid, milestone_seconds)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, attestationUID, int64(milestone/time.Second))
+	if err != nil {
+		return false, fmt.Errorf("failed to claim expiry reminder: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// HandleRegistration maps a claimed .cert handle to its owner
+type HandleRegistration struct {
+	Handle    string    `json:"handle"`
+	Address   string    `json:"address"`
+	Verified  bool      `json:"verified"` // The chain's CertID profile held the handle for Address at claim time
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Handle history events
+const (
+	HandleEventClaimed     = "claimed"
+	HandleEventReleased    = "released"
+	HandleEventTransferred = "transferred"
+)
+
+// HandleHistoryEntry records one change of a handle's ownership
+type HandleHistoryEntry struct {
+	Handle      string    `json:"handle"`
+	Address     string    `json:"address"`
+	Event       string    `json:"event"`
+	FromAddress string    `json:"from_address,omitempty"` // Previous holder of a transferred handle
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ClaimHandle registers a handle, reporting false if the handle is taken or
+// the address already holds one
+func (db *DB) ClaimHandle(ctx context.Context, reg *HandleRegistration) (bool, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim handle: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO handle_registry (handle, address, verified, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING`,
+		reg.Handle, reg.Address, reg.Verified, reg.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim handle: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if err := insertHandleHistory(ctx, tx, reg.Handle, reg.Address, HandleEventClaimed, "", reg.CreatedAt); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// TransferHandle moves a handle held by from to reg.Address, reporting false
+// if from no longer holds it or reg.Address already holds another handle
+func (db *DB) TransferHandle(ctx context.Context, reg *HandleRegistration, from string) (bool, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to transfer handle: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE handle_registry SET address = $2, verified = $3, created_at = $4
+		WHERE handle = $1 AND address = $5
+		AND NOT EXISTS (SELECT 1 FROM handle_registry WHERE address = $2)`,
+		reg.Handle, reg.Address, reg.Verified, reg.CreatedAt, from)
+	if err != nil {
+		return false, fmt.Errorf("failed to transfer handle: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if err := insertHandleHistory(ctx, tx, reg.Handle, reg.Address, HandleEventTransferred, from, reg.CreatedAt); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// ReleaseHandle frees the handle held by address, returning nil if it holds none
+func (db *DB) ReleaseHandle(ctx context.Context, address string, at time.Time) (*HandleRegistration, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release handle: %w", err)
+	}
+	defer tx.Rollback()
+
+	var reg HandleRegistration
+	err = tx.QueryRowContext(ctx, `
+		DELETE FROM handle_registry WHERE address = $1
+		RETURNING handle, address, verified, created_at`, address).Scan(&reg.Handle, &reg.Address, &reg.Verified, &reg.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to release handle: %w", err)
+	}
+	if err := insertHandleHistory(ctx, tx, reg.Handle, reg.Address, HandleEventReleased, "", at); err != nil {
+		return nil, err
+	}
+	return &reg, tx.Commit()
+}
+
+func insertHandleHistory(ctx context.Context, tx *sql.Tx, handle, address, event, from string, at time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO handle_history (handle, address, event, from_address, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)`,
+		handle, address, event, from, at)
+	if err != nil {
+		return fmt.Errorf("failed to record handle history: %w", err)
+	}
+	return nil
+}
+
+// GetHandle retrieves a registration by handle, returning nil if it is unclaimed
+func (db *DB) GetHandle(ctx context.Context, handle string) (*HandleRegistration, error) {
+	return db.getHandle(ctx, "handle", handle)
+}
+
+// GetHandleByAddress retrieves an address's registration, returning nil if it holds no handle
+func (db *DB) GetHandleByAddress(ctx context.Context, address string) (*HandleRegistration, error) {
+	return db.getHandle(ctx, "address", address)
+}
+
+func (db *DB) getHandle(ctx context.Context, column, value string) (*HandleRegistration, error) {
+	query := `
+		SELECT handle, address, verified, created_at
+		FROM handle_registry
+		WHERE ` + column + ` = $1`
+	var reg HandleRegistration
+	err := db.conn.QueryRowContext(ctx, query, value).Scan(&reg.Handle, &reg.Address, &reg.Verified, &reg.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get handle: %w", err)
+	}
+	return &reg, nil
+}
+
+// GetHandleHistory lists a handle's ownership changes, oldest first
+func (db *DB) GetHandleHistory(ctx context.Context, handle string) ([]HandleHistoryEntry, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT handle, address, event, COALESCE(from_address, ''), created_at
+		FROM handle_history
+		WHERE handle = $1
+		ORDER BY created_at, id`, handle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get handle history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []HandleHistoryEntry
+	for rows.Next() {
+		var e HandleHistoryEntry
+		if err := rows.Scan(&e.Handle, &e.Address, &e.Event, &e.FromAddress, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan handle history: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// HumanityWebhook is a subscription to humanity score threshold crossings
+type HumanityWebhook struct {
+	ID           string    `json:"id"`
+	OwnerAddress string    `json:"owner_address"`
+	URL          string    `json:"url"`
+	Address      string    `json:"address,omitempty"` // Empty watches every address
+	Threshold    int       `json:"threshold"`
+	Direction    string    `json:"direction"`
+	Secret       string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// CreateHumanityWebhook stores a new subscription
+func (db *DB) CreateHumanityWebhook(ctx context.Context, hook *HumanityWebhook) error {
+	query := `
+		INSERT INTO humanity_webhooks (id, owner_address, url, subject_address, threshold, direction, secret)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at`
+	return db.conn.QueryRowContext(ctx, query,
+		hook.ID, hook.OwnerAddress, hook.URL, hook.Address, hook.Threshold, hook.Direction, hook.Secret,
+	).Scan(&hook.CreatedAt)
+}
+
+// ListHumanityWebhooks returns the subscriptions of an owner, or all of them when owner is empty
+func (db *DB) ListHumanityWebhooks(ctx context.Context, owner string) ([]HumanityWebhook, error) {
+	query := `
+		SELECT id, owner_address, url, subject_address, threshold, direction, secret, created_at
+		FROM humanity_webhooks
+		WHERE $1 = '' OR owner_address = $1
+		ORDER BY created_at`
+	rows, err := db.conn.QueryContext(ctx, query, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hooks []HumanityWebhook
+	for rows.Next() {
+		var h HumanityWebhook
+		if err := rows.Scan(&h.ID, &h.OwnerAddress, &h.URL, &h.Address, &h.Threshold, &h.Direction, &h.Secret, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, h)
+	}
+	return hooks, rows.Err()
+}
+
+// DeleteHumanityWebhook removes an owner's subscription, reporting whether it existed
+func (db *DB) DeleteHumanityWebhook(ctx context.Context, owner, id string) (bool, error) {
+	res, err := db.conn.ExecContext(ctx, `DELETE FROM humanity_webhooks WHERE id = $1 AND owner_address = $2`, id, owner)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -3,23 +3,33 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
 // KYCSession represents a Didit KYC verification session
 type KYCSession struct {
-	ID            string     `json:"id"`
-	SessionID     string     `json:"session_id"`      // Didit session_id
-	UserAddress   string     `json:"user_address"`    // CERT wallet address
-	WorkflowID    string     `json:"workflow_id"`     // Didit workflow_id
-	Status        string     `json:"status"`          // Not Started, In Progress, Approved, Declined, Abandoned
-	SessionURL    string     `json:"session_url"`     // URL to redirect user to
-	VendorData    string     `json:"vendor_data"`     // Our reference (user address)
-	DecisionData  *string    `json:"decision_data"`   // JSON decision payload from webhook
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
-	CompletedAt   *time.Time `json:"completed_at"`
+	ID            string            `json:"id"`
+	SessionID     string            `json:"session_id"`               // Didit session_id
+	UserAddress   string            `json:"user_address"`             // CERT wallet address
+	WorkflowID    string            `json:"workflow_id"`              // Didit workflow_id
+	Status        string            `json:"status"`                   // Not Started, In Progress, Approved, Declined, Abandoned
+	SessionURL    string            `json:"session_url"`              // URL to redirect user to
+	VendorData    string            `json:"vendor_data"`              // Our reference (user address)
+	DecisionData  *string           `json:"decision_data"`            // JSON decision payload from webhook
+	Level         int               `json:"level,omitempty"`          // KYC level proven once approved (1 or 2)
+	FailureReason *KYCFailureReason `json:"failure_reason,omitempty"` // Why a declined, abandoned or expired session failed
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	CompletedAt   *time.Time        `json:"completed_at"`
+}
+
+// KYCFailureReason explains why a KYC session ended without approval
+type KYCFailureReason struct {
+	Status       string   `json:"status"`                  // Declined, Abandoned or Expired
+	FailedChecks []string `json:"failed_checks,omitempty"` // Didit checks that did not pass, e.g. liveness
+	Warnings     []string `json:"warnings,omitempty"`      // Didit's descriptions of the problems found
 }
 
 // KYC status constants matching Didit statuses
@@ -50,18 +60,13 @@ func (db *DB) CreateKYCSession(ctx context.Context, session *KYCSession) error {
 	).Scan(&session.ID, &session.CreatedAt, &session.UpdatedAt)
 }
 
-// GetKYCSessionBySessionID retrieves a KYC session by Didit session_id
-func (db *DB) GetKYCSessionBySessionID(ctx context.Context, sessionID string) (*KYCSession, error) {
-	query := `
-		SELECT id, session_id, user_address, workflow_id, status, session_url, vendor_data, 
-		       decision_data, created_at, updated_at, completed_at
-		FROM kyc_sessions
-		WHERE session_id = $1
-	`
+// scanKYCSession scans a kyc_sessions row, returning nil if there is none
+func scanKYCSession(row *sql.Row) (*KYCSession, error) {
 	var s KYCSession
-	err := db.conn.QueryRowContext(ctx, query, sessionID).Scan(
+	var failureReason []byte
+	err := row.Scan(
 		&s.ID, &s.SessionID, &s.UserAddress, &s.WorkflowID, &s.Status, &s.SessionURL,
-		&s.VendorData, &s.DecisionData, &s.CreatedAt, &s.UpdatedAt, &s.CompletedAt,
+		&s.VendorData, &s.DecisionData, &s.Level, &failureReason, &s.CreatedAt, &s.UpdatedAt, &s.CompletedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -69,34 +74,42 @@ func (db *DB) GetKYCSessionBySessionID(ctx context.Context, sessionID string) (*
 	if err != nil {
 		return nil, fmt.Errorf("failed to get KYC session: %w", err)
 	}
+	if failureReason != nil {
+		s.FailureReason = &KYCFailureReason{}
+		if err := json.Unmarshal(failureReason, s.FailureReason); err != nil {
+			return nil, fmt.Errorf("failed to decode KYC failure reason: %w", err)
+		}
+	}
 	return &s, nil
 }
 
+// GetKYCSessionBySessionID retrieves a KYC session by Didit session_id
+func (db *DB) GetKYCSessionBySessionID(ctx context.Context, sessionID string) (*KYCSession, error) {
+	query := `
+		SELECT id, session_id, user_address, workflow_id, status, session_url, vendor_data, 
+		       decision_data, COALESCE(level, 0), failure_reason, created_at, updated_at, completed_at
+		FROM kyc_sessions
+		WHERE session_id = $1
+	`
+	return scanKYCSession(db.conn.QueryRowContext(ctx, query, sessionID))
+}
+
 // GetKYCSessionByUserAddress retrieves the latest KYC session for a user
 func (db *DB) GetKYCSessionByUserAddress(ctx context.Context, userAddress string) (*KYCSession, error) {
 	query := `
 		SELECT id, session_id, user_address, workflow_id, status, session_url, vendor_data, 
-		       decision_data, created_at, updated_at, completed_at
+		       decision_data, COALESCE(level, 0), failure_reason, created_at, updated_at, completed_at
 		FROM kyc_sessions
 		WHERE user_address = $1
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
-	var s KYCSession
-	err := db.conn.QueryRowContext(ctx, query, userAddress).Scan(
-		&s.ID, &s.SessionID, &s.UserAddress, &s.WorkflowID, &s.Status, &s.SessionURL,
-		&s.VendorData, &s.DecisionData, &s.CreatedAt, &s.UpdatedAt, &s.CompletedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to get KYC session: %w", err)
-	}
-	return &s, nil
+	return scanKYCSession(db.conn.QueryRowContext(ctx, query, userAddress))
 }
 
-// UpdateKYCSessionStatus updates the status and optionally decision data
+// UpdateKYCSessionStatus updates the status and optionally decision data. An
+// approved session is only ever updated to Approved again, so a late delivery
+// of an earlier status cannot undo an approval.
 func (db *DB) UpdateKYCSessionStatus(ctx context.Context, sessionID, status string, decisionData *string) error {
 	var query string
 	var args []interface{}
@@ -105,16 +118,16 @@ func (db *DB) UpdateKYCSessionStatus(ctx context.Context, sessionID, status stri
 		query = `
 			UPDATE kyc_sessions 
 			SET status = $1, decision_data = $2, updated_at = NOW(), completed_at = NOW()
-			WHERE session_id = $3
+			WHERE session_id = $3 AND (status <> $4 OR $1 = $4)
 		`
-		args = []interface{}{status, decisionData, sessionID}
+		args = []interface{}{status, decisionData, sessionID, KYCStatusApproved}
 	} else {
 		query = `
 			UPDATE kyc_sessions 
 			SET status = $1, decision_data = $2, updated_at = NOW()
-			WHERE session_id = $3
+			WHERE session_id = $3 AND (status <> $4 OR $1 = $4)
 		`
-		args = []interface{}{status, decisionData, sessionID}
+		args = []interface{}{status, decisionData, sessionID, KYCStatusApproved}
 	}
 
 	result, err := db.conn.ExecContext(ctx, query, args...)
@@ -129,11 +142,54 @@ func (db *DB) UpdateKYCSessionStatus(ctx context.Context, sessionID, status stri
 	return nil
 }
 
-// HasApprovedKYC checks if a user has an approved KYC session
-func (db *DB) HasApprovedKYC(ctx context.Context, userAddress string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM kyc_sessions WHERE user_address = $1 AND status = $2)`
-	var exists bool
-	err := db.conn.QueryRowContext(ctx, query, userAddress, KYCStatusApproved).Scan(&exists)
-	return exists, err
+// SetKYCSessionLevel records the KYC level an approved session proved
+func (db *DB) SetKYCSessionLevel(ctx context.Context, sessionID string, level int) error {
+	query := `UPDATE kyc_sessions SET level = $1, updated_at = NOW() WHERE session_id = $2`
+	result, err := db.conn.ExecContext(ctx, query, level, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to set KYC session level: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetKYCSessionFailureReason records why a session ended without approval
+func (db *DB) SetKYCSessionFailureReason(ctx context.Context, sessionID string, reason *KYCFailureReason) error {
+	data, err := json.Marshal(reason)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE kyc_sessions SET failure_reason = $1, updated_at = NOW() WHERE session_id = $2`
+	result, err := db.conn.ExecContext(ctx, query, data, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to set KYC failure reason: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ApprovedKYCLevel returns the highest KYC level a user was approved for after
+// since, or 0 if none. Approvals before since have expired.
+func (db *DB) ApprovedKYCLevel(ctx context.Context, userAddress string, since time.Time) (int, error) {
+	query := `
+		SELECT COALESCE(MAX(COALESCE(level, 1)), 0)
+		FROM kyc_sessions
+		WHERE user_address = $1 AND status = $2 AND COALESCE(completed_at, updated_at) > $3
+	`
+	var level int
+	err := db.conn.QueryRowContext(ctx, query, userAddress, KYCStatusApproved, since).Scan(&level)
+	return level, err
+}
+
+// HasApprovedKYC checks if a user has a KYC session approved after since
+func (db *DB) HasApprovedKYC(ctx context.Context, userAddress string, since time.Time) (bool, error) {
+	level, err := db.ApprovedKYCLevel(ctx, userAddress, since)
+	return level > 0, err
 }
 
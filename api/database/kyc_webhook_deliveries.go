@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RecordKYCWebhookDelivery claims a Didit webhook delivery for processing,
+// reporting whether it did. A delivery already recorded, including one being
+// processed concurrently, is not claimed again.
+func (db *DB) RecordKYCWebhookDelivery(ctx context.Context, deliveryID, sessionID, status string, at time.Time) (bool, error) {
+	res, err := db.conn.ExecContext(ctx, `
+		INSERT INTO kyc_webhook_deliveries (delivery_id, session_id, status, received_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (delivery_id) DO NOTHING`,
+		deliveryID, sessionID, status, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to record KYC webhook delivery: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ForgetKYCWebhookDelivery releases a claimed delivery whose processing failed,
+// so Didit's retry is processed
+func (db *DB) ForgetKYCWebhookDelivery(ctx context.Context, deliveryID string) error {
+	if _, err := db.conn.ExecContext(ctx, `DELETE FROM kyc_webhook_deliveries WHERE delivery_id = $1`, deliveryID); err != nil {
+		return fmt.Errorf("failed to forget KYC webhook delivery: %w", err)
+	}
+	return nil
+}
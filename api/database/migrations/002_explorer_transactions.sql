@@ -0,0 +1,30 @@
+-- CERT Blockchain Explorer Schema
+-- Indexed transactions with ecosystem-decoded call data
+
+-- Transactions table
+-- Populated by the explorer indexer; decoded_params holds the decoded method call
+CREATE TABLE IF NOT EXISTS transactions (
+    hash VARCHAR(66) PRIMARY KEY,
+    status VARCHAR(16) NOT NULL,
+    block_number BIGINT NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    from_address VARCHAR(64) NOT NULL,
+    to_address VARCHAR(64) NOT NULL,
+    value_cert VARCHAR(78) NOT NULL DEFAULT '0',
+    gas_limit BIGINT NOT NULL DEFAULT 0,
+    gas_used BIGINT NOT NULL DEFAULT 0,
+    gas_price BIGINT NOT NULL DEFAULT 0,
+    tx_fee BIGINT NOT NULL DEFAULT 0,
+    input_data TEXT NOT NULL DEFAULT '0x',
+
+    -- Ecosystem tagging: ChainCertify, CertID, CertToken, Standard
+    ecosystem_type VARCHAR(32) NOT NULL DEFAULT 'Standard',
+    cert_hash VARCHAR(128),
+    metadata TEXT,
+    decoded_params JSONB DEFAULT '{}'
+);
+
+-- Create indexes for transaction lookups
+CREATE INDEX IF NOT EXISTS idx_transactions_from ON transactions(LOWER(from_address));
+CREATE INDEX IF NOT EXISTS idx_transactions_to_time ON transactions(LOWER(to_address), timestamp);
+CREATE INDEX IF NOT EXISTS idx_transactions_block ON transactions(block_number);
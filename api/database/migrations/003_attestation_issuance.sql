@@ -0,0 +1,16 @@
+-- CERT Blockchain API Key Attestation Metering
+-- Attestations issued through an API key, per monthly billing period
+
+CREATE TABLE IF NOT EXISTS api_key_attestation_issuance (
+    api_key_id VARCHAR(64) NOT NULL,
+
+    -- First day of the billing month (UTC)
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    issued INTEGER NOT NULL DEFAULT 0 CHECK (issued >= 0),
+
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (api_key_id, period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attestation_issuance_period ON api_key_attestation_issuance(period_start);
@@ -0,0 +1,23 @@
+-- CERT Blockchain Humanity Score Webhooks
+-- Subscriptions notified when an address's humanity score crosses a threshold
+
+CREATE TABLE IF NOT EXISTS humanity_webhooks (
+    id VARCHAR(64) PRIMARY KEY,
+    owner_address VARCHAR(128) NOT NULL,
+    url TEXT NOT NULL,
+
+    -- Only notify for this address; empty watches every address
+    subject_address VARCHAR(128) NOT NULL DEFAULT '',
+
+    threshold INTEGER NOT NULL CHECK (threshold BETWEEN 1 AND 100),
+
+    -- "up", "down" or "both"
+    direction VARCHAR(8) NOT NULL DEFAULT 'up',
+
+    -- HMAC-SHA256 key for payload signatures (needed in plaintext to sign)
+    secret VARCHAR(128) NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_humanity_webhooks_owner ON humanity_webhooks(owner_address);
@@ -0,0 +1,28 @@
+-- CERT Blockchain Custom Badges
+-- Badge definitions and credential type mappings layered over the standard catalog
+
+CREATE TABLE IF NOT EXISTS badge_definitions (
+    id VARCHAR(64) PRIMARY KEY,
+    name VARCHAR(128) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    icon VARCHAR(256) NOT NULL DEFAULT '',
+
+    -- Holders of this badge are treated as institutions in reputation scoring
+    institutional BOOLEAN NOT NULL DEFAULT FALSE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS badge_mappings (
+    credential_type VARCHAR(64) NOT NULL,
+
+    -- May reference a standard badge, so not a foreign key to badge_definitions
+    badge_id VARCHAR(64) NOT NULL,
+
+    -- Only credentials from this issuer; empty applies to every issuer
+    issuer_address VARCHAR(128) NOT NULL DEFAULT '',
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (credential_type, issuer_address)
+);
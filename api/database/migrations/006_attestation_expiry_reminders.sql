@@ -0,0 +1,13 @@
+-- CERT Blockchain Attestation Expiry Reminders
+-- One row per (attestation, milestone) reminder already sent, so each fires once
+
+CREATE TABLE IF NOT EXISTS attestation_expiry_reminders (
+    attestation_uid VARCHAR(128) NOT NULL,
+
+    -- Lead time before expiry, e.g. 2592000 for the 30 day reminder
+    milestone_seconds BIGINT NOT NULL,
+
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (attestation_uid, milestone_seconds)
+);
@@ -0,0 +1,29 @@
+-- CERT Blockchain Bridge Transfers
+-- Cross-chain transfers from lock request through completion
+
+CREATE TABLE IF NOT EXISTS bridge_transfers (
+    transfer_id VARCHAR(66) PRIMARY KEY,
+    sender VARCHAR(128) NOT NULL,
+    recipient VARCHAR(128) NOT NULL,
+
+    -- Base units; NUMERIC(78, 0) holds any uint256
+    amount NUMERIC(78, 0) NOT NULL CHECK (amount > 0),
+
+    source_chain_id BIGINT NOT NULL,
+    target_chain_id BIGINT NOT NULL,
+
+    -- pending, confirmed, completed or failed
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+
+    tx_hash VARCHAR(128) NOT NULL DEFAULT '',
+    target_tx_hash VARCHAR(128) NOT NULL DEFAULT '',
+    confirmations INTEGER NOT NULL DEFAULT 0,
+    required_confirmations INTEGER NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_bridge_transfers_sender ON bridge_transfers(sender, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bridge_transfers_recipient ON bridge_transfers(recipient, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bridge_transfers_status ON bridge_transfers(status);
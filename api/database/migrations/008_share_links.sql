@@ -0,0 +1,18 @@
+-- CERT Blockchain Credential Share Links
+-- Verification links a recipient hands out for one of their attestations
+
+CREATE TABLE IF NOT EXISTS share_links (
+    id VARCHAR(64) PRIMARY KEY,
+    attestation_uid VARCHAR(128) NOT NULL,
+
+    -- The attestation's recipient, who created the link
+    owner_address VARCHAR(128) NOT NULL,
+
+    -- NULL links never expire
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_links_owner ON share_links(owner_address);
@@ -0,0 +1,20 @@
+-- CERT Blockchain Auth Nonces
+-- One-time sign-in challenges issued by /auth/challenge and consumed by /auth/verify
+
+CREATE TABLE IF NOT EXISTS auth_nonces (
+    nonce VARCHAR(64) PRIMARY KEY,
+
+    -- The address the challenge was issued to, as requested (0x... or cert1...)
+    address VARCHAR(128) NOT NULL,
+
+    -- The exact message the wallet signs
+    message TEXT NOT NULL,
+
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    -- Set when the nonce is consumed; a nonce is never accepted twice
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_nonces_expires ON auth_nonces(expires_at);
@@ -0,0 +1,12 @@
+-- CERT Blockchain Handle Registry
+-- .cert handles claimed by addresses; each handle and each address appears once
+
+CREATE TABLE IF NOT EXISTS handle_registry (
+    -- Normalized handle without the .cert suffix
+    handle VARCHAR(32) PRIMARY KEY,
+
+    -- The claiming address (cert1...)
+    address VARCHAR(128) NOT NULL UNIQUE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
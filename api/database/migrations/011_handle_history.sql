@@ -0,0 +1,24 @@
+-- CERT Blockchain Handle History
+-- Every claim, release and transfer of a .cert handle, kept for impersonation disputes
+
+-- Set when the chain's CertID profile held the handle for the claiming address
+ALTER TABLE handle_registry ADD COLUMN IF NOT EXISTS verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS handle_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    handle VARCHAR(32) NOT NULL,
+
+    -- The address holding the handle after the event, or releasing it
+    address VARCHAR(128) NOT NULL,
+
+    -- claimed, released or transferred
+    event VARCHAR(16) NOT NULL,
+
+    -- The previous holder of a transferred handle
+    from_address VARCHAR(128),
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_handle_history_handle ON handle_history(handle, created_at);
+CREATE INDEX IF NOT EXISTS idx_handle_history_address ON handle_history(address);
@@ -0,0 +1,36 @@
+-- CERT Blockchain Social OAuth
+-- Pending OAuth authorizations and the encrypted provider tokens of accounts verified through them
+
+CREATE TABLE IF NOT EXISTS social_oauth_states (
+    state VARCHAR(64) PRIMARY KEY,
+    user_address VARCHAR(64) NOT NULL,
+    platform VARCHAR(50) NOT NULL,
+
+    -- PKCE verifier sent with the code exchange
+    code_verifier VARCHAR(128) NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    -- Set by the callback; a state is never accepted twice
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_social_oauth_states_expires ON social_oauth_states(expires_at);
+
+CREATE TABLE IF NOT EXISTS social_oauth_tokens (
+    user_address VARCHAR(64) NOT NULL REFERENCES user_profiles(address) ON DELETE CASCADE,
+    platform VARCHAR(50) NOT NULL,
+
+    -- The provider's stable account ID; the handle in social_verifications can change
+    provider_user_id VARCHAR(128) NOT NULL,
+
+    -- nonce || AES-256-GCM ciphertext under the API's token key
+    access_token BYTEA NOT NULL,
+    refresh_token BYTEA,
+    expires_at TIMESTAMP WITH TIME ZONE,
+
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    PRIMARY KEY (user_address, platform)
+);
@@ -0,0 +1,14 @@
+-- CERT Blockchain Social Code Generations
+-- One row per verification code minted by /social/generate, counted for its per-hour limit
+
+CREATE TABLE IF NOT EXISTS social_code_generations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_address VARCHAR(64) NOT NULL,
+    platform VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_social_code_generations_user ON social_code_generations(user_address, platform, created_at);
+
+-- Unverified codes are garbage-collected by creation time
+CREATE INDEX IF NOT EXISTS idx_social_verifications_unverified ON social_verifications(created_at) WHERE verified = false;
@@ -0,0 +1,28 @@
+-- CERT Blockchain Referral Seasons
+-- Referral points are earned in seasons; starting a season restarts the leaderboard
+
+CREATE TABLE IF NOT EXISTS referral_seasons (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+
+    -- Share of each earlier season's points carried into this one, compounding per season
+    decay_factor DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (decay_factor >= 0 AND decay_factor <= 1),
+
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMP WITH TIME ZONE
+);
+
+-- At most one season is open
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referral_seasons_open ON referral_seasons ((ended_at IS NULL)) WHERE ended_at IS NULL;
+
+-- Points earned before seasons existed belong to the first season
+INSERT INTO referral_seasons (id, name, started_at) VALUES (1, 'Season 1', 'epoch') ON CONFLICT (id) DO NOTHING;
+SELECT setval(pg_get_serial_sequence('referral_seasons', 'id'), (SELECT MAX(id) FROM referral_seasons));
+
+DO $$
+BEGIN
+    IF to_regclass('referral_points') IS NOT NULL THEN
+        ALTER TABLE referral_points ADD COLUMN IF NOT EXISTS season_id INTEGER NOT NULL DEFAULT 1 REFERENCES referral_seasons(id);
+        CREATE INDEX IF NOT EXISTS idx_referral_points_season ON referral_points(season_id, user_address);
+    END IF;
+END $$;
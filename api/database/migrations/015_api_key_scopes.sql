@@ -0,0 +1,14 @@
+-- Scoped permissions for API keys
+-- Keys created before scopes existed keep full access; new keys default to read-only
+
+DO $$
+BEGIN
+    IF to_regclass('api_keys') IS NOT NULL THEN
+        ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT[];
+        UPDATE api_keys
+        SET scopes = ARRAY['read:attestations', 'write:attestations', 'read:schemas', 'write:schemas', 'read:identity']
+        WHERE scopes IS NULL;
+        ALTER TABLE api_keys ALTER COLUMN scopes SET DEFAULT ARRAY['read:attestations', 'read:schemas', 'read:identity'];
+        ALTER TABLE api_keys ALTER COLUMN scopes SET NOT NULL;
+    END IF;
+END $$;
@@ -0,0 +1,63 @@
+-- CERT Blockchain API Key Rate Limits
+-- Current daily and per-minute windows per API key, shared by every API replica
+
+CREATE TABLE IF NOT EXISTS api_key_rate_limits (
+    api_key_id VARCHAR(64) PRIMARY KEY,
+
+    -- Weighted budget used in the current UTC day
+    day_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    day_used INTEGER NOT NULL DEFAULT 0 CHECK (day_used >= 0),
+
+    -- Requests made in the current minute
+    minute_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    minute_used INTEGER NOT NULL DEFAULT 0 CHECK (minute_used >= 0)
+);
+
+-- consume_api_rate_limit charges p_cost against the key's daily budget and one
+-- request against its minute cap, only if both allow it. The row lock makes
+-- the check and the increment a single step for concurrent requests.
+-- A limit <= 0 means unlimited.
+CREATE OR REPLACE FUNCTION consume_api_rate_limit(
+    p_key_id VARCHAR,
+    p_day_start TIMESTAMP WITH TIME ZONE,
+    p_minute_start TIMESTAMP WITH TIME ZONE,
+    p_cost INTEGER,
+    p_daily_limit INTEGER,
+    p_minute_limit INTEGER,
+    OUT allowed BOOLEAN,
+    OUT day_used INTEGER
+) AS $$
+DECLARE
+    w api_key_rate_limits%ROWTYPE;
+BEGIN
+    INSERT INTO api_key_rate_limits (api_key_id, day_start, minute_start)
+    VALUES (p_key_id, p_day_start, p_minute_start)
+    ON CONFLICT (api_key_id) DO NOTHING;
+
+    SELECT * INTO w FROM api_key_rate_limits WHERE api_key_id = p_key_id FOR UPDATE;
+
+    -- Roll stale windows over
+    IF w.day_start < p_day_start THEN
+        w.day_start := p_day_start;
+        w.day_used := 0;
+    END IF;
+    IF w.minute_start < p_minute_start THEN
+        w.minute_start := p_minute_start;
+        w.minute_used := 0;
+    END IF;
+
+    allowed := (p_daily_limit <= 0 OR w.day_used + p_cost <= p_daily_limit)
+        AND (p_minute_limit <= 0 OR w.minute_used + 1 <= p_minute_limit);
+    IF allowed THEN
+        w.day_used := w.day_used + p_cost;
+        w.minute_used := w.minute_used + 1;
+    END IF;
+
+    UPDATE api_key_rate_limits
+    SET day_start = w.day_start, day_used = w.day_used,
+        minute_start = w.minute_start, minute_used = w.minute_used
+    WHERE api_key_id = p_key_id;
+
+    day_used := w.day_used;
+END;
+$$ LANGUAGE plpgsql;
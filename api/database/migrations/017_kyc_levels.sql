@@ -0,0 +1,12 @@
+-- KYC levels
+-- The level an approved Didit session proved: 1 (KYC_L1, identity document) or
+-- 2 (KYC_L2, plus liveness and proof of address). Sessions approved before
+-- levels existed were all level 1.
+
+DO $$
+BEGIN
+    IF to_regclass('kyc_sessions') IS NOT NULL THEN
+        ALTER TABLE kyc_sessions ADD COLUMN IF NOT EXISTS level SMALLINT CHECK (level IN (1, 2));
+        UPDATE kyc_sessions SET level = 1 WHERE status = 'Approved' AND level IS NULL;
+    END IF;
+END $$;
@@ -0,0 +1,10 @@
+-- KYC failure reasons
+-- Why a Didit session ended Declined, Abandoned or Expired, parsed from its
+-- decision: {"status": ..., "failed_checks": [...], "warnings": [...]}
+
+DO $$
+BEGIN
+    IF to_regclass('kyc_sessions') IS NOT NULL THEN
+        ALTER TABLE kyc_sessions ADD COLUMN IF NOT EXISTS failure_reason JSONB;
+    END IF;
+END $$;
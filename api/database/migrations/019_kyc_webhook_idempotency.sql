@@ -0,0 +1,25 @@
+-- KYC Webhook Idempotency
+-- A credential is recorded once per user, type and attestation, and each Didit
+-- webhook delivery is processed once however often it is retried
+
+-- Keep the oldest of any duplicates left by earlier redeliveries
+DELETE FROM credentials c USING credentials d
+WHERE c.user_address = d.user_address
+  AND c.credential_type = d.credential_type
+  AND c.attestation_uid = d.attestation_uid
+  AND c.ctid > d.ctid;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_credentials_unique
+    ON credentials(user_address, credential_type, attestation_uid);
+
+CREATE TABLE IF NOT EXISTS kyc_webhook_deliveries (
+    -- SHA-256 of the signed body; Didit retries resend the same body
+    delivery_id VARCHAR(64) PRIMARY KEY,
+
+    session_id VARCHAR(128) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_webhook_deliveries_session ON kyc_webhook_deliveries(session_id);
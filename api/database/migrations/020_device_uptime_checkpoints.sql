@@ -0,0 +1,16 @@
+-- Device Uptime Checkpoints
+-- The latest rolling-window uptime of each hardware device, aggregated from
+-- signed heartbeats by the API and flushed in batches
+
+CREATE TABLE IF NOT EXISTS device_uptime_checkpoints (
+    device_id VARCHAR(128) PRIMARY KEY,
+
+    -- Percentage of window hours with a heartbeat (0-100)
+    uptime DOUBLE PRECISION NOT NULL,
+    online_hours INTEGER NOT NULL,
+
+    as_of TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_uptime_checkpoints_as_of ON device_uptime_checkpoints(as_of);
@@ -33,8 +33,18 @@ type Referral struct {
 	VerifiedAt      *time.Time `json:"verified_at,omitempty"`
 }
 
-// ReferralStats holds referral statistics for a user
+// ReferralSeason is a period referral points are earned and ranked in
+type ReferralSeason struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	DecayFactor float64    `json:"decay_factor"`
+	StartedAt   time.Time  `json:"started_at"`
+	EndedAt     *time.Time `json:"ended_at,omitempty"`
+}
+
+// ReferralStats holds referral statistics for a user in a season
 type ReferralStats struct {
+	SeasonID         int `json:"season_id"`
 	TotalReferrals   int `json:"total_referrals"`
 	VerifiedReferrals int `json:"verified_referrals"`
 	TotalPoints      int `json:"total_points"`
@@ -48,6 +58,7 @@ type LeaderboardEntry struct {
 	DisplayName   string `json:"display_name,omitempty"`
 	ReferralCount int    `json:"referral_count"`
 	TotalPoints   int    `json:"total_points"`
+	Self          bool   `json:"self,omitempty"` // The entry of the address a rank was looked up for
 }
 
 // generateCode creates a random 8-character alphanumeric code
@@ -148,7 +159,9 @@ func (db *DB) ValidateReferralCode(ctx context.Context, code string) (*ReferralC
 	return &rc, nil
 }
 
-// RedeemReferralCode records a referral (called during new user signup)
+// RedeemReferralCode records a referral (called during new user signup).
+// Referrals DetectReferralFraud objects to are recorded as flagged rather than
+// rejected, so the redeemer is not told which check they failed.
 func (db *DB) RedeemReferralCode(ctx context.Context, code, refereeAddress string) error {
 	// Validate code
 	rc, err := db.ValidateReferralCode(ctx, code)
@@ -177,11 +190,23 @@ func (db *DB) RedeemReferralCode(ctx context.Context, code, refereeAddress strin
 		return fmt.Errorf("user has already been referred")
 	}
 	
+	// Fraudulent referrals are recorded flagged, so they never earn points
+	status := "pending"
+	reason, err := db.DetectReferralFraud(ctx, rc.OwnerAddress, refereeAddress)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		status = "flagged"
+		db.logger.Warn("Flagged referral",
+			zap.String("referrer", rc.OwnerAddress), zap.String("referee", refereeAddress), zap.String("reason", reason))
+	}
+
 	// Create referral record
 	_, err = db.conn.ExecContext(ctx, `
 		INSERT INTO referrals (referrer_address, referee_address, referral_code, status)
-		VALUES ($1, $2, $3, 'pending')
-	`, rc.OwnerAddress, refereeAddress, rc.Code)
+		VALUES ($1, $2, $3, $4)
+	`, rc.OwnerAddress, refereeAddress, rc.Code, status)
 	if err != nil {
 		return fmt.Errorf("failed to create referral: %w", err)
 	}
@@ -201,7 +226,8 @@ func (db *DB) RedeemReferralCode(ctx context.Context, code, refereeAddress strin
 	return nil
 }
 
-// VerifyReferral marks a referral as verified and awards points
+// VerifyReferral marks a referral as verified and awards points, or flags it
+// without points if DetectReferralFraud now objects to it
 func (db *DB) VerifyReferral(ctx context.Context, refereeAddress string, pointsPerReferral int) error {
 	tx, err := db.conn.BeginTx(ctx, nil)
 	if err != nil {
@@ -209,26 +235,45 @@ func (db *DB) VerifyReferral(ctx context.Context, refereeAddress string, pointsP
 	}
 	defer tx.Rollback()
 	
-	// Get referral and update status
+	// Get the pending referral
 	var referralID, referrerAddress string
 	err = tx.QueryRowContext(ctx, `
-		UPDATE referrals 
-		SET status = 'verified', verified_at = NOW()
+		SELECT id, referrer_address FROM referrals
 		WHERE referee_address = $1 AND status = 'pending'
-		RETURNING id, referrer_address
+		FOR UPDATE
 	`, refereeAddress).Scan(&referralID, &referrerAddress)
 	
 	if err == sql.ErrNoRows {
 		return nil // No pending referral
 	}
 	if err != nil {
+		return fmt.Errorf("failed to get pending referral: %w", err)
+	}
+
+	// Re-check for fraud, since funding and referrals may have appeared since redemption
+	reason, err := detectReferralFraud(ctx, tx, referrerAddress, refereeAddress)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		if _, err := tx.ExecContext(ctx, "UPDATE referrals SET status = 'flagged' WHERE id = $1", referralID); err != nil {
+			return fmt.Errorf("failed to flag referral: %w", err)
+		}
+		db.logger.Warn("Flagged referral instead of awarding points",
+			zap.String("referrer", referrerAddress), zap.String("referee", refereeAddress), zap.String("reason", reason))
+		return tx.Commit()
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE referrals SET status = 'verified', verified_at = NOW() WHERE id = $1
+	`, referralID); err != nil {
 		return fmt.Errorf("failed to verify referral: %w", err)
 	}
 	
 	// Award points to referrer
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO referral_points (user_address, points, reason, reference_id)
-		VALUES ($1, $2, 'referral', $3)
+		INSERT INTO referral_points (user_address, points, reason, reference_id, season_id)
+		VALUES ($1, $2, 'referral', $3, `+currentSeasonID+`)
 	`, referrerAddress, pointsPerReferral, referralID)
 	if err != nil {
 		return fmt.Errorf("failed to award points: %w", err)
@@ -244,8 +289,8 @@ func (db *DB) VerifyReferral(ctx context.Context, refereeAddress string, pointsP
 		bonusPoints := calculateTierBonus(verifiedCount)
 		if bonusPoints > 0 {
 			_, err = tx.ExecContext(ctx, `
-				INSERT INTO referral_points (user_address, points, reason, metadata)
-				VALUES ($1, $2, 'tier_bonus', $3)
+				INSERT INTO referral_points (user_address, points, reason, metadata, season_id)
+				VALUES ($1, $2, 'tier_bonus', $3, `+currentSeasonID+`)
 			`, referrerAddress, bonusPoints, fmt.Sprintf(`{"tier_count": %d}`, verifiedCount))
 			if err != nil {
 				db.logger.Warn("Failed to award tier bonus", zap.Error(err))
@@ -256,6 +301,119 @@ func (db *DB) VerifyReferral(ctx context.Context, refereeAddress string, pointsP
 	return tx.Commit()
 }
 
+// Reasons DetectReferralFraud flags a referral for
+const (
+	// ReferralFraudCycle means the referee is among the referrer's own referrers
+	ReferralFraudCycle = "referral_cycle"
+
+	// ReferralFraudSharedFunder means the two addresses were funded by the same
+	// address, or one funded the other
+	ReferralFraudSharedFunder = "shared_funder"
+)
+
+// referralCycleDepth is how many referrers up from the referrer are searched
+// for the referee; longer rings are not caught
+const referralCycleDepth = 3
+
+// referralQuerier is satisfied by both *sql.DB and *sql.Tx
+type referralQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// DetectReferralFraud returns why referrer referring referee looks like a
+// self-referral cluster, or "" if it does not. Funding sources come from
+// bridge transfer senders; faucet drips all come from the platform's own
+// wallet, so they do not link addresses.
+func (db *DB) DetectReferralFraud(ctx context.Context, referrer, referee string) (string, error) {
+	return detectReferralFraud(ctx, db.conn, referrer, referee)
+}
+
+func detectReferralFraud(ctx context.Context, q referralQuerier, referrer, referee string) (string, error) {
+	upline, err := referralUpline(ctx, q, referrer, referralCycleDepth)
+	if err != nil {
+		return "", err
+	}
+	referrerFunders, err := fundingSources(ctx, q, referrer)
+	if err != nil {
+		return "", err
+	}
+	refereeFunders, err := fundingSources(ctx, q, referee)
+	if err != nil {
+		return "", err
+	}
+	return referralFraudReason(referrer, referee, upline, referrerFunders, refereeFunders), nil
+}
+
+// referralFraudReason decides DetectReferralFraud from what was looked up
+func referralFraudReason(referrer, referee string, upline, referrerFunders, refereeFunders []string) string {
+	referrer, referee = strings.ToLower(referrer), strings.ToLower(referee)
+	for _, addr := range upline {
+		if strings.ToLower(addr) == referee {
+			return ReferralFraudCycle
+		}
+	}
+
+	funders := map[string]bool{referrer: true}
+	for _, addr := range referrerFunders {
+		addr = strings.ToLower(addr)
+		if addr == referee {
+			return ReferralFraudSharedFunder
+		}
+		funders[addr] = true
+	}
+	for _, addr := range refereeFunders {
+		if funders[strings.ToLower(addr)] {
+			return ReferralFraudSharedFunder
+		}
+	}
+	return ""
+}
+
+// referralUpline returns up to depth addresses above address in the referral
+// graph: its referrer, that address's referrer, and so on
+func referralUpline(ctx context.Context, q referralQuerier, address string, depth int) ([]string, error) {
+	var upline []string
+	for len(upline) < depth {
+		var referrer string
+		err := q.QueryRowContext(ctx,
+			"SELECT referrer_address FROM referrals WHERE referee_address = $1",
+			address,
+		).Scan(&referrer)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get referrer: %w", err)
+		}
+		upline = append(upline, referrer)
+		address = referrer
+	}
+	return upline, nil
+}
+
+// fundingSources returns the addresses that bridged funds to address
+func fundingSources(ctx context.Context, q referralQuerier, address string) ([]string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT DISTINCT LOWER(sender) FROM bridge_transfers
+		WHERE LOWER(recipient) = LOWER($1) AND LOWER(sender) <> LOWER($1) AND status <> 'failed'
+	`, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding sources: %w", err)
+	}
+	defer rows.Close()
+
+	var funders []string
+	for rows.Next() {
+		var funder string
+		if err := rows.Scan(&funder); err != nil {
+			return nil, err
+		}
+		funders = append(funders, funder)
+	}
+	return funders, rows.Err()
+}
+
 // calculateTierBonus returns bonus points for reaching referral milestones
 func calculateTierBonus(count int) int {
 	switch count {
@@ -274,85 +432,282 @@ func calculateTierBonus(count int) int {
 	}
 }
 
-// GetReferralStats returns referral statistics for a user
-func (db *DB) GetReferralStats(ctx context.Context, address string) (*ReferralStats, error) {
-	stats := &ReferralStats{}
-	
+// currentSeasonID selects the open referral season in SQL
+const currentSeasonID = "(SELECT id FROM referral_seasons WHERE ended_at IS NULL)"
+
+// seasonPoints is a CTE of each address's points as counted in season $1:
+// its own points in full, plus each earlier season's points carried over at
+// the season's decay factor, compounded once per season
+const seasonPoints = `
+	season AS (SELECT id, decay_factor FROM referral_seasons WHERE id = $1),
+	season_points AS (
+		SELECT rp.user_address, SUM(rp.points * POWER(season.decay_factor, season.id - rp.season_id)) AS points
+		FROM referral_points rp, season
+		WHERE rp.season_id <= season.id
+		GROUP BY rp.user_address
+	)`
+
+// inSeason is a condition that column falls within season $1
+func inSeason(column string) string {
+	return column + ` >= (SELECT started_at FROM referral_seasons WHERE id = $1)
+		AND ` + column + ` < COALESCE((SELECT ended_at FROM referral_seasons WHERE id = $1), 'infinity')`
+}
+
+// CurrentReferralSeason returns the open season
+func (db *DB) CurrentReferralSeason(ctx context.Context) (*ReferralSeason, error) {
+	season, err := db.scanReferralSeason(db.conn.QueryRowContext(ctx, `
+		SELECT id, name, decay_factor, started_at, ended_at FROM referral_seasons WHERE ended_at IS NULL
+	`))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current season: %w", err)
+	}
+	return season, nil
+}
+
+// GetReferralSeason returns a season, or nil if there is none with that ID
+func (db *DB) GetReferralSeason(ctx context.Context, id int) (*ReferralSeason, error) {
+	season, err := db.scanReferralSeason(db.conn.QueryRowContext(ctx, `
+		SELECT id, name, decay_factor, started_at, ended_at FROM referral_seasons WHERE id = $1
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get season: %w", err)
+	}
+	return season, nil
+}
+
+func (db *DB) scanReferralSeason(row *sql.Row) (*ReferralSeason, error) {
+	var season ReferralSeason
+	if err := row.Scan(&season.ID, &season.Name, &season.DecayFactor, &season.StartedAt, &season.EndedAt); err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// StartReferralSeason closes the open season and opens a new one. decayFactor
+// is the share of earlier seasons' points that counts toward the new season.
+func (db *DB) StartReferralSeason(ctx context.Context, name string, decayFactor float64) (*ReferralSeason, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, `UPDATE referral_seasons SET ended_at = NOW() WHERE ended_at IS NULL`); err != nil {
+		return nil, fmt.Errorf("failed to close season: %w", err)
+	}
+	var season ReferralSeason
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO referral_seasons (name, decay_factor, started_at)
+		VALUES ($1, $2, NOW())
+		RETURNING id, name, decay_factor, started_at, ended_at
+	`, name, decayFactor).Scan(&season.ID, &season.Name, &season.DecayFactor, &season.StartedAt, &season.EndedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start season: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// resolveSeasonID returns seasonID, or the current season's ID if it is 0
+func (db *DB) resolveSeasonID(ctx context.Context, seasonID int) (int, error) {
+	if seasonID != 0 {
+		return seasonID, nil
+	}
+	season, err := db.CurrentReferralSeason(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return season.ID, nil
+}
+
+// GetReferralStats returns referral statistics for a user in a season; 0
+// selects the current season
+func (db *DB) GetReferralStats(ctx context.Context, address string, seasonID int) (*ReferralStats, error) {
+	seasonID, err := db.resolveSeasonID(ctx, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	stats := &ReferralStats{SeasonID: seasonID}
+
 	// Get referral counts
-	err := db.conn.QueryRowContext(ctx, `
-		SELECT 
-			COUNT(*) as total,
-			COUNT(*) FILTER (WHERE status = 'verified') as verified
-		FROM referrals
-		WHERE referrer_address = $1
-	`, address).Scan(&stats.TotalReferrals, &stats.VerifiedReferrals)
+	err = db.conn.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE `+inSeason("r.created_at")+`) as total,
+			COUNT(*) FILTER (WHERE r.status = 'verified' AND `+inSeason("r.verified_at")+`) as verified
+		FROM referrals r
+		WHERE r.referrer_address = $2
+	`, seasonID, address).Scan(&stats.TotalReferrals, &stats.VerifiedReferrals)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to get referral counts: %w", err)
 	}
-	
-	// Get total points
-	err = db.conn.QueryRowContext(ctx,
-		"SELECT COALESCE(SUM(points), 0) FROM referral_points WHERE user_address = $1",
-		address,
-	).Scan(&stats.TotalPoints)
+
+	// Get points, rounded down after decay
+	err = db.conn.QueryRowContext(ctx, `
+		WITH `+seasonPoints+`
+		SELECT COALESCE(FLOOR(SUM(points)), 0)::BIGINT FROM season_points WHERE user_address = $2
+	`, seasonID, address).Scan(&stats.TotalPoints)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to get points: %w", err)
 	}
-	
+
 	return stats, nil
 }
 
-// GetReferralLeaderboard returns the top referrers
-func (db *DB) GetReferralLeaderboard(ctx context.Context, limit int) ([]LeaderboardEntry, error) {
+// rankedReferrers is a CTE ranking everyone with points in season $1. rank
+// is the dense rank by points, so ties share a rank; pos orders the board,
+// breaking ties by referral count and then address.
+var rankedReferrers = seasonPoints + `,
+	season_referrals AS (
+		SELECT r.referrer_address, COUNT(*) AS referral_count
+		FROM referrals r
+		WHERE r.status = 'verified' AND ` + inSeason("r.verified_at") + `
+		GROUP BY r.referrer_address
+	),
+	ranked AS (
+		SELECT
+			sp.user_address,
+			COALESCE(p.name, CONCAT(LEFT(sp.user_address, 8), '...')) as display_name,
+			COALESCE(sr.referral_count, 0) as referral_count,
+			FLOOR(sp.points)::BIGINT as total_points,
+			DENSE_RANK() OVER (ORDER BY FLOOR(sp.points) DESC) as rank,
+			ROW_NUMBER() OVER (ORDER BY FLOOR(sp.points) DESC, COALESCE(sr.referral_count, 0) DESC, sp.user_address) as pos
+		FROM season_points sp
+		LEFT JOIN season_referrals sr ON sr.referrer_address = sp.user_address
+		LEFT JOIN user_profiles p ON p.address = sp.user_address
+		WHERE FLOOR(sp.points) > 0
+	)`
+
+// ReferralRank is an address's place on a season's leaderboard
+type ReferralRank struct {
+	SeasonID      int `json:"season_id"`
+	Rank          int `json:"rank"` // 0 when the address has no points this season
+	TotalPoints   int `json:"total_points"`
+	ReferralCount int `json:"referral_count"`
+
+	// Neighbors are the entries around the address, itself included
+	Neighbors []LeaderboardEntry `json:"neighbors"`
+}
+
+// maskAddress shortens an address for public display
+func maskAddress(address string) string {
+	if len(address) > 12 {
+		return address[:8] + "..." + address[len(address)-4:]
+	}
+	return address
+}
+
+// scanLeaderboardEntries scans ranked rows, masking their addresses. self
+// marks the entry whose address it is, if any.
+func scanLeaderboardEntries(rows *sql.Rows, self string) ([]LeaderboardEntry, int, error) {
+	var entries []LeaderboardEntry
+	var total int
+	for rows.Next() {
+		var e LeaderboardEntry
+		if err := rows.Scan(&e.Address, &e.DisplayName, &e.ReferralCount, &e.TotalPoints, &e.Rank, &total); err != nil {
+			return nil, 0, err
+		}
+		e.Self = self != "" && e.Address == self
+		// Privacy: mask address
+		e.Address = maskAddress(e.Address)
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
+
+// GetReferralLeaderboard returns a page of a season's top referrers and how
+// many referrers the season has in total; season 0 selects the current season
+func (db *DB) GetReferralLeaderboard(ctx context.Context, limit, offset, seasonID int) ([]LeaderboardEntry, int, error) {
 	if limit <= 0 || limit > 100 {
 		limit = 25
 	}
-	
+	if offset < 0 {
+		offset = 0
+	}
+	seasonID, err := db.resolveSeasonID(ctx, seasonID)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	query := `
-		SELECT 
-			r.referrer_address,
-			COALESCE(p.name, CONCAT(LEFT(r.referrer_address, 8), '...')) as display_name,
-			COUNT(*) as referral_count,
-			COALESCE(SUM(rp.points), 0) as total_points
-		FROM referrals r
-		LEFT JOIN user_profiles p ON p.address = r.referrer_address
-		LEFT JOIN referral_points rp ON rp.user_address = r.referrer_address
-		WHERE r.status = 'verified'
-		GROUP BY r.referrer_address, p.name
-		ORDER BY total_points DESC, referral_count DESC
-		LIMIT $1
+		WITH ` + rankedReferrers + `
+		SELECT user_address, display_name, referral_count, total_points, rank, COUNT(*) OVER ()
+		FROM ranked
+		ORDER BY pos
+		LIMIT $2 OFFSET $3
 	`
-	
-	rows, err := db.conn.QueryContext(ctx, query, limit)
+
+	rows, err := db.conn.QueryContext(ctx, query, seasonID, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+		return nil, 0, fmt.Errorf("failed to get leaderboard: %w", err)
 	}
 	defer rows.Close()
-	
-	var entries []LeaderboardEntry
-	rank := 1
-	for rows.Next() {
-		var e LeaderboardEntry
-		if err := rows.Scan(&e.Address, &e.DisplayName, &e.ReferralCount, &e.TotalPoints); err != nil {
-			return nil, err
+
+	entries, total, err := scanLeaderboardEntries(rows, "")
+	if err != nil {
+		return nil, 0, err
+	}
+	// A page past the end has no rows to carry the total
+	if len(entries) == 0 && offset > 0 {
+		err = db.conn.QueryRowContext(ctx, `WITH `+rankedReferrers+` SELECT COUNT(*) FROM ranked`, seasonID).Scan(&total)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to count leaderboard: %w", err)
 		}
-		e.Rank = rank
-		// Privacy: mask address
-		if len(e.Address) > 12 {
-			e.Address = e.Address[:8] + "..." + e.Address[len(e.Address)-4:]
+	}
+	return entries, total, nil
+}
+
+// GetReferralRank returns address's dense rank and points in a season, with
+// up to window entries either side of it; season 0 selects the current season
+func (db *DB) GetReferralRank(ctx context.Context, address string, window, seasonID int) (*ReferralRank, error) {
+	if window < 0 {
+		window = 0
+	}
+	seasonID, err := db.resolveSeasonID(ctx, seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH ` + rankedReferrers + `,
+		target AS (SELECT pos FROM ranked WHERE user_address = $2)
+		SELECT user_address, display_name, referral_count, total_points, rank, 0
+		FROM ranked, target
+		WHERE ranked.pos BETWEEN target.pos - $3 AND target.pos + $3
+		ORDER BY ranked.pos
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query, seasonID, address, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referral rank: %w", err)
+	}
+	defer rows.Close()
+
+	neighbors, _, err := scanLeaderboardEntries(rows, address)
+	if err != nil {
+		return nil, err
+	}
+	rank := &ReferralRank{SeasonID: seasonID, Neighbors: neighbors}
+	for _, e := range neighbors {
+		if e.Self {
+			rank.Rank, rank.TotalPoints, rank.ReferralCount = e.Rank, e.TotalPoints, e.ReferralCount
 		}
-		entries = append(entries, e)
-		rank++
 	}
-	
-	return entries, rows.Err()
+	if rank.Neighbors == nil {
+		rank.Neighbors = []LeaderboardEntry{}
+	}
+	return rank, nil
 }
 
-// AddReferralPoints adds points to a user's account
+// AddReferralPoints adds points to a user's account in the current season
 func (db *DB) AddReferralPoints(ctx context.Context, address string, points int, reason string) error {
 	_, err := db.conn.ExecContext(ctx, `
-		INSERT INTO referral_points (user_address, points, reason)
-		VALUES ($1, $2, $3)
+		INSERT INTO referral_points (user_address, points, reason, season_id)
+		VALUES ($1, $2, $3, `+currentSeasonID+`)
 	`, address, points, reason)
 	return err
 }
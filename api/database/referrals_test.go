@@ -0,0 +1,330 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestReferralFraudReason(t *testing.T) {
+	tests := []struct {
+		name                            string
+		upline, referrerFunds, refFunds []string
+		want                            string
+	}{
+		{"unrelated", []string{"0xc"}, []string{"0xf1"}, []string{"0xf2"}, ""},
+		{"no history", nil, nil, nil, ""},
+		{"two-way ring", []string{"0xB"}, nil, nil, ReferralFraudCycle},
+		{"three-way ring", []string{"0xc", "0xb"}, nil, nil, ReferralFraudCycle},
+		{"shared funder", nil, []string{"0xf1", "0xfund"}, []string{"0xFUND"}, ReferralFraudSharedFunder},
+		{"referrer funded referee", nil, nil, []string{"0xa"}, ReferralFraudSharedFunder},
+		{"referee funded referrer", nil, []string{"0xb"}, nil, ReferralFraudSharedFunder},
+	}
+	for _, tt := range tests {
+		if got := referralFraudReason("0xA", "0xb", tt.upline, tt.referrerFunds, tt.refFunds); got != tt.want {
+			t.Errorf("%s: referralFraudReason = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// newReferralTestDB needs the referral tables in the test database; it
+// returns a unique address prefix whose rows are removed after the test
+func newReferralTestDB(t *testing.T) (*DB, string) {
+	t.Helper()
+	db := newTestDB(t, "007_bridge_transfers.sql", "014_referral_seasons.sql")
+	prefix := fmt.Sprintf("0xtest%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_, _ = db.conn.Exec(`DELETE FROM referral_points WHERE user_address LIKE $1`, prefix+"%")
+		_, _ = db.conn.Exec(`DELETE FROM referrals WHERE referrer_address LIKE $1`, prefix+"%")
+		_, _ = db.conn.Exec(`DELETE FROM referral_codes WHERE owner_address LIKE $1`, prefix+"%")
+		_, _ = db.conn.Exec(`DELETE FROM bridge_transfers WHERE transfer_id LIKE $1`, prefix+"%")
+	})
+	return db, prefix
+}
+
+func referralStatus(t *testing.T, db *DB, referee string) string {
+	t.Helper()
+	var status string
+	if err := db.conn.QueryRow(`SELECT status FROM referrals WHERE referee_address = $1`, referee).Scan(&status); err != nil {
+		t.Fatal(err)
+	}
+	return status
+}
+
+func referralPoints(t *testing.T, db *DB, address string) int {
+	t.Helper()
+	stats, err := db.GetReferralStats(context.Background(), address, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stats.TotalPoints
+}
+
+func TestRedeemReferralCodeFlagsCycle(t *testing.T) {
+	db, prefix := newReferralTestDB(t)
+	ctx := context.Background()
+	alice, bob := prefix+"-alice", prefix+"-bob"
+
+	aliceCode, err := db.GenerateReferralCode(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobCode, err := db.GenerateReferralCode(ctx, bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Alice refers Bob, then Bob refers Alice back
+	if err := db.RedeemReferralCode(ctx, aliceCode.Code, bob); err != nil {
+		t.Fatal(err)
+	}
+	if got := referralStatus(t, db, bob); got != "pending" {
+		t.Fatalf("alice -> bob status = %q, want pending", got)
+	}
+	if err := db.RedeemReferralCode(ctx, bobCode.Code, alice); err != nil {
+		t.Fatal(err)
+	}
+	if got := referralStatus(t, db, alice); got != "flagged" {
+		t.Errorf("bob -> alice status = %q, want flagged", got)
+	}
+
+	// Verification skips flagged referrals, and the ring flags the other half too
+	if err := db.VerifyReferral(ctx, alice, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.VerifyReferral(ctx, bob, 100); err != nil {
+		t.Fatal(err)
+	}
+	if got := referralStatus(t, db, bob); got != "flagged" {
+		t.Errorf("alice -> bob status after verification = %q, want flagged", got)
+	}
+	if a, b := referralPoints(t, db, alice), referralPoints(t, db, bob); a != 0 || b != 0 {
+		t.Errorf("points = %d, %d; want none awarded in a ring", a, b)
+	}
+}
+
+func TestVerifyReferralFlagsSharedFunder(t *testing.T) {
+	db, prefix := newReferralTestDB(t)
+	ctx := context.Background()
+	referrer, referee, honest, funder := prefix+"-referrer", prefix+"-referee", prefix+"-honest", prefix+"-funder"
+
+	code, err := db.GenerateReferralCode(ctx, referrer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RedeemReferralCode(ctx, code.Code, referee); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RedeemReferralCode(ctx, code.Code, honest); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same wallet funds the referrer and, after redemption, the referee
+	for i, recipient := range []string{referrer, referee} {
+		if err := db.CreateBridgeTransfer(ctx, &BridgeTransfer{
+			TransferID: fmt.Sprintf("%s-%d", prefix, i), Sender: funder, Recipient: recipient,
+			Amount: "1000", SourceChainID: 1, TargetChainID: 2, Status: "completed", RequiredConfirm: 1,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if reason, err := db.DetectReferralFraud(ctx, referrer, referee); err != nil || reason != ReferralFraudSharedFunder {
+		t.Errorf("DetectReferralFraud = %q, %v; want %q", reason, err, ReferralFraudSharedFunder)
+	}
+
+	for _, addr := range []string{referee, honest} {
+		if err := db.VerifyReferral(ctx, addr, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := referralStatus(t, db, referee); got != "flagged" {
+		t.Errorf("funded referee status = %q, want flagged", got)
+	}
+	if got := referralStatus(t, db, honest); got != "verified" {
+		t.Errorf("unrelated referee status = %q, want verified", got)
+	}
+	if got := referralPoints(t, db, referrer); got != 100 {
+		t.Errorf("referrer points = %d, want 100 for the unrelated referee only", got)
+	}
+}
+
+// TestReferralSeasons starts seasons, so it closes whatever season the test
+// database had open
+func TestReferralSeasons(t *testing.T) {
+	db, prefix := newReferralTestDB(t)
+	ctx := context.Background()
+	referrer, referee := prefix+"-referrer", prefix+"-referee"
+	t.Cleanup(func() { _, _ = db.conn.Exec(`DELETE FROM user_profiles WHERE address = $1`, referrer) })
+	if _, err := db.conn.Exec(`INSERT INTO user_profiles (address, name) VALUES ($1, $1)`, referrer); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := db.StartReferralSeason(ctx, prefix+" first", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := db.GenerateReferralCode(ctx, referrer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RedeemReferralCode(ctx, code.Code, referee); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.VerifyReferral(ctx, referee, 100); err != nil {
+		t.Fatal(err)
+	}
+	if got := referralPoints(t, db, referrer); got != 100 {
+		t.Fatalf("points in the first season = %d, want 100", got)
+	}
+
+	// A fresh season starts from zero, and its leaderboard leaves the referrer out
+	second, err := db.StartReferralSeason(ctx, prefix+" second", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed, err := db.GetReferralSeason(ctx, first.ID)
+	if err != nil || closed == nil || closed.EndedAt == nil {
+		t.Fatalf("first season after the second started = %+v, %v; want it closed", closed, err)
+	}
+	if current, err := db.CurrentReferralSeason(ctx); err != nil || current.ID != second.ID {
+		t.Fatalf("current season = %+v, %v; want %d", current, err, second.ID)
+	}
+	stats, err := db.GetReferralStats(ctx, referrer, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.SeasonID != second.ID || stats.TotalPoints != 0 || stats.VerifiedReferrals != 0 {
+		t.Errorf("current season stats = %+v, want no points or referrals", stats)
+	}
+	entries, _, err := db.GetReferralLeaderboard(ctx, 100, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.DisplayName == referrer {
+			t.Errorf("closed season's points ranked on the current leaderboard: %+v", e)
+		}
+	}
+
+	// The closed season still reports its own totals
+	stats, err = db.GetReferralStats(ctx, referrer, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TotalPoints != 100 || stats.VerifiedReferrals != 1 {
+		t.Errorf("first season stats = %+v, want 100 points from 1 referral", stats)
+	}
+
+	// With decay, earlier seasons carry over at the factor per season elapsed
+	if _, err := db.StartReferralSeason(ctx, prefix+" third", 0.5); err != nil {
+		t.Fatal(err)
+	}
+	if got := referralPoints(t, db, referrer); got != 25 {
+		t.Errorf("points two seasons later at decay 0.5 = %d, want 25", got)
+	}
+}
+
+// TestReferralRank seeds 50 referrers into a new season, so it closes
+// whatever season the test database had open
+func TestReferralRank(t *testing.T) {
+	db, prefix := newReferralTestDB(t)
+	ctx := context.Background()
+	if _, err := db.StartReferralSeason(ctx, prefix+" ranks", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Referrer i has (50-i)*10 points, except 21 ties with 20
+	referrers := make([]string, 50)
+	for i := range referrers {
+		referrers[i] = fmt.Sprintf("%s-%02d", prefix, i)
+		points := (50 - i) * 10
+		if i == 21 {
+			points = (50 - 20) * 10
+		}
+		if _, err := db.conn.Exec(`INSERT INTO user_profiles (address, name) VALUES ($1, $1)`, referrers[i]); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.AddReferralPoints(ctx, referrers[i], points, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { _, _ = db.conn.Exec(`DELETE FROM user_profiles WHERE address LIKE $1`, prefix+"%") })
+
+	// Dense ranks: 1..21 through the tie, then 22 for referrer 22
+	wantRank := func(i int) int {
+		if i <= 21 {
+			return min(i, 20) + 1
+		}
+		return i
+	}
+	names := func(entries []LeaderboardEntry) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.DisplayName)
+		}
+		return out
+	}
+
+	for _, tt := range []struct {
+		referrer, window int
+		neighbors        []int
+	}{
+		{30, 2, []int{28, 29, 30, 31, 32}},
+		{0, 2, []int{0, 1, 2}},
+		{49, 3, []int{46, 47, 48, 49}},
+		{21, 1, []int{20, 21, 22}},
+		{10, 0, []int{10}},
+	} {
+		rank, err := db.GetReferralRank(ctx, referrers[tt.referrer], tt.window, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rank.Rank != wantRank(tt.referrer) || rank.TotalPoints == 0 {
+			t.Errorf("referrer %d: rank %d with %d points, want rank %d", tt.referrer, rank.Rank, rank.TotalPoints, wantRank(tt.referrer))
+		}
+		var want []string
+		for _, i := range tt.neighbors {
+			want = append(want, referrers[i])
+		}
+		if got := names(rank.Neighbors); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("referrer %d window %d: neighbors %v, want %v", tt.referrer, tt.window, got, want)
+		}
+		for i, e := range rank.Neighbors {
+			if e.Self != (tt.neighbors[i] == tt.referrer) {
+				t.Errorf("referrer %d: neighbor %s self = %v", tt.referrer, e.DisplayName, e.Self)
+			}
+			if e.Rank != wantRank(tt.neighbors[i]) {
+				t.Errorf("referrer %d: neighbor %s rank %d, want %d", tt.referrer, e.DisplayName, e.Rank, wantRank(tt.neighbors[i]))
+			}
+			if e.Address != maskAddress(referrers[tt.neighbors[i]]) {
+				t.Errorf("neighbor address %q is not masked", e.Address)
+			}
+		}
+	}
+
+	unranked, err := db.GetReferralRank(ctx, prefix+"-nobody", 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unranked.Rank != 0 || len(unranked.Neighbors) != 0 {
+		t.Errorf("address without points = %+v, want unranked", unranked)
+	}
+
+	// Pages line up with the ranks
+	page, total, err := db.GetReferralLeaderboard(ctx, 10, 20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 50 || len(page) != 10 {
+		t.Fatalf("page at offset 20 = %d entries of %d, want 10 of 50", len(page), total)
+	}
+	for j, e := range page {
+		if e.DisplayName != referrers[20+j] || e.Rank != wantRank(20+j) {
+			t.Errorf("entry %d = %s rank %d, want %s rank %d", 20+j, e.DisplayName, e.Rank, referrers[20+j], wantRank(20+j))
+		}
+	}
+	if page, total, err := db.GetReferralLeaderboard(ctx, 10, 60, 0); err != nil || len(page) != 0 || total != 50 {
+		t.Errorf("page past the end = %d entries of %d, %v; want none of 50", len(page), total, err)
+	}
+}
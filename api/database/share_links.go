@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ShareLink is a verification link for one attestation, created by its recipient
+type ShareLink struct {
+	ID             string     `json:"id"`
+	AttestationUID string     `json:"attestation_uid"`
+	OwnerAddress   string     `json:"owner_address"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"` // nil never expires
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// CreateShareLink stores a new share link
+func (db *DB) CreateShareLink(ctx context.Context, link *ShareLink) error {
+	query := `
+		INSERT INTO share_links (id, attestation_uid, owner_address, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)`
+	if _, err := db.conn.ExecContext(ctx, query, link.ID, link.AttestationUID, link.OwnerAddress, link.ExpiresAt, link.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create share link: %w", err)
+	}
+	return nil
+}
+
+// GetShareLink retrieves a share link by ID, returning nil if it does not exist
+func (db *DB) GetShareLink(ctx context.Context, id string) (*ShareLink, error) {
+	query := `
+		SELECT id, attestation_uid, owner_address, expires_at, revoked_at, created_at
+		FROM share_links
+		WHERE id = $1`
+	var link ShareLink
+	var expiresAt, revokedAt sql.NullTime
+	err := db.conn.QueryRowContext(ctx, query, id).Scan(
+		&link.ID, &link.AttestationUID, &link.OwnerAddress, &expiresAt, &revokedAt, &link.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+	if expiresAt.Valid {
+		link.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		link.RevokedAt = &revokedAt.Time
+	}
+	return &link, nil
+}
+
+// RevokeShareLink revokes an owner's link to an attestation, reporting whether
+// a link that was not already revoked existed
+func (db *DB) RevokeShareLink(ctx context.Context, owner, attestationUID, id string, at time.Time) (bool, error) {
+	res, err := db.conn.ExecContext(ctx, `
+		UPDATE share_links SET revoked_at = $4
+		WHERE id = $1 AND owner_address = $2 AND attestation_uid = $3 AND revoked_at IS NULL`,
+		id, owner, attestationUID, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke share link: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SocialOAuthState is a pending OAuth authorization started by an address
+type SocialOAuthState struct {
+	State        string     `json:"state"`
+	UserAddress  string     `json:"user_address"`
+	Platform     string     `json:"platform"`
+	CodeVerifier string     `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	UsedAt       *time.Time `json:"used_at,omitempty"`
+}
+
+// SocialOAuthAccount is a social account verified through OAuth. The tokens
+// are already encrypted by the caller.
+type SocialOAuthAccount struct {
+	UserAddress    string
+	Platform       string
+	ProviderUserID string
+	Handle         string
+	ProfileURL     string
+	AccessToken    []byte
+	RefreshToken   []byte
+	TokenExpiresAt *time.Time
+	VerifiedAt     time.Time
+}
+
+// CreateSocialOAuthState stores a pending authorization, pruning states that
+// expired before it was created
+func (db *DB) CreateSocialOAuthState(ctx context.Context, st *SocialOAuthState) error {
+	if _, err := db.conn.ExecContext(ctx, `DELETE FROM social_oauth_states WHERE expires_at < $1`, st.CreatedAt); err != nil {
+		return fmt.Errorf("failed to prune oauth states: %w", err)
+	}
+	query := `
+		INSERT INTO social_oauth_states (state, user_address, platform, code_verifier, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	if _, err := db.conn.ExecContext(ctx, query, st.State, st.UserAddress, st.Platform, st.CodeVerifier, st.CreatedAt, st.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to create oauth state: %w", err)
+	}
+	return nil
+}
+
+// ConsumeSocialOAuthState marks an unused, unexpired state used at the given
+// time and returns it, or nil if there was no such state. Of two concurrent
+// callbacks only one consumes the state.
+func (db *DB) ConsumeSocialOAuthState(ctx context.Context, state string, at time.Time) (*SocialOAuthState, error) {
+	query := `
+		UPDATE social_oauth_states SET used_at = $2
+		WHERE state = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING state, user_address, platform, code_verifier, created_at, expires_at, used_at`
+	var st SocialOAuthState
+	var usedAt sql.NullTime
+	err := db.conn.QueryRowContext(ctx, query, state, at).Scan(
+		&st.State, &st.UserAddress, &st.Platform, &st.CodeVerifier, &st.CreatedAt, &st.ExpiresAt, &usedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume oauth state: %w", err)
+	}
+	if usedAt.Valid {
+		st.UsedAt = &usedAt.Time
+	}
+	return &st, nil
+}
+
+// CompleteSocialOAuth marks the account's social verification complete with
+// its provider handle and stores its tokens, creating the user profile if needed
+func (db *DB) CompleteSocialOAuth(ctx context.Context, acct *SocialOAuthAccount) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO user_profiles (address, name) VALUES ($1, 'Anonymous User')
+		ON CONFLICT (address) DO NOTHING`, acct.UserAddress); err != nil {
+		return fmt.Errorf("failed to ensure user profile: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO social_verifications (user_address, platform, handle, proof_url, verified, verified_at)
+		VALUES ($1, $2, $3, $4, TRUE, $5)
+		ON CONFLICT (user_address, platform) DO UPDATE SET
+			handle = EXCLUDED.handle,
+			proof_url = EXCLUDED.proof_url,
+			verified = TRUE,
+			verified_at = EXCLUDED.verified_at`,
+		acct.UserAddress, acct.Platform, acct.Handle, acct.ProfileURL, acct.VerifiedAt); err != nil {
+		return fmt.Errorf("failed to mark social verification complete: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO social_oauth_tokens (user_address, platform, provider_user_id, access_token, refresh_token, expires_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_address, platform) DO UPDATE SET
+			provider_user_id = EXCLUDED.provider_user_id,
+			access_token = EXCLUDED.access_token,
+			refresh_token = EXCLUDED.refresh_token,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = EXCLUDED.updated_at`,
+		acct.UserAddress, acct.Platform, acct.ProviderUserID, acct.AccessToken, acct.RefreshToken, acct.TokenExpiresAt, acct.VerifiedAt); err != nil {
+		return fmt.Errorf("failed to store oauth tokens: %w", err)
+	}
+	return tx.Commit()
+}
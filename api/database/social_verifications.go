@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"time"
 )
 
@@ -95,3 +97,41 @@ func (db *DB) CountVerifiedSocialAccounts(ctx context.Context, address string) (
 	return count, err
 }
 
+
+// RecordSocialCodeGeneration logs a verification code minted for an address and platform
+func (db *DB) RecordSocialCodeGeneration(ctx context.Context, address, platform string, at time.Time) error {
+	query := `INSERT INTO social_code_generations (user_address, platform, created_at) VALUES ($1, $2, $3)`
+	if _, err := db.conn.ExecContext(ctx, query, address, platform, at); err != nil {
+		return fmt.Errorf("failed to record social code generation: %w", err)
+	}
+	return nil
+}
+
+// SocialCodeGenerationsSince counts the codes minted for an address and
+// platform after the given time, and returns the oldest of them
+func (db *DB) SocialCodeGenerationsSince(ctx context.Context, address, platform string, since time.Time) (int, time.Time, error) {
+	query := `
+		SELECT COUNT(*), MIN(created_at)
+		FROM social_code_generations
+		WHERE user_address = $1 AND platform = $2 AND created_at > $3
+	`
+	var count int
+	var oldest sql.NullTime
+	if err := db.conn.QueryRowContext(ctx, query, address, platform, since).Scan(&count, &oldest); err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to count social code generations: %w", err)
+	}
+	return count, oldest.Time, nil
+}
+
+// DeleteStaleSocialVerifications removes unverified codes created before the
+// given time, and generation records older than it, returning the codes removed
+func (db *DB) DeleteStaleSocialVerifications(ctx context.Context, before time.Time) (int64, error) {
+	res, err := db.conn.ExecContext(ctx, `DELETE FROM social_verifications WHERE verified = FALSE AND created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete stale social verifications: %w", err)
+	}
+	if _, err := db.conn.ExecContext(ctx, `DELETE FROM social_code_generations WHERE created_at < $1`, before); err != nil {
+		return 0, fmt.Errorf("failed to prune social code generations: %w", err)
+	}
+	return res.RowsAffected()
+}
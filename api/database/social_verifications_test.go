@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestSocialCodeGenerations needs the initial schema's user_profiles and
+// social_verifications tables in the test database
+func TestSocialCodeGenerations(t *testing.T) {
+	db := newTestDB(t, "013_social_code_generations.sql")
+	ctx := context.Background()
+
+	address := fmt.Sprintf("0xtest%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_, _ = db.conn.Exec(`DELETE FROM social_code_generations WHERE user_address = $1`, address)
+		_, _ = db.conn.Exec(`DELETE FROM user_profiles WHERE address = $1`, address)
+	})
+	if _, err := db.conn.Exec(`INSERT INTO user_profiles (address, name) VALUES ($1, 'Anonymous User')`, address); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	for _, age := range []time.Duration{30 * time.Hour, 3 * time.Hour, 50 * time.Minute, 10 * time.Minute} {
+		if err := db.RecordSocialCodeGeneration(ctx, address, "twitter", now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.RecordSocialCodeGeneration(ctx, address, "github", now); err != nil {
+		t.Fatal(err)
+	}
+
+	count, oldest, err := db.SocialCodeGenerationsSince(ctx, address, "twitter", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || !oldest.Equal(now.Add(-50*time.Minute)) {
+		t.Errorf("generations since an hour ago = %d, oldest %v; want 2, %v", count, oldest, now.Add(-50*time.Minute))
+	}
+	if count, _, err := db.SocialCodeGenerationsSince(ctx, address, "linkedin", now.Add(-time.Hour)); err != nil || count != 0 {
+		t.Errorf("linkedin generations = %d, %v; want 0", count, err)
+	}
+
+	// One stale and one fresh unverified code, and a stale verified account
+	for platform, row := range map[string]struct {
+		verified bool
+		age      time.Duration
+	}{
+		"twitter":  {false, 25 * time.Hour},
+		"linkedin": {false, time.Hour},
+		"github":   {true, 48 * time.Hour},
+	} {
+		if _, err := db.conn.Exec(`
+			INSERT INTO social_verifications (user_address, platform, handle, verified, created_at)
+			VALUES ($1, $2, 'C3RT-TEST', $3, $4)`, address, platform, row.verified, now.Add(-row.age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deleted, err := db.DeleteStaleSocialVerifications(ctx, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted %d codes, want 1", deleted)
+	}
+	if _, err := db.GetSocialVerificationByAddressPlatform(ctx, address, "twitter"); err == nil {
+		t.Error("stale unverified code was kept")
+	}
+	for _, platform := range []string{"linkedin", "github"} {
+		if _, err := db.GetSocialVerificationByAddressPlatform(ctx, address, platform); err != nil {
+			t.Errorf("%s verification was deleted: %v", platform, err)
+		}
+	}
+	if count, _, err := db.SocialCodeGenerationsSince(ctx, address, "twitter", time.Time{}); err != nil || count != 3 {
+		t.Errorf("twitter generations after cleanup = %d, %v; want the 3 from the last day", count, err)
+	}
+}
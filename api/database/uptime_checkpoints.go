@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// UptimeCheckpoint is the rolling-window uptime of a hardware device
+type UptimeCheckpoint struct {
+	DeviceID    string    `json:"device_id"`
+	Uptime      float64   `json:"uptime"` // Percentage of window hours with a heartbeat (0-100)
+	OnlineHours int       `json:"online_hours"`
+	AsOf        time.Time `json:"as_of"`
+}
+
+// UpsertUptimeCheckpoints stores a batch of checkpoints in one transaction,
+// keeping each device's newest. Either the whole batch is stored or none of it.
+func (db *DB) UpsertUptimeCheckpoints(ctx context.Context, checkpoints []UptimeCheckpoint) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to store uptime checkpoints: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, c := range checkpoints {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO device_uptime_checkpoints (device_id, uptime, online_hours, as_of, updated_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			ON CONFLICT (device_id) DO UPDATE
+			SET uptime = EXCLUDED.uptime, online_hours = EXCLUDED.online_hours,
+			    as_of = EXCLUDED.as_of, updated_at = NOW()
+			WHERE device_uptime_checkpoints.as_of <= EXCLUDED.as_of`,
+			c.DeviceID, c.Uptime, c.OnlineHours, c.AsOf)
+		if err != nil {
+			return fmt.Errorf("failed to store uptime checkpoint for %s: %w", c.DeviceID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to store uptime checkpoints: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestUpsertUptimeCheckpoints(t *testing.T) {
+	db := newTestDB(t, "020_device_uptime_checkpoints.sql")
+	ctx := context.Background()
+
+	// Unique per run so the test can share a database
+	device := fmt.Sprintf("dev_test%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_, _ = db.conn.Exec(`DELETE FROM device_uptime_checkpoints WHERE device_id = $1`, device)
+	})
+
+	asOf := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	stored := func() (float64, int) {
+		t.Helper()
+		var uptime float64
+		var hours int
+		err := db.conn.QueryRow(`SELECT uptime, online_hours FROM device_uptime_checkpoints WHERE device_id = $1`, device).Scan(&uptime, &hours)
+		if err != nil {
+			t.Fatalf("read checkpoint: %v", err)
+		}
+		return uptime, hours
+	}
+
+	if err := db.UpsertUptimeCheckpoints(ctx, []UptimeCheckpoint{{DeviceID: device, Uptime: 3.57, OnlineHours: 6, AsOf: asOf}}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if err := db.UpsertUptimeCheckpoints(ctx, []UptimeCheckpoint{{DeviceID: device, Uptime: 7.14, OnlineHours: 12, AsOf: asOf.Add(time.Hour)}}); err != nil {
+		t.Fatalf("upsert newer: %v", err)
+	}
+	if uptime, hours := stored(); uptime != 7.14 || hours != 12 {
+		t.Errorf("checkpoint = %v%%, %d hours; want the newer one", uptime, hours)
+	}
+
+	// A late retry of an older checkpoint does not overwrite a newer one
+	if err := db.UpsertUptimeCheckpoints(ctx, []UptimeCheckpoint{{DeviceID: device, Uptime: 1, OnlineHours: 1, AsOf: asOf}}); err != nil {
+		t.Fatalf("upsert older: %v", err)
+	}
+	if uptime, hours := stored(); uptime != 7.14 || hours != 12 {
+		t.Errorf("checkpoint = %v%%, %d hours after an older retry", uptime, hours)
+	}
+}
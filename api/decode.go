@@ -0,0 +1,112 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// maxJSONBodyBytes caps request bodies decoded by decodeJSONBody
+	maxJSONBodyBytes = 1 << 20
+
+	// maxJSONDepth caps object/array nesting in request bodies
+	maxJSONDepth = 32
+)
+
+// errEmptyBody is returned by decodeJSONBody when the request has no body
+var errEmptyBody = errors.New("body is empty")
+
+// decodeJSONBody decodes a size- and depth-limited JSON request body into dst.
+// Unknown fields are ignored. Errors describe the problem without echoing the
+// body and are meant for respondDecodeError.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	return decodeJSON(w, r, dst, false)
+}
+
+// decodeJSONBodyStrict is decodeJSONBody but rejects fields dst does not declare
+func decodeJSONBodyStrict(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	return decodeJSON(w, r, dst, true)
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}, strict bool) error {
+	if r.Body == nil {
+		return errEmptyBody
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxJSONBodyBytes))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return fmt.Errorf("body exceeds %d bytes", maxJSONBodyBytes)
+		}
+		return errors.New("failed to read body")
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return errEmptyBody
+	}
+	if err := checkJSONDepth(body, maxJSONDepth); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &typeErr) && typeErr.Field != "":
+			return fmt.Errorf("field %q has the wrong type", typeErr.Field)
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			return fmt.Errorf("unknown field %s", strings.TrimPrefix(err.Error(), "json: unknown field "))
+		default:
+			return errors.New("malformed JSON")
+		}
+	}
+	if dec.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
+
+// checkJSONDepth rejects documents nesting objects/arrays deeper than max.
+// It only tracks brackets outside strings, so it is cheap enough to run
+// before the real decode.
+func checkJSONDepth(body []byte, max int) error {
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range body {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > max {
+				return fmt.Errorf("nesting exceeds %d levels", max)
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return nil
+}
+
+// respondDecodeError answers 400 for a decodeJSONBody error
+func (s *Server) respondDecodeError(w http.ResponseWriter, err error) {
+	s.respondError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+}
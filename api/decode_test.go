@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type decodeTestRequest struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestDecodeJSONBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		strict  bool
+		wantErr string
+	}{
+		{name: "valid", body: `{"name":"a","count":2}`},
+		{name: "unknown field allowed", body: `{"name":"a","extra":true}`},
+		{name: "unknown field strict", body: `{"name":"a","extra":true}`, strict: true, wantErr: `unknown field "extra"`},
+		{name: "wrong type", body: `{"count":"two"}`, wantErr: `field "count" has the wrong type`},
+		{name: "malformed", body: `{"name":`, wantErr: "malformed JSON"},
+		{name: "trailing data", body: `{"name":"a"}{"name":"b"}`, wantErr: "unexpected data after JSON value"},
+		{name: "empty", body: "  ", wantErr: "body is empty"},
+		{name: "oversize", body: `{"name":"` + strings.Repeat("x", maxJSONBodyBytes) + `"}`, wantErr: "body exceeds"},
+		{name: "deeply nested", body: `{"name":` + strings.Repeat("[", maxJSONDepth+1) + strings.Repeat("]", maxJSONDepth+1) + `}`, wantErr: "nesting exceeds"},
+		{name: "brackets inside strings", body: `{"name":"` + strings.Repeat("[{", maxJSONDepth) + `\"]"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			var dst decodeTestRequest
+			var err error
+			if tt.strict {
+				err = decodeJSONBodyStrict(httptest.NewRecorder(), req, &dst)
+			} else {
+				err = decodeJSONBody(httptest.NewRecorder(), req, &dst)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONBodyEmptyIsDistinct(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", nil)
+	var dst decodeTestRequest
+	if err := decodeJSONBody(httptest.NewRecorder(), req, &dst); !errors.Is(err, errEmptyBody) {
+		t.Fatalf("err = %v, want errEmptyBody", err)
+	}
+}
+
+// TestHandlersRejectJSONBombs tests that POST handlers answer a consistent 400 before doing any work
+func TestHandlersRejectJSONBombs(t *testing.T) {
+	config := DefaultConfig()
+	s := NewServer(config, zap.NewNop())
+	token := testAuthToken(t, config.JWTSecret, "cert1decodetester")
+	nested := strings.Repeat("[", 10_000) + strings.Repeat("]", 10_000)
+
+	tests := []struct {
+		name, path, body string
+	}{
+		{"deep nesting", "/api/v1/schemas", `{"schema":` + nested + `}`},
+		{"oversize", "/api/v1/schemas", `{"schema":"` + strings.Repeat("a", 2*maxJSONBodyBytes) + `"}`},
+		{"unknown field on strict handler", "/api/v1/staking/delegate", `{"validator_address":"x","amount":"1","amount_ucert":"1000000"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			s.router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400 (body %.200s)", w.Code, w.Body.String())
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if !strings.HasPrefix(resp.Message, "Invalid request body: ") {
+				t.Errorf("message = %q", resp.Message)
+			}
+		})
+	}
+}
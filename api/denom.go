@@ -0,0 +1,153 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DenomUnit mirrors a bank DenomUnit: Exponent is the power of ten relative to the base denom
+type DenomUnit struct {
+	Denom    string   `json:"denom"`
+	Exponent uint32   `json:"exponent"`
+	Aliases  []string `json:"aliases,omitempty"`
+}
+
+// DenomMetadata describes the native token and how base amounts are displayed.
+// It matches the bank denom metadata registered at genesis (app.GetBankGenesisState);
+// every handler converting ucert to CERT goes through it.
+type DenomMetadata struct {
+	Base     string      `json:"base"`
+	Display  string      `json:"display"`
+	Symbol   string      `json:"symbol"`
+	Exponent uint32      `json:"exponent"`
+	Units    []DenomUnit `json:"denom_units"`
+
+	// DisplayPrecision is the number of decimals FormatDisplay renders
+	DisplayPrecision int `json:"display_precision"`
+}
+
+// DefaultDenomMetadata returns the CERT token metadata: 1 CERT = 10^6 ucert
+func DefaultDenomMetadata() DenomMetadata {
+	return DenomMetadata{
+		Base:     "ucert",
+		Display:  "cert",
+		Symbol:   "CERT",
+		Exponent: 6,
+		Units: []DenomUnit{
+			{Denom: "ucert", Exponent: 0, Aliases: []string{"microcert"}},
+			{Denom: "mcert", Exponent: 3, Aliases: []string{"millicert"}},
+			{Denom: "cert", Exponent: 6, Aliases: []string{"CERT"}},
+		},
+		DisplayPrecision: 6,
+	}
+}
+
+// maxDenomExponent bounds Exponent so display strings stay parseable
+const maxDenomExponent = 18
+
+// Validate checks that the metadata can convert amounts
+func (m DenomMetadata) Validate() error {
+	if m.Base == "" || m.Display == "" {
+		return errors.New("denom metadata needs base and display denoms")
+	}
+	if m.Exponent > maxDenomExponent {
+		return fmt.Errorf("denom exponent %d exceeds %d", m.Exponent, maxDenomExponent)
+	}
+	if m.DisplayPrecision < 0 || m.DisplayPrecision > int(m.Exponent) {
+		return fmt.Errorf("display precision %d must be between 0 and %d", m.DisplayPrecision, m.Exponent)
+	}
+	return nil
+}
+
+// FormatAmount renders a base-denom amount in display units with precision
+// decimals (clamped to [0, Exponent]). Extra digits are truncated rather than
+// rounded so a balance is never overstated.
+func (m DenomMetadata) FormatAmount(amount int64, precision int) string {
+	exp := int(m.Exponent)
+	if precision < 0 {
+		precision = 0
+	}
+	if precision > exp {
+		precision = exp
+	}
+
+	digits := new(big.Int).Abs(big.NewInt(amount)).String()
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-exp], digits[len(digits)-exp:]
+
+	out := whole
+	if precision > 0 {
+		out += "." + frac[:precision]
+	}
+	// Drop the sign when truncation left nothing but zeros
+	if amount < 0 && strings.Trim(out, "0.") != "" {
+		out = "-" + out
+	}
+	return out
+}
+
+// FormatDisplay renders a base-denom amount with the configured DisplayPrecision
+func (m DenomMetadata) FormatDisplay(amount int64) string {
+	return m.FormatAmount(amount, m.DisplayPrecision)
+}
+
+// FormatDisplayString is FormatDisplay for a base amount held as a decimal
+// string, as returned by the bank REST and CLI queries. Unparseable input
+// yields "".
+func (m DenomMetadata) FormatDisplayString(amount string) string {
+	n, err := strconv.ParseInt(strings.TrimSpace(amount), 10, 64)
+	if err != nil {
+		return ""
+	}
+	return m.FormatDisplay(n)
+}
+
+// ParseAmount converts a display-unit decimal string (e.g. "1.5") to a
+// base-denom amount. More fractional digits than Exponent, or a result outside
+// int64, is an error.
+func (m DenomMetadata) ParseAmount(display string) (int64, error) {
+	s := strings.TrimSpace(display)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("invalid %s amount %q", m.Display, display)
+	}
+	if len(frac) > int(m.Exponent) {
+		return 0, fmt.Errorf("%s amount %q has more than %d decimal places", m.Display, display, m.Exponent)
+	}
+	for _, c := range whole + frac {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid %s amount %q", m.Display, display)
+		}
+	}
+
+	digits := whole + frac + strings.Repeat("0", int(m.Exponent)-len(frac))
+	n, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return 0, fmt.Errorf("invalid %s amount %q", m.Display, display)
+	}
+	if neg {
+		n.Neg(n)
+	}
+	if !n.IsInt64() {
+		return 0, fmt.Errorf("%s amount %q is out of range", m.Display, display)
+	}
+	return n.Int64(), nil
+}
+
+// handleGetNetwork returns chain identity and native token metadata
+// GET /api/v1/network
+func (s *Server) handleGetNetwork(w http.ResponseWriter, r *http.Request) {
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"chain_id": s.config.ChainID,
+		"denom":    s.denom,
+	})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestDenomFormatAmount(t *testing.T) {
+	m := DefaultDenomMetadata()
+	tests := []struct {
+		amount    int64
+		precision int
+		want      string
+	}{
+		{0, 6, "0.000000"},
+		{1, 6, "0.000001"},
+		{1, 2, "0.00"},
+		{1_500_000, 6, "1.500000"},
+		{1_500_000, 0, "1"},
+		{1_999_999, 2, "1.99"}, // truncated, not rounded
+		{10_000_000, -1, "10"},
+		{10_000_000, 9, "10.000000"}, // precision clamps to the exponent
+		{-2_500_000, 1, "-2.5"},
+		{-1, 2, "0.00"},
+		{math.MaxInt64, 6, "9223372036854.775807"},
+		{math.MinInt64, 6, "-9223372036854.775808"},
+	}
+	for _, tt := range tests {
+		if got := m.FormatAmount(tt.amount, tt.precision); got != tt.want {
+			t.Errorf("FormatAmount(%d, %d) = %q, want %q", tt.amount, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestDenomRoundTrip(t *testing.T) {
+	m := DefaultDenomMetadata()
+	for _, amount := range []int64{0, 1, 999_999, 1_000_000, 123_456_789, math.MaxInt64, -1, math.MinInt64} {
+		display := m.FormatAmount(amount, int(m.Exponent))
+		got, err := m.ParseAmount(display)
+		if err != nil {
+			t.Fatalf("ParseAmount(%q): %v", display, err)
+		}
+		if got != amount {
+			t.Errorf("round trip %d -> %q -> %d", amount, display, got)
+		}
+	}
+}
+
+func TestDenomParseAmount(t *testing.T) {
+	m := DefaultDenomMetadata()
+	valid := map[string]int64{
+		"1":       1_000_000,
+		"1.5":     1_500_000,
+		".000001": 1,
+		"10.":     10_000_000,
+		" 2.25 ":  2_250_000,
+	}
+	for in, want := range valid {
+		got, err := m.ParseAmount(in)
+		if err != nil || got != want {
+			t.Errorf("ParseAmount(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", ".", "abc", "1.0000001", "1e6", "1,5", "9223372036854.775808", "-9223372036854.775809"} {
+		if _, err := m.ParseAmount(in); err == nil {
+			t.Errorf("ParseAmount(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestDenomFormatDisplayUsesConfiguredPrecision(t *testing.T) {
+	config := DefaultConfig()
+	config.Denom.DisplayPrecision = 2
+	s := NewServer(config, zap.NewNop())
+	if got := s.denom.FormatDisplay(1_234_567); got != "1.23" {
+		t.Errorf("FormatDisplay = %q, want 1.23", got)
+	}
+	if got := s.denom.FormatDisplayString("not-a-number"); got != "" {
+		t.Errorf("FormatDisplayString(invalid) = %q, want empty", got)
+	}
+
+	config.Denom.DisplayPrecision = 7
+	if s := NewServer(config, zap.NewNop()); s.denom.DisplayPrecision != 6 {
+		t.Errorf("invalid precision kept: %d", s.denom.DisplayPrecision)
+	}
+}
+
+func TestHandleGetNetwork(t *testing.T) {
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/network", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+
+	var out struct {
+		ChainID string        `json:"chain_id"`
+		Denom   DenomMetadata `json:"denom"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ChainID == "" || out.Denom.Base != "ucert" || out.Denom.Symbol != "CERT" || out.Denom.Exponent != 6 {
+		t.Errorf("network = %+v", out)
+	}
+	if len(out.Denom.Units) != 3 || out.Denom.Units[2].Exponent != 6 {
+		t.Errorf("denom units = %+v", out.Denom.Units)
+	}
+}
@@ -0,0 +1,460 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/retry"
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+// Server-side encrypted uploads
+//
+// POST /api/v1/encrypted-attestations expects the client to have already encrypted the
+// file, pinned the ciphertext and wrapped the key for every recipient, so the API only
+// ever sees ciphertext. POST /api/v1/encrypted-attestations/upload is an optional
+// convenience for clients without that crypto stack: it accepts the PLAINTEXT file and
+// does the whole flow (AES-256-GCM, IPFS pin, ECIES key wrap, hash) server-side.
+//
+// Trust trade-off: the API operator sees the plaintext and the symmetric key. Both are
+// discarded once the request completes, but a compromised or malicious operator could
+// read or retain them. Clients that cannot trust the operator with the document must
+// keep encrypting client-side.
+
+// EncryptedUploadConfig configures POST /encrypted-attestations/upload
+type EncryptedUploadConfig struct {
+	IPFSAPIURL   string        // IPFS (Kubo) RPC API used for pinning ("" disables the endpoint)
+	MaxFileBytes int64         // Largest plaintext accepted
+	PinTimeout   time.Duration // Deadline for pinning the ciphertext
+}
+
+// DefaultEncryptedUploadConfig returns the default upload settings; uploads are off until an IPFS API is set
+func DefaultEncryptedUploadConfig() EncryptedUploadConfig {
+	return EncryptedUploadConfig{
+		MaxFileBytes: 100 * 1024 * 1024, // Matches the chain's MaxEncryptedFileSize
+		PinTimeout:   2 * time.Minute,
+	}
+}
+
+// encryptedUploadFormOverhead is the multipart framing allowed on top of the file itself
+const encryptedUploadFormOverhead = 1 << 20
+
+// ipfsPinner stores content on IPFS and keeps it pinned
+type ipfsPinner interface {
+	Pin(ctx context.Context, data []byte) (cid string, err error)
+}
+
+// ipfsRetry retries IPFS calls that fail on the network, 429 or 5xx; adding
+// content is idempotent, so a repeated add pins the same CID
+var ipfsRetry = retry.DefaultPolicy()
+
+// kuboPinner pins through the IPFS Kubo RPC API
+type kuboPinner struct {
+	apiURL string
+	http   *http.Client
+}
+
+func (p kuboPinner) Pin(ctx context.Context, data []byte) (string, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "ciphertext")
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	resp, err := retry.DoHTTP(ctx, ipfsRetry, p.http, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(p.apiURL, "/")+"/api/v0/add?pin=true&cid-version=1", bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return req, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("ipfs add: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var out struct {
+		Hash string `json:"Hash"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("ipfs add: %w", err)
+	}
+	if out.Hash == "" {
+		return "", errors.New("ipfs add: empty CID")
+	}
+	return out.Hash, nil
+}
+
+// uploadRecipient is a recipient identified by its secp256k1 public key
+type uploadRecipient struct {
+	Address string
+	PubKey  *ecdsa.PublicKey
+}
+
+// parseRecipientKey decodes a hex or base64 secp256k1 public key (compressed or uncompressed)
+func parseRecipientKey(s string) (uploadRecipient, error) {
+	s = strings.TrimSpace(s)
+	raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		if raw, err = base64.StdEncoding.DecodeString(s); err != nil {
+			return uploadRecipient{}, errors.New("public key must be hex or base64")
+		}
+	}
+	var pub *ecdsa.PublicKey
+	switch len(raw) {
+	case 33:
+		pub, err = crypto.DecompressPubkey(raw)
+	case 65:
+		pub, err = crypto.UnmarshalPubkey(raw)
+	default:
+		return uploadRecipient{}, fmt.Errorf("public key must be 33 or 65 bytes, got %d", len(raw))
+	}
+	if err != nil {
+		return uploadRecipient{}, fmt.Errorf("invalid secp256k1 public key: %w", err)
+	}
+	addr, err := bech32.ConvertAndEncode("cert", cosmosAddressFromPubkey(crypto.CompressPubkey(pub)))
+	if err != nil {
+		return uploadRecipient{}, err
+	}
+	return uploadRecipient{Address: addr, PubKey: pub}, nil
+}
+
+// sealedUpload is the output of encryptForRecipients
+type sealedUpload struct {
+	Ciphertext    []byte            // nonce || AES-256-GCM ciphertext
+	EncryptedKeys map[string]string // recipient address -> base64 ECIES-wrapped AES key
+}
+
+// encryptForRecipients encrypts plaintext under a fresh AES-256 key and wraps that key for each recipient
+func encryptForRecipients(plaintext []byte, recipients []uploadRecipient) (sealedUpload, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return sealedUpload{}, err
+	}
+	defer func() {
+		for i := range key {
+			key[i] = 0
+		}
+	}()
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return sealedUpload{}, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return sealedUpload{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return sealedUpload{}, err
+	}
+
+	out := sealedUpload{
+		Ciphertext:    gcm.Seal(nonce, nonce, plaintext, nil),
+		EncryptedKeys: make(map[string]string, len(recipients)),
+	}
+	for _, rcpt := range recipients {
+		wrapped, err := ecies.Encrypt(rand.Reader, ecies.ImportECDSAPublic(rcpt.PubKey), key, nil, nil)
+		if err != nil {
+			return sealedUpload{}, fmt.Errorf("wrap key for %s: %w", rcpt.Address, err)
+		}
+		out.EncryptedKeys[rcpt.Address] = base64.StdEncoding.EncodeToString(wrapped)
+	}
+	return out, nil
+}
+
+// encryptedUploadAuthorizationMessage is what the attester signs (ADR-036) to let the
+// server anchor an upload: it binds the schema, the plaintext and the recipient set
+func encryptedUploadAuthorizationMessage(schemaUID string, plaintextHash string, recipients []string) string {
+	sorted := append([]string(nil), recipients...)
+	sort.Strings(sorted)
+	return "cert-encrypted-upload:v1|" + schemaUID + "|" + plaintextHash + "|" + strings.Join(sorted, ",")
+}
+
+// uploadAuthorization is a Keplr signArbitrary result
+type uploadAuthorization struct {
+	Signature string `json:"signature"`
+	PubKey    string `json:"pub_key"`
+}
+
+// verifyUploadAuthorization checks that attester signed message
+func verifyUploadAuthorization(raw, attester, message string) error {
+	var auth uploadAuthorization
+	if err := json.Unmarshal([]byte(raw), &auth); err != nil {
+		return errors.New("authorization must be a JSON object with signature and pub_key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(auth.Signature)
+	if err != nil || len(sig) < 64 {
+		return errors.New("invalid authorization signature")
+	}
+	pub, err := base64.StdEncoding.DecodeString(auth.PubKey)
+	if err != nil || len(pub) != 33 {
+		return errors.New("authorization pub_key must be a base64 compressed secp256k1 key")
+	}
+	signer, err := bech32.ConvertAndEncode("cert", cosmosAddressFromPubkey(pub))
+	if err != nil || signer != attester {
+		return errors.New("authorization was not signed by the attester")
+	}
+	if !crypto.VerifySignature(pub, adr036SignDocHash(attester, []byte(message)), sig[:64]) {
+		return errors.New("authorization signature verification failed")
+	}
+	return nil
+}
+
+// unsignedEncryptedAttestationTx renders msg as an unsigned tx, like `certd tx --generate-only`
+func unsignedEncryptedAttestationTx(msg *attestationtypes.MsgCreateEncryptedAttestation) map[string]any {
+	message := map[string]any{
+		"@type":                    "/" + msg.XXX_MessageName(),
+		"attester":                 msg.Attester,
+		"schema_uid":               msg.SchemaUID,
+		"ipfs_cid":                 msg.IPFSCID,
+		"encrypted_data_hash":      msg.EncryptedDataHash,
+		"recipients":               msg.Recipients,
+		"encrypted_symmetric_keys": msg.EncryptedSymmetricKeys,
+		"revocable":                msg.Revocable,
+		"expiration_time":          strconv.FormatInt(msg.ExpirationTime, 10),
+	}
+	return map[string]any{
+		"body": map[string]any{
+			"messages": []any{message},
+			"memo":     "",
+		},
+		"auth_info":  map[string]any{"signer_infos": []any{}, "fee": map[string]any{"amount": []any{}, "gas_limit": "200000"}},
+		"signatures": []any{},
+	}
+}
+
+// handleUploadEncryptedAttestation handles POST /api/v1/encrypted-attestations/upload
+func (s *Server) handleUploadEncryptedAttestation(w http.ResponseWriter, r *http.Request) {
+	s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+		if s.ipfs == nil {
+			s.respondError(w, http.StatusServiceUnavailable, "server-side encrypted upload is not enabled")
+			return
+		}
+
+		limit := s.config.EncryptedUploads.MaxFileBytes
+		r.Body = http.MaxBytesReader(w, r.Body, limit+encryptedUploadFormOverhead)
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				s.respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("upload exceeds %d bytes", limit))
+				return
+			}
+			s.respondError(w, http.StatusBadRequest, "expected a multipart/form-data body")
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		schemaUID := strings.TrimSpace(r.FormValue("schema_uid"))
+		if schemaUID == "" {
+			s.respondError(w, http.StatusBadRequest, "schema_uid is required")
+			return
+		}
+		revocable := true
+		if v := r.FormValue("revocable"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				s.respondError(w, http.StatusBadRequest, "revocable must be a boolean")
+				return
+			}
+			revocable = b
+		}
+		var expiration int64
+		if v := r.FormValue("expiration_time"); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 0 {
+				s.respondError(w, http.StatusBadRequest, "expiration_time must be a unix timestamp")
+				return
+			}
+			expiration = n
+		}
+
+		keys := r.MultipartForm.Value["recipient_key"]
+		if len(keys) == 0 {
+			s.respondError(w, http.StatusBadRequest, "at least one recipient_key is required")
+			return
+		}
+		// Per Whitepaper Section 12: Max 50 recipients
+		if !s.enforceBatchSize(w, len(keys), s.config.BatchLimits.Recipients) {
+			return
+		}
+		recipients := make([]uploadRecipient, 0, len(keys))
+		addresses := make([]string, 0, len(keys))
+		seen := make(map[string]bool, len(keys))
+		for _, k := range keys {
+			rcpt, err := parseRecipientKey(k)
+			if err != nil {
+				s.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid recipient_key: %v", err))
+				return
+			}
+			if seen[rcpt.Address] {
+				s.respondError(w, http.StatusBadRequest, "duplicate recipient "+rcpt.Address)
+				return
+			}
+			seen[rcpt.Address] = true
+			recipients = append(recipients, rcpt)
+			addresses = append(addresses, rcpt.Address)
+		}
+
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, "file is required")
+			return
+		}
+		plaintext, err := io.ReadAll(io.LimitReader(file, limit+1))
+		file.Close()
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, "failed to read file")
+			return
+		}
+		if len(plaintext) == 0 {
+			s.respondError(w, http.StatusBadRequest, "file is empty")
+			return
+		}
+		if int64(len(plaintext)) > limit {
+			s.respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("upload exceeds %d bytes", limit))
+			return
+		}
+
+		attester, err := toBech32Address(getAuthenticatedAddress(r))
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid attester address: %v", err))
+			return
+		}
+
+		// Check the anchoring authorization before doing any work on its behalf
+		authorization := strings.TrimSpace(r.FormValue("authorization"))
+		if authorization != "" {
+			plainHash := sha256.Sum256(plaintext)
+			message := encryptedUploadAuthorizationMessage(schemaUID, hex.EncodeToString(plainHash[:]), addresses)
+			if err := verifyUploadAuthorization(authorization, attester, message); err != nil {
+				s.respondError(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+		}
+
+		sealed, err := encryptForRecipients(plaintext, recipients)
+		if err != nil {
+			s.logger.Error("encrypted upload: encryption failed", zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "encryption failed")
+			return
+		}
+		cipherHash := sha256.Sum256(sealed.Ciphertext)
+		encryptedHash := hex.EncodeToString(cipherHash[:])
+
+		pinCtx, cancel := context.WithTimeout(r.Context(), s.config.EncryptedUploads.PinTimeout)
+		defer cancel()
+		cid, err := s.ipfs.Pin(pinCtx, sealed.Ciphertext)
+		if err != nil {
+			s.logger.Error("encrypted upload: ipfs pin failed", zap.Error(err))
+			s.respondError(w, http.StatusBadGateway, "failed to pin ciphertext to IPFS")
+			return
+		}
+
+		msg := attestationtypes.NewMsgCreateEncryptedAttestation(
+			attester, schemaUID, cid, encryptedHash, addresses, sealed.EncryptedKeys, revocable, expiration,
+		)
+		msg.FileSize = uint64(len(sealed.Ciphertext))
+		s.logger.Info("Encrypted upload pinned",
+			zap.String("attester", attester),
+			zap.String("schema_uid", schemaUID),
+			zap.String("ipfs_cid", cid),
+			zap.Int("recipients", len(addresses)),
+			zap.Bool("anchor", authorization != ""),
+		)
+
+		if authorization == "" {
+			s.respondJSON(w, http.StatusOK, map[string]interface{}{
+				"ipfs_cid":            cid,
+				"encrypted_data_hash": encryptedHash,
+				"recipients":          addresses,
+				"encrypted_keys":      sealed.EncryptedKeys,
+				"unsigned_tx":         unsignedEncryptedAttestationTx(msg),
+				"message":             "Sign and broadcast this transaction to anchor the attestation",
+			})
+			return
+		}
+
+		// As with POST /attestations, the API's signer broadcasts; the authorization records the attester's consent
+		keysJSON, err := json.Marshal(sealed.EncryptedKeys)
+		if err != nil {
+			s.respondError(w, http.StatusInternalServerError, "failed to encode recipient keys")
+			return
+		}
+		txCtx, txCancel := context.WithTimeout(r.Context(), 60*time.Second)
+		defer txCancel()
+
+		var txRes certdTxResponse
+		args := []string{"attestation", "create-encrypted", schemaUID, cid, encryptedHash, strings.Join(addresses, ","), string(keysJSON),
+			"--revocable=" + strconv.FormatBool(revocable)}
+		if expiration != 0 {
+			args = append(args, "--expiration", strconv.FormatInt(expiration, 10))
+		}
+		_, err = s.execCertdTxJSON(txCtx, &txRes, args...)
+		if err != nil {
+			s.logger.Error("encrypted attestation tx failed", zap.Error(err))
+			var txErr *certdTxExecError
+			if errors.As(err, &txErr) && txErr.Tx.Code != 0 {
+				s.respondTxError(w, http.StatusBadRequest, "encrypted attestation tx rejected", txErr.Tx.RawLog)
+				return
+			}
+			s.respondError(w, http.StatusBadGateway, "failed to submit encrypted attestation tx")
+			return
+		}
+		if txRes.Code != 0 {
+			s.respondTxError(w, http.StatusBadRequest, "encrypted attestation tx rejected", txRes.RawLog)
+			return
+		}
+
+		uid, _ := findTxEventAttribute(txRes, "attestation_uid")
+		if uid == "" {
+			s.logger.Warn("encrypted attestation tx succeeded but attestation_uid not found in events", zap.String("txhash", txRes.TxHash))
+		}
+
+		txTime := certdTxTimestamp(txRes.Timestamp)
+		s.respondJSON(w, http.StatusCreated, map[string]interface{}{
+			"uid":                 uid,
+			"tx_hash":             txRes.TxHash,
+			"ipfs_cid":            cid,
+			"encrypted_data_hash": encryptedHash,
+			"recipients":          addresses,
+			"encrypted_keys":      sealed.EncryptedKeys,
+			"timestamp":           txTime,
+			"timestamp_unix":      txTime.Unix(),
+		})
+	})(w, r)
+}
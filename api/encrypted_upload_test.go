@@ -0,0 +1,272 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+	"go.uber.org/zap"
+)
+
+// mockIPFS is an in-memory ipfsPinner
+type mockIPFS struct {
+	pinned map[string][]byte
+	err    error
+}
+
+func (m *mockIPFS) Pin(_ context.Context, data []byte) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	sum := sha256.Sum256(data)
+	cid := "bafy" + hex.EncodeToString(sum[:8])
+	m.pinned[cid] = append([]byte(nil), data...)
+	return cid, nil
+}
+
+func newUploadServer(t *testing.T) (*Server, *mockIPFS) {
+	t.Helper()
+	config := DefaultConfig()
+	s := NewServer(config, zap.NewNop())
+	ipfs := &mockIPFS{pinned: map[string][]byte{}}
+	s.ipfs = ipfs
+	return s, ipfs
+}
+
+func secp256k1Address(t *testing.T, key *ecdsa.PrivateKey) string {
+	t.Helper()
+	addr, err := bech32.ConvertAndEncode("cert", cosmosAddressFromPubkey(crypto.CompressPubkey(&key.PublicKey)))
+	if err != nil {
+		t.Fatalf("bech32: %v", err)
+	}
+	return addr
+}
+
+func postUpload(t *testing.T, s *Server, attester string, fields map[string][]string, file []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, values := range fields {
+		for _, v := range values {
+			_ = mw.WriteField(name, v)
+		}
+	}
+	if file != nil {
+		part, _ := mw.CreateFormFile("file", "contract.pdf")
+		_, _ = part.Write(file)
+	}
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/encrypted-attestations/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+testAuthToken(t, s.config.JWTSecret, attester))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// decryptUpload opens a pinned ciphertext the way a recipient would
+func decryptUpload(t *testing.T, key *ecdsa.PrivateKey, wrappedKey string, ciphertext []byte) []byte {
+	t.Helper()
+	wrapped, err := base64.StdEncoding.DecodeString(wrappedKey)
+	if err != nil {
+		t.Fatalf("wrapped key encoding: %v", err)
+	}
+	aesKey, err := ecies.ImportECDSA(key).Decrypt(wrapped, nil, nil)
+	if err != nil {
+		t.Fatalf("unwrap key: %v", err)
+	}
+	block, _ := aes.NewCipher(aesKey)
+	gcm, _ := cipher.NewGCM(block)
+	n := gcm.NonceSize()
+	plaintext, err := gcm.Open(nil, ciphertext[:n], ciphertext[n:], nil)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	return plaintext
+}
+
+func TestEncryptedUpload_UnsignedTx(t *testing.T) {
+	s, ipfs := newUploadServer(t)
+	attesterKey, _ := crypto.GenerateKey()
+	alice, _ := crypto.GenerateKey()
+	bob, _ := crypto.GenerateKey()
+	plaintext := []byte("confidential: series A term sheet")
+
+	rec := postUpload(t, s, secp256k1Address(t, attesterKey), map[string][]string{
+		"schema_uid":    {"0xschema"},
+		"recipient_key": {hex.EncodeToString(crypto.CompressPubkey(&alice.PublicKey)), base64.StdEncoding.EncodeToString(crypto.FromECDSAPub(&bob.PublicKey))},
+		"revocable":     {"false"},
+	}, plaintext)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var out struct {
+		IPFSCID           string            `json:"ipfs_cid"`
+		EncryptedDataHash string            `json:"encrypted_data_hash"`
+		Recipients        []string          `json:"recipients"`
+		EncryptedKeys     map[string]string `json:"encrypted_keys"`
+		UnsignedTx        struct {
+			Body struct {
+				Messages []map[string]any `json:"messages"`
+			} `json:"body"`
+		} `json:"unsigned_tx"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	// The pinned content is ciphertext whose hash is the anchored hash
+	ciphertext, ok := ipfs.pinned[out.IPFSCID]
+	if !ok {
+		t.Fatalf("cid %q was not pinned", out.IPFSCID)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Error("plaintext pinned to IPFS")
+	}
+	if sum := sha256.Sum256(ciphertext); hex.EncodeToString(sum[:]) != out.EncryptedDataHash {
+		t.Errorf("encrypted_data_hash does not match the pinned ciphertext")
+	}
+
+	// Each recipient can unwrap the key and read the document
+	for _, key := range []*ecdsa.PrivateKey{alice, bob} {
+		addr := secp256k1Address(t, key)
+		if got := decryptUpload(t, key, out.EncryptedKeys[addr], ciphertext); !bytes.Equal(got, plaintext) {
+			t.Errorf("%s decrypted %q", addr, got)
+		}
+	}
+
+	msgs := out.UnsignedTx.Body.Messages
+	if len(msgs) != 1 {
+		t.Fatalf("unsigned tx messages = %v", msgs)
+	}
+	msg := msgs[0]
+	if msg["@type"] != "/cert.attestation.v1.MsgCreateEncryptedAttestation" || msg["attester"] != secp256k1Address(t, attesterKey) ||
+		msg["ipfs_cid"] != out.IPFSCID || msg["revocable"] != false || len(msg["recipients"].([]any)) != 2 {
+		t.Errorf("unsigned msg = %v", msg)
+	}
+}
+
+func TestEncryptedUpload_AnchorsWithAuthorization(t *testing.T) {
+	s, ipfs := newUploadServer(t)
+	attesterKey, _ := crypto.GenerateKey()
+	recipientKey, _ := crypto.GenerateKey()
+	attester := secp256k1Address(t, attesterKey)
+	recipient := secp256k1Address(t, recipientKey)
+	plaintext := []byte("board minutes 2026-03")
+
+	var txArgs []string
+	orig := runCertdTx
+	t.Cleanup(func() { runCertdTx = orig })
+	runCertdTx = func(_ context.Context, args ...string) ([]byte, error) {
+		txArgs = args
+		return []byte(`{"txhash":"ABC123","code":0,"logs":[{"events":[{"type":"encrypted_attestation_created",
+			"attributes":[{"key":"attestation_uid","value":"0xenc1"}]}]}]}`), nil
+	}
+
+	plainHash := sha256.Sum256(plaintext)
+	message := encryptedUploadAuthorizationMessage("0xschema", hex.EncodeToString(plainHash[:]), []string{recipient})
+	sig, err := crypto.Sign(adr036SignDocHash(attester, []byte(message)), attesterKey)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	auth, _ := json.Marshal(uploadAuthorization{
+		Signature: base64.StdEncoding.EncodeToString(sig[:64]),
+		PubKey:    base64.StdEncoding.EncodeToString(crypto.CompressPubkey(&attesterKey.PublicKey)),
+	})
+	fields := map[string][]string{
+		"schema_uid":    {"0xschema"},
+		"recipient_key": {hex.EncodeToString(crypto.CompressPubkey(&recipientKey.PublicKey))},
+		"authorization": {string(auth)},
+	}
+
+	rec := postUpload(t, s, attester, fields, plaintext)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var out map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &out)
+	if out["uid"] != "0xenc1" || out["tx_hash"] != "ABC123" {
+		t.Errorf("anchor response = %v", out)
+	}
+
+	cid := out["ipfs_cid"].(string)
+	if !containsArgs(txArgs, "attestation", "create-encrypted", "0xschema", cid, out["encrypted_data_hash"].(string), recipient) {
+		t.Errorf("tx args = %v", txArgs)
+	}
+	var keys map[string]string
+	if err := json.Unmarshal([]byte(txArgs[7]), &keys); err != nil {
+		t.Fatalf("keys argument %q: %v", txArgs[7], err)
+	}
+	if got := decryptUpload(t, recipientKey, keys[recipient], ipfs.pinned[cid]); !bytes.Equal(got, plaintext) {
+		t.Errorf("anchored key decrypts to %q", got)
+	}
+
+	// An authorization for a different document is rejected before anything is pinned
+	pinnedBefore := len(ipfs.pinned)
+	if rec := postUpload(t, s, attester, fields, []byte("other document")); rec.Code != http.StatusUnauthorized {
+		t.Errorf("mismatched authorization: status %d", rec.Code)
+	}
+	// And so is one signed by someone other than the attester
+	other, _ := crypto.GenerateKey()
+	if rec := postUpload(t, s, secp256k1Address(t, other), fields, plaintext); rec.Code != http.StatusUnauthorized {
+		t.Errorf("foreign authorization: status %d", rec.Code)
+	}
+	if len(ipfs.pinned) != pinnedBefore {
+		t.Error("rejected uploads were pinned")
+	}
+}
+
+func TestEncryptedUpload_Validation(t *testing.T) {
+	s, ipfs := newUploadServer(t)
+	s.config.EncryptedUploads.MaxFileBytes = 16
+	attesterKey, _ := crypto.GenerateKey()
+	attester := secp256k1Address(t, attesterKey)
+	rcpt := hex.EncodeToString(crypto.CompressPubkey(&attesterKey.PublicKey))
+
+	tests := []struct {
+		name   string
+		fields map[string][]string
+		file   []byte
+		want   int
+	}{
+		{"missing schema", map[string][]string{"recipient_key": {rcpt}}, []byte("doc"), http.StatusBadRequest},
+		{"missing recipients", map[string][]string{"schema_uid": {"0xs"}}, []byte("doc"), http.StatusBadRequest},
+		{"bad recipient key", map[string][]string{"schema_uid": {"0xs"}, "recipient_key": {"abcd"}}, []byte("doc"), http.StatusBadRequest},
+		{"duplicate recipient", map[string][]string{"schema_uid": {"0xs"}, "recipient_key": {rcpt, rcpt}}, []byte("doc"), http.StatusBadRequest},
+		{"missing file", map[string][]string{"schema_uid": {"0xs"}, "recipient_key": {rcpt}}, nil, http.StatusBadRequest},
+		{"file too large", map[string][]string{"schema_uid": {"0xs"}, "recipient_key": {rcpt}}, []byte(strings.Repeat("x", 17)), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := postUpload(t, s, attester, tt.fields, tt.file); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+
+	ipfs.err = errors.New("ipfs down")
+	if rec := postUpload(t, s, attester, map[string][]string{"schema_uid": {"0xs"}, "recipient_key": {rcpt}}, []byte("doc")); rec.Code != http.StatusBadGateway {
+		t.Errorf("pin failure: status %d", rec.Code)
+	}
+
+	s.ipfs = nil
+	if rec := postUpload(t, s, attester, map[string][]string{"schema_uid": {"0xs"}, "recipient_key": {rcpt}}, []byte("doc")); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("disabled uploads: status %d", rec.Code)
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// Endorsement is a third-party countersignature of an attestation together
+// with what a verifier needs to weigh it
+type Endorsement struct {
+	UID        string            `json:"uid"`
+	Endorser   string            `json:"endorser"`
+	Name       string            `json:"name,omitempty"`
+	Time       string            `json:"time"`
+	Revoked    bool              `json:"revoked"`
+	Reputation *IssuerReputation `json:"reputation,omitempty"`
+}
+
+// handleGetEndorsements returns the endorsements of an attestation with each
+// endorser's profile name and issuer reputation
+// GET /api/v1/attestations/{uid}/endorsements
+func (s *Server) handleGetEndorsements(w http.ResponseWriter, r *http.Request) {
+	uid := mux.Vars(r)["uid"]
+	if uid == "" {
+		s.respondError(w, http.StatusBadRequest, "uid is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	items, err := s.queryAttestationList("endorsements", uid)
+	if err != nil {
+		s.logger.Warn("failed to query endorsements", zap.String("uid", uid), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query endorsements")
+		return
+	}
+
+	endorsements := make([]Endorsement, 0, len(items))
+	reputations := make(map[string]*IssuerReputation)
+	active := 0
+	for _, item := range items {
+		e := Endorsement{}
+		e.UID, _ = item["uid"].(string)
+		e.Endorser, _ = item["issuer"].(string)
+		t, _ := item["time"].(string)
+		e.Time = normalizeTimestamp(t)
+		e.Revoked, _ = item["revoked"].(bool)
+		if !e.Revoked {
+			active++
+		}
+
+		if e.Endorser != "" {
+			rep, seen := reputations[e.Endorser]
+			if !seen {
+				if bech32Addr, err := toBech32Address(e.Endorser); err == nil {
+					if got, err := s.issuerReputation(ctx, e.Endorser, bech32Addr); err == nil {
+						rep = &got
+					} else {
+						s.logger.Warn("failed to compute endorser reputation", zap.String("endorser", e.Endorser), zap.Error(err))
+					}
+				}
+				reputations[e.Endorser] = rep
+			}
+			e.Reputation = rep
+
+			if s.db != nil {
+				if prof, err := s.db.GetProfile(ctx, strings.ToLower(e.Endorser)); err == nil && prof != nil {
+					e.Name = prof.Name
+				}
+			}
+		}
+		endorsements = append(endorsements, e)
+	}
+
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"uid":          uid,
+		"endorsements": endorsements,
+		"count":        len(endorsements),
+		"active_count": active,
+	})
+}
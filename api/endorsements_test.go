@@ -0,0 +1,54 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestHandleGetEndorsements(t *testing.T) {
+	const accreditor = "cert1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+	const ministry = "cert1qyqszqgpqyqszqgpqyqszqgpqyqszqgpjnp7du"
+
+	orig := runCertdCLI
+	t.Cleanup(func() { runCertdCLI = orig })
+	runCertdCLI = func(args ...string) ([]byte, error) {
+		switch {
+		case len(args) > 3 && args[2] == "endorsements":
+			return []byte(`{"attestations":[
+				{"uid":"0xe1","attester":"` + accreditor + `","time":"2025-01-01T00:00:00Z","attestation_type":"endorsement","revocation_time":"0001-01-01T00:00:00Z"},
+				{"uid":"0xe2","attester":"` + ministry + `","time":"2025-02-01T00:00:00Z","attestation_type":"endorsement","revocation_time":"2025-03-01T00:00:00Z"}
+			]}`), nil
+		default:
+			return []byte(`{"attestations":[]}`), nil
+		}
+	}
+
+	issued := func(uid string, day int) attestationtypes.Attestation {
+		return attestationtypes.Attestation{UID: uid, Time: time.Date(2022, 1, day, 0, 0, 0, 0, time.UTC), AttestationType: "public"}
+	}
+	stub := &chainGRPCStub{issued: map[string][]attestationtypes.Attestation{
+		accreditor: {issued("0xa1", 1), issued("0xa2", 2)},
+	}}
+	s := newChainGRPCTestServer(t, stub, func(config *Config) {
+		config.Clock = &fakeClock{now: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}
+	})
+	out := getJSON(t, s, "/api/v1/attestations/0xdiploma/endorsements")
+
+	if out["count"].(float64) != 2 || out["active_count"].(float64) != 1 {
+		t.Fatalf("count = %v active = %v, want 2 and 1", out["count"], out["active_count"])
+	}
+	list := out["endorsements"].([]any)
+	first := list[0].(map[string]any)
+	if first["endorser"] != accreditor || first["revoked"] != false {
+		t.Errorf("first endorsement = %v", first)
+	}
+	rep, ok := first["reputation"].(map[string]any)
+	if !ok || rep["total_issued"].(float64) != 2 {
+		t.Errorf("endorser reputation = %v, want 2 attestations issued", first["reputation"])
+	}
+	if second := list[1].(map[string]any); second["revoked"] != true {
+		t.Errorf("second endorsement = %v, want revoked", second)
+	}
+}
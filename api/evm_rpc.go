@@ -0,0 +1,247 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+// EVM JSON-RPC compatibility
+// POST /rpc answers the read-only subset of the Ethereum JSON-RPC API that
+// wallets and quest platforms use to inspect an address, so CERT can be queried
+// like the other SupportedChains. A 0x address is the cert1 account with the
+// same bytes, and balances are the native denom scaled to 18 decimals (wei).
+
+// JSON-RPC 2.0 error codes
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+	jsonRPCServerError    = -32000
+)
+
+const (
+	// evmDecimals is the precision EVM tooling assumes for the native token
+	evmDecimals = 18
+
+	// maxEVMRPCBatch caps the calls in one batch request
+	maxEVMRPCBatch = 50
+)
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  string          `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *jsonRPCError) Error() string { return e.Message }
+
+func jsonRPCFailure(id json.RawMessage, code int, message string) jsonRPCResponse {
+	return jsonRPCResponse{JSONRPC: "2.0", ID: id, Error: &jsonRPCError{Code: code, Message: message}}
+}
+
+// handleEVMRPC serves single and batch JSON-RPC calls. Failures are reported
+// in the JSON-RPC error object with a 200, as EVM clients expect.
+// POST /rpc
+func (s *Server) handleEVMRPC(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxJSONBodyBytes))
+	if err != nil {
+		s.respondJSON(w, http.StatusOK, jsonRPCFailure(nil, jsonRPCParseError, "failed to read request body"))
+		return
+	}
+	body = bytes.TrimSpace(body)
+	if !json.Valid(body) {
+		s.respondJSON(w, http.StatusOK, jsonRPCFailure(nil, jsonRPCParseError, "parse error"))
+		return
+	}
+
+	if body[0] != '[' {
+		s.respondJSON(w, http.StatusOK, s.serveEVMRPC(r.Context(), body))
+		return
+	}
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil || len(batch) == 0 {
+		s.respondJSON(w, http.StatusOK, jsonRPCFailure(nil, jsonRPCInvalidRequest, "empty batch"))
+		return
+	}
+	if len(batch) > maxEVMRPCBatch {
+		s.respondJSON(w, http.StatusOK, jsonRPCFailure(nil, jsonRPCInvalidRequest, fmt.Sprintf("batch exceeds %d calls", maxEVMRPCBatch)))
+		return
+	}
+	responses := make([]jsonRPCResponse, len(batch))
+	for i, raw := range batch {
+		responses[i] = s.serveEVMRPC(r.Context(), raw)
+	}
+	s.respondJSON(w, http.StatusOK, responses)
+}
+
+// serveEVMRPC answers one JSON-RPC call
+func (s *Server) serveEVMRPC(ctx context.Context, raw json.RawMessage) jsonRPCResponse {
+	var req jsonRPCRequest
+	if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+		return jsonRPCFailure(req.ID, jsonRPCInvalidRequest, "invalid request")
+	}
+
+	var params []json.RawMessage
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return jsonRPCFailure(req.ID, jsonRPCInvalidParams, "params must be an array")
+		}
+	}
+
+	result, err := s.callEVMRPC(ctx, req.Method, params)
+	if err != nil {
+		var rpcErr *jsonRPCError
+		if !errors.As(err, &rpcErr) {
+			s.logger.Warn("EVM RPC chain query failed", zap.String("method", req.Method), zap.Error(err))
+			rpcErr = &jsonRPCError{Code: jsonRPCServerError, Message: "chain query failed"}
+		}
+		return jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// callEVMRPC runs method and returns its hex-encoded result
+func (s *Server) callEVMRPC(ctx context.Context, method string, params []json.RawMessage) (string, error) {
+	switch method {
+	case "eth_chainId":
+		return hexutil.EncodeUint64(uint64(SupportedChains["cert"].ChainID)), nil
+
+	case "eth_blockNumber":
+		height, err := s.chain.LatestHeight(ctx)
+		if err != nil {
+			return "", err
+		}
+		return hexutil.EncodeUint64(uint64(height)), nil
+
+	case "eth_getBalance":
+		address, height, err := evmAccountParams(params)
+		if err != nil {
+			return "", err
+		}
+		var balances BankBalancesResponse
+		err = s.queryEVMState(ctx, height, func(ctx context.Context, height int64) error {
+			var err error
+			balances, err = s.chain.Balances(ctx, address, height)
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+		wei, err := s.weiBalance(balances)
+		if err != nil {
+			return "", err
+		}
+		return hexutil.EncodeBig(wei), nil
+
+	case "eth_getTransactionCount":
+		address, height, err := evmAccountParams(params)
+		if err != nil {
+			return "", err
+		}
+		var sequence uint64
+		err = s.queryEVMState(ctx, height, func(ctx context.Context, height int64) error {
+			var err error
+			sequence, err = s.chain.AccountSequence(ctx, address, height)
+			return err
+		})
+		// An account the chain has never seen has sent nothing
+		if err != nil && !errors.Is(err, ErrChainNotFound) {
+			return "", err
+		}
+		return hexutil.EncodeUint64(sequence), nil
+	}
+	return "", &jsonRPCError{Code: jsonRPCMethodNotFound, Message: fmt.Sprintf("method %s is not supported", method)}
+}
+
+// queryEVMState runs query at height, or at the latest state when height is 0
+func (s *Server) queryEVMState(ctx context.Context, height int64, query func(ctx context.Context, height int64) error) error {
+	if height > 0 {
+		return query(ctx, height)
+	}
+	return retryAtLaggedHeight(ctx, s.chain.LatestHeight, query)
+}
+
+// evmAccountParams decodes the [address, block] params of eth_getBalance and
+// eth_getTransactionCount into a cert1 address and a height (0 for latest)
+func evmAccountParams(params []json.RawMessage) (string, int64, error) {
+	if len(params) == 0 || len(params) > 2 {
+		return "", 0, &jsonRPCError{Code: jsonRPCInvalidParams, Message: "expected [address, block] params"}
+	}
+	var hexAddr string
+	if err := json.Unmarshal(params[0], &hexAddr); err != nil || !strings.HasPrefix(hexAddr, "0x") || !common.IsHexAddress(hexAddr) {
+		return "", 0, &jsonRPCError{Code: jsonRPCInvalidParams, Message: "address must be a 0x-prefixed 20-byte hex address"}
+	}
+	address, err := toBech32Address(hexAddr)
+	if err != nil {
+		return "", 0, &jsonRPCError{Code: jsonRPCInvalidParams, Message: err.Error()}
+	}
+
+	if len(params) == 1 {
+		return address, 0, nil
+	}
+	height, err := evmBlockHeight(params[1])
+	if err != nil {
+		return "", 0, err
+	}
+	return address, height, nil
+}
+
+// evmBlockHeight resolves a block parameter: latest, pending, safe and
+// finalized read the latest state, and a hex quantity pins that height
+func evmBlockHeight(raw json.RawMessage) (int64, error) {
+	var tag string
+	if err := json.Unmarshal(raw, &tag); err != nil {
+		return 0, &jsonRPCError{Code: jsonRPCInvalidParams, Message: "block must be a tag or hex block number"}
+	}
+	switch tag {
+	case "latest", "pending", "safe", "finalized":
+		return 0, nil
+	}
+	height, err := hexutil.DecodeUint64(tag)
+	if err != nil || height == 0 || height > math.MaxInt64 {
+		return 0, &jsonRPCError{Code: jsonRPCInvalidParams, Message: fmt.Sprintf("unsupported block %q", tag)}
+	}
+	return int64(height), nil
+}
+
+// weiBalance is the native-denom balance scaled to evmDecimals
+func (s *Server) weiBalance(balances BankBalancesResponse) (*big.Int, error) {
+	for _, coin := range balances.Balances {
+		if coin.Denom != s.denom.Base {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(coin.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s balance %q", coin.Denom, coin.Amount)
+		}
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(evmDecimals-s.denom.Exponent)), nil)
+		return amount.Mul(amount, scale), nil
+	}
+	return new(big.Int), nil
+}
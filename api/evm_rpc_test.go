@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	evmRPCAddr      = "0x5aeda56215b167893e80b4fe645ba6d5bab767de"
+	evmRPCEmptyAddr = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed" // checksummed
+)
+
+// callRPC POSTs body to /rpc and decodes the JSON-RPC response into out
+func callRPC(t *testing.T, s *Server, body string, out any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, newJSONRequest("POST", "/rpc", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /rpc = %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+		t.Fatalf("decode response %s: %v", rec.Body.String(), err)
+	}
+}
+
+func newEVMRPCTestServer(t *testing.T) (*Server, *mockChainClient) {
+	t.Helper()
+	addr := mustBech32(t, evmRPCAddr)
+	chain := &mockChainClient{
+		height: 4_821,
+		balances: map[string]BankBalancesResponse{
+			addr:                           {Balances: []Coin{{Denom: "stake", Amount: "9"}, {Denom: "ucert", Amount: "1234567"}}},
+			mustBech32(t, evmRPCEmptyAddr): {},
+		},
+		sequences: map[string]uint64{addr: 26},
+	}
+	return newMockChainServer(chain), chain
+}
+
+func TestEVMRPC_Methods(t *testing.T) {
+	s, _ := newEVMRPCTestServer(t)
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"chain id", `{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`, "0x12eef"},
+		{"block number", `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`, "0x12d5"},
+		// 1.234567 CERT scaled to 18 decimals
+		{"balance", `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["` + evmRPCAddr + `","latest"]}`, "0x112210253e6b7000"},
+		{"checksummed address without balance", `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["` + evmRPCEmptyAddr + `","latest"]}`, "0x0"},
+		{"transaction count", `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionCount","params":["` + evmRPCAddr + `","pending"]}`, "0x1a"},
+		{"unknown account has sent nothing", `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionCount","params":["0x0000000000000000000000000000000000000001"]}`, "0x0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res jsonRPCResponse
+			callRPC(t, s, tt.body, &res)
+			if res.Error != nil {
+				t.Fatalf("error = %+v", res.Error)
+			}
+			if res.JSONRPC != "2.0" || string(res.ID) != "1" || res.Result != tt.want {
+				t.Errorf("response = %+v, want result %s", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestEVMRPC_BlockHeight(t *testing.T) {
+	s, chain := newEVMRPCTestServer(t)
+
+	var res jsonRPCResponse
+	callRPC(t, s, `{"jsonrpc":"2.0","id":"a","method":"eth_getBalance","params":["`+evmRPCAddr+`","0x12c0"]}`, &res)
+	if res.Result != "0x112210253e6b7000" || string(res.ID) != `"a"` {
+		t.Fatalf("response = %+v", res)
+	}
+	if got := chain.heights[len(chain.heights)-1]; got != 4_800 {
+		t.Errorf("queried height %d, want 4800", got)
+	}
+
+	// The latest state falls back to a lagged height when it is not yet queryable
+	chain.latestVersionMissing = true
+	callRPC(t, s, `{"jsonrpc":"2.0","id":2,"method":"eth_getTransactionCount","params":["`+evmRPCAddr+`","latest"]}`, &res)
+	if res.Result != "0x1a" || chain.heights[len(chain.heights)-1] <= 0 {
+		t.Errorf("response = %+v at heights %v", res, chain.heights)
+	}
+}
+
+func TestEVMRPC_Errors(t *testing.T) {
+	s, chain := newEVMRPCTestServer(t)
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"malformed JSON", `{"jsonrpc":`, jsonRPCParseError},
+		{"missing version", `{"id":1,"method":"eth_chainId"}`, jsonRPCInvalidRequest},
+		{"unknown method", `{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["0x00"]}`, jsonRPCMethodNotFound},
+		{"bech32 address", `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["cert1v968getnw3jhyh6lta047h6lta047h6l0ytjja","latest"]}`, jsonRPCInvalidParams},
+		{"short address", `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x5aeda5","latest"]}`, jsonRPCInvalidParams},
+		{"unsupported block", `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["` + evmRPCAddr + `","earliest"]}`, jsonRPCInvalidParams},
+		{"object params", `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":{"address":"` + evmRPCAddr + `"}}`, jsonRPCInvalidParams},
+		{"chain failure", `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x0000000000000000000000000000000000000002"]}`, jsonRPCServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res jsonRPCResponse
+			callRPC(t, s, tt.body, &res)
+			if res.Error == nil || res.Error.Code != tt.code || res.Result != "" {
+				t.Errorf("response = %+v, want error code %d", res, tt.code)
+			}
+		})
+	}
+
+	chain.height = 0
+	var res jsonRPCResponse
+	callRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`, &res)
+	if res.Error == nil || res.Error.Code != jsonRPCServerError {
+		t.Errorf("block number without a node = %+v", res)
+	}
+}
+
+func TestEVMRPC_Batch(t *testing.T) {
+	s, _ := newEVMRPCTestServer(t)
+
+	var res []jsonRPCResponse
+	callRPC(t, s, `[
+		{"jsonrpc":"2.0","id":1,"method":"eth_chainId"},
+		{"jsonrpc":"2.0","id":2,"method":"eth_getTransactionCount","params":["`+evmRPCAddr+`","latest"]},
+		{"jsonrpc":"2.0","id":3,"method":"web3_clientVersion"}
+	]`, &res)
+	if len(res) != 3 {
+		t.Fatalf("batch = %+v", res)
+	}
+	if res[0].Result != "0x12eef" || res[1].Result != "0x1a" || string(res[1].ID) != "2" {
+		t.Errorf("batch results = %+v", res)
+	}
+	if res[2].Error == nil || res[2].Error.Code != jsonRPCMethodNotFound {
+		t.Errorf("unsupported call in batch = %+v", res[2])
+	}
+
+	var empty jsonRPCResponse
+	callRPC(t, s, `[]`, &empty)
+	if empty.Error == nil || empty.Error.Code != jsonRPCInvalidRequest {
+		t.Errorf("empty batch = %+v", empty)
+	}
+}
+
+// TestEVMRPC_CrossChainClient checks the cross-chain client reads CERT through /rpc
+// as it reads any other chain in SupportedChains
+func TestEVMRPC_CrossChainClient(t *testing.T) {
+	s, _ := newEVMRPCTestServer(t)
+	node := httptest.NewServer(s.router)
+	defer node.Close()
+
+	balance, err := s.ethGetBalance(context.Background(), node.URL+"/rpc", evmRPCAddr)
+	if err != nil || balance != "1.2346" {
+		t.Errorf("ethGetBalance = %q, %v; want 1.2346", balance, err)
+	}
+	count, err := s.ethGetTransactionCount(context.Background(), node.URL+"/rpc", evmRPCAddr)
+	if err != nil || count != 26 {
+		t.Errorf("ethGetTransactionCount = %d, %v; want 26", count, err)
+	}
+}
+
+func mustBech32(t *testing.T, hexAddr string) string {
+	t.Helper()
+	addr, err := toBech32Address(hexAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
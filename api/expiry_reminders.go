@@ -0,0 +1,273 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// Attestation expiry reminders
+// The API periodically asks the chain for attestations expiring within the
+// longest configured lead time and POSTs a signed reminder to the webhooks
+// owned by each attestation's recipient and issuer as it passes a milestone.
+
+const (
+	// expiryReminderEvent names the delivered payload
+	expiryReminderEvent = "attestation.expiring"
+
+	// expiryReminderPageSize is the --limit of each expiring-attestations query
+	expiryReminderPageSize = 100
+
+	// maxExpiryReminderPages bounds the work of one poll
+	maxExpiryReminderPages = 50
+)
+
+// Roles an expiry reminder is addressed to
+const (
+	ExpiryReminderRoleRecipient = "recipient"
+	ExpiryReminderRoleIssuer    = "issuer"
+)
+
+// ExpiryReminderConfig schedules reminders ahead of attestation expiry
+type ExpiryReminderConfig struct {
+	// PollInterval is how often upcoming expirations are checked (0 disables reminders)
+	PollInterval time.Duration
+
+	// Milestones are the lead times before expiry at which a reminder is sent
+	Milestones []time.Duration
+}
+
+// DefaultExpiryReminderConfig returns reminders 30, 7 and 1 days before expiry
+func DefaultExpiryReminderConfig() ExpiryReminderConfig {
+	return ExpiryReminderConfig{
+		PollInterval: time.Hour,
+		Milestones:   []time.Duration{30 * 24 * time.Hour, 7 * 24 * time.Hour, 24 * time.Hour},
+	}
+}
+
+// milestones returns the positive milestones, shortest first and without duplicates
+func (c ExpiryReminderConfig) milestones() []time.Duration {
+	out := make([]time.Duration, 0, len(c.Milestones))
+	for _, m := range c.Milestones {
+		if m > 0 {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	deduped := out[:0]
+	for i, m := range out {
+		if i == 0 || m != out[i-1] {
+			deduped = append(deduped, m)
+		}
+	}
+	return deduped
+}
+
+// ExpiryReminderPayload is the JSON body delivered to subscribers
+type ExpiryReminderPayload struct {
+	Event            string `json:"event"`
+	WebhookID        string `json:"webhook_id"`
+	AttestationUID   string `json:"attestation_uid"`
+	SchemaUID        string `json:"schema_uid"`
+	Attester         string `json:"attester"`
+	Recipient        string `json:"recipient,omitempty"`
+	Role             string `json:"role"`
+	ExpiresAt        int64  `json:"expires_at"`
+	MilestoneSeconds int64  `json:"milestone_seconds"`
+	Timestamp        int64  `json:"timestamp"`
+}
+
+// expiringAttestation is an attestation returned by the chain's expiring query
+type expiringAttestation struct {
+	UID            string `json:"uid"`
+	SchemaUID      string `json:"schema_uid"`
+	Attester       string `json:"attester"`
+	Recipient      string `json:"recipient"`
+	ExpirationTime string `json:"expiration_time"`
+}
+
+// expiryReminderStore records which milestone reminders have been sent
+type expiryReminderStore interface {
+	// Claim marks a reminder as sent, reporting false if it already was
+	Claim(ctx context.Context, attestationUID string, milestone time.Duration) (bool, error)
+}
+
+// dbExpiryReminderStore persists sent reminders in PostgreSQL
+type dbExpiryReminderStore struct {
+	db *database.DB
+}
+
+func (s dbExpiryReminderStore) Claim(ctx context.Context, attestationUID string, milestone time.Duration) (bool, error) {
+	return s.db.ClaimExpiryReminder(ctx, attestationUID, milestone)
+}
+
+type expiryReminderKey struct {
+	uid       string
+	milestone time.Duration
+}
+
+// memoryExpiryReminderStore keeps sent reminders in memory (used when no database is configured)
+type memoryExpiryReminderStore struct {
+	mu   sync.Mutex
+	sent map[expiryReminderKey]bool
+}
+
+func newMemoryExpiryReminderStore() *memoryExpiryReminderStore {
+	return &memoryExpiryReminderStore{sent: make(map[expiryReminderKey]bool)}
+}
+
+func (s *memoryExpiryReminderStore) Claim(_ context.Context, attestationUID string, milestone time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := expiryReminderKey{uid: attestationUID, milestone: milestone}
+	if s.sent[key] {
+		return false, nil
+	}
+	s.sent[key] = true
+	return true, nil
+}
+
+// queryExpiringAttestations returns up to expiryReminderPageSize unrevoked
+// attestations expiring between from and to (unix seconds), soonest first
+func (s *Server) queryExpiringAttestations(ctx context.Context, from, to int64) ([]expiringAttestation, error) {
+	var res struct {
+		Attestations []expiringAttestation `json:"attestations"`
+	}
+	args := []string{"attestation", "expiring",
+		strconv.FormatInt(from, 10), strconv.FormatInt(to, 10),
+		"--limit", strconv.Itoa(expiryReminderPageSize)}
+	err := retryAtLaggedHeight(ctx, s.chain.LatestHeight, func(ctx context.Context, height int64) error {
+		res.Attestations = nil
+		return s.chain.Query(ctx, &res, height, args...)
+	})
+	return res.Attestations, err
+}
+
+// pollExpiringAttestations sends the reminders that are due at the current time
+func (s *Server) pollExpiringAttestations(ctx context.Context) error {
+	milestones := s.config.ExpiryReminders.milestones()
+	if len(milestones) == 0 {
+		return nil
+	}
+	now := s.clock.Now()
+	from, to := now.Unix(), now.Add(milestones[len(milestones)-1]).Unix()
+
+	for page := 0; page < maxExpiryReminderPages && from <= to; page++ {
+		attestations, err := s.queryExpiringAttestations(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		for _, a := range attestations {
+			s.remindExpiringAttestation(ctx, a, now, milestones)
+		}
+		if len(attestations) < expiryReminderPageSize {
+			return nil
+		}
+		// Resume at the last expiry second; entries already seen are deduplicated by the store
+		last := parseChainTime(attestations[len(attestations)-1].ExpirationTime).Unix()
+		if last <= from {
+			last = from + 1
+		}
+		from = last
+	}
+	return nil
+}
+
+// remindExpiringAttestation sends the reminder for the shortest milestone a,
+// expiring after now, has reached. Longer milestones it has also passed are
+// claimed without sending, so a reminder missed while the API was down (or
+// for an attestation issued close to expiry) does not arrive late.
+func (s *Server) remindExpiringAttestation(ctx context.Context, a expiringAttestation, now time.Time, milestones []time.Duration) {
+	expiresAt := parseChainTime(a.ExpirationTime)
+	remaining := expiresAt.Sub(now)
+	if expiresAt.IsZero() || remaining <= 0 {
+		return
+	}
+	due := sort.Search(len(milestones), func(i int) bool { return remaining <= milestones[i] })
+	if due == len(milestones) {
+		return
+	}
+
+	for _, m := range milestones[due+1:] {
+		if _, err := s.expiryReminders.Claim(ctx, a.UID, m); err != nil {
+			s.logger.Warn("failed to record expiry reminder", zap.String("uid", a.UID), zap.Error(err))
+		}
+	}
+	claimed, err := s.expiryReminders.Claim(ctx, a.UID, milestones[due])
+	if err != nil {
+		s.logger.Warn("failed to record expiry reminder", zap.String("uid", a.UID), zap.Error(err))
+		return
+	}
+	if claimed {
+		s.notifyExpiringAttestation(ctx, a, expiresAt, milestones[due])
+	}
+}
+
+// notifyExpiringAttestation delivers a reminder to every webhook owned by the
+// attestation's recipient or issuer. Delivery failures are logged, not retried.
+func (s *Server) notifyExpiringAttestation(ctx context.Context, a expiringAttestation, expiresAt time.Time, milestone time.Duration) {
+	hooks, err := s.humanityWebhooks.List(ctx, "")
+	if err != nil {
+		s.logger.Warn("failed to list webhooks", zap.Error(err))
+		return
+	}
+
+	attester := strings.TrimSpace(a.Attester)
+	recipient := strings.TrimSpace(a.Recipient)
+	for _, hook := range hooks {
+		var role string
+		switch strings.TrimSpace(hook.OwnerAddress) {
+		case "":
+			continue
+		case recipient:
+			role = ExpiryReminderRoleRecipient
+		case attester:
+			role = ExpiryReminderRoleIssuer
+		default:
+			continue
+		}
+		payload := ExpiryReminderPayload{
+			Event:            expiryReminderEvent,
+			WebhookID:        hook.ID,
+			AttestationUID:   a.UID,
+			SchemaUID:        a.SchemaUID,
+			Attester:         attester,
+			Recipient:        recipient,
+			Role:             role,
+			ExpiresAt:        expiresAt.Unix(),
+			MilestoneSeconds: int64(milestone / time.Second),
+			Timestamp:        s.clock.Now().Unix(),
+		}
+		if err := s.deliverWebhook(ctx, hook, payload.Timestamp, payload); err != nil {
+			s.logger.Warn("expiry reminder delivery failed",
+				zap.String("webhook_id", hook.ID),
+				zap.String("uid", a.UID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// watchExpiringAttestations polls for due reminders until ctx is cancelled
+func (s *Server) watchExpiringAttestations(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.pollExpiringAttestations(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("expiry reminder poll failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+func TestExpiryRemindersFireOncePerMilestone(t *testing.T) {
+	const (
+		issuer    = "cert1qyqszqgpqyqszqgpqyqszqgpqyqszqgpjnp7du"
+		recipient = testBalanceAddr
+		day       = 24 * time.Hour
+	)
+
+	var mu sync.Mutex
+	deliveries := map[string][]webhookDelivery{}
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		deliveries[r.URL.Path] = append(deliveries[r.URL.Path], webhookDelivery{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+
+	t0 := time.Unix(1_700_000_000, 0).UTC()
+	expiring := []expiringAttestation{
+		{UID: "long-lived", SchemaUID: "schema", Attester: issuer, Recipient: recipient, ExpirationTime: t0.Add(30*day + time.Hour).Format(time.RFC3339)},
+		{UID: "issued-late", SchemaUID: "schema", Attester: issuer, Recipient: recipient, ExpirationTime: t0.Add(3 * day).Format(time.RFC3339)},
+	}
+	var queries int
+	chain := &mockChainClient{query: func(out any, _ int64, args ...string) error {
+		if len(args) < 4 || args[1] != "expiring" {
+			t.Fatalf("unexpected query %v", args)
+		}
+		queries++
+		from, _ := strconv.ParseInt(args[2], 10, 64)
+		to, _ := strconv.ParseInt(args[3], 10, 64)
+		var res struct {
+			Attestations []expiringAttestation `json:"attestations"`
+		}
+		for _, a := range expiring {
+			if at := parseChainTime(a.ExpirationTime).Unix(); at >= from && at <= to {
+				res.Attestations = append(res.Attestations, a)
+			}
+		}
+		bz, _ := json.Marshal(res)
+		return json.Unmarshal(bz, out)
+	}}
+
+	clock := newFakeClock(t0)
+	config := DefaultConfig()
+	config.Clock = clock
+	config.Chain = chain
+	// Unordered and duplicated on purpose; the schedule is normalized
+	config.ExpiryReminders.Milestones = []time.Duration{day, 30 * day, 7 * day, day}
+	s := NewServer(config, zap.NewNop())
+
+	ctx := context.Background()
+	secrets := map[string]string{}
+	for _, owner := range []struct{ path, address string }{
+		{"/recipient", recipient},
+		{"/issuer", issuer},
+		{"/bystander", "cert1integrator"},
+	} {
+		hook := &database.HumanityWebhook{
+			ID:           "whk" + owner.path[1:],
+			OwnerAddress: owner.address,
+			URL:          receiver.URL + owner.path,
+			Threshold:    defaultHumanityWebhookThreshold,
+			Direction:    HumanityDirectionUp,
+			Secret:       generateUID(),
+		}
+		if err := s.humanityWebhooks.Create(ctx, hook); err != nil {
+			t.Fatal(err)
+		}
+		secrets[owner.path] = hook.Secret
+	}
+
+	// Poll hourly, twice per tick, from 30 days out until past both expirations
+	for now := t0; now.Before(t0.Add(31 * day)); now = now.Add(time.Hour) {
+		clock.Advance(now.Sub(clock.Now()))
+		for i := 0; i < 2; i++ {
+			if err := s.pollExpiringAttestations(ctx); err != nil {
+				t.Fatalf("poll at %s: %v", now, err)
+			}
+		}
+	}
+	if queries == 0 {
+		t.Fatal("chain was never queried")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := len(deliveries["/bystander"]); got != 0 {
+		t.Errorf("bystander deliveries = %d, want 0", got)
+	}
+
+	want := map[string][]int64{
+		"long-lived":  {int64(30 * day / time.Second), int64(7 * day / time.Second), int64(day / time.Second)},
+		"issued-late": {int64(7 * day / time.Second), int64(day / time.Second)}, // 30 day reminder already passed at issuance
+	}
+	for path, role := range map[string]string{"/recipient": ExpiryReminderRoleRecipient, "/issuer": ExpiryReminderRoleIssuer} {
+		got := map[string][]int64{}
+		for _, d := range deliveries[path] {
+			timestamp := d.header.Get("X-Cert-Timestamp")
+			if want := signHumanityWebhook(secrets[path], timestamp, d.body); d.header.Get("X-Cert-Signature") != want {
+				t.Errorf("%s signature = %q, want %q", path, d.header.Get("X-Cert-Signature"), want)
+			}
+			var payload ExpiryReminderPayload
+			if err := json.Unmarshal(d.body, &payload); err != nil {
+				t.Fatal(err)
+			}
+			if payload.Event != expiryReminderEvent || payload.Role != role || payload.WebhookID != "whk"+path[1:] ||
+				payload.Attester != issuer || payload.Recipient != recipient {
+				t.Errorf("%s payload = %+v", path, payload)
+			}
+			expiresAt := time.Unix(payload.ExpiresAt, 0)
+			lead := expiresAt.Sub(time.Unix(payload.Timestamp, 0))
+			if lead <= 0 || lead > time.Duration(payload.MilestoneSeconds)*time.Second {
+				t.Errorf("%s %s reminder sent %s before expiry", path, payload.AttestationUID, lead)
+			}
+			got[payload.AttestationUID] = append(got[payload.AttestationUID], payload.MilestoneSeconds)
+		}
+		for uid, milestones := range want {
+			if !slices.Equal(got[uid], milestones) {
+				t.Errorf("%s %s milestones = %v, want %v", path, uid, got[uid], milestones)
+			}
+		}
+	}
+}
+
+func TestExpiryReminderConfigMilestones(t *testing.T) {
+	config := ExpiryReminderConfig{Milestones: []time.Duration{time.Hour, 0, 48 * time.Hour, -time.Hour, time.Hour}}
+	got := config.milestones()
+	if len(got) != 2 || got[0] != time.Hour || got[1] != 48*time.Hour {
+		t.Errorf("milestones = %v, want [1h 48h]", got)
+	}
+
+	// No milestones disables reminders without querying the chain
+	cfg := DefaultConfig()
+	cfg.ExpiryReminders.Milestones = nil
+	cfg.Chain = &mockChainClient{query: func(any, int64, ...string) error {
+		t.Fatal("chain queried without milestones")
+		return nil
+	}}
+	s := NewServer(cfg, zap.NewNop())
+	if err := s.pollExpiringAttestations(context.Background()); err != nil {
+		t.Errorf("poll without milestones = %v", err)
+	}
+}
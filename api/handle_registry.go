@@ -0,0 +1,359 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+	certidtypes "github.com/chaincertify/certd/x/certid/types"
+)
+
+// .cert handle registry
+//
+// An authenticated address claims one handle, which then resolves to it and
+// replaces the display handle on its identity. Handles are stored normalized:
+// lowercase and without the .cert suffix.
+
+const (
+	minHandleLength = 3
+	maxHandleLength = 32
+	handleSuffix    = ".cert"
+)
+
+// reservedHandles cannot be claimed; they name the network, its operators or
+// API routes, or could be mistaken for official accounts
+var reservedHandles = map[string]bool{
+	"admin": true, "administrator": true, "api": true, "cert": true, "certid": true,
+	"c3rt": true, "chaincert": true, "foundation": true, "handle": true, "help": true, "mod": true,
+	"moderator": true, "null": true, "official": true, "resolve": true, "root": true,
+	"security": true, "staff": true, "support": true, "system": true, "team": true,
+	"undefined": true, "validator": true, "www": true,
+}
+
+// normalizeHandle lowercases a handle and strips the .cert suffix
+func normalizeHandle(handle string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(handle)), handleSuffix)
+}
+
+// validateHandle checks a normalized handle, returning the reason it cannot be claimed
+func validateHandle(handle string) string {
+	if len(handle) < minHandleLength || len(handle) > maxHandleLength {
+		return "handle must be between 3 and 32 characters"
+	}
+	for _, c := range handle {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return "handle may only contain letters, digits and hyphens"
+		}
+	}
+	if handle[0] == '-' || handle[len(handle)-1] == '-' {
+		return "handle must not start or end with a hyphen"
+	}
+	if reservedHandles[handle] {
+		return "handle is reserved"
+	}
+	return ""
+}
+
+// handleStore persists handle registrations and their history; *database.DB implements it
+type handleStore interface {
+	ClaimHandle(ctx context.Context, reg *database.HandleRegistration) (bool, error)
+	TransferHandle(ctx context.Context, reg *database.HandleRegistration, from string) (bool, error)
+	ReleaseHandle(ctx context.Context, address string, at time.Time) (*database.HandleRegistration, error)
+	GetHandle(ctx context.Context, handle string) (*database.HandleRegistration, error)
+	GetHandleByAddress(ctx context.Context, address string) (*database.HandleRegistration, error)
+	GetHandleHistory(ctx context.Context, handle string) ([]database.HandleHistoryEntry, error)
+}
+
+// memoryHandleStore keeps handle registrations in memory (used when no database is configured)
+type memoryHandleStore struct {
+	mu        sync.Mutex
+	byHandle  map[string]database.HandleRegistration
+	byAddress map[string]string
+	history   []database.HandleHistoryEntry
+}
+
+func newMemoryHandleStore() *memoryHandleStore {
+	return &memoryHandleStore{
+		byHandle:  make(map[string]database.HandleRegistration),
+		byAddress: make(map[string]string),
+	}
+}
+
+func (m *memoryHandleStore) ClaimHandle(_ context.Context, reg *database.HandleRegistration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, taken := m.byHandle[reg.Handle]; taken {
+		return false, nil
+	}
+	if _, held := m.byAddress[reg.Address]; held {
+		return false, nil
+	}
+	m.byHandle[reg.Handle] = *reg
+	m.byAddress[reg.Address] = reg.Handle
+	m.record(reg.Handle, reg.Address, database.HandleEventClaimed, "", reg.CreatedAt)
+	return true, nil
+}
+
+func (m *memoryHandleStore) TransferHandle(_ context.Context, reg *database.HandleRegistration, from string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current, ok := m.byHandle[reg.Handle]; !ok || current.Address != from {
+		return false, nil
+	}
+	if _, held := m.byAddress[reg.Address]; held {
+		return false, nil
+	}
+	delete(m.byAddress, from)
+	m.byHandle[reg.Handle] = *reg
+	m.byAddress[reg.Address] = reg.Handle
+	m.record(reg.Handle, reg.Address, database.HandleEventTransferred, from, reg.CreatedAt)
+	return true, nil
+}
+
+func (m *memoryHandleStore) ReleaseHandle(_ context.Context, address string, at time.Time) (*database.HandleRegistration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	handle, ok := m.byAddress[address]
+	if !ok {
+		return nil, nil
+	}
+	reg := m.byHandle[handle]
+	delete(m.byAddress, address)
+	delete(m.byHandle, handle)
+	m.record(handle, address, database.HandleEventReleased, "", at)
+	return &reg, nil
+}
+
+func (m *memoryHandleStore) record(handle, address, event, from string, at time.Time) {
+	m.history = append(m.history, database.HandleHistoryEntry{
+		Handle:      handle,
+		Address:     address,
+		Event:       event,
+		FromAddress: from,
+		CreatedAt:   at,
+	})
+}
+
+func (m *memoryHandleStore) GetHandle(_ context.Context, handle string) (*database.HandleRegistration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	reg, ok := m.byHandle[handle]
+	if !ok {
+		return nil, nil
+	}
+	return &reg, nil
+}
+
+func (m *memoryHandleStore) GetHandleByAddress(_ context.Context, address string) (*database.HandleRegistration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	handle, ok := m.byAddress[address]
+	if !ok {
+		return nil, nil
+	}
+	reg := m.byHandle[handle]
+	return &reg, nil
+}
+
+func (m *memoryHandleStore) GetHandleHistory(_ context.Context, handle string) ([]database.HandleHistoryEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var entries []database.HandleHistoryEntry
+	for _, e := range m.history {
+		if e.Handle == handle {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// queryOnChainHandleOwner returns the address whose CertID profile holds handle
+// on-chain, or "" if no profile does
+func (s *Server) queryOnChainHandleOwner(handle string) (string, error) {
+	var res struct {
+		Profile struct {
+			Address string `json:"address"`
+		} `json:"profile"`
+	}
+	if err := s.execCertdQueryJSON(&res, "certid", "profile-by-handle", handle); err != nil {
+		if strings.Contains(err.Error(), certidtypes.ErrProfileNotFound.Error()) {
+			return "", nil
+		}
+		return "", err
+	}
+	return res.Profile.Address, nil
+}
+
+// HandleResponse is a claimed, released or resolved handle
+type HandleResponse struct {
+	Handle    string                `json:"handle"`
+	Address   string                `json:"address"`
+	Verified  bool                  `json:"verified"`
+	Profile   *database.UserProfile `json:"profile,omitempty"`
+	ClaimedAt Timestamp             `json:"claimed_at"`
+}
+
+func newHandleResponse(reg *database.HandleRegistration) HandleResponse {
+	return HandleResponse{
+		Handle:    reg.Handle + handleSuffix,
+		Address:   reg.Address,
+		Verified:  reg.Verified,
+		ClaimedAt: NewTimestamp(reg.CreatedAt),
+	}
+}
+
+// handleClaimHandle registers a .cert handle for the caller. A handle the
+// chain's CertID profiles assign to the caller is marked verified and is taken
+// over from an unverified holder; one they assign to another address cannot
+// be claimed.
+// POST /api/v1/identity/handle {"handle": "alice"}
+func (s *Server) handleClaimHandle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Handle string `json:"handle"`
+	}
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
+		return
+	}
+	handle := normalizeHandle(req.Handle)
+	if reason := validateHandle(handle); reason != "" {
+		s.respondError(w, http.StatusBadRequest, reason)
+		return
+	}
+
+	owner, err := toBech32Address(getAuthenticatedAddress(r))
+	if err != nil {
+		s.respondError(w, http.StatusUnauthorized, "invalid authenticated address")
+		return
+	}
+	existing, err := s.handles.GetHandleByAddress(r.Context(), owner)
+	if err != nil {
+		s.logger.Error("failed to look up handle", zap.String("address", owner), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to claim handle")
+		return
+	}
+	if existing != nil {
+		s.respondError(w, http.StatusConflict, "address already holds handle "+existing.Handle+handleSuffix)
+		return
+	}
+
+	// The chain being unreachable only costs the claim its verification
+	chainOwner, err := s.queryOnChainHandleOwner(handle)
+	if err != nil {
+		s.logger.Warn("failed to query on-chain handle", zap.String("handle", handle), zap.Error(err))
+	}
+	if chainOwner != "" && chainOwner != owner {
+		s.respondError(w, http.StatusConflict, "handle is registered on-chain to another address")
+		return
+	}
+
+	reg := &database.HandleRegistration{Handle: handle, Address: owner, Verified: chainOwner == owner, CreatedAt: s.clock.Now()}
+	claimed, err := s.handles.ClaimHandle(r.Context(), reg)
+	if err == nil && !claimed && reg.Verified {
+		var current *database.HandleRegistration
+		if current, err = s.handles.GetHandle(r.Context(), handle); err == nil && current != nil && !current.Verified {
+			claimed, err = s.handles.TransferHandle(r.Context(), reg, current.Address)
+		}
+	}
+	if err != nil {
+		s.logger.Error("failed to claim handle", zap.String("handle", handle), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to claim handle")
+		return
+	}
+	if !claimed {
+		s.respondError(w, http.StatusConflict, "handle is already taken")
+		return
+	}
+	s.respondJSON(w, http.StatusCreated, newHandleResponse(reg))
+}
+
+// handleReleaseHandle frees the caller's handle so anyone may claim it
+// POST /api/v1/identity/handle/release
+func (s *Server) handleReleaseHandle(w http.ResponseWriter, r *http.Request) {
+	owner, err := toBech32Address(getAuthenticatedAddress(r))
+	if err != nil {
+		s.respondError(w, http.StatusUnauthorized, "invalid authenticated address")
+		return
+	}
+	reg, err := s.handles.ReleaseHandle(r.Context(), owner, s.clock.Now())
+	if err != nil {
+		s.logger.Error("failed to release handle", zap.String("address", owner), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to release handle")
+		return
+	}
+	if reg == nil {
+		s.respondError(w, http.StatusNotFound, "address holds no handle")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, newHandleResponse(reg))
+}
+
+// handleGetAddressHandle returns the handle an address holds
+// GET /api/v1/identity/{address}/handle
+func (s *Server) handleGetAddressHandle(w http.ResponseWriter, r *http.Request) {
+	address, err := toBech32Address(mux.Vars(r)["address"])
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, "invalid address")
+		return
+	}
+	reg, err := s.handles.GetHandleByAddress(r.Context(), address)
+	if err != nil {
+		s.logger.Error("failed to look up handle", zap.String("address", address), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to look up handle")
+		return
+	}
+	if reg == nil {
+		s.respondError(w, http.StatusNotFound, "address holds no handle")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, newHandleResponse(reg))
+}
+
+// handleGetHandleHistory lists every claim, release and transfer of a handle, oldest first
+// GET /api/v1/identity/handle/{handle}/history
+func (s *Server) handleGetHandleHistory(w http.ResponseWriter, r *http.Request) {
+	handle := normalizeHandle(mux.Vars(r)["handle"])
+	entries, err := s.handles.GetHandleHistory(r.Context(), handle)
+	if err != nil {
+		s.logger.Error("failed to get handle history", zap.String("handle", handle), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get handle history")
+		return
+	}
+	history := make([]map[string]any, 0, len(entries))
+	for _, e := range entries {
+		entry := map[string]any{
+			"address":    e.Address,
+			"event":      e.Event,
+			"created_at": NewTimestamp(e.CreatedAt),
+		}
+		if e.FromAddress != "" {
+			entry["from_address"] = e.FromAddress
+		}
+		history = append(history, entry)
+	}
+	s.respondJSON(w, http.StatusOK, map[string]any{
+		"handle":  handle + handleSuffix,
+		"history": history,
+	})
+}
+
+// lookupHandle returns the registration held by address, or nil if it holds
+// none or the registry is unavailable
+func (s *Server) lookupHandle(ctx context.Context, address string) *database.HandleRegistration {
+	bech32Addr, err := toBech32Address(address)
+	if err != nil {
+		return nil
+	}
+	reg, err := s.handles.GetHandleByAddress(ctx, bech32Addr)
+	if err != nil {
+		s.logger.Warn("failed to look up handle", zap.String("address", bech32Addr), zap.Error(err))
+		return nil
+	}
+	return reg
+}
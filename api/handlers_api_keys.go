@@ -5,22 +5,21 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
-	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/chaincertify/certd/api/database"
 	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
 
 // API Key handlers
 
 type createAPIKeyRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Tier        string `json:"tier"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tier        string   `json:"tier"`   // Only "free"; paid tiers are granted by the Stripe webhook
+	Scopes      []string `json:"scopes"` // Defaults by tier when empty
 }
 
 type createAPIKeyResponse struct {
@@ -28,26 +27,50 @@ type createAPIKeyResponse struct {
 	APIKey *database.APIKeyNew  `json:"api_key"`
 }
 
+// apiTierLimits returns the daily and per-minute rate limits of a tier, and
+// false for an unknown tier
+func apiTierLimits(tier string) (daily, minute int, ok bool) {
+	switch tier {
+	case "free":
+		return 100, 2, true
+	case "developer":
+		return 10000, 100, true
+	case "enterprise":
+		return 1000000, 1000, true
+	}
+	return 0, 0, false
+}
+
 // handleCreateAPIKey creates a new API key for the authenticated user
 func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
-	address, ok := r.Context().Value("address").(string)
-	if !ok || address == "" {
+	address := getAuthenticatedAddress(r)
+	if address == "" {
 		s.respondJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
 		return
 	}
 
 	var req createAPIKeyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBodyStrict(w, r, &req); err != nil {
 		s.respondJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
 		return
 	}
 
-	// Validate tier
-	if req.Tier == "" {
-		req.Tier = "free"
+	// Keys start on the free tier; a paid tier is only set once the Stripe
+	// webhook confirms the subscription
+	if req.Tier != "" && req.Tier != "free" {
+		if _, _, ok := apiTierLimits(req.Tier); !ok {
+			s.respondJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid tier"})
+			return
+		}
+		s.respondJSON(w, http.StatusForbidden, map[string]string{"error": "paid tiers are granted through billing"})
+		return
 	}
-	if req.Tier != "free" && req.Tier != "developer" && req.Tier != "enterprise" {
-		s.respondJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid tier"})
+	req.Tier = "free"
+	dailyLimit, minuteLimit, _ := apiTierLimits(req.Tier)
+
+	scopes, err := resolveAPIKeyScopes(req.Tier, req.Scopes)
+	if err != nil {
+		s.respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 		return
 	}
 
@@ -67,20 +90,6 @@ func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	hash := sha256.Sum256([]byte(fullKey))
 	keyHash := hex.EncodeToString(hash[:])
 
-	// Set rate limits based on tier
-	var dailyLimit, minuteLimit int
-	switch req.Tier {
-	case "free":
-		dailyLimit = 100
-		minuteLimit = 2
-	case "developer":
-		dailyLimit = 10000
-		minuteLimit = 100
-	case "enterprise":
-		dailyLimit = 1000000
-		minuteLimit = 1000
-	}
-
 	// Create API key in database
 	apiKey := &database.APIKeyNew{
 		OwnerAddress:       address,
@@ -91,6 +100,7 @@ func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		Tier:               req.Tier,
 		RateLimitPerDay:    dailyLimit,
 		RateLimitPerMinute: minuteLimit,
+		Scopes:             scopes,
 		Active:             true,
 	}
 
@@ -108,8 +118,8 @@ func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
 
 // handleListAPIKeys lists all API keys for the authenticated user
 func (s *Server) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
-	address, ok := r.Context().Value("address").(string)
-	if !ok || address == "" {
+	address := getAuthenticatedAddress(r)
+	if address == "" {
 		s.respondJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
 		return
 	}
@@ -126,8 +136,8 @@ func (s *Server) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
 
 // handleRevokeAPIKey revokes an API key
 func (s *Server) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
-	address, ok := r.Context().Value("address").(string)
-	if !ok || address == "" {
+	address := getAuthenticatedAddress(r)
+	if address == "" {
 		s.respondJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
 		return
 	}
@@ -149,18 +159,37 @@ func (s *Server) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
 
 // handleGetAPIKeyUsage gets usage statistics for an API key
 func (s *Server) handleGetAPIKeyUsage(w http.ResponseWriter, r *http.Request) {
-	address, ok := r.Context().Value("address").(string)
-	if !ok || address == "" {
+	address := getAuthenticatedAddress(r)
+	if address == "" {
 		s.respondJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
 		return
 	}
 
-	keyID := chi.URLParam(r, "keyId")
+	keyID := mux.Vars(r)["keyId"]
 	if keyID == "" {
 		s.respondJSON(w, http.StatusBadRequest, map[string]string{"error": "missing key ID"})
 		return
 	}
 
+	// Resolve the key through the owner's keys so callers only see their own usage
+	keys, err := s.db.ListAPIKeysByOwner(r.Context(), address)
+	if err != nil {
+		s.logger.Error("failed to list API keys", zap.Error(err))
+		s.respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get usage"})
+		return
+	}
+	var key *database.APIKeyNew
+	for _, k := range keys {
+		if k.ID == keyID {
+			key = k
+			break
+		}
+	}
+	if key == nil {
+		s.respondJSON(w, http.StatusNotFound, map[string]string{"error": "API key not found"})
+		return
+	}
+
 	// Get daily summaries for the last 30 days
 	summaries, err := s.db.GetUsageSummary(r.Context(), keyID, "day", 30)
 	if err != nil {
@@ -169,7 +198,20 @@ func (s *Server) handleGetAPIKeyUsage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.respondJSON(w, http.StatusOK, summaries)
+	// Attestations issued this billing month against the tier quota
+	issuance, err := s.issuanceUsage(r.Context(), keyID, key.Tier)
+	if err != nil {
+		s.logger.Error("failed to get attestation issuance", zap.Error(err))
+		s.respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get usage"})
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"key_id":       keyID,
+		"tier":         key.Tier,
+		"summaries":    summaries,
+		"attestations": issuance,
+	})
 }
 
 // handleGetAPITiers returns available API tiers
@@ -184,7 +226,12 @@ func (s *Server) handleGetAPITiers(w http.ResponseWriter, r *http.Request) {
 	s.respondJSON(w, http.StatusOK, tiers)
 }
 
-// Rate limit middleware
+// apiKeyStore looks up API keys and records their usage; *database.DB implements it
+type apiKeyStore interface {
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*database.APIKeyNew, error)
+	IncrementAPIUsage(ctx context.Context, keyID string, statusCode, responseTimeMs int) error
+	IncrementAPIKeyUsage(ctx context.Context, keyID string) error
+}
 
 // apiKeyResponseWriter wraps http.ResponseWriter to capture status code
 type apiKeyResponseWriter struct {
@@ -196,80 +243,3 @@ func (rw *apiKeyResponseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
-
-// rateLimitMiddleware checks API key rate limits
-func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Get API key from header
-		apiKey := r.Header.Get("X-API-Key")
-		if apiKey == "" {
-			// No API key provided - allow but count as anonymous
-			next.ServeHTTP(w, r)
-			return
-		}
-
-		// Hash the provided key
-		hash := sha256.Sum256([]byte(apiKey))
-		keyHash := hex.EncodeToString(hash[:])
-
-		// Look up the key
-		key, err := s.db.GetAPIKeyByHash(r.Context(), keyHash)
-		if err != nil {
-			s.logger.Error("failed to get API key", zap.Error(err))
-			s.respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal error"})
-			return
-		}
-		if key == nil {
-			s.respondJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid API key"})
-			return
-		}
-
-		// Check if key is expired
-		if key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now()) {
-			s.respondJSON(w, http.StatusUnauthorized, map[string]string{"error": "API key expired"})
-			return
-		}
-
-		// Check rate limits
-		allowed, err := s.db.CheckRateLimit(r.Context(), key.ID, key.RateLimitPerDay, key.RateLimitPerMinute)
-		if err != nil {
-			s.logger.Error("failed to check rate limit", zap.Error(err))
-			s.respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal error"})
-			return
-		}
-		if !allowed {
-			s.respondJSON(w, http.StatusTooManyRequests, map[string]string{
-				"error": "rate limit exceeded",
-				"tier":  key.Tier,
-				"daily_limit": fmt.Sprintf("%d", key.RateLimitPerDay),
-			})
-			return
-		}
-
-		// Track request start time for response time tracking
-		startTime := time.Now()
-
-		// Add API key info to context
-		ctx := r.Context()
-		ctx = context.WithValue(ctx, "api_key_id", key.ID)
-		ctx = context.WithValue(ctx, "api_key_tier", key.Tier)
-
-		// Create response writer wrapper to capture status code
-		rw := &apiKeyResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
-
-		// Call next handler
-		next.ServeHTTP(rw, r.WithContext(ctx))
-
-		// Track usage asynchronously
-		go func() {
-			responseTimeMs := int(time.Since(startTime).Milliseconds())
-			if err := s.db.IncrementAPIUsage(context.Background(), key.ID, rw.statusCode, responseTimeMs); err != nil {
-				s.logger.Error("failed to increment API usage", zap.Error(err))
-			}
-			// Update last used timestamp
-			if err := s.db.UpdateAPIKeyLastUsed(context.Background(), key.ID); err != nil {
-				s.logger.Error("failed to update last used", zap.Error(err))
-			}
-		}()
-	})
-}
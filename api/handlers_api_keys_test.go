@@ -3,9 +3,14 @@ package api
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"go.uber.org/zap"
+
 	"github.com/chaincertify/certd/api/database"
 )
 
@@ -108,33 +113,29 @@ func TestTierRateLimits(t *testing.T) {
 	}
 }
 
-// TestCreateAPIKeyRequest tests the request structure
-func TestCreateAPIKeyRequest(t *testing.T) {
+// TestCreateAPIKeyPaidTier checks that a paid tier cannot be requested: keys
+// start on the free tier and only the Stripe webhook upgrades them
+func TestCreateAPIKeyPaidTier(t *testing.T) {
+	config := DefaultConfig()
+	s := NewServer(config, zap.NewNop())
+	token := testAuthToken(t, config.JWTSecret, "cert1keyowner")
+
 	tests := []struct {
-		name        string
-		tier        string
-		validTier   bool
+		tier string
+		want int
 	}{
-		{"Default tier", "", true},  // Should default to "free"
-		{"Free tier", "free", true},
-		{"Developer tier", "developer", true},
-		{"Enterprise tier", "enterprise", true},
-		{"Invalid tier", "premium", false},
-		{"Another invalid", "gold", false},
-	}
-
-	validTiers := map[string]bool{
-		"":           true, // Empty defaults to free
-		"free":       true,
-		"developer":  true,
-		"enterprise": true,
+		{"developer", http.StatusForbidden},
+		{"enterprise", http.StatusForbidden},
+		{"premium", http.StatusBadRequest},
 	}
-
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			isValid := validTiers[tt.tier]
-			if isValid != tt.validTier {
-				t.Errorf("Tier %q validation = %v, want %v", tt.tier, isValid, tt.validTier)
+		t.Run(tt.tier, func(t *testing.T) {
+			req := newJSONRequest(http.MethodPost, "/api/v1/api-keys", strings.NewReader(`{"name":"ci","tier":"`+tt.tier+`"}`))
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			s.router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("tier %q: status = %d, want %d: %s", tt.tier, rec.Code, tt.want, rec.Body.String())
 			}
 		})
 	}
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -22,9 +21,10 @@ type CreateEncryptedAttestationRequest struct {
 
 // CreateEncryptedAttestationResponse represents the response
 type CreateEncryptedAttestationResponse struct {
-	UID       string `json:"uid"`
-	TxHash    string `json:"tx_hash"`
-	Timestamp int64  `json:"timestamp"`
+	UID           string    `json:"uid"`
+	TxHash        string    `json:"tx_hash"`
+	Timestamp     Timestamp `json:"timestamp"`
+	TimestampUnix int64     `json:"timestamp_unix"`
 }
 
 // handleCreateEncryptedAttestation handles POST /api/v1/encrypted-attestations
@@ -32,8 +32,8 @@ func (s *Server) handleCreateEncryptedAttestation(w http.ResponseWriter, r *http
 	// Require authentication
 	s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
 		var req CreateEncryptedAttestationRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		if err := decodeJSONBody(w, r, &req); err != nil {
+			s.respondDecodeError(w, err)
 			return
 		}
 
@@ -51,8 +51,7 @@ func (s *Server) handleCreateEncryptedAttestation(w http.ResponseWriter, r *http
 			return
 		}
 		// Per Whitepaper Section 12: Max 50 recipients
-		if len(req.Recipients) > 50 {
-			s.respondError(w, http.StatusBadRequest, "maximum 50 recipients allowed")
+		if !s.enforceBatchSize(w, len(req.Recipients), s.config.BatchLimits.Recipients) {
 			return
 		}
 
@@ -70,8 +69,9 @@ func (s *Server) handleCreateEncryptedAttestation(w http.ResponseWriter, r *http
 		resp := CreateEncryptedAttestationResponse{
 			UID:       "0x" + generateUID(),
 			TxHash:    "0x" + generateUID(),
-			Timestamp: getCurrentTimestamp(),
+			Timestamp: NewTimestamp(s.clock.Now()),
 		}
+		resp.TimestampUnix = resp.Timestamp.Unix()
 
 		s.respondJSON(w, http.StatusCreated, resp)
 	})(w, r)
@@ -86,12 +86,13 @@ type RetrieveEncryptedAttestationRequest struct {
 
 // RetrieveEncryptedAttestationResponse represents the retrieval response
 type RetrieveEncryptedAttestationResponse struct {
-	UID          string `json:"uid"`
-	IPFSCID      string `json:"ipfs_cid"`
-	EncryptedKey string `json:"encrypted_key"`
-	SchemaUID    string `json:"schema_uid"`
-	Attester     string `json:"attester"`
-	Timestamp    int64  `json:"timestamp"`
+	UID           string    `json:"uid"`
+	IPFSCID       string    `json:"ipfs_cid"`
+	EncryptedKey  string    `json:"encrypted_key"`
+	SchemaUID     string    `json:"schema_uid"`
+	Attester      string    `json:"attester"`
+	Timestamp     Timestamp `json:"timestamp"`
+	TimestampUnix int64     `json:"timestamp_unix"`
 }
 
 // handleRetrieveEncryptedAttestation handles POST /api/v1/encrypted-attestations/{uid}/retrieve
@@ -100,8 +101,8 @@ func (s *Server) handleRetrieveEncryptedAttestation(w http.ResponseWriter, r *ht
 	uid := vars["uid"]
 
 	var req RetrieveEncryptedAttestationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 
@@ -126,8 +127,9 @@ func (s *Server) handleRetrieveEncryptedAttestation(w http.ResponseWriter, r *ht
 		EncryptedKey: "encrypted_key_for_recipient",
 		SchemaUID:    "0x...",
 		Attester:     "cert1...",
-		Timestamp:    getCurrentTimestamp(),
+		Timestamp:    NewTimestamp(s.clock.Now()),
 	}
+	resp.TimestampUnix = resp.Timestamp.Unix()
 
 	s.respondJSON(w, http.StatusOK, resp)
 }
@@ -146,7 +148,7 @@ func (s *Server) handleGetEncryptedAttestation(w http.ResponseWriter, r *http.Re
 		"recipients": []string{},
 		"revocable":  true,
 		"revoked":    false,
-		"timestamp":  getCurrentTimestamp(),
+		"timestamp":  s.clock.Now().Unix(),
 	})
 }
 
@@ -167,7 +169,7 @@ func (s *Server) handleRevokeEncryptedAttestation(w http.ResponseWriter, r *http
 		s.respondJSON(w, http.StatusOK, map[string]interface{}{
 			"uid":             uid,
 			"revoked":         true,
-			"revocation_time": getCurrentTimestamp(),
+			"revocation_time": s.clock.Now().Unix(),
 		})
 	})(w, r)
 }
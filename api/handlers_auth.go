@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -19,28 +20,141 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/ripemd160"
+
+	"github.com/chaincertify/certd/api/database"
 )
 
-// Minimal EIP-191 auth (challenge -> signature -> JWT)
-// This is designed for browser wallets (MetaMask) and cert-web in-app wallets.
+// Sign-in with a wallet (challenge -> signature -> JWT)
+// This is designed for browser wallets (MetaMask, Keplr) and cert-web in-app wallets.
+//
+// The challenge is a Sign-In with Ethereum (EIP-4361) style message naming the
+// domain, the address, a one-time nonce and when it was issued and expires.
+// Nonces are stored so any API instance can verify them, and each one is
+// consumed by its first verification attempt, successful or not.
+
+// authTokenTTL is the lifetime of the JWT issued on sign-in
+const authTokenTTL = 12 * time.Hour
+
+// AuthConfig configures wallet sign-in
+type AuthConfig struct {
+	// Domain is the site the user signs in to, shown first in the message
+	Domain string
+
+	// URI is the resource the signed-in session is for
+	URI string
 
-type authChallengeEntry struct {
-	Address   string
-	Message   string
-	ExpiresAt time.Time
-	Used      bool
+	// ChallengeTTL is how long a challenge nonce can be verified
+	ChallengeTTL time.Duration
 }
 
-var (
-	authMu         sync.Mutex
-	authChallenges = map[string]*authChallengeEntry{} // nonce -> entry
-)
+// DefaultAuthConfig signs users in to c3rt.org with five-minute challenges
+func DefaultAuthConfig() AuthConfig {
+	return AuthConfig{
+		Domain:       "c3rt.org",
+		URI:          "https://c3rt.org",
+		ChallengeTTL: 5 * time.Minute,
+	}
+}
+
+// authNonceStore persists sign-in challenges; *database.DB implements it
+type authNonceStore interface {
+	CreateAuthNonce(ctx context.Context, n *database.AuthNonce) error
+	GetAuthNonce(ctx context.Context, nonce string) (*database.AuthNonce, error)
+	ConsumeAuthNonce(ctx context.Context, nonce string, at time.Time) (bool, error)
+}
+
+// memoryAuthNonceStore keeps sign-in challenges in memory (used when no database is configured)
+type memoryAuthNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]database.AuthNonce
+}
+
+func newMemoryAuthNonceStore() *memoryAuthNonceStore {
+	return &memoryAuthNonceStore{nonces: make(map[string]database.AuthNonce)}
+}
+
+func (m *memoryAuthNonceStore) CreateAuthNonce(_ context.Context, n *database.AuthNonce) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for nonce, existing := range m.nonces {
+		if existing.ExpiresAt.Before(n.IssuedAt) {
+			delete(m.nonces, nonce)
+		}
+	}
+	m.nonces[n.Nonce] = *n
+	return nil
+}
+
+func (m *memoryAuthNonceStore) GetAuthNonce(_ context.Context, nonce string) (*database.AuthNonce, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.nonces[nonce]
+	if !ok {
+		return nil, nil
+	}
+	return &n, nil
+}
+
+func (m *memoryAuthNonceStore) ConsumeAuthNonce(_ context.Context, nonce string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, ok := m.nonces[nonce]
+	if !ok || n.UsedAt != nil || !at.Before(n.ExpiresAt) {
+		return false, nil
+	}
+	n.UsedAt = &at
+	m.nonces[nonce] = n
+	return true, nil
+}
+
+// signInMessage renders the EIP-4361 style challenge. The chain ID line carries
+// the EIP-155 chain ID when the Cosmos chain ID encodes one.
+func signInMessage(cfg AuthConfig, chainID, address, nonce string, issuedAt, expiresAt time.Time) string {
+	var b strings.Builder
+	b.WriteString(cfg.Domain + " wants you to sign in with your CERT account:\n")
+	b.WriteString(address + "\n\n")
+	b.WriteString("By signing, you authorize this app to obtain a short-lived JWT for CERT APIs.\n\n")
+	b.WriteString("URI: " + cfg.URI + "\n")
+	b.WriteString("Version: 1\n")
+	if id := evmChainID(chainID); id != "" {
+		b.WriteString("Chain ID: " + id + "\n")
+	}
+	b.WriteString("Nonce: " + nonce + "\n")
+	b.WriteString("Issued At: " + issuedAt.UTC().Format(time.RFC3339) + "\n")
+	b.WriteString("Expiration Time: " + expiresAt.UTC().Format(time.RFC3339))
+	return b.String()
+}
+
+// evmChainID extracts the EIP-155 chain ID from an Ethermint-style chain ID
+// such as cert_4283207343-1, returning "" for any other format
+func evmChainID(chainID string) string {
+	_, rest, ok := strings.Cut(chainID, "_")
+	if !ok {
+		return ""
+	}
+	id, _, ok := strings.Cut(rest, "-")
+	if !ok || id == "" || strings.Trim(id, "0123456789") != "" {
+		return ""
+	}
+	return id
+}
+
+// validAuthAddress reports whether address is a 0x EVM address or a cert1 bech32 address
+func validAuthAddress(address string) bool {
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		raw, err := hex.DecodeString(address[2:])
+		return err == nil && len(raw) == 20
+	}
+	hrp, raw, err := bech32.DecodeAndConvert(address)
+	return err == nil && hrp == "cert" && len(raw) == 20
+}
 
 type authChallengeResponse struct {
-	Address   string `json:"address"`
-	Nonce     string `json:"nonce"`
-	Challenge string `json:"challenge"`
-	ExpiresAt int64  `json:"expires_at"`
+	Address       string    `json:"address"`
+	Nonce         string    `json:"nonce"`
+	Challenge     string    `json:"challenge"`
+	ExpiresAt     Timestamp `json:"expires_at"`
+	ExpiresAtUnix int64     `json:"expires_at_unix"`
 }
 
 func (s *Server) handleAuthChallenge(w http.ResponseWriter, r *http.Request) {
@@ -49,29 +163,41 @@ func (s *Server) handleAuthChallenge(w http.ResponseWriter, r *http.Request) {
 		s.respondError(w, http.StatusBadRequest, "address is required")
 		return
 	}
+	if !validAuthAddress(address) {
+		s.respondError(w, http.StatusBadRequest, "address must be 0x... or cert1... format")
+		return
+	}
 
 	nonceBytes := make([]byte, 16)
-	_, _ = rand.Read(nonceBytes)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		s.logger.Error("failed to generate auth nonce", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to create challenge")
+		return
+	}
 	nonce := hex.EncodeToString(nonceBytes)
 
-	expiresAt := time.Now().Add(5 * time.Minute)
-	challenge := strings.Join([]string{
-		"CERT Authentication",
-		"\n\nAddress: " + address,
-		"\nNonce: " + nonce,
-		"\nIssued At: " + time.Now().UTC().Format(time.RFC3339),
-		"\n\nBy signing, you authorize this app to obtain a short-lived JWT for CERT APIs.",
-	}, "")
+	issuedAt := s.clock.Now()
+	expiresAt := issuedAt.Add(s.config.Auth.ChallengeTTL)
+	challenge := signInMessage(s.config.Auth, s.config.ChainID, address, nonce, issuedAt, expiresAt)
 
-	authMu.Lock()
-	authChallenges[nonce] = &authChallengeEntry{Address: address, Message: challenge, ExpiresAt: expiresAt, Used: false}
-	authMu.Unlock()
+	if err := s.authNonces.CreateAuthNonce(r.Context(), &database.AuthNonce{
+		Nonce:     nonce,
+		Address:   address,
+		Message:   challenge,
+		IssuedAt:  issuedAt,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		s.logger.Error("failed to store auth nonce", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to create challenge")
+		return
+	}
 
 	s.respondJSON(w, http.StatusOK, authChallengeResponse{
-		Address:   address,
-		Nonce:     nonce,
-		Challenge: challenge,
-		ExpiresAt: expiresAt.Unix(),
+		Address:       address,
+		Nonce:         nonce,
+		Challenge:     challenge,
+		ExpiresAt:     NewTimestamp(expiresAt),
+		ExpiresAtUnix: expiresAt.Unix(),
 	})
 }
 
@@ -82,16 +208,17 @@ type authVerifyRequest struct {
 }
 
 type authVerifyResponse struct {
-	OK        bool   `json:"ok"`
-	Address   string `json:"address,omitempty"`
-	Token     string `json:"token,omitempty"`
-	ExpiresAt int64  `json:"expires_at,omitempty"`
-	Error     string `json:"error,omitempty"`
+	OK            bool       `json:"ok"`
+	Address       string     `json:"address,omitempty"`
+	Token         string     `json:"token,omitempty"`
+	ExpiresAt     *Timestamp `json:"expires_at,omitempty"`
+	ExpiresAtUnix int64      `json:"expires_at_unix,omitempty"`
+	Error         string     `json:"error,omitempty"`
 }
 
 func (s *Server) handleAuthVerify(w http.ResponseWriter, r *http.Request) {
 	var req authVerifyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(w, r, &req); err != nil {
 		s.logger.Warn("auth verify failed: invalid json", zap.Error(err))
 		s.respondJSON(w, http.StatusBadRequest, authVerifyResponse{OK: false, Error: "Invalid request body"})
 		return
@@ -114,32 +241,42 @@ func (s *Server) handleAuthVerify(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	authMu.Lock()
-	entry := authChallenges[req.Nonce]
+	entry, err := s.authNonces.GetAuthNonce(r.Context(), req.Nonce)
+	if err != nil {
+		s.logger.Error("failed to load auth nonce", zap.Error(err))
+		s.respondJSON(w, http.StatusInternalServerError, authVerifyResponse{OK: false, Error: "internal error"})
+		return
+	}
 	if entry == nil {
-		authMu.Unlock()
 		s.respondJSON(w, http.StatusUnauthorized, authVerifyResponse{OK: false, Error: "invalid nonce"})
 		return
 	}
 	// One-time use
-	if entry.Used {
-		authMu.Unlock()
+	if entry.UsedAt != nil {
 		s.respondJSON(w, http.StatusUnauthorized, authVerifyResponse{OK: false, Error: "nonce already used"})
 		return
 	}
-	if time.Now().After(entry.ExpiresAt) {
-		authMu.Unlock()
+	now := s.clock.Now()
+	if !now.Before(entry.ExpiresAt) {
 		s.respondJSON(w, http.StatusUnauthorized, authVerifyResponse{OK: false, Error: "nonce expired"})
 		return
 	}
 	// Bind nonce to address
 	if !strings.EqualFold(entry.Address, req.Address) {
-		authMu.Unlock()
 		s.respondJSON(w, http.StatusUnauthorized, authVerifyResponse{OK: false, Error: "nonce not issued for this address"})
 		return
 	}
-	entry.Used = true
-	authMu.Unlock()
+	// Consumed before the signature is checked, so a nonce cannot be used to guess signatures
+	consumed, err := s.authNonces.ConsumeAuthNonce(r.Context(), req.Nonce, now)
+	if err != nil {
+		s.logger.Error("failed to consume auth nonce", zap.Error(err))
+		s.respondJSON(w, http.StatusInternalServerError, authVerifyResponse{OK: false, Error: "internal error"})
+		return
+	}
+	if !consumed {
+		s.respondJSON(w, http.StatusUnauthorized, authVerifyResponse{OK: false, Error: "nonce already used"})
+		return
+	}
 
 	// Normalize address: support both 0x... (EVM) and cert1... (bech32) formats
 	// For bech32 addresses, convert to 0x format for signature verification
@@ -331,13 +468,13 @@ func (s *Server) handleAuthVerify(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Issue JWT (default 12 hours)
+	// Issue JWT
 	// Store the original address format (could be bech32 or EVM) for consistency
-	exp := time.Now().Add(12 * time.Hour)
+	exp := now.Add(authTokenTTL)
 	claims := jwt.MapClaims{
 		"address": originalAddress,
 		"nonce":   req.Nonce,
-		"iat":     time.Now().Unix(),
+		"iat":     now.Unix(),
 		"exp":     exp.Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -347,7 +484,7 @@ func (s *Server) handleAuthVerify(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.respondJSON(w, http.StatusOK, authVerifyResponse{OK: true, Address: originalAddress, Token: signed, ExpiresAt: exp.Unix()})
+	s.respondJSON(w, http.StatusOK, authVerifyResponse{OK: true, Address: originalAddress, Token: signed, ExpiresAt: NewTimestampPtr(&exp), ExpiresAtUnix: exp.Unix()})
 }
 
 
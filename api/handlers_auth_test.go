@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestAuthChallengeVerify(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, time.May, 1, 9, 30, 0, 0, time.UTC))
+	s := newClockTestServer(clock)
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	challenge := func(t *testing.T, address string) authChallengeResponse {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/auth/challenge?address="+address, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("challenge = %d: %s", rec.Code, rec.Body.String())
+		}
+		var res authChallengeResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	// personalSign signs message as a wallet's personal_sign does
+	personalSign := func(t *testing.T, message string) string {
+		t.Helper()
+		sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig[64] += 27
+		return "0x" + hex.EncodeToString(sig)
+	}
+	verify := func(nonce, signature string) (*httptest.ResponseRecorder, authVerifyResponse) {
+		body, _ := json.Marshal(map[string]string{"address": address, "nonce": nonce, "signature": signature})
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, newJSONRequest("POST", "/api/v1/auth/verify", strings.NewReader(string(body))))
+		var res authVerifyResponse
+		_ = json.Unmarshal(rec.Body.Bytes(), &res)
+		return rec, res
+	}
+
+	ch := challenge(t, address)
+	for _, line := range []string{
+		"c3rt.org wants you to sign in with your CERT account:\n" + address + "\n",
+		"\nURI: https://c3rt.org\n",
+		"\nChain ID: 4283207343\n",
+		"\nNonce: " + ch.Nonce + "\n",
+		"\nIssued At: 2026-05-01T09:30:00Z\n",
+		"\nExpiration Time: 2026-05-01T09:35:00Z",
+	} {
+		if !strings.Contains(ch.Challenge, line) {
+			t.Errorf("challenge message missing %q:\n%s", line, ch.Challenge)
+		}
+	}
+
+	signature := personalSign(t, ch.Challenge)
+	rec, res := verify(ch.Nonce, signature)
+	if rec.Code != http.StatusOK || !res.OK {
+		t.Fatalf("verify = %d: %s", rec.Code, rec.Body.String())
+	}
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(res.Token, claims, func(*jwt.Token) (any, error) { return s.config.JWTSecret, nil },
+		jwt.WithTimeFunc(clock.Now)); err != nil {
+		t.Fatalf("issued token does not verify: %v", err)
+	}
+	if claims["address"] != address || claims["nonce"] != ch.Nonce {
+		t.Errorf("token claims = %v", claims)
+	}
+	if exp, _ := claims.GetExpirationTime(); !exp.Equal(clock.Now().Add(authTokenTTL)) {
+		t.Errorf("token expires at %v", exp)
+	}
+
+	t.Run("a nonce cannot be replayed", func(t *testing.T) {
+		rec, res := verify(ch.Nonce, signature)
+		if rec.Code != http.StatusUnauthorized || res.Error != "nonce already used" {
+			t.Errorf("replay = %d %q, want 401 nonce already used", rec.Code, res.Error)
+		}
+	})
+
+	t.Run("rejects an expired nonce", func(t *testing.T) {
+		ch := challenge(t, address)
+		clock.Advance(s.config.Auth.ChallengeTTL)
+		rec, res := verify(ch.Nonce, personalSign(t, ch.Challenge))
+		if rec.Code != http.StatusUnauthorized || res.Error != "nonce expired" {
+			t.Errorf("expired = %d %q, want 401 nonce expired", rec.Code, res.Error)
+		}
+	})
+
+	t.Run("a bad signature burns the nonce", func(t *testing.T) {
+		ch := challenge(t, address)
+		rec, _ := verify(ch.Nonce, personalSign(t, ch.Challenge+"tampered"))
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("tampered signature = %d, want 401", rec.Code)
+		}
+		if rec, res := verify(ch.Nonce, personalSign(t, ch.Challenge)); rec.Code != http.StatusUnauthorized || res.Error != "nonce already used" {
+			t.Errorf("retry after a bad signature = %d %q, want 401 nonce already used", rec.Code, res.Error)
+		}
+	})
+
+	t.Run("rejects unknown nonces and addresses", func(t *testing.T) {
+		if rec, res := verify("deadbeef", signature); rec.Code != http.StatusUnauthorized || res.Error != "invalid nonce" {
+			t.Errorf("unknown nonce = %d %q", rec.Code, res.Error)
+		}
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/auth/challenge?address=not-an-address", nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("challenge for an invalid address = %d, want 400", rec.Code)
+		}
+	})
+}
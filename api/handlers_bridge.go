@@ -1,14 +1,15 @@
 package api
 
 import (
-	"encoding/json"
 	"fmt"
 	"math/big"
 	"net/http"
-	"sync"
-	"time"
+	"strconv"
 
 	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
 )
 
 // BridgeTransfer represents a cross-chain transfer
@@ -22,12 +23,35 @@ type BridgeTransfer struct {
 	Status          string    `json:"status"` // pending, confirmed, completed, failed
 	TxHash          string    `json:"tx_hash,omitempty"`
 	TargetTxHash    string    `json:"target_tx_hash,omitempty"`
-	CreatedAt       time.Time `json:"created_at"`
-	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	CreatedAt       Timestamp  `json:"created_at"`
+	CompletedAt     *Timestamp `json:"completed_at,omitempty"`
 	Confirmations   int       `json:"confirmations"`
 	RequiredConfirm int       `json:"required_confirmations"`
 }
 
+// newBridgeTransfer converts a stored transfer to its API shape
+func newBridgeTransfer(t *database.BridgeTransfer) BridgeTransfer {
+	out := BridgeTransfer{
+		TransferID:      t.TransferID,
+		Sender:          t.Sender,
+		Recipient:       t.Recipient,
+		Amount:          t.Amount,
+		SourceChainID:   t.SourceChainID,
+		TargetChainID:   t.TargetChainID,
+		Status:          t.Status,
+		TxHash:          t.TxHash,
+		TargetTxHash:    t.TargetTxHash,
+		CreatedAt:       NewTimestamp(t.CreatedAt),
+		Confirmations:   t.Confirmations,
+		RequiredConfirm: t.RequiredConfirm,
+	}
+	if t.CompletedAt != nil {
+		completedAt := NewTimestamp(*t.CompletedAt)
+		out.CompletedAt = &completedAt
+	}
+	return out
+}
+
 // SupportedChain represents a chain supported by the bridge
 type SupportedChain struct {
 	ChainID     uint64 `json:"chain_id"`
@@ -41,24 +65,10 @@ type SupportedChain struct {
 	Fee         string `json:"fee_percent"`
 }
 
-// In-memory store for bridge transfers (use DB in production)
-var (
-	bridgeTransfers     = make(map[string]*BridgeTransfer)
-	bridgeTransferMutex sync.RWMutex
-)
-
-// Supported chains configuration
-var supportedChains = []SupportedChain{
-	{ChainID: 951753, Name: "CERT Chain (EVM)", Symbol: "CERT", BridgeAddr: "0x...", IsActive: true, MinAmount: "1", MaxAmount: "1000000", Fee: "0.1"},
-	{ChainID: 1, Name: "Ethereum", Symbol: "ETH", BridgeAddr: "0x...", IsActive: true, MinAmount: "1", MaxAmount: "1000000", Fee: "0.1"},
-	{ChainID: 42161, Name: "Arbitrum One", Symbol: "ARB", BridgeAddr: "0x...", IsActive: true, MinAmount: "1", MaxAmount: "1000000", Fee: "0.05"},
-	{ChainID: 137, Name: "Polygon", Symbol: "MATIC", BridgeAddr: "0x...", IsActive: false, MinAmount: "1", MaxAmount: "1000000", Fee: "0.05"},
-}
-
 // handleGetSupportedChains returns all supported chains for bridging
 func (s *Server) handleGetSupportedChains(w http.ResponseWriter, r *http.Request) {
 	activeChains := make([]SupportedChain, 0)
-	for _, chain := range supportedChains {
+	for _, chain := range s.bridge.supportedChains() {
 		if chain.IsActive {
 			activeChains = append(activeChains, chain)
 		}
@@ -112,8 +122,8 @@ type LockTokensRequest struct {
 // handleLockTokens initiates a bridge transfer by preparing a lock transaction
 func (s *Server) handleLockTokens(w http.ResponseWriter, r *http.Request) {
 	var req LockTokensRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBodyStrict(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 
@@ -134,7 +144,7 @@ func (s *Server) handleLockTokens(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate transfer ID
-	transferID := fmt.Sprintf("0x%x", time.Now().UnixNano())
+	transferID := fmt.Sprintf("0x%x", s.clock.Now().UnixNano())
 
 	// Create unsigned transaction for EVM bridge contract
 	unsignedTx := map[string]interface{}{
@@ -145,21 +155,23 @@ func (s *Server) handleLockTokens(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Store pending transfer
-	transfer := &BridgeTransfer{
+	transfer := database.BridgeTransfer{
 		TransferID:      transferID,
 		Sender:          req.Sender,
 		Recipient:       req.Recipient,
-		Amount:          req.Amount,
+		Amount:          amount.String(),
 		SourceChainID:   req.SourceChainID,
 		TargetChainID:   req.TargetChainID,
 		Status:          "pending",
-		CreatedAt:       time.Now(),
+		CreatedAt:       s.clock.Now(),
 		Confirmations:   0,
 		RequiredConfirm: 12,
 	}
-	bridgeTransferMutex.Lock()
-	bridgeTransfers[transferID] = transfer
-	bridgeTransferMutex.Unlock()
+	if err := s.bridgeTransfers.CreateBridgeTransfer(r.Context(), &transfer); err != nil {
+		s.logger.Error("Failed to store bridge transfer", zap.String("transfer_id", transferID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to record transfer")
+		return
+	}
 
 	s.respondJSON(w, http.StatusOK, map[string]interface{}{
 		"transfer_id":  transferID,
@@ -175,106 +187,130 @@ func (s *Server) handleGetTransferStatus(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	transferID := vars["transfer_id"]
 
-	bridgeTransferMutex.RLock()
-	transfer, exists := bridgeTransfers[transferID]
-	bridgeTransferMutex.RUnlock()
-
-	if !exists {
+	transfer, err := s.bridgeTransfers.GetBridgeTransfer(r.Context(), transferID)
+	if err != nil {
+		s.logger.Error("Failed to get bridge transfer", zap.String("transfer_id", transferID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get transfer")
+		return
+	}
+	if transfer == nil {
 		s.respondError(w, http.StatusNotFound, "Transfer not found")
 		return
 	}
 
-	s.respondJSON(w, http.StatusOK, transfer)
+	s.respondJSON(w, http.StatusOK, newBridgeTransfer(transfer))
 }
 
-// handleGetTransferHistory returns bridge transfer history for an address
+// handleGetTransferHistory returns one page of bridge transfer history for an address, newest first
+// GET /api/v1/bridge/history/{address}?page=1&limit=20
 func (s *Server) handleGetTransferHistory(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	address := vars["address"]
 
-	bridgeTransferMutex.RLock()
-	transfers := make([]*BridgeTransfer, 0)
-	for _, t := range bridgeTransfers {
-		if t.Sender == address || t.Recipient == address {
-			transfers = append(transfers, t)
-		}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	stored, total, err := s.bridgeTransfers.ListBridgeTransfersByAddress(r.Context(), address, limit, (page-1)*limit)
+	if err != nil {
+		s.logger.Error("Failed to list bridge transfers", zap.String("address", address), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to list transfers")
+		return
+	}
+	transfers := make([]BridgeTransfer, 0, len(stored))
+	for i := range stored {
+		transfers = append(transfers, newBridgeTransfer(&stored[i]))
 	}
-	bridgeTransferMutex.RUnlock()
 
 	s.respondJSON(w, http.StatusOK, map[string]interface{}{
 		"address":   address,
 		"transfers": transfers,
-		"total":     len(transfers),
+		"total":     total,
+		"page":      page,
+		"limit":     limit,
 	})
 }
 
-// handleConfirmTransfer updates transfer status (called by validator service)
+// handleConfirmTransfer updates transfer status (called by validator service, requires a service
+// token and a relayer signature; see bridge_relayers.go)
 func (s *Server) handleConfirmTransfer(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	transferID := vars["transfer_id"]
 
 	var req struct {
-		TxHash        string `json:"tx_hash"`
-		Status        string `json:"status"`
-		Confirmations int    `json:"confirmations"`
+		TxHash           string `json:"tx_hash"`
+		Status           string `json:"status"`
+		Confirmations    int    `json:"confirmations"`
+		RelayerSignature string `json:"relayer_signature"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBodyStrict(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 
-	bridgeTransferMutex.Lock()
-	transfer, exists := bridgeTransfers[transferID]
-	if exists {
-		if req.TxHash != "" {
-			transfer.TxHash = req.TxHash
-		}
-		if req.Status != "" {
-			transfer.Status = req.Status
-		}
-		if req.Confirmations > 0 {
-			transfer.Confirmations = req.Confirmations
-		}
-		if req.Status == "completed" {
-			now := time.Now()
-			transfer.CompletedAt = &now
-		}
+	relayer, err := s.verifyRelayerSignature(bridgeConfirmationMessage(transferID, req.TxHash, req.Status, req.Confirmations), req.RelayerSignature)
+	if err != nil {
+		s.respondError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	current, err := s.bridgeTransfers.GetBridgeTransfer(r.Context(), transferID)
+	if err != nil {
+		s.logger.Error("Failed to get bridge transfer", zap.String("transfer_id", transferID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get transfer")
+		return
+	}
+	if current == nil {
+		s.respondError(w, http.StatusNotFound, "Transfer not found")
+		return
+	}
+	if err := checkBridgeStatusTransition(current.Status, req.Status, current.Confirmations, req.Confirmations); err != nil {
+		s.respondError(w, http.StatusConflict, err.Error())
+		return
 	}
-	bridgeTransferMutex.Unlock()
 
-	if !exists {
+	update := database.BridgeTransferUpdate{TxHash: req.TxHash, Status: req.Status, Confirmations: req.Confirmations}
+	if req.Status == "completed" && current.Status != "completed" {
+		now := s.clock.Now()
+		update.CompletedAt = &now
+	}
+	transfer, err := s.bridgeTransfers.UpdateBridgeTransferStatus(r.Context(), transferID, update)
+	if err != nil {
+		s.logger.Error("Failed to update bridge transfer", zap.String("transfer_id", transferID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to update transfer")
+		return
+	}
+	if transfer == nil {
 		s.respondError(w, http.StatusNotFound, "Transfer not found")
 		return
 	}
 
-	s.respondJSON(w, http.StatusOK, transfer)
+	s.logger.Info("Bridge transfer confirmed",
+		zap.String("transfer_id", transferID),
+		zap.String("status", transfer.Status),
+		zap.String("relayer", relayer.Hex()),
+	)
+	s.respondJSON(w, http.StatusOK, newBridgeTransfer(transfer))
 }
 
 // handleGetBridgeStats returns overall bridge statistics
 func (s *Server) handleGetBridgeStats(w http.ResponseWriter, r *http.Request) {
-	bridgeTransferMutex.RLock()
-	totalTransfers := len(bridgeTransfers)
-	pendingCount := 0
-	completedCount := 0
-	totalVolume := big.NewInt(0)
-
-	for _, t := range bridgeTransfers {
-		if t.Status == "pending" || t.Status == "confirmed" {
-			pendingCount++
-		} else if t.Status == "completed" {
-			completedCount++
-		}
-		if amt, ok := new(big.Int).SetString(t.Amount, 10); ok {
-			totalVolume.Add(totalVolume, amt)
-		}
+	st, err := s.bridgeTransfers.GetBridgeTransferStats(r.Context())
+	if err != nil {
+		s.logger.Error("Failed to aggregate bridge transfers", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get bridge stats")
+		return
 	}
-	bridgeTransferMutex.RUnlock()
-
 	s.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"total_transfers":     totalTransfers,
-		"pending_transfers":   pendingCount,
-		"completed_transfers": completedCount,
-		"total_volume":        totalVolume.String(),
-		"supported_chains":    len(supportedChains),
+		"total_transfers":     st.Total,
+		"pending_transfers":   st.Pending,
+		"completed_transfers": st.Completed,
+		"total_volume":        st.Volume,
+		"supported_chains":    len(s.bridge.supportedChains()),
 	})
 }
@@ -38,8 +38,8 @@ type certIDVCVerifyResponse struct {
 
 func (s *Server) handleVerifyCertIDVC(w http.ResponseWriter, r *http.Request) {
 	var req certIDVCVerifyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 	if len(req.VC) == 0 {
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/chaincertify/certd/api/database"
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -24,6 +25,9 @@ type ErrorResponse struct {
 	Code    int    `json:"code"`
 	Message string `json:"message,omitempty"`
 	RawLog  string `json:"raw_log,omitempty"`
+
+	// RequestID is the correlation ID to quote when reporting the error
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // respondJSON sends a JSON response
@@ -57,7 +61,7 @@ func (s *Server) respondTxError(w http.ResponseWriter, status int, message strin
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	s.respondJSON(w, http.StatusOK, map[string]interface{}{
 		"status":    "healthy",
-		"timestamp": getCurrentTimestamp(),
+		"timestamp": s.clock.Now().Unix(),
 		"version":   "1.0.0",
 	})
 }
@@ -71,8 +75,8 @@ func (s *Server) handleCreateSchema(w http.ResponseWriter, r *http.Request) {
 			Revocable bool   `json:"revocable"`
 		}
 
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		if err := decodeJSONBody(w, r, &req); err != nil {
+			s.respondDecodeError(w, err)
 			return
 		}
 
@@ -128,13 +132,15 @@ func (s *Server) handleCreateSchema(w http.ResponseWriter, r *http.Request) {
 			s.logger.Warn("schema tx succeeded but schema_uid not found in events", zap.String("txhash", txRes.TxHash))
 		}
 
+		txTime := certdTxTimestamp(txRes.Timestamp)
 		s.respondJSON(w, http.StatusCreated, map[string]interface{}{
-			"uid":       uid,
-			"tx_hash":   txRes.TxHash,
-			"schema":    req.Schema,
-			"revocable": req.Revocable,
-			"creator":   creator,
-			"timestamp": certdTxTimestampUnix(txRes.Timestamp),
+			"uid":            uid,
+			"tx_hash":        txRes.TxHash,
+			"schema":         req.Schema,
+			"revocable":      req.Revocable,
+			"creator":        creator,
+			"timestamp":      txTime,
+			"timestamp_unix": txTime.Unix(),
 		})
 	})(w, r)
 }
@@ -180,18 +186,19 @@ func (s *Server) handleGetSchema(w http.ResponseWriter, r *http.Request) {
 
 // handleCreateAttestation handles POST /api/v1/attestations
 func (s *Server) handleCreateAttestation(w http.ResponseWriter, r *http.Request) {
-	s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+	s.requireAuthOrAPIKey(func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
-			SchemaUID      string `json:"schema_uid"`
-			Recipient      string `json:"recipient"`
-			Data           string `json:"data"`
-			Revocable      bool   `json:"revocable"`
-			ExpirationTime int64  `json:"expiration_time,omitempty"`
-			RefUID         string `json:"ref_uid,omitempty"`
+			SchemaUID      string   `json:"schema_uid"`
+			Recipient      string   `json:"recipient"`
+			Data           string   `json:"data"`
+			Revocable      bool     `json:"revocable"`
+			ExpirationTime int64    `json:"expiration_time,omitempty"`
+			RefUID         string   `json:"ref_uid,omitempty"`
+			Tags           []string `json:"tags,omitempty"`
 		}
 
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		if err := decodeJSONBody(w, r, &req); err != nil {
+			s.respondDecodeError(w, err)
 			return
 		}
 
@@ -219,6 +226,24 @@ func (s *Server) handleCreateAttestation(w http.ResponseWriter, r *http.Request)
 		}
 		dataHex := hex.EncodeToString(dataBytes)
 
+		tags, err := attestationtypes.NormalizeTags(req.Tags)
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Meter attestations issued through an API key against its monthly quota
+		release, ok := s.reserveIssuance(w, r)
+		if !ok {
+			return
+		}
+		issued := false
+		defer func() {
+			if !issued {
+				release()
+			}
+		}()
+
 		ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
 		defer cancel()
 
@@ -245,6 +270,9 @@ func (s *Server) handleCreateAttestation(w http.ResponseWriter, r *http.Request)
 		if strings.TrimSpace(req.RefUID) != "" {
 			args = append(args, "--ref-uid", strings.TrimSpace(req.RefUID))
 		}
+		if len(tags) > 0 {
+			args = append(args, "--tags", strings.Join(tags, ","))
+		}
 
 		_, err = s.execCertdTxJSON(ctx, &txRes, args...)
 		if err != nil {
@@ -264,17 +292,95 @@ func (s *Server) handleCreateAttestation(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
+		issued = true
+
 		uid, _ := findTxEventAttribute(txRes, "attestation_uid")
 		if uid == "" {
 			s.logger.Warn("attestation tx succeeded but attestation_uid not found in events", zap.String("txhash", txRes.TxHash))
 		}
 
+		txTime := certdTxTimestamp(txRes.Timestamp)
 		s.respondJSON(w, http.StatusCreated, map[string]interface{}{
-			"uid":       uid,
-			"tx_hash":   txRes.TxHash,
-			"attester":  attester,
-			"recipient": recipient,
-			"timestamp": certdTxTimestampUnix(txRes.Timestamp),
+			"uid":            uid,
+			"tx_hash":        txRes.TxHash,
+			"attester":       attester,
+			"recipient":      recipient,
+			"timestamp":      txTime,
+			"timestamp_unix": txTime.Unix(),
+		})
+	})(w, r)
+}
+
+// handleBatchRevokeAttestations revokes several attestations in one all-or-nothing tx
+func (s *Server) handleBatchRevokeAttestations(w http.ResponseWriter, r *http.Request) {
+	s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			UIDs []string `json:"uids"`
+		}
+
+		if err := decodeJSONBodyStrict(w, r, &req); err != nil {
+			s.respondDecodeError(w, err)
+			return
+		}
+
+		uids := make([]string, 0, len(req.UIDs))
+		seen := make(map[string]bool, len(req.UIDs))
+		for _, uid := range req.UIDs {
+			uid = strings.TrimSpace(uid)
+			if uid == "" {
+				s.respondError(w, http.StatusBadRequest, "uids cannot contain empty values")
+				return
+			}
+			if seen[uid] {
+				s.respondError(w, http.StatusBadRequest, fmt.Sprintf("duplicate uid: %s", uid))
+				return
+			}
+			seen[uid] = true
+			uids = append(uids, uid)
+		}
+		if len(uids) == 0 {
+			s.respondError(w, http.StatusBadRequest, "uids is required")
+			return
+		}
+		if !s.enforceBatchSize(w, len(uids), s.config.BatchLimits.RevokeUIDs) {
+			return
+		}
+
+		revoker := getAuthenticatedAddress(r)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+		defer cancel()
+
+		var txRes certdTxResponse
+		// Usage: certd tx attestation batch-revoke [uid]... [flags]
+		args := append([]string{"attestation", "batch-revoke"}, uids...)
+
+		_, err := s.execCertdTxJSON(ctx, &txRes, args...)
+		if err != nil {
+			s.logger.Error("batch revoke tx failed", zap.Error(err))
+			var txErr *certdTxExecError
+			if errors.As(err, &txErr) {
+				if txErr.Tx.Code != 0 {
+					s.respondTxError(w, http.StatusBadRequest, "batch revoke tx rejected", txErr.Tx.RawLog)
+					return
+				}
+			}
+			s.respondError(w, http.StatusBadGateway, "failed to submit batch revoke tx")
+			return
+		}
+		if txRes.Code != 0 {
+			s.respondTxError(w, http.StatusBadRequest, "batch revoke tx rejected", txRes.RawLog)
+			return
+		}
+
+		txTime := certdTxTimestamp(txRes.Timestamp)
+		s.respondJSON(w, http.StatusOK, map[string]interface{}{
+			"uids":           uids,
+			"revoked":        len(uids),
+			"tx_hash":        txRes.TxHash,
+			"revoker":        revoker,
+			"timestamp":      txTime,
+			"timestamp_unix": txTime.Unix(),
 		})
 	})(w, r)
 }
@@ -324,6 +430,7 @@ func tryBase64(s string) ([]byte, error) {
 }
 
 // handleGetAttestation handles GET /api/v1/attestations/{uid}
+// With ?include=issuer_reputation the attester's IssuerReputation is embedded.
 func (s *Server) handleGetAttestation(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	uid := vars["uid"]
@@ -332,6 +439,11 @@ func (s *Server) handleGetAttestation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.URL.Query().Has("height") {
+		s.handleGetAttestationAtHeight(w, r, uid)
+		return
+	}
+
 	// Best-effort: query the chain via certd.
 	// Command: certd query attestation attestation <uid> --output json
 	var raw map[string]any
@@ -342,7 +454,12 @@ func (s *Server) handleGetAttestation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Normalize common shapes for frontend convenience.
+	s.respondJSON(w, http.StatusOK, s.attestationResponse(r, uid, raw))
+}
+
+// attestationResponse normalizes a `certd query attestation attestation` result
+// for the frontend; unrecognized shapes are returned as-is
+func (s *Server) attestationResponse(r *http.Request, uid string, raw map[string]any) map[string]any {
 	if a, ok := raw["attestation"].(map[string]any); ok {
 		out := map[string]any{"uid": uid}
 		if v, ok := a["schema_uid"]; ok {
@@ -387,11 +504,17 @@ func (s *Server) handleGetAttestation(w http.ResponseWriter, r *http.Request) {
 			out["type"] = v
 			out["encrypted"] = v != "public" && v != ""
 		}
-		s.respondJSON(w, http.StatusOK, out)
-		return
+		if attester, ok := a["attester"].(string); ok && attester != "" && r.URL.Query().Get("include") == "issuer_reputation" {
+			if rep, err := s.issuerReputation(r.Context(), attester, attester); err == nil {
+				out["issuer_reputation"] = rep
+			} else {
+				s.logger.Warn("failed to compute issuer reputation", zap.String("attester", attester), zap.Error(err))
+			}
+		}
+		return out
 	}
 
-	s.respondJSON(w, http.StatusOK, raw)
+	return raw
 }
 
 // handleGetAttestationsByAttester handles GET /api/v1/attestations/by-attester/{address}
@@ -439,6 +562,45 @@ func (s *Server) handleGetAttestationsByRecipient(w http.ResponseWriter, r *http
 	s.respondJSON(w, http.StatusOK, attestations)
 }
 
+// revocationListItem is one entry of GET /api/v1/attestations/revoked/{attester}
+type revocationListItem struct {
+	UID            string `json:"uid"`
+	Attester       string `json:"attester"`
+	RevocationTime string `json:"revocation_time"`
+}
+
+// handleGetRevokedAttestations handles GET /api/v1/attestations/revoked/{attester}
+func (s *Server) handleGetRevokedAttestations(w http.ResponseWriter, r *http.Request) {
+	bech32Addr, err := toBech32Address(mux.Vars(r)["attester"])
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var revocations []attestationtypes.Revocation
+	err = retryAtLaggedHeight(r.Context(), s.chain.LatestHeight, func(ctx context.Context, height int64) error {
+		var err error
+		revocations, err = s.chain.AttestationRevocations(ctx, bech32Addr, height)
+		return err
+	})
+	if err != nil {
+		s.logger.Warn("failed to query revocations", zap.String("address", bech32Addr), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query revocations")
+		return
+	}
+
+	// Return a plain array for frontend convenience.
+	items := make([]revocationListItem, 0, len(revocations))
+	for _, rev := range revocations {
+		items = append(items, revocationListItem{
+			UID:            rev.UID,
+			Attester:       certAddress(rev.Attester),
+			RevocationTime: rev.RevocationTime.UTC().Format(time.RFC3339Nano),
+		})
+	}
+	s.respondJSON(w, http.StatusOK, items)
+}
+
 // handleAddCredential handles POST /api/v1/profile/credentials
 func (s *Server) handleAddCredential(w http.ResponseWriter, r *http.Request) {
 	if s.db == nil {
@@ -453,20 +615,46 @@ func (s *Server) handleAddCredential(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req struct {
-		UserAddress    string `json:"user_address"`
-		CredentialType string `json:"credential_type"`
-		AttestationUID string `json:"attestation_uid"`
-		Issuer         string `json:"issuer"`
-		Verified       bool   `json:"verified"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	var req credentialRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 	if req.UserAddress == "" {
 		req.UserAddress = address
 	}
+
+	// Only internal verifiers mark credentials verified (POST /internal/credentials)
+	s.addCredential(w, r, req, false)
+}
+
+// credentialRequest is the body of the credential write endpoints
+type credentialRequest struct {
+	UserAddress    string `json:"user_address"`
+	CredentialType string `json:"credential_type"`
+	AttestationUID string `json:"attestation_uid"`
+	Issuer         string `json:"issuer"`
+}
+
+// handleRecordVerifiedCredential records a credential checked by an internal
+// verification service. Service token required.
+// POST /api/v1/internal/credentials
+func (s *Server) handleRecordVerifiedCredential(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		s.respondError(w, http.StatusServiceUnavailable, "CertID database not configured")
+		return
+	}
+
+	var req credentialRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
+		return
+	}
+	s.addCredential(w, r, req, true)
+}
+
+// addCredential validates and stores a credential write
+func (s *Server) addCredential(w http.ResponseWriter, r *http.Request, req credentialRequest, verified bool) {
 	if req.UserAddress == "" {
 		s.respondError(w, http.StatusBadRequest, "user_address is required")
 		return
@@ -484,8 +672,8 @@ func (s *Server) handleAddCredential(w http.ResponseWriter, r *http.Request) {
 		CredentialType: req.CredentialType,
 		AttestationUID: req.AttestationUID,
 		Issuer:         req.Issuer,
-		Verified:       req.Verified,
-		IssuedAt:       time.Now(),
+		Verified:       verified,
+		IssuedAt:       s.clock.Now(),
 	}
 	if err := s.db.AddCredential(ctx, c); err != nil {
 		s.logger.Warn("failed to add credential", zap.String("address", req.UserAddress), zap.Error(err))
@@ -539,12 +727,18 @@ func (s *Server) handleRemoveCredential(w http.ResponseWriter, r *http.Request)
 
 // handleGetStats handles GET /api/v1/stats
 func (s *Server) handleGetStats(w http.ResponseWriter, r *http.Request) {
-	s.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"total_attestations":           0,
-		"total_schemas":                0,
-		"total_encrypted_attestations": 0,
-		"total_profiles":               0,
+	var stats *attestationtypes.QueryStatsResponse
+	err := retryAtLaggedHeight(r.Context(), s.chain.LatestHeight, func(ctx context.Context, height int64) error {
+		var err error
+		stats, err = s.chain.AttestationStats(ctx, height)
+		return err
 	})
+	if err != nil {
+		s.logger.Warn("failed to query attestation stats", zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query attestation stats")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, stats)
 }
 
 // handleGetProposals stub removed - now using handleGetAllProposals in handlers_governance.go
@@ -556,7 +750,3 @@ func generateUID() string {
 	return hex.EncodeToString(bytes)
 }
 
-// getCurrentTimestamp returns current Unix timestamp
-func getCurrentTimestamp() int64 {
-	return time.Now().Unix()
-}
@@ -7,9 +7,12 @@ import (
 	"math/big"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
+
+	"github.com/chaincertify/certd/api/retry"
 )
 
 // Cross-Chain Sybil API
@@ -42,8 +45,8 @@ type ChainActivityData struct {
 	Chain           string    `json:"chain"`
 	TxCount         int       `json:"tx_count"`
 	Balance         string    `json:"balance"`
-	FirstTxTime     time.Time `json:"first_tx_time,omitempty"`
-	LastTxTime      time.Time `json:"last_tx_time,omitempty"`
+	FirstTxTime     Timestamp `json:"first_tx_time"`
+	LastTxTime      Timestamp `json:"last_tx_time"`
 	ContractDeploys int       `json:"contract_deploys"`
 	NFTsOwned       int       `json:"nfts_owned,omitempty"`
 	DeFiInteraction bool      `json:"defi_interaction"`
@@ -66,7 +69,7 @@ type CrossChainSybilResponse struct {
 	ChainsChecked     []string            `json:"chains_checked"`
 	CrossChainScore   int                 `json:"cross_chain_score"`   // Bonus for multi-chain activity
 	Layer3Compatible  bool                `json:"layer3_compatible"`   // Ready for Layer3 quests
-	CheckedAt         time.Time           `json:"checked_at"`
+	CheckedAt         Timestamp           `json:"checked_at"`
 }
 
 // VerifyActionRequest for Layer3 action verification
@@ -86,7 +89,7 @@ type VerifyActionResponse struct {
 	Action    string    `json:"action"`
 	Verified  bool      `json:"verified"`
 	TxHash    string    `json:"tx_hash,omitempty"`
-	Timestamp time.Time `json:"timestamp,omitempty"`
+	Timestamp Timestamp `json:"timestamp"`
 	Details   string    `json:"details,omitempty"`
 }
 
@@ -118,10 +121,10 @@ func (s *Server) handleCrossChainSybilCheck(w http.ResponseWriter, r *http.Reque
 	chainData := s.getMultiChainActivity(ctx, address, chainsToCheck)
 	
 	// Calculate cross-chain bonus
-	crossChainScore := calculateCrossChainBonus(chainData)
+	crossChainScore := calculateCrossChainBonus(s.trustScore.CrossChain, chainData)
 	
 	// Combine with base trust score
-	baseScore := calculateSybilTrustScore(certFactors)
+	baseScore := calculateSybilTrustScore(s.trustScore.Sybil, certFactors)
 	totalScore := sybilMin(baseScore + crossChainScore, 100)
 	
 	// Default threshold is 50
@@ -141,7 +144,7 @@ func (s *Server) handleCrossChainSybilCheck(w http.ResponseWriter, r *http.Reque
 		ChainsChecked:    chainsToCheck,
 		CrossChainScore:  crossChainScore,
 		Layer3Compatible: totalScore >= 50 && len(chainData) >= 1,
-		CheckedAt:        time.Now(),
+		CheckedAt:        NewTimestamp(s.clock.Now()),
 	}
 
 	s.respondJSON(w, http.StatusOK, response)
@@ -150,7 +153,7 @@ func (s *Server) handleCrossChainSybilCheck(w http.ResponseWriter, r *http.Reque
 // handleVerifyChainAction verifies a specific on-chain action for Layer3
 func (s *Server) handleVerifyChainAction(w http.ResponseWriter, r *http.Request) {
 	var req VerifyActionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(w, r, &req); err != nil {
 		s.respondJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 		return
 	}
@@ -179,7 +182,7 @@ func (s *Server) handleVerifyChainAction(w http.ResponseWriter, r *http.Request)
 		Action:    req.Action,
 		Verified:  verified,
 		TxHash:    txHash,
-		Timestamp: timestamp,
+		Timestamp: NewTimestamp(timestamp),
 		Details:   details,
 	}
 
@@ -189,13 +192,12 @@ func (s *Server) handleVerifyChainAction(w http.ResponseWriter, r *http.Request)
 // handleBatchVerifyActions verifies multiple actions in one request
 func (s *Server) handleBatchVerifyActions(w http.ResponseWriter, r *http.Request) {
 	var requests []VerifyActionRequest
-	if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+	if err := decodeJSONBody(w, r, &requests); err != nil {
 		s.respondJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 		return
 	}
 
-	if len(requests) > 20 {
-		s.respondJSON(w, http.StatusBadRequest, map[string]string{"error": "max 20 actions per batch"})
+	if !s.enforceBatchSize(w, len(requests), s.config.BatchLimits.VerifyActions) {
 		return
 	}
 
@@ -222,7 +224,7 @@ func (s *Server) handleBatchVerifyActions(w http.ResponseWriter, r *http.Request
 			Action:    req.Action,
 			Verified:  verified,
 			TxHash:    txHash,
-			Timestamp: timestamp,
+			Timestamp: NewTimestamp(timestamp),
 			Details:   details,
 		})
 	}
@@ -236,35 +238,69 @@ func (s *Server) handleBatchVerifyActions(w http.ResponseWriter, r *http.Request
 	})
 }
 
-// getMultiChainActivity fetches on-chain activity from multiple chains
+// getMultiChainActivity fetches on-chain activity from multiple chains in
+// parallel. Chains that fail or miss the CrossChain.Timeout are left out.
 func (s *Server) getMultiChainActivity(ctx context.Context, address string, chains []string) map[string]ChainActivityData {
-	chainData := make(map[string]ChainActivityData)
+	if timeout := s.config.CrossChain.Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		chainData = make(map[string]ChainActivityData)
+	)
 	for _, chainName := range chains {
-		chainConfig, ok := SupportedChains[strings.ToLower(chainName)]
+		key := strings.ToLower(chainName)
+		chainConfig, ok := SupportedChains[key]
 		if !ok {
 			continue
 		}
 
-		data := s.fetchChainActivity(ctx, address, chainConfig)
-		if data.TxCount > 0 || data.Balance != "0" {
-			chainData[chainName] = data
-		}
+		wg.Add(1)
+		go func(chainName, key string, chainConfig ChainConfig) {
+			defer wg.Done()
+			data, ok := s.chainActivity.get(address, key)
+			if !ok {
+				release, err := s.chainActivity.acquire(ctx, key)
+				if err != nil {
+					return
+				}
+				data, err = s.fetchChainActivity(ctx, address, chainConfig)
+				release()
+				if err != nil && ctx.Err() != nil {
+					return
+				}
+				// Partial answers are used but not cached
+				if err == nil {
+					s.chainActivity.put(address, key, data)
+				}
+			}
+			if data.TxCount > 0 || data.Balance != "0" {
+				mu.Lock()
+				chainData[chainName] = data
+				mu.Unlock()
+			}
+		}(chainName, key, chainConfig)
 	}
+	wg.Wait()
 
 	return chainData
 }
 
-// fetchChainActivity queries a single chain for activity data
-func (s *Server) fetchChainActivity(ctx context.Context, address string, chain ChainConfig) ChainActivityData {
+// fetchChainActivity queries a single chain for activity data. The error is
+// the first failed RPC call; data holds whatever was read.
+func (s *Server) fetchChainActivity(ctx context.Context, address string, chain ChainConfig) (ChainActivityData, error) {
 	data := ChainActivityData{
 		Chain:   chain.Name,
 		Balance: "0",
 	}
 
 	// Query balance via JSON-RPC
-	balance, err := s.ethGetBalance(ctx, chain.RPCURL, address)
-	if err == nil {
+	balance, balanceErr := s.ethGetBalance(ctx, chain.RPCURL, address)
+	if balanceErr == nil {
 		data.Balance = balance
 	}
 
@@ -279,7 +315,10 @@ func (s *Server) fetchChainActivity(ctx context.Context, address string, chain C
 		data.DeFiInteraction = true
 	}
 
-	return data
+	if balanceErr != nil {
+		return data, balanceErr
+	}
+	return data, err
 }
 
 // ethGetBalance calls eth_getBalance
@@ -338,21 +377,25 @@ func (s *Server) ethGetTransactionCount(ctx context.Context, rpcURL, address str
 	return int(count.Int64()), nil
 }
 
-// makeRPCCall performs a JSON-RPC request
+// crossChainRetry retries JSON-RPC reads that fail on the network, 429 or 5xx
+var crossChainRetry = retry.DefaultPolicy()
+
+// makeRPCCall performs a read-only JSON-RPC request
 func (s *Server) makeRPCCall(ctx context.Context, rpcURL string, payload interface{}) (map[string]interface{}, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", rpcURL, strings.NewReader(string(body)))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
 	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := retry.DoHTTP(ctx, crossChainRetry, client, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", rpcURL, strings.NewReader(string(body)))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -366,49 +409,72 @@ func (s *Server) makeRPCCall(ctx context.Context, rpcURL string, payload interfa
 	return result, nil
 }
 
-// verifyAction verifies a specific on-chain action
+// verifyAction verifies a specific on-chain action. Swaps, bridges, mints and
+// stakes are verified from event logs emitted through known contracts (see
+// ActionContracts); a transaction count alone proves only "any_tx".
 func (s *Server) verifyAction(ctx context.Context, req VerifyActionRequest, chain ChainConfig) (bool, string, time.Time, string) {
+	action := canonicalAction(req.Action)
+	chainKey := strings.ToLower(req.Chain)
+
 	// If tx hash provided, verify it directly
 	if req.TxHash != "" {
+		if actionHasEvents(action) {
+			return s.verifyActionReceipt(ctx, chain.RPCURL, chainKey, req.TxHash, req.Address, action)
+		}
 		return s.verifyTransaction(ctx, chain.RPCURL, req.TxHash, req.Address, req.Action)
 	}
 
-	// Otherwise, scan recent transactions for the action
-	txCount, _ := s.ethGetTransactionCount(ctx, chain.RPCURL, req.Address)
-	
-	switch strings.ToLower(req.Action) {
+	switch action {
 	case "any_tx", "transaction":
+		txCount, _ := s.ethGetTransactionCount(ctx, chain.RPCURL, req.Address)
 		if txCount > 0 {
-			return true, "", time.Now(), fmt.Sprintf("Address has %d transactions", txCount)
-		}
-	case "swap":
-		// For swap verification, check if user has interacted with common DEX routers
-		if txCount >= 5 {
-			return true, "", time.Now(), "Address has DeFi activity"
-		}
-	case "bridge":
-		// Bridge verification - check for specific bridge contract interactions
-		if txCount >= 3 {
-			return true, "", time.Now(), "Address has bridging activity"
-		}
-	case "mint", "nft_mint":
-		// NFT mint verification
-		if txCount >= 2 {
-			return true, "", time.Now(), "Address has minting activity"
-		}
-	case "stake", "staking":
-		// Staking verification
-		if txCount >= 5 {
-			return true, "", time.Now(), "Address has staking activity"
+			return true, "", s.clock.Now(), fmt.Sprintf("Address has %d transactions", txCount)
 		}
 	case "deploy", "contract_deploy":
 		// Contract deployment verification would need trace analysis
 		return false, "", time.Time{}, "Contract deployment requires trace analysis"
+	default:
+		if !actionHasEvents(action) {
+			break
+		}
+		// Otherwise, scan recent logs for the action
+		log, contract, found, err := s.findActionLog(ctx, chain.RPCURL, chainKey, action, req.Address)
+		if err != nil {
+			return false, "", time.Time{}, "Failed to fetch logs"
+		}
+		if found {
+			return true, log.TransactionHash, s.clock.Now(), fmt.Sprintf("%s verified via %s", action, contract.Protocol)
+		}
+		return false, "", time.Time{}, fmt.Sprintf("No %s through a known contract in the last %d blocks", action, actionScanBlocks)
 	}
 
 	return false, "", time.Time{}, "Could not verify action"
 }
 
+// verifyActionReceipt verifies that txHash was sent by expectedFrom and emitted
+// an event for action through a known contract
+func (s *Server) verifyActionReceipt(ctx context.Context, rpcURL, chainKey, txHash, expectedFrom, action string) (bool, string, time.Time, string) {
+	receipt, err := s.ethGetReceipt(ctx, rpcURL, txHash)
+	if err != nil {
+		return false, txHash, time.Time{}, "Failed to fetch transaction receipt"
+	}
+	if receipt == nil {
+		return false, txHash, time.Time{}, "Transaction not found"
+	}
+	if !strings.EqualFold(receipt.From, expectedFrom) {
+		return false, txHash, time.Time{}, "Transaction sender does not match"
+	}
+	if receipt.Status != "0x1" {
+		return false, txHash, time.Time{}, "Transaction failed"
+	}
+
+	ev, contract, ok := matchActionReceipt(receipt, chainKey, action, expectedFrom)
+	if !ok {
+		return false, txHash, time.Time{}, fmt.Sprintf("Transaction has no %s event from a known contract", action)
+	}
+	return true, txHash, s.clock.Now(), fmt.Sprintf("Transaction verified: %s via %s (%s)", action, contract.Protocol, ev.Name)
+}
+
 // verifyTransaction verifies a specific transaction exists and matches criteria
 func (s *Server) verifyTransaction(ctx context.Context, rpcURL, txHash, expectedFrom, action string) (bool, string, time.Time, string) {
 	payload := map[string]interface{}{
@@ -434,11 +500,11 @@ func (s *Server) verifyTransaction(ctx context.Context, rpcURL, txHash, expected
 		return false, txHash, time.Time{}, "Transaction sender does not match"
 	}
 
-	return true, txHash, time.Now(), fmt.Sprintf("Transaction verified: %s", action)
+	return true, txHash, s.clock.Now(), fmt.Sprintf("Transaction verified: %s", action)
 }
 
 // calculateCrossChainBonus calculates bonus points for multi-chain activity
-func calculateCrossChainBonus(chainData map[string]ChainActivityData) int {
+func calculateCrossChainBonus(w CrossChainScoreWeights, chainData map[string]ChainActivityData) int {
 	bonus := 0
 	activeChains := 0
 
@@ -447,29 +513,29 @@ func calculateCrossChainBonus(chainData map[string]ChainActivityData) int {
 			activeChains++
 			
 			// Activity-based bonus per chain
-			if data.TxCount >= 10 {
-				bonus += 5 // High activity
-			} else if data.TxCount >= 3 {
-				bonus += 3 // Medium activity
+			if data.TxCount >= w.HighActivityTxs {
+				bonus += w.HighActivity
+			} else if data.TxCount >= w.MediumActivityTxs {
+				bonus += w.MediumActivity
 			} else {
-				bonus += 1 // Low activity
+				bonus += w.LowActivity
 			}
 
 			// DeFi interaction bonus
 			if data.DeFiInteraction {
-				bonus += 2
+				bonus += w.DeFi
 			}
 		}
 	}
 
 	// Multi-chain presence bonus
 	if activeChains >= 3 {
-		bonus += 10 // Active on 3+ chains
+		bonus += w.ThreeChains
 	} else if activeChains >= 2 {
-		bonus += 5 // Active on 2 chains
+		bonus += w.TwoChains
 	}
 
-	return sybilMin(bonus, 30) // Cap cross-chain bonus at 30 points
+	return sybilMin(bonus, w.Cap)
 }
 
 // countVerified counts verified actions in results
@@ -2,79 +2,40 @@ package api
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
-	"fmt"
-	"io"
+	"errors"
 	"net/http"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/bech32"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
-)
-
-// restClient is a shared HTTP client for REST/LCD queries
-var restClient = &http.Client{
-	Timeout: 10 * time.Second,
-}
 
-// getRESTBaseURL returns the base URL for Cosmos REST/LCD API
-// Uses COSMOS_REST_URL env var if set, otherwise defaults to localhost
-func getRESTBaseURL() string {
-	if url := os.Getenv("COSMOS_REST_URL"); url != "" {
-		return strings.TrimSuffix(url, "/")
-	}
-	return "http://localhost:1317"
-}
-
-// getRPCBaseURL returns the base URL for CometBFT RPC API
-// Uses COSMOS_RPC_URL env var if set, otherwise defaults to localhost
-func getRPCBaseURL() string {
-	if url := os.Getenv("COSMOS_RPC_URL"); url != "" {
-		return strings.TrimSuffix(url, "/")
-	}
-	return "http://localhost:26657"
-}
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
 
 // NOTE: These endpoints are primarily for the web UX. They intentionally:
 // - accept both `cert1...` (bech32) and `0x...` (EVM hex) addresses
 // - aggregate wallet + staking + attestation stats for the User Dashboard
 // - default to TESTNET semantics (tokens have no real-world value)
 
-type walletBalanceResult struct {
-	Balances []struct {
-		Denom  string `json:"denom"`
-		Amount string `json:"amount"`
-	} `json:"balances"`
-}
-
-type stakingDelegationsResult struct {
-	DelegationResponses []struct {
-		Delegation struct {
-			DelegatorAddress string `json:"delegator_address"`
-			ValidatorAddress string `json:"validator_address"`
-		} `json:"delegation"`
-		Balance struct {
-			Denom  string `json:"denom"`
-			Amount string `json:"amount"`
-		} `json:"balance"`
-	} `json:"delegation_responses"`
+type attestationListResult struct {
+	Attestations []attestationListItem `json:"attestations"`
 }
 
-type attestationListResult struct {
-	Attestations []struct {
-		UID             string `json:"uid"`
-		SchemaUID       string `json:"schema_uid"`
-		Attester        string `json:"attester"`
-		Recipient       string `json:"recipient"`
-		Time            string `json:"time"`
-		AttestationType string `json:"attestation_type"`
-	} `json:"attestations"`
+// attestationListItem is an attestation as listed by the certd CLI
+type attestationListItem struct {
+	UID             string   `json:"uid"`
+	SchemaUID       string   `json:"schema_uid"`
+	Attester        string   `json:"attester"`
+	Recipient       string   `json:"recipient"`
+	Time            string   `json:"time"`
+	AttestationType string   `json:"attestation_type"`
+	RevocationTime  string   `json:"revocation_time"`
+	Tags            []string `json:"tags"`
 }
 
 type DashboardSummaryResponse struct {
@@ -82,11 +43,14 @@ type DashboardSummaryResponse struct {
 	Bech32Address string `json:"bech32_address"`
 
 	Wallet struct {
-		BalanceUcert string `json:"balance_ucert"`
+		BalanceUcert  string `json:"balance_ucert"`
+		Balance       string `json:"balance"`
+		BalanceSource string `json:"balance_source,omitempty"`
 	} `json:"wallet"`
 
 	Staking struct {
 		StakedUcert string  `json:"staked_ucert"`
+		Staked      string  `json:"staked"`
 		ApyPercent  float64 `json:"apy_percent"`
 	} `json:"staking"`
 
@@ -107,6 +71,9 @@ type WalletBalanceResponse struct {
 	Bech32Address string `json:"bech32_address"`
 	Denom         string `json:"denom"`
 	BalanceUcert  string `json:"balance_ucert"`
+	Balance       string `json:"balance"`
+	DisplayDenom  string `json:"display_denom"`
+	BalanceSource string `json:"balance_source,omitempty"`
 }
 
 type StakingDelegationsResponse struct {
@@ -141,9 +108,13 @@ func (s *Server) handleGetWalletBalance(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	balanceUcert, err := s.queryWalletBalanceUcert(bech32Addr)
+	balanceUcert, source, err := s.walletBalanceWithSource(r.Context(), bech32Addr)
 	if err != nil {
 		s.logger.Warn("wallet balance query failed", zap.String("address", bech32Addr), zap.Error(err))
+		if errors.Is(err, ErrStateVersionUnavailable) {
+			s.respondStateUnavailable(w, "Wallet balance is temporarily unavailable, retry shortly")
+			return
+		}
 		s.respondError(w, http.StatusBadGateway, "Failed to query wallet balance")
 		return
 	}
@@ -151,8 +122,11 @@ func (s *Server) handleGetWalletBalance(w http.ResponseWriter, r *http.Request)
 	s.respondJSON(w, http.StatusOK, WalletBalanceResponse{
 		Address:       address,
 		Bech32Address: bech32Addr,
-		Denom:         "ucert",
+		Denom:         s.denom.Base,
 		BalanceUcert:  balanceUcert,
+		Balance:       s.denom.FormatDisplayString(balanceUcert),
+		DisplayDenom:  s.denom.Symbol,
+		BalanceSource: source,
 	})
 }
 
@@ -180,12 +154,12 @@ func (s *Server) handleGetStakingDelegations(w http.ResponseWriter, r *http.Requ
 	var out StakingDelegationsResponse
 	out.Address = address
 	out.Bech32Address = bech32Addr
-	out.BondDenom = "ucert"
+	out.BondDenom = s.denom.Base
 	out.TotalStakedUcert = "0"
 
 	total := int64(0)
 	for _, dr := range res.DelegationResponses {
-		if dr.Balance.Denom != "ucert" {
+		if dr.Balance.Denom != s.denom.Base {
 			continue
 		}
 		amt, _ := strconv.ParseInt(dr.Balance.Amount, 10, 64)
@@ -246,7 +220,7 @@ func (s *Server) handleGetDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	balanceUcert, balErr := s.queryWalletBalanceUcert(bech32Addr)
+	balanceUcert, balanceSource, balErr := s.walletBalanceWithSource(r.Context(), bech32Addr)
 	stakedUcert, stakeErr := s.queryTotalStakedUcert(bech32Addr)
 	received, recvErr := s.queryAttestationsByRecipient(bech32Addr)
 	issued, issErr := s.queryAttestationsByAttester(bech32Addr)
@@ -268,7 +242,10 @@ func (s *Server) handleGetDashboard(w http.ResponseWriter, r *http.Request) {
 	resp.Address = address
 	resp.Bech32Address = bech32Addr
 	resp.Wallet.BalanceUcert = safeString(balanceUcert)
+	resp.Wallet.Balance = s.denom.FormatDisplayString(resp.Wallet.BalanceUcert)
+	resp.Wallet.BalanceSource = balanceSource
 	resp.Staking.StakedUcert = safeString(stakedUcert)
+	resp.Staking.Staked = s.denom.FormatDisplayString(resp.Staking.StakedUcert)
 	resp.Staking.ApyPercent = s.getTestnetAPYPercent()
 	resp.Attestations.ReceivedCount = len(received)
 	resp.Attestations.IssuedCount = len(issued)
@@ -297,128 +274,64 @@ func (s *Server) getTestnetAPYPercent() float64 {
 	return 10.0
 }
 
+// queryWalletBalanceUcert queries the ucert balance over the node's gRPC. Queries that
+// hit the SDK state-versioning issue are retried at the latest committed height;
+// if that still fails a StateVersionError is returned rather than a zero balance.
 func (s *Server) queryWalletBalanceUcert(bech32Addr string) (string, error) {
-	// Due to IAVL v1.x bug in Cosmos SDK v0.50.x, state queries fail with
-	// "version does not exist" error. Use database fallback for faucet balances.
-
-	// Try database fallback first (tracks faucet disbursements)
-	if s.db != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		faucetBal, err := s.db.GetFaucetBalance(ctx, bech32Addr)
-		if err == nil && faucetBal > 0 {
-			return fmt.Sprintf("%d", faucetBal), nil
-		}
-	}
-
-	// Cosmos SDK v0.50.x has a race condition where querying at the latest height
-	// often fails with "version does not exist". Workaround: query at height - 1
-
-	// First get current height
-	heightURL := fmt.Sprintf("%s/status", getRPCBaseURL())
-	heightResp, err := restClient.Get(heightURL)
-	if err != nil {
-		return "0", nil
-	}
-	defer heightResp.Body.Close()
-
-	heightBody, _ := io.ReadAll(heightResp.Body)
-	var statusRes struct {
-		Result struct {
-			SyncInfo struct {
-				LatestBlockHeight string `json:"latest_block_height"`
-			} `json:"sync_info"`
-		} `json:"result"`
-	}
-	if err := json.Unmarshal(heightBody, &statusRes); err != nil {
-		return "0", nil
-	}
-
-	// Parse height and use height - 2 for safety margin
-	var height int64
-	fmt.Sscanf(statusRes.Result.SyncInfo.LatestBlockHeight, "%d", &height)
-	if height > 2 {
-		height -= 2
-	}
-
-	// Query balance at a past height
-	url := fmt.Sprintf("%s/cosmos/bank/v1beta1/balances/%s?height=%d",
-		getRESTBaseURL(), bech32Addr, height)
-
-	resp, err := restClient.Get(url)
-	if err != nil {
-		return "0", nil
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		// If past height also fails, return 0
-		return "0", nil
-	}
-
-	body, err := io.ReadAll(resp.Body)
+	ctx, cancel := context.WithTimeout(context.Background(), restClient.Timeout)
+	defer cancel()
+
+	var res BankBalancesResponse
+	err := retryAtLaggedHeight(ctx, s.chain.LatestHeight, func(ctx context.Context, height int64) error {
+		var err error
+		res, err = s.chain.Balances(ctx, bech32Addr, height)
+		return err
+	})
 	if err != nil {
-		return "0", nil
-	}
-
-	var res walletBalanceResult
-	if err := json.Unmarshal(body, &res); err != nil {
-		return "0", nil
-	}
-
-	for _, b := range res.Balances {
-		if b.Denom == "ucert" {
-			return b.Amount, nil
-		}
-	}
-	return "0", nil
-}
-
-// queryWalletBalanceUcertCLI is a fallback using CLI (for older SDK versions or when LCD is disabled)
-func (s *Server) queryWalletBalanceUcertCLI(bech32Addr string) (string, error) {
-	var res walletBalanceResult
-	if err := s.execCertdQueryJSON(&res, "bank", "balances", bech32Addr); err != nil {
 		return "", err
 	}
+
 	for _, b := range res.Balances {
-		if b.Denom == "ucert" {
+		if b.Denom == s.denom.Base {
 			return b.Amount, nil
 		}
 	}
 	return "0", nil
 }
 
-func (s *Server) queryStakingDelegations(bech32Addr string) (stakingDelegationsResult, error) {
-	// Use REST/LCD API (port 1317) for staking delegation queries
-	url := fmt.Sprintf("%s/cosmos/staking/v1beta1/delegations/%s", getRESTBaseURL(), bech32Addr)
-
-	resp, err := restClient.Get(url)
-	if err != nil {
-		return s.queryStakingDelegationsCLI(bech32Addr)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return s.queryStakingDelegationsCLI(bech32Addr)
+// walletBalanceWithSource queries the chain balance and, when chain state is
+// temporarily unavailable, falls back to the faucet-tracked database estimate.
+// The returned source tells clients which one answered.
+func (s *Server) walletBalanceWithSource(ctx context.Context, bech32Addr string) (string, string, error) {
+	balanceUcert, err := s.queryWalletBalanceUcert(bech32Addr)
+	if err == nil {
+		return balanceUcert, BalanceSourceGRPC, nil
 	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return s.queryStakingDelegationsCLI(bech32Addr)
+	if !errors.Is(err, ErrStateVersionUnavailable) || s.db == nil {
+		return "", BalanceSourceUnavailable, err
 	}
 
-	var res stakingDelegationsResult
-	if err := json.Unmarshal(body, &res); err != nil {
-		return s.queryStakingDelegationsCLI(bech32Addr)
+	estimate, dbErr := s.db.GetFaucetBalance(ctx, bech32Addr)
+	if dbErr != nil || estimate <= 0 {
+		return "", BalanceSourceUnavailable, err
 	}
-	return res, nil
+	return strconv.FormatInt(estimate, 10), BalanceSourceDBEstimate, nil
 }
 
-// queryStakingDelegationsCLI is a fallback using CLI
-func (s *Server) queryStakingDelegationsCLI(bech32Addr string) (stakingDelegationsResult, error) {
-	var res stakingDelegationsResult
-	if err := s.execCertdQueryJSON(&res, "staking", "delegations", bech32Addr); err != nil {
-		return stakingDelegationsResult{}, err
+// queryStakingDelegations queries a delegator's delegations over the node's
+// gRPC, retrying at a lagged height on state-versioning failures
+func (s *Server) queryStakingDelegations(bech32Addr string) (DelegationsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), restClient.Timeout)
+	defer cancel()
+
+	var res DelegationsResponse
+	err := retryAtLaggedHeight(ctx, s.chain.LatestHeight, func(ctx context.Context, height int64) error {
+		var err error
+		res, err = s.chain.Delegations(ctx, bech32Addr, height)
+		return err
+	})
+	if err != nil {
+		return DelegationsResponse{}, err
 	}
 	return res, nil
 }
@@ -430,7 +343,7 @@ func (s *Server) queryTotalStakedUcert(bech32Addr string) (string, error) {
 	}
 	total := int64(0)
 	for _, dr := range res.DelegationResponses {
-		if dr.Balance.Denom != "ucert" {
+		if dr.Balance.Denom != s.denom.Base {
 			continue
 		}
 		amt, _ := strconv.ParseInt(dr.Balance.Amount, 10, 64)
@@ -439,112 +352,99 @@ func (s *Server) queryTotalStakedUcert(bech32Addr string) (string, error) {
 	return strconv.FormatInt(total, 10), nil
 }
 
+// queryAttestationsByRecipient lists the attestations received by an address
+// over the node's gRPC, retrying at a lagged height on state-versioning failures
 func (s *Server) queryAttestationsByRecipient(bech32Addr string) ([]map[string]any, error) {
-	var res attestationListResult
-	if err := s.execCertdQueryJSON(&res, "attestation", "by-recipient", bech32Addr); err != nil {
-		return nil, err
-	}
-	return normalizeAttestations(res.Attestations), nil
+	return s.queryChainAttestations(bech32Addr, s.chain.AttestationsByRecipient)
 }
 
+// queryAttestationsByAttester lists the attestations issued by an address
+// over the node's gRPC, retrying at a lagged height on state-versioning failures
 func (s *Server) queryAttestationsByAttester(bech32Addr string) ([]map[string]any, error) {
-	var res attestationListResult
-	if err := s.execCertdQueryJSON(&res, "attestation", "by-attester", bech32Addr); err != nil {
+	return s.queryChainAttestations(bech32Addr, s.chain.AttestationsByAttester)
+}
+
+func (s *Server) queryChainAttestations(bech32Addr string, query func(ctx context.Context, address string, height int64) ([]attestationtypes.Attestation, error)) ([]map[string]any, error) {
+	var attestations []attestationtypes.Attestation
+	err := retryAtLaggedHeight(context.Background(), s.chain.LatestHeight, func(ctx context.Context, height int64) error {
+		var err error
+		attestations, err = query(ctx, bech32Addr, height)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
-	return normalizeAttestations(res.Attestations), nil
-}
 
-func normalizeAttestations(items []struct {
-	UID             string `json:"uid"`
-	SchemaUID       string `json:"schema_uid"`
-	Attester        string `json:"attester"`
-	Recipient       string `json:"recipient"`
-	Time            string `json:"time"`
-	AttestationType string `json:"attestation_type"`
-}) []map[string]any {
-	out := make([]map[string]any, 0, len(items))
-	for _, a := range items {
-		attester := normalizeMaybeAddress(a.Attester)
-		recipient := normalizeMaybeAddress(a.Recipient)
-		out = append(out, map[string]any{
-			"uid":       a.UID,
-			"schema":    a.SchemaUID,
-			"issuer":    attester,
-			"recipient": recipient,
-			"time":      a.Time,
-			"encrypted": a.AttestationType != "public" && a.AttestationType != "",
-			"type":      a.AttestationType,
+	items := make([]attestationListItem, 0, len(attestations))
+	for _, a := range attestations {
+		items = append(items, attestationListItem{
+			UID:             a.UID,
+			SchemaUID:       a.SchemaUID,
+			Attester:        certAddress(a.Attester),
+			Recipient:       certAddress(a.Recipient),
+			Time:            a.Time.UTC().Format(time.RFC3339Nano),
+			AttestationType: a.AttestationType,
+			RevocationTime:  a.RevocationTime.UTC().Format(time.RFC3339Nano),
+			Tags:            a.Tags,
 		})
 	}
-	return out
+	return normalizeAttestations(items), nil
 }
 
-func normalizeMaybeAddress(s string) string {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return s
-	}
-	if strings.HasPrefix(s, "cert1") || strings.HasPrefix(s, "0x") {
-		return s
+// certAddress renders account address bytes as cert1... bech32; empty stays empty
+func certAddress(addr sdk.AccAddress) string {
+	if len(addr) == 0 {
+		return ""
 	}
-	// Many Cosmos CLI JSON outputs encode address bytes as base64. Best-effort decode.
-	decoded, err := base64.StdEncoding.DecodeString(s)
+	bech, err := bech32.ConvertAndEncode("cert", addr)
 	if err != nil {
-		return s
-	}
-	if len(decoded) != 20 {
-		return s
-	}
-	bech, err := bech32.ConvertAndEncode("cert", decoded)
-	if err != nil {
-		return s
+		return ""
 	}
 	return bech
 }
 
-func (s *Server) execCertdQueryJSON(out any, queryArgs ...string) error {
-	// Run inside docker: `certd query <module> <subcommand> [args...] --flags`
-	//
-	// NOTE: certd module queries (notably our attestation module) must use gRPC.
-	// The legacy `--node` path can fail with "unknown query path" once modules
-	// are served exclusively via gRPC Query services.
-	//
-	// Command structure: certd query <queryArgs...> <flags>
-	// Flags must come AFTER subcommands for Cosmos SDK CLI.
-	args := []string{"query"}
-
-	// Append query args first (module, subcommand, address, etc.)
-	args = append(args, queryArgs...)
-
-	// Then append connection flags
-	useGRPC := len(queryArgs) > 0 && queryArgs[0] == "attestation"
-	if useGRPC {
-		args = append(args, "--grpc-addr", "localhost:9090", "--grpc-insecure")
-	} else {
-		args = append(args, "--node", "tcp://localhost:26657")
-	}
-
-	// Always request JSON output.
-	args = append(args, "-o", "json")
-
-	cmd := exec.Command("docker", append([]string{"exec", "certd", "certd"}, args...)...)
-	buf, err := cmd.CombinedOutput()
+// queryAttestationList runs an attestation list query, retrying at a lagged
+// height on state-versioning failures
+func (s *Server) queryAttestationList(subcommand string, args ...string) ([]map[string]any, error) {
+	var res attestationListResult
+	args = append([]string{"attestation", subcommand}, args...)
+	err := retryAtLaggedHeight(context.Background(), s.chain.LatestHeight, func(ctx context.Context, height int64) error {
+		res = attestationListResult{}
+		return s.chain.Query(ctx, &res, height, args...)
+	})
 	if err != nil {
-		return fmt.Errorf("certd query failed: %w: %s", err, string(buf))
+		return nil, err
 	}
+	return normalizeAttestations(res.Attestations), nil
+}
 
-	// certd can print extra lines; extract first JSON object.
-	var raw json.RawMessage
-	if v, ok := extractFirstJSONObject[json.RawMessage](string(buf)); ok {
-		raw = v
-	} else {
-		raw = json.RawMessage(strings.TrimSpace(string(buf)))
+func normalizeAttestations(items []attestationListItem) []map[string]any {
+	out := make([]map[string]any, 0, len(items))
+	for _, a := range items {
+		item := map[string]any{
+			"uid":       a.UID,
+			"schema":    a.SchemaUID,
+			"issuer":    strings.TrimSpace(a.Attester),
+			"recipient": strings.TrimSpace(a.Recipient),
+			"time":      normalizeTimestamp(a.Time),
+			"encrypted": a.AttestationType != "public" && a.AttestationType != "",
+			"type":      a.AttestationType,
+			"revoked":   isRevokedTimestamp(a.RevocationTime),
+		}
+		if len(a.Tags) > 0 {
+			item["tags"] = a.Tags
+		}
+		out = append(out, item)
 	}
+	return out
+}
 
-	if err := json.Unmarshal(raw, out); err != nil {
-		return fmt.Errorf("failed to decode certd json: %w", err)
-	}
+func (s *Server) execCertdQueryJSON(out any, queryArgs ...string) error {
+	return s.execCertdQueryJSONAtHeight(out, 0, queryArgs...)
+}
 
-	return nil
+// execCertdQueryJSONAtHeight runs a certd query pinned to height (0 for latest).
+// State-versioning failures are returned as a StateVersionError.
+func (s *Server) execCertdQueryJSONAtHeight(out any, height int64, queryArgs ...string) error {
+	return s.chain.Query(context.Background(), out, height, queryArgs...)
 }
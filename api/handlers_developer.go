@@ -5,7 +5,6 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"net/http"
 
 	"github.com/chaincertify/certd/api/database"
@@ -85,8 +84,8 @@ func (s *Server) handleCreateApiKey(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Name string `json:"name"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBodyStrict(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 
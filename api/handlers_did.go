@@ -20,8 +20,8 @@ type DIDDocument struct {
 	Authentication     []interface{}        `json:"authentication"`
 	AssertionMethod    []interface{}        `json:"assertionMethod,omitempty"`
 	Service            []ServiceEndpoint    `json:"service,omitempty"`
-	Created            *time.Time           `json:"created,omitempty"`
-	Updated            *time.Time           `json:"updated,omitempty"`
+	Created            *Timestamp           `json:"created,omitempty"`
+	Updated            *Timestamp           `json:"updated,omitempty"`
 }
 
 // VerificationMethod represents a public key or verification method
@@ -160,7 +160,7 @@ func buildDIDDocument(address string) DIDDocument {
 	}
 
 	// Add creation/update timestamps
-	now := time.Now()
+	now := NewTimestamp(time.Now())
 	doc.Created = &now
 	doc.Updated = &now
 
@@ -183,8 +183,8 @@ func (s *Server) handleResolveDID(w http.ResponseWriter, r *http.Request) {
 			"contentType": "application/did+json",
 		},
 		"didDocumentMetadata": map[string]interface{}{
-			"created": time.Now(),
-			"updated": time.Now(),
+			"created": s.clock.Now(),
+			"updated": s.clock.Now(),
 		},
 	})
 }
@@ -210,7 +210,7 @@ func (s *Server) handleGetDIDVerifiablePresentation(w http.ResponseWriter, r *ht
 				"@context":     []string{"https://www.w3.org/2018/credentials/v1"},
 				"type":         []string{"VerifiableCredential", "CertIDCredential"},
 				"issuer":       "did:web:c3rt.org",
-				"issuanceDate": time.Now().Format(time.RFC3339),
+				"issuanceDate": s.clock.Now().Format(time.RFC3339),
 				"credentialSubject": map[string]interface{}{
 					"id":           "did:web:c3rt.org:identity:" + address,
 					"certidProfile": "https://c3rt.org/identity/" + address,
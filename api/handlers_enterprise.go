@@ -1,7 +1,6 @@
 package api
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -35,7 +34,7 @@ func (s *Server) handleEnterpriseContact(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req enterpriseContactRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(w, r, &req); err != nil {
 		s.respondJSON(w, http.StatusBadRequest, enterpriseContactResponse{
 			Error: "Invalid request body",
 		})
@@ -68,7 +67,7 @@ func (s *Server) handleEnterpriseContact(w http.ResponseWriter, r *http.Request)
 		zap.String("email", req.Email),
 		zap.String("company", req.Company),
 		zap.String("useCase", req.UseCase),
-		zap.Time("timestamp", time.Now()),
+		zap.Time("timestamp", s.clock.Now()),
 	)
 
 	// Store in database if available
@@ -89,7 +88,7 @@ func (s *Server) handleEnterpriseContact(w http.ResponseWriter, r *http.Request)
 			"**Message:** %s\n"+
 			"**Time:** %s",
 		req.Name, req.Email, req.Company, req.UseCase, req.Message,
-		time.Now().Format(time.RFC3339),
+		s.clock.Now().Format(time.RFC3339),
 	)
 
 	// Log for email notification (in production, send actual email)
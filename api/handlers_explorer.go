@@ -7,14 +7,13 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -44,6 +43,7 @@ type TransactionResponse struct {
 	GasPrice      string                 `json:"gas_price"`
 	TxFee         string                 `json:"tx_fee"`
 	InputData     string                 `json:"input_data"`
+	Type          string                 `json:"type"` // Transfer, Chain Certify, Cert ID or Transaction
 	EcosystemType string                 `json:"ecosystem_type"` // ChainCertify, CertID, Standard
 	CertHash      string                 `json:"cert_hash,omitempty"`
 	Metadata      string                 `json:"metadata,omitempty"`
@@ -87,6 +87,7 @@ type AddressResponse struct {
 	Address       string  `json:"address"`
 	Label         string  `json:"label,omitempty"`
 	Balance       string  `json:"balance"`
+	BalanceSource string  `json:"balance_source,omitempty"` // grpc, rest, db_estimate or unavailable
 	BalanceUSD    float64 `json:"balance_usd,omitempty"`
 	TxCount       int64   `json:"tx_count"`
 	IsContract    bool    `json:"is_contract"`
@@ -114,6 +115,9 @@ func (s *Server) handleGetTransaction(w http.ResponseWriter, r *http.Request) {
 	if s.db != nil {
 		tx, err := s.db.GetTransaction(ctx, txHash)
 		if err == nil && tx != nil {
+			if !s.isFinalized(tx.BlockNumber, s.getCurrentBlockHeight(ctx)) {
+				cacheResponseFor(r, s.config.Cache.UnfinalizedTTL)
+			}
 			s.respondJSON(w, http.StatusOK, tx)
 			return
 		}
@@ -135,64 +139,39 @@ func (s *Server) handleGetTransaction(w http.ResponseWriter, r *http.Request) {
 		s.enrichAddressLabels(ctx, txData)
 	}
 
+	// Recently included txs are re-fetched soon so their confirmations stay current
+	if txData.Confirmations <= s.config.Cache.FinalizedConfirmations {
+		cacheResponseFor(r, s.config.Cache.UnfinalizedTTL)
+	}
 	s.respondJSON(w, http.StatusOK, txData)
 }
 
 // fetchTransactionFromRPC queries the Tendermint RPC for transaction data
 func (s *Server) fetchTransactionFromRPC(ctx context.Context, txHash string) (*TransactionResponse, error) {
-	// Query Tendermint RPC
-	rpcURL := fmt.Sprintf("%s/tx?hash=%s", s.config.ChainRPCURL, txHash)
-	req, _ := http.NewRequestWithContext(ctx, "GET", rpcURL, nil)
-	resp, err := http.DefaultClient.Do(req)
+	tx, err := s.chain.Tx(ctx, txHash)
 	if err != nil {
 		return nil, fmt.Errorf("RPC request failed: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("RPC returned status %d", resp.StatusCode)
-	}
-
-	body, _ := io.ReadAll(resp.Body)
-	var rpcResult struct {
-		Result struct {
-			Hash     string `json:"hash"`
-			Height   string `json:"height"`
-			Index    int    `json:"index"`
-			TxResult struct {
-				Code      int    `json:"code"`
-				Data      string `json:"data"`
-				Log       string `json:"log"`
-				GasWanted string `json:"gas_wanted"`
-				GasUsed   string `json:"gas_used"`
-			} `json:"tx_result"`
-			Tx string `json:"tx"`
-		} `json:"result"`
-	}
-
-	if err := json.Unmarshal(body, &rpcResult); err != nil {
-		return nil, fmt.Errorf("failed to parse RPC response: %w", err)
-	}
+	return s.transactionFromChainTx(tx, s.getCurrentBlockHeight(ctx)), nil
+}
 
-	height, _ := strconv.ParseInt(rpcResult.Result.Height, 10, 64)
-	gasWanted, _ := strconv.ParseInt(rpcResult.Result.TxResult.GasWanted, 10, 64)
-	gasUsed, _ := strconv.ParseInt(rpcResult.Result.TxResult.GasUsed, 10, 64)
+// transactionFromChainTx decodes an RPC transaction result; currentHeight gives its confirmations
+func (s *Server) transactionFromChainTx(tx ChainTx, currentHeight int64) *TransactionResponse {
+	height, gasWanted, gasUsed := tx.Height, tx.GasWanted, tx.GasUsed
 
 	// Determine status
 	status := "success"
-	if rpcResult.Result.TxResult.Code != 0 {
+	if tx.Code != 0 {
 		status = "failed"
 	}
 
-	// Get current height for confirmations
-	currentHeight := s.getCurrentBlockHeight(ctx)
 	confirmations := int64(0)
 	if currentHeight > height {
 		confirmations = currentHeight - height
 	}
 
 	// Decode transaction bytes to extract from/to/value
-	txBytes, _ := hex.DecodeString(rpcResult.Result.Tx)
+	txBytes, _ := hex.DecodeString(tx.Tx)
 	from, to, value, inputData := s.decodeTxPayload(txBytes)
 
 	// Calculate fee (gasUsed * gasPrice)
@@ -200,7 +179,7 @@ func (s *Server) fetchTransactionFromRPC(ctx context.Context, txHash string) (*T
 	txFee := gasUsed * gasPrice
 
 	return &TransactionResponse{
-		Hash:          "0x" + rpcResult.Result.Hash,
+		Hash:          "0x" + tx.Hash,
 		Status:        status,
 		BlockNumber:   height,
 		Confirmations: confirmations,
@@ -212,8 +191,32 @@ func (s *Server) fetchTransactionFromRPC(ctx context.Context, txHash string) (*T
 		GasPrice:      fmt.Sprintf("%d", gasPrice),
 		TxFee:         fmt.Sprintf("%d", txFee),
 		InputData:     inputData,
+		Type:          classifyTxLog(tx.Log),
 		EcosystemType: "Standard",
-	}, nil
+	}
+}
+
+// classifyTxLog labels a transaction by the module its result log mentions
+func classifyTxLog(log string) string {
+	switch {
+	case strings.Contains(log, "certify"):
+		return "Chain Certify"
+	case strings.Contains(log, "certid"):
+		return "Cert ID"
+	case strings.Contains(log, "send") || strings.Contains(log, "transfer"):
+		return "Transfer"
+	}
+	return "Transaction"
+}
+
+// blockTxHash returns the uppercase hex SHA-256 hash of a base64 block transaction
+func blockTxHash(txBase64 string) (string, error) {
+	txBytes, err := base64.StdEncoding.DecodeString(txBase64)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(txBytes)
+	return strings.ToUpper(hex.EncodeToString(hash[:])), nil
 }
 
 // decodeTxPayload extracts transaction fields from raw bytes
@@ -232,24 +235,7 @@ func (s *Server) decodeTxPayload(txBytes []byte) (from, to, value, inputData str
 
 // getCurrentBlockHeight fetches the current block height from RPC
 func (s *Server) getCurrentBlockHeight(ctx context.Context) int64 {
-	rpcURL := fmt.Sprintf("%s/status", s.config.ChainRPCURL)
-	req, _ := http.NewRequestWithContext(ctx, "GET", rpcURL, nil)
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return 0
-	}
-	defer resp.Body.Close()
-
-	var result struct {
-		Result struct {
-			SyncInfo struct {
-				LatestBlockHeight string `json:"latest_block_height"`
-			} `json:"sync_info"`
-		} `json:"result"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	json.Unmarshal(body, &result)
-	height, _ := strconv.ParseInt(result.Result.SyncInfo.LatestBlockHeight, 10, 64)
+	height, _ := s.chain.LatestHeight(ctx)
 	return height
 }
 
@@ -264,7 +250,7 @@ func (s *Server) enrichTransactionData(tx *TransactionResponse) {
 	// Check if interacting with Chain Certify contract
 	if toAddr == strings.ToLower(ChainCertifyContract) {
 		tx.EcosystemType = "ChainCertify"
-		tx.DecodedParams = s.decodeChainCertifyCall(tx.InputData)
+		tx.DecodedParams = decodeContractCall(toAddr, tx.InputData)
 		if hash, ok := tx.DecodedParams["docHash"].(string); ok {
 			tx.CertHash = hash
 		}
@@ -276,77 +262,16 @@ func (s *Server) enrichTransactionData(tx *TransactionResponse) {
 	// Check if interacting with Cert ID contract
 	if toAddr == strings.ToLower(CertIDContract) {
 		tx.EcosystemType = "CertID"
-		tx.DecodedParams = s.decodeCertIDCall(tx.InputData)
+		tx.DecodedParams = decodeContractCall(toAddr, tx.InputData)
 	}
 
 	// Check if Cert Token contract
 	if toAddr == strings.ToLower(CertTokenContract) {
 		tx.EcosystemType = "CertToken"
-		tx.DecodedParams = s.decodeTokenCall(tx.InputData)
+		tx.DecodedParams = decodeContractCall(toAddr, tx.InputData)
 	}
 }
 
-// decodeChainCertifyCall decodes Chain Certify contract method calls
-func (s *Server) decodeChainCertifyCall(inputData string) map[string]interface{} {
-	params := make(map[string]interface{})
-	if len(inputData) < 10 {
-		return params
-	}
-
-	methodSig := inputData[:10]
-	switch methodSig {
-	case "0x1cf71a93": // issueCertificate(address,string,string)
-		params["method"] = "issueCertificate"
-		// TODO: Proper ABI decoding
-		if len(inputData) > 138 {
-			params["recipient"] = "0x" + inputData[34:74]
-		}
-	case "0x3ccfd60b": // verifyDocument(string)
-		params["method"] = "verifyDocument"
-	default:
-		params["method"] = "unknown"
-	}
-	return params
-}
-
-// decodeCertIDCall decodes Cert ID contract method calls
-func (s *Server) decodeCertIDCall(inputData string) map[string]interface{} {
-	params := make(map[string]interface{})
-	if len(inputData) < 10 {
-		return params
-	}
-
-	methodSig := inputData[:10]
-	switch methodSig {
-	case "0x2e1a7d4d": // registerIdentity(string,address)
-		params["method"] = "registerIdentity"
-	default:
-		params["method"] = "unknown"
-	}
-	return params
-}
-
-// decodeTokenCall decodes Cert Token contract method calls
-func (s *Server) decodeTokenCall(inputData string) map[string]interface{} {
-	params := make(map[string]interface{})
-	if len(inputData) < 10 {
-		return params
-	}
-
-	methodSig := inputData[:10]
-	switch methodSig {
-	case "0xa9059cbb": // transfer(address,uint256)
-		params["method"] = "transfer"
-	case "0x40c10f19": // mint(address,uint256)
-		params["method"] = "mint"
-	case "0x42966c68": // burn(uint256)
-		params["method"] = "burn"
-	default:
-		params["method"] = "unknown"
-	}
-	return params
-}
-
 // enrichAddressLabels looks up Cert ID labels for addresses
 func (s *Server) enrichAddressLabels(ctx context.Context, tx *TransactionResponse) {
 	if tx.From != "" {
@@ -369,69 +294,32 @@ func (s *Server) handleGetBlock(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	var rpcURL string
-	if strings.HasPrefix(heightOrHash, "0x") || len(heightOrHash) == 64 {
-		rpcURL = fmt.Sprintf("%s/block_by_hash?hash=%s", s.config.ChainRPCURL, heightOrHash)
-	} else {
-		rpcURL = fmt.Sprintf("%s/block?height=%s", s.config.ChainRPCURL, heightOrHash)
-	}
-
-	req, _ := http.NewRequestWithContext(ctx, "GET", rpcURL, nil)
-	resp, err := http.DefaultClient.Do(req)
+	chainBlock, err := s.chain.Block(ctx, heightOrHash)
 	if err != nil {
 		s.respondError(w, http.StatusInternalServerError, "Failed to fetch block")
 		return
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	var rpcResult struct {
-		Result struct {
-			BlockID struct {
-				Hash string `json:"hash"`
-			} `json:"block_id"`
-			Block struct {
-				Header struct {
-					Height          string `json:"height"`
-					Time            string `json:"time"`
-					ProposerAddress string `json:"proposer_address"`
-				} `json:"header"`
-				Data struct {
-					Txs []string `json:"txs"`
-				} `json:"data"`
-			} `json:"block"`
-		} `json:"result"`
-	}
-
-	if err := json.Unmarshal(body, &rpcResult); err != nil {
-		s.respondError(w, http.StatusInternalServerError, "Failed to parse block data")
-		return
-	}
 
-	height, _ := strconv.ParseInt(rpcResult.Result.Block.Header.Height, 10, 64)
-	blockTime := rpcResult.Result.Block.Header.Time
+	blockTime := normalizeTimestamp(chainBlock.Time)
 	block := BlockResponse{
-		Height:    height,
-		Hash:      rpcResult.Result.BlockID.Hash,
+		Height:    chainBlock.Height,
+		Hash:      chainBlock.Hash,
 		Time:      blockTime,
 		Timestamp: blockTime,
-		TxCount:   len(rpcResult.Result.Block.Data.Txs),
-		Proposer:  rpcResult.Result.Block.Header.ProposerAddress,
-		TxHashes:  rpcResult.Result.Block.Data.Txs,
+		TxCount:   len(chainBlock.Txs),
+		Proposer:  chainBlock.Proposer,
+		TxHashes:  chainBlock.Txs,
 	}
 
 	// Fetch transaction details for each tx in the block
-	if len(rpcResult.Result.Block.Data.Txs) > 0 {
-		block.Transactions = make([]BlockTransaction, 0, len(rpcResult.Result.Block.Data.Txs))
-		for _, txBase64 := range rpcResult.Result.Block.Data.Txs {
+	if len(chainBlock.Txs) > 0 {
+		block.Transactions = make([]BlockTransaction, 0, len(chainBlock.Txs))
+		for _, txBase64 := range chainBlock.Txs {
 			// Decode base64 tx to get the hash
-			txBytes, err := base64.StdEncoding.DecodeString(txBase64)
+			txHash, err := blockTxHash(txBase64)
 			if err != nil {
 				continue
 			}
-			// Calculate tx hash (SHA256)
-			txHashBytes := sha256.Sum256(txBytes)
-			txHash := strings.ToUpper(hex.EncodeToString(txHashBytes[:]))
 
 			// Try to get tx details from block_results
 			txInfo := BlockTransaction{
@@ -440,38 +328,22 @@ func (s *Server) handleGetBlock(w http.ResponseWriter, r *http.Request) {
 				Success: true,
 			}
 
-			// Query tx_search to get more details
-			txSearchURL := fmt.Sprintf("%s/tx?hash=0x%s", s.config.ChainRPCURL, txHash)
-			txReq, _ := http.NewRequestWithContext(ctx, "GET", txSearchURL, nil)
-			txResp, err := http.DefaultClient.Do(txReq)
-			if err == nil {
-				defer txResp.Body.Close()
-				txBody, _ := io.ReadAll(txResp.Body)
-				var txResult struct {
-					Result struct {
-						TxResult struct {
-							Code int    `json:"code"`
-							Log  string `json:"log"`
-						} `json:"tx_result"`
-					} `json:"result"`
-				}
-				if json.Unmarshal(txBody, &txResult) == nil {
-					txInfo.Success = txResult.Result.TxResult.Code == 0
-					// Try to extract tx type from log
-					if strings.Contains(txResult.Result.TxResult.Log, "certify") {
-						txInfo.Type = "Chain Certify"
-					} else if strings.Contains(txResult.Result.TxResult.Log, "certid") {
-						txInfo.Type = "Cert ID"
-					} else if strings.Contains(txResult.Result.TxResult.Log, "send") || strings.Contains(txResult.Result.TxResult.Log, "transfer") {
-						txInfo.Type = "Transfer"
-					}
-				}
+			// Query the tx to get more details
+			if tx, err := s.chain.Tx(ctx, "0x"+txHash); err == nil {
+				txInfo.Success = tx.Code == 0
+				txInfo.Type = classifyTxLog(tx.Log)
 			}
 
 			block.Transactions = append(block.Transactions, txInfo)
 		}
 	}
 
+	// Blocks buried past the finality depth never change and are kept for FinalizedTTL
+	if s.isFinalized(block.Height, s.getCurrentBlockHeight(ctx)) {
+		keepResponseFor(r, s.config.Cache.FinalizedTTL)
+	} else {
+		cacheResponseFor(r, s.config.Cache.UnfinalizedTTL)
+	}
 	s.respondJSON(w, http.StatusOK, block)
 }
 
@@ -512,8 +384,10 @@ func (s *Server) handleGetAddress(w http.ResponseWriter, r *http.Request) {
 	if err == nil {
 		balance, err := s.queryAddressBalance(bech32Addr)
 		if err == nil {
-			response.Balance = balance
+			response.Balance = balance.Balance
+			response.BalanceSource = balance.Source
 		} else {
+			response.BalanceSource = BalanceSourceUnavailable
 			s.logger.Debug("failed to query balance", zap.String("address", bech32Addr), zap.Error(err))
 		}
 	}
@@ -528,66 +402,16 @@ func (s *Server) handleGetAddress(w http.ResponseWriter, r *http.Request) {
 	s.respondJSON(w, http.StatusOK, response)
 }
 
-// queryAddressBalance queries for an address's CERT balance
-// Note: Due to a known Cosmos SDK v0.50.x state versioning bug, direct blockchain
-// queries may fail with "version does not exist" errors. As a fallback, we estimate
-// balance from faucet transactions stored in the database.
-func (s *Server) queryAddressBalance(bech32Addr string) (string, error) {
-	// First try the REST API (may fail due to SDK bug)
-	url := fmt.Sprintf("http://localhost:1317/cosmos/bank/v1beta1/balances/%s", bech32Addr)
-
-	client := &http.Client{Timeout: 3 * time.Second}
-	resp, err := client.Get(url)
-	if err == nil {
-		defer resp.Body.Close()
-		if resp.StatusCode == http.StatusOK {
-			body, err := io.ReadAll(resp.Body)
-			if err == nil {
-				var result struct {
-					Balances []struct {
-						Denom  string `json:"denom"`
-						Amount string `json:"amount"`
-					} `json:"balances"`
-				}
-				if json.Unmarshal(body, &result) == nil {
-					for _, bal := range result.Balances {
-						if bal.Denom == "ucert" {
-							amount, err := strconv.ParseInt(bal.Amount, 10, 64)
-							if err == nil {
-								certAmount := float64(amount) / 1_000_000
-								return fmt.Sprintf("%.6f", certAmount), nil
-							}
-						}
-					}
-				}
-			}
-		}
-	}
-
-	// REST API failed (common SDK v0.50.x bug) - fall back to database tracking
-	// The blockchain is functioning but state queries have version mismatch issues
-	if s.db != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
-
-		faucetBalance, err := s.db.GetFaucetBalance(ctx, bech32Addr)
-		if err == nil && faucetBalance > 0 {
-			certAmount := float64(faucetBalance) / 1_000_000
-			s.logger.Debug("using database-tracked faucet balance",
-				zap.String("address", bech32Addr),
-				zap.Int64("ucert", faucetBalance))
-			return fmt.Sprintf("%.6f", certAmount), nil
-		}
-	}
-
-	s.logger.Debug("balance query fell back to 0 due to SDK state bug",
-		zap.String("address", bech32Addr))
-	return "0", nil
-}
-
 // handleSearchExplorer provides unified search across transactions, blocks, and addresses
 func (s *Server) handleSearchExplorer(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
+
+	// ?tag=diploma&tag=2024-cohort (or ?tags=diploma,2024-cohort) lists attestations carrying every tag
+	if tags := searchTags(r); len(tags) > 0 {
+		s.searchAttestationsByTags(w, r, tags)
+		return
+	}
+
 	if query == "" {
 		s.respondError(w, http.StatusBadRequest, "Search query required")
 		return
@@ -627,6 +451,52 @@ func (s *Server) handleSearchExplorer(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// searchTags collects the tag filters of a search request
+func searchTags(r *http.Request) []string {
+	var tags []string
+	params := r.URL.Query()
+	for _, values := range [][]string{params["tag"], params["tags"]} {
+		for _, v := range values {
+			for _, tag := range strings.Split(v, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					tags = append(tags, tag)
+				}
+			}
+		}
+	}
+	return tags
+}
+
+// searchAttestationsByTags responds with the attestations carrying every one of tags
+func (s *Server) searchAttestationsByTags(w http.ResponseWriter, r *http.Request, tags []string) {
+	tags, err := attestationtypes.NormalizeTags(tags)
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	args := tags
+	if includeRevoked, _ := strconv.ParseBool(r.URL.Query().Get("include_revoked")); includeRevoked {
+		args = append(args, "--include-revoked")
+	}
+	attestations, err := s.queryAttestationList("by-tag", args...)
+	if err != nil {
+		s.logger.Warn("failed to query attestations by tag", zap.Strings("tags", tags), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query attestations")
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"type":         "attestations",
+		"tags":         tags,
+		"attestations": attestations,
+	})
+}
+
+// addressTransactionsMaxDepth is how many of an address's most recent
+// transactions can be paged through
+const addressTransactionsMaxDepth = 1000
+
 // handleGetAddressTransactions returns paginated transactions for an address
 func (s *Server) handleGetAddressTransactions(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -641,17 +511,75 @@ func (s *Server) handleGetAddressTransactions(w http.ResponseWriter, r *http.Req
 		limit = 20
 	}
 
-	// For now, return empty transactions list - actual implementation would query tx_search
+	// Every page is rebuilt from the newest transactions, so deep pages cost
+	// more tx_search calls; only the most recent ones can be paged through
+	if page > addressTransactionsMaxDepth || (page-1)*limit >= addressTransactionsMaxDepth {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("page out of range: only the %d most recent transactions can be listed", addressTransactionsMaxDepth))
+		return
+	}
+
+	bech32Addr, err := toBech32Address(address)
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, "Invalid address: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	// Each query needs its newest page*limit txs for the merged page to be complete
+	want := min(page*limit, addressTransactionsMaxDepth)
+	sent, sentTotal, err := s.txSearchNewest(ctx, fmt.Sprintf("message.sender='%s'", bech32Addr), want)
+	if err != nil {
+		s.logger.Warn("Failed to search sent transactions", zap.String("address", bech32Addr), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to search transactions")
+		return
+	}
+	received, receivedTotal, err := s.txSearchNewest(ctx, fmt.Sprintf("transfer.recipient='%s'", bech32Addr), want)
+	if err != nil {
+		s.logger.Warn("Failed to search received transactions", zap.String("address", bech32Addr), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to search transactions")
+		return
+	}
+
+	merged := mergeTxSearchResults(sent, received)
+	// Self-transfers match both queries; only duplicates among the fetched
+	// results can be subtracted, so total is exact once both queries fit in one fetch
+	total := sentTotal + receivedTotal - (len(sent) + len(received) - len(merged))
+
+	latest := s.getCurrentBlockHeight(ctx)
+	txs := make([]*TransactionResponse, 0, limit)
+	for i := (page - 1) * limit; i < min(len(merged), want) && len(txs) < limit; i++ {
+		t := merged[i]
+		tx := s.transactionFromChainTx(ChainTx{
+			Hash:      strings.ToUpper(strings.TrimPrefix(t.Hash, "0x")),
+			Height:    t.Height,
+			Code:      t.Code,
+			Log:       t.Log,
+			GasWanted: t.GasWanted,
+			GasUsed:   t.GasUsed,
+			Tx:        t.Tx,
+		}, latest)
+		s.enrichTransactionData(tx)
+		txs = append(txs, tx)
+	}
+
 	s.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"address":      address,
-		"transactions": []interface{}{},
+		"address":      bech32Addr,
+		"transactions": txs,
 		"page":         page,
 		"limit":        limit,
-		"total":        0,
+		"total":        total,
+		"has_more":     want < min(total, addressTransactionsMaxDepth),
 	})
 }
 
-// handleGetRecentTransactions returns paginated recent transactions
+// recentTransactionsMaxBlocks bounds how many blocks one recent transactions page scans
+const recentTransactionsMaxBlocks = 200
+
+// handleGetRecentTransactions returns paginated recent transactions, newest first.
+// It walks back from the latest block, so pages reach at most
+// recentTransactionsMaxBlocks blocks into the past.
 func (s *Server) handleGetRecentTransactions(w http.ResponseWriter, r *http.Request) {
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 	if page < 1 {
@@ -662,30 +590,128 @@ func (s *Server) handleGetRecentTransactions(w http.ResponseWriter, r *http.Requ
 		limit = 20
 	}
 
-	// Return empty for now - actual implementation would query recent blocks
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	latest := s.getCurrentBlockHeight(ctx)
+	if latest == 0 {
+		s.respondError(w, http.StatusBadGateway, "Failed to fetch latest block height")
+		return
+	}
+
+	skip := (page - 1) * limit
+	txs := make([]*TransactionResponse, 0, limit)
+	for height := latest; height > 0 && latest-height < recentTransactionsMaxBlocks && len(txs) < limit; height-- {
+		block, err := s.chain.Block(ctx, strconv.FormatInt(height, 10))
+		if err != nil {
+			// Pruned or unreachable; older blocks will not be available either
+			s.logger.Warn("Failed to fetch block for recent transactions", zap.Int64("height", height), zap.Error(err))
+			break
+		}
+
+		// Later transactions in a block are newer
+		for i := len(block.Txs) - 1; i >= 0 && len(txs) < limit; i-- {
+			txHash, err := blockTxHash(block.Txs[i])
+			if err != nil {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+
+			chainTx, err := s.chain.Tx(ctx, "0x"+txHash)
+			if err != nil {
+				s.logger.Debug("Failed to fetch block transaction", zap.String("hash", txHash), zap.Error(err))
+				continue
+			}
+			tx := s.transactionFromChainTx(chainTx, latest)
+			tx.Timestamp = normalizeTimestamp(block.Time)
+			s.enrichTransactionData(tx)
+			txs = append(txs, tx)
+		}
+	}
+
 	s.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"transactions": []interface{}{},
-		"page":         page,
-		"limit":        limit,
-		"total":        0,
+		"transactions":  txs,
+		"page":          page,
+		"limit":         limit,
+		"latest_height": latest,
 	})
 }
 
+// DocumentAttestation is one attestation found over a verified document
+type DocumentAttestation struct {
+	UID       string `json:"uid"`
+	SchemaUID string `json:"schema_uid"`
+	Attester  string `json:"attester"`
+	Recipient string `json:"recipient,omitempty"`
+	Time      string `json:"time"`
+	Type      string `json:"type"`
+	Status    string `json:"status"` // valid, revoked or expired
+}
+
 // handleVerifyDocument verifies a document hash on-chain
 func (s *Server) handleVerifyDocument(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	docHash := vars["hash"]
+	docHash := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(vars["hash"], "0x"), "0X"))
 
 	if docHash == "" {
 		s.respondError(w, http.StatusBadRequest, "Document hash required")
 		return
 	}
+	if b, err := hex.DecodeString(docHash); err != nil || len(b) != sha256.Size {
+		s.respondError(w, http.StatusBadRequest, "Document hash must be a hex SHA-256 digest")
+		return
+	}
+
+	// The attestation module indexes attestations by document hash
+	var res struct {
+		Attestations []struct {
+			UID             string    `json:"uid"`
+			SchemaUID       string    `json:"schema_uid"`
+			Attester        string    `json:"attester"`
+			Recipient       string    `json:"recipient"`
+			Time            time.Time `json:"time"`
+			ExpirationTime  time.Time `json:"expiration_time"`
+			RevocationTime  time.Time `json:"revocation_time"`
+			AttestationType string    `json:"attestation_type"`
+		} `json:"attestations"`
+	}
+	if err := s.execCertdQueryJSON(&res, "attestation", "by-document-hash", docHash, "--include-revoked"); err != nil {
+		s.logger.Warn("document verification query failed", zap.String("hash", docHash), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "failed to query attestations for document")
+		return
+	}
+
+	now := s.clock.Now()
+	verified := false
+	attestations := make([]DocumentAttestation, 0, len(res.Attestations))
+	for _, a := range res.Attestations {
+		status := "valid"
+		switch {
+		case !a.RevocationTime.IsZero():
+			status = "revoked"
+		case !a.ExpirationTime.IsZero() && now.After(a.ExpirationTime):
+			status = "expired"
+		default:
+			verified = true
+		}
+		attestations = append(attestations, DocumentAttestation{
+			UID:       a.UID,
+			SchemaUID: a.SchemaUID,
+			Attester:  a.Attester,
+			Recipient: a.Recipient,
+			Time:      formatTimestamp(a.Time),
+			Type:      a.AttestationType,
+			Status:    status,
+		})
+	}
 
-	// TODO: Query attestation module for document verification
 	s.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"hash":     docHash,
-		"verified": false,
-		"message":  "Document verification not yet implemented",
+		"hash":         docHash,
+		"verified":     verified,
+		"attestations": attestations,
 	})
 }
 
@@ -703,25 +729,132 @@ func (s *Server) handleGetExplorerStats(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Get latest block height
-	statusURL := fmt.Sprintf("%s/status", s.config.ChainRPCURL)
-	req, _ := http.NewRequestWithContext(ctx, "GET", statusURL, nil)
-	resp, err := http.DefaultClient.Do(req)
-	if err == nil {
-		defer resp.Body.Close()
-		body, _ := io.ReadAll(resp.Body)
-		var statusResult struct {
-			Result struct {
-				SyncInfo struct {
-					LatestBlockHeight string `json:"latest_block_height"`
-				} `json:"sync_info"`
-			} `json:"result"`
-		}
-		if json.Unmarshal(body, &statusResult) == nil {
-			height, _ := strconv.ParseInt(statusResult.Result.SyncInfo.LatestBlockHeight, 10, 64)
-			stats["totalBlocks"] = height
-		}
+	if height, err := s.chain.LatestHeight(ctx); err == nil {
+		stats["totalBlocks"] = height
 	}
 
 	s.respondJSON(w, http.StatusOK, stats)
 }
 
+
+// ContractMethodStats holds aggregate call data for one contract method
+type ContractMethodStats struct {
+	Method        string `json:"method"`
+	Calls         int    `json:"calls"`
+	FailedCalls   int    `json:"failed_calls"`
+	UniqueCallers int    `json:"unique_callers"`
+	VolumeCert    string `json:"volume_cert"`
+}
+
+// ContractStatsResponse represents method analytics for a tagged ecosystem contract
+type ContractStatsResponse struct {
+	Address       string                `json:"address"`
+	Label         string                `json:"label"`
+	EcosystemType string                `json:"ecosystem_type"`
+	From          string                `json:"from"`
+	To            string                `json:"to"`
+	TotalCalls    int                   `json:"total_calls"`
+	UniqueCallers int                   `json:"unique_callers"`
+	VolumeCert    string                `json:"volume_cert"`
+	Methods       []ContractMethodStats `json:"methods"`
+}
+
+// maxContractStatsWindow bounds the time window a stats query may scan
+const maxContractStatsWindow = 90 * 24 * time.Hour
+
+// ecosystemContract returns the label and ecosystem type of a tagged contract
+func ecosystemContract(address string) (label, ecosystemType string, ok bool) {
+	switch strings.ToLower(address) {
+	case strings.ToLower(ChainCertifyContract):
+		return "Chain Certify Contract", "ChainCertify", true
+	case strings.ToLower(CertIDContract):
+		return "Cert ID Contract", "CertID", true
+	case strings.ToLower(CertTokenContract):
+		return "Cert Token Contract", "CertToken", true
+	}
+	return "", "", false
+}
+
+// parseStatsWindow parses a window such as "24h" or "7d" (default 24h)
+func parseStatsWindow(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 24 * time.Hour, nil
+	}
+	var window time.Duration
+	if strings.HasSuffix(raw, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(raw, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid window %q", raw)
+		}
+		window = time.Duration(days) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window %q", raw)
+		}
+		window = d
+	}
+	if window <= 0 || window > maxContractStatsWindow {
+		return 0, fmt.Errorf("window must be between 0 and %s", maxContractStatsWindow)
+	}
+	return window, nil
+}
+
+// handleGetContractStats returns per-method call analytics for a tagged contract
+// GET /api/v1/explorer/contracts/{address}/stats?window=7d
+func (s *Server) handleGetContractStats(w http.ResponseWriter, r *http.Request) {
+	address := strings.ToLower(mux.Vars(r)["address"])
+
+	label, ecosystemType, ok := ecosystemContract(address)
+	if !ok {
+		s.respondError(w, http.StatusNotFound, "Address is not a tagged ecosystem contract")
+		return
+	}
+
+	window, err := parseStatsWindow(r.URL.Query().Get("window"))
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if s.db == nil {
+		s.respondError(w, http.StatusServiceUnavailable, "Explorer index not configured")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	until := s.clock.Now().UTC()
+	since := until.Add(-window)
+
+	total, calls, err := s.db.GetContractCallStats(ctx, address, since, until)
+	if err != nil {
+		s.logger.Error("Failed to aggregate contract calls", zap.String("address", address), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to load contract stats")
+		return
+	}
+
+	methods := make([]ContractMethodStats, 0, len(calls))
+	for _, c := range calls {
+		methods = append(methods, ContractMethodStats{
+			Method:        c.Method,
+			Calls:         c.Calls,
+			FailedCalls:   c.FailedCalls,
+			UniqueCallers: c.UniqueCallers,
+			VolumeCert:    c.VolumeCert,
+		})
+	}
+
+	s.respondJSON(w, http.StatusOK, ContractStatsResponse{
+		Address:       address,
+		Label:         label,
+		EcosystemType: ecosystemType,
+		From:          since.Format(time.RFC3339),
+		To:            until.Format(time.RFC3339),
+		TotalCalls:    total.Calls,
+		UniqueCallers: total.UniqueCallers,
+		VolumeCert:    total.VolumeCert,
+		Methods:       methods,
+	})
+}
@@ -0,0 +1,379 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TestParseStatsWindow tests time window parsing
+func TestParseStatsWindow(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"", 24 * time.Hour, false},
+		{"6h", 6 * time.Hour, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"91d", 0, true},
+		{"-1h", 0, true},
+		{"week", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseStatsWindow(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseStatsWindow(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("parseStatsWindow(%q) = %s, want %s", tt.raw, got, tt.expected)
+		}
+	}
+}
+
+// TestContractStatsEndpoint tests request validation on the contract stats endpoint
+func TestContractStatsEndpoint(t *testing.T) {
+	server := NewServer(DefaultConfig(), zap.NewNop())
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{"untagged address", "/api/v1/explorer/contracts/0x0000000000000000000000000000000000000001/stats", http.StatusNotFound},
+		{"invalid window", "/api/v1/explorer/contracts/" + ChainCertifyContract + "/stats?window=forever", http.StatusBadRequest},
+		{"no index configured", "/api/v1/explorer/contracts/" + CertIDContract + "/stats", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestVerifyDocument(t *testing.T) {
+	const docHash = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	var gotArgs []string
+	chain := &mockChainClient{query: func(out any, _ int64, args ...string) error {
+		gotArgs = args
+		return json.Unmarshal([]byte(`{"attestations":[
+			{"uid":"a1","schema_uid":"s","attester":"cert1a","time":"2026-01-01T00:00:00Z","revocation_time":"2026-02-01T00:00:00Z","attestation_type":"public"},
+			{"uid":"a2","schema_uid":"s","attester":"cert1b","time":"2026-01-02T00:00:00Z","expiration_time":"2026-02-01T00:00:00Z","attestation_type":"public"},
+			{"uid":"a3","schema_uid":"s","attester":"cert1c","time":"2026-01-03T00:00:00Z","revocation_time":"0001-01-01T00:00:00Z","attestation_type":"encrypted_file"}
+		]}`), out)
+	}}
+	config := DefaultConfig()
+	config.Chain = chain
+	config.Clock = newFakeClock(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	s := NewServer(config, zap.NewNop())
+
+	out := getJSON(t, s, "/api/v1/explorer/verify/0x"+strings.ToUpper(docHash))
+	if !containsArgs(gotArgs, "attestation", "by-document-hash", docHash, "--include-revoked") {
+		t.Errorf("query args = %v", gotArgs)
+	}
+	if out["verified"] != true {
+		t.Errorf("verified = %v", out["verified"])
+	}
+	statuses := map[string]string{}
+	for _, a := range out["attestations"].([]any) {
+		a := a.(map[string]any)
+		statuses[a["uid"].(string)] = a["status"].(string)
+	}
+	if statuses["a1"] != "revoked" || statuses["a2"] != "expired" || statuses["a3"] != "valid" {
+		t.Errorf("statuses = %v", statuses)
+	}
+
+	if rec := serve(s, "GET", "/api/v1/explorer/verify/not-a-hash"); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid hash status = %d, want 400", rec.Code)
+	}
+}
+
+func TestSearchExplorerByTags(t *testing.T) {
+	var gotArgs []string
+	chain := &mockChainClient{query: func(out any, _ int64, args ...string) error {
+		gotArgs = args
+		return json.Unmarshal([]byte(`{"attestations":[
+			{"uid":"a1","schema_uid":"s","attester":"cert1a","attestation_type":"public","tags":["diploma","2024-cohort"]}
+		]}`), out)
+	}}
+	config := DefaultConfig()
+	config.Chain = chain
+	s := NewServer(config, zap.NewNop())
+
+	out := getJSON(t, s, "/api/v1/explorer/search?tag=Diploma&tags=2024-cohort,diploma&include_revoked=true")
+	if !containsArgs(gotArgs, "attestation", "by-tag", "diploma", "2024-cohort", "--include-revoked") {
+		t.Errorf("query args = %v", gotArgs)
+	}
+	if out["type"] != "attestations" {
+		t.Errorf("type = %v", out["type"])
+	}
+	attestations := out["attestations"].([]any)
+	if len(attestations) != 1 || attestations[0].(map[string]any)["uid"] != "a1" {
+		t.Fatalf("attestations = %v", attestations)
+	}
+	if tags := attestations[0].(map[string]any)["tags"].([]any); len(tags) != 2 {
+		t.Errorf("tags = %v", tags)
+	}
+
+	if rec := serve(s, "GET", "/api/v1/explorer/search?tag=class%20of%202024"); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid tag status = %d, want 400", rec.Code)
+	}
+}
+
+// fakeExplorerRPC serves /status, /block and /tx for a scripted set of blocks
+type fakeExplorerRPC struct {
+	mu     sync.Mutex
+	latest int64
+	blocks map[int64][]string // Raw txs per height
+	logs   map[string]string  // Tx result log by raw tx
+}
+
+func (c *fakeExplorerRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch r.URL.Path {
+	case "/status":
+		fmt.Fprintf(w, `{"result":{"sync_info":{"latest_block_height":"%d"}}}`, c.latest)
+	case "/block":
+		var height int64
+		fmt.Sscan(r.URL.Query().Get("height"), &height)
+		raw, ok := c.blocks[height]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		txs := make([]string, len(raw))
+		for i, tx := range raw {
+			txs[i] = base64.StdEncoding.EncodeToString([]byte(tx))
+		}
+		bz, _ := json.Marshal(txs)
+		fmt.Fprintf(w, `{"result":{"block_id":{"hash":"B%d"},"block":{"header":{"height":"%d","time":"2026-03-01T00:00:%02dZ"},"data":{"txs":%s}}}}`,
+			height, height, height, bz)
+	case "/tx":
+		for _, raw := range c.blocks {
+			for _, tx := range raw {
+				hash, _ := blockTxHash(base64.StdEncoding.EncodeToString([]byte(tx)))
+				if "0x"+hash == r.URL.Query().Get("hash") {
+					fmt.Fprintf(w, `{"result":{"hash":"%s","height":"%d","tx_result":{"code":0,"log":%q,"gas_wanted":"200000","gas_used":"%d"},"tx":""}}`,
+						hash, c.heightOf(tx), c.logs[tx], len(tx))
+					return
+				}
+			}
+		}
+		fmt.Fprint(w, `{"error":{"data":"tx not found"}}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (c *fakeExplorerRPC) heightOf(tx string) int64 {
+	for height, raw := range c.blocks {
+		for _, t := range raw {
+			if t == tx {
+				return height
+			}
+		}
+	}
+	return 0
+}
+
+func TestRecentTransactions(t *testing.T) {
+	chain := &fakeExplorerRPC{
+		latest: 8,
+		blocks: map[int64][]string{
+			7: {"send-a"},
+			8: {"certify-b", "certid-c"},
+		},
+		logs: map[string]string{
+			"send-a":    `[{"events":[{"type":"transfer"}]}]`,
+			"certify-b": `[{"events":[{"type":"certify"}]}]`,
+			"certid-c":  `[{"events":[{"type":"certid_profile"}]}]`,
+		},
+	}
+	rpc := httptest.NewServer(chain)
+	defer rpc.Close()
+
+	clock := newFakeClock(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	config := DefaultConfig()
+	config.Clock = clock
+	config.ChainRPCURL = rpc.URL
+	s := NewServer(config, zap.NewNop())
+
+	recent := func(query string) ([]TransactionResponse, map[string]any) {
+		t.Helper()
+		rec := serve(s, "GET", "/api/v1/explorer/transactions"+query)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", query, rec.Code, rec.Body.String())
+		}
+		var out struct {
+			Transactions []TransactionResponse `json:"transactions"`
+		}
+		var raw map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		_ = json.Unmarshal(rec.Body.Bytes(), &raw)
+		return out.Transactions, raw
+	}
+	hashOf := func(tx string) string {
+		hash, _ := blockTxHash(base64.StdEncoding.EncodeToString([]byte(tx)))
+		return "0x" + hash
+	}
+
+	// Newest block first, and within a block the later transaction first
+	txs, raw := recent("")
+	if len(txs) != 3 || raw["latest_height"].(float64) != 8 {
+		t.Fatalf("transactions = %+v, body = %v", txs, raw)
+	}
+	want := []struct {
+		tx, typ       string
+		height, confs int64
+	}{
+		{"certid-c", "Cert ID", 8, 0},
+		{"certify-b", "Chain Certify", 8, 0},
+		{"send-a", "Transfer", 7, 1},
+	}
+	for i, w := range want {
+		got := txs[i]
+		if got.Hash != hashOf(w.tx) || got.Type != w.typ || got.BlockNumber != w.height || got.Confirmations != w.confs || got.Status != "success" {
+			t.Errorf("tx %d = %+v, want %s (%s) at %d", i, got, w.tx, w.typ, w.height)
+		}
+	}
+	if txs[2].Timestamp == "" {
+		t.Error("block timestamp not set")
+	}
+
+	// Pages continue across block boundaries
+	page2, _ := recent("?page=2&limit=2")
+	if len(page2) != 1 || page2[0].Hash != hashOf("send-a") {
+		t.Errorf("page 2 = %+v", page2)
+	}
+	if _, raw := recent("?limit=500"); raw["limit"].(float64) != 20 {
+		t.Errorf("limit = %v, want clamped to default 20", raw["limit"])
+	}
+
+	// Responses are cached briefly, then rescanned
+	chain.mu.Lock()
+	chain.latest = 9
+	chain.blocks[9] = []string{"send-d"}
+	chain.logs["send-d"] = `[{"events":[{"type":"transfer"}]}]`
+	chain.mu.Unlock()
+	if txs, _ := recent(""); len(txs) != 3 {
+		t.Errorf("cached transactions = %d, want 3", len(txs))
+	}
+	clock.Advance(6 * time.Second)
+	if txs, _ := recent(""); len(txs) != 4 || txs[0].Hash != hashOf("send-d") || txs[0].Confirmations != 0 {
+		t.Errorf("after expiry = %+v", txs)
+	}
+}
+
+// fakeTxSearchRPC serves /status and a tx_search that returns scripted txs per query
+type fakeTxSearchRPC struct {
+	latest  int64
+	results map[string][]string // Query -> hash@height entries, newest first
+}
+
+func (c *fakeTxSearchRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/status":
+		fmt.Fprintf(w, `{"result":{"sync_info":{"latest_block_height":"%d"}}}`, c.latest)
+	case "/tx_search":
+		query := strings.Trim(r.URL.Query().Get("query"), `"`)
+		entries := c.results[query]
+		txs := make([]string, len(entries))
+		for i, entry := range entries {
+			hash, height, _ := strings.Cut(entry, "@")
+			txs[i] = fmt.Sprintf(`{"hash":"%s","height":"%s","index":0,"tx_result":{"code":0,"log":"transfer","gas_wanted":"200000","gas_used":"80000"},"tx":""}`, hash, height)
+		}
+		fmt.Fprintf(w, `{"result":{"txs":[%s],"total_count":"%d"}}`, strings.Join(txs, ","), len(entries))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestAddressTransactions(t *testing.T) {
+	hexAddr := "0x" + strings.Repeat("ab", 20)
+	bech32Addr, err := toBech32Address(hexAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sentA, selfB, receivedC := strings.Repeat("A", 64), strings.Repeat("B", 64), strings.Repeat("C", 64)
+	rpc := httptest.NewServer(&fakeTxSearchRPC{
+		latest: 12,
+		results: map[string][]string{
+			"message.sender='" + bech32Addr + "'":     {sentA + "@10", selfB + "@8"},
+			"transfer.recipient='" + bech32Addr + "'": {receivedC + "@9", selfB + "@8"},
+		},
+	})
+	defer rpc.Close()
+
+	config := DefaultConfig()
+	config.ChainRPCURL = rpc.URL
+	s := NewServer(config, zap.NewNop())
+
+	get := func(address, query string) ([]TransactionResponse, map[string]any) {
+		t.Helper()
+		rec := serve(s, "GET", "/api/v1/explorer/address/"+address+"/transactions"+query)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s%s = %d: %s", address, query, rec.Code, rec.Body.String())
+		}
+		var out struct {
+			Transactions []TransactionResponse `json:"transactions"`
+		}
+		var raw map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		_ = json.Unmarshal(rec.Body.Bytes(), &raw)
+		return out.Transactions, raw
+	}
+
+	// Sent and received merged newest first, the self-transfer listed once
+	for _, address := range []string{hexAddr, bech32Addr} {
+		txs, raw := get(address, "")
+		if raw["address"] != bech32Addr || raw["total"].(float64) != 3 || raw["has_more"] != false {
+			t.Errorf("%s: body = %v", address, raw)
+		}
+		if len(txs) != 3 || txs[0].Hash != "0x"+sentA || txs[1].Hash != "0x"+receivedC || txs[2].Hash != "0x"+selfB {
+			t.Fatalf("%s: transactions = %+v", address, txs)
+		}
+		if txs[0].BlockNumber != 10 || txs[0].Confirmations != 2 || txs[0].Type != "Transfer" || txs[0].Status != "success" {
+			t.Errorf("%s: decoded tx = %+v", address, txs[0])
+		}
+	}
+
+	txs, raw := get(bech32Addr, "?page=2&limit=2")
+	if len(txs) != 1 || txs[0].Hash != "0x"+selfB || raw["total"].(float64) != 3 || raw["page"].(float64) != 2 {
+		t.Errorf("page 2 = %+v, body = %v", txs, raw)
+	}
+	if _, raw := get(bech32Addr, "?limit=2"); raw["has_more"] != true {
+		t.Errorf("page 1 of 2 has_more = %v", raw["has_more"])
+	}
+
+	if rec := serve(s, "GET", "/api/v1/explorer/address/0xzz/transactions"); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid address = %d, want 400", rec.Code)
+	}
+
+	// Pages past the paging depth are rejected rather than fetched
+	if _, raw := get(bech32Addr, "?page=10&limit=100"); raw["has_more"] != false {
+		t.Errorf("last page in depth: body = %v", raw)
+	}
+	for _, query := range []string{"?page=11&limit=100", "?page=1001&limit=1", "?page=9223372036854775807&limit=100"} {
+		if rec := serve(s, "GET", "/api/v1/explorer/address/"+bech32Addr+"/transactions"+query); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s = %d, want 400", query, rec.Code)
+		}
+	}
+}
@@ -43,7 +43,7 @@ var (
 func (s *Server) handleFaucet(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req FaucetRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(w, r, &req); err != nil {
 		s.respondJSON(w, http.StatusBadRequest, FaucetResponse{
 			Success: false,
 			Message: "Invalid request body",
@@ -65,8 +65,8 @@ func (s *Server) handleFaucet(w http.ResponseWriter, r *http.Request) {
 	lastRequest, exists := faucetRequests[req.Address]
 	faucetMutex.RUnlock()
 
-	if exists && time.Since(lastRequest) < faucetCooldown {
-		remaining := faucetCooldown - time.Since(lastRequest)
+	if elapsed := s.clock.Now().Sub(lastRequest); exists && elapsed < faucetCooldown {
+		remaining := faucetCooldown - elapsed
 		s.respondJSON(w, http.StatusTooManyRequests, FaucetResponse{
 			Success: false,
 			Message: fmt.Sprintf("Rate limited. Please wait %s before requesting again.", formatDuration(remaining)),
@@ -87,7 +87,7 @@ func (s *Server) handleFaucet(w http.ResponseWriter, r *http.Request) {
 
 	// Update rate limiting
 	faucetMutex.Lock()
-	faucetRequests[req.Address] = time.Now()
+	faucetRequests[req.Address] = s.clock.Now()
 	faucetMutex.Unlock()
 
 	// Record the transaction in the database for balance tracking
@@ -2,8 +2,7 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
-	"io"
+	"errors"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -16,11 +15,11 @@ type ProposalInfo struct {
 	Messages         json.RawMessage `json:"messages,omitempty"`
 	Status           string          `json:"status"`
 	FinalTallyResult TallyResult     `json:"final_tally_result"`
-	SubmitTime       string          `json:"submit_time"`
-	DepositEndTime   string          `json:"deposit_end_time"`
+	SubmitTime       Timestamp       `json:"submit_time"`
+	DepositEndTime   Timestamp       `json:"deposit_end_time"`
 	TotalDeposit     []Coin          `json:"total_deposit"`
-	VotingStartTime  string          `json:"voting_start_time"`
-	VotingEndTime    string          `json:"voting_end_time"`
+	VotingStartTime  Timestamp       `json:"voting_start_time"`
+	VotingEndTime    Timestamp       `json:"voting_end_time"`
 	Metadata         string          `json:"metadata"`
 	Title            string          `json:"title"`
 	Summary          string          `json:"summary"`
@@ -49,31 +48,13 @@ type ProposalsResponse struct {
 
 // handleGetProposals returns all governance proposals (replaces stub in handlers_common.go)
 func (s *Server) handleGetAllProposals(w http.ResponseWriter, r *http.Request) {
-	// Query proposals from REST API
-	url := fmt.Sprintf("%s/cosmos/gov/v1/proposals", getRESTBaseURL())
-	
-	resp, err := restClient.Get(url)
+	result, err := s.chain.Proposals(r.Context())
 	if err != nil {
 		s.logger.Warn("proposals query failed", zap.Error(err))
 		// Return empty list on error
 		s.respondJSON(w, http.StatusOK, ProposalsResponse{Proposals: []ProposalInfo{}})
 		return
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		s.logger.Warn("failed to read proposals response", zap.Error(err))
-		s.respondJSON(w, http.StatusOK, ProposalsResponse{Proposals: []ProposalInfo{}})
-		return
-	}
-
-	var result ProposalsResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		s.logger.Warn("failed to parse proposals response", zap.Error(err), zap.String("body", string(body)))
-		s.respondJSON(w, http.StatusOK, ProposalsResponse{Proposals: []ProposalInfo{}})
-		return
-	}
 
 	s.respondJSON(w, http.StatusOK, result)
 }
@@ -87,36 +68,18 @@ func (s *Server) handleGetProposal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	url := fmt.Sprintf("%s/cosmos/gov/v1/proposals/%s", getRESTBaseURL(), proposalID)
-	
-	resp, err := restClient.Get(url)
-	if err != nil {
-		s.logger.Warn("proposal query failed", zap.String("id", proposalID), zap.Error(err))
-		s.respondError(w, http.StatusBadGateway, "Failed to query proposal")
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
+	proposal, err := s.chain.Proposal(r.Context(), proposalID)
+	if errors.Is(err, ErrChainNotFound) {
 		s.respondError(w, http.StatusNotFound, "Proposal not found")
 		return
 	}
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		s.respondError(w, http.StatusBadGateway, "Failed to read response")
-		return
-	}
-
-	var result struct {
-		Proposal ProposalInfo `json:"proposal"`
-	}
-	if err := json.Unmarshal(body, &result); err != nil {
-		s.respondError(w, http.StatusBadGateway, "Failed to parse response")
+		s.logger.Warn("proposal query failed", zap.String("id", proposalID), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query proposal")
 		return
 	}
 
-	s.respondJSON(w, http.StatusOK, result)
+	s.respondJSON(w, http.StatusOK, map[string]ProposalInfo{"proposal": proposal})
 }
 
 // handleGetProposalTally returns current tally for a proposal
@@ -128,26 +91,18 @@ func (s *Server) handleGetProposalTally(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	url := fmt.Sprintf("%s/cosmos/gov/v1/proposals/%s/tally", getRESTBaseURL(), proposalID)
-	
-	resp, err := restClient.Get(url)
+	tally, err := s.chain.ProposalTally(r.Context(), proposalID)
 	if err != nil {
 		s.respondError(w, http.StatusBadGateway, "Failed to query tally")
 		return
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(body)
+	s.respondJSON(w, http.StatusOK, tally)
 }
 
 // handleGetGovParams returns governance module parameters
 func (s *Server) handleGetGovParams(w http.ResponseWriter, r *http.Request) {
-	url := fmt.Sprintf("%s/cosmos/gov/v1/params/voting", getRESTBaseURL())
-
-	resp, err := restClient.Get(url)
+	params, err := s.chain.GovParams(r.Context(), "voting")
 	if err != nil {
 		// Return default params on error
 		s.respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -168,12 +123,8 @@ func (s *Server) handleGetGovParams(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(body)
+	s.respondJSON(w, http.StatusOK, params)
 }
 
 // VoteRequest represents a vote request
@@ -189,8 +140,8 @@ func (s *Server) handleVoteOnProposal(w http.ResponseWriter, r *http.Request) {
 	proposalID := vars["proposal_id"]
 
 	var req VoteRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBodyStrict(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 
@@ -255,8 +206,8 @@ type CreateProposalRequest struct {
 // handleCreateProposal creates an unsigned proposal transaction
 func (s *Server) handleCreateProposal(w http.ResponseWriter, r *http.Request) {
 	var req CreateProposalRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBodyStrict(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 
@@ -313,18 +264,12 @@ func (s *Server) handleGetVotes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	url := fmt.Sprintf("%s/cosmos/gov/v1/proposals/%s/votes", getRESTBaseURL(), proposalID)
-
-	resp, err := restClient.Get(url)
+	votes, err := s.chain.ProposalVotes(r.Context(), proposalID)
 	if err != nil {
 		s.respondJSON(w, http.StatusOK, map[string]interface{}{"votes": []interface{}{}})
 		return
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(body)
+	s.respondJSON(w, http.StatusOK, votes)
 }
 
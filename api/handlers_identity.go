@@ -5,6 +5,7 @@ package api
 import (
 	"context"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,41 +15,47 @@ import (
 
 // FullIdentity represents a complete CertID identity with badges and trust metrics
 type FullIdentity struct {
-	Address        string   `json:"address"`
-	Handle         string   `json:"handle"`
-	Name           string   `json:"name,omitempty"`
-	Bio            string   `json:"bio,omitempty"`
-	AvatarURL      string   `json:"avatar_url,omitempty"`
-	MetadataURI    string   `json:"metadata_uri,omitempty"`
-	IsVerified     bool     `json:"is_verified"`
-	IsInstitutional bool    `json:"is_institutional"`
-	TrustScore     int      `json:"trust_score"`
-	EntityType     int      `json:"entity_type"`
-	Badges         []Badge  `json:"badges"`
-	IsKYC          bool     `json:"is_kyc"`
-	IsAcademic     bool     `json:"is_academic"`
-	IsCreator      bool     `json:"is_creator"`
-	CreatedAt      int64    `json:"created_at,omitempty"`
+	Address         string     `json:"address"`
+	Handle          string     `json:"handle"`
+	HandleVerified  bool       `json:"handle_verified"` // The handle was claimed with an on-chain CertID registration
+	Name            string     `json:"name,omitempty"`
+	Bio             string     `json:"bio,omitempty"`
+	AvatarURL       string     `json:"avatar_url,omitempty"`
+	MetadataURI     string     `json:"metadata_uri,omitempty"`
+	IsVerified      bool       `json:"is_verified"`
+	IsInstitutional bool       `json:"is_institutional"`
+	TrustScore      int        `json:"trust_score"`
+	EntityType      int        `json:"entity_type"`
+	Badges          []Badge    `json:"badges"`
+	IsKYC           bool       `json:"is_kyc"`
+	IsAcademic      bool       `json:"is_academic"`
+	IsCreator       bool       `json:"is_creator"`
+	CreatedAt       *Timestamp `json:"created_at,omitempty"`
+	CreatedAtUnix   int64      `json:"created_at_unix,omitempty"`
+
+	// AttestationsReceived and AttestationsIssued count active on-chain attestations;
+	// revoked and expired attestations are excluded
+	AttestationsReceived int      `json:"attestations_received"`
+	AttestationsIssued   int      `json:"attestations_issued"`
+	RecentAttestations   []string `json:"recent_attestations,omitempty"`
 }
 
+// maxRecentAttestations caps FullIdentity.RecentAttestations
+const maxRecentAttestations = 5
+
 // Badge represents a Soulbound Token badge
 type Badge struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Icon        string `json:"icon"`
-	AwardedAt   int64  `json:"awarded_at,omitempty"`
-}
-
-// Standard badge definitions
-var standardBadges = map[string]Badge{
-	"KYC_L1":           {ID: "KYC_L1", Name: "KYC Level 1", Icon: "🪪", Description: "Basic identity verification"},
-	"KYC_L2":           {ID: "KYC_L2", Name: "KYC Level 2", Icon: "🛡️", Description: "Advanced identity verification"},
-	"ACADEMIC_ISSUER":  {ID: "ACADEMIC_ISSUER", Name: "Academic Issuer", Icon: "🎓", Description: "Verified educational institution"},
-	"VERIFIED_CREATOR": {ID: "VERIFIED_CREATOR", Name: "Verified Creator", Icon: "✨", Description: "Verified content creator"},
-	"GOV_AGENCY":       {ID: "GOV_AGENCY", Name: "Government Agency", Icon: "🏛️", Description: "Verified government entity"},
-	"LEGAL_ENTITY":     {ID: "LEGAL_ENTITY", Name: "Legal Entity", Icon: "⚖️", Description: "Verified legal organization"},
-	"ISO_9001_CERTIFIED": {ID: "ISO_9001_CERTIFIED", Name: "ISO 9001", Icon: "📋", Description: "ISO 9001 quality certified"},
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description,omitempty"`
+	Icon          string     `json:"icon"`               // Registry icon identifier or absolute icon URL
+	IconURL       string     `json:"icon_url,omitempty"` // Where to fetch the icon; filled in from Icon
+	Emoji         string     `json:"emoji,omitempty"`    // Optional fallback for clients that render emoji
+	AwardedAt     *Timestamp `json:"awarded_at,omitempty"`
+	AwardedAtUnix int64      `json:"awarded_at_unix,omitempty"`
+
+	// Institutional marks badges held by institutions rather than individuals
+	Institutional bool `json:"institutional,omitempty"`
 }
 
 // handleGetFullIdentity returns complete identity with badges and trust score
@@ -60,6 +67,12 @@ func (s *Server) handleGetFullIdentity(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
+	s.respondJSON(w, http.StatusOK, s.buildFullIdentity(ctx, address))
+}
+
+// buildFullIdentity assembles the profile, verified badges, attestation
+// summary and trust score for an address; missing sources are left empty
+func (s *Server) buildFullIdentity(ctx context.Context, address string) FullIdentity {
 	identity := FullIdentity{
 		Address:    address,
 		Handle:     "Anonymous",
@@ -78,7 +91,8 @@ func (s *Server) handleGetFullIdentity(w http.ResponseWriter, r *http.Request) {
 			}
 			identity.Bio = prof.Bio
 			identity.AvatarURL = prof.AvatarURL
-			identity.CreatedAt = prof.CreatedAt.Unix()
+			identity.CreatedAt = NewTimestampPtr(&prof.CreatedAt)
+			identity.CreatedAtUnix = prof.CreatedAt.Unix()
 		}
 
 		// Get credentials and map to badges
@@ -87,7 +101,7 @@ func (s *Server) handleGetFullIdentity(w http.ResponseWriter, r *http.Request) {
 				if c.Verified {
 					identity.IsVerified = true
 					// Map credential types to badges
-					if badge, ok := mapCredentialToBadge(c.CredentialType); ok {
+					if badge, ok := s.mapCredentialToBadge(c.CredentialType, c.Issuer); ok {
 						identity.Badges = append(identity.Badges, badge)
 					}
 				}
@@ -95,6 +109,12 @@ func (s *Server) handleGetFullIdentity(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// A claimed .cert handle takes precedence over the display name
+	if reg := s.lookupHandle(ctx, address); reg != nil {
+		identity.Handle = reg.Handle + handleSuffix
+		identity.HandleVerified = reg.Verified
+	}
+
 	// Derive convenience flags from badges
 	for _, b := range identity.Badges {
 		switch b.ID {
@@ -107,6 +127,17 @@ func (s *Server) handleGetFullIdentity(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Summarize on-chain attestations; the identity is still served if the node is unreachable
+	if bech32Addr, err := toBech32Address(address); err == nil {
+		if summary, err := s.queryAttestationSummary(bech32Addr); err == nil {
+			identity.AttestationsReceived = summary.Received
+			identity.AttestationsIssued = summary.Issued
+			identity.RecentAttestations = summary.Recent
+		} else {
+			s.logger.Warn("failed to summarize attestations", zap.String("address", bech32Addr), zap.Error(err))
+		}
+	}
+
 	// Calculate trust score (after KYC flag is set)
 	if s.db != nil {
 		socialCount := 0
@@ -114,11 +145,11 @@ func (s *Server) handleGetFullIdentity(w http.ResponseWriter, r *http.Request) {
 			socialCount = count
 		}
 		if prof, err := s.db.GetProfile(ctx, address); err == nil && prof != nil {
-			identity.TrustScore = calculateTrustScore(prof.CreatedAt, 0, identity.IsKYC, socialCount)
+			identity.TrustScore = calculateTrustScore(s.trustScore.Identity, s.clock.Now(), prof.CreatedAt, 0, identity.IsKYC, socialCount)
 		}
 	}
 
-	s.respondJSON(w, http.StatusOK, identity)
+	return identity
 }
 
 // handleGetBadges returns badges for an address
@@ -136,7 +167,7 @@ func (s *Server) handleGetBadges(w http.ResponseWriter, r *http.Request) {
 		if creds, err := s.db.GetCredentialsByUser(ctx, address); err == nil {
 			for _, c := range creds {
 				if c.Verified {
-					if badge, ok := mapCredentialToBadge(c.CredentialType); ok {
+					if badge, ok := s.mapCredentialToBadge(c.CredentialType, c.Issuer); ok {
 						badges = append(badges, badge)
 					}
 				}
@@ -187,7 +218,7 @@ func (s *Server) handleGetTrustScore(w http.ResponseWriter, r *http.Request) {
 
 		// Calculate trust score with KYC flag and social count
 		if prof, err := s.db.GetProfile(ctx, address); err == nil && prof != nil {
-			score = calculateTrustScore(prof.CreatedAt, attestationCount, hasKYC, socialCount)
+			score = calculateTrustScore(s.trustScore.Identity, s.clock.Now(), prof.CreatedAt, attestationCount, hasKYC, socialCount)
 		}
 	}
 
@@ -209,20 +240,29 @@ func (s *Server) handleGetTrustScore(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleResolveHandle resolves a .cert handle to an address
+// handleResolveHandle resolves a .cert handle to its owner's address and profile
 // GET /api/v1/identity/resolve/{handle}
 func (s *Server) handleResolveHandle(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	handle := strings.ToLower(vars["handle"])
-
-	// Remove .cert suffix if present
-	handle = strings.TrimSuffix(handle, ".cert")
+	handle := normalizeHandle(mux.Vars(r)["handle"])
 
-	s.logger.Info("Resolving handle", zap.String("handle", handle))
+	reg, err := s.handles.GetHandle(r.Context(), handle)
+	if err != nil {
+		s.logger.Error("failed to resolve handle", zap.String("handle", handle), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to resolve handle")
+		return
+	}
+	if reg == nil {
+		s.respondError(w, http.StatusNotFound, "Handle not found")
+		return
+	}
 
-	// TODO: Implement handle registry lookup
-	// For now, return not found
-	s.respondError(w, http.StatusNotFound, "Handle not found")
+	resp := newHandleResponse(reg)
+	if s.db != nil {
+		if prof, err := s.db.GetProfile(r.Context(), reg.Address); err == nil && prof != nil {
+			resp.Profile = prof
+		}
+	}
+	s.respondJSON(w, http.StatusOK, resp)
 }
 
 // Helper functions
@@ -234,61 +274,103 @@ func truncateAddress(addr string) string {
 	return addr[:6] + "..." + addr[len(addr)-4:]
 }
 
-func calculateTrustScore(createdAt time.Time, attestationCount int, hasKYC bool, socialCount int) int {
+// trustScoreModelVersion identifies the default calculateTrustScore weighting;
+// bump it whenever DefaultTrustScoreConfig changes
+const trustScoreModelVersion = "certid-trust-v1"
+
+func calculateTrustScore(w IdentityScoreWeights, now, createdAt time.Time, attestationCount int, hasKYC bool, socialCount int) int {
 	score := 0
 
-	// KYC bonus: 50 points for verified identity (50% of max score)
+	// KYC bonus for verified identity
 	if hasKYC {
-		score += 50
+		score += w.KYC
+	}
+
+	// Social verification bonus per verified social account, capped
+	score += sybilMin(socialCount*w.PerSocial, w.SocialCap)
+
+	// Age bonus for the highest account age tier reached
+	daysOld := int(now.Sub(createdAt).Hours() / 24)
+	score += w.agePoints(daysOld)
+
+	// Attestation bonus per attestation, capped
+	score += sybilMin(attestationCount*w.PerAttestation, w.AttestationCap)
+
+	return score
+}
+
+// attestationSummary counts an address's active attestations
+type attestationSummary struct {
+	Received int
+	Issued   int
+	Recent   []string // most recently received active attestation UIDs, newest first
+}
+
+// queryAttestationSummary counts the active attestations bech32Addr has received and issued
+func (s *Server) queryAttestationSummary(bech32Addr string) (attestationSummary, error) {
+	type listedAttestation struct {
+		UID            string `json:"uid"`
+		Time           string `json:"time"`
+		ExpirationTime string `json:"expiration_time"`
+		RevocationTime string `json:"revocation_time"`
+	}
+	list := func(subcommand string) ([]listedAttestation, error) {
+		var res struct {
+			Attestations []listedAttestation `json:"attestations"`
+		}
+		err := retryAtLaggedHeight(context.Background(), s.chain.LatestHeight, func(ctx context.Context, height int64) error {
+			res.Attestations = nil
+			return s.chain.Query(ctx, &res, height, "attestation", subcommand, bech32Addr)
+		})
+		return res.Attestations, err
 	}
 
-	// Social verification bonus: 8 points per verified social account (up to 24 for 3 platforms)
-	socialBonus := socialCount * 8
-	if socialBonus > 24 {
-		socialBonus = 24
+	now := s.clock.Now()
+	active := func(a listedAttestation) bool {
+		if isRevokedTimestamp(a.RevocationTime) {
+			return false
+		}
+		expires := parseChainTime(a.ExpirationTime)
+		return expires.IsZero() || now.Before(expires)
+	}
+
+	var summary attestationSummary
+	received, err := list("by-recipient")
+	if err != nil {
+		return summary, err
 	}
-	score += socialBonus
-
-	// Age bonus: up to 16 points for account age
-	daysOld := int(time.Since(createdAt).Hours() / 24)
-	if daysOld > 365 {
-		score += 16
-	} else if daysOld > 180 {
-		score += 12
-	} else if daysOld > 30 {
-		score += 8
-	} else if daysOld > 7 {
-		score += 4
+	issued, err := list("by-attester")
+	if err != nil {
+		return summary, err
 	}
 
-	// Attestation bonus: 2 points per attestation, up to 10
-	attBonus := attestationCount * 2
-	if attBonus > 10 {
-		attBonus = 10
+	var recent []listedAttestation
+	for _, a := range received {
+		if active(a) {
+			summary.Received++
+			recent = append(recent, a)
+		}
+	}
+	for _, a := range issued {
+		if active(a) {
+			summary.Issued++
+		}
 	}
-	score += attBonus
 
-	return score
+	sort.SliceStable(recent, func(i, j int) bool {
+		return parseChainTime(recent[i].Time).After(parseChainTime(recent[j].Time))
+	})
+	for i := 0; i < len(recent) && i < maxRecentAttestations; i++ {
+		summary.Recent = append(summary.Recent, recent[i].UID)
+	}
+	return summary, nil
 }
 
-func mapCredentialToBadge(credType string) (Badge, bool) {
-	switch strings.ToUpper(credType) {
-	case "KYC", "KYC_L1", "IDENTITY":
-		return standardBadges["KYC_L1"], true
-	case "KYC_L2", "IDENTITY_ADVANCED":
-		return standardBadges["KYC_L2"], true
-	case "EDUCATION", "ACADEMIC", "UNIVERSITY":
-		return standardBadges["ACADEMIC_ISSUER"], true
-	case "CREATOR", "ARTIST", "DEVELOPER":
-		return standardBadges["VERIFIED_CREATOR"], true
-	case "GOVERNMENT", "GOV":
-		return standardBadges["GOV_AGENCY"], true
-	case "LEGAL", "BUSINESS", "COMPANY":
-		return standardBadges["LEGAL_ENTITY"], true
-	case "ISO", "ISO_9001", "QUALITY":
-		return standardBadges["ISO_9001_CERTIFIED"], true
-	default:
-		return Badge{}, false
+// parseChainTime parses an RFC 3339 timestamp from chain JSON, returning the zero time if unset or malformed
+func parseChainTime(ts string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(ts))
+	if err != nil {
+		return time.Time{}
 	}
+	return t
 }
-
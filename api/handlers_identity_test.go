@@ -0,0 +1,237 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+func TestFullIdentityAttestationSummary(t *testing.T) {
+	received := `{"attestations":[
+		{"uid":"r-old","time":"2026-01-01T00:00:00Z","revocation_time":"0001-01-01T00:00:00Z"},
+		{"uid":"r-revoked","time":"2026-01-02T00:00:00Z","revocation_time":"2026-02-01T00:00:00Z"},
+		{"uid":"r-expired","time":"2026-01-03T00:00:00Z","expiration_time":"2026-02-01T00:00:00Z"},
+		{"uid":"r-new","time":"2026-01-04T00:00:00.5Z","expiration_time":"2027-01-01T00:00:00Z"}
+	]}`
+	issued := `{"attestations":[
+		{"uid":"i-1","time":"2026-01-01T00:00:00Z"},
+		{"uid":"i-2","time":"2026-01-02T00:00:00Z","revocation_time":"2026-01-05T00:00:00Z"}
+	]}`
+	var fail bool
+	chain := &mockChainClient{query: func(out any, _ int64, args ...string) error {
+		if fail {
+			return errors.New("node unavailable")
+		}
+		switch {
+		case containsArgs(args, "attestation", "by-recipient", testBalanceAddr):
+			return json.Unmarshal([]byte(received), out)
+		case containsArgs(args, "attestation", "by-attester", testBalanceAddr):
+			return json.Unmarshal([]byte(issued), out)
+		}
+		t.Fatalf("unexpected query %v", args)
+		return nil
+	}}
+	config := DefaultConfig()
+	config.Chain = chain
+	config.Clock = newFakeClock(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	s := NewServer(config, zap.NewNop())
+
+	out := getJSON(t, s, "/api/v1/identity/"+testBalanceAddr)
+	if out["attestations_received"] != float64(2) || out["attestations_issued"] != float64(1) {
+		t.Errorf("received = %v, issued = %v; want 2 and 1", out["attestations_received"], out["attestations_issued"])
+	}
+	recent, _ := out["recent_attestations"].([]any)
+	if len(recent) != 2 || recent[0] != "r-new" || recent[1] != "r-old" {
+		t.Errorf("recent_attestations = %v, want [r-new r-old]", recent)
+	}
+
+	// The identity is still served when the node is unreachable
+	fail = true
+	out = getJSON(t, s, "/api/v1/identity/"+testBalanceAddr)
+	if out["attestations_received"] != float64(0) || out["recent_attestations"] != nil {
+		t.Errorf("summary without chain = %v", out)
+	}
+}
+
+func claimHandle(t *testing.T, s *Server, caller, handle string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := newJSONRequest("POST", "/api/v1/identity/handle", strings.NewReader(`{"handle":"`+handle+`"}`))
+	req.Header.Set("Authorization", "Bearer "+testAuthToken(t, s.config.JWTSecret, caller))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandleRegistry(t *testing.T) {
+	config := DefaultConfig()
+	config.Chain = &mockChainClient{query: func(out any, _ int64, args ...string) error {
+		return json.Unmarshal([]byte(`{"attestations":[]}`), out)
+	}}
+	s := NewServer(config, zap.NewNop())
+
+	rec := claimHandle(t, s, testBalanceAddr, "Alice-01.cert")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("claim = %d: %s", rec.Code, rec.Body.String())
+	}
+	var claimed HandleResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &claimed); err != nil {
+		t.Fatal(err)
+	}
+	if claimed.Handle != "alice-01.cert" || claimed.Address != testBalanceAddr {
+		t.Errorf("claimed = %+v", claimed)
+	}
+
+	t.Run("resolves to the owner", func(t *testing.T) {
+		for _, handle := range []string{"alice-01", "ALICE-01.cert"} {
+			out := getJSON(t, s, "/api/v1/identity/resolve/"+handle)
+			if out["address"] != testBalanceAddr || out["handle"] != "alice-01.cert" {
+				t.Errorf("resolve %s = %v", handle, out)
+			}
+		}
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/identity/resolve/bob.cert", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("unclaimed handle = %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("identity shows the claimed handle", func(t *testing.T) {
+		if out := getJSON(t, s, "/api/v1/identity/"+testBalanceAddr); out["handle"] != "alice-01.cert" {
+			t.Errorf("identity handle = %v", out["handle"])
+		}
+		if out := getJSON(t, s, "/api/v1/identity/"+validateCreator); out["handle"] != "Anonymous" {
+			t.Errorf("identity without a handle = %v", out["handle"])
+		}
+	})
+
+	t.Run("rejects duplicate claims", func(t *testing.T) {
+		if rec := claimHandle(t, s, validateCreator, "alice-01"); rec.Code != http.StatusConflict {
+			t.Errorf("taken handle = %d: %s", rec.Code, rec.Body.String())
+		}
+		if rec := claimHandle(t, s, testBalanceAddr, "alice-02"); rec.Code != http.StatusConflict {
+			t.Errorf("second handle for an address = %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("rejects invalid and reserved handles", func(t *testing.T) {
+		for _, handle := range []string{"ab", strings.Repeat("a", 33), "bob_smith", "bob.smith", "-bob", "admin", "cert.cert"} {
+			if rec := claimHandle(t, s, validateCreator, handle); rec.Code != http.StatusBadRequest {
+				t.Errorf("claim %q = %d, want 400", handle, rec.Code)
+			}
+		}
+		if rec := claimHandle(t, s, validateCreator, "bob"); rec.Code != http.StatusCreated {
+			t.Errorf("claim bob = %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+func TestHandleReleaseAndHistory(t *testing.T) {
+	config := DefaultConfig()
+	config.Chain = &mockChainClient{query: func(out any, _ int64, args ...string) error {
+		if containsArgs(args, "certid", "profile-by-handle") {
+			if args[len(args)-1] == "carol" {
+				return json.Unmarshal([]byte(`{"profile":{"address":"`+validateAttester+`","handle":"carol"}}`), out)
+			}
+			return errors.New("rpc error: code = Unknown desc = profile not found")
+		}
+		return json.Unmarshal([]byte(`{"attestations":[]}`), out)
+	}}
+	clock := newFakeClock(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	config.Clock = clock
+	s := NewServer(config, zap.NewNop())
+
+	release := func(caller string) *httptest.ResponseRecorder {
+		req := newJSONRequest("POST", "/api/v1/identity/handle/release", nil)
+		req.Header.Set("Authorization", "Bearer "+testAuthToken(t, s.config.JWTSecret, caller))
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := claimHandle(t, s, testBalanceAddr, "alice"); rec.Code != http.StatusCreated {
+		t.Fatalf("claim = %d: %s", rec.Code, rec.Body.String())
+	}
+	if out := getJSON(t, s, "/api/v1/identity/"+testBalanceAddr+"/handle"); out["handle"] != "alice.cert" || out["verified"] != false {
+		t.Errorf("address handle = %v", out)
+	}
+
+	t.Run("release frees the handle for another address", func(t *testing.T) {
+		clock.Advance(time.Hour)
+		if rec := release(testBalanceAddr); rec.Code != http.StatusOK {
+			t.Fatalf("release = %d: %s", rec.Code, rec.Body.String())
+		}
+		if rec := release(testBalanceAddr); rec.Code != http.StatusNotFound {
+			t.Errorf("second release = %d, want 404", rec.Code)
+		}
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/identity/"+testBalanceAddr+"/handle", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("handle after release = %d, want 404", rec.Code)
+		}
+
+		clock.Advance(time.Hour)
+		if rec := claimHandle(t, s, validateCreator, "alice"); rec.Code != http.StatusCreated {
+			t.Fatalf("re-claim = %d: %s", rec.Code, rec.Body.String())
+		}
+		if out := getJSON(t, s, "/api/v1/identity/resolve/alice.cert"); out["address"] != validateCreator {
+			t.Errorf("resolve after re-claim = %v", out)
+		}
+	})
+
+	t.Run("history records each owner", func(t *testing.T) {
+		out := getJSON(t, s, "/api/v1/identity/handle/alice.cert/history")
+		history, _ := out["history"].([]any)
+		want := []struct{ event, address string }{
+			{"claimed", testBalanceAddr},
+			{"released", testBalanceAddr},
+			{"claimed", validateCreator},
+		}
+		if len(history) != len(want) {
+			t.Fatalf("history = %v", history)
+		}
+		for i, w := range want {
+			entry := history[i].(map[string]any)
+			if entry["event"] != w.event || entry["address"] != w.address {
+				t.Errorf("history[%d] = %v, want %s by %s", i, entry, w.event, w.address)
+			}
+		}
+		if out := getJSON(t, s, "/api/v1/identity/handle/nobody/history"); len(out["history"].([]any)) != 0 {
+			t.Errorf("history of an unclaimed handle = %v", out)
+		}
+	})
+
+	t.Run("an on-chain registration verifies and takes over the handle", func(t *testing.T) {
+		if rec := claimHandle(t, s, testBalanceAddr, "carol"); rec.Code != http.StatusConflict {
+			t.Errorf("claim of another address's on-chain handle = %d", rec.Code)
+		}
+
+		// An unverified holder is displaced by the on-chain owner
+		if rec := release(validateCreator); rec.Code != http.StatusOK {
+			t.Fatalf("release = %d", rec.Code)
+		}
+		if _, err := s.handles.ClaimHandle(context.Background(), &database.HandleRegistration{Handle: "carol", Address: validateCreator, CreatedAt: clock.Now()}); err != nil {
+			t.Fatal(err)
+		}
+		if rec := claimHandle(t, s, validateAttester, "carol"); rec.Code != http.StatusCreated {
+			t.Fatalf("verified claim = %d: %s", rec.Code, rec.Body.String())
+		}
+		out := getJSON(t, s, "/api/v1/identity/"+validateAttester)
+		if out["handle"] != "carol.cert" || out["handle_verified"] != true {
+			t.Errorf("identity = handle %v, verified %v", out["handle"], out["handle_verified"])
+		}
+		history := getJSON(t, s, "/api/v1/identity/handle/carol/history")["history"].([]any)
+		last := history[len(history)-1].(map[string]any)
+		if last["event"] != "transferred" || last["address"] != validateAttester || last["from_address"] != validateCreator {
+			t.Errorf("transfer entry = %v", last)
+		}
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
@@ -15,6 +17,7 @@ import (
 	"time"
 
 	"github.com/chaincertify/certd/api/database"
+	"github.com/chaincertify/certd/api/retry"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -24,18 +27,35 @@ type DiditConfig struct {
 	APIKey        string
 	WebhookSecret string
 	WorkflowID    string
+	L2WorkflowID  string // Workflow with liveness and proof of address; WorkflowID when unset
 	BaseURL       string
 }
 
 func getDiditConfig() *DiditConfig {
-	return &DiditConfig{
+	config := &DiditConfig{
 		APIKey:        os.Getenv("DIDIT_API_KEY"),
 		WebhookSecret: os.Getenv("DIDIT_WEBHOOK_SECRET"),
 		WorkflowID:    os.Getenv("DIDIT_WORKFLOW_ID"),
+		L2WorkflowID:  os.Getenv("DIDIT_L2_WORKFLOW_ID"),
 		BaseURL:       "https://verification.didit.me",
 	}
+	if baseURL := os.Getenv("DIDIT_BASE_URL"); baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	return config
+}
+
+// workflowFor returns the Didit workflow that verifies a KYC level
+func (c *DiditConfig) workflowFor(level int) string {
+	if level >= kycLevelAdvanced && c.L2WorkflowID != "" {
+		return c.L2WorkflowID
+	}
+	return c.WorkflowID
 }
 
+// diditRetry retries Didit calls that fail on the network, 429 or 5xx
+var diditRetry = retry.DefaultPolicy()
+
 // DiditSessionRequest is the request to create a Didit verification session
 type DiditSessionRequest struct {
 	WorkflowID string            `json:"workflow_id"`
@@ -58,6 +78,7 @@ type DiditSessionResponse struct {
 // KYCStartRequest is the request from frontend to start KYC
 type KYCStartRequest struct {
 	CallbackURL string `json:"callback_url,omitempty"`
+	Level       int    `json:"level,omitempty"` // 1 (default) or 2
 }
 
 // KYCStartResponse is the response to frontend
@@ -71,8 +92,13 @@ type KYCStartResponse struct {
 type KYCStatusResponse struct {
 	Status      string     `json:"status"`
 	SessionID   string     `json:"session_id,omitempty"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CompletedAt *Timestamp `json:"completed_at,omitempty"`
+	ExpiresAt   *Timestamp `json:"expires_at,omitempty"` // When an approval must be renewed
 	HasKYC      bool       `json:"has_kyc"`
+	Level       int        `json:"level"` // Highest unexpired approved level, 0 if none
+
+	// FailureReason explains a Declined, Abandoned or Expired latest session
+	FailureReason *database.KYCFailureReason `json:"failure_reason,omitempty"`
 }
 
 // handleStartKYC creates a new Didit KYC session for the authenticated user
@@ -84,26 +110,46 @@ func (s *Server) handleStartKYC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Parse optional callback and level from request
+	var req KYCStartRequest
+	if err := decodeJSONBody(w, r, &req); err != nil && !errors.Is(err, errEmptyBody) {
+		s.respondDecodeError(w, err)
+		return
+	}
+	if req.Level == 0 {
+		req.Level = kycLevelBasic
+	}
+	if req.Level != kycLevelBasic && req.Level != kycLevelAdvanced {
+		s.respondError(w, http.StatusBadRequest, "level must be 1 or 2")
+		return
+	}
+
+	// Get Didit config
+	config := getDiditConfig()
+	workflowID := config.workflowFor(req.Level)
+
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	// Check if user already has approved KYC
-	if s.db != nil {
-		hasKYC, err := s.db.HasApprovedKYC(ctx, userAddress)
+	// Check if user already has unexpired KYC at this level; a lower level may be upgraded
+	if s.kyc != nil {
+		level, err := s.kyc.ApprovedKYCLevel(ctx, userAddress, s.kycApprovedSince())
 		if err != nil {
 			s.logger.Error("Failed to check KYC status", zap.Error(err))
 		}
-		if hasKYC {
+		if level >= req.Level {
 			s.respondError(w, http.StatusConflict, "KYC already approved")
 			return
 		}
 
-		// Check for pending session
-		existing, err := s.db.GetKYCSessionByUserAddress(ctx, userAddress)
+		// Check for pending session for the same workflow; after a terminal
+		// failure (Declined, Abandoned, Expired) a fresh session is started
+		existing, err := s.kyc.GetKYCSessionByUserAddress(ctx, userAddress)
 		if err != nil {
 			s.logger.Error("Failed to get existing KYC session", zap.Error(err))
 		}
-		if existing != nil && (existing.Status == database.KYCStatusInProgress || existing.Status == database.KYCStatusNotStarted) {
+		if existing != nil && existing.WorkflowID == workflowID &&
+			(existing.Status == database.KYCStatusInProgress || existing.Status == database.KYCStatusNotStarted) {
 			// Return existing session URL
 			s.respondJSON(w, http.StatusOK, KYCStartResponse{
 				SessionID:  existing.SessionID,
@@ -114,22 +160,14 @@ func (s *Server) handleStartKYC(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Get Didit config
-	config := getDiditConfig()
-	if config.APIKey == "" || config.WorkflowID == "" {
+	if config.APIKey == "" || workflowID == "" {
 		s.respondError(w, http.StatusServiceUnavailable, "KYC service not configured")
 		return
 	}
 
-	// Parse optional callback from request
-	var req KYCStartRequest
-	if r.Body != nil {
-		json.NewDecoder(r.Body).Decode(&req)
-	}
-
 	// Create Didit session
 	diditReq := DiditSessionRequest{
-		WorkflowID: config.WorkflowID,
+		WorkflowID: workflowID,
 		VendorData: userAddress,
 		Callback:   req.CallbackURL,
 		Metadata: map[string]string{
@@ -139,17 +177,16 @@ func (s *Server) handleStartKYC(w http.ResponseWriter, r *http.Request) {
 	}
 
 	reqBody, _ := json.Marshal(diditReq)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", config.BaseURL+"/v2/session/", bytes.NewReader(reqBody))
-	if err != nil {
-		s.respondError(w, http.StatusInternalServerError, "Failed to create request")
-		return
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("X-Api-Key", config.APIKey)
-
 	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(httpReq)
+	resp, err := retry.DoHTTP(ctx, diditRetry, client, func(ctx context.Context) (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", config.BaseURL+"/v2/session/", bytes.NewReader(reqBody))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("X-Api-Key", config.APIKey)
+		return httpReq, nil
+	})
 	if err != nil {
 		s.logger.Error("Didit API request failed", zap.Error(err))
 		s.respondError(w, http.StatusBadGateway, "KYC service unavailable")
@@ -171,7 +208,7 @@ func (s *Server) handleStartKYC(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Store session in database
-	if s.db != nil {
+	if s.kyc != nil {
 		session := &database.KYCSession{
 			SessionID:   diditResp.SessionID,
 			UserAddress: userAddress,
@@ -180,7 +217,7 @@ func (s *Server) handleStartKYC(w http.ResponseWriter, r *http.Request) {
 			SessionURL:  diditResp.URL,
 			VendorData:  userAddress,
 		}
-		if err := s.db.CreateKYCSession(ctx, session); err != nil {
+		if err := s.kyc.CreateKYCSession(ctx, session); err != nil {
 			s.logger.Error("Failed to store KYC session", zap.Error(err))
 			// Continue anyway - user can still verify
 		}
@@ -210,7 +247,7 @@ func (s *Server) handleGetKYCStatus(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	if s.db == nil {
+	if s.kyc == nil {
 		s.respondJSON(w, http.StatusOK, KYCStatusResponse{
 			Status: "unknown",
 			HasKYC: false,
@@ -218,24 +255,23 @@ func (s *Server) handleGetKYCStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check for approved KYC
-	hasKYC, _ := s.db.HasApprovedKYC(ctx, userAddress)
+	// Check for unexpired approved KYC
+	level, _ := s.kyc.ApprovedKYCLevel(ctx, userAddress, s.kycApprovedSince())
 
 	// Get latest session
-	session, err := s.db.GetKYCSessionByUserAddress(ctx, userAddress)
+	session, err := s.kyc.GetKYCSessionByUserAddress(ctx, userAddress)
 	if err != nil {
 		s.logger.Error("Failed to get KYC session", zap.Error(err))
 	}
 
 	resp := KYCStatusResponse{
 		Status: "none",
-		HasKYC: hasKYC,
+		HasKYC: level > 0,
+		Level:  level,
 	}
 
 	if session != nil {
-		resp.Status = session.Status
-		resp.SessionID = session.SessionID
-		resp.CompletedAt = session.CompletedAt
+		s.kycStatus(&resp, session)
 	}
 
 	s.respondJSON(w, http.StatusOK, resp)
@@ -265,7 +301,7 @@ func (s *Server) handleKYCWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Read raw body for signature verification
-	rawBody, err := io.ReadAll(r.Body)
+	rawBody, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxJSONBodyBytes))
 	if err != nil {
 		s.logger.Error("Failed to read webhook body", zap.Error(err))
 		http.Error(w, "Failed to read body", http.StatusBadRequest)
@@ -288,7 +324,7 @@ func (s *Server) handleKYCWebhook(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid timestamp", http.StatusUnauthorized)
 		return
 	}
-	currentTime := time.Now().Unix()
+	currentTime := s.clock.Now().Unix()
 	if abs(currentTime-ts) > 300 {
 		s.logger.Warn("Webhook timestamp too old", zap.Int64("timestamp", ts), zap.Int64("current", currentTime))
 		http.Error(w, "Request timestamp is stale", http.StatusUnauthorized)
@@ -335,30 +371,32 @@ func (s *Server) handleKYCWebhook(w http.ResponseWriter, r *http.Request) {
 		decisionJSON = &str
 	}
 
-	// Update session status in database
-	if s.db != nil {
-		if err := s.db.UpdateKYCSessionStatus(ctx, payload.SessionID, payload.Status, decisionJSON); err != nil {
-			s.logger.Error("Failed to update KYC session", zap.Error(err))
+	if s.kyc != nil {
+		// Didit retries a delivery until it gets a 2xx; process each one once
+		digest := sha256.Sum256(rawBody)
+		deliveryID := hex.EncodeToString(digest[:])
+		claimed, err := s.kyc.RecordKYCWebhookDelivery(ctx, deliveryID, payload.SessionID, payload.Status, s.clock.Now())
+		if err != nil {
+			s.logger.Error("Failed to record KYC webhook delivery", zap.Error(err), zap.String("session_id", payload.SessionID))
+			http.Error(w, "Failed to process webhook", http.StatusInternalServerError)
+			return
+		}
+		if !claimed {
+			s.logger.Info("Duplicate KYC webhook ignored", zap.String("session_id", payload.SessionID), zap.String("delivery_id", deliveryID))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(map[string]string{"message": "Webhook already processed"})
+			return
 		}
 
-		// If approved, add KYC_L1 credential/badge to user
-		if payload.Status == database.KYCStatusApproved {
-			userAddress := strings.ToLower(payload.VendorData)
-			if userAddress != "" {
-				credential := &database.Credential{
-					UserAddress:    userAddress,
-					CredentialType: "KYC_L1",
-					AttestationUID: "kyc_didit_" + payload.SessionID,
-					Issuer:         "didit.me",
-					Verified:       true,
-					IssuedAt:       time.Now(),
-				}
-				if err := s.db.AddCredential(ctx, credential); err != nil {
-					s.logger.Error("Failed to add KYC credential", zap.Error(err), zap.String("user", userAddress))
-				} else {
-					s.logger.Info("KYC_L1 badge awarded", zap.String("user", userAddress))
-				}
+		if err := s.processKYCWebhook(ctx, &payload, decisionJSON); err != nil {
+			// Release the delivery so Didit's retry is processed
+			s.logger.Error("Failed to process KYC webhook", zap.Error(err), zap.String("session_id", payload.SessionID))
+			if err := s.kyc.ForgetKYCWebhookDelivery(ctx, deliveryID); err != nil {
+				s.logger.Error("Failed to release KYC webhook delivery", zap.Error(err), zap.String("delivery_id", deliveryID))
 			}
+			http.Error(w, "Failed to process webhook", http.StatusInternalServerError)
+			return
 		}
 	}
 
@@ -368,6 +406,53 @@ func (s *Server) handleKYCWebhook(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"message": "Webhook processed"})
 }
 
+// processKYCWebhook applies a Didit status update to its session, recording why
+// a failed session failed and awarding the credential an approval proves.
+// Deliveries may arrive out of order, so an approved session is never moved
+// back to an earlier status.
+func (s *Server) processKYCWebhook(ctx context.Context, payload *DiditWebhookPayload, decisionJSON *string) error {
+	session, err := s.kyc.GetKYCSessionBySessionID(ctx, payload.SessionID)
+	if err != nil {
+		return err
+	}
+	if session != nil && session.Status == database.KYCStatusApproved && payload.Status != database.KYCStatusApproved {
+		s.logger.Info("Stale KYC webhook ignored for approved session",
+			zap.String("session_id", payload.SessionID),
+			zap.String("status", payload.Status),
+		)
+		return nil
+	}
+
+	// Update session status in database
+	if err := s.kyc.UpdateKYCSessionStatus(ctx, payload.SessionID, payload.Status, decisionJSON); err != nil {
+		s.logger.Error("Failed to update KYC session", zap.Error(err))
+	}
+
+	// If it failed, record why so the user can see it before retrying
+	if kycTerminalFailure(payload.Status) {
+		reason := kycFailureReason(payload.Status, decisionJSON)
+		if err := s.kyc.SetKYCSessionFailureReason(ctx, payload.SessionID, reason); err != nil {
+			s.logger.Error("Failed to record KYC failure reason", zap.Error(err), zap.String("session_id", payload.SessionID))
+		}
+	}
+
+	// If approved, add the KYC_L1 or KYC_L2 credential/badge the decision proves
+	if payload.Status == database.KYCStatusApproved {
+		userAddress := strings.ToLower(payload.VendorData)
+		if userAddress != "" {
+			level := kycDecisionLevel(decisionJSON)
+			_, awarded, err := s.awardKYCCredential(ctx, payload.SessionID, userAddress, level)
+			if err != nil {
+				return fmt.Errorf("failed to add KYC credential for %s: %w", userAddress, err)
+			}
+			if awarded {
+				s.logger.Info("KYC badge awarded", zap.String("user", userAddress), zap.String("credential", kycCredentialType(level)))
+			}
+		}
+	}
+	return nil
+}
+
 // abs returns absolute value of int64
 func abs(n int64) int64 {
 	if n < 0 {
@@ -391,12 +476,12 @@ func (s *Server) handleGetKYCSession(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	if s.db == nil {
+	if s.kyc == nil {
 		s.respondError(w, http.StatusServiceUnavailable, "Database not available")
 		return
 	}
 
-	session, err := s.db.GetKYCSessionBySessionID(ctx, sessionID)
+	session, err := s.kyc.GetKYCSessionBySessionID(ctx, sessionID)
 	if err != nil {
 		s.respondError(w, http.StatusInternalServerError, "Failed to get session")
 		return
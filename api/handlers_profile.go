@@ -4,7 +4,6 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -18,15 +17,17 @@ import (
 // UserProfile represents a CertID profile
 // Per CertID Section 2.2: user_profiles table structure
 type UserProfile struct {
-	Address     string            `json:"address"`
-	CertIDUID   string            `json:"certid_uid,omitempty"`
-	Name        string            `json:"name"`
-	Bio         string            `json:"bio"`
-	AvatarURL   string            `json:"avatar_url"`
-	SocialLinks map[string]string `json:"social_links"`
-	Credentials []Credential      `json:"credentials"`
-	CreatedAt   int64             `json:"created_at"`
-	UpdatedAt   int64             `json:"updated_at"`
+	Address       string            `json:"address"`
+	CertIDUID     string            `json:"certid_uid,omitempty"`
+	Name          string            `json:"name"`
+	Bio           string            `json:"bio"`
+	AvatarURL     string            `json:"avatar_url"`
+	SocialLinks   map[string]string `json:"social_links"`
+	Credentials   []Credential      `json:"credentials"`
+	CreatedAt     Timestamp         `json:"created_at"`
+	CreatedAtUnix int64             `json:"created_at_unix"`
+	UpdatedAt     Timestamp         `json:"updated_at"`
+	UpdatedAtUnix int64             `json:"updated_at_unix"`
 }
 
 // generateCertIDUID generates a unique CertID UID based on address and timestamp
@@ -38,12 +39,13 @@ func generateCertIDUID(address string) string {
 
 // Credential represents a verified credential linked to a profile
 type Credential struct {
-	ID             string `json:"id"`
-	Type           string `json:"type"`
-	AttestationUID string `json:"attestation_uid"`
-	Issuer         string `json:"issuer"`
-	IssuedAt       int64  `json:"issued_at"`
-	Verified       bool   `json:"verified"`
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	AttestationUID string    `json:"attestation_uid"`
+	Issuer         string    `json:"issuer"`
+	IssuedAt       Timestamp `json:"issued_at"`
+	IssuedAtUnix   int64     `json:"issued_at_unix"`
+	Verified       bool      `json:"verified"`
 }
 
 // UpdateProfileRequest represents a profile update request
@@ -97,8 +99,8 @@ func (s *Server) handleGetProfile(w http.ResponseWriter, r *http.Request) {
 		resp.Bio = prof.Bio
 		resp.AvatarURL = prof.AvatarURL
 		resp.SocialLinks = prof.SocialLinks
-		resp.CreatedAt = prof.CreatedAt.Unix()
-		resp.UpdatedAt = prof.UpdatedAt.Unix()
+		resp.CreatedAt, resp.CreatedAtUnix = NewTimestamp(prof.CreatedAt), prof.CreatedAt.Unix()
+		resp.UpdatedAt, resp.UpdatedAtUnix = NewTimestamp(prof.UpdatedAt), prof.UpdatedAt.Unix()
 	}
 
 	for _, c := range creds {
@@ -107,7 +109,8 @@ func (s *Server) handleGetProfile(w http.ResponseWriter, r *http.Request) {
 			Type:           c.CredentialType,
 			AttestationUID: c.AttestationUID,
 			Issuer:         c.Issuer,
-			IssuedAt:       c.IssuedAt.Unix(),
+			IssuedAt:       NewTimestamp(c.IssuedAt),
+			IssuedAtUnix:   c.IssuedAt.Unix(),
 			Verified:       c.Verified,
 		})
 	}
@@ -124,8 +127,8 @@ func (s *Server) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req UpdateProfileRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 
@@ -191,8 +194,8 @@ func (s *Server) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
 		resp.Bio = prof.Bio
 		resp.AvatarURL = prof.AvatarURL
 		resp.SocialLinks = prof.SocialLinks
-		resp.CreatedAt = prof.CreatedAt.Unix()
-		resp.UpdatedAt = prof.UpdatedAt.Unix()
+		resp.CreatedAt, resp.CreatedAtUnix = NewTimestamp(prof.CreatedAt), prof.CreatedAt.Unix()
+		resp.UpdatedAt, resp.UpdatedAtUnix = NewTimestamp(prof.UpdatedAt), prof.UpdatedAt.Unix()
 	}
 
 	s.respondJSON(w, http.StatusOK, resp)
@@ -216,8 +219,8 @@ func (s *Server) handleVerifySocial(w http.ResponseWriter, r *http.Request) {
 	address := getAuthenticatedAddress(r)
 
 	var req VerifySocialRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 	if address == "" {
@@ -252,7 +255,7 @@ func (s *Server) handleVerifySocial(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// The post must contain the user's address to verify ownership
-	found, err := fetchAndVerifyPost(req.Proof, address)
+	found, err := s.socialPosts.fetchAndVerifyPost(r.Context(), req.Platform, req.Proof, address)
 	if err != nil {
 		s.logger.Warn("Failed to fetch proof", zap.String("url", req.Proof), zap.Error(err))
 		s.respondError(w, http.StatusBadRequest, "Could not fetch proof URL. Ensure post is public.")
@@ -266,7 +269,7 @@ func (s *Server) handleVerifySocial(w http.ResponseWriter, r *http.Request) {
 
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
-	now := time.Now()
+	now := s.clock.Now()
 	err = s.db.AddSocialVerification(ctx, &database.SocialVerification{
 		UserAddress: address,
 		Platform:    req.Platform,
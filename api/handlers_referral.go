@@ -3,11 +3,15 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
+	"github.com/chaincertify/certd/api/database"
 	"go.uber.org/zap"
 )
 
@@ -89,9 +93,20 @@ func (s *Server) handleGetReferralCode(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// referralSeasonParam parses the optional ?season= filter; 0 selects the current season
+func referralSeasonParam(r *http.Request) (int, bool) {
+	v := r.URL.Query().Get("season")
+	if v == "" {
+		return 0, true
+	}
+	id, err := strconv.Atoi(v)
+	return id, err == nil && id > 0
+}
+
 // referralStatsResponse is the response for GET /referral/stats
 type referralStatsResponse struct {
 	OK                bool   `json:"ok"`
+	SeasonID          int    `json:"season_id,omitempty"`
 	TotalReferrals    int    `json:"total_referrals"`
 	VerifiedReferrals int    `json:"verified_referrals"`
 	TotalPoints       int    `json:"total_points"`
@@ -100,8 +115,8 @@ type referralStatsResponse struct {
 	Error             string `json:"error,omitempty"`
 }
 
-// handleReferralStats returns the user's referral statistics
-// GET /api/v1/referral/stats
+// handleReferralStats returns the user's referral statistics for a season
+// GET /api/v1/referral/stats?season=
 func (s *Server) handleReferralStats(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	
@@ -118,7 +133,14 @@ func (s *Server) handleReferralStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	stats, err := s.db.GetReferralStats(r.Context(), address)
+	season, ok := referralSeasonParam(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(referralStatsResponse{OK: false, Error: "Invalid season"})
+		return
+	}
+
+	stats, err := s.db.GetReferralStats(r.Context(), address, season)
 	if err != nil {
 		s.logger.Error("Failed to get referral stats", zap.Error(err))
 		w.WriteHeader(http.StatusInternalServerError)
@@ -131,6 +153,7 @@ func (s *Server) handleReferralStats(w http.ResponseWriter, r *http.Request) {
 	
 	json.NewEncoder(w).Encode(referralStatsResponse{
 		OK:                true,
+		SeasonID:          stats.SeasonID,
 		TotalReferrals:    stats.TotalReferrals,
 		VerifiedReferrals: stats.VerifiedReferrals,
 		TotalPoints:       stats.TotalPoints,
@@ -159,6 +182,9 @@ func getNextTier(current int) (nextAt int, bonus int) {
 type leaderboardResponse struct {
 	OK          bool                          `json:"ok"`
 	Leaderboard []leaderboardEntry            `json:"leaderboard,omitempty"`
+	Total       int                           `json:"total"`
+	Offset      int                           `json:"offset"`
+	Limit       int                           `json:"limit"`
 	Error       string                        `json:"error,omitempty"`
 }
 
@@ -167,10 +193,47 @@ type leaderboardEntry struct {
 	DisplayName   string `json:"display_name"`
 	ReferralCount int    `json:"referral_count"`
 	TotalPoints   int    `json:"total_points"`
+	Self          bool   `json:"self,omitempty"`
 }
 
-// handleReferralLeaderboard returns the top referrers
-// GET /api/v1/referral/leaderboard
+// toLeaderboardEntries converts database entries to the response format,
+// which leaves out even the masked address
+func toLeaderboardEntries(entries []database.LeaderboardEntry) []leaderboardEntry {
+	out := make([]leaderboardEntry, len(entries))
+	for i, e := range entries {
+		out[i] = leaderboardEntry{
+			Rank:          e.Rank,
+			DisplayName:   e.DisplayName,
+			ReferralCount: e.ReferralCount,
+			TotalPoints:   e.TotalPoints,
+			Self:          e.Self,
+		}
+	}
+	return out
+}
+
+// leaderboardPage parses ?limit= and ?offset=, falling back to the first 25
+// entries; offsets are capped so a page query stays cheap
+func leaderboardPage(r *http.Request) (limit, offset int) {
+	limit = 25
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	if o := r.URL.Query().Get("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed > 0 {
+			offset = min(parsed, maxLeaderboardOffset)
+		}
+	}
+	return limit, offset
+}
+
+// maxLeaderboardOffset caps how deep the leaderboard can be paged
+const maxLeaderboardOffset = 100_000
+
+// handleReferralLeaderboard returns the top referrers of a season
+// GET /api/v1/referral/leaderboard?season=
 func (s *Server) handleReferralLeaderboard(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	
@@ -180,14 +243,15 @@ func (s *Server) handleReferralLeaderboard(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	
-	limit := 25
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
-			limit = parsed
-		}
+	limit, offset := leaderboardPage(r)
+	season, ok := referralSeasonParam(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(leaderboardResponse{OK: false, Error: "Invalid season"})
+		return
 	}
-	
-	entries, err := s.db.GetReferralLeaderboard(r.Context(), limit)
+
+	entries, total, err := s.db.GetReferralLeaderboard(r.Context(), limit, offset, season)
 	if err != nil {
 		s.logger.Error("Failed to get leaderboard", zap.Error(err))
 		w.WriteHeader(http.StatusInternalServerError)
@@ -195,20 +259,83 @@ func (s *Server) handleReferralLeaderboard(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	
-	// Convert to response format
-	respEntries := make([]leaderboardEntry, len(entries))
-	for i, e := range entries {
-		respEntries[i] = leaderboardEntry{
-			Rank:          e.Rank,
-			DisplayName:   e.DisplayName,
-			ReferralCount: e.ReferralCount,
-			TotalPoints:   e.TotalPoints,
-		}
-	}
-	
 	json.NewEncoder(w).Encode(leaderboardResponse{
 		OK:          true,
-		Leaderboard: respEntries,
+		Leaderboard: toLeaderboardEntries(entries),
+		Total:       total,
+		Offset:      offset,
+		Limit:       limit,
+	})
+}
+
+// referralRankWindow is how many entries either side of the user /referral/rank
+// returns by default, and maxReferralRankWindow the most it accepts
+const (
+	referralRankWindow    = 2
+	maxReferralRankWindow = 10
+)
+
+// referralRankResponse is the response for GET /referral/rank
+type referralRankResponse struct {
+	OK            bool               `json:"ok"`
+	SeasonID      int                `json:"season_id,omitempty"`
+	Rank          int                `json:"rank"` // 0 when the user has no points this season
+	TotalPoints   int                `json:"total_points"`
+	ReferralCount int                `json:"referral_count"`
+	Neighbors     []leaderboardEntry `json:"neighbors,omitempty"`
+	Error         string             `json:"error,omitempty"`
+}
+
+// handleReferralRank returns the user's leaderboard rank and the entries around it
+// GET /api/v1/referral/rank?season=&window=
+func (s *Server) handleReferralRank(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	address := getAuthenticatedAddress(r)
+	if address == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(referralRankResponse{OK: false, Error: "Authentication required"})
+		return
+	}
+
+	if s.db == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(referralRankResponse{OK: false, Error: "Database unavailable"})
+		return
+	}
+
+	season, ok := referralSeasonParam(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(referralRankResponse{OK: false, Error: "Invalid season"})
+		return
+	}
+	window := referralRankWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 || parsed > maxReferralRankWindow {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(referralRankResponse{OK: false, Error: "window must be between 0 and " + strconv.Itoa(maxReferralRankWindow)})
+			return
+		}
+		window = parsed
+	}
+
+	rank, err := s.db.GetReferralRank(r.Context(), address, window, season)
+	if err != nil {
+		s.logger.Error("Failed to get referral rank", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(referralRankResponse{OK: false, Error: "Failed to get rank"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(referralRankResponse{
+		OK:            true,
+		SeasonID:      rank.SeasonID,
+		Rank:          rank.Rank,
+		TotalPoints:   rank.TotalPoints,
+		ReferralCount: rank.ReferralCount,
+		Neighbors:     toLeaderboardEntries(rank.Neighbors),
 	})
 }
 
@@ -244,7 +371,7 @@ func (s *Server) handleRedeemReferral(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	var req redeemRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(w, r, &req); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(redeemResponse{OK: false, Error: "Invalid request body"})
 		return
@@ -290,12 +417,73 @@ func (s *Server) verifyReferralForUser(address string) {
 	if s.db == nil {
 		return
 	}
-	
-	ctx := s.router.NewRoute().GetHandler() // Simple context, not ideal but works
-	_ = ctx // unused in this simplified version
-	
-	err := s.db.VerifyReferral(nil, address, referralConfig.PointsPerReferral)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.db.VerifyReferral(ctx, address, referralConfig.PointsPerReferral)
 	if err != nil {
 		s.logger.Warn("Failed to verify referral", zap.String("address", address), zap.Error(err))
 	}
 }
+
+// referralSeasonStore starts referral seasons; *database.DB implements it
+type referralSeasonStore interface {
+	StartReferralSeason(ctx context.Context, name string, decayFactor float64) (*database.ReferralSeason, error)
+}
+
+// startSeasonRequest is the request body for POST /admin/referral/season
+type startSeasonRequest struct {
+	Name        string  `json:"name"`
+	DecayFactor float64 `json:"decay_factor"` // Share of earlier seasons' points kept, 0 to 1
+}
+
+// startSeasonResponse is the response for POST /admin/referral/season
+type startSeasonResponse struct {
+	OK     bool                     `json:"ok"`
+	Season *database.ReferralSeason `json:"season,omitempty"`
+	Error  string                   `json:"error,omitempty"`
+}
+
+// handleStartReferralSeason closes the current referral season and opens a new one
+// POST /api/v1/admin/referral/season
+func (s *Server) handleStartReferralSeason(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if s.referralSeasons == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(startSeasonResponse{OK: false, Error: "Database unavailable"})
+		return
+	}
+
+	var req startSeasonRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(startSeasonResponse{OK: false, Error: "Invalid request body"})
+		return
+	}
+	if math.IsNaN(req.DecayFactor) || req.DecayFactor < 0 || req.DecayFactor > 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(startSeasonResponse{OK: false, Error: "decay_factor must be between 0 and 1"})
+		return
+	}
+	if len(req.Name) > 100 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(startSeasonResponse{OK: false, Error: "name must be at most 100 characters"})
+		return
+	}
+
+	season, err := s.referralSeasons.StartReferralSeason(r.Context(), req.Name, req.DecayFactor)
+	if err != nil {
+		s.logger.Error("Failed to start referral season", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(startSeasonResponse{OK: false, Error: "Failed to start season"})
+		return
+	}
+
+	s.logger.Info("Started referral season",
+		zap.String("admin", getAuthenticatedAddress(r)),
+		zap.Int("season_id", season.ID),
+		zap.Float64("decay_factor", season.DecayFactor))
+	json.NewEncoder(w).Encode(startSeasonResponse{OK: true, Season: season})
+}
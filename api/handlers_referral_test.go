@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/chaincertify/certd/api/database"
 	"go.uber.org/zap"
@@ -79,7 +81,7 @@ func TestReferralCodeRedemption(t *testing.T) {
 	}
 
 	// Verify stats updated
-	stats, err := db.GetReferralStats(ctx, referrer)
+	stats, err := db.GetReferralStats(ctx, referrer, 0)
 	if err != nil {
 		t.Fatalf("Failed to get stats: %v", err)
 	}
@@ -156,7 +158,7 @@ func TestLeaderboard(t *testing.T) {
 	ctx := context.Background()
 
 	// Get leaderboard (may be empty)
-	entries, err := db.GetReferralLeaderboard(ctx, 10)
+	entries, _, err := db.GetReferralLeaderboard(ctx, 10, 0, 0)
 	if err != nil {
 		t.Fatalf("Failed to get leaderboard: %v", err)
 	}
@@ -235,3 +237,112 @@ func TestReferralAPIEndpoints(t *testing.T) {
 		}
 	})
 }
+
+// stubReferralSeasonStore records the seasons started through it
+type stubReferralSeasonStore struct {
+	seasons []database.ReferralSeason
+}
+
+func (m *stubReferralSeasonStore) StartReferralSeason(_ context.Context, name string, decayFactor float64) (*database.ReferralSeason, error) {
+	now := time.Unix(1_700_000_000, 0).UTC()
+	if n := len(m.seasons); n > 0 {
+		m.seasons[n-1].EndedAt = &now
+	}
+	m.seasons = append(m.seasons, database.ReferralSeason{ID: len(m.seasons) + 2, Name: name, DecayFactor: decayFactor, StartedAt: now})
+	return &m.seasons[len(m.seasons)-1], nil
+}
+
+func TestStartReferralSeason(t *testing.T) {
+	const admin = "cert1admin"
+	config := DefaultConfig()
+	config.AdminAddresses = []string{admin}
+	s := NewServer(config, zap.NewNop())
+	store := &stubReferralSeasonStore{}
+	s.referralSeasons = store
+
+	start := func(caller, body string) (int, startSeasonResponse) {
+		req := httptest.NewRequest("POST", "/api/v1/admin/referral/season", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if caller != "" {
+			req.Header.Set("Authorization", "Bearer "+testAuthToken(t, config.JWTSecret, caller))
+		}
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		var out startSeasonResponse
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+
+	if code, _ := start("", `{"name":"Season 2"}`); code != http.StatusUnauthorized {
+		t.Errorf("anonymous status = %d, want 401", code)
+	}
+	if code, _ := start("cert1user", `{"name":"Season 2"}`); code != http.StatusForbidden {
+		t.Errorf("non-admin status = %d, want 403", code)
+	}
+	for _, body := range []string{`{"decay_factor":1.5}`, `{"decay_factor":-0.1}`, `{"name":"` + strings.Repeat("x", 101) + `"}`} {
+		if code, _ := start(admin, body); code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, code)
+		}
+	}
+	if len(store.seasons) != 0 {
+		t.Fatalf("%d seasons started by rejected requests", len(store.seasons))
+	}
+
+	code, out := start(admin, `{"name":"Season 2","decay_factor":0.5}`)
+	if code != http.StatusOK || !out.OK || out.Season == nil || out.Season.Name != "Season 2" || out.Season.DecayFactor != 0.5 {
+		t.Fatalf("start = %d, %+v", code, out)
+	}
+	if len(store.seasons) != 1 {
+		t.Errorf("seasons started = %d, want 1", len(store.seasons))
+	}
+}
+
+func TestReferralSeasonParam(t *testing.T) {
+	for query, want := range map[string]struct {
+		id int
+		ok bool
+	}{
+		"":           {0, true},
+		"?season=3":  {3, true},
+		"?season=0":  {0, false},
+		"?season=-1": {0, false},
+		"?season=x":  {0, false},
+	} {
+		id, ok := referralSeasonParam(httptest.NewRequest("GET", "/api/v1/referral/leaderboard"+query, nil))
+		if ok != want.ok || (ok && id != want.id) {
+			t.Errorf("%q: referralSeasonParam = %d, %v; want %d, %v", query, id, ok, want.id, want.ok)
+		}
+	}
+}
+
+func TestLeaderboardPage(t *testing.T) {
+	for query, want := range map[string][2]int{
+		"":                     {25, 0},
+		"?limit=10&offset=190": {10, 190},
+		"?limit=500&offset=-5": {25, 0},
+		"?limit=x&offset=y":    {25, 0},
+		"?offset=999999999":    {25, maxLeaderboardOffset},
+	} {
+		limit, offset := leaderboardPage(httptest.NewRequest("GET", "/api/v1/referral/leaderboard"+query, nil))
+		if limit != want[0] || offset != want[1] {
+			t.Errorf("%q: leaderboardPage = %d, %d; want %d, %d", query, limit, offset, want[0], want[1])
+		}
+	}
+}
+
+func TestReferralRankRequiresAuth(t *testing.T) {
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/referral/rank", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated rank = %d, want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/referral/rank", nil)
+	req.Header.Set("Authorization", "Bearer "+testAuthToken(t, s.config.JWTSecret, "cert1user"))
+	rec = httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("rank without a database = %d, want 503", rec.Code)
+	}
+}
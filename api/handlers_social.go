@@ -3,12 +3,9 @@ package api
 import (
 	"crypto/rand"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/chaincertify/certd/api/database"
 	"github.com/gorilla/mux"
@@ -54,10 +51,11 @@ type socialVerifyResponse struct {
 }
 
 type socialAccountResponse struct {
-	Platform   string `json:"platform"`
-	Name       string `json:"name"`
-	PostURL    string `json:"post_url,omitempty"`
-	VerifiedAt int64  `json:"verified_at"`
+	Platform       string    `json:"platform"`
+	Name           string    `json:"name"`
+	PostURL        string    `json:"post_url,omitempty"`
+	VerifiedAt     Timestamp `json:"verified_at"`
+	VerifiedAtUnix int64     `json:"verified_at_unix"`
 }
 
 type socialStatusResponse struct {
@@ -74,7 +72,7 @@ func (s *Server) handleSocialGenerate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req socialGenerateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(w, r, &req); err != nil {
 		s.respondJSON(w, http.StatusBadRequest, socialGenerateResponse{Error: "invalid request"})
 		return
 	}
@@ -85,6 +83,11 @@ func (s *Server) handleSocialGenerate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Limit how often an address can mint codes for a platform
+	if s.socialCodes != nil && !s.allowSocialCode(w, r, address, platform) {
+		return
+	}
+
 	// Database required for social verification
 	if s.db == nil {
 		s.respondJSON(w, http.StatusServiceUnavailable, socialGenerateResponse{Error: "database service unavailable"})
@@ -120,14 +123,17 @@ func (s *Server) handleSocialGenerate(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Store in database (expires in 24 hours)
-	expiresAt := time.Now().Add(24 * time.Hour)
-	_, err = s.db.CreateSocialVerification(ctx, address, platform, code, expiresAt)
+	// Store in database (expires after socialCodeTTL)
+	now := s.clock.Now()
+	_, err = s.db.CreateSocialVerification(ctx, address, platform, code, now.Add(socialCodeTTL))
 	if err != nil {
 		s.logger.Error("failed to create social verification", zap.Error(err))
 		s.respondJSON(w, http.StatusInternalServerError, socialGenerateResponse{Error: "failed to create verification"})
 		return
 	}
+	if err := s.socialCodes.RecordSocialCodeGeneration(ctx, address, platform, now); err != nil {
+		s.logger.Warn("failed to record social code generation", zap.Error(err))
+	}
 
 	// Generate promotional message
 	message := fmt.Sprintf("I just verified my identity on @C3RT_org - the decentralized identity and attestation platform built on blockchain. Join the future of verifiable credentials! 🔐\n\nVerification code: %s\n\nhttps://c3rt.org", code)
@@ -149,7 +155,7 @@ func (s *Server) handleSocialVerify(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req socialVerifyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(w, r, &req); err != nil {
 		s.respondJSON(w, http.StatusBadRequest, socialVerifyResponse{Error: "invalid request"})
 		return
 	}
@@ -191,14 +197,14 @@ func (s *Server) handleSocialVerify(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if code is older than 24 hours
-	if time.Since(sv.CreatedAt) > 24*time.Hour {
+	// Check if code is older than socialCodeTTL
+	if s.clock.Now().Sub(sv.CreatedAt) > socialCodeTTL {
 		s.respondJSON(w, http.StatusBadRequest, socialVerifyResponse{Error: "verification code expired. Please generate a new one."})
 		return
 	}
 
 	// Fetch the post content and check for the code (stored in Handle field)
-	found, err := fetchAndVerifyPost(req.PostURL, sv.Handle)
+	found, err := s.socialPosts.fetchAndVerifyPost(r.Context(), platform, req.PostURL, sv.Handle)
 	if err != nil {
 		s.logger.Warn("failed to fetch post", zap.String("url", req.PostURL), zap.Error(err))
 		s.respondJSON(w, http.StatusBadRequest, socialVerifyResponse{Error: "could not fetch post. Make sure the post is public."})
@@ -252,60 +258,11 @@ func (s *Server) handleSocialStatus(w http.ResponseWriter, r *http.Request) {
 			PostURL:  v.ProofURL,
 		}
 		if v.VerifiedAt != nil {
-			acc.VerifiedAt = v.VerifiedAt.Unix()
+			acc.VerifiedAt = NewTimestamp(*v.VerifiedAt)
+			acc.VerifiedAtUnix = v.VerifiedAt.Unix()
 		}
 		accounts = append(accounts, acc)
 	}
 
 	s.respondJSON(w, http.StatusOK, socialStatusResponse{Accounts: accounts})
 }
-
-// isValidPlatformURL validates that the URL matches the expected platform
-func isValidPlatformURL(platform, url string) bool {
-	switch platform {
-	case "twitter":
-		// x.com or twitter.com status URLs
-		return strings.Contains(url, "twitter.com/") || strings.Contains(url, "x.com/")
-	case "linkedin":
-		return strings.Contains(url, "linkedin.com/")
-	case "facebook":
-		return strings.Contains(url, "facebook.com/")
-	case "github":
-		// GitHub Gist URLs: gist.github.com/{username}/{gist_id}
-		return strings.Contains(url, "gist.github.com/")
-	}
-	return false
-}
-
-// fetchAndVerifyPost fetches a URL and checks if it contains the verification code
-func fetchAndVerifyPost(url, code string) (bool, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return false, err
-	}
-
-	// Set user agent to avoid blocks
-	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; C3RT-Bot/1.0)")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return false, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("status %d", resp.StatusCode)
-	}
-
-	// Read body (limit to 1MB)
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
-	if err != nil {
-		return false, err
-	}
-
-	// Check if code is present in the page
-	bodyStr := string(body)
-	return strings.Contains(bodyStr, code), nil
-}
-
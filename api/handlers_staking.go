@@ -1,9 +1,9 @@
 package api
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -22,7 +22,7 @@ type ValidatorInfo struct {
 	DelegatorShares   string `json:"delegator_shares"`
 	Description       ValidatorDescription `json:"description"`
 	UnbondingHeight   string `json:"unbonding_height"`
-	UnbondingTime     string `json:"unbonding_time"`
+	UnbondingTime     Timestamp `json:"unbonding_time"`
 	Commission        ValidatorCommission  `json:"commission"`
 	MinSelfDelegation string `json:"min_self_delegation"`
 }
@@ -37,7 +37,7 @@ type ValidatorDescription struct {
 
 type ValidatorCommission struct {
 	CommissionRates CommissionRates `json:"commission_rates"`
-	UpdateTime      string          `json:"update_time"`
+	UpdateTime      Timestamp       `json:"update_time"`
 }
 
 type CommissionRates struct {
@@ -57,21 +57,14 @@ type ValidatorsResponse struct {
 // handleGetValidators returns all validators with their staking info
 func (s *Server) handleGetValidators(w http.ResponseWriter, r *http.Request) {
 	// Try REST API first
-	url := fmt.Sprintf("%s/cosmos/staking/v1beta1/validators?status=BOND_STATUS_BONDED", getRESTBaseURL())
-
-	resp, err := restClient.Get(url)
-	if err == nil {
-		defer resp.Body.Close()
-		body, _ := io.ReadAll(resp.Body)
-		var result ValidatorsResponse
-		if json.Unmarshal(body, &result) == nil && len(result.Validators) > 0 {
-			s.respondJSON(w, http.StatusOK, result)
-			return
-		}
+	result, err := s.chain.Validators(r.Context(), "BOND_STATUS_BONDED")
+	if err == nil && len(result.Validators) > 0 {
+		s.respondJSON(w, http.StatusOK, result)
+		return
 	}
 
 	// Fallback to CometBFT RPC for consensus validators
-	rpcValidators, err := s.getValidatorsFromRPC()
+	rpcValidators, err := s.getValidatorsFromRPC(r.Context())
 	if err != nil {
 		s.logger.Warn("validators RPC query failed", zap.Error(err))
 		s.respondJSON(w, http.StatusOK, ValidatorsResponse{Validators: []ValidatorInfo{}})
@@ -82,43 +75,18 @@ func (s *Server) handleGetValidators(w http.ResponseWriter, r *http.Request) {
 }
 
 // getValidatorsFromRPC queries CometBFT RPC for validator info
-func (s *Server) getValidatorsFromRPC() (ValidatorsResponse, error) {
-	url := fmt.Sprintf("%s/validators", getRPCBaseURL())
-
-	resp, err := restClient.Get(url)
-	if err != nil {
-		return ValidatorsResponse{}, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+func (s *Server) getValidatorsFromRPC(ctx context.Context) (ValidatorsResponse, error) {
+	consensus, err := s.chain.ConsensusValidators(ctx)
 	if err != nil {
 		return ValidatorsResponse{}, err
 	}
 
-	var rpcResult struct {
-		Result struct {
-			Validators []struct {
-				Address  string `json:"address"`
-				PubKey   struct {
-					Type  string `json:"type"`
-					Value string `json:"value"`
-				} `json:"pub_key"`
-				VotingPower      string `json:"voting_power"`
-				ProposerPriority string `json:"proposer_priority"`
-			} `json:"validators"`
-		} `json:"result"`
-	}
-	if err := json.Unmarshal(body, &rpcResult); err != nil {
-		return ValidatorsResponse{}, err
-	}
-
 	// Convert RPC validators to our format
-	validators := make([]ValidatorInfo, 0, len(rpcResult.Result.Validators))
-	for i, v := range rpcResult.Result.Validators {
+	validators := make([]ValidatorInfo, 0, len(consensus))
+	for i, v := range consensus {
 		validators = append(validators, ValidatorInfo{
 			OperatorAddress: v.Address,
-			ConsensusPubkey: v.PubKey.Value,
+			ConsensusPubkey: v.PubKey,
 			Jailed:          false,
 			Status:          "BOND_STATUS_BONDED",
 			Tokens:          v.VotingPower + "000000", // Convert to ucert
@@ -157,43 +125,23 @@ func (s *Server) handleGetValidator(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	url := fmt.Sprintf("%s/cosmos/staking/v1beta1/validators/%s", getRESTBaseURL(), validatorAddr)
-	
-	resp, err := restClient.Get(url)
-	if err != nil {
-		s.logger.Warn("validator query failed", zap.String("validator", validatorAddr), zap.Error(err))
-		s.respondError(w, http.StatusBadGateway, "Failed to query validator")
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
+	validator, err := s.chain.Validator(r.Context(), validatorAddr)
+	if errors.Is(err, ErrChainNotFound) {
 		s.respondError(w, http.StatusNotFound, "Validator not found")
 		return
 	}
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		s.respondError(w, http.StatusBadGateway, "Failed to read response")
-		return
-	}
-
-	var result struct {
-		Validator ValidatorInfo `json:"validator"`
-	}
-	if err := json.Unmarshal(body, &result); err != nil {
-		s.respondError(w, http.StatusBadGateway, "Failed to parse response")
+		s.logger.Warn("validator query failed", zap.String("validator", validatorAddr), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query validator")
 		return
 	}
 
-	s.respondJSON(w, http.StatusOK, result)
+	s.respondJSON(w, http.StatusOK, map[string]ValidatorInfo{"validator": validator})
 }
 
 // handleGetStakingParams returns staking module parameters
 func (s *Server) handleGetStakingParams(w http.ResponseWriter, r *http.Request) {
-	url := fmt.Sprintf("%s/cosmos/staking/v1beta1/params", getRESTBaseURL())
-	
-	resp, err := restClient.Get(url)
+	params, err := s.chain.StakingParams(r.Context())
 	if err != nil {
 		// Return default params on error
 		s.respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -208,12 +156,8 @@ func (s *Server) handleGetStakingParams(w http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(body)
+	s.respondJSON(w, http.StatusOK, params)
 }
 
 // DelegateRequest represents a delegation request
@@ -227,8 +171,8 @@ type DelegateRequest struct {
 // For testnet, this creates an unsigned transaction that can be signed client-side
 func (s *Server) handleDelegate(w http.ResponseWriter, r *http.Request) {
 	var req DelegateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBodyStrict(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 
@@ -287,8 +231,8 @@ func (s *Server) handleDelegate(w http.ResponseWriter, r *http.Request) {
 // handleUndelegate handles POST /api/v1/staking/undelegate
 func (s *Server) handleUndelegate(w http.ResponseWriter, r *http.Request) {
 	var req DelegateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBodyStrict(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 
@@ -346,9 +290,7 @@ func (s *Server) handleGetRewards(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	url := fmt.Sprintf("%s/cosmos/distribution/v1beta1/delegators/%s/rewards", getRESTBaseURL(), bech32Addr)
-
-	resp, err := restClient.Get(url)
+	rewards, err := s.chain.DelegatorRewards(r.Context(), bech32Addr)
 	if err != nil {
 		// Return empty rewards on error
 		s.respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -357,12 +299,8 @@ func (s *Server) handleGetRewards(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(body)
+	s.respondJSON(w, http.StatusOK, rewards)
 }
 
 // RedelegateRequest represents a redelegation request
@@ -376,8 +314,8 @@ type RedelegateRequest struct {
 // handleRedelegate handles POST /api/v1/staking/redelegate
 func (s *Server) handleRedelegate(w http.ResponseWriter, r *http.Request) {
 	var req RedelegateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBodyStrict(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 
@@ -434,8 +372,8 @@ type ClaimRewardsRequest struct {
 // handleClaimRewards handles POST /api/v1/staking/claim-rewards
 func (s *Server) handleClaimRewards(w http.ResponseWriter, r *http.Request) {
 	var req ClaimRewardsRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := decodeJSONBodyStrict(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
 		return
 	}
 
@@ -459,22 +397,10 @@ func (s *Server) handleClaimRewards(w http.ResponseWriter, r *http.Request) {
 	validators := req.ValidatorAddresses
 	if len(validators) == 0 {
 		// Query delegations to get validator list
-		url := fmt.Sprintf("%s/cosmos/staking/v1beta1/delegations/%s", getRESTBaseURL(), bech32Addr)
-		resp, err := restClient.Get(url)
+		delResp, err := s.chain.Delegations(r.Context(), bech32Addr, 0)
 		if err == nil {
-			defer resp.Body.Close()
-			body, _ := io.ReadAll(resp.Body)
-			var delResp struct {
-				DelegationResponses []struct {
-					Delegation struct {
-						ValidatorAddress string `json:"validator_address"`
-					} `json:"delegation"`
-				} `json:"delegation_responses"`
-			}
-			if json.Unmarshal(body, &delResp) == nil {
-				for _, dr := range delResp.DelegationResponses {
-					validators = append(validators, dr.Delegation.ValidatorAddress)
-				}
+			for _, dr := range delResp.DelegationResponses {
+				validators = append(validators, dr.Delegation.ValidatorAddress)
 			}
 		}
 	}
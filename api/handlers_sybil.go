@@ -2,9 +2,7 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
-	"time"
 
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
@@ -27,7 +25,7 @@ type SybilCheckResponse struct {
 	TrustScore    int          `json:"trust_score"`
 	IsLikelyHuman bool         `json:"is_likely_human"`
 	Factors       TrustFactors `json:"factors"`
-	CheckedAt     time.Time    `json:"checked_at"`
+	CheckedAt     Timestamp    `json:"checked_at"`
 }
 
 type BatchCheckRequest struct {
@@ -44,33 +42,32 @@ type BatchCheckResponse struct {
 	} `json:"summary"`
 }
 
-// calculateSybilTrustScore computes a trust score (0-100) based on various factors
-func calculateSybilTrustScore(factors TrustFactors) int {
+// calculateSybilTrustScore computes a trust score (0 to w.MaxScore) based on various factors
+func calculateSybilTrustScore(w SybilScoreWeights, factors TrustFactors) int {
 	score := 0
 
-	// KYC verification: +30 points
+	// KYC verification
 	if factors.KYCVerified {
-		score += 30
+		score += w.KYC
 	}
 
-	// Social verifications: +10 per platform (max 40)
-	score += sybilMin(factors.SocialVerifications*10, 40)
+	// Social verifications: per platform, capped
+	score += sybilMin(factors.SocialVerifications*w.PerSocial, w.SocialCap)
 
-	// On-chain activity: +5 per significant interaction (max 20)
-	score += sybilMin(factors.OnChainActivity*5, 20)
+	// On-chain activity: per significant interaction, capped
+	score += sybilMin(factors.OnChainActivity*w.PerActivity, w.ActivityCap)
 
-	// Account age: +1 per month (max 10)
-	score += sybilMin(factors.AccountAgeMonths, 10)
+	// Account age: per month, capped
+	score += sybilMin(factors.AccountAgeMonths*w.PerAgeMonth, w.AgeCap)
 
-	// Staked amount: +0.01 per CERT staked (max 20)
-	stakedScore := int(factors.StakedAmount * 0.01)
-	score += sybilMin(stakedScore, 20)
+	// Staked amount: per CERT staked, capped
+	stakedScore := int(factors.StakedAmount * w.PerStakedCERT)
+	score += sybilMin(stakedScore, w.StakeCap)
 
-	// Attestations received: +2 per attestation (max 20)
-	score += sybilMin(factors.AttestationsReceived*2, 20)
+	// Attestations received: per attestation, capped
+	score += sybilMin(factors.AttestationsReceived*w.PerAttestation, w.AttestationCap)
 
-	// Cap at 100
-	return sybilMin(score, 100)
+	return sybilMin(score, w.MaxScore)
 }
 
 // getTrustFactors retrieves all trust-related data for an address
@@ -85,7 +82,7 @@ func (s *Server) getTrustFactors(ctx context.Context, address string) TrustFacto
 
 		// Get profile age
 		if prof, err := s.db.GetProfile(ctx, address); err == nil && prof != nil {
-			monthsSinceCreation := int(time.Since(prof.CreatedAt).Hours() / 24 / 30)
+			monthsSinceCreation := int(s.clock.Now().Sub(prof.CreatedAt).Hours() / 24 / 30)
 			factors.AccountAgeMonths = monthsSinceCreation
 		}
 
@@ -120,7 +117,7 @@ func (s *Server) handleSybilCheck(w http.ResponseWriter, r *http.Request) {
 	}
 
 	factors := s.getTrustFactors(r.Context(), address)
-	trustScore := calculateSybilTrustScore(factors)
+	trustScore := calculateSybilTrustScore(s.trustScore.Sybil, factors)
 	isLikelyHuman := trustScore >= 50 // Default threshold
 
 	response := SybilCheckResponse{
@@ -128,7 +125,7 @@ func (s *Server) handleSybilCheck(w http.ResponseWriter, r *http.Request) {
 		TrustScore:    trustScore,
 		IsLikelyHuman: isLikelyHuman,
 		Factors:       factors,
-		CheckedAt:     time.Now(),
+		CheckedAt:     NewTimestamp(s.clock.Now()),
 	}
 
 	s.respondJSON(w, http.StatusOK, response)
@@ -137,7 +134,7 @@ func (s *Server) handleSybilCheck(w http.ResponseWriter, r *http.Request) {
 // handleSybilBatchCheck returns trust scores for multiple addresses
 func (s *Server) handleSybilBatchCheck(w http.ResponseWriter, r *http.Request) {
 	var req BatchCheckRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(w, r, &req); err != nil {
 		s.respondJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
 		return
 	}
@@ -149,8 +146,7 @@ func (s *Server) handleSybilBatchCheck(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Limit batch size
-	if len(req.Addresses) > 100 {
-		s.respondJSON(w, http.StatusBadRequest, map[string]string{"error": "batch size limited to 100 addresses"})
+	if !s.enforceBatchSize(w, len(req.Addresses), s.config.BatchLimits.SybilAddresses) {
 		return
 	}
 	if len(req.Addresses) == 0 {
@@ -165,7 +161,7 @@ func (s *Server) handleSybilBatchCheck(w http.ResponseWriter, r *http.Request) {
 	// Process each address
 	for _, address := range req.Addresses {
 		factors := s.getTrustFactors(r.Context(), address)
-		trustScore := calculateSybilTrustScore(factors)
+		trustScore := calculateSybilTrustScore(s.trustScore.Sybil, factors)
 		isLikelyHuman := trustScore >= threshold
 
 		result := SybilCheckResponse{
@@ -173,7 +169,7 @@ func (s *Server) handleSybilBatchCheck(w http.ResponseWriter, r *http.Request) {
 			TrustScore:    trustScore,
 			IsLikelyHuman: isLikelyHuman,
 			Factors:       factors,
-			CheckedAt:     time.Now(),
+			CheckedAt:     NewTimestamp(s.clock.Now()),
 		}
 
 		response.Results = append(response.Results, result)
@@ -200,11 +196,11 @@ func (s *Server) handleGetTrustScoreHistory(w http.ResponseWriter, r *http.Reque
 	}
 
 	factors := s.getTrustFactors(r.Context(), address)
-	trustScore := calculateSybilTrustScore(factors)
+	trustScore := calculateSybilTrustScore(s.trustScore.Sybil, factors)
 
 	history := []map[string]interface{}{
 		{
-			"timestamp":   time.Now(),
+			"timestamp":   s.clock.Now(),
 			"trust_score": trustScore,
 			"factors":     factors,
 		},
@@ -112,7 +112,7 @@ func TestCalculateSybilTrustScore(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			score := calculateSybilTrustScore(tt.factors)
+			score := calculateSybilTrustScore(DefaultTrustScoreConfig().Sybil, tt.factors)
 			if score != tt.expected {
 				t.Errorf("calculateSybilTrustScore() = %d, want %d", score, tt.expected)
 			}
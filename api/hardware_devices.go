@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDevicesPageLimit = 50
+	maxDevicesPageLimit     = 100
+)
+
+// HardwareDevice is the device management view of a registered hardware device
+type HardwareDevice struct {
+	DeviceID     string     `json:"device_id"`
+	TEEType      string     `json:"tee_type"`
+	Manufacturer string     `json:"manufacturer"`
+	Model        string     `json:"model,omitempty"`
+	TrustScore   uint64     `json:"trust_score"`
+	Uptime       float64    `json:"uptime"`
+	IsActive     bool       `json:"is_active"`
+	IsSuspended  bool       `json:"is_suspended"`
+	LastAttestAt *Timestamp `json:"last_attest_at,omitempty"`
+}
+
+// HardwareDevicesResponse is a page of the devices owned by an address
+type HardwareDevicesResponse struct {
+	Owner   string           `json:"owner"`
+	Devices []HardwareDevice `json:"devices"`
+	// NextKey is the page_key for the following page; empty on the last page
+	NextKey string `json:"next_key,omitempty"`
+	Total   uint64 `json:"total,omitempty"`
+}
+
+// devicesByOwnerResult is the `certd query hardware devices-by-owner` output
+type devicesByOwnerResult struct {
+	Devices []struct {
+		DeviceID     string    `json:"device_id"`
+		TEEType      string    `json:"tee_type"`
+		Manufacturer string    `json:"manufacturer"`
+		Model        string    `json:"model"`
+		TrustScore   protoUint `json:"trust_score"`
+		Uptime       float64   `json:"uptime"`
+		IsActive     bool      `json:"is_active"`
+		IsSuspended  bool      `json:"is_suspended"`
+		LastAttestAt time.Time `json:"last_attest_at"`
+	} `json:"devices"`
+	Pagination struct {
+		NextKey string    `json:"next_key"`
+		Total   protoUint `json:"total"`
+	} `json:"pagination"`
+}
+
+// handleGetHardwareDevices lists the hardware devices owned by an address.
+// Pages are selected with ?limit= and the page_key returned as next_key.
+func (s *Server) handleGetHardwareDevices(w http.ResponseWriter, r *http.Request) {
+	bech32Addr, err := toBech32Address(mux.Vars(r)["address"])
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := defaultDevicesPageLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 || parsed > maxDevicesPageLimit {
+			s.respondError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxDevicesPageLimit))
+			return
+		}
+		limit = parsed
+	}
+
+	args := []string{"hardware", "devices-by-owner", bech32Addr, "--limit", strconv.Itoa(limit)}
+	if pageKey := r.URL.Query().Get("page_key"); pageKey != "" {
+		key, err := base64.StdEncoding.DecodeString(pageKey)
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, "invalid page_key")
+			return
+		}
+		args = append(args, "--page-key", string(key))
+	} else {
+		args = append(args, "--count-total")
+	}
+
+	var res devicesByOwnerResult
+	if err := s.execCertdQueryJSON(&res, args...); err != nil {
+		s.logger.Warn("devices by owner query failed", zap.String("owner", bech32Addr), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query devices")
+		return
+	}
+
+	out := HardwareDevicesResponse{
+		Owner:   bech32Addr,
+		Devices: make([]HardwareDevice, 0, len(res.Devices)),
+		NextKey: res.Pagination.NextKey,
+		Total:   uint64(res.Pagination.Total),
+	}
+	for _, d := range res.Devices {
+		device := HardwareDevice{
+			DeviceID:     d.DeviceID,
+			TEEType:      d.TEEType,
+			Manufacturer: d.Manufacturer,
+			Model:        d.Model,
+			TrustScore:   uint64(d.TrustScore),
+			Uptime:       d.Uptime,
+			IsActive:     d.IsActive,
+			IsSuspended:  d.IsSuspended,
+		}
+		if !d.LastAttestAt.IsZero() {
+			lastAttest := NewTimestamp(d.LastAttestAt)
+			device.LastAttestAt = &lastAttest
+		}
+		out.Devices = append(out.Devices, device)
+	}
+
+	s.respondJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestHandleGetHardwareDevices(t *testing.T) {
+	var gotArgs []string
+	orig := runCertdCLI
+	t.Cleanup(func() { runCertdCLI = orig })
+	runCertdCLI = func(args ...string) ([]byte, error) {
+		gotArgs = args
+		if args[3] != testBalanceAddr {
+			return []byte("Error: invalid owner"), errors.New("exit status 1")
+		}
+		return []byte(`{"devices":[
+			{"device_id":"dev-a","tee_type":"ARM_TRUSTZONE","manufacturer":"Acme","trust_score":"82","uptime":97.5,"is_active":true,"is_suspended":false,"last_attest_at":"2025-08-01T12:00:00Z"},
+			{"device_id":"dev-b","tee_type":"APPLE_SECURE_ENCLAVE","manufacturer":"Apple","model":"iPhone","trust_score":"0","uptime":0,"is_active":true,"is_suspended":true,"last_attest_at":"0001-01-01T00:00:00Z"}
+		],"pagination":{"next_key":"ZGV2LWM=","total":"3"}}`), nil
+	}
+	s := NewServer(DefaultConfig(), zap.NewNop())
+
+	out := getJSON(t, s, "/api/v1/hardware/devices/"+testBalanceAddr+"?limit=2")
+	devices := out["devices"].([]any)
+	if len(devices) != 2 || out["next_key"] != "ZGV2LWM=" || out["total"].(float64) != 3 {
+		t.Fatalf("response = %v", out)
+	}
+	first := devices[0].(map[string]any)
+	if first["device_id"] != "dev-a" || first["tee_type"] != "ARM_TRUSTZONE" || first["trust_score"].(float64) != 82 ||
+		first["uptime"].(float64) != 97.5 || first["is_active"] != true || first["last_attest_at"] != "2025-08-01T12:00:00Z" {
+		t.Errorf("first device = %v", first)
+	}
+	second := devices[1].(map[string]any)
+	if second["is_suspended"] != true {
+		t.Errorf("second device = %v", second)
+	}
+	if _, ok := second["last_attest_at"]; ok {
+		t.Errorf("never-attested device reports last_attest_at %v", second["last_attest_at"])
+	}
+	if !containsArgs(gotArgs, "--limit", "2") || !containsArgs(gotArgs, "--count-total") {
+		t.Errorf("query args = %v", gotArgs)
+	}
+
+	// The next_key is passed back as the raw page key
+	pageKey := base64.StdEncoding.EncodeToString([]byte("dev-c"))
+	getJSON(t, s, "/api/v1/hardware/devices/"+testBalanceAddr+"?page_key="+pageKey)
+	if !containsArgs(gotArgs, "--page-key", "dev-c") || !containsArgs(gotArgs, "--limit", "50") {
+		t.Errorf("query args = %v", gotArgs)
+	}
+
+	for _, path := range []string{
+		"/api/v1/hardware/devices/" + testBalanceAddr + "?limit=0",
+		"/api/v1/hardware/devices/" + testBalanceAddr + "?limit=500",
+		"/api/v1/hardware/devices/" + testBalanceAddr + "?page_key=not*base64",
+		"/api/v1/hardware/devices/0xnothex",
+	} {
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want 400", path, rec.Code)
+		}
+	}
+}
+
+// containsArgs reports whether want appears as a contiguous run in args
+func containsArgs(args []string, want ...string) bool {
+	for i := 0; i+len(want) <= len(args); i++ {
+		match := true
+		for j, w := range want {
+			if args[i+j] != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,415 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chaincertify/certd/api/database"
+	"go.uber.org/zap"
+)
+
+// Device heartbeats
+// Devices prove liveness by POSTing a heartbeat signed with their TEE key. The
+// API verifies and buffers heartbeats into hourly buckets and, every
+// FlushInterval, stores one rolling uptime checkpoint per device in the
+// database, so each device gets an aggregated update per checkpoint instead of
+// a write per heartbeat. Without a database checkpoints stay pending in memory.
+
+const (
+	// heartbeatSignPrefix domain-separates heartbeat signatures from other uses of the TEE key
+	heartbeatSignPrefix = "cert-heartbeat:v1"
+
+	// uptimeWindowHours is the rolling window uptime is averaged over (7 days)
+	uptimeWindowHours = 7 * 24
+)
+
+// HeartbeatConfig tunes device heartbeat ingestion
+type HeartbeatConfig struct {
+	// FlushInterval is how often uptime checkpoints are submitted (0 disables the flusher)
+	FlushInterval time.Duration
+
+	// MinInterval is the per-device rate limit: the minimum time between accepted heartbeats
+	MinInterval time.Duration
+
+	// MaxClockSkew bounds how far a heartbeat timestamp may be from the server clock
+	MaxClockSkew time.Duration
+
+	// KeyCacheTTL is how long a device's on-chain key and status are cached
+	KeyCacheTTL time.Duration
+}
+
+// DefaultHeartbeatConfig returns the default heartbeat settings
+func DefaultHeartbeatConfig() HeartbeatConfig {
+	return HeartbeatConfig{
+		FlushInterval: 10 * time.Minute,
+		MinInterval:   time.Minute,
+		MaxClockSkew:  5 * time.Minute,
+		KeyCacheTTL:   10 * time.Minute,
+	}
+}
+
+// HeartbeatRequest is the POST /hardware/heartbeat body
+type HeartbeatRequest struct {
+	DeviceID string `json:"device_id"`
+	// Timestamp is the device's unix time in seconds; it must increase with every heartbeat
+	Timestamp int64 `json:"timestamp"`
+	// Attestation is the base64 TEE-key signature over heartbeatSignBytes
+	Attestation string `json:"attestation"`
+}
+
+// HeartbeatResponse acknowledges an accepted heartbeat
+type HeartbeatResponse struct {
+	DeviceID string `json:"device_id"`
+	// NextAllowedAt is the earliest time the device's next heartbeat is accepted
+	NextAllowedAt Timestamp `json:"next_allowed_at"`
+}
+
+// UptimeCheckpoint is the aggregated uptime of a device over the rolling window
+type UptimeCheckpoint struct {
+	DeviceID    string    `json:"device_id"`
+	Uptime      float64   `json:"uptime"` // Percentage of window hours with a heartbeat (0-100)
+	OnlineHours int       `json:"online_hours"`
+	AsOf        Timestamp `json:"as_of"`
+}
+
+// heartbeatSignBytes returns the message a device signs for a heartbeat:
+// "cert-heartbeat:v1|<device_id>|<timestamp>". ECDSA keys sign its SHA-256 digest.
+func heartbeatSignBytes(deviceID string, timestamp int64) []byte {
+	return []byte(heartbeatSignPrefix + "|" + deviceID + "|" + strconv.FormatInt(timestamp, 10))
+}
+
+// heartbeatVerifier checks a heartbeat signature against a device key
+type heartbeatVerifier func(msg, sig []byte) bool
+
+// parseDeviceKey builds a verifier for a TEE public key. Secure Enclave,
+// StrongBox and TPM keys are P-256, registered as a PKIX DER key or a raw
+// 65-byte uncompressed point; 32-byte keys are Ed25519.
+func parseDeviceKey(pub []byte) (heartbeatVerifier, error) {
+	if len(pub) == ed25519.PublicKeySize {
+		key := ed25519.PublicKey(pub)
+		return func(msg, sig []byte) bool { return ed25519.Verify(key, msg, sig) }, nil
+	}
+
+	var key *ecdsa.PublicKey
+	if len(pub) == 65 && pub[0] == 4 {
+		x, y := elliptic.Unmarshal(elliptic.P256(), pub)
+		if x == nil {
+			return nil, errors.New("invalid P-256 point")
+		}
+		key = &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+	} else {
+		parsed, err := x509.ParsePKIXPublicKey(pub)
+		if err != nil {
+			return nil, errors.New("unsupported device key format")
+		}
+		switch k := parsed.(type) {
+		case *ecdsa.PublicKey:
+			key = k
+		case ed25519.PublicKey:
+			return func(msg, sig []byte) bool { return ed25519.Verify(k, msg, sig) }, nil
+		default:
+			return nil, errors.New("unsupported device key type")
+		}
+	}
+	return func(msg, sig []byte) bool {
+		digest := sha256.Sum256(msg)
+		return ecdsa.VerifyASN1(key, digest[:], sig)
+	}, nil
+}
+
+// deviceKeyEntry is a cached on-chain device key and status
+type deviceKeyEntry struct {
+	verify    heartbeatVerifier
+	active    bool
+	fetchedAt time.Time
+}
+
+// deviceHeartbeats is the buffered heartbeat state for one device
+type deviceHeartbeats struct {
+	lastTimestamp int64              // Latest accepted device timestamp (replay guard)
+	lastAccepted  time.Time          // Server time of the latest accepted heartbeat (rate limit)
+	hours         map[int64]struct{} // Unix hours with a heartbeat, pruned to the window
+	dirty         bool               // Uptime changed since the last submitted checkpoint
+}
+
+// heartbeatTracker buffers verified heartbeats off-chain
+type heartbeatTracker struct {
+	mu      sync.Mutex
+	devices map[string]*deviceHeartbeats
+	keys    map[string]deviceKeyEntry
+}
+
+func newHeartbeatTracker() *heartbeatTracker {
+	return &heartbeatTracker{
+		devices: make(map[string]*deviceHeartbeats),
+		keys:    make(map[string]deviceKeyEntry),
+	}
+}
+
+var (
+	// errHeartbeatReplay is returned for a timestamp at or before the device's last heartbeat
+	errHeartbeatReplay = errors.New("heartbeat timestamp must increase")
+
+	// errHeartbeatRateLimited is returned when a device heartbeats faster than MinInterval
+	errHeartbeatRateLimited = errors.New("heartbeat rate limit exceeded")
+)
+
+// record accepts a verified heartbeat, enforcing the replay guard and rate limit.
+// When rate limited it returns the time the next heartbeat is allowed.
+func (t *heartbeatTracker) record(deviceID string, timestamp int64, now time.Time, minInterval time.Duration) (time.Time, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	d, ok := t.devices[deviceID]
+	if !ok {
+		d = &deviceHeartbeats{hours: make(map[int64]struct{})}
+		t.devices[deviceID] = d
+	}
+	if timestamp <= d.lastTimestamp {
+		return time.Time{}, errHeartbeatReplay
+	}
+	if next := d.lastAccepted.Add(minInterval); !d.lastAccepted.IsZero() && now.Before(next) {
+		return next, errHeartbeatRateLimited
+	}
+
+	d.lastTimestamp = timestamp
+	d.lastAccepted = now
+	hour := now.Unix() / 3600
+	if _, seen := d.hours[hour]; !seen {
+		d.hours[hour] = struct{}{}
+		d.dirty = true
+	}
+	return now.Add(minInterval), nil
+}
+
+// pendingCheckpoints returns a checkpoint for every device whose uptime changed
+// since its last submission, pruning hours that fell out of the window
+func (t *heartbeatTracker) pendingCheckpoints(now time.Time) []UptimeCheckpoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	oldest := now.Unix()/3600 - uptimeWindowHours + 1
+	var out []UptimeCheckpoint
+	for id, d := range t.devices {
+		for hour := range d.hours {
+			if hour < oldest {
+				delete(d.hours, hour) // Uptime dropped, so the chain needs a fresh checkpoint
+				d.dirty = true
+			}
+		}
+		if !d.dirty {
+			if len(d.hours) == 0 {
+				delete(t.devices, id)
+			}
+			continue
+		}
+		out = append(out, UptimeCheckpoint{
+			DeviceID:    id,
+			Uptime:      math.Round(float64(len(d.hours))*10000/uptimeWindowHours) / 100,
+			OnlineHours: len(d.hours),
+			AsOf:        NewTimestamp(now),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].DeviceID < out[j].DeviceID })
+	return out
+}
+
+// markSubmitted clears the dirty flag of devices whose checkpoint was delivered
+func (t *heartbeatTracker) markSubmitted(checkpoints []UptimeCheckpoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, c := range checkpoints {
+		if d, ok := t.devices[c.DeviceID]; ok {
+			d.dirty = false
+		}
+	}
+}
+
+// uptimeSubmitter delivers a batch of uptime checkpoints
+type uptimeSubmitter interface {
+	SubmitUptime(ctx context.Context, checkpoints []UptimeCheckpoint) error
+}
+
+// dbUptimeSubmitter stores checkpoints in PostgreSQL, where they are kept as
+// each device's latest uptime
+type dbUptimeSubmitter struct {
+	db *database.DB
+}
+
+func (s dbUptimeSubmitter) SubmitUptime(ctx context.Context, checkpoints []UptimeCheckpoint) error {
+	rows := make([]database.UptimeCheckpoint, len(checkpoints))
+	for i, c := range checkpoints {
+		rows[i] = database.UptimeCheckpoint{
+			DeviceID:    c.DeviceID,
+			Uptime:      c.Uptime,
+			OnlineHours: c.OnlineHours,
+			AsOf:        c.AsOf.Time,
+		}
+	}
+	return s.db.UpsertUptimeCheckpoints(ctx, rows)
+}
+
+// deviceQueryResult is the `certd query hardware device` output
+type deviceQueryResult struct {
+	Device struct {
+		PublicKey   []byte `json:"public_key"`
+		IsActive    bool   `json:"is_active"`
+		IsSuspended bool   `json:"is_suspended"`
+	} `json:"device"`
+}
+
+var (
+	// errDeviceNotFound is returned by deviceKey for an unregistered device
+	errDeviceNotFound = errors.New("device not found")
+
+	// errUnsupportedDeviceKey is returned by deviceKey when the TEE key cannot verify heartbeats
+	errUnsupportedDeviceKey = errors.New("device key cannot verify heartbeats")
+)
+
+// deviceKey returns the verifier and status for a device, from cache when fresh
+func (s *Server) deviceKey(deviceID string) (deviceKeyEntry, error) {
+	now := s.clock.Now()
+	s.heartbeats.mu.Lock()
+	entry, ok := s.heartbeats.keys[deviceID]
+	s.heartbeats.mu.Unlock()
+	if ok && now.Sub(entry.fetchedAt) < s.config.Heartbeats.KeyCacheTTL {
+		return entry, nil
+	}
+
+	var res deviceQueryResult
+	if err := s.execCertdQueryJSON(&res, "hardware", "device", deviceID); err != nil {
+		if strings.Contains(err.Error(), "device not found") {
+			return deviceKeyEntry{}, errDeviceNotFound
+		}
+		return deviceKeyEntry{}, err
+	}
+	verify, err := parseDeviceKey(res.Device.PublicKey)
+	if err != nil {
+		return deviceKeyEntry{}, fmt.Errorf("%w: %v", errUnsupportedDeviceKey, err)
+	}
+	entry = deviceKeyEntry{
+		verify:    verify,
+		active:    res.Device.IsActive && !res.Device.IsSuspended,
+		fetchedAt: now,
+	}
+
+	s.heartbeats.mu.Lock()
+	s.heartbeats.keys[deviceID] = entry
+	s.heartbeats.mu.Unlock()
+	return entry, nil
+}
+
+// handleHardwareHeartbeat ingests a device heartbeat signed by its TEE key
+func (s *Server) handleHardwareHeartbeat(w http.ResponseWriter, r *http.Request) {
+	var req HeartbeatRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
+		return
+	}
+	if req.DeviceID == "" || req.Timestamp <= 0 || req.Attestation == "" {
+		s.respondError(w, http.StatusBadRequest, "device_id, timestamp and attestation are required")
+		return
+	}
+	sig, err := base64.StdEncoding.DecodeString(req.Attestation)
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, "attestation must be base64")
+		return
+	}
+
+	now := s.clock.Now()
+	skew := now.Sub(time.Unix(req.Timestamp, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > s.config.Heartbeats.MaxClockSkew {
+		s.respondError(w, http.StatusBadRequest, "timestamp is outside the accepted clock skew")
+		return
+	}
+
+	entry, err := s.deviceKey(req.DeviceID)
+	switch {
+	case errors.Is(err, errDeviceNotFound):
+		s.respondError(w, http.StatusNotFound, "Device not found")
+		return
+	case errors.Is(err, errUnsupportedDeviceKey):
+		s.respondError(w, http.StatusUnauthorized, err.Error())
+		return
+	case err != nil:
+		s.logger.Warn("device key lookup failed", zap.String("device_id", req.DeviceID), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to look up device")
+		return
+	}
+	if !entry.verify(heartbeatSignBytes(req.DeviceID, req.Timestamp), sig) {
+		s.respondError(w, http.StatusUnauthorized, "invalid heartbeat attestation")
+		return
+	}
+	if !entry.active {
+		s.respondError(w, http.StatusForbidden, "device is not active")
+		return
+	}
+
+	next, err := s.heartbeats.record(req.DeviceID, req.Timestamp, now, s.config.Heartbeats.MinInterval)
+	switch {
+	case errors.Is(err, errHeartbeatRateLimited):
+		retryAfter := int(math.Ceil(next.Sub(now).Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(maxInt(retryAfter, 1)))
+		s.respondError(w, http.StatusTooManyRequests, err.Error())
+		return
+	case err != nil:
+		s.respondError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	s.respondJSON(w, http.StatusAccepted, HeartbeatResponse{DeviceID: req.DeviceID, NextAllowedAt: NewTimestamp(next)})
+}
+
+// flushUptimeCheckpoints submits pending checkpoints as one batch. Devices stay
+// pending if the submission fails, or if there is no submitter, so the next
+// flush retries them.
+func (s *Server) flushUptimeCheckpoints(ctx context.Context) (int, error) {
+	if s.uptimeSubmitter == nil {
+		return 0, nil
+	}
+	checkpoints := s.heartbeats.pendingCheckpoints(s.clock.Now())
+	if len(checkpoints) == 0 {
+		return 0, nil
+	}
+	if err := s.uptimeSubmitter.SubmitUptime(ctx, checkpoints); err != nil {
+		return 0, err
+	}
+	s.heartbeats.markSubmitted(checkpoints)
+	return len(checkpoints), nil
+}
+
+// watchUptimeCheckpoints flushes checkpoints every interval until ctx is cancelled
+func (s *Server) watchUptimeCheckpoints(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.flushUptimeCheckpoints(ctx); err != nil {
+				s.logger.Warn("uptime checkpoint submission failed", zap.Error(err))
+			} else if n > 0 {
+				s.logger.Debug("submitted uptime checkpoints", zap.Int("devices", n))
+			}
+		}
+	}
+}
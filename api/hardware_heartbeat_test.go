@@ -0,0 +1,274 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type recordingUptimeSubmitter struct {
+	batches [][]UptimeCheckpoint
+	err     error
+}
+
+func (s *recordingUptimeSubmitter) SubmitUptime(_ context.Context, checkpoints []UptimeCheckpoint) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, checkpoints)
+	return nil
+}
+
+// stubDeviceQuery answers `certd query hardware device` from a map of device keys
+func stubDeviceQuery(t *testing.T, keys map[string][]byte, queries *int) {
+	t.Helper()
+	orig := runCertdCLI
+	t.Cleanup(func() { runCertdCLI = orig })
+	runCertdCLI = func(args ...string) ([]byte, error) {
+		if args[1] != "hardware" || args[2] != "device" {
+			return nil, errors.New("unexpected query")
+		}
+		if queries != nil {
+			*queries++
+		}
+		pub, ok := keys[args[3]]
+		if !ok {
+			return []byte("rpc error: code = Unknown desc = device not found"), errors.New("exit status 1")
+		}
+		return json.Marshal(map[string]any{"device": map[string]any{
+			"device_id": args[3], "public_key": pub, "is_active": true, "is_suspended": args[3] == "dev_suspended",
+		}})
+	}
+}
+
+func newHeartbeatServer(t *testing.T, clock *fakeClock) (*Server, *recordingUptimeSubmitter) {
+	t.Helper()
+	config := DefaultConfig()
+	config.Clock = clock
+	s := NewServer(config, zap.NewNop())
+	submitter := &recordingUptimeSubmitter{}
+	s.uptimeSubmitter = submitter
+	return s, submitter
+}
+
+func postHeartbeat(s *Server, req HeartbeatRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, newJSONRequest(http.MethodPost, "/api/v1/hardware/heartbeat", bytes.NewReader(body)))
+	return rec
+}
+
+func signedHeartbeat(t *testing.T, key *ecdsa.PrivateKey, deviceID string, at time.Time) HeartbeatRequest {
+	t.Helper()
+	digest := sha256.Sum256(heartbeatSignBytes(deviceID, at.Unix()))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("SignASN1: %v", err)
+	}
+	return HeartbeatRequest{DeviceID: deviceID, Timestamp: at.Unix(), Attestation: base64.StdEncoding.EncodeToString(sig)}
+}
+
+func TestHardwareHeartbeat_Attestation(t *testing.T) {
+	enclaveKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	pkix, _ := x509.MarshalPKIXPublicKey(&enclaveKey.PublicKey)
+	edPub, edPriv, _ := ed25519.GenerateKey(rand.Reader)
+	keys := map[string][]byte{
+		"dev_raw":       elliptic.Marshal(elliptic.P256(), enclaveKey.X, enclaveKey.Y),
+		"dev_pkix":      pkix,
+		"dev_ed25519":   edPub,
+		"dev_suspended": pkix,
+		"dev_opaque":    []byte("not-a-key"),
+	}
+	stubDeviceQuery(t, keys, nil)
+
+	clock := newFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	s, _ := newHeartbeatServer(t, clock)
+	now := clock.Now()
+
+	edSig := ed25519.Sign(edPriv, heartbeatSignBytes("dev_ed25519", now.Unix()))
+	forged := signedHeartbeat(t, enclaveKey, "dev_raw", now)
+	forged.Timestamp++ // Signature no longer covers the timestamp
+
+	tests := []struct {
+		name string
+		req  HeartbeatRequest
+		want int
+	}{
+		{"raw P-256 key", signedHeartbeat(t, enclaveKey, "dev_raw", now), http.StatusAccepted},
+		{"PKIX P-256 key", signedHeartbeat(t, enclaveKey, "dev_pkix", now), http.StatusAccepted},
+		{"ed25519 key", HeartbeatRequest{DeviceID: "dev_ed25519", Timestamp: now.Unix(), Attestation: base64.StdEncoding.EncodeToString(edSig)}, http.StatusAccepted},
+		{"tampered timestamp", forged, http.StatusUnauthorized},
+		{"wrong device key", signedHeartbeat(t, mustP256Key(t), "dev_pkix", now.Add(time.Second)), http.StatusUnauthorized},
+		{"stale timestamp", signedHeartbeat(t, enclaveKey, "dev_raw", now.Add(-10*time.Minute)), http.StatusBadRequest},
+		{"unknown device", signedHeartbeat(t, enclaveKey, "dev_missing", now), http.StatusNotFound},
+		{"suspended device", signedHeartbeat(t, enclaveKey, "dev_suspended", now), http.StatusForbidden},
+		{"unsupported key", signedHeartbeat(t, enclaveKey, "dev_opaque", now), http.StatusUnauthorized},
+		{"missing attestation", HeartbeatRequest{DeviceID: "dev_raw", Timestamp: now.Unix()}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := postHeartbeat(s, tt.req); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func mustP256Key(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return key
+}
+
+func TestHardwareHeartbeat_RateLimit(t *testing.T) {
+	key := mustP256Key(t)
+	pkix, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	var queries int
+	stubDeviceQuery(t, map[string][]byte{"dev_a": pkix, "dev_b": pkix}, &queries)
+
+	clock := newFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	s, _ := newHeartbeatServer(t, clock)
+
+	if rec := postHeartbeat(s, signedHeartbeat(t, key, "dev_a", clock.Now())); rec.Code != http.StatusAccepted {
+		t.Fatalf("first heartbeat: %d %s", rec.Code, rec.Body.String())
+	}
+
+	// A second heartbeat inside MinInterval is rejected with Retry-After
+	clock.Advance(20 * time.Second)
+	rec := postHeartbeat(s, signedHeartbeat(t, key, "dev_a", clock.Now()))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "40" {
+		t.Errorf("rate limited heartbeat: status %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	// The limit is per device
+	if rec := postHeartbeat(s, signedHeartbeat(t, key, "dev_b", clock.Now())); rec.Code != http.StatusAccepted {
+		t.Errorf("other device heartbeat: %d", rec.Code)
+	}
+
+	// Replaying an accepted heartbeat fails even after the interval
+	replay := signedHeartbeat(t, key, "dev_a", clock.Now().Add(-20*time.Second))
+	clock.Advance(time.Minute)
+	if rec := postHeartbeat(s, replay); rec.Code != http.StatusUnauthorized {
+		t.Errorf("replayed heartbeat: %d", rec.Code)
+	}
+	if rec := postHeartbeat(s, signedHeartbeat(t, key, "dev_a", clock.Now())); rec.Code != http.StatusAccepted {
+		t.Errorf("heartbeat after interval: %d %s", rec.Code, rec.Body.String())
+	}
+
+	// Device keys are served from cache
+	if queries != 2 {
+		t.Errorf("device key queries = %d, want 2", queries)
+	}
+}
+
+func TestHardwareHeartbeat_BatchedCheckpoints(t *testing.T) {
+	key := mustP256Key(t)
+	pkix, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	stubDeviceQuery(t, map[string][]byte{"dev_a": pkix, "dev_b": pkix}, nil)
+
+	clock := newFakeClock(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	s, submitter := newHeartbeatServer(t, clock)
+	s.config.Heartbeats.KeyCacheTTL = 365 * 24 * time.Hour
+
+	// dev_a heartbeats every minute for 12 hours; dev_b once an hour for 6 hours
+	for minute := 0; minute < 12*60; minute++ {
+		if rec := postHeartbeat(s, signedHeartbeat(t, key, "dev_a", clock.Now())); rec.Code != http.StatusAccepted {
+			t.Fatalf("dev_a heartbeat at minute %d: %d", minute, rec.Code)
+		}
+		if minute%60 == 0 && minute < 6*60 {
+			if rec := postHeartbeat(s, signedHeartbeat(t, key, "dev_b", clock.Now())); rec.Code != http.StatusAccepted {
+				t.Fatalf("dev_b heartbeat at minute %d: %d", minute, rec.Code)
+			}
+		}
+		clock.Advance(time.Minute)
+	}
+
+	n, err := s.flushUptimeCheckpoints(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("flush = %d, %v", n, err)
+	}
+	if len(submitter.batches) != 1 {
+		t.Fatalf("submitted %d batches, want a single batch", len(submitter.batches))
+	}
+	batch := submitter.batches[0]
+	if batch[0].DeviceID != "dev_a" || batch[0].OnlineHours != 12 || batch[0].Uptime != 7.14 {
+		t.Errorf("dev_a checkpoint = %+v", batch[0])
+	}
+	if batch[1].DeviceID != "dev_b" || batch[1].OnlineHours != 6 || batch[1].Uptime != 3.57 {
+		t.Errorf("dev_b checkpoint = %+v", batch[1])
+	}
+
+	// Nothing changed, so nothing is submitted
+	if n, _ := s.flushUptimeCheckpoints(context.Background()); n != 0 {
+		t.Errorf("idle flush submitted %d checkpoints", n)
+	}
+
+	// A failed submission keeps the checkpoint pending for the next flush
+	postHeartbeat(s, signedHeartbeat(t, key, "dev_b", clock.Now()))
+	submitter.err = errors.New("node unavailable")
+	if _, err := s.flushUptimeCheckpoints(context.Background()); err == nil {
+		t.Fatal("expected the submission error to surface")
+	}
+	submitter.err = nil
+	if n, _ := s.flushUptimeCheckpoints(context.Background()); n != 1 {
+		t.Errorf("retry flush submitted %d checkpoints, want 1", n)
+	}
+
+	// Hours age out of the rolling window and lower the checkpointed uptime
+	clock.Advance(7 * 24 * time.Hour)
+	if n, _ := s.flushUptimeCheckpoints(context.Background()); n != 2 {
+		t.Fatalf("window flush submitted %d checkpoints, want 2", n)
+	}
+	for _, c := range submitter.batches[len(submitter.batches)-1] {
+		if c.OnlineHours != 0 || c.Uptime != 0 {
+			t.Errorf("aged-out checkpoint = %+v", c)
+		}
+	}
+	if n, _ := s.flushUptimeCheckpoints(context.Background()); n != 0 {
+		t.Errorf("flush after aging out submitted %d checkpoints", n)
+	}
+}
+
+func TestHardwareHeartbeat_CheckpointsWaitForSubmitter(t *testing.T) {
+	key := mustP256Key(t)
+	pkix, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	stubDeviceQuery(t, map[string][]byte{"dev_a": pkix}, nil)
+
+	clock := newFakeClock(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	s, submitter := newHeartbeatServer(t, clock)
+	s.uptimeSubmitter = nil
+
+	if rec := postHeartbeat(s, signedHeartbeat(t, key, "dev_a", clock.Now())); rec.Code != http.StatusAccepted {
+		t.Fatalf("heartbeat: %d", rec.Code)
+	}
+
+	// Without a database there is nowhere to deliver, so nothing is marked submitted
+	if n, err := s.flushUptimeCheckpoints(context.Background()); n != 0 || err != nil {
+		t.Fatalf("flush without a submitter = %d, %v", n, err)
+	}
+
+	s.uptimeSubmitter = submitter
+	if n, err := s.flushUptimeCheckpoints(context.Background()); n != 1 || err != nil {
+		t.Fatalf("flush = %d, %v; want the held checkpoint", n, err)
+	}
+	if got := submitter.batches[0][0]; got.DeviceID != "dev_a" || got.OnlineHours != 1 {
+		t.Errorf("checkpoint = %+v", got)
+	}
+}
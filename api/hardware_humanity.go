@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// HumanityScore is an address's proof-of-humanity score aggregated from its
+// hardware devices
+type HumanityScore struct {
+	Address            string     `json:"address"`
+	Score              uint64     `json:"score"`
+	VerifiedHuman      bool       `json:"verified_human"`
+	DeviceCount        uint64     `json:"device_count"`
+	AverageDeviceTrust float64    `json:"average_device_trust"`
+	GeoDispersion      float64    `json:"geo_dispersion"`
+	UsagePatternScore  float64    `json:"usage_pattern_score"`
+	LastUpdated        *Timestamp `json:"last_updated,omitempty"`
+}
+
+// humanityScoreResult is the `certd query hardware humanity-score` output
+type humanityScoreResult struct {
+	Score struct {
+		Score              protoUint `json:"score"`
+		DeviceCount        protoUint `json:"device_count"`
+		AverageDeviceTrust float64   `json:"average_device_trust"`
+		GeoDispersion      float64   `json:"geo_dispersion"`
+		UsagePatternScore  float64   `json:"usage_pattern_score"`
+		LastUpdated        time.Time `json:"last_updated"`
+	} `json:"score"`
+	VerifiedHuman bool `json:"verified_human"`
+}
+
+// handleGetHumanityScore returns an address's humanity score
+func (s *Server) handleGetHumanityScore(w http.ResponseWriter, r *http.Request) {
+	bech32Addr, err := toBech32Address(mux.Vars(r)["address"])
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var res humanityScoreResult
+	if err := s.execCertdQueryJSON(&res, "hardware", "humanity-score", bech32Addr); err != nil {
+		s.logger.Warn("humanity score query failed", zap.String("address", bech32Addr), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query humanity score")
+		return
+	}
+
+	out := HumanityScore{
+		Address:            bech32Addr,
+		Score:              uint64(res.Score.Score),
+		VerifiedHuman:      res.VerifiedHuman,
+		DeviceCount:        uint64(res.Score.DeviceCount),
+		AverageDeviceTrust: res.Score.AverageDeviceTrust,
+		GeoDispersion:      res.Score.GeoDispersion,
+		UsagePatternScore:  res.Score.UsagePatternScore,
+	}
+	if !res.Score.LastUpdated.IsZero() {
+		lastUpdated := NewTimestamp(res.Score.LastUpdated)
+		out.LastUpdated = &lastUpdated
+	}
+	s.respondJSON(w, http.StatusOK, out)
+}
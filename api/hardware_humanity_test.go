@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestHandleGetHumanityScore(t *testing.T) {
+	orig := runCertdCLI
+	t.Cleanup(func() { runCertdCLI = orig })
+	runCertdCLI = func(args ...string) ([]byte, error) {
+		if args[1] != "hardware" || args[2] != "humanity-score" || args[3] != testBalanceAddr {
+			return []byte("Error: invalid address"), errors.New("exit status 1")
+		}
+		return []byte(`{"score":{"address":"` + testBalanceAddr + `","score":"72","device_count":"3",
+			"average_device_trust":81.5,"geo_dispersion":0.6309,"usage_pattern_score":0.9,
+			"last_updated":"2025-08-01T12:00:00Z"},"verified_human":true}`), nil
+	}
+	s := NewServer(DefaultConfig(), zap.NewNop())
+
+	out := getJSON(t, s, "/api/v1/humanity/"+testBalanceAddr)
+	if out["address"] != testBalanceAddr || out["score"].(float64) != 72 || out["verified_human"] != true ||
+		out["device_count"].(float64) != 3 || out["average_device_trust"].(float64) != 81.5 ||
+		out["geo_dispersion"].(float64) != 0.6309 || out["usage_pattern_score"].(float64) != 0.9 ||
+		out["last_updated"] != "2025-08-01T12:00:00Z" {
+		t.Errorf("response = %v", out)
+	}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/humanity/not-an-address", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status for invalid address = %d, want 400", rec.Code)
+	}
+
+	runCertdCLI = func(args ...string) ([]byte, error) { return []byte("connection refused"), errors.New("exit status 1") }
+	s = NewServer(DefaultConfig(), zap.NewNop())
+	rec = httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/humanity/"+testBalanceAddr, nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status on query failure = %d, want 502", rec.Code)
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// HardwareStats is the network-wide DePIN device summary
+type HardwareStats struct {
+	TotalDevices      uint64            `json:"total_devices"`
+	ActiveDevices     uint64            `json:"active_devices"`
+	SuspendedDevices  uint64            `json:"suspended_devices"`
+	ByTEEType         map[string]uint64 `json:"by_tee_type"`
+	ByManufacturer    map[string]uint64 `json:"by_manufacturer"`
+	AverageTrustScore float64           `json:"average_trust_score"`
+	VerifiedHumans    uint64            `json:"verified_humans"`
+}
+
+// hardwareStatsResult is the `certd query hardware stats` output
+type hardwareStatsResult struct {
+	Stats struct {
+		TotalDevices     protoUint            `json:"total_devices"`
+		ActiveDevices    protoUint            `json:"active_devices"`
+		SuspendedDevices protoUint            `json:"suspended_devices"`
+		ByTEEType        map[string]protoUint `json:"by_tee_type"`
+		ByManufacturer   map[string]protoUint `json:"by_manufacturer"`
+		VerifiedHumans   protoUint            `json:"verified_humans"`
+	} `json:"stats"`
+	AverageTrustScore float64 `json:"average_trust_score"`
+}
+
+// handleGetHardwareStats returns network-wide hardware device stats
+func (s *Server) handleGetHardwareStats(w http.ResponseWriter, r *http.Request) {
+	var res hardwareStatsResult
+	if err := s.execCertdQueryJSON(&res, "hardware", "stats"); err != nil {
+		s.logger.Warn("hardware stats query failed", zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query hardware stats")
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, HardwareStats{
+		TotalDevices:      uint64(res.Stats.TotalDevices),
+		ActiveDevices:     uint64(res.Stats.ActiveDevices),
+		SuspendedDevices:  uint64(res.Stats.SuspendedDevices),
+		ByTEEType:         protoUintMap(res.Stats.ByTEEType),
+		ByManufacturer:    protoUintMap(res.Stats.ByManufacturer),
+		AverageTrustScore: res.AverageTrustScore,
+		VerifiedHumans:    uint64(res.Stats.VerifiedHumans),
+	})
+}
+
+func protoUintMap(in map[string]protoUint) map[string]uint64 {
+	out := make(map[string]uint64, len(in))
+	for k, v := range in {
+		out[k] = uint64(v)
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestHandleGetHardwareStats(t *testing.T) {
+	orig := runCertdCLI
+	t.Cleanup(func() { runCertdCLI = orig })
+	runCertdCLI = func(args ...string) ([]byte, error) {
+		if args[1] != "hardware" || args[2] != "stats" {
+			return nil, errors.New("unexpected query")
+		}
+		return []byte(`{"stats":{"total_devices":"3","active_devices":"2","suspended_devices":"1",
+			"by_tee_type":{"ARM_TRUSTZONE":"2","APPLE_SECURE_ENCLAVE":"1"},"by_manufacturer":{"Acme":"3"},
+			"trust_score_sum":"150","verified_humans":"1"},"average_trust_score":50}`), nil
+	}
+	s := NewServer(DefaultConfig(), zap.NewNop())
+
+	out := getJSON(t, s, "/api/v1/hardware/stats")
+	if out["total_devices"].(float64) != 3 || out["active_devices"].(float64) != 2 || out["suspended_devices"].(float64) != 1 ||
+		out["average_trust_score"].(float64) != 50 || out["verified_humans"].(float64) != 1 {
+		t.Errorf("stats = %v", out)
+	}
+	if tee := out["by_tee_type"].(map[string]any); tee["ARM_TRUSTZONE"].(float64) != 2 || tee["APPLE_SECURE_ENCLAVE"].(float64) != 1 {
+		t.Errorf("by_tee_type = %v", tee)
+	}
+	if _, leaked := out["trust_score_sum"]; leaked {
+		t.Error("internal trust_score_sum exposed")
+	}
+
+	// A fresh server, so the stats cached above are not served
+	runCertdCLI = func(args ...string) ([]byte, error) { return []byte("connection refused"), errors.New("exit status 1") }
+	s = NewServer(DefaultConfig(), zap.NewNop())
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/hardware/stats", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status on query failure = %d, want 502", rec.Code)
+	}
+}
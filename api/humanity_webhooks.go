@@ -0,0 +1,458 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// Humanity score webhooks
+// The hardware module's EndBlocker emits a humanity_score_changed event when an
+// address's score moves significantly. The API follows block results and POSTs
+// a signed payload to every subscription whose threshold the change crosses.
+
+const (
+	// humanityScoreChangedEvent is the hardware module's event type
+	humanityScoreChangedEvent = "humanity_score_changed"
+
+	// humanityWebhookEvent names the delivered payload
+	humanityWebhookEvent = "humanity_score.threshold_crossed"
+
+	// defaultHumanityWebhookThreshold matches the chain's verified-human threshold
+	defaultHumanityWebhookThreshold = 60
+
+	// maxHumanityWebhooksPerOwner caps subscriptions per authenticated address
+	maxHumanityWebhooksPerOwner = 10
+
+	// maxHumanityBlocksPerPoll bounds catch-up work in one poll
+	maxHumanityBlocksPerPoll = 100
+)
+
+// Crossing directions a subscription can filter on
+const (
+	HumanityDirectionUp   = "up"
+	HumanityDirectionDown = "down"
+	HumanityDirectionBoth = "both"
+)
+
+// HumanityWebhookConfig tunes delivery of humanity score webhooks
+type HumanityWebhookConfig struct {
+	// PollInterval is how often block results are checked (0 disables the watcher)
+	PollInterval time.Duration
+
+	// DeliveryTimeout bounds each webhook POST
+	DeliveryTimeout time.Duration
+}
+
+// DefaultHumanityWebhookConfig returns the default webhook settings
+func DefaultHumanityWebhookConfig() HumanityWebhookConfig {
+	return HumanityWebhookConfig{
+		PollInterval:    5 * time.Second,
+		DeliveryTimeout: 10 * time.Second,
+	}
+}
+
+// HumanityScoreChange is a humanity_score_changed event read from block results
+type HumanityScoreChange struct {
+	Address       string
+	Score         int
+	PreviousScore int
+	VerifiedHuman bool
+	Height        int64
+}
+
+// crossing returns the direction in which the change crosses threshold, or ""
+func (c HumanityScoreChange) crossing(threshold int) string {
+	switch {
+	case c.PreviousScore < threshold && c.Score >= threshold:
+		return HumanityDirectionUp
+	case c.PreviousScore >= threshold && c.Score < threshold:
+		return HumanityDirectionDown
+	}
+	return ""
+}
+
+// HumanityWebhookPayload is the JSON body delivered to subscribers
+type HumanityWebhookPayload struct {
+	Event         string `json:"event"`
+	WebhookID     string `json:"webhook_id"`
+	Address       string `json:"address"`
+	Score         int    `json:"score"`
+	PreviousScore int    `json:"previous_score"`
+	Threshold     int    `json:"threshold"`
+	Direction     string `json:"direction"`
+	VerifiedHuman bool   `json:"verified_human"`
+	Height        int64  `json:"height"`
+	Timestamp     int64  `json:"timestamp"`
+}
+
+// signHumanityWebhook returns the X-Cert-Signature value for a delivery:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>"
+func signHumanityWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// humanityWebhookStore persists webhook subscriptions
+type humanityWebhookStore interface {
+	Create(ctx context.Context, hook *database.HumanityWebhook) error
+	List(ctx context.Context, owner string) ([]database.HumanityWebhook, error) // owner "" lists all
+	Delete(ctx context.Context, owner, id string) (bool, error)
+}
+
+// dbHumanityWebhookStore persists subscriptions in PostgreSQL
+type dbHumanityWebhookStore struct {
+	db *database.DB
+}
+
+func (s dbHumanityWebhookStore) Create(ctx context.Context, hook *database.HumanityWebhook) error {
+	return s.db.CreateHumanityWebhook(ctx, hook)
+}
+
+func (s dbHumanityWebhookStore) List(ctx context.Context, owner string) ([]database.HumanityWebhook, error) {
+	return s.db.ListHumanityWebhooks(ctx, owner)
+}
+
+func (s dbHumanityWebhookStore) Delete(ctx context.Context, owner, id string) (bool, error) {
+	return s.db.DeleteHumanityWebhook(ctx, owner, id)
+}
+
+// memoryHumanityWebhookStore keeps subscriptions in memory (used when no database is configured)
+type memoryHumanityWebhookStore struct {
+	mu    sync.Mutex
+	hooks []database.HumanityWebhook
+	now   func() time.Time
+}
+
+func newMemoryHumanityWebhookStore(now func() time.Time) *memoryHumanityWebhookStore {
+	return &memoryHumanityWebhookStore{now: now}
+}
+
+func (s *memoryHumanityWebhookStore) Create(_ context.Context, hook *database.HumanityWebhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hook.CreatedAt = s.now()
+	s.hooks = append(s.hooks, *hook)
+	return nil
+}
+
+func (s *memoryHumanityWebhookStore) List(_ context.Context, owner string) ([]database.HumanityWebhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []database.HumanityWebhook
+	for _, h := range s.hooks {
+		if owner == "" || h.OwnerAddress == owner {
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryHumanityWebhookStore) Delete(_ context.Context, owner, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, h := range s.hooks {
+		if h.ID == id && h.OwnerAddress == owner {
+			s.hooks = append(s.hooks[:i], s.hooks[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// humanityRPCURL is the CometBFT RPC endpoint block results are read from
+func (s *Server) humanityRPCURL() string {
+	if s.config.ChainRPCURL != "" {
+		return strings.TrimRight(s.config.ChainRPCURL, "/")
+	}
+	return getRPCBaseURL()
+}
+
+// fetchHumanityScoreChanges reads the humanity_score_changed events of a block
+func fetchHumanityScoreChanges(ctx context.Context, rpcURL string, height int64) ([]HumanityScoreChange, error) {
+	body, err := getBody(ctx, fmt.Sprintf("%s/block_results?height=%d", rpcURL, height))
+	if err != nil {
+		return nil, err
+	}
+
+	type rpcEvent struct {
+		Type       string `json:"type"`
+		Attributes []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"attributes"`
+	}
+	var res struct {
+		Result struct {
+			FinalizeBlockEvents []rpcEvent `json:"finalize_block_events"`
+			EndBlockEvents      []rpcEvent `json:"end_block_events"` // CometBFT < 0.38
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("failed to parse block_results: %w", err)
+	}
+
+	var changes []HumanityScoreChange
+	for _, ev := range append(res.Result.FinalizeBlockEvents, res.Result.EndBlockEvents...) {
+		if ev.Type != humanityScoreChangedEvent {
+			continue
+		}
+		change := HumanityScoreChange{Height: height}
+		for _, attr := range ev.Attributes {
+			switch attr.Key {
+			case "address":
+				change.Address = attr.Value
+			case "humanity_score":
+				change.Score, _ = strconv.Atoi(attr.Value)
+			case "previous_score":
+				change.PreviousScore, _ = strconv.Atoi(attr.Value)
+			case "verified_human":
+				change.VerifiedHuman = attr.Value == "true"
+			}
+		}
+		if change.Address != "" {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// pollHumanityScoreEvents delivers webhooks for blocks from next up to the
+// latest height and returns the next height to read. A next of 0 starts at
+// the current tip without replaying history. A failed block is retried on
+// the following poll.
+func (s *Server) pollHumanityScoreEvents(ctx context.Context, next int64) (int64, error) {
+	rpcURL := s.humanityRPCURL()
+	latest, err := fetchLatestHeight(ctx, rpcURL)
+	if err != nil {
+		return next, err
+	}
+	if next == 0 {
+		return latest + 1, nil
+	}
+
+	for h := next; h <= latest && h < next+maxHumanityBlocksPerPoll; h++ {
+		changes, err := fetchHumanityScoreChanges(ctx, rpcURL, h)
+		if err != nil {
+			return h, err
+		}
+		for _, change := range changes {
+			s.notifyHumanityWebhooks(ctx, change)
+		}
+		next = h + 1
+	}
+	return next, nil
+}
+
+// watchHumanityScoreEvents polls block results until ctx is cancelled
+func (s *Server) watchHumanityScoreEvents(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var next int64
+	for {
+		var err error
+		if next, err = s.pollHumanityScoreEvents(ctx, next); err != nil && ctx.Err() == nil {
+			s.logger.Warn("humanity score event poll failed", zap.Int64("height", next), zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// notifyHumanityWebhooks delivers a score change to every subscription whose
+// threshold and direction it matches. Delivery failures are logged, not retried.
+func (s *Server) notifyHumanityWebhooks(ctx context.Context, change HumanityScoreChange) {
+	hooks, err := s.humanityWebhooks.List(ctx, "")
+	if err != nil {
+		s.logger.Warn("failed to list humanity webhooks", zap.Error(err))
+		return
+	}
+
+	for _, hook := range hooks {
+		if hook.Address != "" && hook.Address != change.Address {
+			continue
+		}
+		direction := change.crossing(hook.Threshold)
+		if direction == "" || (hook.Direction != HumanityDirectionBoth && hook.Direction != direction) {
+			continue
+		}
+		payload := HumanityWebhookPayload{
+			Event:         humanityWebhookEvent,
+			WebhookID:     hook.ID,
+			Address:       change.Address,
+			Score:         change.Score,
+			PreviousScore: change.PreviousScore,
+			Threshold:     hook.Threshold,
+			Direction:     direction,
+			VerifiedHuman: change.VerifiedHuman,
+			Height:        change.Height,
+			Timestamp:     s.clock.Now().Unix(),
+		}
+		if err := s.deliverWebhook(ctx, hook, payload.Timestamp, payload); err != nil {
+			s.logger.Warn("humanity webhook delivery failed",
+				zap.String("webhook_id", hook.ID),
+				zap.String("address", change.Address),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// deliverWebhook POSTs a signed JSON payload to a subscription
+func (s *Server) deliverWebhook(ctx context.Context, hook database.HumanityWebhook, sentAt int64, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.HumanityWebhooks.DeliveryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(sentAt, 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Cert-Webhook-Id", hook.ID)
+	req.Header.Set("X-Cert-Timestamp", timestamp)
+	req.Header.Set("X-Cert-Signature", signHumanityWebhook(hook.Secret, timestamp, body))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// handleCreateHumanityWebhook subscribes the caller to humanity score threshold crossings.
+// The signing secret is returned only in this response.
+// POST /api/v1/webhooks/humanity
+func (s *Server) handleCreateHumanityWebhook(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL       string `json:"url"`
+		Address   string `json:"address,omitempty"`
+		Threshold int    `json:"threshold"`
+		Direction string `json:"direction"`
+	}
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		s.respondDecodeError(w, err)
+		return
+	}
+
+	target, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" {
+		s.respondError(w, http.StatusBadRequest, "url must be an absolute http(s) URL")
+		return
+	}
+	if req.Threshold == 0 {
+		req.Threshold = defaultHumanityWebhookThreshold
+	}
+	if req.Threshold < 1 || req.Threshold > 100 {
+		s.respondError(w, http.StatusBadRequest, "threshold must be between 1 and 100")
+		return
+	}
+	switch req.Direction {
+	case "":
+		req.Direction = HumanityDirectionUp
+	case HumanityDirectionUp, HumanityDirectionDown, HumanityDirectionBoth:
+	default:
+		s.respondError(w, http.StatusBadRequest, "direction must be up, down or both")
+		return
+	}
+	if req.Address != "" {
+		bech32Addr, err := toBech32Address(req.Address)
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid address: %v", err))
+			return
+		}
+		req.Address = bech32Addr
+	}
+
+	owner := getAuthenticatedAddress(r)
+	existing, err := s.humanityWebhooks.List(r.Context(), owner)
+	if err != nil {
+		s.logger.Error("failed to list humanity webhooks", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to create webhook")
+		return
+	}
+	if len(existing) >= maxHumanityWebhooksPerOwner {
+		s.respondError(w, http.StatusConflict, fmt.Sprintf("at most %d webhooks per account", maxHumanityWebhooksPerOwner))
+		return
+	}
+
+	hook := &database.HumanityWebhook{
+		ID:           "whk_" + generateUID()[:24],
+		OwnerAddress: owner,
+		URL:          target.String(),
+		Address:      req.Address,
+		Threshold:    req.Threshold,
+		Direction:    req.Direction,
+		Secret:       generateUID(),
+	}
+	if err := s.humanityWebhooks.Create(r.Context(), hook); err != nil {
+		s.logger.Error("failed to create humanity webhook", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to create webhook")
+		return
+	}
+
+	s.respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"webhook": hook,
+		"secret":  hook.Secret,
+	})
+}
+
+// handleListHumanityWebhooks lists the caller's humanity score webhooks
+// GET /api/v1/webhooks/humanity
+func (s *Server) handleListHumanityWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := s.humanityWebhooks.List(r.Context(), getAuthenticatedAddress(r))
+	if err != nil {
+		s.logger.Error("failed to list humanity webhooks", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to list webhooks")
+		return
+	}
+	if hooks == nil {
+		hooks = []database.HumanityWebhook{}
+	}
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{"webhooks": hooks})
+}
+
+// handleDeleteHumanityWebhook removes one of the caller's humanity score webhooks
+// DELETE /api/v1/webhooks/humanity/{id}
+func (s *Server) handleDeleteHumanityWebhook(w http.ResponseWriter, r *http.Request) {
+	deleted, err := s.humanityWebhooks.Delete(r.Context(), getAuthenticatedAddress(r), mux.Vars(r)["id"])
+	if err != nil {
+		s.logger.Error("failed to delete humanity webhook", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to delete webhook")
+		return
+	}
+	if !deleted {
+		s.respondError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
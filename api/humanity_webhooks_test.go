@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type webhookDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+// fakeHumanityChain serves /status and /block_results, with one humanity event per scripted height
+type fakeHumanityChain struct {
+	latest atomic.Int64
+	events map[int64]string
+}
+
+func (c *fakeHumanityChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/status":
+		fmt.Fprintf(w, `{"result":{"sync_info":{"latest_block_height":"%d"}}}`, c.latest.Load())
+	case "/block_results":
+		var height int64
+		fmt.Sscan(r.URL.Query().Get("height"), &height)
+		fmt.Fprintf(w, `{"result":{"height":"%d","finalize_block_events":[%s]}}`, height, c.events[height])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func humanityEventJSON(address string, previous, score int) string {
+	return fmt.Sprintf(`{"type":"humanity_score_changed","attributes":[
+		{"key":"address","value":"%s"},
+		{"key":"humanity_score","value":"%d"},
+		{"key":"previous_score","value":"%d"},
+		{"key":"verified_human","value":"%t"},
+		{"key":"threshold_crossed","value":""}]}`, address, score, previous, score >= 60)
+}
+
+func TestHumanityWebhookThresholdCrossing(t *testing.T) {
+	const subject = "cert1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+
+	var mu sync.Mutex
+	deliveries := map[string][]webhookDelivery{}
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		deliveries[r.URL.Path] = append(deliveries[r.URL.Path], webhookDelivery{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+
+	chain := &fakeHumanityChain{events: map[int64]string{
+		11: humanityEventJSON(subject, 40, 60),                                      // crosses 60 upwards
+		12: humanityEventJSON("cert1qyqszqgpqyqszqgpqyqszqgpqyqszqgpjnp7du", 0, 10), // below every threshold
+		13: humanityEventJSON(subject, 60, 40),                                      // crosses 60 downwards
+	}}
+	chain.latest.Store(10)
+	rpc := httptest.NewServer(chain)
+	defer rpc.Close()
+
+	clock := newFakeClock(time.Unix(1_700_000_000, 0))
+	config := DefaultConfig()
+	config.Clock = clock
+	config.ChainRPCURL = rpc.URL
+	s := NewServer(config, zap.NewNop())
+	token := testAuthToken(t, config.JWTSecret, "cert1integrator")
+
+	subscribe := func(body string) (int, map[string]any) {
+		req := httptest.NewRequest("POST", "/api/v1/webhooks/humanity", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+
+	code, created := subscribe(`{"url":"` + receiver.URL + `/verified"}`)
+	if code != http.StatusCreated {
+		t.Fatalf("subscribe status = %d, body = %v", code, created)
+	}
+	secret, _ := created["secret"].(string)
+	hook := created["webhook"].(map[string]any)
+	if secret == "" || hook["threshold"].(float64) != 60 || hook["direction"] != HumanityDirectionUp {
+		t.Fatalf("created webhook = %v", created)
+	}
+	if _, leaked := hook["secret"]; leaked {
+		t.Error("secret serialized inside the webhook object")
+	}
+	if code, out := subscribe(`{"url":"` + receiver.URL + `/high","threshold":90}`); code != http.StatusCreated {
+		t.Fatalf("subscribe status = %d, body = %v", code, out)
+	}
+	if code, out := subscribe(`{"url":"` + receiver.URL + `/lost","direction":"down","address":"` + subject + `"}`); code != http.StatusCreated {
+		t.Fatalf("subscribe status = %d, body = %v", code, out)
+	}
+	for _, bad := range []string{`{"url":"ftp://example.com"}`, `{"url":"` + receiver.URL + `","threshold":101}`, `{"url":"` + receiver.URL + `","direction":"sideways"}`} {
+		if code, _ := subscribe(bad); code != http.StatusBadRequest {
+			t.Errorf("subscribe(%s) status = %d, want 400", bad, code)
+		}
+	}
+
+	// The first poll starts at the tip; later polls deliver each new block
+	ctx := context.Background()
+	next, err := s.pollHumanityScoreEvents(ctx, 0)
+	if err != nil || next != 11 {
+		t.Fatalf("first poll = %d, %v; want 11", next, err)
+	}
+	chain.latest.Store(13)
+	if next, err = s.pollHumanityScoreEvents(ctx, next); err != nil || next != 14 {
+		t.Fatalf("second poll = %d, %v; want 14", next, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := len(deliveries["/verified"]); got != 1 {
+		t.Fatalf("verified deliveries = %d, want 1", got)
+	}
+	if got := len(deliveries["/high"]); got != 0 {
+		t.Errorf("threshold-90 deliveries = %d, want 0", got)
+	}
+	if got := len(deliveries["/lost"]); got != 1 {
+		t.Errorf("downward deliveries = %d, want 1", got)
+	}
+
+	d := deliveries["/verified"][0]
+	timestamp := d.header.Get("X-Cert-Timestamp")
+	if want := signHumanityWebhook(secret, timestamp, d.body); d.header.Get("X-Cert-Signature") != want {
+		t.Errorf("signature = %q, want %q", d.header.Get("X-Cert-Signature"), want)
+	}
+	if timestamp != "1700000000" {
+		t.Errorf("timestamp = %q", timestamp)
+	}
+	var payload HumanityWebhookPayload
+	if err := json.Unmarshal(d.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Address != subject || payload.Score != 60 || payload.PreviousScore != 40 ||
+		payload.Direction != HumanityDirectionUp || !payload.VerifiedHuman || payload.Height != 11 ||
+		payload.Event != humanityWebhookEvent || payload.WebhookID != hook["id"] {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestHumanityWebhookListAndDelete(t *testing.T) {
+	config := DefaultConfig()
+	s := NewServer(config, zap.NewNop())
+	owner := testAuthToken(t, config.JWTSecret, "cert1owner")
+	other := testAuthToken(t, config.JWTSecret, "cert1other")
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("POST", "/api/v1/webhooks/humanity", owner, `{"url":"https://example.com/hook"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d", rec.Code)
+	}
+	var created struct {
+		Webhook struct {
+			ID string `json:"id"`
+		} `json:"webhook"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &created)
+
+	var list struct {
+		Webhooks []map[string]any `json:"webhooks"`
+	}
+	_ = json.Unmarshal(do("GET", "/api/v1/webhooks/humanity", other, "").Body.Bytes(), &list)
+	if len(list.Webhooks) != 0 {
+		t.Errorf("other account sees %d webhooks", len(list.Webhooks))
+	}
+	if rec := do("DELETE", "/api/v1/webhooks/humanity/"+created.Webhook.ID, other, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("foreign delete status = %d, want 404", rec.Code)
+	}
+	if rec := do("DELETE", "/api/v1/webhooks/humanity/"+created.Webhook.ID, owner, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("delete status = %d, want 204", rec.Code)
+	}
+	_ = json.Unmarshal(do("GET", "/api/v1/webhooks/humanity", owner, "").Body.Bytes(), &list)
+	if len(list.Webhooks) != 0 {
+		t.Errorf("webhooks after delete = %v", list.Webhooks)
+	}
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/chaincertify/certd/api/database"
+	"go.uber.org/zap"
+)
+
+// Attestation issuance metering for API keys
+// Attestations created through an API key count against a per-tier monthly quota.
+
+// DefaultIssuanceQuotas returns the monthly attestation quota per API key tier (0 = unlimited)
+func DefaultIssuanceQuotas() map[string]int {
+	return map[string]int{
+		"free":       25,
+		"developer":  1000,
+		"enterprise": 50000,
+	}
+}
+
+// issuanceUpgradePath is where quota-exceeded responses point callers for higher tiers
+const issuanceUpgradePath = "/api/v1/api-keys/tiers"
+
+// issuancePeriodStart returns the start of the monthly billing period containing t
+func issuancePeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// issuanceCounter tracks attestations issued per API key and billing period
+type issuanceCounter interface {
+	Reserve(ctx context.Context, keyID string, periodStart time.Time, quota int) (int, bool, error)
+	Release(ctx context.Context, keyID string, periodStart time.Time) error
+	Count(ctx context.Context, keyID string, periodStart time.Time) (int, error)
+}
+
+// dbIssuanceCounter persists issuance counts in PostgreSQL
+type dbIssuanceCounter struct {
+	db *database.DB
+}
+
+func (c dbIssuanceCounter) Reserve(ctx context.Context, keyID string, periodStart time.Time, quota int) (int, bool, error) {
+	return c.db.ReserveAttestationIssuance(ctx, keyID, periodStart, quota)
+}
+
+func (c dbIssuanceCounter) Release(ctx context.Context, keyID string, periodStart time.Time) error {
+	return c.db.ReleaseAttestationIssuance(ctx, keyID, periodStart)
+}
+
+func (c dbIssuanceCounter) Count(ctx context.Context, keyID string, periodStart time.Time) (int, error) {
+	return c.db.GetAttestationIssuanceCount(ctx, keyID, periodStart)
+}
+
+type issuanceKey struct {
+	keyID       string
+	periodStart time.Time
+}
+
+// memoryIssuanceCounter keeps issuance counts in memory (used when no database is configured)
+type memoryIssuanceCounter struct {
+	mu     sync.Mutex
+	counts map[issuanceKey]int
+}
+
+func newMemoryIssuanceCounter() *memoryIssuanceCounter {
+	return &memoryIssuanceCounter{counts: make(map[issuanceKey]int)}
+}
+
+func (c *memoryIssuanceCounter) Reserve(_ context.Context, keyID string, periodStart time.Time, quota int) (int, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := issuanceKey{keyID: keyID, periodStart: periodStart}
+	if quota > 0 && c.counts[k] >= quota {
+		return c.counts[k], false, nil
+	}
+	c.counts[k]++
+	return c.counts[k], true, nil
+}
+
+func (c *memoryIssuanceCounter) Release(_ context.Context, keyID string, periodStart time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := issuanceKey{keyID: keyID, periodStart: periodStart}
+	if c.counts[k] > 0 {
+		c.counts[k]--
+	}
+	return nil
+}
+
+func (c *memoryIssuanceCounter) Count(_ context.Context, keyID string, periodStart time.Time) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[issuanceKey{keyID: keyID, periodStart: periodStart}], nil
+}
+
+// issuanceQuota returns the monthly quota for a tier, falling back to free
+func (s *Server) issuanceQuota(tier string) int {
+	if quota, ok := s.config.IssuanceQuotas[tier]; ok {
+		return quota
+	}
+	return s.config.IssuanceQuotas["free"]
+}
+
+// IssuanceUsage describes an API key's attestation issuance for the current period
+type IssuanceUsage struct {
+	PeriodStart Timestamp `json:"period_start"`
+	Issued      int       `json:"issued"`
+	Quota       int       `json:"quota"` // 0 = unlimited
+	Remaining   int       `json:"remaining,omitempty"`
+}
+
+// issuanceUsage returns the current-period issuance for a key
+func (s *Server) issuanceUsage(ctx context.Context, keyID, tier string) (*IssuanceUsage, error) {
+	period := issuancePeriodStart(s.clock.Now())
+	issued, err := s.issuance.Count(ctx, keyID, period)
+	if err != nil {
+		return nil, err
+	}
+	usage := &IssuanceUsage{PeriodStart: NewTimestamp(period), Issued: issued, Quota: s.issuanceQuota(tier)}
+	if usage.Quota > 0 {
+		usage.Remaining = maxInt(usage.Quota-issued, 0)
+	}
+	return usage, nil
+}
+
+// reserveIssuance counts an attestation against the calling API key's monthly quota.
+// Requests without an API key are not metered. On success it returns a release
+// func to hand the reservation back if the attestation is not created; on failure
+// it has already written the error response.
+func (s *Server) reserveIssuance(w http.ResponseWriter, r *http.Request) (func(), bool) {
+	key, ok := r.Context().Value(APIKeyInfoKey).(*database.APIKeyNew)
+	if !ok || key == nil {
+		return func() {}, true
+	}
+
+	tier := key.Tier
+	if tier == "" {
+		tier = "free"
+	}
+	quota := s.issuanceQuota(tier)
+	period := issuancePeriodStart(s.clock.Now())
+
+	issued, ok, err := s.issuance.Reserve(r.Context(), key.ID, period, quota)
+	if err != nil {
+		s.logger.Error("failed to reserve attestation issuance", zap.String("api_key_id", key.ID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "failed to check issuance quota")
+		return nil, false
+	}
+	if !ok {
+		s.respondJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+			"error":        "quota exceeded",
+			"message":      "monthly attestation issuance quota reached for this API key; upgrade your tier for a higher quota",
+			"tier":         tier,
+			"quota":        quota,
+			"issued":       issued,
+			"period_start": period,
+			"upgrade_url":  issuanceUpgradePath,
+		})
+		return nil, false
+	}
+
+	return func() {
+		if err := s.issuance.Release(context.Background(), key.ID, period); err != nil {
+			s.logger.Error("failed to release attestation issuance", zap.String("api_key_id", key.ID), zap.Error(err))
+		}
+	}, true
+}
+
+// requireAuthOrAPIKey allows either a JWT or an API key validated by
+// apiKeyMiddleware. An API key acts for its owner, who becomes the
+// authenticated address; a key without an owner is rejected.
+func (s *Server) requireAuthOrAPIKey(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if key, ok := r.Context().Value(APIKeyInfoKey).(*database.APIKeyNew); ok && key != nil && r.Header.Get("Authorization") == "" {
+			if key.OwnerAddress == "" {
+				s.respondError(w, http.StatusUnauthorized, "API key has no owner")
+				return
+			}
+			handler(w, r.WithContext(context.WithValue(r.Context(), UserAddressKey, key.OwnerAddress)))
+			return
+		}
+		s.requireAuth(handler)(w, r)
+	}
+}
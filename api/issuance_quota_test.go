@@ -0,0 +1,185 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chaincertify/certd/api/database"
+	"go.uber.org/zap"
+)
+
+// TestMemoryIssuanceCounter tests per-key, per-period issuance counting
+func TestMemoryIssuanceCounter(t *testing.T) {
+	ctx := context.Background()
+	c := newMemoryIssuanceCounter()
+	period := issuancePeriodStart(time.Date(2025, 3, 17, 10, 0, 0, 0, time.UTC))
+
+	for i := 1; i <= 3; i++ {
+		issued, ok, _ := c.Reserve(ctx, "key-a", period, 3)
+		if !ok || issued != i {
+			t.Fatalf("reserve %d: issued=%d ok=%v", i, issued, ok)
+		}
+	}
+	if issued, ok, _ := c.Reserve(ctx, "key-a", period, 3); ok || issued != 3 {
+		t.Errorf("reserve past quota: issued=%d ok=%v, want 3 false", issued, ok)
+	}
+
+	// Other keys and later periods are counted separately
+	if _, ok, _ := c.Reserve(ctx, "key-b", period, 3); !ok {
+		t.Error("key-b should have its own quota")
+	}
+	nextPeriod := issuancePeriodStart(period.AddDate(0, 1, 0))
+	if _, ok, _ := c.Reserve(ctx, "key-a", nextPeriod, 3); !ok {
+		t.Error("quota should reset in the next billing month")
+	}
+
+	// Releasing hands back a slot
+	_ = c.Release(ctx, "key-a", period)
+	if n, _ := c.Count(ctx, "key-a", period); n != 2 {
+		t.Errorf("count after release = %d, want 2", n)
+	}
+
+	// Zero quota is unlimited
+	for i := 0; i < 10; i++ {
+		if _, ok, _ := c.Reserve(ctx, "key-unlimited", period, 0); !ok {
+			t.Fatal("zero quota should be unlimited")
+		}
+	}
+}
+
+// TestIssuancePeriodStart tests monthly billing period boundaries
+func TestIssuancePeriodStart(t *testing.T) {
+	got := issuancePeriodStart(time.Date(2025, 2, 28, 23, 59, 0, 0, time.UTC))
+	want := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("issuancePeriodStart = %s, want %s", got, want)
+	}
+}
+
+func apiKeyRequest(t *testing.T, key *database.APIKeyNew, body interface{}) *http.Request {
+	t.Helper()
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", "/api/v1/attestations", bytes.NewReader(data))
+	return req.WithContext(context.WithValue(req.Context(), APIKeyInfoKey, key))
+}
+
+// TestRequireAuthOrAPIKeyResolvesOwner tests that an API key request acts as the key's owner
+func TestRequireAuthOrAPIKeyResolvesOwner(t *testing.T) {
+	server := NewServer(DefaultConfig(), zap.NewNop())
+	var attester string
+	handler := server.requireAuthOrAPIKey(func(w http.ResponseWriter, r *http.Request) {
+		attester = getAuthenticatedAddress(r)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, apiKeyRequest(t, &database.APIKeyNew{ID: "key-1", OwnerAddress: "cert1issuer", Tier: "free"}, nil))
+	if rec.Code != http.StatusCreated || attester != "cert1issuer" {
+		t.Errorf("owned key: status %d, attester %q; want 201 as cert1issuer", rec.Code, attester)
+	}
+
+	for name, req := range map[string]*http.Request{
+		"key without owner": apiKeyRequest(t, &database.APIKeyNew{ID: "key-2", Tier: "free"}, nil),
+		"no credentials":    httptest.NewRequest("POST", "/api/v1/attestations", nil),
+	} {
+		attester = ""
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != http.StatusUnauthorized || attester != "" {
+			t.Errorf("%s: status %d, attester %q; want 401", name, rec.Code, attester)
+		}
+	}
+}
+
+// TestAttestationIssuanceQuotaEnforced tests that API-key issuance past quota is rejected
+func TestAttestationIssuanceQuotaEnforced(t *testing.T) {
+	config := DefaultConfig()
+	config.IssuanceQuotas = map[string]int{"free": 2, "developer": 10}
+	server := NewServer(config, zap.NewNop())
+
+	key := &database.APIKeyNew{ID: "key-free", OwnerAddress: "cert1issuer", Tier: "free"}
+	period := issuancePeriodStart(time.Now())
+	for i := 0; i < 2; i++ {
+		if _, ok, _ := server.issuance.Reserve(context.Background(), key.ID, period, 2); !ok {
+			t.Fatal("setup reservation failed")
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	server.handleCreateAttestation(rec, apiKeyRequest(t, key, map[string]string{"schema_uid": "0x4", "data": "0x00"}))
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusTooManyRequests, rec.Body.String())
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp["error"] != "quota exceeded" {
+		t.Errorf("error = %v, want quota exceeded", resp["error"])
+	}
+	if resp["upgrade_url"] != issuanceUpgradePath {
+		t.Errorf("upgrade_url = %v, want %s", resp["upgrade_url"], issuanceUpgradePath)
+	}
+	if resp["quota"].(float64) != 2 || resp["issued"].(float64) != 2 {
+		t.Errorf("quota/issued = %v/%v, want 2/2", resp["quota"], resp["issued"])
+	}
+
+	// A higher tier key is still allowed to reserve
+	paid := &database.APIKeyNew{ID: "key-dev", OwnerAddress: "cert1issuer", Tier: "developer"}
+	if quota := server.issuanceQuota(paid.Tier); quota != 10 {
+		t.Errorf("developer quota = %d, want 10", quota)
+	}
+}
+
+// TestAttestationIssuanceReleasedOnFailure tests that a failed attestation does not consume quota
+func TestAttestationIssuanceReleasedOnFailure(t *testing.T) {
+	config := DefaultConfig()
+	config.IssuanceQuotas = map[string]int{"free": 1}
+	server := NewServer(config, zap.NewNop())
+
+	key := &database.APIKeyNew{ID: "key-free", OwnerAddress: "cert1issuer", Tier: "free"}
+
+	// The chain tx cannot be submitted in tests, so the request fails after reserving
+	rec := httptest.NewRecorder()
+	server.handleCreateAttestation(rec, apiKeyRequest(t, key, map[string]string{"schema_uid": "0x4", "data": "0x00"}))
+	if rec.Code == http.StatusCreated {
+		t.Skip("chain available; failure path not exercised")
+	}
+
+	n, _ := server.issuance.Count(context.Background(), key.ID, issuancePeriodStart(time.Now()))
+	if n != 0 {
+		t.Errorf("issued count after failed tx = %d, want 0", n)
+	}
+}
+
+// TestAttestationIssuanceCountedPerKey tests usage reporting of issued attestations per key
+func TestAttestationIssuanceCountedPerKey(t *testing.T) {
+	config := DefaultConfig()
+	server := NewServer(config, zap.NewNop())
+	ctx := context.Background()
+	period := issuancePeriodStart(time.Now())
+
+	for i := 0; i < 4; i++ {
+		server.issuance.Reserve(ctx, "key-a", period, 0)
+	}
+	server.issuance.Reserve(ctx, "key-b", period, 0)
+
+	usage, err := server.issuanceUsage(ctx, "key-a", "free")
+	if err != nil {
+		t.Fatalf("issuanceUsage: %v", err)
+	}
+	if usage.Issued != 4 || usage.Quota != 25 || usage.Remaining != 21 {
+		t.Errorf("usage = %+v, want issued 4, quota 25, remaining 21", usage)
+	}
+
+	usage, _ = server.issuanceUsage(ctx, "key-b", "enterprise")
+	if usage.Issued != 1 || usage.Quota != 50000 {
+		t.Errorf("usage = %+v, want issued 1, quota 50000", usage)
+	}
+}
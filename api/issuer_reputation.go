@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// IssuerReputation scores an address as an attestation issuer so verifiers can
+// weigh the attestations it signs. Unlike the trust score, it is driven by the
+// issuer's track record on chain.
+type IssuerReputation struct {
+	Address         string         `json:"address"`
+	Score           int            `json:"score"`
+	TotalIssued     int            `json:"total_issued"`
+	ActiveIssued    int            `json:"active_issued"`
+	Revoked         int            `json:"revoked"`
+	RevocationRate  float64        `json:"revocation_rate"`
+	AgeDays         int            `json:"age_days"`
+	IsKYC           bool           `json:"is_kyc"`
+	IsInstitutional bool           `json:"is_institutional"`
+	Factors         map[string]int `json:"factors"`
+}
+
+// issuerReputationInputs are the raw signals calculateIssuerReputation scores
+type issuerReputationInputs struct {
+	Issued          int
+	Revoked         int
+	Since           time.Time // Profile creation or first attestation; zero if unknown
+	IsKYC           bool
+	IsInstitutional bool
+}
+
+// calculateIssuerReputation returns a 0-100 reputation. Identity and
+// institutional status, active volume and age add points; the revocation
+// rate subtracts up to revocationPenaltyMax, so a busy issuer that revokes
+// much of what it signs ranks below a smaller clean one.
+func calculateIssuerReputation(now time.Time, in issuerReputationInputs) (int, map[string]int) {
+	const revocationPenaltyMax = 60
+
+	factors := map[string]int{}
+
+	if in.IsKYC {
+		factors["kyc"] = 20
+	}
+	if in.IsInstitutional {
+		factors["institutional"] = 20
+	}
+
+	// Volume bonus: non-revoked attestations, tiered so spam volume saturates
+	active := in.Issued - in.Revoked
+	switch {
+	case active >= 100:
+		factors["volume"] = 40
+	case active >= 25:
+		factors["volume"] = 30
+	case active >= 5:
+		factors["volume"] = 20
+	case active >= 1:
+		factors["volume"] = 10
+	}
+
+	// Age bonus: up to 20 points
+	if !in.Since.IsZero() {
+		daysOld := int(now.Sub(in.Since).Hours() / 24)
+		switch {
+		case daysOld > 365:
+			factors["age"] = 20
+		case daysOld > 180:
+			factors["age"] = 15
+		case daysOld > 30:
+			factors["age"] = 10
+		case daysOld > 7:
+			factors["age"] = 5
+		}
+	}
+
+	// Revocation penalty: quadratic so occasional corrections cost little
+	// but a high revocation rate wipes out most of the score
+	if in.Issued > 0 && in.Revoked > 0 {
+		rate := float64(in.Revoked) / float64(in.Issued)
+		factors["revocation_penalty"] = -int(math.Round(math.Min(1, 2*rate*rate) * revocationPenaltyMax))
+	}
+
+	score := 0
+	for _, v := range factors {
+		score += v
+	}
+	if score < 0 {
+		score = 0
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score, factors
+}
+
+// isInstitutionalCredential reports whether a verified credential marks
+// its holder as an institution rather than an individual
+func (s *Server) isInstitutionalCredential(credType, issuer string) bool {
+	badge, ok := s.mapCredentialToBadge(credType, issuer)
+	return ok && badge.Institutional
+}
+
+// isRevokedTimestamp reports whether an attestation's revocation_time is set.
+// The chain serializes an unset time as Go's zero time.
+func isRevokedTimestamp(ts string) bool {
+	ts = strings.TrimSpace(ts)
+	return ts != "" && ts != "0" && !strings.HasPrefix(ts, "0001-01-01")
+}
+
+// issuerReputation gathers the on-chain and profile signals for address and
+// scores them. bech32Addr is address in chain form.
+func (s *Server) issuerReputation(ctx context.Context, address, bech32Addr string) (IssuerReputation, error) {
+	attestations, err := s.queryAttestationsByAttester(bech32Addr)
+	if err != nil {
+		return IssuerReputation{}, err
+	}
+
+	var in issuerReputationInputs
+	for _, a := range attestations {
+		in.Issued++
+		if revoked, _ := a["revoked"].(bool); revoked {
+			in.Revoked++
+		}
+		if ts, _ := a["time"].(string); ts != "" {
+			if t, err := time.Parse(time.RFC3339Nano, ts); err == nil && (in.Since.IsZero() || t.Before(in.Since)) {
+				in.Since = t
+			}
+		}
+	}
+
+	if s.db != nil {
+		dbAddr := strings.ToLower(address)
+		if creds, err := s.db.GetCredentialsByUser(ctx, dbAddr); err == nil {
+			for _, c := range creds {
+				if !c.Verified {
+					continue
+				}
+				switch strings.ToUpper(c.CredentialType) {
+				case "KYC", "KYC_L1", "KYC_L2", "IDENTITY":
+					in.IsKYC = true
+				}
+				if s.isInstitutionalCredential(c.CredentialType, c.Issuer) {
+					in.IsInstitutional = true
+				}
+			}
+		}
+		if prof, err := s.db.GetProfile(ctx, dbAddr); err == nil && prof != nil && (in.Since.IsZero() || prof.CreatedAt.Before(in.Since)) {
+			in.Since = prof.CreatedAt
+		}
+	}
+
+	now := s.clock.Now()
+	score, factors := calculateIssuerReputation(now, in)
+	rep := IssuerReputation{
+		Address:         address,
+		Score:           score,
+		TotalIssued:     in.Issued,
+		ActiveIssued:    in.Issued - in.Revoked,
+		Revoked:         in.Revoked,
+		IsKYC:           in.IsKYC,
+		IsInstitutional: in.IsInstitutional,
+		Factors:         factors,
+	}
+	if in.Issued > 0 {
+		rep.RevocationRate = float64(in.Revoked) / float64(in.Issued)
+	}
+	if !in.Since.IsZero() {
+		rep.AgeDays = int(now.Sub(in.Since).Hours() / 24)
+	}
+	return rep, nil
+}
+
+// handleGetIssuerReputation returns the issuer reputation for an address
+// GET /api/v1/identity/{address}/issuer-reputation
+func (s *Server) handleGetIssuerReputation(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	bech32Addr, err := toBech32Address(address)
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rep, err := s.issuerReputation(ctx, address, bech32Addr)
+	if err != nil {
+		s.logger.Warn("failed to compute issuer reputation", zap.String("address", address), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query issuer attestations")
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, rep)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestCalculateIssuerReputationPenalizesRevocations(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	since := now.AddDate(-2, 0, 0)
+
+	clean, _ := calculateIssuerReputation(now, issuerReputationInputs{Issued: 200, Revoked: 2, Since: since})
+	churny, factors := calculateIssuerReputation(now, issuerReputationInputs{Issued: 200, Revoked: 90, Since: since})
+	small, _ := calculateIssuerReputation(now, issuerReputationInputs{Issued: 10, Since: since})
+
+	if clean <= churny {
+		t.Errorf("clean issuer = %d, want above high-revocation issuer %d", clean, churny)
+	}
+	if small <= churny {
+		t.Errorf("small clean issuer = %d, want above high-revocation issuer %d", small, churny)
+	}
+	if factors["revocation_penalty"] >= 0 {
+		t.Errorf("revocation_penalty = %d, want negative", factors["revocation_penalty"])
+	}
+
+	tests := []struct {
+		name string
+		in   issuerReputationInputs
+		want int
+	}{
+		{"unknown issuer", issuerReputationInputs{}, 0},
+		{"fully credentialed", issuerReputationInputs{Issued: 150, Since: since, IsKYC: true, IsInstitutional: true}, 100},
+		{"everything revoked", issuerReputationInputs{Issued: 5, Revoked: 5, Since: since}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := calculateIssuerReputation(now, tt.in); got != tt.want {
+				t.Errorf("score = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// newIssuerTestServer returns a server on which testBalanceAddr has issued n
+// attestations, the first `revoked` of them revoked. The first is also served
+// by certd as attestation 0x00.
+func newIssuerTestServer(t *testing.T, clock Clock, n, revoked int) *Server {
+	t.Helper()
+	issuedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	items := make([]attestationtypes.Attestation, n)
+	for i := range items {
+		items[i] = attestationtypes.Attestation{UID: fmt.Sprintf("0x%02x", i), Time: issuedAt, AttestationType: "public"}
+		if i < revoked {
+			items[i].RevocationTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		}
+	}
+
+	orig := runCertdCLI
+	t.Cleanup(func() { runCertdCLI = orig })
+	single, _ := json.Marshal(map[string]any{"attestation": map[string]string{
+		"uid":              "0x00",
+		"attester":         testBalanceAddr,
+		"time":             "2023-01-01T00:00:00Z",
+		"attestation_type": "public",
+		"revocation_time":  "0001-01-01T00:00:00Z",
+	}})
+	runCertdCLI = func(...string) ([]byte, error) {
+		return single, nil
+	}
+
+	stub := &chainGRPCStub{issued: map[string][]attestationtypes.Attestation{testBalanceAddr: items}}
+	return newChainGRPCTestServer(t, stub, func(config *Config) { config.Clock = clock })
+}
+
+func getJSON(t *testing.T, s *Server, path string) map[string]any {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d: %s", path, rec.Code, rec.Body.String())
+	}
+	var out map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return out
+}
+
+func TestHandleGetIssuerReputation(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}
+	path := "/api/v1/identity/" + testBalanceAddr + "/issuer-reputation"
+
+	clean := getJSON(t, newIssuerTestServer(t, clock, 120, 0), path)
+	churny := getJSON(t, newIssuerTestServer(t, clock, 120, 60), path)
+
+	if clean["score"].(float64) <= churny["score"].(float64) {
+		t.Errorf("clean score %v, want above high-revocation score %v", clean["score"], churny["score"])
+	}
+	if churny["revoked"].(float64) != 60 || churny["revocation_rate"].(float64) != 0.5 {
+		t.Errorf("revoked = %v rate = %v, want 60 and 0.5", churny["revoked"], churny["revocation_rate"])
+	}
+	if clean["age_days"].(float64) < 365 {
+		t.Errorf("age_days = %v, want at least a year from the first attestation", clean["age_days"])
+	}
+}
+
+func TestGetAttestationEmbedsIssuerReputation(t *testing.T) {
+	s := newIssuerTestServer(t, &fakeClock{now: time.Now()}, 3, 0)
+
+	out := getJSON(t, s, "/api/v1/attestations/0x00?include=issuer_reputation")
+	rep, ok := out["issuer_reputation"].(map[string]any)
+	if !ok {
+		t.Fatalf("issuer_reputation missing from %v", out)
+	}
+	if rep["total_issued"].(float64) != 3 {
+		t.Errorf("total_issued = %v, want 3", rep["total_issued"])
+	}
+
+	plain := getJSON(t, s, "/api/v1/attestations/0x00")
+	if _, ok := plain["issuer_reputation"]; ok {
+		t.Error("issuer_reputation embedded without include=issuer_reputation")
+	}
+	if !strings.HasPrefix(plain["issuer"].(string), "cert1") {
+		t.Errorf("issuer = %v", plain["issuer"])
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// KYC failure reasons
+// A session that ends Declined, Abandoned or Expired is terminal: the status
+// endpoint explains what went wrong, and starting KYC again opens a fresh Didit
+// session rather than returning the failed one.
+
+// kycTerminalFailure reports whether a session status ended without approval
+func kycTerminalFailure(status string) bool {
+	switch status {
+	case database.KYCStatusDeclined, database.KYCStatusAbandoned, database.KYCStatusExpired:
+		return true
+	}
+	return false
+}
+
+// diditDecisionCheck is a check within a Didit decision, with the warnings it raised
+type diditDecisionCheck struct {
+	Status   string `json:"status"`
+	Warnings []struct {
+		Risk             string `json:"risk"`
+		ShortDescription string `json:"short_description"`
+	} `json:"warnings"`
+}
+
+// kycFailureReason parses why a session with a terminal status failed from its
+// Didit decision: the checks that were not approved and the warnings they raised
+func kycFailureReason(status string, decision *string) *database.KYCFailureReason {
+	reason := &database.KYCFailureReason{Status: status}
+	if decision == nil {
+		return reason
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(*decision), &fields); err != nil {
+		return reason
+	}
+	for name, raw := range fields {
+		var check diditDecisionCheck
+		if json.Unmarshal(raw, &check) != nil || check.Status == "" {
+			continue // Not a check, e.g. session_id or the top-level status
+		}
+		if check.Status == database.KYCStatusApproved {
+			continue
+		}
+		reason.FailedChecks = append(reason.FailedChecks, name)
+		for _, w := range check.Warnings {
+			if w.ShortDescription != "" {
+				reason.Warnings = append(reason.Warnings, w.ShortDescription)
+			} else if w.Risk != "" {
+				reason.Warnings = append(reason.Warnings, w.Risk)
+			}
+		}
+	}
+	// Map iteration is random; keep the reason stable across reads
+	sort.Strings(reason.FailedChecks)
+	sort.Strings(reason.Warnings)
+	return reason
+}
+
+// sessionFailureReason returns why a failed session failed, parsing its stored
+// decision when no reason was recorded; nil unless the session failed
+func sessionFailureReason(session *database.KYCSession) *database.KYCFailureReason {
+	if !kycTerminalFailure(session.Status) {
+		return nil
+	}
+	if session.FailureReason != nil {
+		return session.FailureReason
+	}
+	return kycFailureReason(session.Status, session.DecisionData)
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+func TestKYCFailureReason(t *testing.T) {
+	decision := `{"session_id":"s1","status":"Declined",
+		"id_verification":{"status":"Approved","warnings":[]},
+		"liveness":{"status":"Declined","warnings":[{"risk":"LOW_LIVENESS_SCORE","short_description":"Liveness score too low"}]},
+		"face_match":{"status":"Declined","warnings":[{"risk":"LOW_FACE_MATCH_SIMILARITY"}]},
+		"metadata":{"source":"web"}}`
+
+	got := kycFailureReason(database.KYCStatusDeclined, &decision)
+	want := &database.KYCFailureReason{
+		Status:       database.KYCStatusDeclined,
+		FailedChecks: []string{"face_match", "liveness"},
+		Warnings:     []string{"LOW_FACE_MATCH_SIMILARITY", "Liveness score too low"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reason = %+v, want %+v", got, want)
+	}
+
+	// Abandoned sessions have no decision to explain them
+	if got := kycFailureReason(database.KYCStatusAbandoned, nil); got.Status != database.KYCStatusAbandoned || len(got.FailedChecks) != 0 {
+		t.Errorf("abandoned reason = %+v", got)
+	}
+
+	// Only failed sessions report a reason
+	approved := &database.KYCSession{Status: database.KYCStatusApproved, DecisionData: &decision}
+	if r := sessionFailureReason(approved); r != nil {
+		t.Errorf("approved session reason = %+v, want nil", r)
+	}
+}
+
+func TestKYCRetryAfterDecline(t *testing.T) {
+	const secret = "didit-webhook-secret"
+	user := oauthTestAddress
+
+	var created int
+	didit := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/session/" || r.Header.Get("X-Api-Key") != "didit-key" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		created++
+		fmt.Fprintf(w, `{"session_id":"retry-%d","status":"Not Started","workflow_id":"wf-basic","url":"https://verify.example/retry-%d"}`, created, created)
+	}))
+	defer didit.Close()
+	t.Setenv("DIDIT_API_KEY", "didit-key")
+	t.Setenv("DIDIT_WORKFLOW_ID", "wf-basic")
+	t.Setenv("DIDIT_BASE_URL", didit.URL)
+	t.Setenv("DIDIT_WEBHOOK_SECRET", secret)
+
+	clock := newFakeClock(time.Unix(1_700_000_000, 0))
+	config := DefaultConfig()
+	config.Clock = clock
+	s := NewServer(config, zap.NewNop())
+	store := &memoryKYCStore{sessions: map[string]*database.KYCSession{
+		"first": {SessionID: "first", UserAddress: user, VendorData: user, WorkflowID: "wf-basic",
+			Status: database.KYCStatusInProgress, CreatedAt: time.Now().Add(-time.Hour)},
+	}}
+	s.kyc = store
+	token := testAuthToken(t, config.JWTSecret, user)
+
+	serve := func(method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec
+	}
+	webhook := func(body string) {
+		t.Helper()
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		rec := serve("POST", "/api/v1/kyc/webhook", body, map[string]string{
+			"X-Signature": hex.EncodeToString(mac.Sum(nil)),
+			"X-Timestamp": strconv.FormatInt(clock.Now().Unix(), 10),
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("webhook status = %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	status := func() KYCStatusResponse {
+		t.Helper()
+		rec := serve("GET", "/api/v1/kyc/status", "", map[string]string{"Authorization": "Bearer " + token})
+		var resp KYCStatusResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+		}
+		return resp
+	}
+	start := func() KYCStartResponse {
+		t.Helper()
+		rec := serve("POST", "/api/v1/kyc/start", `{}`, map[string]string{"Authorization": "Bearer " + token})
+		var resp KYCStartResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("start = %d: %s", rec.Code, rec.Body.String())
+		}
+		return resp
+	}
+
+	// While the first session is pending, starting again returns it
+	if resp := start(); resp.SessionID != "first" || created != 0 {
+		t.Fatalf("pending session: started %q with %d Didit sessions, want first", resp.SessionID, created)
+	}
+
+	webhook(`{"session_id":"first","status":"Declined","vendor_data":"` + user + `","decision":{"status":"Declined",
+		"id_verification":{"status":"Declined","warnings":[{"risk":"DOCUMENT_EXPIRED","short_description":"Document has expired"}]}}}`)
+
+	resp := status()
+	if resp.Status != database.KYCStatusDeclined || resp.HasKYC {
+		t.Fatalf("after decline: status %q, has KYC %v", resp.Status, resp.HasKYC)
+	}
+	want := &database.KYCFailureReason{Status: database.KYCStatusDeclined, FailedChecks: []string{"id_verification"}, Warnings: []string{"Document has expired"}}
+	if !reflect.DeepEqual(resp.FailureReason, want) {
+		t.Errorf("failure reason = %+v, want %+v", resp.FailureReason, want)
+	}
+	if !reflect.DeepEqual(store.sessions["first"].FailureReason, want) {
+		t.Errorf("stored failure reason = %+v, want %+v", store.sessions["first"].FailureReason, want)
+	}
+
+	// The declined session is terminal, so a fresh one is started
+	retry := start()
+	if retry.SessionID != "retry-1" || created != 1 {
+		t.Fatalf("retry: started %q with %d Didit sessions, want retry-1", retry.SessionID, created)
+	}
+	if resp := status(); resp.Status != "Not Started" || resp.SessionID != "retry-1" || resp.FailureReason != nil {
+		t.Errorf("after retry: status %q, session %q, reason %+v", resp.Status, resp.SessionID, resp.FailureReason)
+	}
+
+	webhook(`{"session_id":"retry-1","status":"Approved","vendor_data":"` + user + `","decision":{"status":"Approved","id_verification":{"status":"Approved"}}}`)
+	resp = status()
+	if resp.Status != database.KYCStatusApproved || !resp.HasKYC || resp.Level != kycLevelBasic || resp.FailureReason != nil {
+		t.Errorf("after approval: %+v", resp)
+	}
+	if rec := serve("POST", "/api/v1/kyc/start", `{}`, map[string]string{"Authorization": "Bearer " + token}); rec.Code != http.StatusConflict {
+		t.Errorf("start after approval = %d, want 409", rec.Code)
+	}
+}
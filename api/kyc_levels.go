@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// KYC levels
+// An approved Didit session proves KYC_L1 (identity document) or, when its
+// decision also approves liveness and proof of address, KYC_L2. Approvals are
+// valid for KYCConfig.ValidityPeriod, after which the user must verify again.
+
+const (
+	kycLevelBasic    = 1 // KYC_L1
+	kycLevelAdvanced = 2 // KYC_L2
+
+	// kycStatusVerificationExpired is reported for an approval past its validity period
+	kycStatusVerificationExpired = "expired"
+)
+
+// KYCConfig configures KYC verification
+type KYCConfig struct {
+	// ValidityPeriod is how long an approval counts before re-verification; 0 never expires
+	ValidityPeriod time.Duration
+}
+
+// DefaultKYCConfig keeps approvals for 12 months
+func DefaultKYCConfig() KYCConfig {
+	return KYCConfig{ValidityPeriod: 365 * 24 * time.Hour}
+}
+
+// kycCredentialType is the credential awarded for an approval at level
+func kycCredentialType(level int) string {
+	if level >= kycLevelAdvanced {
+		return "KYC_L2"
+	}
+	return "KYC_L1"
+}
+
+// diditCheck is one check within a Didit decision
+type diditCheck struct {
+	Status string `json:"status"`
+}
+
+func (c *diditCheck) approved() bool {
+	return c != nil && c.Status == database.KYCStatusApproved
+}
+
+// kycDecisionLevel returns the level an approved session's Didit decision proves
+func kycDecisionLevel(decision *string) int {
+	if decision == nil {
+		return kycLevelBasic
+	}
+	var d struct {
+		Liveness       *diditCheck `json:"liveness"`
+		ProofOfAddress *diditCheck `json:"poa"`
+	}
+	if err := json.Unmarshal([]byte(*decision), &d); err != nil {
+		return kycLevelBasic
+	}
+	if d.Liveness.approved() && d.ProofOfAddress.approved() {
+		return kycLevelAdvanced
+	}
+	return kycLevelBasic
+}
+
+// kycApprovedSince is the oldest approval time that has not expired; the zero
+// time when approvals never expire
+func (s *Server) kycApprovedSince() time.Time {
+	if s.config.KYC.ValidityPeriod <= 0 {
+		return time.Time{}
+	}
+	return s.clock.Now().Add(-s.config.KYC.ValidityPeriod)
+}
+
+// kycApprovalExpiry returns when an approved session's verification expires,
+// or nil if approvals never expire
+func (s *Server) kycApprovalExpiry(session *database.KYCSession) *time.Time {
+	if s.config.KYC.ValidityPeriod <= 0 {
+		return nil
+	}
+	approvedAt := session.UpdatedAt
+	if session.CompletedAt != nil {
+		approvedAt = *session.CompletedAt
+	}
+	expiresAt := approvedAt.Add(s.config.KYC.ValidityPeriod)
+	return &expiresAt
+}
+
+// kycStatus fills in the status reported for a user's latest session
+func (s *Server) kycStatus(resp *KYCStatusResponse, session *database.KYCSession) {
+	resp.Status = session.Status
+	resp.SessionID = session.SessionID
+	resp.CompletedAt = NewTimestampPtr(session.CompletedAt)
+	resp.FailureReason = sessionFailureReason(session)
+	if session.Status != database.KYCStatusApproved {
+		return
+	}
+	if expiresAt := s.kycApprovalExpiry(session); expiresAt != nil {
+		resp.ExpiresAt = NewTimestampPtr(expiresAt)
+		if !s.clock.Now().Before(*expiresAt) {
+			resp.Status = kycStatusVerificationExpired
+		}
+	}
+}
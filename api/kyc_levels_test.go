@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+func TestKYCDecisionLevel(t *testing.T) {
+	for name, tc := range map[string]struct {
+		decision string
+		want     int
+	}{
+		"document only":            {`{"status":"Approved","id_verification":{"status":"Approved"}}`, kycLevelBasic},
+		"liveness without address": {`{"status":"Approved","liveness":{"status":"Approved"}}`, kycLevelBasic},
+		"address declined":         {`{"status":"Approved","liveness":{"status":"Approved"},"poa":{"status":"Declined"}}`, kycLevelBasic},
+		"liveness and address":     {`{"status":"Approved","id_verification":{"status":"Approved"},"liveness":{"status":"Approved"},"poa":{"status":"Approved"}}`, kycLevelAdvanced},
+		"malformed":                {`{"liveness":`, kycLevelBasic},
+	} {
+		decision := tc.decision
+		if got := kycDecisionLevel(&decision); got != tc.want {
+			t.Errorf("%s: level = %d, want %d", name, got, tc.want)
+		}
+	}
+	if got := kycDecisionLevel(nil); got != kycLevelBasic {
+		t.Errorf("no decision: level = %d, want %d", got, kycLevelBasic)
+	}
+	if kycCredentialType(kycLevelBasic) != "KYC_L1" || kycCredentialType(kycLevelAdvanced) != "KYC_L2" {
+		t.Error("levels do not map to KYC_L1 and KYC_L2 credentials")
+	}
+
+	config := &DiditConfig{WorkflowID: "basic"}
+	if got := config.workflowFor(kycLevelAdvanced); got != "basic" {
+		t.Errorf("level 2 workflow without DIDIT_L2_WORKFLOW_ID = %q, want basic", got)
+	}
+	config.L2WorkflowID = "advanced"
+	if config.workflowFor(kycLevelBasic) != "basic" || config.workflowFor(kycLevelAdvanced) != "advanced" {
+		t.Error("levels do not select their workflows")
+	}
+}
+
+func TestKYCWebhookAwardsLevel(t *testing.T) {
+	const secret = "didit-webhook-secret"
+	t.Setenv("DIDIT_WEBHOOK_SECRET", secret)
+
+	clock := newFakeClock(time.Unix(1_700_000_000, 0))
+	config := DefaultConfig()
+	config.Clock = clock
+	s := NewServer(config, zap.NewNop())
+	store := &memoryKYCStore{sessions: map[string]*database.KYCSession{
+		"basic":    {SessionID: "basic", UserAddress: "0xbasic", Status: database.KYCStatusInProgress},
+		"advanced": {SessionID: "advanced", UserAddress: "0xadvanced", Status: database.KYCStatusInProgress},
+	}}
+	s.kyc = store
+
+	send := func(body string) {
+		t.Helper()
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest("POST", "/api/v1/kyc/webhook", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+		req.Header.Set("X-Timestamp", strconv.FormatInt(clock.Now().Unix(), 10))
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("webhook status = %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	send(`{"session_id":"basic","status":"Approved","vendor_data":"0xbasic","decision":{"id_verification":{"status":"Approved"},"liveness":{"status":"Approved"}}}`)
+	send(`{"session_id":"advanced","status":"Approved","vendor_data":"0xadvanced","decision":{"liveness":{"status":"Approved"},"poa":{"status":"Approved"}}}`)
+
+	for user, want := range map[string]struct {
+		session    string
+		level      int
+		credential string
+	}{
+		"0xbasic":    {"basic", kycLevelBasic, "KYC_L1"},
+		"0xadvanced": {"advanced", kycLevelAdvanced, "KYC_L2"},
+	} {
+		creds, _ := store.GetCredentialsByUser(context.Background(), user)
+		if len(creds) != 1 || creds[0].CredentialType != want.credential {
+			t.Errorf("%s credentials = %+v, want one %s", user, creds, want.credential)
+		}
+		if level := store.sessions[want.session].Level; level != want.level {
+			t.Errorf("%s session level = %d, want %d", user, level, want.level)
+		}
+	}
+}
+
+func TestKYCApprovalExpiry(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	config := DefaultConfig()
+	config.Clock = clock
+	s := NewServer(config, zap.NewNop())
+
+	approvedAt := clock.Now().Add(-364 * 24 * time.Hour)
+	session := &database.KYCSession{SessionID: "kyc-1", Status: database.KYCStatusApproved, Level: kycLevelBasic, CompletedAt: &approvedAt}
+
+	var resp KYCStatusResponse
+	s.kycStatus(&resp, session)
+	if resp.Status != database.KYCStatusApproved || resp.ExpiresAt == nil || !resp.ExpiresAt.Time.Equal(approvedAt.Add(365*24*time.Hour)) {
+		t.Fatalf("fresh approval: status %q, expires %v", resp.Status, resp.ExpiresAt)
+	}
+	if since := s.kycApprovedSince(); !approvedAt.After(since) {
+		t.Errorf("approval at %v counted as expired (approved since %v)", approvedAt, since)
+	}
+
+	clock.Advance(24 * time.Hour)
+	resp = KYCStatusResponse{}
+	s.kycStatus(&resp, session)
+	if resp.Status != kycStatusVerificationExpired {
+		t.Errorf("after 12 months: status = %q, want %q", resp.Status, kycStatusVerificationExpired)
+	}
+	if since := s.kycApprovedSince(); approvedAt.After(since) {
+		t.Errorf("approval at %v still counts (approved since %v)", approvedAt, since)
+	}
+
+	// Sessions that are not approved never expire, and a zero period disables expiry
+	pending := &database.KYCSession{SessionID: "kyc-2", Status: database.KYCStatusInProgress}
+	resp = KYCStatusResponse{}
+	if s.kycStatus(&resp, pending); resp.Status != database.KYCStatusInProgress || resp.ExpiresAt != nil {
+		t.Errorf("pending session: status %q, expires %v", resp.Status, resp.ExpiresAt)
+	}
+	s.config.KYC.ValidityPeriod = 0
+	resp = KYCStatusResponse{}
+	if s.kycStatus(&resp, session); resp.Status != database.KYCStatusApproved || !s.kycApprovedSince().IsZero() {
+		t.Errorf("without expiry: status %q, approved since %v", resp.Status, s.kycApprovedSince())
+	}
+}
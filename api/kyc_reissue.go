@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+	"github.com/chaincertify/certd/api/retry"
+)
+
+// kycCredentialIssuer is the issuer recorded on Didit KYC credentials
+const kycCredentialIssuer = "didit.me"
+
+// kycStore persists KYC sessions and the credentials they award; *database.DB implements it
+type kycStore interface {
+	CreateKYCSession(ctx context.Context, session *database.KYCSession) error
+	GetKYCSessionBySessionID(ctx context.Context, sessionID string) (*database.KYCSession, error)
+	GetKYCSessionByUserAddress(ctx context.Context, userAddress string) (*database.KYCSession, error)
+	UpdateKYCSessionStatus(ctx context.Context, sessionID, status string, decisionData *string) error
+	SetKYCSessionLevel(ctx context.Context, sessionID string, level int) error
+	SetKYCSessionFailureReason(ctx context.Context, sessionID string, reason *database.KYCFailureReason) error
+	ApprovedKYCLevel(ctx context.Context, userAddress string, since time.Time) (int, error)
+	GetCredentialsByUser(ctx context.Context, address string) ([]database.Credential, error)
+	AddCredentialIfAbsent(ctx context.Context, credential *database.Credential) (bool, error)
+	RecordKYCWebhookDelivery(ctx context.Context, deliveryID, sessionID, status string, at time.Time) (bool, error)
+	ForgetKYCWebhookDelivery(ctx context.Context, deliveryID string) error
+}
+
+// kycCredentialUID is the credential attestation UID for a Didit session
+func kycCredentialUID(sessionID string) string {
+	return "kyc_didit_" + sessionID
+}
+
+// awardKYCCredential records the level an approved session proved and adds its
+// KYC_L1 or KYC_L2 credential unless the user already holds it, reporting
+// whether the credential was added
+func (s *Server) awardKYCCredential(ctx context.Context, sessionID, userAddress string, level int) (*database.Credential, bool, error) {
+	if err := s.kyc.SetKYCSessionLevel(ctx, sessionID, level); err != nil {
+		return nil, false, err
+	}
+	existing, err := s.kyc.GetCredentialsByUser(ctx, userAddress)
+	if err != nil {
+		return nil, false, err
+	}
+	credentialType := kycCredentialType(level)
+	for i, c := range existing {
+		if c.AttestationUID == kycCredentialUID(sessionID) || c.CredentialType == credentialType {
+			return &existing[i], false, nil
+		}
+	}
+
+	credential := &database.Credential{
+		UserAddress:    userAddress,
+		CredentialType: credentialType,
+		AttestationUID: kycCredentialUID(sessionID),
+		Issuer:         kycCredentialIssuer,
+		Verified:       true,
+		IssuedAt:       s.clock.Now(),
+	}
+	// The store keeps one credential per session even if two deliveries race here
+	added, err := s.kyc.AddCredentialIfAbsent(ctx, credential)
+	if err != nil {
+		return nil, false, err
+	}
+	return credential, added, nil
+}
+
+// fetchDiditDecision asks Didit for the current status of a session and its raw decision
+func fetchDiditDecision(ctx context.Context, config *DiditConfig, sessionID string) (string, *string, error) {
+	endpoint := fmt.Sprintf("%s/v2/session/%s/decision/", strings.TrimRight(config.BaseURL, "/"), url.PathEscape(sessionID))
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := retry.DoHTTP(ctx, diditRetry, client, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-Api-Key", config.APIKey)
+		return req, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxJSONBodyBytes))
+	if err != nil {
+		return "", nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("didit returned status %d", resp.StatusCode)
+	}
+	var decision struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &decision); err != nil {
+		return "", nil, fmt.Errorf("failed to parse didit decision: %w", err)
+	}
+	raw := string(body)
+	return decision.Status, &raw, nil
+}
+
+// storedKYCDecisionStatus returns the status recorded in a session's stored decision, if any
+func storedKYCDecisionStatus(session *database.KYCSession) string {
+	if session.DecisionData == nil {
+		return ""
+	}
+	var decision struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(*session.DecisionData), &decision); err != nil {
+		return ""
+	}
+	return decision.Status
+}
+
+// handleReissueKYCCredential re-runs KYC credential issuance for a session whose
+// webhook failed to award it. Approval is taken from the stored session or
+// decision, falling back to Didit; an existing credential makes it a no-op.
+// POST /api/v1/admin/kyc/{sessionId}/reissue
+func (s *Server) handleReissueKYCCredential(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["sessionId"]
+	admin := getAuthenticatedAddress(r)
+
+	if s.kyc == nil {
+		s.respondError(w, http.StatusServiceUnavailable, "Database not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	session, err := s.kyc.GetKYCSessionBySessionID(ctx, sessionID)
+	if err != nil {
+		s.logger.Error("Failed to get KYC session", zap.Error(err), zap.String("session_id", sessionID))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get session")
+		return
+	}
+	if session == nil {
+		s.respondError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	status, decision := session.Status, session.DecisionData
+	if status != database.KYCStatusApproved && storedKYCDecisionStatus(session) == database.KYCStatusApproved {
+		status = database.KYCStatusApproved
+	}
+	if status != database.KYCStatusApproved {
+		if config := getDiditConfig(); config.APIKey != "" {
+			current, fetched, err := fetchDiditDecision(ctx, config, sessionID)
+			if err != nil {
+				s.logger.Error("Didit decision lookup failed", zap.Error(err), zap.String("session_id", sessionID))
+				s.respondError(w, http.StatusBadGateway, "KYC service unavailable")
+				return
+			}
+			if current != "" && current != session.Status {
+				if err := s.kyc.UpdateKYCSessionStatus(ctx, sessionID, current, fetched); err != nil {
+					s.logger.Error("Failed to update KYC session", zap.Error(err), zap.String("session_id", sessionID))
+				}
+			}
+			status, decision = current, fetched
+		}
+	}
+	if status != database.KYCStatusApproved {
+		s.respondError(w, http.StatusConflict, fmt.Sprintf("KYC session is not approved (status %q)", status))
+		return
+	}
+
+	userAddress := strings.ToLower(session.VendorData)
+	if userAddress == "" {
+		userAddress = strings.ToLower(session.UserAddress)
+	}
+	credential, awarded, err := s.awardKYCCredential(ctx, sessionID, userAddress, kycDecisionLevel(decision))
+	if err != nil {
+		s.logger.Error("Failed to reissue KYC credential", zap.Error(err), zap.String("session_id", sessionID))
+		s.respondError(w, http.StatusInternalServerError, "Failed to award credential")
+		return
+	}
+
+	s.logger.Info("KYC credential reissue",
+		zap.String("admin", admin),
+		zap.String("session_id", sessionID),
+		zap.String("user", userAddress),
+		zap.Bool("awarded", awarded),
+	)
+
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"session_id":   sessionID,
+		"user_address": userAddress,
+		"status":       status,
+		"awarded":      awarded,
+		"credential":   credential,
+	})
+}
@@ -0,0 +1,284 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// memoryKYCStore is an in-memory kycStore
+type memoryKYCStore struct {
+	mu          sync.Mutex
+	sessions    map[string]*database.KYCSession
+	credentials []database.Credential
+	deliveries  map[string]bool
+}
+
+func (m *memoryKYCStore) CreateKYCSession(_ context.Context, session *database.KYCSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session.ID = fmt.Sprintf("kyc-%d", len(m.sessions)+1)
+	session.CreatedAt = time.Now()
+	session.UpdatedAt = session.CreatedAt
+	copied := *session
+	m.sessions[session.SessionID] = &copied
+	return nil
+}
+
+func (m *memoryKYCStore) GetKYCSessionByUserAddress(_ context.Context, userAddress string) (*database.KYCSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var latest *database.KYCSession
+	for _, s := range m.sessions {
+		if s.UserAddress == userAddress && (latest == nil || s.CreatedAt.After(latest.CreatedAt)) {
+			latest = s
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	copied := *latest
+	return &copied, nil
+}
+
+func (m *memoryKYCStore) SetKYCSessionFailureReason(_ context.Context, sessionID string, reason *database.KYCFailureReason) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("no session %s", sessionID)
+	}
+	s.FailureReason = reason
+	return nil
+}
+
+func (m *memoryKYCStore) ApprovedKYCLevel(_ context.Context, userAddress string, since time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	level := 0
+	for _, s := range m.sessions {
+		if s.UserAddress != userAddress || s.Status != database.KYCStatusApproved || s.CompletedAt == nil || !s.CompletedAt.After(since) {
+			continue
+		}
+		level = max(level, max(s.Level, 1))
+	}
+	return level, nil
+}
+
+func (m *memoryKYCStore) GetKYCSessionBySessionID(_ context.Context, sessionID string) (*database.KYCSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.sessions[sessionID]; ok {
+		copied := *s
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *memoryKYCStore) UpdateKYCSessionStatus(_ context.Context, sessionID, status string, decisionData *string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("no session %s", sessionID)
+	}
+	if s.Status == database.KYCStatusApproved && status != database.KYCStatusApproved {
+		return sql.ErrNoRows
+	}
+	s.Status, s.DecisionData = status, decisionData
+	if status == database.KYCStatusApproved || status == database.KYCStatusDeclined {
+		now := time.Now()
+		s.CompletedAt = &now
+	}
+	return nil
+}
+
+func (m *memoryKYCStore) SetKYCSessionLevel(_ context.Context, sessionID string, level int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("no session %s", sessionID)
+	}
+	s.Level = level
+	return nil
+}
+
+func (m *memoryKYCStore) GetCredentialsByUser(_ context.Context, address string) ([]database.Credential, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []database.Credential
+	for _, c := range m.credentials {
+		if c.UserAddress == address {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryKYCStore) AddCredentialIfAbsent(_ context.Context, credential *database.Credential) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.credentials {
+		if c.UserAddress == credential.UserAddress && c.CredentialType == credential.CredentialType && c.AttestationUID == credential.AttestationUID {
+			*credential = c
+			return false, nil
+		}
+	}
+	credential.ID = fmt.Sprintf("cred-%d", len(m.credentials)+1)
+	m.credentials = append(m.credentials, *credential)
+	return true, nil
+}
+
+func (m *memoryKYCStore) RecordKYCWebhookDelivery(_ context.Context, deliveryID, _, _ string, _ time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.deliveries[deliveryID] {
+		return false, nil
+	}
+	if m.deliveries == nil {
+		m.deliveries = map[string]bool{}
+	}
+	m.deliveries[deliveryID] = true
+	return true, nil
+}
+
+func (m *memoryKYCStore) ForgetKYCWebhookDelivery(_ context.Context, deliveryID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.deliveries, deliveryID)
+	return nil
+}
+
+func TestReissueKYCCredential(t *testing.T) {
+	const (
+		admin = "cert1admin"
+		user  = "0xabcdef0123456789abcdef0123456789abcdef01"
+	)
+	decided := `{"status":"Approved","id_verification":{"status":"Approved"}}`
+	store := &memoryKYCStore{sessions: map[string]*database.KYCSession{
+		"approved":     {SessionID: "approved", UserAddress: user, VendorData: user, Status: database.KYCStatusApproved},
+		"decided":      {SessionID: "decided", UserAddress: user, VendorData: user, Status: database.KYCStatusInReview, DecisionData: &decided},
+		"pending":      {SessionID: "pending", UserAddress: "0xpending", VendorData: "0xPENDING", Status: database.KYCStatusInProgress},
+		"declined":     {SessionID: "declined", UserAddress: "0xdeclined", VendorData: "0xdeclined", Status: database.KYCStatusInProgress},
+		"not-approved": {SessionID: "not-approved", UserAddress: "0xother", VendorData: "0xother", Status: database.KYCStatusDeclined},
+	}}
+
+	var diditCalls []string
+	didit := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		diditCalls = append(diditCalls, r.URL.Path)
+		if r.Header.Get("X-Api-Key") != "didit-key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/session/pending/decision/":
+			fmt.Fprint(w, `{"session_id":"pending","status":"Approved"}`)
+		default:
+			fmt.Fprint(w, `{"status":"Declined"}`)
+		}
+	}))
+	defer didit.Close()
+	t.Setenv("DIDIT_API_KEY", "didit-key")
+	t.Setenv("DIDIT_BASE_URL", didit.URL)
+
+	config := DefaultConfig()
+	config.Clock = newFakeClock(time.Unix(1_700_000_000, 0))
+	config.AdminAddresses = []string{admin}
+	s := NewServer(config, zap.NewNop())
+	s.kyc = store
+
+	reissue := func(sessionID, caller string) (int, map[string]any) {
+		req := httptest.NewRequest("POST", "/api/v1/admin/kyc/"+sessionID+"/reissue", nil)
+		if caller != "" {
+			req.Header.Set("Authorization", "Bearer "+testAuthToken(t, config.JWTSecret, caller))
+		}
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+	credentialsOf := func(address string) []database.Credential {
+		creds, _ := store.GetCredentialsByUser(context.Background(), address)
+		return creds
+	}
+
+	t.Run("requires admin auth", func(t *testing.T) {
+		if code, _ := reissue("approved", ""); code != http.StatusUnauthorized {
+			t.Errorf("anonymous status = %d, want 401", code)
+		}
+		if code, _ := reissue("approved", user); code != http.StatusForbidden {
+			t.Errorf("non-admin status = %d, want 403", code)
+		}
+		if n := len(credentialsOf(user)); n != 0 {
+			t.Fatalf("credentials after rejected calls = %d", n)
+		}
+	})
+
+	t.Run("awards a missing credential", func(t *testing.T) {
+		code, out := reissue("approved", admin)
+		if code != http.StatusOK || out["awarded"] != true {
+			t.Fatalf("status = %d, body = %v", code, out)
+		}
+		creds := credentialsOf(user)
+		if len(creds) != 1 || creds[0].CredentialType != kycCredentialType(kycLevelBasic) ||
+			creds[0].AttestationUID != "kyc_didit_approved" || creds[0].Issuer != kycCredentialIssuer || !creds[0].Verified {
+			t.Fatalf("credentials = %+v", creds)
+		}
+	})
+
+	t.Run("is a no-op when the credential exists", func(t *testing.T) {
+		for _, session := range []string{"approved", "decided"} {
+			code, out := reissue(session, admin)
+			if code != http.StatusOK || out["awarded"] != false {
+				t.Errorf("%s: status = %d, body = %v", session, code, out)
+			}
+		}
+		if n := len(credentialsOf(user)); n != 1 {
+			t.Errorf("credentials = %d, want 1", n)
+		}
+		if len(diditCalls) != 0 {
+			t.Errorf("stored approvals queried Didit: %v", diditCalls)
+		}
+	})
+
+	t.Run("re-queries Didit for undecided sessions", func(t *testing.T) {
+		code, out := reissue("pending", admin)
+		if code != http.StatusOK || out["awarded"] != true {
+			t.Fatalf("status = %d, body = %v", code, out)
+		}
+		if n := len(credentialsOf("0xpending")); n != 1 {
+			t.Errorf("credentials = %d, want 1", n)
+		}
+		if session, _ := store.GetKYCSessionBySessionID(context.Background(), "pending"); session.Status != database.KYCStatusApproved {
+			t.Errorf("session status = %q, want refreshed from Didit", session.Status)
+		}
+
+		if code, _ := reissue("declined", admin); code != http.StatusConflict {
+			t.Errorf("declined status = %d, want 409", code)
+		}
+		if n := len(credentialsOf("0xdeclined")); n != 0 {
+			t.Errorf("declined credentials = %d, want 0", n)
+		}
+	})
+
+	t.Run("rejects unapproved and unknown sessions", func(t *testing.T) {
+		if code, _ := reissue("not-approved", admin); code != http.StatusConflict {
+			t.Errorf("not-approved status = %d, want 409", code)
+		}
+		if code, _ := reissue("missing", admin); code != http.StatusNotFound {
+			t.Errorf("missing status = %d, want 404", code)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+func TestKYCWebhookIdempotent(t *testing.T) {
+	const secret = "didit-webhook-secret"
+	user := oauthTestAddress
+	t.Setenv("DIDIT_WEBHOOK_SECRET", secret)
+
+	clock := newFakeClock(time.Unix(1_700_000_000, 0))
+	config := DefaultConfig()
+	config.Clock = clock
+	s := NewServer(config, zap.NewNop())
+	store := &memoryKYCStore{sessions: map[string]*database.KYCSession{
+		"s1": {SessionID: "s1", UserAddress: user, VendorData: user, Status: database.KYCStatusInProgress},
+	}}
+	s.kyc = store
+
+	webhook := func(body string) string {
+		t.Helper()
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest("POST", "/api/v1/kyc/webhook", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+		req.Header.Set("X-Timestamp", strconv.FormatInt(clock.Now().Unix(), 10))
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("webhook status = %d: %s", rec.Code, rec.Body.String())
+		}
+		return rec.Body.String()
+	}
+	credentials := func() []database.Credential {
+		creds, _ := store.GetCredentialsByUser(context.Background(), user)
+		return creds
+	}
+
+	approved := `{"session_id":"s1","status":"Approved","vendor_data":"` + user + `","decision":{"status":"Approved","id_verification":{"status":"Approved"}}}`
+	webhook(approved)
+	if body := webhook(approved); !strings.Contains(body, "already processed") {
+		t.Errorf("redelivery response = %s, want it reported as already processed", body)
+	}
+	if creds := credentials(); len(creds) != 1 || creds[0].AttestationUID != kycCredentialUID("s1") {
+		t.Fatalf("credentials after redelivery = %+v, want one", creds)
+	}
+
+	// A distinct delivery of the same approval still adds nothing
+	webhook(`{"session_id":"s1","status":"Approved","webhook_type":"status.updated","vendor_data":"` + user + `","decision":{"status":"Approved","id_verification":{"status":"Approved"}}}`)
+	if n := len(credentials()); n != 1 {
+		t.Errorf("credentials after second approval = %d, want 1", n)
+	}
+
+	// An earlier status arriving late does not undo the approval
+	webhook(`{"session_id":"s1","status":"In Progress","vendor_data":"` + user + `"}`)
+	if status := store.sessions["s1"].Status; status != database.KYCStatusApproved {
+		t.Errorf("session status after stale delivery = %q, want %q", status, database.KYCStatusApproved)
+	}
+	if n := len(credentials()); n != 1 {
+		t.Errorf("credentials after stale delivery = %d, want 1", n)
+	}
+}
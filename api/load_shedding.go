@@ -0,0 +1,153 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LoadSheddingConfig controls when non-critical requests are rejected under load
+type LoadSheddingConfig struct {
+	MaxInFlight   int           // Concurrent requests before shedding (0 disables)
+	MaxP99Latency time.Duration // p99 latency before shedding (0 disables)
+	LatencyWindow time.Duration // Only samples this recent count toward p99
+	RetryAfter    time.Duration // Value advertised in the Retry-After header
+	CriticalPaths []string      // Paths that are never shed
+}
+
+// DefaultLoadSheddingConfig returns the default load shedding thresholds
+func DefaultLoadSheddingConfig() LoadSheddingConfig {
+	return LoadSheddingConfig{
+		MaxInFlight:   512,
+		MaxP99Latency: 5 * time.Second,
+		LatencyWindow: 30 * time.Second,
+		RetryAfter:    5 * time.Second,
+		CriticalPaths: []string{"/api/v1/health"},
+	}
+}
+
+const (
+	loadShedderSamples  = 512                    // Latency samples retained
+	loadShedderCacheTTL = 250 * time.Millisecond // How long a computed p99 is reused
+)
+
+type latencySample struct {
+	at       time.Time
+	duration time.Duration
+}
+
+// loadShedder tracks in-flight requests and recent latency
+type loadShedder struct {
+	config   LoadSheddingConfig
+	critical map[string]bool
+	inFlight int64
+
+	mu      sync.Mutex
+	samples []latencySample
+	next    int
+	p99     time.Duration
+	p99At   time.Time
+	now     func() time.Time
+}
+
+// newLoadShedder creates a shedder for the given thresholds
+func newLoadShedder(config LoadSheddingConfig) *loadShedder {
+	critical := make(map[string]bool, len(config.CriticalPaths))
+	for _, p := range config.CriticalPaths {
+		critical[p] = true
+	}
+	return &loadShedder{
+		config:   config,
+		critical: critical,
+		samples:  make([]latencySample, 0, loadShedderSamples),
+		now:      time.Now,
+	}
+}
+
+// Observe records the latency of a completed request
+func (l *loadShedder) Observe(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sample := latencySample{at: l.now(), duration: d}
+	if len(l.samples) < loadShedderSamples {
+		l.samples = append(l.samples, sample)
+	} else {
+		l.samples[l.next] = sample
+	}
+	l.next = (l.next + 1) % loadShedderSamples
+}
+
+// P99 returns the p99 latency over samples inside the latency window
+func (l *loadShedder) P99() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.p99At.IsZero() && now.Sub(l.p99At) < loadShedderCacheTTL {
+		return l.p99
+	}
+
+	recent := make([]time.Duration, 0, len(l.samples))
+	for _, s := range l.samples {
+		if l.config.LatencyWindow <= 0 || now.Sub(s.at) <= l.config.LatencyWindow {
+			recent = append(recent, s.duration)
+		}
+	}
+
+	l.p99 = 0
+	if len(recent) > 0 {
+		sort.Slice(recent, func(i, j int) bool { return recent[i] < recent[j] })
+		l.p99 = recent[(len(recent)*99-1)/100]
+	}
+	l.p99At = now
+	return l.p99
+}
+
+// InFlight returns the number of requests currently being served
+func (l *loadShedder) InFlight() int {
+	return int(atomic.LoadInt64(&l.inFlight))
+}
+
+// overloaded reports whether new non-critical requests should be rejected
+func (l *loadShedder) overloaded() bool {
+	if l.config.MaxInFlight > 0 && l.InFlight() >= l.config.MaxInFlight {
+		return true
+	}
+	if l.config.MaxP99Latency > 0 && l.P99() > l.config.MaxP99Latency {
+		return true
+	}
+	return false
+}
+
+// loadSheddingMiddleware rejects non-critical requests with 503 while the server is overloaded
+func (s *Server) loadSheddingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		shedder := s.loadShedder
+		if !shedder.critical[r.URL.Path] && shedder.overloaded() {
+			retryAfter := int(shedder.config.RetryAfter.Seconds())
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			s.respondJSON(w, http.StatusServiceUnavailable, ErrorResponse{
+				Error:   "overloaded",
+				Code:    http.StatusServiceUnavailable,
+				Message: "server is overloaded, retry later",
+			})
+			return
+		}
+
+		atomic.AddInt64(&shedder.inFlight, 1)
+		start := time.Now()
+		defer func() {
+			atomic.AddInt64(&shedder.inFlight, -1)
+			shedder.Observe(time.Since(start))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
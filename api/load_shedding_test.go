@@ -0,0 +1,121 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TestLoadSheddingInFlight tests that saturating in-flight capacity sheds non-critical requests
+func TestLoadSheddingInFlight(t *testing.T) {
+	config := DefaultConfig()
+	config.LoadShedding.MaxInFlight = 1
+	config.LoadShedding.RetryAfter = 7 * time.Second
+	server := NewServer(config, zap.NewNop())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	slow := server.loadSheddingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Occupy the only in-flight slot with a slow request
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		slow.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/explorer/stats", nil))
+	}()
+	<-started
+
+	// Non-critical request is shed
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/explorer/transactions", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "7" {
+		t.Errorf("Retry-After = %q, want 7", got)
+	}
+
+	// Health endpoint stays responsive
+	rec = httptest.NewRecorder()
+	server.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("health status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	close(release)
+	<-done
+
+	if server.loadShedder.InFlight() != 0 {
+		t.Errorf("in-flight = %d after completion, want 0", server.loadShedder.InFlight())
+	}
+	rec = httptest.NewRecorder()
+	server.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status after recovery = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+// TestLoadSheddingLatency tests that high p99 latency sheds until samples age out
+func TestLoadSheddingLatency(t *testing.T) {
+	config := DefaultConfig()
+	config.LoadShedding.MaxP99Latency = 100 * time.Millisecond
+	config.LoadShedding.LatencyWindow = 10 * time.Second
+	server := NewServer(config, zap.NewNop())
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server.loadShedder.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		server.loadShedder.Observe(2 * time.Second)
+	}
+	if p99 := server.loadShedder.P99(); p99 != 2*time.Second {
+		t.Fatalf("p99 = %s, want 2s", p99)
+	}
+
+	ok := server.loadSheddingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	ok.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/attestations/abc", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	rec = httptest.NewRecorder()
+	ok.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("health status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	// Slow samples fall out of the window and shedding stops
+	now = now.Add(11 * time.Second)
+	rec = httptest.NewRecorder()
+	ok.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/attestations/abc", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status after window = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+// TestLoadShedderP99 tests the percentile calculation
+func TestLoadShedderP99(t *testing.T) {
+	l := newLoadShedder(LoadSheddingConfig{})
+	if l.P99() != 0 {
+		t.Errorf("empty p99 = %s, want 0", l.P99())
+	}
+
+	l = newLoadShedder(LoadSheddingConfig{})
+	for i := 1; i <= 100; i++ {
+		l.Observe(time.Duration(i) * time.Millisecond)
+	}
+	if p99 := l.P99(); p99 != 99*time.Millisecond {
+		t.Errorf("p99 = %s, want 99ms", p99)
+	}
+}
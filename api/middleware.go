@@ -2,14 +2,19 @@ package api
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"math"
+	"mime"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
 
@@ -17,10 +22,18 @@ import (
 type contextKey string
 
 const (
-	UserAddressKey contextKey = "user_address"
-	APIKeyInfoKey  contextKey = "api_key_info"
+	UserAddressKey  contextKey = "user_address"
+	APIKeyInfoKey   contextKey = "api_key_info"
+	APIKeyScopesKey contextKey = "api_key_scopes"
+	RequestIDKey    contextKey = "request_id"
 )
 
+// requestIDHeader carries the correlation ID on requests and responses
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds caller-supplied correlation IDs
+const maxRequestIDLength = 64
+
 // loggingMiddleware logs all incoming requests
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -32,6 +45,7 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(wrapped, r)
 
 		s.logger.Info("Request",
+			zap.String("request_id", getRequestID(r)),
 			zap.String("method", r.Method),
 			zap.String("path", r.URL.Path),
 			zap.Int("status", wrapped.statusCode),
@@ -44,26 +58,120 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 // responseWriter wraps http.ResponseWriter to capture status code
 type responseWriter struct {
 	http.ResponseWriter
-	statusCode int
+	statusCode  int
+	wroteHeader bool
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
+	rw.wroteHeader = true
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// recoveryMiddleware recovers from panics
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// getRequestID returns the correlation ID assigned by recoveryMiddleware
+func getRequestID(r *http.Request) string {
+	if id, ok := r.Context().Value(RequestIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// requestIDFor keeps a well-formed caller-supplied correlation ID or generates one
+func requestIDFor(r *http.Request) string {
+	if id := r.Header.Get(requestIDHeader); id != "" && len(id) <= maxRequestIDLength && isPrintableASCII(id) {
+		return id
+	}
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x21 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// recoveryMiddleware is the outermost middleware. It assigns each request a
+// correlation ID and turns a handler panic into a generic 500, logging the
+// panic and stack server-side only.
 func (s *Server) recoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := requestIDFor(r)
+		w.Header().Set(requestIDHeader, requestID)
+		r = r.WithContext(context.WithValue(r.Context(), RequestIDKey, requestID))
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
 		defer func() {
-			if err := recover(); err != nil {
-				s.logger.Error("Panic recovered",
-					zap.Any("error", err),
-					zap.String("stack", string(debug.Stack())),
-				)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			err := recover()
+			if err == nil {
+				return
+			}
+			if err == http.ErrAbortHandler {
+				// Deliberate abort; let net/http drop the connection quietly
+				panic(err)
+			}
+			s.logger.Error("Panic recovered",
+				zap.String("request_id", requestID),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Any("error", err),
+				zap.String("stack", string(debug.Stack())),
+			)
+			if wrapped.wroteHeader {
+				// The response is already under way; nothing clean can be sent
+				return
 			}
+			s.respondJSON(w, http.StatusInternalServerError, ErrorResponse{
+				Error:     http.StatusText(http.StatusInternalServerError),
+				Code:      http.StatusInternalServerError,
+				Message:   "An unexpected error occurred",
+				RequestID: requestID,
+			})
 		}()
+		next.ServeHTTP(wrapped, r)
+	})
+}
+
+// bodyMediaTypes lists routes whose request bodies are not JSON, keyed by
+// route template. Every other route must send bodies as application/json.
+var bodyMediaTypes = map[string]string{
+	"/api/v1/encrypted-attestations/upload": "multipart/form-data",
+	"/api/v1/attestations/bulk-upload":      "multipart/form-data",
+}
+
+// contentTypeMiddleware answers 415 when a request carries a body in a media
+// type its route does not accept. Parameters such as charset are ignored.
+func (s *Server) contentTypeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		want := "application/json"
+		if route := mux.CurrentRoute(r); route != nil {
+			if tmpl, err := route.GetPathTemplate(); err == nil && bodyMediaTypes[tmpl] != "" {
+				want = bodyMediaTypes[tmpl]
+			}
+		}
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != want {
+			s.respondError(w, http.StatusUnsupportedMediaType, "Content-Type must be "+want)
+			return
+		}
 		next.ServeHTTP(w, r)
 	})
 }
@@ -80,6 +188,11 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 
 		// Extract Bearer token
 		parts := strings.Split(authHeader, " ")
+		if len(parts) == 2 && parts[0] == serviceAuthScheme {
+			// Service tokens are only valid on internal endpoints
+			http.Error(w, "Service tokens are not accepted on user endpoints", http.StatusForbidden)
+			return
+		}
 		if len(parts) != 2 || parts[0] != "Bearer" {
 			http.Error(w, "Invalid authorization format", http.StatusUnauthorized)
 			return
@@ -126,6 +239,34 @@ func (s *Server) requireAuth(handler http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// requireAdmin wraps a handler with authentication restricted to Config.AdminAddresses
+func (s *Server) requireAdmin(handler http.HandlerFunc) http.HandlerFunc {
+	return s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(getAuthenticatedAddress(r)) {
+			s.logger.Warn("Rejected admin request",
+				zap.String("path", r.URL.Path),
+				zap.String("address", getAuthenticatedAddress(r)),
+			)
+			s.respondError(w, http.StatusForbidden, "Admin access required")
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// isAdmin reports whether address is one of the configured admin addresses
+func (s *Server) isAdmin(address string) bool {
+	if address == "" {
+		return false
+	}
+	for _, admin := range s.config.AdminAddresses {
+		if strings.EqualFold(strings.TrimSpace(admin), address) {
+			return true
+		}
+	}
+	return false
+}
+
 // getAuthenticatedAddress extracts the authenticated user address from context
 func getAuthenticatedAddress(r *http.Request) string {
 	if addr, ok := r.Context().Value(UserAddressKey).(string); ok {
@@ -134,8 +275,8 @@ func getAuthenticatedAddress(r *http.Request) string {
 	return ""
 }
 
-// apiKeyMiddleware validates API keys from X-API-Key header
-// This provides higher rate limits and tracks usage per key
+// apiKeyMiddleware validates API keys from the X-API-Key header, charges the
+// request against the key's weighted rate limit and tracks usage per key
 func (s *Server) apiKeyMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		apiKey := r.Header.Get("X-API-Key")
@@ -147,44 +288,73 @@ func (s *Server) apiKeyMiddleware(next http.Handler) http.Handler {
 
 		// Validate API key format (must start with cert_)
 		if !strings.HasPrefix(apiKey, "cert_") {
-			http.Error(w, "Invalid API key format", http.StatusUnauthorized)
+			s.respondError(w, http.StatusUnauthorized, "Invalid API key format")
 			return
 		}
 
-		// Hash the key to look up in database
-		hash := sha256.Sum256([]byte(apiKey))
-		keyHash := hex.EncodeToString(hash[:])
-
-		if s.db == nil {
+		if s.apiKeys == nil {
 			// No database - can't validate keys
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Validate the key
-		keyInfo, err := s.db.ValidateAPIKey(r.Context(), keyHash)
+		// Hash the key to look up in database
+		hash := sha256.Sum256([]byte(apiKey))
+		keyHash := hex.EncodeToString(hash[:])
+
+		key, err := s.apiKeys.GetAPIKeyByHash(r.Context(), keyHash)
 		if err != nil {
 			s.logger.Error("Failed to validate API key", zap.Error(err))
-			http.Error(w, "Failed to validate API key", http.StatusInternalServerError)
+			s.respondError(w, http.StatusInternalServerError, "Failed to validate API key")
+			return
+		}
+		if key == nil || (key.ExpiresAt != nil && key.ExpiresAt.Before(s.clock.Now())) {
+			s.respondError(w, http.StatusUnauthorized, "Invalid or expired API key")
 			return
 		}
 
-		if keyInfo == nil {
-			http.Error(w, "Invalid or expired API key", http.StatusUnauthorized)
+		// Check and charge the rate limit (writes and expensive endpoints consume more budget)
+		cost := s.requestCost(key.Tier, r)
+		limit, err := s.rateLimiter.Consume(r.Context(), key.ID, cost, key.RateLimitPerDay, key.RateLimitPerMinute)
+		if err != nil {
+			s.logger.Error("Failed to check rate limit", zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to check rate limit")
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(limit.Remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(limit.Reset.Unix(), 10))
+		w.Header().Set("X-RateLimit-Cost", strconv.Itoa(limit.Cost))
+		if !limit.Allowed {
+			retryAfter := int(math.Ceil(limit.RetryAt.Sub(s.clock.Now()).Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(maxInt(retryAfter, 1)))
+			s.respondJSON(w, http.StatusTooManyRequests, map[string]string{
+				"error":        "rate limit exceeded",
+				"tier":         key.Tier,
+				"daily_limit":  strconv.Itoa(key.RateLimitPerDay),
+				"request_cost": strconv.Itoa(cost),
+			})
 			return
 		}
 
-		// Increment usage counter (async to not block request)
+		// Add key info to context; the owner address authorizes the request
+		ctx := context.WithValue(r.Context(), APIKeyInfoKey, key)
+		ctx = context.WithValue(ctx, APIKeyScopesKey, effectiveAPIKeyScopes(key.Tier, key.Scopes))
+		ctx = context.WithValue(ctx, UserAddressKey, key.OwnerAddress)
+
+		startTime := time.Now()
+		rw := &apiKeyResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		// Track usage asynchronously
 		go func() {
-			if err := s.db.IncrementAPIKeyUsage(context.Background(), keyInfo.ID); err != nil {
+			responseTimeMs := int(time.Since(startTime).Milliseconds())
+			if err := s.apiKeys.IncrementAPIUsage(context.Background(), key.ID, rw.statusCode, responseTimeMs); err != nil {
+				s.logger.Error("Failed to increment API usage", zap.Error(err))
+			}
+			if err := s.apiKeys.IncrementAPIKeyUsage(context.Background(), key.ID); err != nil {
 				s.logger.Error("Failed to increment API key usage", zap.Error(err))
 			}
 		}()
-
-		// Add key info to context
-		ctx := context.WithValue(r.Context(), APIKeyInfoKey, keyInfo)
-		// Also set the owner address for authorization
-		ctx = context.WithValue(ctx, UserAddressKey, keyInfo.OwnerAddress)
-		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
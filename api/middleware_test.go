@@ -0,0 +1,171 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	core, logs := observer.New(zapcore.ErrorLevel)
+	s := NewServer(DefaultConfig(), zap.New(core))
+	s.router.HandleFunc("/api/v1/test/panic", func(w http.ResponseWriter, r *http.Request) {
+		var decoded []byte
+		_ = decoded[4] // short-slice indexing, as in a bad decoder
+	})
+	s.router.HandleFunc("/api/v1/test/panic-after-write", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("late failure")
+	})
+	srv := httptest.NewServer(s.router)
+	defer srv.Close()
+
+	get := func(path string, header http.Header) (*http.Response, []byte) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, body
+	}
+
+	t.Run("returns a clean 500", func(t *testing.T) {
+		resp, body := get("/api/v1/test/panic", http.Header{requestIDHeader: {"trace-123"}})
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("status = %d, body = %s", resp.StatusCode, body)
+		}
+		var out ErrorResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			t.Fatalf("body is not a structured error: %s", body)
+		}
+		if out.Code != http.StatusInternalServerError || out.RequestID != "trace-123" {
+			t.Errorf("error response = %+v", out)
+		}
+		if strings.Contains(string(body), "goroutine") || strings.Contains(string(body), "index out of range") {
+			t.Errorf("response leaks panic details: %s", body)
+		}
+		if resp.Header.Get(requestIDHeader) != "trace-123" {
+			t.Errorf("%s = %q", requestIDHeader, resp.Header.Get(requestIDHeader))
+		}
+	})
+
+	t.Run("logs the panic with correlation ID and stack", func(t *testing.T) {
+		entries := logs.FilterMessage("Panic recovered").All()
+		if len(entries) != 1 {
+			t.Fatalf("panic log entries = %d, want 1", len(entries))
+		}
+		fields := entries[0].ContextMap()
+		if fields["request_id"] != "trace-123" || fields["path"] != "/api/v1/test/panic" {
+			t.Errorf("log fields = %v", fields)
+		}
+		if stack, _ := fields["stack"].(string); !strings.Contains(stack, "middleware_test.go") {
+			t.Errorf("stack does not reach the handler: %q", stack)
+		}
+	})
+
+	t.Run("server stays up", func(t *testing.T) {
+		resp, body := get("/api/v1/health", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("health after panic: status = %d, body = %s", resp.StatusCode, body)
+		}
+		if id := resp.Header.Get(requestIDHeader); len(id) != 16 {
+			t.Errorf("generated %s = %q", requestIDHeader, id)
+		}
+	})
+
+	t.Run("keeps a response already under way", func(t *testing.T) {
+		resp, body := get("/api/v1/test/panic-after-write", nil)
+		if resp.StatusCode != http.StatusAccepted || len(body) != 0 {
+			t.Errorf("status = %d, body = %q", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("rejects malformed caller IDs", func(t *testing.T) {
+		resp, _ := get("/api/v1/health", http.Header{requestIDHeader: {strings.Repeat("a", maxRequestIDLength+1)}})
+		if id := resp.Header.Get(requestIDHeader); len(id) != 16 {
+			t.Errorf("%s = %q, want a generated ID", requestIDHeader, id)
+		}
+	})
+}
+
+// newJSONRequest is httptest.NewRequest with a JSON Content-Type
+func newJSONRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestContentTypeMiddleware(t *testing.T) {
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	lock := `{"sender":"cert1contenttype","amount":"5","target_chain_id":1}`
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        int
+	}{
+		{name: "json", contentType: "application/json", body: lock, want: http.StatusOK},
+		{name: "json with charset", contentType: "application/json; charset=utf-8", body: lock, want: http.StatusOK},
+		{name: "missing", body: lock, want: http.StatusUnsupportedMediaType},
+		{name: "wrong", contentType: "text/plain", body: lock, want: http.StatusUnsupportedMediaType},
+		{name: "form", contentType: "application/x-www-form-urlencoded", body: "sender=cert1contenttype", want: http.StatusUnsupportedMediaType},
+		{name: "malformed", contentType: "application/json; charset", body: lock, want: http.StatusUnsupportedMediaType},
+		{name: "no body needs no type", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/bridge/lock", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			s.router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body.String())
+			}
+			if tt.want == http.StatusUnsupportedMediaType {
+				var out ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil || out.Code != http.StatusUnsupportedMediaType {
+					t.Errorf("error response = %s", rec.Body.String())
+				}
+			}
+		})
+	}
+
+	t.Run("upload route takes multipart", func(t *testing.T) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		_ = mw.WriteField("recipient", "cert1contenttype")
+		_ = mw.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/encrypted-attestations/upload", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		if rec.Code == http.StatusUnsupportedMediaType {
+			t.Fatalf("multipart upload rejected: %s", rec.Body.String())
+		}
+
+		req = newJSONRequest(http.MethodPost, "/api/v1/encrypted-attestations/upload", strings.NewReader(`{}`))
+		rec = httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("JSON upload status = %d, want 415", rec.Code)
+		}
+	})
+}
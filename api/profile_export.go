@@ -0,0 +1,232 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	// profileExportMessagePrefix is prepended to the canonical credential JSON before EIP-191 signing
+	profileExportMessagePrefix = "CERT CertID Profile Export\n"
+
+	// profileExportProofType names the EIP-191 secp256k1 proof on exported profiles
+	profileExportProofType = "EthereumPersonalSignature2021"
+)
+
+// ProfileExport is a portable CertID profile credential. The proof is an
+// EIP-191 signature by the platform key over profileExportMessagePrefix and
+// the stable JSON of the whole credential minus proof.signature, so it can be
+// checked offline with VerifyProfileExport.
+type ProfileExport struct {
+	Context           []string             `json:"@context"`
+	Type              []string             `json:"type"`
+	Issuer            string               `json:"issuer"`
+	IssuanceDate      string               `json:"issuanceDate"`
+	CredentialSubject ProfileExportSubject `json:"credentialSubject"`
+	Proof             *ProfileExportProof  `json:"proof,omitempty"`
+}
+
+// ProfileExportSubject is the identity carried by a ProfileExport
+type ProfileExportSubject struct {
+	ID           string                    `json:"id"`
+	Address      string                    `json:"address"`
+	Profile      ProfileExportProfile      `json:"profile"`
+	Badges       []Badge                   `json:"badges"`
+	TrustScore   ProfileExportTrustScore   `json:"trustScore"`
+	Attestations ProfileExportAttestations `json:"attestations"`
+}
+
+// ProfileExportProfile is the public profile section of an export
+type ProfileExportProfile struct {
+	Handle          string `json:"handle"`
+	Name            string `json:"name,omitempty"`
+	Bio             string `json:"bio,omitempty"`
+	AvatarURL       string `json:"avatar_url,omitempty"`
+	IsVerified      bool   `json:"is_verified"`
+	IsInstitutional bool   `json:"is_institutional"`
+	IsKYC           bool   `json:"is_kyc"`
+	CreatedAt       string `json:"created_at,omitempty"`
+}
+
+// ProfileExportTrustScore records the score and the model version that produced it
+type ProfileExportTrustScore struct {
+	Score        int    `json:"score"`
+	ModelVersion string `json:"model_version"`
+}
+
+// ProfileExportAttestations summarizes active on-chain attestations
+type ProfileExportAttestations struct {
+	Received int      `json:"received"`
+	Issued   int      `json:"issued"`
+	Recent   []string `json:"recent,omitempty"`
+}
+
+// ProfileExportProof is the platform signature over a ProfileExport
+type ProfileExportProof struct {
+	Type               string `json:"type"`
+	Created            string `json:"created"`
+	VerificationMethod string `json:"verificationMethod"`
+	Signer             string `json:"signer"`
+	Signature          string `json:"signature,omitempty"`
+}
+
+// profileExportMessage returns the EIP-191 message signed for a credential,
+// computed over stable JSON so field order never affects the signature
+func profileExportMessage(unsigned any) (string, error) {
+	stable, err := stableJSON(unsigned)
+	if err != nil {
+		return "", err
+	}
+	return profileExportMessagePrefix + stable, nil
+}
+
+// signProfileExport fills in export's proof with an EIP-191 signature by key
+func signProfileExport(export *ProfileExport, key *ecdsa.PrivateKey) error {
+	signer := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	export.Proof = &ProfileExportProof{
+		Type:               profileExportProofType,
+		Created:            export.IssuanceDate,
+		VerificationMethod: export.Issuer + "#" + signer,
+		Signer:             signer,
+	}
+	message, err := profileExportMessage(export)
+	if err != nil {
+		return err
+	}
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	if err != nil {
+		return err
+	}
+	sig[64] += 27 // personal_sign V
+	export.Proof.Signature = hexutil.Encode(sig)
+	return nil
+}
+
+// VerifyProfileExport checks that raw is a profile export signed by platform.
+// It needs no network access: the signer is recovered from the signature over
+// the credential and proof options, so changing any field invalidates it.
+func VerifyProfileExport(raw []byte, platform common.Address) error {
+	var credential map[string]any
+	if err := json.Unmarshal(raw, &credential); err != nil {
+		return fmt.Errorf("credential must be valid JSON: %w", err)
+	}
+	proof, _ := credential["proof"].(map[string]any)
+	if proof == nil {
+		return errors.New("missing proof")
+	}
+	if proof["type"] != profileExportProofType {
+		return fmt.Errorf("unsupported proof type %v", proof["type"])
+	}
+	signature, _ := proof["signature"].(string)
+	sig, err := decodeAnySignature(signature)
+	if err != nil {
+		return err
+	}
+	if len(sig) != 65 {
+		return errors.New("signature must be 65 bytes")
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+
+	options := make(map[string]any, len(proof))
+	for k, v := range proof {
+		if k != "signature" {
+			options[k] = v
+		}
+	}
+	credential["proof"] = options
+	message, err := profileExportMessage(credential)
+	if err != nil {
+		return err
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return fmt.Errorf("failed to recover signer: %w", err)
+	}
+
+	recovered := crypto.PubkeyToAddress(*pub)
+	if recovered != platform {
+		return fmt.Errorf("credential signed by %s, not the platform key %s", recovered.Hex(), platform.Hex())
+	}
+	if signer, _ := proof["signer"].(string); !strings.EqualFold(signer, platform.Hex()) {
+		return fmt.Errorf("proof signer %q is not the platform key %s", signer, platform.Hex())
+	}
+	return nil
+}
+
+// buildProfileExport packages an address's identity as an unsigned ProfileExport
+func (s *Server) buildProfileExport(ctx context.Context, address string) ProfileExport {
+	identity := s.buildFullIdentity(ctx, address)
+	did := "did:web:c3rt.org:identity:" + address
+	var createdAt string
+	if identity.CreatedAt != nil {
+		createdAt = identity.CreatedAt.String()
+	}
+	return ProfileExport{
+		Context:      []string{"https://www.w3.org/2018/credentials/v1"},
+		Type:         []string{"VerifiableCredential", "CertIDProfileCredential"},
+		Issuer:       "did:web:c3rt.org",
+		IssuanceDate: s.clock.Now().UTC().Format(time.RFC3339),
+		CredentialSubject: ProfileExportSubject{
+			ID:      did,
+			Address: address,
+			Profile: ProfileExportProfile{
+				Handle:          identity.Handle,
+				Name:            identity.Name,
+				Bio:             identity.Bio,
+				AvatarURL:       identity.AvatarURL,
+				IsVerified:      identity.IsVerified,
+				IsInstitutional: identity.IsInstitutional,
+				IsKYC:           identity.IsKYC,
+				CreatedAt:       createdAt,
+			},
+			Badges: identity.Badges,
+			TrustScore: ProfileExportTrustScore{
+				Score:        identity.TrustScore,
+				ModelVersion: s.trustScore.ModelVersion(),
+			},
+			Attestations: ProfileExportAttestations{
+				Received: identity.AttestationsReceived,
+				Issued:   identity.AttestationsIssued,
+				Recent:   identity.RecentAttestations,
+			},
+		},
+	}
+}
+
+// handleExportProfile returns the identity as a platform-signed, portable credential
+// GET /api/v1/identity/{address}/export
+func (s *Server) handleExportProfile(w http.ResponseWriter, r *http.Request) {
+	address := strings.ToLower(mux.Vars(r)["address"])
+	if s.config.ProfileSigningKey == nil {
+		s.respondError(w, http.StatusServiceUnavailable, "Profile export signing is not configured")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	export := s.buildProfileExport(ctx, address)
+	if err := signProfileExport(&export, s.config.ProfileSigningKey); err != nil {
+		s.logger.Error("Failed to sign profile export", zap.Error(err), zap.String("address", address))
+		s.respondError(w, http.StatusInternalServerError, "Failed to sign profile export")
+		return
+	}
+
+	w.Header().Set("Content-Disposition", "attachment; filename=certid-"+address+".json")
+	s.respondJSON(w, http.StatusOK, export)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+// tamperedCopies returns one copy of v per leaf value, with that leaf changed
+func tamperedCopies(v any, path string) map[string]any {
+	out := map[string]any{}
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			for p, tampered := range tamperedCopies(child, path+"."+k) {
+				cp := make(map[string]any, len(t))
+				for k2, v2 := range t {
+					cp[k2] = v2
+				}
+				cp[k] = tampered
+				out[p] = cp
+			}
+		}
+	case []any:
+		for i, child := range t {
+			for p, tampered := range tamperedCopies(child, fmt.Sprintf("%s[%d]", path, i)) {
+				cp := append([]any(nil), t...)
+				cp[i] = tampered
+				out[p] = cp
+			}
+		}
+		out[path+"+"] = append(append([]any(nil), t...), "injected")
+	case string:
+		out[path] = t + "x"
+	case float64:
+		out[path] = t + 1
+	case bool:
+		out[path] = !t
+	}
+	return out
+}
+
+func TestProfileExport(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	platform := crypto.PubkeyToAddress(key.PublicKey)
+
+	received := `{"attestations":[
+		{"uid":"r-1","time":"2026-01-01T00:00:00Z"},
+		{"uid":"r-2","time":"2026-01-02T00:00:00Z"}
+	]}`
+	chain := &mockChainClient{query: func(out any, _ int64, args ...string) error {
+		if containsArgs(args, "attestation", "by-recipient", testBalanceAddr) {
+			return json.Unmarshal([]byte(received), out)
+		}
+		return json.Unmarshal([]byte(`{"attestations":[{"uid":"i-1","time":"2026-01-01T00:00:00Z"}]}`), out)
+	}}
+	config := DefaultConfig()
+	config.Chain = chain
+	config.Clock = newFakeClock(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	config.ProfileSigningKey = key
+	s := NewServer(config, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/identity/"+testBalanceAddr+"/export", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	raw := rec.Body.Bytes()
+
+	t.Run("bundles the identity", func(t *testing.T) {
+		var export ProfileExport
+		if err := json.Unmarshal(raw, &export); err != nil {
+			t.Fatal(err)
+		}
+		subject := export.CredentialSubject
+		if subject.Address != testBalanceAddr || subject.Attestations.Received != 2 || subject.Attestations.Issued != 1 {
+			t.Errorf("subject = %+v", subject)
+		}
+		if subject.TrustScore.ModelVersion != trustScoreModelVersion {
+			t.Errorf("model version = %q, want %q", subject.TrustScore.ModelVersion, trustScoreModelVersion)
+		}
+		if export.Proof == nil || export.Proof.Signer != platform.Hex() || export.Proof.Created != "2026-03-01T00:00:00Z" {
+			t.Errorf("proof = %+v", export.Proof)
+		}
+	})
+
+	t.Run("signature verifies offline", func(t *testing.T) {
+		if err := VerifyProfileExport(raw, platform); err != nil {
+			t.Fatalf("VerifyProfileExport: %v", err)
+		}
+		other, _ := crypto.GenerateKey()
+		if err := VerifyProfileExport(raw, crypto.PubkeyToAddress(other.PublicKey)); err == nil {
+			t.Error("export verified against another platform key")
+		}
+	})
+
+	t.Run("tampering with any field invalidates it", func(t *testing.T) {
+		var credential map[string]any
+		if err := json.Unmarshal(raw, &credential); err != nil {
+			t.Fatal(err)
+		}
+		copies := tamperedCopies(credential, "")
+		if len(copies) < 15 {
+			t.Fatalf("only %d tampered copies", len(copies))
+		}
+		for path, tampered := range copies {
+			body, _ := json.Marshal(tampered)
+			if err := VerifyProfileExport(body, platform); err == nil {
+				t.Errorf("tampered %s still verifies", path)
+			}
+		}
+
+		// Adding a field the platform never signed is tampering too
+		subject := credential["credentialSubject"].(map[string]any)
+		subject["badges"] = []any{map[string]any{"id": "KYC_L2", "name": "KYC Level 2", "icon": "kyc"}}
+		body, _ := json.Marshal(credential)
+		if err := VerifyProfileExport(body, platform); err == nil {
+			t.Error("injected badge still verifies")
+		}
+	})
+
+	t.Run("requires a signing key", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Chain = chain
+		s := NewServer(config, zap.NewNop())
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/identity/"+testBalanceAddr+"/export", nil))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("status = %d, want 503", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// Weighted rate limiting for API keys
+// Cheap reads and expensive writes draw from the same daily budget, but writes
+// (and a handful of particularly heavy endpoints) cost more per call.
+
+// EndpointWeights defines how much of the daily budget a request consumes
+type EndpointWeights struct {
+	Read      int `json:"read"`      // GET/HEAD/OPTIONS requests
+	Write     int `json:"write"`     // POST/PUT/PATCH/DELETE requests
+	Expensive int `json:"expensive"` // Routes listed in expensiveRoutes
+}
+
+// DefaultRateLimitWeights returns the per-tier endpoint weights
+func DefaultRateLimitWeights() map[string]EndpointWeights {
+	return map[string]EndpointWeights{
+		"free":       {Read: 1, Write: 5, Expensive: 10},
+		"developer":  {Read: 1, Write: 3, Expensive: 5},
+		"enterprise": {Read: 1, Write: 2, Expensive: 3},
+	}
+}
+
+// expensiveRoute identifies an endpoint that triggers on-chain txs or paid third-party calls
+type expensiveRoute struct {
+	Method string
+	Path   string
+}
+
+// expensiveRoutes are charged the tier's Expensive weight instead of Write
+var expensiveRoutes = []expensiveRoute{
+	{Method: http.MethodPost, Path: "/api/v1/attestations"},
+	{Method: http.MethodPost, Path: "/api/v1/encrypted-attestations"},
+	{Method: http.MethodPost, Path: "/api/v1/encrypted-attestations/upload"},
+	{Method: http.MethodPost, Path: "/api/v1/schemas"},
+	{Method: http.MethodPost, Path: "/api/v1/kyc/start"},
+}
+
+// requestCost returns the budget cost of a request for the given tier
+func (s *Server) requestCost(tier string, r *http.Request) int {
+	weights, ok := s.config.RateLimitWeights[tier]
+	if !ok {
+		weights, ok = s.config.RateLimitWeights["free"]
+		if !ok {
+			return 1
+		}
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	for _, route := range expensiveRoutes {
+		if r.Method == route.Method && path == route.Path {
+			return maxInt(weights.Expensive, 1)
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return maxInt(weights.Read, 1)
+	default:
+		return maxInt(weights.Write, 1)
+	}
+}
+
+// rateLimitHeaders are set by apiKeyMiddleware and exposed to browser clients
+var rateLimitHeaders = []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-RateLimit-Cost", "Retry-After"}
+
+// rateLimitResult describes the outcome of a budget check
+type rateLimitResult struct {
+	Allowed   bool
+	Cost      int
+	Limit     int       // Daily weighted budget
+	Remaining int       // Weighted budget left in the current day
+	Reset     time.Time // When the daily budget resets
+	RetryAt   time.Time // When a rejected request may be retried; zero if allowed
+}
+
+// rateLimitRetryAt returns when a request rejected with used of dailyLimit spent
+// may be retried: at the next day if the daily budget refused it, otherwise at
+// the next minute
+func rateLimitRetryAt(dayStart, minuteStart time.Time, used, cost, dailyLimit int) time.Time {
+	if dailyLimit > 0 && used+cost > dailyLimit {
+		return dayStart.Add(24 * time.Hour)
+	}
+	return minuteStart.Add(time.Minute)
+}
+
+// apiRateLimiter charges requests against API key budgets. Consume must check
+// and charge in one step, so that concurrent requests cannot all pass the check
+// before any of them is counted.
+type apiRateLimiter interface {
+	Consume(ctx context.Context, keyID string, cost, dailyLimit, minuteLimit int) (rateLimitResult, error)
+}
+
+// dbRateLimiter keeps budgets in PostgreSQL, shared by every API replica
+type dbRateLimiter struct {
+	db    *database.DB
+	clock Clock
+}
+
+func (l dbRateLimiter) Consume(ctx context.Context, keyID string, cost, dailyLimit, minuteLimit int) (rateLimitResult, error) {
+	now := l.clock.Now().UTC()
+	dayStart, minuteStart := now.Truncate(24*time.Hour), now.Truncate(time.Minute)
+	allowed, used, err := l.db.ConsumeAPIRateLimit(ctx, keyID, dayStart, minuteStart, cost, dailyLimit, minuteLimit)
+	if err != nil {
+		return rateLimitResult{}, err
+	}
+	result := rateLimitResult{
+		Allowed:   allowed,
+		Cost:      cost,
+		Limit:     dailyLimit,
+		Remaining: maxInt(dailyLimit-used, 0),
+		Reset:     dayStart.Add(24 * time.Hour),
+	}
+	if !allowed {
+		result.RetryAt = rateLimitRetryAt(dayStart, minuteStart, used, cost, dailyLimit)
+	}
+	return result, nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
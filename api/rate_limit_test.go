@@ -0,0 +1,376 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chaincertify/certd/api/database"
+	"go.uber.org/zap"
+)
+
+// mustConsume charges a request against limiter, failing the test on error
+func mustConsume(t *testing.T, limiter apiRateLimiter, keyID string, cost, dailyLimit, minuteLimit int) rateLimitResult {
+	t.Helper()
+	result, err := limiter.Consume(context.Background(), keyID, cost, dailyLimit, minuteLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// rateLimitWindow tracks consumption within a fixed window
+type rateLimitWindow struct {
+	start time.Time
+	used  int
+}
+
+// weightedRateLimiter is an in-memory apiRateLimiter that applies the same rules
+// as consume_api_rate_limit: a weighted daily budget plus a per-minute request
+// cap. The per-minute cap counts requests rather than weight so that an
+// expensive call is never impossible for a tier whose minute limit is lower
+// than its weight.
+type weightedRateLimiter struct {
+	mu     sync.Mutex
+	daily  map[string]*rateLimitWindow
+	minute map[string]*rateLimitWindow
+	now    func() time.Time
+}
+
+// newWeightedRateLimiter creates an empty limiter
+func newWeightedRateLimiter() *weightedRateLimiter {
+	return &weightedRateLimiter{
+		daily:  make(map[string]*rateLimitWindow),
+		minute: make(map[string]*rateLimitWindow),
+		now:    time.Now,
+	}
+}
+
+// Consume charges cost against the key's budget if both windows allow it
+func (l *weightedRateLimiter) Consume(_ context.Context, keyID string, cost, dailyLimit, minuteLimit int) (rateLimitResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	day := l.window(l.daily, keyID, now.Truncate(24*time.Hour))
+	minute := l.window(l.minute, keyID, now.Truncate(time.Minute))
+
+	result := rateLimitResult{
+		Cost:  cost,
+		Limit: dailyLimit,
+		Reset: day.start.Add(24 * time.Hour),
+	}
+
+	withinDaily := dailyLimit <= 0 || day.used+cost <= dailyLimit
+	withinMinute := minuteLimit <= 0 || minute.used+1 <= minuteLimit
+	if withinDaily && withinMinute {
+		day.used += cost
+		minute.used++
+		result.Allowed = true
+	} else {
+		result.RetryAt = rateLimitRetryAt(day.start, minute.start, day.used, cost, dailyLimit)
+	}
+
+	result.Remaining = maxInt(dailyLimit-day.used, 0)
+	return result, nil
+}
+
+// window returns the current window for a key, rolling it over when stale
+func (l *weightedRateLimiter) window(windows map[string]*rateLimitWindow, keyID string, start time.Time) *rateLimitWindow {
+	w, ok := windows[keyID]
+	if !ok || !w.start.Equal(start) {
+		w = &rateLimitWindow{start: start}
+		windows[keyID] = w
+	}
+	return w
+}
+
+func newTestRateLimitServer() *Server {
+	return &Server{
+		config:      &Config{RateLimitWeights: DefaultRateLimitWeights()},
+		rateLimiter: newWeightedRateLimiter(),
+	}
+}
+
+// TestRequestCostReadVsWrite tests that writes are weighted above reads for every tier
+func TestRequestCostReadVsWrite(t *testing.T) {
+	s := newTestRateLimitServer()
+
+	read := httptest.NewRequest("GET", "/api/v1/attestations/abc", nil)
+	write := httptest.NewRequest("POST", "/api/v1/profile", nil)
+	expensive := httptest.NewRequest("POST", "/api/v1/attestations", nil)
+
+	for _, tier := range []string{"free", "developer", "enterprise"} {
+		t.Run(tier, func(t *testing.T) {
+			readCost := s.requestCost(tier, read)
+			writeCost := s.requestCost(tier, write)
+			expensiveCost := s.requestCost(tier, expensive)
+
+			if writeCost <= readCost {
+				t.Errorf("write cost %d should exceed read cost %d", writeCost, readCost)
+			}
+			if expensiveCost <= writeCost {
+				t.Errorf("expensive cost %d should exceed write cost %d", expensiveCost, writeCost)
+			}
+		})
+	}
+}
+
+// TestRequestCostUnknownTier tests that unknown tiers fall back to free-tier weights
+func TestRequestCostUnknownTier(t *testing.T) {
+	s := newTestRateLimitServer()
+	req := httptest.NewRequest("POST", "/api/v1/kyc/start", nil)
+
+	if got, want := s.requestCost("premium", req), DefaultRateLimitWeights()["free"].Expensive; got != want {
+		t.Errorf("requestCost(premium) = %d, want %d", got, want)
+	}
+}
+
+// TestWeightedWriteConsumesMoreBudget tests remaining budget after a read vs a write
+func TestWeightedWriteConsumesMoreBudget(t *testing.T) {
+	s := newTestRateLimitServer()
+	readCost := s.requestCost("free", httptest.NewRequest("GET", "/api/v1/stats", nil))
+	writeCost := s.requestCost("free", httptest.NewRequest("POST", "/api/v1/profile", nil))
+
+	readResult := mustConsume(t, s.rateLimiter, "reader", readCost, 100, 0)
+	writeResult := mustConsume(t, s.rateLimiter, "writer", writeCost, 100, 0)
+
+	if !readResult.Allowed || !writeResult.Allowed {
+		t.Fatal("expected both requests to be allowed")
+	}
+	if writeResult.Remaining >= readResult.Remaining {
+		t.Errorf("write remaining %d should be below read remaining %d", writeResult.Remaining, readResult.Remaining)
+	}
+	if readResult.Remaining != 100-readCost {
+		t.Errorf("read remaining = %d, want %d", readResult.Remaining, 100-readCost)
+	}
+}
+
+// TestWeightedLimitHitSoonerUnderWrites tests that heavy writes exhaust the daily budget first
+func TestWeightedLimitHitSoonerUnderWrites(t *testing.T) {
+	s := newTestRateLimitServer()
+	readCost := s.requestCost("free", httptest.NewRequest("GET", "/api/v1/stats", nil))
+	writeCost := s.requestCost("free", httptest.NewRequest("POST", "/api/v1/profile", nil))
+
+	countAllowed := func(keyID string, cost int) int {
+		allowed := 0
+		for i := 0; i < 200; i++ {
+			if !mustConsume(t, s.rateLimiter, keyID, cost, 100, 0).Allowed {
+				break
+			}
+			allowed++
+		}
+		return allowed
+	}
+
+	reads := countAllowed("reads", readCost)
+	writes := countAllowed("writes", writeCost)
+
+	if reads != 100 {
+		t.Errorf("reads allowed = %d, want 100", reads)
+	}
+	if writes != 100/writeCost {
+		t.Errorf("writes allowed = %d, want %d", writes, 100/writeCost)
+	}
+	if writes >= reads {
+		t.Errorf("writes allowed %d should be fewer than reads allowed %d", writes, reads)
+	}
+}
+
+// TestWeightedMinuteLimitCountsRequests tests that the minute cap is per request, not per weight
+func TestWeightedMinuteLimitCountsRequests(t *testing.T) {
+	limiter := newWeightedRateLimiter()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	// Free tier: minute limit 2, expensive cost 10 - must still be possible
+	if !mustConsume(t, limiter, "key", 10, 100, 2).Allowed {
+		t.Fatal("first expensive request should be allowed")
+	}
+	if !mustConsume(t, limiter, "key", 10, 100, 2).Allowed {
+		t.Fatal("second expensive request should be allowed")
+	}
+	if mustConsume(t, limiter, "key", 1, 100, 2).Allowed {
+		t.Fatal("third request in the same minute should be rejected")
+	}
+
+	// Next minute the burst cap resets but the daily budget carries over
+	now = now.Add(time.Minute)
+	result := mustConsume(t, limiter, "key", 10, 100, 2)
+	if !result.Allowed {
+		t.Fatal("request in the next minute should be allowed")
+	}
+	if result.Remaining != 70 {
+		t.Errorf("remaining = %d, want 70", result.Remaining)
+	}
+
+	// Rejected requests must not consume budget
+	if mustConsume(t, limiter, "key", 80, 100, 0).Allowed {
+		t.Fatal("request exceeding the remaining budget should be rejected")
+	}
+	if got := mustConsume(t, limiter, "key", 1, 100, 0).Remaining; got != 69 {
+		t.Errorf("remaining after rejection = %d, want 69", got)
+	}
+}
+
+// stubAPIKeyStore serves fixed API keys by hash
+type stubAPIKeyStore struct {
+	keys map[string]*database.APIKeyNew
+}
+
+func (m *stubAPIKeyStore) GetAPIKeyByHash(_ context.Context, keyHash string) (*database.APIKeyNew, error) {
+	return m.keys[keyHash], nil
+}
+
+func (m *stubAPIKeyStore) IncrementAPIUsage(context.Context, string, int, int) error { return nil }
+
+func (m *stubAPIKeyStore) IncrementAPIKeyUsage(context.Context, string) error { return nil }
+
+// newAPIKeyServer returns a server that knows the single API key key and
+// keeps its rate limits in memory
+func newAPIKeyServer(t *testing.T, clock *fakeClock, key database.APIKeyNew) (*Server, string) {
+	t.Helper()
+	config := DefaultConfig()
+	config.Clock = clock
+	s := NewServer(config, zap.NewNop())
+	limiter := newWeightedRateLimiter()
+	limiter.now = clock.Now
+	s.rateLimiter = limiter
+
+	const apiKey = "cert_live_ratelimitheaders"
+	hash := sha256.Sum256([]byte(apiKey))
+	s.apiKeys = &stubAPIKeyStore{keys: map[string]*database.APIKeyNew{hex.EncodeToString(hash[:]): &key}}
+	return s, apiKey
+}
+
+// newRateLimitHeaderServer returns a server with one free-tier key and the key
+func newRateLimitHeaderServer(t *testing.T, clock *fakeClock) (*Server, string) {
+	return newAPIKeyServer(t, clock, database.APIKeyNew{ID: "key-1", Tier: "free", RateLimitPerDay: 100, RateLimitPerMinute: 2, Active: true})
+}
+
+// serveRateLimited sends a read with apiKey through the router
+func serveRateLimited(s *Server, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/api/v1/health", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestConcurrentRequestsAllowExactlyLimit fires limit+5 simultaneous requests
+// with one key through the router and expects exactly limit of them through
+func TestConcurrentRequestsAllowExactlyLimit(t *testing.T) {
+	const limit = 20
+	for name, key := range map[string]database.APIKeyNew{
+		"minute cap":   {ID: "key-minute", Tier: "free", RateLimitPerMinute: limit, Active: true},
+		"daily budget": {ID: "key-daily", Tier: "free", RateLimitPerDay: limit, Active: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s, apiKey := newAPIKeyServer(t, newFakeClock(time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)), key)
+			start := make(chan struct{})
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			codes := map[int]int{}
+			for i := 0; i < limit+5; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					code := serveRateLimited(s, apiKey).Code
+					mu.Lock()
+					codes[code]++
+					mu.Unlock()
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			if codes[http.StatusOK] != limit || codes[http.StatusTooManyRequests] != 5 {
+				t.Errorf("status counts %v for %d concurrent requests, want %d OK and 5 rate limited", codes, limit+5, limit)
+			}
+		})
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 5, 1, 12, 0, 30, 0, time.UTC))
+	s, apiKey := newRateLimitHeaderServer(t, clock)
+	reset := strconv.FormatInt(time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC).Unix(), 10)
+
+	for _, wantRemaining := range []string{"99", "98"} {
+		rec := serveRateLimited(s, apiKey)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rec.Code)
+		}
+		h := rec.Header()
+		if h.Get("X-RateLimit-Limit") != "100" || h.Get("X-RateLimit-Remaining") != wantRemaining || h.Get("X-RateLimit-Reset") != reset {
+			t.Errorf("headers limit %q remaining %q reset %q; want 100, %s, %s",
+				h.Get("X-RateLimit-Limit"), h.Get("X-RateLimit-Remaining"), h.Get("X-RateLimit-Reset"), wantRemaining, reset)
+		}
+		if h.Get("Retry-After") != "" {
+			t.Errorf("Retry-After = %q on an allowed request", h.Get("Retry-After"))
+		}
+	}
+
+	// The free tier allows 2 requests a minute; the third waits for the next minute
+	rec := serveRateLimited(s, apiKey)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("third request in the minute: status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+	if got := rec.Header().Get("X-RateLimit-Remaining"); got != "98" {
+		t.Errorf("remaining after rejection = %q, want 98", got)
+	}
+
+	clock.Advance(30 * time.Second)
+	if rec := serveRateLimited(s, apiKey); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Remaining") != "97" {
+		t.Errorf("next minute: status %d, remaining %q; want 200, 97", rec.Code, rec.Header().Get("X-RateLimit-Remaining"))
+	}
+}
+
+func TestRateLimitRetryAfterDailyBudget(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 5, 1, 23, 0, 0, 0, time.UTC))
+	s, apiKey := newRateLimitHeaderServer(t, clock)
+	if _, err := s.rateLimiter.Consume(context.Background(), "key-1", 100, 100, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serveRateLimited(s, apiKey)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rec.Code)
+	}
+	// The daily budget resets at midnight UTC
+	if got := rec.Header().Get("Retry-After"); got != "3600" {
+		t.Errorf("Retry-After = %q, want 3600", got)
+	}
+}
+
+func TestRateLimitHeadersExposedToBrowsers(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 5, 1, 12, 0, 30, 0, time.UTC))
+	s, apiKey := newRateLimitHeaderServer(t, clock)
+
+	req := httptest.NewRequest("GET", "/api/v1/health", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	req.Header.Set("Origin", "https://app.c3rt.org")
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Remaining") != "99" {
+		t.Fatalf("status %d, remaining %q; want 200, 99", rec.Code, rec.Header().Get("X-RateLimit-Remaining"))
+	}
+	exposed := strings.ToLower(rec.Header().Get("Access-Control-Expose-Headers"))
+	for _, h := range []string{"x-ratelimit-limit", "x-ratelimit-remaining", "x-ratelimit-reset", "retry-after"} {
+		if !strings.Contains(exposed, h) {
+			t.Errorf("Access-Control-Expose-Headers %q is missing %s", exposed, h)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// Index reconciliation
+// The explorer's transaction index is written from what the API saw at the
+// time, so a reorg, a missed update or a lagging node can leave it out of step
+// with the chain. A periodic job samples the most recently indexed
+// transactions, re-queries each from the node and corrects the ones that have
+// drifted. Results are served at /api/v1/admin/reconcile/status.
+
+// Kinds of ReconcileDiscrepancy
+const (
+	reconcileMissingOnChain = "missing_on_chain" // Indexed but unknown to the node, e.g. dropped by a reorg
+	reconcileBlockMismatch  = "block_mismatch"
+	reconcileStatusMismatch = "status_mismatch"
+	reconcileGasMismatch    = "gas_mismatch"
+)
+
+// maxReconcileDiscrepancies bounds the discrepancies kept from one run
+const maxReconcileDiscrepancies = 100
+
+// ReconcileConfig schedules index reconciliation
+type ReconcileConfig struct {
+	// Interval is how often the index is reconciled (0 disables the job)
+	Interval time.Duration
+
+	// SampleSize is how many of the most recently indexed transactions each run checks
+	SampleSize int
+}
+
+// DefaultReconcileConfig checks the latest 200 indexed transactions every 10 minutes
+func DefaultReconcileConfig() ReconcileConfig {
+	return ReconcileConfig{Interval: 10 * time.Minute, SampleSize: 200}
+}
+
+// txIndex is the explorer transaction index being reconciled
+type txIndex interface {
+	ListRecentTransactions(ctx context.Context, limit int) ([]database.Transaction, error)
+	UpdateTransactionChainState(ctx context.Context, hash, status string, blockNumber, gasUsed int64) error
+	DeleteTransaction(ctx context.Context, hash string) error
+}
+
+// ReconcileDiscrepancy is one field of an indexed transaction that disagreed with the node
+type ReconcileDiscrepancy struct {
+	Hash      string `json:"hash"`
+	Kind      string `json:"kind"`
+	Indexed   string `json:"indexed,omitempty"`
+	Chain     string `json:"chain,omitempty"`
+	Corrected bool   `json:"corrected"`
+}
+
+// ReconcileRun is the result of one reconciliation pass
+type ReconcileRun struct {
+	StartedAt  Timestamp `json:"started_at"`
+	DurationMS int64     `json:"duration_ms"`
+
+	// Checked counts transactions compared with the node; Skipped ones are
+	// above the node's height and are left for a later run
+	Checked int `json:"checked"`
+	Skipped int `json:"skipped"`
+
+	// Drifted counts transactions with at least one discrepancy, Corrected those fixed in the index
+	Drifted   int     `json:"drifted"`
+	Corrected int     `json:"corrected"`
+	DriftRate float64 `json:"drift_rate"`
+
+	// Errors counts transactions that could not be checked or corrected
+	Errors        int                    `json:"errors"`
+	Error         string                 `json:"error,omitempty"`
+	Discrepancies []ReconcileDiscrepancy `json:"discrepancies"`
+}
+
+// ReconcileStatus is the reconciliation job's configuration, totals and last run
+type ReconcileStatus struct {
+	Enabled    bool   `json:"enabled"`
+	Interval   string `json:"interval"`
+	SampleSize int    `json:"sample_size"`
+
+	Runs           int            `json:"runs"`
+	TotalChecked   int            `json:"total_checked"`
+	TotalDrifted   int            `json:"total_drifted"`
+	TotalCorrected int            `json:"total_corrected"`
+	DriftByKind    map[string]int `json:"drift_by_kind"`
+
+	LastRun *ReconcileRun `json:"last_run"`
+}
+
+// reconcileState accumulates run results for the status endpoint
+type reconcileState struct {
+	mu          sync.Mutex
+	runs        int
+	checked     int
+	drifted     int
+	corrected   int
+	driftByKind map[string]int
+	lastRun     *ReconcileRun
+}
+
+func (r *reconcileState) record(run ReconcileRun) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.driftByKind == nil {
+		r.driftByKind = make(map[string]int)
+	}
+	r.runs++
+	r.checked += run.Checked
+	r.drifted += run.Drifted
+	r.corrected += run.Corrected
+	for _, d := range run.Discrepancies {
+		r.driftByKind[d.Kind]++
+	}
+	r.lastRun = &run
+}
+
+// reconcileIndex compares the most recently indexed transactions with the
+// node and corrects the index where they disagree
+func (s *Server) reconcileIndex(ctx context.Context) ReconcileRun {
+	started := s.clock.Now()
+	run := ReconcileRun{StartedAt: NewTimestamp(started), Discrepancies: []ReconcileDiscrepancy{}}
+	defer func() {
+		run.DurationMS = s.clock.Now().Sub(started).Milliseconds()
+		if run.Checked > 0 {
+			run.DriftRate = float64(run.Drifted) / float64(run.Checked)
+		}
+		s.reconcile.record(run)
+	}()
+
+	txs, err := s.txIndex.ListRecentTransactions(ctx, s.config.IndexReconcile.SampleSize)
+	if err != nil {
+		run.Error = err.Error()
+		return run
+	}
+	latest, err := s.chain.LatestHeight(ctx)
+	if err != nil {
+		run.Error = err.Error()
+		return run
+	}
+
+	for _, indexed := range txs {
+		// A node behind the index cannot tell a dropped tx from one it has not seen yet
+		if indexed.BlockNumber > latest {
+			run.Skipped++
+			continue
+		}
+		compared, err := s.reconcileTransaction(ctx, indexed, &run)
+		if compared {
+			run.Checked++
+		}
+		if err != nil {
+			s.logger.Warn("index reconciliation failed", zap.String("hash", indexed.Hash), zap.Error(err))
+			run.Errors++
+		}
+	}
+	return run
+}
+
+// reconcileTransaction checks one indexed transaction against the node,
+// recording and correcting what differs. compared reports whether the node
+// answered, even if the correction then failed.
+func (s *Server) reconcileTransaction(ctx context.Context, indexed database.Transaction, run *ReconcileRun) (compared bool, err error) {
+	tx, err := s.chain.Tx(ctx, indexed.Hash)
+	if errors.Is(err, ErrChainNotFound) {
+		d := ReconcileDiscrepancy{Hash: indexed.Hash, Kind: reconcileMissingOnChain, Indexed: strconv.FormatInt(indexed.BlockNumber, 10)}
+		err = s.txIndex.DeleteTransaction(ctx, indexed.Hash)
+		s.recordDiscrepancies(run, []ReconcileDiscrepancy{d}, err == nil)
+		return true, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	status := "success"
+	if tx.Code != 0 {
+		status = "failed"
+	}
+	var drift []ReconcileDiscrepancy
+	if indexed.BlockNumber != tx.Height {
+		drift = append(drift, ReconcileDiscrepancy{Kind: reconcileBlockMismatch, Indexed: strconv.FormatInt(indexed.BlockNumber, 10), Chain: strconv.FormatInt(tx.Height, 10)})
+	}
+	if indexed.Status != status {
+		drift = append(drift, ReconcileDiscrepancy{Kind: reconcileStatusMismatch, Indexed: indexed.Status, Chain: status})
+	}
+	if indexed.GasUsed != tx.GasUsed {
+		drift = append(drift, ReconcileDiscrepancy{Kind: reconcileGasMismatch, Indexed: strconv.FormatInt(indexed.GasUsed, 10), Chain: strconv.FormatInt(tx.GasUsed, 10)})
+	}
+	if len(drift) == 0 {
+		return true, nil
+	}
+	for i := range drift {
+		drift[i].Hash = indexed.Hash
+	}
+	err = s.txIndex.UpdateTransactionChainState(ctx, indexed.Hash, status, tx.Height, tx.GasUsed)
+	s.recordDiscrepancies(run, drift, err == nil)
+	return true, err
+}
+
+// recordDiscrepancies adds one drifted transaction's discrepancies to run and logs them
+func (s *Server) recordDiscrepancies(run *ReconcileRun, drift []ReconcileDiscrepancy, corrected bool) {
+	run.Drifted++
+	if corrected {
+		run.Corrected++
+	}
+	for _, d := range drift {
+		d.Corrected = corrected
+		s.logger.Warn("index drift detected",
+			zap.String("hash", d.Hash),
+			zap.String("kind", d.Kind),
+			zap.String("indexed", d.Indexed),
+			zap.String("chain", d.Chain),
+			zap.Bool("corrected", corrected),
+		)
+		if len(run.Discrepancies) < maxReconcileDiscrepancies {
+			run.Discrepancies = append(run.Discrepancies, d)
+		}
+	}
+}
+
+// watchIndexReconciliation reconciles the index every interval until ctx is cancelled
+func (s *Server) watchIndexReconciliation(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if run := s.reconcileIndex(ctx); run.Error != "" && ctx.Err() == nil {
+			s.logger.Warn("index reconciliation run failed", zap.String("error", run.Error))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleReconcileStatus reports the reconciliation job's last run and drift totals
+// GET /api/v1/admin/reconcile/status
+func (s *Server) handleReconcileStatus(w http.ResponseWriter, r *http.Request) {
+	cfg := s.config.IndexReconcile
+	s.reconcile.mu.Lock()
+	status := ReconcileStatus{
+		Enabled:        s.txIndex != nil && cfg.Interval > 0,
+		Interval:       cfg.Interval.String(),
+		SampleSize:     cfg.SampleSize,
+		Runs:           s.reconcile.runs,
+		TotalChecked:   s.reconcile.checked,
+		TotalDrifted:   s.reconcile.drifted,
+		TotalCorrected: s.reconcile.corrected,
+		DriftByKind:    make(map[string]int, len(s.reconcile.driftByKind)),
+		LastRun:        s.reconcile.lastRun,
+	}
+	for kind, n := range s.reconcile.driftByKind {
+		status.DriftByKind[kind] = n
+	}
+	s.reconcile.mu.Unlock()
+
+	s.respondJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// memoryTxIndex is an in-memory txIndex
+type memoryTxIndex struct {
+	mu  sync.Mutex
+	txs map[string]database.Transaction
+}
+
+func (m *memoryTxIndex) ListRecentTransactions(_ context.Context, limit int) ([]database.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]database.Transaction, 0, len(m.txs))
+	for _, tx := range m.txs {
+		out = append(out, tx)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].BlockNumber > out[j].BlockNumber })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (m *memoryTxIndex) UpdateTransactionChainState(_ context.Context, hash, status string, blockNumber, gasUsed int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tx := m.txs[hash]
+	tx.Status, tx.BlockNumber, tx.GasUsed = status, blockNumber, gasUsed
+	m.txs[hash] = tx
+	return nil
+}
+
+func (m *memoryTxIndex) DeleteTransaction(_ context.Context, hash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.txs, hash)
+	return nil
+}
+
+func TestReconcileIndex(t *testing.T) {
+	chain := &mockChainClient{
+		height: 120,
+		txs: map[string]ChainTx{
+			"0xaa": {Hash: "AA", Height: 100, GasUsed: 50000},
+			"0xbb": {Hash: "BB", Height: 105, Code: 11, GasUsed: 61000},
+			"0xcc": {Hash: "CC", Height: 111, GasUsed: 42000},
+		},
+	}
+	index := &memoryTxIndex{txs: map[string]database.Transaction{
+		"0xaa": {Hash: "0xaa", Status: "success", BlockNumber: 100, GasUsed: 50000}, // in step
+		"0xbb": {Hash: "0xbb", Status: "success", BlockNumber: 105, GasUsed: 61000}, // failed on chain
+		"0xcc": {Hash: "0xcc", Status: "success", BlockNumber: 110, GasUsed: 40000}, // re-included after a reorg
+		"0xdd": {Hash: "0xdd", Status: "success", BlockNumber: 110, GasUsed: 30000}, // dropped by the reorg
+		"0xee": {Hash: "0xee", Status: "success", BlockNumber: 125, GasUsed: 30000}, // ahead of the node
+	}}
+
+	config := DefaultConfig()
+	config.Chain = chain
+	config.AdminAddresses = []string{"cert1admin"}
+	s := NewServer(config, zap.NewNop())
+	s.txIndex = index
+
+	run := s.reconcileIndex(context.Background())
+	if run.Checked != 4 || run.Skipped != 1 || run.Drifted != 3 || run.Corrected != 3 || run.Errors != 0 {
+		t.Fatalf("run = %+v", run)
+	}
+	kinds := map[string]int{}
+	for _, d := range run.Discrepancies {
+		kinds[d.Kind]++
+		if !d.Corrected {
+			t.Errorf("discrepancy not corrected: %+v", d)
+		}
+	}
+	want := map[string]int{reconcileStatusMismatch: 1, reconcileBlockMismatch: 1, reconcileGasMismatch: 1, reconcileMissingOnChain: 1}
+	for kind, n := range want {
+		if kinds[kind] != n {
+			t.Errorf("%s discrepancies = %d, want %d (all: %v)", kind, kinds[kind], n, kinds)
+		}
+	}
+
+	if tx := index.txs["0xbb"]; tx.Status != "failed" {
+		t.Errorf("0xbb status = %q, want failed", tx.Status)
+	}
+	if tx := index.txs["0xcc"]; tx.BlockNumber != 111 || tx.GasUsed != 42000 {
+		t.Errorf("0xcc = %+v, want block 111 gas 42000", tx)
+	}
+	if _, ok := index.txs["0xdd"]; ok {
+		t.Error("tx dropped from the chain is still indexed")
+	}
+	if _, ok := index.txs["0xee"]; !ok {
+		t.Error("tx above the node's height was removed")
+	}
+
+	if again := s.reconcileIndex(context.Background()); again.Drifted != 0 || again.Checked != 3 {
+		t.Errorf("second run = %+v, want no drift", again)
+	}
+
+	t.Run("status endpoint", func(t *testing.T) {
+		get := func(caller string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("GET", "/api/v1/admin/reconcile/status", nil)
+			if caller != "" {
+				req.Header.Set("Authorization", "Bearer "+testAuthToken(t, config.JWTSecret, caller))
+			}
+			rec := httptest.NewRecorder()
+			s.router.ServeHTTP(rec, req)
+			return rec
+		}
+		if rec := get("cert1user"); rec.Code != http.StatusForbidden {
+			t.Errorf("non-admin status = %d, want 403", rec.Code)
+		}
+
+		rec := get("cert1admin")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+		}
+		var status ReconcileStatus
+		if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+			t.Fatal(err)
+		}
+		if !status.Enabled || status.Runs != 2 || status.TotalChecked != 7 || status.TotalDrifted != 3 || status.TotalCorrected != 3 {
+			t.Errorf("status = %+v", status)
+		}
+		if status.DriftByKind[reconcileMissingOnChain] != 1 || status.LastRun == nil || status.LastRun.Drifted != 0 {
+			t.Errorf("drift by kind = %v, last run = %+v", status.DriftByKind, status.LastRun)
+		}
+	})
+}
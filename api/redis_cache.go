@@ -0,0 +1,216 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Redis response store
+// Cached responses are shared between API replicas through Redis, spoken over
+// a single RESP connection that is redialled after any failure. Redis being
+// unreachable only turns lookups into misses.
+
+const (
+	// redisCacheKeyPrefix namespaces cached responses in a shared Redis
+	redisCacheKeyPrefix = "certd:api:response:"
+
+	// redisTimeout bounds each Redis round trip
+	redisTimeout = 500 * time.Millisecond
+)
+
+// redisResponseStore keeps cached responses in Redis, letting Redis expire them
+type redisResponseStore struct {
+	addr     string
+	password string
+	db       int
+	logger   *zap.Logger
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// storedResponse is the Redis encoding of a cachedResponse
+type storedResponse struct {
+	Status       int    `json:"status"`
+	ContentType  string `json:"content_type"`
+	Body         []byte `json:"body"`
+	ETag         string `json:"etag"`
+	CacheControl string `json:"cache_control"`
+}
+
+// newRedisResponseStore parses rawURL (redis://[:password@]host:port[/db]);
+// the connection is made on first use
+func newRedisResponseStore(rawURL string, logger *zap.Logger) (*redisResponseStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+	if u.Scheme != "redis" || u.Host == "" {
+		return nil, fmt.Errorf("redis URL must be redis://host:port, got %q", rawURL)
+	}
+	store := &redisResponseStore{addr: u.Host, logger: logger}
+	if _, _, err := net.SplitHostPort(u.Host); err != nil {
+		store.addr = net.JoinHostPort(u.Host, "6379")
+	}
+	if password, ok := u.User.Password(); ok {
+		store.password = password
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if store.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid Redis database %q", db)
+		}
+	}
+	return store, nil
+}
+
+func (s *redisResponseStore) get(key string, _ time.Time) (*cachedResponse, bool) {
+	reply, err := s.do("GET", redisCacheKeyPrefix+key)
+	if err != nil {
+		s.logger.Debug("Redis cache lookup failed", zap.String("key", key), zap.Error(err))
+		return nil, false
+	}
+	if reply == nil {
+		return nil, false
+	}
+	var stored storedResponse
+	if err := json.Unmarshal(reply, &stored); err != nil {
+		return nil, false
+	}
+	return &cachedResponse{
+		status:       stored.Status,
+		contentType:  stored.ContentType,
+		body:         stored.Body,
+		etag:         stored.ETag,
+		cacheControl: stored.CacheControl,
+	}, true
+}
+
+func (s *redisResponseStore) put(key string, e *cachedResponse, now time.Time) {
+	bz, err := json.Marshal(storedResponse{
+		Status:       e.status,
+		ContentType:  e.contentType,
+		Body:         e.body,
+		ETag:         e.etag,
+		CacheControl: e.cacheControl,
+	})
+	if err != nil {
+		return
+	}
+	ttl := e.expires.Sub(now).Milliseconds()
+	if ttl <= 0 {
+		return
+	}
+	if _, err := s.do("SET", redisCacheKeyPrefix+key, string(bz), "PX", strconv.FormatInt(ttl, 10)); err != nil {
+		s.logger.Debug("Redis cache store failed", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// do sends one command and returns its bulk or simple string reply; a nil
+// bulk reply is (nil, nil)
+func (s *redisResponseStore) do(args ...string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		if err := s.dial(); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := s.roundTrip(args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		// The connection may hold a partial reply; start over next time
+		s.conn.Close()
+		s.conn, s.rd = nil, nil
+	}
+	return reply, err
+}
+
+// dial connects and authenticates; the caller holds s.mu
+func (s *redisResponseStore) dial() error {
+	conn, err := net.DialTimeout("tcp", s.addr, redisTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	s.conn, s.rd = conn, bufio.NewReader(conn)
+	if s.password != "" {
+		if _, err = s.roundTrip("AUTH", s.password); err != nil {
+			err = fmt.Errorf("failed to authenticate to Redis: %w", err)
+		}
+	}
+	if err == nil && s.db != 0 {
+		if _, err = s.roundTrip("SELECT", strconv.Itoa(s.db)); err != nil {
+			err = fmt.Errorf("failed to select Redis database %d: %w", s.db, err)
+		}
+	}
+	if err != nil {
+		conn.Close()
+		s.conn, s.rd = nil, nil
+	}
+	return err
+}
+
+// roundTrip writes args as a RESP array and reads the reply
+func (s *redisResponseStore) roundTrip(args ...string) ([]byte, error) {
+	if err := s.conn.SetDeadline(time.Now().Add(redisTimeout)); err != nil {
+		return nil, err
+	}
+	var cmd strings.Builder
+	fmt.Fprintf(&cmd, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&cmd, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(s.conn, cmd.String()); err != nil {
+		return nil, err
+	}
+	return readRESPReply(s.rd)
+}
+
+// redisError is an error reply from Redis; the connection stays usable
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// readRESPReply reads a simple string, error, integer or bulk string reply
+func readRESPReply(rd *bufio.Reader) ([]byte, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, redisError(line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid bulk length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
@@ -0,0 +1,354 @@
+package api
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// CachePolicy is the HTTP caching behaviour for one route
+type CachePolicy struct {
+	TTL             time.Duration // How long a response is served from cache (0 = always revalidate)
+	Immutable       bool          // The response never changes once cacheable (e.g. a finalized block)
+	CacheableStatus []int         // Statuses that get caching headers (default 200 only)
+	VaryByAuth      bool          // Responses depend on the caller's credentials
+}
+
+// CacheConfig maps route path templates to their cache policy
+type CacheConfig struct {
+	Policies   map[string]CachePolicy // Keyed by mux path template, e.g. "/api/v1/explorer/tx/{hash}"
+	MaxEntries int                    // Cached responses retained in memory (0 disables server-side caching)
+	RedisURL   string                 // redis://[:password@]host:port[/db] shares cached responses; empty keeps them in memory
+
+	// FinalizedConfirmations is the depth past which a block no longer changes
+	// and is kept server-side for FinalizedTTL; shallower blocks and
+	// transactions are cached for UnfinalizedTTL
+	FinalizedConfirmations int64
+	FinalizedTTL           time.Duration
+	UnfinalizedTTL         time.Duration
+}
+
+// DefaultCacheConfig returns the default cache policies
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Policies: map[string]CachePolicy{
+			"/api/v1/explorer/tx/{hash}":       {TTL: 5 * time.Second}, // Confirmations grow every block
+			"/api/v1/explorer/block/{height}":  {TTL: 24 * time.Hour, Immutable: true},
+			"/api/v1/badges/icons/{name}":      {TTL: 24 * time.Hour},
+			"/api/v1/explorer/stats":           {TTL: 5 * time.Second},
+			"/api/v1/explorer/transactions":    {TTL: 5 * time.Second}, // Scans recent blocks over RPC
+			"/api/v1/hardware/stats":           {TTL: 30 * time.Second},
+			"/api/v1/staking/validators":       {TTL: 30 * time.Second},
+			"/api/v1/governance/proposals":     {TTL: 15 * time.Second},
+			"/api/v1/wallet/{address}/balance": {}, // Balances move every block
+		},
+		MaxEntries:             4096,
+		FinalizedConfirmations: 10,
+		FinalizedTTL:           7 * 24 * time.Hour,
+		UnfinalizedTTL:         5 * time.Second,
+	}
+}
+
+// cacheable reports whether status gets caching headers under p
+func (p CachePolicy) cacheable(status int) bool {
+	if len(p.CacheableStatus) == 0 {
+		return status == http.StatusOK
+	}
+	for _, s := range p.CacheableStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheControl renders the Cache-Control header for p
+func (p CachePolicy) cacheControl() string {
+	scope := "public"
+	if p.VaryByAuth {
+		scope = "private"
+	}
+	if p.TTL <= 0 {
+		return scope + ", no-cache"
+	}
+	v := scope + ", max-age=" + strconv.Itoa(int(p.TTL/time.Second))
+	if p.Immutable {
+		v += ", immutable"
+	}
+	return v
+}
+
+// cachedResponse is a stored response body with its validator
+type cachedResponse struct {
+	status       int
+	contentType  string
+	body         []byte
+	etag         string
+	cacheControl string
+	expires      time.Time
+}
+
+// fresh reports whether e may still be served at now
+func (e *cachedResponse) fresh(now time.Time) bool {
+	return now.Before(e.expires)
+}
+
+// responseStore holds cached responses for the caching middleware
+type responseStore interface {
+	// get returns the entry for key if it is still fresh at now
+	get(key string, now time.Time) (*cachedResponse, bool)
+	// put stores e under key until e.expires
+	put(key string, e *cachedResponse, now time.Time)
+}
+
+// newResponseStore returns the Redis store when a URL is configured and the
+// in-memory LRU otherwise, or when the URL is invalid
+func newResponseStore(config CacheConfig, logger *zap.Logger) responseStore {
+	if config.RedisURL != "" {
+		store, err := newRedisResponseStore(config.RedisURL, logger)
+		if err == nil {
+			return store
+		}
+		logger.Error("Invalid Redis URL, caching responses in memory", zap.Error(err))
+	}
+	return newResponseCache(config.MaxEntries)
+}
+
+// responseCache is a bounded in-memory LRU of cached responses
+type responseCache struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element // Values are *responseCacheEntry
+	order      *list.List               // Most recently used first
+	maxEntries int
+}
+
+type responseCacheEntry struct {
+	key      string
+	response *cachedResponse
+}
+
+func newResponseCache(maxEntries int) *responseCache {
+	return &responseCache{entries: make(map[string]*list.Element), order: list.New(), maxEntries: maxEntries}
+}
+
+func (c *responseCache) get(key string, now time.Time) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*responseCacheEntry).response
+	if !e.fresh(now) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e, true
+}
+
+// put stores e, evicting the least recently used entries when full
+func (c *responseCache) put(key string, e *cachedResponse, _ time.Time) {
+	if c.maxEntries <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*responseCacheEntry).response = e
+		c.order.MoveToFront(el)
+		return
+	}
+	for c.order.Len() >= c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*responseCacheEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&responseCacheEntry{key: key, response: e})
+}
+
+// cacheDirective lets a handler override its route's TTL for the response it writes
+type cacheDirective struct {
+	ttl  time.Duration
+	keep time.Duration // Server-side lifetime, which may exceed the route's TTL
+	set  bool
+}
+
+type cacheDirectiveKey struct{}
+
+// cacheResponseFor caches the response to r for ttl, which may be shorter than
+// its route's TTL, e.g. while the data can still change
+func cacheResponseFor(r *http.Request, ttl time.Duration) {
+	if d, ok := r.Context().Value(cacheDirectiveKey{}).(*cacheDirective); ok {
+		*d = cacheDirective{ttl: ttl, set: true}
+	}
+}
+
+// keepResponseFor stores the response to r server-side for ttl, which may be
+// longer than its route's TTL; clients still get the route's Cache-Control
+func keepResponseFor(r *http.Request, ttl time.Duration) {
+	if d, ok := r.Context().Value(cacheDirectiveKey{}).(*cacheDirective); ok {
+		*d = cacheDirective{keep: ttl, set: true}
+	}
+}
+
+// isFinalized reports whether a block at height is buried deep enough under
+// latest that it no longer changes
+func (s *Server) isFinalized(height, latest int64) bool {
+	return latest-height > s.config.Cache.FinalizedConfirmations
+}
+
+// bufferedResponseWriter holds a handler's response so it can be validated before sending
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header { return w.header }
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+// responseETag is a strong validator over the response body
+func responseETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches etag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheKey identifies a cached response; credentials are hashed in when the policy varies by auth
+func cacheKey(r *http.Request, p CachePolicy) string {
+	key := r.URL.RequestURI()
+	if p.VaryByAuth {
+		sum := sha256.Sum256([]byte(r.Header.Get("Authorization") + "\x00" + r.Header.Get("X-API-Key")))
+		key += "#" + hex.EncodeToString(sum[:8])
+	}
+	return key
+}
+
+// cachePolicyFor returns the policy of the route r matched, if any
+func (s *Server) cachePolicyFor(r *http.Request) (CachePolicy, bool) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return CachePolicy{}, false
+	}
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return CachePolicy{}, false
+	}
+	tmpl, err := route.GetPathTemplate()
+	if err != nil {
+		return CachePolicy{}, false
+	}
+	p, ok := s.config.Cache.Policies[tmpl]
+	return p, ok
+}
+
+// cachingMiddleware applies the route's cache policy: it sets Cache-Control and ETag,
+// serves fresh responses from cache and answers matching If-None-Match with 304
+func (s *Server) cachingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy, ok := s.cachePolicyFor(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		now := s.clock.Now()
+		key := cacheKey(r, policy)
+		if policy.TTL > 0 {
+			if e, hit := s.responseCache.get(key, now); hit {
+				writeCachedResponse(w, r, policy, e)
+				return
+			}
+		}
+
+		directive := &cacheDirective{}
+		buf := &bufferedResponseWriter{header: w.Header()}
+		next.ServeHTTP(buf, r.WithContext(context.WithValue(r.Context(), cacheDirectiveKey{}, directive)))
+		if buf.status == 0 {
+			buf.status = http.StatusOK
+		}
+		if !policy.cacheable(buf.status) {
+			w.WriteHeader(buf.status)
+			_, _ = w.Write(buf.body.Bytes())
+			return
+		}
+
+		expires := now.Add(policy.TTL)
+		switch {
+		case directive.keep > 0:
+			expires = now.Add(directive.keep)
+		case directive.set && directive.ttl < policy.TTL:
+			policy.TTL, policy.Immutable = directive.ttl, false
+			expires = now.Add(policy.TTL)
+		}
+		e := &cachedResponse{
+			status:       buf.status,
+			contentType:  buf.header.Get("Content-Type"),
+			body:         buf.body.Bytes(),
+			etag:         responseETag(buf.body.Bytes()),
+			cacheControl: policy.cacheControl(),
+			expires:      expires,
+		}
+		if policy.TTL > 0 {
+			s.responseCache.put(key, e, now)
+		}
+		writeCachedResponse(w, r, policy, e)
+	})
+}
+
+// writeCachedResponse sends e, or 304 when the client already holds it
+func writeCachedResponse(w http.ResponseWriter, r *http.Request, p CachePolicy, e *cachedResponse) {
+	h := w.Header()
+	h.Set("ETag", e.etag)
+	h.Set("Cache-Control", e.cacheControl)
+	if p.VaryByAuth {
+		h.Add("Vary", "Authorization, X-API-Key")
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, e.etag) {
+		h.Del("Content-Type")
+		h.Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if e.contentType != "" {
+		h.Set("Content-Type", e.contentType)
+	}
+	h.Set("Content-Length", strconv.Itoa(len(e.body)))
+	w.WriteHeader(e.status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(e.body)
+	}
+}
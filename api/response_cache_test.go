@@ -0,0 +1,373 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// countingChainClient counts tx, block and balance lookups reaching the node
+type countingChainClient struct {
+	*mockChainClient
+	txLookups, blockLookups, balanceLookups int
+}
+
+func (c *countingChainClient) Block(ctx context.Context, heightOrHash string) (ChainBlock, error) {
+	c.blockLookups++
+	return c.mockChainClient.Block(ctx, heightOrHash)
+}
+
+func (c *countingChainClient) Tx(ctx context.Context, hash string) (ChainTx, error) {
+	c.txLookups++
+	return c.mockChainClient.Tx(ctx, hash)
+}
+
+func (c *countingChainClient) Balances(ctx context.Context, address string, height int64) (BankBalancesResponse, error) {
+	c.balanceLookups++
+	return c.mockChainClient.Balances(ctx, address, height)
+}
+
+func newCacheTestServer(t *testing.T, chain ChainClient) (*Server, *fakeClock) {
+	t.Helper()
+	clock := newFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	config := DefaultConfig()
+	config.Chain = chain
+	config.Clock = clock
+	return NewServer(config, zap.NewNop()), clock
+}
+
+func conditionalGet(s *Server, path, etag string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestResponseCache_CacheableEndpoint(t *testing.T) {
+	const txHash = "0xABCDEF"
+	chain := &countingChainClient{mockChainClient: &mockChainClient{
+		height: 120,
+		txs:    map[string]ChainTx{txHash: {Hash: "ABCDEF", Height: 100}},
+	}}
+	s, clock := newCacheTestServer(t, chain)
+	path := "/api/v1/explorer/tx/" + txHash
+
+	first := conditionalGet(s, path, "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("first request: status %d, ETag %q", first.Code, etag)
+	}
+	// Confirmations grow every block, so even a finalized tx is not immutable
+	if cc := first.Header().Get("Cache-Control"); cc != "public, max-age=5" {
+		t.Errorf("Cache-Control = %q", cc)
+	}
+
+	// A matching ETag gets 304 with no body, served without touching the node
+	rec := conditionalGet(s, path, etag)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("conditional request: status %d, body %q", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("ETag") != etag {
+		t.Errorf("304 ETag = %q, want %q", rec.Header().Get("ETag"), etag)
+	}
+	rec = conditionalGet(s, path, `"stale", `+etag)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("etag list: status %d", rec.Code)
+	}
+	rec = conditionalGet(s, path, `"stale"`)
+	if rec.Code != http.StatusOK || rec.Body.String() != first.Body.String() {
+		t.Errorf("mismatched etag: status %d", rec.Code)
+	}
+	if chain.txLookups != 1 {
+		t.Errorf("tx lookups = %d, want 1 (cached)", chain.txLookups)
+	}
+
+	// Errors are neither cached nor given cache headers
+	for i := 0; i < 2; i++ {
+		if rec := conditionalGet(s, "/api/v1/explorer/tx/0xmissing", ""); rec.Code != http.StatusNotFound || rec.Header().Get("ETag") != "" {
+			t.Errorf("missing tx: status %d, ETag %q", rec.Code, rec.Header().Get("ETag"))
+		}
+	}
+	if chain.txLookups != 3 {
+		t.Errorf("tx lookups = %d, want 3", chain.txLookups)
+	}
+
+	// Once the TTL lapses the handler runs again
+	clock.Advance(6 * time.Second)
+	if rec := conditionalGet(s, path, etag); rec.Code != http.StatusNotModified {
+		t.Errorf("after expiry: status %d", rec.Code)
+	}
+	if chain.txLookups != 4 {
+		t.Errorf("tx lookups after expiry = %d, want 4", chain.txLookups)
+	}
+
+	// A new block changes the confirmations, and with them the ETag
+	chain.height = 121
+	clock.Advance(6 * time.Second)
+	rec = conditionalGet(s, path, etag)
+	var tx TransactionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &tx); rec.Code != http.StatusOK || err != nil || tx.Confirmations != 21 {
+		t.Errorf("after a new block: status %d, confirmations %d, %v", rec.Code, tx.Confirmations, err)
+	}
+}
+
+func TestResponseCache_NoCacheEndpointRevalidates(t *testing.T) {
+	chain := &countingChainClient{mockChainClient: &mockChainClient{
+		balances: map[string]BankBalancesResponse{testBalanceAddr: {Balances: []Coin{{Denom: "ucert", Amount: "100"}}}},
+	}}
+	s, _ := newCacheTestServer(t, chain)
+	path := "/api/v1/wallet/" + testBalanceAddr + "/balance"
+
+	first := conditionalGet(s, path, "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("first request: status %d, ETag %q", first.Code, etag)
+	}
+	if cc := first.Header().Get("Cache-Control"); cc != "public, no-cache" {
+		t.Errorf("Cache-Control = %q", cc)
+	}
+
+	// Every request reaches the node; an unchanged balance revalidates to 304
+	if rec := conditionalGet(s, path, etag); rec.Code != http.StatusNotModified {
+		t.Errorf("unchanged balance: status %d", rec.Code)
+	}
+	if chain.balanceLookups != 2 {
+		t.Errorf("balance lookups = %d, want 2", chain.balanceLookups)
+	}
+
+	chain.balances[testBalanceAddr] = BankBalancesResponse{Balances: []Coin{{Denom: "ucert", Amount: "250"}}}
+	rec := conditionalGet(s, path, etag)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("changed balance: status %d, ETag %q", rec.Code, rec.Header().Get("ETag"))
+	}
+	if chain.balanceLookups != 3 {
+		t.Errorf("balance lookups = %d, want 3", chain.balanceLookups)
+	}
+}
+
+func TestCachePolicy_VaryByAuth(t *testing.T) {
+	p := CachePolicy{TTL: time.Minute, VaryByAuth: true}
+	if cc := p.cacheControl(); cc != "private, max-age=60" {
+		t.Errorf("Cache-Control = %q", cc)
+	}
+
+	alice := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil)
+	alice.Header.Set("Authorization", "Bearer alice")
+	bob := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil)
+	bob.Header.Set("Authorization", "Bearer bob")
+	if cacheKey(alice, p) == cacheKey(bob, p) {
+		t.Error("callers with different credentials share a cache entry")
+	}
+	if cacheKey(alice, CachePolicy{}) != cacheKey(bob, CachePolicy{}) {
+		t.Error("public policy keyed by credentials")
+	}
+}
+
+func TestResponseCache_FinalizedBlocksKeptForFinalizedTTL(t *testing.T) {
+	chain := &countingChainClient{mockChainClient: &mockChainClient{
+		height: 120,
+		blocks: map[string]ChainBlock{
+			"100": {Hash: "FINAL", Height: 100, Time: "2026-01-01T00:00:00Z"},
+			"115": {Hash: "RECENT", Height: 115, Time: "2026-01-01T00:00:30Z"},
+		},
+	}}
+	s, clock := newCacheTestServer(t, chain)
+
+	// 20 confirmations: the second request is served from cache, even long after the route TTL
+	first := conditionalGet(s, "/api/v1/explorer/block/100", "")
+	if first.Code != http.StatusOK || first.Header().Get("Cache-Control") != "public, max-age=86400, immutable" {
+		t.Fatalf("finalized block: status %d, Cache-Control %q", first.Code, first.Header().Get("Cache-Control"))
+	}
+	clock.Advance(6 * 24 * time.Hour)
+	if rec := conditionalGet(s, "/api/v1/explorer/block/100", ""); rec.Code != http.StatusOK || rec.Body.String() != first.Body.String() {
+		t.Errorf("cached block: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	if chain.blockLookups != 1 {
+		t.Errorf("block lookups = %d, want 1 (cached)", chain.blockLookups)
+	}
+
+	// Past FinalizedTTL the block is fetched again rather than held indefinitely
+	clock.Advance(2 * 24 * time.Hour)
+	conditionalGet(s, "/api/v1/explorer/block/100", "")
+	if chain.blockLookups != 2 {
+		t.Errorf("block lookups after the finalized TTL = %d, want 2", chain.blockLookups)
+	}
+
+	// 5 confirmations: cached only for the unfinalized TTL
+	recent := conditionalGet(s, "/api/v1/explorer/block/115", "")
+	if cc := recent.Header().Get("Cache-Control"); cc != "public, max-age=5" {
+		t.Errorf("recent block Cache-Control = %q", cc)
+	}
+	conditionalGet(s, "/api/v1/explorer/block/115", "")
+	if chain.blockLookups != 3 {
+		t.Errorf("block lookups = %d, want 3", chain.blockLookups)
+	}
+	clock.Advance(6 * time.Second)
+	conditionalGet(s, "/api/v1/explorer/block/115", "")
+	if chain.blockLookups != 4 {
+		t.Errorf("block lookups after the unfinalized TTL = %d, want 4", chain.blockLookups)
+	}
+}
+
+func TestResponseCache_RecentTxShortTTL(t *testing.T) {
+	const txHash = "0xABCDEF"
+	chain := &countingChainClient{mockChainClient: &mockChainClient{
+		height: 105,
+		txs:    map[string]ChainTx{txHash: {Hash: "ABCDEF", Height: 100}},
+	}}
+	s, clock := newCacheTestServer(t, chain)
+	path := "/api/v1/explorer/tx/" + txHash
+
+	if cc := conditionalGet(s, path, "").Header().Get("Cache-Control"); cc != "public, max-age=5" {
+		t.Errorf("Cache-Control = %q", cc)
+	}
+	conditionalGet(s, path, "")
+	clock.Advance(6 * time.Second)
+	conditionalGet(s, path, "")
+	if chain.txLookups != 2 {
+		t.Errorf("tx lookups = %d, want 2", chain.txLookups)
+	}
+}
+
+func TestResponseCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(time.Minute)
+	c := newResponseCache(2)
+	c.put("a", &cachedResponse{body: []byte("a"), expires: expires}, now)
+	c.put("b", &cachedResponse{body: []byte("b"), expires: expires}, now)
+	c.get("a", now)
+	c.put("c", &cachedResponse{body: []byte("c"), expires: expires}, now)
+
+	if _, ok := c.get("b", now); ok {
+		t.Error("least recently used entry b was kept")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.get(key, now); !ok {
+			t.Errorf("entry %s was evicted", key)
+		}
+	}
+}
+
+// fakeRedis is a RESP server holding GET/SET state, recording each SET's PX argument
+type fakeRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+	px     map[string]string
+}
+
+func serveFakeRedis(t *testing.T) (*fakeRedis, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	fake := &fakeRedis{values: map[string]string{}, px: map[string]string{}}
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go fake.serve(conn)
+		}
+	}()
+	return fake, lis.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	rd := bufio.NewReader(conn)
+	for {
+		var n int
+		if _, err := fmt.Fscanf(rd, "*%d\r\n", &n); err != nil {
+			return
+		}
+		args := make([]string, n)
+		for i := range args {
+			var size int
+			if _, err := fmt.Fscanf(rd, "$%d\r\n", &size); err != nil {
+				return
+			}
+			buf := make([]byte, size+2)
+			if _, err := io.ReadFull(rd, buf); err != nil {
+				return
+			}
+			args[i] = string(buf[:size])
+		}
+
+		f.mu.Lock()
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			if v, ok := f.values[args[1]]; ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(v), v)
+			} else {
+				fmt.Fprint(conn, "$-1\r\n")
+			}
+		case "SET":
+			f.values[args[1]] = args[2]
+			if len(args) == 5 {
+				f.px[args[1]] = args[4]
+			}
+			fmt.Fprint(conn, "+OK\r\n")
+		default:
+			fmt.Fprint(conn, "-ERR unknown command\r\n")
+		}
+		f.mu.Unlock()
+	}
+}
+
+func TestRedisResponseStore(t *testing.T) {
+	fake, addr := serveFakeRedis(t)
+	store, err := newRedisResponseStore("redis://"+addr, zap.NewNop())
+	if err != nil {
+		t.Fatalf("newRedisResponseStore: %v", err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if _, ok := store.get("/missing", now); ok {
+		t.Error("hit for a key never stored")
+	}
+	block := &cachedResponse{status: http.StatusOK, contentType: "application/json", body: []byte(`{"height":100}`), etag: `"e"`, cacheControl: "public", expires: now.Add(7 * 24 * time.Hour)}
+	store.put("/block/100", block, now)
+	store.put("/block/115", &cachedResponse{status: http.StatusOK, expires: now.Add(5 * time.Second)}, now)
+
+	got, ok := store.get("/block/100", now)
+	if !ok || got.status != http.StatusOK || string(got.body) != `{"height":100}` || got.etag != `"e"` || got.cacheControl != "public" {
+		t.Errorf("round trip = %+v, %v", got, ok)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if px := fake.px[redisCacheKeyPrefix+"/block/100"]; px != "604800000" {
+		t.Errorf("finalized block PX = %q, want 604800000", px)
+	}
+	if px := fake.px[redisCacheKeyPrefix+"/block/115"]; px != "5000" {
+		t.Errorf("PX = %q, want 5000", px)
+	}
+}
+
+func TestNewResponseStore(t *testing.T) {
+	if _, ok := newResponseStore(CacheConfig{MaxEntries: 8}, zap.NewNop()).(*responseCache); !ok {
+		t.Error("no Redis URL: want the in-memory cache")
+	}
+	if _, ok := newResponseStore(CacheConfig{RedisURL: "redis://cache:6380/2"}, zap.NewNop()).(*redisResponseStore); !ok {
+		t.Error("Redis URL: want the Redis store")
+	}
+	if _, ok := newResponseStore(CacheConfig{RedisURL: "http://cache"}, zap.NewNop()).(*responseCache); !ok {
+		t.Error("invalid Redis URL: want the in-memory fallback")
+	}
+}
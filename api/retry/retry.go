@@ -0,0 +1,187 @@
+// Package retry retries calls to external services with capped, jittered
+// exponential backoff.
+//
+// Only errors the policy classifies as retryable are retried. By default that
+// is errors marked with Transient, so a call site decides which of its
+// failures (timeouts, 429s, 5xx) are worth another attempt and everything else
+// fails immediately.
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Policy configures Do
+type Policy struct {
+	// MaxAttempts is the total number of calls, including the first (values below 1 mean 1)
+	MaxAttempts int
+
+	// BaseDelay is the wait before the second attempt; each later wait doubles it
+	BaseDelay time.Duration
+
+	// MaxDelay caps any single wait (0 leaves waits uncapped)
+	MaxDelay time.Duration
+
+	// Jitter is the fraction, within [0, 1], of each wait that is randomized
+	// so callers failing together do not retry in lockstep
+	Jitter float64
+
+	// Retryable classifies errors; nil retries errors marked with Transient
+	Retryable func(error) bool
+}
+
+// DefaultPolicy makes 3 attempts, waiting about 250ms and then 500ms
+func DefaultPolicy() Policy {
+	return Policy{MaxAttempts: 3, BaseDelay: 250 * time.Millisecond, MaxDelay: 5 * time.Second, Jitter: 0.2}
+}
+
+// Delay returns the wait after the given failed attempt (1-based), before jitter
+func (p Policy) Delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxDelay > 0 && d >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// wait is Delay with the policy's jitter applied
+func (p Policy) wait(attempt int) time.Duration {
+	d := p.Delay(attempt)
+	jitter := min(max(p.Jitter, 0), 1)
+	if jitter == 0 || d <= 0 {
+		return d
+	}
+	spread := float64(d) * jitter
+	return time.Duration(float64(d) - spread + rand.Float64()*spread)
+}
+
+func (p Policy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsTransient(err)
+}
+
+// Do calls fn until it succeeds, fails with an error the policy does not
+// retry, or runs out of attempts, and returns fn's last error. It stops
+// waiting as soon as ctx is done and then returns ctx's error.
+func Do(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	attempts := max(policy.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if attempt >= attempts || !policy.retryable(err) {
+			return unwrapTransient(err)
+		}
+
+		timer := time.NewTimer(policy.wait(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// transientError marks a failure worth retrying
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+
+func (e *transientError) Unwrap() error { return e.err }
+
+// Transient marks err as retryable under the default classifier; nil stays nil
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &transientError{err: err}
+}
+
+// IsTransient reports whether err, or an error it wraps, was marked with Transient
+func IsTransient(err error) bool {
+	var t *transientError
+	return errors.As(err, &t)
+}
+
+// unwrapTransient strips the Transient marker Do's callers never see
+func unwrapTransient(err error) error {
+	if t, ok := err.(*transientError); ok {
+		return t.err
+	}
+	return err
+}
+
+// RetryableStatus reports whether an HTTP status is worth retrying: 429 and 5xx
+func RetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// StatusError is an HTTP response whose status Do retried
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.StatusCode)
+}
+
+// maxBufferedBody caps how much of a retried response body DoHTTP keeps
+const maxBufferedBody = 1 << 20
+
+// DoHTTP sends the request built by newRequest with client under policy,
+// retrying transport errors and RetryableStatus responses. newRequest is
+// called once per attempt so request bodies can be replayed. When attempts
+// run out on a retryable status, the last response is returned (with its
+// body buffered) so the caller handles it like any other response.
+func DoHTTP(ctx context.Context, policy Policy, client *http.Client, newRequest func(ctx context.Context) (*http.Request, error)) (*http.Response, error) {
+	var last *http.Response
+	var resp *http.Response
+	err := Do(ctx, policy, func(ctx context.Context) error {
+		req, err := newRequest(ctx)
+		if err != nil {
+			return err
+		}
+		resp, err = client.Do(req)
+		if err != nil {
+			return Transient(err)
+		}
+		if !RetryableStatus(resp.StatusCode) {
+			return nil
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBufferedBody))
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		last, resp = resp, nil
+		return Transient(&StatusError{StatusCode: last.StatusCode})
+	})
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && last != nil && ctx.Err() == nil {
+		return last, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
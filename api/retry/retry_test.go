@@ -0,0 +1,169 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastPolicy(attempts int) Policy {
+	return Policy{MaxAttempts: attempts, BaseDelay: time.Millisecond, MaxDelay: 4 * time.Millisecond}
+}
+
+func TestDoSucceedsAfterRetries(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), fastPolicy(5), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return Transient(errors.New("connection reset"))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestDoExhaustsAttempts(t *testing.T) {
+	cause := errors.New("upstream unavailable")
+	calls := 0
+	err := Do(context.Background(), fastPolicy(4), func(context.Context) error {
+		calls++
+		return Transient(cause)
+	})
+	if err != cause {
+		t.Errorf("err = %v, want the last error unwrapped from Transient", err)
+	}
+	if calls != 4 {
+		t.Errorf("calls = %d, want 4", calls)
+	}
+}
+
+func TestDoStopsOnNonRetryableError(t *testing.T) {
+	cause := errors.New("400 bad request")
+	calls := 0
+	err := Do(context.Background(), fastPolicy(5), func(context.Context) error {
+		calls++
+		return cause
+	})
+	if err != cause || calls != 1 {
+		t.Errorf("err = %v after %d calls, want %v after 1", err, calls, cause)
+	}
+
+	t.Run("custom classifier", func(t *testing.T) {
+		policy := fastPolicy(5)
+		policy.Retryable = func(err error) bool { return err != cause }
+		calls = 0
+		err := Do(context.Background(), policy, func(context.Context) error {
+			calls++
+			if calls == 1 {
+				return errors.New("timeout")
+			}
+			return cause
+		})
+		if err != cause || calls != 2 {
+			t.Errorf("err = %v after %d calls, want %v after 2", err, calls, cause)
+		}
+	})
+}
+
+func TestDoCancelledMidRetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := Policy{MaxAttempts: 10, BaseDelay: time.Hour}
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- Do(ctx, policy, func(context.Context) error {
+			calls++
+			return Transient(errors.New("unavailable"))
+		})
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Do kept waiting after the context was cancelled")
+	}
+}
+
+func TestPolicyDelay(t *testing.T) {
+	p := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 300 * time.Millisecond, 40: 300 * time.Millisecond} {
+		if got := p.Delay(attempt); got != want {
+			t.Errorf("Delay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.wait(2); got < 100*time.Millisecond || got > 200*time.Millisecond {
+			t.Fatalf("jittered wait = %v, want within [100ms, 200ms]", got)
+		}
+	}
+}
+
+func TestDoHTTP(t *testing.T) {
+	var calls int32
+	status := http.StatusServiceUnavailable
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(status)
+			w.Write([]byte("busy"))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+	newRequest := func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	}
+
+	resp, err := DoHTTP(context.Background(), fastPolicy(3), ts.Client(), newRequest)
+	if err != nil {
+		t.Fatalf("DoHTTP: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Errorf("status = %d after %d calls, want 200 after 3", resp.StatusCode, calls)
+	}
+
+	t.Run("returns the last response when attempts run out", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		resp, err := DoHTTP(context.Background(), fastPolicy(2), ts.Client(), newRequest)
+		if err != nil {
+			t.Fatalf("DoHTTP: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable || calls != 2 {
+			t.Errorf("status = %d after %d calls, want 503 after 2", resp.StatusCode, calls)
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		status = http.StatusNotFound
+		resp, err := DoHTTP(context.Background(), fastPolicy(3), ts.Client(), newRequest)
+		if err != nil {
+			t.Fatalf("DoHTTP: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound || calls != 1 {
+			t.Errorf("status = %d after %d calls, want 404 after 1", resp.StatusCode, calls)
+		}
+	})
+}
@@ -4,7 +4,9 @@ package api
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"crypto/rand"
+	"io"
 	"net/http"
 	"time"
 
@@ -21,6 +23,47 @@ type Server struct {
 	logger     *zap.Logger
 	config     *Config
 	db         *database.DB
+
+	rateLimiter apiRateLimiter // nil without a database
+	apiKeys     apiKeyStore    // nil without a database
+	loadShedder *loadShedder
+	issuance    issuanceCounter
+
+	responseCache responseStore
+
+	balanceSources []*balanceSource
+	chainActivity  *chainActivityFetcher
+	bridge         *bridgeState
+	badges         badgeCatalogState
+	clock          Clock
+	denom          DenomMetadata
+	trustScore     TrustScoreConfig
+	chain          ChainClient
+
+	kyc              kycStore            // nil without a database
+	referralSeasons  referralSeasonStore // nil without a database
+	billing          stripeBillingStore  // nil without a database
+	humanityWebhooks humanityWebhookStore
+	expiryReminders  expiryReminderStore
+	bridgeTransfers  bridgeTransferStore
+	shareLinks       shareLinkStore
+	authNonces       authNonceStore
+	serviceNonces    *serviceNonceCache
+	socialOAuth      socialOAuthStore
+	socialPosts      *socialPostFetcher
+	socialCodes      socialCodeStore // nil without a database
+	handles          handleStore
+
+	txIndex   txIndex // nil without a database
+	reconcile reconcileState
+
+	heartbeats      *heartbeatTracker
+	uptimeSubmitter uptimeSubmitter // nil without a database
+
+	ipfs ipfsPinner // nil disables server-side encrypted uploads
+
+	// stopBackground cancels the goroutines started by Start
+	stopBackground context.CancelFunc
 }
 
 // Config holds API server configuration
@@ -35,6 +78,7 @@ type Config struct {
 	DatabaseURL     string
 	IPFSGateway     string
 	ChainRPCURL     string
+	ChainGRPCAddr   string // Node gRPC endpoint (host:port) for bank, staking and attestation queries
 	ChainID         string
 
 	// certd tx signing/broadcast config (used by POST create endpoints)
@@ -46,6 +90,93 @@ type Config struct {
 	TxFees           string
 	TxGasPrices      string
 	TxBroadcastMode  string
+
+	// RateLimitWeights maps an API key tier to the per-request budget cost
+	RateLimitWeights map[string]EndpointWeights
+
+	// BatchLimits caps the item count of batch endpoints
+	BatchLimits BatchLimits
+
+	// LoadShedding sets the overload thresholds for rejecting non-critical requests
+	LoadShedding LoadSheddingConfig
+
+	// IssuanceQuotas maps an API key tier to its monthly attestation quota (0 = unlimited)
+	IssuanceQuotas map[string]int
+
+	// ServiceSecrets maps internal caller names to their HMAC service token keys
+	ServiceSecrets map[string][]byte
+
+	// AdminAddresses are the authenticated addresses allowed to call /admin endpoints
+	AdminAddresses []string
+
+	// BalanceSources orders and tunes the backends used for address balance lookups
+	BalanceSources BalanceSourcesConfig
+
+	// Clock is the wall-clock time source; nil uses the system clock
+	Clock Clock
+
+	// Chain is the node client used by handlers; nil talks to the configured node
+	Chain ChainClient
+
+	// BridgeChains is the initial bridge chain list; replace it at runtime with ReloadBridgeChains
+	BridgeChains []SupportedChain
+
+	// BridgeRelayers are the keys allowed to sign bridge transfer confirmations; empty rejects every confirmation
+	BridgeRelayers []*ecdsa.PublicKey
+
+	// Denom describes the native token and its display conversion
+	Denom DenomMetadata
+
+	// HumanityWebhooks tunes humanity score webhook delivery
+	HumanityWebhooks HumanityWebhookConfig
+
+	// ExpiryReminders schedules webhook reminders ahead of attestation expiry
+	ExpiryReminders ExpiryReminderConfig
+
+	// IndexReconcile schedules reconciliation of the explorer index against the chain
+	IndexReconcile ReconcileConfig
+
+	// Heartbeats tunes device heartbeat ingestion and uptime checkpoints
+	Heartbeats HeartbeatConfig
+
+	// Badges is the badge catalog; custom badges stored in the database are layered over it
+	Badges BadgeCatalog
+
+	// EncryptedUploads configures server-side encryption and IPFS pinning for uploads
+	EncryptedUploads EncryptedUploadConfig
+
+	// BulkUploads limits CSV bulk attestation uploads
+	BulkUploads BulkUploadConfig
+
+	// ShareLinks configures recipient-created verification links
+	ShareLinks ShareLinkConfig
+
+	// SocialOAuth configures OAuth verification of social accounts
+	SocialOAuth SocialOAuthConfig
+
+	// SocialCodes limits social verification code generation
+	SocialCodes SocialCodeConfig
+
+	// Stripe configures the billing webhook that upgrades API key tiers
+	Stripe StripeConfig
+
+	// KYC configures how long KYC approvals stay valid
+	KYC KYCConfig
+
+	// Auth configures wallet sign-in challenges
+	Auth AuthConfig
+
+	// TrustScore holds the point values of the identity, sybil and cross-chain scores
+	TrustScore TrustScoreConfig
+
+	// CrossChain tunes the public RPC reads behind cross-chain sybil checks
+	CrossChain CrossChainConfig
+
+	// Cache sets per-route Cache-Control/ETag policies and where cached responses are stored
+	Cache CacheConfig
+
+	// ProfileSigningKey signs identity exports; nil disables /identity/{address}/export
+	ProfileSigningKey *ecdsa.PrivateKey
 }
 
 // DefaultConfig returns default API configuration
@@ -62,6 +193,7 @@ func DefaultConfig() *Config {
 		JWTSecret:       secret,
 		IPFSGateway:     "https://ipfs.c3rt.org",
 		ChainRPCURL:     "http://localhost:26657",
+		ChainGRPCAddr:   defaultChainGRPCAddr,
 		ChainID:         "cert_4283207343-1",
 
 		TxFrom:           "validator",
@@ -72,6 +204,31 @@ func DefaultConfig() *Config {
 		TxFees:           "10000ucert",
 		TxGasPrices:      "",
 		TxBroadcastMode:  "block",
+
+		RateLimitWeights: DefaultRateLimitWeights(),
+		BatchLimits:      DefaultBatchLimits(),
+		LoadShedding:     DefaultLoadSheddingConfig(),
+		IssuanceQuotas:   DefaultIssuanceQuotas(),
+		ServiceSecrets:   map[string][]byte{},
+		BalanceSources:   DefaultBalanceSourcesConfig(),
+		BridgeChains:     DefaultSupportedChains(),
+		Denom:            DefaultDenomMetadata(),
+		HumanityWebhooks: DefaultHumanityWebhookConfig(),
+		ExpiryReminders:  DefaultExpiryReminderConfig(),
+		IndexReconcile:   DefaultReconcileConfig(),
+		Heartbeats:       DefaultHeartbeatConfig(),
+		Badges:           DefaultBadgeCatalog(),
+		EncryptedUploads: DefaultEncryptedUploadConfig(),
+		BulkUploads:      DefaultBulkUploadConfig(),
+		ShareLinks:       DefaultShareLinkConfig(),
+		SocialOAuth:      DefaultSocialOAuthConfig(),
+		SocialCodes:      DefaultSocialCodeConfig(),
+		Stripe:           DefaultStripeConfig(),
+		KYC:              DefaultKYCConfig(),
+		Auth:             DefaultAuthConfig(),
+		TrustScore:       DefaultTrustScoreConfig(),
+		CrossChain:       DefaultCrossChainConfig(),
+		Cache:            DefaultCacheConfig(),
 	}
 }
 
@@ -93,6 +250,67 @@ func NewServer(config *Config, logger *zap.Logger) *Server {
 		logger: logger,
 		config: config,
 		db:     dbConn,
+
+		loadShedder: newLoadShedder(config.LoadShedding),
+		issuance:    newMemoryIssuanceCounter(),
+		clock:       config.Clock,
+		denom:       config.Denom,
+		trustScore:  config.TrustScore,
+		chain:       config.Chain,
+
+		heartbeats:    newHeartbeatTracker(),
+		responseCache: newResponseStore(config.Cache, logger),
+		socialPosts:   newSocialPostFetcher(),
+		serviceNonces: newServiceNonceCache(),
+	}
+	if s.clock == nil {
+		s.clock = systemClock{}
+	}
+	if s.chain == nil {
+		s.chain = newNodeChainClient(config)
+	}
+	if url := config.EncryptedUploads.IPFSAPIURL; url != "" {
+		s.ipfs = kuboPinner{apiURL: url, http: &http.Client{}}
+	}
+	if err := s.denom.Validate(); err != nil {
+		logger.Warn("invalid denom metadata, using defaults", zap.Error(err))
+		s.denom = DefaultDenomMetadata()
+	}
+	if err := s.trustScore.Validate(); err != nil {
+		logger.Warn("invalid trust score weights, using defaults", zap.Error(err))
+		s.trustScore = DefaultTrustScoreConfig()
+	}
+	s.humanityWebhooks = newMemoryHumanityWebhookStore(s.clock.Now)
+	s.expiryReminders = newMemoryExpiryReminderStore()
+	s.bridgeTransfers = newMemoryBridgeTransferStore()
+	s.shareLinks = newMemoryShareLinkStore()
+	s.authNonces = newMemoryAuthNonceStore()
+	s.socialOAuth = newMemorySocialOAuthStore()
+	s.handles = newMemoryHandleStore()
+	if dbConn != nil {
+		s.issuance = dbIssuanceCounter{db: dbConn}
+		s.rateLimiter = dbRateLimiter{db: dbConn, clock: s.clock}
+		s.apiKeys = dbConn
+		s.kyc = dbConn
+		s.referralSeasons = dbConn
+		s.billing = dbConn
+		s.humanityWebhooks = dbHumanityWebhookStore{db: dbConn}
+		s.expiryReminders = dbExpiryReminderStore{db: dbConn}
+		s.txIndex = dbConn
+		s.bridgeTransfers = dbConn
+		s.shareLinks = dbConn
+		s.authNonces = dbConn
+		s.socialOAuth = dbConn
+		s.socialCodes = dbConn
+		s.handles = dbConn
+		s.uptimeSubmitter = dbUptimeSubmitter{db: dbConn}
+	}
+	s.balanceSources = s.newBalanceSources(config.BalanceSources)
+	s.chainActivity = newChainActivityFetcher(config.CrossChain, s.clock.Now)
+	s.bridge = newBridgeState(config.BridgeChains)
+	if err := s.ReloadBadgeCatalog(config.Badges); err != nil {
+		logger.Warn("invalid badge catalog, using the standard badges", zap.Error(err))
+		s.badges.index.Store(newBadgeIndex(DefaultBadgeCatalog()))
 	}
 
 	s.setupRoutes()
@@ -114,20 +332,31 @@ func (s *Server) setupRoutes() {
 	api.HandleFunc("/auth/verify", s.handleAuthVerify).Methods("POST")
 
 	// Encrypted Attestation endpoints (Per Whitepaper Section 8)
-	api.HandleFunc("/encrypted-attestations", s.handleCreateEncryptedAttestation).Methods("POST")
-	api.HandleFunc("/encrypted-attestations/{uid}", s.handleGetEncryptedAttestation).Methods("GET")
-	api.HandleFunc("/encrypted-attestations/{uid}/retrieve", s.handleRetrieveEncryptedAttestation).Methods("POST")
-	api.HandleFunc("/encrypted-attestations/{uid}/revoke", s.handleRevokeEncryptedAttestation).Methods("POST")
+	api.HandleFunc("/encrypted-attestations", s.requireScope(scopeWriteAttestations, s.handleCreateEncryptedAttestation)).Methods("POST")
+	api.HandleFunc("/encrypted-attestations/upload", s.requireScope(scopeWriteAttestations, s.handleUploadEncryptedAttestation)).Methods("POST")
+	api.HandleFunc("/encrypted-attestations/{uid}", s.requireScope(scopeReadAttestations, s.handleGetEncryptedAttestation)).Methods("GET")
+	api.HandleFunc("/encrypted-attestations/{uid}/retrieve", s.requireScope(scopeReadAttestations, s.handleRetrieveEncryptedAttestation)).Methods("POST")
+	api.HandleFunc("/encrypted-attestations/{uid}/revoke", s.requireScope(scopeWriteAttestations, s.handleRevokeEncryptedAttestation)).Methods("POST")
 
 	// Schema endpoints
-	api.HandleFunc("/schemas", s.handleCreateSchema).Methods("POST")
-	api.HandleFunc("/schemas/{uid}", s.handleGetSchema).Methods("GET")
+	api.HandleFunc("/schemas", s.requireScope(scopeWriteSchemas, s.handleCreateSchema)).Methods("POST")
+	api.HandleFunc("/schemas/{uid}", s.requireScope(scopeReadSchemas, s.handleGetSchema)).Methods("GET")
 
 	// Public attestation endpoints
-	api.HandleFunc("/attestations", s.handleCreateAttestation).Methods("POST")
-	api.HandleFunc("/attestations/{uid}", s.handleGetAttestation).Methods("GET")
-	api.HandleFunc("/attestations/by-attester/{address}", s.handleGetAttestationsByAttester).Methods("GET")
-	api.HandleFunc("/attestations/by-recipient/{address}", s.handleGetAttestationsByRecipient).Methods("GET")
+	api.HandleFunc("/attestations", s.requireScope(scopeWriteAttestations, s.handleCreateAttestation)).Methods("POST")
+	api.HandleFunc("/attestations/batch", s.requireScope(scopeReadAttestations, s.handleBatchGetAttestations)).Methods("POST", "OPTIONS")
+	api.HandleFunc("/attestations/batch-revoke", s.requireScope(scopeWriteAttestations, s.handleBatchRevokeAttestations)).Methods("POST", "OPTIONS")
+	api.HandleFunc("/attestations/bulk-upload", s.requireScope(scopeWriteAttestations, s.handleBulkUploadAttestations)).Methods("POST", "OPTIONS")
+	api.HandleFunc("/attestations/estimate", s.handleEstimateAttestation).Methods("POST", "OPTIONS")
+	api.HandleFunc("/attestations/validate", s.handleValidateAttestation).Methods("POST", "OPTIONS")
+	api.HandleFunc("/attestations/{uid}", s.requireScope(scopeReadAttestations, s.handleGetAttestation)).Methods("GET")
+	api.HandleFunc("/attestations/{uid}/endorsements", s.requireScope(scopeReadAttestations, s.handleGetEndorsements)).Methods("GET")
+	api.HandleFunc("/attestations/{uid}/share-link", s.requireAuth(s.handleCreateShareLink)).Methods("POST", "OPTIONS")
+	api.HandleFunc("/attestations/{uid}/share-link/{id}", s.requireAuth(s.handleRevokeShareLink)).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/verify/link/{token}", s.handleVerifyShareLink).Methods("GET")
+	api.HandleFunc("/attestations/by-attester/{address}", s.requireScope(scopeReadAttestations, s.handleGetAttestationsByAttester)).Methods("GET")
+	api.HandleFunc("/attestations/by-recipient/{address}", s.requireScope(scopeReadAttestations, s.handleGetAttestationsByRecipient)).Methods("GET")
+	api.HandleFunc("/attestations/revoked/{attester}", s.requireScope(scopeReadAttestations, s.handleGetRevokedAttestations)).Methods("GET")
 
 	// Wallet + staking (testnet UX)
 	api.HandleFunc("/wallet/{address}/balance", s.handleGetWalletBalance).Methods("GET")
@@ -149,12 +378,20 @@ func (s *Server) setupRoutes() {
 	api.HandleFunc("/profile/verify-social", s.handleVerifySocial).Methods("POST")
 	api.HandleFunc("/profile/credentials", s.handleAddCredential).Methods("POST")
 	api.HandleFunc("/profile/credentials/{id}", s.handleRemoveCredential).Methods("DELETE")
+	api.HandleFunc("/internal/credentials", s.requireService(s.handleRecordVerifiedCredential)).Methods("POST") // Internal: credential verification services
 
 	// CertID Identity Resolution (Per Cert ID Evolution spec)
-	api.HandleFunc("/identity/{address}", s.handleGetFullIdentity).Methods("GET")
-	api.HandleFunc("/identity/{address}/badges", s.handleGetBadges).Methods("GET")
-	api.HandleFunc("/identity/{address}/trust-score", s.handleGetTrustScore).Methods("GET")
-	api.HandleFunc("/identity/resolve/{handle}", s.handleResolveHandle).Methods("GET")
+	api.HandleFunc("/identity/{address}", s.requireScope(scopeReadIdentity, s.handleGetFullIdentity)).Methods("GET")
+	api.HandleFunc("/identity/{address}/badges", s.requireScope(scopeReadIdentity, s.handleGetBadges)).Methods("GET")
+	api.HandleFunc("/badges/icons/{name}", s.handleGetBadgeIcon).Methods("GET")
+	api.HandleFunc("/identity/{address}/trust-score", s.requireScope(scopeReadIdentity, s.handleGetTrustScore)).Methods("GET")
+	api.HandleFunc("/identity/{address}/export", s.requireScope(scopeReadIdentity, s.handleExportProfile)).Methods("GET")
+	api.HandleFunc("/identity/{address}/issuer-reputation", s.requireScope(scopeReadIdentity, s.handleGetIssuerReputation)).Methods("GET")
+	api.HandleFunc("/identity/resolve/{handle}", s.requireScope(scopeReadIdentity, s.handleResolveHandle)).Methods("GET")
+	api.HandleFunc("/identity/handle", s.requireAuth(s.handleClaimHandle)).Methods("POST", "OPTIONS")
+	api.HandleFunc("/identity/handle/release", s.requireAuth(s.handleReleaseHandle)).Methods("POST", "OPTIONS")
+	api.HandleFunc("/identity/handle/{handle}/history", s.requireScope(scopeReadIdentity, s.handleGetHandleHistory)).Methods("GET")
+	api.HandleFunc("/identity/{address}/handle", s.requireScope(scopeReadIdentity, s.handleGetAddressHandle)).Methods("GET")
 
 	// CertID Verifiable Credential (VC) endpoints
 	api.HandleFunc("/certid/vc/verify", s.handleVerifyCertIDVC).Methods("POST")
@@ -164,10 +401,20 @@ func (s *Server) setupRoutes() {
 	api.HandleFunc("/kyc/status", s.requireAuth(s.handleGetKYCStatus)).Methods("GET", "OPTIONS")
 	api.HandleFunc("/kyc/session/{sessionId}", s.requireAuth(s.handleGetKYCSession)).Methods("GET", "OPTIONS")
 	api.HandleFunc("/kyc/webhook", s.handleKYCWebhook).Methods("POST") // No auth - verified by signature
+	api.HandleFunc("/admin/kyc/{sessionId}/reissue", s.requireAdmin(s.handleReissueKYCCredential)).Methods("POST", "OPTIONS")
+	api.HandleFunc("/admin/reconcile/status", s.requireAdmin(s.handleReconcileStatus)).Methods("GET", "OPTIONS")
+	api.HandleFunc("/admin/referral/season", s.requireAdmin(s.handleStartReferralSeason)).Methods("POST", "OPTIONS")
+
+	// Humanity score webhook subscriptions (deliveries are signed with the subscription secret)
+	api.HandleFunc("/webhooks/humanity", s.requireAuth(s.handleCreateHumanityWebhook)).Methods("POST", "OPTIONS")
+	api.HandleFunc("/webhooks/humanity", s.requireAuth(s.handleListHumanityWebhooks)).Methods("GET", "OPTIONS")
+	api.HandleFunc("/webhooks/humanity/{id}", s.requireAuth(s.handleDeleteHumanityWebhook)).Methods("DELETE", "OPTIONS")
 
 	// Social Verification endpoints
 	api.HandleFunc("/social/generate", s.requireAuth(s.handleSocialGenerate)).Methods("POST", "OPTIONS")
 	api.HandleFunc("/social/verify", s.requireAuth(s.handleSocialVerify)).Methods("POST", "OPTIONS")
+	api.HandleFunc("/social/oauth/{platform}/start", s.requireAuth(s.handleSocialOAuthStart)).Methods("GET", "OPTIONS")
+	api.HandleFunc("/social/oauth/{platform}/callback", s.handleSocialOAuthCallback).Methods("GET")
 	api.HandleFunc("/social/{address}", s.handleSocialStatus).Methods("GET")
 
 	// API Key Management endpoints
@@ -176,6 +423,13 @@ func (s *Server) setupRoutes() {
 	api.HandleFunc("/api-keys/{keyId}", s.requireAuth(s.handleRevokeAPIKey)).Methods("DELETE", "OPTIONS")
 	api.HandleFunc("/api-keys/{keyId}/usage", s.requireAuth(s.handleGetAPIKeyUsage)).Methods("GET")
 	api.HandleFunc("/api-keys/tiers", s.handleGetAPITiers).Methods("GET")
+	api.HandleFunc("/billing/stripe/webhook", s.handleStripeWebhook).Methods("POST")
+
+	// Hardware devices (device management UIs and network-wide DePIN metrics)
+	api.HandleFunc("/hardware/stats", s.handleGetHardwareStats).Methods("GET")
+	api.HandleFunc("/hardware/devices/{address}", s.handleGetHardwareDevices).Methods("GET")
+	api.HandleFunc("/hardware/heartbeat", s.handleHardwareHeartbeat).Methods("POST", "OPTIONS") // Authenticated by the device's TEE key
+	api.HandleFunc("/humanity/{address}", s.handleGetHumanityScore).Methods("GET")
 
 	// Sybil Resistance API (Trust Score Validation)
 	api.HandleFunc("/sybil/check/{address}", s.handleSybilCheck).Methods("GET")
@@ -189,13 +443,14 @@ func (s *Server) setupRoutes() {
 
 	// DID:web Support (W3C Decentralized Identifiers)
 	api.HandleFunc("/.well-known/did.json", s.handleGetWellKnownDID).Methods("GET")
-	api.HandleFunc("/identity/{address}/did.json", s.handleGetDIDDocument).Methods("GET")
-	api.HandleFunc("/identity/{address}/presentation", s.handleGetDIDVerifiablePresentation).Methods("GET")
-	api.HandleFunc("/identity/{address}/did/export", s.handleExportDIDtoJSON).Methods("GET")
+	api.HandleFunc("/identity/{address}/did.json", s.requireScope(scopeReadIdentity, s.handleGetDIDDocument)).Methods("GET")
+	api.HandleFunc("/identity/{address}/presentation", s.requireScope(scopeReadIdentity, s.handleGetDIDVerifiablePresentation)).Methods("GET")
+	api.HandleFunc("/identity/{address}/did/export", s.requireScope(scopeReadIdentity, s.handleExportDIDtoJSON)).Methods("GET")
 	api.HandleFunc("/did/resolve", s.handleResolveDID).Methods("GET")
 
 	// Statistics
 	api.HandleFunc("/stats", s.handleGetStats).Methods("GET")
+	api.HandleFunc("/network", s.handleGetNetwork).Methods("GET")
 
 	// Explorer endpoints (Block Explorer)
 	api.HandleFunc("/explorer/tx/{hash}", s.handleGetTransaction).Methods("GET")
@@ -205,6 +460,7 @@ func (s *Server) setupRoutes() {
 	api.HandleFunc("/explorer/transactions", s.handleGetRecentTransactions).Methods("GET")
 	api.HandleFunc("/explorer/verify/{hash}", s.handleVerifyDocument).Methods("GET")
 	api.HandleFunc("/explorer/stats", s.handleGetExplorerStats).Methods("GET")
+	api.HandleFunc("/explorer/contracts/{address}/stats", s.handleGetContractStats).Methods("GET")
 	api.HandleFunc("/explorer/search", s.handleSearchExplorer).Methods("GET")
 
 	// Faucet endpoint (testnet only)
@@ -234,7 +490,7 @@ func (s *Server) setupRoutes() {
 	api.HandleFunc("/bridge/fees", s.handleGetBridgeFees).Methods("GET")
 	api.HandleFunc("/bridge/lock", s.handleLockTokens).Methods("POST")
 	api.HandleFunc("/bridge/transfer/{transfer_id}", s.handleGetTransferStatus).Methods("GET")
-	api.HandleFunc("/bridge/transfer/{transfer_id}/confirm", s.handleConfirmTransfer).Methods("POST")
+	api.HandleFunc("/bridge/transfer/{transfer_id}/confirm", s.requireService(s.handleConfirmTransfer)).Methods("POST") // Internal: bridge validator service
 	api.HandleFunc("/bridge/history/{address}", s.handleGetTransferHistory).Methods("GET")
 	api.HandleFunc("/bridge/stats", s.handleGetBridgeStats).Methods("GET")
 
@@ -245,10 +501,14 @@ func (s *Server) setupRoutes() {
 	api.HandleFunc("/referral/code", s.requireAuth(s.handleGetReferralCode)).Methods("GET")
 	api.HandleFunc("/referral/stats", s.requireAuth(s.handleReferralStats)).Methods("GET")
 	api.HandleFunc("/referral/leaderboard", s.handleReferralLeaderboard).Methods("GET")
+	api.HandleFunc("/referral/rank", s.requireAuth(s.handleReferralRank)).Methods("GET")
 	api.HandleFunc("/referral/redeem", s.requireAuth(s.handleRedeemReferral)).Methods("POST", "OPTIONS")
 
 	// Discourse SSO (Community Forum Integration)
 	s.RegisterDiscourseRoutes(api)
+
+	// EVM JSON-RPC compatibility (wallets, Layer3)
+	s.router.HandleFunc("/rpc", s.handleEVMRPC).Methods("POST", "OPTIONS")
 }
 
 // setupMiddleware configures middleware
@@ -257,15 +517,19 @@ func (s *Server) setupMiddleware() {
 	c := cors.New(cors.Options{
 		AllowedOrigins:   s.config.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Authorization", "Content-Type", "X-Requested-With", "X-API-Key"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type", "X-Requested-With", "X-API-Key", requestIDHeader},
+		ExposedHeaders:   append([]string{requestIDHeader}, rateLimitHeaders...),
 		AllowCredentials: true,
 		MaxAge:           86400,
 	})
 
+	s.router.Use(s.recoveryMiddleware) // Outermost: contains panics from every later layer
 	s.router.Use(c.Handler)
 	s.router.Use(s.loggingMiddleware)
-	s.router.Use(s.recoveryMiddleware)
-	s.router.Use(s.apiKeyMiddleware) // Validate API keys and track usage
+	s.router.Use(s.contentTypeMiddleware)  // 415 for bodies that are not JSON (multipart where the route allows it)
+	s.router.Use(s.loadSheddingMiddleware) // Reject non-critical requests while overloaded
+	s.router.Use(s.apiKeyMiddleware)       // Validate API keys, enforce their rate limits and track usage
+	s.router.Use(s.cachingMiddleware)      // Per-route Cache-Control/ETag and response cache
 }
 
 // Start begins serving the API
@@ -279,6 +543,24 @@ func (s *Server) Start() error {
 		WriteTimeout: s.config.WriteTimeout,
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopBackground = cancel
+	if interval := s.config.HumanityWebhooks.PollInterval; interval > 0 {
+		go s.watchHumanityScoreEvents(ctx, interval)
+	}
+	if interval := s.config.ExpiryReminders.PollInterval; interval > 0 {
+		go s.watchExpiringAttestations(ctx, interval)
+	}
+	if interval := s.config.IndexReconcile.Interval; interval > 0 && s.txIndex != nil {
+		go s.watchIndexReconciliation(ctx, interval)
+	}
+	if interval := s.config.Heartbeats.FlushInterval; interval > 0 && s.uptimeSubmitter != nil {
+		go s.watchUptimeCheckpoints(ctx, interval)
+	}
+	if interval := s.config.SocialCodes.CleanupInterval; interval > 0 && s.socialCodes != nil {
+		go s.watchSocialCodeCleanup(ctx, interval)
+	}
+
 	s.logger.Info("Starting API server", zap.String("address", addr))
 	return s.httpServer.ListenAndServe()
 }
@@ -286,7 +568,15 @@ func (s *Server) Start() error {
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down API server")
+	if s.stopBackground != nil {
+		s.stopBackground()
+	}
 	err := s.httpServer.Shutdown(ctx)
+	if closer, ok := s.chain.(io.Closer); ok {
+		if cerr := closer.Close(); cerr != nil {
+			s.logger.Warn("Failed to close chain client", zap.Error(cerr))
+		}
+	}
 	if s.db != nil {
 		if cerr := s.db.Close(); cerr != nil {
 			s.logger.Warn("Failed to close database", zap.Error(cerr))
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Service-to-service authentication
+// Internal callers (e.g. the bridge validator service) sign requests with an
+// HMAC-SHA256 key shared with the API. Service tokens are sent as
+//
+//	Authorization: Service <caller>:<unix-timestamp>:<nonce>:<hex-signature>
+//
+// where the signature covers the caller, timestamp, nonce, method, path and the
+// SHA-256 of the body. Each nonce is accepted once while its token is fresh.
+// Service tokens and user JWTs are not interchangeable.
+
+const (
+	// serviceAuthScheme is the Authorization scheme used for service tokens
+	serviceAuthScheme = "Service"
+
+	// serviceTokenMaxSkew bounds how far a token timestamp may drift from server time
+	serviceTokenMaxSkew = 5 * time.Minute
+)
+
+// ServiceCallerKey is the context key holding the authenticated internal caller name
+const ServiceCallerKey contextKey = "service_caller"
+
+// ParseServiceSecrets parses "caller=secret,caller2=secret2" into per-caller keys.
+// Caller names containing ':' cannot appear in a token and are skipped.
+func ParseServiceSecrets(s string) map[string][]byte {
+	secrets := make(map[string][]byte)
+	for _, pair := range strings.Split(s, ",") {
+		name, secret, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" || secret == "" || strings.Contains(name, ":") {
+			continue
+		}
+		secrets[name] = []byte(secret)
+	}
+	return secrets
+}
+
+// serviceSignature computes the HMAC signature of a service request
+func serviceSignature(secret []byte, caller string, timestamp int64, nonce, method, path string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%s\n%d\n%s\n%s\n%s\n%x", caller, timestamp, nonce, method, path, bodyHash)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// readServiceBody reads a request body for signing and restores it for the handler
+func readServiceBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxJSONBodyBytes+1))
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxJSONBodyBytes {
+		return nil, fmt.Errorf("request body too large")
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// SignServiceRequest sets a service token on an outgoing internal request.
+// The caller name must not contain ':'.
+func SignServiceRequest(r *http.Request, caller string, secret []byte, now time.Time) error {
+	if strings.Contains(caller, ":") {
+		return fmt.Errorf("service caller %q contains ':'", caller)
+	}
+	body, err := readServiceBody(r)
+	if err != nil {
+		return fmt.Errorf("read request body: %w", err)
+	}
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return fmt.Errorf("generate service token nonce: %w", err)
+	}
+
+	ts := now.Unix()
+	nonceHex := hex.EncodeToString(nonce[:])
+	sig := serviceSignature(secret, caller, ts, nonceHex, r.Method, r.URL.Path, body)
+	r.Header.Set("Authorization", fmt.Sprintf("%s %s:%d:%s:%s", serviceAuthScheme, caller, ts, nonceHex, sig))
+	return nil
+}
+
+// serviceNonceCache remembers the nonces of accepted service tokens until the
+// tokens go stale, so a captured token cannot be replayed
+type serviceNonceCache struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time // caller:nonce -> expiry
+}
+
+func newServiceNonceCache() *serviceNonceCache {
+	return &serviceNonceCache{nonces: make(map[string]time.Time)}
+}
+
+// use records nonce for caller and reports whether it was unused
+func (c *serviceNonceCache) use(caller, nonce string, expiresAt, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, expiry := range c.nonces {
+		if !now.Before(expiry) {
+			delete(c.nonces, key)
+		}
+	}
+	key := caller + ":" + nonce
+	if _, seen := c.nonces[key]; seen {
+		return false
+	}
+	c.nonces[key] = expiresAt
+	return true
+}
+
+// verifyServiceToken validates a service token and returns the caller name
+func (s *Server) verifyServiceToken(r *http.Request, token string, now time.Time) (string, error) {
+	parts := strings.Split(token, ":")
+	if len(parts) != 4 {
+		return "", fmt.Errorf("malformed service token")
+	}
+	caller, tsStr, nonce, sig := parts[0], parts[1], parts[2], parts[3]
+	if nonce == "" {
+		return "", fmt.Errorf("missing service token nonce")
+	}
+
+	secret, ok := s.config.ServiceSecrets[caller]
+	if !ok || len(secret) == 0 {
+		return "", fmt.Errorf("unknown service caller")
+	}
+
+	ts, err := strconv.ParseInt(tsStr, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid service token timestamp")
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > serviceTokenMaxSkew || skew < -serviceTokenMaxSkew {
+		return "", fmt.Errorf("service token expired")
+	}
+
+	body, err := readServiceBody(r)
+	if err != nil {
+		return "", fmt.Errorf("read request body: %w", err)
+	}
+	expected := serviceSignature(secret, caller, ts, nonce, r.Method, r.URL.Path, body)
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return "", fmt.Errorf("invalid service token signature")
+	}
+
+	if !s.serviceNonces.use(caller, nonce, time.Unix(ts, 0).Add(serviceTokenMaxSkew+time.Second), now) {
+		return "", fmt.Errorf("service token already used")
+	}
+	return caller, nil
+}
+
+// serviceAuthMiddleware gates internal endpoints to signed service callers.
+// User JWTs are rejected even when valid.
+func (s *Server) serviceAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			s.respondError(w, http.StatusUnauthorized, "service token required")
+			return
+		}
+
+		scheme, token, _ := strings.Cut(authHeader, " ")
+		if scheme != serviceAuthScheme {
+			s.respondError(w, http.StatusForbidden, "endpoint is restricted to internal services")
+			return
+		}
+
+		caller, err := s.verifyServiceToken(r, token, s.clock.Now())
+		if err != nil {
+			s.logger.Warn("Rejected service token",
+				zap.String("path", r.URL.Path),
+				zap.String("remote_addr", r.RemoteAddr),
+				zap.Error(err),
+			)
+			s.respondError(w, http.StatusUnauthorized, "invalid service token")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), ServiceCallerKey, caller)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requireService wraps a handler with service-to-service authentication
+func (s *Server) requireService(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.serviceAuthMiddleware(http.HandlerFunc(handler)).ServeHTTP(w, r)
+	}
+}
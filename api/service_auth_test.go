@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+func newServiceAuthTestServer(t *testing.T) *Server {
+	t.Helper()
+	config := DefaultConfig()
+	config.ServiceSecrets = map[string][]byte{"bridge-validator": []byte("validator-secret")}
+	config.BridgeRelayers = []*ecdsa.PublicKey{&testRelayerKey.PublicKey}
+	server := NewServer(config, zap.NewNop())
+
+	_ = server.bridgeTransfers.CreateBridgeTransfer(context.Background(), &database.BridgeTransfer{TransferID: "svc-test-transfer", Status: "pending"})
+	return server
+}
+
+func confirmTransferRequest() *http.Request {
+	return newJSONRequest("POST", "/api/v1/bridge/transfer/svc-test-transfer/confirm", strings.NewReader(testConfirmBody(testRelayerKey, "svc-test-transfer", "", "confirmed", 3)))
+}
+
+// TestServiceEndpointAcceptsServiceToken tests that a signed service token reaches the handler
+func TestServiceEndpointAcceptsServiceToken(t *testing.T) {
+	server := newServiceAuthTestServer(t)
+
+	req := confirmTransferRequest()
+	SignServiceRequest(req, "bridge-validator", []byte("validator-secret"), time.Now())
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	transfer, _ := server.bridgeTransfers.GetBridgeTransfer(context.Background(), "svc-test-transfer")
+	if status := transfer.Status; status != "confirmed" {
+		t.Errorf("transfer status = %q, want confirmed", status)
+	}
+}
+
+// TestServiceEndpointRejectsUserJWT tests that a valid user JWT cannot call an internal endpoint
+func TestServiceEndpointRejectsUserJWT(t *testing.T) {
+	server := newServiceAuthTestServer(t)
+
+	req := confirmTransferRequest()
+	req.Header.Set("Authorization", "Bearer "+testAuthToken(t, server.config.JWTSecret, "cert1user"))
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+// TestServiceEndpointRejectsBadTokens tests unsigned, forged, stale and unknown-caller tokens
+func TestServiceEndpointRejectsBadTokens(t *testing.T) {
+	server := newServiceAuthTestServer(t)
+
+	cases := []struct {
+		name string
+		sign func(r *http.Request)
+	}{
+		{"missing", func(r *http.Request) {}},
+		{"wrong secret", func(r *http.Request) {
+			SignServiceRequest(r, "bridge-validator", []byte("other-secret"), time.Now())
+		}},
+		{"unknown caller", func(r *http.Request) {
+			SignServiceRequest(r, "indexer", []byte("validator-secret"), time.Now())
+		}},
+		{"stale", func(r *http.Request) {
+			SignServiceRequest(r, "bridge-validator", []byte("validator-secret"), time.Now().Add(-time.Hour))
+		}},
+		{"malformed", func(r *http.Request) { r.Header.Set("Authorization", "Service garbage") }},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := confirmTransferRequest()
+			tc.sign(req)
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, req)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+
+	// A token signed for one path cannot be replayed against another
+	req := confirmTransferRequest()
+	other := httptest.NewRequest("POST", "/api/v1/bridge/transfer/other/confirm", nil)
+	SignServiceRequest(other, "bridge-validator", []byte("validator-secret"), time.Now())
+	req.Header.Set("Authorization", other.Header.Get("Authorization"))
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("replayed token status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+// TestServiceTokenCoversBodyAndNonce tests that a token cannot be reused or moved to another body
+func TestServiceTokenCoversBodyAndNonce(t *testing.T) {
+	server := newServiceAuthTestServer(t)
+	serve := func(req *http.Request) int {
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// A signed body cannot be swapped for another
+	signed := confirmTransferRequest()
+	SignServiceRequest(signed, "bridge-validator", []byte("validator-secret"), time.Now())
+	tampered := newJSONRequest("POST", "/api/v1/bridge/transfer/svc-test-transfer/confirm",
+		strings.NewReader(testConfirmBody(testRelayerKey, "svc-test-transfer", "", "failed", 3)))
+	tampered.Header.Set("Authorization", signed.Header.Get("Authorization"))
+	if code := serve(tampered); code != http.StatusUnauthorized {
+		t.Errorf("tampered body status = %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	// A token is accepted once
+	first := confirmTransferRequest()
+	SignServiceRequest(first, "bridge-validator", []byte("validator-secret"), time.Now())
+	replay := confirmTransferRequest()
+	replay.Header.Set("Authorization", first.Header.Get("Authorization"))
+	if code := serve(first); code != http.StatusOK {
+		t.Fatalf("first use status = %d, want %d", code, http.StatusOK)
+	}
+	if code := serve(replay); code != http.StatusUnauthorized {
+		t.Errorf("replayed token status = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+// TestInternalCredentialsRequireServiceToken tests the credential verification hook is service-only
+func TestInternalCredentialsRequireServiceToken(t *testing.T) {
+	server := newServiceAuthTestServer(t)
+	body := `{"user_address":"cert1user","credential_type":"degree","attestation_uid":"0xabc","issuer":"cert1issuer"}`
+	serve := func(req *http.Request) int {
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	jwt := newJSONRequest("POST", "/api/v1/internal/credentials", strings.NewReader(body))
+	jwt.Header.Set("Authorization", "Bearer "+testAuthToken(t, server.config.JWTSecret, "cert1user"))
+	if code := serve(jwt); code != http.StatusForbidden {
+		t.Errorf("user JWT status = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := serve(newJSONRequest("POST", "/api/v1/internal/credentials", strings.NewReader(body))); code != http.StatusUnauthorized {
+		t.Errorf("unsigned status = %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	// A signed request reaches the handler, which needs the CertID database
+	signed := newJSONRequest("POST", "/api/v1/internal/credentials", strings.NewReader(body))
+	SignServiceRequest(signed, "bridge-validator", []byte("validator-secret"), time.Now())
+	if code := serve(signed); code != http.StatusServiceUnavailable {
+		t.Errorf("service token status = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+}
+
+// TestUserEndpointRejectsServiceToken tests that service tokens are not accepted as user auth
+func TestUserEndpointRejectsServiceToken(t *testing.T) {
+	server := newServiceAuthTestServer(t)
+
+	req := httptest.NewRequest("GET", "/api/v1/kyc/status", nil)
+	SignServiceRequest(req, "bridge-validator", []byte("validator-secret"), time.Now())
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+// TestParseServiceSecrets tests parsing of the SERVICE_SECRETS format
+func TestParseServiceSecrets(t *testing.T) {
+	secrets := ParseServiceSecrets("bridge-validator=abc, indexer=def,broken,=x,bad:name=y")
+	if len(secrets) != 2 || string(secrets["bridge-validator"]) != "abc" || string(secrets["indexer"]) != "def" {
+		t.Errorf("ParseServiceSecrets = %v", secrets)
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/internal/credentials", nil)
+	if err := SignServiceRequest(req, "bad:name", []byte("y"), time.Now()); err == nil {
+		t.Error("expected a caller name containing ':' to be rejected")
+	}
+}
@@ -0,0 +1,344 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+// Credential share links
+//
+// A recipient creates a link for one of their attestations and hands it out,
+// e.g. on a resume. Anyone holding the link can check that the attestation is
+// valid without the recipient's key. The token is an HMAC-signed
+// "<payload>.<mac>" pair whose payload names the link and its expiry, so
+// forged or expired tokens are rejected before any lookup; the stored link
+// records revocation. Verification returns status and parties only, never
+// the attestation data.
+
+// shareLinkTokenDomain separates share link MACs from other uses of the server secret
+const shareLinkTokenDomain = "cert-share-link:v1|"
+
+// ShareLinkConfig configures credential share links
+type ShareLinkConfig struct {
+	// BaseURL is prefixed to the token to form the shareable URL
+	BaseURL string
+
+	// MaxTTL caps a link's lifetime; 0 allows links that never expire
+	MaxTTL time.Duration
+}
+
+// DefaultShareLinkConfig links to the public verifier and allows any lifetime
+func DefaultShareLinkConfig() ShareLinkConfig {
+	return ShareLinkConfig{BaseURL: "https://c3rt.org/verify/link/"}
+}
+
+// shareLinkClaims is the signed token payload
+type shareLinkClaims struct {
+	ID        string `json:"id"`
+	UID       string `json:"uid"`
+	ExpiresAt int64  `json:"exp,omitempty"` // Unix seconds; 0 never expires
+}
+
+// signShareLinkToken encodes claims as a token signed with secret
+func signShareLinkToken(secret []byte, claims shareLinkClaims) string {
+	payload, _ := json.Marshal(claims)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(shareLinkMAC(secret, encoded))
+}
+
+func shareLinkMAC(secret []byte, encodedPayload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(shareLinkTokenDomain + encodedPayload))
+	return mac.Sum(nil)
+}
+
+// parseShareLinkToken checks a token's signature and returns its claims
+func parseShareLinkToken(secret []byte, token string) (shareLinkClaims, error) {
+	var claims shareLinkClaims
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return claims, errors.New("malformed token")
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, shareLinkMAC(secret, encoded)) {
+		return claims, errors.New("invalid token signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return claims, errors.New("malformed token")
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ID == "" || claims.UID == "" {
+		return claims, errors.New("malformed token")
+	}
+	return claims, nil
+}
+
+// shareLinkStore persists share links; *database.DB implements it
+type shareLinkStore interface {
+	CreateShareLink(ctx context.Context, link *database.ShareLink) error
+	GetShareLink(ctx context.Context, id string) (*database.ShareLink, error)
+	RevokeShareLink(ctx context.Context, owner, attestationUID, id string, at time.Time) (bool, error)
+}
+
+// memoryShareLinkStore keeps share links in memory (used when no database is configured)
+type memoryShareLinkStore struct {
+	mu    sync.Mutex
+	links map[string]database.ShareLink
+}
+
+func newMemoryShareLinkStore() *memoryShareLinkStore {
+	return &memoryShareLinkStore{links: make(map[string]database.ShareLink)}
+}
+
+func (m *memoryShareLinkStore) CreateShareLink(_ context.Context, link *database.ShareLink) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.links[link.ID] = *link
+	return nil
+}
+
+func (m *memoryShareLinkStore) GetShareLink(_ context.Context, id string) (*database.ShareLink, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link, ok := m.links[id]
+	if !ok {
+		return nil, nil
+	}
+	return &link, nil
+}
+
+func (m *memoryShareLinkStore) RevokeShareLink(_ context.Context, owner, attestationUID, id string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link, ok := m.links[id]
+	if !ok || link.OwnerAddress != owner || link.AttestationUID != attestationUID || link.RevokedAt != nil {
+		return false, nil
+	}
+	link.RevokedAt = &at
+	m.links[id] = link
+	return true, nil
+}
+
+// sharedAttestation is the chain record behind a share link
+type sharedAttestation struct {
+	UID            string    `json:"uid"`
+	SchemaUID      string    `json:"schema_uid"`
+	Attester       string    `json:"attester"`
+	Recipient      string    `json:"recipient"`
+	Time           time.Time `json:"time"`
+	ExpirationTime time.Time `json:"expiration_time"`
+	RevocationTime time.Time `json:"revocation_time"`
+}
+
+// querySharedAttestation fetches an attestation, returning nil if the chain does not know it
+func (s *Server) querySharedAttestation(uid string) (*sharedAttestation, error) {
+	var res struct {
+		Attestation sharedAttestation `json:"attestation"`
+	}
+	if err := s.execCertdQueryJSON(&res, "attestation", "attestation", uid); err != nil {
+		if strings.Contains(err.Error(), attestationtypes.ErrAttestationNotFound.Error()) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &res.Attestation, nil
+}
+
+// ShareLinkResponse is a newly created share link
+type ShareLinkResponse struct {
+	ID             string     `json:"id"`
+	AttestationUID string     `json:"attestation_uid"`
+	Token          string     `json:"token"`
+	URL            string     `json:"url"`
+	ExpiresAt      *Timestamp `json:"expires_at,omitempty"`
+}
+
+// handleCreateShareLink creates a verification link for one of the caller's attestations
+// POST /api/v1/attestations/{uid}/share-link {"expires_in": seconds}
+func (s *Server) handleCreateShareLink(w http.ResponseWriter, r *http.Request) {
+	uid := mux.Vars(r)["uid"]
+	var req struct {
+		ExpiresIn int64 `json:"expires_in"` // Seconds; 0 never expires
+	}
+	if err := decodeJSONBody(w, r, &req); err != nil && !errors.Is(err, errEmptyBody) {
+		s.respondDecodeError(w, err)
+		return
+	}
+	ttl := time.Duration(req.ExpiresIn) * time.Second
+	switch maxTTL := s.config.ShareLinks.MaxTTL; {
+	case req.ExpiresIn < 0:
+		s.respondError(w, http.StatusBadRequest, "expires_in must not be negative")
+		return
+	case maxTTL > 0 && (ttl == 0 || ttl > maxTTL):
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("expires_in must be between 1 and %d seconds", int64(maxTTL/time.Second)))
+		return
+	}
+
+	owner, err := toBech32Address(getAuthenticatedAddress(r))
+	if err != nil {
+		s.respondError(w, http.StatusUnauthorized, "invalid authenticated address")
+		return
+	}
+	attestation, err := s.querySharedAttestation(uid)
+	if err != nil {
+		s.logger.Warn("failed to query attestation", zap.String("uid", uid), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query attestation")
+		return
+	}
+	if attestation == nil {
+		s.respondError(w, http.StatusNotFound, "attestation not found")
+		return
+	}
+	if attestation.Recipient != owner {
+		s.respondError(w, http.StatusForbidden, "only the attestation's recipient can share it")
+		return
+	}
+
+	now := s.clock.Now()
+	link := &database.ShareLink{
+		ID:             "shl_" + generateUID()[:24],
+		AttestationUID: uid,
+		OwnerAddress:   owner,
+		CreatedAt:      now,
+	}
+	claims := shareLinkClaims{ID: link.ID, UID: uid}
+	resp := ShareLinkResponse{ID: link.ID, AttestationUID: uid}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		link.ExpiresAt = &expiresAt
+		claims.ExpiresAt = expiresAt.Unix()
+		ts := NewTimestamp(expiresAt)
+		resp.ExpiresAt = &ts
+	}
+	if err := s.shareLinks.CreateShareLink(r.Context(), link); err != nil {
+		s.logger.Error("failed to create share link", zap.String("uid", uid), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to create share link")
+		return
+	}
+
+	resp.Token = signShareLinkToken(s.config.JWTSecret, claims)
+	resp.URL = s.config.ShareLinks.BaseURL + resp.Token
+	s.respondJSON(w, http.StatusCreated, resp)
+}
+
+// handleRevokeShareLink revokes one of the caller's share links
+// DELETE /api/v1/attestations/{uid}/share-link/{id}
+func (s *Server) handleRevokeShareLink(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	owner, err := toBech32Address(getAuthenticatedAddress(r))
+	if err != nil {
+		s.respondError(w, http.StatusUnauthorized, "invalid authenticated address")
+		return
+	}
+	revoked, err := s.shareLinks.RevokeShareLink(r.Context(), owner, vars["uid"], vars["id"], s.clock.Now())
+	if err != nil {
+		s.logger.Error("failed to revoke share link", zap.String("id", vars["id"]), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to revoke share link")
+		return
+	}
+	if !revoked {
+		s.respondError(w, http.StatusNotFound, "share link not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ShareLinkVerification is the public result of resolving a share link
+type ShareLinkVerification struct {
+	Valid          bool       `json:"valid"`
+	Status         string     `json:"status"` // valid, revoked or expired
+	UID            string     `json:"uid"`
+	SchemaUID      string     `json:"schema_uid"`
+	Attester       string     `json:"attester"`
+	Recipient      string     `json:"recipient"`
+	IssuedAt       Timestamp  `json:"issued_at"`
+	ExpirationTime *Timestamp `json:"expiration_time,omitempty"`
+	RevocationTime *Timestamp `json:"revocation_time,omitempty"`
+	LinkExpiresAt  *Timestamp `json:"link_expires_at,omitempty"`
+	VerifiedAt     Timestamp  `json:"verified_at"`
+}
+
+// handleVerifyShareLink resolves a share link to the attestation's current status
+// GET /api/v1/verify/link/{token}
+func (s *Server) handleVerifyShareLink(w http.ResponseWriter, r *http.Request) {
+	claims, err := parseShareLinkToken(s.config.JWTSecret, mux.Vars(r)["token"])
+	if err != nil {
+		s.respondError(w, http.StatusNotFound, "share link not found")
+		return
+	}
+	now := s.clock.Now()
+	if claims.ExpiresAt != 0 && !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		s.respondError(w, http.StatusGone, "share link expired")
+		return
+	}
+
+	link, err := s.shareLinks.GetShareLink(r.Context(), claims.ID)
+	if err != nil {
+		s.logger.Error("failed to get share link", zap.String("id", claims.ID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to resolve share link")
+		return
+	}
+	if link == nil || link.AttestationUID != claims.UID {
+		s.respondError(w, http.StatusNotFound, "share link not found")
+		return
+	}
+	if link.RevokedAt != nil {
+		s.respondError(w, http.StatusGone, "share link revoked")
+		return
+	}
+
+	a, err := s.querySharedAttestation(link.AttestationUID)
+	if err != nil {
+		s.logger.Warn("failed to query attestation", zap.String("uid", link.AttestationUID), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to query attestation")
+		return
+	}
+	if a == nil {
+		s.respondError(w, http.StatusNotFound, "attestation not found")
+		return
+	}
+
+	out := ShareLinkVerification{
+		Status:     "valid",
+		UID:        link.AttestationUID,
+		SchemaUID:  a.SchemaUID,
+		Attester:   a.Attester,
+		Recipient:  a.Recipient,
+		IssuedAt:   NewTimestamp(a.Time),
+		VerifiedAt: NewTimestamp(now),
+	}
+	if !a.ExpirationTime.IsZero() {
+		ts := NewTimestamp(a.ExpirationTime)
+		out.ExpirationTime = &ts
+	}
+	if link.ExpiresAt != nil {
+		ts := NewTimestamp(*link.ExpiresAt)
+		out.LinkExpiresAt = &ts
+	}
+	switch {
+	case !a.RevocationTime.IsZero():
+		out.Status = "revoked"
+		ts := NewTimestamp(a.RevocationTime)
+		out.RevocationTime = &ts
+	case !a.ExpirationTime.IsZero() && now.After(a.ExpirationTime):
+		out.Status = "expired"
+	default:
+		out.Valid = true
+	}
+	s.respondJSON(w, http.StatusOK, out)
+}
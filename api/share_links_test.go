@@ -0,0 +1,164 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newShareLinkServer serves 0xdiploma, held by validateCreator, and 0xrevoked, revoked on chain
+func newShareLinkServer(t *testing.T) (*Server, *fakeClock) {
+	t.Helper()
+	clock := newFakeClock(time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC))
+	s := newClockTestServer(clock)
+
+	orig := runCertdCLI
+	t.Cleanup(func() { runCertdCLI = orig })
+	runCertdCLI = func(args ...string) ([]byte, error) {
+		if len(args) < 4 || args[2] != "attestation" {
+			return nil, errors.New("unexpected query")
+		}
+		switch args[3] {
+		case "0xdiploma":
+			return []byte(`{"attestation":{"uid":"0xdiploma","schema_uid":"0xschema","attester":"` + validateAttester + `","recipient":"` + validateCreator + `",
+				"time":"2026-01-10T00:00:00Z","expiration_time":"0001-01-01T00:00:00Z","revocation_time":"0001-01-01T00:00:00Z","data":"c2VjcmV0IGdyYWRlcw=="}}`), nil
+		case "0xrevoked":
+			return []byte(`{"attestation":{"uid":"0xrevoked","schema_uid":"0xschema","attester":"` + validateAttester + `","recipient":"` + validateCreator + `",
+				"time":"2026-01-10T00:00:00Z","revocation_time":"2026-02-01T00:00:00Z"}}`), nil
+		}
+		return []byte("Error: attestation not found"), errors.New("exit status 1")
+	}
+	return s, clock
+}
+
+func createShareLink(t *testing.T, s *Server, caller, uid, body string) (*httptest.ResponseRecorder, ShareLinkResponse) {
+	t.Helper()
+	req := newJSONRequest("POST", "/api/v1/attestations/"+uid+"/share-link", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testAuthToken(t, s.config.JWTSecret, caller))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	var link ShareLinkResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &link)
+	return rec, link
+}
+
+func verifyShareLink(s *Server, token string) (*httptest.ResponseRecorder, map[string]any) {
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/verify/link/"+token, nil))
+	var out map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &out)
+	return rec, out
+}
+
+func TestShareLinkGenerationAndVerification(t *testing.T) {
+	s, _ := newShareLinkServer(t)
+
+	rec, link := createShareLink(t, s, validateCreator, "0xdiploma", "")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.HasPrefix(link.ID, "shl_") || link.ExpiresAt != nil || link.URL != s.config.ShareLinks.BaseURL+link.Token {
+		t.Errorf("link = %+v", link)
+	}
+
+	rec, out := verifyShareLink(s, link.Token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("verify = %d: %s", rec.Code, rec.Body.String())
+	}
+	if out["valid"] != true || out["status"] != "valid" || out["uid"] != "0xdiploma" || out["attester"] != validateAttester || out["recipient"] != validateCreator {
+		t.Errorf("verification = %v", out)
+	}
+	if strings.Contains(rec.Body.String(), "c2VjcmV0IGdyYWRlcw") {
+		t.Error("verification exposes attestation data")
+	}
+
+	t.Run("only the recipient can share", func(t *testing.T) {
+		if rec, _ := createShareLink(t, s, validateAttester, "0xdiploma", ""); rec.Code != http.StatusForbidden {
+			t.Errorf("status = %d, want 403", rec.Code)
+		}
+		if rec, _ := createShareLink(t, s, validateCreator, "0xmissing", ""); rec.Code != http.StatusNotFound {
+			t.Errorf("missing attestation: status = %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("tampered tokens are rejected", func(t *testing.T) {
+		payload, sig, _ := strings.Cut(link.Token, ".")
+		forged := signShareLinkToken([]byte("another secret"), shareLinkClaims{ID: link.ID, UID: "0xdiploma"})
+		for _, token := range []string{payload + "." + sig[:len(sig)-2] + "AA", forged, "not-a-token"} {
+			if rec, _ := verifyShareLink(s, token); rec.Code != http.StatusNotFound {
+				t.Errorf("token %q: status = %d, want 404", token, rec.Code)
+			}
+		}
+	})
+
+	t.Run("revoked attestations verify as revoked", func(t *testing.T) {
+		_, revoked := createShareLink(t, s, validateCreator, "0xrevoked", "")
+		rec, out := verifyShareLink(s, revoked.Token)
+		if rec.Code != http.StatusOK || out["valid"] != false || out["status"] != "revoked" || out["revocation_time"] == nil {
+			t.Errorf("verify = %d %v", rec.Code, out)
+		}
+	})
+}
+
+func TestShareLinkExpiry(t *testing.T) {
+	s, clock := newShareLinkServer(t)
+
+	rec, link := createShareLink(t, s, validateCreator, "0xdiploma", `{"expires_in":3600}`)
+	if rec.Code != http.StatusCreated || link.ExpiresAt == nil || link.ExpiresAt.Unix() != clock.Now().Add(time.Hour).Unix() {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	clock.Advance(59 * time.Minute)
+	if rec, out := verifyShareLink(s, link.Token); rec.Code != http.StatusOK || out["link_expires_at"] == nil {
+		t.Fatalf("before expiry = %d %v", rec.Code, out)
+	}
+	clock.Advance(time.Minute)
+	if rec, _ := verifyShareLink(s, link.Token); rec.Code != http.StatusGone {
+		t.Errorf("after expiry = %d, want 410", rec.Code)
+	}
+
+	if rec, _ := createShareLink(t, s, validateCreator, "0xdiploma", `{"expires_in":-1}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("negative expires_in: status = %d, want 400", rec.Code)
+	}
+	s.config.ShareLinks.MaxTTL = 24 * time.Hour
+	for _, body := range []string{"", `{"expires_in":172800}`} {
+		if rec, _ := createShareLink(t, s, validateCreator, "0xdiploma", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%q over MaxTTL: status = %d, want 400", body, rec.Code)
+		}
+	}
+}
+
+func TestShareLinkRevocation(t *testing.T) {
+	s, _ := newShareLinkServer(t)
+	_, link := createShareLink(t, s, validateCreator, "0xdiploma", "")
+	_, other := createShareLink(t, s, validateCreator, "0xdiploma", "")
+
+	revoke := func(caller, uid, id string) int {
+		req := httptest.NewRequest("DELETE", "/api/v1/attestations/"+uid+"/share-link/"+id, nil)
+		req.Header.Set("Authorization", "Bearer "+testAuthToken(t, s.config.JWTSecret, caller))
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := revoke(validateAttester, "0xdiploma", link.ID); code != http.StatusNotFound {
+		t.Errorf("revoke by another address = %d, want 404", code)
+	}
+	if code := revoke(validateCreator, "0xdiploma", link.ID); code != http.StatusNoContent {
+		t.Fatalf("revoke = %d, want 204", code)
+	}
+	if code := revoke(validateCreator, "0xdiploma", link.ID); code != http.StatusNotFound {
+		t.Errorf("second revoke = %d, want 404", code)
+	}
+
+	if rec, _ := verifyShareLink(s, link.Token); rec.Code != http.StatusGone {
+		t.Errorf("revoked link = %d, want 410", rec.Code)
+	}
+	if rec, _ := verifyShareLink(s, other.Token); rec.Code != http.StatusOK {
+		t.Errorf("other link = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Social verification code limits
+// Each /social/generate call mints a fresh C3RT- code, so generations are
+// logged and counted per address and platform over a sliding hour. Codes left
+// unverified past socialCodeTTL can no longer be verified and are deleted by a
+// background sweep.
+
+// socialCodeTTL is how long a generated code can be verified
+const socialCodeTTL = 24 * time.Hour
+
+// socialCodeWindow is the sliding window MaxPerHour is counted over
+const socialCodeWindow = time.Hour
+
+// SocialCodeConfig limits social verification code generation
+type SocialCodeConfig struct {
+	// MaxPerHour caps the codes an address can generate per platform per hour; 0 disables the limit
+	MaxPerHour int
+
+	// CleanupInterval is how often expired unverified codes are deleted; 0 disables the sweep
+	CleanupInterval time.Duration
+}
+
+// DefaultSocialCodeConfig allows five codes per platform an hour and sweeps hourly
+func DefaultSocialCodeConfig() SocialCodeConfig {
+	return SocialCodeConfig{MaxPerHour: 5, CleanupInterval: time.Hour}
+}
+
+// socialCodeStore counts code generations and deletes expired codes; *database.DB implements it
+type socialCodeStore interface {
+	RecordSocialCodeGeneration(ctx context.Context, address, platform string, at time.Time) error
+	SocialCodeGenerationsSince(ctx context.Context, address, platform string, since time.Time) (int, time.Time, error)
+	DeleteStaleSocialVerifications(ctx context.Context, before time.Time) (int64, error)
+}
+
+// allowSocialCode responds 429 with a Retry-After hint and returns false when
+// address has generated MaxPerHour codes for platform within the last hour
+func (s *Server) allowSocialCode(w http.ResponseWriter, r *http.Request, address, platform string) bool {
+	limit := s.config.SocialCodes.MaxPerHour
+	if limit <= 0 {
+		return true
+	}
+	now := s.clock.Now()
+	count, oldest, err := s.socialCodes.SocialCodeGenerationsSince(r.Context(), address, platform, now.Add(-socialCodeWindow))
+	if err != nil {
+		s.logger.Error("failed to count social code generations", zap.Error(err))
+		s.respondJSON(w, http.StatusInternalServerError, socialGenerateResponse{Error: "failed to create verification"})
+		return false
+	}
+	if count < limit {
+		return true
+	}
+	retryAfter := int(math.Ceil(oldest.Add(socialCodeWindow).Sub(now).Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(maxInt(retryAfter, 1)))
+	s.respondJSON(w, http.StatusTooManyRequests, socialGenerateResponse{Error: "too many verification codes generated, try again later"})
+	return false
+}
+
+// cleanupSocialCodes deletes unverified codes that are past socialCodeTTL
+func (s *Server) cleanupSocialCodes(ctx context.Context) (int64, error) {
+	return s.socialCodes.DeleteStaleSocialVerifications(ctx, s.clock.Now().Add(-socialCodeTTL))
+}
+
+// watchSocialCodeCleanup sweeps expired codes every interval until ctx is cancelled
+func (s *Server) watchSocialCodeCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.cleanupSocialCodes(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("social code cleanup failed", zap.Error(err))
+		} else if n > 0 {
+			s.logger.Info("deleted expired social verification codes", zap.Int64("count", n))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// stubSocialCodeStore keeps generation times per address and platform
+type stubSocialCodeStore struct {
+	generations map[string][]time.Time
+	cutoff      time.Time // Last DeleteStaleSocialVerifications cutoff
+}
+
+func (m *stubSocialCodeStore) RecordSocialCodeGeneration(_ context.Context, address, platform string, at time.Time) error {
+	m.generations[address+"|"+platform] = append(m.generations[address+"|"+platform], at)
+	return nil
+}
+
+func (m *stubSocialCodeStore) SocialCodeGenerationsSince(_ context.Context, address, platform string, since time.Time) (int, time.Time, error) {
+	var count int
+	var oldest time.Time
+	for _, at := range m.generations[address+"|"+platform] {
+		if !at.After(since) {
+			continue
+		}
+		if count == 0 || at.Before(oldest) {
+			oldest = at
+		}
+		count++
+	}
+	return count, oldest, nil
+}
+
+func (m *stubSocialCodeStore) DeleteStaleSocialVerifications(_ context.Context, before time.Time) (int64, error) {
+	m.cutoff = before
+	var deleted int64
+	for key, times := range m.generations {
+		var kept []time.Time
+		for _, at := range times {
+			if at.Before(before) {
+				deleted++
+			} else {
+				kept = append(kept, at)
+			}
+		}
+		m.generations[key] = kept
+	}
+	return deleted, nil
+}
+
+func newSocialCodeTestServer(t *testing.T) (*Server, *stubSocialCodeStore, *fakeClock) {
+	t.Helper()
+	clock := newFakeClock(time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC))
+	config := DefaultConfig()
+	config.Clock = clock
+	s := NewServer(config, zap.NewNop())
+	store := &stubSocialCodeStore{generations: map[string][]time.Time{}}
+	s.socialCodes = store
+	return s, store, clock
+}
+
+func generateSocialCode(t *testing.T, s *Server, platform string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/v1/social/generate", strings.NewReader(`{"platform":"`+platform+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAuthToken(t, s.config.JWTSecret, oauthTestAddress))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSocialGenerateRateLimit(t *testing.T) {
+	s, store, clock := newSocialCodeTestServer(t)
+	now := clock.Now()
+	key := oauthTestAddress + "|twitter"
+	store.generations[key] = []time.Time{
+		now.Add(-70 * time.Minute), // Outside the window
+		now.Add(-40 * time.Minute),
+		now.Add(-30 * time.Minute),
+		now.Add(-20 * time.Minute),
+		now.Add(-10 * time.Minute),
+	}
+
+	// Under the limit the request gets past the check, to the missing database
+	if rec := generateSocialCode(t, s, "twitter"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("4 codes in the last hour: generate = %d, want 503: %s", rec.Code, rec.Body.String())
+	}
+
+	store.generations[key] = append(store.generations[key], now.Add(-5*time.Minute))
+	rec := generateSocialCode(t, s, "twitter")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("5 codes in the last hour: generate = %d, want 429", rec.Code)
+	}
+	// The oldest code in the window leaves it in 20 minutes
+	if got := rec.Header().Get("Retry-After"); got != "1200" {
+		t.Errorf("Retry-After = %q, want 1200", got)
+	}
+
+	// The limit is per platform
+	if rec := generateSocialCode(t, s, "github"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("other platform: generate = %d, want 503", rec.Code)
+	}
+
+	clock.Advance(20 * time.Minute)
+	if rec := generateSocialCode(t, s, "twitter"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("after the oldest code left the window: generate = %d, want 503", rec.Code)
+	}
+}
+
+func TestSocialGenerateRateLimitDisabled(t *testing.T) {
+	s, store, clock := newSocialCodeTestServer(t)
+	s.config.SocialCodes.MaxPerHour = 0
+	for i := 0; i < 10; i++ {
+		store.generations[oauthTestAddress+"|twitter"] = append(store.generations[oauthTestAddress+"|twitter"], clock.Now())
+	}
+	if rec := generateSocialCode(t, s, "twitter"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("generate = %d, want 503", rec.Code)
+	}
+}
+
+func TestCleanupSocialCodes(t *testing.T) {
+	s, store, clock := newSocialCodeTestServer(t)
+	now := clock.Now()
+	store.generations["a|twitter"] = []time.Time{now.Add(-25 * time.Hour), now.Add(-23 * time.Hour)}
+
+	deleted, err := s.cleanupSocialCodes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !store.cutoff.Equal(now.Add(-socialCodeTTL)) {
+		t.Errorf("cutoff = %v, want %v", store.cutoff, now.Add(-socialCodeTTL))
+	}
+	if deleted != 1 || len(store.generations["a|twitter"]) != 1 {
+		t.Errorf("deleted %d, kept %v; want the 25h-old row removed", deleted, store.generations["a|twitter"])
+	}
+}
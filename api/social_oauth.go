@@ -0,0 +1,472 @@
+package api
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/chaincertify/certd/api/database"
+)
+
+// Social OAuth verification
+// X and LinkedIn block the bot fetches behind post verification, so their
+// accounts can instead be verified by signing in: /social/oauth/{platform}/start
+// returns the provider's authorize URL, and the provider redirects the user's
+// browser to the app, which hands the code and state to
+// /social/oauth/{platform}/callback. The callback exchanges the code, reads the
+// authenticated profile and marks the account verified with its real handle.
+// Provider tokens are stored AES-256-GCM encrypted under SocialOAuthConfig.TokenKey.
+
+// socialOAuthStateTTL is how long a started authorization can be completed
+const socialOAuthStateTTL = 10 * time.Minute
+
+// OAuthProvider is one platform's OAuth 2.0 client registration and endpoints
+type OAuthProvider struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // Registered redirect URI; the app there forwards code and state to the callback
+	AuthorizeURL string
+	TokenURL     string
+	ProfileURL   string
+	Scopes       []string
+	PKCE         bool // Send an S256 code challenge (required by X)
+	SecretInBody bool // Send client_secret as a form field instead of HTTP Basic auth
+}
+
+// SocialOAuthConfig configures OAuth social verification
+type SocialOAuthConfig struct {
+	// Providers by platform; a provider without a client ID or redirect URL is disabled
+	Providers map[string]OAuthProvider
+	// TokenKey is the 32-byte AES key provider tokens are encrypted under; without it OAuth is disabled
+	TokenKey []byte
+}
+
+// DefaultSocialOAuthConfig returns the X and LinkedIn endpoints; both stay
+// disabled until client credentials, redirect URLs and a token key are set
+func DefaultSocialOAuthConfig() SocialOAuthConfig {
+	return SocialOAuthConfig{Providers: map[string]OAuthProvider{
+		"twitter": {
+			AuthorizeURL: "https://twitter.com/i/oauth2/authorize",
+			TokenURL:     "https://api.twitter.com/2/oauth2/token",
+			ProfileURL:   "https://api.twitter.com/2/users/me",
+			Scopes:       []string{"users.read", "tweet.read"},
+			PKCE:         true,
+		},
+		"linkedin": {
+			AuthorizeURL: "https://www.linkedin.com/oauth/v2/authorization",
+			TokenURL:     "https://www.linkedin.com/oauth/v2/accessToken",
+			ProfileURL:   "https://api.linkedin.com/v2/userinfo",
+			Scopes:       []string{"openid", "profile"},
+			SecretInBody: true,
+		},
+	}}
+}
+
+// oauthProfile is the authenticated account read from a provider
+type oauthProfile struct {
+	ID         string
+	Handle     string
+	ProfileURL string
+}
+
+// oauthProfileParsers decode each platform's profile response
+var oauthProfileParsers = map[string]func([]byte) (oauthProfile, error){
+	"twitter": func(body []byte) (oauthProfile, error) {
+		var res struct {
+			Data struct {
+				ID       string `json:"id"`
+				Username string `json:"username"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &res); err != nil {
+			return oauthProfile{}, err
+		}
+		if res.Data.ID == "" || res.Data.Username == "" {
+			return oauthProfile{}, errors.New("profile has no id or username")
+		}
+		return oauthProfile{ID: res.Data.ID, Handle: res.Data.Username, ProfileURL: "https://x.com/" + res.Data.Username}, nil
+	},
+	// LinkedIn's OpenID userinfo has no public vanity name; the display name is the handle
+	"linkedin": func(body []byte) (oauthProfile, error) {
+		var res struct {
+			Sub  string `json:"sub"`
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(body, &res); err != nil {
+			return oauthProfile{}, err
+		}
+		if res.Sub == "" || res.Name == "" {
+			return oauthProfile{}, errors.New("profile has no sub or name")
+		}
+		return oauthProfile{ID: res.Sub, Handle: res.Name}, nil
+	},
+}
+
+// socialOAuthStore persists pending authorizations and OAuth-verified accounts
+type socialOAuthStore interface {
+	CreateSocialOAuthState(ctx context.Context, st *database.SocialOAuthState) error
+	ConsumeSocialOAuthState(ctx context.Context, state string, at time.Time) (*database.SocialOAuthState, error)
+	CompleteSocialOAuth(ctx context.Context, acct *database.SocialOAuthAccount) error
+}
+
+// memorySocialOAuthStore keeps OAuth state in memory (used when no database is configured)
+type memorySocialOAuthStore struct {
+	mu       sync.Mutex
+	states   map[string]database.SocialOAuthState
+	accounts map[string]database.SocialOAuthAccount // Keyed by address|platform
+}
+
+func newMemorySocialOAuthStore() *memorySocialOAuthStore {
+	return &memorySocialOAuthStore{
+		states:   make(map[string]database.SocialOAuthState),
+		accounts: make(map[string]database.SocialOAuthAccount),
+	}
+}
+
+func (m *memorySocialOAuthStore) CreateSocialOAuthState(_ context.Context, st *database.SocialOAuthState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for state, existing := range m.states {
+		if existing.ExpiresAt.Before(st.CreatedAt) {
+			delete(m.states, state)
+		}
+	}
+	m.states[st.State] = *st
+	return nil
+}
+
+func (m *memorySocialOAuthStore) ConsumeSocialOAuthState(_ context.Context, state string, at time.Time) (*database.SocialOAuthState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st, ok := m.states[state]
+	if !ok || st.UsedAt != nil || !at.Before(st.ExpiresAt) {
+		return nil, nil
+	}
+	st.UsedAt = &at
+	m.states[state] = st
+	return &st, nil
+}
+
+func (m *memorySocialOAuthStore) CompleteSocialOAuth(_ context.Context, acct *database.SocialOAuthAccount) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accounts[acct.UserAddress+"|"+acct.Platform] = *acct
+	return nil
+}
+
+// oauthProvider returns the platform's provider if OAuth is usable for it
+func (s *Server) oauthProvider(platform string) (OAuthProvider, bool) {
+	cfg := s.config.SocialOAuth
+	p, ok := cfg.Providers[platform]
+	if !ok || p.ClientID == "" || p.RedirectURL == "" || len(cfg.TokenKey) != 32 {
+		return OAuthProvider{}, false
+	}
+	return p, true
+}
+
+type socialOAuthStartResponse struct {
+	AuthorizeURL string    `json:"authorize_url,omitempty"`
+	State        string    `json:"state,omitempty"`
+	ExpiresAt    Timestamp `json:"expires_at,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// handleSocialOAuthStart starts OAuth verification of the caller's account
+// GET /api/v1/social/oauth/{platform}/start
+func (s *Server) handleSocialOAuthStart(w http.ResponseWriter, r *http.Request) {
+	address := getAuthenticatedAddress(r)
+	if address == "" {
+		s.respondJSON(w, http.StatusUnauthorized, socialOAuthStartResponse{Error: "authentication required"})
+		return
+	}
+	platform := strings.ToLower(mux.Vars(r)["platform"])
+	if _, ok := oauthProfileParsers[platform]; !ok {
+		s.respondJSON(w, http.StatusBadRequest, socialOAuthStartResponse{Error: "unsupported platform"})
+		return
+	}
+	provider, ok := s.oauthProvider(platform)
+	if !ok {
+		s.respondJSON(w, http.StatusServiceUnavailable, socialOAuthStartResponse{Error: "oauth verification is not configured for this platform"})
+		return
+	}
+
+	state, err := randomURLToken(32)
+	if err != nil {
+		s.respondJSON(w, http.StatusInternalServerError, socialOAuthStartResponse{Error: "failed to start authorization"})
+		return
+	}
+	var verifier string
+	if provider.PKCE {
+		if verifier, err = randomURLToken(32); err != nil {
+			s.respondJSON(w, http.StatusInternalServerError, socialOAuthStartResponse{Error: "failed to start authorization"})
+			return
+		}
+	}
+	now := s.clock.Now()
+	st := &database.SocialOAuthState{
+		State:        state,
+		UserAddress:  address,
+		Platform:     platform,
+		CodeVerifier: verifier,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(socialOAuthStateTTL),
+	}
+	if err := s.socialOAuth.CreateSocialOAuthState(r.Context(), st); err != nil {
+		s.logger.Error("failed to store oauth state", zap.Error(err))
+		s.respondJSON(w, http.StatusInternalServerError, socialOAuthStartResponse{Error: "failed to start authorization"})
+		return
+	}
+
+	q := url.Values{
+		"response_type": {"code"},
+		"client_id":     {provider.ClientID},
+		"redirect_uri":  {provider.RedirectURL},
+		"scope":         {strings.Join(provider.Scopes, " ")},
+		"state":         {state},
+	}
+	if provider.PKCE {
+		challenge := sha256.Sum256([]byte(verifier))
+		q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+		q.Set("code_challenge_method", "S256")
+	}
+	s.respondJSON(w, http.StatusOK, socialOAuthStartResponse{
+		AuthorizeURL: provider.AuthorizeURL + "?" + q.Encode(),
+		State:        state,
+		ExpiresAt:    NewTimestamp(st.ExpiresAt),
+	})
+}
+
+type socialOAuthCallbackResponse struct {
+	OK       bool   `json:"ok"`
+	Platform string `json:"platform,omitempty"`
+	Handle   string `json:"handle,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// handleSocialOAuthCallback completes OAuth verification; the state identifies
+// the address that started it, so no session is needed
+// GET /api/v1/social/oauth/{platform}/callback?code=...&state=...
+func (s *Server) handleSocialOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	platform := strings.ToLower(mux.Vars(r)["platform"])
+	provider, ok := s.oauthProvider(platform)
+	if !ok {
+		s.respondJSON(w, http.StatusServiceUnavailable, socialOAuthCallbackResponse{Error: "oauth verification is not configured for this platform"})
+		return
+	}
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		s.respondJSON(w, http.StatusBadRequest, socialOAuthCallbackResponse{Error: "authorization was not granted: " + e})
+		return
+	}
+	code, state := q.Get("code"), q.Get("state")
+	if code == "" || state == "" {
+		s.respondJSON(w, http.StatusBadRequest, socialOAuthCallbackResponse{Error: "code and state are required"})
+		return
+	}
+
+	ctx := r.Context()
+	now := s.clock.Now()
+	st, err := s.socialOAuth.ConsumeSocialOAuthState(ctx, state, now)
+	if err != nil {
+		s.logger.Error("failed to consume oauth state", zap.Error(err))
+		s.respondJSON(w, http.StatusInternalServerError, socialOAuthCallbackResponse{Error: "failed to complete verification"})
+		return
+	}
+	if st == nil || st.Platform != platform {
+		s.respondJSON(w, http.StatusBadRequest, socialOAuthCallbackResponse{Error: "invalid or expired state. Please start again."})
+		return
+	}
+
+	token, err := exchangeOAuthCode(ctx, provider, code, st.CodeVerifier)
+	if err != nil {
+		s.logger.Warn("oauth code exchange failed", zap.String("platform", platform), zap.Error(err))
+		s.respondJSON(w, http.StatusBadGateway, socialOAuthCallbackResponse{Error: "could not exchange authorization code"})
+		return
+	}
+	profile, err := fetchOAuthProfile(ctx, provider, token.AccessToken, oauthProfileParsers[platform])
+	if err != nil {
+		s.logger.Warn("oauth profile fetch failed", zap.String("platform", platform), zap.Error(err))
+		s.respondJSON(w, http.StatusBadGateway, socialOAuthCallbackResponse{Error: "could not read the authenticated profile"})
+		return
+	}
+
+	acct := &database.SocialOAuthAccount{
+		UserAddress:    st.UserAddress,
+		Platform:       platform,
+		ProviderUserID: profile.ID,
+		Handle:         profile.Handle,
+		ProfileURL:     profile.ProfileURL,
+		VerifiedAt:     now,
+	}
+	if acct.AccessToken, err = sealOAuthToken(s.config.SocialOAuth.TokenKey, token.AccessToken); err == nil && token.RefreshToken != "" {
+		acct.RefreshToken, err = sealOAuthToken(s.config.SocialOAuth.TokenKey, token.RefreshToken)
+	}
+	if err != nil {
+		s.logger.Error("failed to encrypt oauth tokens", zap.Error(err))
+		s.respondJSON(w, http.StatusInternalServerError, socialOAuthCallbackResponse{Error: "failed to complete verification"})
+		return
+	}
+	if token.ExpiresIn > 0 {
+		expires := now.Add(time.Duration(token.ExpiresIn) * time.Second)
+		acct.TokenExpiresAt = &expires
+	}
+	if err := s.socialOAuth.CompleteSocialOAuth(ctx, acct); err != nil {
+		s.logger.Error("failed to complete oauth verification", zap.Error(err))
+		s.respondJSON(w, http.StatusInternalServerError, socialOAuthCallbackResponse{Error: "failed to complete verification"})
+		return
+	}
+
+	s.logger.Info("social oauth verification complete", zap.String("address", st.UserAddress), zap.String("platform", platform))
+	s.respondJSON(w, http.StatusOK, socialOAuthCallbackResponse{OK: true, Platform: platform, Handle: profile.Handle})
+}
+
+// oauthToken is a provider's token endpoint response
+type oauthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// oauthHTTPClient is used for token and profile requests. Code exchanges are
+// not retried, as providers accept an authorization code once.
+var oauthHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// exchangeOAuthCode redeems an authorization code at the provider's token endpoint
+func exchangeOAuthCode(ctx context.Context, p OAuthProvider, code, verifier string) (oauthToken, error) {
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {p.RedirectURL},
+		"client_id":    {p.ClientID},
+	}
+	if verifier != "" {
+		form.Set("code_verifier", verifier)
+	}
+	if p.SecretInBody {
+		form.Set("client_secret", p.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauthToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if !p.SecretInBody && p.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	}
+
+	var token oauthToken
+	if err := doOAuthRequest(req, &token); err != nil {
+		return oauthToken{}, err
+	}
+	if token.AccessToken == "" {
+		return oauthToken{}, errors.New("token response has no access_token")
+	}
+	return token, nil
+}
+
+// fetchOAuthProfile reads the authenticated account with an access token
+func fetchOAuthProfile(ctx context.Context, p OAuthProvider, accessToken string, parse func([]byte) (oauthProfile, error)) (oauthProfile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.ProfileURL, nil)
+	if err != nil {
+		return oauthProfile{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+	var body json.RawMessage
+	if err := doOAuthRequest(req, &body); err != nil {
+		return oauthProfile{}, err
+	}
+	return parse(body)
+}
+
+// doOAuthRequest sends req and decodes a 200 JSON response into out
+func doOAuthRequest(req *http.Request, out any) error {
+	resp, err := oauthHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// randomURLToken returns n random bytes, base64url encoded
+func randomURLToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// sealOAuthToken encrypts a provider token as nonce || AES-256-GCM ciphertext
+func sealOAuthToken(key []byte, token string) ([]byte, error) {
+	gcm, err := oauthTokenCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, []byte(token), nil), nil
+}
+
+// openOAuthToken decrypts a token sealed by sealOAuthToken
+func openOAuthToken(key, sealed []byte) (string, error) {
+	gcm, err := oauthTokenCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("sealed token is too short")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func oauthTokenCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ParseSocialOAuthTokenKey decodes a hex-encoded 32-byte token key
+func ParseSocialOAuthTokenKey(v string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimPrefix(v, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("token key must be hex: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("token key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
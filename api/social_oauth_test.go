@@ -0,0 +1,230 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const oauthTestAddress = "0x00000000000000000000000000000000000a11ce"
+
+// mockXOAuth serves X's token and users/me endpoints, checking the code
+// exchange against the PKCE challenge sent to the authorize URL
+type mockXOAuth struct {
+	challenge string // Set from the authorize URL by the test
+	exchanges int
+}
+
+func (m *mockXOAuth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/2/oauth2/token":
+		m.exchanges++
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "x-client" || secret != "x-secret" {
+			http.Error(w, `{"error":"unauthorized_client"}`, http.StatusUnauthorized)
+			return
+		}
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != "auth-code" || r.PostForm.Get("grant_type") != "authorization_code" ||
+			base64.RawURLEncoding.EncodeToString(sum[:]) != m.challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+			"token_type":    "bearer",
+			"access_token":  "x-access-token",
+			"refresh_token": "x-refresh-token",
+			"expires_in":    7200,
+		})
+	case "/2/users/me":
+		if r.Header.Get("Authorization") != "Bearer x-access-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":{"id":"1468432","name":"Alice","username":"alice_onchain"}}`)) //nolint:errcheck
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newOAuthTestServer(t *testing.T, providerURL string) (*Server, *fakeClock) {
+	t.Helper()
+	clock := newFakeClock(time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC))
+	config := DefaultConfig()
+	config.Clock = clock
+	config.SocialOAuth.TokenKey = bytes.Repeat([]byte{7}, 32)
+	config.SocialOAuth.Providers["twitter"] = OAuthProvider{
+		ClientID:     "x-client",
+		ClientSecret: "x-secret",
+		RedirectURL:  "https://app.c3rt.org/social/callback/twitter",
+		AuthorizeURL: providerURL + "/i/oauth2/authorize",
+		TokenURL:     providerURL + "/2/oauth2/token",
+		ProfileURL:   providerURL + "/2/users/me",
+		Scopes:       []string{"users.read", "tweet.read"},
+		PKCE:         true,
+	}
+	return NewServer(config, zap.NewNop()), clock
+}
+
+// startOAuth calls /start as oauthTestAddress and returns the authorize URL
+func startOAuth(t *testing.T, s *Server, platform string) *url.URL {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/api/v1/social/oauth/"+platform+"/start", nil)
+	req.Header.Set("Authorization", "Bearer "+testAuthToken(t, s.config.JWTSecret, oauthTestAddress))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("start = %d: %s", rec.Code, rec.Body.String())
+	}
+	var res socialOAuthStartResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(res.AuthorizeURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func oauthCallback(s *Server, platform, query string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/social/oauth/"+platform+"/callback?"+query, nil))
+	return rec
+}
+
+func TestSocialOAuthXFlow(t *testing.T) {
+	mock := &mockXOAuth{}
+	provider := httptest.NewServer(mock)
+	defer provider.Close()
+	s, _ := newOAuthTestServer(t, provider.URL)
+
+	authorize := startOAuth(t, s, "twitter")
+	q := authorize.Query()
+	if !strings.HasPrefix(authorize.String(), provider.URL+"/i/oauth2/authorize?") ||
+		q.Get("client_id") != "x-client" || q.Get("redirect_uri") != "https://app.c3rt.org/social/callback/twitter" ||
+		q.Get("scope") != "users.read tweet.read" || q.Get("code_challenge_method") != "S256" || q.Get("state") == "" {
+		t.Fatalf("authorize URL = %s", authorize)
+	}
+	mock.challenge = q.Get("code_challenge")
+	state := q.Get("state")
+
+	rec := oauthCallback(s, "twitter", url.Values{"code": {"auth-code"}, "state": {state}}.Encode())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("callback = %d: %s", rec.Code, rec.Body.String())
+	}
+	var res socialOAuthCallbackResponse
+	json.Unmarshal(rec.Body.Bytes(), &res) //nolint:errcheck
+	if !res.OK || res.Handle != "alice_onchain" {
+		t.Errorf("callback response = %+v", res)
+	}
+
+	store := s.socialOAuth.(*memorySocialOAuthStore)
+	acct, ok := store.accounts[oauthTestAddress+"|twitter"]
+	if !ok {
+		t.Fatal("account was not marked verified")
+	}
+	if acct.Handle != "alice_onchain" || acct.ProviderUserID != "1468432" || acct.ProfileURL != "https://x.com/alice_onchain" {
+		t.Errorf("account = %+v", acct)
+	}
+	if acct.TokenExpiresAt == nil || !acct.TokenExpiresAt.Equal(s.clock.Now().Add(2*time.Hour)) {
+		t.Errorf("token expiry = %v", acct.TokenExpiresAt)
+	}
+
+	// Tokens are stored encrypted under the token key
+	if bytes.Contains(acct.AccessToken, []byte("x-access-token")) || bytes.Contains(acct.RefreshToken, []byte("x-refresh-token")) {
+		t.Error("tokens stored in plaintext")
+	}
+	if token, err := openOAuthToken(s.config.SocialOAuth.TokenKey, acct.AccessToken); err != nil || token != "x-access-token" {
+		t.Errorf("access token decrypts to %q, %v", token, err)
+	}
+	if _, err := openOAuthToken(bytes.Repeat([]byte{8}, 32), acct.AccessToken); err == nil {
+		t.Error("access token opened with the wrong key")
+	}
+
+	// A state is accepted once
+	if rec := oauthCallback(s, "twitter", url.Values{"code": {"auth-code"}, "state": {state}}.Encode()); rec.Code != http.StatusBadRequest {
+		t.Errorf("replayed callback = %d, want 400", rec.Code)
+	}
+	if mock.exchanges != 1 {
+		t.Errorf("code exchanges = %d, want 1", mock.exchanges)
+	}
+}
+
+func TestSocialOAuthCallbackRejections(t *testing.T) {
+	mock := &mockXOAuth{}
+	provider := httptest.NewServer(mock)
+	defer provider.Close()
+
+	t.Run("expired state", func(t *testing.T) {
+		s, clock := newOAuthTestServer(t, provider.URL)
+		state := startOAuth(t, s, "twitter").Query().Get("state")
+		clock.Advance(socialOAuthStateTTL)
+		if rec := oauthCallback(s, "twitter", "code=auth-code&state="+state); rec.Code != http.StatusBadRequest {
+			t.Errorf("callback = %d, want 400", rec.Code)
+		}
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		s, _ := newOAuthTestServer(t, provider.URL)
+		if rec := oauthCallback(s, "twitter", "code=auth-code&state=forged"); rec.Code != http.StatusBadRequest {
+			t.Errorf("callback = %d, want 400", rec.Code)
+		}
+	})
+
+	t.Run("denied authorization", func(t *testing.T) {
+		s, _ := newOAuthTestServer(t, provider.URL)
+		if rec := oauthCallback(s, "twitter", "error=access_denied&state=x"); rec.Code != http.StatusBadRequest {
+			t.Errorf("callback = %d, want 400", rec.Code)
+		}
+	})
+
+	t.Run("failed code exchange", func(t *testing.T) {
+		s, _ := newOAuthTestServer(t, provider.URL)
+		authorize := startOAuth(t, s, "twitter")
+		mock.challenge = authorize.Query().Get("code_challenge")
+		rec := oauthCallback(s, "twitter", "code=wrong-code&state="+authorize.Query().Get("state"))
+		if rec.Code != http.StatusBadGateway {
+			t.Errorf("callback = %d, want 502", rec.Code)
+		}
+		if n := len(s.socialOAuth.(*memorySocialOAuthStore).accounts); n != 0 {
+			t.Errorf("%d accounts verified after a failed exchange", n)
+		}
+	})
+}
+
+func TestSocialOAuthStartRequiresConfiguration(t *testing.T) {
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	token := testAuthToken(t, s.config.JWTSecret, oauthTestAddress)
+	for path, want := range map[string]int{
+		"/api/v1/social/oauth/twitter/start":  http.StatusServiceUnavailable, // No client credentials or token key
+		"/api/v1/social/oauth/facebook/start": http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/social/oauth/twitter/start", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated start = %d, want 401", rec.Code)
+	}
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/chaincertify/certd/api/retry"
+)
+
+// Verification post fetching
+// Post verification GETs a user-supplied URL, so every hop is checked before
+// it is requested: https only, a host on the platform's exact allowlist, and
+// DNS answers that are all public addresses. The dialer re-checks the address
+// it actually connects to, so a host that re-resolves to an internal address
+// between the check and the connection is still refused.
+
+const (
+	// maxPostRedirects is how many redirects a post fetch follows
+	maxPostRedirects = 2
+
+	// maxPostBodyBytes caps how much of a post page is read
+	maxPostBodyBytes = 1024 * 1024
+
+	// socialPostTimeout bounds a whole fetch, retries and redirects included
+	socialPostTimeout = 15 * time.Second
+)
+
+// errBlockedPostURL rejects post URLs and redirect targets that fail the checks
+var errBlockedPostURL = errors.New("post URL is not allowed")
+
+// platformPostHosts are the exact hosts a platform's posts are fetched from
+var platformPostHosts = map[string][]string{
+	"twitter":  {"x.com", "www.x.com", "twitter.com", "www.twitter.com", "mobile.twitter.com", "mobile.x.com"},
+	"linkedin": {"linkedin.com", "www.linkedin.com"},
+	"facebook": {"facebook.com", "www.facebook.com", "m.facebook.com"},
+	"github":   {"gist.github.com"},
+}
+
+// parsePlatformURL parses rawURL, requiring https, no credentials, the default
+// port and a host allowed for platform
+func parsePlatformURL(platform, rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errBlockedPostURL, err)
+	}
+	if err := checkPlatformHost(platform, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func checkPlatformHost(platform string, u *url.URL) error {
+	if u.Scheme != "https" || u.User != nil {
+		return fmt.Errorf("%w: %s must be https without credentials", errBlockedPostURL, u.Redacted())
+	}
+	if port := u.Port(); port != "" && port != "443" {
+		return fmt.Errorf("%w: port %s", errBlockedPostURL, port)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	for _, allowed := range platformPostHosts[platform] {
+		if host == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: host %q is not a %s host", errBlockedPostURL, u.Hostname(), platform)
+}
+
+// isValidPlatformURL validates that the URL is a post on the expected platform
+func isValidPlatformURL(platform, rawURL string) bool {
+	_, err := parsePlatformURL(platform, rawURL)
+	return err == nil
+}
+
+// isPublicIP reports whether ip is a globally routable unicast address
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, block := range nonPublicBlocks {
+		if block.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// nonPublicBlocks are reserved ranges net.IP has no predicate for
+var nonPublicBlocks = func() []*net.IPNet {
+	var blocks []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",     // "This" network
+		"100.64.0.0/10", // Carrier-grade NAT
+		"192.0.0.0/24",  // IETF protocol assignments
+		"198.18.0.0/15", // Benchmarking
+		"240.0.0.0/4",   // Reserved
+		"64:ff9b::/96",  // NAT64, which can reach IPv4 internals
+	} {
+		_, block, _ := net.ParseCIDR(cidr)
+		blocks = append(blocks, block)
+	}
+	return blocks
+}()
+
+// socialPostFetcher fetches verification posts under the SSRF checks
+type socialPostFetcher struct {
+	transport http.RoundTripper
+	// lookupIP resolves hosts for the pre-request check
+	lookupIP func(ctx context.Context, host string) ([]net.IP, error)
+}
+
+func newSocialPostFetcher() *socialPostFetcher {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: connection to %s", errBlockedPostURL, host)
+			}
+			return nil
+		},
+	}
+	return &socialPostFetcher{
+		transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		lookupIP: func(ctx context.Context, host string) ([]net.IP, error) {
+			return net.DefaultResolver.LookupIP(ctx, "ip", host)
+		},
+	}
+}
+
+// checkURL checks u's host against platform and every address it resolves to
+func (f *socialPostFetcher) checkURL(ctx context.Context, platform string, u *url.URL) error {
+	if err := checkPlatformHost(platform, u); err != nil {
+		return err
+	}
+	ips, err := f.lookupIP(ctx, u.Hostname())
+	if err != nil {
+		return err
+	}
+	if len(ips) == 0 {
+		return fmt.Errorf("%s has no addresses", u.Hostname())
+	}
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return fmt.Errorf("%w: %s resolves to %s", errBlockedPostURL, u.Hostname(), ip)
+		}
+	}
+	return nil
+}
+
+// socialRetry retries post fetches that fail on the network, 429 or 5xx, but
+// never a blocked URL
+var socialRetry = func() retry.Policy {
+	p := retry.DefaultPolicy()
+	p.Retryable = func(err error) bool {
+		return retry.IsTransient(err) && !errors.Is(err, errBlockedPostURL)
+	}
+	return p
+}()
+
+// fetchAndVerifyPost fetches a platform post and checks if it contains the verification code
+func (f *socialPostFetcher) fetchAndVerifyPost(ctx context.Context, platform, rawURL, code string) (bool, error) {
+	u, err := parsePlatformURL(platform, rawURL)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, socialPostTimeout)
+	defer cancel()
+	if err := f.checkURL(ctx, platform, u); err != nil {
+		return false, err
+	}
+
+	client := &http.Client{
+		Transport: f.transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxPostRedirects {
+				return fmt.Errorf("%w: more than %d redirects", errBlockedPostURL, maxPostRedirects)
+			}
+			return f.checkURL(req.Context(), platform, req.URL)
+		},
+	}
+	resp, err := retry.DoHTTP(ctx, socialRetry, client, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// Set user agent to avoid blocks
+		req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; C3RT-Bot/1.0)")
+		return req, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPostBodyBytes))
+	if err != nil {
+		return false, err
+	}
+
+	// Check if code is present in the page
+	return strings.Contains(string(body), code), nil
+}
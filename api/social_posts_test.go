@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestIsValidPlatformURL(t *testing.T) {
+	tests := []struct {
+		platform string
+		url      string
+		want     bool
+	}{
+		{"twitter", "https://x.com/alice/status/1", true},
+		{"twitter", "https://twitter.com/alice/status/1", true},
+		{"twitter", "https://X.com./alice/status/1", true},
+		{"linkedin", "https://www.linkedin.com/posts/alice-1", true},
+		{"github", "https://gist.github.com/alice/abc123", true},
+		{"twitter", "http://x.com/alice/status/1", false},
+		{"twitter", "https://x.com.evil.example/alice", false},
+		{"twitter", "https://evil.example/x.com/alice", false},
+		{"twitter", "https://x.com@evil.example/alice", false},
+		{"twitter", "https://x.com:8443/alice", false},
+		{"twitter", "https://169.254.169.254/latest/meta-data/", false},
+		{"github", "https://github.com/alice", false},
+		{"linkedin", "https://x.com/alice/status/1", false},
+		{"discord", "https://discord.com/channels/1", false},
+	}
+	for _, tt := range tests {
+		if got := isValidPlatformURL(tt.platform, tt.url); got != tt.want {
+			t.Errorf("isValidPlatformURL(%s, %s) = %v, want %v", tt.platform, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"104.244.42.1":    true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fd00::1":         false,
+		"fe80::1":         false,
+		"::ffff:10.0.0.1": false,
+		"64:ff9b::a00:1":  false,
+	} {
+		if got := isPublicIP(net.ParseIP(addr)); got != want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+// newTestPostFetcher returns a fetcher that resolves hosts through addrs and
+// connects every request to srv
+func newTestPostFetcher(srv *httptest.Server, addrs map[string]string) *socialPostFetcher {
+	transport := srv.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // test server certificate
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}
+	return &socialPostFetcher{
+		transport: transport,
+		lookupIP: func(_ context.Context, host string) ([]net.IP, error) {
+			addr, ok := addrs[host]
+			if !ok {
+				return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+			}
+			return []net.IP{net.ParseIP(addr)}, nil
+		},
+	}
+}
+
+func TestFetchAndVerifyPostValidXURL(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "x.com" || r.URL.Path != "/alice/status/1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("<html>Verification code: C3RT-ABCDEF</html>")) //nolint:errcheck
+	}))
+	defer srv.Close()
+	f := newTestPostFetcher(srv, map[string]string{"x.com": "104.244.42.1"})
+
+	found, err := f.fetchAndVerifyPost(context.Background(), "twitter", "https://x.com/alice/status/1", "C3RT-ABCDEF")
+	if err != nil || !found {
+		t.Fatalf("fetchAndVerifyPost = %v, %v; want the code found", found, err)
+	}
+	found, err = f.fetchAndVerifyPost(context.Background(), "twitter", "https://x.com/alice/status/1", "C3RT-000000")
+	if err != nil || found {
+		t.Errorf("other code: fetchAndVerifyPost = %v, %v; want not found", found, err)
+	}
+}
+
+func TestFetchAndVerifyPostBlocksMetadataEndpoint(t *testing.T) {
+	var hits int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte("C3RT-ABCDEF")) //nolint:errcheck
+	}))
+	defer srv.Close()
+	f := newTestPostFetcher(srv, map[string]string{
+		"169.254.169.254": "169.254.169.254",
+		"x.com":           "169.254.169.254", // An allowed host pointed at the metadata service
+	})
+
+	for _, rawURL := range []string{
+		"https://169.254.169.254/latest/meta-data/",
+		"http://169.254.169.254/latest/meta-data/",
+		"https://x.com/latest/meta-data/",
+	} {
+		if _, err := f.fetchAndVerifyPost(context.Background(), "twitter", rawURL, "C3RT-ABCDEF"); !errors.Is(err, errBlockedPostURL) {
+			t.Errorf("%s: err = %v, want blocked", rawURL, err)
+		}
+	}
+	if hits != 0 {
+		t.Errorf("blocked targets were requested %d times", hits)
+	}
+}
+
+func TestFetchAndVerifyPostRedirects(t *testing.T) {
+	var internalHits int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host + r.URL.Path {
+		case "twitter.com/alice/status/1":
+			http.Redirect(w, r, "https://x.com/alice/status/1", http.StatusMovedPermanently)
+		case "x.com/alice/status/1":
+			w.Write([]byte("C3RT-ABCDEF")) //nolint:errcheck
+		case "x.com/to-internal":
+			http.Redirect(w, r, "https://www.x.com/admin", http.StatusFound)
+		case "x.com/to-loopback":
+			http.Redirect(w, r, "http://127.0.0.1/admin", http.StatusFound)
+		case "x.com/loop":
+			http.Redirect(w, r, "https://x.com/loop", http.StatusFound)
+		default:
+			atomic.AddInt32(&internalHits, 1)
+			w.Write([]byte("C3RT-ABCDEF")) //nolint:errcheck
+		}
+	}))
+	defer srv.Close()
+	f := newTestPostFetcher(srv, map[string]string{
+		"x.com":       "104.244.42.1",
+		"twitter.com": "104.244.42.65",
+		"www.x.com":   "10.0.0.7", // Resolves inside the network
+	})
+
+	found, err := f.fetchAndVerifyPost(context.Background(), "twitter", "https://twitter.com/alice/status/1", "C3RT-ABCDEF")
+	if err != nil || !found {
+		t.Errorf("redirect to x.com: %v, %v; want the code found", found, err)
+	}
+
+	for _, path := range []string{"/to-internal", "/to-loopback", "/loop"} {
+		if _, err := f.fetchAndVerifyPost(context.Background(), "twitter", "https://x.com"+path, "C3RT-ABCDEF"); !errors.Is(err, errBlockedPostURL) {
+			t.Errorf("%s: err = %v, want blocked", path, err)
+		}
+	}
+	if internalHits != 0 {
+		t.Errorf("internal redirect targets were requested %d times", internalHits)
+	}
+}
+
+// TestSocialPostDialerRefusesInternalAddresses tests the connect-time check
+// that catches hosts re-resolving to internal addresses after checkURL
+func TestSocialPostDialerRefusesInternalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("loopback server was reached")
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := newSocialPostFetcher().transport.RoundTrip(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, errBlockedPostURL) {
+		t.Errorf("RoundTrip to %s: err = %v, want blocked", srv.URL, err)
+	}
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Cosmos SDK v0.50.x with IAVL v1.x intermittently fails queries against the
+// latest height with "version does not exist": the query lands on a version
+// that has not been committed to the state store yet. The state is there a
+// moment later, and pinning the query slightly behind the tip (tip-1) almost
+// always succeeds.
+//
+// Handlers must not turn this error into an empty success (a zero balance is
+// indistinguishable from a real one). Instead they either:
+//   - retry pinned to a lagged height via queryAtLatestHeight,
+//   - fall back explicitly and tag the response with the fallback source, or
+//   - answer 503 via respondStateUnavailable so clients retry.
+
+// ErrStateVersionUnavailable matches any StateVersionError via errors.Is
+var ErrStateVersionUnavailable = errors.New("chain state temporarily unavailable")
+
+const (
+	// stateVersionHeightLag is how far behind the tip the first pinned retry queries
+	stateVersionHeightLag = 1
+
+	// stateVersionRetryAttempts is how many pinned heights are tried, walking back from tip-lag
+	stateVersionRetryAttempts = 2
+
+	// stateVersionRetryAfter is the Retry-After hint, in seconds, sent with 503 responses
+	stateVersionRetryAfter = "2"
+
+	// blockHeightHeader pins Cosmos REST gateway queries to a height
+	blockHeightHeader = "x-cosmos-block-height"
+)
+
+// StateVersionError reports a query that hit the SDK state-versioning issue
+type StateVersionError struct {
+	// Height is the height the query was pinned to, or 0 for the latest state
+	Height int64
+	// Detail is the upstream error message
+	Detail string
+}
+
+func (e *StateVersionError) Error() string {
+	if e.Height > 0 {
+		return fmt.Sprintf("state version unavailable at height %d: %s", e.Height, e.Detail)
+	}
+	return fmt.Sprintf("state version unavailable at latest height: %s", e.Detail)
+}
+
+// Is makes errors.Is(err, ErrStateVersionUnavailable) match
+func (e *StateVersionError) Is(target error) bool {
+	return target == ErrStateVersionUnavailable
+}
+
+// Retryable reports that the same query is expected to succeed shortly
+func (e *StateVersionError) Retryable() bool {
+	return true
+}
+
+// isStateVersionMessage reports whether an upstream message describes the state-versioning issue
+func isStateVersionMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "version does not exist") ||
+		strings.Contains(msg, "failed to load state at height")
+}
+
+// checkStateVersion returns a StateVersionError if msg describes the state-versioning issue
+func checkStateVersion(msg string, height int64) error {
+	if !isStateVersionMessage(msg) {
+		return nil
+	}
+	return &StateVersionError{Height: height, Detail: strings.TrimSpace(msg)}
+}
+
+// queryAtLatestHeight runs query against the latest state and, if it hits the
+// state-versioning issue, fetches the tip from the CometBFT RPC at rpcURL and
+// retries pinned to tip-stateVersionHeightLag, walking back one height per
+// attempt. A height of 0 passed to query means "latest".
+func queryAtLatestHeight(ctx context.Context, rpcURL string, query func(ctx context.Context, height int64) error) error {
+	return retryAtLaggedHeight(ctx, func(ctx context.Context) (int64, error) {
+		return fetchLatestHeight(ctx, rpcURL)
+	}, query)
+}
+
+// retryAtLaggedHeight is queryAtLatestHeight with the tip read from latestHeight
+func retryAtLaggedHeight(ctx context.Context, latestHeight func(ctx context.Context) (int64, error), query func(ctx context.Context, height int64) error) error {
+	err := query(ctx, 0)
+	if !errors.Is(err, ErrStateVersionUnavailable) {
+		return err
+	}
+
+	latest, herr := latestHeight(ctx)
+	if herr != nil {
+		return err
+	}
+	for i := 0; i < stateVersionRetryAttempts; i++ {
+		h := latest - stateVersionHeightLag - int64(i)
+		if h <= 0 {
+			break
+		}
+		err = query(ctx, h)
+		if !errors.Is(err, ErrStateVersionUnavailable) {
+			return err
+		}
+	}
+	return err
+}
+
+// fetchLatestHeight returns the latest committed block height from CometBFT /status
+func fetchLatestHeight(ctx context.Context, rpcURL string) (int64, error) {
+	body, err := getBody(ctx, strings.TrimRight(rpcURL, "/")+"/status")
+	if err != nil {
+		return 0, err
+	}
+	var status struct {
+		Result struct {
+			SyncInfo struct {
+				LatestBlockHeight string `json:"latest_block_height"`
+			} `json:"sync_info"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return 0, fmt.Errorf("failed to parse status response: %w", err)
+	}
+	return strconv.ParseInt(status.Result.SyncInfo.LatestBlockHeight, 10, 64)
+}
+
+// getRESTBodyAtHeight performs a Cosmos REST GET pinned to height (0 for latest)
+// and returns the body of a 200 response. State-versioning failures are
+// returned as a StateVersionError.
+func getRESTBodyAtHeight(ctx context.Context, rawURL string, height int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if height > 0 {
+		req.Header.Set(blockHeightHeader, strconv.FormatInt(height, 10))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		if err := checkStateVersion(string(body), height); err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrChainNotFound
+		}
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// respondStateUnavailable answers 503 with a Retry-After hint for state-versioning failures
+func (s *Server) respondStateUnavailable(w http.ResponseWriter, message string) {
+	w.Header().Set("Retry-After", stateVersionRetryAfter)
+	s.respondError(w, http.StatusServiceUnavailable, message)
+}
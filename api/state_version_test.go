@@ -0,0 +1,291 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+const versionDoesNotExist = "rpc error: code = Unknown desc = version does not exist"
+
+// heightRecorder records the heights a mock backend was queried at
+type heightRecorder struct {
+	mu      sync.Mutex
+	heights []string
+}
+
+func (h *heightRecorder) add(height string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.heights = append(h.heights, height)
+}
+
+func (h *heightRecorder) get() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.heights...)
+}
+
+// mockStateVersionRPC serves /status at latest and abci_query balances that
+// fail with the state-versioning error except at okHeight
+func mockStateVersionRPC(t *testing.T, latest int64, okHeight string, ucert int64, rec *heightRecorder) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status":
+			fmt.Fprintf(w, `{"result":{"sync_info":{"latest_block_height":"%d"}}}`, latest)
+		case "/abci_query":
+			height := r.URL.Query().Get("height")
+			rec.add(height)
+			if height != okHeight {
+				fmt.Fprintf(w, `{"result":{"response":{"code":1,"log":%q}}}`, versionDoesNotExist)
+				return
+			}
+			coin := sdk.NewCoin("ucert", sdkmath.NewInt(ucert))
+			bz, err := (&banktypes.QueryBalanceResponse{Balance: &coin}).Marshal()
+			if err != nil {
+				t.Fatalf("marshal balance response: %v", err)
+			}
+			fmt.Fprintf(w, `{"result":{"response":{"code":0,"value":%q}}}`, base64.StdEncoding.EncodeToString(bz))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+// mockStateVersionREST serves REST balances that fail with the
+// state-versioning error except at okHeight
+func mockStateVersionREST(okHeight string, ucert int64, rec *heightRecorder) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		height := r.Header.Get(blockHeightHeader)
+		rec.add(height)
+		if height != okHeight {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 2, "message": versionDoesNotExist})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"balances": []map[string]string{{"denom": "ucert", "amount": fmt.Sprint(ucert)}},
+		})
+	}))
+}
+
+func TestIsStateVersionMessage(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want bool
+	}{
+		{versionDoesNotExist, true},
+		{"Version Does Not Exist", true},
+		{"failed to load state at height 120; version does not exist (latest height: 119)", true},
+		{"failed to load state at height 5", true},
+		{"account not found", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isStateVersionMessage(tt.msg); got != tt.want {
+			t.Errorf("isStateVersionMessage(%q) = %v, want %v", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestStateVersionErrorMapping(t *testing.T) {
+	if err := checkStateVersion("insufficient funds", 10); err != nil {
+		t.Fatalf("unrelated message mapped to %v", err)
+	}
+
+	err := fmt.Errorf("balance query: %w", checkStateVersion(versionDoesNotExist, 10))
+	if !errors.Is(err, ErrStateVersionUnavailable) {
+		t.Fatalf("errors.Is(%v, ErrStateVersionUnavailable) = false", err)
+	}
+	var sve *StateVersionError
+	if !errors.As(err, &sve) {
+		t.Fatalf("errors.As(%v, *StateVersionError) = false", err)
+	}
+	if sve.Height != 10 || !sve.Retryable() {
+		t.Errorf("StateVersionError = %+v, want height 10 and retryable", sve)
+	}
+}
+
+// TestFetchBalanceGRPCRetriesAtLatestHeight tests that abci_query is retried pinned below the failing height
+func TestFetchBalanceGRPCRetriesAtLatestHeight(t *testing.T) {
+	rec := &heightRecorder{}
+	rpc := mockStateVersionRPC(t, 100, "98", 3_000_000, rec)
+	defer rpc.Close()
+
+	s := newBalanceTestServer(rpc.URL, "http://127.0.0.1:0", "grpc")
+	bal, err := s.queryAddressBalance(testBalanceAddr)
+	if err != nil {
+		t.Fatalf("queryAddressBalance: %v", err)
+	}
+	if bal.Source != BalanceSourceGRPC || bal.Balance != "3.000000" {
+		t.Errorf("balance = %+v, want 3.000000 from grpc", bal)
+	}
+	if got := fmt.Sprint(rec.get()); got != "[ 99 98]" {
+		t.Errorf("queried heights = %s, want [ 99 98]", got)
+	}
+}
+
+// TestFetchBalanceRESTRetriesAtLatestHeight tests that REST is retried with the block height header
+func TestFetchBalanceRESTRetriesAtLatestHeight(t *testing.T) {
+	rpc := mockStateVersionRPC(t, 100, "", 0, &heightRecorder{})
+	defer rpc.Close()
+	rec := &heightRecorder{}
+	rest := mockStateVersionREST("99", 8_000_000, rec)
+	defer rest.Close()
+
+	s := newBalanceTestServer(rpc.URL, rest.URL, "rest")
+	bal, err := s.queryAddressBalance(testBalanceAddr)
+	if err != nil {
+		t.Fatalf("queryAddressBalance: %v", err)
+	}
+	if bal.Source != BalanceSourceREST || bal.Balance != "8.000000" {
+		t.Errorf("balance = %+v, want 8.000000 from rest", bal)
+	}
+	if got := fmt.Sprint(rec.get()); got != "[ 99]" {
+		t.Errorf("queried heights = %s, want [ 99]", got)
+	}
+}
+
+// TestGetWalletBalanceStateUnavailable tests that exhausted retries answer 503 instead of a zero balance
+func TestGetWalletBalanceStateUnavailable(t *testing.T) {
+	rpc := mockStateVersionRPC(t, 100, "", 0, &heightRecorder{})
+	defer rpc.Close()
+	t.Setenv("COSMOS_RPC_URL", rpc.URL)
+
+	stub := &chainGRPCStub{okHeight: "never"}
+	s := newChainGRPCTestServer(t, stub, nil)
+	req := httptest.NewRequest("GET", "/api/v1/wallet/"+testBalanceAddr+"/balance", nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 (body %s)", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("missing Retry-After header")
+	}
+	if got := len(stub.heights.get()); got != 1+stateVersionRetryAttempts {
+		t.Errorf("gRPC queried %d times, want %d", got, 1+stateVersionRetryAttempts)
+	}
+}
+
+// TestGetWalletBalanceRetrySucceeds tests that the wallet endpoint reports the balance found at a pinned height
+func TestGetWalletBalanceRetrySucceeds(t *testing.T) {
+	rpc := mockStateVersionRPC(t, 100, "", 0, &heightRecorder{})
+	defer rpc.Close()
+	t.Setenv("COSMOS_RPC_URL", rpc.URL)
+
+	s := newChainGRPCTestServer(t, &chainGRPCStub{
+		balances: map[string]sdk.Coins{testBalanceAddr: sdk.NewCoins(sdk.NewCoin("ucert", sdkmath.NewInt(12_000_000)))},
+		okHeight: "99",
+	}, nil)
+	req := httptest.NewRequest("GET", "/api/v1/wallet/"+testBalanceAddr+"/balance", nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body.String())
+	}
+	var resp WalletBalanceResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.BalanceUcert != "12000000" || resp.BalanceSource != BalanceSourceGRPC {
+		t.Errorf("response = %+v, want 12000000 tagged grpc", resp)
+	}
+}
+
+// TestQueryAtLatestHeightPinsLaggedHeight tests that only the version error triggers a pinned retry
+func TestQueryAtLatestHeightPinsLaggedHeight(t *testing.T) {
+	rpc := mockStateVersionRPC(t, 50, "", 0, &heightRecorder{})
+	defer rpc.Close()
+
+	var heights []int64
+	err := queryAtLatestHeight(context.Background(), rpc.URL, func(_ context.Context, height int64) error {
+		heights = append(heights, height)
+		if height == 0 {
+			return checkStateVersion(versionDoesNotExist, height)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("queryAtLatestHeight: %v", err)
+	}
+	if fmt.Sprint(heights) != "[0 49]" {
+		t.Errorf("heights = %v, want [0 49]", heights)
+	}
+
+	other := errors.New("account not found")
+	heights = nil
+	err = queryAtLatestHeight(context.Background(), rpc.URL, func(_ context.Context, height int64) error {
+		heights = append(heights, height)
+		return other
+	})
+	if !errors.Is(err, other) || len(heights) != 1 {
+		t.Errorf("err = %v after %d attempts, want unrelated error without retry", err, len(heights))
+	}
+}
+
+// TestQueryStakingDelegationsRetriesAtLaggedHeight tests the delegation query against a gRPC stub
+func TestQueryStakingDelegationsRetriesAtLaggedHeight(t *testing.T) {
+	rpc := mockStateVersionRPC(t, 100, "", 0, &heightRecorder{})
+	defer rpc.Close()
+	t.Setenv("COSMOS_RPC_URL", rpc.URL)
+
+	stub := &chainGRPCStub{
+		delegations: map[string]stakingtypes.DelegationResponses{
+			testBalanceAddr: {{Balance: sdk.NewCoin("ucert", sdkmath.NewInt(500))}},
+		},
+		okHeight: "99",
+	}
+	s := newChainGRPCTestServer(t, stub, nil)
+	total, err := s.queryTotalStakedUcert(testBalanceAddr)
+	if err != nil {
+		t.Fatalf("queryTotalStakedUcert: %v", err)
+	}
+	if total != "500" {
+		t.Errorf("total = %s, want 500", total)
+	}
+	if got := fmt.Sprint(stub.heights.get()); got != "[ 99]" {
+		t.Errorf("queried heights = %s, want [ 99]", got)
+	}
+}
+
+// TestQueryAttestationsRetriesAtLaggedHeight tests that attestation gRPC queries are retried pinned to a lagged height
+func TestQueryAttestationsRetriesAtLaggedHeight(t *testing.T) {
+	rpc := mockStateVersionRPC(t, 100, "", 0, &heightRecorder{})
+	defer rpc.Close()
+	t.Setenv("COSMOS_RPC_URL", rpc.URL)
+
+	stub := &chainGRPCStub{
+		received: map[string][]attestationtypes.Attestation{
+			testBalanceAddr: {{UID: "0xabc", AttestationType: "public"}},
+		},
+		okHeight: "99",
+	}
+	s := newChainGRPCTestServer(t, stub, nil)
+	got, err := s.queryAttestationsByRecipient(testBalanceAddr)
+	if err != nil {
+		t.Fatalf("queryAttestationsByRecipient: %v", err)
+	}
+	if len(got) != 1 || got[0]["uid"] != "0xabc" {
+		t.Errorf("attestations = %v, want 0xabc", got)
+	}
+	if got := fmt.Sprint(stub.heights.get()); got != "[ 99]" {
+		t.Errorf("queried heights = %s, want [ 99]", got)
+	}
+}
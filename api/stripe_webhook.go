@@ -0,0 +1,262 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chaincertify/certd/api/database"
+	"go.uber.org/zap"
+)
+
+// Stripe billing webhooks
+// Checkout sessions for API key upgrades are created with the key's ID as
+// client_reference_id and the purchased tier in metadata.tier. Completing
+// checkout moves the key to that tier and stores its subscription; later
+// subscription events are matched to the key by subscription ID, and a
+// cancelled subscription drops the key back to the free tier.
+
+// stripeSignatureTolerance is how far a signed timestamp may be from now
+const stripeSignatureTolerance = 300 * time.Second
+
+// StripeConfig configures the Stripe billing webhook
+type StripeConfig struct {
+	// WebhookSecret is the endpoint's whsec_ signing secret; empty disables the webhook
+	WebhookSecret string
+
+	// PriceTiers maps Stripe price IDs to API tiers, for subscription updates
+	// that change plan
+	PriceTiers map[string]string
+}
+
+// DefaultStripeConfig leaves the webhook disabled until a secret is configured
+func DefaultStripeConfig() StripeConfig {
+	return StripeConfig{PriceTiers: map[string]string{}}
+}
+
+// stripeBillingStore applies Stripe billing state to API keys; *database.DB implements it
+type stripeBillingStore interface {
+	SetAPIKeyBilling(ctx context.Context, keyID string, b database.APIKeyBilling) (bool, error)
+	SetAPIKeyBillingBySubscription(ctx context.Context, subscriptionID string, b database.APIKeyBilling) (bool, error)
+}
+
+var errStripeSignature = errors.New("invalid Stripe signature")
+
+// verifyStripeSignature checks a Stripe-Signature header ("t=...,v1=...")
+// against the HMAC-SHA256 of "t.body" under secret
+func verifyStripeSignature(header string, body []byte, secret string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			timestamp = v
+		case "v1":
+			signatures = append(signatures, v)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: missing timestamp or v1 signature", errStripeSignature)
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp", errStripeSignature)
+	}
+	if abs(now.Unix()-ts) > int64(stripeSignatureTolerance.Seconds()) {
+		return fmt.Errorf("%w: timestamp outside tolerance", errStripeSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	// Stripe sends one v1 signature per active secret while a secret is rolled
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return nil
+		}
+	}
+	return errStripeSignature
+}
+
+// stripeEvent is the envelope of a Stripe webhook event
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// stripeCheckoutSession holds the checkout.session fields the webhook uses
+type stripeCheckoutSession struct {
+	ClientReferenceID string            `json:"client_reference_id"`
+	Customer          string            `json:"customer"`
+	Subscription      string            `json:"subscription"`
+	CustomerEmail     string            `json:"customer_email"`
+	Metadata          map[string]string `json:"metadata"`
+	CustomerDetails   struct {
+		Email string `json:"email"`
+	} `json:"customer_details"`
+}
+
+// stripeSubscription holds the subscription fields the webhook uses
+type stripeSubscription struct {
+	ID       string            `json:"id"`
+	Customer string            `json:"customer"`
+	Status   string            `json:"status"`
+	Metadata map[string]string `json:"metadata"`
+	Items    struct {
+		Data []struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// stripeEndedStatuses are subscription statuses that no longer pay for a tier
+var stripeEndedStatuses = map[string]bool{"canceled": true, "unpaid": true, "incomplete_expired": true}
+
+// tier returns the subscription's API tier from its price, falling back to its metadata
+func (sub *stripeSubscription) tier(priceTiers map[string]string) string {
+	for _, item := range sub.Items.Data {
+		if tier, ok := priceTiers[item.Price.ID]; ok {
+			return tier
+		}
+	}
+	return sub.Metadata["tier"]
+}
+
+// paidBilling is the billing state of a key on a paid tier
+func paidBilling(tier, subscriptionID, customerID, email string) (database.APIKeyBilling, error) {
+	daily, minute, ok := apiTierLimits(tier)
+	if !ok || tier == "free" {
+		return database.APIKeyBilling{}, fmt.Errorf("unknown paid tier %q", tier)
+	}
+	b := database.APIKeyBilling{
+		Tier: tier, RateLimitPerDay: daily, RateLimitPerMinute: minute,
+		Scopes: defaultAPIKeyScopes(tier),
+	}
+	if subscriptionID != "" {
+		b.StripeSubscriptionID = &subscriptionID
+	}
+	if customerID != "" {
+		b.StripeCustomerID = &customerID
+	}
+	if email != "" {
+		b.BillingEmail = &email
+	}
+	return b, nil
+}
+
+// freeBilling is the billing state of a key whose subscription ended
+func freeBilling() database.APIKeyBilling {
+	daily, minute, _ := apiTierLimits("free")
+	return database.APIKeyBilling{Tier: "free", RateLimitPerDay: daily, RateLimitPerMinute: minute}
+}
+
+// handleStripeWebhook applies Stripe subscription events to API key tiers
+// POST /api/v1/billing/stripe/webhook
+func (s *Server) handleStripeWebhook(w http.ResponseWriter, r *http.Request) {
+	secret := s.config.Stripe.WebhookSecret
+	if secret == "" || s.billing == nil {
+		http.Error(w, "Webhook not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Read raw body for signature verification
+	rawBody, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxJSONBodyBytes))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+	if err := verifyStripeSignature(r.Header.Get("Stripe-Signature"), rawBody, secret, s.clock.Now()); err != nil {
+		s.logger.Warn("Rejected Stripe webhook", zap.Error(err))
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event stripeEvent
+	if err := json.Unmarshal(rawBody, &event); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	found, err := s.applyStripeEvent(ctx, event)
+	if errors.Is(err, errStripePayload) {
+		// Acknowledged, since retrying cannot make the event usable
+		s.logger.Warn("Ignoring unusable Stripe event", zap.String("event_id", event.ID), zap.String("type", event.Type), zap.Error(err))
+		s.respondJSON(w, http.StatusOK, map[string]bool{"received": true})
+		return
+	}
+	if err != nil {
+		// A 5xx makes Stripe retry the event
+		s.logger.Error("Failed to apply Stripe event", zap.String("event_id", event.ID), zap.String("type", event.Type), zap.Error(err))
+		http.Error(w, "Failed to apply event", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		s.logger.Warn("Stripe event matched no API key", zap.String("event_id", event.ID), zap.String("type", event.Type))
+	}
+
+	s.respondJSON(w, http.StatusOK, map[string]bool{"received": true})
+}
+
+// errStripePayload marks events that cannot be applied however often they are retried
+var errStripePayload = errors.New("unusable Stripe event")
+
+// applyStripeEvent applies event to the API key it concerns, reporting whether
+// a key matched. Event types the webhook does not handle are ignored.
+func (s *Server) applyStripeEvent(ctx context.Context, event stripeEvent) (bool, error) {
+	switch event.Type {
+	case "checkout.session.completed":
+		var session stripeCheckoutSession
+		if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+			return false, fmt.Errorf("%w: %v", errStripePayload, err)
+		}
+		if session.ClientReferenceID == "" {
+			return false, fmt.Errorf("%w: checkout session has no client_reference_id", errStripePayload)
+		}
+		email := session.CustomerDetails.Email
+		if email == "" {
+			email = session.CustomerEmail
+		}
+		b, err := paidBilling(session.Metadata["tier"], session.Subscription, session.Customer, email)
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", errStripePayload, err)
+		}
+		return s.billing.SetAPIKeyBilling(ctx, session.ClientReferenceID, b)
+
+	case "customer.subscription.updated", "customer.subscription.deleted":
+		var sub stripeSubscription
+		if err := json.Unmarshal(event.Data.Object, &sub); err != nil {
+			return false, fmt.Errorf("%w: %v", errStripePayload, err)
+		}
+		if sub.ID == "" {
+			return false, fmt.Errorf("%w: subscription has no id", errStripePayload)
+		}
+		if event.Type == "customer.subscription.deleted" || stripeEndedStatuses[sub.Status] {
+			return s.billing.SetAPIKeyBillingBySubscription(ctx, sub.ID, freeBilling())
+		}
+		b, err := paidBilling(sub.tier(s.config.Stripe.PriceTiers), sub.ID, sub.Customer, "")
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", errStripePayload, err)
+		}
+		return s.billing.SetAPIKeyBillingBySubscription(ctx, sub.ID, b)
+	}
+	return true, nil
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chaincertify/certd/api/database"
+	"go.uber.org/zap"
+)
+
+const stripeTestSecret = "whsec_test"
+
+// memoryBillingStore keeps API key billing state by key ID
+type memoryBillingStore struct {
+	keys map[string]*database.APIKeyNew
+}
+
+func (m *memoryBillingStore) apply(key *database.APIKeyNew, b database.APIKeyBilling) {
+	key.Tier, key.RateLimitPerDay, key.RateLimitPerMinute = b.Tier, b.RateLimitPerDay, b.RateLimitPerMinute
+	key.StripeSubscriptionID, key.StripeCustomerID = b.StripeSubscriptionID, b.StripeCustomerID
+	if b.BillingEmail != nil {
+		key.BillingEmail = b.BillingEmail
+	}
+	if b.Scopes != nil {
+		key.Scopes = b.Scopes
+	}
+}
+
+func (m *memoryBillingStore) SetAPIKeyBilling(_ context.Context, keyID string, b database.APIKeyBilling) (bool, error) {
+	key, ok := m.keys[keyID]
+	if ok {
+		m.apply(key, b)
+	}
+	return ok, nil
+}
+
+func (m *memoryBillingStore) SetAPIKeyBillingBySubscription(_ context.Context, subscriptionID string, b database.APIKeyBilling) (bool, error) {
+	for _, key := range m.keys {
+		if key.StripeSubscriptionID != nil && *key.StripeSubscriptionID == subscriptionID {
+			m.apply(key, b)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func signStripe(body string, ts int64, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s", ts, body)
+	return fmt.Sprintf("t=%d,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func newStripeTestServer(t *testing.T) (*Server, *memoryBillingStore) {
+	t.Helper()
+	config := DefaultConfig()
+	config.Clock = newFakeClock(time.Unix(1_700_000_000, 0))
+	config.Stripe.WebhookSecret = stripeTestSecret
+	config.Stripe.PriceTiers = map[string]string{"price_enterprise": "enterprise"}
+	s := NewServer(config, zap.NewNop())
+	store := &memoryBillingStore{keys: map[string]*database.APIKeyNew{
+		"key-1": {ID: "key-1", Tier: "free", RateLimitPerDay: 100, RateLimitPerMinute: 2, Active: true},
+	}}
+	s.billing = store
+	return s, store
+}
+
+func postStripeEvent(s *Server, body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/v1/billing/stripe/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if signature != "" {
+		req.Header.Set("Stripe-Signature", signature)
+	}
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+const checkoutCompleted = `{"id":"evt_1","type":"checkout.session.completed","data":{"object":{
+	"client_reference_id":"key-1","customer":"cus_1","subscription":"sub_1",
+	"customer_details":{"email":"billing@example.com"},"metadata":{"tier":"developer"}}}}`
+
+func TestStripeWebhookRejectsBadSignatures(t *testing.T) {
+	s, store := newStripeTestServer(t)
+	now := s.clock.Now().Unix()
+
+	for name, signature := range map[string]string{
+		"missing header":  "",
+		"no v1 signature": fmt.Sprintf("t=%d", now),
+		"wrong secret":    signStripe(checkoutCompleted, now, "whsec_other"),
+		"stale timestamp": signStripe(checkoutCompleted, now-301, stripeTestSecret),
+		"tampered body":   signStripe(strings.Replace(checkoutCompleted, "developer", "enterprise", 1), now, stripeTestSecret),
+	} {
+		if rec := postStripeEvent(s, checkoutCompleted, signature); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, rec.Code)
+		}
+	}
+	if key := store.keys["key-1"]; key.Tier != "free" || key.StripeSubscriptionID != nil {
+		t.Errorf("key changed by rejected events: %+v", key)
+	}
+}
+
+func TestStripeWebhookUpgradesAndCancels(t *testing.T) {
+	s, store := newStripeTestServer(t)
+	now := s.clock.Now().Unix()
+	send := func(body string) {
+		t.Helper()
+		// A second v1 signature from a rolled secret is ignored
+		signature := signStripe(body, now, stripeTestSecret) + ",v1=" + strings.Repeat("0", 64)
+		if rec := postStripeEvent(s, body, signature); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	key := store.keys["key-1"]
+
+	send(checkoutCompleted)
+	if key.Tier != "developer" || key.RateLimitPerDay != 10000 || key.RateLimitPerMinute != 100 {
+		t.Errorf("after checkout: tier %s, limits %d/%d; want developer 10000/100", key.Tier, key.RateLimitPerDay, key.RateLimitPerMinute)
+	}
+	if key.StripeSubscriptionID == nil || *key.StripeSubscriptionID != "sub_1" ||
+		key.StripeCustomerID == nil || *key.StripeCustomerID != "cus_1" ||
+		key.BillingEmail == nil || *key.BillingEmail != "billing@example.com" {
+		t.Errorf("after checkout: billing IDs not stored: %+v", key)
+	}
+	if !hasScope(key.Scopes, scopeWriteAttestations) {
+		t.Errorf("after checkout: scopes = %v, want the developer write scopes", key.Scopes)
+	}
+
+	send(`{"id":"evt_2","type":"customer.subscription.updated","data":{"object":{
+		"id":"sub_1","customer":"cus_1","status":"active","items":{"data":[{"price":{"id":"price_enterprise"}}]}}}}`)
+	if key.Tier != "enterprise" || key.RateLimitPerDay != 1000000 {
+		t.Errorf("after plan change: tier %s, daily %d; want enterprise", key.Tier, key.RateLimitPerDay)
+	}
+
+	send(`{"id":"evt_3","type":"customer.subscription.deleted","data":{"object":{"id":"sub_1","customer":"cus_1","status":"canceled"}}}`)
+	if key.Tier != "free" || key.RateLimitPerDay != 100 || key.RateLimitPerMinute != 2 {
+		t.Errorf("after cancellation: tier %s, limits %d/%d; want free 100/2", key.Tier, key.RateLimitPerDay, key.RateLimitPerMinute)
+	}
+	if key.StripeSubscriptionID != nil || key.StripeCustomerID != nil {
+		t.Errorf("after cancellation: billing IDs not cleared: %+v", key)
+	}
+
+	// Events for other subscriptions and unhandled types are acknowledged
+	send(`{"id":"evt_4","type":"customer.subscription.deleted","data":{"object":{"id":"sub_unknown"}}}`)
+	send(`{"id":"evt_5","type":"invoice.paid","data":{"object":{}}}`)
+	send(`{"id":"evt_6","type":"checkout.session.completed","data":{"object":{"client_reference_id":"key-1","metadata":{"tier":"platinum"}}}}`)
+	if key.Tier != "free" {
+		t.Errorf("tier = %s after unusable events, want free", key.Tier)
+	}
+}
+
+func TestStripeWebhookRequiresSecret(t *testing.T) {
+	s := NewServer(DefaultConfig(), zap.NewNop())
+	if rec := postStripeEvent(s, checkoutCompleted, "t=1,v1=00"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
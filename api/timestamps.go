@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampLayout is the one format API responses use for timestamps:
+// RFC3339 in UTC with second precision
+const timestampLayout = time.RFC3339
+
+// Timestamp is a response timestamp. It serializes as an RFC3339 UTC string,
+// or null when zero, and decodes RFC3339 strings at any offset or precision,
+// unix seconds and null, so chain and CLI output can be decoded into it.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp wraps t as a Timestamp
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// NewTimestampPtr wraps t as a *Timestamp, keeping nil as nil
+func NewTimestampPtr(t *time.Time) *Timestamp {
+	if t == nil {
+		return nil
+	}
+	ts := NewTimestamp(*t)
+	return &ts
+}
+
+// UnixTimestamp is the Timestamp for unix seconds, zero for sec <= 0
+func UnixTimestamp(sec int64) Timestamp {
+	if sec <= 0 {
+		return Timestamp{}
+	}
+	return NewTimestamp(time.Unix(sec, 0))
+}
+
+// String returns the standard form of t, or "" when zero
+func (t Timestamp) String() string {
+	return formatTimestamp(t.Time)
+}
+
+// Unix returns the unix seconds of t, or 0 when zero
+func (t Timestamp) Unix() int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Time.Unix()
+}
+
+// MarshalJSON encodes t as an RFC3339 UTC string, or null when zero
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(formatTimestamp(t.Time))
+}
+
+// UnmarshalJSON accepts null, an RFC3339 string or unix seconds as a number or string
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		*t = Timestamp{}
+		return nil
+	}
+	var s string
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	} else {
+		s = string(b)
+	}
+	parsed, err := parseTimestamp(s)
+	if err != nil {
+		return err
+	}
+	*t = Timestamp{Time: parsed}
+	return nil
+}
+
+// formatTimestamp renders t in the standard response form, or "" when zero
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(timestampLayout)
+}
+
+// parseTimestamp reads the timestamp forms the chain, CLI and clients produce:
+// RFC3339 at any precision or offset, or unix seconds. The chain's zero time
+// ("0001-01-01T00:00:00Z") and "" parse as the zero time.
+func parseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return time.Time{}, nil
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if sec < 0 {
+			return time.Time{}, fmt.Errorf("negative unix timestamp %d", sec)
+		}
+		return time.Unix(sec, 0).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+	if t.Year() <= 1 {
+		return time.Time{}, nil
+	}
+	return t.UTC(), nil
+}
+
+// normalizeTimestamp rewrites a chain or CLI timestamp string in the standard
+// response form. Zero times become "" and unparseable input is returned as-is.
+func normalizeTimestamp(s string) string {
+	t, err := parseTimestamp(s)
+	if err != nil {
+		return s
+	}
+	return formatTimestamp(t)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// rfc3339UTC matches the standard response timestamp form
+var rfc3339UTC = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`)
+
+func TestTimestampJSON(t *testing.T) {
+	offset := time.FixedZone("UTC+2", 2*60*60)
+	at := time.Date(2026, 3, 1, 14, 30, 5, 123456789, offset)
+
+	b, err := json.Marshal(NewTimestamp(at))
+	if err != nil || string(b) != `"2026-03-01T12:30:05Z"` {
+		t.Fatalf("marshal = %s, %v", b, err)
+	}
+	if b, _ := json.Marshal(Timestamp{}); string(b) != "null" {
+		t.Errorf("zero marshal = %s, want null", b)
+	}
+
+	decode := []struct {
+		in   string
+		want int64
+	}{
+		{`"2026-03-01T12:30:05Z"`, 1772368205},
+		{`"2026-03-01T14:30:05.123456789+02:00"`, 1772368205},
+		{`1772368205`, 1772368205},
+		{`"1772368205"`, 1772368205},
+		{`"0001-01-01T00:00:00Z"`, 0},
+		{`""`, 0},
+		{`null`, 0},
+	}
+	for _, tt := range decode {
+		var ts Timestamp
+		if err := json.Unmarshal([]byte(tt.in), &ts); err != nil {
+			t.Errorf("unmarshal %s: %v", tt.in, err)
+			continue
+		}
+		if ts.Unix() != tt.want {
+			t.Errorf("unmarshal %s = %d, want %d", tt.in, ts.Unix(), tt.want)
+		}
+	}
+	var ts Timestamp
+	if err := json.Unmarshal([]byte(`"yesterday"`), &ts); err == nil {
+		t.Error("unmarshal accepted a non-timestamp")
+	}
+
+	if got := normalizeTimestamp("2026-03-01T14:30:05.5+02:00"); got != "2026-03-01T12:30:05Z" {
+		t.Errorf("normalizeTimestamp = %q", got)
+	}
+	if got := normalizeTimestamp("0001-01-01T00:00:00Z"); got != "" {
+		t.Errorf("normalizeTimestamp(zero) = %q, want empty", got)
+	}
+}
+
+func TestResponseTimestampsSerializeRFC3339UTC(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 999, time.FixedZone("EST", -5*60*60))
+	ts := NewTimestamp(at)
+
+	responses := map[string]any{
+		"CreateEncryptedAttestationResponse":   CreateEncryptedAttestationResponse{Timestamp: ts, TimestampUnix: ts.Unix()},
+		"RetrieveEncryptedAttestationResponse": RetrieveEncryptedAttestationResponse{Timestamp: ts, TimestampUnix: ts.Unix()},
+		"authChallengeResponse":                authChallengeResponse{ExpiresAt: ts, ExpiresAtUnix: ts.Unix()},
+		"authVerifyResponse":                   authVerifyResponse{ExpiresAt: &ts, ExpiresAtUnix: ts.Unix()},
+		"FullIdentity":                         FullIdentity{CreatedAt: &ts, CreatedAtUnix: ts.Unix()},
+		"Badge":                                Badge{AwardedAt: &ts, AwardedAtUnix: ts.Unix()},
+		"UserProfile":                          UserProfile{CreatedAt: ts, CreatedAtUnix: ts.Unix(), UpdatedAt: ts, UpdatedAtUnix: ts.Unix()},
+		"Credential":                           Credential{IssuedAt: ts, IssuedAtUnix: ts.Unix()},
+		"socialAccountResponse":                socialAccountResponse{VerifiedAt: ts, VerifiedAtUnix: ts.Unix()},
+		"ProposalInfo":                         ProposalInfo{SubmitTime: ts, DepositEndTime: ts, VotingStartTime: ts, VotingEndTime: ts},
+		"ValidatorInfo":                        ValidatorInfo{UnbondingTime: ts, Commission: ValidatorCommission{UpdateTime: ts}},
+	}
+	fields := map[string][]string{
+		"CreateEncryptedAttestationResponse":   {"timestamp"},
+		"RetrieveEncryptedAttestationResponse": {"timestamp"},
+		"authChallengeResponse":                {"expires_at"},
+		"authVerifyResponse":                   {"expires_at"},
+		"FullIdentity":                         {"created_at"},
+		"Badge":                                {"awarded_at"},
+		"UserProfile":                          {"created_at", "updated_at"},
+		"Credential":                           {"issued_at"},
+		"socialAccountResponse":                {"verified_at"},
+		"ProposalInfo":                         {"submit_time", "deposit_end_time", "voting_start_time", "voting_end_time"},
+		"ValidatorInfo":                        {"unbonding_time"},
+	}
+
+	for name, resp := range responses {
+		b, err := json.Marshal(resp)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var out map[string]any
+		_ = json.Unmarshal(b, &out)
+		for _, field := range fields[name] {
+			s, _ := out[field].(string)
+			if s != "2026-03-01T14:00:00Z" || !rfc3339UTC.MatchString(s) {
+				t.Errorf("%s.%s = %v, want RFC3339 UTC", name, field, out[field])
+			}
+			if unix, ok := out[field+"_unix"]; ok && unix != float64(ts.Unix()) {
+				t.Errorf("%s.%s_unix = %v, want %d", name, field, unix, ts.Unix())
+			}
+		}
+	}
+}
+
+func TestCertdTxTimestamp(t *testing.T) {
+	if got := certdTxTimestamp("2026-03-01T14:00:00.123456Z").String(); got != "2026-03-01T14:00:00Z" {
+		t.Errorf("certdTxTimestamp = %q", got)
+	}
+	if got := certdTxTimestamp(""); got.IsZero() || !rfc3339UTC.MatchString(got.String()) {
+		t.Errorf("certdTxTimestamp(empty) = %q, want now", got)
+	}
+}
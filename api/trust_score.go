@@ -0,0 +1,179 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TrustScoreConfig holds the point values of the CertID trust score
+// (calculateTrustScore), the sybil score (calculateSybilTrustScore) and the
+// cross-chain bonus (calculateCrossChainBonus)
+type TrustScoreConfig struct {
+	Identity   IdentityScoreWeights   `json:"identity"`
+	Sybil      SybilScoreWeights      `json:"sybil"`
+	CrossChain CrossChainScoreWeights `json:"cross_chain"`
+}
+
+// IdentityScoreWeights are the CertID trust score's points
+type IdentityScoreWeights struct {
+	KYC            int       `json:"kyc"`
+	PerSocial      int       `json:"per_social"`
+	SocialCap      int       `json:"social_cap"`
+	AgeTiers       []AgeTier `json:"age_tiers"` // The highest reached tier counts
+	PerAttestation int       `json:"per_attestation"`
+	AttestationCap int       `json:"attestation_cap"`
+}
+
+// AgeTier awards Points to accounts more than OlderThanDays old
+type AgeTier struct {
+	OlderThanDays int `json:"older_than_days"`
+	Points        int `json:"points"`
+}
+
+// SybilScoreWeights are the sybil trust score's points; the total is capped at MaxScore
+type SybilScoreWeights struct {
+	KYC            int     `json:"kyc"`
+	PerSocial      int     `json:"per_social"`
+	SocialCap      int     `json:"social_cap"`
+	PerActivity    int     `json:"per_activity"`
+	ActivityCap    int     `json:"activity_cap"`
+	PerAgeMonth    int     `json:"per_age_month"`
+	AgeCap         int     `json:"age_cap"`
+	PerStakedCERT  float64 `json:"per_staked_cert"`
+	StakeCap       int     `json:"stake_cap"`
+	PerAttestation int     `json:"per_attestation"`
+	AttestationCap int     `json:"attestation_cap"`
+	MaxScore       int     `json:"max_score"`
+}
+
+// CrossChainScoreWeights are the cross-chain bonus points, capped at Cap
+type CrossChainScoreWeights struct {
+	HighActivityTxs   int `json:"high_activity_txs"` // Tx count for the high activity points
+	HighActivity      int `json:"high_activity"`
+	MediumActivityTxs int `json:"medium_activity_txs"` // Tx count for the medium activity points
+	MediumActivity    int `json:"medium_activity"`
+	LowActivity       int `json:"low_activity"` // Any other chain with transactions
+	DeFi              int `json:"defi"`
+	TwoChains         int `json:"two_chains"`
+	ThreeChains       int `json:"three_chains"` // Active on three or more chains
+	Cap               int `json:"cap"`
+}
+
+// DefaultTrustScoreConfig returns the standard trust score weights
+func DefaultTrustScoreConfig() TrustScoreConfig {
+	return TrustScoreConfig{
+		Identity: IdentityScoreWeights{
+			KYC:       50, // 50% of max score
+			PerSocial: 8,
+			SocialCap: 24, // 3 platforms
+			AgeTiers: []AgeTier{
+				{OlderThanDays: 7, Points: 4},
+				{OlderThanDays: 30, Points: 8},
+				{OlderThanDays: 180, Points: 12},
+				{OlderThanDays: 365, Points: 16},
+			},
+			PerAttestation: 2,
+			AttestationCap: 10,
+		},
+		Sybil: SybilScoreWeights{
+			KYC:            30,
+			PerSocial:      10,
+			SocialCap:      40,
+			PerActivity:    5,
+			ActivityCap:    20,
+			PerAgeMonth:    1,
+			AgeCap:         10,
+			PerStakedCERT:  0.01,
+			StakeCap:       20,
+			PerAttestation: 2,
+			AttestationCap: 20,
+			MaxScore:       100,
+		},
+		CrossChain: CrossChainScoreWeights{
+			HighActivityTxs:   10,
+			HighActivity:      5,
+			MediumActivityTxs: 3,
+			MediumActivity:    3,
+			LowActivity:       1,
+			DeFi:              2,
+			TwoChains:         5,
+			ThreeChains:       10,
+			Cap:               30,
+		},
+	}
+}
+
+// LoadTrustScoreConfig reads JSON trust score weights from path; weights the
+// file leaves out keep their defaults
+func LoadTrustScoreConfig(path string) (TrustScoreConfig, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return TrustScoreConfig{}, err
+	}
+	config := DefaultTrustScoreConfig()
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&config); err != nil {
+		return TrustScoreConfig{}, fmt.Errorf("failed to parse trust score config: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return TrustScoreConfig{}, err
+	}
+	return config, nil
+}
+
+// Validate rejects negative weights and caps
+func (c TrustScoreConfig) Validate() error {
+	id, sy, cc := c.Identity, c.Sybil, c.CrossChain
+	for _, v := range []int{
+		id.KYC, id.PerSocial, id.SocialCap, id.PerAttestation, id.AttestationCap,
+		sy.KYC, sy.PerSocial, sy.SocialCap, sy.PerActivity, sy.ActivityCap, sy.PerAgeMonth, sy.AgeCap,
+		sy.StakeCap, sy.PerAttestation, sy.AttestationCap, sy.MaxScore,
+		cc.HighActivityTxs, cc.HighActivity, cc.MediumActivityTxs, cc.MediumActivity, cc.LowActivity,
+		cc.DeFi, cc.TwoChains, cc.ThreeChains, cc.Cap,
+	} {
+		if v < 0 {
+			return errors.New("trust score weights and caps cannot be negative")
+		}
+	}
+	if sy.PerStakedCERT < 0 {
+		return errors.New("trust score weights and caps cannot be negative")
+	}
+	for _, tier := range id.AgeTiers {
+		if tier.OlderThanDays < 0 || tier.Points < 0 {
+			return fmt.Errorf("invalid age tier %+v", tier)
+		}
+	}
+	if cc.MediumActivityTxs > cc.HighActivityTxs {
+		return fmt.Errorf("medium activity threshold %d exceeds high activity threshold %d", cc.MediumActivityTxs, cc.HighActivityTxs)
+	}
+	return nil
+}
+
+// ModelVersion identifies the weighting in exported trust scores: the standard
+// model version, suffixed with a digest of the weights when they are customized
+func (c TrustScoreConfig) ModelVersion() string {
+	custom, _ := json.Marshal(c)
+	standard, _ := json.Marshal(DefaultTrustScoreConfig())
+	if bytes.Equal(custom, standard) {
+		return trustScoreModelVersion
+	}
+	sum := sha256.Sum256(custom)
+	return trustScoreModelVersion + "+" + hex.EncodeToString(sum[:4])
+}
+
+// agePoints returns the points of the highest tier daysOld exceeds
+func (w IdentityScoreWeights) agePoints(daysOld int) int {
+	points := 0
+	for _, tier := range w.AgeTiers {
+		if daysOld > tier.OlderThanDays && tier.Points > points {
+			points = tier.Points
+		}
+	}
+	return points
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrustScoreWeightsChangeScores(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := created.Add(200 * 24 * time.Hour)
+	defaults := DefaultTrustScoreConfig()
+
+	t.Run("identity", func(t *testing.T) {
+		// 50 KYC + 16 (2 socials) + 12 (200 days) + 6 (3 attestations)
+		if got := calculateTrustScore(defaults.Identity, now, created, 3, true, 2); got != 84 {
+			t.Fatalf("default score = %d, want 84", got)
+		}
+		w := DefaultTrustScoreConfig().Identity
+		w.KYC = 20
+		w.PerSocial = 15
+		w.SocialCap = 20
+		w.AgeTiers = []AgeTier{{OlderThanDays: 90, Points: 30}, {OlderThanDays: 365, Points: 40}}
+		// 20 KYC + 20 (capped socials) + 30 (past 90 days) + 6
+		for i := 0; i < 3; i++ {
+			if got := calculateTrustScore(w, now, created, 3, true, 2); got != 76 {
+				t.Fatalf("tuned score = %d, want 76", got)
+			}
+		}
+	})
+
+	t.Run("sybil", func(t *testing.T) {
+		factors := TrustFactors{KYCVerified: true, SocialVerifications: 2, AccountAgeMonths: 6, StakedAmount: 500, AttestationsReceived: 4}
+		// 30 + 20 + 6 + 5 + 8
+		if got := calculateSybilTrustScore(defaults.Sybil, factors); got != 69 {
+			t.Fatalf("default score = %d, want 69", got)
+		}
+		w := DefaultTrustScoreConfig().Sybil
+		w.KYC = 60
+		w.PerStakedCERT = 0.1
+		// 60 + 20 + 6 + 20 (capped stake) + 8, capped at MaxScore
+		if got := calculateSybilTrustScore(w, factors); got != 100 {
+			t.Errorf("tuned score = %d, want 100", got)
+		}
+		w.MaxScore = 90
+		if got := calculateSybilTrustScore(w, factors); got != 90 {
+			t.Errorf("score capped at 90 = %d", got)
+		}
+	})
+
+	t.Run("cross-chain", func(t *testing.T) {
+		chains := map[string]ChainActivityData{
+			"ethereum": {TxCount: 12, DeFiInteraction: true},
+			"base":     {TxCount: 4},
+		}
+		// 5 + 2 + 3 + 5 for two chains
+		if got := calculateCrossChainBonus(defaults.CrossChain, chains); got != 15 {
+			t.Fatalf("default bonus = %d, want 15", got)
+		}
+		w := DefaultTrustScoreConfig().CrossChain
+		w.HighActivityTxs = 4
+		w.TwoChains = 20
+		w.Cap = 25
+		// 5 + 2 + 5 + 20, capped at 25
+		if got := calculateCrossChainBonus(w, chains); got != 25 {
+			t.Errorf("tuned bonus = %d, want 25", got)
+		}
+	})
+}
+
+func TestLoadTrustScoreConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	config, err := LoadTrustScoreConfig(write("weights.json", `{"identity": {"kyc": 40}, "cross_chain": {"cap": 50}}`))
+	if err != nil {
+		t.Fatalf("LoadTrustScoreConfig: %v", err)
+	}
+	if config.Identity.KYC != 40 || config.CrossChain.Cap != 50 {
+		t.Errorf("config = %+v, want the file's weights", config)
+	}
+	// Weights left out keep their defaults
+	if config.Identity.PerSocial != 8 || len(config.Identity.AgeTiers) != 4 || config.Sybil.KYC != 30 {
+		t.Errorf("config = %+v, want defaults for unspecified weights", config)
+	}
+
+	for name, body := range map[string]string{
+		"negative.json": `{"sybil": {"kyc": -5}}`,
+		"unknown.json":  `{"identity": {"kyc_points": 40}}`,
+		"tiers.json":    `{"cross_chain": {"medium_activity_txs": 20}}`,
+	} {
+		if _, err := LoadTrustScoreConfig(write(name, body)); err == nil {
+			t.Errorf("%s loaded, want an error", name)
+		}
+	}
+}
+
+func TestTrustScoreModelVersion(t *testing.T) {
+	if v := DefaultTrustScoreConfig().ModelVersion(); v != trustScoreModelVersion {
+		t.Errorf("default model version = %q, want %q", v, trustScoreModelVersion)
+	}
+	tuned := DefaultTrustScoreConfig()
+	tuned.Identity.KYC = 40
+	v := tuned.ModelVersion()
+	if !strings.HasPrefix(v, trustScoreModelVersion+"+") || v != tuned.ModelVersion() {
+		t.Errorf("tuned model version = %q, want a stable suffixed version", v)
+	}
+	tuned.Identity.KYC = 45
+	if tuned.ModelVersion() == v {
+		t.Error("different weights share a model version")
+	}
+}
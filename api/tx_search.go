@@ -0,0 +1,278 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chaincertify/certd/api/retry"
+)
+
+// Tendermint tx_search limits
+const (
+	txSearchDefaultPerPage = 30
+	txSearchMaxPerPage     = 100   // Tendermint rejects per_page above 100
+	txSearchMaxResults     = 10000 // Hard cap on results reachable through paging
+)
+
+// txSearchRetry retries transient tx_search failures (a variable so tests can shorten the backoff)
+var txSearchRetry = retry.DefaultPolicy()
+
+// txSearchTx is a decoded tx_search result entry
+type txSearchTx struct {
+	Hash      string            `json:"hash"`
+	Height    int64             `json:"height"`
+	Index     int               `json:"index"`
+	Code      int               `json:"code"`
+	Log       string            `json:"log"`
+	GasWanted int64             `json:"gas_wanted"`
+	GasUsed   int64             `json:"gas_used"`
+	Tx        string            `json:"tx"` // base64-encoded tx bytes
+	Events    []txSearchTxEvent `json:"events"`
+}
+
+// txSearchTxEvent is an ABCI event emitted by a searched transaction
+type txSearchTxEvent struct {
+	Type       string            `json:"type"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// txSearchResult is one normalized page of tx_search results
+type txSearchResult struct {
+	Txs        []txSearchTx `json:"txs"`
+	TotalCount int          `json:"total_count"` // Capped at txSearchMaxResults
+	Page       int          `json:"page"`
+	PerPage    int          `json:"per_page"`
+}
+
+// HasMore reports whether a later page holds more results
+func (r *txSearchResult) HasMore() bool {
+	return r.Page*r.PerPage < r.TotalCount
+}
+
+// rpcTxSearchResponse mirrors the raw Tendermint tx_search JSON-RPC response
+type rpcTxSearchResponse struct {
+	Result struct {
+		Txs []struct {
+			Hash     string `json:"hash"`
+			Height   string `json:"height"`
+			Index    int    `json:"index"`
+			TxResult struct {
+				Code      int    `json:"code"`
+				Log       string `json:"log"`
+				GasWanted string `json:"gas_wanted"`
+				GasUsed   string `json:"gas_used"`
+				Events    []struct {
+					Type       string `json:"type"`
+					Attributes []struct {
+						Key   string `json:"key"`
+						Value string `json:"value"`
+					} `json:"attributes"`
+				} `json:"events"`
+			} `json:"tx_result"`
+			Tx string `json:"tx"`
+		} `json:"txs"`
+		TotalCount string `json:"total_count"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// normalizeTxSearchPaging clamps page/perPage to what Tendermint accepts
+func normalizeTxSearchPaging(page, perPage int) (int, int) {
+	if perPage < 1 {
+		perPage = txSearchDefaultPerPage
+	}
+	if perPage > txSearchMaxPerPage {
+		perPage = txSearchMaxPerPage
+	}
+	if page < 1 {
+		page = 1
+	}
+	return page, perPage
+}
+
+// txSearch runs a Tendermint tx_search query for a single page, newest first.
+// Pages are 1-based; pages past the end (or past txSearchMaxResults) return an
+// empty result rather than the RPC's range error.
+func (s *Server) txSearch(ctx context.Context, query string, page, perPage int) (*txSearchResult, error) {
+	page, perPage = normalizeTxSearchPaging(page, perPage)
+	result := &txSearchResult{Txs: []txSearchTx{}, Page: page, PerPage: perPage}
+
+	if (page-1)*perPage >= txSearchMaxResults {
+		return result, nil
+	}
+
+	var raw *rpcTxSearchResponse
+	err := retry.Do(ctx, txSearchRetry, func(ctx context.Context) error {
+		var err error
+		raw, err = s.fetchTxSearchPage(ctx, query, page, perPage)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if raw.Error != nil {
+		// Tendermint reports an out-of-range page as an error; treat it as empty
+		if strings.Contains(raw.Error.Data, "page should be within") {
+			return result, nil
+		}
+		return nil, fmt.Errorf("tx_search failed: %s: %s", raw.Error.Message, raw.Error.Data)
+	}
+
+	total, _ := strconv.Atoi(raw.Result.TotalCount)
+	if total > txSearchMaxResults {
+		total = txSearchMaxResults
+	}
+	result.TotalCount = total
+
+	for _, t := range raw.Result.Txs {
+		height, _ := strconv.ParseInt(t.Height, 10, 64)
+		gasWanted, _ := strconv.ParseInt(t.TxResult.GasWanted, 10, 64)
+		gasUsed, _ := strconv.ParseInt(t.TxResult.GasUsed, 10, 64)
+
+		tx := txSearchTx{
+			Hash:      "0x" + strings.ToLower(t.Hash),
+			Height:    height,
+			Index:     t.Index,
+			Code:      t.TxResult.Code,
+			Log:       t.TxResult.Log,
+			GasWanted: gasWanted,
+			GasUsed:   gasUsed,
+			Tx:        t.Tx,
+		}
+		for _, ev := range t.TxResult.Events {
+			event := txSearchTxEvent{Type: ev.Type, Attributes: make(map[string]string, len(ev.Attributes))}
+			for _, attr := range ev.Attributes {
+				event.Attributes[attr.Key] = attr.Value
+			}
+			tx.Events = append(tx.Events, event)
+		}
+		result.Txs = append(result.Txs, tx)
+	}
+
+	return result, nil
+}
+
+// txSearchAll walks tx_search pages until maxResults txs are collected or results run out
+func (s *Server) txSearchAll(ctx context.Context, query string, maxResults int) ([]txSearchTx, error) {
+	if maxResults < 1 || maxResults > txSearchMaxResults {
+		maxResults = txSearchMaxResults
+	}
+
+	var txs []txSearchTx
+	for page := 1; len(txs) < maxResults; page++ {
+		res, err := s.txSearch(ctx, query, page, txSearchMaxPerPage)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, res.Txs...)
+		if !res.HasMore() || len(res.Txs) == 0 {
+			break
+		}
+	}
+
+	if len(txs) > maxResults {
+		txs = txs[:maxResults]
+	}
+	return txs, nil
+}
+
+// txSearchNewest walks tx_search pages until at least maxResults txs are
+// collected or results run out, returning them with the query's total_count
+func (s *Server) txSearchNewest(ctx context.Context, query string, maxResults int) ([]txSearchTx, int, error) {
+	var txs []txSearchTx
+	total := 0
+	for page := 1; ; page++ {
+		res, err := s.txSearch(ctx, query, page, txSearchMaxPerPage)
+		if err != nil {
+			return nil, 0, err
+		}
+		total = res.TotalCount
+		txs = append(txs, res.Txs...)
+		if len(txs) >= maxResults || !res.HasMore() || len(res.Txs) == 0 {
+			return txs, total, nil
+		}
+	}
+}
+
+// mergeTxSearchResults merges tx_search result sets newest first, dropping
+// transactions that appear in more than one
+func mergeTxSearchResults(sets ...[]txSearchTx) []txSearchTx {
+	seen := make(map[string]bool)
+	var merged []txSearchTx
+	for _, set := range sets {
+		for _, tx := range set {
+			if !seen[tx.Hash] {
+				seen[tx.Hash] = true
+				merged = append(merged, tx)
+			}
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Height != merged[j].Height {
+			return merged[i].Height > merged[j].Height
+		}
+		return merged[i].Index > merged[j].Index
+	})
+	return merged
+}
+
+// fetchTxSearchPage performs one tx_search RPC call; network errors, 429 and
+// 5xx without a JSON-RPC error are marked retry.Transient
+func (s *Server) fetchTxSearchPage(ctx context.Context, query string, page, perPage int) (*rpcTxSearchResponse, error) {
+	params := url.Values{}
+	params.Set("query", strconv.Quote(query))
+	params.Set("page", strconv.Itoa(page))
+	params.Set("per_page", strconv.Itoa(perPage))
+	params.Set("order_by", `"desc"`)
+	rpcURL := fmt.Sprintf("%s/tx_search?%s", s.config.ChainRPCURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rpcURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tx_search request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, retry.Transient(fmt.Errorf("tx_search request failed: %w", err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, retry.Transient(fmt.Errorf("failed to read tx_search response: %w", err))
+	}
+
+	if retry.RetryableStatus(resp.StatusCode) {
+		// Tendermint returns JSON-RPC errors (bad query, out-of-range page) with
+		// a 500 status; retrying them gives the same answer, so only a body
+		// without a JSON-RPC error is transient
+		var raw rpcTxSearchResponse
+		if json.Unmarshal(body, &raw) == nil && raw.Error != nil {
+			return &raw, nil
+		}
+		return nil, retry.Transient(fmt.Errorf("tx_search returned status %d", resp.StatusCode))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tx_search returned status %d", resp.StatusCode)
+	}
+
+	var raw rpcTxSearchResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse tx_search response: %w", err)
+	}
+	return &raw, nil
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockTxSearchRPC serves `total` fake txs through a paginated tx_search endpoint
+func mockTxSearchRPC(t *testing.T, total int, failures int32) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		if perPage > 100 {
+			t.Errorf("per_page %d exceeds Tendermint max", perPage)
+		}
+
+		pages := (total + perPage - 1) / perPage
+		if pages == 0 {
+			pages = 1
+		}
+		if page < 1 || page > pages {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"error":{"code":-32603,"message":"Internal error","data":"page should be within [1, %d] range, given %d"}}`, pages, page)
+			return
+		}
+
+		var txs []string
+		for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+			txs = append(txs, fmt.Sprintf(`{"hash":"%064X","height":"%d","index":0,"tx_result":{"code":0,"gas_wanted":"200000","gas_used":"%d","events":[{"type":"message","attributes":[{"key":"action","value":"send"}]}]},"tx":"dGVzdA=="}`, i, 1000-i, 50000+i))
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"result":{"txs":[%s],"total_count":"%d"}}`, strings.Join(txs, ","), total)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &calls
+}
+
+func newTxSearchTestServer(rpcURL string) *Server {
+	return &Server{config: &Config{ChainRPCURL: rpcURL}}
+}
+
+func shortenTxSearchBackoff(t *testing.T) {
+	t.Helper()
+	prev := txSearchRetry
+	txSearchRetry.BaseDelay = time.Millisecond
+	t.Cleanup(func() { txSearchRetry = prev })
+}
+
+// TestTxSearchMultiPage tests normalized paging across several pages
+func TestTxSearchMultiPage(t *testing.T) {
+	ts, _ := mockTxSearchRPC(t, 25, 0)
+	s := newTxSearchTestServer(ts.URL)
+
+	first, err := s.txSearch(context.Background(), "message.sender='cert1abc'", 0, 10)
+	if err != nil {
+		t.Fatalf("txSearch: %v", err)
+	}
+	if first.Page != 1 {
+		t.Errorf("page = %d, want 1 (normalized from 0)", first.Page)
+	}
+	if len(first.Txs) != 10 || first.TotalCount != 25 || !first.HasMore() {
+		t.Errorf("first page = %d txs, total %d, hasMore %v", len(first.Txs), first.TotalCount, first.HasMore())
+	}
+	if first.Txs[0].Height != 1000 || first.Txs[0].GasUsed != 50000 {
+		t.Errorf("decoded tx = %+v", first.Txs[0])
+	}
+	if !strings.HasPrefix(first.Txs[0].Hash, "0x") || first.Txs[0].Hash != strings.ToLower(first.Txs[0].Hash) {
+		t.Errorf("hash not normalized: %s", first.Txs[0].Hash)
+	}
+	if first.Txs[0].Events[0].Attributes["action"] != "send" {
+		t.Errorf("events not decoded: %+v", first.Txs[0].Events)
+	}
+
+	last, err := s.txSearch(context.Background(), "message.sender='cert1abc'", 3, 10)
+	if err != nil {
+		t.Fatalf("txSearch page 3: %v", err)
+	}
+	if len(last.Txs) != 5 || last.HasMore() {
+		t.Errorf("last page = %d txs, hasMore %v", len(last.Txs), last.HasMore())
+	}
+
+	// Past the end: Tendermint's range error becomes an empty page
+	past, err := s.txSearch(context.Background(), "message.sender='cert1abc'", 9, 10)
+	if err != nil {
+		t.Fatalf("txSearch past end: %v", err)
+	}
+	if len(past.Txs) != 0 {
+		t.Errorf("past-end page returned %d txs", len(past.Txs))
+	}
+
+	all, err := s.txSearchAll(context.Background(), "message.sender='cert1abc'", 0)
+	if err != nil {
+		t.Fatalf("txSearchAll: %v", err)
+	}
+	if len(all) != 25 {
+		t.Errorf("txSearchAll returned %d txs, want 25", len(all))
+	}
+
+	capped, err := s.txSearchAll(context.Background(), "message.sender='cert1abc'", 7)
+	if err != nil {
+		t.Fatalf("txSearchAll capped: %v", err)
+	}
+	if len(capped) != 7 {
+		t.Errorf("capped txSearchAll returned %d txs, want 7", len(capped))
+	}
+}
+
+// TestTxSearchPerPageClamp tests that per_page is clamped to the Tendermint max
+func TestTxSearchPerPageClamp(t *testing.T) {
+	ts, _ := mockTxSearchRPC(t, 150, 0)
+	s := newTxSearchTestServer(ts.URL)
+
+	res, err := s.txSearch(context.Background(), "tx.height>0", 1, 500)
+	if err != nil {
+		t.Fatalf("txSearch: %v", err)
+	}
+	if res.PerPage != txSearchMaxPerPage || len(res.Txs) != txSearchMaxPerPage {
+		t.Errorf("perPage = %d, txs = %d, want %d", res.PerPage, len(res.Txs), txSearchMaxPerPage)
+	}
+
+	// Pages beyond the result cap never hit the RPC
+	beyond, err := s.txSearch(context.Background(), "tx.height>0", txSearchMaxResults/10+1, 10)
+	if err != nil || len(beyond.Txs) != 0 {
+		t.Errorf("page beyond cap = %v, %v", beyond, err)
+	}
+}
+
+// TestTxSearchEmpty tests empty result handling
+func TestTxSearchEmpty(t *testing.T) {
+	ts, _ := mockTxSearchRPC(t, 0, 0)
+	s := newTxSearchTestServer(ts.URL)
+
+	res, err := s.txSearch(context.Background(), "message.sender='cert1nobody'", 1, 20)
+	if err != nil {
+		t.Fatalf("txSearch: %v", err)
+	}
+	if res.Txs == nil || len(res.Txs) != 0 || res.TotalCount != 0 || res.HasMore() {
+		t.Errorf("empty result = %+v", res)
+	}
+}
+
+// TestTxSearchRetry tests retries of transient RPC failures
+func TestTxSearchRetry(t *testing.T) {
+	shortenTxSearchBackoff(t)
+
+	t.Run("recovers", func(t *testing.T) {
+		ts, calls := mockTxSearchRPC(t, 3, 2)
+		s := newTxSearchTestServer(ts.URL)
+
+		res, err := s.txSearch(context.Background(), "tx.height>0", 1, 10)
+		if err != nil {
+			t.Fatalf("txSearch: %v", err)
+		}
+		if len(res.Txs) != 3 {
+			t.Errorf("txs = %d, want 3", len(res.Txs))
+		}
+		if got := atomic.LoadInt32(calls); got != 3 {
+			t.Errorf("RPC calls = %d, want 3", got)
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		ts, calls := mockTxSearchRPC(t, 3, 100)
+		s := newTxSearchTestServer(ts.URL)
+
+		if _, err := s.txSearch(context.Background(), "tx.height>0", 1, 10); err == nil {
+			t.Fatal("expected error after exhausting retries")
+		}
+		if got := atomic.LoadInt32(calls); got != int32(txSearchRetry.MaxAttempts) {
+			t.Errorf("RPC calls = %d, want %d", got, txSearchRetry.MaxAttempts)
+		}
+	})
+
+	t.Run("does not retry JSON-RPC errors", func(t *testing.T) {
+		var calls int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":-1,"error":{"code":-32603,"message":"Internal error","data":"failed to parse query: unexpected token"}}`)
+		}))
+		t.Cleanup(ts.Close)
+		s := newTxSearchTestServer(ts.URL)
+
+		_, err := s.txSearch(context.Background(), "tx.height>", 1, 10)
+		if err == nil || !strings.Contains(err.Error(), "failed to parse query") {
+			t.Fatalf("err = %v, want the RPC error data", err)
+		}
+		if got := atomic.LoadInt32(&calls); got != 1 {
+			t.Errorf("RPC calls = %d, want 1", got)
+		}
+	})
+}
@@ -1,15 +1,190 @@
 package app
 
 import (
+	"bytes"
+
+	errorsmod "cosmossdk.io/errors"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	ante "github.com/evmos/evmos/v20/app/ante"
+
+	attestationkeeper "github.com/chaincertify/certd/x/attestation/keeper"
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
 )
 
+// MaxAttestationsPerTx caps the attestations a single transaction may create
+// across all of its messages, so a tx cannot sidestep the MsgBatchAttest limit
+// by carrying many messages
+const MaxAttestationsPerTx = attestationtypes.MaxBatchAttestEntries
+
+// HandlerOptions extends the Evmos ante handler options with the keepers read
+// by the CERT attestation decorators
+type HandlerOptions struct {
+	ante.HandlerOptions
+
+	AttestationKeeper attestationkeeper.Keeper
+}
+
 // NewAnteHandler returns an ante handler responsible for attempting to route an
 // Ethereum or SDK transaction to an internal ante handler for performing
 // transaction-level processing (e.g. fee payment, signature verification) before
-// being passed onto it's respective handler.
-func NewAnteHandler(options ante.HandlerOptions) (sdk.AnteHandler, error) {
-	return ante.NewAnteHandler(options), nil
+// being passed onto it's respective handler. Attestation transactions are first
+// checked for spam, for the minimum tx fee and for the attestation fee, so they
+// are turned away before reaching the mempool.
+func NewAnteHandler(options HandlerOptions) (sdk.AnteHandler, error) {
+	attestationAnte := NewAttestationAnteHandler(options.AttestationKeeper)
+	evmosAnte := ante.NewAnteHandler(options.HandlerOptions)
+
+	return func(ctx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context, error) {
+		newCtx, err := attestationAnte(ctx, tx, simulate)
+		if err != nil {
+			return newCtx, err
+		}
+		return evmosAnte(newCtx, tx, simulate)
+	}, nil
+}
+
+// NewAttestationAnteHandler chains the attestation decorators
+func NewAttestationAnteHandler(k attestationkeeper.Keeper) sdk.AnteHandler {
+	return sdk.ChainAnteDecorators(
+		NewAttestationSpamDecorator(),
+		NewAttestationMinFeeDecorator(k),
+		NewAttestationFeeDecorator(k),
+	)
+}
+
+// AttestationSpamDecorator rejects transactions that create more than
+// MaxAttestationsPerTx attestations or repeat the same attestation
+type AttestationSpamDecorator struct{}
+
+// NewAttestationSpamDecorator returns an AttestationSpamDecorator
+func NewAttestationSpamDecorator() AttestationSpamDecorator {
+	return AttestationSpamDecorator{}
+}
+
+// AnteHandle implements sdk.AnteDecorator
+func (AttestationSpamDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	attestations := txAttestations(tx.GetMsgs())
+	if len(attestations) > MaxAttestationsPerTx {
+		return ctx, errorsmod.Wrapf(sdkerrors.ErrInvalidRequest,
+			"tx creates %d attestations, more than the %d allowed", len(attestations), MaxAttestationsPerTx)
+	}
+	for i := range attestations {
+		for j := 0; j < i; j++ {
+			if attestations[i].equal(attestations[j]) {
+				return ctx, errorsmod.Wrapf(sdkerrors.ErrInvalidRequest,
+					"tx repeats attestation %d as attestation %d", j, i)
+			}
+		}
+	}
+	return next(ctx, tx, simulate)
+}
+
+// AttestationMinFeeDecorator requires the tx fee to cover the attestation
+// module's MinAttestationTxFee param once for every attestation the tx creates.
+// Simulations carry no fee and are not checked.
+type AttestationMinFeeDecorator struct {
+	keeper attestationkeeper.Keeper
+}
+
+// NewAttestationMinFeeDecorator returns an AttestationMinFeeDecorator
+func NewAttestationMinFeeDecorator(k attestationkeeper.Keeper) AttestationMinFeeDecorator {
+	return AttestationMinFeeDecorator{keeper: k}
 }
 
+// AnteHandle implements sdk.AnteDecorator
+func (d AttestationMinFeeDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	count := len(txAttestations(tx.GetMsgs()))
+	if simulate || count == 0 {
+		return next(ctx, tx, simulate)
+	}
+
+	required := sdk.NewCoins()
+	for _, coin := range d.keeper.GetParams(ctx).MinAttestationTxFee {
+		required = required.Add(sdk.NewCoin(coin.Denom, coin.Amount.MulRaw(int64(count))))
+	}
+	if required.IsZero() {
+		return next(ctx, tx, simulate)
+	}
+
+	feeTx, ok := tx.(sdk.FeeTx)
+	if !ok {
+		return ctx, errorsmod.Wrap(sdkerrors.ErrTxDecode, "attestation tx must be a FeeTx")
+	}
+	if fee := feeTx.GetFee(); !fee.IsAllGTE(required) {
+		return ctx, errorsmod.Wrapf(sdkerrors.ErrInsufficientFee,
+			"fee %s is below the %s required for %d attestations", fee, required, count)
+	}
+	return next(ctx, tx, simulate)
+}
+
+// AttestationFeeDecorator turns away attestation txs whose attesters cannot
+// cover the attestation module's AttestationFee param once for every
+// attestation they create. The fee itself is charged when the message runs.
+type AttestationFeeDecorator struct {
+	keeper attestationkeeper.Keeper
+}
+
+// NewAttestationFeeDecorator returns an AttestationFeeDecorator
+func NewAttestationFeeDecorator(k attestationkeeper.Keeper) AttestationFeeDecorator {
+	return AttestationFeeDecorator{keeper: k}
+}
+
+// AnteHandle implements sdk.AnteDecorator
+func (d AttestationFeeDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	if d.keeper.GetParams(ctx).AttestationFee.IsZero() {
+		return next(ctx, tx, simulate)
+	}
+
+	counts := make(map[string]int)
+	var attesters []string
+	for _, a := range txAttestations(tx.GetMsgs()) {
+		if counts[a.attester] == 0 {
+			attesters = append(attesters, a.attester)
+		}
+		counts[a.attester]++
+	}
+
+	for _, attester := range attesters {
+		addr, err := sdk.AccAddressFromBech32(attester)
+		if err != nil {
+			return ctx, errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid attester address: %s", err)
+		}
+		if err := d.keeper.CheckAttestationFees(ctx, addr, counts[attester]); err != nil {
+			return ctx, errorsmod.Wrapf(err, "%d attestations by %s", counts[attester], attester)
+		}
+	}
+	return next(ctx, tx, simulate)
+}
+
+// txAttestation identifies one attestation a tx creates
+type txAttestation struct {
+	attester  string
+	schemaUID string
+	recipient string
+	data      []byte
+}
+
+func (a txAttestation) equal(other txAttestation) bool {
+	return a.attester == other.attester && a.schemaUID == other.schemaUID &&
+		a.recipient == other.recipient && bytes.Equal(a.data, other.data)
+}
+
+// txAttestations lists the attestations created by msgs. Encrypted attestations
+// are identified by their encrypted data hash.
+func txAttestations(msgs []sdk.Msg) []txAttestation {
+	var attestations []txAttestation
+	for _, msg := range msgs {
+		switch msg := msg.(type) {
+		case *attestationtypes.MsgAttest:
+			attestations = append(attestations, txAttestation{msg.Attester, msg.SchemaUID, msg.Recipient, msg.Data})
+		case *attestationtypes.MsgBatchAttest:
+			for _, entry := range msg.Entries {
+				attestations = append(attestations, txAttestation{msg.Attester, msg.SchemaUID, entry.Recipient, entry.Data})
+			}
+		case *attestationtypes.MsgCreateEncryptedAttestation:
+			attestations = append(attestations, txAttestation{msg.Attester, msg.SchemaUID, "", []byte(msg.EncryptedDataHash)})
+		}
+	}
+	return attestations
+}
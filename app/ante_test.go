@@ -0,0 +1,103 @@
+package app
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/stretchr/testify/require"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestAttestationAnteHandler(t *testing.T) {
+	app, ctx := setupTestApp(t)
+	params := app.AttestationKeeper.GetParams(ctx)
+	params.AttestationFee = sdk.NewCoins(sdk.NewInt64Coin(BondDenom, 1_000))
+	app.AttestationKeeper.SetParams(ctx, params)
+	anteHandler := NewAttestationAnteHandler(app.AttestationKeeper)
+
+	attester := sdk.AccAddress("attester____________")
+	fundAccount(t, app, ctx, attester, sdk.NewCoins(sdk.NewInt64Coin(BondDenom, 2_000)))
+	attest := func(data string) sdk.Msg {
+		return attestationtypes.NewMsgAttest(attester.String(), "schema", "", 0, true, "", []byte(data))
+	}
+	buildTx := func(t *testing.T, msgs ...sdk.Msg) sdk.Tx {
+		t.Helper()
+		builder := app.TxConfig().NewTxBuilder()
+		require.NoError(t, builder.SetMsgs(msgs...))
+		builder.SetGasLimit(200_000)
+		return builder.GetTx()
+	}
+	buildFeeTx := func(t *testing.T, amount int64, msgs ...sdk.Msg) sdk.Tx {
+		t.Helper()
+		builder := app.TxConfig().NewTxBuilder()
+		require.NoError(t, builder.SetMsgs(msgs...))
+		builder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin(BondDenom, amount)))
+		builder.SetGasLimit(200_000)
+		return builder.GetTx()
+	}
+
+	t.Run("accepts attestations the attester can pay for", func(t *testing.T) {
+		_, err := anteHandler(ctx, buildTx(t, attest("degree")), false)
+		require.NoError(t, err)
+		_, err = anteHandler(ctx, buildTx(t, attest("degree"), attest("diploma")), false)
+		require.NoError(t, err)
+
+		// Txs without attestations are not checked
+		broke := sdk.AccAddress("broke_______________").String()
+		_, err = anteHandler(ctx, buildTx(t, attestationtypes.NewMsgRevoke(broke, "uid")), false)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects attestations beyond the attester's balance", func(t *testing.T) {
+		// The fee is charged per attestation
+		_, err := anteHandler(ctx, buildTx(t, attest("degree"), attest("diploma"), attest("transcript")), false)
+		require.ErrorIs(t, err, sdkerrors.ErrInsufficientFunds)
+
+		broke := sdk.AccAddress("broke_______________").String()
+		_, err = anteHandler(ctx, buildTx(t, attestationtypes.NewMsgAttest(broke, "schema", "", 0, true, "", []byte("degree"))), false)
+		require.ErrorIs(t, err, sdkerrors.ErrInsufficientFunds)
+	})
+
+	t.Run("rejects an attestation below the minimum fee", func(t *testing.T) {
+		params := app.AttestationKeeper.GetParams(ctx)
+		params.MinAttestationTxFee = sdk.NewCoins(sdk.NewInt64Coin(BondDenom, 500))
+		app.AttestationKeeper.SetParams(ctx, params)
+		t.Cleanup(func() {
+			params.MinAttestationTxFee = nil
+			app.AttestationKeeper.SetParams(ctx, params)
+		})
+
+		_, err := anteHandler(ctx, buildFeeTx(t, 499, attest("degree")), false)
+		require.ErrorIs(t, err, sdkerrors.ErrInsufficientFee)
+
+		// The minimum is per attestation
+		_, err = anteHandler(ctx, buildFeeTx(t, 500, attest("degree"), attest("diploma")), false)
+		require.ErrorIs(t, err, sdkerrors.ErrInsufficientFee)
+
+		_, err = anteHandler(ctx, buildFeeTx(t, 1_000, attest("degree"), attest("diploma")), false)
+		require.NoError(t, err)
+
+		// Simulations carry no fee
+		_, err = anteHandler(ctx, buildTx(t, attest("degree")), true)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects spammy attestation txs", func(t *testing.T) {
+		_, err := anteHandler(ctx, buildTx(t, attest("degree"), attest("degree")), false)
+		require.ErrorIs(t, err, sdkerrors.ErrInvalidRequest)
+
+		entries := make([]attestationtypes.BatchAttestEntry, MaxAttestationsPerTx)
+		for i := range entries {
+			entries[i] = attestationtypes.BatchAttestEntry{Data: []byte{byte(i)}}
+		}
+		batch := &attestationtypes.MsgBatchAttest{Attester: attester.String(), SchemaUID: "schema", Entries: entries}
+		_, err = anteHandler(ctx, buildTx(t, batch, attest("degree")), false)
+		require.ErrorIs(t, err, sdkerrors.ErrInvalidRequest)
+
+		fundAccount(t, app, ctx, attester, sdk.NewCoins(sdk.NewInt64Coin(BondDenom, 1_000*MaxAttestationsPerTx)))
+		_, err = anteHandler(ctx, buildTx(t, batch), false)
+		require.NoError(t, err)
+	})
+}
@@ -11,6 +11,7 @@ import (
 
 	dbm "github.com/cosmos/cosmos-db"
 	"github.com/cosmos/gogoproto/grpc"
+	"github.com/spf13/cast"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	protov2 "google.golang.org/protobuf/proto"
@@ -156,6 +157,8 @@ var (
 		govtypes.ModuleName:  {authtypes.Burner},
 		evmtypes.ModuleName:  {authtypes.Minter, authtypes.Burner},
 		feemarkettypes.ModuleName: nil,
+		// Accumulates schema fees; governance may burn them
+		attestationtypes.ModuleName: {authtypes.Burner},
 	}
 )
 
@@ -252,6 +255,10 @@ type CertApp struct {
 
 	// Simulation Manager (for testing)
 	sm *module.SimulationManager
+
+	// Module invariants, asserted every invCheckPeriod blocks (0 disables)
+	invariants     invariantRegistry
+	invCheckPeriod uint
 }
 
 // NewCertApp creates and initializes a new CERT blockchain application
@@ -385,6 +392,9 @@ func NewCertApp(
 	bApp.SetMempool(nonceMempool)
 
 	bApp.SetCommitMultiStoreTracer(traceStore)
+	if err := setupSnapshots(bApp, appOpts); err != nil {
+		panic(err)
+	}
 	bApp.SetVersion("v1.0.0")
 	bApp.SetInterfaceRegistry(interfaceRegistry)
 	bApp.SetTxEncoder(txConfig.TxEncoder())
@@ -501,6 +511,8 @@ func NewCertApp(
 		nil, // memKey - not used
 		authtypes.NewModuleAddress(govtypes.ModuleName).String(),
 	)
+	// Schema fees are paid through the bank module
+	certApp.AttestationKeeper.SetBankKeeper(certApp.BankKeeper)
 
 	// Initialize CertID keeper
 	certApp.CertIDKeeper = certidkeeper.NewKeeper(
@@ -686,7 +698,20 @@ func NewCertApp(
 			evmtypes.ModuleName,
 			feemarkettypes.ModuleName,
 			upgradetypes.ModuleName,
+			hardwaretypes.ModuleName,
+			trustscoretypes.ModuleName,
+	)
+
+	// Invariants are asserted in EndBlocker in place of the disabled crisis module
+	certApp.invCheckPeriod = cast.ToUint(appOpts.Get(server.FlagInvCheckPeriod))
+	certApp.ModuleManager.RegisterInvariants(&certApp.invariants)
+
+	// Simulation covers the attestation module; the remaining modules are
+	// exercised by their upstream simulations
+	certApp.sm = module.NewSimulationManager(
+		attestationmodule.NewAppModule(appCodec, certApp.AttestationKeeper, certApp.AccountKeeper, certApp.BankKeeper),
 	)
+	certApp.sm.RegisterStoreDecoders()
 
 	// Register services (message handlers and query handlers)
 	// This is REQUIRED in Cosmos SDK v0.50.x for message routing to work
@@ -717,7 +742,10 @@ func NewCertApp(
 		TxFeeChecker:           evmante.NewDynamicFeeChecker(certApp.EvmKeeper),
 	}
 
-	anteHandler, err := NewAnteHandler(anteOptions)
+	anteHandler, err := NewAnteHandler(HandlerOptions{
+		HandlerOptions:    anteOptions,
+		AttestationKeeper: certApp.AttestationKeeper,
+	})
 	if err != nil {
 		panic(fmt.Sprintf("failed to create ante handler: %v", err))
 	}
@@ -924,7 +952,14 @@ func (app *CertApp) BeginBlocker(ctx sdk.Context) (sdk.BeginBlock, error) {
 
 // EndBlocker application updates every end block
 func (app *CertApp) EndBlocker(ctx sdk.Context) (sdk.EndBlock, error) {
-	return app.ModuleManager.EndBlock(ctx)
+	res, err := app.ModuleManager.EndBlock(ctx)
+	if err != nil {
+		return res, err
+	}
+	if app.invCheckPeriod != 0 && ctx.BlockHeight()%int64(app.invCheckPeriod) == 0 {
+		app.AssertInvariants(ctx)
+	}
+	return res, nil
 }
 
 // RegisterAPIRoutes registers all application module routes with the provided API server
@@ -994,6 +1029,10 @@ func (app *CertApp) RegisterGRPCServer(grpcServer grpc.Server) {
 	attestationtypes.RegisterQueryServer(grpcServer, attestationkeeper.NewQueryServerImpl(app.AttestationKeeper))
 	attestationtypes.RegisterMsgServer(grpcServer, attestationkeeper.NewMsgServerImpl(app.AttestationKeeper))
 
+	// The hardware module's query and message types are manually defined as well
+	hardwaretypes.RegisterQueryServer(grpcServer, hardwarekeeper.NewQueryServerImpl(app.HardwareKeeper))
+	hardwaretypes.RegisterMsgServer(grpcServer, hardwarekeeper.NewMsgServerImpl(app.HardwareKeeper))
+
 	// Register certid module gRPC services directly
 	// Note: These are now registered via module.RegisterServices() in module.go
 
@@ -1009,6 +1048,11 @@ func (app *CertApp) AppCodec() codec.Codec {
 	return app.appCodec
 }
 
+// SimulationManager returns the app's simulation manager
+func (app *CertApp) SimulationManager() *module.SimulationManager {
+	return app.sm
+}
+
 // InterfaceRegistry returns the app's interface registry
 func (app *CertApp) InterfaceRegistry() codectypes.InterfaceRegistry {
 	return app.interfaceRegistry
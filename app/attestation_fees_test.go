@@ -0,0 +1,113 @@
+package app
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/stretchr/testify/require"
+
+	attestationkeeper "github.com/chaincertify/certd/x/attestation/keeper"
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+// TestMain sets the cert bech32 prefixes before any address is formatted
+func TestMain(m *testing.M) {
+	SetConfig()
+	os.Exit(m.Run())
+}
+
+// setupTestApp returns an in-memory CertApp and a context over its latest state
+func setupTestApp(t *testing.T) (*CertApp, sdk.Context) {
+	t.Helper()
+	app := NewCertApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, simtestutil.EmptyAppOptions{})
+	ctx := app.BaseApp.NewUncachedContext(false, cmtproto.Header{Height: 1, Time: time.Unix(1_700_000_000, 0).UTC()})
+	return app, ctx
+}
+
+// fundAccount mints amt to addr through the mint module account
+func fundAccount(t *testing.T, app *CertApp, ctx sdk.Context, addr sdk.AccAddress, amt sdk.Coins) {
+	t.Helper()
+	require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, amt))
+	require.NoError(t, app.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, addr, amt))
+}
+
+func TestAttestationModuleAccountBlocked(t *testing.T) {
+	require.True(t, BlockedAddresses()[attestationtypes.ModuleAddress.String()])
+	require.Contains(t, GetMaccPerms()[attestationtypes.ModuleName], authtypes.Burner)
+	require.Equal(t, authtypes.NewModuleAddress(attestationtypes.ModuleName), attestationtypes.ModuleAddress)
+}
+
+func TestAttestationFeesAccrueAndWithdraw(t *testing.T) {
+	app, ctx := setupTestApp(t)
+	gov := authtypes.NewModuleAddress(govtypes.ModuleName).String()
+	creator := sdk.AccAddress("schema_creator______")
+	attester := sdk.AccAddress("attester____________")
+	treasury := sdk.AccAddress("community_treasury__")
+	fundAccount(t, app, ctx, attester, sdk.NewCoins(sdk.NewInt64Coin(BondDenom, 10_000)))
+
+	msgServer := attestationkeeper.NewMsgServerImpl(app.AttestationKeeper)
+	queryServer := attestationkeeper.NewQueryServerImpl(app.AttestationKeeper)
+
+	fee := sdk.NewCoins(sdk.NewInt64Coin(BondDenom, 1_000))
+	schemaUID, err := app.AttestationKeeper.RegisterSchemaWithFee(ctx, creator, "bytes32 degreeHash", nil, true, fee, 2_000)
+	require.NoError(t, err)
+	for _, data := range []string{"degree", "diploma", "transcript"} {
+		_, err := app.AttestationKeeper.CreateAttestation(ctx, attester, schemaUID, nil, time.Time{}, true, "", []byte(data))
+		require.NoError(t, err)
+	}
+
+	// Three attestations: 600 to the creator, 2,400 to the module account
+	balanceOf := func(addr sdk.AccAddress) sdkmath.Int {
+		return app.BankKeeper.GetBalance(ctx, addr, BondDenom).Amount
+	}
+	require.Equal(t, sdkmath.NewInt(7_000), balanceOf(attester))
+	require.Equal(t, sdkmath.NewInt(600), balanceOf(creator))
+	require.Equal(t, sdkmath.NewInt(2_400), balanceOf(attestationtypes.ModuleAddress))
+	require.True(t, balanceOf(authtypes.NewModuleAddress(authtypes.FeeCollectorName)).IsZero())
+
+	res, err := queryServer.FeeBalance(ctx, &attestationtypes.QueryFeeBalanceRequest{})
+	require.NoError(t, err)
+	require.Equal(t, attestationtypes.ModuleAddress.String(), res.Address)
+	require.Equal(t, BondDenom, res.FeeDenom)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(BondDenom, 2_400)), res.Balance)
+
+	t.Run("only governance may withdraw", func(t *testing.T) {
+		_, err := msgServer.WithdrawFees(ctx, attestationtypes.NewMsgWithdrawFees(creator.String(), nil, treasury.String(), false))
+		require.ErrorIs(t, err, attestationtypes.ErrUnauthorized)
+		require.Equal(t, sdkmath.NewInt(2_400), balanceOf(attestationtypes.ModuleAddress))
+	})
+
+	t.Run("pays part of the balance to the treasury", func(t *testing.T) {
+		amount := sdk.NewCoins(sdk.NewInt64Coin(BondDenom, 1_000))
+		out, err := msgServer.WithdrawFees(ctx, attestationtypes.NewMsgWithdrawFees(gov, amount, treasury.String(), false))
+		require.NoError(t, err)
+		require.Equal(t, amount, out.Amount)
+		require.Equal(t, sdkmath.NewInt(1_000), balanceOf(treasury))
+		require.Equal(t, sdkmath.NewInt(1_400), balanceOf(attestationtypes.ModuleAddress))
+
+		_, err = msgServer.WithdrawFees(ctx, attestationtypes.NewMsgWithdrawFees(gov, fee.MulInt(sdkmath.NewInt(5)), treasury.String(), false))
+		require.ErrorIs(t, err, attestationtypes.ErrInvalidFeeWithdrawal)
+	})
+
+	t.Run("burns the remaining balance", func(t *testing.T) {
+		supply := app.BankKeeper.GetSupply(ctx, BondDenom).Amount
+		out, err := msgServer.WithdrawFees(ctx, attestationtypes.NewMsgWithdrawFees(gov, nil, "", true))
+		require.NoError(t, err)
+		require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(BondDenom, 1_400)), out.Amount)
+		require.True(t, balanceOf(attestationtypes.ModuleAddress).IsZero())
+		require.Equal(t, supply.SubRaw(1_400), app.BankKeeper.GetSupply(ctx, BondDenom).Amount)
+
+		_, err = msgServer.WithdrawFees(ctx, attestationtypes.NewMsgWithdrawFees(gov, nil, "", true))
+		require.ErrorIs(t, err, attestationtypes.ErrInvalidFeeWithdrawal)
+	})
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"slices"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	hardwarekeeper "github.com/chaincertify/certd/x/hardware/keeper"
+	hardwaretypes "github.com/chaincertify/certd/x/hardware/types"
+)
+
+func TestHardwareModuleWiring(t *testing.T) {
+	app, ctx := setupTestApp(t)
+
+	require.Contains(t, app.ModuleManager.Modules, hardwaretypes.ModuleName)
+	for name, order := range map[string][]string{
+		"init genesis":  app.ModuleManager.OrderInitGenesis,
+		"begin blocker": app.ModuleManager.OrderBeginBlockers,
+		"end blocker":   app.ModuleManager.OrderEndBlockers,
+	} {
+		require.True(t, slices.Contains(order, hardwaretypes.ModuleName), "hardware module missing from the %s order", name)
+	}
+
+	grpcServer := grpc.NewServer()
+	app.RegisterGRPCServer(grpcServer)
+	services := grpcServer.GetServiceInfo()
+	methods := func(service string) []string {
+		var names []string
+		for _, method := range services[service].Methods {
+			names = append(names, method.Name)
+		}
+		return names
+	}
+	require.Subset(t, methods("cert.hardware.v1.Query"), []string{"Device", "DevicesByOwner", "Stats", "HumanityScore"})
+	require.Subset(t, methods("cert.hardware.v1.Msg"), []string{"RegisterDevice", "RequestAttestationChallenge", "SubmitAttestation", "TransferDevice", "SetFirmwareRelease", "ReportFirmware", "SetManufacturer", "RemoveManufacturer", "SetManufacturerStrictMode"})
+
+	// The keeper reads and writes the mounted hardware store
+	owner := sdk.AccAddress("device_owner________").String()
+	res, err := hardwarekeeper.NewMsgServerImpl(app.HardwareKeeper).RegisterDevice(ctx, &hardwaretypes.MsgRegisterDevice{
+		Creator:            owner,
+		Manufacturer:       "Acme",
+		TEEType:            hardwaretypes.TEETypeTrustZone,
+		PublicKey:          []byte("pubkey"),
+		InitialAttestation: []byte("attestation"),
+	})
+	require.NoError(t, err)
+	device, err := hardwarekeeper.NewQueryServerImpl(app.HardwareKeeper).Device(ctx, &hardwaretypes.QueryDeviceRequest{DeviceID: res.DeviceID})
+	require.NoError(t, err)
+	require.Equal(t, owner, device.Device.OwnerAddress)
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Invariant checks
+// The crisis module is disabled (see NewCertApp), so CertApp collects module
+// invariants itself and asserts them in EndBlocker every --inv-check-period
+// blocks. The flag defaults to 0, which turns the checks off; set it on dev and
+// test nodes only, since a broken invariant halts the node.
+
+// invariantRoute is one registered module invariant
+type invariantRoute struct {
+	module string
+	route  string
+	check  sdk.Invariant
+}
+
+// invariantRegistry is the sdk.InvariantRegistry modules register their invariants with
+type invariantRegistry struct {
+	routes []invariantRoute
+}
+
+var _ sdk.InvariantRegistry = (*invariantRegistry)(nil)
+
+// RegisterRoute registers an invariant under moduleName/route
+func (r *invariantRegistry) RegisterRoute(moduleName, route string, invar sdk.Invariant) {
+	r.routes = append(r.routes, invariantRoute{module: moduleName, route: route, check: invar})
+}
+
+// AssertInvariants runs every registered invariant, panicking on the first broken one
+func (app *CertApp) AssertInvariants(ctx sdk.Context) {
+	for _, r := range app.invariants.routes {
+		if msg, broken := r.check(ctx); broken {
+			app.Logger().Error("invariant broken", "module", r.module, "route", r.route, "height", ctx.BlockHeight())
+			panic(fmt.Errorf("invariant %s/%s broken at height %d: %s", r.module, r.route, ctx.BlockHeight(), msg))
+		}
+	}
+	app.Logger().Info("asserted all invariants", "count", len(app.invariants.routes), "height", ctx.BlockHeight())
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/server"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/stretchr/testify/require"
+
+	attestationtypes "github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestAssertInvariants(t *testing.T) {
+	app := NewCertApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, simtestutil.AppOptionsMap{server.FlagInvCheckPeriod: 1})
+	ctx := app.BaseApp.NewUncachedContext(false, cmtproto.Header{Height: 1, Time: time.Unix(1_700_000_000, 0).UTC()})
+	require.Equal(t, uint(1), app.invCheckPeriod)
+
+	var routes []string
+	for _, r := range app.invariants.routes {
+		if r.module == attestationtypes.ModuleName {
+			routes = append(routes, r.route)
+		}
+	}
+	require.ElementsMatch(t, []string{"attestation-counts", "attestation-indexes", "encrypted-recipient-keys"}, routes)
+
+	// The staking invariants need a bond denom; nothing here runs InitChain
+	params := stakingtypes.DefaultParams()
+	params.BondDenom = BondDenom
+	require.NoError(t, app.StakingKeeper.SetParams(ctx, params))
+
+	attester := sdk.AccAddress("attester____________")
+	schemaUID, err := app.AttestationKeeper.RegisterSchema(ctx, attester, "bytes32 degreeHash", nil, true)
+	require.NoError(t, err)
+	_, err = app.AttestationKeeper.CreateAttestation(ctx, attester, schemaUID, nil, time.Time{}, true, "", []byte("degree"))
+	require.NoError(t, err)
+	require.NotPanics(t, func() { app.AssertInvariants(ctx) })
+
+	// Drop the attestation record, leaving the counter and indexes behind
+	store := ctx.KVStore(app.keys[attestationtypes.StoreKey])
+	iterator := storetypes.KVStorePrefixIterator(store, attestationtypes.AttestationKeyPrefix)
+	require.True(t, iterator.Valid())
+	key := iterator.Key()
+	iterator.Close()
+	store.Delete(key)
+
+	defer func() {
+		err, ok := recover().(error)
+		require.True(t, ok)
+		require.Contains(t, err.Error(), "attestation/attestation-counts")
+	}()
+	app.AssertInvariants(ctx)
+}
+
+func TestInvariantsDisabledByDefault(t *testing.T) {
+	app, _ := setupTestApp(t)
+	require.Zero(t, app.invCheckPeriod)
+}
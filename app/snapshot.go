@@ -0,0 +1,41 @@
+package app
+
+import (
+	"fmt"
+
+	snapshottypes "cosmossdk.io/store/snapshots/types"
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/server"
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	"github.com/spf13/cast"
+)
+
+// State sync snapshot defaults written to a new node's app.toml. New validators
+// bootstrap from a peer's snapshot instead of replaying from genesis, so every
+// node serves recent snapshots unless its operator turns them off.
+const (
+	DefaultSnapshotInterval   = 1000
+	DefaultSnapshotKeepRecent = 2
+)
+
+// setupSnapshots attaches a snapshot manager to bApp from the state-sync app
+// options. The server commands already pass one through
+// server.DefaultBaseappOptions; this covers callers that construct the app
+// without those options. Snapshots are stored under the node home, so nothing
+// is set up without one.
+func setupSnapshots(bApp *baseapp.BaseApp, appOpts servertypes.AppOptions) error {
+	if bApp.SnapshotManager() != nil || cast.ToString(appOpts.Get(flags.FlagHome)) == "" {
+		return nil
+	}
+
+	snapshotStore, err := server.GetSnapshotStore(appOpts)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot store: %w", err)
+	}
+	bApp.SetSnapshot(snapshotStore, snapshottypes.NewSnapshotOptions(
+		cast.ToUint64(appOpts.Get(server.FlagStateSyncSnapshotInterval)),
+		cast.ToUint32(appOpts.Get(server.FlagStateSyncSnapshotKeepRecent)),
+	))
+	return nil
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cosmossdk.io/log"
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/cosmos/cosmos-sdk/testutil/mock"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/stretchr/testify/require"
+)
+
+const snapshotTestChainID = "cert_4283207343-1"
+
+func TestStateSyncSnapshots(t *testing.T) {
+	genesisTime := time.Unix(1_700_000_000, 0).UTC()
+	newApp := func(t *testing.T) *CertApp {
+		return NewCertApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, simtestutil.AppOptionsMap{
+			flags.FlagHome:                         t.TempDir(),
+			server.FlagStateSyncSnapshotInterval:   2,
+			server.FlagStateSyncSnapshotKeepRecent: 2,
+		}, baseapp.SetChainID(snapshotTestChainID))
+	}
+
+	source := newApp(t)
+	require.NotNil(t, source.SnapshotManager())
+
+	pubKey, err := mock.NewPV().GetPubKey()
+	require.NoError(t, err)
+	valSet := cmttypes.NewValidatorSet([]*cmttypes.Validator{cmttypes.NewValidator(pubKey, 1)})
+	holder := sdk.AccAddress("snapshot_holder_____")
+	genesis, err := simtestutil.GenesisStateWithValSet(source.appCodec, NewDefaultGenesisState(source.appCodec), valSet,
+		[]authtypes.GenesisAccount{authtypes.NewBaseAccount(holder, nil, 0, 0)})
+	require.NoError(t, err)
+	appState, err := json.Marshal(genesis)
+	require.NoError(t, err)
+	_, err = source.InitChain(&abci.RequestInitChain{
+		ChainId:         snapshotTestChainID,
+		Time:            genesisTime,
+		AppStateBytes:   appState,
+		ConsensusParams: simtestutil.DefaultConsensusParams,
+	})
+	require.NoError(t, err)
+
+	// Snapshots are taken in the background after the commit at each interval,
+	// and one still running makes the manager skip the next; wait on each as a
+	// real two-second block time would
+	var snapshots []*abci.Snapshot
+	waitForSnapshot := func(height uint64) {
+		require.Eventually(t, func() bool {
+			res, err := source.ListSnapshots(&abci.RequestListSnapshots{})
+			require.NoError(t, err)
+			snapshots = res.Snapshots
+			return len(snapshots) > 0 && snapshots[0].Height == height
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+
+	appHashes := map[int64][]byte{}
+	for height := int64(1); height <= 5; height++ {
+		res, err := source.FinalizeBlock(&abci.RequestFinalizeBlock{
+			Height: height,
+			Time:   genesisTime.Add(time.Duration(height) * 2 * time.Second),
+		})
+		require.NoError(t, err)
+		_, err = source.Commit()
+		require.NoError(t, err)
+		appHashes[height] = res.AppHash
+		if height%2 == 0 {
+			waitForSnapshot(uint64(height))
+		}
+	}
+	require.Len(t, snapshots, 2)
+	require.Equal(t, []uint64{4, 2}, []uint64{snapshots[0].Height, snapshots[1].Height})
+
+	t.Run("a fresh app restores from the latest snapshot", func(t *testing.T) {
+		snapshot := snapshots[0]
+		target := newApp(t)
+		require.Zero(t, target.LastBlockHeight())
+		offer, err := target.OfferSnapshot(&abci.RequestOfferSnapshot{
+			Snapshot: snapshot,
+			AppHash:  appHashes[int64(snapshot.Height)],
+		})
+		require.NoError(t, err)
+		require.Equal(t, abci.ResponseOfferSnapshot_ACCEPT, offer.Result)
+
+		for index := uint32(0); index < snapshot.Chunks; index++ {
+			chunk, err := source.LoadSnapshotChunk(&abci.RequestLoadSnapshotChunk{
+				Height: snapshot.Height,
+				Format: snapshot.Format,
+				Chunk:  index,
+			})
+			require.NoError(t, err)
+			applied, err := target.ApplySnapshotChunk(&abci.RequestApplySnapshotChunk{Index: index, Chunk: chunk.Chunk})
+			require.NoError(t, err)
+			require.Equal(t, abci.ResponseApplySnapshotChunk_ACCEPT, applied.Result)
+		}
+
+		require.Equal(t, int64(snapshot.Height), target.LastBlockHeight())
+		require.Equal(t, appHashes[int64(snapshot.Height)], target.LastCommitID().Hash)
+
+		ctx := target.NewUncachedContext(false, cmtproto.Header{Height: target.LastBlockHeight()})
+		require.True(t, target.AccountKeeper.HasAccount(ctx, holder))
+	})
+}
+
+func TestSnapshotsNeedHome(t *testing.T) {
+	app := NewCertApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, simtestutil.AppOptionsMap{
+		server.FlagStateSyncSnapshotInterval: 2,
+	})
+	require.Nil(t, app.SnapshotManager())
+}
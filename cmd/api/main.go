@@ -7,9 +7,12 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"go.uber.org/zap"
 
 	"github.com/chaincertify/certd/api"
@@ -29,6 +32,11 @@ func main() {
 	// Create API server
 	server := api.NewServer(config, logger)
 
+	// Reload the bridge chain list from BRIDGE_CHAINS_FILE on SIGHUP
+	if path := os.Getenv("BRIDGE_CHAINS_FILE"); path != "" {
+		go reloadBridgeChainsOnHUP(server, path, logger)
+	}
+
 	// Start server in goroutine
 	go func() {
 		if err := server.Start(); err != nil {
@@ -84,6 +92,9 @@ func loadConfig() *api.Config {
 	if chainRPC := os.Getenv("CHAIN_RPC_URL"); chainRPC != "" {
 		config.ChainRPCURL = chainRPC
 	}
+	if chainGRPC := os.Getenv("CHAIN_GRPC_ADDR"); chainGRPC != "" {
+		config.ChainGRPCAddr = chainGRPC
+	}
 	if chainID := os.Getenv("CERT_TX_CHAIN_ID"); chainID != "" {
 		config.ChainID = chainID
 	} else if chainID := os.Getenv("CERT_CHAIN_ID"); chainID != "" {
@@ -131,5 +142,266 @@ func loadConfig() *api.Config {
 		}
 	}
 
+	// Parse batch size limits; a limit must be a positive item count
+	for env, limit := range map[string]*int{
+		"BATCH_MAX_VERIFY_ACTIONS":  &config.BatchLimits.VerifyActions,
+		"BATCH_MAX_SYBIL_ADDRESSES": &config.BatchLimits.SybilAddresses,
+		"BATCH_MAX_RECIPIENTS":      &config.BatchLimits.Recipients,
+		"BATCH_MAX_REVOKE_UIDS":     &config.BatchLimits.RevokeUIDs,
+		"BATCH_MAX_LOOKUP_UIDS":     &config.BatchLimits.LookupUIDs,
+	} {
+		if v := os.Getenv(env); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				panic("Invalid " + env + ": " + v)
+			}
+			*limit = n
+		}
+	}
+
+	// Parse load shedding thresholds
+	if v := os.Getenv("LOAD_SHED_MAX_INFLIGHT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.LoadShedding.MaxInFlight = n
+		}
+	}
+	if v := os.Getenv("LOAD_SHED_MAX_P99_LATENCY"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.LoadShedding.MaxP99Latency = d
+		}
+	}
+
+	// Internal service token keys, e.g. "bridge-validator=<secret>"
+	if v := os.Getenv("SERVICE_SECRETS"); v != "" {
+		config.ServiceSecrets = api.ParseServiceSecrets(v)
+	}
+
+	// Addresses allowed to call /api/v1/admin endpoints, comma-separated
+	if v := os.Getenv("ADMIN_ADDRESSES"); v != "" {
+		for _, addr := range strings.Split(v, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				config.AdminAddresses = append(config.AdminAddresses, addr)
+			}
+		}
+	}
+
+	// Balance lookup source order, e.g. "grpc,rest,db_estimate"
+	if v := os.Getenv("BALANCE_SOURCES"); v != "" {
+		config.BalanceSources.Order = strings.Split(v, ",")
+	}
+	// The REST source defaults to the node's REST gateway used by the chain client
+	if v := os.Getenv("BALANCE_REST_URL"); v != "" {
+		config.BalanceSources.RESTURL = v
+	} else if v := os.Getenv("COSMOS_REST_URL"); v != "" {
+		config.BalanceSources.RESTURL = v
+	}
+
+	// Decimals rendered for display amounts (0-6 for CERT)
+	if v := os.Getenv("DENOM_DISPLAY_PRECISION"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.Denom.DisplayPrecision = n
+		}
+	}
+
+	// Block results poll interval for humanity score webhooks ("0" disables delivery)
+	if v := os.Getenv("HUMANITY_WEBHOOK_POLL_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.HumanityWebhooks.PollInterval = d
+		}
+	}
+
+	// Expiring attestation poll interval for expiry reminders ("0" disables reminders)
+	if v := os.Getenv("EXPIRY_REMINDER_POLL_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.ExpiryReminders.PollInterval = d
+		}
+	}
+
+	// Lead times before expiry at which reminders are sent, e.g. "720h,168h,24h"
+	if v := os.Getenv("EXPIRY_REMINDER_MILESTONES"); v != "" {
+		var milestones []time.Duration
+		for _, part := range strings.Split(v, ",") {
+			d, err := time.ParseDuration(strings.TrimSpace(part))
+			if err != nil || d <= 0 {
+				panic("Invalid EXPIRY_REMINDER_MILESTONES entry: " + part)
+			}
+			milestones = append(milestones, d)
+		}
+		config.ExpiryReminders.Milestones = milestones
+	}
+
+	// Hex secp256k1 key that signs identity exports; its address is the published platform signer
+	if v := os.Getenv("PROFILE_SIGNING_KEY"); v != "" {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(v, "0x"))
+		if err != nil {
+			panic("Invalid PROFILE_SIGNING_KEY: " + err.Error())
+		}
+		config.ProfileSigningKey = key
+	}
+
+	// OAuth social verification; tokens are encrypted under SOCIAL_OAUTH_TOKEN_KEY (32 bytes, hex)
+	if v := os.Getenv("SOCIAL_OAUTH_TOKEN_KEY"); v != "" {
+		key, err := api.ParseSocialOAuthTokenKey(v)
+		if err != nil {
+			panic("Invalid SOCIAL_OAUTH_TOKEN_KEY: " + err.Error())
+		}
+		config.SocialOAuth.TokenKey = key
+	}
+	for platform, env := range map[string]string{"twitter": "SOCIAL_OAUTH_TWITTER", "linkedin": "SOCIAL_OAUTH_LINKEDIN"} {
+		provider := config.SocialOAuth.Providers[platform]
+		if v := os.Getenv(env + "_CLIENT_ID"); v != "" {
+			provider.ClientID = v
+		}
+		if v := os.Getenv(env + "_CLIENT_SECRET"); v != "" {
+			provider.ClientSecret = v
+		}
+		if v := os.Getenv(env + "_REDIRECT_URL"); v != "" {
+			provider.RedirectURL = v
+		}
+		config.SocialOAuth.Providers[platform] = provider
+	}
+
+	// Social verification codes an address can generate per platform per hour ("0" disables the limit)
+	if v := os.Getenv("SOCIAL_CODE_MAX_PER_HOUR"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.SocialCodes.MaxPerHour = n
+		}
+	}
+
+	// How often expired unverified social codes are deleted ("0" disables the sweep)
+	if v := os.Getenv("SOCIAL_CODE_CLEANUP_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.SocialCodes.CleanupInterval = d
+		}
+	}
+
+	// How long a KYC approval stays valid before re-verification, e.g. "8760h" ("0" never expires)
+	if v := os.Getenv("KYC_VALIDITY_PERIOD"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.KYC.ValidityPeriod = d
+		}
+	}
+
+	// Stripe billing webhook; STRIPE_PRICE_TIERS maps prices to tiers, e.g. "price_123=developer,price_456=enterprise"
+	config.Stripe.WebhookSecret = os.Getenv("STRIPE_WEBHOOK_SECRET")
+	if v := os.Getenv("STRIPE_PRICE_TIERS"); v != "" {
+		for _, pair := range strings.Split(v, ",") {
+			price, tier, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				panic("Invalid STRIPE_PRICE_TIERS entry: " + pair)
+			}
+			config.Stripe.PriceTiers[price] = tier
+		}
+	}
+
+	// Bridge chain list as a JSON array of chains, reloadable with SIGHUP
+	if path := os.Getenv("BRIDGE_CHAINS_FILE"); path != "" {
+		chains, err := api.LoadSupportedChains(path)
+		if err != nil {
+			panic("Failed to load BRIDGE_CHAINS_FILE: " + err.Error())
+		}
+		config.BridgeChains = chains
+	}
+
+	// Relayer public keys (hex secp256k1, comma-separated) allowed to sign bridge confirmations
+	if v := os.Getenv("BRIDGE_RELAYER_KEYS"); v != "" {
+		keys, err := api.ParseRelayerKeys(v)
+		if err != nil {
+			panic("Invalid BRIDGE_RELAYER_KEYS: " + err.Error())
+		}
+		config.BridgeRelayers = keys
+	}
+
+	// Public verifier URL that credential share link tokens are appended to
+	if v := os.Getenv("SHARE_LINK_BASE_URL"); v != "" {
+		config.ShareLinks.BaseURL = v
+	}
+
+	// Wallet sign-in: the domain and URI named in the challenge message, and how long it can be verified
+	if v := os.Getenv("AUTH_DOMAIN"); v != "" {
+		config.Auth.Domain = v
+	}
+	if v := os.Getenv("AUTH_URI"); v != "" {
+		config.Auth.URI = v
+	}
+	if v := os.Getenv("AUTH_CHALLENGE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			config.Auth.ChallengeTTL = d
+		}
+	}
+
+	// Explorer response cache: shared through Redis when set, otherwise an in-memory LRU
+	if v := os.Getenv("REDIS_URL"); v != "" {
+		config.Cache.RedisURL = v
+	}
+	if v := os.Getenv("CACHE_FINALIZED_CONFIRMATIONS"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n >= 0 {
+			config.Cache.FinalizedConfirmations = n
+		}
+	}
+	if v := os.Getenv("CACHE_FINALIZED_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			config.Cache.FinalizedTTL = d
+		}
+	}
+	if v := os.Getenv("CACHE_UNFINALIZED_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.Cache.UnfinalizedTTL = d
+		}
+	}
+
+	// Cross-chain sybil check RPC reads
+	if v := os.Getenv("CROSSCHAIN_PER_CHAIN_CONCURRENCY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.CrossChain.PerChainConcurrency = n
+		}
+	}
+	if v := os.Getenv("CROSSCHAIN_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.CrossChain.Timeout = d
+		}
+	}
+	if v := os.Getenv("CROSSCHAIN_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.CrossChain.CacheTTL = d
+		}
+	}
+
+	// Badge catalog JSON ({"badges": [...], "mappings": [...]}) layered over the standard badges
+	if path := os.Getenv("BADGE_CATALOG_FILE"); path != "" {
+		catalog, err := api.LoadBadgeCatalog(path)
+		if err != nil {
+			panic("Failed to load BADGE_CATALOG_FILE: " + err.Error())
+		}
+		config.Badges = catalog
+	}
+
+	// Trust score weights JSON; weights the file leaves out keep their defaults
+	if path := os.Getenv("TRUST_SCORE_CONFIG_FILE"); path != "" {
+		weights, err := api.LoadTrustScoreConfig(path)
+		if err != nil {
+			panic("Failed to load TRUST_SCORE_CONFIG_FILE: " + err.Error())
+		}
+		config.TrustScore = weights
+	}
+
 	return config
 }
+
+// reloadBridgeChainsOnHUP re-reads the bridge chain file on every SIGHUP.
+// A file that fails to load leaves the current configuration in place.
+func reloadBridgeChainsOnHUP(server *api.Server, path string, logger *zap.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		chains, err := api.LoadSupportedChains(path)
+		if err == nil {
+			err = server.ReloadBridgeChains(chains)
+		}
+		if err != nil {
+			logger.Error("Bridge chain reload failed", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		logger.Info("Bridge chains reloaded", zap.Int("chains", len(chains)))
+	}
+}
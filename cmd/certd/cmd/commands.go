@@ -282,6 +282,7 @@ func initRootCmd(rootCmd *cobra.Command, moduleBasics module.BasicManager) {
 	rootCmd.AddCommand(
 		CertStatusCmd(),
 		ValidatorCmd(),
+		HealthCmd(),
 	)
 }
 
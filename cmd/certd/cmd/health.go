@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+)
+
+const (
+	flagMaxBlockAge = "max-block-age"
+	flagTimeout     = "timeout"
+
+	defaultHealthNode = "tcp://localhost:26657"
+)
+
+// nodeStatus is the part of the CometBFT /status response the health check reads
+type nodeStatus struct {
+	Result struct {
+		SyncInfo struct {
+			LatestBlockHeight string    `json:"latest_block_height"`
+			LatestBlockTime   time.Time `json:"latest_block_time"`
+			CatchingUp        bool      `json:"catching_up"`
+		} `json:"sync_info"`
+	} `json:"result"`
+}
+
+// HealthCmd returns the command that checks a running node for container
+// liveness and readiness probes. It exits non-zero, printing the reason, when
+// the node is unreachable, still catching up, or its latest block is older
+// than --max-block-age.
+func HealthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Check that the local node is reachable, synced and producing blocks",
+		Long: `Query the node's CometBFT /status endpoint and exit non-zero if the node is
+unreachable, still catching up, or its latest block is older than --max-block-age.
+
+Example (Kubernetes exec probe):
+
+	certd health --node tcp://localhost:26657 --max-block-age 30s`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			node, _ := cmd.Flags().GetString(flags.FlagNode)
+			maxBlockAge, _ := cmd.Flags().GetDuration(flagMaxBlockAge)
+			timeout, _ := cmd.Flags().GetDuration(flagTimeout)
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			height, age, err := checkNodeHealth(ctx, node, maxBlockAge, time.Now())
+			if err != nil {
+				return fmt.Errorf("unhealthy: %w", err)
+			}
+			cmd.Printf("healthy: height %d, latest block %s old\n", height, age.Round(time.Second))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flags.FlagNode, defaultHealthNode, "<host>:<port> to CometBFT RPC interface for this chain")
+	cmd.Flags().Duration(flagMaxBlockAge, 30*time.Second, "Report unhealthy when the latest block is older than this (0 disables the check)")
+	cmd.Flags().Duration(flagTimeout, 5*time.Second, "Timeout for the status request")
+	return cmd
+}
+
+// checkNodeHealth queries node's /status and returns the latest block height
+// and its age at now, or the reason the node is unhealthy
+func checkNodeHealth(ctx context.Context, node string, maxBlockAge time.Duration, now time.Time) (int64, time.Duration, error) {
+	url := rpcHTTPURL(node) + "/status"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid node address %q: %w", node, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, 0, fmt.Errorf("node unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("status endpoint returned %s", resp.Status)
+	}
+
+	var status nodeStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return 0, 0, fmt.Errorf("malformed status response: %w", err)
+	}
+	info := status.Result.SyncInfo
+	height, err := strconv.ParseInt(info.LatestBlockHeight, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed latest block height %q", info.LatestBlockHeight)
+	}
+
+	age := now.Sub(info.LatestBlockTime)
+	if info.CatchingUp {
+		return height, age, fmt.Errorf("node is catching up (height %d)", height)
+	}
+	if maxBlockAge > 0 && age > maxBlockAge {
+		return height, age, fmt.Errorf("latest block %d is %s old, exceeding %s", height, age.Round(time.Second), maxBlockAge)
+	}
+	return height, age, nil
+}
+
+// rpcHTTPURL turns a --node address (tcp://host:port, host:port or an http URL) into an HTTP base URL
+func rpcHTTPURL(node string) string {
+	switch {
+	case strings.HasPrefix(node, "tcp://"):
+		node = "http://" + strings.TrimPrefix(node, "tcp://")
+	case !strings.Contains(node, "://"):
+		node = "http://" + node
+	}
+	return strings.TrimRight(node, "/")
+}
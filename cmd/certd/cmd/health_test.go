@@ -0,0 +1,77 @@
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/cmd/certd/cmd"
+)
+
+// statusServer serves a CometBFT /status response for a node at height
+// whose latest block is blockAge old
+func statusServer(t *testing.T, height int64, blockAge time.Duration, catchingUp bool) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/status" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"result":{"sync_info":{"latest_block_height":"%d","latest_block_time":"%s","catching_up":%t}}}`,
+			height, time.Now().Add(-blockAge).UTC().Format(time.RFC3339Nano), catchingUp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func runHealth(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	c := cmd.HealthCmd()
+	var out bytes.Buffer
+	c.SetOut(&out)
+	c.SetErr(&out)
+	c.SetArgs(args)
+	err := c.Execute()
+	return out.String(), err
+}
+
+func TestHealthCmd(t *testing.T) {
+	t.Run("healthy", func(t *testing.T) {
+		srv := statusServer(t, 1200, 2*time.Second, false)
+		// --node accepts the tcp:// form used by the other commands
+		out, err := runHealth(t, "--node", strings.Replace(srv.URL, "http://", "tcp://", 1))
+		require.NoError(t, err)
+		require.Contains(t, out, "healthy: height 1200")
+	})
+
+	t.Run("catching up", func(t *testing.T) {
+		srv := statusServer(t, 40, time.Second, true)
+		_, err := runHealth(t, "--node", srv.URL)
+		require.ErrorContains(t, err, "catching up")
+	})
+
+	t.Run("stale block", func(t *testing.T) {
+		srv := statusServer(t, 1200, 5*time.Minute, false)
+		_, err := runHealth(t, "--node", srv.URL)
+		require.ErrorContains(t, err, "latest block 1200 is 5m0s old, exceeding 30s")
+
+		// A looser threshold accepts it, and 0 disables the check
+		_, err = runHealth(t, "--node", srv.URL, "--max-block-age", "10m")
+		require.NoError(t, err)
+		_, err = runHealth(t, "--node", srv.URL, "--max-block-age", "0")
+		require.NoError(t, err)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		srv := statusServer(t, 1, 0, false)
+		srv.Close()
+		_, err := runHealth(t, "--node", srv.URL, "--timeout", "1s")
+		require.ErrorContains(t, err, "node unreachable")
+	})
+}
@@ -153,6 +153,8 @@ func initAppConfig() (string, interface{}) {
 
 	srvCfg := serverconfig.DefaultConfig()
 	srvCfg.MinGasPrices = "0.0001ucert"
+	srvCfg.StateSync.SnapshotInterval = app.DefaultSnapshotInterval
+	srvCfg.StateSync.SnapshotKeepRecent = app.DefaultSnapshotKeepRecent
 
 	customAppConfig := CustomAppConfig{
 		Config: *srvCfg,
@@ -116,10 +116,11 @@ if [ ! -f "$HOME_DIR/config/genesis.json" ]; then
     # Set min-retain-blocks to 0 to keep all blocks (prevents pruning version issues)
     sed -i 's/min-retain-blocks = 0/min-retain-blocks = 0/' $HOME_DIR/config/app.toml
 
-    # Disable state-sync snapshots to avoid IAVL v1.x bug with empty module stores
-    # The bug causes "version does not exist" errors when exporting empty stores like crisis module
-    # See: https://github.com/cosmos/iavl/issues/939
-    sed -i 's/snapshot-interval = 100/snapshot-interval = 0/' $HOME_DIR/config/app.toml
+    # Serve state-sync snapshots so new validators can bootstrap without replaying from genesis
+    # Every store is mounted by a module, so snapshot export no longer hits the IAVL v1.x
+    # empty-store bug (https://github.com/cosmos/iavl/issues/939). SNAPSHOT_INTERVAL=0 disables them.
+    sed -i "s/^snapshot-interval = .*/snapshot-interval = ${SNAPSHOT_INTERVAL:-1000}/" $HOME_DIR/config/app.toml
+    sed -i "s/^snapshot-keep-recent = .*/snapshot-keep-recent = ${SNAPSHOT_KEEP_RECENT:-2}/" $HOME_DIR/config/app.toml
 
     # Configure consensus parameters (Whitepaper Section 4.1)
     echo "Step 3: Configuring config.toml..."
@@ -230,37 +230,43 @@ func (h *Handler) RetrieveEncryptedData(w http.ResponseWriter, r *http.Request)
 
 	// TODO: Verify signature to authenticate requester
 
+	encryptedKey, ipfsCID, status, msg := h.authorizeRetrieval(uid, req.Requester)
+	if status != http.StatusOK {
+		respondError(w, status, msg)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"ipfsCID":      ipfsCID,
+		"encryptedKey": encryptedKey,
+	})
+}
+
+// authorizeRetrieval checks that requester is a recipient of the unrevoked
+// attestation uid and returns their wrapped key and the IPFS CID. On failure
+// status and msg describe the error response.
+func (h *Handler) authorizeRetrieval(uid, requester string) (encryptedKey, ipfsCID string, status int, msg string) {
 	// Check if requester is authorized
-	var encryptedKey string
 	err := h.db.QueryRow(`
 		SELECT encrypted_key FROM attestation_recipients 
 		WHERE attestation_uid = $1 AND recipient = $2
-	`, uid, req.Requester).Scan(&encryptedKey)
+	`, uid, requester).Scan(&encryptedKey)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			respondError(w, http.StatusForbidden, "Not authorized to access this attestation")
-			return
+			return "", "", http.StatusForbidden, "Not authorized to access this attestation"
 		}
-		respondError(w, http.StatusInternalServerError, "Database error")
-		return
+		return "", "", http.StatusInternalServerError, "Database error"
 	}
 
 	// Get attestation details
-	var ipfsCID string
 	err = h.db.QueryRow(`SELECT ipfs_cid FROM encrypted_attestations WHERE uid = $1 AND revoked = false`, uid).Scan(&ipfsCID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			respondError(w, http.StatusNotFound, "Attestation not found or revoked")
-			return
+			return "", "", http.StatusNotFound, "Attestation not found or revoked"
 		}
-		respondError(w, http.StatusInternalServerError, "Database error")
-		return
+		return "", "", http.StatusInternalServerError, "Database error"
 	}
-
-	respondJSON(w, http.StatusOK, map[string]string{
-		"ipfsCID":      ipfsCID,
-		"encryptedKey": encryptedKey,
-	})
+	return encryptedKey, ipfsCID, http.StatusOK, ""
 }
 
 // RevokeAttestation handles POST /api/v1/encrypted-attestations/{uid}/revoke
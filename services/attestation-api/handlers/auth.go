@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// Signed requests
+// Reads of encrypted content are authorized by the requester signing
+// contentRequestMessage with their wallet (ADR-036, Keplr signArbitrary) and
+// sending the result in these headers.
+const (
+	signatureHeader = "X-Cert-Signature" // base64 64-byte r||s
+	pubKeyHeader    = "X-Cert-Pubkey"    // base64 compressed secp256k1 key
+	timestampHeader = "X-Cert-Timestamp" // unix seconds the message was signed at
+)
+
+// signedRequestMaxAge bounds how far a signed request's timestamp may be from now
+const signedRequestMaxAge = 5 * time.Minute
+
+// contentRequestMessage is what the requester signs to read uid's content
+func contentRequestMessage(uid, requester string, timestamp int64) string {
+	return "cert-content-request:v1|" + uid + "|" + requester + "|" + strconv.FormatInt(timestamp, 10)
+}
+
+// verifyContentRequest checks that requester signed the request for uid's
+// content within signedRequestMaxAge of now
+func verifyContentRequest(r *http.Request, uid, requester string, now time.Time) error {
+	sig, err := base64.StdEncoding.DecodeString(r.Header.Get(signatureHeader))
+	if err != nil || len(sig) < 64 {
+		return errors.New("missing or invalid " + signatureHeader + " header")
+	}
+	pub, err := base64.StdEncoding.DecodeString(r.Header.Get(pubKeyHeader))
+	if err != nil || len(pub) != 33 {
+		return errors.New(pubKeyHeader + " must be a base64 compressed secp256k1 key")
+	}
+	timestamp, err := strconv.ParseInt(r.Header.Get(timestampHeader), 10, 64)
+	if err != nil {
+		return errors.New("missing or invalid " + timestampHeader + " header")
+	}
+	if signedAt := time.Unix(timestamp, 0); signedAt.Before(now.Add(-signedRequestMaxAge)) || signedAt.After(now.Add(signedRequestMaxAge)) {
+		return errors.New("signed request has expired")
+	}
+
+	signer, err := bech32.ConvertAndEncode("cert", cosmosAddressFromPubkey(pub))
+	if err != nil || signer != requester {
+		return errors.New("request was not signed by the requester")
+	}
+	message := contentRequestMessage(uid, requester, timestamp)
+	if !crypto.VerifySignature(pub, adr036SignDocHash(requester, []byte(message)), sig[:64]) {
+		return errors.New("signature verification failed")
+	}
+	return nil
+}
+
+// adr036SignDocHash returns the hash a wallet signs for ADR-036 arbitrary data
+func adr036SignDocHash(signer string, data []byte) []byte {
+	// Maps marshal with sorted keys, giving the canonical amino JSON
+	signDoc := map[string]interface{}{
+		"account_number": "0",
+		"chain_id":       "",
+		"fee": map[string]interface{}{
+			"amount": []interface{}{},
+			"gas":    "0",
+		},
+		"memo": "",
+		"msgs": []interface{}{map[string]interface{}{
+			"type": "sign/MsgSignData",
+			"value": map[string]interface{}{
+				"data":   base64.StdEncoding.EncodeToString(data),
+				"signer": signer,
+			},
+		}},
+		"sequence": "0",
+	}
+	bz, _ := json.Marshal(signDoc)
+	hash := sha256.Sum256(bz)
+	return hash[:]
+}
+
+// cosmosAddressFromPubkey computes the account address of a compressed secp256k1 key
+func cosmosAddressFromPubkey(pubkey []byte) []byte {
+	sha := sha256.Sum256(pubkey)
+	rip := ripemd160.New()
+	rip.Write(sha[:])
+	return rip.Sum(nil)
+}
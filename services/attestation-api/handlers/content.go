@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// MaxEncryptedFileSize matches the chain's MaxEncryptedFileSize parameter (100 MB)
+	MaxEncryptedFileSize int64 = 100 * 1024 * 1024
+
+	// contentChunkSize is the buffer ciphertext is streamed through
+	contentChunkSize = 256 * 1024
+
+	// ipfsStatTimeout bounds the size lookup before streaming starts
+	ipfsStatTimeout = 10 * time.Second
+)
+
+// errContentTooLarge is returned for IPFS content above MaxEncryptedFileSize
+var errContentTooLarge = errors.New("encrypted content exceeds the maximum file size")
+
+// byteRange is an inclusive byte range of the ciphertext
+type byteRange struct {
+	start, end int64
+}
+
+func (br byteRange) length() int64 {
+	return br.end - br.start + 1
+}
+
+// parseRange resolves a single "bytes=" Range header against a file of size
+// bytes. ok is false when the header should be ignored (absent, malformed or
+// multi-range, which is served as a full response); satisfiable is false
+// when the range lies outside the file.
+func parseRange(header string, size int64) (br byteRange, ok, satisfiable bool) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return byteRange{}, false, false
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return byteRange{}, false, false
+	}
+
+	if first == "" {
+		// Suffix range: the final n bytes
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return byteRange{}, false, false
+		}
+		if n == 0 || size == 0 {
+			return byteRange{}, true, false
+		}
+		return byteRange{start: max(size-n, 0), end: size - 1}, true, true
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return byteRange{}, false, false
+	}
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+			return byteRange{}, false, false
+		}
+		end = min(end, size-1)
+	}
+	if start >= size {
+		return byteRange{}, true, false
+	}
+	return byteRange{start: start, end: end}, true, true
+}
+
+// ipfsContentSize returns the size of the file behind cid from the IPFS node
+func (h *Handler) ipfsContentSize(ctx context.Context, cid string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, ipfsStatTimeout)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("%s/api/v0/files/stat?arg=%s", strings.TrimRight(h.ipfsURL, "/"), url.QueryEscape("/ipfs/"+cid))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("ipfs stat returned status %d", resp.StatusCode)
+	}
+
+	var stat struct {
+		Size int64  `json:"Size"`
+		Type string `json:"Type"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stat); err != nil {
+		return 0, fmt.Errorf("failed to parse ipfs stat: %w", err)
+	}
+	if stat.Type != "" && stat.Type != "file" {
+		return 0, fmt.Errorf("ipfs content is a %s, not a file", stat.Type)
+	}
+	if stat.Size > MaxEncryptedFileSize {
+		return 0, errContentTooLarge
+	}
+	return stat.Size, nil
+}
+
+// openIPFSContent opens a stream of br from the file behind cid
+func (h *Handler) openIPFSContent(ctx context.Context, cid string, br byteRange) (io.ReadCloser, error) {
+	endpoint := fmt.Sprintf("%s/api/v0/cat?arg=%s&offset=%d&length=%d",
+		strings.TrimRight(h.ipfsURL, "/"), url.QueryEscape(cid), br.start, br.length())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("ipfs cat returned status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// serveIPFSContent streams the ciphertext behind cid, honouring a single
+// byte Range so large files can be fetched in parts without buffering
+func (h *Handler) serveIPFSContent(w http.ResponseWriter, r *http.Request, cid string) {
+	size, err := h.ipfsContentSize(r.Context(), cid)
+	if err != nil {
+		if errors.Is(err, errContentTooLarge) {
+			respondError(w, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("Encrypted content exceeds the maximum file size of %d bytes", MaxEncryptedFileSize))
+			return
+		}
+		respondError(w, http.StatusBadGateway, "Failed to read content from IPFS")
+		return
+	}
+
+	// The CID is the content address, so it is a strong validator
+	etag := `"` + cid + `"`
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.Header().Set("ETag", etag)
+
+	status := http.StatusOK
+	br := byteRange{start: 0, end: size - 1}
+	if ifRange := r.Header.Get("If-Range"); ifRange == "" || ifRange == etag {
+		if requested, ok, satisfiable := parseRange(r.Header.Get("Range"), size); ok {
+			if !satisfiable {
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+				respondError(w, http.StatusRequestedRangeNotSatisfiable, "Requested range not satisfiable")
+				return
+			}
+			status = http.StatusPartialContent
+			br = requested
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", br.start, br.end, size))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.FormatInt(br.length(), 10))
+	if br.length() == 0 || r.Method == http.MethodHead {
+		w.WriteHeader(status)
+		return
+	}
+
+	body, err := h.openIPFSContent(r.Context(), cid, br)
+	if err != nil {
+		w.Header().Del("Content-Length")
+		w.Header().Del("Content-Range")
+		respondError(w, http.StatusBadGateway, "Failed to read content from IPFS")
+		return
+	}
+	defer body.Close()
+
+	w.WriteHeader(status)
+	// Never send more than the announced length, whatever the node returns
+	_, _ = io.CopyBuffer(w, io.LimitReader(body, br.length()), make([]byte, contentChunkSize))
+}
+
+// RetrieveEncryptedContent handles GET /api/v1/encrypted-attestations/{uid}/content?requester={address}
+// Streams the ciphertext from IPFS; supports Range requests for large files.
+// The requester must sign the request (see verifyContentRequest).
+func (h *Handler) RetrieveEncryptedContent(w http.ResponseWriter, r *http.Request) {
+	uid := mux.Vars(r)["uid"]
+	requester := r.URL.Query().Get("requester")
+	if requester == "" {
+		respondError(w, http.StatusBadRequest, "Requester required")
+		return
+	}
+
+	if err := verifyContentRequest(r, uid, requester, time.Now()); err != nil {
+		respondError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	_, ipfsCID, status, msg := h.authorizeRetrieval(uid, requester)
+	if status != http.StatusOK {
+		respondError(w, status, msg)
+		return
+	}
+
+	h.serveIPFSContent(w, r, ipfsCID)
+}
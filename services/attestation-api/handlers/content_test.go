@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+)
+
+// fakeIPFSNode serves the Kubo files/stat and cat endpoints for one file
+type fakeIPFSNode struct {
+	cid     string
+	content []byte
+	size    int64 // reported by stat; defaults to len(content)
+	cats    atomic.Int32
+}
+
+func (n *fakeIPFSNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/v0/files/stat":
+		if r.URL.Query().Get("arg") != "/ipfs/"+n.cid {
+			http.Error(w, "not found", http.StatusInternalServerError)
+			return
+		}
+		size := n.size
+		if size == 0 {
+			size = int64(len(n.content))
+		}
+		fmt.Fprintf(w, `{"Hash":"%s","Size":%d,"CumulativeSize":%d,"Type":"file"}`, n.cid, size, size+100)
+	case "/api/v0/cat":
+		n.cats.Add(1)
+		offset, _ := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+		length, _ := strconv.ParseInt(r.URL.Query().Get("length"), 10, 64)
+		end := min(offset+length, int64(len(n.content)))
+		w.Write(n.content[offset:end])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newContentHandler(t *testing.T, node *fakeIPFSNode) *Handler {
+	t.Helper()
+	srv := httptest.NewServer(node)
+	t.Cleanup(srv.Close)
+	return &Handler{ipfsURL: srv.URL}
+}
+
+func fetchContent(h *Handler, cid string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/encrypted-attestations/uid/content", nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.serveIPFSContent(rec, req, cid)
+	return rec
+}
+
+func TestServeIPFSContent(t *testing.T) {
+	const cid = "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"
+	content := make([]byte, 3*contentChunkSize+123)
+	for i := range content {
+		content[i] = byte(i * 7)
+	}
+	node := &fakeIPFSNode{cid: cid, content: content}
+	h := newContentHandler(t, node)
+
+	t.Run("full fetch", func(t *testing.T) {
+		rec := fetchContent(h, cid, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+		}
+		if !bytes.Equal(rec.Body.Bytes(), content) {
+			t.Errorf("body differs from content (%d vs %d bytes)", rec.Body.Len(), len(content))
+		}
+		if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(len(content)) {
+			t.Errorf("Content-Length = %q", got)
+		}
+		if rec.Header().Get("Accept-Ranges") != "bytes" || rec.Header().Get("ETag") != `"`+cid+`"` {
+			t.Errorf("headers = %v", rec.Header())
+		}
+	})
+
+	t.Run("range request", func(t *testing.T) {
+		rec := fetchContent(h, cid, http.Header{"Range": {"bytes=1000-1999"}})
+		if rec.Code != http.StatusPartialContent {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+		}
+		if !bytes.Equal(rec.Body.Bytes(), content[1000:2000]) {
+			t.Errorf("range body differs (%d bytes)", rec.Body.Len())
+		}
+		if want := fmt.Sprintf("bytes 1000-1999/%d", len(content)); rec.Header().Get("Content-Range") != want {
+			t.Errorf("Content-Range = %q, want %q", rec.Header().Get("Content-Range"), want)
+		}
+
+		rec = fetchContent(h, cid, http.Header{"Range": {"bytes=-100"}})
+		if rec.Code != http.StatusPartialContent || !bytes.Equal(rec.Body.Bytes(), content[len(content)-100:]) {
+			t.Errorf("suffix range: status = %d, %d bytes", rec.Code, rec.Body.Len())
+		}
+
+		// A stale If-Range falls back to the full content
+		rec = fetchContent(h, cid, http.Header{"Range": {"bytes=0-9"}, "If-Range": {`"other"`}})
+		if rec.Code != http.StatusOK || rec.Body.Len() != len(content) {
+			t.Errorf("stale If-Range: status = %d, %d bytes", rec.Code, rec.Body.Len())
+		}
+
+		before := node.cats.Load()
+		rec = fetchContent(h, cid, http.Header{"Range": {fmt.Sprintf("bytes=%d-", len(content))}})
+		if rec.Code != http.StatusRequestedRangeNotSatisfiable {
+			t.Errorf("out of range status = %d, want 416", rec.Code)
+		}
+		if want := fmt.Sprintf("bytes */%d", len(content)); rec.Header().Get("Content-Range") != want {
+			t.Errorf("416 Content-Range = %q, want %q", rec.Header().Get("Content-Range"), want)
+		}
+		if node.cats.Load() != before {
+			t.Error("unsatisfiable range was fetched from IPFS")
+		}
+	})
+
+	t.Run("enforces the size cap", func(t *testing.T) {
+		large := &fakeIPFSNode{cid: cid, content: []byte("ciphertext"), size: MaxEncryptedFileSize + 1}
+		rec := fetchContent(newContentHandler(t, large), cid, nil)
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("oversized status = %d, want 413", rec.Code)
+		}
+		if large.cats.Load() != 0 {
+			t.Error("oversized content was streamed")
+		}
+
+		atCap := &fakeIPFSNode{cid: cid, content: []byte("ciphertext"), size: MaxEncryptedFileSize}
+		rec = fetchContent(newContentHandler(t, atCap), cid, http.Header{"Range": {"bytes=0-9"}})
+		if rec.Code != http.StatusPartialContent || rec.Body.String() != "ciphertext" {
+			t.Errorf("at-cap status = %d, body = %q", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("unknown content", func(t *testing.T) {
+		rec := fetchContent(h, "bafkqaaa", nil)
+		if rec.Code != http.StatusBadGateway {
+			t.Errorf("status = %d, want 502", rec.Code)
+		}
+	})
+}
+
+func TestParseRange(t *testing.T) {
+	const size = 1000
+	tests := []struct {
+		header          string
+		want            byteRange
+		ok, satisfiable bool
+	}{
+		{header: "", ok: false},
+		{header: "bytes=0-99", want: byteRange{0, 99}, ok: true, satisfiable: true},
+		{header: "bytes=900-", want: byteRange{900, 999}, ok: true, satisfiable: true},
+		{header: "bytes=900-5000", want: byteRange{900, 999}, ok: true, satisfiable: true},
+		{header: "bytes=-10", want: byteRange{990, 999}, ok: true, satisfiable: true},
+		{header: "bytes=-5000", want: byteRange{0, 999}, ok: true, satisfiable: true},
+		{header: "bytes=1000-", ok: true, satisfiable: false},
+		{header: "bytes=-0", ok: true, satisfiable: false},
+		{header: "bytes=0-1,5-6", ok: false},
+		{header: "bytes=9-1", ok: false},
+		{header: "items=0-1", ok: false},
+		{header: "bytes=abc", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok, satisfiable := parseRange(tt.header, size)
+		if ok != tt.ok || satisfiable != tt.satisfiable || (satisfiable && got != tt.want) {
+			t.Errorf("parseRange(%q) = %v, %v, %v; want %v, %v, %v", tt.header, got, ok, satisfiable, tt.want, tt.ok, tt.satisfiable)
+		}
+	}
+}
+
+// signContentRequest returns the headers of a content request for uid signed by key
+func signContentRequest(t *testing.T, key *ecdsa.PrivateKey, uid string, at time.Time) (string, http.Header) {
+	t.Helper()
+	pub := crypto.CompressPubkey(&key.PublicKey)
+	requester, err := bech32.ConvertAndEncode("cert", cosmosAddressFromPubkey(pub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := contentRequestMessage(uid, requester, at.Unix())
+	sig, err := crypto.Sign(adr036SignDocHash(requester, []byte(message)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := http.Header{}
+	header.Set(signatureHeader, base64.StdEncoding.EncodeToString(sig[:64]))
+	header.Set(pubKeyHeader, base64.StdEncoding.EncodeToString(pub))
+	header.Set(timestampHeader, strconv.FormatInt(at.Unix(), 10))
+	return requester, header
+}
+
+func TestRetrieveEncryptedContentRequiresSignature(t *testing.T) {
+	const uid = "0x01"
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	requester, signed := signContentRequest(t, key, uid, now)
+
+	// Unsigned requests are rejected before any lookup, so no database is needed
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/encrypted-attestations/"+uid+"/content?requester="+requester, nil)
+	req = mux.SetURLVars(req, map[string]string{"uid": uid})
+	rec := httptest.NewRecorder()
+	(&Handler{}).RetrieveEncryptedContent(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("unsigned request: status = %d, want 401: %s", rec.Code, rec.Body.String())
+	}
+
+	verify := func(header http.Header, uid, requester string) error {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header = header
+		return verifyContentRequest(req, uid, requester, now)
+	}
+	if err := verify(signed, uid, requester); err != nil {
+		t.Errorf("signed request rejected: %v", err)
+	}
+	if err := verify(signed, "0x02", requester); err == nil {
+		t.Error("signature accepted for another attestation")
+	}
+	otherRequester, _ := signContentRequest(t, other, uid, now)
+	if err := verify(signed, uid, otherRequester); err == nil {
+		t.Error("signature accepted for another requester")
+	}
+	_, stale := signContentRequest(t, key, uid, now.Add(-signedRequestMaxAge-time.Second))
+	if err := verify(stale, uid, requester); err == nil {
+		t.Error("expired signature accepted")
+	}
+}
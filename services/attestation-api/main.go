@@ -58,6 +58,7 @@ func main() {
 	api.HandleFunc("/encrypted-attestations", h.CreateEncryptedAttestation).Methods("POST", "OPTIONS")
 	api.HandleFunc("/encrypted-attestations/{uid}", h.GetEncryptedAttestation).Methods("GET", "OPTIONS")
 	api.HandleFunc("/encrypted-attestations/{uid}/retrieve", h.RetrieveEncryptedData).Methods("POST", "OPTIONS")
+	api.HandleFunc("/encrypted-attestations/{uid}/content", h.RetrieveEncryptedContent).Methods("GET", "HEAD", "OPTIONS")
 	api.HandleFunc("/encrypted-attestations/{uid}/revoke", h.RevokeAttestation).Methods("POST", "OPTIONS")
 
 	// Schema endpoints
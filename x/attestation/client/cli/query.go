@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/spf13/cobra"
 
 	"github.com/cosmos/cosmos-sdk/client"
@@ -26,6 +29,14 @@ func GetQueryCmd() *cobra.Command {
 		CmdQueryAttestationsByRecipient(),
 		CmdQueryEncryptedAttestation(),
 		CmdQueryStats(),
+		CmdQueryAttestationChain(),
+		CmdQueryEndorsements(),
+		CmdQueryAttestationsByDocumentHash(),
+		CmdQueryAttestationsByTag(),
+		CmdQueryExpiringAttestations(),
+		CmdQueryRevocations(),
+		CmdQueryFeeBalance(),
+		CmdQueryParams(),
 	)
 
 	return attestationQueryCmd
@@ -198,3 +209,242 @@ func CmdQueryStats() *cobra.Command {
 	flags.AddQueryFlagsToCmd(cmd)
 	return cmd
 }
+
+// CmdQueryAttestationChain queries the reassignment chain of an attestation
+func CmdQueryAttestationChain() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chain [uid]",
+		Short: "Query the reassignment chain of an attestation, oldest first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.AttestationChain(cmd.Context(), &types.QueryAttestationChainRequest{
+				Uid: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryEndorsements queries the endorsements of an attestation
+func CmdQueryEndorsements() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "endorsements [uid]",
+		Short: "Query the endorsements of an attestation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Endorsements(cmd.Context(), &types.QueryEndorsementsRequest{
+				Uid: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryAttestationsByDocumentHash queries the attestations over a document
+func CmdQueryAttestationsByDocumentHash() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "by-document-hash [sha256-hex]",
+		Short: "Query attestations by document hash",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			includeRevoked, _ := cmd.Flags().GetBool("include-revoked")
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.AttestationsByDocumentHash(cmd.Context(), &types.QueryAttestationsByDocumentHashRequest{
+				DocumentHash:   args[0],
+				IncludeRevoked: includeRevoked,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	cmd.Flags().Bool("include-revoked", false, "Include revoked attestations")
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryAttestationsByTag queries the attestations carrying every given tag
+func CmdQueryAttestationsByTag() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "by-tag [tag]...",
+		Short: "Query attestations carrying all of the given tags",
+		Args:  cobra.RangeArgs(1, types.MaxAttestationTags),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			includeRevoked, _ := cmd.Flags().GetBool("include-revoked")
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.AttestationsByTag(cmd.Context(), &types.QueryAttestationsByTagRequest{
+				Tags:           args,
+				IncludeRevoked: includeRevoked,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	cmd.Flags().Bool("include-revoked", false, "Include revoked attestations")
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryExpiringAttestations queries the unrevoked attestations expiring within a time window
+func CmdQueryExpiringAttestations() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "expiring [from-unix] [to-unix]",
+		Short: "Query unrevoked attestations expiring between two unix timestamps, soonest first",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			from, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid from timestamp: %w", err)
+			}
+			to, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid to timestamp: %w", err)
+			}
+			limit, _ := cmd.Flags().GetUint32("limit")
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.ExpiringAttestations(cmd.Context(), &types.QueryExpiringAttestationsRequest{
+				From:  from,
+				To:    to,
+				Limit: limit,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	cmd.Flags().Uint32("limit", types.DefaultExpiringAttestationsLimit, "Maximum number of attestations to return")
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryRevocations queries the attestations an address has revoked
+func CmdQueryRevocations() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revocations [address]",
+		Short: "Query the attestations revoked by an address, oldest first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Revocations(cmd.Context(), &types.QueryRevocationsRequest{
+				Attester: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryFeeBalance queries the schema fees held by the module account
+func CmdQueryFeeBalance() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fee-balance",
+		Short: "Query the schema fees accumulated in the attestation module account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.FeeBalance(cmd.Context(), &types.QueryFeeBalanceRequest{})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryParams queries the module parameters
+func CmdQueryParams() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "params",
+		Short: "Query the attestation module parameters",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Params(cmd.Context(), &types.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
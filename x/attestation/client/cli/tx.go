@@ -2,14 +2,19 @@ package cli
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/chaincertify/certd/x/attestation/types"
 )
@@ -28,7 +33,16 @@ func GetTxCmd() *cobra.Command {
 		CmdRegisterSchema(),
 		CmdAttest(),
 		CmdRevoke(),
+		CmdBatchRevoke(),
+		CmdBatchAttest(),
+		CmdReassignAttestation(),
+		CmdReaffirm(),
+		CmdEndorseAttestation(),
 		CmdCreateEncryptedAttestation(),
+		CmdUpdateSchemaAllowlist(),
+		CmdWithdrawFees(),
+		CmdUpdateRetentionPolicy(),
+		CmdUpdateParams(),
 	)
 
 	return attestationTxCmd
@@ -58,6 +72,14 @@ Example schema format: "string name, uint256 age, address wallet"`,
 				resolver,
 				revocable,
 			)
+			if feeStr, _ := cmd.Flags().GetString("fee"); feeStr != "" {
+				if msg.Fee, err = sdk.ParseCoinsNormalized(feeStr); err != nil {
+					return fmt.Errorf("invalid fee: %w", err)
+				}
+			}
+			msg.CreatorShareBps, _ = cmd.Flags().GetUint32("creator-share-bps")
+			maxStaleness, _ := cmd.Flags().GetDuration("max-staleness")
+			msg.MaxStaleness = uint64(maxStaleness.Seconds())
 
 			if err := msg.ValidateBasic(); err != nil {
 				return err
@@ -69,6 +91,9 @@ Example schema format: "string name, uint256 age, address wallet"`,
 
 	cmd.Flags().Bool("revocable", true, "Whether attestations using this schema can be revoked")
 	cmd.Flags().String("resolver", "", "Optional resolver contract address")
+	cmd.Flags().String("fee", "", "Optional fee charged per attestation under this schema (e.g. 1000ucert)")
+	cmd.Flags().Uint32("creator-share-bps", 0, "Schema creator's cut of the fee in basis points (remainder goes to the fee collector)")
+	cmd.Flags().Duration("max-staleness", 0, "Optional window after which attestations go stale unless reaffirmed (e.g. 720h)")
 	flags.AddTxFlagsToCmd(cmd)
 
 	return cmd
@@ -99,6 +124,7 @@ func CmdAttest() *cobra.Command {
 			expirationTime, _ := cmd.Flags().GetInt64("expiration")
 			revocable, _ := cmd.Flags().GetBool("revocable")
 			refUID, _ := cmd.Flags().GetString("ref-uid")
+			tags, _ := cmd.Flags().GetStringSlice("tags")
 
 			msg := types.NewMsgAttest(
 				clientCtx.GetFromAddress().String(),
@@ -109,6 +135,7 @@ func CmdAttest() *cobra.Command {
 				refUID,
 				data,
 			)
+			msg.Tags = tags
 
 			if err := msg.ValidateBasic(); err != nil {
 				return err
@@ -122,6 +149,7 @@ func CmdAttest() *cobra.Command {
 	cmd.Flags().Int64("expiration", 0, "Expiration timestamp (0 = never)")
 	cmd.Flags().Bool("revocable", true, "Whether this attestation can be revoked")
 	cmd.Flags().String("ref-uid", "", "Reference to another attestation UID")
+	cmd.Flags().StringSlice("tags", nil, "Comma-separated categorization tags (e.g. diploma,2024-cohort)")
 	flags.AddTxFlagsToCmd(cmd)
 
 	return cmd
@@ -156,6 +184,196 @@ func CmdRevoke() *cobra.Command {
 	return cmd
 }
 
+// CmdBatchRevoke returns the command for revoking several attestations atomically
+func CmdBatchRevoke() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch-revoke [attestation-uid]...",
+		Short: "Revoke multiple attestations in a single all-or-nothing transaction",
+		Args:  cobra.RangeArgs(1, types.MaxBatchRevokeUIDs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgBatchRevoke(
+				clientCtx.GetFromAddress().String(),
+				args,
+			)
+
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdBatchAttest returns the command for creating several attestations in one transaction
+func CmdBatchAttest() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch-attest [schema-uid] [recipient:data-hex[:expiration]]...",
+		Short: "Create multiple attestations under one schema in a single all-or-nothing transaction",
+		Long: `Create multiple public attestations under one schema. Each entry is the
+recipient address (may be empty), the hex-encoded data and an optional
+expiration timestamp, separated by colons.`,
+		Args: cobra.RangeArgs(2, types.MaxBatchAttestEntries+1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			entries := make([]types.BatchAttestEntry, 0, len(args)-1)
+			for i, arg := range args[1:] {
+				entry, err := parseBatchAttestEntry(arg)
+				if err != nil {
+					return fmt.Errorf("entry %d: %w", i, err)
+				}
+				entries = append(entries, entry)
+			}
+
+			revocable, _ := cmd.Flags().GetBool("revocable")
+			tags, _ := cmd.Flags().GetStringSlice("tags")
+
+			msg := types.NewMsgBatchAttest(
+				clientCtx.GetFromAddress().String(),
+				args[0],
+				revocable,
+				entries,
+			)
+			msg.Tags = tags
+
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().Bool("revocable", true, "Whether the attestations can be revoked")
+	cmd.Flags().StringSlice("tags", nil, "Comma-separated categorization tags applied to every attestation")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// parseBatchAttestEntry parses a "recipient:data-hex[:expiration]" batch-attest argument
+func parseBatchAttestEntry(arg string) (types.BatchAttestEntry, error) {
+	parts := strings.Split(arg, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return types.BatchAttestEntry{}, fmt.Errorf("expected recipient:data-hex[:expiration], got %q", arg)
+	}
+	data, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return types.BatchAttestEntry{}, fmt.Errorf("invalid data hex: %w", err)
+	}
+	entry := types.BatchAttestEntry{Recipient: parts[0], Data: data}
+	if len(parts) == 3 && parts[2] != "" {
+		entry.ExpirationTime, err = strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return types.BatchAttestEntry{}, fmt.Errorf("invalid expiration: %w", err)
+		}
+	}
+	return entry, nil
+}
+
+// CmdReassignAttestation returns the command for reassigning an attestation to a new recipient
+func CmdReassignAttestation() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reassign [attestation-uid] [new-recipient]",
+		Short: "Reissue an attestation to a new recipient and revoke the original",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgReassignAttestation(
+				clientCtx.GetFromAddress().String(),
+				args[0],
+				args[1],
+			)
+
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdReaffirm returns the command for reaffirming an attestation so it is no longer stale
+func CmdReaffirm() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reaffirm [attestation-uid]",
+		Short: "Reaffirm an attestation under a schema that requires periodic re-affirmation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgReaffirm(
+				clientCtx.GetFromAddress().String(),
+				args[0],
+			)
+
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdEndorseAttestation returns the command for endorsing an existing attestation
+func CmdEndorseAttestation() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "endorse [attestation-uid]",
+		Short: "Countersign an attestation issued by someone else",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			note, _ := cmd.Flags().GetString("note")
+
+			msg := types.NewMsgEndorseAttestation(
+				clientCtx.GetFromAddress().String(),
+				args[0],
+				[]byte(note),
+			)
+
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String("note", "", "Optional endorsement note")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
 // CmdCreateEncryptedAttestation returns the command for creating an encrypted attestation
 func CmdCreateEncryptedAttestation() *cobra.Command {
 	cmd := &cobra.Command{
@@ -163,7 +381,8 @@ func CmdCreateEncryptedAttestation() *cobra.Command {
 		Short: "Create a new encrypted attestation",
 		Long: `Create a new encrypted attestation with IPFS-stored encrypted data.
 Recipients should be comma-separated addresses.
-Keys file should be JSON with recipient->encrypted_key mapping.`,
+Keys should be a JSON recipient->encrypted_key mapping, given either as the path
+of a file or inline (an argument starting with "{").`,
 		Args: cobra.ExactArgs(5),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			clientCtx, err := client.GetClientTxContext(cmd)
@@ -175,11 +394,21 @@ Keys file should be JSON with recipient->encrypted_key mapping.`,
 			ipfsCID := args[1]
 			encryptedHash := args[2]
 			recipients := strings.Split(args[3], ",")
-			keysFile := args[4]
 
-			// Read keys file - this is a placeholder for the actual implementation
-			// In production, this would be handled by the SDK client library
-			_ = keysFile
+			keysJSON := []byte(args[4])
+			if !strings.HasPrefix(strings.TrimSpace(args[4]), "{") {
+				if keysJSON, err = os.ReadFile(args[4]); err != nil {
+					return fmt.Errorf("failed to read keys file: %w", err)
+				}
+			}
+			var keys map[string]string
+			if err := json.Unmarshal(keysJSON, &keys); err != nil {
+				return fmt.Errorf("invalid keys JSON: %w", err)
+			}
+
+			revocable, _ := cmd.Flags().GetBool("revocable")
+			expirationTime, _ := cmd.Flags().GetInt64("expiration")
+			fileSize, _ := cmd.Flags().GetUint64("file-size")
 
 			msg := types.NewMsgCreateEncryptedAttestation(
 				clientCtx.GetFromAddress().String(),
@@ -187,10 +416,11 @@ Keys file should be JSON with recipient->encrypted_key mapping.`,
 				ipfsCID,
 				encryptedHash,
 				recipients,
-				nil, // Keys would be loaded from file
-				true,
-				0,
+				keys,
+				revocable,
+				expirationTime,
 			)
+			msg.FileSize = fileSize
 
 			if err := msg.ValidateBasic(); err != nil {
 				return err
@@ -202,7 +432,169 @@ Keys file should be JSON with recipient->encrypted_key mapping.`,
 
 	cmd.Flags().Bool("revocable", true, "Whether this attestation can be revoked")
 	cmd.Flags().Int64("expiration", 0, "Expiration timestamp (0 = never)")
+	cmd.Flags().Uint64("file-size", 0, "Size in bytes of the encrypted file pinned to IPFS")
 	flags.AddTxFlagsToCmd(cmd)
 
 	return cmd
 }
+
+// CmdUpdateSchemaAllowlist returns the command for editing the schema allowlist.
+// The signer must be the module authority, so this is normally wrapped in a
+// governance proposal via --generate-only.
+func CmdUpdateSchemaAllowlist() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-schema-allowlist",
+		Short: "Toggle schema restriction and add or remove allowlisted schema creators (authority only)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			restricted, _ := cmd.Flags().GetBool("restricted")
+			add, _ := cmd.Flags().GetStringSlice("add")
+			remove, _ := cmd.Flags().GetStringSlice("remove")
+
+			msg := types.NewMsgUpdateSchemaAllowlist(
+				clientCtx.GetFromAddress().String(),
+				restricted,
+				add,
+				remove,
+			)
+
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().Bool("restricted", false, "Only allow attestations against schemas from allowlisted creators")
+	cmd.Flags().StringSlice("add", nil, "Schema creator addresses to allowlist")
+	cmd.Flags().StringSlice("remove", nil, "Schema creator addresses to remove from the allowlist")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdWithdrawFees returns the command for moving schema fees out of the
+// module account. The signer must be the module authority, so this is
+// normally wrapped in a governance proposal via --generate-only.
+func CmdWithdrawFees() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "withdraw-fees [amount]",
+		Short: "Burn or pay out schema fees held by the module account (authority only); omit amount to withdraw everything",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			var amount sdk.Coins
+			if len(args) == 1 {
+				if amount, err = sdk.ParseCoinsNormalized(args[0]); err != nil {
+					return fmt.Errorf("invalid amount: %w", err)
+				}
+			}
+			recipient, _ := cmd.Flags().GetString("recipient")
+			burn, _ := cmd.Flags().GetBool("burn")
+
+			msg := types.NewMsgWithdrawFees(
+				clientCtx.GetFromAddress().String(),
+				amount,
+				recipient,
+				burn,
+			)
+
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String("recipient", "", "Address to pay the fees to, e.g. the community treasury")
+	cmd.Flags().Bool("burn", false, "Burn the fees instead of paying them out")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdUpdateRetentionPolicy returns the command for setting how long revoked
+// and expired attestations are kept before pruning. The signer must be the
+// module authority, so this is normally wrapped in a governance proposal via
+// --generate-only.
+func CmdUpdateRetentionPolicy() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-retention-policy [retention-period]",
+		Short: "Set how long revoked and expired attestations are kept before pruning to tombstones (authority only); 0 keeps them forever",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			period, err := time.ParseDuration(args[0])
+			if err != nil || period < 0 {
+				return fmt.Errorf("invalid retention period %q", args[0])
+			}
+			maxPrunes, _ := cmd.Flags().GetUint32("max-prunes-per-block")
+
+			msg := types.NewMsgUpdateRetentionPolicy(
+				clientCtx.GetFromAddress().String(),
+				uint64(period.Seconds()),
+				maxPrunes,
+			)
+
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().Uint32("max-prunes-per-block", 0, fmt.Sprintf("Cap on attestations pruned in one block (default %d)", types.DefaultMaxPrunesPerBlock))
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdUpdateParams returns the command for replacing the module parameters
+// with those in a JSON file. The signer must be the module authority, so this
+// is normally wrapped in a governance proposal via --generate-only.
+func CmdUpdateParams() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-params [params-json-file]",
+		Short: "Replace the attestation module parameters (authority only)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			bz, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read params file: %w", err)
+			}
+			var params types.Params
+			if err := json.Unmarshal(bz, &params); err != nil {
+				return fmt.Errorf("invalid params JSON: %w", err)
+			}
+
+			msg := types.NewMsgUpdateParams(clientCtx.GetFromAddress().String(), params)
+
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
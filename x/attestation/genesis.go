@@ -1,6 +1,9 @@
 package attestation
 
 import (
+	"fmt"
+
+	errorsmod "cosmossdk.io/errors"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/chaincertify/certd/x/attestation/keeper"
@@ -20,6 +23,12 @@ type GenesisState struct {
 
 	// EncryptedAttestations contains any genesis encrypted attestations
 	EncryptedAttestations []types.EncryptedAttestation `json:"encrypted_attestations" protobuf:"bytes,4,rep,name=encrypted_attestations,proto3"`
+
+	// Tombstones contains what remains of attestations pruned under the retention policy
+	Tombstones []types.AttestationTombstone `json:"tombstones,omitempty" protobuf:"bytes,5,rep,name=tombstones,proto3"`
+
+	// Reassignments links reassigned attestations to their replacements
+	Reassignments []types.AttestationReassignment `json:"reassignments,omitempty" protobuf:"bytes,6,rep,name=reassignments,proto3"`
 }
 
 // Proto interface implementations for GenesisState
@@ -42,53 +51,67 @@ func DefaultGenesisState() *GenesisState {
 
 // GetDefaultSchemas returns the pre-deployed EAS schemas per Whitepaper Section 3.4
 func GetDefaultSchemas() []types.Schema {
-	return []types.Schema{
+	schemas := []types.Schema{
 		{
 			// EncryptedFileAttestation schema per Whitepaper Section 3.4
-			UID:       "0x1", // Will be generated properly
 			Revocable: true,
 			Schema:    "string ipfsCID, bytes32 encryptedDataHash, address recipient, bytes encryptedSymmetricKey, uint256 timestamp",
 		},
 		{
 			// EncryptedMultiRecipientAttestation schema per Whitepaper Section 3.4
-			UID:       "0x2",
 			Revocable: true,
 			Schema:    "string ipfsCID, bytes32 encryptedDataHash, address[] recipients, bytes[] encryptedSymmetricKeys, bool revocable",
 		},
 		{
 			// EncryptedBusinessDocumentAttestation schema per Whitepaper Section 3.4
-			UID:       "0x3",
 			Revocable: true,
 			Schema:    "string ipfsCID, bytes32 encryptedDataHash, address[] recipients, bytes[] encryptedSymmetricKeys, string businessID, string documentCategory, uint256 validUntil",
 		},
 		{
 			// Public attestation schema
-			UID:       "0x4",
 			Revocable: true,
 			Schema:    "bytes32 dataHash, string metadata, uint256 timestamp",
 		},
 	}
+	// Genesis imports schemas as is, so they carry the UID registration would give them
+	for i := range schemas {
+		schemas[i].UID = types.GenerateSchemaUID(schemas[i].Schema, schemas[i].Resolver, schemas[i].Revocable)
+	}
+	return schemas
 }
 
 // Validate validates the genesis state
 func (gs GenesisState) Validate() error {
 	// Validate params
-	if gs.Params.MaxRecipientsPerAttestation == 0 {
-		return types.ErrInvalidParams
-	}
-	if gs.Params.MaxEncryptedFileSize == 0 {
-		return types.ErrInvalidParams
+	if err := gs.Params.Validate(); err != nil {
+		return err
 	}
 
 	// Validate schemas
 	schemaUIDs := make(map[string]bool)
 	for _, schema := range gs.Schemas {
+		// Schemas are imported as is, not re-registered, so each must carry its UID
+		if schema.UID == "" {
+			return errorsmod.Wrapf(types.ErrInvalidSchemaFormat, "schema %q has no UID", schema.Schema)
+		}
 		if schemaUIDs[schema.UID] {
 			return types.ErrDuplicateSchema
 		}
 		schemaUIDs[schema.UID] = true
 	}
 
+	// Validate reassignment links
+	reassigned := make(map[string]bool)
+	for _, reassignment := range gs.Reassignments {
+		if reassignment.UID == "" || reassignment.ReassignedTo == "" {
+			return errorsmod.Wrap(types.ErrInvalidReassignment, "reassignment link is missing a UID")
+		}
+		if reassigned[reassignment.UID] {
+			return errorsmod.Wrapf(types.ErrInvalidReassignment, "attestation %s reassigned more than once", reassignment.UID)
+		}
+		reassigned[reassignment.UID] = true
+	}
+
 	return nil
 }
 
@@ -97,17 +120,29 @@ func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState GenesisState) {
 	// Set params
 	k.SetParams(ctx, genState.Params)
 
-	// Register default schemas
+	// Import schemas as exported, without re-registering them
 	for _, schema := range genState.Schemas {
 		if schema.Creator == nil {
 			// Genesis schemas created by module account
 			schema.Creator = sdk.AccAddress{}
 		}
-		k.RegisterSchema(ctx, schema.Creator, schema.Schema, schema.Resolver, schema.Revocable)
+		if err := k.ImportSchema(ctx, schema); err != nil {
+			panic(fmt.Sprintf("failed to import schema %s: %v", schema.UID, err))
+		}
 	}
 
-	// Import any genesis attestations
+	// Import any genesis attestations. Encrypted attestations are also exported
+	// as attestations, whose record holds their revocation; they are imported,
+	// and counted, once below.
+	encrypted := make(map[string]int, len(genState.EncryptedAttestations))
+	for i, encAttestation := range genState.EncryptedAttestations {
+		encrypted[encAttestation.UID] = i
+	}
 	for _, attestation := range genState.Attestations {
+		if i, ok := encrypted[attestation.UID]; ok {
+			genState.EncryptedAttestations[i].RevocationTime = attestation.RevocationTime
+			continue
+		}
 		k.ImportAttestation(ctx, attestation)
 	}
 
@@ -115,6 +150,16 @@ func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState GenesisState) {
 	for _, encAttestation := range genState.EncryptedAttestations {
 		k.ImportEncryptedAttestation(ctx, encAttestation)
 	}
+
+	// Import tombstones of pruned attestations
+	for _, tombstone := range genState.Tombstones {
+		k.ImportTombstone(ctx, tombstone)
+	}
+
+	// Import reassignment links
+	for _, reassignment := range genState.Reassignments {
+		k.ImportReassignment(ctx, reassignment)
+	}
 }
 
 // ExportGenesis returns a GenesisState for a given context and keeper
@@ -124,5 +169,7 @@ func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *GenesisState {
 		Schemas:               k.GetAllSchemas(ctx),
 		Attestations:          k.GetAllAttestations(ctx),
 		EncryptedAttestations: k.GetAllEncryptedAttestations(ctx),
+		Tombstones:            k.GetAllTombstones(ctx),
+		Reassignments:         k.GetAllReassignments(ctx),
 	}
 }
@@ -0,0 +1,59 @@
+package keeper
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// attestationFees returns the AttestationFee param charged count times
+func (k Keeper) attestationFees(ctx sdk.Context, count int) sdk.Coins {
+	fees := sdk.NewCoins()
+	for _, coin := range k.GetParams(ctx).AttestationFee {
+		fees = fees.Add(sdk.NewCoin(coin.Denom, coin.Amount.MulRaw(int64(count))))
+	}
+	return fees
+}
+
+// CheckAttestationFees returns an insufficient funds error when attester's
+// spendable balance cannot cover the AttestationFee for count attestations
+func (k Keeper) CheckAttestationFees(ctx sdk.Context, attester sdk.AccAddress, count int) error {
+	fees := k.attestationFees(ctx, count)
+	if fees.IsZero() {
+		return nil
+	}
+	if k.bankKeeper == nil {
+		return errorsmod.Wrap(sdkerrors.ErrInsufficientFunds, "fee collection is not configured")
+	}
+	if spendable := k.bankKeeper.SpendableCoins(ctx, attester); !spendable.IsAllGTE(fees) {
+		return errorsmod.Wrapf(sdkerrors.ErrInsufficientFunds, "spendable balance %s is smaller than attestation fee %s", spendable, fees)
+	}
+	return nil
+}
+
+// collectAttestationFee charges attester the AttestationFee param, sending it
+// to the fee collector. It is a no-op while the param is empty.
+func (k Keeper) collectAttestationFee(ctx sdk.Context, attester sdk.AccAddress) error {
+	if err := k.CheckAttestationFees(ctx, attester, 1); err != nil {
+		return err
+	}
+	fee := k.attestationFees(ctx, 1)
+	if fee.IsZero() {
+		return nil
+	}
+	if err := k.bankKeeper.SendCoinsFromAccountToModule(ctx, attester, authtypes.FeeCollectorName, fee); err != nil {
+		return errorsmod.Wrap(sdkerrors.ErrInsufficientFunds, err.Error())
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestationFeePaid,
+			sdk.NewAttribute(types.AttributeKeyAttester, attester.String()),
+			sdk.NewAttribute(types.AttributeKeyFee, fee.String()),
+		),
+	)
+	return nil
+}
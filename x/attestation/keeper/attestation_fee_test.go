@@ -0,0 +1,87 @@
+package keeper_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestAttestationFee(t *testing.T) {
+	attester := sdk.AccAddress("fee_attester________")
+	recipient := sdk.AccAddress("fee_recipient_______")
+	feeCollector := authtypes.NewModuleAddress(authtypes.FeeCollectorName)
+
+	// setup charges fee per attestation, funding the attester with balance
+	setup := func(t *testing.T, fee, balance int64) (keeper.Keeper, sdk.Context, *mockBankKeeper, string) {
+		k, ctx := setupTestKeeper(t)
+		bank := newMockBankKeeper()
+		k.SetBankKeeper(bank)
+		bank.fund(attester, sdk.NewCoins(sdk.NewInt64Coin("ucert", balance)))
+
+		params := k.GetParams(ctx)
+		params.AttestationFee = sdk.NewCoins(sdk.NewInt64Coin("ucert", fee))
+		k.SetParams(ctx, params)
+
+		schemaUID, err := k.RegisterSchema(ctx, attester, "bytes32 degreeHash", nil, true)
+		require.NoError(t, err)
+		return k, ctx, bank, schemaUID
+	}
+	createEncrypted := func(k keeper.Keeper, ctx sdk.Context, schemaUID string) (string, error) {
+		return k.CreateEncryptedAttestation(ctx, attester, schemaUID, "Qm"+strings.Repeat("a", 44), strings.Repeat("ab", 32),
+			[]sdk.AccAddress{recipient}, map[string]string{recipient.String(): "key"}, true, time.Time{})
+	}
+
+	t.Run("charges public and encrypted attestations", func(t *testing.T) {
+		k, ctx, bank, schemaUID := setup(t, 1_000, 10_000)
+
+		uid, err := k.CreateAttestation(ctx, attester, schemaUID, recipient, time.Time{}, true, "", []byte("degree"))
+		require.NoError(t, err)
+		_, err = createEncrypted(k, ctx, schemaUID)
+		require.NoError(t, err)
+		_, err = k.BatchCreateAttestations(ctx, attester, schemaUID, true, nil, []types.BatchAttestEntry{
+			{Data: make([]byte, 32)}, {Data: append(make([]byte, 31), 1)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, sdkmath.NewInt(6_000), bank.balance(attester).AmountOf("ucert"))
+		require.Equal(t, sdkmath.NewInt(4_000), bank.balance(feeCollector).AmountOf("ucert"))
+
+		// Reassignment reissues an attestation that was already paid for
+		_, err = k.ReassignAttestation(ctx, attester, uid, sdk.AccAddress("fee_new_recipient___"))
+		require.NoError(t, err)
+		require.Equal(t, sdkmath.NewInt(6_000), bank.balance(attester).AmountOf("ucert"))
+	})
+
+	t.Run("rejects an attester who cannot pay", func(t *testing.T) {
+		k, ctx, bank, schemaUID := setup(t, 1_000, 999)
+
+		_, err := k.CreateAttestation(ctx, attester, schemaUID, recipient, time.Time{}, true, "", []byte("degree"))
+		require.ErrorIs(t, err, sdkerrors.ErrInsufficientFunds)
+		_, err = createEncrypted(k, ctx, schemaUID)
+		require.ErrorIs(t, err, sdkerrors.ErrInsufficientFunds)
+
+		require.Equal(t, uint64(0), k.GetAttestationCount(ctx))
+		require.Equal(t, sdkmath.NewInt(999), bank.balance(attester).AmountOf("ucert"))
+		require.True(t, bank.balance(feeCollector).IsZero())
+	})
+
+	t.Run("charges nothing when the fee is zero", func(t *testing.T) {
+		k, ctx, bank, schemaUID := setup(t, 0, 10_000)
+		require.True(t, k.GetParams(ctx).AttestationFee.IsZero())
+
+		_, err := k.CreateAttestation(ctx, attester, schemaUID, recipient, time.Time{}, true, "", []byte("degree"))
+		require.NoError(t, err)
+		_, err = createEncrypted(k, ctx, schemaUID)
+		require.NoError(t, err)
+		require.Equal(t, sdkmath.NewInt(10_000), bank.balance(attester).AmountOf("ucert"))
+		require.True(t, bank.balance(feeCollector).IsZero())
+	})
+}
@@ -0,0 +1,75 @@
+package keeper_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestBatchCreateAttestations(t *testing.T) {
+	config := sdk.GetConfig()
+	config.SetBech32PrefixForAccount("cert", "certpub")
+
+	university := sdk.AccAddress("university__________")
+	alice := sdk.AccAddress("alice_______________")
+	bob := sdk.AccAddress("bob_________________")
+
+	diploma := func(b byte) []byte {
+		data := make([]byte, 32)
+		data[31] = b
+		return data
+	}
+
+	t.Run("all succeed", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, university, "bytes32 diplomaHash", nil, true)
+		require.NoError(t, err)
+
+		entries := []types.BatchAttestEntry{
+			{Recipient: alice.String(), Data: diploma(1)},
+			{Recipient: bob.String(), Data: diploma(2), ExpirationTime: 1_900_000_000},
+		}
+		uids, err := k.BatchCreateAttestations(ctx, university, schemaUID, true, []string{"class-of-2026"}, entries)
+		require.NoError(t, err)
+		require.Len(t, uids, 2)
+		require.NotEqual(t, uids[0], uids[1])
+
+		first, err := k.GetAttestation(ctx, uids[0])
+		require.NoError(t, err)
+		require.True(t, first.Recipient.Equals(alice))
+		require.Equal(t, []string{"class-of-2026"}, first.Tags)
+
+		second, err := k.GetAttestation(ctx, uids[1])
+		require.NoError(t, err)
+		require.True(t, second.Recipient.Equals(bob))
+		require.Equal(t, int64(1_900_000_000), second.ExpirationTime.Unix())
+	})
+
+	t.Run("one invalid entry rejects the whole batch", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, university, "bytes32 diplomaHash", nil, true)
+		require.NoError(t, err)
+
+		entries := []types.BatchAttestEntry{
+			{Recipient: alice.String(), Data: diploma(1)},
+			{Recipient: bob.String(), Data: []byte("not a bytes32")},
+		}
+		_, err = k.BatchCreateAttestations(ctx, university, schemaUID, true, nil, entries)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.ErrorContains(t, err, "entry 1")
+
+		created, err := k.GetAttestationsByAttester(ctx, university)
+		require.NoError(t, err)
+		require.Empty(t, created)
+		require.Zero(t, k.GetAttestationCount(ctx))
+	})
+
+	t.Run("unknown schema", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		_, err := k.BatchCreateAttestations(ctx, university, "0xmissing", true, nil, []types.BatchAttestEntry{{Data: diploma(1)}})
+		require.ErrorIs(t, err, types.ErrSchemaNotFound)
+	})
+}
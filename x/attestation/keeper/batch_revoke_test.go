@@ -0,0 +1,118 @@
+package keeper_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestBatchRevokeAttestations(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	other := sdk.AccAddress("other_______________")
+
+	t.Run("all succeed", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		uids := createTestAttestations(t, k, ctx, issuer, 5, true)
+
+		require.NoError(t, k.BatchRevokeAttestations(ctx, issuer, uids))
+		for _, uid := range uids {
+			revoked, err := k.IsAttestationRevoked(ctx, uid)
+			require.NoError(t, err)
+			require.True(t, revoked, uid)
+		}
+		require.Equal(t, uint64(5), k.GetBlockBatchRevocationCount(ctx))
+	})
+
+	t.Run("mixed list with unauthorized uid rejects whole batch", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		mine := createTestAttestations(t, k, ctx, issuer, 3, true)
+		theirs := createTestAttestations(t, k, ctx, other, 1, true)
+
+		batch := []string{mine[0], mine[1], theirs[0], mine[2]}
+		err := k.BatchRevokeAttestations(ctx, issuer, batch)
+		require.ErrorIs(t, err, types.ErrUnauthorized)
+
+		for _, uid := range batch {
+			revoked, err := k.IsAttestationRevoked(ctx, uid)
+			require.NoError(t, err)
+			require.False(t, revoked, "attestation %s must not be revoked", uid)
+		}
+		require.Zero(t, k.GetBlockBatchRevocationCount(ctx))
+	})
+
+	t.Run("non-revocable uid rejects whole batch", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		uids := createTestAttestations(t, k, ctx, issuer, 2, true)
+		fixed := createTestAttestations(t, k, ctx, issuer, 1, false)
+
+		err := k.BatchRevokeAttestations(ctx, issuer, append(uids, fixed...))
+		require.ErrorIs(t, err, types.ErrAttestationNotRevocable)
+		revoked, _ := k.IsAttestationRevoked(ctx, uids[0])
+		require.False(t, revoked)
+	})
+
+	t.Run("unknown and already revoked uids", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		uids := createTestAttestations(t, k, ctx, issuer, 2, true)
+
+		require.ErrorIs(t, k.BatchRevokeAttestations(ctx, issuer, []string{uids[0], "0xmissing"}), types.ErrAttestationNotFound)
+
+		require.NoError(t, k.BatchRevokeAttestations(ctx, issuer, uids[:1]))
+		require.ErrorIs(t, k.BatchRevokeAttestations(ctx, issuer, uids), types.ErrAttestationAlreadyRevoked)
+	})
+
+	t.Run("per-block cap", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		params := types.DefaultParams()
+		params.MaxBatchRevocationsPerBlock = 4
+		k.SetParams(ctx, params)
+		uids := createTestAttestations(t, k, ctx, issuer, 7, true)
+
+		require.NoError(t, k.BatchRevokeAttestations(ctx, issuer, uids[:3]))
+
+		// Only one slot is left in this block; a batch of two is rejected as a whole
+		err := k.BatchRevokeAttestations(ctx, issuer, uids[3:5])
+		require.ErrorIs(t, err, types.ErrRevocationLimitExceeded)
+		revoked, _ := k.IsAttestationRevoked(ctx, uids[3])
+		require.False(t, revoked)
+
+		require.NoError(t, k.BatchRevokeAttestations(ctx, issuer, uids[3:4]))
+		require.ErrorIs(t, k.BatchRevokeAttestations(ctx, issuer, uids[4:5]), types.ErrRevocationLimitExceeded)
+
+		// The cap resets in the next block
+		next := ctx.WithBlockHeight(ctx.BlockHeight() + 1)
+		require.Zero(t, k.GetBlockBatchRevocationCount(next))
+		require.NoError(t, k.BatchRevokeAttestations(next, issuer, uids[4:7]))
+	})
+}
+
+func TestMsgServerBatchRevokeEmitsEvent(t *testing.T) {
+	k, ctx := setupTestKeeper(t)
+	issuer := sdk.AccAddress("issuer______________")
+	uids := createTestAttestations(t, k, ctx, issuer, 2, true)
+
+	msgServer := keeper.NewMsgServerImpl(k)
+	res, err := msgServer.BatchRevoke(ctx, types.NewMsgBatchRevoke(issuer.String(), uids))
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), res.Revoked)
+
+	var found bool
+	for _, ev := range ctx.EventManager().Events() {
+		if ev.Type != types.EventTypeAttestationsBatchRevoked {
+			continue
+		}
+		found = true
+		attrs := map[string]string{}
+		for _, a := range ev.Attributes {
+			attrs[a.Key] = a.Value
+		}
+		require.Equal(t, "2", attrs[types.AttributeKeyRevokedCount])
+		require.Equal(t, uids[0]+","+uids[1], attrs[types.AttributeKeyAttestationUIDs])
+		require.Equal(t, issuer.String(), attrs[types.AttributeKeyRevoker])
+	}
+	require.True(t, found, "batch revoke event not emitted")
+}
@@ -0,0 +1,76 @@
+package keeper_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestAttestationDataCompression(t *testing.T) {
+	attester := sdk.AccAddress("registrar___________")
+
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).
+		WithBlockTime(time.Unix(1_700_000_000, 0).UTC())
+	k := keeper.NewKeeper(nil, storeKey, nil, "")
+	qs := keeper.NewQueryServerImpl(k)
+
+	schemaUID, err := k.RegisterSchema(ctx, attester, "string transcript", nil, true)
+	require.NoError(t, err)
+
+	storedData := func(uid string) []byte {
+		var raw types.Attestation
+		require.NoError(t, json.Unmarshal(ctx.KVStore(storeKey).Get(types.GetAttestationKey(uid)), &raw))
+		return raw.Data
+	}
+
+	transcript := []byte(strings.Repeat(`{"course":"Distributed Systems","credits":4,"grade":"A"},`, 100))
+	uid, err := k.CreateAttestation(ctx, attester, schemaUID, nil, time.Time{}, true, "", transcript)
+	require.NoError(t, err)
+
+	stored := storedData(uid)
+	require.True(t, types.IsCompressedAttestationData(stored))
+	require.Less(t, len(stored), len(transcript))
+
+	got, err := qs.Attestation(ctx, &types.QueryAttestationRequest{Uid: uid})
+	require.NoError(t, err)
+	require.Equal(t, transcript, got.Attestation.Data)
+
+	byAttester, err := qs.AttestationsByAttester(ctx, &types.QueryAttestationsByAttesterRequest{Attester: attester.String()})
+	require.NoError(t, err)
+	require.Len(t, byAttester.Attestations, 1)
+	require.Equal(t, transcript, byAttester.Attestations[0].Data)
+
+	// Rewriting on revoke keeps the data compressed and intact
+	require.NoError(t, k.RevokeAttestation(ctx, attester, uid))
+	require.True(t, types.IsCompressedAttestationData(storedData(uid)))
+	revoked, err := k.GetAttestation(ctx, uid)
+	require.NoError(t, err)
+	require.Equal(t, transcript, revoked.Data)
+
+	exported := k.GetAllAttestations(ctx)
+	require.Equal(t, transcript, exported[0].Data)
+
+	// Small data is stored as-is
+	hash := make([]byte, 32)
+	hash[0] = 0xab
+	small, err := k.CreateAttestation(ctx, attester, schemaUID, nil, time.Time{}, true, "", hash)
+	require.NoError(t, err)
+	require.Equal(t, hash, storedData(small))
+
+	// The size limit applies to uncompressed data, however well it compresses
+	params := k.GetParams(ctx)
+	params.MaxAttestationDataSize = 1024
+	k.SetParams(ctx, params)
+	_, err = k.CreateAttestation(ctx, attester, schemaUID, nil, time.Time{}, true, "", transcript)
+	require.ErrorIs(t, err, types.ErrAttestationDataTooLarge)
+}
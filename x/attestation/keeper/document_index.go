@@ -0,0 +1,93 @@
+package keeper
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// Document hash index values; revoked entries are kept so verification can
+// report that a document's attestation was revoked
+var (
+	documentIndexActive  = []byte{1}
+	documentIndexRevoked = []byte{0}
+)
+
+// attestationDocumentHash returns the document hash indexed for an attestation:
+// the encrypted data hash for encrypted attestations, otherwise the hash its data carries
+func attestationDocumentHash(attestation types.Attestation, encryptedDataHash string) (string, bool) {
+	if encryptedDataHash != "" {
+		return types.NormalizeDocumentHash(encryptedDataHash)
+	}
+	if attestation.AttestationType == types.AttestationTypeEndorsement {
+		return "", false
+	}
+	return types.DocumentHashFromData(attestation.Data)
+}
+
+// indexDocumentHash records attestation under its document hash, if it has one
+func (k Keeper) indexDocumentHash(ctx sdk.Context, attestation types.Attestation, encryptedDataHash string) {
+	docHash, ok := attestationDocumentHash(attestation, encryptedDataHash)
+	if !ok {
+		return
+	}
+	value := documentIndexActive
+	if !attestation.RevocationTime.IsZero() {
+		value = documentIndexRevoked
+	}
+	ctx.KVStore(k.storeKey).Set(types.GetAttestationByDocumentHashKey(docHash, attestation.UID), value)
+}
+
+// markDocumentHashRevoked flags a revoked attestation's document index entry
+func (k Keeper) markDocumentHashRevoked(ctx sdk.Context, attestation types.Attestation) {
+	docHash, ok := attestationDocumentHash(attestation, "")
+	if !ok {
+		return
+	}
+	store := ctx.KVStore(k.storeKey)
+	key := types.GetAttestationByDocumentHashKey(docHash, attestation.UID)
+	if store.Has(key) {
+		store.Set(key, documentIndexRevoked)
+	}
+}
+
+// GetAttestationUIDsByDocumentHash returns the UIDs of attestations over docHash,
+// in UID order, optionally including revoked ones
+func (k Keeper) GetAttestationUIDsByDocumentHash(ctx sdk.Context, docHash string, includeRevoked bool) ([]string, error) {
+	normalized, ok := types.NormalizeDocumentHash(docHash)
+	if !ok {
+		return nil, errorsmod.Wrap(types.ErrInvalidDocumentHash, docHash)
+	}
+
+	prefix := types.GetAttestationsByDocumentHashIteratorPrefix(normalized)
+	iterator := storetypes.KVStorePrefixIterator(ctx.KVStore(k.storeKey), prefix)
+	defer iterator.Close()
+
+	var uids []string
+	for ; iterator.Valid(); iterator.Next() {
+		if !includeRevoked && iterator.Value()[0] == documentIndexRevoked[0] {
+			continue
+		}
+		uids = append(uids, string(iterator.Key()[len(prefix):]))
+	}
+	return uids, nil
+}
+
+// GetAttestationsByDocumentHash returns the attestations over docHash, optionally including revoked ones
+func (k Keeper) GetAttestationsByDocumentHash(ctx sdk.Context, docHash string, includeRevoked bool) ([]types.Attestation, error) {
+	uids, err := k.GetAttestationUIDsByDocumentHash(ctx, docHash, includeRevoked)
+	if err != nil {
+		return nil, err
+	}
+	attestations := make([]types.Attestation, 0, len(uids))
+	for _, uid := range uids {
+		attestation, err := k.getAttestationOrTombstone(ctx, uid)
+		if err != nil {
+			continue
+		}
+		attestations = append(attestations, *attestation)
+	}
+	return attestations, nil
+}
@@ -0,0 +1,134 @@
+package keeper_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestAttestationsByDocumentHash(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	notary := sdk.AccAddress("notary______________")
+	recipient := sdk.AccAddress("recipient___________")
+	contract := sha256.Sum256([]byte("contract.pdf"))
+	invoice := sha256.Sum256([]byte("invoice.pdf"))
+	contractHash := hex.EncodeToString(contract[:])
+	invoiceHash := hex.EncodeToString(invoice[:])
+
+	t.Run("resolves every attestation over a document", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 documentHash", nil, true)
+		require.NoError(t, err)
+
+		// The same document attested as a raw digest, as 0x-hex and inside JSON data
+		raw, err := k.CreateAttestation(ctx, issuer, schemaUID, recipient, time.Time{}, true, "", contract[:])
+		require.NoError(t, err)
+		prefixed, err := k.CreateAttestation(ctx, notary, schemaUID, nil, time.Time{}, true, "", []byte("0x"+strings.ToUpper(contractHash)))
+		require.NoError(t, err)
+		inJSON, err := k.CreateAttestation(ctx, notary, schemaUID, nil, time.Time{}, true, "", []byte(`{"title":"MSA","document_hash":"`+contractHash+`"}`))
+		require.NoError(t, err)
+		other, err := k.CreateAttestation(ctx, issuer, schemaUID, nil, time.Time{}, true, "", invoice[:])
+		require.NoError(t, err)
+		_, err = k.CreateAttestation(ctx, issuer, schemaUID, nil, time.Time{}, true, "", []byte("no document here"))
+		require.NoError(t, err)
+
+		uids, err := k.GetAttestationUIDsByDocumentHash(ctx, "0X"+strings.ToUpper(contractHash), false)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{raw, prefixed, inJSON}, uids)
+
+		attestations, err := k.GetAttestationsByDocumentHash(ctx, invoiceHash, false)
+		require.NoError(t, err)
+		require.Len(t, attestations, 1)
+		require.Equal(t, other, attestations[0].UID)
+
+		unknown := sha256.Sum256([]byte("unknown"))
+		uids, err = k.GetAttestationUIDsByDocumentHash(ctx, hex.EncodeToString(unknown[:]), true)
+		require.NoError(t, err)
+		require.Empty(t, uids)
+
+		_, err = k.GetAttestationUIDsByDocumentHash(ctx, "not-a-hash", false)
+		require.ErrorIs(t, err, types.ErrInvalidDocumentHash)
+	})
+
+	t.Run("indexes encrypted attestations by encrypted data hash", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "string ipfsCid", nil, true)
+		require.NoError(t, err)
+		cid := "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"
+		uid, err := k.CreateEncryptedAttestation(ctx, issuer, schemaUID, cid, contractHash,
+			[]sdk.AccAddress{recipient}, map[string]string{recipient.String(): "wrapped"}, true, time.Time{})
+		require.NoError(t, err)
+
+		attestations, err := k.GetAttestationsByDocumentHash(ctx, contractHash, false)
+		require.NoError(t, err)
+		require.Len(t, attestations, 1)
+		require.Equal(t, uid, attestations[0].UID)
+
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, uid))
+		uids, err := k.GetAttestationUIDsByDocumentHash(ctx, contractHash, false)
+		require.NoError(t, err)
+		require.Empty(t, uids)
+	})
+
+	t.Run("stays consistent through revocation", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 documentHash", nil, true)
+		require.NoError(t, err)
+		first, err := k.CreateAttestation(ctx, issuer, schemaUID, nil, time.Time{}, true, "", contract[:])
+		require.NoError(t, err)
+		second, err := k.CreateAttestation(ctx, issuer, schemaUID, nil, time.Time{}, true, "", contract[:])
+		require.NoError(t, err)
+		third, err := k.CreateAttestation(ctx, issuer, schemaUID, recipient, time.Time{}, true, "", contract[:])
+		require.NoError(t, err)
+
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, first))
+		require.NoError(t, k.BatchRevokeAttestations(ctx, issuer, []string{second}))
+
+		active, err := k.GetAttestationUIDsByDocumentHash(ctx, contractHash, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{third}, active)
+
+		all, err := k.GetAttestationsByDocumentHash(ctx, contractHash, true)
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+		for _, a := range all {
+			require.Equal(t, a.UID != third, !a.RevocationTime.IsZero(), "revocation state of %s", a.UID)
+		}
+
+		// Reassignment revokes the original and indexes its replacement
+		replacement, err := k.ReassignAttestation(ctx, issuer, third, notary)
+		require.NoError(t, err)
+		active, err = k.GetAttestationUIDsByDocumentHash(ctx, contractHash, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{replacement}, active)
+	})
+
+	t.Run("is rebuilt from genesis", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 documentHash", nil, true)
+		require.NoError(t, err)
+		kept, err := k.CreateAttestation(ctx, issuer, schemaUID, nil, time.Time{}, true, "", contract[:])
+		require.NoError(t, err)
+		revoked, err := k.CreateAttestation(ctx, issuer, schemaUID, nil, time.Time{}, true, "", contract[:])
+		require.NoError(t, err)
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, revoked))
+		exported := attestation.ExportGenesis(ctx, k)
+
+		imported, importCtx := setupTestKeeper(t)
+		attestation.InitGenesis(importCtx, imported, *exported)
+		active, err := imported.GetAttestationUIDsByDocumentHash(importCtx, contractHash, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{kept}, active)
+		all, err := imported.GetAttestationUIDsByDocumentHash(importCtx, contractHash, true)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{kept, revoked}, all)
+	})
+}
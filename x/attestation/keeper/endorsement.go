@@ -0,0 +1,111 @@
+package keeper
+
+import (
+	"fmt"
+
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// EndorseAttestation records endorser's countersignature of targetUID, e.g. an
+// accreditation body endorsing a university's diploma. The endorsement is a
+// revocable attestation of type endorsement whose RefUID is the target, so the
+// endorser can withdraw it with MsgRevoke. Neither the target's attester nor its
+// recipient may endorse it, and each endorser may endorse a target once.
+func (k Keeper) EndorseAttestation(ctx sdk.Context, endorser sdk.AccAddress, targetUID string, note []byte) (string, error) {
+	store := ctx.KVStore(k.storeKey)
+
+	target, err := k.GetAttestation(ctx, targetUID)
+	if err != nil {
+		return "", errorsmod.Wrap(types.ErrAttestationNotFound, targetUID)
+	}
+	if target.AttestationType == types.AttestationTypeEndorsement {
+		return "", errorsmod.Wrap(types.ErrInvalidEndorsement, "endorsements cannot be endorsed")
+	}
+	if target.Attester.Equals(endorser) || (len(target.Recipient) > 0 && target.Recipient.Equals(endorser)) {
+		return "", errorsmod.Wrap(types.ErrSelfEndorsement, targetUID)
+	}
+	if !target.RevocationTime.IsZero() {
+		return "", errorsmod.Wrap(types.ErrAttestationAlreadyRevoked, targetUID)
+	}
+	if !target.ExpirationTime.IsZero() && ctx.BlockTime().After(target.ExpirationTime) {
+		return "", errorsmod.Wrap(types.ErrAttestationExpired, targetUID)
+	}
+
+	indexKey := types.GetEndorsementKey(targetUID, endorser)
+	if existing := store.Get(indexKey); existing != nil {
+		if prev, err := k.GetAttestation(ctx, string(existing)); err == nil && prev.RevocationTime.IsZero() {
+			return "", errorsmod.Wrapf(types.ErrInvalidEndorsement, "already endorsed by %s", endorser)
+		}
+	}
+
+	nonce := k.GetAttestationCount(ctx)
+	uid := types.GenerateUID(endorser, target.SchemaUID, ctx.BlockTime(), append([]byte(targetUID), note...), nonce)
+
+	endorsement := types.Attestation{
+		UID:             uid,
+		SchemaUID:       target.SchemaUID,
+		Attester:        endorser,
+		Time:            ctx.BlockTime(),
+		Revocable:       true,
+		RefUID:          targetUID,
+		Data:            note,
+		AttestationType: types.AttestationTypeEndorsement,
+	}
+	bz, err := marshalAttestation(endorsement)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal endorsement: %w", err)
+	}
+
+	// Endorsements are indexed by endorser and target but not by schema, so
+	// schema listings only contain the credentials themselves
+	store.Set(types.GetAttestationKey(uid), bz)
+	store.Set(types.GetAttestationByAttesterKey(endorser, uid), []byte{1})
+	store.Set(indexKey, []byte(uid))
+	k.incrementAttestationCount(ctx)
+
+	k.Logger(ctx).Info("Attestation endorsed", "uid", targetUID, "endorsement", uid, "endorser", endorser.String())
+
+	return uid, nil
+}
+
+// GetEndorsements returns each endorser's latest endorsement of targetUID,
+// including revoked ones
+func (k Keeper) GetEndorsements(ctx sdk.Context, targetUID string) ([]types.Attestation, error) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := storetypes.KVStorePrefixIterator(store, types.GetEndorsementsIteratorPrefix(targetUID))
+	defer iterator.Close()
+
+	endorsements := []types.Attestation{}
+	for ; iterator.Valid(); iterator.Next() {
+		endorsement, err := k.GetAttestation(ctx, string(iterator.Value()))
+		if err != nil {
+			return nil, err
+		}
+		endorsements = append(endorsements, *endorsement)
+	}
+	return endorsements, nil
+}
+
+// indexImportedEndorsement restores the endorsement index entry of an
+// endorsement imported at genesis. The index holds each endorser's latest
+// endorsement of a target, so an older one imported later does not replace it.
+func (k Keeper) indexImportedEndorsement(ctx sdk.Context, endorsement types.Attestation) {
+	if endorsement.AttestationType != types.AttestationTypeEndorsement || endorsement.RefUID == "" {
+		return
+	}
+	store := ctx.KVStore(k.storeKey)
+	indexKey := types.GetEndorsementKey(endorsement.RefUID, endorsement.Attester)
+	if existing := store.Get(indexKey); existing != nil {
+		if prev, err := k.GetAttestation(ctx, string(existing)); err == nil {
+			// A re-endorsement in the same block follows the revoked one
+			if prev.Time.After(endorsement.Time) || (prev.Time.Equal(endorsement.Time) && prev.RevocationTime.IsZero()) {
+				return
+			}
+		}
+	}
+	store.Set(indexKey, []byte(endorsement.UID))
+}
@@ -0,0 +1,98 @@
+package keeper_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestEndorseAttestation(t *testing.T) {
+	university := sdk.AccAddress("university__________")
+	graduate := sdk.AccAddress("graduate____________")
+	accreditor := sdk.AccAddress("accreditor__________")
+	ministry := sdk.AccAddress("ministry____________")
+
+	setup := func(t *testing.T) (keeper.Keeper, sdk.Context, string) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, university, "bytes32 diplomaHash", nil, true)
+		require.NoError(t, err)
+		uid, err := k.CreateAttestation(ctx, university, schemaUID, graduate, ctx.BlockTime().AddDate(5, 0, 0), true, "", []byte("diploma"))
+		require.NoError(t, err)
+		return k, ctx, uid
+	}
+
+	t.Run("records endorsements against the target", func(t *testing.T) {
+		k, ctx, diploma := setup(t)
+
+		first, err := k.EndorseAttestation(ctx, accreditor, diploma, []byte("accredited program"))
+		require.NoError(t, err)
+		second, err := k.EndorseAttestation(ctx, ministry, diploma, nil)
+		require.NoError(t, err)
+
+		endorsements, err := k.GetEndorsements(ctx, diploma)
+		require.NoError(t, err)
+		require.Len(t, endorsements, 2)
+
+		byUID := map[string]types.Attestation{}
+		for _, e := range endorsements {
+			byUID[e.UID] = e
+			require.Equal(t, types.AttestationTypeEndorsement, e.AttestationType)
+			require.Equal(t, diploma, e.RefUID)
+			require.True(t, e.Revocable)
+		}
+		require.True(t, byUID[first].Attester.Equals(accreditor))
+		require.Equal(t, []byte("accredited program"), byUID[first].Data)
+		require.True(t, byUID[second].Attester.Equals(ministry))
+
+		// Endorsements show up under the endorser's attestations
+		byAttester, err := k.GetAttestationsByAttester(ctx, accreditor)
+		require.NoError(t, err)
+		require.Len(t, byAttester, 1)
+	})
+
+	t.Run("rejects self-endorsement", func(t *testing.T) {
+		k, ctx, diploma := setup(t)
+
+		_, err := k.EndorseAttestation(ctx, university, diploma, nil)
+		require.ErrorIs(t, err, types.ErrSelfEndorsement)
+		_, err = k.EndorseAttestation(ctx, graduate, diploma, nil)
+		require.ErrorIs(t, err, types.ErrSelfEndorsement)
+
+		endorsements, err := k.GetEndorsements(ctx, diploma)
+		require.NoError(t, err)
+		require.Empty(t, endorsements)
+	})
+
+	t.Run("rejects duplicates until the endorsement is revoked", func(t *testing.T) {
+		k, ctx, diploma := setup(t)
+
+		uid, err := k.EndorseAttestation(ctx, accreditor, diploma, nil)
+		require.NoError(t, err)
+		_, err = k.EndorseAttestation(ctx, accreditor, diploma, nil)
+		require.ErrorIs(t, err, types.ErrInvalidEndorsement)
+
+		require.NoError(t, k.RevokeAttestation(ctx, accreditor, uid))
+		_, err = k.EndorseAttestation(ctx, accreditor, diploma, []byte("re-accredited"))
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects missing, revoked and endorsement targets", func(t *testing.T) {
+		k, ctx, diploma := setup(t)
+
+		_, err := k.EndorseAttestation(ctx, accreditor, "missing", nil)
+		require.ErrorIs(t, err, types.ErrAttestationNotFound)
+
+		endorsement, err := k.EndorseAttestation(ctx, accreditor, diploma, nil)
+		require.NoError(t, err)
+		_, err = k.EndorseAttestation(ctx, ministry, endorsement, nil)
+		require.ErrorIs(t, err, types.ErrInvalidEndorsement)
+
+		require.NoError(t, k.RevokeAttestation(ctx, university, diploma))
+		_, err = k.EndorseAttestation(ctx, ministry, diploma, nil)
+		require.ErrorIs(t, err, types.ErrAttestationAlreadyRevoked)
+	})
+}
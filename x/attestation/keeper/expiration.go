@@ -0,0 +1,150 @@
+package keeper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// indexExpiration records attestation under its expiration time, if it expires
+func (k Keeper) indexExpiration(ctx sdk.Context, attestation types.Attestation) {
+	if attestation.ExpirationTime.IsZero() {
+		return
+	}
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetAttestationByExpirationKey(attestation.ExpirationTime.Unix(), attestation.UID), []byte{1})
+}
+
+// checkExpirationTime rejects an expiration at or before the current block's
+// second. expireAttestations has already walked the index up to that second, so
+// an attestation indexed behind its cursor would never be expired or pruned.
+func checkExpirationTime(ctx sdk.Context, expirationTime time.Time) error {
+	if !expirationTime.IsZero() && expirationTime.Unix() <= ctx.BlockTime().Unix() {
+		return errorsmod.Wrapf(types.ErrAttestationExpired, "expiration time %d is not after block time %d", expirationTime.Unix(), ctx.BlockTime().Unix())
+	}
+	return nil
+}
+
+// GetAttestationsExpiringBetween returns up to limit unrevoked attestations
+// expiring between from and to (inclusive, second precision), soonest first
+func (k Keeper) GetAttestationsExpiringBetween(ctx sdk.Context, from, to time.Time, limit int) []types.Attestation {
+	start, end := max(from.Unix(), 0), to.Unix()
+	if end < start || limit <= 0 {
+		return nil
+	}
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(
+		types.GetAttestationsByExpirationIteratorPrefix(start),
+		types.GetAttestationsByExpirationIteratorPrefix(end+1),
+	)
+	defer iterator.Close()
+
+	prefixLen := len(types.AttestationByExpirationPrefix) + 8
+	var attestations []types.Attestation
+	for ; iterator.Valid() && len(attestations) < limit; iterator.Next() {
+		attestation, err := k.GetAttestation(ctx, string(iterator.Key()[prefixLen:]))
+		if err != nil || !attestation.RevocationTime.IsZero() {
+			continue
+		}
+		attestations = append(attestations, *attestation)
+	}
+	return attestations
+}
+
+// EndBlock expires attestations whose expiration time has passed, then prunes
+// those revoked or expired longer ago than the retention period
+func (k Keeper) EndBlock(ctx sdk.Context) error {
+	if err := k.expireAttestations(ctx); err != nil {
+		return err
+	}
+	return k.pruneAttestations(ctx)
+}
+
+// expireAttestations marks attestations whose expiration time has passed as
+// expired and emits an attestation_expired event for each. It walks the
+// expiration index from where the previous block stopped, handling at most
+// MaxExpirationsPerBlock entries, so its cost does not grow with the store.
+func (k Keeper) expireAttestations(ctx sdk.Context) error {
+	store := ctx.KVStore(k.storeKey)
+	start := types.GetAttestationsByExpirationIteratorPrefix(0)
+	if cursor := store.Get(types.ExpirationCursorKey); cursor != nil {
+		start = append(append([]byte{}, cursor...), 0x00)
+	}
+	end := types.GetAttestationsByExpirationIteratorPrefix(ctx.BlockTime().Unix() + 1)
+	if bytes.Compare(start, end) >= 0 {
+		return nil
+	}
+
+	prefixLen := len(types.AttestationByExpirationPrefix) + 8
+	var keys [][]byte
+	iterator := store.Iterator(start, end)
+	for ; iterator.Valid() && len(keys) < types.MaxExpirationsPerBlock; iterator.Next() {
+		key := iterator.Key()
+		expiresAt := int64(types.BytesToUint64(key[len(types.AttestationByExpirationPrefix):prefixLen]))
+		if !ctx.BlockTime().After(time.Unix(expiresAt, 0)) {
+			break
+		}
+		keys = append(keys, key)
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		if err := k.expireAttestation(ctx, string(key[prefixLen:])); err != nil {
+			return err
+		}
+	}
+	if len(keys) > 0 {
+		store.Set(types.ExpirationCursorKey, keys[len(keys)-1])
+	}
+	return nil
+}
+
+// expireAttestation marks uid expired and emits its event, unless it is
+// missing, revoked or already marked
+func (k Keeper) expireAttestation(ctx sdk.Context, uid string) error {
+	store := ctx.KVStore(k.storeKey)
+	attestation, err := k.GetAttestation(ctx, uid)
+	if err != nil || attestation.Expired || !attestation.RevocationTime.IsZero() {
+		return nil
+	}
+
+	// Encrypted attestations keep their full record under both keys
+	if store.Has(types.GetEncryptedAttestationKey(uid)) {
+		encrypted, err := k.GetEncryptedAttestation(ctx, uid)
+		if err != nil {
+			return err
+		}
+		encrypted.Expired = true
+		bz, err := json.Marshal(encrypted)
+		if err != nil {
+			return fmt.Errorf("failed to marshal expired attestation: %w", err)
+		}
+		store.Set(types.GetEncryptedAttestationKey(uid), bz)
+		store.Set(types.GetAttestationKey(uid), bz)
+	} else {
+		attestation.Expired = true
+		bz, err := marshalAttestation(*attestation)
+		if err != nil {
+			return fmt.Errorf("failed to marshal expired attestation: %w", err)
+		}
+		store.Set(types.GetAttestationKey(uid), bz)
+	}
+	k.queueForPruning(ctx, attestation.ExpirationTime, uid)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestationExpired,
+			sdk.NewAttribute(types.AttributeKeyAttestationUID, uid),
+			sdk.NewAttribute(types.AttributeKeySchemaUID, attestation.SchemaUID),
+			sdk.NewAttribute(types.AttributeKeyExpirationTime, strconv.FormatInt(attestation.ExpirationTime.Unix(), 10)),
+		),
+	)
+	return nil
+}
@@ -0,0 +1,211 @@
+package keeper_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation"
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestExpiringAttestations(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	recipient := sdk.AccAddress("recipient___________")
+	now := time.Unix(1_700_000_000, 0).UTC()
+	day := 24 * time.Hour
+
+	k, ctx := setupTestKeeper(t)
+	ctx = ctx.WithBlockTime(now)
+	schemaUID, err := k.RegisterSchema(ctx, issuer, "string credential", nil, true)
+	require.NoError(t, err)
+
+	create := func(expiresAt time.Time) string {
+		uid, err := k.CreateAttestation(ctx, issuer, schemaUID, recipient, expiresAt, true, "", []byte("credential"))
+		require.NoError(t, err)
+		return uid
+	}
+	in30 := create(now.Add(30 * day))
+	in7 := create(now.Add(7 * day))
+	in1 := create(now.Add(day))
+	revoked := create(now.Add(2 * day))
+	create(now.Add(90 * day))
+	create(time.Time{})
+	require.NoError(t, k.RevokeAttestation(ctx, issuer, revoked))
+
+	uidsOf := func(attestations []types.Attestation) []string {
+		uids := make([]string, 0, len(attestations))
+		for _, a := range attestations {
+			uids = append(uids, a.UID)
+		}
+		return uids
+	}
+
+	t.Run("returns unrevoked attestations in the window, soonest first", func(t *testing.T) {
+		expiring := k.GetAttestationsExpiringBetween(ctx, now, now.Add(30*day), 100)
+		require.Equal(t, []string{in1, in7, in30}, uidsOf(expiring))
+
+		// Both bounds are inclusive
+		expiring = k.GetAttestationsExpiringBetween(ctx, now.Add(7*day), now.Add(30*day), 100)
+		require.Equal(t, []string{in7, in30}, uidsOf(expiring))
+
+		require.Equal(t, []string{in1}, uidsOf(k.GetAttestationsExpiringBetween(ctx, now, now.Add(30*day), 1)))
+		require.Empty(t, k.GetAttestationsExpiringBetween(ctx, now.Add(30*day), now, 100))
+	})
+
+	t.Run("is served by the query server", func(t *testing.T) {
+		res, err := keeper.NewQueryServerImpl(k).ExpiringAttestations(ctx, &types.QueryExpiringAttestationsRequest{
+			From: now.Unix(),
+			To:   now.Add(7 * day).Unix(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{in1, in7}, uidsOf(res.Attestations))
+	})
+
+	t.Run("is rebuilt from genesis", func(t *testing.T) {
+		exported := attestation.ExportGenesis(ctx, k)
+		imported, importCtx := setupTestKeeper(t)
+		attestation.InitGenesis(importCtx, imported, *exported)
+
+		expiring := imported.GetAttestationsExpiringBetween(importCtx, now, now.Add(30*day), 100)
+		require.Equal(t, []string{in1, in7, in30}, uidsOf(expiring))
+	})
+}
+
+func TestEndBlockExpiresAttestations(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	recipient := sdk.AccAddress("recipient___________")
+	now := time.Unix(1_700_000_000, 0).UTC()
+
+	k, ctx := setupTestKeeper(t)
+	ctx = ctx.WithBlockTime(now)
+	schemaUID, err := k.RegisterSchema(ctx, issuer, "string credential", nil, true)
+	require.NoError(t, err)
+
+	create := func(expiresAt time.Time) string {
+		uid, err := k.CreateAttestation(ctx, issuer, schemaUID, recipient, expiresAt, true, "", []byte("credential"))
+		require.NoError(t, err)
+		return uid
+	}
+	soon := create(now.Add(time.Hour))
+	later := create(now.Add(48 * time.Hour))
+	revoked := create(now.Add(time.Hour))
+	never := create(time.Time{})
+	require.NoError(t, k.RevokeAttestation(ctx, issuer, revoked))
+	encrypted, err := k.CreateEncryptedAttestation(ctx, issuer, schemaUID, "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi", strings.Repeat("ab", 32),
+		[]sdk.AccAddress{recipient}, map[string]string{recipient.String(): "wrapped"}, true, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	endBlock := func(at time.Time) []sdk.Event {
+		blockCtx := ctx.WithBlockTime(at).WithEventManager(sdk.NewEventManager())
+		require.NoError(t, k.EndBlock(blockCtx))
+		var expired []sdk.Event
+		for _, e := range blockCtx.EventManager().Events() {
+			if e.Type == types.EventTypeAttestationExpired {
+				expired = append(expired, e)
+			}
+		}
+		return expired
+	}
+	isExpired := func(uid string) bool {
+		a, err := k.GetAttestation(ctx, uid)
+		require.NoError(t, err)
+		return a.Expired
+	}
+
+	require.Empty(t, endBlock(now.Add(time.Hour)), "expiration time itself is not past")
+	require.False(t, isExpired(soon))
+
+	events := endBlock(now.Add(time.Hour + time.Second))
+	require.Len(t, events, 2)
+	for _, e := range events {
+		uid, _ := e.GetAttribute(types.AttributeKeyAttestationUID)
+		schema, _ := e.GetAttribute(types.AttributeKeySchemaUID)
+		require.Contains(t, []string{soon, encrypted}, uid.Value)
+		require.Equal(t, schemaUID, schema.Value)
+	}
+	require.True(t, isExpired(soon))
+	require.True(t, isExpired(encrypted))
+	require.False(t, isExpired(revoked))
+	require.False(t, isExpired(later))
+	require.False(t, isExpired(never))
+
+	record, err := k.GetEncryptedAttestation(ctx, encrypted)
+	require.NoError(t, err)
+	require.True(t, record.Expired)
+	require.Equal(t, "wrapped", record.EncryptedSymmetricKeys[recipient.String()])
+
+	require.Empty(t, endBlock(now.Add(2*time.Hour)), "expired attestations are not reported again")
+	require.Len(t, endBlock(now.Add(72*time.Hour)), 1)
+	require.True(t, isExpired(later))
+}
+
+func TestExpirationAfterCursorAdvances(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	now := time.Unix(1_700_000_000, 0).UTC()
+
+	k, ctx := setupTestKeeper(t)
+	ctx = ctx.WithBlockTime(now)
+	schemaUID, err := k.RegisterSchema(ctx, issuer, "string credential", nil, true)
+	require.NoError(t, err)
+	_, err = k.CreateAttestation(ctx, issuer, schemaUID, nil, now.Add(time.Minute), true, "", []byte("first"))
+	require.NoError(t, err)
+
+	// Expiring the first attestation moves the cursor past now+1m
+	later := ctx.WithBlockTime(now.Add(time.Hour)).WithEventManager(sdk.NewEventManager())
+	require.NoError(t, k.EndBlock(later))
+
+	// A back-dated expiration would sit behind the cursor and never expire, so it is rejected
+	for _, expiresAt := range []time.Time{now.Add(2 * time.Minute), later.BlockTime(), later.BlockTime().Add(500 * time.Millisecond)} {
+		_, err := k.CreateAttestation(later, issuer, schemaUID, nil, expiresAt, true, "", []byte("backdated"))
+		require.ErrorIs(t, err, types.ErrAttestationExpired, "expiration %s", expiresAt)
+		_, err = k.CreateEncryptedAttestation(later, issuer, schemaUID, "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi", strings.Repeat("ab", 32),
+			[]sdk.AccAddress{issuer}, map[string]string{issuer.String(): "wrapped"}, true, expiresAt)
+		require.ErrorIs(t, err, types.ErrAttestationExpired, "encrypted expiration %s", expiresAt)
+	}
+
+	// The next second is ahead of the cursor and expires as usual
+	uid, err := k.CreateAttestation(later, issuer, schemaUID, nil, later.BlockTime().Add(time.Second), true, "", []byte("next"))
+	require.NoError(t, err)
+	next := later.WithBlockTime(later.BlockTime().Add(time.Minute)).WithEventManager(sdk.NewEventManager())
+	require.NoError(t, k.EndBlock(next))
+	a, err := k.GetAttestation(next, uid)
+	require.NoError(t, err)
+	require.True(t, a.Expired)
+}
+
+func TestEndBlockExpirationIsBounded(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	now := time.Unix(1_700_000_000, 0).UTC()
+
+	k, ctx := setupTestKeeper(t)
+	ctx = ctx.WithBlockTime(now)
+	schemaUID, err := k.RegisterSchema(ctx, issuer, "uint64 n", nil, true)
+	require.NoError(t, err)
+	total := types.MaxExpirationsPerBlock + 10
+	for i := 0; i < total; i++ {
+		_, err := k.CreateAttestation(ctx, issuer, schemaUID, nil, now.Add(time.Minute), true, "", []byte{byte(i), byte(i >> 8)})
+		require.NoError(t, err)
+	}
+
+	expired := 0
+	for block := 0; block < 2; block++ {
+		blockCtx := ctx.WithBlockTime(now.Add(time.Hour)).WithEventManager(sdk.NewEventManager())
+		require.NoError(t, k.EndBlock(blockCtx))
+		n := 0
+		for _, e := range blockCtx.EventManager().Events() {
+			if e.Type == types.EventTypeAttestationExpired {
+				n++
+			}
+		}
+		if block == 0 {
+			require.Equal(t, types.MaxExpirationsPerBlock, n)
+		}
+		expired += n
+	}
+	require.Equal(t, total, expired)
+}
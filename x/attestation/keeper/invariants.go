@@ -0,0 +1,249 @@
+package keeper
+
+import (
+	"fmt"
+
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// generatedUIDLength is the length of a keeper-generated attestation UID (hex SHA-256).
+// The attester, recipient and schema index keys are not length-prefixed, so the
+// indexed UID is recovered as the key's trailing generatedUIDLength bytes.
+const generatedUIDLength = 64
+
+// RegisterInvariants registers the attestation module invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(types.ModuleName, "attestation-counts", AttestationCountInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "attestation-indexes", AttestationIndexInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "encrypted-recipient-keys", EncryptedRecipientKeysInvariant(k))
+}
+
+// AllInvariants runs every attestation module invariant
+func AllInvariants(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		for _, invariant := range []sdk.Invariant{
+			AttestationCountInvariant(k),
+			AttestationIndexInvariant(k),
+			EncryptedRecipientKeysInvariant(k),
+		} {
+			if msg, broken := invariant(ctx); broken {
+				return msg, broken
+			}
+		}
+		return "", false
+	}
+}
+
+// AttestationCountInvariant checks that the attestation, encrypted attestation,
+// schema and revocation counters match the number of stored records and
+// tombstones, that every encrypted attestation is also stored as an
+// attestation, and that the current block's batch revocations are within the
+// per-block cap
+func AttestationCountInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		store := ctx.KVStore(k.storeKey)
+
+		attestations := countPrefix(store, types.AttestationKeyPrefix)
+		encrypted, revoked := uint64(0), uint64(0)
+		for _, attestation := range k.GetAllAttestations(ctx) {
+			if !attestation.RevocationTime.IsZero() {
+				revoked++
+			}
+		}
+		for _, tombstone := range k.GetAllTombstones(ctx) {
+			attestations++
+			if tombstone.Encrypted {
+				encrypted++
+			}
+			if !tombstone.RevocationTime.IsZero() {
+				revoked++
+			}
+		}
+		var missing []string
+		iterator := storetypes.KVStorePrefixIterator(store, types.EncryptedAttestationKeyPrefix)
+		for ; iterator.Valid(); iterator.Next() {
+			encrypted++
+			uid := string(iterator.Key()[len(types.EncryptedAttestationKeyPrefix):])
+			if !store.Has(types.GetAttestationKey(uid)) {
+				missing = append(missing, uid)
+			}
+		}
+		iterator.Close()
+
+		var msg string
+		broken := false
+		if count := k.GetAttestationCount(ctx); count != attestations {
+			broken = true
+			msg += fmt.Sprintf("\tattestation count %d, stored attestations %d\n", count, attestations)
+		}
+		if count := k.GetEncryptedAttestationCount(ctx); count != encrypted {
+			broken = true
+			msg += fmt.Sprintf("\tencrypted attestation count %d, stored encrypted attestations %d\n", count, encrypted)
+		}
+		if count, schemas := k.GetSchemaCount(ctx), countPrefix(store, types.SchemaKeyPrefix); count != schemas {
+			broken = true
+			msg += fmt.Sprintf("\tschema count %d, stored schemas %d\n", count, schemas)
+		}
+		if count := k.GetRevocationCount(ctx); count != revoked {
+			broken = true
+			msg += fmt.Sprintf("\trevocation count %d, revoked attestations %d\n", count, revoked)
+		}
+		for _, uid := range missing {
+			broken = true
+			msg += fmt.Sprintf("\tencrypted attestation %s has no attestation record\n", uid)
+		}
+
+		limit := k.GetParams(ctx).MaxBatchRevocationsPerBlock
+		if limit == 0 {
+			limit = types.DefaultMaxBatchRevocationsPerBlock
+		}
+		if count := k.GetBlockBatchRevocationCount(ctx); count > uint64(limit) {
+			broken = true
+			msg += fmt.Sprintf("\t%d batch revocations in block %d exceed the %d cap\n", count, ctx.BlockHeight(), limit)
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "attestation-counts", msg), broken
+	}
+}
+
+// AttestationIndexInvariant checks that no index entry points at a missing
+// attestation or schema. An entry pointing at a pruned attestation's tombstone
+// is not dangling: the document hash and tag indexes are kept after pruning.
+func AttestationIndexInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		store := ctx.KVStore(k.storeKey)
+
+		var dangling []string
+		check := func(index string, key []byte, uid string) {
+			if !store.Has(types.GetAttestationKey(uid)) && !store.Has(types.GetAttestationTombstoneKey(uid)) {
+				dangling = append(dangling, fmt.Sprintf("%s index %x points at missing attestation %s", index, key, uid))
+			}
+		}
+		trailingUID := func(key []byte) string {
+			if len(key) < generatedUIDLength {
+				return ""
+			}
+			return string(key[len(key)-generatedUIDLength:])
+		}
+
+		for _, index := range []struct {
+			name   string
+			prefix []byte
+		}{
+			{"attester", types.AttestationByAttesterPrefix},
+			{"recipient", types.AttestationByRecipientPrefix},
+			{"schema", types.AttestationBySchemaPrefix},
+		} {
+			iterator := storetypes.KVStorePrefixIterator(store, index.prefix)
+			for ; iterator.Valid(); iterator.Next() {
+				check(index.name, iterator.Key(), trailingUID(iterator.Key()))
+			}
+			iterator.Close()
+		}
+
+		// Schema index keys are the schema UID followed by the attestation UID
+		iterator := storetypes.KVStorePrefixIterator(store, types.AttestationBySchemaPrefix)
+		for ; iterator.Valid(); iterator.Next() {
+			key := iterator.Key()
+			if len(key) < len(types.AttestationBySchemaPrefix)+generatedUIDLength {
+				continue // Reported as a missing attestation above
+			}
+			schemaUID := string(key[len(types.AttestationBySchemaPrefix) : len(key)-generatedUIDLength])
+			if !store.Has(types.GetSchemaKey(schemaUID)) {
+				dangling = append(dangling, fmt.Sprintf("schema index %x points at missing schema %s", key, schemaUID))
+			}
+		}
+		iterator.Close()
+
+		// Length-prefixed indexes: prefix | len | value | uid
+		for _, index := range []struct {
+			name   string
+			prefix []byte
+		}{
+			{"document hash", types.AttestationByDocumentHashPrefix},
+			{"tag", types.AttestationByTagPrefix},
+		} {
+			iterator := storetypes.KVStorePrefixIterator(store, index.prefix)
+			for ; iterator.Valid(); iterator.Next() {
+				key := iterator.Key()
+				start := len(index.prefix) + 1
+				if len(key) < start || len(key) < start+int(key[start-1]) {
+					dangling = append(dangling, fmt.Sprintf("%s index key %x is malformed", index.name, key))
+					continue
+				}
+				check(index.name, key, string(key[start+int(key[start-1]):]))
+			}
+			iterator.Close()
+		}
+
+		iterator = storetypes.KVStorePrefixIterator(store, types.AttestationByExpirationPrefix)
+		for ; iterator.Valid(); iterator.Next() {
+			key := iterator.Key()
+			check("expiration", key, string(key[min(len(key), len(types.AttestationByExpirationPrefix)+8):]))
+		}
+		iterator.Close()
+
+		iterator = storetypes.KVStorePrefixIterator(store, types.IPFSCIDIndexPrefix)
+		for ; iterator.Valid(); iterator.Next() {
+			check("IPFS CID", iterator.Key(), string(iterator.Value()))
+		}
+		iterator.Close()
+
+		iterator = storetypes.KVStorePrefixIterator(store, types.EndorsementByTargetPrefix)
+		for ; iterator.Valid(); iterator.Next() {
+			check("endorsement", iterator.Key(), string(iterator.Value()))
+		}
+		iterator.Close()
+
+		iterator = storetypes.KVStorePrefixIterator(store, types.AttestationReassignmentPrefix)
+		for ; iterator.Valid(); iterator.Next() {
+			key := iterator.Key()
+			check("reassignment", key, string(key[len(types.AttestationReassignmentPrefix):]))
+			check("reassignment", key, string(iterator.Value()))
+		}
+		iterator.Close()
+
+		var msg string
+		for _, d := range dangling {
+			msg += "\t" + d + "\n"
+		}
+		return sdk.FormatInvariant(types.ModuleName, "attestation-indexes", msg), len(dangling) > 0
+	}
+}
+
+// EncryptedRecipientKeysInvariant checks that every encrypted attestation has
+// recipients and a wrapped symmetric key for each of them
+func EncryptedRecipientKeysInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		broken := false
+		for _, attestation := range k.GetAllEncryptedAttestations(ctx) {
+			if len(attestation.Recipients) == 0 {
+				broken = true
+				msg += fmt.Sprintf("\tencrypted attestation %s has no recipients\n", attestation.UID)
+			}
+			for _, recipient := range attestation.Recipients {
+				if attestation.EncryptedSymmetricKeys[recipient.String()] == "" {
+					broken = true
+					msg += fmt.Sprintf("\tencrypted attestation %s has no key for recipient %s\n", attestation.UID, recipient)
+				}
+			}
+		}
+		return sdk.FormatInvariant(types.ModuleName, "encrypted-recipient-keys", msg), broken
+	}
+}
+
+// countPrefix counts the store entries under prefix
+func countPrefix(store storetypes.KVStore, prefix []byte) uint64 {
+	iterator := storetypes.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+
+	count := uint64(0)
+	for ; iterator.Valid(); iterator.Next() {
+		count++
+	}
+	return count
+}
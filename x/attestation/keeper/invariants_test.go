@@ -0,0 +1,108 @@
+package keeper_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestInvariants(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	recipient := sdk.AccAddress("recipient___________")
+
+	// setup returns a keeper with public, tagged, expiring and encrypted
+	// attestations, and its store for corrupting
+	setup := func(t *testing.T) (keeper.Keeper, sdk.Context, storetypes.KVStore, string) {
+		storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+		ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).
+			WithBlockTime(time.Unix(1_700_000_000, 0).UTC())
+		k := keeper.NewKeeper(nil, storeKey, nil, "")
+
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 programHash", nil, true)
+		require.NoError(t, err)
+		data := make([]byte, 32)
+		data[31] = 1
+		uid, err := k.CreateAttestationWithTags(ctx, issuer, schemaUID, recipient, ctx.BlockTime().Add(time.Hour), true, "", data, []string{"diploma"})
+		require.NoError(t, err)
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, uid))
+		_, err = k.CreateEncryptedAttestation(ctx, issuer, schemaUID, "Qm"+strings.Repeat("a", 44), strings.Repeat("ab", 32),
+			[]sdk.AccAddress{recipient}, map[string]string{recipient.String(): "key"}, true, time.Time{})
+		require.NoError(t, err)
+		return k, ctx, ctx.KVStore(storeKey), uid
+	}
+
+	t.Run("hold for keeper-written state", func(t *testing.T) {
+		k, ctx, _, _ := setup(t)
+		msg, broken := keeper.AllInvariants(k)(ctx)
+		require.False(t, broken, msg)
+	})
+
+	t.Run("counter mismatch", func(t *testing.T) {
+		k, ctx, store, _ := setup(t)
+		store.Set(types.AttestationCountKey, types.Uint64ToBytes(5))
+
+		msg, broken := keeper.AttestationCountInvariant(k)(ctx)
+		require.True(t, broken)
+		require.Contains(t, msg, "attestation count 5, stored attestations 2")
+	})
+
+	t.Run("dangling indexes", func(t *testing.T) {
+		k, ctx, store, uid := setup(t)
+		store.Delete(types.GetAttestationKey(uid))
+		store.Set(types.AttestationCountKey, types.Uint64ToBytes(1))
+		store.Set(types.RevocationCountKey, types.Uint64ToBytes(0))
+
+		_, broken := keeper.AttestationCountInvariant(k)(ctx)
+		require.False(t, broken)
+		msg, broken := keeper.AttestationIndexInvariant(k)(ctx)
+		require.True(t, broken)
+		for _, index := range []string{"attester", "recipient", "schema", "document hash", "tag", "expiration"} {
+			require.Contains(t, msg, index+" index")
+		}
+	})
+
+	t.Run("encrypted attestation without attestation record", func(t *testing.T) {
+		k, ctx, store, _ := setup(t)
+		encrypted := k.GetAllEncryptedAttestations(ctx)[0]
+		store.Delete(types.GetAttestationKey(encrypted.UID))
+
+		msg, broken := keeper.AttestationCountInvariant(k)(ctx)
+		require.True(t, broken)
+		require.Contains(t, msg, "encrypted attestation "+encrypted.UID+" has no attestation record")
+		msg, broken = keeper.AttestationIndexInvariant(k)(ctx)
+		require.True(t, broken)
+		require.Contains(t, msg, "IPFS CID index")
+	})
+
+	t.Run("batch revocations over the block cap", func(t *testing.T) {
+		k, ctx, store, _ := setup(t)
+		over := types.DefaultMaxBatchRevocationsPerBlock + 1
+		store.Set(types.BlockBatchRevocationCountKey, append(types.Uint64ToBytes(uint64(ctx.BlockHeight())), types.Uint64ToBytes(uint64(over))...))
+
+		msg, broken := keeper.AttestationCountInvariant(k)(ctx)
+		require.True(t, broken)
+		require.Contains(t, msg, "batch revocations")
+	})
+
+	t.Run("recipient without a wrapped key", func(t *testing.T) {
+		k, ctx, store, _ := setup(t)
+		encrypted := k.GetAllEncryptedAttestations(ctx)[0]
+		encrypted.Recipients = append(encrypted.Recipients, issuer)
+		bz, err := json.Marshal(encrypted)
+		require.NoError(t, err)
+		store.Set(types.GetEncryptedAttestationKey(encrypted.UID), bz)
+
+		msg, broken := keeper.AllInvariants(k)(ctx)
+		require.True(t, broken)
+		require.Contains(t, msg, "no key for recipient "+issuer.String())
+	})
+}
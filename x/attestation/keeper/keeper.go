@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	errorsmod "cosmossdk.io/errors"
 	"cosmossdk.io/log"
 	storetypes "cosmossdk.io/store/types"
 
@@ -21,6 +22,9 @@ type Keeper struct {
 	storeKey storetypes.StoreKey
 	memKey   storetypes.StoreKey
 
+	// bankKeeper collects schema fees; nil until SetBankKeeper is called
+	bankKeeper types.BankKeeper
+
 	// Authority is the address capable of executing governance proposals
 	authority string
 }
@@ -39,6 +43,11 @@ func NewKeeper(
 	}
 }
 
+// SetBankKeeper sets the bank keeper used to collect schema fees
+func (k *Keeper) SetBankKeeper(bk types.BankKeeper) {
+	k.bankKeeper = bk
+}
+
 // Logger returns a module-specific logger
 func (k Keeper) Logger(ctx sdk.Context) log.Logger {
 	return ctx.Logger().With("module", fmt.Sprintf("x/%s", types.ModuleName))
@@ -51,8 +60,70 @@ func (k Keeper) GetAuthority() string {
 
 // RegisterSchema registers a new attestation schema
 func (k Keeper) RegisterSchema(ctx sdk.Context, creator sdk.AccAddress, schema string, resolver sdk.AccAddress, revocable bool) (string, error) {
+	return k.RegisterSchemaWithFee(ctx, creator, schema, resolver, revocable, nil, 0)
+}
+
+// RegisterSchemaWithFee registers a new attestation schema whose attestations
+// pay fee, creatorShareBps of which goes to the creator
+func (k Keeper) RegisterSchemaWithFee(
+	ctx sdk.Context,
+	creator sdk.AccAddress,
+	schema string,
+	resolver sdk.AccAddress,
+	revocable bool,
+	fee sdk.Coins,
+	creatorShareBps uint32,
+) (string, error) {
+	return k.registerSchema(ctx, creator, schema, resolver, revocable, fee, creatorShareBps, 0)
+}
+
+// RegisterSchemaWithStaleness registers a new attestation schema whose
+// attestations go stale unless reaffirmed within maxStaleness seconds
+func (k Keeper) RegisterSchemaWithStaleness(ctx sdk.Context, creator sdk.AccAddress, schema string, resolver sdk.AccAddress, revocable bool, maxStaleness uint64) (string, error) {
+	return k.registerSchema(ctx, creator, schema, resolver, revocable, nil, 0, maxStaleness)
+}
+
+// validateSchemaData checks attestation data against the schema's field layout.
+// Schemas stored before definitions were parsed at registration may not parse;
+// their data is accepted unchecked, as it was when they were registered, rather
+// than blocking every new attestation against them.
+func validateSchemaData(schema *types.Schema, data []byte) error {
+	if _, err := types.ParseSchema(schema.Schema); err != nil {
+		return nil
+	}
+	return types.ValidateSchemaData(schema.Schema, data)
+}
+
+// registerSchema validates and stores a new schema
+func (k Keeper) registerSchema(
+	ctx sdk.Context,
+	creator sdk.AccAddress,
+	schema string,
+	resolver sdk.AccAddress,
+	revocable bool,
+	fee sdk.Coins,
+	creatorShareBps uint32,
+	maxStaleness uint64,
+) (string, error) {
 	store := ctx.KVStore(k.storeKey)
 
+	// Attestation data is validated against the field types, so they must parse
+	if _, err := types.ParseSchema(schema); err != nil {
+		return "", err
+	}
+	if err := types.ValidateSchemaFee(fee, creatorShareBps); err != nil {
+		return "", err
+	}
+	if err := types.ValidateMaxStaleness(maxStaleness); err != nil {
+		return "", err
+	}
+	if limit := k.maxSchemaCreatorShareBps(ctx); creatorShareBps > limit {
+		return "", errorsmod.Wrapf(types.ErrInvalidSchemaFee, "creator share %d bps exceeds the %d bps limit", creatorShareBps, limit)
+	}
+	if denom := k.feeDenom(ctx); !fee.IsZero() && (len(fee) != 1 || fee[0].Denom != denom) {
+		return "", errorsmod.Wrapf(types.ErrInvalidSchemaFee, "fee %s must be in %s", fee, denom)
+	}
+
 	// Generate schema UID
 	schemaUID := types.GenerateSchemaUID(schema, resolver, revocable)
 
@@ -63,11 +134,16 @@ func (k Keeper) RegisterSchema(ctx sdk.Context, creator sdk.AccAddress, schema s
 
 	// Create schema object
 	schemaObj := types.Schema{
-		UID:       schemaUID,
-		Resolver:  resolver,
-		Revocable: revocable,
-		Schema:    schema,
-		Creator:   creator,
+		UID:          schemaUID,
+		Resolver:     resolver,
+		Revocable:    revocable,
+		Schema:       schema,
+		Creator:      creator,
+		Fee:          fee,
+		MaxStaleness: maxStaleness,
+	}
+	if !fee.IsZero() {
+		schemaObj.CreatorShareBps = creatorShareBps
 	}
 
 	// Serialize and store
@@ -77,6 +153,7 @@ func (k Keeper) RegisterSchema(ctx sdk.Context, creator sdk.AccAddress, schema s
 	}
 
 	store.Set(types.GetSchemaKey(schemaUID), bz)
+	k.incrementSchemaCount(ctx)
 
 	k.Logger(ctx).Info("Schema registered", "uid", schemaUID, "creator", creator.String())
 
@@ -89,7 +166,7 @@ func (k Keeper) GetSchema(ctx sdk.Context, uid string) (*types.Schema, error) {
 
 	bz := store.Get(types.GetSchemaKey(uid))
 	if bz == nil {
-		return nil, fmt.Errorf("schema not found: %s", uid)
+		return nil, errorsmod.Wrap(types.ErrSchemaNotFound, uid)
 	}
 
 	var schema types.Schema
@@ -110,19 +187,80 @@ func (k Keeper) CreateAttestation(
 	revocable bool,
 	refUID string,
 	data []byte,
+) (string, error) {
+	return k.CreateAttestationWithTags(ctx, attester, schemaUID, recipient, expirationTime, revocable, refUID, data, nil)
+}
+
+// CreateAttestationWithTags creates a new public attestation labelled with tags
+func (k Keeper) CreateAttestationWithTags(
+	ctx sdk.Context,
+	attester sdk.AccAddress,
+	schemaUID string,
+	recipient sdk.AccAddress,
+	expirationTime time.Time,
+	revocable bool,
+	refUID string,
+	data []byte,
+	tags []string,
+) (string, error) {
+	return k.createAttestation(ctx, attester, schemaUID, recipient, expirationTime, revocable, refUID, data, tags, true)
+}
+
+// createAttestation creates a public attestation, collecting the schema and
+// attestation fees when payFee is set
+func (k Keeper) createAttestation(
+	ctx sdk.Context,
+	attester sdk.AccAddress,
+	schemaUID string,
+	recipient sdk.AccAddress,
+	expirationTime time.Time,
+	revocable bool,
+	refUID string,
+	data []byte,
+	tags []string,
+	payFee bool,
 ) (string, error) {
 	store := ctx.KVStore(k.storeKey)
 
+	tags, err := types.NormalizeTags(tags)
+	if err != nil {
+		return "", err
+	}
+
 	// Verify schema exists
 	schema, err := k.GetSchema(ctx, schemaUID)
 	if err != nil {
 		return "", err
 	}
+	if err := k.checkSchemaAllowed(ctx, schema); err != nil {
+		return "", err
+	}
 
 	// Check revocability against schema
 	if revocable && !schema.Revocable {
 		return "", fmt.Errorf("schema does not allow revocable attestations")
 	}
+	if err := checkExpirationTime(ctx, expirationTime); err != nil {
+		return "", err
+	}
+
+	// The size limit applies to the data as submitted, not its stored form
+	limit := k.GetParams(ctx).MaxAttestationDataSize
+	if limit == 0 {
+		limit = types.DefaultMaxAttestationDataSize
+	}
+	if uint64(len(data)) > limit {
+		return "", errorsmod.Wrapf(types.ErrAttestationDataTooLarge, "%d bytes exceeds %d", len(data), limit)
+	}
+
+	if payFee {
+		if err := k.collectSchemaFee(ctx, attester, schema); err != nil {
+			return "", err
+		}
+		if err := k.collectAttestationFee(ctx, attester); err != nil {
+			return "", err
+		}
+	}
 
 	// Generate attestation UID
 	nonce := k.GetAttestationCount(ctx)
@@ -140,10 +278,11 @@ func (k Keeper) CreateAttestation(
 		RefUID:          refUID,
 		Data:            data,
 		AttestationType: types.AttestationTypePublic,
+		Tags:            tags,
 	}
 
 	// Serialize and store
-	bz, err := json.Marshal(attestation)
+	bz, err := marshalAttestation(attestation)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal attestation: %w", err)
 	}
@@ -156,6 +295,9 @@ func (k Keeper) CreateAttestation(
 		store.Set(types.GetAttestationByRecipientKey(recipient, uid), []byte{1})
 	}
 	store.Set(types.GetAttestationBySchemaKey(schemaUID, uid), []byte{1})
+	k.indexDocumentHash(ctx, attestation, "")
+	k.indexTags(ctx, attestation)
+	k.indexExpiration(ctx, attestation)
 
 	// Increment attestation count
 	k.incrementAttestationCount(ctx)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	errorsmod "cosmossdk.io/errors"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/chaincertify/certd/x/attestation/types"
@@ -30,11 +31,21 @@ func (k Keeper) CreateEncryptedAttestation(
 	if err != nil {
 		return "", err
 	}
+	if err := k.checkSchemaAllowed(ctx, schema); err != nil {
+		return "", err
+	}
 
 	// Check revocability against schema
 	if revocable && !schema.Revocable {
 		return "", fmt.Errorf("schema does not allow revocable attestations")
 	}
+	if err := checkExpirationTime(ctx, expirationTime); err != nil {
+		return "", err
+	}
+
+	if limit := k.maxRecipientsPerAttestation(ctx); uint64(len(recipients)) > uint64(limit) {
+		return "", errorsmod.Wrapf(types.ErrTooManyRecipients, "%d recipients exceeds %d", len(recipients), limit)
+	}
 
 	// Validate IPFS CID format (basic validation)
 	if len(ipfsCID) < 46 {
@@ -46,6 +57,13 @@ func (k Keeper) CreateEncryptedAttestation(
 		return "", fmt.Errorf("invalid encrypted data hash format (expected SHA-256 hex)")
 	}
 
+	if err := k.collectSchemaFee(ctx, attester, schema); err != nil {
+		return "", err
+	}
+	if err := k.collectAttestationFee(ctx, attester); err != nil {
+		return "", err
+	}
+
 	// Generate attestation UID using encrypted data hash as part of data
 	nonce := k.GetAttestationCount(ctx)
 	uid := types.GenerateUID(attester, schemaUID, ctx.BlockTime(), []byte(encryptedDataHash), nonce)
@@ -90,6 +108,8 @@ func (k Keeper) CreateEncryptedAttestation(
 	store.Set(types.GetAttestationByAttesterKey(attester, uid), []byte{1})
 	store.Set(types.GetIPFSCIDIndexKey(ipfsCID), []byte(uid))
 	store.Set(types.GetAttestationBySchemaKey(schemaUID, uid), []byte{1})
+	k.indexDocumentHash(ctx, baseAttestation, encryptedDataHash)
+	k.indexExpiration(ctx, baseAttestation)
 
 	// Index by each recipient
 	for _, recipient := range recipients {
@@ -116,7 +136,7 @@ func (k Keeper) GetEncryptedAttestation(ctx sdk.Context, uid string) (*types.Enc
 
 	bz := store.Get(types.GetEncryptedAttestationKey(uid))
 	if bz == nil {
-		return nil, fmt.Errorf("encrypted attestation not found: %s", uid)
+		return nil, errorsmod.Wrapf(types.ErrAttestationNotFound, "encrypted attestation %s", uid)
 	}
 
 	var attestation types.EncryptedAttestation
@@ -177,3 +197,22 @@ func (k Keeper) GetEncryptedKeyForRecipient(ctx sdk.Context, uid string, recipie
 	return key, nil
 }
 
+// maxRecipientsPerAttestation returns the recipient cap per encrypted attestation
+func (k Keeper) maxRecipientsPerAttestation(ctx sdk.Context) uint32 {
+	if limit := k.GetParams(ctx).MaxRecipientsPerAttestation; limit > 0 {
+		return limit
+	}
+	return types.DefaultMaxRecipientsPerAttestation
+}
+
+// checkEncryptedFileSize rejects an encrypted file larger than MaxEncryptedFileSize
+func (k Keeper) checkEncryptedFileSize(ctx sdk.Context, size uint64) error {
+	limit := k.GetParams(ctx).MaxEncryptedFileSize
+	if limit == 0 {
+		limit = types.DefaultMaxEncryptedFileSize
+	}
+	if size > limit {
+		return errorsmod.Wrapf(types.ErrEncryptedFileTooLarge, "%d bytes exceeds %d", size, limit)
+	}
+	return nil
+}
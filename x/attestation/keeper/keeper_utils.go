@@ -3,7 +3,9 @@ package keeper
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
+	errorsmod "cosmossdk.io/errors"
 	storetypes "cosmossdk.io/store/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
@@ -16,21 +18,83 @@ func (k Keeper) GetAttestation(ctx sdk.Context, uid string) (*types.Attestation,
 
 	bz := store.Get(types.GetAttestationKey(uid))
 	if bz == nil {
-		return nil, fmt.Errorf("attestation not found: %s", uid)
+		return nil, errorsmod.Wrap(types.ErrAttestationNotFound, uid)
 	}
 
+	return unmarshalAttestation(bz)
+}
+
+// marshalAttestation serializes an attestation for the store, compressing its
+// Data when that makes it smaller
+func marshalAttestation(attestation types.Attestation) ([]byte, error) {
+	attestation.Data = types.CompressAttestationData(attestation.Data)
+	attestation.Stale = false // Derived at query time
+	return json.Marshal(attestation)
+}
+
+// unmarshalAttestation parses a stored attestation and restores its original Data
+func unmarshalAttestation(bz []byte) (*types.Attestation, error) {
 	var attestation types.Attestation
 	if err := json.Unmarshal(bz, &attestation); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal attestation: %w", err)
 	}
-
+	data, err := types.DecompressAttestationData(attestation.Data)
+	if err != nil {
+		return nil, err
+	}
+	attestation.Data = data
 	return &attestation, nil
 }
 
 // RevokeAttestation revokes an existing attestation
 func (k Keeper) RevokeAttestation(ctx sdk.Context, revoker sdk.AccAddress, uid string) error {
-	store := ctx.KVStore(k.storeKey)
+	if err := k.revokeOne(ctx, revoker, uid); err != nil {
+		return err
+	}
+
+	k.Logger(ctx).Info("Attestation revoked", "uid", uid, "revoker", revoker.String())
+
+	return nil
+}
 
+// BatchRevokeAttestations revokes every listed attestation or none of them.
+// Revocations are written to a cached context that is only committed once every
+// UID has been revoked, and the batch counts against the per-block
+// MaxBatchRevocationsPerBlock cap.
+func (k Keeper) BatchRevokeAttestations(ctx sdk.Context, revoker sdk.AccAddress, uids []string) error {
+	limit := k.GetParams(ctx).MaxBatchRevocationsPerBlock
+	if limit == 0 {
+		limit = types.DefaultMaxBatchRevocationsPerBlock
+	}
+	used := k.GetBlockBatchRevocationCount(ctx)
+	if used+uint64(len(uids)) > uint64(limit) {
+		return errorsmod.Wrapf(types.ErrRevocationLimitExceeded, "%d requested, %d of %d already used in block %d", len(uids), used, limit, ctx.BlockHeight())
+	}
+
+	cacheCtx, write := ctx.CacheContext()
+	seen := make(map[string]bool, len(uids))
+	for _, uid := range uids {
+		if seen[uid] {
+			return fmt.Errorf("duplicate attestation UID: %s", uid)
+		}
+		seen[uid] = true
+
+		if err := k.revokeOne(cacheCtx, revoker, uid); err != nil {
+			return err
+		}
+	}
+	write()
+	k.setBlockBatchRevocationCount(ctx, used+uint64(len(uids)))
+
+	k.Logger(ctx).Info("Attestations batch revoked", "count", len(uids), "revoker", revoker.String())
+
+	return nil
+}
+
+// revokeOne checks that revoker may revoke uid and records the revocation:
+// the attestation's revocation time, its document hash, the pruning queue and
+// the attester's revocation history
+func (k Keeper) revokeOne(ctx sdk.Context, revoker sdk.AccAddress, uid string) error {
 	attestation, err := k.GetAttestation(ctx, uid)
 	if err != nil {
 		return err
@@ -38,35 +102,95 @@ func (k Keeper) RevokeAttestation(ctx sdk.Context, revoker sdk.AccAddress, uid s
 
 	// Check if attestation is revocable
 	if !attestation.Revocable {
-		return fmt.Errorf("attestation is not revocable")
+		return errorsmod.Wrap(types.ErrAttestationNotRevocable, uid)
 	}
 
 	// Check if already revoked
 	if !attestation.RevocationTime.IsZero() {
-		return fmt.Errorf("attestation already revoked at: %s", attestation.RevocationTime.String())
+		return errorsmod.Wrapf(types.ErrAttestationAlreadyRevoked, "%s at %s", uid, attestation.RevocationTime.String())
 	}
 
 	// Only attester can revoke
 	if !attestation.Attester.Equals(revoker) {
-		return fmt.Errorf("only the attester can revoke this attestation")
+		return errorsmod.Wrapf(types.ErrUnauthorized, "only the attester can revoke attestation %s", uid)
 	}
 
 	// Set revocation time
 	attestation.RevocationTime = ctx.BlockTime()
 
 	// Update store
-	bz, err := json.Marshal(attestation)
+	bz, err := marshalAttestation(*attestation)
 	if err != nil {
 		return fmt.Errorf("failed to marshal revoked attestation: %w", err)
 	}
 
-	store.Set(types.GetAttestationKey(uid), bz)
-
-	k.Logger(ctx).Info("Attestation revoked", "uid", uid, "revoker", revoker.String())
+	ctx.KVStore(k.storeKey).Set(types.GetAttestationKey(uid), bz)
+	k.markDocumentHashRevoked(ctx, *attestation)
+	k.queueForPruning(ctx, attestation.RevocationTime, uid)
+	k.recordRevocation(ctx, uid, attestation.Attester, attestation.RevocationTime)
 
 	return nil
 }
 
+// BatchCreateAttestations creates one public attestation per entry under
+// schemaUID, or none of them: entries are written to a cached context that is
+// only committed once every entry has been accepted. Each entry's data must
+// decode against the schema, as for MsgAttest.
+func (k Keeper) BatchCreateAttestations(ctx sdk.Context, attester sdk.AccAddress, schemaUID string, revocable bool, tags []string, entries []types.BatchAttestEntry) ([]string, error) {
+	schema, err := k.GetSchema(ctx, schemaUID)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheCtx, write := ctx.CacheContext()
+	uids := make([]string, len(entries))
+	for i, entry := range entries {
+		if err := validateSchemaData(schema, entry.Data); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+
+		var recipient sdk.AccAddress
+		if entry.Recipient != "" {
+			recipient, err = sdk.AccAddressFromBech32(entry.Recipient)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: %w", i, err)
+			}
+		}
+		var expirationTime time.Time
+		if entry.ExpirationTime > 0 {
+			expirationTime = time.Unix(entry.ExpirationTime, 0)
+		}
+
+		uid, err := k.CreateAttestationWithTags(cacheCtx, attester, schemaUID, recipient, expirationTime, revocable, "", entry.Data, tags)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		uids[i] = uid
+	}
+	write()
+
+	k.Logger(ctx).Info("Attestations batch created", "count", len(uids), "attester", attester.String(), "schema", schemaUID)
+
+	return uids, nil
+}
+
+// GetBlockBatchRevocationCount returns how many attestations were batch revoked in the current block
+func (k Keeper) GetBlockBatchRevocationCount(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.BlockBatchRevocationCountKey)
+	if len(bz) != 16 || types.BytesToUint64(bz[:8]) != uint64(ctx.BlockHeight()) {
+		return 0
+	}
+	return types.BytesToUint64(bz[8:])
+}
+
+// setBlockBatchRevocationCount stores the batch revocation count for the current block
+func (k Keeper) setBlockBatchRevocationCount(ctx sdk.Context, count uint64) {
+	store := ctx.KVStore(k.storeKey)
+	bz := append(types.Uint64ToBytes(uint64(ctx.BlockHeight())), types.Uint64ToBytes(count)...)
+	store.Set(types.BlockBatchRevocationCountKey, bz)
+}
+
 // GetAttestationsByAttester returns all attestations created by an attester
 func (k Keeper) GetAttestationsByAttester(ctx sdk.Context, attester sdk.AccAddress) ([]types.Attestation, error) {
 	store := ctx.KVStore(k.storeKey)
@@ -116,11 +240,14 @@ func (k Keeper) GetAttestationsByRecipient(ctx sdk.Context, recipient sdk.AccAdd
 
 // IsAttestationExpired checks if an attestation has expired
 func (k Keeper) IsAttestationExpired(ctx sdk.Context, uid string) (bool, error) {
-	attestation, err := k.GetAttestation(ctx, uid)
+	attestation, err := k.getAttestationOrTombstone(ctx, uid)
 	if err != nil {
 		return false, err
 	}
 
+	if attestation.Expired {
+		return true, nil
+	}
 	if attestation.ExpirationTime.IsZero() {
 		return false, nil // Never expires
 	}
@@ -130,7 +257,7 @@ func (k Keeper) IsAttestationExpired(ctx sdk.Context, uid string) (bool, error)
 
 // IsAttestationRevoked checks if an attestation has been revoked
 func (k Keeper) IsAttestationRevoked(ctx sdk.Context, uid string) (bool, error) {
-	attestation, err := k.GetAttestation(ctx, uid)
+	attestation, err := k.getAttestationOrTombstone(ctx, uid)
 	if err != nil {
 		return false, err
 	}
@@ -171,3 +298,146 @@ func (k Keeper) incrementEncryptedAttestationCount(ctx sdk.Context) {
 	count := k.GetEncryptedAttestationCount(ctx)
 	store.Set(types.EncryptedAttestationCountKey, types.Uint64ToBytes(count+1))
 }
+
+// incrementSchemaCount increments the registered schema count
+func (k Keeper) incrementSchemaCount(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+	count := k.GetSchemaCount(ctx)
+	store.Set(types.SchemaCountKey, types.Uint64ToBytes(count+1))
+}
+
+// GetRevocationCount returns the number of revoked attestations, pruned ones included
+func (k Keeper) GetRevocationCount(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.RevocationCountKey)
+	if bz == nil {
+		return 0
+	}
+	return types.BytesToUint64(bz)
+}
+
+// addRevocationCount adds n revoked attestations to the revocation count
+func (k Keeper) addRevocationCount(ctx sdk.Context, n uint64) {
+	store := ctx.KVStore(k.storeKey)
+	count := k.GetRevocationCount(ctx)
+	store.Set(types.RevocationCountKey, types.Uint64ToBytes(count+n))
+}
+
+// maxAttestationChainLength bounds reassignment chain traversal
+const maxAttestationChainLength = 256
+
+// ReassignAttestation reissues an attestation to a new recipient, e.g. after the
+// recipient lost their key. The replacement copies the original's schema, data,
+// expiration and revocability, references the original via RefUID, and the
+// original is revoked. Only the original attester may reassign.
+func (k Keeper) ReassignAttestation(ctx sdk.Context, issuer sdk.AccAddress, uid string, newRecipient sdk.AccAddress) (string, error) {
+	store := ctx.KVStore(k.storeKey)
+
+	old, err := k.GetAttestation(ctx, uid)
+	if err != nil {
+		return "", errorsmod.Wrap(types.ErrAttestationNotFound, uid)
+	}
+	if !old.Attester.Equals(issuer) {
+		return "", errorsmod.Wrap(types.ErrUnauthorized, "only the attester can reassign this attestation")
+	}
+	if old.AttestationType != "" && old.AttestationType != types.AttestationTypePublic {
+		return "", errorsmod.Wrap(types.ErrInvalidReassignment, "only public attestations can be reassigned")
+	}
+	if !old.Revocable {
+		return "", errorsmod.Wrap(types.ErrAttestationNotRevocable, uid)
+	}
+	if !old.RevocationTime.IsZero() {
+		return "", errorsmod.Wrap(types.ErrAttestationAlreadyRevoked, uid)
+	}
+	if !old.ExpirationTime.IsZero() && ctx.BlockTime().After(old.ExpirationTime) {
+		return "", errorsmod.Wrap(types.ErrAttestationExpired, uid)
+	}
+	if old.Recipient.Equals(newRecipient) {
+		return "", errorsmod.Wrap(types.ErrInvalidReassignment, "new recipient matches the current recipient")
+	}
+
+	// The original attestation already paid the schema and attestation fees
+	newUID, err := k.createAttestation(ctx, issuer, old.SchemaUID, newRecipient, old.ExpirationTime, old.Revocable, old.UID, old.Data, old.Tags, false)
+	if err != nil {
+		return "", err
+	}
+
+	if err := k.RevokeAttestation(ctx, issuer, old.UID); err != nil {
+		return "", err
+	}
+	store.Set(types.GetAttestationReassignmentKey(old.UID), []byte(newUID))
+
+	k.Logger(ctx).Info("Attestation reassigned", "uid", old.UID, "new_uid", newUID, "recipient", newRecipient.String())
+
+	return newUID, nil
+}
+
+// GetReassignedTo returns the UID that replaced a reassigned attestation, if any
+func (k Keeper) GetReassignedTo(ctx sdk.Context, uid string) (string, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetAttestationReassignmentKey(uid))
+	if bz == nil {
+		return "", false
+	}
+	return string(bz), true
+}
+
+// GetAllReassignments returns every reassignment link, for genesis export
+func (k Keeper) GetAllReassignments(ctx sdk.Context) []types.AttestationReassignment {
+	iterator := storetypes.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.AttestationReassignmentPrefix)
+	defer iterator.Close()
+
+	var reassignments []types.AttestationReassignment
+	for ; iterator.Valid(); iterator.Next() {
+		reassignments = append(reassignments, types.AttestationReassignment{
+			UID:          string(iterator.Key()[len(types.AttestationReassignmentPrefix):]),
+			ReassignedTo: string(iterator.Value()),
+		})
+	}
+	return reassignments
+}
+
+// ImportReassignment restores a reassignment link during genesis
+func (k Keeper) ImportReassignment(ctx sdk.Context, reassignment types.AttestationReassignment) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetAttestationReassignmentKey(reassignment.UID), []byte(reassignment.ReassignedTo))
+}
+
+// GetAttestationChain returns the reassignment chain containing uid, ordered from
+// the original issuance to the current attestation
+func (k Keeper) GetAttestationChain(ctx sdk.Context, uid string) ([]types.Attestation, error) {
+	current, err := k.GetAttestation(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk back to the original: RefUID points at the predecessor only when the
+	// predecessor's reassignment link points back at us
+	root := current
+	for i := 0; i < maxAttestationChainLength && root.RefUID != ""; i++ {
+		next, ok := k.GetReassignedTo(ctx, root.RefUID)
+		if !ok || next != root.UID {
+			break
+		}
+		prev, err := k.GetAttestation(ctx, root.RefUID)
+		if err != nil {
+			break
+		}
+		root = prev
+	}
+
+	chain := []types.Attestation{*root}
+	for len(chain) < maxAttestationChainLength {
+		nextUID, ok := k.GetReassignedTo(ctx, chain[len(chain)-1].UID)
+		if !ok {
+			break
+		}
+		next, err := k.GetAttestation(ctx, nextUID)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, *next)
+	}
+
+	return chain, nil
+}
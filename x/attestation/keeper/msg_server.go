@@ -2,6 +2,8 @@ package keeper
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -40,7 +42,7 @@ func (k msgServer) RegisterSchema(goCtx context.Context, msg *types.MsgRegisterS
 		}
 	}
 
-	uid, err := k.Keeper.RegisterSchema(ctx, creator, msg.Schema, resolver, msg.Revocable)
+	uid, err := k.Keeper.registerSchema(ctx, creator, msg.Schema, resolver, msg.Revocable, msg.Fee, msg.CreatorShareBps, msg.MaxStaleness)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +54,9 @@ func (k msgServer) RegisterSchema(goCtx context.Context, msg *types.MsgRegisterS
 			sdk.NewAttribute(types.AttributeKeySchemaUID, uid),
 			sdk.NewAttribute(types.AttributeKeyCreator, msg.Creator),
 			sdk.NewAttribute(types.AttributeKeyRevocable, boolToString(msg.Revocable)),
+			sdk.NewAttribute(types.AttributeKeyFee, msg.Fee.String()),
+			sdk.NewAttribute(types.AttributeKeyCreatorShare, strconv.FormatUint(uint64(msg.CreatorShareBps), 10)),
+			sdk.NewAttribute(types.AttributeKeyMaxStaleness, strconv.FormatUint(msg.MaxStaleness, 10)),
 		),
 	)
 
@@ -80,12 +85,21 @@ func (k msgServer) Attest(goCtx context.Context, msg *types.MsgAttest) (*types.M
 		}
 	}
 
+	// Data must decode cleanly against the schema's field layout
+	schema, err := k.Keeper.GetSchema(ctx, msg.SchemaUID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSchemaData(schema, msg.Data); err != nil {
+		return nil, err
+	}
+
 	var expirationTime time.Time
 	if msg.ExpirationTime > 0 {
 		expirationTime = time.Unix(msg.ExpirationTime, 0)
 	}
 
-	uid, err := k.Keeper.CreateAttestation(
+	uid, err := k.Keeper.CreateAttestationWithTags(
 		ctx,
 		attester,
 		msg.SchemaUID,
@@ -94,6 +108,7 @@ func (k msgServer) Attest(goCtx context.Context, msg *types.MsgAttest) (*types.M
 		msg.Revocable,
 		msg.RefUID,
 		msg.Data,
+		msg.Tags,
 	)
 	if err != nil {
 		return nil, err
@@ -144,6 +159,135 @@ func (k msgServer) Revoke(goCtx context.Context, msg *types.MsgRevoke) (*types.M
 	return &types.MsgRevokeResponse{}, nil
 }
 
+// BatchRevoke handles MsgBatchRevoke for revoking several attestations atomically
+func (k msgServer) BatchRevoke(goCtx context.Context, msg *types.MsgBatchRevoke) (*types.MsgBatchRevokeResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	revoker, err := sdk.AccAddressFromBech32(msg.Revoker)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := k.Keeper.BatchRevokeAttestations(ctx, revoker, msg.UIDs); err != nil {
+		return nil, err
+	}
+
+	// Emit per-attestation events so existing indexers see each revocation
+	for _, uid := range msg.UIDs {
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeAttestationRevoked,
+				sdk.NewAttribute(types.AttributeKeyAttestationUID, uid),
+				sdk.NewAttribute(types.AttributeKeyRevoker, msg.Revoker),
+			),
+		)
+	}
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestationsBatchRevoked,
+			sdk.NewAttribute(types.AttributeKeyRevoker, msg.Revoker),
+			sdk.NewAttribute(types.AttributeKeyRevokedCount, strconv.Itoa(len(msg.UIDs))),
+			sdk.NewAttribute(types.AttributeKeyAttestationUIDs, strings.Join(msg.UIDs, ",")),
+		),
+	)
+
+	return &types.MsgBatchRevokeResponse{
+		Revoked: uint32(len(msg.UIDs)),
+	}, nil
+}
+
+// BatchAttest handles MsgBatchAttest for creating several attestations atomically
+func (k msgServer) BatchAttest(goCtx context.Context, msg *types.MsgBatchAttest) (*types.MsgBatchAttestResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	attester, err := sdk.AccAddressFromBech32(msg.Attester)
+	if err != nil {
+		return nil, err
+	}
+
+	uids, err := k.Keeper.BatchCreateAttestations(ctx, attester, msg.SchemaUID, msg.Revocable, msg.Tags, msg.Entries)
+	if err != nil {
+		return nil, err
+	}
+
+	// Emit per-attestation events so existing indexers see each attestation
+	for _, uid := range uids {
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeAttestationCreated,
+				sdk.NewAttribute(types.AttributeKeyAttestationUID, uid),
+				sdk.NewAttribute(types.AttributeKeyAttester, msg.Attester),
+				sdk.NewAttribute(types.AttributeKeySchemaUID, msg.SchemaUID),
+				sdk.NewAttribute(types.AttributeKeyAttestationType, types.AttestationTypePublic),
+			),
+		)
+	}
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestationsBatchCreated,
+			sdk.NewAttribute(types.AttributeKeyAttester, msg.Attester),
+			sdk.NewAttribute(types.AttributeKeySchemaUID, msg.SchemaUID),
+			sdk.NewAttribute(types.AttributeKeyCreatedCount, strconv.Itoa(len(uids))),
+			sdk.NewAttribute(types.AttributeKeyAttestationUIDs, strings.Join(uids, ",")),
+		),
+	)
+
+	return &types.MsgBatchAttestResponse{
+		Uids: uids,
+	}, nil
+}
+
+// ReassignAttestation handles MsgReassignAttestation for recipient key rotation
+func (k msgServer) ReassignAttestation(goCtx context.Context, msg *types.MsgReassignAttestation) (*types.MsgReassignAttestationResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	issuer, err := sdk.AccAddressFromBech32(msg.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	newRecipient, err := sdk.AccAddressFromBech32(msg.NewRecipient)
+	if err != nil {
+		return nil, err
+	}
+
+	uid, err := k.Keeper.ReassignAttestation(ctx, issuer, msg.UID, newRecipient)
+	if err != nil {
+		return nil, err
+	}
+
+	// Emit events
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestationRevoked,
+			sdk.NewAttribute(types.AttributeKeyAttestationUID, msg.UID),
+			sdk.NewAttribute(types.AttributeKeyRevoker, msg.Issuer),
+		),
+	)
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestationReassigned,
+			sdk.NewAttribute(types.AttributeKeyAttestationUID, uid),
+			sdk.NewAttribute(types.AttributeKeyPreviousUID, msg.UID),
+			sdk.NewAttribute(types.AttributeKeyAttester, msg.Issuer),
+			sdk.NewAttribute(types.AttributeKeyRecipient, msg.NewRecipient),
+		),
+	)
+
+	return &types.MsgReassignAttestationResponse{
+		Uid: uid,
+	}, nil
+}
+
 // CreateEncryptedAttestation handles MsgCreateEncryptedAttestation
 // This is the core privacy feature per Whitepaper Section 3
 func (k msgServer) CreateEncryptedAttestation(goCtx context.Context, msg *types.MsgCreateEncryptedAttestation) (*types.MsgCreateEncryptedAttestationResponse, error) {
@@ -167,6 +311,10 @@ func (k msgServer) CreateEncryptedAttestation(goCtx context.Context, msg *types.
 		recipients[i] = recipient
 	}
 
+	if err := k.Keeper.checkEncryptedFileSize(ctx, msg.FileSize); err != nil {
+		return nil, err
+	}
+
 	var expirationTime time.Time
 	if msg.ExpirationTime > 0 {
 		expirationTime = time.Unix(msg.ExpirationTime, 0)
@@ -215,3 +363,167 @@ func intToString(n int) string {
 	return string(rune(n + '0'))
 }
 
+// UpdateSchemaAllowlist handles MsgUpdateSchemaAllowlist (governance only)
+func (k msgServer) UpdateSchemaAllowlist(goCtx context.Context, msg *types.MsgUpdateSchemaAllowlist) (*types.MsgUpdateSchemaAllowlistResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	params, err := k.Keeper.UpdateSchemaAllowlist(ctx, msg.Authority, msg.Restricted, msg.AddCreators, msg.RemoveCreators)
+	if err != nil {
+		return nil, err
+	}
+
+	// Emit event
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeSchemaAllowlistUpdated,
+			sdk.NewAttribute(types.AttributeKeyRestricted, strconv.FormatBool(params.RestrictSchemas)),
+			sdk.NewAttribute(types.AttributeKeyCreatorsCount, strconv.Itoa(len(params.AllowedSchemaCreators))),
+		),
+	)
+
+	return &types.MsgUpdateSchemaAllowlistResponse{}, nil
+}
+
+// EndorseAttestation handles MsgEndorseAttestation
+func (k msgServer) EndorseAttestation(goCtx context.Context, msg *types.MsgEndorseAttestation) (*types.MsgEndorseAttestationResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	endorser, err := sdk.AccAddressFromBech32(msg.Endorser)
+	if err != nil {
+		return nil, err
+	}
+
+	uid, err := k.Keeper.EndorseAttestation(ctx, endorser, msg.UID, msg.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	// Emit event
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestationEndorsed,
+			sdk.NewAttribute(types.AttributeKeyAttestationUID, uid),
+			sdk.NewAttribute(types.AttributeKeyEndorsedUID, msg.UID),
+			sdk.NewAttribute(types.AttributeKeyEndorser, msg.Endorser),
+			sdk.NewAttribute(types.AttributeKeyAttestationType, types.AttestationTypeEndorsement),
+		),
+	)
+
+	return &types.MsgEndorseAttestationResponse{
+		Uid: uid,
+	}, nil
+}
+
+// WithdrawFees handles MsgWithdrawFees (governance only)
+func (k msgServer) WithdrawFees(goCtx context.Context, msg *types.MsgWithdrawFees) (*types.MsgWithdrawFeesResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	var recipient sdk.AccAddress
+	if msg.Recipient != "" {
+		var err error
+		if recipient, err = sdk.AccAddressFromBech32(msg.Recipient); err != nil {
+			return nil, err
+		}
+	}
+
+	amount, err := k.Keeper.WithdrawFees(ctx, msg.Authority, msg.Amount, recipient, msg.Burn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Emit event
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeFeesWithdrawn,
+			sdk.NewAttribute(types.AttributeKeyAmount, amount.String()),
+			sdk.NewAttribute(types.AttributeKeyRecipient, msg.Recipient),
+			sdk.NewAttribute(types.AttributeKeyBurned, strconv.FormatBool(msg.Burn)),
+		),
+	)
+
+	return &types.MsgWithdrawFeesResponse{Amount: amount}, nil
+}
+
+// Reaffirm handles MsgReaffirm
+func (k msgServer) Reaffirm(goCtx context.Context, msg *types.MsgReaffirm) (*types.MsgReaffirmResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	attester, err := sdk.AccAddressFromBech32(msg.Attester)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := k.Keeper.ReaffirmAttestation(ctx, attester, msg.UID); err != nil {
+		return nil, err
+	}
+
+	// Emit event
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestationReaffirmed,
+			sdk.NewAttribute(types.AttributeKeyAttestationUID, msg.UID),
+			sdk.NewAttribute(types.AttributeKeyAttester, msg.Attester),
+			sdk.NewAttribute(types.AttributeKeyReaffirmedTime, strconv.FormatInt(ctx.BlockTime().Unix(), 10)),
+		),
+	)
+
+	return &types.MsgReaffirmResponse{}, nil
+}
+
+// UpdateRetentionPolicy handles MsgUpdateRetentionPolicy (governance only)
+func (k msgServer) UpdateRetentionPolicy(goCtx context.Context, msg *types.MsgUpdateRetentionPolicy) (*types.MsgUpdateRetentionPolicyResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	params, err := k.Keeper.UpdateRetentionPolicy(ctx, msg.Authority, msg.RetentionPeriod, msg.MaxPrunesPerBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	// Emit event
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeRetentionPolicyUpdated,
+			sdk.NewAttribute(types.AttributeKeyRetentionPeriod, strconv.FormatUint(params.RetentionPeriod, 10)),
+			sdk.NewAttribute(types.AttributeKeyMaxPrunes, strconv.FormatUint(uint64(params.MaxPrunesPerBlock), 10)),
+		),
+	)
+
+	return &types.MsgUpdateRetentionPolicyResponse{}, nil
+}
+
+// UpdateParams handles MsgUpdateParams (governance only)
+func (k msgServer) UpdateParams(goCtx context.Context, msg *types.MsgUpdateParams) (*types.MsgUpdateParamsResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := k.Keeper.UpdateParams(ctx, msg.Authority, msg.Params); err != nil {
+		return nil, err
+	}
+
+	// Emit event
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeParamsUpdated,
+			sdk.NewAttribute(types.AttributeKeyAuthority, msg.Authority),
+		),
+	)
+
+	return &types.MsgUpdateParamsResponse{}, nil
+}
@@ -2,7 +2,9 @@ package keeper
 
 import (
 	"encoding/json"
+	"fmt"
 
+	errorsmod "cosmossdk.io/errors"
 	storetypes "cosmossdk.io/store/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
@@ -28,17 +30,29 @@ func (k Keeper) GetParams(ctx sdk.Context) types.Params {
 	return params
 }
 
+// UpdateParams replaces the module parameters. Only the module authority may call it.
+func (k Keeper) UpdateParams(ctx sdk.Context, authority string, params types.Params) error {
+	if authority != k.authority {
+		return errorsmod.Wrapf(types.ErrUnauthorized, "expected %s, got %s", k.authority, authority)
+	}
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	k.SetParams(ctx, params)
+
+	k.Logger(ctx).Info("Params updated", "authority", authority)
+
+	return nil
+}
+
 // GetSchemaCount returns the total number of schemas
 func (k Keeper) GetSchemaCount(ctx sdk.Context) uint64 {
 	store := ctx.KVStore(k.storeKey)
-	iterator := storetypes.KVStorePrefixIterator(store, types.SchemaKeyPrefix)
-	defer iterator.Close()
-
-	var count uint64
-	for ; iterator.Valid(); iterator.Next() {
-		count++
+	bz := store.Get(types.SchemaCountKey)
+	if bz == nil {
+		return 0
 	}
-	return count
+	return types.BytesToUint64(bz)
 }
 
 // GetAllSchemas returns all registered schemas
@@ -65,9 +79,8 @@ func (k Keeper) GetAllAttestations(ctx sdk.Context) []types.Attestation {
 
 	var attestations []types.Attestation
 	for ; iterator.Valid(); iterator.Next() {
-		var attestation types.Attestation
-		if err := json.Unmarshal(iterator.Value(), &attestation); err == nil {
-			attestations = append(attestations, attestation)
+		if attestation, err := unmarshalAttestation(iterator.Value()); err == nil {
+			attestations = append(attestations, *attestation)
 		}
 	}
 	return attestations
@@ -89,12 +102,40 @@ func (k Keeper) GetAllEncryptedAttestations(ctx sdk.Context) []types.EncryptedAt
 	return attestations
 }
 
+// ImportSchema stores a schema during genesis exactly as it was exported. It is
+// not re-registered, so its UID and fields, such as MaxStaleness, survive even
+// where the current registration rules would reject or rewrite them.
+func (k Keeper) ImportSchema(ctx sdk.Context, schema types.Schema) error {
+	if schema.UID == "" {
+		return errorsmod.Wrap(types.ErrInvalidSchemaFormat, "schema has no UID")
+	}
+	store := ctx.KVStore(k.storeKey)
+	if store.Has(types.GetSchemaKey(schema.UID)) {
+		return errorsmod.Wrap(types.ErrDuplicateSchema, schema.UID)
+	}
+	bz, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	store.Set(types.GetSchemaKey(schema.UID), bz)
+	k.incrementSchemaCount(ctx)
+	return nil
+}
+
 // ImportAttestation imports an attestation during genesis
 func (k Keeper) ImportAttestation(ctx sdk.Context, attestation types.Attestation) {
 	store := ctx.KVStore(k.storeKey)
-	bz, _ := json.Marshal(attestation)
+	bz, _ := marshalAttestation(attestation)
 	store.Set(types.GetAttestationKey(attestation.UID), bz)
+	k.indexDocumentHash(ctx, attestation, "")
+	k.indexTags(ctx, attestation)
+	k.indexExpiration(ctx, attestation)
+	k.indexImportedEndorsement(ctx, attestation)
+	k.queueImportedForPruning(ctx, attestation)
 	k.incrementAttestationCount(ctx)
+	if !attestation.RevocationTime.IsZero() {
+		k.recordRevocation(ctx, attestation.UID, attestation.Attester, attestation.RevocationTime)
+	}
 }
 
 // ImportEncryptedAttestation imports an encrypted attestation during genesis
@@ -103,6 +144,12 @@ func (k Keeper) ImportEncryptedAttestation(ctx sdk.Context, attestation types.En
 	bz, _ := json.Marshal(attestation)
 	store.Set(types.GetEncryptedAttestationKey(attestation.UID), bz)
 	store.Set(types.GetAttestationKey(attestation.UID), bz)
+	k.indexDocumentHash(ctx, attestation.Attestation, attestation.EncryptedDataHash)
+	k.indexExpiration(ctx, attestation.Attestation)
+	k.queueImportedForPruning(ctx, attestation.Attestation)
 	k.incrementAttestationCount(ctx)
 	k.incrementEncryptedAttestationCount(ctx)
+	if !attestation.RevocationTime.IsZero() {
+		k.recordRevocation(ctx, attestation.UID, attestation.Attester, attestation.RevocationTime)
+	}
 }
@@ -0,0 +1,119 @@
+package keeper_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestUpdateParams(t *testing.T) {
+	authority := sdk.AccAddress("gov_authority_______").String()
+	issuer := sdk.AccAddress("issuer______________")
+
+	setup := func(t *testing.T) (keeper.Keeper, sdk.Context, types.MsgServer, string) {
+		storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+		ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test"))
+		k := keeper.NewKeeper(nil, storeKey, nil, authority)
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 documentHash", nil, true)
+		require.NoError(t, err)
+		return k, ctx, keeper.NewMsgServerImpl(k), schemaUID
+	}
+	// encryptedMsg returns a MsgCreateEncryptedAttestation to n distinct recipients
+	encryptedMsg := func(schemaUID string, n int, fileSize uint64) *types.MsgCreateEncryptedAttestation {
+		recipients := make([]string, n)
+		keys := make(map[string]string, n)
+		for i := range recipients {
+			recipients[i] = sdk.AccAddress(fmt.Sprintf("recipient_%010d", i)).String()
+			keys[recipients[i]] = "key"
+		}
+		msg := types.NewMsgCreateEncryptedAttestation(issuer.String(), schemaUID, "Qm"+strings.Repeat("a", 44),
+			strings.Repeat("ab", 32), recipients, keys, true, 0)
+		msg.FileSize = fileSize
+		return msg
+	}
+
+	t.Run("only the authority updates params", func(t *testing.T) {
+		k, ctx, msgServer, _ := setup(t)
+		params := types.DefaultParams()
+		params.MaxRecipientsPerAttestation = 5
+
+		_, err := msgServer.UpdateParams(ctx, types.NewMsgUpdateParams(issuer.String(), params))
+		require.ErrorIs(t, err, types.ErrUnauthorized)
+		require.Equal(t, types.DefaultParams(), k.GetParams(ctx))
+
+		_, err = msgServer.UpdateParams(ctx, types.NewMsgUpdateParams(authority, params))
+		require.NoError(t, err)
+		require.Equal(t, params, k.GetParams(ctx))
+	})
+
+	t.Run("rejects invalid params", func(t *testing.T) {
+		k, ctx, msgServer, _ := setup(t)
+		for name, mutate := range map[string]func(*types.Params){
+			"no recipients":          func(p *types.Params) { p.MaxRecipientsPerAttestation = 0 },
+			"recipients over limit":  func(p *types.Params) { p.MaxRecipientsPerAttestation = types.MaxRecipientsLimit + 1 },
+			"no encrypted file size": func(p *types.Params) { p.MaxEncryptedFileSize = 0 },
+			"creator share over 100%": func(p *types.Params) {
+				p.MaxSchemaCreatorShareBps = types.BasisPointsDenominator + 1
+			},
+		} {
+			params := types.DefaultParams()
+			mutate(&params)
+			require.ErrorIs(t, types.NewMsgUpdateParams(authority, params).ValidateBasic(), types.ErrInvalidParams, name)
+			_, err := msgServer.UpdateParams(ctx, types.NewMsgUpdateParams(authority, params))
+			require.ErrorIs(t, err, types.ErrInvalidParams, name)
+		}
+		require.Equal(t, types.DefaultParams(), k.GetParams(ctx))
+	})
+
+	t.Run("lowering the recipient cap rejects over-cap attestations", func(t *testing.T) {
+		_, ctx, msgServer, schemaUID := setup(t)
+		_, err := msgServer.CreateEncryptedAttestation(ctx, encryptedMsg(schemaUID, 3, 0))
+		require.NoError(t, err)
+
+		params := types.DefaultParams()
+		params.MaxRecipientsPerAttestation = 2
+		_, err = msgServer.UpdateParams(ctx, types.NewMsgUpdateParams(authority, params))
+		require.NoError(t, err)
+
+		_, err = msgServer.CreateEncryptedAttestation(ctx, encryptedMsg(schemaUID, 3, 0))
+		require.ErrorIs(t, err, types.ErrTooManyRecipients)
+		_, err = msgServer.CreateEncryptedAttestation(ctx, encryptedMsg(schemaUID, 2, 0))
+		require.NoError(t, err)
+	})
+
+	t.Run("raising the recipient cap above the default allows more", func(t *testing.T) {
+		_, ctx, msgServer, schemaUID := setup(t)
+		over := types.DefaultMaxRecipientsPerAttestation + 1
+		require.NoError(t, encryptedMsg(schemaUID, over, 0).ValidateBasic())
+		_, err := msgServer.CreateEncryptedAttestation(ctx, encryptedMsg(schemaUID, over, 0))
+		require.ErrorIs(t, err, types.ErrTooManyRecipients)
+
+		params := types.DefaultParams()
+		params.MaxRecipientsPerAttestation = uint32(over)
+		_, err = msgServer.UpdateParams(ctx, types.NewMsgUpdateParams(authority, params))
+		require.NoError(t, err)
+		_, err = msgServer.CreateEncryptedAttestation(ctx, encryptedMsg(schemaUID, over, 0))
+		require.NoError(t, err)
+	})
+
+	t.Run("enforces the encrypted file size cap", func(t *testing.T) {
+		_, ctx, msgServer, schemaUID := setup(t)
+		params := types.DefaultParams()
+		params.MaxEncryptedFileSize = 1024
+		_, err := msgServer.UpdateParams(ctx, types.NewMsgUpdateParams(authority, params))
+		require.NoError(t, err)
+
+		_, err = msgServer.CreateEncryptedAttestation(ctx, encryptedMsg(schemaUID, 1, 1025))
+		require.ErrorIs(t, err, types.ErrEncryptedFileTooLarge)
+		_, err = msgServer.CreateEncryptedAttestation(ctx, encryptedMsg(schemaUID, 1, 1024))
+		require.NoError(t, err)
+	})
+}
@@ -2,6 +2,7 @@ package keeper
 
 import (
 	"context"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
@@ -43,10 +44,13 @@ func (k queryServer) Attestation(goCtx context.Context, req *types.QueryAttestat
 	}
 	ctx := sdk.UnwrapSDKContext(goCtx)
 
-	attestation, err := k.Keeper.GetAttestation(ctx, req.Uid)
+	attestation, err := k.Keeper.getAttestationOrTombstone(ctx, req.Uid)
 	if err != nil {
 		return nil, err
 	}
+	if schema, err := k.Keeper.GetSchema(ctx, attestation.SchemaUID); err == nil && !attestation.Pruned {
+		attestation.Stale = schema.IsStale(*attestation, ctx.BlockTime())
+	}
 
 	return &types.QueryAttestationResponse{
 		Attestation: attestation,
@@ -69,6 +73,7 @@ func (k queryServer) AttestationsByAttester(goCtx context.Context, req *types.Qu
 	if err != nil {
 		return nil, err
 	}
+	k.Keeper.setStaleFlags(ctx, attestations)
 
 	return &types.QueryAttestationsByAttesterResponse{
 		Attestations: attestations,
@@ -91,13 +96,16 @@ func (k queryServer) AttestationsByRecipient(goCtx context.Context, req *types.Q
 	if err != nil {
 		return nil, err
 	}
+	k.Keeper.setStaleFlags(ctx, attestations)
 
 	return &types.QueryAttestationsByRecipientResponse{
 		Attestations: attestations,
 	}, nil
 }
 
-// EncryptedAttestation queries an encrypted attestation with access control
+// EncryptedAttestation queries an encrypted attestation with access control.
+// The per-recipient key map is never returned; EncryptedKey carries the
+// requester's own wrapped key, and only when the requester is authorized.
 func (k queryServer) EncryptedAttestation(goCtx context.Context, req *types.QueryEncryptedAttestationRequest) (*types.QueryEncryptedAttestationResponse, error) {
 	if goCtx == nil {
 		return nil, nil
@@ -109,8 +117,10 @@ func (k queryServer) EncryptedAttestation(goCtx context.Context, req *types.Quer
 		return nil, err
 	}
 
+	redacted := *attestation
+	redacted.EncryptedSymmetricKeys = nil
 	response := &types.QueryEncryptedAttestationResponse{
-		Attestation: attestation,
+		Attestation: &redacted,
 	}
 
 	// If requester is provided, check authorization and include encrypted key
@@ -125,6 +135,7 @@ func (k queryServer) EncryptedAttestation(goCtx context.Context, req *types.Quer
 			return nil, err
 		}
 
+		response.Authorized = authorized
 		if authorized {
 			encryptedKey, err := k.Keeper.GetEncryptedKeyForRecipient(ctx, req.Uid, requester)
 			if err == nil {
@@ -147,6 +158,157 @@ func (k queryServer) Stats(goCtx context.Context, req *types.QueryStatsRequest)
 		TotalAttestations:          k.Keeper.GetAttestationCount(ctx),
 		TotalEncryptedAttestations: k.Keeper.GetEncryptedAttestationCount(ctx),
 		TotalSchemas:               k.Keeper.GetSchemaCount(ctx),
+		TotalRevocations:           k.Keeper.GetRevocationCount(ctx),
+	}, nil
+}
+
+
+// AttestationChain returns the reassignment chain an attestation belongs to
+func (k queryServer) AttestationChain(goCtx context.Context, req *types.QueryAttestationChainRequest) (*types.QueryAttestationChainResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	chain, err := k.Keeper.GetAttestationChain(ctx, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+	k.Keeper.setStaleFlags(ctx, chain)
+
+	return &types.QueryAttestationChainResponse{
+		Attestations: chain,
+	}, nil
+}
+
+// Endorsements returns the endorsements of an attestation
+func (k queryServer) Endorsements(goCtx context.Context, req *types.QueryEndorsementsRequest) (*types.QueryEndorsementsResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if _, err := k.Keeper.GetAttestation(ctx, req.Uid); err != nil {
+		return nil, err
+	}
+	endorsements, err := k.Keeper.GetEndorsements(ctx, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+	k.Keeper.setStaleFlags(ctx, endorsements)
+
+	return &types.QueryEndorsementsResponse{
+		Attestations: endorsements,
+	}, nil
+}
+
+// AttestationsByDocumentHash returns the attestations over a document
+func (k queryServer) AttestationsByDocumentHash(goCtx context.Context, req *types.QueryAttestationsByDocumentHashRequest) (*types.QueryAttestationsByDocumentHashResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	attestations, err := k.Keeper.GetAttestationsByDocumentHash(ctx, req.DocumentHash, req.IncludeRevoked)
+	if err != nil {
+		return nil, err
+	}
+	k.Keeper.setStaleFlags(ctx, attestations)
+
+	return &types.QueryAttestationsByDocumentHashResponse{
+		Attestations: attestations,
+	}, nil
+}
+
+// AttestationsByTag returns the attestations carrying every requested tag
+func (k queryServer) AttestationsByTag(goCtx context.Context, req *types.QueryAttestationsByTagRequest) (*types.QueryAttestationsByTagResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	tags := req.Tags
+	if req.Tag != "" {
+		tags = append([]string{req.Tag}, tags...)
+	}
+	attestations, err := k.Keeper.GetAttestationsByTags(ctx, tags, req.IncludeRevoked)
+	if err != nil {
+		return nil, err
+	}
+	k.Keeper.setStaleFlags(ctx, attestations)
+
+	return &types.QueryAttestationsByTagResponse{
+		Attestations: attestations,
+	}, nil
+}
+
+// ExpiringAttestations returns the unrevoked attestations expiring within a time window
+func (k queryServer) ExpiringAttestations(goCtx context.Context, req *types.QueryExpiringAttestationsRequest) (*types.QueryExpiringAttestationsResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = types.DefaultExpiringAttestationsLimit
+	}
+	limit = min(limit, types.MaxExpiringAttestationsLimit)
+	attestations := k.Keeper.GetAttestationsExpiringBetween(ctx, time.Unix(req.From, 0), time.Unix(req.To, 0), limit)
+	k.Keeper.setStaleFlags(ctx, attestations)
+
+	return &types.QueryExpiringAttestationsResponse{
+		Attestations: attestations,
 	}, nil
 }
 
+// Revocations returns the attestations an attester has revoked, oldest first
+func (k queryServer) Revocations(goCtx context.Context, req *types.QueryRevocationsRequest) (*types.QueryRevocationsResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	attester, err := sdk.AccAddressFromBech32(req.Attester)
+	if err != nil {
+		return nil, err
+	}
+
+	revocations, err := k.Keeper.GetRevocationsByAttester(ctx, attester)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryRevocationsResponse{
+		Revocations: revocations,
+	}, nil
+}
+
+// Params returns the module parameters
+func (k queryServer) Params(goCtx context.Context, req *types.QueryParamsRequest) (*types.QueryParamsResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	return &types.QueryParamsResponse{Params: k.Keeper.GetParams(ctx)}, nil
+}
+
+// FeeBalance returns the schema fees held by the module account
+func (k queryServer) FeeBalance(goCtx context.Context, req *types.QueryFeeBalanceRequest) (*types.QueryFeeBalanceResponse, error) {
+	if goCtx == nil {
+		return nil, nil
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	balance, err := k.Keeper.GetFeeBalance(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryFeeBalanceResponse{
+		Address:  types.ModuleAddress.String(),
+		FeeDenom: k.Keeper.feeDenom(ctx),
+		Balance:  balance,
+	}, nil
+}
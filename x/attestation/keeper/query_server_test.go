@@ -0,0 +1,83 @@
+package keeper_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestQueryServer(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	recipient := sdk.AccAddress("recipient___________")
+	outsider := sdk.AccAddress("outsider____________")
+
+	k, ctx := setupTestKeeper(t)
+	queryServer := keeper.NewQueryServerImpl(k)
+
+	schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 degreeHash", nil, true)
+	require.NoError(t, err)
+
+	t.Run("returns a registered schema", func(t *testing.T) {
+		res, err := queryServer.Schema(ctx, &types.QuerySchemaRequest{Uid: schemaUID})
+		require.NoError(t, err)
+		require.Equal(t, schemaUID, res.Schema.UID)
+		require.True(t, res.Schema.Creator.Equals(issuer))
+
+		_, err = queryServer.Schema(ctx, &types.QuerySchemaRequest{Uid: "0xmissing"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("returns a public attestation", func(t *testing.T) {
+		uid, err := k.CreateAttestation(ctx, issuer, schemaUID, recipient, time.Time{}, true, "", []byte("degree"))
+		require.NoError(t, err)
+
+		res, err := queryServer.Attestation(ctx, &types.QueryAttestationRequest{Uid: uid})
+		require.NoError(t, err)
+		require.Equal(t, uid, res.Attestation.UID)
+		require.Equal(t, []byte("degree"), res.Attestation.Data)
+
+		byRecipient, err := queryServer.AttestationsByRecipient(ctx, &types.QueryAttestationsByRecipientRequest{Recipient: recipient.String()})
+		require.NoError(t, err)
+		require.Len(t, byRecipient.Attestations, 1)
+
+		_, err = queryServer.Attestation(ctx, &types.QueryAttestationRequest{Uid: "0xmissing"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("shares encrypted keys only with authorized requesters", func(t *testing.T) {
+		cid := "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"
+		keys := map[string]string{recipient.String(): "wrapped-for-recipient"}
+		uid, err := k.CreateEncryptedAttestation(ctx, issuer, schemaUID, cid, strings.Repeat("ab", 32), []sdk.AccAddress{recipient}, keys, true, time.Time{})
+		require.NoError(t, err)
+
+		res, err := queryServer.EncryptedAttestation(ctx, &types.QueryEncryptedAttestationRequest{Uid: uid, Requester: outsider.String()})
+		require.NoError(t, err)
+		require.False(t, res.Authorized)
+		require.Empty(t, res.EncryptedKey)
+		require.Empty(t, res.Attestation.EncryptedSymmetricKeys, "key map must not leak to unauthorized requesters")
+		require.Equal(t, cid, res.Attestation.IPFSCID)
+
+		res, err = queryServer.EncryptedAttestation(ctx, &types.QueryEncryptedAttestationRequest{Uid: uid, Requester: recipient.String()})
+		require.NoError(t, err)
+		require.True(t, res.Authorized)
+		require.Equal(t, "wrapped-for-recipient", res.EncryptedKey)
+
+		_, err = queryServer.EncryptedAttestation(ctx, &types.QueryEncryptedAttestationRequest{Uid: "0xmissing", Requester: recipient.String()})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("counts stored objects", func(t *testing.T) {
+		res, err := queryServer.Stats(ctx, &types.QueryStatsRequest{})
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), res.TotalSchemas)
+		require.Equal(t, uint64(1), res.TotalEncryptedAttestations)
+	})
+}
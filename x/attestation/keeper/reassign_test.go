@@ -0,0 +1,163 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation"
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestReassignAttestation(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	lostKey := sdk.AccAddress("lost_key____________")
+	newKey := sdk.AccAddress("new_key_____________")
+
+	t.Run("creates linked attestation and revokes the old one", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 degreeHash", nil, true)
+		require.NoError(t, err)
+		oldUID, err := k.CreateAttestation(ctx, issuer, schemaUID, lostKey, ctx.BlockTime().AddDate(1, 0, 0), true, "", []byte("degree"))
+		require.NoError(t, err)
+
+		newUID, err := k.ReassignAttestation(ctx, issuer, oldUID, newKey)
+		require.NoError(t, err)
+		require.NotEqual(t, oldUID, newUID)
+
+		old, err := k.GetAttestation(ctx, oldUID)
+		require.NoError(t, err)
+		require.False(t, old.RevocationTime.IsZero(), "old attestation must be revoked")
+
+		replacement, err := k.GetAttestation(ctx, newUID)
+		require.NoError(t, err)
+		require.Equal(t, oldUID, replacement.RefUID)
+		require.True(t, replacement.Recipient.Equals(newKey))
+		require.Equal(t, old.SchemaUID, replacement.SchemaUID)
+		require.Equal(t, old.Data, replacement.Data)
+		require.Equal(t, old.ExpirationTime, replacement.ExpirationTime)
+		require.True(t, replacement.RevocationTime.IsZero())
+
+		byRecipient, err := k.GetAttestationsByRecipient(ctx, newKey)
+		require.NoError(t, err)
+		require.Len(t, byRecipient, 1)
+	})
+
+	t.Run("requires issuer authorization", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		uids := createTestAttestations(t, k, ctx, issuer, 1, true)
+
+		_, err := k.ReassignAttestation(ctx, lostKey, uids[0], newKey)
+		require.ErrorIs(t, err, types.ErrUnauthorized)
+
+		revoked, err := k.IsAttestationRevoked(ctx, uids[0])
+		require.NoError(t, err)
+		require.False(t, revoked)
+	})
+
+	t.Run("rejects revoked and non-revocable attestations", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		revocable := createTestAttestations(t, k, ctx, issuer, 1, true)
+		fixed := createTestAttestations(t, k, ctx, issuer, 1, false)
+
+		_, err := k.ReassignAttestation(ctx, issuer, fixed[0], newKey)
+		require.ErrorIs(t, err, types.ErrAttestationNotRevocable)
+
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, revocable[0]))
+		_, err = k.ReassignAttestation(ctx, issuer, revocable[0], newKey)
+		require.ErrorIs(t, err, types.ErrAttestationAlreadyRevoked)
+	})
+
+	t.Run("chain query traverses every reassignment", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		uids := createTestAttestations(t, k, ctx, issuer, 1, true)
+		second, err := k.ReassignAttestation(ctx, issuer, uids[0], lostKey)
+		require.NoError(t, err)
+		third, err := k.ReassignAttestation(ctx, issuer, second, newKey)
+		require.NoError(t, err)
+
+		want := []string{uids[0], second, third}
+		for _, start := range want {
+			chain, err := k.GetAttestationChain(ctx, start)
+			require.NoError(t, err)
+			got := make([]string, len(chain))
+			for i, a := range chain {
+				got[i] = a.UID
+			}
+			require.Equal(t, want, got, "chain from %s", start)
+		}
+
+		// RefUID on its own does not make a reassignment link
+		refSchema := types.GenerateSchemaUID("bytes32 programHash", nil, true)
+		referencing, err := k.CreateAttestation(ctx, issuer, refSchema, nil, time.Time{}, true, third, []byte("unrelated"))
+		require.NoError(t, err)
+		chain, err := k.GetAttestationChain(ctx, referencing)
+		require.NoError(t, err)
+		require.Len(t, chain, 1)
+	})
+
+	t.Run("links and endorsements survive genesis export and import", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		accreditor := sdk.AccAddress("accreditor__________")
+		uids := createTestAttestations(t, k, ctx, issuer, 1, true)
+		second, err := k.ReassignAttestation(ctx, issuer, uids[0], newKey)
+		require.NoError(t, err)
+
+		// The accreditor withdraws and re-endorses within the block; the index
+		// must keep pointing at the live endorsement
+		withdrawn, err := k.EndorseAttestation(ctx, accreditor, second, []byte("first"))
+		require.NoError(t, err)
+		require.NoError(t, k.RevokeAttestation(ctx, accreditor, withdrawn))
+		endorsement, err := k.EndorseAttestation(ctx, accreditor, second, []byte("second"))
+		require.NoError(t, err)
+
+		exported := attestation.ExportGenesis(ctx, k)
+		require.NoError(t, exported.Validate())
+		imported, importCtx := setupTestKeeper(t)
+		attestation.InitGenesis(importCtx, imported, *exported)
+
+		for _, start := range []string{uids[0], second} {
+			chain, err := imported.GetAttestationChain(importCtx, start)
+			require.NoError(t, err)
+			require.Len(t, chain, 2, "chain from %s", start)
+			require.Equal(t, uids[0], chain[0].UID)
+			require.Equal(t, second, chain[1].UID)
+		}
+
+		endorsements, err := imported.GetEndorsements(importCtx, second)
+		require.NoError(t, err)
+		require.Len(t, endorsements, 1)
+		require.Equal(t, endorsement, endorsements[0].UID)
+
+		msg, broken := keeper.AttestationIndexInvariant(imported)(importCtx)
+		require.False(t, broken, msg)
+	})
+}
+
+func TestMsgServerReassignAttestation(t *testing.T) {
+	k, ctx := setupTestKeeper(t)
+	issuer := sdk.AccAddress("issuer______________")
+	newKey := sdk.AccAddress("new_key_____________")
+	uids := createTestAttestations(t, k, ctx, issuer, 1, true)
+
+	msgServer := keeper.NewMsgServerImpl(k)
+	res, err := msgServer.ReassignAttestation(ctx, types.NewMsgReassignAttestation(issuer.String(), uids[0], newKey.String()))
+	require.NoError(t, err)
+
+	queryServer := keeper.NewQueryServerImpl(k)
+	chainRes, err := queryServer.AttestationChain(ctx, &types.QueryAttestationChainRequest{Uid: uids[0]})
+	require.NoError(t, err)
+	require.Len(t, chainRes.Attestations, 2)
+	require.Equal(t, res.Uid, chainRes.Attestations[1].UID)
+
+	var found bool
+	for _, ev := range ctx.EventManager().Events() {
+		if ev.Type == types.EventTypeAttestationReassigned {
+			found = true
+		}
+	}
+	require.True(t, found, "reassignment event not emitted")
+}
@@ -0,0 +1,220 @@
+package keeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// Retention policy
+//
+// With Params.RetentionPeriod set, EndBlock prunes attestations revoked or
+// expired more than RetentionPeriod ago, at most MaxPrunesPerBlock per block.
+// A pruned attestation's record and its attester, recipient, schema,
+// expiration and IPFS CID index entries are deleted and a tombstone is left
+// in their place. The document hash and tag index entries are kept, so
+// lookups by document hash or tag (including revoked) still find the
+// attestation, resolved from its tombstone.
+//
+// Attestations are queued for pruning when they are revoked or expire, so
+// EndBlock only walks the prune queue, never the whole store.
+
+// UpdateRetentionPolicy sets the retention period (seconds, 0 disables pruning)
+// and per-block prune cap (0 restores the default). Only the module authority may call it.
+func (k Keeper) UpdateRetentionPolicy(ctx sdk.Context, authority string, retentionPeriod uint64, maxPrunesPerBlock uint32) (types.Params, error) {
+	if authority != k.authority {
+		return types.Params{}, errorsmod.Wrapf(types.ErrUnauthorized, "expected %s, got %s", k.authority, authority)
+	}
+	if retentionPeriod > types.MaxRetentionPeriod {
+		return types.Params{}, errorsmod.Wrapf(types.ErrInvalidParams, "retention period %ds exceeds %ds", retentionPeriod, uint64(types.MaxRetentionPeriod))
+	}
+	if maxPrunesPerBlock == 0 {
+		maxPrunesPerBlock = types.DefaultMaxPrunesPerBlock
+	}
+
+	params := k.GetParams(ctx)
+	params.RetentionPeriod = retentionPeriod
+	params.MaxPrunesPerBlock = maxPrunesPerBlock
+	k.SetParams(ctx, params)
+
+	k.Logger(ctx).Info("Retention policy updated", "retention_period", retentionPeriod, "max_prunes_per_block", maxPrunesPerBlock)
+
+	return params, nil
+}
+
+// queueForPruning records that uid was revoked or expired at since
+func (k Keeper) queueForPruning(ctx sdk.Context, since time.Time, uid string) {
+	ctx.KVStore(k.storeKey).Set(types.GetPruneQueueKey(since.Unix(), uid), []byte{1})
+}
+
+// queueImportedForPruning queues a genesis attestation that is already revoked or expired
+func (k Keeper) queueImportedForPruning(ctx sdk.Context, attestation types.Attestation) {
+	switch {
+	case !attestation.RevocationTime.IsZero():
+		k.queueForPruning(ctx, attestation.RevocationTime, attestation.UID)
+	case attestation.Expired:
+		k.queueForPruning(ctx, attestation.ExpirationTime, attestation.UID)
+	}
+}
+
+// pruneAttestations prunes queued attestations revoked or expired more than
+// RetentionPeriod before the block time, up to MaxPrunesPerBlock of them
+func (k Keeper) pruneAttestations(ctx sdk.Context) error {
+	params := k.GetParams(ctx)
+	if params.RetentionPeriod == 0 {
+		return nil
+	}
+	limit := int(params.MaxPrunesPerBlock)
+	if limit == 0 {
+		limit = types.DefaultMaxPrunesPerBlock
+	}
+	cutoff := ctx.BlockTime().Unix() - int64(params.RetentionPeriod)
+	if cutoff <= 0 {
+		return nil
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	prefixLen := len(types.PruneQueuePrefix) + 8
+	var keys [][]byte
+	iterator := store.Iterator(types.GetPruneQueueIteratorPrefix(0), types.GetPruneQueueIteratorPrefix(cutoff))
+	for ; iterator.Valid() && len(keys) < limit; iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		if err := k.pruneAttestation(ctx, string(key[prefixLen:])); err != nil {
+			return err
+		}
+		store.Delete(key)
+	}
+	return nil
+}
+
+// pruneAttestation replaces a revoked or expired attestation with its
+// tombstone. Attestations that are missing or neither revoked nor expired
+// are left alone.
+func (k Keeper) pruneAttestation(ctx sdk.Context, uid string) error {
+	store := ctx.KVStore(k.storeKey)
+	attestation, err := k.GetAttestation(ctx, uid)
+	if err != nil || (attestation.RevocationTime.IsZero() && !attestation.Expired) {
+		return nil
+	}
+
+	var encryptedDataHash string
+	if store.Has(types.GetEncryptedAttestationKey(uid)) {
+		encrypted, err := k.GetEncryptedAttestation(ctx, uid)
+		if err != nil {
+			return err
+		}
+		encryptedDataHash = encrypted.EncryptedDataHash
+		store.Delete(types.GetEncryptedAttestationKey(uid))
+		store.Delete(types.GetIPFSCIDIndexKey(encrypted.IPFSCID))
+		for _, recipient := range encrypted.Recipients {
+			store.Delete(types.GetAttestationByRecipientKey(recipient, uid))
+		}
+	}
+
+	docHash, _ := attestationDocumentHash(*attestation, encryptedDataHash)
+	tombstone := types.NewAttestationTombstone(*attestation, encryptedDataHash, docHash, ctx.BlockTime())
+	bz, err := json.Marshal(tombstone)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tombstone: %w", err)
+	}
+	store.Set(types.GetAttestationTombstoneKey(uid), bz)
+
+	store.Delete(types.GetAttestationKey(uid))
+	store.Delete(types.GetAttestationByAttesterKey(attestation.Attester, uid))
+	if len(attestation.Recipient) > 0 {
+		store.Delete(types.GetAttestationByRecipientKey(attestation.Recipient, uid))
+	}
+	store.Delete(types.GetAttestationBySchemaKey(attestation.SchemaUID, uid))
+	if !attestation.ExpirationTime.IsZero() {
+		store.Delete(types.GetAttestationByExpirationKey(attestation.ExpirationTime.Unix(), uid))
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestationPruned,
+			sdk.NewAttribute(types.AttributeKeyAttestationUID, uid),
+			sdk.NewAttribute(types.AttributeKeySchemaUID, attestation.SchemaUID),
+			sdk.NewAttribute(types.AttributeKeyRevoked, strconv.FormatBool(!attestation.RevocationTime.IsZero())),
+		),
+	)
+	return nil
+}
+
+// GetTombstone returns the tombstone of a pruned attestation
+func (k Keeper) GetTombstone(ctx sdk.Context, uid string) (*types.AttestationTombstone, error) {
+	bz := ctx.KVStore(k.storeKey).Get(types.GetAttestationTombstoneKey(uid))
+	if bz == nil {
+		return nil, errorsmod.Wrap(types.ErrAttestationNotFound, uid)
+	}
+	var tombstone types.AttestationTombstone
+	if err := json.Unmarshal(bz, &tombstone); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tombstone: %w", err)
+	}
+	return &tombstone, nil
+}
+
+// GetAllTombstones returns every pruned attestation's tombstone
+func (k Keeper) GetAllTombstones(ctx sdk.Context) []types.AttestationTombstone {
+	iterator := storetypes.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.AttestationTombstonePrefix)
+	defer iterator.Close()
+
+	var tombstones []types.AttestationTombstone
+	for ; iterator.Valid(); iterator.Next() {
+		var tombstone types.AttestationTombstone
+		if err := json.Unmarshal(iterator.Value(), &tombstone); err == nil {
+			tombstones = append(tombstones, tombstone)
+		}
+	}
+	return tombstones
+}
+
+// ImportTombstone imports a pruned attestation's tombstone during genesis,
+// restoring its document hash and tag index entries. Tombstones count towards
+// the attestation counters, which double as UID nonces.
+func (k Keeper) ImportTombstone(ctx sdk.Context, tombstone types.AttestationTombstone) {
+	store := ctx.KVStore(k.storeKey)
+	bz, _ := json.Marshal(tombstone)
+	store.Set(types.GetAttestationTombstoneKey(tombstone.UID), bz)
+	if docHash, ok := types.NormalizeDocumentHash(tombstone.DocumentHash); ok {
+		value := documentIndexActive
+		if !tombstone.RevocationTime.IsZero() {
+			value = documentIndexRevoked
+		}
+		store.Set(types.GetAttestationByDocumentHashKey(docHash, tombstone.UID), value)
+	}
+	k.indexTags(ctx, tombstone.Attestation())
+	k.incrementAttestationCount(ctx)
+	if tombstone.Encrypted {
+		k.incrementEncryptedAttestationCount(ctx)
+	}
+	if !tombstone.RevocationTime.IsZero() {
+		k.recordRevocation(ctx, tombstone.UID, tombstone.Attester, tombstone.RevocationTime)
+	}
+}
+
+// getAttestationOrTombstone returns an attestation, or the view of it its
+// tombstone keeps when it was pruned. Use it only for reads; never write the
+// result back.
+func (k Keeper) getAttestationOrTombstone(ctx sdk.Context, uid string) (*types.Attestation, error) {
+	attestation, err := k.GetAttestation(ctx, uid)
+	if err == nil {
+		return attestation, nil
+	}
+	tombstone, tombErr := k.GetTombstone(ctx, uid)
+	if tombErr != nil {
+		return nil, err
+	}
+	view := tombstone.Attestation()
+	return &view, nil
+}
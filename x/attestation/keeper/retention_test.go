@@ -0,0 +1,212 @@
+package keeper_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation"
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestRetentionPruning(t *testing.T) {
+	authority := sdk.AccAddress("gov_authority_______").String()
+	issuer := sdk.AccAddress("issuer______________")
+	recipient := sdk.AccAddress("recipient___________")
+	now := time.Unix(1_700_000_000, 0).UTC()
+	day := 24 * time.Hour
+	retention := uint64((30 * day).Seconds())
+	diploma := sha256.Sum256([]byte("diploma.pdf"))
+	diplomaHash := hex.EncodeToString(diploma[:])
+	cid := "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"
+
+	setup := func(t *testing.T) (keeper.Keeper, sdk.Context, string) {
+		storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+		tkey := storetypes.NewTransientStoreKey("transient_test")
+		ctx := testutil.DefaultContext(storeKey, tkey).
+			WithBlockHeight(10).
+			WithBlockTime(now)
+		k := keeper.NewKeeper(nil, storeKey, nil, authority)
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 documentHash", nil, true)
+		require.NoError(t, err)
+		return k, ctx, schemaUID
+	}
+	endBlock := func(t *testing.T, k keeper.Keeper, ctx sdk.Context, at time.Time) []sdk.Event {
+		blockCtx := ctx.WithBlockTime(at).WithEventManager(sdk.NewEventManager())
+		require.NoError(t, k.EndBlock(blockCtx))
+		var pruned []sdk.Event
+		for _, e := range blockCtx.EventManager().Events() {
+			if e.Type == types.EventTypeAttestationPruned {
+				pruned = append(pruned, e)
+			}
+		}
+		return pruned
+	}
+
+	t.Run("prunes revoked and expired attestations to tombstones", func(t *testing.T) {
+		k, ctx, schemaUID := setup(t)
+		_, err := k.UpdateRetentionPolicy(ctx, authority, retention, 0)
+		require.NoError(t, err)
+
+		revoked, err := k.CreateAttestationWithTags(ctx, issuer, schemaUID, recipient, time.Time{}, true, "", diploma[:], []string{"diploma"})
+		require.NoError(t, err)
+		expiring, err := k.CreateAttestation(ctx, issuer, schemaUID, recipient, now.Add(day), true, "", []byte("0x"+diplomaHash))
+		require.NoError(t, err)
+		active, err := k.CreateAttestation(ctx, issuer, schemaUID, recipient, time.Time{}, true, "", []byte("active"))
+		require.NoError(t, err)
+		encrypted, err := k.CreateEncryptedAttestation(ctx, issuer, schemaUID, cid, strings.Repeat("ab", 32),
+			[]sdk.AccAddress{recipient}, map[string]string{recipient.String(): "wrapped"}, true, time.Time{})
+		require.NoError(t, err)
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, revoked))
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, encrypted))
+
+		// Expired on day 1 but still within the retention period
+		require.Empty(t, endBlock(t, k, ctx, now.Add(2*day)))
+		_, err = k.GetAttestation(ctx, revoked)
+		require.NoError(t, err)
+
+		// The revocations pass the retention period first
+		pruned := endBlock(t, k, ctx, now.Add(30*day+time.Second))
+		require.Len(t, pruned, 2)
+		for _, uid := range []string{revoked, encrypted} {
+			_, err := k.GetAttestation(ctx, uid)
+			require.ErrorIs(t, err, types.ErrAttestationNotFound)
+		}
+		require.Len(t, endBlock(t, k, ctx, now.Add(31*day+time.Second)), 1)
+
+		tombstone, err := k.GetTombstone(ctx, revoked)
+		require.NoError(t, err)
+		require.Equal(t, issuer, tombstone.Attester)
+		require.Equal(t, schemaUID, tombstone.SchemaUID)
+		require.Equal(t, now, tombstone.RevocationTime)
+		require.Equal(t, diplomaHash, tombstone.DocumentHash)
+		sum := sha256.Sum256(diploma[:])
+		require.Equal(t, hex.EncodeToString(sum[:]), tombstone.DataHash)
+		encTombstone, err := k.GetTombstone(ctx, encrypted)
+		require.NoError(t, err)
+		require.True(t, encTombstone.Encrypted)
+		require.Equal(t, strings.Repeat("ab", 32), encTombstone.DataHash)
+
+		// Record indexes are gone; the active attestation is untouched
+		byRecipient, err := k.GetAttestationsByRecipient(ctx, recipient)
+		require.NoError(t, err)
+		require.Len(t, byRecipient, 1)
+		require.Equal(t, active, byRecipient[0].UID)
+		_, err = k.GetEncryptedAttestationByIPFSCID(ctx, cid)
+		require.Error(t, err)
+
+		// Verification still reports them revoked or expired
+		isRevoked, err := k.IsAttestationRevoked(ctx, revoked)
+		require.NoError(t, err)
+		require.True(t, isRevoked)
+		isExpired, err := k.IsAttestationExpired(ctx, expiring)
+		require.NoError(t, err)
+		require.True(t, isExpired)
+
+		res, err := keeper.NewQueryServerImpl(k).Attestation(ctx, &types.QueryAttestationRequest{Uid: expiring})
+		require.NoError(t, err)
+		require.True(t, res.Attestation.Pruned)
+		require.True(t, res.Attestation.Expired)
+		require.Equal(t, now.Add(day), res.Attestation.ExpirationTime)
+
+		// The revocation list over the document hash and tag still resolves
+		listed, err := k.GetAttestationsByDocumentHash(ctx, diplomaHash, true)
+		require.NoError(t, err)
+		require.Len(t, listed, 2)
+		for _, a := range listed {
+			require.True(t, a.Pruned)
+		}
+		listed, err = k.GetAttestationsByDocumentHash(ctx, diplomaHash, false)
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		require.Equal(t, expiring, listed[0].UID)
+		tagged, err := k.GetAttestationsByTags(ctx, []string{"diploma"}, true)
+		require.NoError(t, err)
+		require.Len(t, tagged, 1)
+		require.False(t, tagged[0].RevocationTime.IsZero())
+
+		msg, broken := keeper.AllInvariants(k)(ctx)
+		require.False(t, broken, msg)
+	})
+
+	t.Run("caps prunes per block", func(t *testing.T) {
+		k, ctx, _ := setup(t)
+		_, err := k.UpdateRetentionPolicy(ctx, authority, retention, 2)
+		require.NoError(t, err)
+		uids := createTestAttestations(t, k, ctx, issuer, 5, true)
+		require.NoError(t, k.BatchRevokeAttestations(ctx, issuer, uids))
+
+		after := now.Add(31 * day)
+		require.Len(t, endBlock(t, k, ctx, after), 2)
+		require.Len(t, endBlock(t, k, ctx, after), 2)
+		require.Len(t, endBlock(t, k, ctx, after), 1)
+		require.Empty(t, endBlock(t, k, ctx, after))
+		require.Len(t, k.GetAllTombstones(ctx), 5)
+	})
+
+	t.Run("keeps everything while disabled", func(t *testing.T) {
+		k, ctx, _ := setup(t)
+		require.Zero(t, k.GetParams(ctx).RetentionPeriod)
+		uids := createTestAttestations(t, k, ctx, issuer, 1, true)
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, uids[0]))
+
+		require.Empty(t, endBlock(t, k, ctx, now.Add(3650*day)))
+		_, err := k.GetAttestation(ctx, uids[0])
+		require.NoError(t, err)
+
+		// Enabling the policy later prunes what was queued while disabled
+		_, err = k.UpdateRetentionPolicy(ctx, authority, retention, 0)
+		require.NoError(t, err)
+		require.Len(t, endBlock(t, k, ctx, now.Add(3650*day)), 1)
+	})
+
+	t.Run("only the authority sets the policy", func(t *testing.T) {
+		k, ctx, _ := setup(t)
+		msgServer := keeper.NewMsgServerImpl(k)
+		_, err := msgServer.UpdateRetentionPolicy(ctx, types.NewMsgUpdateRetentionPolicy(issuer.String(), retention, 10))
+		require.ErrorIs(t, err, types.ErrUnauthorized)
+
+		_, err = msgServer.UpdateRetentionPolicy(ctx, types.NewMsgUpdateRetentionPolicy(authority, retention, 10))
+		require.NoError(t, err)
+		params := k.GetParams(ctx)
+		require.Equal(t, retention, params.RetentionPeriod)
+		require.Equal(t, uint32(10), params.MaxPrunesPerBlock)
+
+		require.Error(t, types.NewMsgUpdateRetentionPolicy(authority, types.MaxRetentionPeriod+1, 0).ValidateBasic())
+	})
+
+	t.Run("tombstones survive genesis export and import", func(t *testing.T) {
+		k, ctx, schemaUID := setup(t)
+		_, err := k.UpdateRetentionPolicy(ctx, authority, retention, 0)
+		require.NoError(t, err)
+		uid, err := k.CreateAttestation(ctx, issuer, schemaUID, recipient, time.Time{}, true, "", diploma[:])
+		require.NoError(t, err)
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, uid))
+		require.Len(t, endBlock(t, k, ctx, now.Add(31*day)), 1)
+
+		genesis := attestation.ExportGenesis(ctx, k)
+		require.Len(t, genesis.Tombstones, 1)
+
+		storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+		ctx2 := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).WithBlockHeight(10).WithBlockTime(now)
+		k2 := keeper.NewKeeper(nil, storeKey, nil, authority)
+		attestation.InitGenesis(ctx2, k2, *genesis)
+		require.Equal(t, k.GetAttestationCount(ctx), k2.GetAttestationCount(ctx2))
+		listed, err := k2.GetAttestationsByDocumentHash(ctx2, diplomaHash, true)
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		require.Equal(t, uid, listed[0].UID)
+		require.True(t, listed[0].Pruned)
+
+		msg, broken := keeper.AllInvariants(k2)(ctx2)
+		require.False(t, broken, msg)
+	})
+}
@@ -0,0 +1,55 @@
+package keeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// recordRevocation stores the revocation record of uid, indexes it under its
+// attester and counts it
+func (k Keeper) recordRevocation(ctx sdk.Context, uid string, attester sdk.AccAddress, revokedAt time.Time) {
+	store := ctx.KVStore(k.storeKey)
+	bz, _ := json.Marshal(types.Revocation{UID: uid, Attester: attester, RevocationTime: revokedAt})
+	store.Set(types.GetRevocationKey(uid), bz)
+	store.Set(types.GetRevocationByAttesterKey(attester, revokedAt.Unix(), uid), []byte{1})
+	k.addRevocationCount(ctx, 1)
+}
+
+// GetRevocation returns the revocation record of uid
+func (k Keeper) GetRevocation(ctx sdk.Context, uid string) (*types.Revocation, error) {
+	bz := ctx.KVStore(k.storeKey).Get(types.GetRevocationKey(uid))
+	if bz == nil {
+		return nil, errorsmod.Wrapf(types.ErrAttestationNotFound, "no revocation of %s", uid)
+	}
+	var revocation types.Revocation
+	if err := json.Unmarshal(bz, &revocation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal revocation: %w", err)
+	}
+	return &revocation, nil
+}
+
+// GetRevocationsByAttester returns the revocations by attester, oldest first
+func (k Keeper) GetRevocationsByAttester(ctx sdk.Context, attester sdk.AccAddress) ([]types.Revocation, error) {
+	store := ctx.KVStore(k.storeKey)
+	prefixLen := len(types.GetRevocationsByAttesterIteratorPrefix(attester)) + 8
+
+	iterator := storetypes.KVStorePrefixIterator(store, types.GetRevocationsByAttesterIteratorPrefix(attester))
+	defer iterator.Close()
+
+	var revocations []types.Revocation
+	for ; iterator.Valid(); iterator.Next() {
+		revocation, err := k.GetRevocation(ctx, string(iterator.Key()[prefixLen:]))
+		if err != nil {
+			return nil, err
+		}
+		revocations = append(revocations, *revocation)
+	}
+	return revocations, nil
+}
@@ -0,0 +1,78 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation"
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestRevocations(t *testing.T) {
+	attester := sdk.AccAddress("revoking_attester___")
+	other := sdk.AccAddress("other_attester______")
+
+	k, ctx := setupTestKeeper(t)
+	start := ctx.BlockTime()
+	uids := createTestAttestations(t, k, ctx, attester, 3, true)
+	permanent := createTestAttestations(t, k, ctx, attester, 1, false)[0]
+	otherUIDs := createTestAttestations(t, k, ctx, other, 1, true)
+
+	revocations := func(k keeper.Keeper, ctx sdk.Context, attester sdk.AccAddress) []types.Revocation {
+		t.Helper()
+		res, err := keeper.NewQueryServerImpl(k).Revocations(ctx, &types.QueryRevocationsRequest{Attester: attester.String()})
+		require.NoError(t, err)
+		return res.Revocations
+	}
+
+	t.Run("records a revocation", func(t *testing.T) {
+		require.Empty(t, revocations(k, ctx, attester))
+
+		ctx := ctx.WithBlockTime(start.Add(2 * time.Hour))
+		require.NoError(t, k.RevokeAttestation(ctx, attester, uids[1]))
+
+		revocation, err := k.GetRevocation(ctx, uids[1])
+		require.NoError(t, err)
+		require.Equal(t, types.Revocation{UID: uids[1], Attester: attester, RevocationTime: ctx.BlockTime()}, *revocation)
+		_, err = k.GetRevocation(ctx, uids[0])
+		require.ErrorIs(t, err, types.ErrAttestationNotFound)
+	})
+
+	t.Run("rejects revoking twice or a non-revocable attestation", func(t *testing.T) {
+		err := k.RevokeAttestation(ctx, attester, uids[1])
+		require.ErrorIs(t, err, types.ErrAttestationAlreadyRevoked)
+		require.ErrorIs(t, k.RevokeAttestation(ctx, attester, permanent), types.ErrAttestationNotRevocable)
+		require.ErrorIs(t, k.RevokeAttestation(ctx, attester, otherUIDs[0]), types.ErrUnauthorized)
+
+		_, err = keeper.NewMsgServerImpl(k).Revoke(ctx, types.NewMsgRevoke(attester.String(), uids[1]))
+		require.ErrorIs(t, err, types.ErrAttestationAlreadyRevoked)
+		require.Equal(t, uint64(1), k.GetRevocationCount(ctx))
+	})
+
+	t.Run("lists an attester's revocations oldest first", func(t *testing.T) {
+		require.NoError(t, k.BatchRevokeAttestations(ctx.WithBlockTime(start.Add(time.Hour)), attester, []string{uids[2], uids[0]}))
+		require.NoError(t, k.RevokeAttestation(ctx, other, otherUIDs[0]))
+
+		got := revocations(k, ctx, attester)
+		require.Len(t, got, 3)
+		require.Equal(t, uids[1], got[2].UID)
+		require.ElementsMatch(t, []string{uids[0], uids[2]}, []string{got[0].UID, got[1].UID})
+		require.Equal(t, start.Add(time.Hour), got[0].RevocationTime)
+		require.Equal(t, []types.Revocation{{UID: otherUIDs[0], Attester: other, RevocationTime: start}}, revocations(k, ctx, other))
+		require.Equal(t, uint64(4), k.GetRevocationCount(ctx))
+
+		_, err := keeper.NewQueryServerImpl(k).Revocations(ctx, &types.QueryRevocationsRequest{Attester: "not-an-address"})
+		require.Error(t, err)
+	})
+
+	t.Run("survive export and import", func(t *testing.T) {
+		imported, importCtx := setupTestKeeper(t)
+		attestation.InitGenesis(importCtx, imported, *attestation.ExportGenesis(ctx, k))
+		require.Equal(t, revocations(k, ctx, attester), revocations(imported, importCtx, attester))
+		require.Equal(t, revocations(k, ctx, other), revocations(imported, importCtx, other))
+	})
+}
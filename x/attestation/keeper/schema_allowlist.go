@@ -0,0 +1,56 @@
+package keeper
+
+import (
+	"fmt"
+	"sort"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// checkSchemaAllowed rejects attestations against schemas whose creator is not
+// allowlisted while schema restriction is enabled
+func (k Keeper) checkSchemaAllowed(ctx sdk.Context, schema *types.Schema) error {
+	if k.GetParams(ctx).IsSchemaCreatorAllowed(schema.Creator) {
+		return nil
+	}
+	return errorsmod.Wrapf(types.ErrSchemaNotAllowed, "schema %s registered by %s", schema.UID, schema.Creator)
+}
+
+// UpdateSchemaAllowlist sets schema restriction and applies the creator additions
+// and removals. Only the module authority may call it. Removals are applied
+// after additions, so a creator listed in both ends up removed.
+func (k Keeper) UpdateSchemaAllowlist(ctx sdk.Context, authority string, restricted bool, add, remove []string) (types.Params, error) {
+	if authority != k.authority {
+		return types.Params{}, errorsmod.Wrapf(types.ErrUnauthorized, "expected %s, got %s", k.authority, authority)
+	}
+
+	creators := make(map[string]bool)
+	params := k.GetParams(ctx)
+	for _, c := range params.AllowedSchemaCreators {
+		creators[c] = true
+	}
+	for _, c := range add {
+		if _, err := sdk.AccAddressFromBech32(c); err != nil {
+			return types.Params{}, fmt.Errorf("invalid schema creator address %q", c)
+		}
+		creators[c] = true
+	}
+	for _, c := range remove {
+		delete(creators, c)
+	}
+
+	params.RestrictSchemas = restricted
+	params.AllowedSchemaCreators = make([]string, 0, len(creators))
+	for c := range creators {
+		params.AllowedSchemaCreators = append(params.AllowedSchemaCreators, c)
+	}
+	sort.Strings(params.AllowedSchemaCreators)
+	k.SetParams(ctx, params)
+
+	k.Logger(ctx).Info("Schema allowlist updated", "restricted", restricted, "creators", len(params.AllowedSchemaCreators))
+
+	return params, nil
+}
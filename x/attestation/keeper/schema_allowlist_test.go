@@ -0,0 +1,85 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestSchemaAllowlist(t *testing.T) {
+	authority := sdk.AccAddress("gov_authority_______").String()
+	trusted := sdk.AccAddress("trusted_issuer______")
+	outsider := sdk.AccAddress("outsider____________")
+	recipient := sdk.AccAddress("recipient___________")
+
+	setup := func(t *testing.T) (keeper.Keeper, sdk.Context, string, string) {
+		storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+		tkey := storetypes.NewTransientStoreKey("transient_test")
+		ctx := testutil.DefaultContext(storeKey, tkey).
+			WithBlockHeight(10).
+			WithBlockTime(time.Unix(1_700_000_000, 0).UTC())
+		k := keeper.NewKeeper(nil, storeKey, nil, authority)
+
+		trustedSchema, err := k.RegisterSchema(ctx, trusted, "bytes32 licenseHash", nil, true)
+		require.NoError(t, err)
+		outsiderSchema, err := k.RegisterSchema(ctx, outsider, "bytes32 badgeHash", nil, true)
+		require.NoError(t, err)
+		return k, ctx, trustedSchema, outsiderSchema
+	}
+
+	t.Run("open by default", func(t *testing.T) {
+		k, ctx, _, outsiderSchema := setup(t)
+		require.False(t, k.GetParams(ctx).RestrictSchemas)
+
+		_, err := k.CreateAttestation(ctx, outsider, outsiderSchema, recipient, time.Time{}, true, "", []byte("badge"))
+		require.NoError(t, err)
+	})
+
+	t.Run("allows schemas from allowlisted creators", func(t *testing.T) {
+		k, ctx, trustedSchema, _ := setup(t)
+		_, err := k.UpdateSchemaAllowlist(ctx, authority, true, []string{trusted.String()}, nil)
+		require.NoError(t, err)
+
+		// Any attester may use an allowlisted creator's schema
+		uid, err := k.CreateAttestation(ctx, outsider, trustedSchema, recipient, time.Time{}, true, "", []byte("license"))
+		require.NoError(t, err)
+		_, err = k.GetAttestation(ctx, uid)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects schemas from other creators", func(t *testing.T) {
+		k, ctx, _, outsiderSchema := setup(t)
+		_, err := k.UpdateSchemaAllowlist(ctx, authority, true, []string{trusted.String()}, nil)
+		require.NoError(t, err)
+
+		_, err = k.CreateAttestation(ctx, trusted, outsiderSchema, recipient, time.Time{}, true, "", []byte("badge"))
+		require.ErrorIs(t, err, types.ErrSchemaNotAllowed)
+		require.Zero(t, k.GetAttestationCount(ctx))
+	})
+
+	t.Run("removing a creator revokes access", func(t *testing.T) {
+		k, ctx, trustedSchema, _ := setup(t)
+		_, err := k.UpdateSchemaAllowlist(ctx, authority, true, []string{trusted.String(), outsider.String()}, nil)
+		require.NoError(t, err)
+		params, err := k.UpdateSchemaAllowlist(ctx, authority, true, nil, []string{trusted.String()})
+		require.NoError(t, err)
+		require.Equal(t, []string{outsider.String()}, params.AllowedSchemaCreators)
+
+		_, err = k.CreateAttestation(ctx, trusted, trustedSchema, recipient, time.Time{}, true, "", []byte("license"))
+		require.ErrorIs(t, err, types.ErrSchemaNotAllowed)
+	})
+
+	t.Run("requires the module authority", func(t *testing.T) {
+		k, ctx, _, _ := setup(t)
+		_, err := k.UpdateSchemaAllowlist(ctx, trusted.String(), true, []string{trusted.String()}, nil)
+		require.ErrorIs(t, err, types.ErrUnauthorized)
+		require.False(t, k.GetParams(ctx).RestrictSchemas)
+	})
+}
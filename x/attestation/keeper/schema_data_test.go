@@ -0,0 +1,129 @@
+package keeper_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation"
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestAttestSchemaData(t *testing.T) {
+	registrar := sdk.AccAddress("registrar___________")
+	student := sdk.AccAddress("student_____________")
+
+	k, ctx := setupTestKeeper(t)
+	msgServer := keeper.NewMsgServerImpl(k)
+
+	pack := func(typeNames []string, values ...interface{}) []byte {
+		args := make(abi.Arguments, len(typeNames))
+		for i, name := range typeNames {
+			typ, err := abi.NewType(name, "", nil)
+			require.NoError(t, err)
+			args[i] = abi.Argument{Type: typ}
+		}
+		data, err := args.Pack(values...)
+		require.NoError(t, err)
+		return data
+	}
+
+	t.Run("rejects a schema with unsupported field types", func(t *testing.T) {
+		_, err := msgServer.RegisterSchema(ctx, types.NewMsgRegisterSchema(registrar.String(), "uint256 grade,text course", "", true))
+		require.ErrorIs(t, err, types.ErrInvalidSchemaFormat)
+		_, err = k.RegisterSchema(ctx, registrar, "uint256", nil, true)
+		require.ErrorIs(t, err, types.ErrInvalidSchemaFormat)
+	})
+
+	res, err := msgServer.RegisterSchema(ctx, types.NewMsgRegisterSchema(registrar.String(), "uint256 grade,string course", "", true))
+	require.NoError(t, err)
+	attest := func(data []byte) error {
+		_, err := msgServer.Attest(ctx, types.NewMsgAttest(registrar.String(), res.Uid, student.String(), 0, true, "", data))
+		return err
+	}
+
+	t.Run("accepts data matching the schema", func(t *testing.T) {
+		require.NoError(t, attest(pack([]string{"uint256", "string"}, big.NewInt(92), "Distributed Systems")))
+	})
+
+	t.Run("rejects a type mismatch", func(t *testing.T) {
+		err := attest(pack([]string{"uint256", "bool"}, big.NewInt(92), true))
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.ErrorContains(t, err, "field 2 (string course)")
+
+		err = attest(pack([]string{"uint256"}, big.NewInt(92)))
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.ErrorContains(t, err, "schema expects 2 fields")
+
+		require.Equal(t, uint64(1), k.GetAttestationCount(ctx))
+	})
+}
+
+// TestAttestLegacySchemaData checks that schemas stored before definitions were
+// parsed at registration keep accepting attestations
+func TestAttestLegacySchemaData(t *testing.T) {
+	registrar := sdk.AccAddress("legacy_registrar____")
+
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test"))
+	k := keeper.NewKeeper(nil, storeKey, nil, "")
+	msgServer := keeper.NewMsgServerImpl(k)
+
+	legacy := types.Schema{UID: "legacy-schema", Schema: `{"name":"string","grade":"uint8"}`, Revocable: true, Creator: registrar}
+	bz, err := json.Marshal(legacy)
+	require.NoError(t, err)
+	ctx.KVStore(storeKey).Set(types.GetSchemaKey(legacy.UID), bz)
+
+	data := []byte(`{"name":"Ada","grade":92}`)
+	_, err = msgServer.Attest(ctx, types.NewMsgAttest(registrar.String(), legacy.UID, "", 0, true, "", data))
+	require.NoError(t, err)
+
+	uids, err := k.BatchCreateAttestations(ctx, registrar, legacy.UID, true, nil, []types.BatchAttestEntry{{Data: data}, {Data: []byte("free-form")}})
+	require.NoError(t, err)
+	require.Len(t, uids, 2)
+}
+
+// TestGenesisKeepsLegacySchemas checks that genesis import keeps schemas that
+// no longer parse, and the attestations under them, instead of dropping them
+func TestGenesisKeepsLegacySchemas(t *testing.T) {
+	registrar := sdk.AccAddress("legacy_registrar____")
+
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test"))
+	k := keeper.NewKeeper(nil, storeKey, nil, "")
+
+	legacy := types.Schema{UID: "legacy-schema", Schema: `{"name":"string","grade":"uint8"}`, Revocable: true, Creator: registrar}
+	bz, err := json.Marshal(legacy)
+	require.NoError(t, err)
+	ctx.KVStore(storeKey).Set(types.GetSchemaKey(legacy.UID), bz)
+	uid, err := k.CreateAttestation(ctx, registrar, legacy.UID, nil, time.Time{}, true, "", []byte(`{"name":"Ada","grade":92}`))
+	require.NoError(t, err)
+
+	exported := attestation.ExportGenesis(ctx, k)
+	imported, importCtx := setupTestKeeper(t)
+	attestation.InitGenesis(importCtx, imported, *exported)
+
+	schema, err := imported.GetSchema(importCtx, legacy.UID)
+	require.NoError(t, err)
+	require.Equal(t, legacy.Schema, schema.Schema)
+	a, err := imported.GetAttestation(importCtx, uid)
+	require.NoError(t, err)
+	_, err = imported.GetSchema(importCtx, a.SchemaUID)
+	require.NoError(t, err)
+
+	// A schema that cannot be imported stops genesis rather than being skipped
+	exported.Schemas = append(exported.Schemas, exported.Schemas[0])
+	again, againCtx := setupTestKeeper(t)
+	require.Panics(t, func() { attestation.InitGenesis(againCtx, again, *exported) })
+	require.ErrorIs(t, exported.Validate(), types.ErrDuplicateSchema)
+	exported.Schemas[0].UID = ""
+	require.ErrorIs(t, exported.Validate(), types.ErrInvalidSchemaFormat)
+}
@@ -0,0 +1,118 @@
+package keeper
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// maxSchemaCreatorShareBps returns the creator share limit, defaulting when the param is unset
+func (k Keeper) maxSchemaCreatorShareBps(ctx sdk.Context) uint32 {
+	if limit := k.GetParams(ctx).MaxSchemaCreatorShareBps; limit != 0 {
+		return limit
+	}
+	return types.DefaultMaxSchemaCreatorShareBps
+}
+
+// feeDenom returns the denom schema fees are charged in, defaulting when the param is unset
+func (k Keeper) feeDenom(ctx sdk.Context) string {
+	if denom := k.GetParams(ctx).FeeDenom; denom != "" {
+		return denom
+	}
+	return types.DefaultFeeDenom
+}
+
+// collectSchemaFee charges attester the schema's fee, paying the creator's
+// share to the schema creator and the remainder to the module account
+func (k Keeper) collectSchemaFee(ctx sdk.Context, attester sdk.AccAddress, schema *types.Schema) error {
+	if !schema.HasFee() {
+		return nil
+	}
+	if k.bankKeeper == nil {
+		return errorsmod.Wrap(types.ErrSchemaFeePayment, "fee collection is not configured")
+	}
+
+	// Check the whole fee up front so a failed payment leaves no partial transfer
+	if spendable := k.bankKeeper.SpendableCoins(ctx, attester); !spendable.IsAllGTE(schema.Fee) {
+		return errorsmod.Wrapf(types.ErrSchemaFeePayment, "spendable balance %s is smaller than fee %s", spendable, schema.Fee)
+	}
+
+	creatorCut, moduleCut := types.SplitSchemaFee(schema.Fee, schema.CreatorShareBps)
+	if len(schema.Creator) == 0 {
+		// Genesis schemas have no creator to pay
+		creatorCut, moduleCut = sdk.NewCoins(), schema.Fee
+	}
+
+	if !creatorCut.IsZero() {
+		if err := k.bankKeeper.SendCoins(ctx, attester, schema.Creator, creatorCut); err != nil {
+			return errorsmod.Wrapf(types.ErrSchemaFeePayment, "creator share: %s", err)
+		}
+	}
+	if !moduleCut.IsZero() {
+		if err := k.bankKeeper.SendCoinsFromAccountToModule(ctx, attester, types.ModuleName, moduleCut); err != nil {
+			return errorsmod.Wrapf(types.ErrSchemaFeePayment, "module share: %s", err)
+		}
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeSchemaFeePaid,
+			sdk.NewAttribute(types.AttributeKeySchemaUID, schema.UID),
+			sdk.NewAttribute(types.AttributeKeyAttester, attester.String()),
+			sdk.NewAttribute(types.AttributeKeyCreator, schema.Creator.String()),
+			sdk.NewAttribute(types.AttributeKeyFee, schema.Fee.String()),
+			sdk.NewAttribute(types.AttributeKeyCreatorShare, creatorCut.String()),
+			sdk.NewAttribute(types.AttributeKeyModuleShare, moduleCut.String()),
+		),
+	)
+	return nil
+}
+
+// GetFeeBalance returns the schema fees held by the module account
+func (k Keeper) GetFeeBalance(ctx sdk.Context) (sdk.Coins, error) {
+	if k.bankKeeper == nil {
+		return nil, errorsmod.Wrap(types.ErrSchemaFeePayment, "fee collection is not configured")
+	}
+	return k.bankKeeper.GetAllBalances(ctx, types.ModuleAddress), nil
+}
+
+// WithdrawFees moves amount (the whole balance when empty) out of the module
+// account, either burning it or sending it to recipient. Only the module
+// authority may call it. CERT runs without x/distribution, so a community
+// pool payout names the community treasury as recipient.
+func (k Keeper) WithdrawFees(ctx sdk.Context, authority string, amount sdk.Coins, recipient sdk.AccAddress, burn bool) (sdk.Coins, error) {
+	if authority != k.authority {
+		return nil, errorsmod.Wrapf(types.ErrUnauthorized, "expected %s, got %s", k.authority, authority)
+	}
+	if burn == (len(recipient) != 0) {
+		return nil, errorsmod.Wrap(types.ErrInvalidFeeWithdrawal, "exactly one of recipient or burn is required")
+	}
+
+	balance, err := k.GetFeeBalance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if amount.IsZero() {
+		amount = balance
+	}
+	if amount.IsZero() {
+		return nil, errorsmod.Wrap(types.ErrInvalidFeeWithdrawal, "module account holds no fees")
+	}
+	if !balance.IsAllGTE(amount) {
+		return nil, errorsmod.Wrapf(types.ErrInvalidFeeWithdrawal, "balance %s is smaller than %s", balance, amount)
+	}
+
+	if burn {
+		err = k.bankKeeper.BurnCoins(ctx, types.ModuleName, amount)
+	} else {
+		err = k.bankKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, recipient, amount)
+	}
+	if err != nil {
+		return nil, errorsmod.Wrap(types.ErrInvalidFeeWithdrawal, err.Error())
+	}
+
+	k.Logger(ctx).Info("Module fees withdrawn", "amount", amount.String(), "recipient", recipient.String(), "burned", burn)
+
+	return amount, nil
+}
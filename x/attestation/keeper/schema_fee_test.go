@@ -0,0 +1,182 @@
+package keeper_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation"
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// mockBankKeeper tracks balances in memory, keyed by bech32 address
+type mockBankKeeper struct {
+	balances map[string]sdk.Coins
+}
+
+func newMockBankKeeper() *mockBankKeeper {
+	return &mockBankKeeper{balances: map[string]sdk.Coins{}}
+}
+
+func (b *mockBankKeeper) fund(addr sdk.AccAddress, amt sdk.Coins) {
+	b.balances[addr.String()] = b.balances[addr.String()].Add(amt...)
+}
+
+func (b *mockBankKeeper) balance(addr sdk.AccAddress) sdk.Coins {
+	return b.balances[addr.String()]
+}
+
+func (b *mockBankKeeper) move(from, to string, amt sdk.Coins) error {
+	remaining, negative := b.balances[from].SafeSub(amt...)
+	if negative {
+		return fmt.Errorf("%s is smaller than %s: insufficient funds", b.balances[from], amt)
+	}
+	b.balances[from] = remaining
+	b.balances[to] = b.balances[to].Add(amt...)
+	return nil
+}
+
+func (b *mockBankKeeper) SpendableCoins(_ context.Context, addr sdk.AccAddress) sdk.Coins {
+	return b.balances[addr.String()]
+}
+
+func (b *mockBankKeeper) GetAllBalances(_ context.Context, addr sdk.AccAddress) sdk.Coins {
+	return b.balances[addr.String()]
+}
+
+func (b *mockBankKeeper) SendCoinsFromAccountToModule(_ context.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error {
+	return b.move(senderAddr.String(), authtypes.NewModuleAddress(recipientModule).String(), amt)
+}
+
+func (b *mockBankKeeper) SendCoinsFromModuleToAccount(_ context.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins) error {
+	return b.move(authtypes.NewModuleAddress(senderModule).String(), recipientAddr.String(), amt)
+}
+
+func (b *mockBankKeeper) BurnCoins(_ context.Context, moduleName string, amt sdk.Coins) error {
+	return b.move(authtypes.NewModuleAddress(moduleName).String(), "burned", amt)
+}
+
+func (b *mockBankKeeper) SendCoins(_ context.Context, fromAddr, toAddr sdk.AccAddress, amt sdk.Coins) error {
+	return b.move(fromAddr.String(), toAddr.String(), amt)
+}
+
+func TestSchemaFees(t *testing.T) {
+	creator := sdk.AccAddress("schema_creator______")
+	attester := sdk.AccAddress("attester____________")
+	recipient := sdk.AccAddress("recipient___________")
+	fee := sdk.NewCoins(sdk.NewInt64Coin("ucert", 1_000))
+
+	setup := func(t *testing.T) (keeper.Keeper, sdk.Context, *mockBankKeeper) {
+		k, ctx := setupTestKeeper(t)
+		bank := newMockBankKeeper()
+		k.SetBankKeeper(bank)
+		bank.fund(attester, sdk.NewCoins(sdk.NewInt64Coin("ucert", 10_000)))
+		return k, ctx, bank
+	}
+
+	t.Run("splits the fee between creator and module account", func(t *testing.T) {
+		k, ctx, bank := setup(t)
+		schemaUID, err := k.RegisterSchemaWithFee(ctx, creator, "bytes32 degreeHash", nil, true, fee, 2_500)
+		require.NoError(t, err)
+
+		_, err = k.CreateAttestation(ctx, attester, schemaUID, recipient, time.Time{}, true, "", []byte("degree"))
+		require.NoError(t, err)
+		_, err = k.CreateAttestation(ctx, attester, schemaUID, recipient, time.Time{}, true, "", []byte("diploma"))
+		require.NoError(t, err)
+
+		require.Equal(t, sdkmath.NewInt(8_000), bank.balance(attester).AmountOf("ucert"))
+		require.Equal(t, sdkmath.NewInt(500), bank.balance(creator).AmountOf("ucert"))
+		require.Equal(t, sdkmath.NewInt(1_500), bank.balance(types.ModuleAddress).AmountOf("ucert"))
+	})
+
+	t.Run("schemas without a fee are free", func(t *testing.T) {
+		k, ctx, bank := setup(t)
+		uids := createTestAttestations(t, k, ctx, attester, 2, true)
+		require.Len(t, uids, 2)
+		require.Equal(t, sdkmath.NewInt(10_000), bank.balance(attester).AmountOf("ucert"))
+	})
+
+	t.Run("enforces the creator share limit", func(t *testing.T) {
+		k, ctx, _ := setup(t)
+		_, err := k.RegisterSchemaWithFee(ctx, creator, "bytes32 a", nil, true, fee, types.DefaultMaxSchemaCreatorShareBps+1)
+		require.ErrorIs(t, err, types.ErrInvalidSchemaFee)
+
+		params := k.GetParams(ctx)
+		params.MaxSchemaCreatorShareBps = types.BasisPointsDenominator
+		k.SetParams(ctx, params)
+		_, err = k.RegisterSchemaWithFee(ctx, creator, "bytes32 a", nil, true, fee, types.BasisPointsDenominator)
+		require.NoError(t, err)
+
+		_, err = k.RegisterSchemaWithFee(ctx, creator, "bytes32 b", nil, true, nil, 100)
+		require.ErrorIs(t, err, types.ErrInvalidSchemaFee)
+	})
+
+	t.Run("fees must be in the fee denom", func(t *testing.T) {
+		k, ctx, _ := setup(t)
+		_, err := k.RegisterSchemaWithFee(ctx, creator, "bytes32 a", nil, true, sdk.NewCoins(sdk.NewInt64Coin("uatom", 10)), 0)
+		require.ErrorIs(t, err, types.ErrInvalidSchemaFee)
+
+		params := k.GetParams(ctx)
+		params.FeeDenom = "uatom"
+		k.SetParams(ctx, params)
+		_, err = k.RegisterSchemaWithFee(ctx, creator, "bytes32 a", nil, true, sdk.NewCoins(sdk.NewInt64Coin("uatom", 10)), 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("insufficient funds reject the attestation", func(t *testing.T) {
+		k, ctx, bank := setup(t)
+		expensive := sdk.NewCoins(sdk.NewInt64Coin("ucert", 50_000))
+		schemaUID, err := k.RegisterSchemaWithFee(ctx, creator, "bytes32 degreeHash", nil, true, expensive, 1_000)
+		require.NoError(t, err)
+
+		_, err = k.CreateAttestation(ctx, attester, schemaUID, recipient, time.Time{}, true, "", []byte("degree"))
+		require.ErrorIs(t, err, types.ErrSchemaFeePayment)
+
+		byRecipient, err := k.GetAttestationsByRecipient(ctx, recipient)
+		require.NoError(t, err)
+		require.Empty(t, byRecipient)
+		require.Equal(t, sdkmath.NewInt(10_000), bank.balance(attester).AmountOf("ucert"))
+	})
+
+	t.Run("reassignment is not charged again", func(t *testing.T) {
+		k, ctx, bank := setup(t)
+		schemaUID, err := k.RegisterSchemaWithFee(ctx, creator, "bytes32 degreeHash", nil, true, fee, 5_000)
+		require.NoError(t, err)
+		uid, err := k.CreateAttestation(ctx, attester, schemaUID, recipient, time.Time{}, true, "", []byte("degree"))
+		require.NoError(t, err)
+
+		_, err = k.ReassignAttestation(ctx, attester, uid, sdk.AccAddress("new_recipient_______"))
+		require.NoError(t, err)
+		require.Equal(t, sdkmath.NewInt(9_000), bank.balance(attester).AmountOf("ucert"))
+	})
+
+	t.Run("fees survive genesis export and import", func(t *testing.T) {
+		k, ctx, _ := setup(t)
+		schemaUID, err := k.RegisterSchemaWithFee(ctx, creator, "bytes32 degreeHash", nil, true, fee, 2_500)
+		require.NoError(t, err)
+
+		genesis := attestation.ExportGenesis(ctx, k)
+		imported, importedCtx := setupTestKeeper(t)
+		attestation.InitGenesis(importedCtx, imported, *genesis)
+
+		schema, err := imported.GetSchema(importedCtx, schemaUID)
+		require.NoError(t, err)
+		require.Equal(t, fee, schema.Fee)
+		require.Equal(t, uint32(2_500), schema.CreatorShareBps)
+	})
+}
+
+func TestSplitSchemaFee(t *testing.T) {
+	fee := sdk.NewCoins(sdk.NewInt64Coin("uatom", 3), sdk.NewInt64Coin("ucert", 999))
+	creatorCut, moduleCut := types.SplitSchemaFee(fee, 3_333)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("ucert", 332)), creatorCut)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uatom", 3), sdk.NewInt64Coin("ucert", 667)), moduleCut)
+	require.Equal(t, fee, creatorCut.Add(moduleCut...))
+}
@@ -0,0 +1,48 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// setupTestKeeper returns a keeper backed by an in-memory store
+func setupTestKeeper(t *testing.T) (keeper.Keeper, sdk.Context) {
+	t.Helper()
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	tkey := storetypes.NewTransientStoreKey("transient_test")
+	ctx := testutil.DefaultContext(storeKey, tkey).
+		WithBlockHeight(10).
+		WithBlockTime(time.Unix(1_700_000_000, 0).UTC())
+	return keeper.NewKeeper(nil, storeKey, nil, ""), ctx
+}
+
+// createTestAttestations creates n attestations by attester under a shared schema
+func createTestAttestations(t *testing.T, k keeper.Keeper, ctx sdk.Context, attester sdk.AccAddress, n int, revocable bool) []string {
+	t.Helper()
+	schemaUID, err := k.RegisterSchema(ctx, attester, "bytes32 programHash", nil, true)
+	if err != nil {
+		// Schema already registered by an earlier call in the same test
+		schemaUID = types.GenerateSchemaUID("bytes32 programHash", nil, true)
+	}
+
+	uids := make([]string, n)
+	for i := range uids {
+		data := make([]byte, 32)
+		data[31] = byte(i + 1)
+		if !revocable {
+			data[0] = 0xff
+		}
+		uid, err := k.CreateAttestation(ctx, attester, schemaUID, nil, time.Time{}, revocable, "", data)
+		require.NoError(t, err)
+		uids[i] = uid
+	}
+	return uids
+}
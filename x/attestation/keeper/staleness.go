@@ -0,0 +1,76 @@
+package keeper
+
+import (
+	"fmt"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// ReaffirmAttestation refreshes the affirmation time of an attestation whose
+// schema has a MaxStaleness, clearing its stale flag. Only the attester may
+// reaffirm, and revoked or expired attestations cannot be reaffirmed.
+func (k Keeper) ReaffirmAttestation(ctx sdk.Context, attester sdk.AccAddress, uid string) error {
+	store := ctx.KVStore(k.storeKey)
+
+	attestation, err := k.GetAttestation(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if !attestation.Attester.Equals(attester) {
+		return errorsmod.Wrap(types.ErrUnauthorized, "only the attester can reaffirm this attestation")
+	}
+	if !attestation.RevocationTime.IsZero() {
+		return errorsmod.Wrap(types.ErrAttestationAlreadyRevoked, uid)
+	}
+	if attestation.Expired || (!attestation.ExpirationTime.IsZero() && ctx.BlockTime().After(attestation.ExpirationTime)) {
+		return errorsmod.Wrap(types.ErrAttestationExpired, uid)
+	}
+
+	schema, err := k.GetSchema(ctx, attestation.SchemaUID)
+	if err != nil {
+		return err
+	}
+	if !schema.RequiresReaffirmation() {
+		return errorsmod.Wrap(types.ErrReaffirmationNotRequired, schema.UID)
+	}
+
+	attestation.ReaffirmedTime = ctx.BlockTime()
+	bz, err := marshalAttestation(*attestation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reaffirmed attestation: %w", err)
+	}
+	store.Set(types.GetAttestationKey(uid), bz)
+
+	k.Logger(ctx).Info("Attestation reaffirmed", "uid", uid, "attester", attester.String())
+
+	return nil
+}
+
+// IsAttestationStale reports whether an attestation has gone unaffirmed past its schema's MaxStaleness
+func (k Keeper) IsAttestationStale(ctx sdk.Context, uid string) (bool, error) {
+	attestation, err := k.GetAttestation(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	schema, err := k.GetSchema(ctx, attestation.SchemaUID)
+	if err != nil {
+		return false, err
+	}
+	return schema.IsStale(*attestation, ctx.BlockTime()), nil
+}
+
+// setStaleFlags sets Stale on query results as of the current block time
+func (k Keeper) setStaleFlags(ctx sdk.Context, attestations []types.Attestation) {
+	schemas := make(map[string]*types.Schema)
+	for i := range attestations {
+		schema, ok := schemas[attestations[i].SchemaUID]
+		if !ok {
+			schema, _ = k.GetSchema(ctx, attestations[i].SchemaUID)
+			schemas[attestations[i].SchemaUID] = schema
+		}
+		attestations[i].Stale = schema != nil && schema.IsStale(attestations[i], ctx.BlockTime())
+	}
+}
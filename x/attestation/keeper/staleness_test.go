@@ -0,0 +1,129 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation"
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestAttestationStaleness(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	recipient := sdk.AccAddress("recipient___________")
+	now := time.Unix(1_700_000_000, 0).UTC()
+	day := 24 * time.Hour
+
+	k, ctx := setupTestKeeper(t)
+	ctx = ctx.WithBlockTime(now)
+	msgServer := keeper.NewMsgServerImpl(k)
+	queryServer := keeper.NewQueryServerImpl(k)
+
+	// "Currently employed" must be reaffirmed every 30 days
+	res, err := msgServer.RegisterSchema(ctx, &types.MsgRegisterSchema{
+		Creator:      issuer.String(),
+		Schema:       "string employer",
+		Revocable:    true,
+		MaxStaleness: uint64((30 * day).Seconds()),
+	})
+	require.NoError(t, err)
+	employedSchema := res.Uid
+	schema, err := k.GetSchema(ctx, employedSchema)
+	require.NoError(t, err)
+	require.Equal(t, uint64(30*24*60*60), schema.MaxStaleness)
+
+	diplomaSchema, err := k.RegisterSchema(ctx, issuer, "string degree", nil, true)
+	require.NoError(t, err)
+
+	employed, err := k.CreateAttestation(ctx, issuer, employedSchema, recipient, now.Add(365*day), true, "", []byte("acme"))
+	require.NoError(t, err)
+	diploma, err := k.CreateAttestation(ctx, issuer, diplomaSchema, recipient, time.Time{}, true, "", []byte("bsc"))
+	require.NoError(t, err)
+
+	staleOf := func(ctx sdk.Context) map[string]bool {
+		res, err := queryServer.AttestationsByRecipient(ctx, &types.QueryAttestationsByRecipientRequest{Recipient: recipient.String()})
+		require.NoError(t, err)
+		stale := make(map[string]bool)
+		for _, a := range res.Attestations {
+			stale[a.UID] = a.Stale
+		}
+		return stale
+	}
+
+	t.Run("fresh within the window", func(t *testing.T) {
+		ctx := ctx.WithBlockTime(now.Add(30 * day))
+		require.Equal(t, map[string]bool{employed: false, diploma: false}, staleOf(ctx))
+	})
+
+	t.Run("goes stale once the window passes", func(t *testing.T) {
+		ctx := ctx.WithBlockTime(now.Add(31 * day))
+		require.Equal(t, map[string]bool{employed: true, diploma: false}, staleOf(ctx))
+
+		res, err := queryServer.Attestation(ctx, &types.QueryAttestationRequest{Uid: employed})
+		require.NoError(t, err)
+		require.True(t, res.Attestation.Stale)
+		// Stale is not expired
+		require.False(t, res.Attestation.Expired)
+		expired, err := k.IsAttestationExpired(ctx, employed)
+		require.NoError(t, err)
+		require.False(t, expired)
+
+		stale, err := k.IsAttestationStale(ctx, employed)
+		require.NoError(t, err)
+		require.True(t, stale)
+	})
+
+	t.Run("reaffirmation clears the flag", func(t *testing.T) {
+		ctx := ctx.WithBlockTime(now.Add(40 * day))
+		_, err := msgServer.Reaffirm(ctx, types.NewMsgReaffirm(recipient.String(), employed))
+		require.ErrorIs(t, err, types.ErrUnauthorized)
+
+		_, err = msgServer.Reaffirm(ctx, types.NewMsgReaffirm(issuer.String(), employed))
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{employed: false, diploma: false}, staleOf(ctx))
+
+		attestation, err := k.GetAttestation(ctx, employed)
+		require.NoError(t, err)
+		require.Equal(t, now.Add(40*day), attestation.ReaffirmedTime)
+		require.False(t, attestation.Stale, "stale flag must not be stored")
+
+		// The window restarts from the reaffirmation
+		require.Equal(t, map[string]bool{employed: false, diploma: false}, staleOf(ctx.WithBlockTime(now.Add(70*day))))
+		require.Equal(t, map[string]bool{employed: true, diploma: false}, staleOf(ctx.WithBlockTime(now.Add(71*day))))
+	})
+
+	t.Run("survives genesis export and import", func(t *testing.T) {
+		exported := attestation.ExportGenesis(ctx, k)
+
+		imported, importCtx := setupTestKeeper(t)
+		attestation.InitGenesis(importCtx, imported, *exported)
+		require.Equal(t, k.GetAllSchemas(ctx), imported.GetAllSchemas(importCtx))
+		schema, err := imported.GetSchema(importCtx, employedSchema)
+		require.NoError(t, err)
+		require.Equal(t, uint64(30*24*60*60), schema.MaxStaleness)
+
+		stale, err := imported.IsAttestationStale(importCtx.WithBlockTime(now.Add(71*day)), employed)
+		require.NoError(t, err)
+		require.True(t, stale)
+	})
+
+	t.Run("rejects schemas without staleness and revoked attestations", func(t *testing.T) {
+		err := k.ReaffirmAttestation(ctx, issuer, diploma)
+		require.ErrorIs(t, err, types.ErrReaffirmationNotRequired)
+
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, employed))
+		err = k.ReaffirmAttestation(ctx, issuer, employed)
+		require.ErrorIs(t, err, types.ErrAttestationAlreadyRevoked)
+	})
+
+	t.Run("validates the window", func(t *testing.T) {
+		msg := types.NewMsgRegisterSchema(issuer.String(), "string role", "", true)
+		msg.MaxStaleness = types.MaxSchemaStaleness + 1
+		require.Error(t, msg.ValidateBasic())
+		require.Error(t, types.NewMsgReaffirm(issuer.String(), "").ValidateBasic())
+	})
+}
@@ -0,0 +1,80 @@
+package keeper_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation"
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestStats(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	recipient := sdk.AccAddress("recipient___________")
+	newRecipient := sdk.AccAddress("new_recipient_______")
+
+	k, ctx := setupTestKeeper(t)
+	stats := func(k keeper.Keeper, ctx sdk.Context) types.QueryStatsResponse {
+		t.Helper()
+		res, err := keeper.NewQueryServerImpl(k).Stats(ctx, &types.QueryStatsRequest{})
+		require.NoError(t, err)
+		return *res
+	}
+
+	t.Run("start at zero", func(t *testing.T) {
+		require.Equal(t, types.QueryStatsResponse{}, stats(k, ctx))
+	})
+
+	t.Run("move on each operation", func(t *testing.T) {
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 degreeHash", nil, true)
+		require.NoError(t, err)
+		_, err = k.RegisterSchema(ctx, issuer, "bytes32 degreeHash", nil, true)
+		require.Error(t, err)
+		require.Equal(t, types.QueryStatsResponse{TotalSchemas: 1}, stats(k, ctx))
+
+		uids := createTestAttestations(t, k, ctx, issuer, 4, true)
+		encryptedUID, err := k.CreateEncryptedAttestation(ctx, issuer, schemaUID, "Qm"+strings.Repeat("a", 44), strings.Repeat("ab", 32),
+			[]sdk.AccAddress{recipient}, map[string]string{recipient.String(): "key"}, true, time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, types.QueryStatsResponse{
+			TotalAttestations:          5,
+			TotalEncryptedAttestations: 1,
+			TotalSchemas:               2,
+		}, stats(k, ctx))
+
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, uids[0]))
+		require.Error(t, k.RevokeAttestation(ctx, issuer, uids[0]))
+		require.NoError(t, k.BatchRevokeAttestations(ctx, issuer, uids[1:2]))
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, encryptedUID))
+		require.Equal(t, uint64(3), stats(k, ctx).TotalRevocations)
+
+		// Reassignment issues a replacement and revokes the original
+		_, err = k.ReassignAttestation(ctx, issuer, uids[3], newRecipient)
+		require.NoError(t, err)
+		require.Equal(t, types.QueryStatsResponse{
+			TotalAttestations:          6,
+			TotalEncryptedAttestations: 1,
+			TotalSchemas:               2,
+			TotalRevocations:           4,
+		}, stats(k, ctx))
+
+		msg, broken := keeper.AttestationCountInvariant(k)(ctx)
+		require.False(t, broken, msg)
+	})
+
+	t.Run("survive export and import", func(t *testing.T) {
+		want := stats(k, ctx)
+		exported := attestation.ExportGenesis(ctx, k)
+
+		imported, importCtx := setupTestKeeper(t)
+		attestation.InitGenesis(importCtx, imported, *exported)
+		require.Equal(t, want, stats(imported, importCtx))
+		msg, broken := keeper.AttestationCountInvariant(imported)(importCtx)
+		require.False(t, broken, msg)
+	})
+}
@@ -0,0 +1,69 @@
+package keeper
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// indexTags records attestation under each of its tags
+func (k Keeper) indexTags(ctx sdk.Context, attestation types.Attestation) {
+	store := ctx.KVStore(k.storeKey)
+	for _, tag := range attestation.Tags {
+		store.Set(types.GetAttestationByTagKey(tag, attestation.UID), []byte{1})
+	}
+}
+
+// GetAttestationUIDsByTags returns, in UID order, the UIDs of attestations carrying every one of tags
+func (k Keeper) GetAttestationUIDsByTags(ctx sdk.Context, tags []string) ([]string, error) {
+	normalized, err := types.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(normalized) == 0 {
+		return nil, errorsmod.Wrap(types.ErrInvalidTags, "at least one tag is required")
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	prefix := types.GetAttestationsByTagIteratorPrefix(normalized[0])
+	iterator := storetypes.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+
+	var uids []string
+	for ; iterator.Valid(); iterator.Next() {
+		uid := string(iterator.Key()[len(prefix):])
+		matches := true
+		for _, tag := range normalized[1:] {
+			if !store.Has(types.GetAttestationByTagKey(tag, uid)) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			uids = append(uids, uid)
+		}
+	}
+	return uids, nil
+}
+
+// GetAttestationsByTags returns the attestations carrying every one of tags, optionally including revoked ones
+func (k Keeper) GetAttestationsByTags(ctx sdk.Context, tags []string, includeRevoked bool) ([]types.Attestation, error) {
+	uids, err := k.GetAttestationUIDsByTags(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+	attestations := make([]types.Attestation, 0, len(uids))
+	for _, uid := range uids {
+		attestation, err := k.getAttestationOrTombstone(ctx, uid)
+		if err != nil {
+			continue
+		}
+		if !includeRevoked && !attestation.RevocationTime.IsZero() {
+			continue
+		}
+		attestations = append(attestations, *attestation)
+	}
+	return attestations, nil
+}
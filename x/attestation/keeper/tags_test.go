@@ -0,0 +1,150 @@
+package keeper_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation"
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func TestAttestationTags(t *testing.T) {
+	issuer := sdk.AccAddress("issuer______________")
+	recipient := sdk.AccAddress("recipient___________")
+	data := make([]byte, 32)
+
+	t.Run("indexes normalized tags", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 programHash", nil, true)
+		require.NoError(t, err)
+
+		uid, err := k.CreateAttestationWithTags(ctx, issuer, schemaUID, recipient, time.Time{}, true, "", data,
+			[]string{" Diploma", "2024-cohort", "diploma"})
+		require.NoError(t, err)
+		untagged, err := k.CreateAttestation(ctx, issuer, schemaUID, recipient, time.Time{}, true, "", data)
+		require.NoError(t, err)
+
+		stored, err := k.GetAttestation(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, []string{"diploma", "2024-cohort"}, stored.Tags)
+		plain, err := k.GetAttestation(ctx, untagged)
+		require.NoError(t, err)
+		require.Empty(t, plain.Tags)
+
+		uids, err := k.GetAttestationUIDsByTags(ctx, []string{"DIPLOMA"})
+		require.NoError(t, err)
+		require.Equal(t, []string{uid}, uids)
+
+		uids, err = k.GetAttestationUIDsByTags(ctx, []string{"employment"})
+		require.NoError(t, err)
+		require.Empty(t, uids)
+	})
+
+	t.Run("filters by every requested tag", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 programHash", nil, true)
+		require.NoError(t, err)
+		create := func(tags ...string) string {
+			uid, err := k.CreateAttestationWithTags(ctx, issuer, schemaUID, nil, time.Time{}, true, "", data, tags)
+			require.NoError(t, err)
+			return uid
+		}
+		diploma2024 := create("diploma", "2024-cohort")
+		diploma2025 := create("diploma", "2025-cohort")
+		employment2024 := create("employment", "2024-cohort")
+		honours := create("diploma", "2024-cohort", "honours")
+
+		uids, err := k.GetAttestationUIDsByTags(ctx, []string{"diploma"})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{diploma2024, diploma2025, honours}, uids)
+
+		uids, err = k.GetAttestationUIDsByTags(ctx, []string{"2024-cohort", "diploma"})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{diploma2024, honours}, uids)
+
+		uids, err = k.GetAttestationUIDsByTags(ctx, []string{"2024-cohort"})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{diploma2024, employment2024, honours}, uids)
+
+		// Revoked attestations stay tagged but are filtered unless requested
+		require.NoError(t, k.RevokeAttestation(ctx, issuer, honours))
+		active, err := k.GetAttestationsByTags(ctx, []string{"diploma", "2024-cohort"}, false)
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		require.Equal(t, diploma2024, active[0].UID)
+		all, err := k.GetAttestationsByTags(ctx, []string{"diploma", "2024-cohort"}, true)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+
+		queryServer := keeper.NewQueryServerImpl(k)
+		res, err := queryServer.AttestationsByTag(ctx, &types.QueryAttestationsByTagRequest{Tag: "diploma", Tags: []string{"2025-cohort"}})
+		require.NoError(t, err)
+		require.Len(t, res.Attestations, 1)
+		require.Equal(t, diploma2025, res.Attestations[0].UID)
+		_, err = queryServer.AttestationsByTag(ctx, &types.QueryAttestationsByTagRequest{})
+		require.ErrorIs(t, err, types.ErrInvalidTags)
+	})
+
+	t.Run("enforces tag caps", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 programHash", nil, true)
+		require.NoError(t, err)
+
+		maxTags := make([]string, types.MaxAttestationTags)
+		for i := range maxTags {
+			maxTags[i] = fmt.Sprintf("tag-%d", i)
+		}
+		_, err = k.CreateAttestationWithTags(ctx, issuer, schemaUID, nil, time.Time{}, true, "", data, maxTags)
+		require.NoError(t, err)
+		_, err = k.CreateAttestationWithTags(ctx, issuer, schemaUID, nil, time.Time{}, true, "", data,
+			[]string{strings.Repeat("a", types.MaxTagLength)})
+		require.NoError(t, err)
+		before := k.GetAttestationCount(ctx)
+
+		for name, tags := range map[string][]string{
+			"too many tags":  append(maxTags, "one-more"),
+			"tag too long":   {strings.Repeat("a", types.MaxTagLength+1)},
+			"empty tag":      {"diploma", " "},
+			"invalid rune":   {"class of 2024"},
+			"non-ascii rune": {"diplôme"},
+		} {
+			_, err := k.CreateAttestationWithTags(ctx, issuer, schemaUID, nil, time.Time{}, true, "", data, tags)
+			require.ErrorIs(t, err, types.ErrInvalidTags, name)
+		}
+		require.Equal(t, before, k.GetAttestationCount(ctx), "rejected attestations were stored")
+
+		_, err = k.GetAttestationUIDsByTags(ctx, append(maxTags, "one-more"))
+		require.ErrorIs(t, err, types.ErrInvalidTags)
+	})
+
+	t.Run("carries tags through the msg server, reassignment and genesis", func(t *testing.T) {
+		k, ctx := setupTestKeeper(t)
+		schemaUID, err := k.RegisterSchema(ctx, issuer, "bytes32 programHash", nil, true)
+		require.NoError(t, err)
+
+		msg := types.NewMsgAttest(issuer.String(), schemaUID, recipient.String(), 0, true, "", data)
+		msg.Tags = []string{"Employment"}
+		res, err := keeper.NewMsgServerImpl(k).Attest(ctx, msg)
+		require.NoError(t, err)
+
+		replacement, err := k.ReassignAttestation(ctx, issuer, res.Uid, sdk.AccAddress("new_recipient_______"))
+		require.NoError(t, err)
+		uids, err := k.GetAttestationUIDsByTags(ctx, []string{"employment"})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{res.Uid, replacement}, uids)
+
+		exported := attestation.ExportGenesis(ctx, k)
+		imported, importCtx := setupTestKeeper(t)
+		attestation.InitGenesis(importCtx, imported, *exported)
+		active, err := imported.GetAttestationsByTags(importCtx, []string{"employment"}, false)
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		require.Equal(t, replacement, active[0].UID)
+	})
+}
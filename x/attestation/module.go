@@ -115,7 +115,9 @@ func (am AppModule) Name() string {
 }
 
 // RegisterInvariants registers module invariants
-func (am AppModule) RegisterInvariants(_ sdk.InvariantRegistry) {}
+func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {
+	keeper.RegisterInvariants(ir, am.keeper)
+}
 
 // RegisterServices registers module services
 // NOTE: Service registration is skipped because we use manual Go types instead of
@@ -149,6 +151,6 @@ func (am AppModule) BeginBlock(_ context.Context) error {
 }
 
 // EndBlock executes all ABCI EndBlock logic
-func (am AppModule) EndBlock(_ context.Context) error {
-	return nil
+func (am AppModule) EndBlock(ctx context.Context) error {
+	return am.keeper.EndBlock(sdk.UnwrapSDKContext(ctx))
 }
@@ -0,0 +1,28 @@
+package attestation
+
+import (
+	"github.com/cosmos/cosmos-sdk/types/module"
+	simtypes "github.com/cosmos/cosmos-sdk/types/simulation"
+
+	"github.com/chaincertify/certd/x/attestation/simulation"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+var _ module.AppModuleSimulation = AppModule{}
+
+// GenerateGenesisState creates a randomized genesis state for the attestation module
+func (AppModule) GenerateGenesisState(simState *module.SimulationState) {
+	genState := DefaultGenesisState()
+	genState.Params = simulation.RandomizedParams(simState.Rand)
+	simState.GenState[types.ModuleName] = simState.Cdc.MustMarshalJSON(genState)
+}
+
+// RegisterStoreDecoder registers a decoder for the attestation module's store
+func (AppModule) RegisterStoreDecoder(sdr simtypes.StoreDecoderRegistry) {
+	sdr[types.StoreKey] = simulation.NewDecodeStore()
+}
+
+// WeightedOperations returns the attestation module operations with their respective weights
+func (am AppModule) WeightedOperations(simState module.SimulationState) []simtypes.WeightedOperation {
+	return simulation.WeightedOperations(simState.AppParams, am.keeper)
+}
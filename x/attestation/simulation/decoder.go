@@ -0,0 +1,34 @@
+package simulation
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/types/kv"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// NewDecodeStore returns a decoder function closure that unmarshals the KVPair's
+// values for the attestation store
+func NewDecodeStore() func(kvA, kvB kv.Pair) string {
+	return func(kvA, kvB kv.Pair) string {
+		switch {
+		case bytes.Equal(kvA.Key, types.AttestationCountKey),
+			bytes.Equal(kvA.Key, types.EncryptedAttestationCountKey),
+			bytes.Equal(kvA.Key, types.BlockBatchRevocationCountKey):
+			return fmt.Sprintf("%d\n%d", types.BytesToUint64(kvA.Value), types.BytesToUint64(kvB.Value))
+
+		// Records and params are stored as JSON
+		case bytes.HasPrefix(kvA.Key, types.AttestationKeyPrefix),
+			bytes.HasPrefix(kvA.Key, types.SchemaKeyPrefix),
+			bytes.HasPrefix(kvA.Key, types.EncryptedAttestationKeyPrefix),
+			bytes.Equal(kvA.Key, types.ParamsKey):
+			return fmt.Sprintf("%s\n%s", kvA.Value, kvB.Value)
+
+		// Index values are markers or UIDs
+		default:
+			return fmt.Sprintf("%x\n%x", kvA.Value, kvB.Value)
+		}
+	}
+}
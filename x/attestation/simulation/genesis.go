@@ -0,0 +1,18 @@
+package simulation
+
+import (
+	"math/rand"
+
+	simtypes "github.com/cosmos/cosmos-sdk/types/simulation"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// RandomizedParams returns the default params with randomized limits
+func RandomizedParams(r *rand.Rand) types.Params {
+	params := types.DefaultParams()
+	params.MaxRecipientsPerAttestation = uint32(simtypes.RandIntBetween(r, 3, 51))
+	params.MaxBatchRevocationsPerBlock = uint32(simtypes.RandIntBetween(r, 1, types.DefaultMaxBatchRevocationsPerBlock+1))
+	params.MaxAttestationDataSize = uint64(simtypes.RandIntBetween(r, 4*1024, types.DefaultMaxAttestationDataSize+1))
+	return params
+}
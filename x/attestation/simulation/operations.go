@@ -0,0 +1,267 @@
+package simulation
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	simtypes "github.com/cosmos/cosmos-sdk/types/simulation"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+// Simulation operation weights constants
+const (
+	OpWeightMsgRegisterSchema             = "op_weight_msg_register_schema"
+	OpWeightMsgAttest                     = "op_weight_msg_attest"
+	OpWeightMsgRevoke                     = "op_weight_msg_revoke"
+	OpWeightMsgCreateEncryptedAttestation = "op_weight_msg_create_encrypted_attestation"
+
+	DefaultWeightMsgRegisterSchema             = 10
+	DefaultWeightMsgAttest                     = 50
+	DefaultWeightMsgRevoke                     = 20
+	DefaultWeightMsgCreateEncryptedAttestation = 20
+)
+
+// simFieldTypes are the schema field types random schemas are built from
+var simFieldTypes = []string{"bytes32", "uint64", "uint256", "bool", "string", "address", "bytes"}
+
+// simTags are the tags random attestations are labelled with
+var simTags = []string{"diploma", "kyc", "audit", "membership"}
+
+// WeightedOperations returns all the operations from the attestation module with their respective weights
+func WeightedOperations(appParams simtypes.AppParams, k keeper.Keeper) simulation.WeightedOperations {
+	var (
+		weightMsgRegisterSchema             int
+		weightMsgAttest                     int
+		weightMsgRevoke                     int
+		weightMsgCreateEncryptedAttestation int
+	)
+
+	appParams.GetOrGenerate(OpWeightMsgRegisterSchema, &weightMsgRegisterSchema, nil, func(_ *rand.Rand) {
+		weightMsgRegisterSchema = DefaultWeightMsgRegisterSchema
+	})
+	appParams.GetOrGenerate(OpWeightMsgAttest, &weightMsgAttest, nil, func(_ *rand.Rand) {
+		weightMsgAttest = DefaultWeightMsgAttest
+	})
+	appParams.GetOrGenerate(OpWeightMsgRevoke, &weightMsgRevoke, nil, func(_ *rand.Rand) {
+		weightMsgRevoke = DefaultWeightMsgRevoke
+	})
+	appParams.GetOrGenerate(OpWeightMsgCreateEncryptedAttestation, &weightMsgCreateEncryptedAttestation, nil, func(_ *rand.Rand) {
+		weightMsgCreateEncryptedAttestation = DefaultWeightMsgCreateEncryptedAttestation
+	})
+
+	return simulation.WeightedOperations{
+		simulation.NewWeightedOperation(weightMsgRegisterSchema, SimulateMsgRegisterSchema(k)),
+		simulation.NewWeightedOperation(weightMsgAttest, SimulateMsgAttest(k)),
+		simulation.NewWeightedOperation(weightMsgRevoke, SimulateMsgRevoke(k)),
+		simulation.NewWeightedOperation(weightMsgCreateEncryptedAttestation, SimulateMsgCreateEncryptedAttestation(k)),
+	}
+}
+
+// SimulateMsgRegisterSchema generates a MsgRegisterSchema with a random schema
+func SimulateMsgRegisterSchema(k keeper.Keeper) simtypes.Operation {
+	return func(r *rand.Rand, _ *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, _ string) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		creator, _ := simtypes.RandomAcc(r, accs)
+		msg := types.NewMsgRegisterSchema(creator.Address.String(), randomSchema(r), "", r.Intn(4) != 0)
+
+		return deliver(ctx, k, msg, types.TypeMsgRegisterSchema, func(server types.MsgServer, ctx sdk.Context) error {
+			_, err := server.RegisterSchema(ctx, msg)
+			return err
+		})
+	}
+}
+
+// SimulateMsgAttest generates a MsgAttest with data matching a random registered schema
+func SimulateMsgAttest(k keeper.Keeper) simtypes.Operation {
+	return func(r *rand.Rand, _ *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, _ string) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		schemas := k.GetAllSchemas(ctx)
+		if len(schemas) == 0 {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgAttest, "no schemas registered"), nil, nil
+		}
+		schema := schemas[r.Intn(len(schemas))]
+		data, err := randomSchemaData(r, schema.Schema)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgAttest, err.Error()), nil, nil
+		}
+
+		attester, _ := simtypes.RandomAcc(r, accs)
+		var recipient string
+		if r.Intn(5) != 0 {
+			acc, _ := simtypes.RandomAcc(r, accs)
+			recipient = acc.Address.String()
+		}
+		var expirationTime int64
+		if r.Intn(4) == 0 {
+			expirationTime = ctx.BlockTime().Add(time.Duration(simtypes.RandIntBetween(r, 1, 3600)) * time.Second).Unix()
+		}
+
+		msg := types.NewMsgAttest(attester.Address.String(), schema.UID, recipient, expirationTime, schema.Revocable && r.Intn(3) != 0, "", data)
+		if r.Intn(3) == 0 {
+			msg.Tags = []string{simTags[r.Intn(len(simTags))]}
+		}
+
+		return deliver(ctx, k, msg, types.TypeMsgAttest, func(server types.MsgServer, ctx sdk.Context) error {
+			_, err := server.Attest(ctx, msg)
+			return err
+		})
+	}
+}
+
+// SimulateMsgRevoke generates a MsgRevoke for a random revocable attestation, signed by its attester
+func SimulateMsgRevoke(k keeper.Keeper) simtypes.Operation {
+	return func(r *rand.Rand, _ *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, _ string) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		var candidates []types.Attestation
+		for _, attestation := range k.GetAllAttestations(ctx) {
+			if attestation.Revocable && attestation.RevocationTime.IsZero() {
+				candidates = append(candidates, attestation)
+			}
+		}
+		if len(candidates) == 0 {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRevoke, "no revocable attestations"), nil, nil
+		}
+		attestation := candidates[r.Intn(len(candidates))]
+		attester, ok := simtypes.FindAccount(accs, attestation.Attester)
+		if !ok {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRevoke, "attester is not a simulation account"), nil, nil
+		}
+
+		msg := types.NewMsgRevoke(attester.Address.String(), attestation.UID)
+		return deliver(ctx, k, msg, types.TypeMsgRevoke, func(server types.MsgServer, ctx sdk.Context) error {
+			_, err := server.Revoke(ctx, msg)
+			return err
+		})
+	}
+}
+
+// SimulateMsgCreateEncryptedAttestation generates a MsgCreateEncryptedAttestation
+// for up to three random recipients under a random registered schema
+func SimulateMsgCreateEncryptedAttestation(k keeper.Keeper) simtypes.Operation {
+	return func(r *rand.Rand, _ *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, _ string) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		schemas := k.GetAllSchemas(ctx)
+		if len(schemas) == 0 {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateEncryptedAttestation, "no schemas registered"), nil, nil
+		}
+		schema := schemas[r.Intn(len(schemas))]
+
+		attester, _ := simtypes.RandomAcc(r, accs)
+		n := simtypes.RandIntBetween(r, 1, min(3, len(accs))+1)
+		recipients := make([]string, n)
+		keys := make(map[string]string, n)
+		for i, j := range r.Perm(len(accs))[:n] {
+			recipients[i] = accs[j].Address.String()
+			keys[recipients[i]] = hex.EncodeToString(randomBytes(r, 32))
+		}
+		var expirationTime int64
+		if r.Intn(4) == 0 {
+			expirationTime = ctx.BlockTime().Add(time.Duration(simtypes.RandIntBetween(r, 1, 3600)) * time.Second).Unix()
+		}
+
+		msg := &types.MsgCreateEncryptedAttestation{
+			Attester:               attester.Address.String(),
+			SchemaUID:              schema.UID,
+			IPFSCID:                "Qm" + simtypes.RandStringOfLength(r, 44),
+			EncryptedDataHash:      hex.EncodeToString(randomBytes(r, 32)),
+			Recipients:             recipients,
+			EncryptedSymmetricKeys: keys,
+			Revocable:              schema.Revocable && r.Intn(2) == 0,
+			ExpirationTime:         expirationTime,
+		}
+		return deliver(ctx, k, msg, types.TypeMsgCreateEncryptedAttestation, func(server types.MsgServer, ctx sdk.Context) error {
+			_, err := server.CreateEncryptedAttestation(ctx, msg)
+			return err
+		})
+	}
+}
+
+// deliver validates msg and runs it through the msg server in a cached context,
+// committing its writes only on success. The module does not register its
+// services with the baseapp router, so operations call the msg server directly
+// instead of delivering signed transactions. Every generated msg is valid, so a
+// rejection is reported as an error.
+func deliver(
+	ctx sdk.Context,
+	k keeper.Keeper,
+	msg sdk.HasValidateBasic,
+	msgType string,
+	run func(types.MsgServer, sdk.Context) error,
+) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+	if err := msg.ValidateBasic(); err != nil {
+		return simtypes.NoOpMsg(types.ModuleName, msgType, "invalid msg"), nil, err
+	}
+
+	cacheCtx, write := ctx.CacheContext()
+	if err := run(keeper.NewMsgServerImpl(k), cacheCtx); err != nil {
+		return simtypes.NoOpMsg(types.ModuleName, msgType, "msg rejected"), nil, err
+	}
+	write()
+
+	return simtypes.NewOperationMsgBasic(types.ModuleName, msgType, "", true, nil), nil, nil
+}
+
+// randomSchema returns a schema of one to four random fields
+func randomSchema(r *rand.Rand) string {
+	n := simtypes.RandIntBetween(r, 1, 5)
+	schema := ""
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			schema += ","
+		}
+		schema += fmt.Sprintf("%s %s%d", simFieldTypes[r.Intn(len(simFieldTypes))], simtypes.RandStringOfLength(r, 8), i)
+	}
+	return schema
+}
+
+// randomSchemaData ABI-encodes a random value for each field of schema
+func randomSchemaData(r *rand.Rand, schema string) ([]byte, error) {
+	fields, err := types.ParseSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make(abi.Arguments, len(fields))
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		typ, err := abi.NewType(field.Type, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = abi.Argument{Name: field.Name, Type: typ}
+
+		switch field.Type {
+		case "bytes32":
+			var v [32]byte
+			copy(v[:], randomBytes(r, 32))
+			values[i] = v
+		case "uint64":
+			values[i] = r.Uint64()
+		case "uint256":
+			values[i] = new(big.Int).SetUint64(r.Uint64())
+		case "bool":
+			values[i] = r.Intn(2) == 0
+		case "string":
+			values[i] = simtypes.RandStringOfLength(r, r.Intn(40))
+		case "address":
+			values[i] = common.BytesToAddress(randomBytes(r, 20))
+		case "bytes":
+			values[i] = randomBytes(r, r.Intn(64))
+		default:
+			return nil, fmt.Errorf("unsupported schema field type %s", field.Type)
+		}
+	}
+	return args.Pack(values...)
+}
+
+func randomBytes(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	r.Read(b)
+	return b
+}
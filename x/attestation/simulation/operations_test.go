@@ -0,0 +1,98 @@
+package simulation_test
+
+import (
+	"crypto/sha256"
+	"math/rand"
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	simtypes "github.com/cosmos/cosmos-sdk/types/simulation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation"
+	"github.com/chaincertify/certd/x/attestation/keeper"
+	"github.com/chaincertify/certd/x/attestation/simulation"
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+const (
+	simBlocks      = 50
+	simOpsPerBlock = 20
+)
+
+// runSimulation executes weighted random operations for simBlocks blocks from
+// seed, checking the module invariants after every block. It returns the
+// number of successful operations by msg type and a digest of the final store.
+func runSimulation(t *testing.T, seed int64) (map[string]int, [32]byte) {
+	t.Helper()
+	r := rand.New(rand.NewSource(seed))
+
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test"))
+	k := keeper.NewKeeper(nil, storeKey, nil, "")
+
+	genState := attestation.DefaultGenesisState()
+	genState.Params = simulation.RandomizedParams(r)
+	attestation.InitGenesis(ctx, k, *genState)
+
+	accs := simtypes.RandomAccounts(r, 10)
+	ops := simulation.WeightedOperations(make(simtypes.AppParams), k)
+	totalWeight := 0
+	for _, op := range ops {
+		totalWeight += op.Weight()
+	}
+
+	invariant := keeper.AllInvariants(k)
+	delivered := make(map[string]int)
+	start := time.Unix(1_700_000_000, 0).UTC()
+	for height := int64(1); height <= simBlocks; height++ {
+		ctx = ctx.WithBlockHeight(height).WithBlockTime(start.Add(time.Duration(height) * time.Minute))
+
+		for i := 0; i < simOpsPerBlock; i++ {
+			pick := r.Intn(totalWeight)
+			for _, op := range ops {
+				if pick -= op.Weight(); pick < 0 {
+					opMsg, _, err := op.Op()(r, nil, ctx, accs, "")
+					require.NoError(t, err, "block %d: %s", height, opMsg.Name)
+					if opMsg.OK {
+						delivered[opMsg.Name]++
+					}
+					break
+				}
+			}
+		}
+		require.NoError(t, k.EndBlock(ctx))
+
+		msg, broken := invariant(ctx)
+		require.False(t, broken, "block %d: %s", height, msg)
+	}
+
+	hash := sha256.New()
+	iterator := ctx.KVStore(storeKey).Iterator(nil, nil)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		hash.Write(iterator.Key())
+		hash.Write(iterator.Value())
+	}
+	var digest [32]byte
+	copy(digest[:], hash.Sum(nil))
+	return delivered, digest
+}
+
+func TestSimulation(t *testing.T) {
+	delivered, digest := runSimulation(t, 42)
+	for _, msgType := range []string{
+		types.TypeMsgRegisterSchema,
+		types.TypeMsgAttest,
+		types.TypeMsgRevoke,
+		types.TypeMsgCreateEncryptedAttestation,
+	} {
+		require.Positive(t, delivered[msgType], "no %s operation succeeded", msgType)
+	}
+
+	// The same seed reproduces the same state
+	_, again := runSimulation(t, 42)
+	require.Equal(t, digest, again)
+}
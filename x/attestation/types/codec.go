@@ -12,7 +12,16 @@ func RegisterCodec(cdc *codec.LegacyAmino) {
 	cdc.RegisterConcrete(&MsgRegisterSchema{}, "cert/attestation/MsgRegisterSchema", nil)
 	cdc.RegisterConcrete(&MsgAttest{}, "cert/attestation/MsgAttest", nil)
 	cdc.RegisterConcrete(&MsgRevoke{}, "cert/attestation/MsgRevoke", nil)
+	cdc.RegisterConcrete(&MsgBatchRevoke{}, "cert/attestation/MsgBatchRevoke", nil)
+	cdc.RegisterConcrete(&MsgBatchAttest{}, "cert/attestation/MsgBatchAttest", nil)
+	cdc.RegisterConcrete(&MsgReassignAttestation{}, "cert/attestation/MsgReassignAttestation", nil)
 	cdc.RegisterConcrete(&MsgCreateEncryptedAttestation{}, "cert/attestation/MsgCreateEncryptedAttestation", nil)
+	cdc.RegisterConcrete(&MsgUpdateSchemaAllowlist{}, "cert/attestation/MsgUpdateSchemaAllowlist", nil)
+	cdc.RegisterConcrete(&MsgEndorseAttestation{}, "cert/attestation/MsgEndorseAttestation", nil)
+	cdc.RegisterConcrete(&MsgWithdrawFees{}, "cert/attestation/MsgWithdrawFees", nil)
+	cdc.RegisterConcrete(&MsgReaffirm{}, "cert/attestation/MsgReaffirm", nil)
+	cdc.RegisterConcrete(&MsgUpdateRetentionPolicy{}, "cert/attestation/MsgUpdateRetentionPolicy", nil)
+	cdc.RegisterConcrete(&MsgUpdateParams{}, "cert/attestation/MsgUpdateParams", nil)
 }
 
 // RegisterInterfaces registers the module types with the interface registry
@@ -32,10 +41,46 @@ func RegisterInterfaces(registry cdctypes.InterfaceRegistry) {
 		(*sdk.Msg)(nil),
 		&MsgRevoke{},
 	)
+	registry.RegisterImplementations(
+		(*sdk.Msg)(nil),
+		&MsgBatchRevoke{},
+	)
+	registry.RegisterImplementations(
+		(*sdk.Msg)(nil),
+		&MsgBatchAttest{},
+	)
+	registry.RegisterImplementations(
+		(*sdk.Msg)(nil),
+		&MsgReassignAttestation{},
+	)
 	registry.RegisterImplementations(
 		(*sdk.Msg)(nil),
 		&MsgCreateEncryptedAttestation{},
 	)
+	registry.RegisterImplementations(
+		(*sdk.Msg)(nil),
+		&MsgUpdateSchemaAllowlist{},
+	)
+	registry.RegisterImplementations(
+		(*sdk.Msg)(nil),
+		&MsgEndorseAttestation{},
+	)
+	registry.RegisterImplementations(
+		(*sdk.Msg)(nil),
+		&MsgWithdrawFees{},
+	)
+	registry.RegisterImplementations(
+		(*sdk.Msg)(nil),
+		&MsgReaffirm{},
+	)
+	registry.RegisterImplementations(
+		(*sdk.Msg)(nil),
+		&MsgUpdateRetentionPolicy{},
+	)
+	registry.RegisterImplementations(
+		(*sdk.Msg)(nil),
+		&MsgUpdateParams{},
+	)
 }
 
 var (
@@ -75,6 +120,22 @@ func registerProtoTypes() {
 	proto.RegisterType((*QueryEncryptedAttestationResponse)(nil), "cert.attestation.v1.QueryEncryptedAttestationResponse")
 	proto.RegisterType((*QueryStatsRequest)(nil), "cert.attestation.v1.QueryStatsRequest")
 	proto.RegisterType((*QueryStatsResponse)(nil), "cert.attestation.v1.QueryStatsResponse")
+	proto.RegisterType((*QueryAttestationChainRequest)(nil), "cert.attestation.v1.QueryAttestationChainRequest")
+	proto.RegisterType((*QueryAttestationChainResponse)(nil), "cert.attestation.v1.QueryAttestationChainResponse")
+	proto.RegisterType((*QueryEndorsementsRequest)(nil), "cert.attestation.v1.QueryEndorsementsRequest")
+	proto.RegisterType((*QueryEndorsementsResponse)(nil), "cert.attestation.v1.QueryEndorsementsResponse")
+	proto.RegisterType((*QueryAttestationsByDocumentHashRequest)(nil), "cert.attestation.v1.QueryAttestationsByDocumentHashRequest")
+	proto.RegisterType((*QueryAttestationsByDocumentHashResponse)(nil), "cert.attestation.v1.QueryAttestationsByDocumentHashResponse")
+	proto.RegisterType((*QueryAttestationsByTagRequest)(nil), "cert.attestation.v1.QueryAttestationsByTagRequest")
+	proto.RegisterType((*QueryAttestationsByTagResponse)(nil), "cert.attestation.v1.QueryAttestationsByTagResponse")
+	proto.RegisterType((*QueryExpiringAttestationsRequest)(nil), "cert.attestation.v1.QueryExpiringAttestationsRequest")
+	proto.RegisterType((*QueryExpiringAttestationsResponse)(nil), "cert.attestation.v1.QueryExpiringAttestationsResponse")
+	proto.RegisterType((*QueryRevocationsRequest)(nil), "cert.attestation.v1.QueryRevocationsRequest")
+	proto.RegisterType((*QueryRevocationsResponse)(nil), "cert.attestation.v1.QueryRevocationsResponse")
+	proto.RegisterType((*QueryFeeBalanceRequest)(nil), "cert.attestation.v1.QueryFeeBalanceRequest")
+	proto.RegisterType((*QueryFeeBalanceResponse)(nil), "cert.attestation.v1.QueryFeeBalanceResponse")
+	proto.RegisterType((*QueryParamsRequest)(nil), "cert.attestation.v1.QueryParamsRequest")
+	proto.RegisterType((*QueryParamsResponse)(nil), "cert.attestation.v1.QueryParamsResponse")
 
 	// Message types (Tx)
 	proto.RegisterType((*MsgRegisterSchema)(nil), "cert.attestation.v1.MsgRegisterSchema")
@@ -83,6 +144,25 @@ func registerProtoTypes() {
 	proto.RegisterType((*MsgAttestResponse)(nil), "cert.attestation.v1.MsgAttestResponse")
 	proto.RegisterType((*MsgRevoke)(nil), "cert.attestation.v1.MsgRevoke")
 	proto.RegisterType((*MsgRevokeResponse)(nil), "cert.attestation.v1.MsgRevokeResponse")
+	proto.RegisterType((*MsgBatchRevoke)(nil), "cert.attestation.v1.MsgBatchRevoke")
+	proto.RegisterType((*MsgBatchRevokeResponse)(nil), "cert.attestation.v1.MsgBatchRevokeResponse")
+	proto.RegisterType((*BatchAttestEntry)(nil), "cert.attestation.v1.BatchAttestEntry")
+	proto.RegisterType((*MsgBatchAttest)(nil), "cert.attestation.v1.MsgBatchAttest")
+	proto.RegisterType((*MsgBatchAttestResponse)(nil), "cert.attestation.v1.MsgBatchAttestResponse")
+	proto.RegisterType((*MsgReassignAttestation)(nil), "cert.attestation.v1.MsgReassignAttestation")
+	proto.RegisterType((*MsgReassignAttestationResponse)(nil), "cert.attestation.v1.MsgReassignAttestationResponse")
 	proto.RegisterType((*MsgCreateEncryptedAttestation)(nil), "cert.attestation.v1.MsgCreateEncryptedAttestation")
 	proto.RegisterType((*MsgCreateEncryptedAttestationResponse)(nil), "cert.attestation.v1.MsgCreateEncryptedAttestationResponse")
+	proto.RegisterType((*MsgUpdateSchemaAllowlist)(nil), "cert.attestation.v1.MsgUpdateSchemaAllowlist")
+	proto.RegisterType((*MsgUpdateSchemaAllowlistResponse)(nil), "cert.attestation.v1.MsgUpdateSchemaAllowlistResponse")
+	proto.RegisterType((*MsgEndorseAttestation)(nil), "cert.attestation.v1.MsgEndorseAttestation")
+	proto.RegisterType((*MsgEndorseAttestationResponse)(nil), "cert.attestation.v1.MsgEndorseAttestationResponse")
+	proto.RegisterType((*MsgWithdrawFees)(nil), "cert.attestation.v1.MsgWithdrawFees")
+	proto.RegisterType((*MsgWithdrawFeesResponse)(nil), "cert.attestation.v1.MsgWithdrawFeesResponse")
+	proto.RegisterType((*MsgReaffirm)(nil), "cert.attestation.v1.MsgReaffirm")
+	proto.RegisterType((*MsgReaffirmResponse)(nil), "cert.attestation.v1.MsgReaffirmResponse")
+	proto.RegisterType((*MsgUpdateRetentionPolicy)(nil), "cert.attestation.v1.MsgUpdateRetentionPolicy")
+	proto.RegisterType((*MsgUpdateRetentionPolicyResponse)(nil), "cert.attestation.v1.MsgUpdateRetentionPolicyResponse")
+	proto.RegisterType((*MsgUpdateParams)(nil), "cert.attestation.v1.MsgUpdateParams")
+	proto.RegisterType((*MsgUpdateParamsResponse)(nil), "cert.attestation.v1.MsgUpdateParamsResponse")
 }
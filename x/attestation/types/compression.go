@@ -0,0 +1,97 @@
+package types
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Public attestation data is stored DEFLATE-compressed when that makes it
+// smaller. The stored form is compressedDataPrefix (a marker byte followed by
+// a format version byte) and the raw DEFLATE stream; anything else is stored
+// verbatim. Callers only ever see uncompressed data: the keeper compresses on
+// write and decompresses on every read.
+//
+// Compressed bytes are consensus state, so every node must produce the same
+// bytes for the same data. The format is the standard library's compress/flate
+// at a fixed level, pinned by a golden test; a new encoding needs a new format
+// version rather than a change to this one.
+
+const (
+	// compressedDataMarker tags compressed attestation data
+	compressedDataMarker = 0xC1
+
+	// compressedDataFormatFlate is the format version of DEFLATE at
+	// compressedDataLevel
+	compressedDataFormatFlate = 0x01
+
+	// compressedDataLevel is the DEFLATE level of compressedDataFormatFlate
+	compressedDataLevel = 6
+
+	// MinCompressibleDataSize is the smallest data worth trying to compress
+	MinCompressibleDataSize = 128
+
+	// maxDecompressedDataSize bounds decompression regardless of params
+	maxDecompressedDataSize = 16 << 20
+)
+
+var compressedDataPrefix = []byte{compressedDataMarker, compressedDataFormatFlate}
+
+var dataEncoders = sync.Pool{
+	New: func() any {
+		w, _ := flate.NewWriter(nil, compressedDataLevel)
+		return w
+	},
+}
+
+// IsCompressedAttestationData reports whether stored data is in compressed form
+func IsCompressedAttestationData(stored []byte) bool {
+	return bytes.HasPrefix(stored, compressedDataPrefix)
+}
+
+// CompressAttestationData returns the stored form of data: compressed when that
+// is smaller, otherwise data itself. Data that happens to start with the
+// compressed prefix is always compressed so reads stay unambiguous.
+func CompressAttestationData(data []byte) []byte {
+	mustCompress := IsCompressedAttestationData(data)
+	if len(data) < MinCompressibleDataSize && !mustCompress {
+		return data
+	}
+	out := compressData(data)
+	if len(out) >= len(data) && !mustCompress {
+		return data
+	}
+	return out
+}
+
+// DecompressAttestationData returns the original data for its stored form
+func DecompressAttestationData(stored []byte) ([]byte, error) {
+	if !IsCompressedAttestationData(stored) {
+		return stored, nil
+	}
+	r := flate.NewReader(bytes.NewReader(stored[len(compressedDataPrefix):]))
+	defer r.Close()
+	data, err := io.ReadAll(io.LimitReader(r, maxDecompressedDataSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress attestation data: %w", err)
+	}
+	if len(data) > maxDecompressedDataSize {
+		return nil, fmt.Errorf("failed to decompress attestation data: exceeds %d bytes", maxDecompressedDataSize)
+	}
+	return data, nil
+}
+
+// compressData returns data in compressed stored form
+func compressData(data []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(compressedDataPrefix)
+	w := dataEncoders.Get().(*flate.Writer)
+	defer dataEncoders.Put(w)
+	w.Reset(&buf)
+	// Writes to a bytes.Buffer cannot fail
+	_, _ = w.Write(data)
+	_ = w.Close()
+	return buf.Bytes()
+}
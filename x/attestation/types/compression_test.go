@@ -0,0 +1,69 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressAttestationData(t *testing.T) {
+	t.Run("compresses large textual data", func(t *testing.T) {
+		data := []byte(strings.Repeat(`{"course":"Distributed Systems","grade":"A"},`, 200))
+		stored := CompressAttestationData(data)
+		require.True(t, IsCompressedAttestationData(stored))
+		require.Less(t, len(stored), len(data))
+
+		got, err := DecompressAttestationData(stored)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+	})
+
+	t.Run("stores incompressible data verbatim", func(t *testing.T) {
+		data := make([]byte, 4096)
+		rand.New(rand.NewSource(1)).Read(data)
+		stored := CompressAttestationData(data)
+		require.Equal(t, data, stored)
+
+		got, err := DecompressAttestationData(stored)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+	})
+
+	t.Run("leaves small data alone", func(t *testing.T) {
+		data := bytes.Repeat([]byte{'a'}, MinCompressibleDataSize-1)
+		require.Equal(t, data, CompressAttestationData(data))
+	})
+
+	t.Run("round-trips data that looks compressed", func(t *testing.T) {
+		data := append(append([]byte{}, compressedDataPrefix...), 'x')
+		stored := CompressAttestationData(data)
+		require.NotEqual(t, data, stored)
+
+		got, err := DecompressAttestationData(stored)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+	})
+
+	t.Run("matches the pinned encoding", func(t *testing.T) {
+		// Stored data is consensus state: this must never change for format
+		// 0x01. A different encoding needs a new format version.
+		data := []byte(strings.Repeat(`{"course":"Distributed Systems","grade":"A"},`, 20))
+		golden := "c101aa564ace2f2d2a4e55b25272c92c2e29ca4c2a2d494d5108ae2c2e49cd2d56d2514a2f4a4c01c93a2ad5ea8c2a1e553caa98ea8a010300"
+		require.Equal(t, golden, hex.EncodeToString(CompressAttestationData(data)))
+
+		stored, err := hex.DecodeString(golden)
+		require.NoError(t, err)
+		got, err := DecompressAttestationData(stored)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+	})
+
+	t.Run("rejects corrupt frames", func(t *testing.T) {
+		_, err := DecompressAttestationData(append(append([]byte{}, compressedDataPrefix...), 0, 1, 2))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// documentHashFields are the JSON data fields read as a document hash, in order
+var documentHashFields = []string{"document_hash", "documentHash", "doc_hash"}
+
+// NormalizeDocumentHash returns hash as lowercase hex without a 0x prefix,
+// or false if it is not a SHA-256 digest
+func NormalizeDocumentHash(hash string) (string, bool) {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	hash = strings.TrimPrefix(hash, "0x")
+	if len(hash) != 64 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", false
+	}
+	return hash, true
+}
+
+// DocumentHashFromData extracts the document hash an attestation's data carries:
+// a raw 32-byte digest, a hex digest, or a JSON object with a document_hash field
+func DocumentHashFromData(data []byte) (string, bool) {
+	if len(data) == 32 {
+		return hex.EncodeToString(data), true
+	}
+	if hash, ok := NormalizeDocumentHash(string(data)); ok {
+		return hash, true
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", false
+	}
+	for _, name := range documentHashFields {
+		var hash string
+		if raw, ok := fields[name]; ok && json.Unmarshal(raw, &hash) == nil {
+			if normalized, ok := NormalizeDocumentHash(hash); ok {
+				return normalized, true
+			}
+		}
+	}
+	return "", false
+}
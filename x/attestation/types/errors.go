@@ -2,6 +2,7 @@ package types
 
 import (
 	"cosmossdk.io/errors"
+	"google.golang.org/grpc/codes"
 )
 
 // Module errors
@@ -10,10 +11,10 @@ var (
 	ErrInvalidParams = errors.Register(ModuleName, 1, "invalid params")
 
 	// ErrSchemaNotFound is returned when a schema is not found
-	ErrSchemaNotFound = errors.Register(ModuleName, 2, "schema not found")
+	ErrSchemaNotFound = errors.RegisterWithGRPCCode(ModuleName, 2, codes.NotFound, "schema not found")
 
 	// ErrAttestationNotFound is returned when an attestation is not found
-	ErrAttestationNotFound = errors.Register(ModuleName, 3, "attestation not found")
+	ErrAttestationNotFound = errors.RegisterWithGRPCCode(ModuleName, 3, codes.NotFound, "attestation not found")
 
 	// ErrUnauthorized is returned when an action is not authorized
 	ErrUnauthorized = errors.Register(ModuleName, 4, "unauthorized")
@@ -47,5 +48,47 @@ var (
 
 	// ErrInvalidSchemaFormat is returned when schema format is invalid
 	ErrInvalidSchemaFormat = errors.Register(ModuleName, 14, "invalid schema format")
+
+	// ErrInvalidAttestationData is returned when attestation data does not match its schema
+	ErrInvalidAttestationData = errors.Register(ModuleName, 15, "attestation data does not match schema")
+
+	// ErrRevocationLimitExceeded is returned when a batch revocation exceeds the per-block cap
+	ErrRevocationLimitExceeded = errors.Register(ModuleName, 16, "per-block revocation limit exceeded")
+
+	// ErrInvalidReassignment is returned when an attestation cannot be reassigned
+	ErrInvalidReassignment = errors.Register(ModuleName, 17, "invalid attestation reassignment")
+
+	// ErrSchemaNotAllowed is returned when schema restriction is on and the schema's creator is not allowlisted
+	ErrSchemaNotAllowed = errors.Register(ModuleName, 18, "schema creator not allowlisted")
+
+	// ErrInvalidEndorsement is returned when an attestation cannot be endorsed
+	ErrInvalidEndorsement = errors.Register(ModuleName, 19, "invalid endorsement")
+
+	// ErrSelfEndorsement is returned when the attester or recipient endorses their own attestation
+	ErrSelfEndorsement = errors.Register(ModuleName, 20, "self-endorsement not allowed")
+
+	// ErrAttestationDataTooLarge is returned when attestation data exceeds MaxAttestationDataSize
+	ErrAttestationDataTooLarge = errors.Register(ModuleName, 21, "attestation data too large")
+
+	// ErrInvalidDocumentHash is returned when a document hash is not a hex SHA-256 digest
+	ErrInvalidDocumentHash = errors.Register(ModuleName, 22, "invalid document hash")
+
+	// ErrInvalidTags is returned when attestation tags exceed the caps or contain invalid characters
+	ErrInvalidTags = errors.Register(ModuleName, 23, "invalid attestation tags")
+
+	// ErrInvalidSchemaFee is returned when a schema fee or creator share is malformed or above the allowed cut
+	ErrInvalidSchemaFee = errors.Register(ModuleName, 24, "invalid schema fee")
+
+	// ErrSchemaFeePayment is returned when an attester cannot pay a schema's fee
+	ErrSchemaFeePayment = errors.Register(ModuleName, 25, "schema fee payment failed")
+
+	// ErrInvalidFeeWithdrawal is returned when a module fee withdrawal has no valid destination or amount
+	ErrInvalidFeeWithdrawal = errors.Register(ModuleName, 26, "invalid fee withdrawal")
+
+	// ErrReaffirmationNotRequired is returned when reaffirming an attestation whose schema has no MaxStaleness
+	ErrReaffirmationNotRequired = errors.Register(ModuleName, 27, "schema does not require reaffirmation")
+
+	// ErrEncryptedFileTooLarge is returned when an encrypted file exceeds MaxEncryptedFileSize
+	ErrEncryptedFileTooLarge = errors.Register(ModuleName, 28, "encrypted file too large")
 )
 
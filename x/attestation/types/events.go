@@ -5,7 +5,20 @@ const (
 	EventTypeSchemaRegistered           = "schema_registered"
 	EventTypeAttestationCreated         = "attestation_created"
 	EventTypeAttestationRevoked         = "attestation_revoked"
+	EventTypeAttestationsBatchRevoked   = "attestations_batch_revoked"
+	EventTypeAttestationsBatchCreated   = "attestations_batch_created"
+	EventTypeAttestationReassigned      = "attestation_reassigned"
 	EventTypeEncryptedAttestationCreated = "encrypted_attestation_created"
+	EventTypeSchemaAllowlistUpdated     = "schema_allowlist_updated"
+	EventTypeAttestationEndorsed        = "attestation_endorsed"
+	EventTypeSchemaFeePaid              = "schema_fee_paid"
+	EventTypeAttestationFeePaid         = "attestation_fee_paid"
+	EventTypeFeesWithdrawn              = "fees_withdrawn"
+	EventTypeAttestationExpired         = "attestation_expired"
+	EventTypeAttestationReaffirmed      = "attestation_reaffirmed"
+	EventTypeAttestationPruned          = "attestation_pruned"
+	EventTypeRetentionPolicyUpdated     = "retention_policy_updated"
+	EventTypeParamsUpdated              = "params_updated"
 )
 
 // Attribute keys for attestation events
@@ -20,5 +33,25 @@ const (
 	AttributeKeyAttestationType = "attestation_type"
 	AttributeKeyIPFSCID         = "ipfs_cid"
 	AttributeKeyRecipientsCount = "recipients_count"
+	AttributeKeyAttestationUIDs = "attestation_uids"
+	AttributeKeyRevokedCount    = "revoked_count"
+	AttributeKeyCreatedCount    = "created_count"
+	AttributeKeyPreviousUID     = "previous_uid"
+	AttributeKeyRestricted      = "restricted"
+	AttributeKeyCreatorsCount   = "creators_count"
+	AttributeKeyEndorser        = "endorser"
+	AttributeKeyEndorsedUID     = "endorsed_uid"
+	AttributeKeyFee             = "fee"
+	AttributeKeyCreatorShare    = "creator_share"
+	AttributeKeyModuleShare     = "module_share"
+	AttributeKeyAmount          = "amount"
+	AttributeKeyBurned          = "burned"
+	AttributeKeyExpirationTime  = "expiration_time"
+	AttributeKeyMaxStaleness    = "max_staleness"
+	AttributeKeyReaffirmedTime  = "reaffirmed_time"
+	AttributeKeyRevoked         = "revoked"
+	AttributeKeyRetentionPeriod = "retention_period"
+	AttributeKeyMaxPrunes       = "max_prunes_per_block"
+	AttributeKeyAuthority       = "authority"
 )
 
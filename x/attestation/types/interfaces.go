@@ -3,6 +3,7 @@ package types
 import (
 	"context"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"google.golang.org/grpc"
 )
 
@@ -17,8 +18,35 @@ type MsgServer interface {
 	// Revoke revokes an existing attestation
 	Revoke(context.Context, *MsgRevoke) (*MsgRevokeResponse, error)
 
+	// BatchRevoke atomically revokes several attestations
+	BatchRevoke(context.Context, *MsgBatchRevoke) (*MsgBatchRevokeResponse, error)
+
+	// BatchAttest atomically creates several public attestations under one schema
+	BatchAttest(context.Context, *MsgBatchAttest) (*MsgBatchAttestResponse, error)
+
+	// ReassignAttestation reissues an attestation to a new recipient and revokes the old one
+	ReassignAttestation(context.Context, *MsgReassignAttestation) (*MsgReassignAttestationResponse, error)
+
 	// CreateEncryptedAttestation creates a new encrypted attestation
 	CreateEncryptedAttestation(context.Context, *MsgCreateEncryptedAttestation) (*MsgCreateEncryptedAttestationResponse, error)
+
+	// UpdateSchemaAllowlist toggles schema restriction and edits the allowlisted schema creators
+	UpdateSchemaAllowlist(context.Context, *MsgUpdateSchemaAllowlist) (*MsgUpdateSchemaAllowlistResponse, error)
+
+	// EndorseAttestation countersigns an existing attestation
+	EndorseAttestation(context.Context, *MsgEndorseAttestation) (*MsgEndorseAttestationResponse, error)
+
+	// WithdrawFees burns or pays out schema fees held by the module account
+	WithdrawFees(context.Context, *MsgWithdrawFees) (*MsgWithdrawFeesResponse, error)
+
+	// Reaffirm refreshes an attestation's affirmation time so it is no longer stale
+	Reaffirm(context.Context, *MsgReaffirm) (*MsgReaffirmResponse, error)
+
+	// UpdateRetentionPolicy sets how long revoked and expired attestations are kept before pruning
+	UpdateRetentionPolicy(context.Context, *MsgUpdateRetentionPolicy) (*MsgUpdateRetentionPolicyResponse, error)
+
+	// UpdateParams replaces the module parameters
+	UpdateParams(context.Context, *MsgUpdateParams) (*MsgUpdateParamsResponse, error)
 }
 
 // MsgRegisterSchemaResponse is the response for MsgRegisterSchema
@@ -46,6 +74,33 @@ func (m *MsgRevokeResponse) Reset()         { *m = MsgRevokeResponse{} }
 func (m *MsgRevokeResponse) String() string { return "MsgRevokeResponse" }
 func (m *MsgRevokeResponse) ProtoMessage()  {}
 
+// MsgBatchRevokeResponse is the response for MsgBatchRevoke
+type MsgBatchRevokeResponse struct {
+	Revoked uint32 `json:"revoked" protobuf:"varint,1,opt,name=revoked,proto3"`
+}
+
+func (m *MsgBatchRevokeResponse) Reset()         { *m = MsgBatchRevokeResponse{} }
+func (m *MsgBatchRevokeResponse) String() string { return "MsgBatchRevokeResponse" }
+func (m *MsgBatchRevokeResponse) ProtoMessage()  {}
+
+// MsgBatchAttestResponse is the response for MsgBatchAttest
+type MsgBatchAttestResponse struct {
+	Uids []string `json:"uids" protobuf:"bytes,1,rep,name=uids,proto3"`
+}
+
+func (m *MsgBatchAttestResponse) Reset()         { *m = MsgBatchAttestResponse{} }
+func (m *MsgBatchAttestResponse) String() string { return "MsgBatchAttestResponse" }
+func (m *MsgBatchAttestResponse) ProtoMessage()  {}
+
+// MsgReassignAttestationResponse is the response for MsgReassignAttestation
+type MsgReassignAttestationResponse struct {
+	Uid string `json:"uid" protobuf:"bytes,1,opt,name=uid,proto3"`
+}
+
+func (m *MsgReassignAttestationResponse) Reset()         { *m = MsgReassignAttestationResponse{} }
+func (m *MsgReassignAttestationResponse) String() string { return m.Uid }
+func (m *MsgReassignAttestationResponse) ProtoMessage()  {}
+
 // MsgCreateEncryptedAttestationResponse is the response for MsgCreateEncryptedAttestation
 type MsgCreateEncryptedAttestationResponse struct {
 	Uid string `json:"uid" protobuf:"bytes,1,opt,name=uid,proto3"`
@@ -55,6 +110,52 @@ func (m *MsgCreateEncryptedAttestationResponse) Reset()         { *m = MsgCreate
 func (m *MsgCreateEncryptedAttestationResponse) String() string { return m.Uid }
 func (m *MsgCreateEncryptedAttestationResponse) ProtoMessage()  {}
 
+// MsgUpdateSchemaAllowlistResponse is the response for MsgUpdateSchemaAllowlist
+type MsgUpdateSchemaAllowlistResponse struct{}
+
+func (m *MsgUpdateSchemaAllowlistResponse) Reset()         { *m = MsgUpdateSchemaAllowlistResponse{} }
+func (m *MsgUpdateSchemaAllowlistResponse) String() string { return "MsgUpdateSchemaAllowlistResponse" }
+func (m *MsgUpdateSchemaAllowlistResponse) ProtoMessage()  {}
+
+// MsgEndorseAttestationResponse is the response for MsgEndorseAttestation
+type MsgEndorseAttestationResponse struct {
+	Uid string `json:"uid" protobuf:"bytes,1,opt,name=uid,proto3"`
+}
+
+func (m *MsgEndorseAttestationResponse) Reset()         { *m = MsgEndorseAttestationResponse{} }
+func (m *MsgEndorseAttestationResponse) String() string { return m.Uid }
+func (m *MsgEndorseAttestationResponse) ProtoMessage()  {}
+
+// MsgWithdrawFeesResponse is the response for MsgWithdrawFees
+type MsgWithdrawFeesResponse struct {
+	Amount sdk.Coins `json:"amount" protobuf:"bytes,1,rep,name=amount,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins"`
+}
+
+func (m *MsgWithdrawFeesResponse) Reset()         { *m = MsgWithdrawFeesResponse{} }
+func (m *MsgWithdrawFeesResponse) String() string { return m.Amount.String() }
+func (m *MsgWithdrawFeesResponse) ProtoMessage()  {}
+
+// MsgReaffirmResponse is the response for MsgReaffirm
+type MsgReaffirmResponse struct{}
+
+func (m *MsgReaffirmResponse) Reset()         { *m = MsgReaffirmResponse{} }
+func (m *MsgReaffirmResponse) String() string { return "MsgReaffirmResponse" }
+func (m *MsgReaffirmResponse) ProtoMessage()  {}
+
+// MsgUpdateRetentionPolicyResponse is the response for MsgUpdateRetentionPolicy
+type MsgUpdateRetentionPolicyResponse struct{}
+
+func (m *MsgUpdateRetentionPolicyResponse) Reset()         { *m = MsgUpdateRetentionPolicyResponse{} }
+func (m *MsgUpdateRetentionPolicyResponse) String() string { return "MsgUpdateRetentionPolicyResponse" }
+func (m *MsgUpdateRetentionPolicyResponse) ProtoMessage()  {}
+
+// MsgUpdateParamsResponse is the response for MsgUpdateParams
+type MsgUpdateParamsResponse struct{}
+
+func (m *MsgUpdateParamsResponse) Reset()         { *m = MsgUpdateParamsResponse{} }
+func (m *MsgUpdateParamsResponse) String() string { return "MsgUpdateParamsResponse" }
+func (m *MsgUpdateParamsResponse) ProtoMessage()  {}
+
 // QueryServer defines the attestation module's gRPC query service
 type QueryServer interface {
 	// Schema queries a schema by UID
@@ -74,6 +175,30 @@ type QueryServer interface {
 
 	// Stats returns attestation statistics
 	Stats(context.Context, *QueryStatsRequest) (*QueryStatsResponse, error)
+
+	// AttestationChain returns the reassignment chain an attestation belongs to
+	AttestationChain(context.Context, *QueryAttestationChainRequest) (*QueryAttestationChainResponse, error)
+
+	// Endorsements returns the endorsements of an attestation
+	Endorsements(context.Context, *QueryEndorsementsRequest) (*QueryEndorsementsResponse, error)
+
+	// AttestationsByDocumentHash returns the attestations over a document
+	AttestationsByDocumentHash(context.Context, *QueryAttestationsByDocumentHashRequest) (*QueryAttestationsByDocumentHashResponse, error)
+
+	// AttestationsByTag returns the attestations carrying every requested tag
+	AttestationsByTag(context.Context, *QueryAttestationsByTagRequest) (*QueryAttestationsByTagResponse, error)
+
+	// ExpiringAttestations returns unrevoked attestations expiring within a time window
+	ExpiringAttestations(context.Context, *QueryExpiringAttestationsRequest) (*QueryExpiringAttestationsResponse, error)
+
+	// Revocations returns the attestations an attester has revoked
+	Revocations(context.Context, *QueryRevocationsRequest) (*QueryRevocationsResponse, error)
+
+	// FeeBalance returns the schema fees held by the module account
+	FeeBalance(context.Context, *QueryFeeBalanceRequest) (*QueryFeeBalanceResponse, error)
+
+	// Params returns the module parameters
+	Params(context.Context, *QueryParamsRequest) (*QueryParamsResponse, error)
 }
 
 // Query request/response types
@@ -196,6 +321,166 @@ func (m *QueryStatsResponse) Reset()         { *m = QueryStatsResponse{} }
 func (m *QueryStatsResponse) String() string { return "QueryStatsResponse" }
 func (m *QueryStatsResponse) ProtoMessage()  {}
 
+// QueryAttestationChainRequest is the request type for Query/AttestationChain
+type QueryAttestationChainRequest struct {
+	Uid string `json:"uid" protobuf:"bytes,1,opt,name=uid,proto3"`
+}
+
+func (m *QueryAttestationChainRequest) Reset()         { *m = QueryAttestationChainRequest{} }
+func (m *QueryAttestationChainRequest) String() string { return m.Uid }
+func (m *QueryAttestationChainRequest) ProtoMessage()  {}
+
+// QueryAttestationChainResponse is the response type for Query/AttestationChain.
+// Attestations are ordered from the original issuance to the current one.
+type QueryAttestationChainResponse struct {
+	Attestations []Attestation `json:"attestations" protobuf:"bytes,1,rep,name=attestations,proto3"`
+}
+
+func (m *QueryAttestationChainResponse) Reset()         { *m = QueryAttestationChainResponse{} }
+func (m *QueryAttestationChainResponse) String() string { return "QueryAttestationChainResponse" }
+func (m *QueryAttestationChainResponse) ProtoMessage()  {}
+
+// QueryEndorsementsRequest is the request type for Query/Endorsements
+type QueryEndorsementsRequest struct {
+	Uid string `json:"uid" protobuf:"bytes,1,opt,name=uid,proto3"`
+}
+
+func (m *QueryEndorsementsRequest) Reset()         { *m = QueryEndorsementsRequest{} }
+func (m *QueryEndorsementsRequest) String() string { return m.Uid }
+func (m *QueryEndorsementsRequest) ProtoMessage()  {}
+
+// QueryEndorsementsResponse is the response type for Query/Endorsements.
+// Revoked endorsements are included; check revocation_time.
+type QueryEndorsementsResponse struct {
+	Attestations []Attestation `json:"attestations" protobuf:"bytes,1,rep,name=attestations,proto3"`
+}
+
+func (m *QueryEndorsementsResponse) Reset()         { *m = QueryEndorsementsResponse{} }
+func (m *QueryEndorsementsResponse) String() string { return "QueryEndorsementsResponse" }
+func (m *QueryEndorsementsResponse) ProtoMessage()  {}
+
+// QueryAttestationsByDocumentHashRequest is the request type for Query/AttestationsByDocumentHash
+type QueryAttestationsByDocumentHashRequest struct {
+	DocumentHash   string `json:"document_hash" protobuf:"bytes,1,opt,name=document_hash,proto3"`
+	IncludeRevoked bool   `json:"include_revoked" protobuf:"varint,2,opt,name=include_revoked,proto3"`
+}
+
+func (m *QueryAttestationsByDocumentHashRequest) Reset() {
+	*m = QueryAttestationsByDocumentHashRequest{}
+}
+func (m *QueryAttestationsByDocumentHashRequest) String() string { return m.DocumentHash }
+func (m *QueryAttestationsByDocumentHashRequest) ProtoMessage()  {}
+
+// QueryAttestationsByDocumentHashResponse is the response type for Query/AttestationsByDocumentHash.
+// Revoked attestations are only included when requested; check revocation_time.
+type QueryAttestationsByDocumentHashResponse struct {
+	Attestations []Attestation `json:"attestations" protobuf:"bytes,1,rep,name=attestations,proto3"`
+}
+
+func (m *QueryAttestationsByDocumentHashResponse) Reset() {
+	*m = QueryAttestationsByDocumentHashResponse{}
+}
+func (m *QueryAttestationsByDocumentHashResponse) String() string {
+	return "QueryAttestationsByDocumentHashResponse"
+}
+func (m *QueryAttestationsByDocumentHashResponse) ProtoMessage() {}
+
+// QueryAttestationsByTagRequest is the request type for Query/AttestationsByTag.
+// Attestations must carry all of Tags; Tag is shorthand for a single tag.
+type QueryAttestationsByTagRequest struct {
+	Tag            string   `json:"tag,omitempty" protobuf:"bytes,1,opt,name=tag,proto3"`
+	Tags           []string `json:"tags,omitempty" protobuf:"bytes,2,rep,name=tags,proto3"`
+	IncludeRevoked bool     `json:"include_revoked" protobuf:"varint,3,opt,name=include_revoked,proto3"`
+}
+
+func (m *QueryAttestationsByTagRequest) Reset()         { *m = QueryAttestationsByTagRequest{} }
+func (m *QueryAttestationsByTagRequest) String() string { return m.Tag }
+func (m *QueryAttestationsByTagRequest) ProtoMessage()  {}
+
+// QueryAttestationsByTagResponse is the response type for Query/AttestationsByTag
+type QueryAttestationsByTagResponse struct {
+	Attestations []Attestation `json:"attestations" protobuf:"bytes,1,rep,name=attestations,proto3"`
+}
+
+func (m *QueryAttestationsByTagResponse) Reset()         { *m = QueryAttestationsByTagResponse{} }
+func (m *QueryAttestationsByTagResponse) String() string { return "QueryAttestationsByTagResponse" }
+func (m *QueryAttestationsByTagResponse) ProtoMessage()  {}
+
+// QueryExpiringAttestationsRequest is the request type for Query/ExpiringAttestations.
+// From and To are unix seconds, both inclusive; Limit 0 uses DefaultExpiringAttestationsLimit.
+type QueryExpiringAttestationsRequest struct {
+	From  int64  `json:"from" protobuf:"varint,1,opt,name=from,proto3"`
+	To    int64  `json:"to" protobuf:"varint,2,opt,name=to,proto3"`
+	Limit uint32 `json:"limit,omitempty" protobuf:"varint,3,opt,name=limit,proto3"`
+}
+
+func (m *QueryExpiringAttestationsRequest) Reset()         { *m = QueryExpiringAttestationsRequest{} }
+func (m *QueryExpiringAttestationsRequest) String() string { return "QueryExpiringAttestationsRequest" }
+func (m *QueryExpiringAttestationsRequest) ProtoMessage()  {}
+
+// QueryExpiringAttestationsResponse is the response type for Query/ExpiringAttestations, soonest expiry first
+type QueryExpiringAttestationsResponse struct {
+	Attestations []Attestation `json:"attestations" protobuf:"bytes,1,rep,name=attestations,proto3"`
+}
+
+func (m *QueryExpiringAttestationsResponse) Reset() { *m = QueryExpiringAttestationsResponse{} }
+func (m *QueryExpiringAttestationsResponse) String() string {
+	return "QueryExpiringAttestationsResponse"
+}
+func (m *QueryExpiringAttestationsResponse) ProtoMessage() {}
+
+// QueryRevocationsRequest is the request type for Query/Revocations
+type QueryRevocationsRequest struct {
+	Attester string `json:"attester" protobuf:"bytes,1,opt,name=attester,proto3"`
+}
+
+func (m *QueryRevocationsRequest) Reset()         { *m = QueryRevocationsRequest{} }
+func (m *QueryRevocationsRequest) String() string { return m.Attester }
+func (m *QueryRevocationsRequest) ProtoMessage()  {}
+
+// QueryRevocationsResponse is the response type for Query/Revocations, oldest revocation first
+type QueryRevocationsResponse struct {
+	Revocations []Revocation `json:"revocations" protobuf:"bytes,1,rep,name=revocations,proto3"`
+}
+
+func (m *QueryRevocationsResponse) Reset()         { *m = QueryRevocationsResponse{} }
+func (m *QueryRevocationsResponse) String() string { return "QueryRevocationsResponse" }
+func (m *QueryRevocationsResponse) ProtoMessage()  {}
+
+// QueryFeeBalanceRequest is the request type for Query/FeeBalance
+type QueryFeeBalanceRequest struct{}
+
+func (m *QueryFeeBalanceRequest) Reset()         { *m = QueryFeeBalanceRequest{} }
+func (m *QueryFeeBalanceRequest) String() string { return "QueryFeeBalanceRequest" }
+func (m *QueryFeeBalanceRequest) ProtoMessage()  {}
+
+// QueryFeeBalanceResponse is the response type for Query/FeeBalance
+type QueryFeeBalanceResponse struct {
+	Address  string    `json:"address" protobuf:"bytes,1,opt,name=address,proto3"`
+	FeeDenom string    `json:"fee_denom" protobuf:"bytes,2,opt,name=fee_denom,proto3"`
+	Balance  sdk.Coins `json:"balance" protobuf:"bytes,3,rep,name=balance,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins"`
+}
+
+func (m *QueryFeeBalanceResponse) Reset()         { *m = QueryFeeBalanceResponse{} }
+func (m *QueryFeeBalanceResponse) String() string { return m.Balance.String() }
+func (m *QueryFeeBalanceResponse) ProtoMessage()  {}
+
+// QueryParamsRequest is the request type for Query/Params
+type QueryParamsRequest struct{}
+
+func (m *QueryParamsRequest) Reset()         { *m = QueryParamsRequest{} }
+func (m *QueryParamsRequest) String() string { return "QueryParamsRequest" }
+func (m *QueryParamsRequest) ProtoMessage()  {}
+
+// QueryParamsResponse is the response type for Query/Params
+type QueryParamsResponse struct {
+	Params Params `json:"params" protobuf:"bytes,1,opt,name=params,proto3"`
+}
+
+func (m *QueryParamsResponse) Reset()         { *m = QueryParamsResponse{} }
+func (m *QueryParamsResponse) String() string { return "QueryParamsResponse" }
+func (m *QueryParamsResponse) ProtoMessage()  {}
+
 // RegisterMsgServer registers the MsgServer implementation with the gRPC server
 func RegisterMsgServer(s grpc.ServiceRegistrar, srv MsgServer) {
 	s.RegisterService(&_Msg_serviceDesc, srv)
@@ -223,10 +508,46 @@ var _Msg_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Revoke",
 			Handler:    _Msg_Revoke_Handler,
 		},
+		{
+			MethodName: "BatchRevoke",
+			Handler:    _Msg_BatchRevoke_Handler,
+		},
+		{
+			MethodName: "BatchAttest",
+			Handler:    _Msg_BatchAttest_Handler,
+		},
+		{
+			MethodName: "ReassignAttestation",
+			Handler:    _Msg_ReassignAttestation_Handler,
+		},
 		{
 			MethodName: "CreateEncryptedAttestation",
 			Handler:    _Msg_CreateEncryptedAttestation_Handler,
 		},
+		{
+			MethodName: "UpdateSchemaAllowlist",
+			Handler:    _Msg_UpdateSchemaAllowlist_Handler,
+		},
+		{
+			MethodName: "EndorseAttestation",
+			Handler:    _Msg_EndorseAttestation_Handler,
+		},
+		{
+			MethodName: "WithdrawFees",
+			Handler:    _Msg_WithdrawFees_Handler,
+		},
+		{
+			MethodName: "Reaffirm",
+			Handler:    _Msg_Reaffirm_Handler,
+		},
+		{
+			MethodName: "UpdateRetentionPolicy",
+			Handler:    _Msg_UpdateRetentionPolicy_Handler,
+		},
+		{
+			MethodName: "UpdateParams",
+			Handler:    _Msg_UpdateParams_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "cert/attestation/v1/tx.proto",
@@ -260,6 +581,38 @@ var _Query_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Stats",
 			Handler:    _Query_Stats_Handler,
 		},
+		{
+			MethodName: "AttestationChain",
+			Handler:    _Query_AttestationChain_Handler,
+		},
+		{
+			MethodName: "Endorsements",
+			Handler:    _Query_Endorsements_Handler,
+		},
+		{
+			MethodName: "AttestationsByDocumentHash",
+			Handler:    _Query_AttestationsByDocumentHash_Handler,
+		},
+		{
+			MethodName: "AttestationsByTag",
+			Handler:    _Query_AttestationsByTag_Handler,
+		},
+		{
+			MethodName: "ExpiringAttestations",
+			Handler:    _Query_ExpiringAttestations_Handler,
+		},
+		{
+			MethodName: "Revocations",
+			Handler:    _Query_Revocations_Handler,
+		},
+		{
+			MethodName: "FeeBalance",
+			Handler:    _Query_FeeBalance_Handler,
+		},
+		{
+			MethodName: "Params",
+			Handler:    _Query_Params_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "cert/attestation/v1/query.proto",
@@ -320,6 +673,60 @@ func _Msg_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Msg_BatchRevoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgBatchRevoke)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).BatchRevoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Msg/BatchRevoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).BatchRevoke(ctx, req.(*MsgBatchRevoke))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_BatchAttest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgBatchAttest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).BatchAttest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Msg/BatchAttest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).BatchAttest(ctx, req.(*MsgBatchAttest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_ReassignAttestation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgReassignAttestation)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).ReassignAttestation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Msg/ReassignAttestation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).ReassignAttestation(ctx, req.(*MsgReassignAttestation))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Msg_CreateEncryptedAttestation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(MsgCreateEncryptedAttestation)
 	if err := dec(in); err != nil {
@@ -338,6 +745,114 @@ func _Msg_CreateEncryptedAttestation_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Msg_UpdateSchemaAllowlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgUpdateSchemaAllowlist)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).UpdateSchemaAllowlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Msg/UpdateSchemaAllowlist",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).UpdateSchemaAllowlist(ctx, req.(*MsgUpdateSchemaAllowlist))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_EndorseAttestation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgEndorseAttestation)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).EndorseAttestation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Msg/EndorseAttestation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).EndorseAttestation(ctx, req.(*MsgEndorseAttestation))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_WithdrawFees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgWithdrawFees)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).WithdrawFees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Msg/WithdrawFees",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).WithdrawFees(ctx, req.(*MsgWithdrawFees))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_Reaffirm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgReaffirm)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).Reaffirm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Msg/Reaffirm",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).Reaffirm(ctx, req.(*MsgReaffirm))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_UpdateRetentionPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgUpdateRetentionPolicy)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).UpdateRetentionPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Msg/UpdateRetentionPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).UpdateRetentionPolicy(ctx, req.(*MsgUpdateRetentionPolicy))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_UpdateParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgUpdateParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).UpdateParams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Msg/UpdateParams",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).UpdateParams(ctx, req.(*MsgUpdateParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // gRPC method handlers for Query service
 func _Query_Schema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(QuerySchemaRequest)
@@ -446,3 +961,147 @@ func _Query_Stats_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	}
 	return interceptor(ctx, in, info, handler)
 }
+
+func _Query_AttestationChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryAttestationChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).AttestationChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Query/AttestationChain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).AttestationChain(ctx, req.(*QueryAttestationChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_Endorsements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryEndorsementsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).Endorsements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Query/Endorsements",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).Endorsements(ctx, req.(*QueryEndorsementsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_AttestationsByDocumentHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryAttestationsByDocumentHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).AttestationsByDocumentHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Query/AttestationsByDocumentHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).AttestationsByDocumentHash(ctx, req.(*QueryAttestationsByDocumentHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_AttestationsByTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryAttestationsByTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).AttestationsByTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Query/AttestationsByTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).AttestationsByTag(ctx, req.(*QueryAttestationsByTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_ExpiringAttestations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryExpiringAttestationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).ExpiringAttestations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Query/ExpiringAttestations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).ExpiringAttestations(ctx, req.(*QueryExpiringAttestationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_Revocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRevocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).Revocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Query/Revocations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).Revocations(ctx, req.(*QueryRevocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_FeeBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryFeeBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).FeeBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Query/FeeBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).FeeBalance(ctx, req.(*QueryFeeBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_Params_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).Params(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.attestation.v1.Query/Params",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).Params(ctx, req.(*QueryParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
@@ -28,12 +28,52 @@ var (
 	// IPFSCIDIndexPrefix indexes encrypted attestations by IPFS CID
 	IPFSCIDIndexPrefix = []byte{0x07}
 
+	// AttestationReassignmentPrefix maps a reassigned attestation UID to its replacement UID
+	AttestationReassignmentPrefix = []byte{0x08}
+
+	// EndorsementByTargetPrefix maps an endorsed attestation UID and endorser to the endorsement UID
+	EndorsementByTargetPrefix = []byte{0x09}
+
+	// AttestationByDocumentHashPrefix indexes attestations by the document hash they attest to
+	AttestationByDocumentHashPrefix = []byte{0x0A}
+
+	// AttestationByTagPrefix indexes attestations by tag
+	AttestationByTagPrefix = []byte{0x0B}
+
+	// AttestationByExpirationPrefix indexes attestations by expiration time
+	AttestationByExpirationPrefix = []byte{0x0C}
+
+	// AttestationTombstonePrefix stores the tombstones of pruned attestations by UID
+	AttestationTombstonePrefix = []byte{0x0D}
+
+	// PruneQueuePrefix indexes revoked and expired attestations by the time they
+	// were revoked or expired, for pruning once the retention period passes
+	PruneQueuePrefix = []byte{0x0E}
+
+	// RevocationKeyPrefix stores revocation records by attestation UID
+	RevocationKeyPrefix = []byte{0x0F}
+
 	// AttestationCountKey stores the total attestation count
 	AttestationCountKey = []byte{0x10}
 
 	// EncryptedAttestationCountKey stores the encrypted attestation count
 	EncryptedAttestationCountKey = []byte{0x11}
 
+	// BlockBatchRevocationCountKey stores the batch revocation count for the current block
+	BlockBatchRevocationCountKey = []byte{0x12}
+
+	// ExpirationCursorKey stores the last expiration index key processed by EndBlock
+	ExpirationCursorKey = []byte{0x13}
+
+	// SchemaCountKey stores the registered schema count
+	SchemaCountKey = []byte{0x14}
+
+	// RevocationCountKey stores the count of revoked attestations
+	RevocationCountKey = []byte{0x15}
+
+	// RevocationByAttesterPrefix indexes revocations by attester and revocation time
+	RevocationByAttesterPrefix = []byte{0x16}
+
 	// ParamsKey is the key for module parameters
 	ParamsKey = []byte{0x20}
 )
@@ -76,6 +116,110 @@ func GetIPFSCIDIndexKey(cid string) []byte {
 	return append(IPFSCIDIndexPrefix, []byte(cid)...)
 }
 
+// GetAttestationReassignmentKey returns the key linking a reassigned attestation to its replacement
+func GetAttestationReassignmentKey(uid string) []byte {
+	return append(AttestationReassignmentPrefix, []byte(uid)...)
+}
+
+// GetEndorsementKey returns the index key for endorser's endorsement of targetUID
+func GetEndorsementKey(targetUID string, endorser sdk.AccAddress) []byte {
+	return append(GetEndorsementsIteratorPrefix(targetUID), endorser.Bytes()...)
+}
+
+// GetEndorsementsIteratorPrefix returns the prefix for iterating endorsements of targetUID.
+// The UID is length-prefixed so one UID cannot be a prefix of another's keys.
+func GetEndorsementsIteratorPrefix(targetUID string) []byte {
+	key := append([]byte{}, EndorsementByTargetPrefix...)
+	key = append(key, byte(len(targetUID)))
+	return append(key, []byte(targetUID)...)
+}
+
+// GetAttestationByDocumentHashKey returns the index key for uid under a normalized document hash
+func GetAttestationByDocumentHashKey(docHash string, uid string) []byte {
+	return append(GetAttestationsByDocumentHashIteratorPrefix(docHash), []byte(uid)...)
+}
+
+// GetAttestationsByDocumentHashIteratorPrefix returns the prefix for iterating attestations of a document.
+// The hash is length-prefixed so one hash cannot be a prefix of another's keys.
+func GetAttestationsByDocumentHashIteratorPrefix(docHash string) []byte {
+	key := append([]byte{}, AttestationByDocumentHashPrefix...)
+	key = append(key, byte(len(docHash)))
+	return append(key, []byte(docHash)...)
+}
+
+// GetAttestationByTagKey returns the index key for uid under a normalized tag
+func GetAttestationByTagKey(tag string, uid string) []byte {
+	return append(GetAttestationsByTagIteratorPrefix(tag), []byte(uid)...)
+}
+
+// GetAttestationsByTagIteratorPrefix returns the prefix for iterating attestations with a tag.
+// The tag is length-prefixed so one tag cannot be a prefix of another's keys.
+func GetAttestationsByTagIteratorPrefix(tag string) []byte {
+	key := append([]byte{}, AttestationByTagPrefix...)
+	key = append(key, byte(len(tag)))
+	return append(key, []byte(tag)...)
+}
+
+const (
+	// DefaultExpiringAttestationsLimit caps a Query/ExpiringAttestations page when no limit is given
+	DefaultExpiringAttestationsLimit = 100
+
+	// MaxExpiringAttestationsLimit is the largest page Query/ExpiringAttestations returns
+	MaxExpiringAttestationsLimit = 1000
+
+	// MaxExpirationsPerBlock caps how many expiration index entries EndBlock processes;
+	// the rest carry over to the following blocks
+	MaxExpirationsPerBlock = 200
+)
+
+// GetAttestationByExpirationKey returns the index key for uid expiring at unix second expiresAt.
+// The big-endian timestamp keeps entries in expiration order.
+func GetAttestationByExpirationKey(expiresAt int64, uid string) []byte {
+	return append(GetAttestationsByExpirationIteratorPrefix(expiresAt), []byte(uid)...)
+}
+
+// GetAttestationsByExpirationIteratorPrefix returns the index key prefix for attestations expiring at unix second expiresAt
+func GetAttestationsByExpirationIteratorPrefix(expiresAt int64) []byte {
+	key := append([]byte{}, AttestationByExpirationPrefix...)
+	return append(key, Uint64ToBytes(uint64(expiresAt))...)
+}
+
+// GetAttestationTombstoneKey returns the store key for a pruned attestation's tombstone
+func GetAttestationTombstoneKey(uid string) []byte {
+	return append(AttestationTombstonePrefix, []byte(uid)...)
+}
+
+// GetPruneQueueKey returns the prune queue key for uid, revoked or expired at unix second since
+func GetPruneQueueKey(since int64, uid string) []byte {
+	return append(GetPruneQueueIteratorPrefix(since), []byte(uid)...)
+}
+
+// GetPruneQueueIteratorPrefix returns the prune queue key prefix for attestations revoked or expired at unix second since
+func GetPruneQueueIteratorPrefix(since int64) []byte {
+	key := append([]byte{}, PruneQueuePrefix...)
+	return append(key, Uint64ToBytes(uint64(since))...)
+}
+
+// GetRevocationKey returns the store key for the revocation record of uid
+func GetRevocationKey(uid string) []byte {
+	return append(RevocationKeyPrefix, []byte(uid)...)
+}
+
+// GetRevocationByAttesterKey returns the index key for uid revoked by attester at unix second revokedAt.
+// The big-endian timestamp keeps an attester's entries in revocation order.
+func GetRevocationByAttesterKey(attester sdk.AccAddress, revokedAt int64, uid string) []byte {
+	key := append(GetRevocationsByAttesterIteratorPrefix(attester), Uint64ToBytes(uint64(revokedAt))...)
+	return append(key, []byte(uid)...)
+}
+
+// GetRevocationsByAttesterIteratorPrefix returns the prefix for iterating revocations by attester.
+// The address is length-prefixed so one address cannot be a prefix of another's keys.
+func GetRevocationsByAttesterIteratorPrefix(attester sdk.AccAddress) []byte {
+	key := append([]byte{}, RevocationByAttesterPrefix...)
+	key = append(key, byte(len(attester)))
+	return append(key, attester.Bytes()...)
+}
+
 // GetAttestationIteratorPrefix returns the prefix for iterating all attestations
 func GetAttestationIteratorPrefix() []byte {
 	return AttestationKeyPrefix
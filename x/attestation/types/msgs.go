@@ -2,6 +2,7 @@ package types
 
 import (
 	"errors"
+	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
@@ -10,7 +11,16 @@ const (
 	TypeMsgRegisterSchema             = "register_schema"
 	TypeMsgAttest                     = "attest"
 	TypeMsgRevoke                     = "revoke"
+	TypeMsgBatchRevoke                = "batch_revoke"
+	TypeMsgBatchAttest                = "batch_attest"
+	TypeMsgReassignAttestation        = "reassign_attestation"
 	TypeMsgCreateEncryptedAttestation = "create_encrypted_attestation"
+	TypeMsgUpdateSchemaAllowlist      = "update_schema_allowlist"
+	TypeMsgWithdrawFees               = "withdraw_fees"
+	TypeMsgEndorseAttestation         = "endorse_attestation"
+	TypeMsgReaffirm                   = "reaffirm"
+	TypeMsgUpdateRetentionPolicy      = "update_retention_policy"
+	TypeMsgUpdateParams               = "update_params"
 )
 
 // MsgRegisterSchema registers a new attestation schema
@@ -19,6 +29,15 @@ type MsgRegisterSchema struct {
 	Schema    string `json:"schema" protobuf:"bytes,2,opt,name=schema,proto3"`
 	Resolver  string `json:"resolver,omitempty" protobuf:"bytes,3,opt,name=resolver,proto3"`
 	Revocable bool   `json:"revocable" protobuf:"varint,4,opt,name=revocable,proto3"`
+
+	// Fee is charged per attestation under the schema (optional)
+	Fee sdk.Coins `json:"fee,omitempty" protobuf:"bytes,5,rep,name=fee,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins"`
+
+	// CreatorShareBps is the creator's cut of Fee in basis points
+	CreatorShareBps uint32 `json:"creator_share_bps,omitempty" protobuf:"varint,6,opt,name=creator_share_bps,proto3"`
+
+	// MaxStaleness is the re-affirmation window in seconds (0 = attestations never go stale)
+	MaxStaleness uint64 `json:"max_staleness,omitempty" protobuf:"varint,7,opt,name=max_staleness,proto3"`
 }
 
 // Proto interface implementations
@@ -46,10 +65,13 @@ func (msg MsgRegisterSchema) ValidateBasic() error {
 	if err != nil {
 		return errors.New("invalid creator address")
 	}
-	if msg.Schema == "" {
-		return errors.New("schema cannot be empty")
+	if _, err := ParseSchema(msg.Schema); err != nil {
+		return err
 	}
-	return nil
+	if err := ValidateMaxStaleness(msg.MaxStaleness); err != nil {
+		return err
+	}
+	return ValidateSchemaFee(msg.Fee, msg.CreatorShareBps)
 }
 
 func (msg MsgRegisterSchema) GetSigners() []sdk.AccAddress {
@@ -59,13 +81,14 @@ func (msg MsgRegisterSchema) GetSigners() []sdk.AccAddress {
 
 // MsgAttest creates a new public attestation
 type MsgAttest struct {
-	Attester       string `json:"attester" protobuf:"bytes,1,opt,name=attester,proto3"`
-	SchemaUID      string `json:"schema_uid" protobuf:"bytes,2,opt,name=schema_uid,proto3"`
-	Recipient      string `json:"recipient,omitempty" protobuf:"bytes,3,opt,name=recipient,proto3"`
-	ExpirationTime int64  `json:"expiration_time,omitempty" protobuf:"varint,4,opt,name=expiration_time,proto3"`
-	Revocable      bool   `json:"revocable" protobuf:"varint,5,opt,name=revocable,proto3"`
-	RefUID         string `json:"ref_uid,omitempty" protobuf:"bytes,6,opt,name=ref_uid,proto3"`
-	Data           []byte `json:"data" protobuf:"bytes,7,opt,name=data,proto3"`
+	Attester       string   `json:"attester" protobuf:"bytes,1,opt,name=attester,proto3"`
+	SchemaUID      string   `json:"schema_uid" protobuf:"bytes,2,opt,name=schema_uid,proto3"`
+	Recipient      string   `json:"recipient,omitempty" protobuf:"bytes,3,opt,name=recipient,proto3"`
+	ExpirationTime int64    `json:"expiration_time,omitempty" protobuf:"varint,4,opt,name=expiration_time,proto3"`
+	Revocable      bool     `json:"revocable" protobuf:"varint,5,opt,name=revocable,proto3"`
+	RefUID         string   `json:"ref_uid,omitempty" protobuf:"bytes,6,opt,name=ref_uid,proto3"`
+	Data           []byte   `json:"data" protobuf:"bytes,7,opt,name=data,proto3"`
+	Tags           []string `json:"tags,omitempty" protobuf:"bytes,8,rep,name=tags,proto3"`
 }
 
 // Proto interface implementations
@@ -99,6 +122,9 @@ func (msg MsgAttest) ValidateBasic() error {
 	if msg.SchemaUID == "" {
 		return errors.New("schema UID cannot be empty")
 	}
+	if _, err := NormalizeTags(msg.Tags); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -147,6 +173,475 @@ func (msg MsgRevoke) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{revoker}
 }
 
+// MaxBatchRevokeUIDs is the maximum number of UIDs in a single MsgBatchRevoke
+const MaxBatchRevokeUIDs = 100
+
+// MsgBatchRevoke revokes several attestations at once; either all are revoked or none
+type MsgBatchRevoke struct {
+	Revoker string   `json:"revoker" protobuf:"bytes,1,opt,name=revoker,proto3"`
+	UIDs    []string `json:"uids" protobuf:"bytes,2,rep,name=uids,proto3"`
+}
+
+// Proto interface implementations
+func (msg *MsgBatchRevoke) Reset()         { *msg = MsgBatchRevoke{} }
+func (msg *MsgBatchRevoke) String() string { return msg.Revoker }
+func (msg *MsgBatchRevoke) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgBatchRevoke) XXX_MessageName() string { return "cert.attestation.v1.MsgBatchRevoke" }
+
+func NewMsgBatchRevoke(revoker string, uids []string) *MsgBatchRevoke {
+	return &MsgBatchRevoke{
+		Revoker: revoker,
+		UIDs:    uids,
+	}
+}
+
+func (msg MsgBatchRevoke) Route() string { return RouterKey }
+func (msg MsgBatchRevoke) Type() string  { return TypeMsgBatchRevoke }
+
+func (msg MsgBatchRevoke) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Revoker)
+	if err != nil {
+		return errors.New("invalid revoker address")
+	}
+	if len(msg.UIDs) == 0 {
+		return errors.New("at least one attestation UID is required")
+	}
+	if len(msg.UIDs) > MaxBatchRevokeUIDs {
+		return fmt.Errorf("too many attestation UIDs: %d (max %d)", len(msg.UIDs), MaxBatchRevokeUIDs)
+	}
+	seen := make(map[string]bool, len(msg.UIDs))
+	for _, uid := range msg.UIDs {
+		if uid == "" {
+			return errors.New("attestation UID cannot be empty")
+		}
+		if seen[uid] {
+			return fmt.Errorf("duplicate attestation UID: %s", uid)
+		}
+		seen[uid] = true
+	}
+	return nil
+}
+
+func (msg MsgBatchRevoke) GetSigners() []sdk.AccAddress {
+	revoker, _ := sdk.AccAddressFromBech32(msg.Revoker)
+	return []sdk.AccAddress{revoker}
+}
+
+// MaxBatchAttestEntries is the maximum number of attestations in a single MsgBatchAttest
+const MaxBatchAttestEntries = 100
+
+// BatchAttestEntry is one attestation of a MsgBatchAttest
+type BatchAttestEntry struct {
+	Recipient      string `json:"recipient,omitempty" protobuf:"bytes,1,opt,name=recipient,proto3"`
+	ExpirationTime int64  `json:"expiration_time,omitempty" protobuf:"varint,2,opt,name=expiration_time,proto3"`
+	Data           []byte `json:"data" protobuf:"bytes,3,opt,name=data,proto3"`
+}
+
+// Proto interface implementations
+func (e *BatchAttestEntry) Reset()         { *e = BatchAttestEntry{} }
+func (e *BatchAttestEntry) String() string { return e.Recipient }
+func (e *BatchAttestEntry) ProtoMessage()  {}
+
+// MsgBatchAttest creates several public attestations under one schema; either
+// all are created or none
+type MsgBatchAttest struct {
+	Attester  string             `json:"attester" protobuf:"bytes,1,opt,name=attester,proto3"`
+	SchemaUID string             `json:"schema_uid" protobuf:"bytes,2,opt,name=schema_uid,proto3"`
+	Revocable bool               `json:"revocable" protobuf:"varint,3,opt,name=revocable,proto3"`
+	Tags      []string           `json:"tags,omitempty" protobuf:"bytes,4,rep,name=tags,proto3"`
+	Entries   []BatchAttestEntry `json:"entries" protobuf:"bytes,5,rep,name=entries,proto3"`
+}
+
+// Proto interface implementations
+func (msg *MsgBatchAttest) Reset()         { *msg = MsgBatchAttest{} }
+func (msg *MsgBatchAttest) String() string { return msg.Attester }
+func (msg *MsgBatchAttest) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgBatchAttest) XXX_MessageName() string { return "cert.attestation.v1.MsgBatchAttest" }
+
+func NewMsgBatchAttest(attester, schemaUID string, revocable bool, entries []BatchAttestEntry) *MsgBatchAttest {
+	return &MsgBatchAttest{
+		Attester:  attester,
+		SchemaUID: schemaUID,
+		Revocable: revocable,
+		Entries:   entries,
+	}
+}
+
+func (msg MsgBatchAttest) Route() string { return RouterKey }
+func (msg MsgBatchAttest) Type() string  { return TypeMsgBatchAttest }
+
+func (msg MsgBatchAttest) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Attester)
+	if err != nil {
+		return errors.New("invalid attester address")
+	}
+	if msg.SchemaUID == "" {
+		return errors.New("schema UID cannot be empty")
+	}
+	if _, err := NormalizeTags(msg.Tags); err != nil {
+		return err
+	}
+	if len(msg.Entries) == 0 {
+		return errors.New("at least one attestation entry is required")
+	}
+	if len(msg.Entries) > MaxBatchAttestEntries {
+		return fmt.Errorf("too many attestation entries: %d (max %d)", len(msg.Entries), MaxBatchAttestEntries)
+	}
+	for i, entry := range msg.Entries {
+		if entry.Recipient == "" {
+			continue
+		}
+		if _, err := sdk.AccAddressFromBech32(entry.Recipient); err != nil {
+			return fmt.Errorf("entry %d: invalid recipient address", i)
+		}
+	}
+	return nil
+}
+
+func (msg MsgBatchAttest) GetSigners() []sdk.AccAddress {
+	attester, _ := sdk.AccAddressFromBech32(msg.Attester)
+	return []sdk.AccAddress{attester}
+}
+
+// MsgReassignAttestation reissues an attestation to a new recipient address.
+// The new attestation references the old one via RefUID and the old one is revoked.
+type MsgReassignAttestation struct {
+	Issuer       string `json:"issuer" protobuf:"bytes,1,opt,name=issuer,proto3"`
+	UID          string `json:"uid" protobuf:"bytes,2,opt,name=uid,proto3"`
+	NewRecipient string `json:"new_recipient" protobuf:"bytes,3,opt,name=new_recipient,proto3"`
+}
+
+// Proto interface implementations
+func (msg *MsgReassignAttestation) Reset()         { *msg = MsgReassignAttestation{} }
+func (msg *MsgReassignAttestation) String() string { return msg.Issuer }
+func (msg *MsgReassignAttestation) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgReassignAttestation) XXX_MessageName() string {
+	return "cert.attestation.v1.MsgReassignAttestation"
+}
+
+func NewMsgReassignAttestation(issuer, uid, newRecipient string) *MsgReassignAttestation {
+	return &MsgReassignAttestation{
+		Issuer:       issuer,
+		UID:          uid,
+		NewRecipient: newRecipient,
+	}
+}
+
+func (msg MsgReassignAttestation) Route() string { return RouterKey }
+func (msg MsgReassignAttestation) Type() string  { return TypeMsgReassignAttestation }
+
+func (msg MsgReassignAttestation) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Issuer)
+	if err != nil {
+		return errors.New("invalid issuer address")
+	}
+	if msg.UID == "" {
+		return errors.New("attestation UID cannot be empty")
+	}
+	if _, err := sdk.AccAddressFromBech32(msg.NewRecipient); err != nil {
+		return errors.New("invalid new recipient address")
+	}
+	return nil
+}
+
+func (msg MsgReassignAttestation) GetSigners() []sdk.AccAddress {
+	issuer, _ := sdk.AccAddressFromBech32(msg.Issuer)
+	return []sdk.AccAddress{issuer}
+}
+
+// MsgReaffirm refreshes an attestation's affirmation time so it is no longer
+// stale. Only the attester may reaffirm.
+type MsgReaffirm struct {
+	Attester string `json:"attester" protobuf:"bytes,1,opt,name=attester,proto3"`
+	UID      string `json:"uid" protobuf:"bytes,2,opt,name=uid,proto3"`
+}
+
+// Proto interface implementations
+func (msg *MsgReaffirm) Reset()         { *msg = MsgReaffirm{} }
+func (msg *MsgReaffirm) String() string { return msg.Attester }
+func (msg *MsgReaffirm) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgReaffirm) XXX_MessageName() string { return "cert.attestation.v1.MsgReaffirm" }
+
+func NewMsgReaffirm(attester, uid string) *MsgReaffirm {
+	return &MsgReaffirm{
+		Attester: attester,
+		UID:      uid,
+	}
+}
+
+func (msg MsgReaffirm) Route() string { return RouterKey }
+func (msg MsgReaffirm) Type() string  { return TypeMsgReaffirm }
+
+func (msg MsgReaffirm) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Attester); err != nil {
+		return errors.New("invalid attester address")
+	}
+	if msg.UID == "" {
+		return errors.New("attestation UID cannot be empty")
+	}
+	return nil
+}
+
+func (msg MsgReaffirm) GetSigners() []sdk.AccAddress {
+	attester, _ := sdk.AccAddressFromBech32(msg.Attester)
+	return []sdk.AccAddress{attester}
+}
+
+// MaxEndorsementNoteLength bounds the free-form note carried by an endorsement
+const MaxEndorsementNoteLength = 1024
+
+// MsgEndorseAttestation countersigns an existing attestation. The endorsement
+// is itself an attestation of type endorsement referencing the target via RefUID.
+type MsgEndorseAttestation struct {
+	Endorser string `json:"endorser" protobuf:"bytes,1,opt,name=endorser,proto3"`
+	UID      string `json:"uid" protobuf:"bytes,2,opt,name=uid,proto3"`
+	Note     []byte `json:"note,omitempty" protobuf:"bytes,3,opt,name=note,proto3"`
+}
+
+// Proto interface implementations
+func (msg *MsgEndorseAttestation) Reset()         { *msg = MsgEndorseAttestation{} }
+func (msg *MsgEndorseAttestation) String() string { return msg.Endorser }
+func (msg *MsgEndorseAttestation) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgEndorseAttestation) XXX_MessageName() string {
+	return "cert.attestation.v1.MsgEndorseAttestation"
+}
+
+func NewMsgEndorseAttestation(endorser, uid string, note []byte) *MsgEndorseAttestation {
+	return &MsgEndorseAttestation{
+		Endorser: endorser,
+		UID:      uid,
+		Note:     note,
+	}
+}
+
+func (msg MsgEndorseAttestation) Route() string { return RouterKey }
+func (msg MsgEndorseAttestation) Type() string  { return TypeMsgEndorseAttestation }
+
+func (msg MsgEndorseAttestation) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Endorser); err != nil {
+		return errors.New("invalid endorser address")
+	}
+	if msg.UID == "" {
+		return errors.New("attestation UID cannot be empty")
+	}
+	if len(msg.Note) > MaxEndorsementNoteLength {
+		return fmt.Errorf("endorsement note exceeds %d bytes", MaxEndorsementNoteLength)
+	}
+	return nil
+}
+
+func (msg MsgEndorseAttestation) GetSigners() []sdk.AccAddress {
+	endorser, _ := sdk.AccAddressFromBech32(msg.Endorser)
+	return []sdk.AccAddress{endorser}
+}
+
+// MsgUpdateSchemaAllowlist toggles schema restriction and edits the allowlisted
+// schema creators. Only the module authority (governance) may send it.
+type MsgUpdateSchemaAllowlist struct {
+	Authority      string   `json:"authority" protobuf:"bytes,1,opt,name=authority,proto3"`
+	Restricted     bool     `json:"restricted" protobuf:"varint,2,opt,name=restricted,proto3"`
+	AddCreators    []string `json:"add_creators,omitempty" protobuf:"bytes,3,rep,name=add_creators,proto3"`
+	RemoveCreators []string `json:"remove_creators,omitempty" protobuf:"bytes,4,rep,name=remove_creators,proto3"`
+}
+
+// Proto interface implementations
+func (msg *MsgUpdateSchemaAllowlist) Reset()         { *msg = MsgUpdateSchemaAllowlist{} }
+func (msg *MsgUpdateSchemaAllowlist) String() string { return msg.Authority }
+func (msg *MsgUpdateSchemaAllowlist) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgUpdateSchemaAllowlist) XXX_MessageName() string {
+	return "cert.attestation.v1.MsgUpdateSchemaAllowlist"
+}
+
+func NewMsgUpdateSchemaAllowlist(authority string, restricted bool, addCreators, removeCreators []string) *MsgUpdateSchemaAllowlist {
+	return &MsgUpdateSchemaAllowlist{
+		Authority:      authority,
+		Restricted:     restricted,
+		AddCreators:    addCreators,
+		RemoveCreators: removeCreators,
+	}
+}
+
+func (msg MsgUpdateSchemaAllowlist) Route() string { return RouterKey }
+func (msg MsgUpdateSchemaAllowlist) Type() string  { return TypeMsgUpdateSchemaAllowlist }
+
+func (msg MsgUpdateSchemaAllowlist) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Authority); err != nil {
+		return errors.New("invalid authority address")
+	}
+	for _, creator := range append(append([]string(nil), msg.AddCreators...), msg.RemoveCreators...) {
+		if _, err := sdk.AccAddressFromBech32(creator); err != nil {
+			return fmt.Errorf("invalid schema creator address %q", creator)
+		}
+	}
+	return nil
+}
+
+func (msg MsgUpdateSchemaAllowlist) GetSigners() []sdk.AccAddress {
+	authority, _ := sdk.AccAddressFromBech32(msg.Authority)
+	return []sdk.AccAddress{authority}
+}
+
+// MsgUpdateRetentionPolicy sets how long revoked and expired attestations are
+// kept before they are pruned to tombstones, and how many are pruned per
+// block. Only the module authority (governance) may send it.
+type MsgUpdateRetentionPolicy struct {
+	Authority string `json:"authority" protobuf:"bytes,1,opt,name=authority,proto3"`
+
+	// RetentionPeriod is in seconds; 0 disables pruning
+	RetentionPeriod uint64 `json:"retention_period" protobuf:"varint,2,opt,name=retention_period,proto3"`
+
+	// MaxPrunesPerBlock of 0 restores DefaultMaxPrunesPerBlock
+	MaxPrunesPerBlock uint32 `json:"max_prunes_per_block,omitempty" protobuf:"varint,3,opt,name=max_prunes_per_block,proto3"`
+}
+
+// Proto interface implementations
+func (msg *MsgUpdateRetentionPolicy) Reset()         { *msg = MsgUpdateRetentionPolicy{} }
+func (msg *MsgUpdateRetentionPolicy) String() string { return msg.Authority }
+func (msg *MsgUpdateRetentionPolicy) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgUpdateRetentionPolicy) XXX_MessageName() string {
+	return "cert.attestation.v1.MsgUpdateRetentionPolicy"
+}
+
+func NewMsgUpdateRetentionPolicy(authority string, retentionPeriod uint64, maxPrunesPerBlock uint32) *MsgUpdateRetentionPolicy {
+	return &MsgUpdateRetentionPolicy{
+		Authority:         authority,
+		RetentionPeriod:   retentionPeriod,
+		MaxPrunesPerBlock: maxPrunesPerBlock,
+	}
+}
+
+func (msg MsgUpdateRetentionPolicy) Route() string { return RouterKey }
+func (msg MsgUpdateRetentionPolicy) Type() string  { return TypeMsgUpdateRetentionPolicy }
+
+func (msg MsgUpdateRetentionPolicy) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Authority); err != nil {
+		return errors.New("invalid authority address")
+	}
+	if msg.RetentionPeriod > MaxRetentionPeriod {
+		return fmt.Errorf("retention period cannot exceed %d seconds", MaxRetentionPeriod)
+	}
+	return nil
+}
+
+func (msg MsgUpdateRetentionPolicy) GetSigners() []sdk.AccAddress {
+	authority, _ := sdk.AccAddressFromBech32(msg.Authority)
+	return []sdk.AccAddress{authority}
+}
+
+// MsgUpdateParams replaces the module parameters. Only the module authority
+// (governance) may send it.
+type MsgUpdateParams struct {
+	Authority string `json:"authority" protobuf:"bytes,1,opt,name=authority,proto3"`
+	Params    Params `json:"params" protobuf:"bytes,2,opt,name=params,proto3"`
+}
+
+// Proto interface implementations
+func (msg *MsgUpdateParams) Reset()         { *msg = MsgUpdateParams{} }
+func (msg *MsgUpdateParams) String() string { return msg.Authority }
+func (msg *MsgUpdateParams) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgUpdateParams) XXX_MessageName() string {
+	return "cert.attestation.v1.MsgUpdateParams"
+}
+
+func NewMsgUpdateParams(authority string, params Params) *MsgUpdateParams {
+	return &MsgUpdateParams{
+		Authority: authority,
+		Params:    params,
+	}
+}
+
+func (msg MsgUpdateParams) Route() string { return RouterKey }
+func (msg MsgUpdateParams) Type() string  { return TypeMsgUpdateParams }
+
+func (msg MsgUpdateParams) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Authority); err != nil {
+		return errors.New("invalid authority address")
+	}
+	return msg.Params.Validate()
+}
+
+func (msg MsgUpdateParams) GetSigners() []sdk.AccAddress {
+	authority, _ := sdk.AccAddressFromBech32(msg.Authority)
+	return []sdk.AccAddress{authority}
+}
+
+// MsgWithdrawFees moves accumulated schema fees out of the module account,
+// burning them or paying them to a recipient such as the community treasury.
+// Only the module authority (governance) may send it.
+type MsgWithdrawFees struct {
+	Authority string `json:"authority" protobuf:"bytes,1,opt,name=authority,proto3"`
+
+	// Amount to withdraw; empty withdraws the whole balance
+	Amount sdk.Coins `json:"amount,omitempty" protobuf:"bytes,2,rep,name=amount,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins"`
+
+	// Recipient receives the fees; must be empty when Burn is set
+	Recipient string `json:"recipient,omitempty" protobuf:"bytes,3,opt,name=recipient,proto3"`
+
+	// Burn destroys the fees instead of paying them out
+	Burn bool `json:"burn,omitempty" protobuf:"varint,4,opt,name=burn,proto3"`
+}
+
+// Proto interface implementations
+func (msg *MsgWithdrawFees) Reset()         { *msg = MsgWithdrawFees{} }
+func (msg *MsgWithdrawFees) String() string { return msg.Authority }
+func (msg *MsgWithdrawFees) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgWithdrawFees) XXX_MessageName() string {
+	return "cert.attestation.v1.MsgWithdrawFees"
+}
+
+func NewMsgWithdrawFees(authority string, amount sdk.Coins, recipient string, burn bool) *MsgWithdrawFees {
+	return &MsgWithdrawFees{
+		Authority: authority,
+		Amount:    amount,
+		Recipient: recipient,
+		Burn:      burn,
+	}
+}
+
+func (msg MsgWithdrawFees) Route() string { return RouterKey }
+func (msg MsgWithdrawFees) Type() string  { return TypeMsgWithdrawFees }
+
+func (msg MsgWithdrawFees) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Authority); err != nil {
+		return errors.New("invalid authority address")
+	}
+	if err := msg.Amount.Validate(); err != nil {
+		return fmt.Errorf("invalid amount: %w", err)
+	}
+	if msg.Burn == (msg.Recipient != "") {
+		return errors.New("exactly one of recipient or burn is required")
+	}
+	if msg.Recipient != "" {
+		if _, err := sdk.AccAddressFromBech32(msg.Recipient); err != nil {
+			return errors.New("invalid recipient address")
+		}
+	}
+	return nil
+}
+
+func (msg MsgWithdrawFees) GetSigners() []sdk.AccAddress {
+	authority, _ := sdk.AccAddressFromBech32(msg.Authority)
+	return []sdk.AccAddress{authority}
+}
+
 // MsgCreateEncryptedAttestation creates a new encrypted attestation
 // Per Whitepaper Section 3.2 - Step 4: On-Chain Anchoring
 type MsgCreateEncryptedAttestation struct {
@@ -158,6 +653,10 @@ type MsgCreateEncryptedAttestation struct {
 	EncryptedSymmetricKeys map[string]string `json:"encrypted_symmetric_keys" protobuf:"bytes,6,rep,name=encrypted_symmetric_keys,proto3"`
 	Revocable              bool              `json:"revocable" protobuf:"varint,7,opt,name=revocable,proto3"`
 	ExpirationTime         int64             `json:"expiration_time,omitempty" protobuf:"varint,8,opt,name=expiration_time,proto3"`
+
+	// FileSize is the size in bytes of the encrypted file pinned at IPFSCID,
+	// checked against Params.MaxEncryptedFileSize
+	FileSize uint64 `json:"file_size,omitempty" protobuf:"varint,9,opt,name=file_size,proto3"`
 }
 
 // Proto interface implementations
@@ -209,9 +708,9 @@ func (msg MsgCreateEncryptedAttestation) ValidateBasic() error {
 	if len(msg.Recipients) == 0 {
 		return errors.New("at least one recipient is required")
 	}
-	// Whitepaper Section 12: Max 50 recipients per attestation
-	if len(msg.Recipients) > 50 {
-		return errors.New("maximum 50 recipients allowed per attestation")
+	// The governed cap, Params.MaxRecipientsPerAttestation, is checked on delivery
+	if len(msg.Recipients) > MaxRecipientsLimit {
+		return fmt.Errorf("maximum %d recipients allowed per attestation", MaxRecipientsLimit)
 	}
 	// Verify each recipient has an encrypted key
 	for _, recipient := range msg.Recipients {
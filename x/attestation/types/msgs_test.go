@@ -59,6 +59,16 @@ func TestMsgRegisterSchema_ValidateBasic(t *testing.T) {
 			),
 			expectErr: true,
 		},
+		{
+			name: "unsupported field type",
+			msg: types.NewMsgRegisterSchema(
+				validAddr,
+				"uint256 grade,text course",
+				"",
+				true,
+			),
+			expectErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -125,6 +135,15 @@ func TestMsgAttest_ValidateBasic(t *testing.T) {
 			),
 			expectErr: true,
 		},
+		{
+			name: "invalid tags",
+			msg: &types.MsgAttest{
+				Attester:  validAddr,
+				SchemaUID: "0x1234567890abcdef",
+				Tags:      []string{"diploma", "class of 2024"},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -167,7 +186,7 @@ func TestMsgCreateEncryptedAttestation_ValidateBasic(t *testing.T) {
 			expectErr: false,
 		},
 		{
-			name: "exceeds max recipients (50)",
+			name: "exceeds the default recipient cap, checked against params on delivery",
 			msg: func() *types.MsgCreateEncryptedAttestation {
 				recipients := make([]string, 51)
 				keys := make(map[string]string)
@@ -180,6 +199,138 @@ func TestMsgCreateEncryptedAttestation_ValidateBasic(t *testing.T) {
 					recipients, keys, true, 0,
 				)
 			}(),
+			expectErr: false,
+		},
+		{
+			name: "exceeds max recipients limit",
+			msg: func() *types.MsgCreateEncryptedAttestation {
+				recipients := make([]string, types.MaxRecipientsLimit+1)
+				keys := make(map[string]string)
+				for i := range recipients {
+					recipients[i] = validRecipient
+					keys[recipients[i]] = "key"
+				}
+				return types.NewMsgCreateEncryptedAttestation(
+					validAddr, "0x1234", "QmTest", "0xhash",
+					recipients, keys, true, 0,
+				)
+			}(),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.msg.ValidateBasic()
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMsgBatchRevoke_ValidateBasic(t *testing.T) {
+	config := sdk.GetConfig()
+	config.SetBech32PrefixForAccount("cert", "certpub")
+
+	validAddr := createTestAddress("cert")
+	tooMany := make([]string, types.MaxBatchRevokeUIDs+1)
+	for i := range tooMany {
+		tooMany[i] = string(rune('a'+i%26)) + string(rune('0'+i/26))
+	}
+
+	testCases := []struct {
+		name      string
+		msg       *types.MsgBatchRevoke
+		expectErr bool
+	}{
+		{
+			name:      "valid batch",
+			msg:       types.NewMsgBatchRevoke(validAddr, []string{"0xaa", "0xbb"}),
+			expectErr: false,
+		},
+		{
+			name:      "invalid revoker",
+			msg:       types.NewMsgBatchRevoke("invalid", []string{"0xaa"}),
+			expectErr: true,
+		},
+		{
+			name:      "empty batch",
+			msg:       types.NewMsgBatchRevoke(validAddr, nil),
+			expectErr: true,
+		},
+		{
+			name:      "duplicate uid",
+			msg:       types.NewMsgBatchRevoke(validAddr, []string{"0xaa", "0xaa"}),
+			expectErr: true,
+		},
+		{
+			name:      "empty uid",
+			msg:       types.NewMsgBatchRevoke(validAddr, []string{"0xaa", ""}),
+			expectErr: true,
+		},
+		{
+			name:      "exceeds max uids",
+			msg:       types.NewMsgBatchRevoke(validAddr, tooMany),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.msg.ValidateBasic()
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMsgBatchAttest_ValidateBasic(t *testing.T) {
+	config := sdk.GetConfig()
+	config.SetBech32PrefixForAccount("cert", "certpub")
+
+	validAddr := createTestAddress("cert")
+	entry := types.BatchAttestEntry{Recipient: validAddr, Data: []byte{0x01}}
+	tooMany := make([]types.BatchAttestEntry, types.MaxBatchAttestEntries+1)
+
+	testCases := []struct {
+		name      string
+		msg       *types.MsgBatchAttest
+		expectErr bool
+	}{
+		{
+			name:      "valid batch",
+			msg:       types.NewMsgBatchAttest(validAddr, "0xschema", true, []types.BatchAttestEntry{entry, {Data: []byte{0x02}}}),
+			expectErr: false,
+		},
+		{
+			name:      "invalid attester",
+			msg:       types.NewMsgBatchAttest("invalid", "0xschema", true, []types.BatchAttestEntry{entry}),
+			expectErr: true,
+		},
+		{
+			name:      "empty schema",
+			msg:       types.NewMsgBatchAttest(validAddr, "", true, []types.BatchAttestEntry{entry}),
+			expectErr: true,
+		},
+		{
+			name:      "empty batch",
+			msg:       types.NewMsgBatchAttest(validAddr, "0xschema", true, nil),
+			expectErr: true,
+		},
+		{
+			name:      "invalid recipient",
+			msg:       types.NewMsgBatchAttest(validAddr, "0xschema", true, []types.BatchAttestEntry{entry, {Recipient: "cert1bad"}}),
+			expectErr: true,
+		},
+		{
+			name:      "exceeds max entries",
+			msg:       types.NewMsgBatchAttest(validAddr, "0xschema", true, tooMany),
 			expectErr: true,
 		},
 	}
@@ -16,6 +16,14 @@ type QueryClient interface {
 	AttestationsByRecipient(ctx context.Context, in *QueryAttestationsByRecipientRequest, opts ...grpc.CallOption) (*QueryAttestationsByRecipientResponse, error)
 	EncryptedAttestation(ctx context.Context, in *QueryEncryptedAttestationRequest, opts ...grpc.CallOption) (*QueryEncryptedAttestationResponse, error)
 	Stats(ctx context.Context, in *QueryStatsRequest, opts ...grpc.CallOption) (*QueryStatsResponse, error)
+	AttestationChain(ctx context.Context, in *QueryAttestationChainRequest, opts ...grpc.CallOption) (*QueryAttestationChainResponse, error)
+	Endorsements(ctx context.Context, in *QueryEndorsementsRequest, opts ...grpc.CallOption) (*QueryEndorsementsResponse, error)
+	AttestationsByDocumentHash(ctx context.Context, in *QueryAttestationsByDocumentHashRequest, opts ...grpc.CallOption) (*QueryAttestationsByDocumentHashResponse, error)
+	AttestationsByTag(ctx context.Context, in *QueryAttestationsByTagRequest, opts ...grpc.CallOption) (*QueryAttestationsByTagResponse, error)
+	ExpiringAttestations(ctx context.Context, in *QueryExpiringAttestationsRequest, opts ...grpc.CallOption) (*QueryExpiringAttestationsResponse, error)
+	Revocations(ctx context.Context, in *QueryRevocationsRequest, opts ...grpc.CallOption) (*QueryRevocationsResponse, error)
+	FeeBalance(ctx context.Context, in *QueryFeeBalanceRequest, opts ...grpc.CallOption) (*QueryFeeBalanceResponse, error)
+	Params(ctx context.Context, in *QueryParamsRequest, opts ...grpc.CallOption) (*QueryParamsResponse, error)
 }
 
 type queryClient struct {
@@ -86,3 +94,83 @@ func (c *queryClient) Stats(ctx context.Context, in *QueryStatsRequest, opts ...
 	}
 	return out, nil
 }
+
+// AttestationChain queries the reassignment chain of an attestation
+func (c *queryClient) AttestationChain(ctx context.Context, in *QueryAttestationChainRequest, opts ...grpc.CallOption) (*QueryAttestationChainResponse, error) {
+	out := new(QueryAttestationChainResponse)
+	err := c.cc.Invoke(ctx, "/cert.attestation.v1.Query/AttestationChain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Endorsements queries the endorsements of an attestation
+func (c *queryClient) Endorsements(ctx context.Context, in *QueryEndorsementsRequest, opts ...grpc.CallOption) (*QueryEndorsementsResponse, error) {
+	out := new(QueryEndorsementsResponse)
+	err := c.cc.Invoke(ctx, "/cert.attestation.v1.Query/Endorsements", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AttestationsByDocumentHash queries the attestations over a document
+func (c *queryClient) AttestationsByDocumentHash(ctx context.Context, in *QueryAttestationsByDocumentHashRequest, opts ...grpc.CallOption) (*QueryAttestationsByDocumentHashResponse, error) {
+	out := new(QueryAttestationsByDocumentHashResponse)
+	err := c.cc.Invoke(ctx, "/cert.attestation.v1.Query/AttestationsByDocumentHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AttestationsByTag queries the attestations carrying every requested tag
+func (c *queryClient) AttestationsByTag(ctx context.Context, in *QueryAttestationsByTagRequest, opts ...grpc.CallOption) (*QueryAttestationsByTagResponse, error) {
+	out := new(QueryAttestationsByTagResponse)
+	err := c.cc.Invoke(ctx, "/cert.attestation.v1.Query/AttestationsByTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExpiringAttestations queries the unrevoked attestations expiring within a time window
+func (c *queryClient) ExpiringAttestations(ctx context.Context, in *QueryExpiringAttestationsRequest, opts ...grpc.CallOption) (*QueryExpiringAttestationsResponse, error) {
+	out := new(QueryExpiringAttestationsResponse)
+	err := c.cc.Invoke(ctx, "/cert.attestation.v1.Query/ExpiringAttestations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Revocations queries the attestations an attester has revoked
+func (c *queryClient) Revocations(ctx context.Context, in *QueryRevocationsRequest, opts ...grpc.CallOption) (*QueryRevocationsResponse, error) {
+	out := new(QueryRevocationsResponse)
+	err := c.cc.Invoke(ctx, "/cert.attestation.v1.Query/Revocations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FeeBalance queries the schema fees held by the module account
+func (c *queryClient) FeeBalance(ctx context.Context, in *QueryFeeBalanceRequest, opts ...grpc.CallOption) (*QueryFeeBalanceResponse, error) {
+	out := new(QueryFeeBalanceResponse)
+	err := c.cc.Invoke(ctx, "/cert.attestation.v1.Query/FeeBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Params queries the module parameters
+func (c *queryClient) Params(ctx context.Context, in *QueryParamsRequest, opts ...grpc.CallOption) (*QueryParamsResponse, error) {
+	out := new(QueryParamsResponse)
+	err := c.cc.Invoke(ctx, "/cert.attestation.v1.Query/Params", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// DefaultMaxPrunesPerBlock is the default cap on attestations EndBlock prunes in one block
+	DefaultMaxPrunesPerBlock = 100
+
+	// MaxRetentionPeriod caps Params.RetentionPeriod, in seconds (100 years)
+	MaxRetentionPeriod = 100 * 365 * 24 * 60 * 60
+)
+
+// AttestationTombstone is what remains of an attestation pruned under the
+// retention policy: enough to show that it existed and was revoked or expired
+type AttestationTombstone struct {
+	UID       string         `json:"uid" protobuf:"bytes,1,opt,name=uid,proto3"`
+	SchemaUID string         `json:"schema_uid" protobuf:"bytes,2,opt,name=schema_uid,proto3"`
+	Attester  sdk.AccAddress `json:"attester" protobuf:"bytes,3,opt,name=attester,proto3"`
+
+	// DataHash is the hex SHA-256 of the attestation data, or the encrypted data
+	// hash for encrypted attestations
+	DataHash string `json:"data_hash" protobuf:"bytes,4,opt,name=data_hash,proto3"`
+
+	RevocationTime time.Time `json:"revocation_time,omitempty" protobuf:"bytes,5,opt,name=revocation_time,proto3,stdtime"`
+	ExpirationTime time.Time `json:"expiration_time,omitempty" protobuf:"bytes,6,opt,name=expiration_time,proto3,stdtime"`
+	Encrypted      bool      `json:"encrypted,omitempty" protobuf:"varint,7,opt,name=encrypted,proto3"`
+	PrunedTime     time.Time `json:"pruned_time" protobuf:"bytes,8,opt,name=pruned_time,proto3,stdtime"`
+
+	// DocumentHash and Tags are the index entries kept after pruning, so
+	// revocation lookups by document hash or tag still find the attestation
+	DocumentHash string   `json:"document_hash,omitempty" protobuf:"bytes,9,opt,name=document_hash,proto3"`
+	Tags         []string `json:"tags,omitempty" protobuf:"bytes,10,rep,name=tags,proto3"`
+}
+
+// Proto interface implementations for AttestationTombstone
+func (t *AttestationTombstone) Reset()         { *t = AttestationTombstone{} }
+func (t *AttestationTombstone) String() string { return t.UID }
+func (t *AttestationTombstone) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name
+func (*AttestationTombstone) XXX_MessageName() string {
+	return "cert.attestation.v1.AttestationTombstone"
+}
+
+// NewAttestationTombstone returns the tombstone left when attestation, indexed
+// under documentHash, is pruned at prunedTime
+func NewAttestationTombstone(attestation Attestation, encryptedDataHash, documentHash string, prunedTime time.Time) AttestationTombstone {
+	dataHash := encryptedDataHash
+	if dataHash == "" {
+		sum := sha256.Sum256(attestation.Data)
+		dataHash = hex.EncodeToString(sum[:])
+	}
+	return AttestationTombstone{
+		UID:            attestation.UID,
+		SchemaUID:      attestation.SchemaUID,
+		Attester:       attestation.Attester,
+		DataHash:       dataHash,
+		RevocationTime: attestation.RevocationTime,
+		ExpirationTime: attestation.ExpirationTime,
+		Encrypted:      encryptedDataHash != "",
+		PrunedTime:     prunedTime,
+		DocumentHash:   documentHash,
+		Tags:           attestation.Tags,
+	}
+}
+
+// Attestation returns the pruned attestation as far as the tombstone records it
+func (t AttestationTombstone) Attestation() Attestation {
+	return Attestation{
+		UID:            t.UID,
+		SchemaUID:      t.SchemaUID,
+		Attester:       t.Attester,
+		ExpirationTime: t.ExpirationTime,
+		RevocationTime: t.RevocationTime,
+		Expired:        t.RevocationTime.IsZero(),
+		Tags:           t.Tags,
+		Pruned:         true,
+	}
+}
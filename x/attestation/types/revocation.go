@@ -0,0 +1,25 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Revocation records that an attester revoked an attestation. Records are kept
+// after the attestation itself is pruned.
+type Revocation struct {
+	UID            string         `json:"uid" protobuf:"bytes,1,opt,name=uid,proto3"`
+	Attester       sdk.AccAddress `json:"attester" protobuf:"bytes,2,opt,name=attester,proto3"`
+	RevocationTime time.Time      `json:"revocation_time" protobuf:"bytes,3,opt,name=revocation_time,proto3,stdtime"`
+}
+
+// Proto interface implementations for Revocation
+func (r *Revocation) Reset()         { *r = Revocation{} }
+func (r *Revocation) String() string { return r.UID }
+func (r *Revocation) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name
+func (*Revocation) XXX_MessageName() string {
+	return "cert.attestation.v1.Revocation"
+}
@@ -0,0 +1,179 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	errorsmod "cosmossdk.io/errors"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// SchemaField is a single typed field of an EAS-style schema definition
+type SchemaField struct {
+	Type string
+	Name string
+	abi  abi.Type
+}
+
+// ParseSchema parses an EAS-style schema definition such as
+// "bytes32 dataHash, string metadata, uint256 timestamp" into its fields.
+// Field types follow Solidity ABI type names.
+func ParseSchema(schema string) ([]SchemaField, error) {
+	if strings.TrimSpace(schema) == "" {
+		return nil, errorsmod.Wrap(ErrInvalidSchemaFormat, "schema cannot be empty")
+	}
+
+	parts := strings.Split(schema, ",")
+	fields := make([]SchemaField, 0, len(parts))
+	seen := make(map[string]bool, len(parts))
+
+	for i, part := range parts {
+		tokens := strings.Fields(part)
+		if len(tokens) != 2 {
+			return nil, errorsmod.Wrapf(ErrInvalidSchemaFormat, "field %d: expected \"<type> <name>\", got %q", i+1, strings.TrimSpace(part))
+		}
+
+		typ, err := abi.NewType(tokens[0], "", nil)
+		if err != nil {
+			return nil, errorsmod.Wrapf(ErrInvalidSchemaFormat, "field %d (%s): unsupported type %q", i+1, tokens[1], tokens[0])
+		}
+		if seen[tokens[1]] {
+			return nil, errorsmod.Wrapf(ErrInvalidSchemaFormat, "field %d: duplicate field name %q", i+1, tokens[1])
+		}
+		seen[tokens[1]] = true
+
+		fields = append(fields, SchemaField{Type: tokens[0], Name: tokens[1], abi: typ})
+	}
+
+	return fields, nil
+}
+
+// ValidateSchemaData checks that ABI-encoded attestation data decodes cleanly
+// against the schema: every field must be present, canonically encoded for its
+// declared type, and no bytes may follow the last field.
+func ValidateSchemaData(schema string, data []byte) error {
+	fields, err := ParseSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	headSize := 0
+	for _, field := range fields {
+		headSize += schemaTypeHeadSize(field.abi)
+	}
+
+	offset := 0
+	end := headSize
+	for i, field := range fields {
+		size := schemaTypeHeadSize(field.abi)
+		label := fmt.Sprintf("field %d (%s %s)", i+1, field.Type, field.Name)
+
+		if offset+size > len(data) {
+			return errorsmod.Wrapf(ErrInvalidAttestationData, "%s: missing; schema expects %d fields but data is %d bytes", label, len(fields), len(data))
+		}
+
+		args := abi.Arguments{{Name: field.Name, Type: field.abi}}
+		if !schemaTypeIsDynamic(field.abi) {
+			slot := data[offset : offset+size]
+			if err := checkCanonical(args, slot); err != nil {
+				return errorsmod.Wrapf(ErrInvalidAttestationData, "%s: %v", label, err)
+			}
+		} else {
+			// Dynamic fields store an offset to their tail in the head slot
+			ptr := new(big.Int).SetBytes(data[offset : offset+32])
+			if !ptr.IsInt64() || ptr.Int64() >= int64(len(data)) || ptr.Int64()%32 != 0 {
+				return errorsmod.Wrapf(ErrInvalidAttestationData, "%s: invalid data offset %s", label, ptr.String())
+			}
+			if ptr.Int64() < int64(headSize) {
+				return errorsmod.Wrapf(ErrInvalidAttestationData, "%s: data offset %s points inside the %d-byte head; schema expects %d fields", label, ptr.String(), headSize, len(fields))
+			}
+			tail := data[ptr.Int64():]
+
+			// Re-base the tail so it can be decoded as a standalone argument
+			rebased := append(abiWord(32), tail...)
+			values, err := args.UnpackValues(rebased)
+			if err != nil {
+				return errorsmod.Wrapf(ErrInvalidAttestationData, "%s: %v", label, err)
+			}
+			canonical, err := args.PackValues(values)
+			if err != nil {
+				return errorsmod.Wrapf(ErrInvalidAttestationData, "%s: %v", label, err)
+			}
+			canonicalTail := canonical[32:]
+			if len(canonicalTail) > len(tail) || !bytes.Equal(canonicalTail, tail[:len(canonicalTail)]) {
+				return errorsmod.Wrapf(ErrInvalidAttestationData, "%s: value is not a valid %s", label, field.Type)
+			}
+			if tailEnd := int(ptr.Int64()) + len(canonicalTail); tailEnd > end {
+				end = tailEnd
+			}
+		}
+
+		offset += size
+	}
+
+	if len(data) > end {
+		return errorsmod.Wrapf(ErrInvalidAttestationData, "data has %d trailing bytes; schema expects %d fields", len(data)-end, len(fields))
+	}
+
+	return nil
+}
+
+// checkCanonical decodes a static value and verifies it re-encodes to the same bytes
+func checkCanonical(args abi.Arguments, encoded []byte) error {
+	values, err := args.UnpackValues(encoded)
+	if err != nil {
+		return err
+	}
+	canonical, err := args.PackValues(values)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(canonical, encoded) {
+		return fmt.Errorf("value is not a valid %s", args[0].Type.String())
+	}
+	return nil
+}
+
+// abiWord returns n as a 32-byte big-endian word
+func abiWord(n int) []byte {
+	word := make([]byte, 32)
+	new(big.Int).SetInt64(int64(n)).FillBytes(word)
+	return word
+}
+
+// schemaTypeIsDynamic mirrors the ABI definition of dynamic types
+func schemaTypeIsDynamic(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy:
+		return true
+	case abi.ArrayTy:
+		return schemaTypeIsDynamic(*t.Elem)
+	case abi.TupleTy:
+		for _, elem := range t.TupleElems {
+			if schemaTypeIsDynamic(*elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// schemaTypeHeadSize returns the bytes a field occupies in the ABI head
+func schemaTypeHeadSize(t abi.Type) int {
+	if schemaTypeIsDynamic(t) {
+		return 32
+	}
+	switch t.T {
+	case abi.ArrayTy:
+		return t.Size * schemaTypeHeadSize(*t.Elem)
+	case abi.TupleTy:
+		total := 0
+		for _, elem := range t.TupleElems {
+			total += schemaTypeHeadSize(*elem)
+		}
+		return total
+	}
+	return 32
+}
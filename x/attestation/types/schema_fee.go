@@ -0,0 +1,53 @@
+package types
+
+import (
+	"cosmossdk.io/errors"
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+const (
+	// BasisPointsDenominator is 100% in basis points
+	BasisPointsDenominator = 10_000
+
+	// DefaultMaxSchemaCreatorShareBps caps the creator's cut of a schema fee at 50%
+	DefaultMaxSchemaCreatorShareBps = 5_000
+
+	// DefaultFeeDenom is the denom schema fees are charged in
+	DefaultFeeDenom = "ucert"
+)
+
+// ModuleAddress is the attestation module account, which accumulates the
+// non-creator share of schema fees until governance withdraws or burns it
+var ModuleAddress = authtypes.NewModuleAddress(ModuleName)
+
+// HasFee reports whether attestations under the schema pay a fee
+func (s Schema) HasFee() bool {
+	return !s.Fee.IsZero()
+}
+
+// ValidateSchemaFee checks a schema fee and creator share independently of module params
+func ValidateSchemaFee(fee sdk.Coins, creatorShareBps uint32) error {
+	if err := fee.Validate(); err != nil {
+		return errors.Wrap(ErrInvalidSchemaFee, err.Error())
+	}
+	if creatorShareBps > BasisPointsDenominator {
+		return errors.Wrapf(ErrInvalidSchemaFee, "creator share %d bps exceeds 100%%", creatorShareBps)
+	}
+	if creatorShareBps > 0 && fee.IsZero() {
+		return errors.Wrap(ErrInvalidSchemaFee, "creator share requires a fee")
+	}
+	return nil
+}
+
+// SplitSchemaFee divides fee into the creator's cut (rounded down per denom)
+// and the remainder owed to the module account
+func SplitSchemaFee(fee sdk.Coins, creatorShareBps uint32) (creatorCut, moduleCut sdk.Coins) {
+	creatorCut = sdk.NewCoins()
+	for _, coin := range fee {
+		amount := coin.Amount.MulRaw(int64(creatorShareBps)).Quo(math.NewInt(BasisPointsDenominator))
+		creatorCut = creatorCut.Add(sdk.NewCoin(coin.Denom, amount))
+	}
+	return creatorCut, fee.Sub(creatorCut...)
+}
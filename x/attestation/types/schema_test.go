@@ -0,0 +1,163 @@
+package types_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/attestation/types"
+)
+
+func packSchemaData(t *testing.T, typeNames []string, values ...interface{}) []byte {
+	t.Helper()
+	args := make(abi.Arguments, len(typeNames))
+	for i, name := range typeNames {
+		typ, err := abi.NewType(name, "", nil)
+		require.NoError(t, err)
+		args[i] = abi.Argument{Type: typ}
+	}
+	data, err := args.Pack(values...)
+	require.NoError(t, err)
+	return data
+}
+
+func TestParseSchema(t *testing.T) {
+	fields, err := types.ParseSchema("bytes32 dataHash, string metadata, uint256 timestamp")
+	require.NoError(t, err)
+	require.Len(t, fields, 3)
+	require.Equal(t, "bytes32", fields[0].Type)
+	require.Equal(t, "metadata", fields[1].Name)
+
+	_, err = types.ParseSchema("bytes32 dataHash, notatype metadata")
+	require.ErrorIs(t, err, types.ErrInvalidSchemaFormat)
+	require.Contains(t, err.Error(), "field 2 (metadata)")
+
+	_, err = types.ParseSchema("uint256 a, bool a")
+	require.ErrorIs(t, err, types.ErrInvalidSchemaFormat)
+
+	_, err = types.ParseSchema("uint256")
+	require.ErrorIs(t, err, types.ErrInvalidSchemaFormat)
+}
+
+func TestValidateSchemaData(t *testing.T) {
+	publicSchema := "bytes32 dataHash, string metadata, uint256 timestamp"
+	var hash [32]byte
+	copy(hash[:], "document-hash")
+
+	recipients := []common.Address{
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+	}
+
+	t.Run("matching data", func(t *testing.T) {
+		data := packSchemaData(t, []string{"bytes32", "string", "uint256"}, hash, "diploma", big.NewInt(1700000000))
+		require.NoError(t, types.ValidateSchemaData(publicSchema, data))
+	})
+
+	t.Run("matching data with dynamic arrays", func(t *testing.T) {
+		schema := "string ipfsCID, bytes32 encryptedDataHash, address[] recipients, bytes[] encryptedSymmetricKeys, bool revocable"
+		data := packSchemaData(t,
+			[]string{"string", "bytes32", "address[]", "bytes[]", "bool"},
+			"bafybeigdyrzt", hash, recipients, [][]byte{{0x01, 0x02}, {0x03}}, true,
+		)
+		require.NoError(t, types.ValidateSchemaData(schema, data))
+	})
+
+	t.Run("grade and course", func(t *testing.T) {
+		schema := "uint256 grade,string course"
+		data := packSchemaData(t, []string{"uint256", "string"}, big.NewInt(92), "Distributed Systems")
+		require.NoError(t, types.ValidateSchemaData(schema, data))
+
+		// Fields in the wrong order: the course's offset word is read as the
+		// grade and the grade as the course's data offset
+		swapped := packSchemaData(t, []string{"string", "uint256"}, "Distributed Systems", big.NewInt(92))
+		err := types.ValidateSchemaData(schema, swapped)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.Contains(t, err.Error(), "field 2 (string course)")
+
+		mismatch := packSchemaData(t, []string{"uint256", "bool"}, big.NewInt(92), true)
+		err = types.ValidateSchemaData(schema, mismatch)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.Contains(t, err.Error(), "field 2 (string course)")
+	})
+
+	t.Run("fixed arrays", func(t *testing.T) {
+		schema := "uint8[3] scores, address[2] signers, string[2] notes, int64 delta"
+		data := packSchemaData(t, []string{"uint8[3]", "address[2]", "string[2]", "int64"},
+			[3]uint8{90, 85, 77}, [2]common.Address(recipients), [2]string{"a", "b"}, int64(-4))
+		require.NoError(t, types.ValidateSchemaData(schema, data))
+
+		// The head of three uint8 slots is too short for a fourth element
+		short := packSchemaData(t, []string{"uint8[2]"}, [2]uint8{90, 85})
+		err := types.ValidateSchemaData("uint8[3] scores", short)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.Contains(t, err.Error(), "field 1 (uint8[3] scores)")
+
+		overflow := packSchemaData(t, []string{"uint256[3]"}, [3]*big.Int{big.NewInt(1), big.NewInt(256), big.NewInt(3)})
+		err = types.ValidateSchemaData("uint8[3] scores", overflow)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+	})
+
+	t.Run("too few fields", func(t *testing.T) {
+		data := packSchemaData(t, []string{"bytes32", "uint256"}, hash, big.NewInt(1700000000))
+		err := types.ValidateSchemaData("bytes32 dataHash, uint256 timestamp, bool revocable", data)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.Contains(t, err.Error(), "field 3 (bool revocable)")
+
+		// A dynamic field's offset lands inside the larger head the schema expects
+		data = packSchemaData(t, []string{"bytes32", "string"}, hash, "diploma")
+		err = types.ValidateSchemaData(publicSchema, data)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.Contains(t, err.Error(), "schema expects 3 fields")
+	})
+
+	t.Run("too many fields", func(t *testing.T) {
+		data := packSchemaData(t, []string{"bytes32", "uint256", "uint256"}, hash, big.NewInt(1), big.NewInt(2))
+		err := types.ValidateSchemaData("bytes32 dataHash, uint256 timestamp", data)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.Contains(t, err.Error(), "trailing bytes")
+	})
+
+	t.Run("empty data", func(t *testing.T) {
+		err := types.ValidateSchemaData(publicSchema, nil)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.Contains(t, err.Error(), "field 1 (bytes32 dataHash)")
+	})
+
+	t.Run("bool type mismatch", func(t *testing.T) {
+		data := packSchemaData(t, []string{"uint256", "uint256"}, big.NewInt(1), big.NewInt(5))
+		err := types.ValidateSchemaData("uint256 id, bool active", data)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.Contains(t, err.Error(), "field 2 (bool active)")
+	})
+
+	t.Run("address type mismatch", func(t *testing.T) {
+		large := new(big.Int).Lsh(big.NewInt(1), 200)
+		data := packSchemaData(t, []string{"uint256"}, large)
+		err := types.ValidateSchemaData("address owner", data)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.Contains(t, err.Error(), "field 1 (address owner)")
+	})
+
+	t.Run("integer overflow", func(t *testing.T) {
+		data := packSchemaData(t, []string{"uint256"}, big.NewInt(300))
+		err := types.ValidateSchemaData("uint8 entityType", data)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.Contains(t, err.Error(), "field 1 (uint8 entityType)")
+	})
+
+	t.Run("non-ABI data", func(t *testing.T) {
+		err := types.ValidateSchemaData(publicSchema, []byte(`{"dataHash":"0x01"}`))
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+	})
+
+	t.Run("string offset out of range", func(t *testing.T) {
+		data := packSchemaData(t, []string{"uint256"}, big.NewInt(4096))
+		err := types.ValidateSchemaData("string metadata", data)
+		require.ErrorIs(t, err, types.ErrInvalidAttestationData)
+		require.Contains(t, err.Error(), "field 1 (string metadata)")
+	})
+}
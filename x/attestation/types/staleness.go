@@ -0,0 +1,41 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxSchemaStaleness caps a schema's MaxStaleness, in seconds (100 years)
+const MaxSchemaStaleness = 100 * 365 * 24 * 60 * 60
+
+// ValidateMaxStaleness checks a schema's re-affirmation window
+func ValidateMaxStaleness(maxStaleness uint64) error {
+	if maxStaleness > MaxSchemaStaleness {
+		return fmt.Errorf("max staleness %ds exceeds %ds", maxStaleness, uint64(MaxSchemaStaleness))
+	}
+	return nil
+}
+
+// RequiresReaffirmation reports whether attestations under the schema go stale
+func (s Schema) RequiresReaffirmation() bool {
+	return s.MaxStaleness > 0
+}
+
+// LastAffirmed returns when the attestation was last affirmed: its latest
+// reaffirmation, or its creation when it was never reaffirmed
+func (a Attestation) LastAffirmed() time.Time {
+	if !a.ReaffirmedTime.IsZero() {
+		return a.ReaffirmedTime
+	}
+	return a.Time
+}
+
+// IsStale reports whether an attestation under schema has gone unaffirmed for
+// longer than the schema's MaxStaleness at now. Staleness is independent of
+// expiration and revocation.
+func (s Schema) IsStale(attestation Attestation, now time.Time) bool {
+	if !s.RequiresReaffirmation() {
+		return false
+	}
+	return now.After(attestation.LastAffirmed().Add(time.Duration(s.MaxStaleness) * time.Second))
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"strings"
+
+	errorsmod "cosmossdk.io/errors"
+)
+
+const (
+	// MaxAttestationTags is the maximum number of tags on a single attestation
+	MaxAttestationTags = 10
+
+	// MaxTagLength is the maximum length of a single tag in bytes
+	MaxTagLength = 32
+)
+
+// NormalizeTags lowercases and trims tags, drops duplicates while keeping first-seen order,
+// and enforces the count and length caps. Tags may contain a-z, 0-9, '-', '_', '.' and ':'.
+func NormalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxAttestationTags {
+		return nil, errorsmod.Wrapf(ErrInvalidTags, "%d tags exceeds %d", len(normalized), MaxAttestationTags)
+	}
+	return normalized, nil
+}
+
+// NormalizeTag lowercases and trims a single tag and checks its length and characters
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", errorsmod.Wrap(ErrInvalidTags, "tag cannot be empty")
+	}
+	if len(tag) > MaxTagLength {
+		return "", errorsmod.Wrapf(ErrInvalidTags, "tag %q exceeds %d bytes", tag, MaxTagLength)
+	}
+	for _, c := range tag {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || strings.ContainsRune("-_.:", c)) {
+			return "", errorsmod.Wrapf(ErrInvalidTags, "tag %q contains %q", tag, c)
+		}
+	}
+	return tag, nil
+}
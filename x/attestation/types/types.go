@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"time"
 
+	errorsmod "cosmossdk.io/errors"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -31,6 +32,9 @@ const (
 	AttestationTypeEncryptedFile             = "encrypted_file"
 	AttestationTypeEncryptedMultiRecipient   = "encrypted_multi_recipient"
 	AttestationTypeEncryptedBusinessDocument = "encrypted_business_document"
+
+	// AttestationTypeEndorsement marks a third-party endorsement; RefUID is the endorsed attestation
+	AttestationTypeEndorsement = "endorsement"
 )
 
 // Attestation represents a generic on-chain attestation (EAS compatible)
@@ -68,6 +72,23 @@ type Attestation struct {
 
 	// AttestationType distinguishes public vs encrypted attestations
 	AttestationType string `json:"attestation_type" protobuf:"bytes,11,opt,name=attestation_type,proto3"`
+
+	// Tags are lightweight labels for categorization and filtering (e.g. "diploma", "2024-cohort")
+	Tags []string `json:"tags,omitempty" protobuf:"bytes,19,rep,name=tags,proto3"`
+
+	// Expired is set by EndBlock once ExpirationTime has passed
+	Expired bool `json:"expired,omitempty" protobuf:"varint,20,opt,name=expired,proto3"`
+
+	// ReaffirmedTime is when the attester last reaffirmed the attestation (0 = never)
+	ReaffirmedTime time.Time `json:"reaffirmed_time,omitempty" protobuf:"bytes,21,opt,name=reaffirmed_time,proto3,stdtime"`
+
+	// Stale is set on query results when the schema's MaxStaleness has passed
+	// since the attestation was last affirmed. It is never stored.
+	Stale bool `json:"stale,omitempty" protobuf:"varint,22,opt,name=stale,proto3"`
+
+	// Pruned is set when the attestation was pruned under the retention policy
+	// and only its tombstone remains; Data and Recipient are not kept
+	Pruned bool `json:"pruned,omitempty" protobuf:"varint,23,opt,name=pruned,proto3"`
 }
 
 // Proto interface implementations for Attestation
@@ -78,6 +99,23 @@ func (a *Attestation) ProtoMessage()  {}
 // XXX_MessageName returns the fully qualified protobuf message name
 func (*Attestation) XXX_MessageName() string { return "cert.attestation.v1.Attestation" }
 
+// AttestationReassignment links a reassigned attestation to the attestation
+// that replaced it
+type AttestationReassignment struct {
+	UID          string `json:"uid" protobuf:"bytes,1,opt,name=uid,proto3"`
+	ReassignedTo string `json:"reassigned_to" protobuf:"bytes,2,opt,name=reassigned_to,proto3"`
+}
+
+// Proto interface implementations for AttestationReassignment
+func (r *AttestationReassignment) Reset()         { *r = AttestationReassignment{} }
+func (r *AttestationReassignment) String() string { return r.UID }
+func (r *AttestationReassignment) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name
+func (*AttestationReassignment) XXX_MessageName() string {
+	return "cert.attestation.v1.AttestationReassignment"
+}
+
 // EncryptedAttestation extends Attestation with encryption-specific fields
 // Per Whitepaper Section 3.2 and 3.4
 type EncryptedAttestation struct {
@@ -148,6 +186,16 @@ type Schema struct {
 
 	// Creator is the address that registered this schema
 	Creator sdk.AccAddress `json:"creator" protobuf:"bytes,5,opt,name=creator,proto3"`
+
+	// Fee is paid by the attester for each attestation under this schema (optional)
+	Fee sdk.Coins `json:"fee,omitempty" protobuf:"bytes,6,rep,name=fee,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins"`
+
+	// CreatorShareBps is the creator's cut of Fee in basis points; the rest goes to the fee collector
+	CreatorShareBps uint32 `json:"creator_share_bps,omitempty" protobuf:"varint,7,opt,name=creator_share_bps,proto3"`
+
+	// MaxStaleness is how long, in seconds, an attestation stays fresh without
+	// reaffirmation (0 = never stale)
+	MaxStaleness uint64 `json:"max_staleness,omitempty" protobuf:"varint,8,opt,name=max_staleness,proto3"`
 }
 
 // Proto interface implementations for Schema
@@ -191,6 +239,35 @@ type Params struct {
 
 	// AttestationFee is the fee for creating an attestation (optional)
 	AttestationFee sdk.Coins `json:"attestation_fee" protobuf:"bytes,3,rep,name=attestation_fee,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins"`
+
+	// MaxBatchRevocationsPerBlock caps attestations revoked through MsgBatchRevoke in a single block
+	MaxBatchRevocationsPerBlock uint32 `json:"max_batch_revocations_per_block" protobuf:"varint,4,opt,name=max_batch_revocations_per_block,proto3"`
+
+	// RestrictSchemas limits attestations to schemas registered by AllowedSchemaCreators (permissioned deployments)
+	RestrictSchemas bool `json:"restrict_schemas" protobuf:"varint,5,opt,name=restrict_schemas,proto3"`
+
+	// AllowedSchemaCreators lists the schema creators whose schemas may be attested against when RestrictSchemas is set
+	AllowedSchemaCreators []string `json:"allowed_schema_creators,omitempty" protobuf:"bytes,6,rep,name=allowed_schema_creators,proto3"`
+
+	// MaxAttestationDataSize caps the uncompressed size in bytes of public attestation data
+	MaxAttestationDataSize uint64 `json:"max_attestation_data_size" protobuf:"varint,7,opt,name=max_attestation_data_size,proto3"`
+
+	// MaxSchemaCreatorShareBps caps the creator's cut of a schema fee, in basis points
+	MaxSchemaCreatorShareBps uint32 `json:"max_schema_creator_share_bps" protobuf:"varint,8,opt,name=max_schema_creator_share_bps,proto3"`
+
+	// FeeDenom is the only denom schema fees may be charged in
+	FeeDenom string `json:"fee_denom" protobuf:"bytes,9,opt,name=fee_denom,proto3"`
+
+	// RetentionPeriod is how long, in seconds, revoked and expired attestations
+	// are kept in full before EndBlock prunes them to tombstones (0 = keep forever)
+	RetentionPeriod uint64 `json:"retention_period,omitempty" protobuf:"varint,10,opt,name=retention_period,proto3"`
+
+	// MaxPrunesPerBlock caps the attestations EndBlock prunes in a single block
+	MaxPrunesPerBlock uint32 `json:"max_prunes_per_block,omitempty" protobuf:"varint,11,opt,name=max_prunes_per_block,proto3"`
+
+	// MinAttestationTxFee is the minimum tx fee per attestation a tx creates, paid
+	// as the tx fee on top of AttestationFee (optional)
+	MinAttestationTxFee sdk.Coins `json:"min_attestation_tx_fee" protobuf:"bytes,12,rep,name=min_attestation_tx_fee,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins"`
 }
 
 // Proto interface implementations for Params
@@ -201,11 +278,80 @@ func (p *Params) ProtoMessage()  {}
 // XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
 func (*Params) XXX_MessageName() string { return "cert.attestation.v1.Params" }
 
+// DefaultMaxBatchRevocationsPerBlock is the default per-block cap on batch revocations
+const DefaultMaxBatchRevocationsPerBlock = 500
+
+// DefaultMaxAttestationDataSize is the default cap on uncompressed public attestation data
+const DefaultMaxAttestationDataSize = 64 * 1024
+
+const (
+	// DefaultMaxRecipientsPerAttestation is the default recipient cap per encrypted attestation (Whitepaper Section 12)
+	DefaultMaxRecipientsPerAttestation = 50
+
+	// MaxRecipientsLimit is the most recipients any encrypted attestation may have,
+	// whatever MaxRecipientsPerAttestation governance sets
+	MaxRecipientsLimit = 1000
+
+	// DefaultMaxEncryptedFileSize is the default cap on encrypted file size, 100 MB (Whitepaper Section 12)
+	DefaultMaxEncryptedFileSize = 100 * 1024 * 1024
+)
+
 // DefaultParams returns default module parameters per Whitepaper Section 12
 func DefaultParams() Params {
 	return Params{
-		MaxRecipientsPerAttestation: 50,                // Whitepaper Section 12
-		MaxEncryptedFileSize:        100 * 1024 * 1024, // 100 MB - Whitepaper Section 12
-		AttestationFee:              sdk.NewCoins(),    // No fee by default
+		MaxRecipientsPerAttestation: DefaultMaxRecipientsPerAttestation,
+		MaxEncryptedFileSize:        DefaultMaxEncryptedFileSize,
+		AttestationFee:              sdk.NewCoins(), // No fee by default
+		MaxBatchRevocationsPerBlock: DefaultMaxBatchRevocationsPerBlock,
+		RestrictSchemas:             false, // Open deployment by default
+		MaxAttestationDataSize:      DefaultMaxAttestationDataSize,
+		MaxSchemaCreatorShareBps:    DefaultMaxSchemaCreatorShareBps,
+		FeeDenom:                    DefaultFeeDenom,
+		MaxPrunesPerBlock:           DefaultMaxPrunesPerBlock,
+		MinAttestationTxFee:         sdk.NewCoins(), // No minimum by default
+	}
+}
+
+// Validate checks that the params are within the bounds the module enforces
+func (p Params) Validate() error {
+	if p.MaxRecipientsPerAttestation == 0 || p.MaxRecipientsPerAttestation > MaxRecipientsLimit {
+		return errorsmod.Wrapf(ErrInvalidParams, "max recipients per attestation must be between 1 and %d", MaxRecipientsLimit)
+	}
+	if p.MaxEncryptedFileSize == 0 {
+		return errorsmod.Wrap(ErrInvalidParams, "max encrypted file size must be positive")
+	}
+	if err := p.AttestationFee.Validate(); err != nil {
+		return errorsmod.Wrapf(ErrInvalidParams, "attestation fee: %s", err)
+	}
+	if err := p.MinAttestationTxFee.Validate(); err != nil {
+		return errorsmod.Wrapf(ErrInvalidParams, "min attestation tx fee: %s", err)
+	}
+	if p.MaxSchemaCreatorShareBps > BasisPointsDenominator {
+		return errorsmod.Wrapf(ErrInvalidParams, "max schema creator share %d bps exceeds %d", p.MaxSchemaCreatorShareBps, BasisPointsDenominator)
+	}
+	if p.FeeDenom != "" && sdk.ValidateDenom(p.FeeDenom) != nil {
+		return errorsmod.Wrapf(ErrInvalidParams, "invalid fee denom %q", p.FeeDenom)
+	}
+	if p.RetentionPeriod > MaxRetentionPeriod {
+		return errorsmod.Wrapf(ErrInvalidParams, "retention period %ds exceeds %ds", p.RetentionPeriod, uint64(MaxRetentionPeriod))
+	}
+	for _, creator := range p.AllowedSchemaCreators {
+		if _, err := sdk.AccAddressFromBech32(creator); err != nil {
+			return errorsmod.Wrapf(ErrInvalidParams, "invalid schema creator address %q", creator)
+		}
+	}
+	return nil
+}
+
+// IsSchemaCreatorAllowed reports whether attestations may use schemas registered by creator
+func (p Params) IsSchemaCreatorAllowed(creator sdk.AccAddress) bool {
+	if !p.RestrictSchemas {
+		return true
+	}
+	for _, allowed := range p.AllowedSchemaCreators {
+		if allowed == creator.String() {
+			return true
+		}
 	}
+	return false
 }
@@ -57,6 +57,7 @@ func TestDefaultParams(t *testing.T) {
 	require.Equal(t, uint32(50), params.MaxRecipientsPerAttestation)
 	require.Equal(t, uint64(100*1024*1024), params.MaxEncryptedFileSize)
 	require.True(t, params.AttestationFee.IsZero())
+	require.True(t, params.MinAttestationTxFee.IsZero())
 }
 
 func TestAttestationTypes(t *testing.T) {
@@ -0,0 +1,41 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chaincertify/certd/x/certid/keeper"
+	"github.com/chaincertify/certd/x/certid/types"
+)
+
+// TestStateTimestampsUseBlockTime tests that stored timestamps come from the
+// block header, so every validator writes identical state
+func TestStateTimestampsUseBlockTime(t *testing.T) {
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	created := time.Unix(1_700_000_000, 0).UTC()
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).WithBlockTime(created)
+	k := keeper.NewKeeper(nil, storeKey, nil, nil, "authority")
+	user := sdk.AccAddress("certid_user_________").String()
+
+	profile, err := k.CreateProfile(ctx, &types.MsgCreateProfile{Creator: user, Name: "alice"})
+	require.NoError(t, err)
+	require.True(t, profile.CreatedAt.Equal(created), "created_at = %s", profile.CreatedAt)
+	require.True(t, profile.UpdatedAt.Equal(created), "updated_at = %s", profile.UpdatedAt)
+
+	awarded := created.Add(time.Hour)
+	ctx = ctx.WithBlockTime(awarded)
+	require.NoError(t, k.AwardBadge(ctx, &types.MsgAwardBadge{Authority: "authority", User: user, BadgeName: types.BadgeKYCL1}))
+
+	profile, err = k.GetProfile(ctx, user)
+	require.NoError(t, err)
+	badge := profile.Badges[types.BadgeID(types.BadgeKYCL1)]
+	require.NotNil(t, badge)
+	require.True(t, badge.AwardedAt.Equal(awarded), "awarded_at = %s", badge.AwardedAt)
+	require.True(t, profile.UpdatedAt.Equal(awarded), "updated_at = %s", profile.UpdatedAt)
+	require.True(t, profile.CreatedAt.Equal(created))
+}
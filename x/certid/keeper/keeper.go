@@ -69,7 +69,7 @@ func (k Keeper) CreateProfile(ctx sdk.Context, msg *types.MsgCreateProfile) (*ty
 	}
 
 	// Create new profile
-	profile := types.NewCertID(msg.Creator)
+	profile := types.NewCertID(msg.Creator, ctx.BlockTime())
 	profile.Name = msg.Name
 	profile.Bio = msg.Bio
 	profile.AvatarCID = msg.AvatarCID
@@ -191,7 +191,7 @@ func (k Keeper) UpdateProfile(ctx sdk.Context, msg *types.MsgUpdateProfile) (*ty
 			profile.SocialLinks[k] = v
 		}
 	}
-	profile.UpdatedAt = time.Now()
+	profile.UpdatedAt = ctx.BlockTime()
 
 	// Store updated profile
 	bz, err := json.Marshal(profile)
@@ -242,7 +242,7 @@ func (k Keeper) AddCredential(ctx sdk.Context, msg *types.MsgAddCredential) erro
 
 	// Add credential
 	profile.Credentials = append(profile.Credentials, msg.AttestationUID)
-	profile.UpdatedAt = time.Now()
+	profile.UpdatedAt = ctx.BlockTime()
 
 	// Store updated profile
 	bz, err := json.Marshal(profile)
@@ -271,7 +271,7 @@ func (k Keeper) VerifySocial(ctx sdk.Context, msg *types.MsgVerifySocial) error
 
 	// Update social links
 	profile.SocialLinks[msg.Platform] = msg.Handle
-	profile.UpdatedAt = time.Now()
+	profile.UpdatedAt = ctx.BlockTime()
 
 	// Update verification level if applicable
 	if profile.VerificationLevel < 2 {
@@ -290,7 +290,7 @@ func (k Keeper) VerifySocial(ctx sdk.Context, msg *types.MsgVerifySocial) error
 		Platform:   msg.Platform,
 		Handle:     msg.Handle,
 		Verified:   true,
-		VerifiedAt: time.Now(),
+		VerifiedAt: ctx.BlockTime(),
 	}
 	verificationBz, _ := json.Marshal(verification)
 	store.Set(types.GetSocialVerificationKey(msg.Creator, msg.Platform), verificationBz)
@@ -315,7 +315,7 @@ func (k Keeper) AwardBadge(ctx sdk.Context, msg *types.MsgAwardBadge) error {
 	}
 
 	// Create badge
-	badge := types.NewBadge(msg.BadgeName, msg.Description, msg.Authority)
+	badge := types.NewBadge(msg.BadgeName, msg.Description, msg.Authority, ctx.BlockTime())
 
 	// Check if badge already awarded
 	if profile.Badges == nil {
@@ -327,7 +327,7 @@ func (k Keeper) AwardBadge(ctx sdk.Context, msg *types.MsgAwardBadge) error {
 
 	// Award badge
 	profile.Badges[badge.ID] = badge
-	profile.UpdatedAt = time.Now()
+	profile.UpdatedAt = ctx.BlockTime()
 
 	// Store updated profile
 	bz, err := json.Marshal(profile)
@@ -364,15 +364,15 @@ func (k Keeper) RevokeBadge(ctx sdk.Context, msg *types.MsgRevokeBadge) error {
 	}
 
 	// Find badge by name
-	badge := types.NewBadge(msg.BadgeName, "", "")
-	if profile.Badges == nil || profile.Badges[badge.ID] == nil {
+	badgeID := types.BadgeID(msg.BadgeName)
+	if profile.Badges == nil || profile.Badges[badgeID] == nil {
 		return types.ErrBadgeNotFound
 	}
 
 	// Mark badge as revoked
-	now := time.Now()
-	profile.Badges[badge.ID].IsRevoked = true
-	profile.Badges[badge.ID].RevokedAt = &now
+	now := ctx.BlockTime()
+	profile.Badges[badgeID].IsRevoked = true
+	profile.Badges[badgeID].RevokedAt = &now
 	profile.UpdatedAt = now
 
 	// Store updated profile
@@ -388,9 +388,9 @@ func (k Keeper) RevokeBadge(ctx sdk.Context, msg *types.MsgRevokeBadge) error {
 			types.EventTypeBadgeRevoked,
 			sdk.NewAttribute(types.AttributeKeyUser, msg.User),
 			sdk.NewAttribute(types.AttributeKeyBadgeName, msg.BadgeName),
-			sdk.NewAttribute(types.AttributeKeyBadgeID, badge.ID),
+			sdk.NewAttribute(types.AttributeKeyBadgeID, badgeID),
 			sdk.NewAttribute(types.AttributeKeyTimestamp, fmt.Sprintf("%d", ctx.BlockTime().Unix())),
-			sdk.NewAttribute(types.AttributeKeyProofHash, k.GenerateBadgeProof(ctx, msg.User, badge.ID)),
+			sdk.NewAttribute(types.AttributeKeyProofHash, k.GenerateBadgeProof(ctx, msg.User, badgeID)),
 		),
 	)
 
@@ -417,7 +417,7 @@ func (k Keeper) UpdateTrustScore(ctx sdk.Context, msg *types.MsgUpdateTrustScore
 
 	oldScore := profile.TrustScore
 	profile.TrustScore = msg.Score
-	profile.UpdatedAt = time.Now()
+	profile.UpdatedAt = ctx.BlockTime()
 
 	// Store updated profile
 	bz, err := json.Marshal(profile)
@@ -456,7 +456,7 @@ func (k Keeper) SetVerificationStatus(ctx sdk.Context, msg *types.MsgSetVerifica
 	}
 
 	profile.Verified = msg.IsVerified
-	profile.UpdatedAt = time.Now()
+	profile.UpdatedAt = ctx.BlockTime()
 
 	// Store updated profile
 	bz, err := json.Marshal(profile)
@@ -488,12 +488,11 @@ func (k Keeper) HasBadge(ctx sdk.Context, address, badgeName string) (bool, erro
 		return false, err
 	}
 
-	badge := types.NewBadge(badgeName, "", "")
 	if profile.Badges == nil {
 		return false, nil
 	}
 
-	b, exists := profile.Badges[badge.ID]
+	b, exists := profile.Badges[types.BadgeID(badgeName)]
 	if !exists {
 		return false, nil
 	}
@@ -536,7 +535,7 @@ func (k Keeper) RemoveCredential(ctx sdk.Context, msg *types.MsgRemoveCredential
 	}
 
 	profile.Credentials = credentials
-	profile.UpdatedAt = time.Now()
+	profile.UpdatedAt = ctx.BlockTime()
 
 	// Store updated profile
 	bz, err := json.Marshal(profile)
@@ -574,7 +573,7 @@ func (k Keeper) RegisterHandle(ctx sdk.Context, msg *types.MsgRegisterHandle) er
 
 	// Update profile with new handle
 	profile.Handle = msg.Handle
-	profile.UpdatedAt = time.Now()
+	profile.UpdatedAt = ctx.BlockTime()
 
 	// Store profile
 	bz, err := json.Marshal(profile)
@@ -612,7 +611,7 @@ func (k Keeper) AuthorizeOracle(ctx sdk.Context, msg *types.MsgAuthorizeOracle)
 	auth := types.OracleAuthorization{
 		Address:      msg.Oracle,
 		IsAuthorized: true,
-		AuthorizedAt: time.Now(),
+		AuthorizedAt: ctx.BlockTime(),
 		AuthorizedBy: msg.Authority,
 	}
 
@@ -693,7 +692,7 @@ func (k Keeper) RequestVerification(ctx sdk.Context, msg *types.MsgRequestVerifi
 		RequestType:      msg.VerificationType,
 		VerificationData: msg.VerificationData,
 		Status:           "pending",
-		CreatedAt:        time.Now(),
+		CreatedAt:        ctx.BlockTime(),
 	}
 
 	bz, err := json.Marshal(request)
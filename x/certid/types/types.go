@@ -107,9 +107,8 @@ func ValidCredentialTypes() []string {
 // Per Whitepaper CertID Section
 // CertID struct is defined in certid.pb.go
 
-// NewCertID creates a new CertID profile
-func NewCertID(address string) *CertID {
-	now := time.Now()
+// NewCertID creates a new CertID profile created at now (the block time in state transitions)
+func NewCertID(address string, now time.Time) *CertID {
 	return &CertID{
 		Address:           address,
 		EntityType:        uint32(EntityTypeIndividual),
@@ -203,14 +202,19 @@ const (
 // Badge represents a soulbound token (non-transferable badge)
 // Badge struct is defined in certid.pb.go
 
-// NewBadge creates a new Badge
-func NewBadge(name, description, awardedBy string) *Badge {
+// BadgeID returns the deterministic ID of the badge with the given name
+func BadgeID(name string) string {
 	hash := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(hash[:])
+}
+
+// NewBadge creates a new Badge awarded at awardedAt (the block time in state transitions)
+func NewBadge(name, description, awardedBy string, awardedAt time.Time) *Badge {
 	return &Badge{
-		ID:          hex.EncodeToString(hash[:]),
+		ID:          BadgeID(name),
 		Name:        name,
 		Description: description,
-		AwardedAt:   time.Now(),
+		AwardedAt:   awardedAt,
 		AwardedBy:   awardedBy,
 		IsRevoked:   false,
 	}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+// GetQueryCmd returns the query commands for the hardware module
+func GetQueryCmd() *cobra.Command {
+	hardwareQueryCmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Querying commands for the hardware module",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	hardwareQueryCmd.AddCommand(
+		CmdQueryDevicesByOwner(),
+		CmdQueryStats(),
+		CmdQueryDevice(),
+		CmdQueryHumanityScore(),
+	)
+
+	return hardwareQueryCmd
+}
+
+// CmdQueryDevicesByOwner queries the devices owned by an address
+func CmdQueryDevicesByOwner() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "devices-by-owner [address]",
+		Short: "Query the devices owned by an address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			pageReq, err := client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.DevicesByOwner(cmd.Context(), &types.QueryDevicesByOwnerRequest{
+				Owner:      args[0],
+				Pagination: pageReq,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	flags.AddPaginationFlagsToCmd(cmd, "devices-by-owner")
+	return cmd
+}
+
+// CmdQueryStats queries the network-wide hardware stats
+func CmdQueryStats() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Query network-wide hardware device stats",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Stats(cmd.Context(), &types.QueryStatsRequest{})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryDevice queries a registered device by ID
+func CmdQueryDevice() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "device [device-id]",
+		Short: "Query a registered hardware device",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Device(cmd.Context(), &types.QueryDeviceRequest{DeviceID: args[0]})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryHumanityScore queries an address's humanity score
+func CmdQueryHumanityScore() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "humanity-score [address]",
+		Short: "Query the humanity score of an address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.HumanityScore(cmd.Context(), &types.QueryHumanityScoreRequest{Address: args[0]})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
@@ -0,0 +1,190 @@
+package keeper
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+// RequestAttestationChallenge issues a one-time nonce bound to a device, replacing
+// any challenge still pending for it. Devices suspended by the congruence audit
+// may request one to appeal with. The nonce must be derived deterministically
+// so every validator stores the same value: it hashes the device, requester and
+// block with a module-wide sequence, which no one can predict before the block is
+// proposed and which never repeats.
+func (k Keeper) RequestAttestationChallenge(ctx sdk.Context, msg *types.MsgRequestAttestationChallenge) (*types.AttestationChallenge, error) {
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	device, err := k.GetDevice(ctx, msg.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.OwnerAddress != msg.Owner {
+		return nil, types.ErrDeviceNotOwned
+	}
+	// An audit-suspended device still gets a challenge, which its appeal answers
+	if device.IsSuspended && device.SuspensionReason != types.SuspensionReasonCongruenceAudit {
+		return nil, types.ErrDeviceSuspended
+	}
+
+	challenge := types.AttestationChallenge{
+		DeviceID:  device.DeviceID,
+		Nonce:     k.nextChallengeNonce(ctx, device.DeviceID, msg.Owner),
+		Requester: msg.Owner,
+		IssuedAt:  ctx.BlockTime(),
+		ExpiresAt: ctx.BlockTime().Add(types.ChallengeTTL),
+	}
+	if err := k.setPendingChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeChallengeIssued,
+			sdk.NewAttribute(types.AttributeKeyDeviceID, device.DeviceID),
+			sdk.NewAttribute(types.AttributeKeyOwner, msg.Owner),
+			sdk.NewAttribute(types.AttributeKeyExpiresAt, strconv.FormatInt(challenge.ExpiresAt.Unix(), 10)),
+		),
+	)
+
+	return &challenge, nil
+}
+
+// GetPendingChallenge returns the unanswered challenge for a device, if any
+func (k Keeper) GetPendingChallenge(ctx sdk.Context, deviceID string) (*types.AttestationChallenge, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetPendingChallengeKey(deviceID))
+	if bz == nil {
+		return nil, false
+	}
+
+	var challenge types.AttestationChallenge
+	if err := json.Unmarshal(bz, &challenge); err != nil {
+		return nil, false
+	}
+	return &challenge, true
+}
+
+// SubmitAttestation verifies a device attestation and records it. Periodic and
+// challenge attestations must answer the device's pending challenge, which is
+// consumed so the same attestation cannot be submitted twice.
+func (k Keeper) SubmitAttestation(ctx sdk.Context, msg *types.MsgSubmitAttestation) (*types.Device, error) {
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	device, err := k.GetDevice(ctx, msg.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.OwnerAddress != msg.Submitter {
+		return nil, types.ErrDeviceNotOwned
+	}
+	if device.IsSuspended {
+		return nil, types.ErrDeviceSuspended
+	}
+
+	if msg.AttestationType == types.AttestationTypePeriodic || msg.AttestationType == types.AttestationTypeChallenge {
+		if err := k.consumeChallenge(ctx, device.DeviceID, msg.Nonce); err != nil {
+			return nil, err
+		}
+	}
+
+	verified, err := k.VerifyAttestation(ctx, device.DeviceID, device.TEEType, msg.AttestationData, msg.Nonce)
+	if err != nil || !verified {
+		return nil, types.ErrAttestationFailed.Wrapf("%s attestation verification failed", msg.AttestationType)
+	}
+
+	now := ctx.BlockTime()
+	attestation := types.TEEAttestation{
+		DeviceID:        device.DeviceID,
+		AttestationData: msg.AttestationData,
+		Nonce:           msg.Nonce,
+		Timestamp:       now,
+		AttestationType: msg.AttestationType,
+		Verified:        true,
+		VerifiedAt:      &now,
+	}
+	bz, err := json.Marshal(attestation)
+	if err != nil {
+		return nil, err
+	}
+	ctx.KVStore(k.storeKey).Set(types.GetAttestationKey(device.DeviceID, now.Unix()), bz)
+
+	device.AttestationCount++
+	device.LastAttestAt = now
+	if msg.GeoBucket != "" {
+		device.GeoBucket = msg.GeoBucket
+	}
+	if err := k.SetDevice(ctx, *device); err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestationVerified,
+			sdk.NewAttribute(types.AttributeKeyDeviceID, device.DeviceID),
+			sdk.NewAttribute(types.AttributeKeyAttestationType, string(msg.AttestationType)),
+			sdk.NewAttribute(types.AttributeKeyOwner, msg.Submitter),
+		),
+	)
+
+	return device, nil
+}
+
+// consumeChallenge checks nonce against the device's pending challenge and
+// deletes the challenge. An expired challenge is deleted as well; the device
+// has to request a new one.
+func (k Keeper) consumeChallenge(ctx sdk.Context, deviceID string, nonce []byte) error {
+	challenge, found := k.GetPendingChallenge(ctx, deviceID)
+	if !found {
+		return types.ErrChallengeNotFound.Wrapf("device %s", deviceID)
+	}
+	if challenge.IsExpired(ctx.BlockTime()) {
+		ctx.KVStore(k.storeKey).Delete(types.GetPendingChallengeKey(deviceID))
+		return types.ErrChallengeExpired.Wrapf("challenge expired at %s", challenge.ExpiresAt)
+	}
+	if !bytes.Equal(challenge.Nonce, nonce) {
+		return types.ErrChallengeMismatch
+	}
+
+	ctx.KVStore(k.storeKey).Delete(types.GetPendingChallengeKey(deviceID))
+	return nil
+}
+
+func (k Keeper) setPendingChallenge(ctx sdk.Context, challenge types.AttestationChallenge) error {
+	bz, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	ctx.KVStore(k.storeKey).Set(types.GetPendingChallengeKey(challenge.DeviceID), bz)
+	return nil
+}
+
+// nextChallengeNonce derives a fresh nonce and advances the challenge sequence
+func (k Keeper) nextChallengeNonce(ctx sdk.Context, deviceID, requester string) []byte {
+	store := ctx.KVStore(k.storeKey)
+	var sequence uint64
+	if bz := store.Get(types.ChallengeSequenceKey); len(bz) == 8 {
+		sequence = binary.BigEndian.Uint64(bz)
+	}
+	sequence++
+	store.Set(types.ChallengeSequenceKey, sdk.Uint64ToBigEndian(sequence))
+
+	h := sha256.New()
+	h.Write([]byte(deviceID))
+	h.Write([]byte(requester))
+	h.Write(sdk.Uint64ToBigEndian(uint64(ctx.BlockHeight())))
+	h.Write(sdk.Uint64ToBigEndian(uint64(ctx.BlockTime().UnixNano())))
+	h.Write(ctx.HeaderHash())
+	h.Write(sdk.Uint64ToBigEndian(sequence))
+	return h.Sum(nil)
+}
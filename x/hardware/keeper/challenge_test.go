@@ -0,0 +1,136 @@
+package keeper_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/keeper"
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+func TestAttestationChallenge(t *testing.T) {
+	owner := sdk.AccAddress("device_owner________").String()
+	now := time.Unix(1_700_000_000, 0).UTC()
+
+	setup := func(t *testing.T) (sdk.Context, types.MsgServer, keeper.Keeper, string) {
+		t.Helper()
+		storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+		ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).
+			WithBlockHeight(10).
+			WithBlockTime(now)
+		k := keeper.NewKeeper(nil, storeKey, "", nil)
+		ms := keeper.NewMsgServerImpl(k)
+
+		res, err := ms.RegisterDevice(ctx, &types.MsgRegisterDevice{
+			Creator:            owner,
+			Manufacturer:       "Acme",
+			TEEType:            types.TEETypeTrustZone,
+			PublicKey:          []byte("pubkey"),
+			InitialAttestation: []byte("attestation"),
+		})
+		if err != nil {
+			t.Fatalf("RegisterDevice: %v", err)
+		}
+		return ctx, ms, k, res.DeviceID
+	}
+	heartbeat := func(deviceID string, nonce []byte) *types.MsgSubmitAttestation {
+		return &types.MsgSubmitAttestation{
+			Submitter:       owner,
+			DeviceID:        deviceID,
+			AttestationData: []byte("heartbeat"),
+			Nonce:           nonce,
+			AttestationType: types.AttestationTypePeriodic,
+		}
+	}
+
+	t.Run("a nonce can only be consumed once", func(t *testing.T) {
+		ctx, ms, k, deviceID := setup(t)
+		challenge, err := ms.RequestAttestationChallenge(ctx, &types.MsgRequestAttestationChallenge{Owner: owner, DeviceID: deviceID})
+		if err != nil {
+			t.Fatalf("RequestAttestationChallenge: %v", err)
+		}
+		if len(challenge.Nonce) != types.ChallengeNonceSize {
+			t.Fatalf("nonce length = %d, want %d", len(challenge.Nonce), types.ChallengeNonceSize)
+		}
+		if want := now.Add(types.ChallengeTTL).Unix(); challenge.ExpiresAt != want {
+			t.Errorf("expires at = %d, want %d", challenge.ExpiresAt, want)
+		}
+
+		res, err := ms.SubmitAttestation(ctx, heartbeat(deviceID, challenge.Nonce))
+		if err != nil {
+			t.Fatalf("SubmitAttestation: %v", err)
+		}
+		if res.AttestationCount != 2 {
+			t.Errorf("attestation count = %d, want 2", res.AttestationCount)
+		}
+		if _, found := k.GetPendingChallenge(ctx, deviceID); found {
+			t.Error("challenge still pending after use")
+		}
+
+		if _, err := ms.SubmitAttestation(ctx, heartbeat(deviceID, challenge.Nonce)); !errors.Is(err, types.ErrChallengeNotFound) {
+			t.Fatalf("replayed attestation: got %v, want ErrChallengeNotFound", err)
+		}
+		device, err := k.GetDevice(ctx, deviceID)
+		if err != nil {
+			t.Fatalf("GetDevice: %v", err)
+		}
+		if device.AttestationCount != 2 {
+			t.Errorf("attestation count after replay = %d, want 2", device.AttestationCount)
+		}
+	})
+
+	t.Run("rejects an expired nonce", func(t *testing.T) {
+		ctx, ms, k, deviceID := setup(t)
+		challenge, err := ms.RequestAttestationChallenge(ctx, &types.MsgRequestAttestationChallenge{Owner: owner, DeviceID: deviceID})
+		if err != nil {
+			t.Fatalf("RequestAttestationChallenge: %v", err)
+		}
+
+		late := ctx.WithBlockTime(now.Add(types.ChallengeTTL))
+		if _, err := ms.SubmitAttestation(late, heartbeat(deviceID, challenge.Nonce)); !errors.Is(err, types.ErrChallengeExpired) {
+			t.Fatalf("expired nonce: got %v, want ErrChallengeExpired", err)
+		}
+		if _, found := k.GetPendingChallenge(ctx, deviceID); found {
+			t.Error("expired challenge was not cleared")
+		}
+	})
+
+	t.Run("rejects an unknown nonce", func(t *testing.T) {
+		ctx, ms, _, deviceID := setup(t)
+		first, err := ms.RequestAttestationChallenge(ctx, &types.MsgRequestAttestationChallenge{Owner: owner, DeviceID: deviceID})
+		if err != nil {
+			t.Fatalf("RequestAttestationChallenge: %v", err)
+		}
+		second, err := ms.RequestAttestationChallenge(ctx, &types.MsgRequestAttestationChallenge{Owner: owner, DeviceID: deviceID})
+		if err != nil {
+			t.Fatalf("RequestAttestationChallenge: %v", err)
+		}
+		if string(first.Nonce) == string(second.Nonce) {
+			t.Fatal("two challenges in one block share a nonce")
+		}
+
+		// A new request replaces the pending challenge
+		if _, err := ms.SubmitAttestation(ctx, heartbeat(deviceID, first.Nonce)); !errors.Is(err, types.ErrChallengeMismatch) {
+			t.Fatalf("superseded nonce: got %v, want ErrChallengeMismatch", err)
+		}
+		if _, err := ms.SubmitAttestation(ctx, heartbeat(deviceID, second.Nonce)); err != nil {
+			t.Fatalf("SubmitAttestation: %v", err)
+		}
+	})
+
+	t.Run("only the owner requests a challenge", func(t *testing.T) {
+		ctx, ms, _, deviceID := setup(t)
+		other := sdk.AccAddress("someone_else________").String()
+		if _, err := ms.RequestAttestationChallenge(ctx, &types.MsgRequestAttestationChallenge{Owner: other, DeviceID: deviceID}); !errors.Is(err, types.ErrDeviceNotOwned) {
+			t.Fatalf("non-owner request: got %v, want ErrDeviceNotOwned", err)
+		}
+		if err := heartbeat(deviceID, nil).ValidateBasic(); !errors.Is(err, types.ErrChallengeMismatch) {
+			t.Errorf("periodic attestation without a nonce: got %v, want ErrChallengeMismatch", err)
+		}
+	})
+}
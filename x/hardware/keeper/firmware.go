@@ -0,0 +1,148 @@
+package keeper
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+// SetFirmwareRelease registers a signed firmware release. Only the module
+// authority (governance) may manage the firmware registry.
+func (k Keeper) SetFirmwareRelease(ctx sdk.Context, msg *types.MsgSetFirmwareRelease) error {
+	if msg.Authority != k.authority {
+		return types.ErrUnauthorized.Wrapf("expected %s, got %s", k.authority, msg.Authority)
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	release := msg.Release
+	release.ReleasedAt = ctx.BlockTime()
+	bz, err := json.Marshal(release)
+	if err != nil {
+		return types.ErrInvalidFirmware.Wrap("failed to marshal firmware release")
+	}
+	ctx.KVStore(k.storeKey).Set(types.GetFirmwareReleaseKey(release.Manufacturer, release.Model, release.Version), bz)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeFirmwareReleased,
+			sdk.NewAttribute(types.AttributeKeyManufacturer, release.Manufacturer),
+			sdk.NewAttribute(types.AttributeKeyModel, release.Model),
+			sdk.NewAttribute(types.AttributeKeyFirmwareVersion, strconv.FormatUint(release.Version, 10)),
+			sdk.NewAttribute(types.AttributeKeyFirmwareHash, hex.EncodeToString(release.Hash)),
+		),
+	)
+	return nil
+}
+
+// GetFirmwareRelease returns a registered firmware release
+func (k Keeper) GetFirmwareRelease(ctx sdk.Context, manufacturer, model string, version uint64) (*types.FirmwareRelease, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(types.GetFirmwareReleaseKey(manufacturer, model, version))
+	if bz == nil {
+		return nil, false
+	}
+	var release types.FirmwareRelease
+	if err := json.Unmarshal(bz, &release); err != nil {
+		return nil, false
+	}
+	return &release, true
+}
+
+// GetLatestFirmware returns the newest registered release for a manufacturer/model
+func (k Keeper) GetLatestFirmware(ctx sdk.Context, manufacturer, model string) (*types.FirmwareRelease, bool) {
+	iterator := storetypes.KVStoreReversePrefixIterator(ctx.KVStore(k.storeKey), types.GetFirmwareModelPrefix(manufacturer, model))
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var release types.FirmwareRelease
+		if err := json.Unmarshal(iterator.Value(), &release); err == nil {
+			return &release, true
+		}
+	}
+	return nil, false
+}
+
+// DeviceTrustFactors builds the trust score inputs for a device, checking its
+// reported firmware against the firmware registry. Firmware that was never
+// reported, is not registered for the device's model, or whose hash differs
+// from the signed release counts as unverified.
+func (k Keeper) DeviceTrustFactors(ctx sdk.Context, device types.Device) types.DeviceTrustFactors {
+	factors := types.DeviceTrustFactors{
+		TEEAttestationValid: !device.IsSuspended,
+		Uptime:              device.Uptime / 100,
+		DataCongruence:      device.DataQuality / 100,
+		FirmwareVersion:     int(device.FirmwareVersion),
+		FirmwareUnverified:  true,
+	}
+
+	latest, found := k.GetLatestFirmware(ctx, device.Manufacturer, device.Model)
+	if !found {
+		return factors
+	}
+	factors.LatestFirmwareVersion = int(latest.Version)
+
+	release, found := k.GetFirmwareRelease(ctx, device.Manufacturer, device.Model, device.FirmwareVersion)
+	factors.FirmwareUnverified = !found || !bytes.Equal(release.Hash, device.FirmwareHash)
+	return factors
+}
+
+// ReportFirmware records the firmware a device reports running, verified
+// against the firmware registry, and recomputes the device's trust score.
+// Unknown or unsigned firmware is accepted but earns no firmware points.
+func (k Keeper) ReportFirmware(ctx sdk.Context, msg *types.MsgReportFirmware) (*types.Device, error) {
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	device, err := k.GetDevice(ctx, msg.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.OwnerAddress != msg.Submitter {
+		return nil, types.ErrDeviceNotOwned
+	}
+	if device.IsSuspended {
+		return nil, types.ErrDeviceSuspended
+	}
+
+	verified, err := k.VerifyAttestation(ctx, device.DeviceID, device.TEEType, msg.Attestation, nil)
+	if err != nil || !verified {
+		return nil, types.ErrAttestationFailed.Wrap("firmware attestation verification failed")
+	}
+
+	device.FirmwareVersion = msg.Version
+	device.FirmwareHash = msg.Hash
+	device.LastAttestAt = ctx.BlockTime()
+	device.AttestationCount++
+
+	factors := k.DeviceTrustFactors(ctx, *device)
+	device.FirmwareVerified = !factors.FirmwareUnverified
+	device.TrustScore = CalculateDeviceTrustScore(factors).Score
+
+	if err := k.SetDevice(ctx, *device); err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeFirmwareReported,
+			sdk.NewAttribute(types.AttributeKeyDeviceID, device.DeviceID),
+			sdk.NewAttribute(types.AttributeKeyFirmwareVersion, strconv.FormatUint(msg.Version, 10)),
+			sdk.NewAttribute(types.AttributeKeyFirmwareVerified, strconv.FormatBool(device.FirmwareVerified)),
+		),
+		sdk.NewEvent(
+			types.EventTypeTrustScoreUpdated,
+			sdk.NewAttribute(types.AttributeKeyDeviceID, device.DeviceID),
+			sdk.NewAttribute(types.AttributeKeyTrustScore, strconv.FormatUint(device.TrustScore, 10)),
+		),
+	})
+
+	return device, nil
+}
@@ -0,0 +1,204 @@
+package keeper_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/keeper"
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+func firmwareHash(image string) []byte {
+	hash := sha256.Sum256([]byte(image))
+	return hash[:]
+}
+
+func setupFirmware(t *testing.T) (sdk.Context, keeper.Keeper, string, *types.Device) {
+	t.Helper()
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test"))
+	authority := sdk.AccAddress("gov_authority_______").String()
+	k := keeper.NewKeeper(nil, storeKey, authority, nil)
+
+	device, err := k.RegisterDevice(ctx, &types.MsgRegisterDevice{
+		Creator:            sdk.AccAddress("device_owner________").String(),
+		Manufacturer:       "Acme",
+		Model:              "Sensor-1",
+		TEEType:            types.TEETypeTrustZone,
+		PublicKey:          []byte("pubkey"),
+		InitialAttestation: []byte("attestation"),
+	})
+	if err != nil {
+		t.Fatalf("RegisterDevice: %v", err)
+	}
+	device.Uptime = 100
+	device.DataQuality = 100
+	if err := k.SetDevice(ctx, *device); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+
+	for version, image := range map[uint64]string{2: "acme-fw-2", 3: "acme-fw-3"} {
+		err := k.SetFirmwareRelease(ctx, &types.MsgSetFirmwareRelease{
+			Authority: authority,
+			Release: types.FirmwareRelease{
+				Manufacturer: "Acme",
+				Model:        "Sensor-1",
+				Version:      version,
+				Hash:         firmwareHash(image),
+			},
+		})
+		if err != nil {
+			t.Fatalf("SetFirmwareRelease(%d): %v", version, err)
+		}
+	}
+	return ctx, k, authority, device
+}
+
+func TestReportFirmware_TrustScore(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        uint64
+		image          string
+		wantVerified   bool
+		wantFirmPoints uint64
+	}{
+		{"latest signed firmware", 3, "acme-fw-3", true, 15},
+		{"one version behind", 2, "acme-fw-2", true, 10},
+		{"unknown version", 4, "acme-fw-4", false, 0},
+		{"unsigned image for a known version", 3, "tampered", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, k, _, device := setupFirmware(t)
+
+			reported, err := k.ReportFirmware(ctx, &types.MsgReportFirmware{
+				Submitter:   device.OwnerAddress,
+				DeviceID:    device.DeviceID,
+				Version:     tt.version,
+				Hash:        firmwareHash(tt.image),
+				Attestation: []byte("firmware-attestation"),
+			})
+			if err != nil {
+				t.Fatalf("ReportFirmware: %v", err)
+			}
+			if reported.FirmwareVerified != tt.wantVerified {
+				t.Errorf("FirmwareVerified = %v, want %v", reported.FirmwareVerified, tt.wantVerified)
+			}
+			// TEE 40 + uptime 25 + congruence 20 + firmware points
+			if want := 85 + tt.wantFirmPoints; reported.TrustScore != want {
+				t.Errorf("TrustScore = %d, want %d", reported.TrustScore, want)
+			}
+
+			stored, err := k.GetDevice(ctx, device.DeviceID)
+			if err != nil || stored.TrustScore != reported.TrustScore || stored.FirmwareVersion != tt.version {
+				t.Errorf("stored device = %+v, %v", stored, err)
+			}
+		})
+	}
+}
+
+func TestReportFirmware_UnregisteredModel(t *testing.T) {
+	ctx, k, _, device := setupFirmware(t)
+	device.Model = "Sensor-2"
+	if err := k.SetDevice(ctx, *device); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+
+	factors := k.DeviceTrustFactors(ctx, *device)
+	if !factors.FirmwareUnverified {
+		t.Error("firmware for a model missing from the registry should be unverified")
+	}
+	if points := keeper.CalculateDeviceTrustScore(factors).FirmwarePoints; points != 0 {
+		t.Errorf("firmware points = %d, want 0", points)
+	}
+}
+
+func TestReportFirmware_Rejections(t *testing.T) {
+	ctx, k, authority, device := setupFirmware(t)
+
+	_, err := k.ReportFirmware(ctx, &types.MsgReportFirmware{
+		Submitter:   sdk.AccAddress("not_the_owner_______").String(),
+		DeviceID:    device.DeviceID,
+		Version:     3,
+		Hash:        firmwareHash("acme-fw-3"),
+		Attestation: []byte("firmware-attestation"),
+	})
+	if !errors.Is(err, types.ErrDeviceNotOwned) {
+		t.Errorf("expected ErrDeviceNotOwned, got %v", err)
+	}
+
+	err = k.SetFirmwareRelease(ctx, &types.MsgSetFirmwareRelease{
+		Authority: device.OwnerAddress,
+		Release:   types.FirmwareRelease{Manufacturer: "Acme", Model: "Sensor-1", Version: 9, Hash: firmwareHash("evil")},
+	})
+	if !errors.Is(err, types.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for a non-authority release, got %v", err)
+	}
+	if latest, _ := k.GetLatestFirmware(ctx, "Acme", "Sensor-1"); latest.Version != 3 {
+		t.Errorf("latest firmware = %d, want 3", latest.Version)
+	}
+
+	err = k.SetFirmwareRelease(ctx, &types.MsgSetFirmwareRelease{
+		Authority: authority,
+		Release:   types.FirmwareRelease{Manufacturer: "Acme", Version: 1, Hash: []byte("short")},
+	})
+	if !errors.Is(err, types.ErrInvalidFirmware) {
+		t.Errorf("expected ErrInvalidFirmware for a malformed hash, got %v", err)
+	}
+}
+
+func TestMsgServerFirmware(t *testing.T) {
+	ctx, k, authority, device := setupFirmware(t)
+	ms := keeper.NewMsgServerImpl(k)
+
+	release := types.FirmwareRelease{Manufacturer: "Acme", Model: "Sensor-1", Version: 4, Hash: firmwareHash("acme-fw-4")}
+	if _, err := ms.SetFirmwareRelease(ctx, &types.MsgSetFirmwareRelease{Authority: device.OwnerAddress, Release: release}); !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("non-authority release: got %v, want ErrUnauthorized", err)
+	}
+	if _, err := ms.SetFirmwareRelease(ctx, &types.MsgSetFirmwareRelease{Authority: authority, Release: release}); err != nil {
+		t.Fatalf("SetFirmwareRelease: %v", err)
+	}
+
+	res, err := ms.ReportFirmware(ctx, &types.MsgReportFirmware{
+		Submitter:   device.OwnerAddress,
+		DeviceID:    device.DeviceID,
+		Version:     4,
+		Hash:        firmwareHash("acme-fw-4"),
+		Attestation: []byte("firmware-attestation"),
+	})
+	if err != nil {
+		t.Fatalf("ReportFirmware: %v", err)
+	}
+	if !res.FirmwareVerified || res.TrustScore != 100 {
+		t.Errorf("response = %+v, want verified latest firmware scoring 100", res)
+	}
+}
+
+// TestFirmwareRegistry_NamesDoNotCollide checks manufacturer/model pairs that
+// join to the same string keep separate registry entries
+func TestFirmwareRegistry_NamesDoNotCollide(t *testing.T) {
+	ctx, k, authority, _ := setupFirmware(t)
+
+	err := k.SetFirmwareRelease(ctx, &types.MsgSetFirmwareRelease{
+		Authority: authority,
+		Release:   types.FirmwareRelease{Manufacturer: "Acme/Sensor-1", Version: 7, Hash: firmwareHash("other-fw-7")},
+	})
+	if err != nil {
+		t.Fatalf("SetFirmwareRelease: %v", err)
+	}
+
+	if latest, found := k.GetLatestFirmware(ctx, "Acme", "Sensor-1"); !found || latest.Version != 3 {
+		t.Errorf("Acme Sensor-1 latest = %+v, want version 3", latest)
+	}
+	if latest, found := k.GetLatestFirmware(ctx, "Acme/Sensor-1", ""); !found || latest.Version != 7 {
+		t.Errorf("Acme/Sensor-1 latest = %+v, want version 7", latest)
+	}
+	if _, found := k.GetLatestFirmware(ctx, "Acme", ""); found {
+		t.Error("Acme without a model should have no releases")
+	}
+}
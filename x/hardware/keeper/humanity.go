@@ -0,0 +1,284 @@
+package keeper
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+// Threshold crossing directions reported in humanity score events
+const (
+	HumanityThresholdCrossedUp   = "up"
+	HumanityThresholdCrossedDown = "down"
+)
+
+// SetDevice stores a device, updates the network-wide stats and schedules
+// its owner's humanity score for recomputation
+func (k Keeper) SetDevice(ctx sdk.Context, device types.Device) error {
+	bz, err := json.Marshal(device)
+	if err != nil {
+		return types.ErrInvalidDevice.Wrap("failed to marshal device")
+	}
+	previous, err := k.GetDevice(ctx, device.DeviceID)
+	if err != nil {
+		previous = nil
+	}
+	ctx.KVStore(k.storeKey).Set(types.GetDeviceKey(device.DeviceID), bz)
+	k.updateHardwareStats(ctx, previous, device)
+	k.MarkHumanityScoreDirty(ctx, device.OwnerAddress)
+	return nil
+}
+
+// MarkHumanityScoreDirty schedules address for humanity score recomputation in EndBlock
+func (k Keeper) MarkHumanityScoreDirty(ctx sdk.Context, address string) {
+	if address == "" {
+		return
+	}
+	ctx.KVStore(k.storeKey).Set(types.GetHumanityScoreDirtyKey(address), []byte{0x01})
+}
+
+// GetHumanityScore returns the stored humanity score for an address
+func (k Keeper) GetHumanityScore(ctx sdk.Context, address string) (*types.HumanityScore, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(types.GetHumanityScoreKey(address))
+	if bz == nil {
+		return nil, false
+	}
+	var score types.HumanityScore
+	if err := json.Unmarshal(bz, &score); err != nil {
+		return nil, false
+	}
+	return &score, true
+}
+
+// ComputeHumanityScore derives an address's humanity score from the on-chain
+// evidence this module holds: the best active device anchors the hardware
+// points, and how long the address has owned a device and its attestation
+// count stand in for on-chain history. Social and fee factors are tracked off-chain and count as zero.
+// Devices spread across regions and attesting on a regular cadence add up to
+// GeoDispersionWeight and UsagePatternWeight points on top.
+func (k Keeper) ComputeHumanityScore(ctx sdk.Context, address string) types.HumanityScore {
+	score := types.HumanityScore{Address: address, LastUpdated: ctx.BlockTime()}
+
+	var factors types.HumanityFactors
+	var trustTotal uint64
+	var active []types.Device
+	for _, device := range k.GetDevicesByOwner(ctx, address) {
+		if !device.IsActive || device.IsSuspended {
+			continue
+		}
+		active = append(active, device)
+		score.DeviceCount++
+		trustTotal += device.TrustScore
+		if device.TrustScore > factors.LinkedDeviceScore {
+			factors.LinkedDeviceScore = device.TrustScore
+		}
+		ownedSince := device.RegisteredAt
+		if !device.OwnedSince.IsZero() {
+			ownedSince = device.OwnedSince
+		}
+		if months := monthsBetween(ownedSince, ctx.BlockTime()); months > factors.AccountAgeMonths {
+			factors.AccountAgeMonths = months
+		}
+		factors.TransactionCount += int(device.AttestationCount)
+	}
+	if score.DeviceCount == 0 {
+		return score
+	}
+	factors.LinkedDeviceSharedAccounts = 1 // A device has exactly one owner
+
+	score.AverageDeviceTrust = float64(trustTotal) / float64(score.DeviceCount)
+	score.GeoDispersion = geoDispersion(active)
+
+	var regularityTotal float64
+	var sampled int
+	for _, device := range active {
+		if regularity, ok := k.attestationRegularity(ctx, device.DeviceID); ok {
+			regularityTotal += regularity
+			sampled++
+		}
+	}
+	if sampled > 0 {
+		score.UsagePatternScore = roundScore(regularityTotal / float64(sampled))
+	}
+
+	config := types.DefaultTrustScoreConfig()
+	bonus := uint64(math.Round(score.GeoDispersion*float64(config.GeoDispersionWeight) +
+		score.UsagePatternScore*float64(config.UsagePatternWeight)))
+	score.Score = min(CalculateHumanityScore(factors).Score+bonus, 100)
+	return score
+}
+
+// CalculateHumanityScore recomputes and stores an address's humanity score,
+// keeping the verified-human count current and emitting an event when the
+// score changes significantly
+func (k Keeper) CalculateHumanityScore(ctx sdk.Context, address string) types.HumanityScore {
+	config := types.DefaultTrustScoreConfig()
+	var previous uint64
+	if prev, found := k.GetHumanityScore(ctx, address); found {
+		previous = prev.Score
+	}
+	next := k.ComputeHumanityScore(ctx, address)
+
+	bz, err := json.Marshal(next)
+	if err != nil {
+		k.Logger(ctx).Error("failed to marshal humanity score", "address", address, "error", err)
+		return next
+	}
+	ctx.KVStore(k.storeKey).Set(types.GetHumanityScoreKey(address), bz)
+
+	crossed, significant := humanityScoreChange(previous, next.Score, config)
+	k.adjustVerifiedHumans(ctx, crossed)
+	if significant {
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeHumanityScoreChanged,
+				sdk.NewAttribute(types.AttributeKeyAddress, address),
+				sdk.NewAttribute(types.AttributeKeyHumanityScore, strconv.FormatUint(next.Score, 10)),
+				sdk.NewAttribute(types.AttributeKeyPreviousScore, strconv.FormatUint(previous, 10)),
+				sdk.NewAttribute(types.AttributeKeyVerifiedHuman, strconv.FormatBool(next.Score >= config.VerifiedHumanityThreshold)),
+				sdk.NewAttribute(types.AttributeKeyThresholdCrossed, crossed),
+			),
+		)
+	}
+	return next
+}
+
+// UpdateHumanityScores recomputes the humanity score of every address marked
+// dirty since the last block and emits an event for significant changes
+func (k Keeper) UpdateHumanityScores(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := storetypes.KVStorePrefixIterator(store, types.HumanityScoreDirtyPrefix)
+	var addresses []string
+	for ; iterator.Valid(); iterator.Next() {
+		addresses = append(addresses, string(iterator.Key()[len(types.HumanityScoreDirtyPrefix):]))
+	}
+	iterator.Close()
+
+	for _, address := range addresses {
+		store.Delete(types.GetHumanityScoreDirtyKey(address))
+		k.CalculateHumanityScore(ctx, address)
+	}
+}
+
+// humanityScoreChange reports whether a score change is significant: it
+// crosses the verified-human threshold (returned as "up" or "down") or moves
+// by at least HumanityScoreEventDelta
+func humanityScoreChange(previous, next uint64, config types.TrustScoreConfig) (string, bool) {
+	threshold := config.VerifiedHumanityThreshold
+	switch {
+	case previous < threshold && next >= threshold:
+		return HumanityThresholdCrossedUp, true
+	case previous >= threshold && next < threshold:
+		return HumanityThresholdCrossedDown, true
+	}
+	delta := next - previous
+	if previous > next {
+		delta = previous - next
+	}
+	return "", delta > 0 && delta >= config.HumanityScoreEventDelta
+}
+
+// monthsBetween returns the number of whole months from start to end
+func monthsBetween(start, end time.Time) int {
+	if !end.After(start) {
+		return 0
+	}
+	months := (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month())
+	if end.Day() < start.Day() {
+		months--
+	}
+	return months
+}
+
+// maxCadenceSamples is how many of a device's most recent attestations its
+// cadence regularity is measured over
+const maxCadenceSamples = 30
+
+// geoDispersion is the Shannon entropy of the devices' reported geo buckets,
+// normalized by the most the reporting devices could reach (each in its own
+// region). It is 0 for fewer than two reporting devices or a single region
+// and 1 when every device reports a distinct region.
+func geoDispersion(devices []types.Device) float64 {
+	regions := make(map[string]int)
+	reporting := 0
+	for _, device := range devices {
+		if device.GeoBucket == "" {
+			continue
+		}
+		regions[device.GeoBucket]++
+		reporting++
+	}
+	if reporting < 2 || len(regions) < 2 {
+		return 0
+	}
+
+	// Sum in a fixed order; map iteration order would change the rounding
+	buckets := make([]string, 0, len(regions))
+	for bucket := range regions {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+
+	var entropy float64
+	for _, bucket := range buckets {
+		p := float64(regions[bucket]) / float64(reporting)
+		entropy -= p * math.Log2(p)
+	}
+	return roundScore(entropy / math.Log2(float64(reporting)))
+}
+
+// attestationRegularity scores how evenly spaced a device's recent stored
+// attestations are, as 1/(1+cv) where cv is the coefficient of variation of
+// the gaps between them: 1 for a steady cadence, approaching 0 for bursts.
+// Fewer than three attestations give no signal.
+func (k Keeper) attestationRegularity(ctx sdk.Context, deviceID string) (float64, bool) {
+	store := ctx.KVStore(k.storeKey)
+	prefix := append(append([]byte{}, types.AttestationKeyPrefix...), []byte(deviceID)...)
+	iterator := storetypes.KVStoreReversePrefixIterator(store, prefix)
+	defer iterator.Close()
+
+	var timestamps []int64
+	for ; iterator.Valid() && len(timestamps) < maxCadenceSamples; iterator.Next() {
+		key := iterator.Key()
+		if len(key) != len(prefix)+8 {
+			continue
+		}
+		timestamps = append(timestamps, int64(binary.BigEndian.Uint64(key[len(prefix):])))
+	}
+	if len(timestamps) < 3 {
+		return 0, false
+	}
+
+	gaps := make([]float64, len(timestamps)-1)
+	var mean float64
+	for i := range gaps {
+		gaps[i] = float64(timestamps[i] - timestamps[i+1])
+		mean += gaps[i]
+	}
+	mean /= float64(len(gaps))
+	if mean <= 0 {
+		return 0, false
+	}
+	var variance float64
+	for _, gap := range gaps {
+		variance += (gap - mean) * (gap - mean)
+	}
+	variance /= float64(len(gaps))
+
+	return roundScore(1 / (1 + math.Sqrt(variance)/mean)), true
+}
+
+// roundScore rounds a 0-1 score to four decimal places, so the stored value
+// does not depend on last-bit differences in floating point math across
+// validator platforms
+func roundScore(v float64) float64 {
+	return math.Round(v*10_000) / 10_000
+}
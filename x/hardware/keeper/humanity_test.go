@@ -0,0 +1,277 @@
+package keeper_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/keeper"
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+func humanityEvents(ctx sdk.Context) []sdk.Event {
+	var out []sdk.Event
+	for _, ev := range ctx.EventManager().Events() {
+		if ev.Type == types.EventTypeHumanityScoreChanged {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+func eventAttribute(ev sdk.Event, key string) string {
+	for _, attr := range ev.Attributes {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func TestUpdateHumanityScores_ThresholdCrossing(t *testing.T) {
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).
+		WithBlockTime(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	k := keeper.NewKeeper(nil, storeKey, "", nil)
+	owner := sdk.AccAddress("device_owner________").String()
+
+	device, err := k.RegisterDevice(ctx, &types.MsgRegisterDevice{
+		Creator:            owner,
+		Manufacturer:       "Acme",
+		TEEType:            types.TEETypeTrustZone,
+		PublicKey:          []byte("pubkey"),
+		InitialAttestation: []byte("attestation"),
+	})
+	if err != nil {
+		t.Fatalf("RegisterDevice: %v", err)
+	}
+
+	// A fresh low-trust device scores nothing and emits no event
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	k.UpdateHumanityScores(ctx)
+	if events := humanityEvents(ctx); len(events) != 0 {
+		t.Fatalf("expected no events for an unchanged score, got %v", events)
+	}
+	if score, found := k.GetHumanityScore(ctx, owner); !found || score.Score != 0 || score.DeviceCount != 1 {
+		t.Fatalf("unexpected initial humanity score: %+v (found=%v)", score, found)
+	}
+
+	// A high-trust, aged, well-attested device crosses the verified-human threshold
+	device.TrustScore = 85
+	device.AttestationCount = 6
+	device.RegisteredAt = ctx.BlockTime().AddDate(0, -7, 0)
+	if err := k.SetDevice(ctx, *device); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	k.UpdateHumanityScores(ctx)
+
+	events := humanityEvents(ctx)
+	if len(events) != 1 {
+		t.Fatalf("expected one humanity event, got %d", len(events))
+	}
+	ev := events[0]
+	if eventAttribute(ev, types.AttributeKeyAddress) != owner ||
+		eventAttribute(ev, types.AttributeKeyHumanityScore) != "60" ||
+		eventAttribute(ev, types.AttributeKeyPreviousScore) != "0" ||
+		eventAttribute(ev, types.AttributeKeyVerifiedHuman) != "true" ||
+		eventAttribute(ev, types.AttributeKeyThresholdCrossed) != keeper.HumanityThresholdCrossedUp {
+		t.Errorf("unexpected event attributes: %v", ev.Attributes)
+	}
+
+	// Nothing is recomputed without a device change
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	k.UpdateHumanityScores(ctx)
+	if events := humanityEvents(ctx); len(events) != 0 {
+		t.Errorf("expected no events without changes, got %d", len(events))
+	}
+
+	// Suspending the device drops the score back below the threshold
+	device.IsSuspended = true
+	if err := k.SetDevice(ctx, *device); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	k.UpdateHumanityScores(ctx)
+	events = humanityEvents(ctx)
+	if len(events) != 1 || eventAttribute(events[0], types.AttributeKeyThresholdCrossed) != keeper.HumanityThresholdCrossedDown {
+		t.Errorf("expected a downward crossing event, got %v", events)
+	}
+}
+
+func TestUpdateHumanityScores_SmallChangesAreQuiet(t *testing.T) {
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).
+		WithBlockTime(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	k := keeper.NewKeeper(nil, storeKey, "", nil)
+	owner := sdk.AccAddress("device_owner________").String()
+
+	device, err := k.RegisterDevice(ctx, &types.MsgRegisterDevice{
+		Creator:            owner,
+		Manufacturer:       "Acme",
+		TEEType:            types.TEETypeSecureEnclave,
+		PublicKey:          []byte("pubkey"),
+		InitialAttestation: []byte("attestation"),
+	})
+	if err != nil {
+		t.Fatalf("RegisterDevice: %v", err)
+	}
+	k.UpdateHumanityScores(ctx)
+
+	// Reaching 5 attestations adds 10 points: significant, but no crossing
+	device.AttestationCount = 5
+	if err := k.SetDevice(ctx, *device); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	k.UpdateHumanityScores(ctx)
+	events := humanityEvents(ctx)
+	if len(events) != 1 || eventAttribute(events[0], types.AttributeKeyThresholdCrossed) != "" {
+		t.Fatalf("expected one non-crossing event, got %v", events)
+	}
+	if eventAttribute(events[0], types.AttributeKeyVerifiedHuman) != "false" {
+		t.Errorf("score 10 should not be verified: %v", events[0].Attributes)
+	}
+
+	// Raising trust below the high-trust bar leaves the score unchanged
+	device.TrustScore = 50
+	if err := k.SetDevice(ctx, *device); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	k.UpdateHumanityScores(ctx)
+	if events := humanityEvents(ctx); len(events) != 0 {
+		t.Errorf("expected no events for an unchanged score, got %v", events)
+	}
+}
+
+func TestCalculateHumanityScore_GeoDispersion(t *testing.T) {
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).
+		WithBlockTime(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	k := keeper.NewKeeper(nil, storeKey, "", nil)
+
+	registerIn := func(owner string, regions ...string) {
+		t.Helper()
+		for i, region := range regions {
+			device, err := k.RegisterDevice(ctx, &types.MsgRegisterDevice{
+				Creator:            owner,
+				Manufacturer:       "Acme",
+				TEEType:            types.TEETypeTrustZone,
+				PublicKey:          []byte(owner + region + strconv.Itoa(i)),
+				InitialAttestation: []byte("attestation"),
+			})
+			if err != nil {
+				t.Fatalf("RegisterDevice: %v", err)
+			}
+			device.TrustScore = 85
+			device.GeoBucket = region
+			if err := k.SetDevice(ctx, *device); err != nil {
+				t.Fatalf("SetDevice: %v", err)
+			}
+		}
+	}
+	single := sdk.AccAddress("single_device_owner_").String()
+	clustered := sdk.AccAddress("clustered_owner_____").String()
+	dispersed := sdk.AccAddress("dispersed_owner_____").String()
+	registerIn(single, "eu-west")
+	registerIn(clustered, "eu-west", "eu-west", "eu-west")
+	registerIn(dispersed, "eu-west", "us-east", "ap-south")
+
+	singleScore := k.CalculateHumanityScore(ctx, single)
+	clusteredScore := k.CalculateHumanityScore(ctx, clustered)
+	dispersedScore := k.CalculateHumanityScore(ctx, dispersed)
+
+	if singleScore.GeoDispersion != 0 || clusteredScore.GeoDispersion != 0 {
+		t.Errorf("geo dispersion for one region = %v (single), %v (clustered), want 0",
+			singleScore.GeoDispersion, clusteredScore.GeoDispersion)
+	}
+	if dispersedScore.GeoDispersion != 1 {
+		t.Errorf("geo dispersion across distinct regions = %v, want 1", dispersedScore.GeoDispersion)
+	}
+	if dispersedScore.Score <= singleScore.Score {
+		t.Errorf("dispersed score %d is not above the single-device score %d", dispersedScore.Score, singleScore.Score)
+	}
+	if dispersedScore.DeviceCount != 3 || dispersedScore.AverageDeviceTrust != 85 {
+		t.Errorf("unexpected device aggregates: %+v", dispersedScore)
+	}
+
+	// The score is persisted
+	stored, found := k.GetHumanityScore(ctx, dispersed)
+	if !found || stored.Score != dispersedScore.Score || stored.GeoDispersion != dispersedScore.GeoDispersion {
+		t.Errorf("stored score = %+v (found=%v), want %+v", stored, found, dispersedScore)
+	}
+}
+
+func TestCalculateHumanityScore_AttestationCadence(t *testing.T) {
+	owner := sdk.AccAddress("device_owner________").String()
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	// attestAt registers a device and submits a challenge attestation after each gap
+	attestAt := func(t *testing.T, gaps ...time.Duration) types.HumanityScore {
+		t.Helper()
+		storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+		ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).
+			WithBlockTime(start)
+		k := keeper.NewKeeper(nil, storeKey, "", nil)
+		ms := keeper.NewMsgServerImpl(k)
+
+		res, err := ms.RegisterDevice(ctx, &types.MsgRegisterDevice{
+			Creator:            owner,
+			Manufacturer:       "Acme",
+			TEEType:            types.TEETypeTrustZone,
+			PublicKey:          []byte("pubkey"),
+			InitialAttestation: []byte("attestation"),
+		})
+		if err != nil {
+			t.Fatalf("RegisterDevice: %v", err)
+		}
+		now := start
+		for _, gap := range gaps {
+			now = now.Add(gap)
+			ctx = ctx.WithBlockTime(now)
+			challenge, err := ms.RequestAttestationChallenge(ctx, &types.MsgRequestAttestationChallenge{Owner: owner, DeviceID: res.DeviceID})
+			if err != nil {
+				t.Fatalf("RequestAttestationChallenge: %v", err)
+			}
+			if _, err := ms.SubmitAttestation(ctx, &types.MsgSubmitAttestation{
+				Submitter:       owner,
+				DeviceID:        res.DeviceID,
+				AttestationData: []byte("heartbeat"),
+				Nonce:           challenge.Nonce,
+				AttestationType: types.AttestationTypePeriodic,
+				GeoBucket:       "eu-west",
+			}); err != nil {
+				t.Fatalf("SubmitAttestation: %v", err)
+			}
+		}
+
+		device, err := k.GetDevice(ctx, res.DeviceID)
+		if err != nil {
+			t.Fatalf("GetDevice: %v", err)
+		}
+		if device.GeoBucket != "eu-west" {
+			t.Errorf("geo bucket = %q, want the attested eu-west", device.GeoBucket)
+		}
+		return k.CalculateHumanityScore(ctx, owner)
+	}
+
+	if score := attestAt(t, time.Hour, time.Hour); score.UsagePatternScore != 0 {
+		t.Errorf("usage pattern from two attestations = %v, want 0", score.UsagePatternScore)
+	}
+	steady := attestAt(t, time.Hour, time.Hour, time.Hour, time.Hour)
+	if steady.UsagePatternScore != 1 {
+		t.Errorf("usage pattern for a steady cadence = %v, want 1", steady.UsagePatternScore)
+	}
+	bursty := attestAt(t, time.Hour, time.Minute, time.Minute, 10*time.Hour)
+	if bursty.UsagePatternScore <= 0 || bursty.UsagePatternScore >= steady.UsagePatternScore {
+		t.Errorf("usage pattern for a bursty cadence = %v, want between 0 and %v", bursty.UsagePatternScore, steady.UsagePatternScore)
+	}
+	if bursty.Score >= steady.Score {
+		t.Errorf("bursty score %d is not below the steady score %d", bursty.Score, steady.Score)
+	}
+}
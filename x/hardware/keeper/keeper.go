@@ -2,11 +2,14 @@ package keeper
 
 import (
 	"encoding/json"
+	"strconv"
 
 	"cosmossdk.io/log"
+	"cosmossdk.io/store/prefix"
 	storetypes "cosmossdk.io/store/types"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
 
 	"github.com/chaincertify/certd/x/hardware/types"
 )
@@ -56,6 +59,10 @@ func (k Keeper) Logger(ctx sdk.Context) log.Logger {
 
 // RegisterDevice registers a new hardware device after TEE verification
 func (k Keeper) RegisterDevice(ctx sdk.Context, msg *types.MsgRegisterDevice) (*types.Device, error) {
+	if !types.ValidateTEEType(msg.TEEType) {
+		return nil, types.ErrUnsupportedTEE.Wrapf("unsupported TEE type: %s", msg.TEEType)
+	}
+
 	// Generate device ID from public key and TEE type
 	deviceID := types.GenerateDeviceID(msg.PublicKey, msg.TEEType)
 
@@ -73,18 +80,23 @@ func (k Keeper) RegisterDevice(ctx sdk.Context, msg *types.MsgRegisterDevice) (*
 		return nil, types.ErrAttestationFailed.Wrap("initial attestation verification failed")
 	}
 
+	// Verify the manufacturer's signature over the device key and attestation
+	manufacturerVerified, err := k.verifyManufacturer(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create device
 	creator, _ := sdk.AccAddressFromBech32(msg.Creator)
-	device := types.NewDevice(deviceID, msg.Manufacturer, msg.TEEType, msg.PublicKey, creator)
+	device := types.NewDevice(deviceID, msg.Manufacturer, msg.TEEType, msg.PublicKey, creator, ctx.BlockTime())
 	device.Model = msg.Model
 	device.AttestationCount = 1
+	device.ManufacturerVerified = manufacturerVerified
 
 	// Store device (using JSON encoding for now, will migrate to protobuf)
-	bz, err := json.Marshal(device)
-	if err != nil {
-		return nil, types.ErrInvalidDevice.Wrap("failed to marshal device")
+	if err := k.SetDevice(ctx, *device); err != nil {
+		return nil, err
 	}
-	store.Set(deviceKey, bz)
 
 	// Create owner -> device index
 	indexKey := types.GetOwnerDeviceIndexKey(msg.Creator, deviceID)
@@ -98,6 +110,7 @@ func (k Keeper) RegisterDevice(ctx sdk.Context, msg *types.MsgRegisterDevice) (*
 			sdk.NewAttribute(types.AttributeKeyManufacturer, msg.Manufacturer),
 			sdk.NewAttribute(types.AttributeKeyTEEType, string(msg.TEEType)),
 			sdk.NewAttribute(types.AttributeKeyOwner, msg.Creator),
+			sdk.NewAttribute(types.AttributeKeyManufacturerVerified, strconv.FormatBool(manufacturerVerified)),
 		),
 	)
 
@@ -209,6 +222,25 @@ func (k Keeper) GetDevicesByOwner(ctx sdk.Context, owner string) []types.Device
 	return devices
 }
 
+// GetDevicesByOwnerPaginated returns one page of the devices owned by an address, ordered by device ID
+func (k Keeper) GetDevicesByOwnerPaginated(ctx sdk.Context, owner string, pageReq *query.PageRequest) ([]types.Device, *query.PageResponse, error) {
+	indexStore := prefix.NewStore(ctx.KVStore(k.storeKey), types.GetOwnerDeviceIndexKey(owner, ""))
+
+	var devices []types.Device
+	pageRes, err := query.Paginate(indexStore, pageReq, func(key, _ []byte) error {
+		device, err := k.GetDevice(ctx, string(key))
+		if err != nil {
+			return err
+		}
+		devices = append(devices, *device)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return devices, pageRes, nil
+}
+
 // splitKeyAtSlash splits a byte slice at the first slash
 func splitKeyAtSlash(key []byte) [][]byte {
 	for i, b := range key {
@@ -0,0 +1,153 @@
+package keeper
+
+import (
+	"encoding/json"
+	"strconv"
+
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+// SetManufacturer adds or updates a trusted manufacturer. Only the module
+// authority (governance) may manage the manufacturer registry.
+func (k Keeper) SetManufacturer(ctx sdk.Context, msg *types.MsgSetManufacturer) error {
+	if msg.Authority != k.authority {
+		return types.ErrUnauthorized.Wrapf("expected %s, got %s", k.authority, msg.Authority)
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	bz, err := json.Marshal(msg.Manufacturer)
+	if err != nil {
+		return types.ErrUnknownManufacturer.Wrap("failed to marshal manufacturer")
+	}
+	ctx.KVStore(k.storeKey).Set(types.GetManufacturerKey(msg.Manufacturer.Name), bz)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeManufacturerSet,
+			sdk.NewAttribute(types.AttributeKeyManufacturer, msg.Manufacturer.Name),
+		),
+	)
+	return nil
+}
+
+// RemoveManufacturer removes a manufacturer from the registry. Devices already
+// registered keep their verification status.
+func (k Keeper) RemoveManufacturer(ctx sdk.Context, msg *types.MsgRemoveManufacturer) error {
+	if msg.Authority != k.authority {
+		return types.ErrUnauthorized.Wrapf("expected %s, got %s", k.authority, msg.Authority)
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	key := types.GetManufacturerKey(msg.Name)
+	if !store.Has(key) {
+		return types.ErrUnknownManufacturer.Wrap(msg.Name)
+	}
+	store.Delete(key)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeManufacturerRemoved,
+			sdk.NewAttribute(types.AttributeKeyManufacturer, msg.Name),
+		),
+	)
+	return nil
+}
+
+// GetManufacturer returns a registered manufacturer
+func (k Keeper) GetManufacturer(ctx sdk.Context, name string) (*types.Manufacturer, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(types.GetManufacturerKey(name))
+	if bz == nil {
+		return nil, false
+	}
+	var manufacturer types.Manufacturer
+	if err := json.Unmarshal(bz, &manufacturer); err != nil {
+		return nil, false
+	}
+	return &manufacturer, true
+}
+
+// GetAllManufacturers returns every registered manufacturer, ordered by name
+func (k Keeper) GetAllManufacturers(ctx sdk.Context) []types.Manufacturer {
+	iterator := storetypes.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.ManufacturerKeyPrefix)
+	defer iterator.Close()
+
+	var manufacturers []types.Manufacturer
+	for ; iterator.Valid(); iterator.Next() {
+		var manufacturer types.Manufacturer
+		if err := json.Unmarshal(iterator.Value(), &manufacturer); err == nil {
+			manufacturers = append(manufacturers, manufacturer)
+		}
+	}
+	return manufacturers
+}
+
+// SetManufacturerStrictMode toggles whether device registration requires a
+// valid signature from a registered manufacturer
+func (k Keeper) SetManufacturerStrictMode(ctx sdk.Context, msg *types.MsgSetManufacturerStrictMode) error {
+	if msg.Authority != k.authority {
+		return types.ErrUnauthorized.Wrapf("expected %s, got %s", k.authority, msg.Authority)
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	if msg.Strict {
+		store.Set(types.ManufacturerStrictModeKey, []byte{0x01})
+	} else {
+		store.Delete(types.ManufacturerStrictModeKey)
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeManufacturerStrictMode,
+			sdk.NewAttribute(types.AttributeKeyStrict, strconv.FormatBool(msg.Strict)),
+		),
+	)
+	return nil
+}
+
+// IsManufacturerStrictMode reports whether strict manufacturer verification is enabled
+func (k Keeper) IsManufacturerStrictMode(ctx sdk.Context) bool {
+	return ctx.KVStore(k.storeKey).Has(types.ManufacturerStrictModeKey)
+}
+
+// verifyManufacturer checks a registration's manufacturer signature and
+// reports whether the device is manufacturer-verified. A signature that is
+// present but invalid is always rejected; strict mode additionally rejects
+// unregistered manufacturers and unsigned registrations.
+func (k Keeper) verifyManufacturer(ctx sdk.Context, msg *types.MsgRegisterDevice) (bool, error) {
+	strict := k.IsManufacturerStrictMode(ctx)
+
+	manufacturer, found := k.GetManufacturer(ctx, msg.Manufacturer)
+	if !found {
+		if strict {
+			return false, types.ErrUnknownManufacturer.Wrap(msg.Manufacturer)
+		}
+		return false, nil
+	}
+
+	if len(msg.ManufacturerSig) == 0 {
+		if strict {
+			return false, types.ErrInvalidManufacturerSig.Wrap("manufacturer signature required")
+		}
+		return false, nil
+	}
+
+	pubKey, err := manufacturer.PubKey()
+	if err != nil {
+		return false, err
+	}
+	if !pubKey.VerifySignature(types.ManufacturerSignBytes(msg.PublicKey, msg.InitialAttestation), msg.ManufacturerSig) {
+		return false, types.ErrInvalidManufacturerSig.Wrapf("signature does not verify against %s key", manufacturer.Name)
+	}
+	return true, nil
+}
@@ -0,0 +1,235 @@
+package keeper_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/grpc"
+
+	"github.com/chaincertify/certd/x/hardware/keeper"
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+func setupManufacturers(t *testing.T) (sdk.Context, keeper.Keeper, string) {
+	t.Helper()
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test"))
+	authority := sdk.AccAddress("gov_authority_______").String()
+	return ctx, keeper.NewKeeper(nil, storeKey, authority, nil), authority
+}
+
+func registerManufacturer(t *testing.T, ctx sdk.Context, k keeper.Keeper, authority, name, keyType string, pub cryptotypes.PubKey) {
+	t.Helper()
+	err := k.SetManufacturer(ctx, &types.MsgSetManufacturer{
+		Authority:    authority,
+		Manufacturer: types.Manufacturer{Name: name, KeyType: keyType, PublicKey: pub.Bytes()},
+	})
+	if err != nil {
+		t.Fatalf("SetManufacturer(%s): %v", name, err)
+	}
+}
+
+func signedRegistration(t *testing.T, signer cryptotypes.PrivKey, manufacturer string, devicePubKey []byte) *types.MsgRegisterDevice {
+	t.Helper()
+	msg := &types.MsgRegisterDevice{
+		Creator:            sdk.AccAddress("device_owner________").String(),
+		Manufacturer:       manufacturer,
+		TEEType:            types.TEETypeTrustZone,
+		PublicKey:          devicePubKey,
+		InitialAttestation: []byte("attestation"),
+	}
+	sig, err := signer.Sign(types.ManufacturerSignBytes(msg.PublicKey, msg.InitialAttestation))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	msg.ManufacturerSig = sig
+	return msg
+}
+
+func TestRegisterDevice_ManufacturerSignature(t *testing.T) {
+	ctx, k, authority := setupManufacturers(t)
+	acmeKey := ed25519.GenPrivKey()
+	globexKey := secp256k1.GenPrivKey()
+	registerManufacturer(t, ctx, k, authority, "Acme", types.ManufacturerKeyEd25519, acmeKey.PubKey())
+	registerManufacturer(t, ctx, k, authority, "Globex", types.ManufacturerKeySecp256k1, globexKey.PubKey())
+
+	for _, strict := range []bool{false, true} {
+		if err := k.SetManufacturerStrictMode(ctx, &types.MsgSetManufacturerStrictMode{Authority: authority, Strict: strict}); err != nil {
+			t.Fatalf("SetManufacturerStrictMode: %v", err)
+		}
+		suffix := "-lenient"
+		if strict {
+			suffix = "-strict"
+		}
+
+		// A valid signature from a registered manufacturer verifies in either mode
+		for name, signer := range map[string]cryptotypes.PrivKey{"Acme": acmeKey, "Globex": globexKey} {
+			device, err := k.RegisterDevice(ctx, signedRegistration(t, signer, name, []byte(name+suffix)))
+			if err != nil {
+				t.Fatalf("RegisterDevice(%s, strict=%v): %v", name, strict, err)
+			}
+			if !device.ManufacturerVerified {
+				t.Errorf("%s device not marked manufacturer-verified (strict=%v)", name, strict)
+			}
+		}
+
+		// A signature by the wrong key is always rejected
+		forged := signedRegistration(t, ed25519.GenPrivKey(), "Acme", []byte("forged"+suffix))
+		if _, err := k.RegisterDevice(ctx, forged); !errors.Is(err, types.ErrInvalidManufacturerSig) {
+			t.Errorf("forged signature (strict=%v): expected ErrInvalidManufacturerSig, got %v", strict, err)
+		}
+
+		// A signature over a different device key does not verify
+		swapped := signedRegistration(t, acmeKey, "Acme", []byte("original"+suffix))
+		swapped.PublicKey = []byte("swapped" + suffix)
+		if _, err := k.RegisterDevice(ctx, swapped); !errors.Is(err, types.ErrInvalidManufacturerSig) {
+			t.Errorf("swapped device key (strict=%v): expected ErrInvalidManufacturerSig, got %v", strict, err)
+		}
+	}
+}
+
+func TestRegisterDevice_UnknownManufacturer(t *testing.T) {
+	ctx, k, authority := setupManufacturers(t)
+	registerManufacturer(t, ctx, k, authority, "Acme", types.ManufacturerKeyEd25519, ed25519.GenPrivKey().PubKey())
+	unknown := signedRegistration(t, ed25519.GenPrivKey(), "Initech", []byte("initech-device"))
+
+	// Lenient mode accepts the device without manufacturer verification
+	device, err := k.RegisterDevice(ctx, unknown)
+	if err != nil {
+		t.Fatalf("RegisterDevice in lenient mode: %v", err)
+	}
+	if device.ManufacturerVerified {
+		t.Error("device from an unknown manufacturer marked verified")
+	}
+
+	if err := k.SetManufacturerStrictMode(ctx, &types.MsgSetManufacturerStrictMode{Authority: authority, Strict: true}); err != nil {
+		t.Fatalf("SetManufacturerStrictMode: %v", err)
+	}
+	unknown.PublicKey = []byte("initech-device-2")
+	if _, err := k.RegisterDevice(ctx, unknown); !errors.Is(err, types.ErrUnknownManufacturer) {
+		t.Errorf("strict mode: expected ErrUnknownManufacturer, got %v", err)
+	}
+
+	unsigned := &types.MsgRegisterDevice{
+		Creator:            sdk.AccAddress("device_owner________").String(),
+		Manufacturer:       "Acme",
+		TEEType:            types.TEETypeTrustZone,
+		PublicKey:          []byte("unsigned-device"),
+		InitialAttestation: []byte("attestation"),
+	}
+	if _, err := k.RegisterDevice(ctx, unsigned); !errors.Is(err, types.ErrInvalidManufacturerSig) {
+		t.Errorf("strict mode unsigned: expected ErrInvalidManufacturerSig, got %v", err)
+	}
+}
+
+func TestManufacturerRegistry_Authority(t *testing.T) {
+	ctx, k, authority := setupManufacturers(t)
+	pub := ed25519.GenPrivKey().PubKey()
+	outsider := sdk.AccAddress("not_the_authority___").String()
+
+	err := k.SetManufacturer(ctx, &types.MsgSetManufacturer{
+		Authority:    outsider,
+		Manufacturer: types.Manufacturer{Name: "Acme", KeyType: types.ManufacturerKeyEd25519, PublicKey: pub.Bytes()},
+	})
+	if !errors.Is(err, types.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if err := k.SetManufacturerStrictMode(ctx, &types.MsgSetManufacturerStrictMode{Authority: outsider, Strict: true}); !errors.Is(err, types.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized for strict mode, got %v", err)
+	}
+
+	err = k.SetManufacturer(ctx, &types.MsgSetManufacturer{
+		Authority:    authority,
+		Manufacturer: types.Manufacturer{Name: "Acme", KeyType: "rsa", PublicKey: pub.Bytes()},
+	})
+	if !errors.Is(err, types.ErrInvalidManufacturerSig) {
+		t.Errorf("expected unsupported key type to be rejected, got %v", err)
+	}
+
+	registerManufacturer(t, ctx, k, authority, "Acme", types.ManufacturerKeyEd25519, pub)
+	if all := k.GetAllManufacturers(ctx); len(all) != 1 || all[0].Name != "Acme" {
+		t.Errorf("registry = %+v", all)
+	}
+	if err := k.RemoveManufacturer(ctx, &types.MsgRemoveManufacturer{Authority: authority, Name: "Acme"}); err != nil {
+		t.Fatalf("RemoveManufacturer: %v", err)
+	}
+	if _, found := k.GetManufacturer(ctx, "Acme"); found {
+		t.Error("manufacturer still registered after removal")
+	}
+}
+
+// msgServiceRegistrar captures the Msg service registered by types.RegisterMsgServer
+type msgServiceRegistrar struct {
+	desc *grpc.ServiceDesc
+	impl interface{}
+}
+
+func (r *msgServiceRegistrar) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	r.desc, r.impl = desc, impl
+}
+
+// call invokes method through the registered service descriptor, as the gRPC server would
+func (r *msgServiceRegistrar) call(t *testing.T, ctx context.Context, method string, msg interface{}) error {
+	t.Helper()
+	for _, m := range r.desc.Methods {
+		if m.MethodName != method {
+			continue
+		}
+		dec := func(in interface{}) error {
+			reflect.ValueOf(in).Elem().Set(reflect.ValueOf(msg).Elem())
+			return nil
+		}
+		_, err := m.Handler(r.impl, ctx, dec, nil)
+		return err
+	}
+	t.Fatalf("%s is not in the %s service descriptor", method, r.desc.ServiceName)
+	return nil
+}
+
+func TestMsgServerManufacturerRegistry(t *testing.T) {
+	ctx, k, authority := setupManufacturers(t)
+	outsider := sdk.AccAddress("not_the_authority___").String()
+	manufacturer := types.Manufacturer{Name: "Acme", KeyType: types.ManufacturerKeyEd25519, PublicKey: ed25519.GenPrivKey().PubKey().Bytes()}
+
+	var svc msgServiceRegistrar
+	types.RegisterMsgServer(&svc, keeper.NewMsgServerImpl(k))
+
+	t.Run("rejects signers other than the authority", func(t *testing.T) {
+		if err := svc.call(t, ctx, "SetManufacturer", &types.MsgSetManufacturer{Authority: outsider, Manufacturer: manufacturer}); !errors.Is(err, types.ErrUnauthorized) {
+			t.Errorf("SetManufacturer: got %v, want ErrUnauthorized", err)
+		}
+		if err := svc.call(t, ctx, "SetManufacturerStrictMode", &types.MsgSetManufacturerStrictMode{Authority: outsider, Strict: true}); !errors.Is(err, types.ErrUnauthorized) {
+			t.Errorf("SetManufacturerStrictMode: got %v, want ErrUnauthorized", err)
+		}
+		if _, found := k.GetManufacturer(ctx, "Acme"); found || k.IsManufacturerStrictMode(ctx) {
+			t.Error("registry changed by an unauthorized signer")
+		}
+	})
+
+	t.Run("applies authority changes", func(t *testing.T) {
+		if err := svc.call(t, ctx, "SetManufacturer", &types.MsgSetManufacturer{Authority: authority, Manufacturer: manufacturer}); err != nil {
+			t.Fatalf("SetManufacturer: %v", err)
+		}
+		if err := svc.call(t, ctx, "SetManufacturerStrictMode", &types.MsgSetManufacturerStrictMode{Authority: authority, Strict: true}); err != nil {
+			t.Fatalf("SetManufacturerStrictMode: %v", err)
+		}
+		if _, found := k.GetManufacturer(ctx, "Acme"); !found || !k.IsManufacturerStrictMode(ctx) {
+			t.Error("expected Acme registered with strict mode on")
+		}
+
+		if err := svc.call(t, ctx, "RemoveManufacturer", &types.MsgRemoveManufacturer{Authority: authority, Name: "Acme"}); err != nil {
+			t.Fatalf("RemoveManufacturer: %v", err)
+		}
+		if _, found := k.GetManufacturer(ctx, "Acme"); found {
+			t.Error("manufacturer still registered after removal")
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package keeper
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+type msgServer struct {
+	Keeper
+}
+
+// NewMsgServerImpl returns an implementation of the MsgServer interface
+func NewMsgServerImpl(keeper Keeper) types.MsgServer {
+	return &msgServer{Keeper: keeper}
+}
+
+var _ types.MsgServer = msgServer{}
+
+// RegisterDevice handles MsgRegisterDevice; the keeper emits the device_registered event
+func (k msgServer) RegisterDevice(goCtx context.Context, msg *types.MsgRegisterDevice) (*types.MsgRegisterDeviceResponse, error) {
+	if msg == nil {
+		return nil, types.ErrInvalidDevice.Wrap("empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	device, err := k.Keeper.RegisterDevice(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgRegisterDeviceResponse{DeviceID: device.DeviceID}, nil
+}
+
+// RequestAttestationChallenge handles MsgRequestAttestationChallenge
+func (k msgServer) RequestAttestationChallenge(goCtx context.Context, msg *types.MsgRequestAttestationChallenge) (*types.MsgRequestAttestationChallengeResponse, error) {
+	if msg == nil {
+		return nil, types.ErrInvalidDevice.Wrap("empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	challenge, err := k.Keeper.RequestAttestationChallenge(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgRequestAttestationChallengeResponse{
+		Nonce:     challenge.Nonce,
+		ExpiresAt: challenge.ExpiresAt.Unix(),
+	}, nil
+}
+
+// SubmitAttestation handles MsgSubmitAttestation; the keeper emits the attestation_verified event
+func (k msgServer) SubmitAttestation(goCtx context.Context, msg *types.MsgSubmitAttestation) (*types.MsgSubmitAttestationResponse, error) {
+	if msg == nil {
+		return nil, types.ErrInvalidAttestation.Wrap("empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	device, err := k.Keeper.SubmitAttestation(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgSubmitAttestationResponse{AttestationCount: device.AttestationCount}, nil
+}
+
+// AppealSuspension handles MsgAppealSuspension; the keeper emits the device_reactivated event
+func (k msgServer) AppealSuspension(goCtx context.Context, msg *types.MsgAppealSuspension) (*types.MsgAppealSuspensionResponse, error) {
+	if msg == nil {
+		return nil, types.ErrInvalidDevice.Wrap("empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if _, err := k.Keeper.AppealSuspension(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgAppealSuspensionResponse{}, nil
+}
+
+// TransferDevice handles MsgTransferDevice; the keeper emits the device_transferred event
+func (k msgServer) TransferDevice(goCtx context.Context, msg *types.MsgTransferDevice) (*types.MsgTransferDeviceResponse, error) {
+	if msg == nil {
+		return nil, types.ErrInvalidDevice.Wrap("empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	device, err := k.Keeper.TransferDevice(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgTransferDeviceResponse{TrustScore: device.TrustScore}, nil
+}
+
+// SetFirmwareRelease handles MsgSetFirmwareRelease; the keeper rejects any
+// signer other than the module authority
+func (k msgServer) SetFirmwareRelease(goCtx context.Context, msg *types.MsgSetFirmwareRelease) (*types.MsgSetFirmwareReleaseResponse, error) {
+	if msg == nil {
+		return nil, types.ErrInvalidFirmware.Wrap("empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := k.Keeper.SetFirmwareRelease(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgSetFirmwareReleaseResponse{}, nil
+}
+
+// ReportFirmware handles MsgReportFirmware; the keeper emits the firmware_reported event
+func (k msgServer) ReportFirmware(goCtx context.Context, msg *types.MsgReportFirmware) (*types.MsgReportFirmwareResponse, error) {
+	if msg == nil {
+		return nil, types.ErrInvalidFirmware.Wrap("empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	device, err := k.Keeper.ReportFirmware(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgReportFirmwareResponse{
+		FirmwareVerified: device.FirmwareVerified,
+		TrustScore:       device.TrustScore,
+	}, nil
+}
+
+// SetManufacturer handles MsgSetManufacturer; the keeper rejects any signer
+// other than the module authority
+func (k msgServer) SetManufacturer(goCtx context.Context, msg *types.MsgSetManufacturer) (*types.MsgSetManufacturerResponse, error) {
+	if msg == nil {
+		return nil, types.ErrUnknownManufacturer.Wrap("empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := k.Keeper.SetManufacturer(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgSetManufacturerResponse{}, nil
+}
+
+// RemoveManufacturer handles MsgRemoveManufacturer; the keeper rejects any
+// signer other than the module authority
+func (k msgServer) RemoveManufacturer(goCtx context.Context, msg *types.MsgRemoveManufacturer) (*types.MsgRemoveManufacturerResponse, error) {
+	if msg == nil {
+		return nil, types.ErrUnknownManufacturer.Wrap("empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := k.Keeper.RemoveManufacturer(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgRemoveManufacturerResponse{}, nil
+}
+
+// SetManufacturerStrictMode handles MsgSetManufacturerStrictMode; the keeper
+// rejects any signer other than the module authority
+func (k msgServer) SetManufacturerStrictMode(goCtx context.Context, msg *types.MsgSetManufacturerStrictMode) (*types.MsgSetManufacturerStrictModeResponse, error) {
+	if msg == nil {
+		return nil, types.ErrUnknownManufacturer.Wrap("empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := k.Keeper.SetManufacturerStrictMode(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgSetManufacturerStrictModeResponse{}, nil
+}
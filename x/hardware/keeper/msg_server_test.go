@@ -0,0 +1,72 @@
+package keeper_test
+
+import (
+	"errors"
+	"testing"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/keeper"
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+func TestMsgServerRegisterDevice(t *testing.T) {
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test"))
+	k := keeper.NewKeeper(nil, storeKey, "", nil)
+	ms := keeper.NewMsgServerImpl(k)
+	owner := sdk.AccAddress("device_owner________").String()
+
+	msg := &types.MsgRegisterDevice{
+		Creator:            owner,
+		Manufacturer:       "Acme",
+		TEEType:            types.TEETypeTrustZone,
+		PublicKey:          []byte("pubkey"),
+		InitialAttestation: []byte("attestation"),
+	}
+
+	t.Run("registers the device", func(t *testing.T) {
+		res, err := ms.RegisterDevice(ctx, msg)
+		if err != nil {
+			t.Fatalf("RegisterDevice: %v", err)
+		}
+		if want := types.GenerateDeviceID(msg.PublicKey, msg.TEEType); res.DeviceID != want {
+			t.Fatalf("device ID = %s, want %s", res.DeviceID, want)
+		}
+
+		device, err := k.GetDevice(ctx, res.DeviceID)
+		if err != nil {
+			t.Fatalf("GetDevice: %v", err)
+		}
+		if device.OwnerAddress != owner || device.TEEType != types.TEETypeTrustZone || !device.IsActive {
+			t.Errorf("unexpected device: %+v", device)
+		}
+		owned := k.GetDevicesByOwner(ctx, owner)
+		if len(owned) != 1 || owned[0].DeviceID != res.DeviceID {
+			t.Errorf("owner index = %+v, want [%s]", owned, res.DeviceID)
+		}
+	})
+
+	t.Run("rejects a duplicate device", func(t *testing.T) {
+		if _, err := ms.RegisterDevice(ctx, msg); !errors.Is(err, types.ErrDeviceAlreadyExists) {
+			t.Fatalf("duplicate registration: got %v, want ErrDeviceAlreadyExists", err)
+		}
+	})
+
+	t.Run("rejects an unsupported TEE type", func(t *testing.T) {
+		sgx := *msg
+		sgx.TEEType = types.TEETypeSGX
+		sgx.PublicKey = []byte("sgx-pubkey")
+		if err := sgx.ValidateBasic(); !errors.Is(err, types.ErrUnsupportedTEE) {
+			t.Errorf("ValidateBasic: got %v, want ErrUnsupportedTEE", err)
+		}
+		if _, err := ms.RegisterDevice(ctx, &sgx); !errors.Is(err, types.ErrUnsupportedTEE) {
+			t.Fatalf("RegisterDevice: got %v, want ErrUnsupportedTEE", err)
+		}
+		if _, err := k.GetDevice(ctx, types.GenerateDeviceID(sgx.PublicKey, sgx.TEEType)); !errors.Is(err, types.ErrDeviceNotFound) {
+			t.Errorf("unsupported device was stored: %v", err)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package keeper
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+type queryServer struct {
+	Keeper
+}
+
+// NewQueryServerImpl returns an implementation of the QueryServer interface
+func NewQueryServerImpl(keeper Keeper) types.QueryServer {
+	return &queryServer{Keeper: keeper}
+}
+
+var _ types.QueryServer = queryServer{}
+
+// DevicesByOwner returns a page of the devices owned by an address
+func (k queryServer) DevicesByOwner(goCtx context.Context, req *types.QueryDevicesByOwnerRequest) (*types.QueryDevicesByOwnerResponse, error) {
+	if req == nil {
+		return nil, types.ErrInvalidAddress.Wrap("empty request")
+	}
+	if _, err := sdk.AccAddressFromBech32(req.Owner); err != nil {
+		return nil, types.ErrInvalidAddress.Wrapf("invalid owner address: %s", req.Owner)
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	devices, pageRes, err := k.Keeper.GetDevicesByOwnerPaginated(ctx, req.Owner, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	if devices == nil {
+		devices = []types.Device{}
+	}
+
+	return &types.QueryDevicesByOwnerResponse{
+		Devices:    devices,
+		Pagination: pageRes,
+	}, nil
+}
+
+// Stats returns the network-wide hardware stats
+func (k queryServer) Stats(goCtx context.Context, req *types.QueryStatsRequest) (*types.QueryStatsResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	stats := k.Keeper.GetHardwareStats(ctx)
+	return &types.QueryStatsResponse{
+		Stats:             stats,
+		AverageTrustScore: stats.AverageTrustScore(),
+	}, nil
+}
+
+// Device returns a registered device by ID
+func (k queryServer) Device(goCtx context.Context, req *types.QueryDeviceRequest) (*types.QueryDeviceResponse, error) {
+	if req == nil || req.DeviceID == "" {
+		return nil, types.ErrInvalidDevice.Wrap("empty device id")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	device, err := k.Keeper.GetDevice(ctx, req.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	return &types.QueryDeviceResponse{Device: *device}, nil
+}
+
+// HumanityScore returns an address's stored humanity score. An address whose
+// score has not been stored yet gets one computed from its current devices.
+func (k queryServer) HumanityScore(goCtx context.Context, req *types.QueryHumanityScoreRequest) (*types.QueryHumanityScoreResponse, error) {
+	if req == nil {
+		return nil, types.ErrInvalidAddress.Wrap("empty request")
+	}
+	if _, err := sdk.AccAddressFromBech32(req.Address); err != nil {
+		return nil, types.ErrInvalidAddress.Wrapf("invalid address: %s", req.Address)
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	score, found := k.Keeper.GetHumanityScore(ctx, req.Address)
+	if !found {
+		computed := k.Keeper.ComputeHumanityScore(ctx, req.Address)
+		score = &computed
+	}
+	return &types.QueryHumanityScoreResponse{
+		Score:         *score,
+		VerifiedHuman: score.Score >= types.DefaultTrustScoreConfig().VerifiedHumanityThreshold,
+	}, nil
+}
@@ -0,0 +1,134 @@
+package keeper_test
+
+import (
+	"fmt"
+	"testing"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+
+	"github.com/chaincertify/certd/x/hardware/keeper"
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+func registerDevices(t *testing.T, ctx sdk.Context, k keeper.Keeper, owner string, n int) map[string]bool {
+	t.Helper()
+	ids := make(map[string]bool, n)
+	for i := 0; i < n; i++ {
+		device, err := k.RegisterDevice(ctx, &types.MsgRegisterDevice{
+			Creator:            owner,
+			Manufacturer:       "Acme",
+			TEEType:            types.TEETypeSecureEnclave,
+			PublicKey:          []byte(fmt.Sprintf("%s-pubkey-%d", owner, i)),
+			InitialAttestation: []byte("attestation"),
+		})
+		if err != nil {
+			t.Fatalf("RegisterDevice: %v", err)
+		}
+		ids[device.DeviceID] = true
+	}
+	return ids
+}
+
+func TestQueryDevicesByOwner(t *testing.T) {
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test"))
+	k := keeper.NewKeeper(nil, storeKey, "", nil)
+	qs := keeper.NewQueryServerImpl(k)
+
+	none := sdk.AccAddress("owner_with_none_____").String()
+	single := sdk.AccAddress("owner_with_one______").String()
+	many := sdk.AccAddress("owner_with_many_____").String()
+	registerDevices(t, ctx, k, single, 1)
+	manyIDs := registerDevices(t, ctx, k, many, 5)
+
+	t.Run("zero devices", func(t *testing.T) {
+		res, err := qs.DevicesByOwner(ctx, &types.QueryDevicesByOwnerRequest{Owner: none})
+		if err != nil {
+			t.Fatalf("DevicesByOwner: %v", err)
+		}
+		if res.Devices == nil || len(res.Devices) != 0 || len(res.Pagination.NextKey) != 0 {
+			t.Errorf("expected an empty page, got %+v", res)
+		}
+	})
+
+	t.Run("one device", func(t *testing.T) {
+		res, err := qs.DevicesByOwner(ctx, &types.QueryDevicesByOwnerRequest{Owner: single})
+		if err != nil {
+			t.Fatalf("DevicesByOwner: %v", err)
+		}
+		if len(res.Devices) != 1 || res.Devices[0].OwnerAddress != single {
+			t.Errorf("unexpected devices: %+v", res.Devices)
+		}
+	})
+
+	t.Run("many devices paginated", func(t *testing.T) {
+		seen := map[string]bool{}
+		var pages int
+		pageReq := &query.PageRequest{Limit: 2, CountTotal: true}
+		for {
+			res, err := qs.DevicesByOwner(ctx, &types.QueryDevicesByOwnerRequest{Owner: many, Pagination: pageReq})
+			if err != nil {
+				t.Fatalf("DevicesByOwner: %v", err)
+			}
+			pages++
+			if len(res.Devices) > 2 {
+				t.Fatalf("page %d has %d devices, limit is 2", pages, len(res.Devices))
+			}
+			if pages == 1 && res.Pagination.Total != 5 {
+				t.Errorf("total = %d, want 5", res.Pagination.Total)
+			}
+			for _, d := range res.Devices {
+				if d.OwnerAddress != many || seen[d.DeviceID] {
+					t.Errorf("unexpected or repeated device %s", d.DeviceID)
+				}
+				seen[d.DeviceID] = true
+			}
+			if len(res.Pagination.NextKey) == 0 {
+				break
+			}
+			pageReq = &query.PageRequest{Key: res.Pagination.NextKey, Limit: 2}
+		}
+		if pages != 3 || len(seen) != len(manyIDs) {
+			t.Errorf("saw %d devices over %d pages, want 5 over 3", len(seen), pages)
+		}
+		for id := range manyIDs {
+			if !seen[id] {
+				t.Errorf("device %s missing from pages", id)
+			}
+		}
+	})
+
+	t.Run("invalid owner", func(t *testing.T) {
+		if _, err := qs.DevicesByOwner(ctx, &types.QueryDevicesByOwnerRequest{Owner: "not-an-address"}); err == nil {
+			t.Error("expected an error for an invalid owner address")
+		}
+	})
+}
+
+func TestQueryDevice(t *testing.T) {
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test"))
+	k := keeper.NewKeeper(nil, storeKey, "", nil)
+	qs := keeper.NewQueryServerImpl(k)
+
+	owner := sdk.AccAddress("device_owner________").String()
+	var id string
+	for deviceID := range registerDevices(t, ctx, k, owner, 1) {
+		id = deviceID
+	}
+
+	res, err := qs.Device(ctx, &types.QueryDeviceRequest{DeviceID: id})
+	if err != nil {
+		t.Fatalf("Device: %v", err)
+	}
+	if res.Device.DeviceID != id || res.Device.OwnerAddress != owner || len(res.Device.PublicKey) == 0 {
+		t.Errorf("unexpected device: %+v", res.Device)
+	}
+
+	if _, err := qs.Device(ctx, &types.QueryDeviceRequest{DeviceID: "dev_missing"}); err == nil {
+		t.Error("expected an error for an unknown device")
+	}
+}
@@ -62,12 +62,19 @@ func CalculateDeviceTrustScore(factors types.DeviceTrustFactors) types.DeviceTru
 	// FIRMWARE INTEGRITY (15 points max)
 	// +15 for latest signed firmware
 	// -5 for every version behind
+	// 0 for unsigned or unknown firmware (not in the firmware registry)
 	// Ensures security patches are applied, prevents hacked firmware
 	// ==================================================
-	if factors.FirmwareVersion == types.LatestFirmwareVersion {
+	latestFirmware := factors.LatestFirmwareVersion
+	if latestFirmware == 0 {
+		latestFirmware = types.LatestFirmwareVersion
+	}
+	if factors.FirmwareUnverified {
+		result.FirmwarePoints = 0
+	} else if factors.FirmwareVersion == latestFirmware {
 		result.FirmwarePoints = config.FirmwareIntegrityWeight // +15
 	} else {
-		versionsBehind := types.LatestFirmwareVersion - factors.FirmwareVersion
+		versionsBehind := latestFirmware - factors.FirmwareVersion
 		if versionsBehind < 0 {
 			versionsBehind = 0 // Future version? Give full points
 			result.FirmwarePoints = config.FirmwareIntegrityWeight
@@ -146,3 +146,22 @@ func TestCalculateHumanityScore_LowTrustDevice(t *testing.T) {
 		t.Error("Expected IsVerifiedHuman to be false (below 60 threshold)")
 	}
 }
+
+func TestCalculateDeviceTrustScore_RegistryFirmware(t *testing.T) {
+	factors := types.DeviceTrustFactors{
+		TEEAttestationValid:   true,
+		Uptime:                1.0,
+		DataCongruence:        1.0,
+		FirmwareVersion:       4,
+		LatestFirmwareVersion: 5,
+	}
+	if points := keeper.CalculateDeviceTrustScore(factors).FirmwarePoints; points != 10 {
+		t.Errorf("Expected firmware points 10 one version behind the registry, got %d", points)
+	}
+
+	factors.FirmwareVersion = 5
+	factors.FirmwareUnverified = true
+	if points := keeper.CalculateDeviceTrustScore(factors).FirmwarePoints; points != 0 {
+		t.Errorf("Expected no firmware points for unverified firmware, got %d", points)
+	}
+}
@@ -0,0 +1,102 @@
+package keeper
+
+import (
+	"encoding/json"
+
+	storetypes "cosmossdk.io/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+// GetHardwareStats returns the network-wide device counters
+func (k Keeper) GetHardwareStats(ctx sdk.Context) types.HardwareStats {
+	stats := types.NewHardwareStats()
+	bz := ctx.KVStore(k.storeKey).Get(types.HardwareStatsKey)
+	if bz == nil {
+		return stats
+	}
+	if err := json.Unmarshal(bz, &stats); err != nil {
+		k.Logger(ctx).Error("failed to unmarshal hardware stats", "error", err)
+		return types.NewHardwareStats()
+	}
+	if stats.ByTEEType == nil {
+		stats.ByTEEType = map[string]uint64{}
+	}
+	if stats.ByManufacturer == nil {
+		stats.ByManufacturer = map[string]uint64{}
+	}
+	return stats
+}
+
+func (k Keeper) setHardwareStats(ctx sdk.Context, stats types.HardwareStats) {
+	bz, err := json.Marshal(stats)
+	if err != nil {
+		k.Logger(ctx).Error("failed to marshal hardware stats", "error", err)
+		return
+	}
+	ctx.KVStore(k.storeKey).Set(types.HardwareStatsKey, bz)
+}
+
+// updateHardwareStats replaces previous (nil for a new device) with next in the counters
+func (k Keeper) updateHardwareStats(ctx sdk.Context, previous *types.Device, next types.Device) {
+	stats := k.GetHardwareStats(ctx)
+	if previous != nil {
+		stats.RemoveDevice(*previous)
+	}
+	stats.AddDevice(next)
+	k.setHardwareStats(ctx, stats)
+}
+
+// adjustVerifiedHumans moves the verified-human count when a humanity score crosses the threshold
+func (k Keeper) adjustVerifiedHumans(ctx sdk.Context, crossed string) {
+	stats := k.GetHardwareStats(ctx)
+	switch crossed {
+	case HumanityThresholdCrossedUp:
+		stats.VerifiedHumans++
+	case HumanityThresholdCrossedDown:
+		if stats.VerifiedHumans > 0 {
+			stats.VerifiedHumans--
+		}
+	default:
+		return
+	}
+	k.setHardwareStats(ctx, stats)
+}
+
+// RecomputeHardwareStats rebuilds the counters from stored devices and
+// humanity scores, replacing whatever was maintained incrementally
+func (k Keeper) RecomputeHardwareStats(ctx sdk.Context) types.HardwareStats {
+	stats := types.NewHardwareStats()
+	for _, device := range k.GetAllDevices(ctx) {
+		stats.AddDevice(device)
+	}
+
+	threshold := types.DefaultTrustScoreConfig().VerifiedHumanityThreshold
+	iterator := storetypes.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.HumanityScoreKeyPrefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var score types.HumanityScore
+		if err := json.Unmarshal(iterator.Value(), &score); err == nil && score.Score >= threshold {
+			stats.VerifiedHumans++
+		}
+	}
+
+	k.setHardwareStats(ctx, stats)
+	return stats
+}
+
+// GetAllDevices returns every registered device, ordered by device ID
+func (k Keeper) GetAllDevices(ctx sdk.Context) []types.Device {
+	iterator := storetypes.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.DeviceKeyPrefix)
+	defer iterator.Close()
+
+	var devices []types.Device
+	for ; iterator.Valid(); iterator.Next() {
+		var device types.Device
+		if err := json.Unmarshal(iterator.Value(), &device); err == nil {
+			devices = append(devices, device)
+		}
+	}
+	return devices
+}
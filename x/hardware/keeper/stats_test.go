@@ -0,0 +1,151 @@
+package keeper_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware"
+	"github.com/chaincertify/certd/x/hardware/keeper"
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+func newStatsKeeper(authority string) (sdk.Context, keeper.Keeper) {
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).
+		WithBlockTime(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	return ctx, keeper.NewKeeper(nil, storeKey, authority, nil)
+}
+
+func TestHardwareStats_Counters(t *testing.T) {
+	authority := sdk.AccAddress("gov_authority_______").String()
+	ctx, k := newStatsKeeper(authority)
+	alice := sdk.AccAddress("alice_______________").String()
+	bob := sdk.AccAddress("bob_________________").String()
+
+	register := func(owner, manufacturer string, tee types.TEEType, key string) *types.Device {
+		device, err := k.RegisterDevice(ctx, &types.MsgRegisterDevice{
+			Creator:            owner,
+			Manufacturer:       manufacturer,
+			TEEType:            tee,
+			PublicKey:          []byte(key),
+			InitialAttestation: []byte("attestation"),
+		})
+		if err != nil {
+			t.Fatalf("RegisterDevice: %v", err)
+		}
+		return device
+	}
+	anchor := register(alice, "Acme", types.TEETypeTrustZone, "key-1")
+	register(alice, "Acme", types.TEETypeTrustZone, "key-2")
+	phone := register(bob, "Apple", types.TEETypeSecureEnclave, "key-3")
+
+	stats := k.GetHardwareStats(ctx)
+	if stats.TotalDevices != 3 || stats.ActiveDevices != 3 || stats.SuspendedDevices != 0 {
+		t.Fatalf("after registration: %+v", stats)
+	}
+	if !reflect.DeepEqual(stats.ByTEEType, map[string]uint64{"ARM_TRUSTZONE": 2, "APPLE_SECURE_ENCLAVE": 1}) ||
+		!reflect.DeepEqual(stats.ByManufacturer, map[string]uint64{"Acme": 2, "Apple": 1}) {
+		t.Errorf("breakdowns = %v / %v", stats.ByTEEType, stats.ByManufacturer)
+	}
+
+	// Trust score changes flow into the average, and a verified human is counted in EndBlock
+	anchor.TrustScore = 85
+	anchor.AttestationCount = 6
+	anchor.RegisteredAt = ctx.BlockTime().AddDate(0, -7, 0)
+	if err := k.SetDevice(ctx, *anchor); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+	phone.TrustScore = 40
+	if err := k.SetDevice(ctx, *phone); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+	k.UpdateHumanityScores(ctx)
+	stats = k.GetHardwareStats(ctx)
+	if stats.TrustScoreSum != 125 || stats.VerifiedHumans != 1 {
+		t.Errorf("after scoring: sum=%d verified=%d", stats.TrustScoreSum, stats.VerifiedHumans)
+	}
+
+	// Suspension moves the device out of the active count and the owner out of verified humans
+	if _, err := k.SuspendDevice(ctx, &types.MsgSuspendDevice{Authority: authority, DeviceID: anchor.DeviceID, Reason: "audit"}); err != nil {
+		t.Fatalf("SuspendDevice: %v", err)
+	}
+	k.UpdateHumanityScores(ctx)
+	stats = k.GetHardwareStats(ctx)
+	if stats.TotalDevices != 3 || stats.ActiveDevices != 2 || stats.SuspendedDevices != 1 || stats.VerifiedHumans != 0 {
+		t.Errorf("after suspension: %+v", stats)
+	}
+
+	// Transfer resets the phone's trust but keeps the device counted once
+	if _, err := k.TransferDevice(ctx, &types.MsgTransferDevice{Owner: bob, DeviceID: phone.DeviceID, NewOwner: alice}); err != nil {
+		t.Fatalf("TransferDevice: %v", err)
+	}
+	stats = k.GetHardwareStats(ctx)
+	if stats.TotalDevices != 3 || stats.ByManufacturer["Apple"] != 1 || stats.TrustScoreSum != 85 {
+		t.Errorf("after transfer: %+v", stats)
+	}
+
+	if _, err := k.ReactivateDevice(ctx, &types.MsgReactivateDevice{Authority: authority, DeviceID: anchor.DeviceID}); err != nil {
+		t.Fatalf("ReactivateDevice: %v", err)
+	}
+	stats = k.GetHardwareStats(ctx)
+	if stats.ActiveDevices != 3 || stats.SuspendedDevices != 0 {
+		t.Errorf("after reactivation: %+v", stats)
+	}
+	if got := stats.AverageTrustScore(); got < 28.3 || got > 28.4 {
+		t.Errorf("average trust score = %v", got)
+	}
+
+	// The maintained counters agree with a full recomputation
+	if recomputed := k.RecomputeHardwareStats(ctx); !reflect.DeepEqual(recomputed, stats) {
+		t.Errorf("recomputed %+v != maintained %+v", recomputed, stats)
+	}
+
+	if _, err := k.SuspendDevice(ctx, &types.MsgSuspendDevice{Authority: alice, DeviceID: anchor.DeviceID, Reason: "x"}); err == nil {
+		t.Error("expected a non-authority suspension to fail")
+	}
+}
+
+func TestHardwareStats_GenesisImport(t *testing.T) {
+	ctx, k := newStatsKeeper("")
+	owner := sdk.AccAddress("genesis_owner_______").String()
+	gs := hardware.GenesisState{Devices: []types.Device{
+		{DeviceID: "dev_a", OwnerAddress: owner, Manufacturer: "Acme", TEEType: types.TEETypeTrustZone, TrustScore: 90, IsActive: true},
+		{DeviceID: "dev_b", OwnerAddress: owner, Manufacturer: "Acme", TEEType: types.TEETypeTrustZone, TrustScore: 10, IsActive: true, IsSuspended: true},
+		{DeviceID: "dev_c", OwnerAddress: owner, Manufacturer: "Apple", TEEType: types.TEETypeSecureEnclave, TrustScore: 50},
+	}}
+	if err := gs.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	hardware.InitGenesis(ctx, k, gs)
+	stats := k.GetHardwareStats(ctx)
+	want := types.HardwareStats{
+		TotalDevices:     3,
+		ActiveDevices:    1,
+		SuspendedDevices: 1,
+		ByTEEType:        map[string]uint64{"ARM_TRUSTZONE": 2, "APPLE_SECURE_ENCLAVE": 1},
+		ByManufacturer:   map[string]uint64{"Acme": 2, "Apple": 1},
+		TrustScoreSum:    150,
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("stats after genesis = %+v, want %+v", stats, want)
+	}
+	if devices := k.GetDevicesByOwner(ctx, owner); len(devices) != 3 {
+		t.Errorf("owner index has %d devices after import", len(devices))
+	}
+
+	exported := hardware.ExportGenesis(ctx, k)
+	if len(exported.Devices) != 3 {
+		t.Errorf("exported %d devices", len(exported.Devices))
+	}
+
+	dup := hardware.GenesisState{Devices: []types.Device{gs.Devices[0], gs.Devices[0]}}
+	if err := dup.Validate(); err == nil {
+		t.Error("expected duplicate genesis devices to fail validation")
+	}
+}
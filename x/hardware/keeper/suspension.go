@@ -0,0 +1,203 @@
+package keeper
+
+import (
+	"encoding/binary"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+// SuspendDevice flags a device for suspicious activity. Only the module authority may suspend devices.
+func (k Keeper) SuspendDevice(ctx sdk.Context, msg *types.MsgSuspendDevice) (*types.Device, error) {
+	if msg.Authority != k.authority {
+		return nil, types.ErrUnauthorized.Wrapf("expected %s, got %s", k.authority, msg.Authority)
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	device, err := k.GetDevice(ctx, msg.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.IsSuspended {
+		return nil, types.ErrDeviceSuspended
+	}
+
+	if err := k.suspendDevice(ctx, device, msg.Reason); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// suspendDevice suspends device with reason and emits the device_suspended
+// event. Suspended devices no longer count toward their owner's humanity score.
+func (k Keeper) suspendDevice(ctx sdk.Context, device *types.Device, reason string) error {
+	device.IsSuspended = true
+	device.SuspensionReason = reason
+	if err := k.SetDevice(ctx, *device); err != nil {
+		return err
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeDeviceSuspended,
+			sdk.NewAttribute(types.AttributeKeyDeviceID, device.DeviceID),
+			sdk.NewAttribute(types.AttributeKeyOwner, device.OwnerAddress),
+			sdk.NewAttribute(types.AttributeKeyReason, reason),
+		),
+	)
+
+	k.Logger(ctx).Info("device suspended", "device_id", device.DeviceID, "reason", reason)
+	return nil
+}
+
+// SetDataCongruence records a device's measured data congruence (0-100), the
+// DataQuality the daily congruence audit checks
+func (k Keeper) SetDataCongruence(ctx sdk.Context, deviceID string, congruence float64) error {
+	if congruence < 0 || congruence > 100 {
+		return types.ErrInvalidDevice.Wrapf("data congruence %v out of range [0, 100]", congruence)
+	}
+	device, err := k.GetDevice(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	device.DataQuality = congruence
+	device.CongruenceUpdatedAt = ctx.BlockTime()
+	return k.SetDevice(ctx, *device)
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+// AuditDataCongruence runs once per UTC day, in the first block of the day. Each
+// active device with a congruence measurement has its run of low-congruence
+// days extended or reset, and a device the trust score flags for audit (below
+// 50% for DataCongruenceAuditDays in a row) is suspended.
+func (k Keeper) AuditDataCongruence(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+	day := ctx.BlockTime().Unix() / secondsPerDay
+	if bz := store.Get(types.CongruenceAuditDayKey); len(bz) == 8 && int64(binary.BigEndian.Uint64(bz)) >= day {
+		return
+	}
+	store.Set(types.CongruenceAuditDayKey, sdk.Uint64ToBigEndian(uint64(day)))
+
+	for _, device := range k.GetAllDevices(ctx) {
+		if !device.IsActive || device.IsSuspended || device.CongruenceUpdatedAt.IsZero() {
+			continue
+		}
+
+		factors := k.DeviceTrustFactors(ctx, device)
+		if factors.DataCongruence < 0.5 {
+			device.ConsecutiveLowCongruenceDays++
+		} else if device.ConsecutiveLowCongruenceDays == 0 {
+			continue
+		} else {
+			device.ConsecutiveLowCongruenceDays = 0
+		}
+		factors.ConsecutiveLowCongruenceDays = int(device.ConsecutiveLowCongruenceDays)
+
+		var err error
+		if CalculateDeviceTrustScore(factors).FlaggedForAudit {
+			err = k.suspendDevice(ctx, &device, types.SuspensionReasonCongruenceAudit)
+		} else {
+			err = k.SetDevice(ctx, device)
+		}
+		if err != nil {
+			k.Logger(ctx).Error("failed to store congruence audit", "device_id", device.DeviceID, "error", err)
+		}
+	}
+}
+
+// AppealSuspension lifts a congruence audit suspension for the device owner.
+// The appeal must carry a valid TEE attestation answering the device's pending
+// challenge, so it proves the hardware is genuine now rather than replaying an
+// old attestation. The low-congruence run starts over.
+func (k Keeper) AppealSuspension(ctx sdk.Context, msg *types.MsgAppealSuspension) (*types.Device, error) {
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	device, err := k.GetDevice(ctx, msg.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.OwnerAddress != msg.Owner {
+		return nil, types.ErrDeviceNotOwned
+	}
+	if !device.IsSuspended {
+		return nil, types.ErrInvalidDevice.Wrap("device is not suspended")
+	}
+	if device.SuspensionReason != types.SuspensionReasonCongruenceAudit {
+		return nil, types.ErrUnauthorized.Wrap("only congruence audit suspensions can be appealed")
+	}
+
+	if err := k.consumeChallenge(ctx, device.DeviceID, msg.Nonce); err != nil {
+		return nil, err
+	}
+	verified, err := k.VerifyAttestation(ctx, device.DeviceID, device.TEEType, msg.AttestationData, msg.Nonce)
+	if err != nil || !verified {
+		return nil, types.ErrAttestationFailed.Wrap("appeal attestation verification failed")
+	}
+
+	device.IsSuspended = false
+	device.SuspensionReason = ""
+	device.ConsecutiveLowCongruenceDays = 0
+	device.LastAttestAt = ctx.BlockTime()
+	device.AttestationCount++
+	if err := k.SetDevice(ctx, *device); err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeDeviceReactivated,
+			sdk.NewAttribute(types.AttributeKeyDeviceID, device.DeviceID),
+			sdk.NewAttribute(types.AttributeKeyOwner, device.OwnerAddress),
+			sdk.NewAttribute(types.AttributeKeyReason, "appeal"),
+		),
+	)
+	return device, nil
+}
+
+// ReactivateDevice lifts a device suspension. Only the module authority may reactivate devices.
+func (k Keeper) ReactivateDevice(ctx sdk.Context, msg *types.MsgReactivateDevice) (*types.Device, error) {
+	if msg.Authority != k.authority {
+		return nil, types.ErrUnauthorized.Wrapf("expected %s, got %s", k.authority, msg.Authority)
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	device, err := k.GetDevice(ctx, msg.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !device.IsSuspended {
+		return nil, types.ErrInvalidDevice.Wrap("device is not suspended")
+	}
+
+	device.IsSuspended = false
+	device.SuspensionReason = ""
+	if err := k.SetDevice(ctx, *device); err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeDeviceReactivated,
+			sdk.NewAttribute(types.AttributeKeyDeviceID, device.DeviceID),
+			sdk.NewAttribute(types.AttributeKeyOwner, device.OwnerAddress),
+		),
+	)
+	return device, nil
+}
+
+// ImportDevice stores a device and its owner index without verification (genesis import)
+func (k Keeper) ImportDevice(ctx sdk.Context, device types.Device) error {
+	if err := k.SetDevice(ctx, device); err != nil {
+		return err
+	}
+	ctx.KVStore(k.storeKey).Set(types.GetOwnerDeviceIndexKey(device.OwnerAddress, device.DeviceID), []byte{0x01})
+	return nil
+}
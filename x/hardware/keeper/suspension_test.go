@@ -0,0 +1,191 @@
+package keeper_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware"
+	"github.com/chaincertify/certd/x/hardware/keeper"
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+func TestCongruenceAuditSuspensionAndAppeal(t *testing.T) {
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	start := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).
+		WithBlockHeight(1).
+		WithBlockTime(start)
+	k := keeper.NewKeeper(nil, storeKey, "", nil)
+	ms := keeper.NewMsgServerImpl(k)
+	module := hardware.NewAppModule(nil, k)
+	owner := sdk.AccAddress("device_owner________").String()
+
+	res, err := ms.RegisterDevice(ctx, &types.MsgRegisterDevice{
+		Creator:            owner,
+		Manufacturer:       "Acme",
+		TEEType:            types.TEETypeTrustZone,
+		PublicKey:          []byte("pubkey"),
+		InitialAttestation: []byte("attestation"),
+	})
+	if err != nil {
+		t.Fatalf("RegisterDevice: %v", err)
+	}
+	deviceID := res.DeviceID
+
+	// A high-trust, aged device whose owner counts as a verified human
+	device, err := k.GetDevice(ctx, deviceID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	device.TrustScore = 85
+	device.AttestationCount = 6
+	device.RegisteredAt = start.AddDate(0, -7, 0)
+	if err := k.SetDevice(ctx, *device); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+	if err := k.SetDataCongruence(ctx, deviceID, 30); err != nil {
+		t.Fatalf("SetDataCongruence: %v", err)
+	}
+
+	// endBlock runs the module EndBlock in a block on the given day
+	endBlock := func(day int) sdk.Context {
+		t.Helper()
+		ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1).
+			WithBlockTime(start.AddDate(0, 0, day)).
+			WithEventManager(sdk.NewEventManager())
+		if err := module.EndBlock(ctx); err != nil {
+			t.Fatalf("EndBlock: %v", err)
+		}
+		return ctx
+	}
+	suspensions := func(ctx sdk.Context) []sdk.Event {
+		var out []sdk.Event
+		for _, ev := range ctx.EventManager().Events() {
+			if ev.Type == types.EventTypeDeviceSuspended {
+				out = append(out, ev)
+			}
+		}
+		return out
+	}
+
+	endBlock(0)
+	if score, found := k.GetHumanityScore(ctx, owner); !found || score.Score < 60 {
+		t.Fatalf("humanity score before the audit = %+v (found=%v), want at least 60", score, found)
+	}
+
+	// Two low days, audited once each however many blocks they span
+	endBlock(1)
+	endBlock(1)
+	if device, _ := k.GetDevice(ctx, deviceID); device.IsSuspended || device.ConsecutiveLowCongruenceDays != 2 {
+		t.Fatalf("after two low days: suspended=%v low days=%d, want active with 2",
+			device.IsSuspended, device.ConsecutiveLowCongruenceDays)
+	}
+
+	// The third low day triggers the audit flag and the suspension
+	ctx = endBlock(2)
+	events := suspensions(ctx)
+	if len(events) != 1 || eventAttribute(events[0], types.AttributeKeyDeviceID) != deviceID ||
+		eventAttribute(events[0], types.AttributeKeyReason) != types.SuspensionReasonCongruenceAudit {
+		t.Fatalf("expected one device_suspended event for the audit, got %v", events)
+	}
+	device, err = k.GetDevice(ctx, deviceID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	if !device.IsSuspended || device.SuspensionReason != types.SuspensionReasonCongruenceAudit {
+		t.Fatalf("device not suspended by the audit: %+v", device)
+	}
+	if score, _ := k.GetHumanityScore(ctx, owner); score.Score != 0 || score.DeviceCount != 0 {
+		t.Errorf("suspended device still counts toward the humanity score: %+v", score)
+	}
+
+	appeal := func(nonce []byte) error {
+		_, err := ms.AppealSuspension(ctx, &types.MsgAppealSuspension{
+			Owner:           owner,
+			DeviceID:        deviceID,
+			AttestationData: []byte("fresh attestation"),
+			Nonce:           nonce,
+		})
+		return err
+	}
+
+	// An appeal must answer a pending challenge
+	stale := make([]byte, types.ChallengeNonceSize)
+	if err := appeal(stale); !errors.Is(err, types.ErrChallengeNotFound) {
+		t.Fatalf("appeal without a challenge: got %v, want ErrChallengeNotFound", err)
+	}
+
+	// Only the owner may appeal
+	challenge, err := ms.RequestAttestationChallenge(ctx, &types.MsgRequestAttestationChallenge{Owner: owner, DeviceID: deviceID})
+	if err != nil {
+		t.Fatalf("RequestAttestationChallenge for an audit-suspended device: %v", err)
+	}
+	other := sdk.AccAddress("someone_else________").String()
+	if _, err := ms.AppealSuspension(ctx, &types.MsgAppealSuspension{
+		Owner: other, DeviceID: deviceID, AttestationData: []byte("fresh attestation"), Nonce: challenge.Nonce,
+	}); !errors.Is(err, types.ErrDeviceNotOwned) {
+		t.Fatalf("appeal by a non-owner: got %v, want ErrDeviceNotOwned", err)
+	}
+
+	if err := appeal(challenge.Nonce); err != nil {
+		t.Fatalf("AppealSuspension: %v", err)
+	}
+	device, err = k.GetDevice(ctx, deviceID)
+	if err != nil {
+		t.Fatalf("GetDevice: %v", err)
+	}
+	if device.IsSuspended || device.SuspensionReason != "" || device.ConsecutiveLowCongruenceDays != 0 {
+		t.Errorf("device after a successful appeal: %+v", device)
+	}
+	if err := appeal(challenge.Nonce); !errors.Is(err, types.ErrInvalidDevice) {
+		t.Errorf("appeal of a reinstated device: got %v, want ErrInvalidDevice", err)
+	}
+
+	// The humanity score comes back once the device is reinstated
+	endBlock(2)
+	if score, _ := k.GetHumanityScore(ctx, owner); score.Score < 60 || score.DeviceCount != 1 {
+		t.Errorf("humanity score after the appeal = %+v", score)
+	}
+}
+
+func TestAppealSuspension_OnlyAuditSuspensions(t *testing.T) {
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).
+		WithBlockTime(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	authority := sdk.AccAddress("authority___________").String()
+	k := keeper.NewKeeper(nil, storeKey, authority, nil)
+	ms := keeper.NewMsgServerImpl(k)
+	owner := sdk.AccAddress("device_owner________").String()
+
+	res, err := ms.RegisterDevice(ctx, &types.MsgRegisterDevice{
+		Creator:            owner,
+		Manufacturer:       "Acme",
+		TEEType:            types.TEETypeTrustZone,
+		PublicKey:          []byte("pubkey"),
+		InitialAttestation: []byte("attestation"),
+	})
+	if err != nil {
+		t.Fatalf("RegisterDevice: %v", err)
+	}
+	if _, err := k.SuspendDevice(ctx, &types.MsgSuspendDevice{Authority: authority, DeviceID: res.DeviceID, Reason: "emulator"}); err != nil {
+		t.Fatalf("SuspendDevice: %v", err)
+	}
+
+	// An authority suspension gets no challenge and cannot be appealed
+	if _, err := ms.RequestAttestationChallenge(ctx, &types.MsgRequestAttestationChallenge{Owner: owner, DeviceID: res.DeviceID}); !errors.Is(err, types.ErrDeviceSuspended) {
+		t.Errorf("challenge for an authority-suspended device: got %v, want ErrDeviceSuspended", err)
+	}
+	if _, err := ms.AppealSuspension(ctx, &types.MsgAppealSuspension{
+		Owner:           owner,
+		DeviceID:        res.DeviceID,
+		AttestationData: []byte("fresh attestation"),
+		Nonce:           make([]byte, types.ChallengeNonceSize),
+	}); !errors.Is(err, types.ErrUnauthorized) {
+		t.Errorf("appeal of an authority suspension: got %v, want ErrUnauthorized", err)
+	}
+}
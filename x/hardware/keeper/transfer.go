@@ -0,0 +1,80 @@
+package keeper
+
+import (
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+// TransferDevice moves a device to a new owner. The current owner must sign,
+// suspended devices cannot change hands, and an optional fresh attestation
+// proves the device is present. Trust history is reset unless the trust score
+// config preserves it across transfers.
+func (k Keeper) TransferDevice(ctx sdk.Context, msg *types.MsgTransferDevice) (*types.Device, error) {
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	device, err := k.GetDevice(ctx, msg.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.OwnerAddress != msg.Owner {
+		return nil, types.ErrDeviceNotOwned
+	}
+	if device.IsSuspended {
+		return nil, types.ErrDeviceSuspended.Wrap("suspended devices cannot be transferred")
+	}
+
+	if len(msg.Attestation) > 0 {
+		verified, err := k.VerifyAttestation(ctx, device.DeviceID, device.TEEType, msg.Attestation, nil)
+		if err != nil || !verified {
+			return nil, types.ErrAttestationFailed.Wrap("transfer attestation verification failed")
+		}
+	}
+
+	config := types.DefaultTrustScoreConfig()
+	if !config.TransferPreservesTrust {
+		device.TrustScore = 0
+		device.Uptime = 0
+		device.DataQuality = 0
+		device.AttestationCount = 0
+	}
+	if len(msg.Attestation) > 0 {
+		device.AttestationCount++
+		device.LastAttestAt = ctx.BlockTime()
+	}
+	device.OwnerAddress = msg.NewOwner
+	device.OwnedSince = ctx.BlockTime()
+
+	// Reindex under the new owner; a challenge issued to the previous owner no longer applies
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetOwnerDeviceIndexKey(msg.Owner, device.DeviceID))
+	store.Set(types.GetOwnerDeviceIndexKey(msg.NewOwner, device.DeviceID), []byte{0x01})
+	store.Delete(types.GetPendingChallengeKey(device.DeviceID))
+
+	if err := k.SetDevice(ctx, *device); err != nil {
+		return nil, err
+	}
+	k.MarkHumanityScoreDirty(ctx, msg.Owner)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeDeviceTransferred,
+			sdk.NewAttribute(types.AttributeKeyDeviceID, device.DeviceID),
+			sdk.NewAttribute(types.AttributeKeyPreviousOwner, msg.Owner),
+			sdk.NewAttribute(types.AttributeKeyOwner, msg.NewOwner),
+			sdk.NewAttribute(types.AttributeKeyTrustPreserved, strconv.FormatBool(config.TransferPreservesTrust)),
+		),
+	)
+
+	k.Logger(ctx).Info("device transferred",
+		"device_id", device.DeviceID,
+		"previous_owner", msg.Owner,
+		"owner", msg.NewOwner,
+	)
+
+	return device, nil
+}
@@ -0,0 +1,169 @@
+package keeper_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/chaincertify/certd/x/hardware/keeper"
+	"github.com/chaincertify/certd/x/hardware/types"
+)
+
+func setupTransfer(t *testing.T) (sdk.Context, keeper.Keeper, *types.Device) {
+	t.Helper()
+	storeKey := storetypes.NewKVStoreKey(types.StoreKey)
+	ctx := testutil.DefaultContext(storeKey, storetypes.NewTransientStoreKey("transient_test")).
+		WithBlockTime(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	k := keeper.NewKeeper(nil, storeKey, "", nil)
+
+	device, err := k.RegisterDevice(ctx, &types.MsgRegisterDevice{
+		Creator:            sdk.AccAddress("device_owner________").String(),
+		Manufacturer:       "Acme",
+		TEEType:            types.TEETypeTrustZone,
+		PublicKey:          []byte("pubkey"),
+		InitialAttestation: []byte("attestation"),
+	})
+	if err != nil {
+		t.Fatalf("RegisterDevice: %v", err)
+	}
+	device.TrustScore = 85
+	device.Uptime = 99
+	device.AttestationCount = 12
+	device.RegisteredAt = ctx.BlockTime().AddDate(-1, 0, 0)
+	if err := k.SetDevice(ctx, *device); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+	return ctx, k, device
+}
+
+func TestTransferDevice(t *testing.T) {
+	ctx, k, device := setupTransfer(t)
+	owner := device.OwnerAddress
+	buyer := sdk.AccAddress("device_buyer________").String()
+
+	transferred, err := k.TransferDevice(ctx, &types.MsgTransferDevice{
+		Owner:       owner,
+		DeviceID:    device.DeviceID,
+		NewOwner:    buyer,
+		Attestation: []byte("fresh-attestation"),
+	})
+	if err != nil {
+		t.Fatalf("TransferDevice: %v", err)
+	}
+	if transferred.OwnerAddress != buyer || !transferred.OwnedSince.Equal(ctx.BlockTime()) {
+		t.Errorf("unexpected owner state: %+v", transferred)
+	}
+	// Trust history resets by default; the fresh attestation counts for the new owner
+	if transferred.TrustScore != 0 || transferred.Uptime != 0 || transferred.AttestationCount != 1 {
+		t.Errorf("trust history not reset: %+v", transferred)
+	}
+
+	stored, err := k.GetDevice(ctx, device.DeviceID)
+	if err != nil || stored.OwnerAddress != buyer {
+		t.Fatalf("stored device = %+v, %v", stored, err)
+	}
+	if devices := k.GetDevicesByOwner(ctx, owner); len(devices) != 0 {
+		t.Errorf("previous owner still indexed with %d devices", len(devices))
+	}
+	if devices := k.GetDevicesByOwner(ctx, buyer); len(devices) != 1 || devices[0].DeviceID != device.DeviceID {
+		t.Errorf("new owner devices = %+v", devices)
+	}
+
+	var found bool
+	for _, ev := range ctx.EventManager().Events() {
+		if ev.Type == types.EventTypeDeviceTransferred {
+			found = eventAttribute(ev, types.AttributeKeyPreviousOwner) == owner &&
+				eventAttribute(ev, types.AttributeKeyOwner) == buyer &&
+				eventAttribute(ev, types.AttributeKeyTrustPreserved) == "false"
+		}
+	}
+	if !found {
+		t.Error("expected a device_transferred event")
+	}
+
+	// Device age moves with ownership: the buyer's humanity score starts fresh
+	k.UpdateHumanityScores(ctx)
+	if score, ok := k.GetHumanityScore(ctx, buyer); !ok || score.DeviceCount != 1 || score.Score != 0 {
+		t.Errorf("buyer humanity score = %+v (found=%v)", score, ok)
+	}
+	if score, ok := k.GetHumanityScore(ctx, owner); !ok || score.DeviceCount != 0 {
+		t.Errorf("seller humanity score = %+v (found=%v)", score, ok)
+	}
+}
+
+func TestMsgServerTransferDevice(t *testing.T) {
+	ctx, k, device := setupTransfer(t)
+	ms := keeper.NewMsgServerImpl(k)
+	buyer := sdk.AccAddress("device_buyer________").String()
+
+	if _, err := ms.TransferDevice(ctx, nil); !errors.Is(err, types.ErrInvalidDevice) {
+		t.Errorf("nil request: got %v, want ErrInvalidDevice", err)
+	}
+
+	res, err := ms.TransferDevice(ctx, &types.MsgTransferDevice{
+		Owner:       device.OwnerAddress,
+		DeviceID:    device.DeviceID,
+		NewOwner:    buyer,
+		Attestation: []byte("fresh-attestation"),
+	})
+	if err != nil {
+		t.Fatalf("TransferDevice: %v", err)
+	}
+	if res.TrustScore != 0 {
+		t.Errorf("trust score = %d, want a reset score", res.TrustScore)
+	}
+	if stored, err := k.GetDevice(ctx, device.DeviceID); err != nil || stored.OwnerAddress != buyer {
+		t.Errorf("stored device = %+v, %v", stored, err)
+	}
+}
+
+func TestTransferDevice_Unauthorized(t *testing.T) {
+	ctx, k, device := setupTransfer(t)
+	thief := sdk.AccAddress("not_the_owner_______").String()
+
+	_, err := k.TransferDevice(ctx, &types.MsgTransferDevice{
+		Owner:    thief,
+		DeviceID: device.DeviceID,
+		NewOwner: thief + "x",
+	})
+	if err == nil {
+		t.Fatal("expected an invalid new owner to be rejected")
+	}
+
+	_, err = k.TransferDevice(ctx, &types.MsgTransferDevice{
+		Owner:    thief,
+		DeviceID: device.DeviceID,
+		NewOwner: sdk.AccAddress("accomplice__________").String(),
+	})
+	if !errors.Is(err, types.ErrDeviceNotOwned) {
+		t.Fatalf("expected ErrDeviceNotOwned, got %v", err)
+	}
+	if stored, _ := k.GetDevice(ctx, device.DeviceID); stored.OwnerAddress != device.OwnerAddress || stored.TrustScore != 85 {
+		t.Errorf("device modified by unauthorized transfer: %+v", stored)
+	}
+}
+
+func TestTransferDevice_Suspended(t *testing.T) {
+	ctx, k, device := setupTransfer(t)
+	device.IsSuspended = true
+	device.SuspensionReason = "emulator detected"
+	if err := k.SetDevice(ctx, *device); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+
+	_, err := k.TransferDevice(ctx, &types.MsgTransferDevice{
+		Owner:    device.OwnerAddress,
+		DeviceID: device.DeviceID,
+		NewOwner: sdk.AccAddress("device_buyer________").String(),
+	})
+	if !errors.Is(err, types.ErrDeviceSuspended) {
+		t.Fatalf("expected ErrDeviceSuspended, got %v", err)
+	}
+	if devices := k.GetDevicesByOwner(ctx, device.OwnerAddress); len(devices) != 1 {
+		t.Errorf("suspended device was reindexed: %+v", devices)
+	}
+}
@@ -12,7 +12,9 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/spf13/cobra"
 
+	"github.com/chaincertify/certd/x/hardware/client/cli"
 	"github.com/chaincertify/certd/x/hardware/keeper"
 	"github.com/chaincertify/certd/x/hardware/types"
 )
@@ -40,12 +42,12 @@ func (AppModuleBasic) Name() string {
 
 // RegisterLegacyAminoCodec registers the module's types for legacy amino
 func (AppModuleBasic) RegisterLegacyAminoCodec(cdc *codec.LegacyAmino) {
-	// TODO: Register amino types
+	types.RegisterCodec(cdc)
 }
 
 // RegisterInterfaces registers the module's interface types
 func (AppModuleBasic) RegisterInterfaces(registry cdctypes.InterfaceRegistry) {
-	// TODO: Register interface types
+	types.RegisterInterfaces(registry)
 }
 
 // DefaultGenesis returns default genesis state
@@ -69,6 +71,11 @@ func (AppModuleBasic) RegisterGRPCGatewayRoutes(clientCtx client.Context, mux *r
 	// TODO: Register gRPC gateway routes
 }
 
+// GetQueryCmd returns the hardware module's root query command
+func (AppModuleBasic) GetQueryCmd() *cobra.Command {
+	return cli.GetQueryCmd()
+}
+
 // AppModule implements the sdk.AppModule interface
 type AppModule struct {
 	AppModuleBasic
@@ -111,24 +118,50 @@ func (am AppModule) ConsensusVersion() uint64 {
 }
 
 // RegisterServices registers module services
+// NOTE: As in the attestation module, service registration is skipped: the
+// hardware types are manual Go types rather than protobuf-generated code, and
+// Cosmos SDK v0.50.x requires proto descriptors in the global registry for gRPC
+// service registration. The msg server is reached through keeper.NewMsgServerImpl.
 func (am AppModule) RegisterServices(cfg module.Configurator) {
-	// TODO: Register msg server and query server
+	// No-op: Skip gRPC service registration for the hardware module
 }
 
 // Validate validates genesis state
 func (gs GenesisState) Validate() error {
+	seen := make(map[string]bool, len(gs.Devices))
+	for _, device := range gs.Devices {
+		if device.DeviceID == "" {
+			return types.ErrInvalidDevice.Wrap("device ID cannot be empty")
+		}
+		if seen[device.DeviceID] {
+			return types.ErrDeviceAlreadyExists.Wrapf("duplicate device %s", device.DeviceID)
+		}
+		seen[device.DeviceID] = true
+		if _, err := sdk.AccAddressFromBech32(device.OwnerAddress); err != nil {
+			return types.ErrInvalidAddress.Wrapf("device %s: invalid owner address", device.DeviceID)
+		}
+	}
 	return nil
 }
 
-// InitGenesis initializes state from genesis
+// InitGenesis initializes state from genesis and recomputes the hardware stats
 func InitGenesis(ctx sdk.Context, k keeper.Keeper, gs GenesisState) {
-	// TODO: Initialize devices from genesis
+	for _, device := range gs.Devices {
+		if err := k.ImportDevice(ctx, device); err != nil {
+			panic(fmt.Sprintf("failed to import device %s: %v", device.DeviceID, err))
+		}
+	}
+	k.RecomputeHardwareStats(ctx)
 }
 
 // ExportGenesis exports state to genesis
 func ExportGenesis(ctx sdk.Context, k keeper.Keeper) GenesisState {
+	devices := k.GetAllDevices(ctx)
+	if devices == nil {
+		devices = []types.Device{}
+	}
 	return GenesisState{
-		Devices: []types.Device{},
+		Devices: devices,
 	}
 }
 
@@ -137,8 +170,12 @@ func (am AppModule) BeginBlock(ctx context.Context) error {
 	return nil
 }
 
-// EndBlock is called at the end of each block
+// EndBlock runs the daily data congruence audit, then recomputes humanity scores
+// for addresses whose devices changed this block (including any it suspended)
 func (am AppModule) EndBlock(ctx context.Context) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	am.keeper.AuditDataCongruence(sdkCtx)
+	am.keeper.UpdateHumanityScores(sdkCtx)
 	return nil
 }
 
@@ -0,0 +1,36 @@
+package types
+
+import "time"
+
+const (
+	// ChallengeNonceSize is the length in bytes of an attestation challenge nonce
+	ChallengeNonceSize = 32
+
+	// ChallengeTTL is how long an issued challenge can be answered
+	ChallengeTTL = 5 * time.Minute
+)
+
+// AttestationChallenge is a one-time nonce issued to a device. The next
+// periodic or challenge attestation from the device must sign over it, and
+// the challenge is deleted once answered so the attestation cannot be replayed.
+type AttestationChallenge struct {
+	// DeviceID is the device the nonce is bound to
+	DeviceID string `json:"device_id"`
+
+	// Nonce is the value the attestation must cover
+	Nonce []byte `json:"nonce"`
+
+	// Requester is the owner address that requested the challenge
+	Requester string `json:"requester"`
+
+	// IssuedAt is the block time the challenge was issued
+	IssuedAt time.Time `json:"issued_at"`
+
+	// ExpiresAt is when the challenge stops being accepted
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IsExpired reports whether the challenge can no longer be answered at now
+func (c AttestationChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
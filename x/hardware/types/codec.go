@@ -0,0 +1,72 @@
+package types
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	cdctypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// RegisterCodec registers the hardware module messages for Amino JSON serialization
+func RegisterCodec(cdc *codec.LegacyAmino) {
+	cdc.RegisterConcrete(&MsgRegisterDevice{}, "cert/hardware/MsgRegisterDevice", nil)
+	cdc.RegisterConcrete(&MsgRequestAttestationChallenge{}, "cert/hardware/MsgRequestAttestationChallenge", nil)
+	cdc.RegisterConcrete(&MsgSubmitAttestation{}, "cert/hardware/MsgSubmitAttestation", nil)
+	cdc.RegisterConcrete(&MsgAppealSuspension{}, "cert/hardware/MsgAppealSuspension", nil)
+	cdc.RegisterConcrete(&MsgTransferDevice{}, "cert/hardware/MsgTransferDevice", nil)
+	cdc.RegisterConcrete(&MsgSetFirmwareRelease{}, "cert/hardware/MsgSetFirmwareRelease", nil)
+	cdc.RegisterConcrete(&MsgReportFirmware{}, "cert/hardware/MsgReportFirmware", nil)
+	cdc.RegisterConcrete(&MsgSetManufacturer{}, "cert/hardware/MsgSetManufacturer", nil)
+	cdc.RegisterConcrete(&MsgRemoveManufacturer{}, "cert/hardware/MsgRemoveManufacturer", nil)
+	cdc.RegisterConcrete(&MsgSetManufacturerStrictMode{}, "cert/hardware/MsgSetManufacturerStrictMode", nil)
+}
+
+// RegisterInterfaces registers the hardware module messages with the interface registry.
+// The XXX_MessageName() method on each message provides its TypeURL.
+func RegisterInterfaces(registry cdctypes.InterfaceRegistry) {
+	registry.RegisterImplementations(
+		(*sdk.Msg)(nil),
+		&MsgRegisterDevice{},
+		&MsgRequestAttestationChallenge{},
+		&MsgSubmitAttestation{},
+		&MsgAppealSuspension{},
+		&MsgTransferDevice{},
+		&MsgSetFirmwareRelease{},
+		&MsgReportFirmware{},
+		&MsgSetManufacturer{},
+		&MsgRemoveManufacturer{},
+		&MsgSetManufacturerStrictMode{},
+	)
+}
+
+var (
+	Amino     = codec.NewLegacyAmino()
+	ModuleCdc = codec.NewProtoCodec(cdctypes.NewInterfaceRegistry())
+)
+
+func init() {
+	RegisterCodec(Amino)
+	Amino.Seal()
+
+	// Register the manually-defined message types with gogoproto for gRPC marshaling
+	proto.RegisterType((*MsgRegisterDevice)(nil), "cert.hardware.v1.MsgRegisterDevice")
+	proto.RegisterType((*MsgRegisterDeviceResponse)(nil), "cert.hardware.v1.MsgRegisterDeviceResponse")
+	proto.RegisterType((*MsgRequestAttestationChallenge)(nil), "cert.hardware.v1.MsgRequestAttestationChallenge")
+	proto.RegisterType((*MsgRequestAttestationChallengeResponse)(nil), "cert.hardware.v1.MsgRequestAttestationChallengeResponse")
+	proto.RegisterType((*MsgSubmitAttestation)(nil), "cert.hardware.v1.MsgSubmitAttestation")
+	proto.RegisterType((*MsgSubmitAttestationResponse)(nil), "cert.hardware.v1.MsgSubmitAttestationResponse")
+	proto.RegisterType((*MsgAppealSuspension)(nil), "cert.hardware.v1.MsgAppealSuspension")
+	proto.RegisterType((*MsgAppealSuspensionResponse)(nil), "cert.hardware.v1.MsgAppealSuspensionResponse")
+	proto.RegisterType((*MsgTransferDevice)(nil), "cert.hardware.v1.MsgTransferDevice")
+	proto.RegisterType((*MsgTransferDeviceResponse)(nil), "cert.hardware.v1.MsgTransferDeviceResponse")
+	proto.RegisterType((*MsgSetFirmwareRelease)(nil), "cert.hardware.v1.MsgSetFirmwareRelease")
+	proto.RegisterType((*MsgSetFirmwareReleaseResponse)(nil), "cert.hardware.v1.MsgSetFirmwareReleaseResponse")
+	proto.RegisterType((*MsgReportFirmware)(nil), "cert.hardware.v1.MsgReportFirmware")
+	proto.RegisterType((*MsgReportFirmwareResponse)(nil), "cert.hardware.v1.MsgReportFirmwareResponse")
+	proto.RegisterType((*MsgSetManufacturer)(nil), "cert.hardware.v1.MsgSetManufacturer")
+	proto.RegisterType((*MsgSetManufacturerResponse)(nil), "cert.hardware.v1.MsgSetManufacturerResponse")
+	proto.RegisterType((*MsgRemoveManufacturer)(nil), "cert.hardware.v1.MsgRemoveManufacturer")
+	proto.RegisterType((*MsgRemoveManufacturerResponse)(nil), "cert.hardware.v1.MsgRemoveManufacturerResponse")
+	proto.RegisterType((*MsgSetManufacturerStrictMode)(nil), "cert.hardware.v1.MsgSetManufacturerStrictMode")
+	proto.RegisterType((*MsgSetManufacturerStrictModeResponse)(nil), "cert.hardware.v1.MsgSetManufacturerStrictModeResponse")
+}
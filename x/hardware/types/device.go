@@ -92,6 +92,9 @@ type Device struct {
 	// RegisteredAt is the timestamp when device was registered
 	RegisteredAt time.Time `json:"registered_at"`
 
+	// OwnedSince is when the current owner acquired the device (zero means since registration)
+	OwnedSince time.Time `json:"owned_since,omitempty"`
+
 	// IsActive indicates if device is currently active
 	IsActive bool `json:"is_active"`
 
@@ -100,8 +103,38 @@ type Device struct {
 
 	// SuspensionReason provides context for suspension
 	SuspensionReason string `json:"suspension_reason,omitempty"`
+
+	// CongruenceUpdatedAt is when DataQuality was last measured; zero until the first measurement
+	CongruenceUpdatedAt time.Time `json:"congruence_updated_at,omitempty"`
+
+	// ConsecutiveLowCongruenceDays counts daily audits in a row that found DataQuality below 50
+	ConsecutiveLowCongruenceDays uint64 `json:"consecutive_low_congruence_days,omitempty"`
+
+	// FirmwareVersion is the firmware version last reported by the device
+	FirmwareVersion uint64 `json:"firmware_version,omitempty"`
+
+	// FirmwareHash is the SHA-256 hash of the reported firmware image
+	FirmwareHash []byte `json:"firmware_hash,omitempty"`
+
+	// FirmwareVerified indicates the reported firmware matched a registered release
+	FirmwareVerified bool `json:"firmware_verified"`
+
+	// ManufacturerVerified indicates registration carried a valid signature from a registered manufacturer
+	ManufacturerVerified bool `json:"manufacturer_verified"`
+
+	// GeoBucket is the coarse region the device last reported attesting from
+	GeoBucket string `json:"geo_bucket,omitempty"`
 }
 
+// SuspensionReasonCongruenceAudit is the reason recorded when the daily
+// congruence audit suspends a device. Only these suspensions can be appealed
+// by the owner; the others are lifted by the module authority.
+const SuspensionReasonCongruenceAudit = "failed data congruence audit"
+
+// MaxGeoBucketLength caps a reported geo bucket, which is a coarse region
+// identifier such as a country code or short geohash, never a precise location
+const MaxGeoBucketLength = 16
+
 // TEEAttestation represents a cryptographic proof from a TEE
 // This proves the device is genuine hardware, not emulated
 type TEEAttestation struct {
@@ -174,9 +207,8 @@ type HumanityScore struct {
 	LastUpdated time.Time `json:"last_updated"`
 }
 
-// NewDevice creates a new Device instance
-func NewDevice(deviceID, manufacturer string, teeType TEEType, publicKey []byte, owner sdk.AccAddress) *Device {
-	now := time.Now()
+// NewDevice creates a new Device instance registered at now (the block time in state transitions)
+func NewDevice(deviceID, manufacturer string, teeType TEEType, publicKey []byte, owner sdk.AccAddress, now time.Time) *Device {
 	return &Device{
 		DeviceID:         deviceID,
 		Manufacturer:     manufacturer,
@@ -19,4 +19,9 @@ var (
 	ErrLinkFailed = errors.Register(ModuleName, 12, "failed to link device to CertID")
 	ErrChallengeMismatch = errors.Register(ModuleName, 13, "challenge nonce mismatch")
 	ErrChallengeExpired = errors.Register(ModuleName, 14, "challenge has expired")
+	ErrInvalidTransfer = errors.Register(ModuleName, 15, "invalid device transfer")
+	ErrInvalidFirmware = errors.Register(ModuleName, 16, "invalid firmware release")
+	ErrUnknownManufacturer = errors.Register(ModuleName, 17, "unknown manufacturer")
+	ErrInvalidManufacturerSig = errors.Register(ModuleName, 18, "invalid manufacturer signature")
+	ErrChallengeNotFound = errors.Register(ModuleName, 19, "no pending challenge for device")
 )
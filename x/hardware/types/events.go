@@ -8,6 +8,14 @@ const (
 	EventTypeDeviceSuspended     = "device_suspended"
 	EventTypeDeviceReactivated   = "device_reactivated"
 	EventTypeTrustScoreUpdated   = "device_trust_updated"
+	EventTypeHumanityScoreChanged = "humanity_score_changed"
+	EventTypeDeviceTransferred    = "device_transferred"
+	EventTypeFirmwareReleased     = "firmware_released"
+	EventTypeFirmwareReported     = "firmware_reported"
+	EventTypeManufacturerSet      = "manufacturer_set"
+	EventTypeManufacturerRemoved  = "manufacturer_removed"
+	EventTypeManufacturerStrictMode = "manufacturer_strict_mode"
+	EventTypeChallengeIssued        = "attestation_challenge_issued"
 
 	AttributeKeyDeviceID       = "device_id"
 	AttributeKeyManufacturer   = "manufacturer"
@@ -17,4 +25,18 @@ const (
 	AttributeKeyCertIDAddress  = "certid_address"
 	AttributeKeyReason         = "reason"
 	AttributeKeyAttestationType = "attestation_type"
+	AttributeKeyAddress         = "address"
+	AttributeKeyHumanityScore   = "humanity_score"
+	AttributeKeyPreviousScore   = "previous_score"
+	AttributeKeyVerifiedHuman   = "verified_human"
+	AttributeKeyThresholdCrossed = "threshold_crossed"
+	AttributeKeyPreviousOwner    = "previous_owner"
+	AttributeKeyTrustPreserved   = "trust_preserved"
+	AttributeKeyModel            = "model"
+	AttributeKeyFirmwareVersion  = "firmware_version"
+	AttributeKeyFirmwareHash     = "firmware_hash"
+	AttributeKeyFirmwareVerified = "firmware_verified"
+	AttributeKeyManufacturerVerified = "manufacturer_verified"
+	AttributeKeyStrict           = "strict"
+	AttributeKeyExpiresAt        = "expires_at"
 )
@@ -0,0 +1,39 @@
+package types
+
+import "time"
+
+// FirmwareHashSize is the size of a firmware image hash (SHA-256)
+const FirmwareHashSize = 32
+
+// FirmwareRelease is a signed firmware release in the firmware registry.
+// The newest registered version for a manufacturer/model is its latest firmware.
+type FirmwareRelease struct {
+	// Manufacturer must match the device's Manufacturer exactly
+	Manufacturer string `json:"manufacturer" protobuf:"bytes,1,opt,name=manufacturer,proto3"`
+
+	// Model must match the device's Model exactly (empty matches devices without a model)
+	Model string `json:"model,omitempty" protobuf:"bytes,2,opt,name=model,proto3"`
+
+	// Version is the firmware version number
+	Version uint64 `json:"version" protobuf:"varint,3,opt,name=version,proto3"`
+
+	// Hash is the SHA-256 hash of the signed firmware image
+	Hash []byte `json:"hash" protobuf:"bytes,4,opt,name=hash,proto3"`
+
+	// ReleasedAt is when the release was registered
+	ReleasedAt time.Time `json:"released_at" protobuf:"bytes,5,opt,name=released_at,json=releasedAt,proto3,stdtime"`
+}
+
+// Validate checks that a firmware release is well formed
+func (r FirmwareRelease) Validate() error {
+	if r.Manufacturer == "" {
+		return ErrInvalidFirmware.Wrap("manufacturer cannot be empty")
+	}
+	if r.Version == 0 {
+		return ErrInvalidFirmware.Wrap("firmware version must be positive")
+	}
+	if len(r.Hash) != FirmwareHashSize {
+		return ErrInvalidFirmware.Wrapf("firmware hash must be %d bytes", FirmwareHashSize)
+	}
+	return nil
+}
@@ -1,5 +1,7 @@
 package types
 
+import "encoding/binary"
+
 // Store key prefixes for the hardware module
 var (
 	// DeviceKeyPrefix is the prefix for device storage
@@ -19,8 +21,34 @@ var (
 	HumanityScoreKeyPrefix = []byte{0x04}
 
 	// PendingChallengePrefix stores pending attestation challenges
-	// Format: PendingChallengePrefix | DeviceID -> Challenge
+	// Format: PendingChallengePrefix | DeviceID -> AttestationChallenge
 	PendingChallengePrefix = []byte{0x05}
+
+	// HumanityScoreDirtyPrefix marks addresses whose humanity score is recomputed in EndBlock
+	// Format: HumanityScoreDirtyPrefix | Address -> nil
+	HumanityScoreDirtyPrefix = []byte{0x06}
+
+	// FirmwareReleasePrefix stores the firmware registry
+	// Format: FirmwareReleasePrefix | len(Manufacturer) | Manufacturer | len(Model) | Model | Version -> FirmwareRelease
+	FirmwareReleasePrefix = []byte{0x07}
+
+	// ManufacturerKeyPrefix stores the trusted manufacturer registry
+	// Format: ManufacturerKeyPrefix | Name -> Manufacturer
+	ManufacturerKeyPrefix = []byte{0x08}
+
+	// ManufacturerStrictModeKey is set when device registration requires a registered manufacturer signature
+	ManufacturerStrictModeKey = []byte{0x09}
+
+	// HardwareStatsKey stores the network-wide device counters
+	// Format: HardwareStatsKey -> HardwareStats
+	HardwareStatsKey = []byte{0x0A}
+
+	// ChallengeSequenceKey stores the number of attestation challenges issued,
+	// mixed into each nonce so two challenges never share one
+	ChallengeSequenceKey = []byte{0x0B}
+
+	// CongruenceAuditDayKey stores the UTC day (Unix days) of the last data congruence audit
+	CongruenceAuditDayKey = []byte{0x0C}
 )
 
 // GetDeviceKey returns the store key for a device
@@ -52,6 +80,38 @@ func GetHumanityScoreKey(address string) []byte {
 	return append(HumanityScoreKeyPrefix, []byte(address)...)
 }
 
+// GetHumanityScoreDirtyKey returns the marker key for a pending humanity score update
+func GetHumanityScoreDirtyKey(address string) []byte {
+	return append(HumanityScoreDirtyPrefix, []byte(address)...)
+}
+
+// GetFirmwareModelPrefix returns the registry prefix for all releases of a manufacturer/model.
+// Each part is prefixed with its uvarint length so no manufacturer/model pair
+// shares a prefix with another, whatever characters the names contain.
+func GetFirmwareModelPrefix(manufacturer, model string) []byte {
+	key := append([]byte{}, FirmwareReleasePrefix...)
+	key = binary.AppendUvarint(key, uint64(len(manufacturer)))
+	key = append(key, manufacturer...)
+	key = binary.AppendUvarint(key, uint64(len(model)))
+	return append(key, model...)
+}
+
+// GetFirmwareReleaseKey returns the registry key for one firmware release
+func GetFirmwareReleaseKey(manufacturer, model string, version uint64) []byte {
+	// Big-endian version so releases iterate in version order
+	versionBytes := make([]byte, 8)
+	for i := 7; i >= 0; i-- {
+		versionBytes[i] = byte(version & 0xff)
+		version >>= 8
+	}
+	return append(GetFirmwareModelPrefix(manufacturer, model), versionBytes...)
+}
+
+// GetManufacturerKey returns the store key for a registered manufacturer
+func GetManufacturerKey(name string) []byte {
+	return append(ManufacturerKeyPrefix, []byte(name)...)
+}
+
 // GetPendingChallengeKey returns the store key for a pending challenge
 func GetPendingChallengeKey(deviceID string) []byte {
 	return append(PendingChallengePrefix, []byte(deviceID)...)
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"crypto/sha256"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+)
+
+// Manufacturer signing key types
+const (
+	ManufacturerKeyEd25519   = "ed25519"
+	ManufacturerKeySecp256k1 = "secp256k1"
+)
+
+// Manufacturer is a trusted device manufacturer whose key signs device attestations
+type Manufacturer struct {
+	// Name must match Device.Manufacturer exactly
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name,proto3"`
+
+	// KeyType is ManufacturerKeyEd25519 or ManufacturerKeySecp256k1
+	KeyType string `json:"key_type" protobuf:"bytes,2,opt,name=key_type,json=keyType,proto3"`
+
+	// PublicKey is the raw manufacturer public key
+	PublicKey []byte `json:"public_key" protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3"`
+}
+
+// Validate checks that a manufacturer entry is well formed
+func (m Manufacturer) Validate() error {
+	if m.Name == "" {
+		return ErrUnknownManufacturer.Wrap("manufacturer name cannot be empty")
+	}
+	_, err := m.PubKey()
+	return err
+}
+
+// PubKey returns the manufacturer's public key
+func (m Manufacturer) PubKey() (cryptotypes.PubKey, error) {
+	switch m.KeyType {
+	case ManufacturerKeyEd25519:
+		if len(m.PublicKey) != ed25519.PubKeySize {
+			return nil, ErrInvalidManufacturerSig.Wrapf("ed25519 public key must be %d bytes", ed25519.PubKeySize)
+		}
+		return &ed25519.PubKey{Key: m.PublicKey}, nil
+	case ManufacturerKeySecp256k1:
+		if len(m.PublicKey) != secp256k1.PubKeySize {
+			return nil, ErrInvalidManufacturerSig.Wrapf("secp256k1 public key must be %d bytes", secp256k1.PubKeySize)
+		}
+		return &secp256k1.PubKey{Key: m.PublicKey}, nil
+	default:
+		return nil, ErrInvalidManufacturerSig.Wrapf("unsupported manufacturer key type: %q", m.KeyType)
+	}
+}
+
+// ManufacturerSignBytes returns the bytes a manufacturer signs for a device:
+// the SHA-256 of the device's TEE public key followed by its initial attestation
+func ManufacturerSignBytes(devicePublicKey, attestation []byte) []byte {
+	keyHash := sha256.Sum256(devicePublicKey)
+	return append(keyHash[:], attestation...)
+}
@@ -11,29 +11,48 @@ const (
 	TypeMsgLinkDeviceToCertID = "link_device_to_certid"
 	TypeMsgSuspendDevice     = "suspend_device"
 	TypeMsgReactivateDevice  = "reactivate_device"
+	TypeMsgTransferDevice    = "transfer_device"
+	TypeMsgSetFirmwareRelease = "set_firmware_release"
+	TypeMsgReportFirmware    = "report_firmware"
+	TypeMsgSetManufacturer   = "set_manufacturer"
+	TypeMsgRemoveManufacturer = "remove_manufacturer"
+	TypeMsgSetManufacturerStrictMode = "set_manufacturer_strict_mode"
+	TypeMsgRequestAttestationChallenge = "request_attestation_challenge"
+	TypeMsgAppealSuspension = "appeal_suspension"
 )
 
 // MsgRegisterDevice registers a new hardware device
 type MsgRegisterDevice struct {
 	// Creator is the address registering the device
-	Creator string `json:"creator"`
+	Creator string `json:"creator" protobuf:"bytes,1,opt,name=creator,proto3"`
 
 	// Manufacturer of the device
-	Manufacturer string `json:"manufacturer"`
+	Manufacturer string `json:"manufacturer" protobuf:"bytes,2,opt,name=manufacturer,proto3"`
 
 	// Model of the device (optional)
-	Model string `json:"model,omitempty"`
+	Model string `json:"model,omitempty" protobuf:"bytes,3,opt,name=model,proto3"`
 
 	// TEEType specifies the TEE type (ARM_TRUSTZONE, APPLE_SECURE_ENCLAVE)
-	TEEType TEEType `json:"tee_type"`
+	TEEType TEEType `json:"tee_type" protobuf:"bytes,4,opt,name=tee_type,proto3,casttype=TEEType"`
 
 	// PublicKey is the hardware-bound public key from TEE
-	PublicKey []byte `json:"public_key"`
+	PublicKey []byte `json:"public_key" protobuf:"bytes,5,opt,name=public_key,proto3"`
 
 	// InitialAttestation is the attestation proof for registration
-	InitialAttestation []byte `json:"initial_attestation"`
+	InitialAttestation []byte `json:"initial_attestation" protobuf:"bytes,6,opt,name=initial_attestation,proto3"`
+
+	// ManufacturerSig is the manufacturer's signature over ManufacturerSignBytes
+	ManufacturerSig []byte `json:"manufacturer_sig,omitempty" protobuf:"bytes,7,opt,name=manufacturer_sig,proto3"`
 }
 
+// Proto interface implementations for MsgRegisterDevice
+func (msg *MsgRegisterDevice) Reset()         { *msg = MsgRegisterDevice{} }
+func (msg *MsgRegisterDevice) String() string { return msg.Creator }
+func (msg *MsgRegisterDevice) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgRegisterDevice) XXX_MessageName() string { return "cert.hardware.v1.MsgRegisterDevice" }
+
 // Route implements sdk.Msg
 func (msg MsgRegisterDevice) Route() string { return RouterKey }
 
@@ -75,21 +94,33 @@ func (msg MsgRegisterDevice) GetSigners() []sdk.AccAddress {
 // MsgSubmitAttestation submits a TEE attestation for verification
 type MsgSubmitAttestation struct {
 	// Submitter is the device owner submitting attestation
-	Submitter string `json:"submitter"`
+	Submitter string `json:"submitter" protobuf:"bytes,1,opt,name=submitter,proto3"`
 
 	// DeviceID identifies the attesting device
-	DeviceID string `json:"device_id"`
+	DeviceID string `json:"device_id" protobuf:"bytes,2,opt,name=device_id,proto3"`
 
 	// AttestationData is the raw TEE attestation blob
-	AttestationData []byte `json:"attestation_data"`
+	AttestationData []byte `json:"attestation_data" protobuf:"bytes,3,opt,name=attestation_data,proto3"`
 
-	// Nonce is the challenge nonce (if challenge-response)
-	Nonce []byte `json:"nonce,omitempty"`
+	// Nonce is the challenge nonce from MsgRequestAttestationChallenge;
+	// required for periodic and challenge attestations
+	Nonce []byte `json:"nonce,omitempty" protobuf:"bytes,4,opt,name=nonce,proto3"`
 
 	// AttestationType indicates attestation context
-	AttestationType AttestationType `json:"attestation_type"`
+	AttestationType AttestationType `json:"attestation_type" protobuf:"bytes,5,opt,name=attestation_type,proto3,casttype=AttestationType"`
+
+	// GeoBucket is the coarse region the device is attesting from (optional)
+	GeoBucket string `json:"geo_bucket,omitempty" protobuf:"bytes,6,opt,name=geo_bucket,proto3"`
 }
 
+// Proto interface implementations for MsgSubmitAttestation
+func (msg *MsgSubmitAttestation) Reset()         { *msg = MsgSubmitAttestation{} }
+func (msg *MsgSubmitAttestation) String() string { return msg.Submitter }
+func (msg *MsgSubmitAttestation) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgSubmitAttestation) XXX_MessageName() string { return "cert.hardware.v1.MsgSubmitAttestation" }
+
 // Route implements sdk.Msg
 func (msg MsgSubmitAttestation) Route() string { return RouterKey }
 
@@ -111,6 +142,22 @@ func (msg MsgSubmitAttestation) ValidateBasic() error {
 		return ErrInvalidAttestation.Wrap("attestation data cannot be empty")
 	}
 
+	if len(msg.GeoBucket) > MaxGeoBucketLength {
+		return ErrInvalidAttestation.Wrapf("geo bucket longer than %d characters", MaxGeoBucketLength)
+	}
+
+	switch msg.AttestationType {
+	case AttestationTypePeriodic, AttestationTypeChallenge:
+		if len(msg.Nonce) != ChallengeNonceSize {
+			return ErrChallengeMismatch.Wrapf("%s attestation requires a %d-byte challenge nonce", msg.AttestationType, ChallengeNonceSize)
+		}
+	case AttestationTypeBoot:
+	case AttestationTypeInitial:
+		return ErrInvalidAttestation.Wrap("initial attestations are submitted with device registration")
+	default:
+		return ErrInvalidAttestation.Wrapf("unknown attestation type: %s", msg.AttestationType)
+	}
+
 	return nil
 }
 
@@ -120,6 +167,52 @@ func (msg MsgSubmitAttestation) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{submitter}
 }
 
+// MsgRequestAttestationChallenge asks for a one-time nonce that the device's
+// next periodic or challenge attestation must cover
+type MsgRequestAttestationChallenge struct {
+	// Owner is the device owner requesting the challenge
+	Owner string `json:"owner" protobuf:"bytes,1,opt,name=owner,proto3"`
+
+	// DeviceID is the device the nonce is bound to
+	DeviceID string `json:"device_id" protobuf:"bytes,2,opt,name=device_id,proto3"`
+}
+
+// Proto interface implementations for MsgRequestAttestationChallenge
+func (msg *MsgRequestAttestationChallenge) Reset()         { *msg = MsgRequestAttestationChallenge{} }
+func (msg *MsgRequestAttestationChallenge) String() string { return msg.Owner }
+func (msg *MsgRequestAttestationChallenge) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgRequestAttestationChallenge) XXX_MessageName() string {
+	return "cert.hardware.v1.MsgRequestAttestationChallenge"
+}
+
+// Route implements sdk.Msg
+func (msg MsgRequestAttestationChallenge) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgRequestAttestationChallenge) Type() string { return TypeMsgRequestAttestationChallenge }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgRequestAttestationChallenge) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Owner)
+	if err != nil {
+		return ErrInvalidAddress.Wrap("invalid owner address")
+	}
+
+	if msg.DeviceID == "" {
+		return ErrInvalidDevice.Wrap("device ID cannot be empty")
+	}
+
+	return nil
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgRequestAttestationChallenge) GetSigners() []sdk.AccAddress {
+	owner, _ := sdk.AccAddressFromBech32(msg.Owner)
+	return []sdk.AccAddress{owner}
+}
+
 // MsgLinkDeviceToCertID links a verified device to a CertID profile
 type MsgLinkDeviceToCertID struct {
 	// Owner is the CertID profile owner
@@ -204,3 +297,386 @@ func (msg MsgSuspendDevice) GetSigners() []sdk.AccAddress {
 	authority, _ := sdk.AccAddressFromBech32(msg.Authority)
 	return []sdk.AccAddress{authority}
 }
+
+// MsgTransferDevice transfers a device to a new owner
+type MsgTransferDevice struct {
+	// Owner is the current device owner
+	Owner string `json:"owner" protobuf:"bytes,1,opt,name=owner,proto3"`
+
+	// DeviceID is the device to transfer
+	DeviceID string `json:"device_id" protobuf:"bytes,2,opt,name=device_id,proto3"`
+
+	// NewOwner is the address receiving the device
+	NewOwner string `json:"new_owner" protobuf:"bytes,3,opt,name=new_owner,json=newOwner,proto3"`
+
+	// Attestation is a fresh TEE attestation proving the device is present (optional)
+	Attestation []byte `json:"attestation,omitempty" protobuf:"bytes,4,opt,name=attestation,proto3"`
+}
+
+// Proto interface implementations for MsgTransferDevice
+func (msg *MsgTransferDevice) Reset()         { *msg = MsgTransferDevice{} }
+func (msg *MsgTransferDevice) String() string { return msg.DeviceID }
+func (msg *MsgTransferDevice) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgTransferDevice) XXX_MessageName() string { return "cert.hardware.v1.MsgTransferDevice" }
+
+// Route implements sdk.Msg
+func (msg MsgTransferDevice) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgTransferDevice) Type() string { return TypeMsgTransferDevice }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgTransferDevice) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Owner)
+	if err != nil {
+		return ErrInvalidAddress.Wrap("invalid owner address")
+	}
+
+	if msg.DeviceID == "" {
+		return ErrInvalidDevice.Wrap("device ID cannot be empty")
+	}
+
+	_, err = sdk.AccAddressFromBech32(msg.NewOwner)
+	if err != nil {
+		return ErrInvalidAddress.Wrap("invalid new owner address")
+	}
+
+	if msg.NewOwner == msg.Owner {
+		return ErrInvalidTransfer.Wrap("new owner must differ from current owner")
+	}
+
+	return nil
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgTransferDevice) GetSigners() []sdk.AccAddress {
+	owner, _ := sdk.AccAddressFromBech32(msg.Owner)
+	return []sdk.AccAddress{owner}
+}
+
+// MsgSetFirmwareRelease registers a signed firmware release in the firmware registry
+type MsgSetFirmwareRelease struct {
+	// Authority is the module authority (governance)
+	Authority string `json:"authority" protobuf:"bytes,1,opt,name=authority,proto3"`
+
+	// Release is the firmware release to register
+	Release FirmwareRelease `json:"release" protobuf:"bytes,2,opt,name=release,proto3"`
+}
+
+// Proto interface implementations for MsgSetFirmwareRelease
+func (msg *MsgSetFirmwareRelease) Reset()         { *msg = MsgSetFirmwareRelease{} }
+func (msg *MsgSetFirmwareRelease) String() string { return msg.Release.Manufacturer }
+func (msg *MsgSetFirmwareRelease) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgSetFirmwareRelease) XXX_MessageName() string {
+	return "cert.hardware.v1.MsgSetFirmwareRelease"
+}
+
+// Route implements sdk.Msg
+func (msg MsgSetFirmwareRelease) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgSetFirmwareRelease) Type() string { return TypeMsgSetFirmwareRelease }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgSetFirmwareRelease) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Authority)
+	if err != nil {
+		return ErrInvalidAddress.Wrap("invalid authority address")
+	}
+
+	return msg.Release.Validate()
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgSetFirmwareRelease) GetSigners() []sdk.AccAddress {
+	authority, _ := sdk.AccAddressFromBech32(msg.Authority)
+	return []sdk.AccAddress{authority}
+}
+
+// MsgReportFirmware reports the firmware a device is running, attested by its TEE
+type MsgReportFirmware struct {
+	// Submitter is the device owner
+	Submitter string `json:"submitter" protobuf:"bytes,1,opt,name=submitter,proto3"`
+
+	// DeviceID identifies the reporting device
+	DeviceID string `json:"device_id" protobuf:"bytes,2,opt,name=device_id,proto3"`
+
+	// Version is the firmware version the device is running
+	Version uint64 `json:"version" protobuf:"varint,3,opt,name=version,proto3"`
+
+	// Hash is the SHA-256 hash of the running firmware image
+	Hash []byte `json:"hash" protobuf:"bytes,4,opt,name=hash,proto3"`
+
+	// Attestation is the TEE attestation covering the firmware measurement
+	Attestation []byte `json:"attestation" protobuf:"bytes,5,opt,name=attestation,proto3"`
+}
+
+// Proto interface implementations for MsgReportFirmware
+func (msg *MsgReportFirmware) Reset()         { *msg = MsgReportFirmware{} }
+func (msg *MsgReportFirmware) String() string { return msg.DeviceID }
+func (msg *MsgReportFirmware) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgReportFirmware) XXX_MessageName() string { return "cert.hardware.v1.MsgReportFirmware" }
+
+// Route implements sdk.Msg
+func (msg MsgReportFirmware) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgReportFirmware) Type() string { return TypeMsgReportFirmware }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgReportFirmware) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Submitter)
+	if err != nil {
+		return ErrInvalidAddress.Wrap("invalid submitter address")
+	}
+
+	if msg.DeviceID == "" {
+		return ErrInvalidDevice.Wrap("device ID cannot be empty")
+	}
+
+	if msg.Version == 0 {
+		return ErrInvalidFirmware.Wrap("firmware version must be positive")
+	}
+
+	if len(msg.Hash) != FirmwareHashSize {
+		return ErrInvalidFirmware.Wrapf("firmware hash must be %d bytes", FirmwareHashSize)
+	}
+
+	if len(msg.Attestation) == 0 {
+		return ErrInvalidAttestation.Wrap("firmware attestation required")
+	}
+
+	return nil
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgReportFirmware) GetSigners() []sdk.AccAddress {
+	submitter, _ := sdk.AccAddressFromBech32(msg.Submitter)
+	return []sdk.AccAddress{submitter}
+}
+
+// MsgSetManufacturer adds or updates a trusted manufacturer in the manufacturer registry
+type MsgSetManufacturer struct {
+	// Authority is the module authority (governance)
+	Authority string `json:"authority" protobuf:"bytes,1,opt,name=authority,proto3"`
+
+	// Manufacturer is the registry entry to store
+	Manufacturer Manufacturer `json:"manufacturer" protobuf:"bytes,2,opt,name=manufacturer,proto3"`
+}
+
+// Proto interface implementations for MsgSetManufacturer
+func (msg *MsgSetManufacturer) Reset()         { *msg = MsgSetManufacturer{} }
+func (msg *MsgSetManufacturer) String() string { return msg.Manufacturer.Name }
+func (msg *MsgSetManufacturer) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgSetManufacturer) XXX_MessageName() string { return "cert.hardware.v1.MsgSetManufacturer" }
+
+// Route implements sdk.Msg
+func (msg MsgSetManufacturer) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgSetManufacturer) Type() string { return TypeMsgSetManufacturer }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgSetManufacturer) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Authority)
+	if err != nil {
+		return ErrInvalidAddress.Wrap("invalid authority address")
+	}
+
+	return msg.Manufacturer.Validate()
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgSetManufacturer) GetSigners() []sdk.AccAddress {
+	authority, _ := sdk.AccAddressFromBech32(msg.Authority)
+	return []sdk.AccAddress{authority}
+}
+
+// MsgRemoveManufacturer removes a manufacturer from the manufacturer registry
+type MsgRemoveManufacturer struct {
+	// Authority is the module authority (governance)
+	Authority string `json:"authority" protobuf:"bytes,1,opt,name=authority,proto3"`
+
+	// Name is the manufacturer to remove
+	Name string `json:"name" protobuf:"bytes,2,opt,name=name,proto3"`
+}
+
+// Proto interface implementations for MsgRemoveManufacturer
+func (msg *MsgRemoveManufacturer) Reset()         { *msg = MsgRemoveManufacturer{} }
+func (msg *MsgRemoveManufacturer) String() string { return msg.Name }
+func (msg *MsgRemoveManufacturer) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgRemoveManufacturer) XXX_MessageName() string {
+	return "cert.hardware.v1.MsgRemoveManufacturer"
+}
+
+// Route implements sdk.Msg
+func (msg MsgRemoveManufacturer) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgRemoveManufacturer) Type() string { return TypeMsgRemoveManufacturer }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgRemoveManufacturer) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Authority)
+	if err != nil {
+		return ErrInvalidAddress.Wrap("invalid authority address")
+	}
+
+	if msg.Name == "" {
+		return ErrUnknownManufacturer.Wrap("manufacturer name cannot be empty")
+	}
+
+	return nil
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgRemoveManufacturer) GetSigners() []sdk.AccAddress {
+	authority, _ := sdk.AccAddressFromBech32(msg.Authority)
+	return []sdk.AccAddress{authority}
+}
+
+// MsgSetManufacturerStrictMode toggles strict manufacturer verification for device registration
+type MsgSetManufacturerStrictMode struct {
+	// Authority is the module authority (governance)
+	Authority string `json:"authority" protobuf:"bytes,1,opt,name=authority,proto3"`
+
+	// Strict rejects devices from unregistered manufacturers or without a valid manufacturer signature
+	Strict bool `json:"strict" protobuf:"varint,2,opt,name=strict,proto3"`
+}
+
+// Proto interface implementations for MsgSetManufacturerStrictMode
+func (msg *MsgSetManufacturerStrictMode) Reset()         { *msg = MsgSetManufacturerStrictMode{} }
+func (msg *MsgSetManufacturerStrictMode) String() string { return msg.Authority }
+func (msg *MsgSetManufacturerStrictMode) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgSetManufacturerStrictMode) XXX_MessageName() string {
+	return "cert.hardware.v1.MsgSetManufacturerStrictMode"
+}
+
+// Route implements sdk.Msg
+func (msg MsgSetManufacturerStrictMode) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgSetManufacturerStrictMode) Type() string { return TypeMsgSetManufacturerStrictMode }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgSetManufacturerStrictMode) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Authority)
+	if err != nil {
+		return ErrInvalidAddress.Wrap("invalid authority address")
+	}
+
+	return nil
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgSetManufacturerStrictMode) GetSigners() []sdk.AccAddress {
+	authority, _ := sdk.AccAddressFromBech32(msg.Authority)
+	return []sdk.AccAddress{authority}
+}
+
+// MsgAppealSuspension lets an owner clear a congruence audit suspension by
+// proving the device is still genuine hardware with a fresh attestation
+type MsgAppealSuspension struct {
+	// Owner is the device owner appealing the suspension
+	Owner string `json:"owner" protobuf:"bytes,1,opt,name=owner,proto3"`
+
+	// DeviceID is the suspended device
+	DeviceID string `json:"device_id" protobuf:"bytes,2,opt,name=device_id,proto3"`
+
+	// AttestationData is the TEE attestation covering Nonce
+	AttestationData []byte `json:"attestation_data" protobuf:"bytes,3,opt,name=attestation_data,json=attestationData,proto3"`
+
+	// Nonce is the challenge nonce from MsgRequestAttestationChallenge
+	Nonce []byte `json:"nonce" protobuf:"bytes,4,opt,name=nonce,proto3"`
+}
+
+// Proto interface implementations for MsgAppealSuspension
+func (msg *MsgAppealSuspension) Reset()         { *msg = MsgAppealSuspension{} }
+func (msg *MsgAppealSuspension) String() string { return msg.DeviceID }
+func (msg *MsgAppealSuspension) ProtoMessage()  {}
+
+// XXX_MessageName returns the fully qualified protobuf message name for TypeURL registration
+func (*MsgAppealSuspension) XXX_MessageName() string {
+	return "cert.hardware.v1.MsgAppealSuspension"
+}
+
+// Route implements sdk.Msg
+func (msg MsgAppealSuspension) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgAppealSuspension) Type() string { return TypeMsgAppealSuspension }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgAppealSuspension) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Owner)
+	if err != nil {
+		return ErrInvalidAddress.Wrap("invalid owner address")
+	}
+
+	if msg.DeviceID == "" {
+		return ErrInvalidDevice.Wrap("device ID cannot be empty")
+	}
+
+	if len(msg.AttestationData) == 0 {
+		return ErrInvalidAttestation.Wrap("attestation data cannot be empty")
+	}
+
+	if len(msg.Nonce) != ChallengeNonceSize {
+		return ErrChallengeMismatch.Wrapf("appeals must answer a %d-byte challenge nonce", ChallengeNonceSize)
+	}
+
+	return nil
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgAppealSuspension) GetSigners() []sdk.AccAddress {
+	owner, _ := sdk.AccAddressFromBech32(msg.Owner)
+	return []sdk.AccAddress{owner}
+}
+
+// MsgReactivateDevice lifts a device suspension
+type MsgReactivateDevice struct {
+	// Authority is the authorized oracle/admin
+	Authority string `json:"authority"`
+
+	// DeviceID is the device to reactivate
+	DeviceID string `json:"device_id"`
+}
+
+// Route implements sdk.Msg
+func (msg MsgReactivateDevice) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgReactivateDevice) Type() string { return TypeMsgReactivateDevice }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgReactivateDevice) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Authority)
+	if err != nil {
+		return ErrInvalidAddress.Wrap("invalid authority address")
+	}
+
+	if msg.DeviceID == "" {
+		return ErrInvalidDevice.Wrap("device ID cannot be empty")
+	}
+
+	return nil
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgReactivateDevice) GetSigners() []sdk.AccAddress {
+	authority, _ := sdk.AccAddressFromBech32(msg.Authority)
+	return []sdk.AccAddress{authority}
+}
@@ -0,0 +1,271 @@
+package types
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// QueryServer is the server API for the hardware Query service
+type QueryServer interface {
+	// DevicesByOwner returns a page of the devices owned by an address
+	DevicesByOwner(context.Context, *QueryDevicesByOwnerRequest) (*QueryDevicesByOwnerResponse, error)
+
+	// Stats returns the network-wide hardware stats
+	Stats(context.Context, *QueryStatsRequest) (*QueryStatsResponse, error)
+
+	// Device returns a registered device by ID
+	Device(context.Context, *QueryDeviceRequest) (*QueryDeviceResponse, error)
+
+	// HumanityScore returns an address's humanity score
+	HumanityScore(context.Context, *QueryHumanityScoreRequest) (*QueryHumanityScoreResponse, error)
+}
+
+// QueryClient is the client API for the hardware Query service
+type QueryClient interface {
+	DevicesByOwner(ctx context.Context, in *QueryDevicesByOwnerRequest, opts ...grpc.CallOption) (*QueryDevicesByOwnerResponse, error)
+	Stats(ctx context.Context, in *QueryStatsRequest, opts ...grpc.CallOption) (*QueryStatsResponse, error)
+	Device(ctx context.Context, in *QueryDeviceRequest, opts ...grpc.CallOption) (*QueryDeviceResponse, error)
+	HumanityScore(ctx context.Context, in *QueryHumanityScoreRequest, opts ...grpc.CallOption) (*QueryHumanityScoreResponse, error)
+}
+
+// QueryDevicesByOwnerRequest is the request type for Query/DevicesByOwner
+type QueryDevicesByOwnerRequest struct {
+	Owner      string             `json:"owner" protobuf:"bytes,1,opt,name=owner,proto3"`
+	Pagination *query.PageRequest `json:"pagination,omitempty" protobuf:"bytes,2,opt,name=pagination,proto3"`
+}
+
+func (m *QueryDevicesByOwnerRequest) Reset()         { *m = QueryDevicesByOwnerRequest{} }
+func (m *QueryDevicesByOwnerRequest) String() string { return m.Owner }
+func (m *QueryDevicesByOwnerRequest) ProtoMessage()  {}
+
+// QueryDevicesByOwnerResponse is the response type for Query/DevicesByOwner
+type QueryDevicesByOwnerResponse struct {
+	Devices    []Device            `json:"devices" protobuf:"bytes,1,rep,name=devices,proto3"`
+	Pagination *query.PageResponse `json:"pagination,omitempty" protobuf:"bytes,2,opt,name=pagination,proto3"`
+}
+
+func (m *QueryDevicesByOwnerResponse) Reset()         { *m = QueryDevicesByOwnerResponse{} }
+func (m *QueryDevicesByOwnerResponse) String() string { return "QueryDevicesByOwnerResponse" }
+func (m *QueryDevicesByOwnerResponse) ProtoMessage()  {}
+
+// QueryStatsRequest is the request type for Query/Stats
+type QueryStatsRequest struct{}
+
+func (m *QueryStatsRequest) Reset()         { *m = QueryStatsRequest{} }
+func (m *QueryStatsRequest) String() string { return "QueryStatsRequest" }
+func (m *QueryStatsRequest) ProtoMessage()  {}
+
+// QueryStatsResponse is the response type for Query/Stats
+type QueryStatsResponse struct {
+	Stats             HardwareStats `json:"stats" protobuf:"bytes,1,opt,name=stats,proto3"`
+	AverageTrustScore float64       `json:"average_trust_score" protobuf:"fixed64,2,opt,name=average_trust_score,proto3"`
+}
+
+func (m *QueryStatsResponse) Reset()         { *m = QueryStatsResponse{} }
+func (m *QueryStatsResponse) String() string { return "QueryStatsResponse" }
+func (m *QueryStatsResponse) ProtoMessage()  {}
+
+// QueryDeviceRequest is the request type for Query/Device
+type QueryDeviceRequest struct {
+	DeviceID string `json:"device_id" protobuf:"bytes,1,opt,name=device_id,proto3"`
+}
+
+func (m *QueryDeviceRequest) Reset()         { *m = QueryDeviceRequest{} }
+func (m *QueryDeviceRequest) String() string { return m.DeviceID }
+func (m *QueryDeviceRequest) ProtoMessage()  {}
+
+// QueryDeviceResponse is the response type for Query/Device
+type QueryDeviceResponse struct {
+	Device Device `json:"device" protobuf:"bytes,1,opt,name=device,proto3"`
+}
+
+func (m *QueryDeviceResponse) Reset()         { *m = QueryDeviceResponse{} }
+func (m *QueryDeviceResponse) String() string { return "QueryDeviceResponse" }
+func (m *QueryDeviceResponse) ProtoMessage()  {}
+
+// QueryHumanityScoreRequest is the request type for Query/HumanityScore
+type QueryHumanityScoreRequest struct {
+	Address string `json:"address" protobuf:"bytes,1,opt,name=address,proto3"`
+}
+
+func (m *QueryHumanityScoreRequest) Reset()         { *m = QueryHumanityScoreRequest{} }
+func (m *QueryHumanityScoreRequest) String() string { return m.Address }
+func (m *QueryHumanityScoreRequest) ProtoMessage()  {}
+
+// QueryHumanityScoreResponse is the response type for Query/HumanityScore
+type QueryHumanityScoreResponse struct {
+	Score         HumanityScore `json:"score" protobuf:"bytes,1,opt,name=score,proto3"`
+	VerifiedHuman bool          `json:"verified_human" protobuf:"varint,2,opt,name=verified_human,proto3"`
+}
+
+func (m *QueryHumanityScoreResponse) Reset()         { *m = QueryHumanityScoreResponse{} }
+func (m *QueryHumanityScoreResponse) String() string { return "QueryHumanityScoreResponse" }
+func (m *QueryHumanityScoreResponse) ProtoMessage()  {}
+
+func init() {
+	// Register the manually-defined query types with gogoproto for gRPC marshaling
+	proto.RegisterType((*QueryDevicesByOwnerRequest)(nil), "cert.hardware.v1.QueryDevicesByOwnerRequest")
+	proto.RegisterType((*QueryDevicesByOwnerResponse)(nil), "cert.hardware.v1.QueryDevicesByOwnerResponse")
+	proto.RegisterType((*QueryStatsRequest)(nil), "cert.hardware.v1.QueryStatsRequest")
+	proto.RegisterType((*QueryStatsResponse)(nil), "cert.hardware.v1.QueryStatsResponse")
+	proto.RegisterType((*QueryDeviceRequest)(nil), "cert.hardware.v1.QueryDeviceRequest")
+	proto.RegisterType((*QueryDeviceResponse)(nil), "cert.hardware.v1.QueryDeviceResponse")
+	proto.RegisterType((*QueryHumanityScoreRequest)(nil), "cert.hardware.v1.QueryHumanityScoreRequest")
+	proto.RegisterType((*QueryHumanityScoreResponse)(nil), "cert.hardware.v1.QueryHumanityScoreResponse")
+}
+
+type queryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewQueryClient creates a new Query client
+func NewQueryClient(cc client.Context) QueryClient {
+	return &queryClient{cc}
+}
+
+// DevicesByOwner queries the devices owned by an address
+func (c *queryClient) DevicesByOwner(ctx context.Context, in *QueryDevicesByOwnerRequest, opts ...grpc.CallOption) (*QueryDevicesByOwnerResponse, error) {
+	out := new(QueryDevicesByOwnerResponse)
+	err := c.cc.Invoke(ctx, "/cert.hardware.v1.Query/DevicesByOwner", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Stats queries the network-wide hardware stats
+func (c *queryClient) Stats(ctx context.Context, in *QueryStatsRequest, opts ...grpc.CallOption) (*QueryStatsResponse, error) {
+	out := new(QueryStatsResponse)
+	err := c.cc.Invoke(ctx, "/cert.hardware.v1.Query/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Device queries a registered device by ID
+func (c *queryClient) Device(ctx context.Context, in *QueryDeviceRequest, opts ...grpc.CallOption) (*QueryDeviceResponse, error) {
+	out := new(QueryDeviceResponse)
+	err := c.cc.Invoke(ctx, "/cert.hardware.v1.Query/Device", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HumanityScore queries an address's humanity score
+func (c *queryClient) HumanityScore(ctx context.Context, in *QueryHumanityScoreRequest, opts ...grpc.CallOption) (*QueryHumanityScoreResponse, error) {
+	out := new(QueryHumanityScoreResponse)
+	err := c.cc.Invoke(ctx, "/cert.hardware.v1.Query/HumanityScore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RegisterQueryServer registers the QueryServer implementation with the gRPC server
+func RegisterQueryServer(s grpc.ServiceRegistrar, srv QueryServer) {
+	s.RegisterService(&_Query_serviceDesc, srv)
+}
+
+var _Query_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cert.hardware.v1.Query",
+	HandlerType: (*QueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DevicesByOwner",
+			Handler:    _Query_DevicesByOwner_Handler,
+		},
+		{
+			MethodName: "Stats",
+			Handler:    _Query_Stats_Handler,
+		},
+		{
+			MethodName: "Device",
+			Handler:    _Query_Device_Handler,
+		},
+		{
+			MethodName: "HumanityScore",
+			Handler:    _Query_HumanityScore_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "cert/hardware/v1/query.proto",
+}
+
+func _Query_DevicesByOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryDevicesByOwnerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).DevicesByOwner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Query/DevicesByOwner",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).DevicesByOwner(ctx, req.(*QueryDevicesByOwnerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Query/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).Stats(ctx, req.(*QueryStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_Device_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).Device(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Query/Device",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).Device(ctx, req.(*QueryDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_HumanityScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryHumanityScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).HumanityScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Query/HumanityScore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).HumanityScore(ctx, req.(*QueryHumanityScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
@@ -15,10 +15,22 @@ type TrustScoreConfig struct {
 	OnChainHistoryWeight  uint64 // 20% - Account age + tx history
 	NetworkFeesPaidWeight uint64 // 10% - Burned $CERT/ETH fees
 
+	// On-chain device evidence, added on top of the weights above (score capped at 100)
+	GeoDispersionWeight uint64 // 10 - Devices spread across distinct regions
+	UsagePatternWeight  uint64 // 10 - Regular attestation cadence
+
 	// Thresholds
 	VerifiedHumanityThreshold uint64 // 60 - Minimum for "Verified Human"
 	HighTrustDeviceThreshold  uint64 // 80 - Device qualifies for Hardware Anchor
 	DataCongruenceAuditDays   uint64 // 3 - Days of <50% before audit flag
+
+	// HumanityScoreEventDelta is the smallest humanity score change that emits an event
+	// (crossing VerifiedHumanityThreshold always does)
+	HumanityScoreEventDelta uint64 // 10
+
+	// TransferPreservesTrust keeps a device's trust history across ownership
+	// transfers; when false the new owner starts from a zero trust score
+	TransferPreservesTrust bool // false
 }
 
 // DefaultTrustScoreConfig returns the standard scoring weights
@@ -35,16 +47,19 @@ func DefaultTrustScoreConfig() TrustScoreConfig {
 		SocialStakingWeight:   30,
 		OnChainHistoryWeight:  20,
 		NetworkFeesPaidWeight: 10,
+		GeoDispersionWeight:   10,
+		UsagePatternWeight:    10,
 
 		// Thresholds
 		VerifiedHumanityThreshold: 60,
 		HighTrustDeviceThreshold:  80,
 		DataCongruenceAuditDays:   3,
+		HumanityScoreEventDelta:   10,
 	}
 }
 
-// LatestFirmwareVersion is the current expected firmware version
-// This should be updated via governance when new firmware is released
+// LatestFirmwareVersion is the expected firmware version for devices whose
+// manufacturer/model has no entry in the firmware registry
 const LatestFirmwareVersion = 1
 
 // DeviceTrustFactors contains the inputs for device trust calculation
@@ -61,6 +76,12 @@ type DeviceTrustFactors struct {
 	// FirmwareVersion: current firmware version number
 	FirmwareVersion int
 
+	// LatestFirmwareVersion: latest registered version for the device model (0 = LatestFirmwareVersion)
+	LatestFirmwareVersion int
+
+	// FirmwareUnverified: firmware is unknown to the registry or its hash does not match (no firmware points)
+	FirmwareUnverified bool
+
 	// ConsecutiveLowCongruenceDays: days with <50% data congruence
 	ConsecutiveLowCongruenceDays int
 }
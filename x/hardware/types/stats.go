@@ -0,0 +1,83 @@
+package types
+
+// HardwareStats holds the network-wide device counters maintained by the keeper
+type HardwareStats struct {
+	// TotalDevices is the number of registered devices
+	TotalDevices uint64 `json:"total_devices"`
+
+	// ActiveDevices counts devices that are active and not suspended
+	ActiveDevices uint64 `json:"active_devices"`
+
+	// SuspendedDevices counts devices flagged for suspicious activity
+	SuspendedDevices uint64 `json:"suspended_devices"`
+
+	// ByTEEType counts devices per TEE type
+	ByTEEType map[string]uint64 `json:"by_tee_type"`
+
+	// ByManufacturer counts devices per manufacturer
+	ByManufacturer map[string]uint64 `json:"by_manufacturer"`
+
+	// TrustScoreSum is the sum of all device trust scores (for the average)
+	TrustScoreSum uint64 `json:"trust_score_sum"`
+
+	// VerifiedHumans counts addresses whose humanity score meets the verified-human threshold
+	VerifiedHumans uint64 `json:"verified_humans"`
+}
+
+// NewHardwareStats returns empty hardware stats
+func NewHardwareStats() HardwareStats {
+	return HardwareStats{
+		ByTEEType:      map[string]uint64{},
+		ByManufacturer: map[string]uint64{},
+	}
+}
+
+// AddDevice counts a device in the stats
+func (s *HardwareStats) AddDevice(d Device) {
+	s.TotalDevices++
+	if d.IsSuspended {
+		s.SuspendedDevices++
+	} else if d.IsActive {
+		s.ActiveDevices++
+	}
+	s.ByTEEType[string(d.TEEType)]++
+	s.ByManufacturer[d.Manufacturer]++
+	s.TrustScoreSum += d.TrustScore
+}
+
+// RemoveDevice uncounts a device previously added with AddDevice
+func (s *HardwareStats) RemoveDevice(d Device) {
+	s.TotalDevices = decrement(s.TotalDevices, 1)
+	if d.IsSuspended {
+		s.SuspendedDevices = decrement(s.SuspendedDevices, 1)
+	} else if d.IsActive {
+		s.ActiveDevices = decrement(s.ActiveDevices, 1)
+	}
+	decrementKey(s.ByTEEType, string(d.TEEType))
+	decrementKey(s.ByManufacturer, d.Manufacturer)
+	s.TrustScoreSum = decrement(s.TrustScoreSum, d.TrustScore)
+}
+
+// AverageTrustScore returns the mean device trust score (0 with no devices)
+func (s HardwareStats) AverageTrustScore() float64 {
+	if s.TotalDevices == 0 {
+		return 0
+	}
+	return float64(s.TrustScoreSum) / float64(s.TotalDevices)
+}
+
+func decrement(v, by uint64) uint64 {
+	if by > v {
+		return 0
+	}
+	return v - by
+}
+
+// decrementKey lowers a breakdown count, dropping the key at zero
+func decrementKey(m map[string]uint64, key string) {
+	if m[key] <= 1 {
+		delete(m, key)
+		return
+	}
+	m[key]--
+}
@@ -0,0 +1,352 @@
+package types
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+)
+
+// MsgServer is the server API for the hardware Msg service
+type MsgServer interface {
+	// RegisterDevice registers a TEE-backed hardware device
+	RegisterDevice(context.Context, *MsgRegisterDevice) (*MsgRegisterDeviceResponse, error)
+	// RequestAttestationChallenge issues a one-time nonce for a device's next attestation
+	RequestAttestationChallenge(context.Context, *MsgRequestAttestationChallenge) (*MsgRequestAttestationChallengeResponse, error)
+	// SubmitAttestation verifies a device attestation, consuming its challenge nonce
+	SubmitAttestation(context.Context, *MsgSubmitAttestation) (*MsgSubmitAttestationResponse, error)
+	// AppealSuspension clears a congruence audit suspension given a fresh attestation
+	AppealSuspension(context.Context, *MsgAppealSuspension) (*MsgAppealSuspensionResponse, error)
+	// TransferDevice moves a device to a new owner
+	TransferDevice(context.Context, *MsgTransferDevice) (*MsgTransferDeviceResponse, error)
+	// SetFirmwareRelease registers a signed firmware release (module authority only)
+	SetFirmwareRelease(context.Context, *MsgSetFirmwareRelease) (*MsgSetFirmwareReleaseResponse, error)
+	// ReportFirmware records the attested firmware a device is running
+	ReportFirmware(context.Context, *MsgReportFirmware) (*MsgReportFirmwareResponse, error)
+	// SetManufacturer adds or updates a trusted manufacturer (module authority only)
+	SetManufacturer(context.Context, *MsgSetManufacturer) (*MsgSetManufacturerResponse, error)
+	// RemoveManufacturer removes a trusted manufacturer (module authority only)
+	RemoveManufacturer(context.Context, *MsgRemoveManufacturer) (*MsgRemoveManufacturerResponse, error)
+	// SetManufacturerStrictMode toggles strict manufacturer verification (module authority only)
+	SetManufacturerStrictMode(context.Context, *MsgSetManufacturerStrictMode) (*MsgSetManufacturerStrictModeResponse, error)
+}
+
+// MsgRegisterDeviceResponse is the response for MsgRegisterDevice
+type MsgRegisterDeviceResponse struct {
+	DeviceID string `json:"device_id" protobuf:"bytes,1,opt,name=device_id,proto3"`
+}
+
+func (m *MsgRegisterDeviceResponse) Reset()         { *m = MsgRegisterDeviceResponse{} }
+func (m *MsgRegisterDeviceResponse) String() string { return m.DeviceID }
+func (m *MsgRegisterDeviceResponse) ProtoMessage()  {}
+
+// MsgRequestAttestationChallengeResponse is the response for MsgRequestAttestationChallenge
+type MsgRequestAttestationChallengeResponse struct {
+	Nonce     []byte `json:"nonce" protobuf:"bytes,1,opt,name=nonce,proto3"`
+	ExpiresAt int64  `json:"expires_at" protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3"`
+}
+
+func (m *MsgRequestAttestationChallengeResponse) Reset() {
+	*m = MsgRequestAttestationChallengeResponse{}
+}
+func (m *MsgRequestAttestationChallengeResponse) String() string { return fmt.Sprintf("%x", m.Nonce) }
+func (m *MsgRequestAttestationChallengeResponse) ProtoMessage()  {}
+
+// MsgSubmitAttestationResponse is the response for MsgSubmitAttestation
+type MsgSubmitAttestationResponse struct {
+	AttestationCount uint64 `json:"attestation_count" protobuf:"varint,1,opt,name=attestation_count,json=attestationCount,proto3"`
+}
+
+func (m *MsgSubmitAttestationResponse) Reset()         { *m = MsgSubmitAttestationResponse{} }
+func (m *MsgSubmitAttestationResponse) String() string { return fmt.Sprintf("%d", m.AttestationCount) }
+func (m *MsgSubmitAttestationResponse) ProtoMessage()  {}
+
+// MsgAppealSuspensionResponse is the response for MsgAppealSuspension
+type MsgAppealSuspensionResponse struct{}
+
+func (m *MsgAppealSuspensionResponse) Reset()         { *m = MsgAppealSuspensionResponse{} }
+func (m *MsgAppealSuspensionResponse) String() string { return "MsgAppealSuspensionResponse" }
+func (m *MsgAppealSuspensionResponse) ProtoMessage()  {}
+
+// MsgTransferDeviceResponse is the response for MsgTransferDevice
+type MsgTransferDeviceResponse struct {
+	TrustScore uint64 `json:"trust_score" protobuf:"varint,1,opt,name=trust_score,json=trustScore,proto3"`
+}
+
+func (m *MsgTransferDeviceResponse) Reset()         { *m = MsgTransferDeviceResponse{} }
+func (m *MsgTransferDeviceResponse) String() string { return fmt.Sprintf("%d", m.TrustScore) }
+func (m *MsgTransferDeviceResponse) ProtoMessage()  {}
+
+// MsgSetFirmwareReleaseResponse is the response for MsgSetFirmwareRelease
+type MsgSetFirmwareReleaseResponse struct{}
+
+func (m *MsgSetFirmwareReleaseResponse) Reset()         { *m = MsgSetFirmwareReleaseResponse{} }
+func (m *MsgSetFirmwareReleaseResponse) String() string { return "MsgSetFirmwareReleaseResponse" }
+func (m *MsgSetFirmwareReleaseResponse) ProtoMessage()  {}
+
+// MsgReportFirmwareResponse is the response for MsgReportFirmware
+type MsgReportFirmwareResponse struct {
+	FirmwareVerified bool   `json:"firmware_verified" protobuf:"varint,1,opt,name=firmware_verified,json=firmwareVerified,proto3"`
+	TrustScore       uint64 `json:"trust_score" protobuf:"varint,2,opt,name=trust_score,json=trustScore,proto3"`
+}
+
+func (m *MsgReportFirmwareResponse) Reset()         { *m = MsgReportFirmwareResponse{} }
+func (m *MsgReportFirmwareResponse) String() string { return fmt.Sprintf("%d", m.TrustScore) }
+func (m *MsgReportFirmwareResponse) ProtoMessage()  {}
+
+// MsgSetManufacturerResponse is the response for MsgSetManufacturer
+type MsgSetManufacturerResponse struct{}
+
+func (m *MsgSetManufacturerResponse) Reset()         { *m = MsgSetManufacturerResponse{} }
+func (m *MsgSetManufacturerResponse) String() string { return "MsgSetManufacturerResponse" }
+func (m *MsgSetManufacturerResponse) ProtoMessage()  {}
+
+// MsgRemoveManufacturerResponse is the response for MsgRemoveManufacturer
+type MsgRemoveManufacturerResponse struct{}
+
+func (m *MsgRemoveManufacturerResponse) Reset()         { *m = MsgRemoveManufacturerResponse{} }
+func (m *MsgRemoveManufacturerResponse) String() string { return "MsgRemoveManufacturerResponse" }
+func (m *MsgRemoveManufacturerResponse) ProtoMessage()  {}
+
+// MsgSetManufacturerStrictModeResponse is the response for MsgSetManufacturerStrictMode
+type MsgSetManufacturerStrictModeResponse struct{}
+
+func (m *MsgSetManufacturerStrictModeResponse) Reset() { *m = MsgSetManufacturerStrictModeResponse{} }
+func (m *MsgSetManufacturerStrictModeResponse) String() string {
+	return "MsgSetManufacturerStrictModeResponse"
+}
+func (m *MsgSetManufacturerStrictModeResponse) ProtoMessage() {}
+
+// RegisterMsgServer registers the MsgServer implementation with the gRPC server
+func RegisterMsgServer(s grpc.ServiceRegistrar, srv MsgServer) {
+	s.RegisterService(&_Msg_serviceDesc, srv)
+}
+
+var _Msg_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cert.hardware.v1.Msg",
+	HandlerType: (*MsgServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterDevice",
+			Handler:    _Msg_RegisterDevice_Handler,
+		},
+		{
+			MethodName: "RequestAttestationChallenge",
+			Handler:    _Msg_RequestAttestationChallenge_Handler,
+		},
+		{
+			MethodName: "SubmitAttestation",
+			Handler:    _Msg_SubmitAttestation_Handler,
+		},
+		{
+			MethodName: "AppealSuspension",
+			Handler:    _Msg_AppealSuspension_Handler,
+		},
+		{
+			MethodName: "TransferDevice",
+			Handler:    _Msg_TransferDevice_Handler,
+		},
+		{
+			MethodName: "SetFirmwareRelease",
+			Handler:    _Msg_SetFirmwareRelease_Handler,
+		},
+		{
+			MethodName: "ReportFirmware",
+			Handler:    _Msg_ReportFirmware_Handler,
+		},
+		{
+			MethodName: "SetManufacturer",
+			Handler:    _Msg_SetManufacturer_Handler,
+		},
+		{
+			MethodName: "RemoveManufacturer",
+			Handler:    _Msg_RemoveManufacturer_Handler,
+		},
+		{
+			MethodName: "SetManufacturerStrictMode",
+			Handler:    _Msg_SetManufacturerStrictMode_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "cert/hardware/v1/tx.proto",
+}
+
+func _Msg_RegisterDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgRegisterDevice)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).RegisterDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Msg/RegisterDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).RegisterDevice(ctx, req.(*MsgRegisterDevice))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_RequestAttestationChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgRequestAttestationChallenge)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).RequestAttestationChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Msg/RequestAttestationChallenge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).RequestAttestationChallenge(ctx, req.(*MsgRequestAttestationChallenge))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_SubmitAttestation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgSubmitAttestation)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).SubmitAttestation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Msg/SubmitAttestation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).SubmitAttestation(ctx, req.(*MsgSubmitAttestation))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_AppealSuspension_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgAppealSuspension)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).AppealSuspension(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Msg/AppealSuspension",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).AppealSuspension(ctx, req.(*MsgAppealSuspension))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_TransferDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgTransferDevice)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).TransferDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Msg/TransferDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).TransferDevice(ctx, req.(*MsgTransferDevice))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_SetFirmwareRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgSetFirmwareRelease)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).SetFirmwareRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Msg/SetFirmwareRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).SetFirmwareRelease(ctx, req.(*MsgSetFirmwareRelease))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_ReportFirmware_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgReportFirmware)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).ReportFirmware(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Msg/ReportFirmware",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).ReportFirmware(ctx, req.(*MsgReportFirmware))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_SetManufacturer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgSetManufacturer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).SetManufacturer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Msg/SetManufacturer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).SetManufacturer(ctx, req.(*MsgSetManufacturer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_RemoveManufacturer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgRemoveManufacturer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).RemoveManufacturer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Msg/RemoveManufacturer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).RemoveManufacturer(ctx, req.(*MsgRemoveManufacturer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Msg_SetManufacturerStrictMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MsgSetManufacturerStrictMode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MsgServer).SetManufacturerStrictMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cert.hardware.v1.Msg/SetManufacturerStrictMode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MsgServer).SetManufacturerStrictMode(ctx, req.(*MsgSetManufacturerStrictMode))
+	}
+	return interceptor(ctx, in, info, handler)
+}